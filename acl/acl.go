@@ -5,6 +5,7 @@ package acl
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -65,9 +66,17 @@ type ACL struct {
 	hostVolumes         *iradix.Tree[capabilitySet]
 	wildcardHostVolumes *iradix.Tree[capabilitySet]
 
+	jobTemplates         *iradix.Tree[capabilitySet]
+	wildcardJobTemplates *iradix.Tree[capabilitySet]
+
 	variables         *iradix.Tree[capabilitySet]
 	wildcardVariables *iradix.Tree[capabilitySet]
 
+	// jobs maps the name of the namespace policies, including glob patterns,
+	// to the job policies granting capabilities on the allocations of the
+	// matching jobs of the namespace.
+	jobs map[string][]*JobPolicy
+
 	// The attributes below store the policy value for policies that don't have
 	// fine-grained capabilities.
 	agent    string
@@ -120,6 +129,9 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	hvTxn := iradix.New[capabilitySet]().Txn()
 	whvTxn := iradix.New[capabilitySet]().Txn()
 
+	jtTxn := iradix.New[capabilitySet]().Txn()
+	wjtTxn := iradix.New[capabilitySet]().Txn()
+
 	svTxn := iradix.New[capabilitySet]().Txn()
 	wsvTxn := iradix.New[capabilitySet]().Txn()
 
@@ -177,6 +189,13 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 				}
 			}
 
+			if len(ns.Jobs) > 0 {
+				if acl.jobs == nil {
+					acl.jobs = make(map[string][]*JobPolicy)
+				}
+				acl.jobs[ns.Name] = append(acl.jobs[ns.Name], ns.Jobs...)
+			}
+
 			// Deny always takes precedence
 			if capabilities.Check(NamespaceCapabilityDeny) {
 				continue NAMESPACES
@@ -230,6 +249,42 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 			}
 		}
 
+	JOBTEMPLATES:
+		for _, jt := range policy.JobTemplates {
+			// Use wildcard transaction if policy name uses glob matching.
+			txn := jtTxn
+			if strings.Contains(jt.Name, "*") {
+				txn = wjtTxn
+			}
+
+			// Check for existing capabilities.
+			var capabilities capabilitySet
+
+			raw, ok := txn.Get([]byte(jt.Name))
+			if ok {
+				capabilities = raw
+			} else {
+				capabilities = make(capabilitySet)
+				txn.Insert([]byte(jt.Name), capabilities)
+			}
+
+			// Deny always takes precedence.
+			if capabilities.Check(JobTemplateCapabilityDeny) {
+				continue JOBTEMPLATES
+			}
+
+			// Add in all the capabilities.
+			for _, cap := range jt.Capabilities {
+				if cap == JobTemplateCapabilityDeny {
+					// Overwrite any existing capabilities.
+					capabilities.Clear()
+					capabilities.Set(JobTemplateCapabilityDeny)
+					continue JOBTEMPLATES
+				}
+				capabilities.Set(cap)
+			}
+		}
+
 	HOSTVOLUMES:
 		for _, hv := range policy.HostVolumes {
 			// Should the volume be matched using a glob?
@@ -301,6 +356,9 @@ func NewACL(management bool, policies []*Policy) (*ACL, error) {
 	acl.hostVolumes = hvTxn.Commit()
 	acl.wildcardHostVolumes = whvTxn.Commit()
 
+	acl.jobTemplates = jtTxn.Commit()
+	acl.wildcardJobTemplates = wjtTxn.Commit()
+
 	acl.variables = svTxn.Commit()
 	acl.wildcardVariables = wsvTxn.Commit()
 
@@ -357,6 +415,52 @@ func (a *ACL) AllowNamespaceOperation(ns string, op string) bool {
 	return capabilities.Check(op)
 }
 
+// AllowJobTaskOperation checks if a given operation is allowed for a task of
+// an allocation of a job. The operation is allowed if it is granted for the
+// whole namespace, or if a job policy of the namespace grants it for the job
+// and task. An empty task, such as for a path outside of the task
+// directories, is only allowed by job policies not restricted to tasks.
+func (a *ACL) AllowJobTaskOperation(ns, jobID, task, op string) bool {
+	if a.AllowNamespaceOperation(ns, op) {
+		return true
+	}
+	if a == nil || ns == AllNamespacesSentinel {
+		return false
+	}
+
+	name, capabilities, ok := a.matchingNamespace(ns)
+	if !ok || capabilities.Check(NamespaceCapabilityDeny) {
+		return false
+	}
+
+	for _, job := range a.jobs[name] {
+		if jobPolicyAllows(job, jobID, task, op) {
+			return true
+		}
+	}
+	return false
+}
+
+// jobPolicyAllows returns true if the job policy grants the operation for
+// the task of the job.
+func jobPolicyAllows(job *JobPolicy, jobID, task, op string) bool {
+	if !slices.Contains(job.Capabilities, op) || !glob.Glob(job.Name, jobID) {
+		return false
+	}
+	if len(job.Tasks) == 0 {
+		return true
+	}
+	if task == "" {
+		return false
+	}
+	for _, pattern := range job.Tasks {
+		if glob.Glob(pattern, task) {
+			return true
+		}
+	}
+	return false
+}
+
 // AllowNamespace checks if any operations are allowed for a namespace
 func (a *ACL) AllowNamespace(ns string) bool {
 	if a == nil {
@@ -467,6 +571,28 @@ func (a *ACL) AllowNodePoolSearch() bool {
 	return false
 }
 
+// AllowJobTemplateOperation returns true if the given operation is allowed for
+// the job template specified.
+func (a *ACL) AllowJobTemplateOperation(name string, op string) bool {
+	if a == nil {
+		return false
+	}
+
+	// Hot path management tokens or when ACLs are disabled
+	if a.aclsDisabled || a.management {
+		return true
+	}
+
+	// Check for matching capability set.
+	capabilities, ok := a.matchingJobTemplateCapabilitySet(name)
+	if !ok {
+		return false
+	}
+
+	// Check if the capability has been granted.
+	return capabilities.Check(op)
+}
+
 // AllowHostVolumeOperation checks if a given operation is allowed for a host volume
 func (a *ACL) AllowHostVolumeOperation(hv string, op string) bool {
 	if a == nil {
@@ -585,6 +711,18 @@ func (a *ACL) matchingNamespaceCapabilitySet(ns string) (capabilitySet, bool) {
 	return a.findClosestMatchingGlob(a.wildcardNamespaces, ns)
 }
 
+// matchingNamespace returns the name of the namespace policy closest
+// matching the namespace, along with its capabilitySet.
+func (a *ACL) matchingNamespace(ns string) (string, capabilitySet, bool) {
+	raw, ok := a.namespaces.Get([]byte(ns))
+	if ok {
+		return ns, raw, true
+	}
+
+	match, ok := closestMatchingGlob(a.wildcardNamespaces, ns)
+	return match.name, match.capabilitySet, ok
+}
+
 // anyNamespaceAllowsOp returns true if any namespace in ACL object allows the
 // given operation.
 func (a *ACL) anyNamespaceAllowsOp(op string) bool {
@@ -631,6 +769,17 @@ func (a *ACL) matchingNodePoolCapabilitySet(pool string) (capabilitySet, bool) {
 	return a.findClosestMatchingGlob(a.wildcardNodePools, pool)
 }
 
+// matchingJobTemplateCapabilitySet returns the capabilitySet that closest
+// match the job template.
+func (a *ACL) matchingJobTemplateCapabilitySet(name string) (capabilitySet, bool) {
+	raw, ok := a.jobTemplates.Get([]byte(name))
+	if ok {
+		return raw, true
+	}
+
+	return a.findClosestMatchingGlob(a.wildcardJobTemplates, name)
+}
+
 // matchingHostVolumeCapabilitySet looks for a capabilitySet that matches the host volume name,
 // if no concrete definitions are found, then we return the closest matching
 // glob.
@@ -684,17 +833,27 @@ type matchingGlob struct {
 }
 
 func (a *ACL) findClosestMatchingGlob(radix *iradix.Tree[capabilitySet], ns string) (capabilitySet, bool) {
+	match, ok := closestMatchingGlob(radix, ns)
+	if !ok {
+		return capabilitySet{}, false
+	}
+	return match.capabilitySet, true
+}
+
+// closestMatchingGlob returns the glob of the radix tree closest matching
+// the name.
+func closestMatchingGlob(radix *iradix.Tree[capabilitySet], ns string) (matchingGlob, bool) {
 	// First, find all globs that match.
 	matchingGlobs := findAllMatchingWildcards(radix, ns)
 
 	// If none match, let's return.
 	if len(matchingGlobs) == 0 {
-		return capabilitySet{}, false
+		return matchingGlob{}, false
 	}
 
 	// If a single matches, lets be efficient and return early.
 	if len(matchingGlobs) == 1 {
-		return matchingGlobs[0].capabilitySet, true
+		return matchingGlobs[0], true
 	}
 
 	// Stable sort the matched globs, based on the character difference between
@@ -704,7 +863,7 @@ func (a *ACL) findClosestMatchingGlob(radix *iradix.Tree[capabilitySet], ns stri
 		return matchingGlobs[i].difference <= matchingGlobs[j].difference
 	})
 
-	return matchingGlobs[0].capabilitySet, true
+	return matchingGlobs[0], true
 }
 
 func findAllMatchingWildcards(radix *iradix.Tree[capabilitySet], name string) []matchingGlob {
@@ -445,6 +445,227 @@ func TestWildcardNamespaceMatching(t *testing.T) {
 	}
 }
 
+func TestAllowJobTaskOperation(t *testing.T) {
+	ci.Parallel(t)
+
+	policy := `
+namespace "default" {
+  policy = "read"
+
+  job "web-*" {
+    capabilities = ["alloc-exec", "read-logs"]
+    tasks        = ["server", "sidecar-*"]
+  }
+
+  job "batch" {
+    capabilities = ["read-fs"]
+  }
+}
+
+namespace "prod-*" {
+  job "*" {
+    capabilities = ["read-logs"]
+  }
+}
+
+namespace "prod-secret" {
+  policy = "deny"
+}
+
+namespace "dev" {
+  capabilities = ["alloc-exec"]
+}
+`
+
+	tests := []struct {
+		name      string
+		namespace string
+		jobID     string
+		task      string
+		op        string
+		allow     bool
+	}{
+		{
+			name:      "matching job and task",
+			namespace: "default",
+			jobID:     "web-api",
+			task:      "server",
+			op:        NamespaceCapabilityAllocExec,
+			allow:     true,
+		},
+		{
+			name:      "matching task glob",
+			namespace: "default",
+			jobID:     "web-api",
+			task:      "sidecar-proxy",
+			op:        NamespaceCapabilityReadLogs,
+			allow:     true,
+		},
+		{
+			name:      "task not allowed",
+			namespace: "default",
+			jobID:     "web-api",
+			task:      "init",
+			op:        NamespaceCapabilityAllocExec,
+			allow:     false,
+		},
+		{
+			name:      "empty task with task restriction",
+			namespace: "default",
+			jobID:     "web-api",
+			task:      "",
+			op:        NamespaceCapabilityAllocExec,
+			allow:     false,
+		},
+		{
+			name:      "job not allowed",
+			namespace: "default",
+			jobID:     "api",
+			task:      "server",
+			op:        NamespaceCapabilityAllocExec,
+			allow:     false,
+		},
+		{
+			name:      "capability not granted for job",
+			namespace: "default",
+			jobID:     "web-api",
+			task:      "server",
+			op:        NamespaceCapabilityReadFS,
+			allow:     false,
+		},
+		{
+			name:      "empty task without task restriction",
+			namespace: "default",
+			jobID:     "batch",
+			task:      "",
+			op:        NamespaceCapabilityReadFS,
+			allow:     true,
+		},
+		{
+			name:      "wildcard namespace",
+			namespace: "prod-api",
+			jobID:     "example",
+			task:      "web",
+			op:        NamespaceCapabilityReadLogs,
+			allow:     true,
+		},
+		{
+			name:      "denied namespace",
+			namespace: "prod-secret",
+			jobID:     "example",
+			task:      "web",
+			op:        NamespaceCapabilityReadLogs,
+			allow:     false,
+		},
+		{
+			name:      "namespace capability",
+			namespace: "dev",
+			jobID:     "example",
+			task:      "",
+			op:        NamespaceCapabilityAllocExec,
+			allow:     true,
+		},
+		{
+			name:      "unknown namespace",
+			namespace: "other",
+			jobID:     "web-api",
+			task:      "server",
+			op:        NamespaceCapabilityAllocExec,
+			allow:     false,
+		},
+	}
+
+	p, err := Parse(policy)
+	must.NoError(t, err)
+
+	acl, err := NewACL(false, []*Policy{p})
+	must.NoError(t, err)
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			must.Eq(t, tc.allow, acl.AllowJobTaskOperation(tc.namespace, tc.jobID, tc.task, tc.op))
+		})
+	}
+}
+
+func TestJobTemplate(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name     string
+		policy   string
+		template string
+		allowOps []string
+		denyOps  []string
+	}{
+		{
+			name: "policy read allows run",
+			policy: `
+job_template "web" {
+	policy = "read"
+}
+`,
+			template: "web",
+			allowOps: []string{JobTemplateCapabilityRead, JobTemplateCapabilityRun},
+			denyOps:  []string{JobTemplateCapabilityWrite, JobTemplateCapabilityDelete},
+		},
+		{
+			name: "capability run only",
+			policy: `
+job_template "batch-*" {
+	capabilities = ["run"]
+}
+`,
+			template: "batch-etl",
+			allowOps: []string{JobTemplateCapabilityRun},
+			denyOps:  []string{JobTemplateCapabilityRead, JobTemplateCapabilityWrite},
+		},
+		{
+			name: "closest glob and deny",
+			policy: `
+job_template "*" {
+	policy = "write"
+}
+
+job_template "prod-*" {
+	policy = "deny"
+}
+`,
+			template: "prod-web",
+			allowOps: []string{},
+			denyOps:  []string{JobTemplateCapabilityRead, JobTemplateCapabilityRun},
+		},
+		{
+			name: "no matching policy",
+			policy: `
+job_template "web" {
+	policy = "write"
+}
+`,
+			template: "worker",
+			allowOps: []string{},
+			denyOps:  []string{JobTemplateCapabilityRead, JobTemplateCapabilityRun},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policy, err := Parse(tc.policy)
+			must.NoError(t, err)
+
+			acl, err := NewACL(false, []*Policy{policy})
+			must.NoError(t, err)
+
+			for _, op := range tc.allowOps {
+				must.True(t, acl.AllowJobTemplateOperation(tc.template, op), must.Sprintf("op %q", op))
+			}
+			for _, op := range tc.denyOps {
+				must.False(t, acl.AllowJobTemplateOperation(tc.template, op), must.Sprintf("op %q", op))
+			}
+		})
+	}
+}
+
 func TestNodePool(t *testing.T) {
 	ci.Parallel(t)
 
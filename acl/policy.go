@@ -37,8 +37,10 @@ const (
 	NamespaceCapabilityDispatchJob          = "dispatch-job"
 	NamespaceCapabilityReadLogs             = "read-logs"
 	NamespaceCapabilityReadFS               = "read-fs"
+	NamespaceCapabilityWriteFS              = "write-fs"
 	NamespaceCapabilityAllocExec            = "alloc-exec"
 	NamespaceCapabilityAllocNodeExec        = "alloc-node-exec"
+	NamespaceCapabilityAllocExecUnrecorded  = "alloc-exec-unrecorded"
 	NamespaceCapabilityAllocLifecycle       = "alloc-lifecycle"
 	NamespaceCapabilitySentinelOverride     = "sentinel-override"
 	NamespaceCapabilityCSIRegisterPlugin    = "csi-register-plugin"
@@ -76,6 +78,28 @@ var (
 	validNodePool = regexp.MustCompile("^[a-zA-Z0-9-_*]{1,128}$")
 )
 
+const (
+	// The following are the fine-grained capabilities that can be granted for
+	// job templates. The run capability allows rendering jobs from a
+	// template, while registering the rendered job still requires the
+	// submit-job capability in the job namespace.
+	//
+	// The Policy field is a short hand for granting several of these. When
+	// capabilities are combined we take the union of all capabilities. If the
+	// deny capability is present, it takes precedence and overwrites all other
+	// capabilities.
+
+	JobTemplateCapabilityDelete = "delete"
+	JobTemplateCapabilityDeny   = "deny"
+	JobTemplateCapabilityRead   = "read"
+	JobTemplateCapabilityRun    = "run"
+	JobTemplateCapabilityWrite  = "write"
+)
+
+var (
+	validJobTemplate = regexp.MustCompile("^[a-zA-Z0-9-_*]{1,128}$")
+)
+
 const (
 	// The following are the fine-grained capabilities that can be granted for a volume set.
 	// The Policy field is a short hand for granting several of these. When capabilities are
@@ -104,15 +128,16 @@ const (
 
 // Policy represents a parsed HCL or JSON policy.
 type Policy struct {
-	Namespaces  []*NamespacePolicy  `hcl:"namespace,expand"`
-	NodePools   []*NodePoolPolicy   `hcl:"node_pool,expand"`
-	HostVolumes []*HostVolumePolicy `hcl:"host_volume,expand"`
-	Agent       *AgentPolicy        `hcl:"agent"`
-	Node        *NodePolicy         `hcl:"node"`
-	Operator    *OperatorPolicy     `hcl:"operator"`
-	Quota       *QuotaPolicy        `hcl:"quota"`
-	Plugin      *PluginPolicy       `hcl:"plugin"`
-	Raw         string              `hcl:"-"`
+	Namespaces   []*NamespacePolicy   `hcl:"namespace,expand"`
+	NodePools    []*NodePoolPolicy    `hcl:"node_pool,expand"`
+	HostVolumes  []*HostVolumePolicy  `hcl:"host_volume,expand"`
+	JobTemplates []*JobTemplatePolicy `hcl:"job_template,expand"`
+	Agent        *AgentPolicy         `hcl:"agent"`
+	Node         *NodePolicy          `hcl:"node"`
+	Operator     *OperatorPolicy      `hcl:"operator"`
+	Quota        *QuotaPolicy         `hcl:"quota"`
+	Plugin       *PluginPolicy        `hcl:"plugin"`
+	Raw          string               `hcl:"-"`
 }
 
 // IsEmpty checks to make sure that at least one policy has been set and is not
@@ -121,6 +146,7 @@ func (p *Policy) IsEmpty() bool {
 	return len(p.Namespaces) == 0 &&
 		len(p.NodePools) == 0 &&
 		len(p.HostVolumes) == 0 &&
+		len(p.JobTemplates) == 0 &&
 		p.Agent == nil &&
 		p.Node == nil &&
 		p.Operator == nil &&
@@ -134,6 +160,16 @@ type NamespacePolicy struct {
 	Policy       string
 	Capabilities []string
 	Variables    *VariablesPolicy `hcl:"variables"`
+	Jobs         []*JobPolicy     `hcl:"job,expand"`
+}
+
+// JobPolicy grants capabilities on the allocations of the jobs whose ID
+// matches the Name glob pattern. If Tasks is set, the capabilities are only
+// granted for the tasks whose name matches one of its glob patterns.
+type JobPolicy struct {
+	Name         string `hcl:",key"`
+	Capabilities []string
+	Tasks        []string
 }
 
 // NodePoolPolicy is the policfy for a specific node pool.
@@ -143,6 +179,13 @@ type NodePoolPolicy struct {
 	Capabilities []string
 }
 
+// JobTemplatePolicy is the policy for a specific job template.
+type JobTemplatePolicy struct {
+	Name         string `hcl:",key"`
+	Policy       string
+	Capabilities []string
+}
+
 type VariablesPolicy struct {
 	Paths []*VariablesPathPolicy `hcl:"path"`
 }
@@ -203,8 +246,8 @@ func isNamespaceCapabilityValid(cap string) bool {
 	switch cap {
 	case NamespaceCapabilityDeny, NamespaceCapabilityParseJob, NamespaceCapabilityListJobs, NamespaceCapabilityReadJob,
 		NamespaceCapabilitySubmitJob, NamespaceCapabilityDispatchJob, NamespaceCapabilityReadLogs,
-		NamespaceCapabilityReadFS, NamespaceCapabilityAllocLifecycle,
-		NamespaceCapabilityAllocExec, NamespaceCapabilityAllocNodeExec,
+		NamespaceCapabilityReadFS, NamespaceCapabilityWriteFS, NamespaceCapabilityAllocLifecycle,
+		NamespaceCapabilityAllocExec, NamespaceCapabilityAllocNodeExec, NamespaceCapabilityAllocExecUnrecorded,
 		NamespaceCapabilityCSIReadVolume, NamespaceCapabilityCSIWriteVolume, NamespaceCapabilityCSIListVolume, NamespaceCapabilityCSIMountVolume, NamespaceCapabilityCSIRegisterPlugin,
 		NamespaceCapabilityListScalingPolicies, NamespaceCapabilityReadScalingPolicy, NamespaceCapabilityReadJobScaling, NamespaceCapabilityScaleJob:
 		return true
@@ -216,6 +259,18 @@ func isNamespaceCapabilityValid(cap string) bool {
 	}
 }
 
+// isJobCapabilityValid ensures the given capability is valid for a job policy
+// within a namespace. Only the capabilities operating on the tasks of an
+// allocation can be scoped to jobs.
+func isJobCapabilityValid(cap string) bool {
+	switch cap {
+	case NamespaceCapabilityAllocExec, NamespaceCapabilityReadLogs, NamespaceCapabilityReadFS:
+		return true
+	default:
+		return false
+	}
+}
+
 // isPathCapabilityValid ensures the given capability is valid for a
 // variables path policy
 func isPathCapabilityValid(cap string) bool {
@@ -304,6 +359,37 @@ func expandNodePoolPolicy(policy string) []string {
 	}
 }
 
+func isJobTemplateCapabilityValid(cap string) bool {
+	switch cap {
+	case JobTemplateCapabilityDelete, JobTemplateCapabilityDeny, JobTemplateCapabilityRead,
+		JobTemplateCapabilityRun, JobTemplateCapabilityWrite:
+		return true
+	default:
+		return false
+	}
+}
+
+func expandJobTemplatePolicy(policy string) []string {
+	switch policy {
+	case PolicyDeny:
+		return []string{JobTemplateCapabilityDeny}
+	case PolicyRead:
+		return []string{
+			JobTemplateCapabilityRead,
+			JobTemplateCapabilityRun,
+		}
+	case PolicyWrite:
+		return []string{
+			JobTemplateCapabilityDelete,
+			JobTemplateCapabilityRead,
+			JobTemplateCapabilityRun,
+			JobTemplateCapabilityWrite,
+		}
+	default:
+		return nil
+	}
+}
+
 func isHostVolumeCapabilityValid(cap string) bool {
 	switch cap {
 	case HostVolumeCapabilityDeny, HostVolumeCapabilityMountReadOnly, HostVolumeCapabilityMountReadWrite:
@@ -407,6 +493,26 @@ func Parse(rules string) (*Policy, error) {
 
 		}
 
+		for _, job := range ns.Jobs {
+			if job.Name == "" {
+				return nil, fmt.Errorf("Invalid missing job name in namespace %s", ns.Name)
+			}
+			if len(job.Capabilities) == 0 {
+				return nil, fmt.Errorf("Invalid job policy: no capabilities for job %s in namespace %s", job.Name, ns.Name)
+			}
+			for _, cap := range job.Capabilities {
+				if !isJobCapabilityValid(cap) {
+					return nil, fmt.Errorf(
+						"Invalid job capability '%s' for job %s in namespace %s", cap, job.Name, ns.Name)
+				}
+			}
+			for _, task := range job.Tasks {
+				if task == "" {
+					return nil, fmt.Errorf("Invalid empty task name for job %s in namespace %s", job.Name, ns.Name)
+				}
+			}
+		}
+
 	}
 
 	for _, np := range p.NodePools {
@@ -428,6 +534,25 @@ func Parse(rules string) (*Policy, error) {
 		}
 	}
 
+	for _, jt := range p.JobTemplates {
+		if !validJobTemplate.MatchString(jt.Name) {
+			return nil, fmt.Errorf("Invalid job template name '%s'", jt.Name)
+		}
+		if jt.Policy != "" && !isPolicyValid(jt.Policy) {
+			return nil, fmt.Errorf("Invalid job template policy '%s' for '%s'", jt.Policy, jt.Name)
+		}
+		for _, cap := range jt.Capabilities {
+			if !isJobTemplateCapabilityValid(cap) {
+				return nil, fmt.Errorf("Invalid job template capability '%s' for '%s'", cap, jt.Name)
+			}
+		}
+
+		if jt.Policy != "" {
+			extraCap := expandJobTemplatePolicy(jt.Policy)
+			jt.Capabilities = append(jt.Capabilities, extraCap...)
+		}
+	}
+
 	for _, hv := range p.HostVolumes {
 		if !validVolume.MatchString(hv.Name) {
 			return nil, fmt.Errorf("Invalid host volume name: %#v", hv)
@@ -509,11 +634,18 @@ func hclDecode(p *Policy, rules string) (err error) {
 			p.Namespaces[i].Name = ""
 		}
 
-		// Fix missing variable paths.
+		// Fix missing job keys and variable paths.
 		nsOT, ok := nsObj.Val.(*ast.ObjectType)
 		if !ok {
 			continue
 		}
+
+		jobList := nsOT.List.Filter("job")
+		for j, jobObj := range jobList.Items {
+			if len(jobObj.Keys) == 0 {
+				p.Namespaces[i].Jobs[j].Name = ""
+			}
+		}
 		varsList := nsOT.List.Filter("variables")
 		if varsList == nil || len(varsList.Items) == 0 {
 			continue
@@ -539,6 +671,14 @@ func hclDecode(p *Policy, rules string) (err error) {
 		}
 	}
 
+	jtList := list.Filter("job_template")
+	for i, jtObj := range jtList.Items {
+		// Fix missing job template key.
+		if len(jtObj.Keys) == 0 {
+			p.JobTemplates[i].Name = ""
+		}
+	}
+
 	hvList := list.Filter("host_volume")
 	for i, hvObj := range hvList.Items {
 		// Fix missing host volume key.
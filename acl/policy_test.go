@@ -719,6 +719,69 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
+		{
+			`
+			job_template "web-service" {
+				policy = "read"
+			}
+
+			job_template "batch-*" {
+				capabilities = ["run"]
+			}
+
+			job_template "*" {
+				policy = "write"
+			}
+			`,
+			"",
+			&Policy{
+				JobTemplates: []*JobTemplatePolicy{
+					{
+						Name:   "web-service",
+						Policy: PolicyRead,
+						Capabilities: []string{
+							JobTemplateCapabilityRead,
+							JobTemplateCapabilityRun,
+						},
+					},
+					{
+						Name:   "batch-*",
+						Policy: "",
+						Capabilities: []string{
+							JobTemplateCapabilityRun,
+						},
+					},
+					{
+						Name:   "*",
+						Policy: PolicyWrite,
+						Capabilities: []string{
+							JobTemplateCapabilityDelete,
+							JobTemplateCapabilityRead,
+							JobTemplateCapabilityRun,
+							JobTemplateCapabilityWrite,
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			job_template "web" {
+				capabilities = ["read", "invalid"]
+			}
+			`,
+			"Invalid job template capability",
+			nil,
+		},
+		{
+			`
+			job_template {
+				policy = "read"
+			}
+			`,
+			"Invalid job template name",
+			nil,
+		},
 		{
 			`
 			node_pool "" {
@@ -839,6 +902,79 @@ func TestParse(t *testing.T) {
 			"Invalid host volume name",
 			nil,
 		},
+		{
+			`
+			namespace "dev" {
+				job "web-*" {
+					capabilities = ["alloc-exec", "read-logs"]
+					tasks        = ["server"]
+				}
+				job "batch" {
+					capabilities = ["read-fs"]
+				}
+			}
+			`,
+			"",
+			&Policy{
+				Namespaces: []*NamespacePolicy{
+					{
+						Name: "dev",
+						Jobs: []*JobPolicy{
+							{
+								Name:         "web-*",
+								Capabilities: []string{NamespaceCapabilityAllocExec, NamespaceCapabilityReadLogs},
+								Tasks:        []string{"server"},
+							},
+							{
+								Name:         "batch",
+								Capabilities: []string{NamespaceCapabilityReadFS},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			`
+			namespace "dev" {
+				job "web" {
+					capabilities = ["submit-job"]
+				}
+			}
+			`,
+			"Invalid job capability 'submit-job' for job web in namespace dev",
+			nil,
+		},
+		{
+			`
+			{
+				"namespace": [
+					{
+						"dev": {
+							"job": [
+								{
+									"": {
+										"capabilities": ["read-logs"]
+									}
+								}
+							]
+						}
+					}
+				]
+			}
+			`,
+			"Invalid missing job name in namespace dev",
+			nil,
+		},
+		{
+			`
+			namespace "dev" {
+				job "web" {}
+			}
+			`,
+			"Invalid job policy: no capabilities for job web in namespace dev",
+			nil,
+		},
 		{
 			`
 			plugin {
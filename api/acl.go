@@ -143,6 +143,20 @@ func (a *ACLTokens) Update(token *ACLToken, q *WriteOptions) (*ACLToken, *WriteM
 	return &resp, wm, nil
 }
 
+// Approve is used to approve a pending token. Tokens must be approved by an
+// operator other than the one who created them.
+func (a *ACLTokens) Approve(accessorID string, q *WriteOptions) (*ACLToken, *WriteMeta, error) {
+	if accessorID == "" {
+		return nil, nil, errors.New("missing accessor ID")
+	}
+	var resp ACLToken
+	wm, err := a.client.put("/v1/acl/token/"+accessorID+"/approve", nil, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // Delete is used to delete a token
 func (a *ACLTokens) Delete(accessorID string, q *WriteOptions) (*WriteMeta, error) {
 	if accessorID == "" {
@@ -208,6 +222,21 @@ func (a *ACLTokens) ExchangeOneTimeToken(secret string, q *WriteOptions) (*ACLTo
 	return resp.Token, wm, nil
 }
 
+// CreateChildIdentity is used to create a short-lived child identity, scoped
+// to a subset of capabilities, from the workload identity set as the token of
+// the client or write options.
+func (a *ACLTokens) CreateChildIdentity(req *ChildIdentityRequest, q *WriteOptions) (*ChildIdentity, *WriteMeta, error) {
+	if req == nil {
+		return nil, nil, errors.New("missing child identity request")
+	}
+	var resp ChildIdentity
+	wm, err := a.client.put("/v1/acl/identity/child", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 var (
 	// errMissingACLRoleID is the generic errors to use when a call is missing
 	// the required ACL Role ID parameter.
@@ -494,6 +523,30 @@ func (a *ACLAuth) CompleteAuth(req *ACLOIDCCompleteAuthRequest, q *WriteOptions)
 	return &resp, wm, nil
 }
 
+// DeviceAuth starts the OIDC device authorization flow. The user should visit
+// the returned verification URI and enter the user code, after which
+// CompleteDeviceAuth returns the Nomad token.
+func (a *ACLAuth) DeviceAuth(req *ACLOIDCDeviceAuthRequest, q *WriteOptions) (*ACLOIDCDeviceAuthResponse, *WriteMeta, error) {
+	var resp ACLOIDCDeviceAuthResponse
+	wm, err := a.client.put("/v1/acl/oidc/device-auth", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// CompleteDeviceAuth exchanges the device code for a Nomad token with the
+// appropriate claims attached. If the user hasn't authorized the device yet,
+// the response is pending and should be retried after the polling interval.
+func (a *ACLAuth) CompleteDeviceAuth(req *ACLOIDCCompleteDeviceAuthRequest, q *WriteOptions) (*ACLOIDCCompleteDeviceAuthResponse, *WriteMeta, error) {
+	var resp ACLOIDCCompleteDeviceAuthResponse
+	wm, err := a.client.put("/v1/acl/oidc/complete-device-auth", req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
 // Login exchanges the third party token for a Nomad token with the appropriate
 // claims attached.
 func (a *ACLAuth) Login(req *ACLLoginRequest, q *WriteOptions) (*ACLToken, *WriteMeta, error) {
@@ -558,6 +611,31 @@ type ACLToken struct {
 	// creation. This is a string version of a time.Duration like "2m".
 	ExpirationTTL time.Duration `json:",omitempty"`
 
+	// NotBefore is the point before which the token can't be used.
+	NotBefore *time.Time `json:",omitempty"`
+
+	// MaxUses is the number of requests the token can authenticate. Zero
+	// means unlimited. Only local tokens can have a maximum number of uses.
+	MaxUses uint64 `json:",omitempty"`
+
+	// Uses is the number of requests the token has authenticated, when
+	// MaxUses is set.
+	Uses uint64 `json:",omitempty"`
+
+	// Pending is set when creating a token that must be approved by another
+	// operator before it can be used.
+	Pending bool `json:",omitempty"`
+
+	// CreatedBy and ApprovedBy are the accessor IDs of the tokens used to
+	// create the token and to approve a pending token.
+	CreatedBy  string `json:",omitempty"`
+	ApprovedBy string `json:",omitempty"`
+
+	// AuthIdentity identifies the operator the token was issued to, as the
+	// auth method name and the subject of the login. Tokens created with a
+	// token inherit its identity.
+	AuthIdentity string `json:",omitempty"`
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -637,6 +715,11 @@ type ACLTokenListStub struct {
 	// indicates no expiration has been set on the token.
 	ExpirationTime *time.Time `json:",omitempty"`
 
+	NotBefore *time.Time `json:",omitempty"`
+	MaxUses   uint64     `json:",omitempty"`
+	Uses      uint64     `json:",omitempty"`
+	Pending   bool       `json:",omitempty"`
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -661,6 +744,25 @@ type OneTimeTokenExchangeResponse struct {
 	Token *ACLToken
 }
 
+// ChildIdentityRequest is used to create a child identity from a workload
+// identity.
+type ChildIdentityRequest struct {
+	// Capabilities is the list of namespace capabilities the child identity
+	// is scoped to. Only capabilities the workload is also granted are
+	// effective.
+	Capabilities []string
+
+	// TTL is how long the child identity is valid for. Defaults to 5 minutes
+	// and may not exceed 1 hour.
+	TTL time.Duration
+}
+
+// ChildIdentity is a signed child identity.
+type ChildIdentity struct {
+	JWT        string
+	Expiration time.Time
+}
+
 // BootstrapRequest is used for when operators provide an ACL Bootstrap Token
 type BootstrapRequest struct {
 	BootstrapSecret string
@@ -692,6 +794,11 @@ type ACLRole struct {
 	// both. At least one entry is required.
 	Policies []*ACLRolePolicyLink
 
+	// RequireApproval makes the tokens linked to the role pending when they
+	// are created, so they must be approved by another operator before they
+	// can be used.
+	RequireApproval bool `json:",omitempty"`
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -1106,6 +1213,61 @@ type ACLOIDCCompleteAuthRequest struct {
 	RedirectURI string
 }
 
+// ACLOIDCDeviceAuthRequest is the request object to start the OIDC device
+// authorization flow.
+type ACLOIDCDeviceAuthRequest struct {
+
+	// AuthMethodName is the OIDC auth-method to use. This is a required
+	// parameter.
+	AuthMethodName string
+}
+
+// ACLOIDCDeviceAuthResponse is the response when starting the OIDC device
+// authorization flow.
+type ACLOIDCDeviceAuthResponse struct {
+
+	// DeviceCode is used to complete the flow and must be kept secret.
+	DeviceCode string
+
+	// UserCode is the code the user enters at the verification URI.
+	UserCode string
+
+	// VerificationURI is the URI the user visits to authorize the device, and
+	// VerificationURIComplete includes the user code, if the provider
+	// supports it.
+	VerificationURI         string
+	VerificationURIComplete string
+
+	// ExpiresIn is how long the device and user codes are valid for.
+	ExpiresIn time.Duration
+
+	// Interval is how long to wait between attempts to complete the flow.
+	Interval time.Duration
+}
+
+// ACLOIDCCompleteDeviceAuthRequest is the request object to complete the OIDC
+// device authorization flow.
+type ACLOIDCCompleteDeviceAuthRequest struct {
+
+	// AuthMethodName is the name of the auth method being used to login. This
+	// is a required parameter.
+	AuthMethodName string
+
+	// DeviceCode is the device code returned when starting the flow. This is
+	// a required parameter.
+	DeviceCode string
+}
+
+// ACLOIDCCompleteDeviceAuthResponse is the response when completing the OIDC
+// device authorization flow. ACLToken is nil and Pending is set if the user
+// hasn't authorized the device yet. SlowDown indicates the polling interval
+// should be increased.
+type ACLOIDCCompleteDeviceAuthResponse struct {
+	ACLToken *ACLToken
+	Pending  bool
+	SlowDown bool
+}
+
 // ACLLoginRequest is the request object to begin auth with an external bearer
 // token provider.
 type ACLLoginRequest struct {
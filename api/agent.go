@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 // Agent encapsulates an API client which talks to Nomad's
@@ -299,6 +300,34 @@ func (a *Agent) Host(serverID, nodeID string, q *QueryOptions) (*HostDataRespons
 	return &resp, nil
 }
 
+// FlightRecorder dumps the flight recorder of the agent, which holds its
+// recent RPC latencies, eval broker stats, leadership changes, and goroutine
+// snapshots
+func (a *Agent) FlightRecorder(serverID, nodeID string, q *QueryOptions) (*FlightRecorderResponse, error) {
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	if q.Params == nil {
+		q.Params = make(map[string]string)
+	}
+
+	if serverID != "" {
+		q.Params["server_id"] = serverID
+	}
+
+	if nodeID != "" {
+		q.Params["node_id"] = nodeID
+	}
+
+	var resp FlightRecorderResponse
+	_, err := a.client.query("/v1/agent/flight-recorder", &resp, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
 // Monitor returns a channel which will receive streaming logs from the agent
 // Providing a non-nil stopCh can be used to close the connection and stop log streaming
 func (a *Agent) Monitor(stopCh <-chan struct{}, q *QueryOptions) (<-chan *StreamFrame, <-chan error) {
@@ -518,6 +547,48 @@ type HostDataResponse struct {
 	HostData *HostData `json:",omitempty"`
 }
 
+type FlightRecorderRPCSample struct {
+	Time     time.Time
+	Method   string
+	Duration time.Duration
+	Blocking bool
+	Error    string `json:",omitempty"`
+}
+
+type FlightRecorderEvalBrokerSample struct {
+	Time       time.Time
+	Ready      int
+	Unacked    int
+	Pending    int
+	Waiting    int
+	Cancelable int
+}
+
+type FlightRecorderLeadershipEvent struct {
+	Time   time.Time
+	Leader bool
+}
+
+type FlightRecorderGoroutineSnapshot struct {
+	Time   time.Time
+	Count  int
+	Stacks string
+}
+
+type FlightRecorderDump struct {
+	Time       time.Time
+	Reason     string
+	RPCs       []*FlightRecorderRPCSample
+	EvalBroker []*FlightRecorderEvalBrokerSample
+	Leadership []*FlightRecorderLeadershipEvent
+	Goroutines []*FlightRecorderGoroutineSnapshot
+}
+
+type FlightRecorderResponse struct {
+	AgentID string
+	Dump    *FlightRecorderDump `json:",omitempty"`
+}
+
 // GetSchedulerWorkerConfig returns the targeted agent's worker pool configuration
 func (a *Agent) GetSchedulerWorkerConfig(q *QueryOptions) (*SchedulerWorkerPoolArgs, error) {
 	var resp AgentSchedulerWorkerConfigResponse
@@ -272,8 +272,10 @@ type Allocation struct {
 	NextAllocation        string
 	RescheduleTracker     *RescheduleTracker
 	NetworkStatus         *AllocNetworkStatus
+	PendingReason         *AllocPendingReason
 	PreemptedAllocations  []string
 	PreemptedByAllocation string
+	TraceParent           string
 	CreateIndex           uint64
 	ModifyIndex           uint64
 	AllocModifyIndex      uint64
@@ -390,6 +392,7 @@ type AllocationListStub struct {
 	FollowupEvalID        string
 	NextAllocation        string
 	RescheduleTracker     *RescheduleTracker
+	PendingReason         *AllocPendingReason
 	PreemptedAllocations  []string
 	PreemptedByAllocation string
 	CreateIndex           uint64
@@ -417,6 +420,23 @@ type AllocNetworkStatus struct {
 	DNS           *DNSConfig
 }
 
+const (
+	AllocPendingStageCSIVolumes = "csi-volumes"
+	AllocPendingStageArtifacts  = "artifacts"
+	AllocPendingStageTemplate   = "template"
+	AllocPendingStageImagePull  = "image-pull"
+)
+
+// AllocPendingReason captures the stage a pending allocation is blocked on,
+// such as pulling an image or waiting for the dependencies of a template.
+type AllocPendingReason struct {
+	Stage   string
+	Task    string
+	Message string
+	Details map[string]string
+	Time    int64
+}
+
 type AllocatedResources struct {
 	Tasks  map[string]*AllocatedTaskResources
 	Shared AllocatedSharedResources
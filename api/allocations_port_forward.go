@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/gorilla/websocket"
+)
+
+// portForwardBufferSize is the maximum amount of data sent in a single port
+// forward message
+const portForwardBufferSize = 32 * 1024
+
+// PortForward forwards the connection to a port in the network namespace of
+// the allocation, such as the port of a service in bridge networking mode.
+// Data is forwarded over the streaming RPC connection between the servers and
+// the client, so the port does not need to be reachable from the caller.
+//
+// The call blocks until either end closes the connection, the context is
+// cancelled, or an error occurs. The connection is closed on return.
+//
+// Note: for cluster topologies where API consumers don't have network access to
+// Nomad clients, set api.ClientConnTimeout to a small value (ex 1ms) to avoid
+// long pauses on this API call.
+func (a *Allocations) PortForward(ctx context.Context, alloc *Allocation, port int,
+	conn io.ReadWriteCloser, q *QueryOptions) error {
+	defer conn.Close()
+
+	ws, err := a.portForwardConnection(alloc, port, q)
+	if err != nil {
+		return err
+	}
+	defer ws.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errCh := make(chan error, 2)
+
+	// forward the data sent to the connection
+	go func() {
+		buf := make([]byte, portForwardBufferSize)
+		for {
+			n, err := conn.Read(buf)
+			if n > 0 {
+				if err := ws.WriteMessage(websocket.BinaryMessage, buf[:n]); err != nil {
+					errCh <- err
+					return
+				}
+			}
+			if err == io.EOF {
+				errCh <- ws.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return
+			} else if err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+
+	// forward the data sent by the allocation
+	go func() {
+		for {
+			_, data, err := ws.ReadMessage()
+			if err != nil {
+				errCh <- err
+				return
+			}
+			if _, err := conn.Write(data); err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-errCh:
+		if err == nil || websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+			return nil
+		}
+
+		// drop websocket code, not relevant to user
+		var wsErr *websocket.CloseError
+		if errors.As(err, &wsErr) && wsErr.Text != "" {
+			return errors.New(wsErr.Text)
+		}
+		return err
+	}
+}
+
+func (a *Allocations) portForwardConnection(alloc *Allocation, port int, q *QueryOptions) (*websocket.Conn, error) {
+	// First, attempt to connect to the node directly, but may fail due to network isolation
+	// and network errors.  Fallback to using server-side forwarding instead.
+	nodeClient, err := a.client.GetNodeClientWithTimeout(alloc.NodeID, ClientConnTimeout, q)
+	if err == NodeDownErr {
+		return nil, NodeDownErr
+	}
+
+	// copy the query options as they may be shared by concurrent port
+	// forwards
+	var opts QueryOptions
+	if q != nil {
+		opts = *q
+	}
+	opts.Params = make(map[string]string, len(opts.Params)+1)
+	if q != nil {
+		for k, v := range q.Params {
+			opts.Params[k] = v
+		}
+	}
+	opts.Params["port"] = strconv.Itoa(port)
+
+	reqPath := fmt.Sprintf("/v1/client/allocation/%s/port-forward", alloc.ID)
+
+	var conn *websocket.Conn
+	if nodeClient != nil {
+		conn, _, _ = nodeClient.websocket(reqPath, &opts) //nolint:bodyclose // gorilla/websocket Dialer.DialContext() does not require the body to be closed.
+	}
+
+	if conn == nil {
+		conn, _, err = a.client.websocket(reqPath, &opts) //nolint:bodyclose // gorilla/websocket Dialer.DialContext() does not require the body to be closed.
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
@@ -59,6 +59,11 @@ type QueryOptions struct {
 	// a read. This allows for lower latency and higher throughput
 	AllowStale bool
 
+	// ReadReplica requests that a read replica of the region services the
+	// read, offloading the voting servers. It implies AllowStale. The read is
+	// serviced by the server receiving it if the region has no read replicas.
+	ReadReplica bool
+
 	// WaitIndex is used to enable a blocking query. Waits
 	// until the timeout or the next index is reached
 	WaitIndex uint64
@@ -98,6 +103,14 @@ type QueryOptions struct {
 	// Currently only supported by specific endpoints.
 	Reverse bool
 
+	// Fields is the list of fields of the objects to return in list
+	// results. Other fields are left empty, reducing the size of the
+	// response.
+	//
+	// Currently only supported by the allocations and nodes list endpoints
+	// and the job allocations endpoint.
+	Fields []string
+
 	// ctx is an optional context pass through to the underlying HTTP
 	// request layer. Use Context() and WithContext() to manage this.
 	ctx context.Context
@@ -692,6 +705,9 @@ func (r *request) setQueryOptions(q *QueryOptions) {
 	if q.AllowStale {
 		r.params.Set("stale", "")
 	}
+	if q.ReadReplica {
+		r.params.Set("read_replica", "")
+	}
 	if q.WaitIndex != 0 {
 		r.params.Set("index", strconv.FormatUint(q.WaitIndex, 10))
 	}
@@ -713,6 +729,9 @@ func (r *request) setQueryOptions(q *QueryOptions) {
 	if q.Reverse {
 		r.params.Set("reverse", "true")
 	}
+	if len(q.Fields) > 0 {
+		r.params.Set("fields", strings.Join(q.Fields, ","))
+	}
 	for k, v := range q.Params {
 		r.params.Set(k, v)
 	}
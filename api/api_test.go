@@ -160,13 +160,15 @@ func TestSetQueryOptions(t *testing.T) {
 
 	r, _ := c.newRequest("GET", "/v1/jobs")
 	q := &QueryOptions{
-		Region:     "foo",
-		Namespace:  "bar",
-		AllowStale: true,
-		WaitIndex:  1000,
-		WaitTime:   100 * time.Second,
-		AuthToken:  "foobar",
-		Reverse:    true,
+		Region:      "foo",
+		Namespace:   "bar",
+		AllowStale:  true,
+		ReadReplica: true,
+		WaitIndex:   1000,
+		WaitTime:    100 * time.Second,
+		AuthToken:   "foobar",
+		Reverse:     true,
+		Fields:      []string{"ID", "Name"},
 	}
 	r.setQueryOptions(q)
 
@@ -182,9 +184,11 @@ func TestSetQueryOptions(t *testing.T) {
 	try("region", "foo")
 	try("namespace", "bar")
 	try("stale", "") // should not be present
+	try("read_replica", "")
 	try("index", "1000")
 	try("wait", "100000ms")
 	try("reverse", "true")
+	try("fields", "ID,Name")
 }
 
 func TestQueryOptionsContext(t *testing.T) {
@@ -162,6 +162,7 @@ type ConsulProxy struct {
 	Expose              *ConsulExposeConfig    `mapstructure:"expose" hcl:"expose,block"`
 	ExposeConfig        *ConsulExposeConfig    // Deprecated: only to maintain backwards compatibility. Use Expose instead.
 	Upstreams           []*ConsulUpstream      `hcl:"upstreams,block"`
+	Envoy               *ConsulEnvoyConfig     `mapstructure:"envoy" hcl:"envoy,block"`
 	Config              map[string]interface{} `hcl:"config,block"`
 }
 
@@ -180,11 +181,76 @@ func (cp *ConsulProxy) Canonicalize() {
 		upstream.Canonicalize()
 	}
 
+	cp.Envoy.Canonicalize()
+
 	if len(cp.Config) == 0 {
 		cp.Config = nil
 	}
 }
 
+// ConsulEnvoyConfig is the structured configuration of the Envoy proxy of a
+// Connect sidecar service.
+type ConsulEnvoyConfig struct {
+	// Concurrency is the number of worker threads of Envoy. If unset, the
+	// connect.proxy_concurrency meta of the client is used.
+	Concurrency int `mapstructure:"concurrency" hcl:"concurrency,optional"`
+
+	// Stats configures the stats sinks of Envoy.
+	Stats *ConsulEnvoyStats `mapstructure:"stats" hcl:"stats,block"`
+
+	// Tracing configures the tracer of Envoy.
+	Tracing *ConsulEnvoyTracing `mapstructure:"tracing" hcl:"tracing,block"`
+
+	// BootstrapTemplate replaces the template Consul renders the bootstrap
+	// configuration of Envoy with.
+	BootstrapTemplate string `mapstructure:"bootstrap_template" hcl:"bootstrap_template,optional"`
+}
+
+func (e *ConsulEnvoyConfig) Canonicalize() {
+	if e == nil {
+		return
+	}
+	e.Stats.Canonicalize()
+}
+
+// ConsulEnvoyStats configures the stats sinks of Envoy.
+type ConsulEnvoyStats struct {
+	StatsdURL          string         `mapstructure:"statsd_url" hcl:"statsd_url,optional"`
+	DogstatsdURL       string         `mapstructure:"dogstatsd_url" hcl:"dogstatsd_url,optional"`
+	PrometheusBindAddr string         `mapstructure:"prometheus_bind_addr" hcl:"prometheus_bind_addr,optional"`
+	Tags               []string       `mapstructure:"tags" hcl:"tags,optional"`
+	FlushInterval      *time.Duration `mapstructure:"flush_interval" hcl:"flush_interval,optional"`
+}
+
+func (s *ConsulEnvoyStats) Canonicalize() {
+	if s == nil {
+		return
+	}
+	if len(s.Tags) == 0 {
+		s.Tags = nil
+	}
+	if s.FlushInterval == nil {
+		s.FlushInterval = pointerOf(time.Duration(0))
+	}
+}
+
+// ConsulEnvoyTracing configures the tracer of Envoy.
+type ConsulEnvoyTracing struct {
+	// Provider is one of "zipkin", "datadog", or "opentelemetry".
+	Provider string `mapstructure:"provider" hcl:"provider,optional"`
+
+	// Address is the host:port address of the trace collector.
+	Address string `mapstructure:"address" hcl:"address,optional"`
+
+	// Path is the path of the Zipkin collector endpoint, which defaults to
+	// /api/v2/spans.
+	Path string `mapstructure:"path" hcl:"path,optional"`
+
+	// ServiceName is the name of the service in the traces. It defaults to
+	// the name of the Connect service.
+	ServiceName string `mapstructure:"service_name" hcl:"service_name,optional"`
+}
+
 // ConsulMeshGateway is used to configure mesh gateway usage when connecting to
 // a connect upstream in another datacenter.
 type ConsulMeshGateway struct {
@@ -124,6 +124,15 @@ func (v *CSIVolumes) DeleteOpts(req *CSIVolumeDeleteRequest, w *WriteOptions) er
 	return err
 }
 
+// Expand grows the capacity of a registered CSI volume in its storage
+// provider, and the filesystem of the volume on the nodes it is mounted on if
+// the plugin requires it. The volume remains mounted while it is expanded.
+func (v *CSIVolumes) Expand(req *CSIVolumeExpandRequest, w *WriteOptions) (*CSIVolumeExpandResponse, *WriteMeta, error) {
+	resp := &CSIVolumeExpandResponse{}
+	meta, err := v.client.put(fmt.Sprintf("/v1/volume/csi/%v/expand", url.PathEscape(req.VolumeID)), req, resp, w)
+	return resp, meta, err
+}
+
 // Detach causes Nomad to attempt to detach a CSI volume from a client
 // node. This is used in the case that the node is temporarily lost and the
 // allocations are unable to drop their claims automatically.
@@ -323,6 +332,14 @@ type CSIVolume struct {
 	CloneID               string                 `mapstructure:"clone_id" hcl:"clone_id"`
 	SnapshotID            string                 `mapstructure:"snapshot_id" hcl:"snapshot_id"`
 
+	// SnapshotSchedule configures the snapshots the servers take of the
+	// volume periodically.
+	SnapshotSchedule *CSIVolumeSnapshotSchedule `hcl:"snapshot_schedule"`
+
+	// ScheduledSnapshots are the snapshots taken by the snapshot schedule
+	// that are still retained. This value cannot be set by the user.
+	ScheduledSnapshots []*CSIVolumeScheduledSnapshot `hcl:"-"`
+
 	// ReadAllocs is a map of allocation IDs for tracking reader claim status.
 	// The Allocation value will always be nil; clients can populate this data
 	// by iterating over the Allocations field.
@@ -448,6 +465,34 @@ type CSIVolumeCreateResponse struct {
 	QueryMeta
 }
 
+type CSIVolumeExpandRequest struct {
+	VolumeID             string
+	RequestedCapacityMin int64
+	RequestedCapacityMax int64
+	Secrets              CSISecrets
+	WriteRequest
+}
+
+type CSIVolumeExpandResponse struct {
+	CapacityBytes int64
+	QueryMeta
+}
+
+// CSIVolumeSnapshotSchedule configures the snapshots the servers take of a
+// volume periodically, see also nomad/structs/csi.go
+type CSIVolumeSnapshotSchedule struct {
+	Interval   time.Duration     `hcl:"interval"`
+	Retain     int               `hcl:"retain"`
+	Parameters map[string]string `hcl:"parameters"`
+}
+
+// CSIVolumeScheduledSnapshot is a snapshot taken by the snapshot schedule of
+// a volume.
+type CSIVolumeScheduledSnapshot struct {
+	ID         string
+	CreateTime int64
+}
+
 type CSIVolumeRegisterRequest struct {
 	Volumes []*CSIVolume
 	WriteRequest
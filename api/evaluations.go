@@ -43,6 +43,18 @@ func (e *Evaluations) Count(q *QueryOptions) (*EvalCountResponse, *QueryMeta, er
 	return resp, qm, nil
 }
 
+// PendingSummary is used to summarize the blocked evaluations of jobs, to
+// explain why their allocations can't be placed. The "job" query parameter
+// limits the summaries to a single job.
+func (e *Evaluations) PendingSummary(q *QueryOptions) ([]*JobPendingSummary, *QueryMeta, error) {
+	var resp []*JobPendingSummary
+	qm, err := e.client.query("/v1/evaluations/pending", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
 // Info is used to query a single evaluation by its ID.
 func (e *Evaluations) Info(evalID string, q *QueryOptions) (*Evaluation, *QueryMeta, error) {
 	var resp Evaluation
@@ -75,6 +87,23 @@ func (e *Evaluations) DeleteOpts(req *EvalDeleteRequest, w *WriteOptions) (*Eval
 	return resp, wm, nil
 }
 
+// BumpPriority is used to raise the priority of pending or blocked
+// evaluations, and their pending or blocked follow-up evaluations, so they
+// are processed before evaluations of lower priority. The IDs of the bumped
+// evaluations are returned.
+func (e *Evaluations) BumpPriority(evalIDs []string, priority int, w *WriteOptions) ([]string, *WriteMeta, error) {
+	req := EvalBumpPriorityRequest{
+		EvalIDs:  evalIDs,
+		Priority: priority,
+	}
+	var resp EvalBumpPriorityResponse
+	wm, err := e.client.put("/v1/evaluations/bump", &req, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.EvalIDs, wm, nil
+}
+
 // Allocations is used to retrieve a set of allocations given
 // an evaluation ID.
 func (e *Evaluations) Allocations(evalID string, q *QueryOptions) ([]*AllocationListStub, *QueryMeta, error) {
@@ -121,13 +150,22 @@ type Evaluation struct {
 	QuotaLimitReached    string
 	AnnotatePlan         bool
 	QueuedAllocations    map[string]int
+	PriorityBump         *EvalPriorityBump
 	SnapshotIndex        uint64
+	TraceParent          string
 	CreateIndex          uint64
 	ModifyIndex          uint64
 	CreateTime           int64
 	ModifyTime           int64
 }
 
+// EvalPriorityBump records an operator raising the priority of an evaluation.
+type EvalPriorityBump struct {
+	PreviousPriority int
+	BumpedBy         string
+	BumpTime         int64
+}
+
 // EvaluationStub is used to serialize parts of an evaluation returned in the
 // RelatedEvals field of an Evaluation.
 type EvaluationStub struct {
@@ -151,6 +189,25 @@ type EvaluationStub struct {
 	ModifyTime        int64
 }
 
+// JobPendingSummary digests the blocked evaluation of a job to explain why
+// some of its allocations can't be placed.
+type JobPendingSummary struct {
+	Namespace         string
+	JobID             string
+	EvalID            string
+	BlockedSince      int64
+	QuotaLimitReached string
+	TaskGroups        map[string]*TaskGroupPendingSummary
+}
+
+// TaskGroupPendingSummary explains why allocations of a task group can't be
+// placed.
+type TaskGroupPendingSummary struct {
+	Queued  int
+	Reasons []string
+	Metrics *AllocationMetric
+}
+
 type EvalDeleteRequest struct {
 	EvalIDs []string
 	Filter  string
@@ -161,6 +218,16 @@ type EvalDeleteResponse struct {
 	Count int
 }
 
+type EvalBumpPriorityRequest struct {
+	EvalIDs  []string
+	Priority int
+	WriteRequest
+}
+
+type EvalBumpPriorityResponse struct {
+	EvalIDs []string
+}
+
 type EvalCountResponse struct {
 	Count int
 	QueryMeta
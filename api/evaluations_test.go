@@ -198,6 +198,18 @@ func TestEvaluations_Allocations(t *testing.T) {
 	must.SliceEmpty(t, allocs)
 }
 
+func TestEvaluations_PendingSummary(t *testing.T) {
+	testutil.Parallel(t)
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	e := c.Evaluations()
+
+	// Returns empty if no evaluations are blocked
+	summaries, _, err := e.PendingSummary(nil)
+	must.NoError(t, err)
+	must.SliceEmpty(t, summaries)
+}
+
 func TestEvaluations_Sort(t *testing.T) {
 	testutil.Parallel(t)
 	evals := []*Evaluation{
@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+const (
+	// EventSinkTypeWebhook is the type of event sinks that POST batches of
+	// events to an HTTP endpoint.
+	EventSinkTypeWebhook = "webhook"
+
+	// EventSinkTypeKafka is the type of event sinks that produce events to a
+	// Kafka topic.
+	EventSinkTypeKafka = "kafka"
+
+	// EventSinkTypeNATS is the type of event sinks that publish events to a
+	// NATS subject.
+	EventSinkTypeNATS = "nats"
+)
+
+// EventSinks is used to access event sinks endpoints.
+type EventSinks struct {
+	client *Client
+}
+
+// EventSinks returns a handle on the event sinks endpoints.
+func (c *Client) EventSinks() *EventSinks {
+	return &EventSinks{client: c}
+}
+
+// List is used to list all event sinks.
+func (e *EventSinks) List(q *QueryOptions) ([]*EventSinkListStub, *QueryMeta, error) {
+	var resp []*EventSinkListStub
+	qm, err := e.client.query("/v1/event/sinks", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// PrefixList is used to list event sinks that match a given ID prefix.
+func (e *EventSinks) PrefixList(prefix string, q *QueryOptions) ([]*EventSinkListStub, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	q.Prefix = prefix
+	return e.List(q)
+}
+
+// Info is used to fetch details of a specific event sink.
+func (e *EventSinks) Info(id string, q *QueryOptions) (*EventSink, *QueryMeta, error) {
+	if id == "" {
+		return nil, nil, errors.New("missing event sink ID")
+	}
+
+	var resp EventSink
+	qm, err := e.client.query("/v1/event/sink/"+url.PathEscape(id), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Register is used to register or update an event sink.
+func (e *EventSinks) Register(sink *EventSink, w *WriteOptions) (*WriteMeta, error) {
+	if sink == nil {
+		return nil, errors.New("missing event sink")
+	}
+	if sink.ID == "" {
+		return nil, errors.New("missing event sink ID")
+	}
+
+	wm, err := e.client.put("/v1/event/sink/"+url.PathEscape(sink.ID), sink, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Deregister is used to deregister an event sink.
+func (e *EventSinks) Deregister(id string, w *WriteOptions) (*WriteMeta, error) {
+	if id == "" {
+		return nil, errors.New("missing event sink ID")
+	}
+
+	wm, err := e.client.delete("/v1/event/sink/"+url.PathEscape(id), nil, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// EventSink is a managed subscription to the event stream. The leader
+// delivers the events matching the sink topics to the sink address, at least
+// once.
+type EventSink struct {
+	ID   string
+	Type string
+
+	// Address is the URL of the endpoint for webhook sinks, a comma
+	// separated list of bootstrap brokers for Kafka sinks, and the URL of
+	// the server for NATS sinks.
+	Address   string
+	Topics    map[Topic][]string
+	Namespace string
+
+	Webhook *EventSinkWebhookConfig
+	Kafka   *EventSinkKafkaConfig
+	NATS    *EventSinkNATSConfig
+
+	// MinBackoff and MaxBackoff bound the exponential backoff between
+	// retries of a failed delivery.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// LatestIndex is the index of the last event delivered to the sink.
+	LatestIndex uint64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// EventSinkWebhookConfig is the configuration of webhook event sinks.
+type EventSinkWebhookConfig struct {
+	Headers map[string]string
+}
+
+// EventSinkKafkaConfig is the configuration of Kafka event sinks.
+type EventSinkKafkaConfig struct {
+	Topic    string
+	ClientID string
+}
+
+// EventSinkNATSConfig is the configuration of NATS event sinks.
+type EventSinkNATSConfig struct {
+	Subject string
+}
+
+// EventSinkListStub is the summary of an event sink returned when listing
+// sinks.
+type EventSinkListStub struct {
+	ID          string
+	Type        string
+	Address     string
+	Topics      map[Topic][]string
+	LatestIndex uint64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
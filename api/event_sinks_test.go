@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api/internal/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestEventSinks(t *testing.T) {
+	testutil.Parallel(t)
+
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	sinks := c.EventSinks()
+
+	sink := &EventSink{
+		ID:      "my-sink",
+		Type:    EventSinkTypeWebhook,
+		Address: "http://127.0.0.1:8080/events",
+		Topics: map[Topic][]string{
+			TopicJob: {"*"},
+		},
+	}
+	wm, err := sinks.Register(sink, nil)
+	must.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	list, _, err := sinks.PrefixList("my", nil)
+	must.NoError(t, err)
+	must.Len(t, 1, list)
+	must.Eq(t, EventSinkTypeWebhook, list[0].Type)
+
+	info, _, err := sinks.Info("my-sink", nil)
+	must.NoError(t, err)
+	must.Eq(t, "http://127.0.0.1:8080/events", info.Address)
+	must.Eq(t, "*", info.Namespace)
+	must.Positive(t, info.MinBackoff)
+
+	_, err = sinks.Deregister("my-sink", nil)
+	must.NoError(t, err)
+
+	_, _, err = sinks.Info("my-sink", nil)
+	must.ErrorContains(t, err, "not found")
+}
@@ -6,7 +6,10 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"strconv"
 	"time"
 
@@ -21,6 +24,7 @@ const (
 	TopicNode       Topic = "Node"
 	TopicNodePool   Topic = "NodePool"
 	TopicService    Topic = "Service"
+	TopicVariable   Topic = "Variable"
 	TopicAll        Topic = "*"
 )
 
@@ -90,6 +94,27 @@ func (e *Event) Job() (*Job, error) {
 	return out.Job, nil
 }
 
+// JobDiffSummary returns the summary of the changes from the previous version
+// of the job from a given event payload. It returns nil if the Event Topic is
+// not Job or the event is not for a new version of the job.
+func (e *Event) JobDiffSummary() (*JobDiffSummary, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.DiffSummary, nil
+}
+
+// JobNotification returns the notification of a completed job from a given
+// event payload. It returns nil unless the event is a JobNotification event.
+func (e *Event) JobNotification() (*JobNotification, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Notification, nil
+}
+
 // Node returns a Node struct from a given event payload. If the
 // Event Topic is Node this will return a valid Node.
 func (e *Event) Node() (*Node, error) {
@@ -120,14 +145,60 @@ func (e *Event) Service() (*ServiceRegistration, error) {
 	return out.Service, nil
 }
 
+// Variable returns the metadata of a Variable from a given event payload. If
+// the Event Topic is Variable this will return valid VariableMetadata. The
+// variable's items are never included in events.
+func (e *Event) Variable() (*VariableMetadata, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+	return out.Variable, nil
+}
+
+// Object returns the payload of the event decoded into the struct of its
+// Topic: *Allocation, *Deployment, *Evaluation, *Job, *Node, *NodePool,
+// *ServiceRegistration, or *VariableMetadata. It allows handling events of
+// several topics with a type switch.
+func (e *Event) Object() (any, error) {
+	out, err := e.decodePayload()
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.Topic {
+	case TopicAllocation:
+		return out.Allocation, nil
+	case TopicDeployment:
+		return out.Deployment, nil
+	case TopicEvaluation:
+		return out.Evaluation, nil
+	case TopicJob:
+		return out.Job, nil
+	case TopicNode:
+		return out.Node, nil
+	case TopicNodePool:
+		return out.NodePool, nil
+	case TopicService:
+		return out.Service, nil
+	case TopicVariable:
+		return out.Variable, nil
+	default:
+		return nil, fmt.Errorf("unsupported event topic %q", e.Topic)
+	}
+}
+
 type eventPayload struct {
-	Allocation *Allocation          `mapstructure:"Allocation"`
-	Deployment *Deployment          `mapstructure:"Deployment"`
-	Evaluation *Evaluation          `mapstructure:"Evaluation"`
-	Job        *Job                 `mapstructure:"Job"`
-	Node       *Node                `mapstructure:"Node"`
-	NodePool   *NodePool            `mapstructure:"NodePool"`
-	Service    *ServiceRegistration `mapstructure:"Service"`
+	Allocation   *Allocation          `mapstructure:"Allocation"`
+	Deployment   *Deployment          `mapstructure:"Deployment"`
+	Evaluation   *Evaluation          `mapstructure:"Evaluation"`
+	Job          *Job                 `mapstructure:"Job"`
+	DiffSummary  *JobDiffSummary      `mapstructure:"DiffSummary"`
+	Notification *JobNotification     `mapstructure:"Notification"`
+	Node         *Node                `mapstructure:"Node"`
+	NodePool     *NodePool            `mapstructure:"NodePool"`
+	Service      *ServiceRegistration `mapstructure:"Service"`
+	Variable     *VariableMetadata    `mapstructure:"Variable"`
 }
 
 func (e *Event) decodePayload() (*eventPayload, error) {
@@ -168,6 +239,12 @@ func (c *Client) EventStream() *EventStream {
 // Stream establishes a new subscription to Nomad's event stream and streams
 // results back to the returned channel.
 func (e *EventStream) Stream(ctx context.Context, topics map[Topic][]string, index uint64, q *QueryOptions) (<-chan *Events, error) {
+	return e.stream(ctx, topics, index, q, false)
+}
+
+// stream establishes a new subscription to Nomad's event stream. Heartbeats
+// are only sent to the returned channel if heartbeats is true.
+func (e *EventStream) stream(ctx context.Context, topics map[Topic][]string, index uint64, q *QueryOptions, heartbeats bool) (<-chan *Events, error) {
 	r, err := e.client.newRequest("GET", "/v1/event/stream")
 	if err != nil {
 		return nil, err
@@ -207,7 +284,7 @@ func (e *EventStream) Stream(ctx context.Context, topics map[Topic][]string, ind
 				// select eventsCh
 				events = Events{Err: err}
 			}
-			if events.Err == nil && events.IsHeartbeat() {
+			if events.Err == nil && events.IsHeartbeat() && !heartbeats {
 				continue
 			}
 
@@ -221,3 +298,255 @@ func (e *EventStream) Stream(ctx context.Context, topics map[Topic][]string, ind
 
 	return eventsCh, nil
 }
+
+// EventFilter builds the topics to subscribe to with EventStream.Stream or
+// EventStream.Subscribe.
+type EventFilter struct {
+	topics map[Topic][]string
+}
+
+// NewEventFilter returns an empty EventFilter.
+func NewEventFilter() *EventFilter {
+	return &EventFilter{topics: map[Topic][]string{}}
+}
+
+// Topic subscribes to the events of the topic whose key or filter keys match
+// one of the given keys, or to all events of the topic if no keys are given.
+func (f *EventFilter) Topic(topic Topic, keys ...string) *EventFilter {
+	if len(keys) == 0 {
+		keys = []string{"*"}
+	}
+	f.topics[topic] = append(f.topics[topic], keys...)
+	return f
+}
+
+// All subscribes to all events.
+func (f *EventFilter) All() *EventFilter { return f.Topic(TopicAll) }
+
+// Allocations subscribes to the events of the given allocation IDs, or of
+// the allocations of the given job IDs.
+func (f *EventFilter) Allocations(ids ...string) *EventFilter {
+	return f.Topic(TopicAllocation, ids...)
+}
+
+// Deployments subscribes to the events of the given deployment IDs, or of
+// the deployments of the given job IDs.
+func (f *EventFilter) Deployments(ids ...string) *EventFilter {
+	return f.Topic(TopicDeployment, ids...)
+}
+
+// Evaluations subscribes to the events of the given evaluation IDs, or of
+// the evaluations of the given job IDs.
+func (f *EventFilter) Evaluations(ids ...string) *EventFilter {
+	return f.Topic(TopicEvaluation, ids...)
+}
+
+// Jobs subscribes to the events of the given job IDs.
+func (f *EventFilter) Jobs(ids ...string) *EventFilter { return f.Topic(TopicJob, ids...) }
+
+// Nodes subscribes to the events of the given node IDs.
+func (f *EventFilter) Nodes(ids ...string) *EventFilter { return f.Topic(TopicNode, ids...) }
+
+// NodePools subscribes to the events of the given node pools.
+func (f *EventFilter) NodePools(names ...string) *EventFilter {
+	return f.Topic(TopicNodePool, names...)
+}
+
+// Services subscribes to the events of the given service names.
+func (f *EventFilter) Services(names ...string) *EventFilter {
+	return f.Topic(TopicService, names...)
+}
+
+// Variables subscribes to the events of the given variable paths.
+func (f *EventFilter) Variables(paths ...string) *EventFilter {
+	return f.Topic(TopicVariable, paths...)
+}
+
+// Topics returns the topics to subscribe to.
+func (f *EventFilter) Topics() map[Topic][]string {
+	topics := make(map[Topic][]string, len(f.topics))
+	for topic, keys := range f.topics {
+		topics[topic] = append([]string(nil), keys...)
+	}
+	return topics
+}
+
+const (
+	// defaultEventHeartbeatTimeout is how long Subscribe waits for events
+	// or heartbeats before reconnecting. Servers send heartbeats every 30s.
+	defaultEventHeartbeatTimeout = 1 * time.Minute
+
+	defaultEventMinRetryWait = 1 * time.Second
+	defaultEventMaxRetryWait = 30 * time.Second
+)
+
+// EventSubscribeOptions configures how EventStream.Subscribe reconnects.
+type EventSubscribeOptions struct {
+	// HeartbeatTimeout is how long to wait for events or heartbeats before
+	// the connection is considered lost. Defaults to 1 minute.
+	HeartbeatTimeout time.Duration
+
+	// MinRetryWait and MaxRetryWait bound the exponential backoff between
+	// reconnection attempts. Default to 1 and 30 seconds.
+	MinRetryWait time.Duration
+	MaxRetryWait time.Duration
+}
+
+func (o *EventSubscribeOptions) canonicalize() *EventSubscribeOptions {
+	out := new(EventSubscribeOptions)
+	if o != nil {
+		*out = *o
+	}
+	if out.HeartbeatTimeout <= 0 {
+		out.HeartbeatTimeout = defaultEventHeartbeatTimeout
+	}
+	if out.MinRetryWait <= 0 {
+		out.MinRetryWait = defaultEventMinRetryWait
+	}
+	if out.MaxRetryWait < out.MinRetryWait {
+		out.MaxRetryWait = defaultEventMaxRetryWait
+		if out.MaxRetryWait < out.MinRetryWait {
+			out.MaxRetryWait = out.MinRetryWait
+		}
+	}
+	return out
+}
+
+// Subscribe establishes a subscription to Nomad's event stream that survives
+// connection failures, and streams results back to the returned channel
+// until ctx is canceled.
+//
+// If the connection fails or no heartbeat is received within the heartbeat
+// timeout, Subscribe reconnects with an exponential backoff and resumes from
+// the index following the last received events, so each index is delivered
+// at most once and in order. Events older than the server's event buffer are
+// skipped. Connection errors are sent to the channel as Events with Err set
+// and the subscription continues, except for errors that retrying can't fix,
+// such as an invalid request or a permission denied, after which the channel
+// is closed.
+func (e *EventStream) Subscribe(ctx context.Context, topics map[Topic][]string, index uint64, opts *EventSubscribeOptions, q *QueryOptions) <-chan *Events {
+	opts = opts.canonicalize()
+
+	eventsCh := make(chan *Events, 10)
+	go func() {
+		defer close(eventsCh)
+
+		// next is the index to resume from
+		next := index
+
+		wait := opts.MinRetryWait
+		for ctx.Err() == nil {
+			received, err := e.subscribeOnce(ctx, topics, &next, opts, q, eventsCh)
+			if ctx.Err() != nil {
+				return
+			}
+			if received {
+				wait = opts.MinRetryWait
+			}
+
+			if err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case eventsCh <- &Events{Err: err}:
+				}
+				if !isRetryableEventStreamErr(err) {
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+			wait *= 2
+			if wait > opts.MaxRetryWait {
+				wait = opts.MaxRetryWait
+			}
+		}
+	}()
+
+	return eventsCh
+}
+
+// subscribeOnce streams events until the connection fails or ctx is
+// canceled. It returns whether any events were received.
+func (e *EventStream) subscribeOnce(ctx context.Context, topics map[Topic][]string, next *uint64,
+	opts *EventSubscribeOptions, q *QueryOptions, eventsCh chan<- *Events) (bool, error) {
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Copy the query options as Stream sets the index in their params
+	qCopy := new(QueryOptions)
+	if q != nil {
+		*qCopy = *q
+	}
+	qCopy.Params = make(map[string]string, len(qCopy.Params))
+	if q != nil {
+		for k, v := range q.Params {
+			qCopy.Params[k] = v
+		}
+	}
+
+	stream, err := e.stream(ctx, topics, *next, qCopy, true)
+	if err != nil {
+		return false, err
+	}
+
+	received := false
+	timer := time.NewTimer(opts.HeartbeatTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return received, nil
+		case <-timer.C:
+			return received, fmt.Errorf("no events or heartbeats received for %s", opts.HeartbeatTimeout)
+		case events, ok := <-stream:
+			if !ok {
+				return received, nil
+			}
+			if errors.Is(events.Err, io.EOF) {
+				// The server closed the connection
+				return received, nil
+			} else if events.Err != nil {
+				return received, events.Err
+			}
+
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(opts.HeartbeatTimeout)
+
+			// The server resumes at the closest index it has, which may be
+			// the last one already sent
+			if events.IsHeartbeat() || events.Index < *next {
+				continue
+			}
+
+			select {
+			case <-ctx.Done():
+				return received, nil
+			case eventsCh <- events:
+			}
+			*next = events.Index + 1
+			received = true
+		}
+	}
+}
+
+// isRetryableEventStreamErr returns false for errors that reconnecting to
+// the event stream can't fix.
+func isRetryableEventStreamErr(err error) bool {
+	var uerr UnexpectedResponseError
+	if errors.As(err, &uerr) {
+		switch uerr.StatusCode() {
+		case http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound:
+			return false
+		}
+	}
+	return true
+}
@@ -6,6 +6,9 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -342,3 +345,97 @@ func TestEventStream_PayloadValueHelpers(t *testing.T) {
 		})
 	}
 }
+
+func TestEvent_Object(t *testing.T) {
+	testutil.Parallel(t)
+
+	event := Event{
+		Topic:   TopicJob,
+		Payload: map[string]interface{}{"Job": map[string]interface{}{"ID": "example"}},
+	}
+	obj, err := event.Object()
+	must.NoError(t, err)
+	job, ok := obj.(*Job)
+	must.True(t, ok)
+	must.Eq(t, "example", *job.ID)
+
+	event.Topic = "Unknown"
+	_, err = event.Object()
+	must.ErrorContains(t, err, "unsupported event topic")
+}
+
+func TestEventFilter(t *testing.T) {
+	testutil.Parallel(t)
+
+	topics := NewEventFilter().
+		Jobs("web", "api").
+		Allocations().
+		Nodes("node1").
+		Jobs("batch").
+		Topics()
+
+	must.Eq(t, map[Topic][]string{
+		TopicJob:        {"web", "api", "batch"},
+		TopicAllocation: {"*"},
+		TopicNode:       {"node1"},
+	}, topics)
+}
+
+func TestEventStream_Subscribe(t *testing.T) {
+	testutil.Parallel(t)
+
+	// Each connection is answered with the next response, and the requested
+	// indexes are recorded
+	responses := [][]Events{
+		{{Index: 5}, {Index: 6}},
+		{{Index: 6}, {}, {Index: 7}},
+	}
+	var lock sync.Mutex
+	indexes := []string{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		indexes = append(indexes, r.URL.Query().Get("index"))
+		if len(responses) == 0 {
+			http.Error(w, "Permission denied", http.StatusForbidden)
+			return
+		}
+
+		enc := json.NewEncoder(w)
+		for _, events := range responses[0] {
+			must.NoError(t, enc.Encode(events))
+		}
+		responses = responses[1:]
+	}))
+	defer srv.Close()
+
+	conf := DefaultConfig()
+	conf.Address = srv.URL
+	client, err := NewClient(conf)
+	must.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	opts := &EventSubscribeOptions{MinRetryWait: 10 * time.Millisecond}
+	eventsCh := client.EventStream().Subscribe(ctx, NewEventFilter().All().Topics(), 5, opts, nil)
+
+	received := []uint64{}
+	var errs []error
+	for events := range eventsCh {
+		if events.Err != nil {
+			errs = append(errs, events.Err)
+			continue
+		}
+		received = append(received, events.Index)
+	}
+
+	// Each index is received once, and the subscription stops on a
+	// permission denied error
+	must.Eq(t, []uint64{5, 6, 7}, received)
+	must.Eq(t, []string{"5", "7", "8"}, indexes)
+	must.Len(t, 1, errs)
+	must.False(t, isRetryableEventStreamErr(errs[0]))
+}
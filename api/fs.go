@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"strconv"
 	"sync"
 	"time"
@@ -137,6 +138,39 @@ func (a *AllocFS) Cat(alloc *Allocation, path string, q *QueryOptions) (io.ReadC
 		})
 }
 
+// Archive is used to read a tar archive of the file or directory at the given
+// path of an allocation. Entries of the archive are named relative to the
+// parent of the path. The caller must close the returned reader.
+func (a *AllocFS) Archive(alloc *Allocation, path string, q *QueryOptions) (io.ReadCloser, error) {
+	reqPath := fmt.Sprintf("/v1/client/fs/archive/%s", alloc.ID)
+	return queryClientNode(a.client, alloc, reqPath, q,
+		func(q *QueryOptions) {
+			q.Params["path"] = path
+		})
+}
+
+// Extract is used to extract the tar archive read from r into the directory
+// at the given path of an allocation. The request is always sent to the
+// configured agent since the archive can't be replayed against another node.
+func (a *AllocFS) Extract(alloc *Allocation, path string, archive io.Reader, q *WriteOptions) (*WriteMeta, error) {
+	r, err := a.client.newRequest(http.MethodPut, fmt.Sprintf("/v1/client/fs/extract/%s", alloc.ID))
+	if err != nil {
+		return nil, err
+	}
+	r.setWriteOptions(q)
+	r.params.Set("path", path)
+	r.body = archive
+	rtt, resp, err := requireOK(a.client.doRequest(r)) //nolint:bodyclose // Closing the body is the caller's responsibility.
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	wm := &WriteMeta{RequestTime: rtt}
+	parseWriteMeta(resp, wm)
+	return wm, nil
+}
+
 // Stream streams the content of a file blocking on EOF.
 // The parameters are:
 // * path: path to file to stream.
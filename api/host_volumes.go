@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"net/url"
+)
+
+// HostVolumes is used to create and delete host volumes on clients with host
+// volume plugins.
+type HostVolumes struct {
+	client *Client
+}
+
+// HostVolumes returns a handle on the host volumes endpoints.
+func (c *Client) HostVolumes() *HostVolumes {
+	return &HostVolumes{client: c}
+}
+
+// HostVolumeCreateRequest is the host volume to create with a host volume
+// plugin.
+type HostVolumeCreateRequest struct {
+	// NodeID is the node the volume is created on. If empty, the servers
+	// pick a ready node in NodePool with the plugin and enough capacity.
+	NodeID   string
+	NodePool string
+
+	Name     string
+	PluginID string
+
+	RequestedCapacityMinBytes int64
+	RequestedCapacityMaxBytes int64
+
+	// Parameters are opaque values passed to the plugin.
+	Parameters map[string]string
+}
+
+// HostVolumeCreateResponse is the host volume created on the client.
+type HostVolumeCreateResponse struct {
+	NodeID        string
+	Name          string
+	Path          string
+	CapacityBytes int64
+}
+
+// Create creates a host volume with a host volume plugin.
+func (v *HostVolumes) Create(req *HostVolumeCreateRequest, w *WriteOptions) (*HostVolumeCreateResponse, *WriteMeta, error) {
+	var resp HostVolumeCreateResponse
+	meta, err := v.client.put("/v1/volume/host/create", req, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, meta, nil
+}
+
+// Delete deletes a host volume created by a host volume plugin on the node.
+func (v *HostVolumes) Delete(nodeID, name string, w *WriteOptions) (*WriteMeta, error) {
+	qp := url.Values{}
+	qp.Set("node_id", nodeID)
+	return v.client.delete("/v1/volume/host/"+url.PathEscape(name)+"?"+qp.Encode(), nil, nil, w)
+}
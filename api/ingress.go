@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+const (
+	// IngressProtocolTCP forwards the connections accepted by an ingress
+	// listener to the instances of a single service.
+	IngressProtocolTCP = "tcp"
+
+	// IngressProtocolHTTP routes the HTTP requests received by an ingress
+	// listener to services based on their host and path.
+	IngressProtocolHTTP = "http"
+)
+
+// Ingress configures the proxy the client runs for the allocations of a task
+// group, routing external traffic to Nomad services.
+type Ingress struct {
+	Listeners []*IngressListener `hcl:"listener,block"`
+}
+
+// IngressListener accepts the traffic of a port of the group network.
+type IngressListener struct {
+	Port     string          `hcl:"port,optional"`
+	Protocol string          `hcl:"protocol,optional"`
+	Service  string          `hcl:"service,optional"`
+	Routes   []*IngressRoute `hcl:"route,block"`
+}
+
+// IngressRoute routes the HTTP requests matching its host and path prefix to
+// a Nomad service.
+type IngressRoute struct {
+	Host       string `hcl:"host,optional"`
+	PathPrefix string `mapstructure:"path_prefix" hcl:"path_prefix,optional"`
+	Service    string `hcl:"service,optional"`
+}
+
+func (i *Ingress) Canonicalize() {
+	if i == nil {
+		return
+	}
+	for _, l := range i.Listeners {
+		if l.Protocol == "" {
+			l.Protocol = IngressProtocolTCP
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// JobLogLine is a single line written by a task of a job to its stdout or
+// stderr.
+type JobLogLine struct {
+	// Timestamp is the time the line was read by the agent serving the
+	// request, as tasks' output is not timestamped.
+	Timestamp time.Time
+
+	AllocID   string
+	TaskGroup string
+	Task      string
+
+	// Stream is either "stdout" or "stderr".
+	Stream string
+
+	Line string
+}
+
+// JobLogsOptions are the options for streaming the logs of a job.
+type JobLogsOptions struct {
+	// Follow keeps the stream open, including allocations placed after the
+	// stream was started.
+	Follow bool
+
+	// Task limits the stream to tasks with the given name.
+	Task string
+
+	// LogType limits the stream to "stdout" or "stderr". Both are streamed if
+	// empty.
+	LogType string
+
+	// Origin and Offset are applied to each task log stream, as in
+	// AllocFS.Logs.
+	Origin string
+	Offset int64
+}
+
+// Logs streams the stdout and stderr of all the tasks of all the allocations
+// of a job as structured lines. The logs are streamed until there are no
+// more logs, or if following, until the cancel channel is closed. Unexpected
+// (non-EOF) errors will be sent on the error chan.
+func (j *Jobs) Logs(jobID string, opts *JobLogsOptions, cancel <-chan struct{},
+	q *QueryOptions) (<-chan *JobLogLine, <-chan error) {
+
+	errCh := make(chan error, 1)
+
+	if opts == nil {
+		opts = &JobLogsOptions{}
+	}
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	if q.Params == nil {
+		q.Params = make(map[string]string)
+	}
+	q.Params["follow"] = strconv.FormatBool(opts.Follow)
+	q.Params["task"] = opts.Task
+	q.Params["type"] = opts.LogType
+	q.Params["origin"] = opts.Origin
+	q.Params["offset"] = strconv.FormatInt(opts.Offset, 10)
+
+	r, err := j.client.rawQuery("/v1/job/"+url.PathEscape(jobID)+"/logs", q)
+	if err != nil {
+		errCh <- err
+		return nil, errCh
+	}
+
+	lines := make(chan *JobLogLine, 10)
+
+	go func() {
+		defer r.Close()
+		defer close(lines)
+
+		// Close the body when cancelled to unblock the decoder
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-cancel:
+				r.Close()
+			case <-done:
+			}
+		}()
+
+		dec := json.NewDecoder(r)
+		for {
+			var line JobLogLine
+			if err := dec.Decode(&line); err != nil {
+				select {
+				case <-cancel:
+				default:
+					if err != io.EOF && err != io.ErrClosedPipe {
+						errCh <- fmt.Errorf("failed to decode job logs response: %w", err)
+					}
+				}
+				return
+			}
+
+			select {
+			case lines <- &line:
+			case <-cancel:
+				return
+			}
+		}
+	}()
+
+	return lines, errCh
+}
@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// JobTemplates is used to access job templates endpoints.
+type JobTemplates struct {
+	client *Client
+}
+
+// JobTemplates returns a handle on the job templates endpoints.
+func (c *Client) JobTemplates() *JobTemplates {
+	return &JobTemplates{client: c}
+}
+
+// List is used to list all job templates.
+func (j *JobTemplates) List(q *QueryOptions) ([]*JobTemplateListStub, *QueryMeta, error) {
+	var resp []*JobTemplateListStub
+	qm, err := j.client.query("/v1/job-templates", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// PrefixList is used to list job templates that match a given prefix.
+func (j *JobTemplates) PrefixList(prefix string, q *QueryOptions) ([]*JobTemplateListStub, *QueryMeta, error) {
+	if q == nil {
+		q = &QueryOptions{}
+	}
+	q.Prefix = prefix
+	return j.List(q)
+}
+
+// Info is used to fetch details of a specific job template.
+func (j *JobTemplates) Info(name string, q *QueryOptions) (*JobTemplate, *QueryMeta, error) {
+	if name == "" {
+		return nil, nil, errors.New("missing job template name")
+	}
+
+	var resp JobTemplate
+	qm, err := j.client.query("/v1/job-template/"+url.PathEscape(name), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Register is used to create or update a job template.
+func (j *JobTemplates) Register(tmpl *JobTemplate, w *WriteOptions) (*WriteMeta, error) {
+	if tmpl == nil {
+		return nil, errors.New("missing job template")
+	}
+	if tmpl.Name == "" {
+		return nil, errors.New("missing job template name")
+	}
+
+	wm, err := j.client.put("/v1/job-template/"+url.PathEscape(tmpl.Name), tmpl, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Delete is used to delete a job template.
+func (j *JobTemplates) Delete(name string, w *WriteOptions) (*WriteMeta, error) {
+	if name == "" {
+		return nil, errors.New("missing job template name")
+	}
+
+	wm, err := j.client.delete("/v1/job-template/"+url.PathEscape(name), nil, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Render is used to render a job from a job template and values for its
+// variables. The job is not registered.
+func (j *JobTemplates) Render(name string, req *JobTemplateRenderRequest, q *WriteOptions) (*JobTemplateRenderResponse, *WriteMeta, error) {
+	if name == "" {
+		return nil, nil, errors.New("missing job template name")
+	}
+	if req == nil {
+		req = &JobTemplateRenderRequest{}
+	}
+
+	var resp JobTemplateRenderResponse
+	wm, err := j.client.put(fmt.Sprintf("/v1/job-template/%s/render", url.PathEscape(name)), req, &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// JobTemplate is a parameterized job registered by operators. Its source is an
+// HCL2 jobspec whose variable blocks declare the parameters of the template.
+type JobTemplate struct {
+	Name        string
+	Description string
+	Source      string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// JobTemplateListStub is the summary of a job template returned when listing
+// templates.
+type JobTemplateListStub struct {
+	Name        string
+	Description string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// JobTemplateRenderRequest is used to render a job from a job template.
+type JobTemplateRenderRequest struct {
+	// VariableFlags are the values of variables, as with the -var flag.
+	VariableFlags map[string]string
+
+	// Variables is interpreted as if it were the content of a variables
+	// file.
+	Variables string
+
+	// Canonicalize is a flag as to if the server should return default values
+	// for unset fields.
+	Canonicalize bool
+}
+
+// JobTemplateRenderResponse is the response of rendering a job template.
+type JobTemplateRenderResponse struct {
+	Job *Job
+
+	// Submission is the source of the template and the variables used to
+	// render the job, so it can be submitted along with the job.
+	Submission *JobSubmission
+}
@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/api/internal/testutil"
+	"github.com/shoenig/test/must"
+)
+
+const testJobTemplateSource = `
+variable "image" {
+  type = string
+}
+
+job "web" {
+  group "web" {
+    task "server" {
+      driver = "docker"
+
+      config {
+        image = var.image
+      }
+    }
+  }
+}
+`
+
+func TestJobTemplates(t *testing.T) {
+	testutil.Parallel(t)
+
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	templates := c.JobTemplates()
+
+	tmpl := &JobTemplate{
+		Name:        "web-service",
+		Description: "web service",
+		Source:      testJobTemplateSource,
+	}
+	wm, err := templates.Register(tmpl, nil)
+	must.NoError(t, err)
+	assertWriteMeta(t, wm)
+
+	list, _, err := templates.PrefixList("web", nil)
+	must.NoError(t, err)
+	must.Len(t, 1, list)
+	must.Eq(t, "web service", list[0].Description)
+
+	info, _, err := templates.Info("web-service", nil)
+	must.NoError(t, err)
+	must.Eq(t, testJobTemplateSource, info.Source)
+
+	rendered, _, err := templates.Render("web-service", &JobTemplateRenderRequest{
+		VariableFlags: map[string]string{"image": "nginx:1.25"},
+	}, nil)
+	must.NoError(t, err)
+	must.Eq(t, "web", *rendered.Job.ID)
+	must.Eq(t, "nginx:1.25", rendered.Job.TaskGroups[0].Tasks[0].Config["image"])
+	must.Eq(t, map[string]string{"image": "nginx:1.25"}, rendered.Submission.VariableFlags)
+
+	_, err = templates.Delete("web-service", nil)
+	must.NoError(t, err)
+
+	_, _, err = templates.Info("web-service", nil)
+	must.ErrorContains(t, err, "not found")
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/hashicorp/cronexpr"
 	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 const (
@@ -264,6 +265,37 @@ func (j *Jobs) Versions(jobID string, diffs bool, q *QueryOptions) ([]*Job, []*J
 	return resp.Versions, resp.Diffs, qm, nil
 }
 
+// VersionsWithDiffs is used to retrieve all versions of a particular job
+// along with the diffs between consecutive versions and their machine
+// readable summaries.
+func (j *Jobs) VersionsWithDiffs(jobID string, q *QueryOptions) (*JobVersionsResponse, *QueryMeta, error) {
+	var resp JobVersionsResponse
+	qm, err := j.client.query(fmt.Sprintf("/v1/job/%s/versions?diffs=true", url.PathEscape(jobID)), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// VersionDiff is used to diff two versions of a job. If to is nil the latest
+// version is used, and if from is nil the version preceding to is used.
+func (j *Jobs) VersionDiff(jobID string, from, to *uint64, q *QueryOptions) (*JobVersionDiffResponse, *QueryMeta, error) {
+	v := url.Values{}
+	if from != nil {
+		v.Set("from", strconv.FormatUint(*from, 10))
+	}
+	if to != nil {
+		v.Set("to", strconv.FormatUint(*to, 10))
+	}
+
+	var resp JobVersionDiffResponse
+	qm, err := j.client.query(fmt.Sprintf("/v1/job/%s/diff?%s", url.PathEscape(jobID), v.Encode()), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
 // Submission is used to retrieve the original submitted source of a job given its
 // namespace, jobID, and version number. The original source might not be available,
 // which case nil is returned with no error.
@@ -438,6 +470,31 @@ func (j *Jobs) PeriodicForce(jobID string, q *WriteOptions) (string, *WriteMeta,
 type PlanOptions struct {
 	Diff           bool
 	PolicyOverride bool
+
+	// WhatIf holds hypothetical changes to the cluster to plan the job
+	// against. The changes are never applied to the cluster itself.
+	WhatIf *JobPlanWhatIf
+}
+
+// JobPlanWhatIf describes hypothetical changes to the cluster to plan a job
+// against.
+type JobPlanWhatIf struct {
+	// AddNodes are groups of hypothetical nodes added to the cluster.
+	AddNodes []*JobPlanWhatIfNodes
+
+	// DrainNodes are the IDs, or unique ID prefixes, of nodes that are
+	// drained.
+	DrainNodes []string
+}
+
+// JobPlanWhatIfNodes adds Count hypothetical nodes to the cluster that are
+// copies of an existing node of the given node class.
+type JobPlanWhatIfNodes struct {
+	NodeClass string
+	Count     int
+
+	// Datacenter overrides the datacenter of the copied node if set.
+	Datacenter string
 }
 
 func (j *Jobs) Plan(job *Job, diff bool, q *WriteOptions) (*JobPlanResponse, *WriteMeta, error) {
@@ -460,6 +517,7 @@ func (j *Jobs) PlanOpts(job *Job, opts *PlanOptions, q *WriteOptions) (*JobPlanR
 	if opts != nil {
 		req.Diff = opts.Diff
 		req.PolicyOverride = opts.PolicyOverride
+		req.WhatIf = opts.WhatIf
 	}
 
 	var resp JobPlanResponse
@@ -481,14 +539,19 @@ func (j *Jobs) Summary(jobID string, q *QueryOptions) (*JobSummary, *QueryMeta,
 
 func (j *Jobs) Dispatch(jobID string, meta map[string]string,
 	payload []byte, idPrefixTemplate string, q *WriteOptions) (*JobDispatchResponse, *WriteMeta, error) {
-	var resp JobDispatchResponse
-	req := &JobDispatchRequest{
+	return j.DispatchOpts(&JobDispatchRequest{
 		JobID:            jobID,
 		Meta:             meta,
 		Payload:          payload,
 		IdPrefixTemplate: idPrefixTemplate,
-	}
-	wm, err := j.client.put("/v1/job/"+url.PathEscape(jobID)+"/dispatch", req, &resp, q)
+	}, q)
+}
+
+// DispatchOpts is used to dispatch a parameterized job with all the options of
+// the dispatch request.
+func (j *Jobs) DispatchOpts(req *JobDispatchRequest, q *WriteOptions) (*JobDispatchResponse, *WriteMeta, error) {
+	var resp JobDispatchResponse
+	wm, err := j.client.put("/v1/job/"+url.PathEscape(req.JobID)+"/dispatch", req, &resp, q)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -557,6 +620,15 @@ type UpdateStrategy struct {
 	Canary           *int           `mapstructure:"canary" hcl:"canary,optional"`
 	AutoRevert       *bool          `mapstructure:"auto_revert" hcl:"auto_revert,optional"`
 	AutoPromote      *bool          `mapstructure:"auto_promote" hcl:"auto_promote,optional"`
+	Strategy         *string        `mapstructure:"strategy" hcl:"strategy,optional"`
+
+	// CanaryConstraints selects the nodes of a system job that are updated
+	// before all others.
+	CanaryConstraints []*Constraint `mapstructure:"canary_constraint" hcl:"canary_constraint,block"`
+
+	// CanaryPlacement is the preference for placing canaries on nodes that
+	// are, or aren't, running the previous version; "distinct" or "same".
+	CanaryPlacement *string `mapstructure:"canary_placement" hcl:"canary_placement,optional"`
 }
 
 // DefaultUpdateStrategy provides a baseline that can be used to upgrade
@@ -572,6 +644,7 @@ func DefaultUpdateStrategy() *UpdateStrategy {
 		AutoRevert:       pointerOf(false),
 		Canary:           pointerOf(0),
 		AutoPromote:      pointerOf(false),
+		Strategy:         pointerOf("rolling"),
 	}
 }
 
@@ -618,6 +691,21 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 		copy.AutoPromote = pointerOf(*u.AutoPromote)
 	}
 
+	if u.Strategy != nil {
+		copy.Strategy = pointerOf(*u.Strategy)
+	}
+
+	if u.CanaryConstraints != nil {
+		copy.CanaryConstraints = make([]*Constraint, len(u.CanaryConstraints))
+		for i, c := range u.CanaryConstraints {
+			copy.CanaryConstraints[i] = NewConstraint(c.LTarget, c.Operand, c.RTarget)
+		}
+	}
+
+	if u.CanaryPlacement != nil {
+		copy.CanaryPlacement = pointerOf(*u.CanaryPlacement)
+	}
+
 	return copy
 }
 
@@ -661,6 +749,18 @@ func (u *UpdateStrategy) Merge(o *UpdateStrategy) {
 	if o.AutoPromote != nil {
 		u.AutoPromote = pointerOf(*o.AutoPromote)
 	}
+
+	if o.Strategy != nil {
+		u.Strategy = pointerOf(*o.Strategy)
+	}
+
+	if o.CanaryConstraints != nil {
+		u.CanaryConstraints = o.Copy().CanaryConstraints
+	}
+
+	if o.CanaryPlacement != nil {
+		u.CanaryPlacement = pointerOf(*o.CanaryPlacement)
+	}
 }
 
 func (u *UpdateStrategy) Canonicalize() {
@@ -701,6 +801,10 @@ func (u *UpdateStrategy) Canonicalize() {
 	if u.AutoPromote == nil {
 		u.AutoPromote = d.AutoPromote
 	}
+
+	if u.Strategy == nil {
+		u.Strategy = d.Strategy
+	}
 }
 
 // Empty returns whether the UpdateStrategy is empty or has user defined values.
@@ -745,6 +849,18 @@ func (u *UpdateStrategy) Empty() bool {
 		return false
 	}
 
+	if u.Strategy != nil && *u.Strategy != "" {
+		return false
+	}
+
+	if len(u.CanaryConstraints) != 0 {
+		return false
+	}
+
+	if u.CanaryPlacement != nil && *u.CanaryPlacement != "" {
+		return false
+	}
+
 	return true
 }
 
@@ -909,6 +1025,64 @@ type ParameterizedJobConfig struct {
 	Payload      string   `hcl:"payload,optional"`
 	MetaRequired []string `mapstructure:"meta_required" hcl:"meta_required,optional"`
 	MetaOptional []string `mapstructure:"meta_optional" hcl:"meta_optional,optional"`
+
+	// MaxConcurrent is the maximum number of dispatched jobs that run at the
+	// same time, beyond which dispatched jobs are queued.
+	MaxConcurrent int `mapstructure:"max_concurrent" hcl:"max_concurrent,optional"`
+
+	// QueueLimit is the maximum number of queued dispatched jobs, beyond which
+	// dispatches are rejected.
+	QueueLimit int `mapstructure:"queue_limit" hcl:"queue_limit,optional"`
+}
+
+const (
+	// JobNotifyComplete is the outcome of batch jobs whose allocations all
+	// completed successfully.
+	JobNotifyComplete = "complete"
+
+	// JobNotifyFailed is the outcome of batch jobs with failed or lost
+	// allocations that were not replaced.
+	JobNotifyFailed = "failed"
+)
+
+// JobNotify is used to configure the notification sent once a batch or
+// sysbatch job completes.
+type JobNotify struct {
+	// Webhook is the URL the notification is POSTed to.
+	Webhook string `hcl:"webhook,optional"`
+
+	// Secret is the key used to sign the body of webhook notifications with
+	// HMAC-SHA256, in the X-Nomad-Signature header.
+	Secret string `hcl:"secret,optional"`
+
+	// Headers are the extra headers of webhook notifications.
+	Headers map[string]string `hcl:"headers,optional"`
+
+	// On is the outcomes of the job that are notified, "complete" and
+	// "failed". It defaults to both.
+	On []string `hcl:"on,optional"`
+}
+
+// JobNotification is the notification of a completed batch job.
+type JobNotification struct {
+	Namespace  string
+	JobID      string
+	ParentID   string
+	Version    uint64
+	Status     string
+	StartTime  int64
+	EndTime    int64
+	Stats      JobNotificationStats
+	TaskGroups map[string]*JobNotificationStats
+}
+
+// JobNotificationStats counts the allocations of a completed job.
+type JobNotificationStats struct {
+	Allocations int
+	Complete    int
+	Failed      int
+	Lost        int
+	Rescheduled int
 }
 
 // JobSubmission is used to hold information about the original content of a job
@@ -967,6 +1141,7 @@ type Job struct {
 	Type             *string                 `hcl:"type,optional"`
 	Priority         *int                    `hcl:"priority,optional"`
 	AllAtOnce        *bool                   `mapstructure:"all_at_once" hcl:"all_at_once,optional"`
+	Prefetch         *bool                   `hcl:"prefetch,optional"`
 	Datacenters      []string                `hcl:"datacenters,optional"`
 	NodePool         *string                 `mapstructure:"node_pool" hcl:"node_pool,optional"`
 	Constraints      []*Constraint           `hcl:"constraint,block"`
@@ -977,6 +1152,7 @@ type Job struct {
 	Spreads          []*Spread               `hcl:"spread,block"`
 	Periodic         *PeriodicConfig         `hcl:"periodic,block"`
 	ParameterizedJob *ParameterizedJobConfig `hcl:"parameterized,block"`
+	Notify           *JobNotify              `hcl:"notify,block"`
 	Reschedule       *ReschedulePolicy       `hcl:"reschedule,block"`
 	Migrate          *MigrateStrategy        `hcl:"migrate,block"`
 	Meta             map[string]string       `hcl:"meta,block"`
@@ -989,6 +1165,9 @@ type Job struct {
 	ParentID                 *string
 	Dispatched               bool
 	DispatchIdempotencyToken *string
+	RegisterIdempotencyToken *string
+	DispatchQueued           bool
+	NotifyPending            bool
 	Payload                  []byte
 	ConsulNamespace          *string `mapstructure:"consul_namespace"`
 	VaultNamespace           *string `mapstructure:"vault_namespace"`
@@ -1389,6 +1568,7 @@ type JobPlanRequest struct {
 	Job            *Job
 	Diff           bool
 	PolicyOverride bool
+	WhatIf         *JobPlanWhatIf
 	WriteRequest
 }
 
@@ -1403,6 +1583,10 @@ type JobPlanResponse struct {
 	// Warnings contains any warnings about the given job. These may include
 	// deprecation warnings.
 	Warnings string
+
+	// WhatIfPlacements maps the name of each hypothetical node added by the
+	// plan to the number of allocations placed on it.
+	WhatIfPlacements map[string]int
 }
 
 type JobDiff struct {
@@ -1438,15 +1622,17 @@ type FieldDiff struct {
 }
 
 type ObjectDiff struct {
-	Type    string
-	Name    string
-	Fields  []*FieldDiff
-	Objects []*ObjectDiff
+	Type        string
+	Name        string
+	Fields      []*FieldDiff
+	Objects     []*ObjectDiff
+	Annotations []string
 }
 
 type PlanAnnotations struct {
 	DesiredTGUpdates map[string]*DesiredUpdates
 	PreemptedAllocs  []*AllocationListStub
+	PrefetchNodes    []string
 }
 
 type DesiredUpdates struct {
@@ -1465,6 +1651,10 @@ type JobDispatchRequest struct {
 	Payload          []byte
 	Meta             map[string]string
 	IdPrefixTemplate string
+
+	// Priority overrides the priority of the dispatched job, which also
+	// orders the queued dispatched jobs of the parameterized job.
+	Priority int
 }
 
 type JobDispatchResponse struct {
@@ -1472,6 +1662,11 @@ type JobDispatchResponse struct {
 	EvalID          string
 	EvalCreateIndex uint64
 	JobCreateIndex  uint64
+
+	// Queued is set if the dispatched job is queued until the parameterized
+	// job is below its concurrency limit.
+	Queued bool
+
 	WriteMeta
 }
 
@@ -1479,9 +1674,54 @@ type JobDispatchResponse struct {
 type JobVersionsResponse struct {
 	Versions []*Job
 	Diffs    []*JobDiff
+
+	// DiffSummaries are the machine readable summaries of the Diffs, in the
+	// same order.
+	DiffSummaries []*JobDiffSummary
+	QueryMeta
+}
+
+// JobVersionDiffResponse is used for a job version diff request
+type JobVersionDiffResponse struct {
+	Diff    *JobDiff
+	Summary *JobDiffSummary
 	QueryMeta
 }
 
+// JobDiffSummary is a machine readable summary of the diff of two versions of
+// a job, listing the changed fields and their categories.
+type JobDiffSummary struct {
+	FromVersion uint64
+	ToVersion   uint64
+	Type        string
+	Changes     []*JobDiffChange
+
+	// Categories is the sorted set of categories of the changes, such as
+	// "image", "config", "count", "env", or "other".
+	Categories []string
+}
+
+// OnlyCategories returns whether all the changes of the summary are in the
+// given categories, for example to check that only images changed.
+func (s *JobDiffSummary) OnlyCategories(categories ...string) bool {
+	for _, c := range s.Categories {
+		if !slices.Contains(categories, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// JobDiffChange is a changed field of a job diff.
+type JobDiffChange struct {
+	// Path is the path of the field from the job, for example
+	// TaskGroups[web].Tasks[server].Config.image
+	Path     string
+	Category string
+	Type     string
+	Old, New string
+}
+
 // JobSubmissionResponse is used for a job get submission request
 type JobSubmissionResponse struct {
 	Submission *JobSubmission
@@ -309,6 +309,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoRevert:       pointerOf(false),
 					Canary:           pointerOf(0),
 					AutoPromote:      pointerOf(false),
+					Strategy:         pointerOf("rolling"),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -349,6 +350,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							AutoRevert:       pointerOf(false),
 							Canary:           pointerOf(0),
 							AutoPromote:      pointerOf(false),
+							Strategy:         pointerOf("rolling"),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -490,6 +492,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoRevert:       pointerOf(false),
 					Canary:           pointerOf(0),
 					AutoPromote:      pointerOf(false),
+					Strategy:         pointerOf("rolling"),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -530,6 +533,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							AutoRevert:       pointerOf(false),
 							Canary:           pointerOf(0),
 							AutoPromote:      pointerOf(false),
+							Strategy:         pointerOf("rolling"),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -667,6 +671,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoRevert:       pointerOf(false),
 					Canary:           pointerOf(0),
 					AutoPromote:      pointerOf(true),
+					Strategy:         pointerOf("rolling"),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -707,6 +712,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							AutoRevert:       pointerOf(true),
 							Canary:           pointerOf(0),
 							AutoPromote:      pointerOf(true),
+							Strategy:         pointerOf("rolling"),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -839,6 +845,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoRevert:       pointerOf(false),
 					Canary:           pointerOf(0),
 					AutoPromote:      pointerOf(false),
+					Strategy:         pointerOf("rolling"),
 				},
 				Periodic: &PeriodicConfig{
 					Enabled:         pointerOf(true),
@@ -933,6 +940,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoRevert:       pointerOf(false),
 					Canary:           pointerOf(0),
 					AutoPromote:      pointerOf(false),
+					Strategy:         pointerOf("rolling"),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -973,6 +981,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							AutoRevert:       pointerOf(true),
 							Canary:           pointerOf(1),
 							AutoPromote:      pointerOf(true),
+							Strategy:         pointerOf("rolling"),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -1023,6 +1032,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							AutoRevert:       pointerOf(false),
 							Canary:           pointerOf(0),
 							AutoPromote:      pointerOf(false),
+							Strategy:         pointerOf("rolling"),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -1117,6 +1127,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoRevert:       pointerOf(false),
 					Canary:           pointerOf(0),
 					AutoPromote:      pointerOf(false),
+					Strategy:         pointerOf("rolling"),
 				},
 				TaskGroups: []*TaskGroup{
 					{
@@ -1157,6 +1168,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							AutoRevert:       pointerOf(false),
 							Canary:           pointerOf(0),
 							AutoPromote:      pointerOf(false),
+							Strategy:         pointerOf("rolling"),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -1213,6 +1225,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 							AutoRevert:       pointerOf(false),
 							Canary:           pointerOf(0),
 							AutoPromote:      pointerOf(false),
+							Strategy:         pointerOf("rolling"),
 						},
 						Migrate: DefaultMigrateStrategy(),
 						Tasks: []*Task{
@@ -1296,6 +1309,7 @@ func TestJobs_Canonicalize(t *testing.T) {
 					AutoRevert:       pointerOf(false),
 					Canary:           pointerOf(0),
 					AutoPromote:      pointerOf(false),
+					Strategy:         pointerOf("rolling"),
 				},
 			},
 		},
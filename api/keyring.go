@@ -89,3 +89,30 @@ type KeyringRotateOptions struct {
 	Full      bool
 	Algorithm EncryptionAlgorithm
 }
+
+// KeyringSealRequest is the request to seal the values of a task of a job.
+type KeyringSealRequest struct {
+	JobID     string
+	TaskGroup string
+	Task      string
+
+	// Values are the cleartext values to seal, keyed by name.
+	Values map[string]string
+}
+
+// KeyringSealResponse has the sealed values of a task.
+type KeyringSealResponse struct {
+	Values map[string]string
+}
+
+// Seal encrypts the values of a task of the job with the cluster keyring, so
+// they are never submitted to the servers in cleartext. The namespace of the
+// write options must be the job's namespace.
+func (k *Keyring) Seal(req *KeyringSealRequest, w *WriteOptions) (map[string]string, *WriteMeta, error) {
+	var resp KeyringSealResponse
+	wm, err := k.client.put("/v1/operator/keyring/seal", req, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Values, wm, nil
+}
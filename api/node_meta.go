@@ -3,10 +3,20 @@
 
 package api
 
+import (
+	"context"
+	"time"
+)
+
 // NodeMetaApplyRequest contains the Node meta update.
 type NodeMetaApplyRequest struct {
 	NodeID string
 	Meta   map[string]*string
+
+	// Expected optionally makes the update a compare-and-set operation: it is
+	// only applied if every key currently has the expected value. A nil value
+	// expects the key to be unset. Conflicting updates fail with a 409 status.
+	Expected map[string]*string
 }
 
 // NodeMetaResponse contains the merged Node metadata.
@@ -19,6 +29,10 @@ type NodeMetaResponse struct {
 
 	// Static is the static Node metadata (set via agent configuration)
 	Static map[string]string
+
+	// Index is incremented by the Node on every dynamic metadata update. It
+	// may be used as the WaitIndex of a Read to watch for changes.
+	Index uint64
 }
 
 // NodeMeta is a client for manipulating dynamic Node metadata.
@@ -48,6 +62,9 @@ func (n *NodeMeta) Apply(meta *NodeMetaApplyRequest, qo *QueryOptions) (*NodeMet
 //
 // If nodeID is empty then the metadata for the Node receiving the request is
 // returned.
+//
+// Setting QueryOptions.WaitIndex to the Index of a previous response blocks
+// until the metadata changes, see Watch.
 func (n *NodeMeta) Read(nodeID string, qo *QueryOptions) (*NodeMetaResponse, error) {
 	if qo == nil {
 		qo = &QueryOptions{}
@@ -69,3 +86,55 @@ func (n *NodeMeta) Read(nodeID string, qo *QueryOptions) (*NodeMetaResponse, err
 
 	return &out, nil
 }
+
+// Watch returns a channel that receives the Node metadata every time it
+// changes. If QueryOptions.WaitIndex is set, the first value received is the
+// first change after that index, otherwise it is the current metadata. The
+// channel is closed when ctx is cancelled. Errors are retried after a second.
+//
+// If nodeID is empty then the metadata for the Node receiving the request is
+// watched.
+func (n *NodeMeta) Watch(ctx context.Context, nodeID string, qo *QueryOptions) <-chan *NodeMetaResponse {
+	if qo == nil {
+		qo = &QueryOptions{}
+	}
+	qo = qo.WithContext(ctx)
+
+	ch := make(chan *NodeMetaResponse)
+	go func() {
+		defer close(ch)
+
+		index := qo.WaitIndex
+		for {
+			q := *qo
+			q.WaitIndex = index
+			q.Params = make(map[string]string, len(qo.Params))
+			for k, v := range qo.Params {
+				q.Params[k] = v
+			}
+
+			resp, err := n.Read(nodeID, &q)
+			if err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Second):
+				}
+				continue
+			}
+
+			if resp.Index == index {
+				// Blocking query timed out without changes
+				continue
+			}
+			index = resp.Index
+
+			select {
+			case ch <- resp:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
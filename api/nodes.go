@@ -504,6 +504,73 @@ type NodePurgeResponse struct {
 	NodeModifyIndex uint64
 }
 
+// Hardware is used to query the hardware inventory of a node, built from the
+// attributes and resources fingerprinted by its client.
+func (n *Nodes) Hardware(nodeID string, q *QueryOptions) (*NodeHardware, *QueryMeta, error) {
+	var resp NodeHardware
+	qm, err := n.client.query("/v1/node/"+nodeID+"/hardware", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// NodeHardware is the hardware inventory of a node.
+type NodeHardware struct {
+	NodeID   string
+	NodeName string
+	NodePool string
+	CPU      NodeHardwareCPU
+	MemoryMB int64
+	Disks    []*NodeHardwareDisk
+	NICs     []*NodeHardwareNIC
+	Devices  []*NodeHardwareDevice
+	NUMA     []*NodeHardwareNUMANode
+}
+
+// NodeHardwareCPU describes the processors of a node.
+type NodeHardwareCPU struct {
+	ModelName        string
+	Sockets          int
+	Cores            int
+	PerformanceCores int
+	EfficiencyCores  int
+	TotalCompute     uint64
+}
+
+// NodeHardwareDisk describes a disk volume of a node.
+type NodeHardwareDisk struct {
+	Volume     string
+	TotalBytes uint64
+	FreeBytes  uint64
+}
+
+// NodeHardwareNIC describes a host network interface of a node.
+type NodeHardwareNIC struct {
+	Device     string
+	MacAddress string
+	SpeedMbits int
+	Addresses  []string
+}
+
+// NodeHardwareDevice describes a group of devices of a node, such as GPUs.
+type NodeHardwareDevice struct {
+	Vendor     string
+	Type       string
+	Name       string
+	Instances  int
+	Healthy    int
+	Attributes map[string]string
+}
+
+// NodeHardwareNUMANode describes a NUMA node of a node.
+type NodeHardwareNUMANode struct {
+	ID        uint8
+	Cores     string
+	Compute   uint64
+	Distances []uint8
+}
+
 // DriverInfo is used to deserialize a DriverInfo entry
 type DriverInfo struct {
 	Attributes        map[string]string
@@ -521,10 +588,12 @@ type HostVolumeInfo struct {
 
 // HostNetworkInfo is used to return metadata about a given HostNetwork
 type HostNetworkInfo struct {
-	Name          string
-	CIDR          string
-	Interface     string
-	ReservedPorts string
+	Name           string
+	CIDR           string
+	Interface      string
+	ReservedPorts  string
+	MinDynamicPort int
+	MaxDynamicPort int
 }
 
 type DrainStatus string
@@ -734,6 +803,12 @@ type DrainSpec struct {
 	// IgnoreSystemJobs allows systems jobs to remain on the node even though it
 	// has been marked for draining.
 	IgnoreSystemJobs bool
+
+	// CSIDetachTimeout is how long to wait for the CSI volumes of drained
+	// allocations to be unpublished from the node before forcing their
+	// claims to be released. Zero waits until the volumes are unpublished or
+	// the drain deadline is reached.
+	CSIDetachTimeout time.Duration
 }
 
 func (d *DrainStrategy) Equal(o *DrainStrategy) bool {
@@ -750,6 +825,9 @@ func (d *DrainStrategy) Equal(o *DrainStrategy) bool {
 	if d.IgnoreSystemJobs != o.IgnoreSystemJobs {
 		return false
 	}
+	if d.CSIDetachTimeout != o.CSIDetachTimeout {
+		return false
+	}
 
 	return true
 }
@@ -763,10 +841,12 @@ func (d *DrainStrategy) String() string {
 }
 
 const (
-	NodeEventSubsystemDrain     = "Drain"
-	NodeEventSubsystemDriver    = "Driver"
-	NodeEventSubsystemHeartbeat = "Heartbeat"
-	NodeEventSubsystemCluster   = "Cluster"
+	NodeEventSubsystemDrain       = "Drain"
+	NodeEventSubsystemDriver      = "Driver"
+	NodeEventSubsystemFingerprint = "Fingerprint"
+	NodeEventSubsystemHeartbeat   = "Heartbeat"
+	NodeEventSubsystemCluster     = "Cluster"
+	NodeEventSubsystemNetwork     = "Network"
 )
 
 // NodeEvent is a single unit representing a node’s state change
@@ -153,6 +153,31 @@ func TestNodes_Info(t *testing.T) {
 	must.GreaterEq(t, 1, len(result.Events))
 }
 
+func TestNodes_Hardware(t *testing.T) {
+	testutil.Parallel(t)
+
+	c, s := makeClient(t, nil, func(c *testutil.TestServerConfig) {
+		c.DevMode = true
+	})
+	defer s.Stop()
+	nodes := c.Nodes()
+
+	// Retrieving a nonexistent node returns error
+	_, _, err := nodes.Hardware("12345678-abcd-efab-cdef-123456789abc", nil)
+	must.ErrorContains(t, err, "not found")
+
+	node := oneNodeFromNodeList(t, nodes)
+
+	hw, qm, err := nodes.Hardware(node.ID, nil)
+	must.NoError(t, err)
+	assertQueryMeta(t, qm)
+
+	must.Eq(t, node.ID, hw.NodeID)
+	must.Eq(t, node.NodePool, hw.NodePool)
+	must.Positive(t, hw.CPU.Cores)
+	must.Positive(t, hw.MemoryMB)
+}
+
 func TestNodes_NoSecretID(t *testing.T) {
 	testutil.Parallel(t)
 
@@ -471,3 +471,35 @@ func (op *Operator) UpgradeCheckVaultWorkloadIdentity(q *QueryOptions) (*VaultWo
 	}
 	return &resp, qm, nil
 }
+
+// StateUsage is the object counts and estimated memory usage of the tables of
+// the servers' state store.
+type StateUsage struct {
+	// Tables holds the usage of every table, sorted by estimated memory usage
+	// in descending order.
+	Tables []*StateTableUsage
+}
+
+// StateTableUsage is the usage of a table of the state store.
+type StateTableUsage struct {
+	// Name is the name of the table.
+	Name string
+
+	// Objects is the number of objects in the table.
+	Objects int
+
+	// EstimatedBytes is the memory used by the objects of the table,
+	// estimated from the encoded size of a sample of the objects.
+	EstimatedBytes int64
+}
+
+// StateUsage retrieves the object counts and estimated memory usage of the
+// tables of the servers' state store.
+func (op *Operator) StateUsage(q *QueryOptions) (*StateUsage, *QueryMeta, error) {
+	var resp StateUsage
+	qm, err := op.c.query("/v1/operator/state/usage", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
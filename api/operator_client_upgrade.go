@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+const (
+	// ClientUpgradeStatusRunning is the status of a client upgrade whose
+	// nodes are being restarted.
+	ClientUpgradeStatusRunning = "running"
+
+	// ClientUpgradeStatusPaused is the status of a client upgrade that was
+	// paused by an operator or because a node failed to become healthy.
+	ClientUpgradeStatusPaused = "paused"
+
+	// ClientUpgradeStatusComplete is the status of a client upgrade whose
+	// nodes were all restarted.
+	ClientUpgradeStatusComplete = "complete"
+
+	// ClientUpgradeStatusCancelled is the status of a client upgrade that was
+	// cancelled by an operator.
+	ClientUpgradeStatusCancelled = "cancelled"
+
+	// ClientUpgradeNodeStatusPending is the status of a node that hasn't been
+	// restarted yet.
+	ClientUpgradeNodeStatusPending = "pending"
+
+	// ClientUpgradeNodeStatusRestarting is the status of a node that must be
+	// restarted, and that hasn't become healthy yet.
+	ClientUpgradeNodeStatusRestarting = "restarting"
+
+	// ClientUpgradeNodeStatusHealthy is the status of a node that became
+	// healthy after its restart.
+	ClientUpgradeNodeStatusHealthy = "healthy"
+
+	// ClientUpgradeNodeStatusFailed is the status of a node that failed to
+	// become healthy after its restart.
+	ClientUpgradeNodeStatusFailed = "failed"
+
+	// ClientUpgradeNodeStatusSkipped is the status of a node that wasn't
+	// restarted because it was down or already ran the target version.
+	ClientUpgradeNodeStatusSkipped = "skipped"
+)
+
+// ClientUpgrade is a rolling restart of the client agents of a node pool,
+// coordinated by the servers. The servers select the nodes to restart in
+// batches, mark them ineligible for scheduling, and wait for them to become
+// healthy before marking them eligible again. The agents of the nodes with the
+// restarting status must be restarted by the operator.
+type ClientUpgrade struct {
+	// ID is the UUID of the client upgrade, which is set by the servers when
+	// the upgrade is started.
+	ID string
+
+	// NodePool is the node pool whose nodes are restarted, or empty for all
+	// the node pools.
+	NodePool string
+
+	// Version is the version of Nomad the nodes must report after their
+	// restart. Nodes that already report it are skipped.
+	Version string
+
+	// MaxParallel is the number of nodes restarted at once.
+	MaxParallel int
+
+	// HealthTimeout is how long a node has to become healthy after it was
+	// selected to be restarted, after which the upgrade is paused.
+	HealthTimeout time.Duration
+
+	// Status and StatusDescription are set by the servers.
+	Status            string
+	StatusDescription string
+
+	// Nodes are the nodes of the upgrade in the order they are restarted,
+	// which are selected by the servers.
+	Nodes []*ClientUpgradeNode
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ClientUpgradeNode is the progress of a node of a client upgrade.
+type ClientUpgradeNode struct {
+	NodeID            string
+	Name              string
+	Status            string
+	StatusDescription string
+
+	// MarkedIneligible is set while the node is ineligible for scheduling
+	// because the upgrade marked it so.
+	MarkedIneligible bool
+
+	RunningAllocs []string
+	RestartIndex  uint64
+	RestartTime   time.Time
+}
+
+// ClientUpgrades lists the client upgrades, sorted by creation.
+func (op *Operator) ClientUpgrades(q *QueryOptions) ([]*ClientUpgrade, *QueryMeta, error) {
+	var resp []*ClientUpgrade
+	qm, err := op.c.query("/v1/operator/upgrade/clients", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// ClientUpgrade returns a client upgrade.
+func (op *Operator) ClientUpgrade(id string, q *QueryOptions) (*ClientUpgrade, *QueryMeta, error) {
+	if id == "" {
+		return nil, nil, errors.New("missing client upgrade ID")
+	}
+
+	var resp ClientUpgrade
+	qm, err := op.c.query("/v1/operator/upgrade/client/"+url.PathEscape(id), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// StartClientUpgrade starts a client upgrade. The servers select its nodes,
+// and it returns the upgrade as it was written.
+func (op *Operator) StartClientUpgrade(upgrade *ClientUpgrade, w *WriteOptions) (*ClientUpgrade, *WriteMeta, error) {
+	if upgrade == nil {
+		return nil, nil, errors.New("missing client upgrade")
+	}
+
+	var resp ClientUpgrade
+	wm, err := op.c.put("/v1/operator/upgrade/clients", upgrade, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// PauseClientUpgrade pauses a client upgrade, marking the nodes it marked
+// ineligible eligible again.
+func (op *Operator) PauseClientUpgrade(id, reason string, w *WriteOptions) (*ClientUpgrade, *WriteMeta, error) {
+	return op.updateClientUpgrade(id, "pause", reason, w)
+}
+
+// ResumeClientUpgrade resumes a paused client upgrade. The nodes that failed
+// are restarted again.
+func (op *Operator) ResumeClientUpgrade(id string, w *WriteOptions) (*ClientUpgrade, *WriteMeta, error) {
+	return op.updateClientUpgrade(id, "resume", "", w)
+}
+
+// CancelClientUpgrade cancels a client upgrade, marking the nodes it marked
+// ineligible eligible again.
+func (op *Operator) CancelClientUpgrade(id, reason string, w *WriteOptions) (*ClientUpgrade, *WriteMeta, error) {
+	return op.updateClientUpgrade(id, "cancel", reason, w)
+}
+
+func (op *Operator) updateClientUpgrade(id, action, reason string, w *WriteOptions) (*ClientUpgrade, *WriteMeta, error) {
+	if id == "" {
+		return nil, nil, errors.New("missing client upgrade ID")
+	}
+
+	body := struct{ Reason string }{Reason: reason}
+	var resp ClientUpgrade
+	wm, err := op.c.put("/v1/operator/upgrade/client/"+url.PathEscape(id)+"/"+action, &body, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"net/url"
+	"time"
+)
+
+const (
+	// MaintenanceWindowStatusPending is the status of a maintenance window
+	// that hasn't started yet.
+	MaintenanceWindowStatusPending = "pending"
+
+	// MaintenanceWindowStatusActive is the status of a maintenance window
+	// whose nodes are drained.
+	MaintenanceWindowStatusActive = "active"
+
+	// MaintenanceWindowStatusComplete is the status of a maintenance window
+	// that ended and whose nodes were marked eligible again.
+	MaintenanceWindowStatusComplete = "complete"
+)
+
+// MaintenanceWindow is a period of time during which a set of nodes is
+// drained. The nodes are drained when the window starts and marked eligible
+// for scheduling again when the window ends.
+type MaintenanceWindow struct {
+	// ID is the UUID of the maintenance window, which is set by the servers
+	// when the window is created.
+	ID string
+
+	// Description is the human-friendly description of the window.
+	Description string
+
+	// NodeIDs and NodeClasses select the nodes drained during the window.
+	NodeIDs     []string
+	NodeClasses []string
+
+	// Start and End are the times the window starts and ends.
+	Start time.Time
+	End   time.Time
+
+	// Deadline is the deadline of the drain of the nodes, after which their
+	// remaining allocations are stopped. Zero means no deadline.
+	Deadline time.Duration
+
+	// IgnoreSystemJobs keeps the allocations of system jobs running on the
+	// drained nodes.
+	IgnoreSystemJobs bool
+
+	// Status is the status of the window, which is set by the servers.
+	Status string
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// MaintenanceWindows lists the maintenance windows of the nodes, sorted by
+// start time.
+func (op *Operator) MaintenanceWindows(q *QueryOptions) ([]*MaintenanceWindow, *QueryMeta, error) {
+	var resp []*MaintenanceWindow
+	qm, err := op.c.query("/v1/operator/maintenance", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// UpsertMaintenanceWindow creates a maintenance window, or updates it if its
+// ID is set. It returns the window as it was written.
+func (op *Operator) UpsertMaintenanceWindow(window *MaintenanceWindow, w *WriteOptions) (*MaintenanceWindow, *WriteMeta, error) {
+	if window == nil {
+		return nil, nil, errors.New("missing maintenance window")
+	}
+
+	endpoint := "/v1/operator/maintenance"
+	if window.ID != "" {
+		endpoint += "/" + url.PathEscape(window.ID)
+	}
+
+	var resp MaintenanceWindow
+	wm, err := op.c.put(endpoint, window, &resp, w)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, wm, nil
+}
+
+// DeleteMaintenanceWindow deletes a maintenance window. The nodes drained by
+// the window are marked eligible again if the window is active.
+func (op *Operator) DeleteMaintenanceWindow(id string, w *WriteOptions) (*WriteMeta, error) {
+	if id == "" {
+		return nil, errors.New("missing maintenance window ID")
+	}
+
+	wm, err := op.c.delete("/v1/operator/maintenance/"+url.PathEscape(id), nil, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
@@ -19,6 +19,10 @@ type Resources struct {
 	Devices     []*RequestedDevice `hcl:"device,block"`
 	NUMA        *NUMAResource      `hcl:"numa,block"`
 
+	// ExclusiveCores requests the Cores to be reserved from the pool of
+	// exclusive cores of the node, which are never shared.
+	ExclusiveCores *bool `mapstructure:"exclusive_cores" hcl:"exclusive_cores,optional"`
+
 	// COMPAT(0.10)
 	// XXX Deprecated. Please do not use. The field will be removed in Nomad
 	// 0.10 and is only being kept to allow any references to be removed before
@@ -88,6 +92,9 @@ func (r *Resources) Merge(other *Resources) {
 	if other.CPU != nil {
 		r.CPU = other.CPU
 	}
+	if other.ExclusiveCores != nil {
+		r.ExclusiveCores = other.ExclusiveCores
+	}
 	if other.MemoryMB != nil {
 		r.MemoryMB = other.MemoryMB
 	}
@@ -155,6 +162,7 @@ type NetworkResource struct {
 	ReservedPorts []Port     `hcl:"reserved_ports,block"`
 	DynamicPorts  []Port     `hcl:"port,block"`
 	Hostname      string     `hcl:"hostname,optional"`
+	Interfaces    []string   `mapstructure:"interface" hcl:"interface,optional"`
 
 	// COMPAT(0.13)
 	// XXX Deprecated. Please do not use. The field will be removed in Nomad
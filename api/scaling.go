@@ -65,11 +65,12 @@ type ScalingRequest struct {
 type ScalingPolicy struct {
 	/* fields set by user in HCL config */
 
-	Min     *int64                 `hcl:"min,optional"`
-	Max     *int64                 `hcl:"max,optional"`
-	Policy  map[string]interface{} `hcl:"policy,block"`
-	Enabled *bool                  `hcl:"enabled,optional"`
-	Type    string                 `hcl:"type,optional"`
+	Min       *int64                 `hcl:"min,optional"`
+	Max       *int64                 `hcl:"max,optional"`
+	Policy    map[string]interface{} `hcl:"policy,block"`
+	Enabled   *bool                  `hcl:"enabled,optional"`
+	Type      string                 `hcl:"type,optional"`
+	Schedules []*ScalingSchedule     `hcl:"schedule,block"`
 
 	/* fields set by server */
 
@@ -80,6 +81,14 @@ type ScalingPolicy struct {
 	ModifyIndex uint64
 }
 
+// ScalingSchedule sets the count of the target task group of a scaling policy
+// at the times matching a cron expression
+type ScalingSchedule struct {
+	Cron     string `hcl:"cron"`
+	Count    int64  `hcl:"count"`
+	TimeZone string `mapstructure:"time_zone" hcl:"time_zone,optional"`
+}
+
 // ScalingPolicyListStub is used to return a subset of scaling policy information
 // for the scaling policy list
 type ScalingPolicyListStub struct {
@@ -59,6 +59,10 @@ type ServiceRegistration struct {
 	// is determined by a combination of factors on the client.
 	Port int
 
+	// Weight is the sum of the weights of the passing checks of this service
+	// registration, or 1 if the service has no checks.
+	Weight int
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -225,6 +229,8 @@ type ServiceCheck struct {
 	FailuresBeforeWarning  int                 `mapstructure:"failures_before_warning" hcl:"failures_before_warning,optional"`
 	Body                   string              `hcl:"body,optional"`
 	OnUpdate               string              `mapstructure:"on_update" hcl:"on_update,optional"`
+	Weight                 int                 `hcl:"weight,optional"`
+	UseContainerHealth     bool                `mapstructure:"use_container_health" hcl:"use_container_health,optional"`
 }
 
 // Service represents a Nomad job-submitters view of a Consul or Nomad service.
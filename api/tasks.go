@@ -22,6 +22,21 @@ const (
 	// attempts are reached within an interval.
 	RestartPolicyModeFail = "fail"
 
+	// OOMPolicyModeRestart follows the restart policy when a task is OOM
+	// killed.
+	OOMPolicyModeRestart = "restart"
+
+	// OOMPolicyModeFail fails a task when it is OOM killed.
+	OOMPolicyModeFail = "fail"
+
+	// OOMPolicyModeIgnore restarts a task when it is OOM killed without
+	// counting the restart against the restart policy.
+	OOMPolicyModeIgnore = "ignore"
+
+	// OOMPolicyModeAdjust raises the memory limit of a task when it is OOM
+	// killed before restarting it according to the restart policy.
+	OOMPolicyModeAdjust = "adjust"
+
 	// ReconcileOption is used to specify the behavior of the reconciliation process
 	// between the original allocations and the replacements when a previously
 	// disconnected client comes back online.
@@ -103,6 +118,12 @@ type RestartPolicy struct {
 	Delay           *time.Duration `hcl:"delay,optional"`
 	Mode            *string        `hcl:"mode,optional"`
 	RenderTemplates *bool          `mapstructure:"render_templates" hcl:"render_templates,optional"`
+	DelayFunction   *string        `mapstructure:"delay_function" hcl:"delay_function,optional"`
+	DelayMultiplier *float64       `mapstructure:"delay_multiplier" hcl:"delay_multiplier,optional"`
+	MaxDelay        *time.Duration `mapstructure:"max_delay" hcl:"max_delay,optional"`
+	Jitter          *float64       `hcl:"jitter,optional"`
+	Budget          *int           `hcl:"budget,optional"`
+	BudgetWindow    *time.Duration `mapstructure:"budget_window" hcl:"budget_window,optional"`
 }
 
 func (r *RestartPolicy) Merge(rp *RestartPolicy) {
@@ -121,6 +142,44 @@ func (r *RestartPolicy) Merge(rp *RestartPolicy) {
 	if rp.RenderTemplates != nil {
 		r.RenderTemplates = rp.RenderTemplates
 	}
+	if rp.DelayFunction != nil {
+		r.DelayFunction = rp.DelayFunction
+	}
+	if rp.DelayMultiplier != nil {
+		r.DelayMultiplier = rp.DelayMultiplier
+	}
+	if rp.MaxDelay != nil {
+		r.MaxDelay = rp.MaxDelay
+	}
+	if rp.Jitter != nil {
+		r.Jitter = rp.Jitter
+	}
+	if rp.Budget != nil {
+		r.Budget = rp.Budget
+	}
+	if rp.BudgetWindow != nil {
+		r.BudgetWindow = rp.BudgetWindow
+	}
+}
+
+// OOMPolicy defines how the Nomad client handles a task being killed by the
+// OOM killer.
+type OOMPolicy struct {
+	Mode             *string `hcl:"mode,optional"`
+	MemoryIncreaseMB *int    `mapstructure:"memory_increase" hcl:"memory_increase,optional"`
+	MemoryMaxMB      *int    `mapstructure:"memory_max" hcl:"memory_max,optional"`
+}
+
+func (o *OOMPolicy) Canonicalize() {
+	if o.Mode == nil {
+		o.Mode = pointerOf(OOMPolicyModeRestart)
+	}
+	if o.MemoryIncreaseMB == nil {
+		o.MemoryIncreaseMB = pointerOf(0)
+	}
+	if o.MemoryMaxMB == nil {
+		o.MemoryMaxMB = pointerOf(0)
+	}
 }
 
 // Disconnect strategy defines how both clients and server should behave in case of
@@ -363,9 +422,10 @@ func (s *Spread) Canonicalize() {
 
 // EphemeralDisk is an ephemeral disk object
 type EphemeralDisk struct {
-	Sticky  *bool `hcl:"sticky,optional"`
-	Migrate *bool `hcl:"migrate,optional"`
-	SizeMB  *int  `mapstructure:"size" hcl:"size,optional"`
+	Sticky       *bool          `hcl:"sticky,optional"`
+	Migrate      *bool          `hcl:"migrate,optional"`
+	SizeMB       *int           `mapstructure:"size" hcl:"size,optional"`
+	MigrateHooks []*MigrateHook `mapstructure:"hook" hcl:"hook,block"`
 }
 
 func DefaultEphemeralDisk() *EphemeralDisk {
@@ -386,6 +446,59 @@ func (e *EphemeralDisk) Canonicalize() {
 	if e.SizeMB == nil {
 		e.SizeMB = pointerOf(300)
 	}
+	for _, hook := range e.MigrateHooks {
+		hook.Canonicalize()
+	}
+}
+
+const (
+	// MigrateHookStagePreMigrate hooks are run inside a task before it is
+	// stopped, to quiesce and flush the state written to the disk.
+	MigrateHookStagePreMigrate = "pre_migrate"
+
+	// MigrateHookStagePostRestore hooks are run inside a task once it first
+	// starts after the data of the previous allocation was restored.
+	MigrateHookStagePostRestore = "post_restore"
+)
+
+// MigrateHook is a command run inside a task of the group when its ephemeral
+// disk is migrated.
+type MigrateHook struct {
+	Stage   string         `hcl:"stage,label"`
+	Task    *string        `mapstructure:"task" hcl:"task"`
+	Command *string        `mapstructure:"command" hcl:"command"`
+	Args    []string       `mapstructure:"args" hcl:"args,optional"`
+	Timeout *time.Duration `mapstructure:"timeout" hcl:"timeout,optional"`
+}
+
+func (h *MigrateHook) Canonicalize() {
+	if h.Task == nil {
+		h.Task = pointerOf("")
+	}
+	if h.Command == nil {
+		h.Command = pointerOf("")
+	}
+	if h.Args == nil {
+		h.Args = []string{}
+	}
+	if h.Timeout == nil {
+		h.Timeout = pointerOf(30 * time.Second)
+	}
+}
+
+// ArrayConfig configures the task group of a batch job as a job array.
+type ArrayConfig struct {
+	Count       *int `hcl:"count,optional"`
+	Concurrency *int `hcl:"concurrency,optional"`
+}
+
+func (a *ArrayConfig) Canonicalize() {
+	if a.Count == nil {
+		a.Count = pointerOf(1)
+	}
+	if a.Concurrency == nil {
+		a.Concurrency = pointerOf(0)
+	}
 }
 
 // MigrateStrategy describes how allocations for a task group should be
@@ -459,6 +572,9 @@ type VolumeRequest struct {
 	AttachmentMode string           `hcl:"attachment_mode,optional"`
 	MountOptions   *CSIMountOptions `hcl:"mount_options,block"`
 	PerAlloc       bool             `hcl:"per_alloc,optional"`
+	Create         bool             `hcl:"create,optional"`
+	SizeMB         int              `hcl:"size,optional"`
+	Affinity       int8             `hcl:"affinity,optional"`
 	ExtraKeysHCL   []string         `hcl1:",unusedKeys,optional" json:"-"`
 }
 
@@ -506,6 +622,7 @@ type TaskGroup struct {
 	ReschedulePolicy *ReschedulePolicy         `hcl:"reschedule,block"`
 	EphemeralDisk    *EphemeralDisk            `hcl:"ephemeral_disk,block"`
 	Update           *UpdateStrategy           `hcl:"update,block"`
+	Array            *ArrayConfig              `hcl:"array,block"`
 	Migrate          *MigrateStrategy          `hcl:"migrate,block"`
 	Networks         []*NetworkResource        `hcl:"network,block"`
 	Meta             map[string]string         `hcl:"meta,block"`
@@ -517,6 +634,7 @@ type TaskGroup struct {
 	MaxClientDisconnect *time.Duration `mapstructure:"max_client_disconnect" hcl:"max_client_disconnect,optional"`
 	Scaling             *ScalingPolicy `hcl:"scaling,block"`
 	Consul              *Consul        `hcl:"consul,block"`
+	Ingress             *Ingress       `hcl:"ingress,block"`
 	// To be deprecated after 1.8.0 infavour of Disconnect.Replace
 	PreventRescheduleOnLost *bool `hcl:"prevent_reschedule_on_lost,optional"`
 }
@@ -535,6 +653,17 @@ func (g *TaskGroup) Canonicalize(job *Job) {
 		g.Name = pointerOf("")
 	}
 
+	// The count of a job array is the number of indices
+	if g.Array != nil {
+		if g.Array.Count == nil && g.Count != nil {
+			g.Array.Count = pointerOf(*g.Count)
+		}
+		g.Array.Canonicalize()
+		g.Count = pointerOf(*g.Array.Count)
+	}
+
+	g.Ingress.Canonicalize()
+
 	if g.Count == nil {
 		if g.Scaling != nil && g.Scaling.Min != nil {
 			g.Count = pointerOf(int(*g.Scaling.Min))
@@ -721,6 +850,30 @@ type LogConfig struct {
 	Enabled *bool `mapstructure:"enabled" hcl:"enabled,optional"`
 
 	Disabled *bool `mapstructure:"disabled" hcl:"disabled,optional"`
+
+	// Backpressure is the policy applied when the task writes logs faster
+	// than they can be written to disk, one of "block", "drop", or "spill".
+	Backpressure *string `mapstructure:"backpressure" hcl:"backpressure,optional"`
+
+	// RotateInterval rotates the current log file once it has been open for
+	// the interval, in addition to rotating it by size.
+	RotateInterval *time.Duration `mapstructure:"rotate_interval" hcl:"rotate_interval,optional"`
+
+	// Compression compresses rotated log files, either "gzip" or "zstd".
+	Compression *string `mapstructure:"compression" hcl:"compression,optional"`
+
+	// Retention removes rotated log files older than the duration.
+	Retention *time.Duration `mapstructure:"retention" hcl:"retention,optional"`
+
+	// Sinks are the log sink plugins the task's output is shipped to. The
+	// log sinks configured on the client are used if empty.
+	Sinks []*LogSink `mapstructure:"sink" hcl:"sink,block"`
+}
+
+// LogSink is a log sink plugin that the output of a task is shipped to.
+type LogSink struct {
+	Name    string            `hcl:"name,label"`
+	Options map[string]string `mapstructure:"options" hcl:"options,optional"`
 }
 
 func DefaultLogConfig() *LogConfig {
@@ -754,9 +907,18 @@ const (
 	TaskLifecycleHookPoststop  = "poststop"
 )
 
+const (
+	TaskLifecycleUnhealthyActionFail    = "fail"
+	TaskLifecycleUnhealthyActionRestart = "restart"
+	TaskLifecycleUnhealthyActionIgnore  = "ignore"
+)
+
 type TaskLifecycle struct {
-	Hook    string `mapstructure:"hook" hcl:"hook,optional"`
-	Sidecar bool   `mapstructure:"sidecar" hcl:"sidecar,optional"`
+	Hook            string         `mapstructure:"hook" hcl:"hook,optional"`
+	Sidecar         bool           `mapstructure:"sidecar" hcl:"sidecar,optional"`
+	WaitHealthy     bool           `mapstructure:"wait_healthy" hcl:"wait_healthy,optional"`
+	HealthyTimeout  *time.Duration `mapstructure:"healthy_timeout" hcl:"healthy_timeout,optional"`
+	UnhealthyAction string         `mapstructure:"unhealthy_action" hcl:"unhealthy_action,optional"`
 }
 
 // Determine if lifecycle has user-input values
@@ -764,6 +926,20 @@ func (l *TaskLifecycle) Empty() bool {
 	return l == nil || (l.Hook == "")
 }
 
+// Canonicalize sets the readiness defaults for sidecars that wait to be
+// healthy.
+func (l *TaskLifecycle) Canonicalize() {
+	if l == nil || !l.WaitHealthy {
+		return
+	}
+	if l.HealthyTimeout == nil {
+		l.HealthyTimeout = pointerOf(5 * time.Minute)
+	}
+	if l.UnhealthyAction == "" {
+		l.UnhealthyAction = TaskLifecycleUnhealthyActionFail
+	}
+}
+
 // Task is a single process in a task group.
 type Task struct {
 	Name            string                 `hcl:"name,label"`
@@ -774,9 +950,11 @@ type Task struct {
 	Constraints     []*Constraint          `hcl:"constraint,block"`
 	Affinities      []*Affinity            `hcl:"affinity,block"`
 	Env             map[string]string      `hcl:"env,block"`
+	Sealed          map[string]string      `hcl:"sealed,block"`
 	Services        []*Service             `hcl:"service,block"`
 	Resources       *Resources             `hcl:"resources,block"`
 	RestartPolicy   *RestartPolicy         `hcl:"restart,block"`
+	OOMPolicy       *OOMPolicy             `mapstructure:"oom_policy" hcl:"oom_policy,block"`
 	Meta            map[string]string      `hcl:"meta,block"`
 	KillTimeout     *time.Duration         `mapstructure:"kill_timeout" hcl:"kill_timeout,optional"`
 	LogConfig       *LogConfig             `mapstructure:"logs" hcl:"logs,block"`
@@ -841,9 +1019,13 @@ func (t *Task) Canonicalize(tg *TaskGroup, job *Job) {
 	if t.Lifecycle.Empty() {
 		t.Lifecycle = nil
 	}
+	t.Lifecycle.Canonicalize()
 	if t.CSIPluginConfig != nil {
 		t.CSIPluginConfig.Canonicalize()
 	}
+	if t.OOMPolicy != nil {
+		t.OOMPolicy.Canonicalize()
+	}
 	if t.RestartPolicy == nil {
 		t.RestartPolicy = tg.RestartPolicy
 	} else {
@@ -862,6 +1044,7 @@ type TaskArtifact struct {
 	GetterMode     *string           `mapstructure:"mode" hcl:"mode,optional"`
 	GetterInsecure *bool             `mapstructure:"insecure" hcl:"insecure,optional"`
 	RelativeDest   *string           `mapstructure:"destination" hcl:"destination,optional"`
+	GetterIdentity *string           `mapstructure:"identity" hcl:"identity,optional"`
 }
 
 func (a *TaskArtifact) Canonicalize() {
@@ -1142,6 +1325,7 @@ const (
 	TaskLeaderDead             = "Leader Task Dead"
 	TaskBuildingTaskDir        = "Building Task Directory"
 	TaskClientReconnected      = "Reconnected"
+	TaskDeviceVanished         = "Device Vanished"
 )
 
 // TaskEvent is an event that effects the state of a task and contains meta-data
@@ -1238,14 +1422,15 @@ func (t *TaskCSIPluginConfig) Canonicalize() {
 // WorkloadIdentity is the jobspec block which determines if and how a workload
 // identity is exposed to tasks.
 type WorkloadIdentity struct {
-	Name         string        `hcl:"name,optional"`
-	Audience     []string      `mapstructure:"aud" hcl:"aud,optional"`
-	ChangeMode   string        `mapstructure:"change_mode" hcl:"change_mode,optional"`
-	ChangeSignal string        `mapstructure:"change_signal" hcl:"change_signal,optional"`
-	Env          bool          `hcl:"env,optional"`
-	File         bool          `hcl:"file,optional"`
-	ServiceName  string        `hcl:"service_name,optional"`
-	TTL          time.Duration `mapstructure:"ttl" hcl:"ttl,optional"`
+	Name         string            `hcl:"name,optional"`
+	Audience     []string          `mapstructure:"aud" hcl:"aud,optional"`
+	ChangeMode   string            `mapstructure:"change_mode" hcl:"change_mode,optional"`
+	ChangeSignal string            `mapstructure:"change_signal" hcl:"change_signal,optional"`
+	Env          bool              `hcl:"env,optional"`
+	File         bool              `hcl:"file,optional"`
+	ServiceName  string            `hcl:"service_name,optional"`
+	TTL          time.Duration     `mapstructure:"ttl" hcl:"ttl,optional"`
+	ExtraClaims  map[string]string `mapstructure:"extra_claims" hcl:"extra_claims,optional"`
 }
 
 type Action struct {
@@ -363,6 +363,61 @@ func TestTask_Canonicalize_TaskLifecycle(t *testing.T) {
 	}
 }
 
+func TestTask_Canonicalize_OOMPolicy(t *testing.T) {
+	testutil.Parallel(t)
+
+	testCases := []struct {
+		name     string
+		expected *OOMPolicy
+		task     *Task
+	}{
+		{
+			name:     "nil",
+			task:     &Task{},
+			expected: nil,
+		},
+		{
+			name: "empty",
+			task: &Task{
+				OOMPolicy: &OOMPolicy{},
+			},
+			expected: &OOMPolicy{
+				Mode:             pointerOf(OOMPolicyModeRestart),
+				MemoryIncreaseMB: pointerOf(0),
+				MemoryMaxMB:      pointerOf(0),
+			},
+		},
+		{
+			name: "adjust",
+			task: &Task{
+				OOMPolicy: &OOMPolicy{
+					Mode:             pointerOf(OOMPolicyModeAdjust),
+					MemoryIncreaseMB: pointerOf(128),
+					MemoryMaxMB:      pointerOf(1024),
+				},
+			},
+			expected: &OOMPolicy{
+				Mode:             pointerOf(OOMPolicyModeAdjust),
+				MemoryIncreaseMB: pointerOf(128),
+				MemoryMaxMB:      pointerOf(1024),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tg := &TaskGroup{
+				Name: pointerOf("foo"),
+			}
+			j := &Job{
+				ID: pointerOf("test"),
+			}
+			tc.task.Canonicalize(tg, j)
+			must.Eq(t, tc.expected, tc.task.OOMPolicy)
+		})
+	}
+}
+
 func TestTask_Template_WaitConfig_Canonicalize_and_Copy(t *testing.T) {
 	testutil.Parallel(t)
 
@@ -496,6 +551,7 @@ func TestTaskGroup_Canonicalize_Update(t *testing.T) {
 			MaxParallel:      pointerOf(0),
 			MinHealthyTime:   pointerOf(time.Duration(0)),
 			Stagger:          pointerOf(time.Duration(0)),
+			Strategy:         pointerOf(""),
 		},
 	}
 	job.Canonicalize()
@@ -593,6 +649,7 @@ func TestTaskGroup_Merge_Update(t *testing.T) {
 		MaxParallel:      pointerOf(1),
 		MinHealthyTime:   pointerOf(10 * time.Second),
 		Stagger:          pointerOf(30 * time.Second),
+		Strategy:         pointerOf("rolling"),
 	}, tg.Update)
 }
 
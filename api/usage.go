@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	// UsageGroupByJob groups usage records by job, which is the default.
+	UsageGroupByJob = "job"
+
+	// UsageGroupByNamespace groups usage records by namespace.
+	UsageGroupByNamespace = "namespace"
+)
+
+// Usage is used to query the resource usage accounted by the servers.
+type Usage struct {
+	client *Client
+}
+
+// Usage returns a handle on the usage endpoints.
+func (c *Client) Usage() *Usage {
+	return &Usage{client: c}
+}
+
+// UsageListOptions filters and groups the usage records returned by List.
+type UsageListOptions struct {
+	// JobID restricts the records to a single job of the namespace.
+	JobID string
+
+	// Start and End restrict the records to the periods starting within the
+	// range. Zero values leave the range open.
+	Start time.Time
+	End   time.Time
+
+	// GroupBy is either UsageGroupByJob or UsageGroupByNamespace.
+	GroupBy string
+}
+
+// List is used to list the usage records of the namespace of the query
+// options, one per job or namespace and period.
+func (u *Usage) List(opts *UsageListOptions, q *QueryOptions) ([]*UsageRecord, *QueryMeta, error) {
+	if opts != nil {
+		if q == nil {
+			q = &QueryOptions{}
+		}
+		if q.Params == nil {
+			q.Params = make(map[string]string)
+		}
+		if opts.JobID != "" {
+			q.Params["job"] = opts.JobID
+		}
+		if !opts.Start.IsZero() {
+			q.Params["start"] = strconv.FormatInt(opts.Start.UnixNano(), 10)
+		}
+		if !opts.End.IsZero() {
+			q.Params["end"] = strconv.FormatInt(opts.End.UnixNano(), 10)
+		}
+		if opts.GroupBy != "" {
+			q.Params["group_by"] = opts.GroupBy
+		}
+	}
+
+	var resp []*UsageRecord
+	qm, err := u.client.query("/v1/usage", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// UsageRecord is the resource usage of a job, or of a namespace, during one
+// period. Resources are accounted in resource-seconds: CPU in MHz-seconds and
+// memory and disk in MB-seconds.
+type UsageRecord struct {
+	Namespace string
+	JobID     string
+
+	// Start is the start of the period in Unix nanoseconds.
+	Start      int64
+	Resolution time.Duration
+
+	ReservedCPU    uint64
+	ReservedMemory uint64
+	ReservedDisk   uint64
+	UsedCPU        uint64
+	UsedMemory     uint64
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/api/internal/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestUsage_List(t *testing.T) {
+	testutil.Parallel(t)
+
+	c, s := makeClient(t, nil, nil)
+	defer s.Stop()
+	usage := c.Usage()
+
+	// Accounting is disabled by default, so there are no records.
+	records, qm, err := usage.List(nil, nil)
+	must.NoError(t, err)
+	must.Len(t, 0, records)
+	assertQueryMeta(t, qm)
+
+	records, _, err = usage.List(&UsageListOptions{
+		Start:   time.Now().Add(-time.Hour),
+		GroupBy: UsageGroupByNamespace,
+	}, nil)
+	must.NoError(t, err)
+	must.Len(t, 0, records)
+
+	// Jobs can't be filtered when grouping by namespace.
+	_, _, err = usage.List(&UsageListOptions{
+		JobID:   "example",
+		GroupBy: UsageGroupByNamespace,
+	}, nil)
+	must.ErrorContains(t, err, "job filter")
+}
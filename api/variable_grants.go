@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"net/url"
+)
+
+// VariableGrants is used to access the variable grants endpoints.
+type VariableGrants struct {
+	client *Client
+}
+
+// VariableGrants returns a handle on the variable grants endpoints.
+func (c *Client) VariableGrants() *VariableGrants {
+	return &VariableGrants{client: c}
+}
+
+// List is used to list the variable grants of the namespace.
+func (g *VariableGrants) List(q *QueryOptions) ([]*VariableGrant, *QueryMeta, error) {
+	var resp []*VariableGrant
+	qm, err := g.client.query("/v1/var-grants", &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Info is used to fetch details of a specific variable grant.
+func (g *VariableGrants) Info(name string, q *QueryOptions) (*VariableGrant, *QueryMeta, error) {
+	if name == "" {
+		return nil, nil, errors.New("missing variable grant name")
+	}
+
+	var resp VariableGrant
+	qm, err := g.client.query("/v1/var-grant/"+url.PathEscape(name), &resp, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &resp, qm, nil
+}
+
+// Register is used to create or update a variable grant.
+func (g *VariableGrants) Register(grant *VariableGrant, w *WriteOptions) (*WriteMeta, error) {
+	if grant == nil {
+		return nil, errors.New("missing variable grant")
+	}
+	if grant.Name == "" {
+		return nil, errors.New("missing variable grant name")
+	}
+	if grant.Namespace != "" {
+		if w == nil {
+			w = &WriteOptions{}
+		}
+		w.Namespace = grant.Namespace
+	}
+
+	wm, err := g.client.put("/v1/var-grant/"+url.PathEscape(grant.Name), grant, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// Delete is used to delete a variable grant of the namespace.
+func (g *VariableGrants) Delete(name string, w *WriteOptions) (*WriteMeta, error) {
+	if name == "" {
+		return nil, errors.New("missing variable grant name")
+	}
+
+	wm, err := g.client.delete("/v1/var-grant/"+url.PathEscape(name), nil, nil, w)
+	if err != nil {
+		return nil, err
+	}
+	return wm, nil
+}
+
+// VariableGrant shares the variables at a path of a namespace with the
+// workloads of other namespaces, which can then read and list them.
+type VariableGrant struct {
+	// Name is the name of the grant. It must be unique within the namespace.
+	Name string
+
+	// Namespace is the namespace of the shared variables.
+	Namespace string
+
+	// Description is the human-friendly description of the grant.
+	Description string
+
+	// Path is the path of the shared variables. It may contain glob
+	// patterns, such as "shared/*".
+	Path string
+
+	// Namespaces are the namespaces whose workloads are granted access to
+	// the variables, or "*" for all the namespaces.
+	Namespaces []string
+
+	// Jobs optionally restricts the grant to the workloads of the given jobs
+	// of the granted namespaces.
+	Jobs []string
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
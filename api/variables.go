@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 )
 
 const (
@@ -85,6 +86,53 @@ func (vars *Variables) Peek(path string, qo *QueryOptions) (*Variable, *QueryMet
 	return v, qm, nil
 }
 
+// ReadVersion is used to query a version of a variable by path. The version
+// can be the current version or a prior version, including a version of a
+// deleted variable. This will error if the version is not found.
+func (vars *Variables) ReadVersion(path string, version uint64, qo *QueryOptions) (*Variable, *QueryMeta, error) {
+	path = cleanPathString(path)
+	var v = new(Variable)
+	qm, err := vars.readInternal("/v1/var/"+path+"?version="+fmt.Sprint(version), &v, qo)
+	if err != nil {
+		return nil, nil, err
+	}
+	if v == nil {
+		return nil, qm, ErrVariablePathNotFound
+	}
+	return v, qm, nil
+}
+
+// Versions is used to list the metadata of the current and prior versions
+// of a variable, newest first. The versions of a deleted variable are listed
+// until its retention period has passed. This will error if the variable has
+// no versions.
+func (vars *Variables) Versions(path string, qo *QueryOptions) ([]*VariableMetadata, *QueryMeta, error) {
+	path = cleanPathString(path)
+	var resp []*VariableMetadata
+	qm, err := vars.client.query("/v1/var/"+path+"?versions", &resp, qo)
+	if err != nil {
+		var respErr UnexpectedResponseError
+		if errors.As(err, &respErr) && respErr.StatusCode() == http.StatusNotFound {
+			return nil, nil, ErrVariablePathNotFound
+		}
+		return nil, nil, err
+	}
+	return resp, qm, nil
+}
+
+// Restore is used to restore a prior version of a variable, including a
+// deleted variable. The items of the prior version are written as a new
+// version of the variable.
+func (vars *Variables) Restore(path string, version uint64, qo *WriteOptions) (*VariableMetadata, *WriteMeta, error) {
+	path = cleanPathString(path)
+	var out VariableMetadata
+	wm, err := vars.client.put("/v1/var/"+path+"?restore="+fmt.Sprint(version), nil, &out, qo)
+	if err != nil {
+		return nil, wm, err
+	}
+	return &out, wm, nil
+}
+
 // Update is used to update a variable.
 func (vars *Variables) Update(v *Variable, qo *WriteOptions) (*Variable, *WriteMeta, error) {
 	v.Path = cleanPathString(v.Path)
@@ -395,6 +443,22 @@ type Variable struct {
 	// ModifyTime is the unix nano of the last modified time
 	ModifyTime int64 `hcl:"modify_time"`
 
+	// TTL is the optional time-to-live of the variable. The variable is
+	// deleted once the TTL has passed since it was last written.
+	TTL time.Duration `hcl:"ttl,optional" json:",omitempty"`
+
+	// ExpireTime is the unix nano of the time the variable expires. It is
+	// set by the server and is zero if the variable has no TTL.
+	ExpireTime int64 `hcl:"expire_time,optional" json:",omitempty"`
+
+	// Version is incremented by the server each time the variable is
+	// written. Prior versions can be read and restored.
+	Version uint64 `hcl:"version,optional"`
+
+	// DeleteTime is the unix nano of the time the variable was deleted. It
+	// is only set on the latest prior version of a deleted variable.
+	DeleteTime int64 `hcl:"delete_time,optional" json:",omitempty"`
+
 	// Items contains the k/v variable component
 	Items VariableItems `hcl:"items"`
 
@@ -423,6 +487,22 @@ type VariableMetadata struct {
 	// ModifyTime is the unix nano of the last modified time
 	ModifyTime int64 `hcl:"modify_time"`
 
+	// TTL is the optional time-to-live of the variable. The variable is
+	// deleted once the TTL has passed since it was last written.
+	TTL time.Duration `hcl:"ttl,optional" json:",omitempty"`
+
+	// ExpireTime is the unix nano of the time the variable expires. It is
+	// set by the server and is zero if the variable has no TTL.
+	ExpireTime int64 `hcl:"expire_time,optional" json:",omitempty"`
+
+	// Version is incremented by the server each time the variable is
+	// written. Prior versions can be read and restored.
+	Version uint64 `hcl:"version,optional"`
+
+	// DeleteTime is the unix nano of the time the variable was deleted. It
+	// is only set on the latest prior version of a deleted variable.
+	DeleteTime int64 `hcl:"delete_time,optional" json:",omitempty"`
+
 	// Lock holds the information about the variable lock if its being used.
 	Lock *VariableLock `hcl:",lock,optional" json:",omitempty"`
 }
@@ -475,6 +555,10 @@ func (v *Variable) Metadata() *VariableMetadata {
 		ModifyIndex: v.ModifyIndex,
 		CreateTime:  v.CreateTime,
 		ModifyTime:  v.ModifyTime,
+		TTL:         v.TTL,
+		ExpireTime:  v.ExpireTime,
+		Version:     v.Version,
+		DeleteTime:  v.DeleteTime,
 	}
 }
 
@@ -233,3 +233,23 @@ func (a *Agent) Host(args *structs.HostDataRequest, reply *structs.HostDataRespo
 	reply.HostData = data
 	return nil
 }
+
+// FlightRecorder dumps the flight recorder of the agent
+func (a *Agent) FlightRecorder(args *structs.FlightRecorderRequest, reply *structs.FlightRecorderResponse) error {
+	aclObj, err := a.c.ResolveToken(args.AuthToken)
+	if err != nil {
+		return err
+	}
+	if !aclObj.AllowAgentDebug(a.c.GetConfig().EnableDebug) {
+		return structs.ErrPermissionDenied
+	}
+
+	dump, err := a.c.GetConfig().FlightRecorder.Dump("requested")
+	if err != nil {
+		return structs.NewErrRPCCoded(404, err.Error())
+	}
+
+	reply.AgentID = a.c.NodeID()
+	reply.Dump = dump
+	return nil
+}
@@ -9,12 +9,18 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/armon/go-metrics"
 	"github.com/hashicorp/go-msgpack/codec"
 	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	arstate "github.com/hashicorp/nomad/client/allocrunner/state"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -31,6 +37,7 @@ type Allocations struct {
 func NewAllocationsEndpoint(c *Client) *Allocations {
 	a := &Allocations{c: c}
 	a.c.streamingRpcs.Register("Allocations.Exec", a.exec)
+	a.c.streamingRpcs.Register("Allocations.PortForward", a.portForward)
 	return a
 }
 
@@ -218,6 +225,7 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 			"command", req.Cmd,
 			"tty", req.Tty,
 			"action", req.Action,
+			"no_record", req.NoRecord,
 		}
 		if ident != nil {
 			if ident.ACLToken != nil {
@@ -238,13 +246,23 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 		a.c.logger.Info("task exec session starting", logArgs...)
 	}
 
-	// Check alloc-exec permission.
+	// Check alloc-exec permission for the namespace or the task.
 	if err != nil {
 		return pointer.Of(int64(400)), err
-	} else if !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocExec) {
+	} else if !aclObj.AllowJobTaskOperation(alloc.Namespace, alloc.JobID, req.Task, acl.NamespaceCapabilityAllocExec) {
 		return nil, nstructs.ErrPermissionDenied
 	}
 
+	// Skipping the recording of the session requires the
+	// alloc-exec-unrecorded capability
+	record := a.c.GetConfig().RecordExecSessions
+	if record && req.NoRecord {
+		if !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityAllocExecUnrecorded) {
+			return nil, nstructs.ErrPermissionDenied
+		}
+		record = false
+	}
+
 	// Validate the arguments
 	if req.Task == "" {
 		return pointer.Of(int64(400)), taskNotPresentErr
@@ -322,7 +340,21 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 		return pointer.Of(int64(404)), fmt.Errorf("task %q is not running.", req.Task)
 	}
 
-	err = h(ctx, req.Cmd, req.Tty, newExecStream(decoder, encoder))
+	stream := newExecStream(decoder, encoder)
+	if record {
+		path := a.execRecordingPath(ar, req.Task, execID)
+		title := fmt.Sprintf("exec %s alloc %s task %s by %s", execID, alloc.ID, req.Task, ident)
+		recorder, err := newExecRecorder(stream, path, title, req.Cmd)
+		if err != nil {
+			return pointer.Of(int64(500)), fmt.Errorf("failed to record exec session: %w", err)
+		}
+		defer recorder.Close()
+
+		a.c.logger.Info("recording task exec session", "exec_id", execID, "path", path)
+		stream = recorder
+	}
+
+	err = h(ctx, req.Cmd, req.Tty, stream)
 	if err != nil {
 		code := pointer.Of(int64(500))
 		return code, err
@@ -331,6 +363,165 @@ func (a *Allocations) execImpl(encoder *codec.Encoder, decoder *codec.Decoder, e
 	return nil, nil
 }
 
+// execRecordingPath returns the path of the recording of an exec session:
+// either the exec_recording_dir of the client or the log dir of the
+// allocation.
+func (a *Allocations) execRecordingPath(ar interfaces.AllocRunner, task, execID string) string {
+	if dir := a.c.GetConfig().ExecRecordingDir; dir != "" {
+		name := fmt.Sprintf("%s.%s.exec.%s%s", ar.Alloc().ID, task, execID, execRecordingExt)
+		return filepath.Join(dir, name)
+	}
+	name := fmt.Sprintf("%s.exec.%s%s", task, execID, execRecordingExt)
+	return filepath.Join(ar.GetAllocDir().ShareDirPath(), allocdir.LogDirName, name)
+}
+
+const (
+	// portForwardDialTimeout is the amount of time to wait for a connection
+	// to the forwarded port of an allocation
+	portForwardDialTimeout = 10 * time.Second
+
+	// portForwardFrameSize is the maximum amount of data sent in a single
+	// port forward frame
+	portForwardFrameSize = 32 * 1024
+)
+
+// portForward is used to forward a connection to a port in the network
+// namespace of an allocation
+func (a *Allocations) portForward(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "allocations", "port_forward"}, time.Now())
+	defer conn.Close()
+
+	decoder := codec.NewDecoder(conn, nstructs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, nstructs.MsgpackHandle)
+
+	code, err := a.portForwardImpl(encoder, decoder)
+	if err != nil {
+		a.c.logger.Debug("port forward session ended with an error", "error", err, "code", code)
+		handleStreamResultError(err, code, encoder)
+	}
+}
+
+func (a *Allocations) portForwardImpl(encoder *codec.Encoder, decoder *codec.Decoder) (code *int64, err error) {
+
+	// Decode the arguments
+	var req cstructs.AllocPortForwardRequest
+	if err := decoder.Decode(&req); err != nil {
+		return pointer.Of(int64(500)), err
+	}
+
+	// Port forwarding gives the same access to an allocation as exec
+	if a.c.GetConfig().DisableRemoteExec {
+		return nil, nstructs.ErrPermissionDenied
+	}
+
+	if req.AllocID == "" {
+		return pointer.Of(int64(400)), allocIDNotPresentErr
+	}
+	if req.Port <= 0 || req.Port > 65535 {
+		return pointer.Of(int64(400)), fmt.Errorf("invalid port %d", req.Port)
+	}
+
+	ar, err := a.c.getAllocRunner(req.AllocID)
+	if err != nil {
+		code := pointer.Of(int64(500))
+		if nstructs.IsErrUnknownAllocation(err) {
+			code = pointer.Of(int64(404))
+		}
+
+		return code, err
+	}
+	alloc := ar.Alloc()
+
+	// Check alloc-exec permission. The network namespace is shared by the
+	// tasks of the allocation, so job policies restricted to tasks don't
+	// allow port forwarding.
+	aclObj, _, err := a.c.resolveTokenAndACL(req.QueryOptions.AuthToken)
+	if err != nil {
+		return pointer.Of(int64(400)), err
+	} else if !aclObj.AllowJobTaskOperation(alloc.Namespace, alloc.JobID, "", acl.NamespaceCapabilityAllocExec) {
+		return nil, nstructs.ErrPermissionDenied
+	}
+
+	if req.JobID != "" && req.JobID != alloc.JobID {
+		return pointer.Of(int64(http.StatusBadRequest)),
+			fmt.Errorf("job %s does not have allocation %s", req.JobID, req.AllocID)
+	}
+
+	if alloc.ClientTerminalStatus() {
+		return pointer.Of(int64(http.StatusBadRequest)),
+			fmt.Errorf("port forward not possible, client status of allocation %s is %s", alloc.ID, alloc.ClientStatus)
+	}
+
+	addr := net.JoinHostPort(portForwardHost(alloc, ar.AllocState()), strconv.Itoa(req.Port))
+	target, err := net.DialTimeout("tcp", addr, portForwardDialTimeout)
+	if err != nil {
+		return pointer.Of(int64(http.StatusBadGateway)),
+			fmt.Errorf("failed to connect to port %d of allocation %s: %w", req.Port, alloc.ID, err)
+	}
+	defer target.Close()
+
+	a.c.logger.Info("port forward session starting", "alloc_id", alloc.ID, "port", req.Port)
+	defer a.c.logger.Info("port forward session ended", "alloc_id", alloc.ID, "port", req.Port)
+
+	errCh := make(chan error, 2)
+
+	// Stream the data sent by the allocation
+	go func() {
+		buf := make([]byte, portForwardFrameSize)
+		for {
+			n, err := target.Read(buf)
+			if n > 0 {
+				if err := encoder.Encode(&cstructs.StreamErrWrapper{Payload: buf[:n]}); err != nil {
+					errCh <- err
+					return
+				}
+			}
+			if err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+
+	// Forward the data sent by the caller to the allocation
+	go func() {
+		for {
+			var frame cstructs.StreamErrWrapper
+			if err := decoder.Decode(&frame); err != nil {
+				errCh <- err
+				return
+			}
+			if _, err := target.Write(frame.Payload); err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+
+	if err := <-errCh; err != nil && !errors.Is(err, io.EOF) {
+		return pointer.Of(int64(500)), err
+	}
+	return nil, nil
+}
+
+// portForwardHost returns the address the ports of an allocation are
+// reachable at from the client. This is the address of the allocation's
+// network namespace in bridge and CNI networking modes, and the host address
+// of the allocation's network otherwise.
+func portForwardHost(alloc *nstructs.Allocation, state *arstate.State) string {
+	if state != nil && state.NetworkStatus != nil && state.NetworkStatus.Address != "" {
+		return state.NetworkStatus.Address
+	}
+	if alloc.AllocatedResources != nil {
+		for _, nw := range alloc.AllocatedResources.Shared.Networks {
+			if nw.IP != "" {
+				return nw.IP
+			}
+		}
+	}
+	return "127.0.0.1"
+}
+
 // newExecStream returns a new exec stream as expected by drivers that interpolate with RPC streaming format
 func newExecStream(decoder *codec.Decoder, encoder *codec.Encoder) drivers.ExecTaskStream {
 	buf := new(bytes.Buffer)
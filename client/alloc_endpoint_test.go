@@ -16,6 +16,7 @@ import (
 	"github.com/hashicorp/go-msgpack/codec"
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/ci"
+	arstate "github.com/hashicorp/nomad/client/allocrunner/state"
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/lib/proclib"
 	cstructs "github.com/hashicorp/nomad/client/structs"
@@ -290,7 +291,7 @@ func TestAllocations_GarbageCollect(t *testing.T) {
 	req.AllocID = a.ID
 	testutil.WaitForResult(func() (bool, error) {
 		// Check if has been removed first
-		if ar, ok := client.allocs[a.ID]; !ok || ar.IsDestroyed() {
+		if ar, ok := client.allocs.get(a.ID); !ok || ar.IsDestroyed() {
 			return true, nil
 		}
 
@@ -1419,6 +1420,103 @@ func TestAlloc_ExecStreaming_ACL_WithIsolation_None(t *testing.T) {
 	}
 }
 
+func TestAlloc_PortForward(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a server and client
+	s, cleanupS := nomad.TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c, cleanupC := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanupC()
+
+	// Start an echo server for the allocation's port. The mock job uses host
+	// networking, so the port is forwarded to the loopback address.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.Copy(conn, conn)
+	}()
+
+	job := mock.BatchJob()
+	job.TaskGroups[0].Count = 1
+	job.TaskGroups[0].Networks = nil
+	job.TaskGroups[0].Tasks[0].Config = map[string]interface{}{
+		"run_for": "20s",
+	}
+
+	// Wait for client to be running job
+	testutil.WaitForRunning(t, s.RPC, job)
+
+	// Get the allocation ID
+	args := nstructs.AllocListRequest{}
+	args.Region = "global"
+	resp := nstructs.AllocListResponse{}
+	must.NoError(t, s.RPC("Alloc.List", &args, &resp))
+	must.Len(t, 1, resp.Allocations)
+
+	// Make the request
+	req := &cstructs.AllocPortForwardRequest{
+		AllocID:      resp.Allocations[0].ID,
+		Port:         ln.Addr().(*net.TCPAddr).Port,
+		QueryOptions: nstructs.QueryOptions{Region: "global"},
+	}
+
+	// Get the handler
+	handler, err := c.StreamingRpcHandler("Allocations.PortForward")
+	must.NoError(t, err)
+
+	// Create a pipe
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	// Start the handler
+	go handler(p2)
+
+	// Send the request and data
+	encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+	must.NoError(t, encoder.Encode(req))
+	must.NoError(t, encoder.Encode(&cstructs.StreamErrWrapper{Payload: []byte("ping")}))
+
+	// Expect the data to be echoed back
+	p1.SetReadDeadline(time.Now().Add(5 * time.Second))
+	decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
+	var frame cstructs.StreamErrWrapper
+	must.NoError(t, decoder.Decode(&frame))
+	must.Nil(t, frame.Error)
+	must.Eq(t, "ping", string(frame.Payload))
+}
+
+func TestAlloc_portForwardHost(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	alloc.AllocatedResources.Shared.Networks = []*nstructs.NetworkResource{{IP: "10.0.0.1"}}
+
+	// The address of the allocation's network namespace takes precedence
+	state := &arstate.State{
+		NetworkStatus: &nstructs.AllocNetworkStatus{Address: "172.26.64.2"},
+	}
+	must.Eq(t, "172.26.64.2", portForwardHost(alloc, state))
+
+	// Host networking uses the address of the allocation's network
+	must.Eq(t, "10.0.0.1", portForwardHost(alloc, &arstate.State{}))
+
+	// Falls back to the loopback address
+	alloc.AllocatedResources.Shared.Networks = nil
+	must.Eq(t, "127.0.0.1", portForwardHost(alloc, nil))
+}
+
 func decodeFrames(t *testing.T, p1 net.Conn, frames chan<- *drivers.ExecTaskStreamingResponseMsg, errCh chan<- error) {
 	// Start the decoder
 	decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
@@ -0,0 +1,395 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"errors"
+	"hash/fnv"
+	"sync"
+
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/allocrunner/state"
+	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// allocRunnerShards is the number of shards the alloc runners of a client are
+// split into. Alloc runners are sharded by alloc ID so that clients running
+// many allocs don't serialize every alloc runner lookup, restore and update
+// on a single lock.
+const allocRunnerShards = 16
+
+// allocRunners maps alloc IDs to their AllocRunner. It includes all
+// AllocRunners - running and GC'd - until the server GCs them.
+type allocRunners struct {
+	shards [allocRunnerShards]allocRunnerShard
+}
+
+type allocRunnerShard struct {
+	lock    sync.RWMutex
+	runners map[string]interfaces.AllocRunner
+}
+
+func newAllocRunners() *allocRunners {
+	r := &allocRunners{}
+	for i := range r.shards {
+		r.shards[i].runners = make(map[string]interfaces.AllocRunner)
+	}
+	return r
+}
+
+// allocRunnerShardIndex returns the index of the shard of the alloc runner of
+// an alloc.
+func allocRunnerShardIndex(allocID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(allocID))
+	return int(h.Sum32() % allocRunnerShards)
+}
+
+func (r *allocRunners) shard(allocID string) *allocRunnerShard {
+	return &r.shards[allocRunnerShardIndex(allocID)]
+}
+
+// get returns the alloc runner of an alloc.
+func (r *allocRunners) get(allocID string) (interfaces.AllocRunner, bool) {
+	s := r.shard(allocID)
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	ar, ok := s.runners[allocID]
+	return ar, ok
+}
+
+// with calls fn with the alloc runner of an alloc, if it exists, while
+// holding the lock of its shard so the alloc runner isn't removed
+// concurrently. It returns false if the alloc has no alloc runner.
+func (r *allocRunners) with(allocID string, fn func(interfaces.AllocRunner)) bool {
+	s := r.shard(allocID)
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	ar, ok := s.runners[allocID]
+	if ok {
+		fn(ar)
+	}
+	return ok
+}
+
+// set stores the alloc runner of an alloc.
+func (r *allocRunners) set(allocID string, ar interfaces.AllocRunner) {
+	s := r.shard(allocID)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.runners[allocID] = ar
+}
+
+// remove removes and returns the alloc runner of an alloc.
+func (r *allocRunners) remove(allocID string) (interfaces.AllocRunner, bool) {
+	s := r.shard(allocID)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	ar, ok := s.runners[allocID]
+	delete(s.runners, allocID)
+	return ar, ok
+}
+
+// len returns the number of alloc runners.
+func (r *allocRunners) len() int {
+	n := 0
+	for i := range r.shards {
+		s := &r.shards[i]
+		s.lock.RLock()
+		n += len(s.runners)
+		s.lock.RUnlock()
+	}
+	return n
+}
+
+// forEach calls fn for each alloc runner, one shard at a time, while holding
+// the lock of the shard. Iteration stops if fn returns false.
+func (r *allocRunners) forEach(fn func(allocID string, ar interfaces.AllocRunner) bool) {
+	for i := range r.shards {
+		s := &r.shards[i]
+		s.lock.RLock()
+		for id, ar := range s.runners {
+			if !fn(id, ar) {
+				s.lock.RUnlock()
+				return
+			}
+		}
+		s.lock.RUnlock()
+	}
+}
+
+// errAllocRunnerHydration is returned by the methods of a lazy alloc runner
+// that failed to hydrate.
+var errAllocRunnerHydration = errors.New("alloc runner failed to restore")
+
+// lazyAllocRunner is the AllocRunner of an alloc restored from the client
+// state that was terminal when the client stopped, and whose terminal state
+// the servers acknowledged. Such allocs have nothing left to run, so their
+// alloc runner is only built, restored and run the first time it's used, for
+// example to read the alloc dir or to garbage collect the alloc. This keeps
+// the startup of clients with many terminal allocs fast and their memory
+// usage low. Reading the alloc and its state doesn't hydrate the alloc
+// runner.
+type lazyAllocRunner struct {
+	alloc *structs.Allocation
+
+	// state is the last acknowledged state of the alloc.
+	state *state.State
+
+	// hydrateFn builds, restores and runs the alloc runner. It's called at
+	// most once, and returns a nil alloc runner if the restore failed.
+	hydrateFn func() interfaces.AllocRunner
+
+	once sync.Once
+	ar   interfaces.AllocRunner
+
+	// hydrated is closed once hydrateFn has returned.
+	hydrated chan struct{}
+}
+
+func newLazyAllocRunner(alloc *structs.Allocation, allocState *state.State,
+	hydrateFn func() interfaces.AllocRunner) *lazyAllocRunner {
+	return &lazyAllocRunner{
+		alloc:     alloc,
+		state:     allocState,
+		hydrateFn: hydrateFn,
+		hydrated:  make(chan struct{}),
+	}
+}
+
+// runner hydrates the alloc runner if it wasn't already and returns it, or
+// nil if it failed to restore.
+func (l *lazyAllocRunner) runner() interfaces.AllocRunner {
+	l.once.Do(func() {
+		l.ar = l.hydrateFn()
+		close(l.hydrated)
+	})
+	return l.ar
+}
+
+// isHydrated returns true if the alloc runner was hydrated, successfully or
+// not.
+func (l *lazyAllocRunner) isHydrated() bool {
+	select {
+	case <-l.hydrated:
+		return true
+	default:
+		return false
+	}
+}
+
+// closedCh is returned by the channel methods of lazy alloc runners that
+// failed to restore, which are treated as destroyed, or that have nothing to
+// wait for.
+var closedCh = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+func (l *lazyAllocRunner) Alloc() *structs.Allocation {
+	if l.isHydrated() && l.ar != nil {
+		return l.ar.Alloc()
+	}
+	return l.alloc
+}
+
+func (l *lazyAllocRunner) Run() {
+	if ar := l.runner(); ar != nil {
+		<-ar.WaitCh()
+	}
+}
+
+func (l *lazyAllocRunner) Restore() error {
+	if l.runner() == nil {
+		return errAllocRunnerHydration
+	}
+	return nil
+}
+
+func (l *lazyAllocRunner) Update(update *structs.Allocation) {
+	if ar := l.runner(); ar != nil {
+		ar.Update(update)
+	}
+}
+
+func (l *lazyAllocRunner) Reconnect(update *structs.Allocation) error {
+	ar := l.runner()
+	if ar == nil {
+		return errAllocRunnerHydration
+	}
+	return ar.Reconnect(update)
+}
+
+func (l *lazyAllocRunner) Shutdown() {
+	// There is nothing to shut down if the alloc runner was never hydrated.
+	if l.isHydrated() && l.ar != nil {
+		l.ar.Shutdown()
+	}
+}
+
+func (l *lazyAllocRunner) Destroy() {
+	if ar := l.runner(); ar != nil {
+		ar.Destroy()
+	}
+}
+
+func (l *lazyAllocRunner) IsDestroyed() bool {
+	if !l.isHydrated() {
+		return false
+	}
+	return l.ar == nil || l.ar.IsDestroyed()
+}
+
+func (l *lazyAllocRunner) IsMigrating() bool {
+	return l.isHydrated() && l.ar != nil && l.ar.IsMigrating()
+}
+
+func (l *lazyAllocRunner) IsWaiting() bool {
+	return l.isHydrated() && l.ar != nil && l.ar.IsWaiting()
+}
+
+func (l *lazyAllocRunner) WaitCh() <-chan struct{} {
+	if ar := l.runner(); ar != nil {
+		return ar.WaitCh()
+	}
+	return closedCh
+}
+
+func (l *lazyAllocRunner) DestroyCh() <-chan struct{} {
+	if ar := l.runner(); ar != nil {
+		return ar.DestroyCh()
+	}
+	return closedCh
+}
+
+func (l *lazyAllocRunner) ShutdownCh() <-chan struct{} {
+	if l.isHydrated() && l.ar != nil {
+		return l.ar.ShutdownCh()
+	}
+	return closedCh
+}
+
+func (l *lazyAllocRunner) AllocState() *state.State {
+	if l.isHydrated() && l.ar != nil {
+		return l.ar.AllocState()
+	}
+	return l.state.Copy()
+}
+
+func (l *lazyAllocRunner) PersistState() error {
+	// The state of an alloc runner that was never hydrated is already
+	// persisted.
+	if l.isHydrated() && l.ar != nil {
+		return l.ar.PersistState()
+	}
+	return nil
+}
+
+func (l *lazyAllocRunner) AcknowledgeState(a *state.State) {
+	if ar := l.runner(); ar != nil {
+		ar.AcknowledgeState(a)
+	}
+}
+
+func (l *lazyAllocRunner) GetUpdatePriority(a *structs.Allocation) cstructs.AllocUpdatePriority {
+	if ar := l.runner(); ar != nil {
+		return ar.GetUpdatePriority(a)
+	}
+	// Send the failed update of allocs whose alloc runner failed to restore
+	return cstructs.AllocUpdatePriorityTypical
+}
+
+func (l *lazyAllocRunner) SetClientStatus(status string) {
+	if ar := l.runner(); ar != nil {
+		ar.SetClientStatus(status)
+	}
+}
+
+func (l *lazyAllocRunner) Signal(taskName, signal string) error {
+	ar := l.runner()
+	if ar == nil {
+		return errAllocRunnerHydration
+	}
+	return ar.Signal(taskName, signal)
+}
+
+func (l *lazyAllocRunner) RestartTask(taskName string, taskEvent *structs.TaskEvent) error {
+	ar := l.runner()
+	if ar == nil {
+		return errAllocRunnerHydration
+	}
+	return ar.RestartTask(taskName, taskEvent)
+}
+
+func (l *lazyAllocRunner) RestartRunning(taskEvent *structs.TaskEvent) error {
+	ar := l.runner()
+	if ar == nil {
+		return errAllocRunnerHydration
+	}
+	return ar.RestartRunning(taskEvent)
+}
+
+func (l *lazyAllocRunner) RestartAll(taskEvent *structs.TaskEvent) error {
+	ar := l.runner()
+	if ar == nil {
+		return errAllocRunnerHydration
+	}
+	return ar.RestartAll(taskEvent)
+}
+
+func (l *lazyAllocRunner) KillTask(taskName string, taskEvent *structs.TaskEvent) error {
+	ar := l.runner()
+	if ar == nil {
+		return errAllocRunnerHydration
+	}
+	return ar.KillTask(taskName, taskEvent)
+}
+
+func (l *lazyAllocRunner) GetTaskEventHandler(taskName string) drivermanager.EventHandler {
+	if ar := l.runner(); ar != nil {
+		return ar.GetTaskEventHandler(taskName)
+	}
+	return nil
+}
+
+func (l *lazyAllocRunner) GetTaskExecHandler(taskName string) drivermanager.TaskExecHandler {
+	if ar := l.runner(); ar != nil {
+		return ar.GetTaskExecHandler(taskName)
+	}
+	return nil
+}
+
+func (l *lazyAllocRunner) GetTaskDriverCapabilities(taskName string) (*drivers.Capabilities, error) {
+	ar := l.runner()
+	if ar == nil {
+		return nil, errAllocRunnerHydration
+	}
+	return ar.GetTaskDriverCapabilities(taskName)
+}
+
+func (l *lazyAllocRunner) StatsReporter() interfaces.AllocStatsReporter {
+	if ar := l.runner(); ar != nil {
+		return ar.StatsReporter()
+	}
+	return nil
+}
+
+func (l *lazyAllocRunner) Listener() *cstructs.AllocListener {
+	if ar := l.runner(); ar != nil {
+		return ar.Listener()
+	}
+	return nil
+}
+
+func (l *lazyAllocRunner) GetAllocDir() allocdir.Interface {
+	if ar := l.runner(); ar != nil {
+		return ar.GetAllocDir()
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/allocrunner/state"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestAllocRunners(t *testing.T) {
+	ci.Parallel(t)
+
+	runners := newAllocRunners()
+	ids := make([]string, 50)
+	for i := range ids {
+		alloc := mock.Alloc()
+		ar, err := newEmptyAllocRunnerFunc(&config.AllocRunnerConfig{Alloc: alloc})
+		must.NoError(t, err)
+		runners.set(alloc.ID, ar)
+		ids[i] = alloc.ID
+	}
+	must.Eq(t, 50, runners.len())
+
+	ar, ok := runners.get(ids[0])
+	must.True(t, ok)
+	must.Eq(t, ids[0], ar.Alloc().ID)
+
+	seen := 0
+	runners.forEach(func(id string, ar interfaces.AllocRunner) bool {
+		must.Eq(t, id, ar.Alloc().ID)
+		seen++
+		return true
+	})
+	must.Eq(t, 50, seen)
+
+	// Iteration stops when the callback returns false
+	seen = 0
+	runners.forEach(func(string, interfaces.AllocRunner) bool {
+		seen++
+		return false
+	})
+	must.Eq(t, 1, seen)
+
+	ar, ok = runners.remove(ids[0])
+	must.True(t, ok)
+	must.Eq(t, ids[0], ar.Alloc().ID)
+	must.False(t, runners.with(ids[0], func(interfaces.AllocRunner) {
+		t.Fatal("removed alloc runner should not be found")
+	}))
+	must.Eq(t, 49, runners.len())
+}
+
+func TestLazyAllocRunner(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.BatchAlloc()
+	allocState := &state.State{ClientStatus: structs.AllocClientStatusComplete}
+
+	t.Run("hydrate", func(t *testing.T) {
+		hydrations := 0
+		ar := newLazyAllocRunner(alloc, allocState, func() interfaces.AllocRunner {
+			hydrations++
+			ar, _ := newEmptyAllocRunnerFunc(&config.AllocRunnerConfig{Alloc: alloc})
+			return ar
+		})
+
+		// Reading the alloc, its state, and shutting down don't hydrate
+		must.Eq(t, alloc.ID, ar.Alloc().ID)
+		must.Eq(t, structs.AllocClientStatusComplete, ar.AllocState().ClientStatus)
+		must.False(t, ar.IsDestroyed())
+		must.NoError(t, ar.PersistState())
+		ar.Shutdown()
+		<-ar.ShutdownCh()
+		must.False(t, ar.isHydrated())
+
+		// Using the alloc runner hydrates it once
+		must.NoError(t, ar.Signal("web", "SIGHUP"))
+		ar.Destroy()
+		must.Eq(t, 1, hydrations)
+		must.True(t, ar.isHydrated())
+	})
+
+	t.Run("failed hydration", func(t *testing.T) {
+		ar := newLazyAllocRunner(alloc, allocState, func() interfaces.AllocRunner {
+			return nil
+		})
+
+		must.ErrorIs(t, ar.Restore(), errAllocRunnerHydration)
+		must.True(t, ar.IsDestroyed())
+		<-ar.DestroyCh()
+		<-ar.WaitCh()
+		must.Eq(t, structs.AllocClientStatusComplete, ar.AllocState().ClientStatus)
+		must.ErrorIs(t, ar.Signal("web", "SIGHUP"), errAllocRunnerHydration)
+	})
+}
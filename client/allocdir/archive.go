@@ -0,0 +1,154 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocdir
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/helper/escapingfs"
+)
+
+// archiveWalkFunc is called by walkArchive for the archived path and every
+// file beneath it. The reader holds the content of regular files and is nil
+// for other files. Returning filepath.SkipDir for a directory skips it.
+type archiveWalkFunc func(path string, hdr *tar.Header, r io.Reader) error
+
+// Archive writes a tar archive of the file or directory at the path relative
+// to the alloc dir. Entries are named relative to the parent of the path, so
+// the archive of "web/local/conf" has "conf" as its root. Symlinks are
+// archived as links, files other than directories, regular files, and symlinks
+// are skipped, and the secrets and private directories of tasks are skipped.
+func (d *AllocDir) Archive(path string, w io.Writer) error {
+	if escapes, err := escapingfs.PathEscapesAllocDir(d.AllocDir, "", path); err != nil {
+		return fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
+	} else if escapes {
+		return fmt.Errorf("Path escapes the alloc directory")
+	}
+
+	p := filepath.Join(d.AllocDir, path)
+	if err := d.checkProtected(p, "Reading"); err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(d.AllocDir, p)
+	if err != nil {
+		return err
+	}
+
+	root := filepath.Dir(p)
+	tw := tar.NewWriter(w)
+
+	walkFn := func(path string, hdr *tar.Header, r io.Reader) error {
+		if hdr.Typeflag == tar.TypeDir && d.checkProtected(path, "") != nil {
+			return filepath.SkipDir
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(relPath)
+		if hdr.Typeflag == tar.TypeDir {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if r == nil {
+			return nil
+		}
+		_, err = io.Copy(tw, r)
+		return err
+	}
+
+	if err := walkArchive(d.AllocDir, rel, walkFn); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// Extract extracts a tar archive into the directory at the path relative to
+// the alloc dir. Only directories, regular files, and symlinks that don't
+// point outside of the alloc dir are extracted. Extracted files are owned by
+// the owner of the destination directory, and files can't be written to the
+// secrets and private directories of tasks.
+func (d *AllocDir) Extract(path string, r io.Reader) error {
+	if escapes, err := escapingfs.PathEscapesAllocDir(d.AllocDir, "", path); err != nil {
+		return fmt.Errorf("Failed to check if path escapes alloc directory: %v", err)
+	} else if escapes {
+		return fmt.Errorf("Path escapes the alloc directory")
+	}
+
+	dest := filepath.Join(d.AllocDir, path)
+	if err := d.checkProtected(dest, "Writing"); err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(d.AllocDir, dest)
+	if err != nil {
+		return err
+	}
+	uid, gid, err := extractOwner(d.AllocDir, rel)
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		target := filepath.Join(dest, name)
+		if filepath.IsAbs(name) || escapingfs.PathEscapesSandbox(d.AllocDir, target) {
+			return fmt.Errorf("Archive entry %q escapes the alloc directory", hdr.Name)
+		}
+		if err := d.checkProtected(target, "Writing"); err != nil {
+			return err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir, tar.TypeReg:
+		case tar.TypeSymlink:
+			linkTarget := filepath.FromSlash(hdr.Linkname)
+			if filepath.IsAbs(linkTarget) ||
+				escapingfs.PathEscapesSandbox(d.AllocDir, filepath.Join(filepath.Dir(target), linkTarget)) {
+				return fmt.Errorf("Archive entry %q links outside of the alloc directory", hdr.Name)
+			}
+		default:
+			return fmt.Errorf("Archive entry %q has unsupported type %q", hdr.Name, hdr.Typeflag)
+		}
+
+		if err := extractEntry(d.AllocDir, filepath.Join(rel, name), hdr, tr, uid, gid); err != nil {
+			return fmt.Errorf("Archive entry %q: %v", hdr.Name, err)
+		}
+	}
+}
+
+// checkProtected returns an error if the path is in the secrets or private
+// directory of a task. The action prefixes the error message. Archive and
+// Extract never follow symlinks beneath the alloc dir, so comparing the paths
+// is enough to keep a symlink from reaching into a protected directory.
+func (d *AllocDir) checkProtected(p, action string) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, dir := range d.TaskDirs {
+		if filepath.HasPrefix(p, dir.SecretsDir) {
+			return fmt.Errorf("%s secret file prohibited: %s", action, strings.TrimPrefix(p, d.AllocDir))
+		}
+		if filepath.HasPrefix(p, dir.PrivateDir) {
+			return fmt.Errorf("%s private file prohibited: %s", action, strings.TrimPrefix(p, d.AllocDir))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build unix && !solaris
+
+package allocdir
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// readlinkAt returns the target of the symlink with the name in dir.
+func readlinkAt(dir *os.File, name string) (string, error) {
+	for size := 128; ; size *= 2 {
+		buf := make([]byte, size)
+		n, err := unix.Readlinkat(int(dir.Fd()), name, buf)
+		if err != nil {
+			return "", err
+		}
+		if n < size {
+			return string(buf[:n]), nil
+		}
+	}
+}
+
+// symlinkAt creates a symlink with the name in dir.
+func symlinkAt(target string, dir *os.File, name string) error {
+	return unix.Symlinkat(target, int(dir.Fd()), name)
+}
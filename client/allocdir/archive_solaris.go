@@ -0,0 +1,21 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocdir
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// readlinkAt returns the target of the symlink with the name in dir. Solaris
+// has no readlinkat, so the link is read through the path of dir.
+func readlinkAt(dir *os.File, name string) (string, error) {
+	return os.Readlink(filepath.Join(dir.Name(), name))
+}
+
+// symlinkAt creates a symlink with the name in dir. Solaris has no symlinkat,
+// so the link is created through the path of dir.
+func symlinkAt(target string, dir *os.File, name string) error {
+	return os.Symlink(target, filepath.Join(dir.Name(), name))
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocdir
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/plugins/drivers/fsisolation"
+	"github.com/shoenig/test/must"
+)
+
+func TestAllocDir_ArchiveExtract(t *testing.T) {
+	ci.Parallel(t)
+	tmp := t.TempDir()
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, tmp, "test")
+	must.NoError(t, d.Build())
+	defer func() { _ = d.Destroy() }()
+
+	td := d.NewTaskDir(t1.Name)
+	must.NoError(t, td.Build(fsisolation.None, nil, "nobody"))
+
+	conf := filepath.Join(td.LocalDir, "conf")
+	must.NoError(t, os.MkdirAll(filepath.Join(conf, "nested"), 0o755))
+	must.NoError(t, os.WriteFile(filepath.Join(conf, "a.txt"), []byte("a"), 0o644))
+	must.NoError(t, os.WriteFile(filepath.Join(conf, "nested", "b.txt"), []byte("bb"), 0o600))
+	must.NoError(t, os.Symlink("a.txt", filepath.Join(conf, "link")))
+
+	var buf bytes.Buffer
+	must.NoError(t, d.Archive("web/local/conf", &buf))
+
+	names := map[string]string{}
+	tr := tar.NewReader(bytes.NewReader(buf.Bytes()))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		must.NoError(t, err)
+		names[hdr.Name] = hdr.Linkname
+	}
+	must.Eq(t, map[string]string{
+		"conf/":             "",
+		"conf/a.txt":        "",
+		"conf/link":         "a.txt",
+		"conf/nested/":      "",
+		"conf/nested/b.txt": "",
+	}, names)
+
+	// Extract the archive into another task dir
+	must.NoError(t, d.Extract("web/tmp", &buf))
+	out, err := os.ReadFile(filepath.Join(td.Dir, "tmp", "conf", "nested", "b.txt"))
+	must.NoError(t, err)
+	must.Eq(t, "bb", string(out))
+	target, err := os.Readlink(filepath.Join(td.Dir, "tmp", "conf", "link"))
+	must.NoError(t, err)
+	must.Eq(t, "a.txt", target)
+
+	// The secrets dir can't be archived or written
+	err = d.Archive("web/secrets", io.Discard)
+	must.EqError(t, err, "Reading secret file prohibited: /web/secrets")
+	err = d.Extract("web/secrets", bytes.NewReader(nil))
+	must.EqError(t, err, "Writing secret file prohibited: /web/secrets")
+}
+
+func TestAllocDir_Extract_Escapes(t *testing.T) {
+	ci.Parallel(t)
+	tmp := t.TempDir()
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, tmp, "test")
+	must.NoError(t, d.Build())
+	defer func() { _ = d.Destroy() }()
+
+	archive := func(hdrs ...*tar.Header) io.Reader {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for _, hdr := range hdrs {
+			must.NoError(t, tw.WriteHeader(hdr))
+		}
+		must.NoError(t, tw.Close())
+		return &buf
+	}
+
+	err := d.Extract("../", archive())
+	must.ErrorContains(t, err, "escapes")
+
+	err = d.Extract("alloc", archive(&tar.Header{
+		Name: "../../escape", Typeflag: tar.TypeReg, Mode: 0o644}))
+	must.ErrorContains(t, err, "escapes the alloc directory")
+
+	err = d.Extract("alloc", archive(&tar.Header{
+		Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc"}))
+	must.ErrorContains(t, err, "links outside of the alloc directory")
+
+	err = d.Extract("alloc", archive(&tar.Header{
+		Name: "link", Typeflag: tar.TypeSymlink, Linkname: "../../.."}))
+	must.ErrorContains(t, err, "links outside of the alloc directory")
+
+	err = d.Extract("alloc", archive(&tar.Header{
+		Name: "fifo", Typeflag: tar.TypeFifo, Mode: 0o644}))
+	must.ErrorContains(t, err, "unsupported type")
+}
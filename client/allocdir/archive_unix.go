@@ -0,0 +1,260 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build unix
+
+package allocdir
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+const openDirFlags = unix.O_RDONLY | unix.O_DIRECTORY | unix.O_NOFOLLOW | unix.O_CLOEXEC
+
+// openDirBeneath opens the directory at the path relative to the root one
+// component at a time without following symlinks. Tasks can write to the alloc
+// dir while the client reads or writes it as root, so resolving the path once
+// and using it later would let a task swap a component for a symlink in
+// between. Missing directories are created if mkdir is set.
+func openDirBeneath(root, path string, mkdir bool) (*os.File, error) {
+	dir, err := os.Open(root)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range strings.Split(filepath.Clean(path), string(filepath.Separator)) {
+		if name == "." || name == "" {
+			continue
+		}
+		if name == ".." {
+			dir.Close()
+			return nil, errors.New("path escapes the alloc directory")
+		}
+
+		sub, err := openDirAt(dir, name, mkdir)
+		dir.Close()
+		if err != nil {
+			return nil, err
+		}
+		dir = sub
+	}
+	return dir, nil
+}
+
+// openDirAt opens the directory with the name in dir without following
+// symlinks, and creates it first if mkdir is set.
+func openDirAt(dir *os.File, name string, mkdir bool) (*os.File, error) {
+	dirfd := int(dir.Fd())
+	path := filepath.Join(dir.Name(), name)
+
+	fd, err := unix.Openat(dirfd, name, openDirFlags, 0)
+	if err == unix.ENOENT && mkdir {
+		if err = unix.Mkdirat(dirfd, name, 0o755); err == nil || err == unix.EEXIST {
+			fd, err = unix.Openat(dirfd, name, openDirFlags, 0)
+		}
+	}
+	if err != nil {
+		if isSymlinkAt(dirfd, name) {
+			return nil, fmt.Errorf("path %q is a symlink", path)
+		}
+		return nil, &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	return os.NewFile(uintptr(fd), path), nil
+}
+
+// isSymlinkAt returns whether the file with the name in the directory is a
+// symlink.
+func isSymlinkAt(dirfd int, name string) bool {
+	var st unix.Stat_t
+	if err := unix.Fstatat(dirfd, name, &st, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		return false
+	}
+	return st.Mode&unix.S_IFMT == unix.S_IFLNK
+}
+
+// walkArchive walks the path relative to the root without following symlinks
+// and calls fn for every directory, regular file, and symlink.
+func walkArchive(root, path string, fn archiveWalkFunc) error {
+	dir, err := openDirBeneath(root, filepath.Dir(path), false)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return walkAt(dir, filepath.Base(path), fn)
+}
+
+// walkAt calls fn for the file with the name in dir and, if it's a directory,
+// walks the files in it in lexical order.
+func walkAt(dir *os.File, name string, fn archiveWalkFunc) error {
+	dirfd := int(dir.Fd())
+	path := filepath.Join(dir.Name(), name)
+
+	var st unix.Stat_t
+	if err := unix.Fstatat(dirfd, name, &st, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+		return &os.PathError{Op: "lstat", Path: path, Err: err}
+	}
+
+	hdr := &tar.Header{}
+	var r io.Reader
+	switch st.Mode & unix.S_IFMT {
+	case unix.S_IFDIR:
+		hdr.Typeflag = tar.TypeDir
+	case unix.S_IFLNK:
+		link, err := readlinkAt(dir, name)
+		if err != nil {
+			return fmt.Errorf("error reading symlink: %v", err)
+		}
+		hdr.Typeflag = tar.TypeSymlink
+		hdr.Linkname = link
+	case unix.S_IFREG:
+		fd, err := unix.Openat(dirfd, name, unix.O_RDONLY|unix.O_NOFOLLOW|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+		if err != nil {
+			return &os.PathError{Op: "open", Path: path, Err: err}
+		}
+		f := os.NewFile(uintptr(fd), path)
+		defer f.Close()
+
+		// The file may have been replaced since it was checked, so the
+		// header is built from the file that was opened.
+		if err := unix.Fstat(fd, &st); err != nil {
+			return &os.PathError{Op: "fstat", Path: path, Err: err}
+		}
+		if st.Mode&unix.S_IFMT != unix.S_IFREG {
+			return fmt.Errorf("%q changed while being archived", path)
+		}
+		hdr.Typeflag = tar.TypeReg
+		hdr.Size = st.Size
+		r = io.LimitReader(f, st.Size)
+	default:
+		return nil
+	}
+	hdr.Mode = int64(st.Mode & 0o7777)
+	hdr.Uid = int(st.Uid)
+	hdr.Gid = int(st.Gid)
+	hdr.ModTime = time.Unix(st.Mtim.Unix())
+
+	if err := fn(path, hdr, r); err != nil {
+		if err == filepath.SkipDir && hdr.Typeflag == tar.TypeDir {
+			return nil
+		}
+		return err
+	}
+	if hdr.Typeflag != tar.TypeDir {
+		return nil
+	}
+
+	sub, err := openDirAt(dir, name, false)
+	if err != nil {
+		return err
+	}
+	defer sub.Close()
+
+	names, err := sub.Readdirnames(-1)
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := walkAt(sub, name, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractOwner returns the owner of the destination directory of Extract.
+func extractOwner(root, path string) (int, int, error) {
+	dir, err := openDirBeneath(root, path, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer dir.Close()
+
+	fi, err := dir.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+	uid, gid := getOwner(fi)
+	return uid, gid, nil
+}
+
+// extractEntry writes an archive entry to the path relative to the root. The
+// parent directories are opened without following symlinks and created if
+// they're missing, and an existing file at the path is replaced rather than
+// written through, so a symlink or hard link to a file outside of the alloc
+// dir can't be used to write to it.
+func extractEntry(root, path string, hdr *tar.Header, r io.Reader, uid, gid int) error {
+	dir, err := openDirBeneath(root, filepath.Dir(path), true)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	dirfd := int(dir.Fd())
+	name := filepath.Base(path)
+	mode := uint32(hdr.FileInfo().Mode().Perm())
+
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		if err := unix.Mkdirat(dirfd, name, mode); err != nil && err != unix.EEXIST {
+			return &os.PathError{Op: "mkdir", Path: path, Err: err}
+		}
+		f, err := openDirAt(dir, name, false)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return f.Chown(uid, gid)
+
+	case tar.TypeReg:
+		if err := unlinkAt(dirfd, name); err != nil {
+			return &os.PathError{Op: "remove", Path: path, Err: err}
+		}
+		fd, err := unix.Openat(dirfd, name, unix.O_WRONLY|unix.O_CREAT|unix.O_EXCL|unix.O_NOFOLLOW|unix.O_CLOEXEC, mode)
+		if err != nil {
+			return &os.PathError{Op: "open", Path: path, Err: err}
+		}
+		f := os.NewFile(uintptr(fd), path)
+		if _, err := io.Copy(f, r); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Chown(uid, gid); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+
+	case tar.TypeSymlink:
+		if err := unlinkAt(dirfd, name); err != nil {
+			return &os.PathError{Op: "remove", Path: path, Err: err}
+		}
+		if err := symlinkAt(filepath.FromSlash(hdr.Linkname), dir, name); err != nil {
+			return &os.PathError{Op: "symlink", Path: path, Err: err}
+		}
+		if err := unix.Fchownat(dirfd, name, uid, gid, unix.AT_SYMLINK_NOFOLLOW); err != nil {
+			return &os.PathError{Op: "lchown", Path: path, Err: err}
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported type %q", hdr.Typeflag)
+}
+
+// unlinkAt removes the file with the name in the directory if it exists.
+func unlinkAt(dirfd int, name string) error {
+	if err := unix.Unlinkat(dirfd, name, 0); err != nil && err != unix.ENOENT {
+		return err
+	}
+	return nil
+}
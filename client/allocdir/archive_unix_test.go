@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build unix
+
+package allocdir
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/plugins/drivers/fsisolation"
+	"github.com/shoenig/test/must"
+)
+
+func TestAllocDir_ArchiveExtract_NoFollow(t *testing.T) {
+	ci.Parallel(t)
+	tmp := t.TempDir()
+
+	d := NewAllocDir(testlog.HCLogger(t), tmp, tmp, "test")
+	must.NoError(t, d.Build())
+	defer func() { _ = d.Destroy() }()
+
+	td := d.NewTaskDir(t1.Name)
+	must.NoError(t, td.Build(fsisolation.None, nil, "nobody"))
+	secret := filepath.Join(td.SecretsDir, "token")
+	must.NoError(t, os.WriteFile(secret, []byte("secret"), 0o600))
+
+	archive := func(hdr *tar.Header, content string) io.Reader {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		must.NoError(t, tw.WriteHeader(hdr))
+		_, err := tw.Write([]byte(content))
+		must.NoError(t, err)
+		must.NoError(t, tw.Close())
+		return &buf
+	}
+
+	// Files can't be written through symlinks pointing outside
+	outside := t.TempDir()
+	must.NoError(t, os.Symlink(outside, filepath.Join(d.SharedDir, "out")))
+	err := d.Extract("alloc", archive(&tar.Header{
+		Name: "out/new/file", Typeflag: tar.TypeReg, Mode: 0o644}, ""))
+	must.ErrorContains(t, err, "is a symlink")
+	_, err = os.Stat(filepath.Join(outside, "new"))
+	must.True(t, os.IsNotExist(err))
+
+	// A task can link into the secrets dir of another task, but the link
+	// isn't followed by either direction of the copy
+	must.NoError(t, os.Symlink(td.SecretsDir, filepath.Join(d.SharedDir, "secrets")))
+
+	err = d.Archive("alloc/secrets/token", io.Discard)
+	must.ErrorContains(t, err, "is a symlink")
+
+	token := &tar.Header{Name: "token", Typeflag: tar.TypeReg, Mode: 0o644, Size: 3}
+	err = d.Extract("alloc/secrets", archive(token, "new"))
+	must.ErrorContains(t, err, "is a symlink")
+
+	out, err := os.ReadFile(secret)
+	must.NoError(t, err)
+	must.Eq(t, "secret", string(out))
+
+	// Files are replaced rather than written through existing links
+	must.NoError(t, os.Symlink(secret, filepath.Join(d.SharedDir, "token")))
+	must.NoError(t, d.Extract("alloc", archive(token, "new")))
+
+	out, err = os.ReadFile(secret)
+	must.NoError(t, err)
+	must.Eq(t, "secret", string(out))
+	out, err = os.ReadFile(filepath.Join(d.SharedDir, "token"))
+	must.NoError(t, err)
+	must.Eq(t, "new", string(out))
+}
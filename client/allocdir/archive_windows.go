@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocdir
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/nomad/helper/escapingfs"
+)
+
+// walkArchive walks the path relative to the root and calls fn for every
+// directory, regular file, and symlink.
+func walkArchive(root, path string, fn archiveWalkFunc) error {
+	return filepath.Walk(filepath.Join(root, path), func(path string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		switch {
+		case fileInfo.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return fmt.Errorf("error reading symlink: %v", err)
+			}
+			link = target
+		case !fileInfo.IsDir() && !fileInfo.Mode().IsRegular():
+			return nil
+		}
+		hdr, err := tar.FileInfoHeader(fileInfo, link)
+		if err != nil {
+			return fmt.Errorf("error creating file header: %v", err)
+		}
+
+		if !fileInfo.Mode().IsRegular() {
+			return fn(path, hdr, nil)
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return fn(path, hdr, file)
+	})
+}
+
+// extractOwner returns the owner of the destination directory of Extract.
+func extractOwner(root, path string) (int, int, error) {
+	fi, err := os.Stat(filepath.Join(root, path))
+	if err != nil {
+		return 0, 0, err
+	}
+	if !fi.IsDir() {
+		return 0, 0, fmt.Errorf("Destination %q is not a directory", path)
+	}
+	uid, gid := getOwner(fi)
+	return uid, gid, nil
+}
+
+// extractEntry writes an archive entry to the path relative to the root.
+func extractEntry(root, path string, hdr *tar.Header, r io.Reader, uid, gid int) error {
+	target := filepath.Join(root, path)
+	if err := checkExtractParent(root, target); err != nil {
+		return err
+	}
+
+	mode := hdr.FileInfo().Mode().Perm()
+	switch hdr.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, mode)
+	case tar.TypeReg:
+		if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, r); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	case tar.TypeSymlink:
+		if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return os.Symlink(filepath.FromSlash(hdr.Linkname), target)
+	}
+	return fmt.Errorf("unsupported type %q", hdr.Typeflag)
+}
+
+// checkExtractParent creates the parent directories of an extracted file and
+// makes sure they don't escape the alloc dir through symlinks, including
+// symlinks created by previous entries of the archive.
+func checkExtractParent(root, target string) error {
+	parent := filepath.Dir(target)
+
+	// Check the closest existing ancestor before creating the missing
+	// directories, since creating them would follow its symlinks.
+	existing := parent
+	for !pathExists(existing) {
+		existing = filepath.Dir(existing)
+	}
+	rel, err := filepath.Rel(root, existing)
+	if err != nil {
+		return err
+	}
+	if escapes, err := escapingfs.PathEscapesAllocDir(root, "", rel); err != nil {
+		return err
+	} else if escapes {
+		return errors.New("path escapes the alloc directory")
+	}
+
+	return os.MkdirAll(parent, 0o755)
+}
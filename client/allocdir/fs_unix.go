@@ -65,6 +65,12 @@ func dropDirPermissions(path string, desired os.FileMode) error {
 	return nil
 }
 
+// secureSecretDir is a no-op on unix as the secrets dir permissions are set
+// when it is created and dropped.
+func secureSecretDir(dir, username string, encrypt bool) error {
+	return nil
+}
+
 // getUid for a user
 func getUid(u *user.User) (int, error) {
 	uid, err := strconv.Atoi(u.Uid)
@@ -87,19 +93,19 @@ func getGid(u *user.User) (int, error) {
 
 // linkOrCopy attempts to hardlink dst to src and fallsback to copying if the
 // hardlink fails.
-func linkOrCopy(src, dst string, uid, gid int, perm os.FileMode) error {
+func linkOrCopy(src, dst string, uid, gid int, perm os.FileMode) (embedResult, error) {
 	// Avoid link/copy if the file already exists in the chroot
 	// TODO 0.6 clean this up. This was needed because chroot creation fails
 	// when a process restarts.
 	if fileInfo, _ := os.Stat(dst); fileInfo != nil {
-		return nil
+		return embedSkipped, nil
 	}
 	// Attempt to hardlink.
 	if err := os.Link(src, dst); err == nil {
-		return nil
+		return embedLinked, nil
 	}
 
-	return fileCopy(src, dst, uid, gid, perm)
+	return embedCopied, fileCopy(src, dst, uid, gid, perm)
 }
 
 func getOwner(fi os.FileInfo) (int, int) {
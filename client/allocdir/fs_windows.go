@@ -4,8 +4,12 @@
 package allocdir
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
 )
 
 var (
@@ -20,11 +24,14 @@ var (
 	// TaskSecretsContainerPath is the path inside a container for mounted
 	// secrets directory
 	TaskSecretsContainerPath = filepath.Join("c:\\", TaskSecrets)
+
+	advapi32DLL     = windows.NewLazySystemDLL("advapi32.dll")
+	procEncryptFile = advapi32DLL.NewProc("EncryptFileW")
 )
 
 // linkOrCopy is always copies dst to src on Windows.
-func linkOrCopy(src, dst string, uid, gid int, perm os.FileMode) error {
-	return fileCopy(src, dst, uid, gid, perm)
+func linkOrCopy(src, dst string, uid, gid int, perm os.FileMode) (embedResult, error) {
+	return embedCopied, fileCopy(src, dst, uid, gid, perm)
 }
 
 // The windows version does nothing currently.
@@ -47,6 +54,67 @@ func removeSecretDir(dir string) error {
 	return os.RemoveAll(dir)
 }
 
+// secureSecretDir replaces the inherited DACL of the secrets dir with one that
+// only grants access to SYSTEM, Administrators, the agent's user and, if set,
+// the task's user. If encrypt is set the directory is marked for encryption
+// with EFS so that files created within it are encrypted at rest.
+func secureSecretDir(dir, username string, encrypt bool) error {
+	sddl := "D:P(A;OICI;FA;;;SY)(A;OICI;FA;;;BA)"
+
+	token := windows.GetCurrentProcessToken()
+	tokenUser, err := token.GetTokenUser()
+	if err != nil {
+		return fmt.Errorf("failed to lookup agent user: %w", err)
+	}
+	sddl += fmt.Sprintf("(A;OICI;FA;;;%s)", tokenUser.User.Sid.String())
+
+	if username != "" {
+		sid, _, _, err := windows.LookupSID("", username)
+		if err != nil {
+			return fmt.Errorf("failed to lookup user %q: %w", username, err)
+		}
+		sddl += fmt.Sprintf("(A;OICI;FA;;;%s)", sid.String())
+	}
+
+	sd, err := windows.SecurityDescriptorFromString(sddl)
+	if err != nil {
+		return fmt.Errorf("failed to build security descriptor for %q: %w", dir, err)
+	}
+
+	dacl, _, err := sd.DACL()
+	if err != nil {
+		return fmt.Errorf("failed to get DACL for %q: %w", dir, err)
+	}
+
+	// PROTECTED_DACL_SECURITY_INFORMATION prevents ACEs from the parent
+	// directory, such as Users read access, from being inherited.
+	err = windows.SetNamedSecurityInfo(dir, windows.SE_FILE_OBJECT,
+		windows.DACL_SECURITY_INFORMATION|windows.PROTECTED_DACL_SECURITY_INFORMATION,
+		nil, nil, dacl, nil)
+	if err != nil {
+		return fmt.Errorf("failed to set DACL on %q: %w", dir, err)
+	}
+
+	if !encrypt {
+		return nil
+	}
+
+	path, err := windows.UTF16PtrFromString(dir)
+	if err != nil {
+		return err
+	}
+
+	// BOOL EncryptFileW(
+	//   [in] LPCWSTR lpFileName
+	// );
+	// https://learn.microsoft.com/en-us/windows/win32/api/winefs/nf-winefs-encryptfilew
+	if ok, _, err := procEncryptFile.Call(uintptr(unsafe.Pointer(path))); ok == 0 {
+		return fmt.Errorf("failed to encrypt %q: %w", dir, err)
+	}
+
+	return nil
+}
+
 // The windows version does nothing currently.
 func dropDirPermissions(path string, desired os.FileMode) error {
 	return nil
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
+	metrics "github.com/armon/go-metrics"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-set/v2"
 	"github.com/hashicorp/nomad/helper/users/dynamic"
@@ -71,14 +73,54 @@ type TaskDir struct {
 	// <task_dir>/private/
 	PrivateDir string
 
+	// EncryptSecrets enables encryption at rest of the secrets/ and private/
+	// directories. Only supported on Windows.
+	EncryptSecrets bool
+
 	// skip embedding these paths in chroots. Used for avoiding embedding
 	// client.alloc_dir and client.mounts_dir recursively.
 	skip *set.Set[string]
 
+	// driver is the name of the task driver, used to label metrics
+	driver string
+
 	// logger for this task
 	logger hclog.Logger
 }
 
+// embedResult is the outcome of embedding a file into a chroot.
+type embedResult int
+
+const (
+	// embedSkipped is returned if the file already exists in the chroot
+	embedSkipped embedResult = iota
+
+	// embedLinked is returned if the file was hardlinked into the chroot
+	embedLinked
+
+	// embedCopied is returned if the file was copied into the chroot
+	embedCopied
+)
+
+// chrootStats tracks the files embedded into a chroot.
+type chrootStats struct {
+	entries     int
+	linkedBytes int64
+	copiedBytes int64
+}
+
+// record adds a file of the given size embedded into the chroot.
+func (c *chrootStats) record(result embedResult, size int64) {
+	switch result {
+	case embedLinked:
+		c.entries++
+		c.linkedBytes += size
+	case embedCopied:
+		c.entries++
+		c.copiedBytes += size
+	}
+}
+
 // newTaskDir creates a TaskDir struct with paths set. Call Build() to
 // create paths on disk.
 //
@@ -103,10 +145,24 @@ func (d *AllocDir) newTaskDir(taskName string) *TaskDir {
 	}
 }
 
+// SetDriver sets the name of the task driver, which labels the metrics emitted
+// while building the task directory.
+func (t *TaskDir) SetDriver(driver string) {
+	t.driver = driver
+}
+
+// metricLabels returns the labels of the metrics emitted for the task
+// directory.
+func (t *TaskDir) metricLabels() []metrics.Label {
+	return []metrics.Label{{Name: "driver", Value: t.driver}}
+}
+
 // Build default directories and permissions in a task directory. chrootCreated
 // allows skipping chroot creation if the caller knows it has already been
 // done. client.alloc_dir will be skipped.
 func (t *TaskDir) Build(fsi fsisolation.Mode, chroot map[string]string, username string) error {
+	defer metrics.MeasureSinceWithLabels([]string{"client", "allocdir", "build"}, time.Now(), t.metricLabels())
+
 	if err := os.MkdirAll(t.Dir, 0777); err != nil {
 		return err
 	}
@@ -153,6 +209,7 @@ func (t *TaskDir) Build(fsi fsisolation.Mode, chroot map[string]string, username
 
 	// Create the secret directory
 	if err := createSecretDir(t.SecretsDir); err != nil {
+		metrics.IncrCounterWithLabels([]string{"client", "allocdir", "secret_dir_failures"}, 1, t.metricLabels())
 		return err
 	}
 
@@ -160,8 +217,13 @@ func (t *TaskDir) Build(fsi fsisolation.Mode, chroot map[string]string, username
 		return err
 	}
 
+	if err := secureSecretDir(t.SecretsDir, username, t.EncryptSecrets); err != nil {
+		return err
+	}
+
 	// Create the private directory
 	if err := createSecretDir(t.PrivateDir); err != nil {
+		metrics.IncrCounterWithLabels([]string{"client", "allocdir", "secret_dir_failures"}, 1, t.metricLabels())
 		return err
 	}
 
@@ -169,6 +231,10 @@ func (t *TaskDir) Build(fsi fsisolation.Mode, chroot map[string]string, username
 		return err
 	}
 
+	if err := secureSecretDir(t.PrivateDir, username, t.EncryptSecrets); err != nil {
+		return err
+	}
+
 	// Build chroot if chroot filesystem isolation is going to be used
 	if fsi == fsisolation.Chroot {
 		if err := t.buildChroot(chroot); err != nil {
@@ -205,10 +271,22 @@ func (t *TaskDir) Build(fsi fsisolation.Mode, chroot map[string]string, username
 // attempts hardlink and then defaults to copying. If the path exists on the
 // host and can't be embedded an error is returned.
 func (t *TaskDir) buildChroot(entries map[string]string) error {
-	return t.embedDirs(entries)
+	var stats chrootStats
+	err := t.embedDirs(entries, &stats)
+
+	// Emit what was embedded even on failure, since a partial chroot still
+	// accounts for time spent building the task directory
+	labels := t.metricLabels()
+	metrics.IncrCounterWithLabels([]string{"client", "allocdir", "chroot", "entries_embedded"}, float32(stats.entries), labels)
+	metrics.IncrCounterWithLabels([]string{"client", "allocdir", "chroot", "bytes_linked"}, float32(stats.linkedBytes), labels)
+	metrics.IncrCounterWithLabels([]string{"client", "allocdir", "chroot", "bytes_copied"}, float32(stats.copiedBytes), labels)
+
+	return err
 }
 
-func (t *TaskDir) embedDirs(entries map[string]string) error {
+// embedDirs embeds the entries into the task directory, recording the
+// embedded files in stats.
+func (t *TaskDir) embedDirs(entries map[string]string, stats *chrootStats) error {
 	subdirs := make(map[string]string)
 	for source, dest := range entries {
 		if t.skip.Contains(source) {
@@ -231,9 +309,11 @@ func (t *TaskDir) embedDirs(entries map[string]string) error {
 			// Copy the file.
 			taskEntry := filepath.Join(t.Dir, dest)
 			uid, gid := getOwner(s)
-			if err := linkOrCopy(source, taskEntry, uid, gid, s.Mode().Perm()); err != nil {
+			result, err := linkOrCopy(source, taskEntry, uid, gid, s.Mode().Perm())
+			if err != nil {
 				return err
 			}
+			stats.record(result, s.Size())
 
 			continue
 		}
@@ -290,15 +370,17 @@ func (t *TaskDir) embedDirs(entries map[string]string) error {
 			}
 
 			uid, gid := getOwner(entry)
-			if err := linkOrCopy(hostEntry, taskEntry, uid, gid, entry.Mode().Perm()); err != nil {
+			result, err := linkOrCopy(hostEntry, taskEntry, uid, gid, entry.Mode().Perm())
+			if err != nil {
 				return err
 			}
+			stats.record(result, entry.Size())
 		}
 	}
 
 	// Recurse on self to copy subdirectories.
 	if len(subdirs) != 0 {
-		return t.embedDirs(subdirs)
+		return t.embedDirs(subdirs, stats)
 	}
 
 	return nil
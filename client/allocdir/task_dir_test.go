@@ -28,7 +28,9 @@ func TestTaskDir_EmbedNonexistent(t *testing.T) {
 
 	fakeDir := "/foobarbaz"
 	mapping := map[string]string{fakeDir: fakeDir}
-	must.NoError(t, td.embedDirs(mapping))
+	var stats chrootStats
+	must.NoError(t, td.embedDirs(mapping, &stats))
+	must.Zero(t, stats.entries)
 }
 
 // Test that building a chroot copies files from the host into the task dir.
@@ -58,7 +60,8 @@ func TestTaskDir_EmbedDirs(t *testing.T) {
 	// Create mapping from host dir to task dir.
 	taskDest := "bin/test/"
 	mapping := map[string]string{host: taskDest}
-	must.NoError(t, td.embedDirs(mapping))
+	var stats chrootStats
+	must.NoError(t, td.embedDirs(mapping, &stats))
 
 	exp := []string{filepath.Join(td.Dir, taskDest, file), filepath.Join(td.Dir, taskDest, subDirName, subFile)}
 	for _, f := range exp {
@@ -66,6 +69,15 @@ func TestTaskDir_EmbedDirs(t *testing.T) {
 			t.Fatalf("File %v not embedded: %v", f, err)
 		}
 	}
+
+	// Both files are either hardlinked or copied
+	must.Eq(t, 2, stats.entries)
+	must.Eq(t, 2, stats.linkedBytes+stats.copiedBytes)
+
+	// Embedding again skips the existing files
+	stats = chrootStats{}
+	must.NoError(t, td.embedDirs(mapping, &stats))
+	must.Zero(t, stats.entries)
 }
 
 // Test that task dirs for image based isolation don't require root.
@@ -6,6 +6,8 @@ package allocrunner
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -27,6 +29,7 @@ import (
 	"github.com/hashicorp/nomad/client/pluginmanager/csimanager"
 	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
 	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/client/serviceregistration/checks"
 	"github.com/hashicorp/nomad/client/serviceregistration/checks/checkstore"
 	"github.com/hashicorp/nomad/client/serviceregistration/wrapper"
 	cstate "github.com/hashicorp/nomad/client/state"
@@ -130,6 +133,10 @@ type allocRunner struct {
 	// acknowledged by the server (may lag behind ar.state)
 	lastAcknowledgedState *state.State
 
+	// pendingReason is the stage of the alloc runner hooks the allocation is
+	// blocked on, guarded by stateLock
+	pendingReason *structs.AllocPendingReason
+
 	stateDB cstate.StateDB
 
 	// allocDir is used to build the allocations directory structure.
@@ -324,6 +331,8 @@ func (ar *allocRunner) initTaskRunners(tasks []*structs.Task) error {
 			DriverManager:       ar.driverManager,
 			ServersContactedCh:  ar.serversContactedCh,
 			StartConditionMetCh: ar.taskCoordinator.StartConditionForTask(task),
+			TaskReadyFunc:       ar.taskReadyFunc(task.Name),
+			CheckStore:          ar.checkStore,
 			ShutdownDelayCtx:    ar.shutdownDelayCtx,
 			ServiceRegWrapper:   ar.serviceRegWrapper,
 			Getter:              ar.getter,
@@ -331,6 +340,7 @@ func (ar *allocRunner) initTaskRunners(tasks []*structs.Task) error {
 			AllocHookResources:  ar.hookResources,
 			WIDMgr:              ar.widmgr,
 			Users:               ar.users,
+			RPCClient:           ar.rpcClient,
 		}
 
 		// Create, but do not Run, the task runner
@@ -530,6 +540,16 @@ func (ar *allocRunner) TaskStateUpdated() {
 	}
 }
 
+// taskReadyFunc returns the function a prestart sidecar's TaskRunner calls once
+// it is healthy. The coordinator is notified of the readiness and then
+// re-evaluated by triggering a task state update.
+func (ar *allocRunner) taskReadyFunc(task string) func() {
+	return func() {
+		ar.taskCoordinator.TaskReady(task)
+		ar.TaskStateUpdated()
+	}
+}
+
 // handleTaskStateUpdates must be run in goroutine as it monitors
 // taskStateUpdatedCh for task state update notifications and processes task
 // states.
@@ -821,6 +841,15 @@ func (ar *allocRunner) clientAlloc(taskStates map[string]*structs.TaskState) *st
 		a.ClientStatus, a.ClientDescription = getClientStatus(taskStates)
 	}
 
+	// Surface the stage a pending allocation is blocked on, where the alloc
+	// runner hooks run before any of the tasks
+	if a.ClientStatus == structs.AllocClientStatusPending {
+		a.PendingReason = ar.pendingReason.Copy()
+		if a.PendingReason == nil {
+			a.PendingReason = taskPendingReason(taskStates)
+		}
+	}
+
 	// If the allocation is terminal, make sure all required fields are properly
 	// set.
 	if a.ClientTerminalStatus() {
@@ -912,6 +941,16 @@ func (ar *allocRunner) SetClientStatus(clientStatus string) {
 	ar.state.ClientStatus = clientStatus
 }
 
+// SetPendingReason sets the stage of the alloc runner hooks the allocation is
+// blocked on, or clears it if nil, and updates the server.
+func (ar *allocRunner) SetPendingReason(reason *structs.AllocPendingReason) {
+	ar.stateLock.Lock()
+	ar.pendingReason = reason.Copy()
+	ar.stateLock.Unlock()
+
+	ar.TaskStateUpdated()
+}
+
 func (ar *allocRunner) SetNetworkStatus(s *structs.AllocNetworkStatus) {
 	ar.stateLock.Lock()
 	defer ar.stateLock.Unlock()
@@ -1059,6 +1098,14 @@ func (ar *allocRunner) destroyImpl() {
 		ar.logger.Warn("error running destroy hooks", "error", err)
 	}
 
+	// Remove any task logs spilled outside of the alloc dir
+	if ar.clientConfig.LogSpillDir != "" {
+		spillDir := filepath.Join(ar.clientConfig.LogSpillDir, ar.id)
+		if err := os.RemoveAll(spillDir); err != nil {
+			ar.logger.Warn("failed to remove spilled task logs", "path", spillDir, "error", err)
+		}
+	}
+
 	// Wait for task state update handler to exit before removing local
 	// state if Run() ran at all.
 	<-ar.taskStateUpdateHandlerCh
@@ -1380,6 +1427,22 @@ func (ar *allocRunner) restartTasks(ctx context.Context, event *structs.TaskEven
 	return err.ErrorOrNil()
 }
 
+// KillTask kills the provided task. The task is not restarted, and the
+// allocation fails if the event fails the task.
+func (ar *allocRunner) KillTask(taskName string, event *structs.TaskEvent) error {
+	tr, ok := ar.tasks[taskName]
+	if !ok {
+		return fmt.Errorf("Could not find task runner for task: %s", taskName)
+	}
+
+	event.SetKillTimeout(tr.Task().KillTimeout, ar.clientConfig.MaxKillTimeout)
+	err := tr.Kill(context.TODO(), event)
+	if err == taskrunner.ErrTaskNotRunning {
+		return nil
+	}
+	return err
+}
+
 // Signal sends a signal request to task runners inside an allocation. If the
 // taskName is empty, then it is sent to all tasks.
 func (ar *allocRunner) Signal(taskName, signal string) error {
@@ -1451,6 +1514,17 @@ func (ar *allocRunner) GetTaskExecHandler(taskName string) drivermanager.TaskExe
 	return tr.TaskExecHandler()
 }
 
+// getTaskScriptExecutor returns the executor running the script checks of
+// Nomad services in the task, or nil if the task isn't running.
+func (ar *allocRunner) getTaskScriptExecutor(taskName string) checks.ScriptExecutor {
+	tr, ok := ar.tasks[taskName]
+	if !ok {
+		return nil
+	}
+
+	return tr.ScriptExecutor()
+}
+
 func (ar *allocRunner) GetTaskDriverCapabilities(taskName string) (*drivers.Capabilities, error) {
 	tr, ok := ar.tasks[taskName]
 	if !ok {
@@ -1508,6 +1582,8 @@ func (ar *allocRunner) GetUpdatePriority(a *structs.Allocation) cstructs.AllocUp
 		return cstructs.AllocUpdatePriorityTypical
 	case !last.NetworkStatus.Equal(a.NetworkStatus):
 		return cstructs.AllocUpdatePriorityTypical
+	case !last.PendingReason.Equal(a.PendingReason):
+		return cstructs.AllocUpdatePriorityTypical
 	}
 
 	if !maps.EqualFunc(last.TaskStates, a.TaskStates, func(st, o *structs.TaskState) bool {
@@ -37,6 +37,14 @@ type observer struct {
 	qc      *checks.QueryContext
 	check   *structs.ServiceCheck
 	allocID string
+
+	// thresholds applies the success and failure thresholds of the check to
+	// its results
+	thresholds *checks.Thresholds
+
+	// scriptExecutor returns the executor of the task running the check, for
+	// script checks
+	scriptExecutor func(string) checks.ScriptExecutor
 }
 
 // start checking our check on its interval
@@ -56,8 +64,17 @@ func (o *observer) start() {
 
 		// time to execute the check
 		case <-timer.C:
+			if o.check.Type == structs.ServiceCheckScript {
+				// the task may have been restarted since the last execution
+				o.qc.Exec = o.scriptExecutor(o.qc.Task)
+				if o.check.UseContainerHealth {
+					o.qc.Exec = checks.NewContainerHealthExecutor(o.qc.Exec)
+				}
+			}
+
 			query := checks.GetCheckQuery(o.check)
 			result := o.checker.Do(o.ctx, o.qc, query)
+			o.thresholds.Apply(result)
 
 			// and put the results into the store (already logged)
 			_ = o.checkStore.Set(o.allocID, result)
@@ -85,6 +102,9 @@ type checksHook struct {
 	allocID string
 	taskEnv *taskenv.TaskEnv
 
+	// scriptExecutor returns the executor running script checks in a task
+	scriptExecutor func(string) checks.ScriptExecutor
+
 	// fields that get re-initialized on allocation update
 	lock      sync.RWMutex
 	ctx       context.Context
@@ -98,16 +118,18 @@ func newChecksHook(
 	alloc *structs.Allocation,
 	shim checkstore.Shim,
 	network structs.NetworkStatus,
+	scriptExecutor func(string) checks.ScriptExecutor,
 	taskEnv *taskenv.TaskEnv,
 ) *checksHook {
 	h := &checksHook{
-		logger:  logger.Named(checksHookName),
-		allocID: alloc.ID,
-		alloc:   alloc,
-		shim:    shim,
-		network: network,
-		checker: checks.New(logger),
-		taskEnv: taskEnv,
+		logger:         logger.Named(checksHookName),
+		allocID:        alloc.ID,
+		alloc:          alloc,
+		shim:           shim,
+		network:        network,
+		checker:        checks.New(logger),
+		taskEnv:        taskEnv,
+		scriptExecutor: scriptExecutor,
 	}
 	h.initialize(alloc)
 	return h
@@ -164,14 +186,22 @@ func (h *checksHook) observe(alloc *structs.Allocation, services []*structs.Serv
 
 			ctx, cancel := context.WithCancel(h.ctx)
 
+			// script checks run in the task of the check, or of the service
+			task := service.TaskName
+			if check.Type == structs.ServiceCheckScript && check.TaskName != "" {
+				task = check.TaskName
+			}
+
 			// create the observer for this check
 			h.observers[id] = &observer{
-				ctx:        ctx,
-				cancel:     cancel,
-				check:      check.Copy(),
-				checkStore: h.shim,
-				checker:    h.checker,
-				allocID:    h.allocID,
+				ctx:            ctx,
+				cancel:         cancel,
+				check:          check.Copy(),
+				checkStore:     h.shim,
+				checker:        h.checker,
+				allocID:        h.allocID,
+				thresholds:     checks.NewThresholds(check),
+				scriptExecutor: h.scriptExecutor,
 				qc: &checks.QueryContext{
 					ID:               id,
 					CustomAddress:    service.Address,
@@ -180,14 +210,14 @@ func (h *checksHook) observe(alloc *structs.Allocation, services []*structs.Serv
 					Networks:         networks,
 					NetworkStatus:    h.network,
 					Group:            alloc.Name,
-					Task:             service.TaskName,
+					Task:             task,
 					Service:          service.Name,
 					Check:            check.Name,
 				},
 			}
 
 			// insert a pending result into state store for each check
-			result := checks.Stub(id, structs.GetCheckMode(check), now, alloc.Name, service.TaskName, service.Name, check.Name)
+			result := checks.Stub(id, structs.GetCheckMode(check), now, alloc.Name, task, service.Name, check.Name)
 			if err := h.shim.Set(h.allocID, result); err != nil {
 				h.logger.Error("failed to set initial check status", "id", h.allocID, "error", err)
 				continue
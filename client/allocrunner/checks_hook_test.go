@@ -172,7 +172,7 @@ func TestCheckHook_Checks_ResultsSet(t *testing.T) {
 
 		envBuilder := taskenv.NewBuilder(mock.Node(), alloc, nil, alloc.Job.Region)
 
-		h := newChecksHook(logger, alloc, checkStore, network, envBuilder.Build())
+		h := newChecksHook(logger, alloc, checkStore, network, nil, envBuilder.Build())
 
 		// initialize is called; observers are created but not started yet
 		must.MapEmpty(t, h.observers)
@@ -239,7 +239,7 @@ func TestCheckHook_Checks_UpdateSet(t *testing.T) {
 
 	envBuilder := taskenv.NewBuilder(mock.Node(), alloc, nil, alloc.Job.Region)
 
-	h := newChecksHook(logger, alloc, shim, network, envBuilder.Build())
+	h := newChecksHook(logger, alloc, shim, network, nil, envBuilder.Build())
 
 	// calling pre-run starts the observers
 	err := h.Prerun()
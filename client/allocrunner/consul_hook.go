@@ -140,6 +140,7 @@ func (h *consulHook) prepareConsulTokensForTask(task *structs.Task, tg *structs.
 		Meta: map[string]string{
 			"requested_by": fmt.Sprintf("nomad_task_%s", task.Name),
 		},
+		Partition: task.GetConsulPartition(tg),
 	}
 	token, err := h.getConsulToken(consulConfig.Name, req)
 	if err != nil {
@@ -191,6 +192,7 @@ func (h *consulHook) prepareConsulTokensForServices(services []*structs.Service,
 			Meta: map[string]string{
 				"requested_by": fmt.Sprintf("nomad_service_%s", identity.WorkloadIdentifier),
 			},
+			Partition: service.GetConsulPartition(tg),
 		}
 		token, err := h.getConsulToken(clusterName, req)
 		if err != nil {
@@ -6,6 +6,7 @@ package allocrunner
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -53,6 +54,7 @@ type allocRunnerShim interface {
 	GetTaskDriverCapabilities(string) (*drivers.Capabilities, error)
 	SetCSIVolumes(vols map[string]*state.CSIVolumeStub) error
 	GetCSIVolumes() (map[string]*state.CSIVolumeStub, error)
+	SetPendingReason(reason *structs.AllocPendingReason)
 }
 
 func newCSIHook(alloc *structs.Allocation, logger hclog.Logger, csi csimanager.Manager, rpcClient config.RPCer, arShim allocRunnerShim, hookResources *cstructs.AllocHookResources, nodeSecret string) *csiHook {
@@ -101,6 +103,7 @@ func (c *csiHook) Prerun() error {
 	defer c.volumeResultsLock.Unlock()
 
 	// Initially, populate the result map with all of the requests
+	volumeIDs := []string{}
 	for alias, volumeRequest := range tg.Volumes {
 		if volumeRequest.Type == structs.VolumeTypeCSI {
 			c.volumeResults[alias] = &volumePublishResult{
@@ -108,9 +111,21 @@ func (c *csiHook) Prerun() error {
 				stub: &state.CSIVolumeStub{
 					VolumeID: volumeRequest.VolumeID(c.alloc.Name)},
 			}
+			volumeIDs = append(volumeIDs, volumeRequest.VolumeID(c.alloc.Name))
 		}
 	}
 
+	// Claiming and mounting volumes can block for a long time, so the
+	// allocation reports it's pending on the volumes until it's done
+	sort.Strings(volumeIDs)
+	c.allocRunnerShim.SetPendingReason(&structs.AllocPendingReason{
+		Stage:   structs.AllocPendingStageCSIVolumes,
+		Message: "Claiming and mounting CSI volumes",
+		Details: map[string]string{"volumes": strings.Join(volumeIDs, ", ")},
+		Time:    time.Now().UnixNano(),
+	})
+	defer c.allocRunnerShim.SetPendingReason(nil)
+
 	err := c.restoreMounts(c.volumeResults)
 	if err != nil {
 		return fmt.Errorf("restoring mounts: %w", err)
@@ -483,3 +483,5 @@ func (ar mockAllocRunner) GetCSIVolumes() (map[string]*state.CSIVolumeStub, erro
 	}
 	return ar.stubs, nil
 }
+
+func (ar mockAllocRunner) SetPendingReason(*structs.AllocPendingReason) {}
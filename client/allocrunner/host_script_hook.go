@@ -0,0 +1,193 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	clientconfig "github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// hostScriptHookName is the name of this hook as appears in logs
+	hostScriptHookName = "host_script"
+
+	// hostScriptOutputLimit is the maximum number of bytes of a script's
+	// output included in logs and errors
+	hostScriptOutputLimit = 1024
+
+	// hostScriptWaitDelay is how long to wait for a script's output to close
+	// after it has been killed
+	hostScriptWaitDelay = time.Second
+)
+
+// Environment variables set for host scripts in addition to the allocation's
+// environment.
+const (
+	// hostScriptEnvHookName is the name of the configured hook being run.
+	hostScriptEnvHookName = "NOMAD_ALLOC_HOOK_NAME"
+
+	// hostScriptEnvHookStage is the allocation lifecycle stage being run.
+	hostScriptEnvHookStage = "NOMAD_ALLOC_HOOK_STAGE"
+
+	// hostScriptEnvAllocTasks is a comma separated list of the names of the
+	// allocation's tasks.
+	hostScriptEnvAllocTasks = "NOMAD_ALLOC_TASKS"
+
+	// hostScriptEnvAllocAddress is the address of the allocation's network
+	// namespace, if any.
+	hostScriptEnvAllocAddress = "NOMAD_ALLOC_ADDRESS"
+)
+
+// hostScriptHook runs the scripts configured by the operator on the host at
+// allocation lifecycle points.
+type hostScriptHook struct {
+	alloc         *structs.Allocation
+	scripts       []*clientconfig.AllocHookScript
+	envBuilder    func() *taskenv.Builder
+	networkStatus structs.NetworkStatus
+	logger        hclog.Logger
+
+	// ctx is cancelled when the client shuts down so that running scripts
+	// don't block it
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newHostScriptHook(
+	logger hclog.Logger,
+	alloc *structs.Allocation,
+	scripts []*clientconfig.AllocHookScript,
+	envBuilder func() *taskenv.Builder,
+	networkStatus structs.NetworkStatus,
+) *hostScriptHook {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &hostScriptHook{
+		alloc:         alloc,
+		scripts:       scripts,
+		envBuilder:    envBuilder,
+		networkStatus: networkStatus,
+		logger:        logger.Named(hostScriptHookName),
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+func (h *hostScriptHook) Name() string {
+	return hostScriptHookName
+}
+
+// Prerun runs the prerun scripts. A failing script with the "fail" failure
+// policy fails the allocation.
+func (h *hostScriptHook) Prerun() error {
+	return h.run(clientconfig.AllocHookStagePrerun)
+}
+
+// Postrun runs the postrun scripts. Failures are only logged so that the
+// other postrun hooks still run.
+func (h *hostScriptHook) Postrun() error {
+	_ = h.run(clientconfig.AllocHookStagePostrun)
+	return nil
+}
+
+// Destroy runs the destroy scripts. Failures are only logged.
+func (h *hostScriptHook) Destroy() error {
+	_ = h.run(clientconfig.AllocHookStageDestroy)
+	return nil
+}
+
+// Shutdown stops any running scripts when the client shuts down.
+func (h *hostScriptHook) Shutdown() {
+	h.cancel()
+}
+
+// run runs the scripts configured for the stage in order, and returns the
+// error of the first failing script with the "fail" failure policy.
+func (h *hostScriptHook) run(stage string) error {
+	for _, script := range h.scripts {
+		if !script.HasStage(stage) {
+			continue
+		}
+
+		err := h.runScript(script, stage)
+		if err == nil {
+			continue
+		}
+
+		if stage == clientconfig.AllocHookStagePrerun &&
+			script.FailurePolicy == clientconfig.AllocHookFailurePolicyFail {
+			return fmt.Errorf("alloc hook %q failed: %v", script.Name, err)
+		}
+		h.logger.Warn("alloc hook failed", "hook", script.Name, "stage", stage, "error", err)
+	}
+	return nil
+}
+
+func (h *hostScriptHook) runScript(script *clientconfig.AllocHookScript, stage string) error {
+	ctx, cancel := context.WithTimeout(h.ctx, script.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, script.Command, script.Args...)
+	cmd.Env = append(os.Environ(), h.env(script, stage)...)
+
+	// Don't wait on the output of children that outlive a killed script
+	cmd.WaitDelay = hostScriptWaitDelay
+
+	h.logger.Debug("running alloc hook", "hook", script.Name, "stage", stage)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %s", script.Timeout)
+		}
+		if len(output) > 0 {
+			err = fmt.Errorf("%v: %s", err, truncateOutput(output))
+		}
+		return err
+	}
+
+	if len(output) > 0 {
+		h.logger.Trace("alloc hook output", "hook", script.Name, "stage", stage,
+			"output", truncateOutput(output))
+	}
+	return nil
+}
+
+// env returns the environment variables for a script, in the form of
+// NAME=value pairs.
+func (h *hostScriptHook) env(script *clientconfig.AllocHookScript, stage string) []string {
+	tg := h.alloc.Job.LookupTaskGroup(h.alloc.TaskGroup)
+	tasks := make([]string, 0, len(tg.Tasks))
+	for _, task := range tg.Tasks {
+		tasks = append(tasks, task.Name)
+	}
+
+	env := h.envBuilder().Build().List()
+	env = append(env,
+		hostScriptEnvHookName+"="+script.Name,
+		hostScriptEnvHookStage+"="+stage,
+		hostScriptEnvAllocTasks+"="+strings.Join(tasks, ","),
+	)
+	if status := h.networkStatus.NetworkStatus(); status != nil && status.Address != "" {
+		env = append(env, hostScriptEnvAllocAddress+"="+status.Address)
+	}
+	return env
+}
+
+// truncateOutput returns the output of a script, limited to
+// hostScriptOutputLimit bytes.
+func truncateOutput(output []byte) string {
+	out := strings.TrimSpace(string(output))
+	if len(out) > hostScriptOutputLimit {
+		out = out[:hostScriptOutputLimit] + "..."
+	}
+	return out
+}
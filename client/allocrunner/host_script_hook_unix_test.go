@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !windows
+
+package allocrunner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	clientconfig "github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/shoenig/test/must"
+)
+
+var (
+	_ interfaces.RunnerPrerunHook  = (*hostScriptHook)(nil)
+	_ interfaces.RunnerPostrunHook = (*hostScriptHook)(nil)
+	_ interfaces.RunnerDestroyHook = (*hostScriptHook)(nil)
+	_ interfaces.ShutdownHook      = (*hostScriptHook)(nil)
+)
+
+func testHostScriptHook(t *testing.T, scripts ...*clientconfig.AllocHookScript) *hostScriptHook {
+	alloc := mock.Alloc()
+	node := mock.Node()
+	envBuilder := func() *taskenv.Builder {
+		return taskenv.NewBuilder(node, alloc, nil, "global")
+	}
+	return newHostScriptHook(testlog.HCLogger(t), alloc, scripts, envBuilder,
+		mock.NewNetworkStatus("10.0.0.2"))
+}
+
+func TestHostScriptHook_Env(t *testing.T) {
+	ci.Parallel(t)
+
+	out := filepath.Join(t.TempDir(), "env")
+	h := testHostScriptHook(t, &clientconfig.AllocHookScript{
+		Name:          "register",
+		Command:       "/bin/sh",
+		Args:          []string{"-c", "env > " + out},
+		Stages:        []string{clientconfig.AllocHookStagePrerun},
+		Timeout:       5 * time.Second,
+		FailurePolicy: clientconfig.AllocHookFailurePolicyFail,
+	})
+
+	must.NoError(t, h.Prerun())
+
+	b, err := os.ReadFile(out)
+	must.NoError(t, err)
+	env := strings.Split(string(b), "\n")
+	must.SliceContains(t, env, "NOMAD_ALLOC_ID="+h.alloc.ID)
+	must.SliceContains(t, env, "NOMAD_JOB_NAME="+h.alloc.Job.Name)
+	must.SliceContains(t, env, "NOMAD_ALLOC_HOOK_NAME=register")
+	must.SliceContains(t, env, "NOMAD_ALLOC_HOOK_STAGE=prerun")
+	must.SliceContains(t, env, "NOMAD_ALLOC_TASKS=web")
+	must.SliceContains(t, env, "NOMAD_ALLOC_ADDRESS=10.0.0.2")
+}
+
+func TestHostScriptHook_Stages(t *testing.T) {
+	ci.Parallel(t)
+
+	out := filepath.Join(t.TempDir(), "stages")
+	h := testHostScriptHook(t, &clientconfig.AllocHookScript{
+		Name:    "audit",
+		Command: "/bin/sh",
+		Args:    []string{"-c", `echo "$NOMAD_ALLOC_HOOK_STAGE" >> ` + out},
+		Stages: []string{
+			clientconfig.AllocHookStagePostrun,
+			clientconfig.AllocHookStageDestroy,
+		},
+		Timeout:       5 * time.Second,
+		FailurePolicy: clientconfig.AllocHookFailurePolicyFail,
+	})
+
+	must.NoError(t, h.Prerun())
+	must.NoError(t, h.Postrun())
+	must.NoError(t, h.Destroy())
+
+	b, err := os.ReadFile(out)
+	must.NoError(t, err)
+	must.Eq(t, "postrun\ndestroy\n", string(b))
+}
+
+func TestHostScriptHook_FailurePolicy(t *testing.T) {
+	ci.Parallel(t)
+
+	failing := &clientconfig.AllocHookScript{
+		Name:          "failing",
+		Command:       "/bin/sh",
+		Args:          []string{"-c", "echo oops; exit 1"},
+		Stages:        []string{clientconfig.AllocHookStagePrerun, clientconfig.AllocHookStagePostrun},
+		Timeout:       5 * time.Second,
+		FailurePolicy: clientconfig.AllocHookFailurePolicyFail,
+	}
+
+	h := testHostScriptHook(t, failing)
+	err := h.Prerun()
+	must.ErrorContains(t, err, `alloc hook "failing" failed`)
+	must.ErrorContains(t, err, "oops")
+
+	// failures after the allocation ran never fail it
+	must.NoError(t, h.Postrun())
+
+	ignored := failing.Copy()
+	ignored.FailurePolicy = clientconfig.AllocHookFailurePolicyIgnore
+	h = testHostScriptHook(t, ignored)
+	must.NoError(t, h.Prerun())
+}
+
+func TestHostScriptHook_Timeout(t *testing.T) {
+	ci.Parallel(t)
+
+	h := testHostScriptHook(t, &clientconfig.AllocHookScript{
+		Name:          "slow",
+		Command:       "/bin/sh",
+		Args:          []string{"-c", "sleep 10"},
+		Stages:        []string{clientconfig.AllocHookStagePrerun},
+		Timeout:       100 * time.Millisecond,
+		FailurePolicy: clientconfig.AllocHookFailurePolicyFail,
+	})
+
+	start := time.Now()
+	must.ErrorContains(t, h.Prerun(), "timed out after 100ms")
+	must.Less(t, 5*time.Second, time.Since(start))
+}
@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/ingress"
+	"github.com/hashicorp/nomad/client/widmgr"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	ingressHookName = "ingress"
+)
+
+// ingressHook runs the proxy of the ingress block of the task group, routing
+// the traffic received on the ports of the allocation to the healthy
+// instances of Nomad services.
+type ingressHook struct {
+	logger hclog.Logger
+	widmgr widmgr.IdentityManager
+	rpc    config.RPCer
+
+	// lock synchronizes proxy and alloc which may be mutated and read
+	// concurrently via Prerun, Update, and Postrun.
+	lock    sync.Mutex
+	alloc   *structs.Allocation
+	ingress *structs.Ingress
+	proxy   *ingress.Proxy
+}
+
+func newIngressHook(
+	logger hclog.Logger,
+	alloc *structs.Allocation,
+	widmgr widmgr.IdentityManager,
+	rpc config.RPCer,
+) *ingressHook {
+	return &ingressHook{
+		logger: logger.Named(ingressHookName),
+		alloc:  alloc,
+		widmgr: widmgr,
+		rpc:    rpc,
+	}
+}
+
+// statically assert the hook implements the expected interfaces
+var (
+	_ interfaces.RunnerPrerunHook  = (*ingressHook)(nil)
+	_ interfaces.RunnerUpdateHook  = (*ingressHook)(nil)
+	_ interfaces.RunnerPostrunHook = (*ingressHook)(nil)
+)
+
+func (*ingressHook) Name() string {
+	return ingressHookName
+}
+
+func (h *ingressHook) Prerun() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	return h.start()
+}
+
+func (h *ingressHook) Update(req *interfaces.RunnerUpdateRequest) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.alloc = req.Alloc
+
+	// Only restart the proxy if the ingress block changed, so connections
+	// being proxied aren't interrupted.
+	tg := h.alloc.Job.LookupTaskGroup(h.alloc.TaskGroup)
+	if tg == nil || tg.Ingress.Equal(h.ingress) {
+		return nil
+	}
+
+	h.stop()
+	return h.start()
+}
+
+func (h *ingressHook) Postrun() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.stop()
+	return nil
+}
+
+// start runs the proxy of the ingress block of the task group, if any. It
+// must be called with the lock held.
+func (h *ingressHook) start() error {
+	tg := h.alloc.Job.LookupTaskGroup(h.alloc.TaskGroup)
+	if tg == nil || tg.Ingress == nil {
+		return nil
+	}
+
+	addrs := make(map[string]string, len(tg.Ingress.Listeners))
+	if res := h.alloc.AllocatedResources; res != nil {
+		for _, port := range res.Shared.Ports {
+			addrs[port.Label] = net.JoinHostPort(port.HostIP, strconv.Itoa(port.Value))
+		}
+	}
+
+	// The services are resolved with the allocation the proxy was started
+	// for, as the proxy may be stopped while the lock is held.
+	alloc := h.alloc
+	proxy := ingress.NewProxy(h.logger, func(service string) ([]*structs.ServiceRegistration, error) {
+		return h.resolve(alloc, service)
+	})
+	if err := proxy.Start(tg.Ingress, addrs); err != nil {
+		return err
+	}
+
+	h.proxy = proxy
+	h.ingress = tg.Ingress
+	return nil
+}
+
+// stop stops the proxy if it is running. It must be called with the lock
+// held.
+func (h *ingressHook) stop() {
+	if h.proxy == nil {
+		return
+	}
+	h.proxy.Stop()
+	h.proxy = nil
+	h.ingress = nil
+}
+
+// resolve returns the healthy instances of the service in the namespace of
+// the allocation.
+func (h *ingressHook) resolve(alloc *structs.Allocation, service string) ([]*structs.ServiceRegistration, error) {
+	token, err := h.identityToken(alloc)
+	if err != nil {
+		return nil, err
+	}
+
+	args := structs.ServiceRegistrationByNameRequest{
+		ServiceName: service,
+		HealthyOnly: true,
+		QueryOptions: structs.QueryOptions{
+			Region:     alloc.Job.Region,
+			Namespace:  alloc.Namespace,
+			AuthToken:  token,
+			AllowStale: true,
+		},
+	}
+	var reply structs.ServiceRegistrationByNameResponse
+	if err := h.rpc.RPC(structs.ServiceRegistrationGetServiceRPCMethod, &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Services, nil
+}
+
+// identityToken returns the default workload identity of the first task of
+// the group, which is allowed to read the services of the namespace of the
+// allocation.
+func (h *ingressHook) identityToken(alloc *structs.Allocation) (string, error) {
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil || len(tg.Tasks) == 0 {
+		return "", fmt.Errorf("task group %q has no tasks", alloc.TaskGroup)
+	}
+
+	signed, err := h.widmgr.Get(structs.WIHandle{
+		IdentityName:       structs.WorkloadIdentityDefaultName,
+		WorkloadIdentifier: tg.Tasks[0].Name,
+		WorkloadType:       structs.WorkloadTypeTask,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve signed workload identity: %w", err)
+	}
+	if signed == nil {
+		return "", errors.New("no signed workload identity available")
+	}
+	return signed.JWT, nil
+}
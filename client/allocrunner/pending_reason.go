@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"sort"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// taskPendingReason returns the stage the first pending task is blocked on,
+// determined by the most recent of the task's events that marks a stage.
+func taskPendingReason(taskStates map[string]*structs.TaskState) *structs.AllocPendingReason {
+	names := make([]string, 0, len(taskStates))
+	for name := range taskStates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ts := taskStates[name]
+		if ts == nil || ts.State != structs.TaskStatePending {
+			continue
+		}
+
+		for i := len(ts.Events) - 1; i >= 0; i-- {
+			if reason := eventPendingReason(ts.Events[i]); reason != nil {
+				reason.Task = name
+				return reason
+			}
+		}
+	}
+
+	return nil
+}
+
+// eventPendingReason returns the stage a task event marks, or nil if the event
+// doesn't mark a stage.
+func eventPendingReason(event *structs.TaskEvent) *structs.AllocPendingReason {
+	reason := &structs.AllocPendingReason{
+		Message: event.DisplayMessage,
+		Time:    event.Time,
+	}
+
+	switch {
+	case event.Type == structs.TaskDownloadingArtifacts:
+		reason.Stage = structs.AllocPendingStageArtifacts
+
+	case event.Details["missing_dependencies"] != "":
+		reason.Stage = structs.AllocPendingStageTemplate
+		reason.Details = map[string]string{
+			"missing_dependencies": event.Details["missing_dependencies"],
+		}
+
+	case event.Type == structs.TaskDriverMessage && event.Details["image"] != "":
+		reason.Stage = structs.AllocPendingStageImagePull
+		reason.Message = event.DriverMessage
+		reason.Details = map[string]string{
+			"image": event.Details["image"],
+		}
+
+	default:
+		return nil
+	}
+
+	return reason
+}
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package allocrunner
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestAllocRunner_taskPendingReason(t *testing.T) {
+	ci.Parallel(t)
+
+	received := structs.NewTaskEvent(structs.TaskReceived)
+	artifacts := structs.NewTaskEvent(structs.TaskDownloadingArtifacts).
+		SetDisplayMessage("Client is downloading artifacts")
+	template := structs.NewTaskEvent("Template").
+		SetDisplayMessage("Missing: vault.read(secret/data/db)").
+		SetMissingDependencies("vault.read(secret/data/db)")
+	imagePull := &structs.TaskEvent{
+		Type:          structs.TaskDriverMessage,
+		Time:          template.Time + 1,
+		DriverMessage: "Downloading image",
+		Details:       map[string]string{"image": "redis:7"},
+	}
+
+	cases := []struct {
+		name       string
+		taskStates map[string]*structs.TaskState
+		expected   *structs.AllocPendingReason
+	}{
+		{
+			name: "no stage",
+			taskStates: map[string]*structs.TaskState{
+				"web": {State: structs.TaskStatePending, Events: []*structs.TaskEvent{received}},
+			},
+		},
+		{
+			name: "artifacts",
+			taskStates: map[string]*structs.TaskState{
+				"web": {State: structs.TaskStatePending, Events: []*structs.TaskEvent{received, artifacts}},
+			},
+			expected: &structs.AllocPendingReason{
+				Stage:   structs.AllocPendingStageArtifacts,
+				Task:    "web",
+				Message: "Client is downloading artifacts",
+				Time:    artifacts.Time,
+			},
+		},
+		{
+			name: "template",
+			taskStates: map[string]*structs.TaskState{
+				"web": {State: structs.TaskStatePending, Events: []*structs.TaskEvent{received, artifacts, template}},
+			},
+			expected: &structs.AllocPendingReason{
+				Stage:   structs.AllocPendingStageTemplate,
+				Task:    "web",
+				Message: "Missing: vault.read(secret/data/db)",
+				Details: map[string]string{"missing_dependencies": "vault.read(secret/data/db)"},
+				Time:    template.Time,
+			},
+		},
+		{
+			name: "image pull of the first pending task",
+			taskStates: map[string]*structs.TaskState{
+				"web":   {State: structs.TaskStatePending, Events: []*structs.TaskEvent{received, imagePull}},
+				"cache": {State: structs.TaskStateRunning, Events: []*structs.TaskEvent{received, template}},
+			},
+			expected: &structs.AllocPendingReason{
+				Stage:   structs.AllocPendingStageImagePull,
+				Task:    "web",
+				Message: "Downloading image",
+				Details: map[string]string{"image": "redis:7"},
+				Time:    imagePull.Time,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			must.Eq(t, tc.expected, taskPendingReason(tc.taskStates))
+		})
+	}
+}
@@ -27,6 +27,9 @@ type State struct {
 
 	// NetworkStatus captures network details not known until runtime
 	NetworkStatus *structs.AllocNetworkStatus
+
+	// PendingReason captures the stage a pending allocation is blocked on
+	PendingReason *structs.AllocPendingReason
 }
 
 // SetDeploymentStatus is a helper for updating the client-controlled
@@ -65,6 +68,7 @@ func (s *State) Copy() *State {
 		DeploymentStatus:  s.DeploymentStatus.Copy(),
 		TaskStates:        taskStates,
 		NetworkStatus:     s.NetworkStatus.Copy(),
+		PendingReason:     s.PendingReason.Copy(),
 	}
 }
 
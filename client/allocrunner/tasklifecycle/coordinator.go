@@ -94,6 +94,14 @@ type Coordinator struct {
 
 	// gates store the gates that control each task lifecycle stage.
 	gates map[lifecycleStage]*Gate
+
+	// waitHealthy are the prestart sidecar tasks that must be marked ready
+	// before the prestart stage is done.
+	waitHealthy map[string]struct{}
+
+	// readyTasks are the tasks in waitHealthy that have been marked ready. It
+	// must only be accessed while holding currentStateLock.
+	readyTasks map[string]struct{}
 }
 
 // NewCoordinator returns a new Coordinator with all tasks initially blocked.
@@ -102,6 +110,14 @@ func NewCoordinator(logger hclog.Logger, tasks []*structs.Task, shutdownCh <-cha
 		logger:           logger.Named("task_coordinator"),
 		tasksByLifecycle: indexTasksByLifecycle(tasks),
 		gates:            make(map[lifecycleStage]*Gate),
+		waitHealthy:      make(map[string]struct{}),
+		readyTasks:       make(map[string]struct{}),
+	}
+
+	for _, task := range tasks {
+		if task.IsPrestart() && task.Lifecycle.Sidecar && task.Lifecycle.WaitsHealthy() {
+			c.waitHealthy[task.Name] = struct{}{}
+		}
 	}
 
 	for lifecycle := range c.tasksByLifecycle {
@@ -118,6 +134,7 @@ func NewCoordinator(logger hclog.Logger, tasks []*structs.Task, shutdownCh <-cha
 func (c *Coordinator) Restart() {
 	c.currentStateLock.Lock()
 	defer c.currentStateLock.Unlock()
+	c.readyTasks = make(map[string]struct{})
 	c.enterStateLocked(coordinatorStateInit)
 }
 
@@ -128,9 +145,29 @@ func (c *Coordinator) Restore(states map[string]*structs.TaskState) {
 	// running, causing the Coordinator to be stuck waiting for them to be
 	// "pending".
 	c.enterStateLocked(coordinatorStatePrestart)
+
+	// Readiness is not persisted, so if any main task has already left the
+	// "pending" state the sidecars must have been ready when it started.
+	for _, task := range c.tasksByLifecycle[lifecycleStageMain] {
+		if state, ok := states[task]; ok && state.State != structs.TaskStatePending {
+			for name := range c.waitHealthy {
+				c.readyTasks[name] = struct{}{}
+			}
+			break
+		}
+	}
+
 	c.TaskStateUpdated(states)
 }
 
+// TaskReady marks a prestart sidecar task waiting to be healthy as ready. The
+// new state is only evaluated on the next call to TaskStateUpdated.
+func (c *Coordinator) TaskReady(task string) {
+	c.currentStateLock.Lock()
+	defer c.currentStateLock.Unlock()
+	c.readyTasks[task] = struct{}{}
+}
+
 // StartConditionForTask returns a channel that is unblocked when the task is
 // allowed to run.
 func (c *Coordinator) StartConditionForTask(task *structs.Task) <-chan struct{} {
@@ -290,6 +327,7 @@ func (c *Coordinator) isInitDone(states map[string]*structs.TaskState) bool {
 //   - all ephemeral prestart tasks are successful.
 //   - no ephemeral prestart task has failed.
 //   - all prestart sidecar tasks are running.
+//   - all prestart sidecar tasks that wait to be healthy are ready.
 func (c *Coordinator) isPrestartDone(states map[string]*structs.TaskState) bool {
 	if !c.hasPrestart() {
 		return true
@@ -304,6 +342,11 @@ func (c *Coordinator) isPrestartDone(states map[string]*structs.TaskState) bool
 		if states[task].State != structs.TaskStateRunning {
 			return false
 		}
+		if _, ok := c.waitHealthy[task]; ok {
+			if _, ready := c.readyTasks[task]; !ready {
+				return false
+			}
+		}
 	}
 	return true
 }
@@ -359,6 +359,82 @@ func TestCoordinator_SidecarNeverStarts(t *testing.T) {
 	RequireTaskBlocked(t, coord, mainTask)
 }
 
+func TestCoordinator_SidecarWaitHealthy(t *testing.T) {
+	ci.Parallel(t)
+
+	logger := testlog.HCLogger(t)
+
+	alloc := mock.LifecycleAlloc()
+	tasks := alloc.Job.TaskGroups[0].Tasks
+
+	mainTask := tasks[0]
+	sideTask := tasks[1]
+	sideTask.Lifecycle.WaitHealthy = true
+
+	// Only use the tasks that we care about.
+	tasks = []*structs.Task{mainTask, sideTask}
+
+	shutdownCh := make(chan struct{})
+	defer close(shutdownCh)
+	coord := NewCoordinator(logger, tasks, shutdownCh)
+
+	states := map[string]*structs.TaskState{
+		sideTask.Name: {
+			State:  structs.TaskStatePending,
+			Failed: false,
+		},
+		mainTask.Name: {
+			State:  structs.TaskStatePending,
+			Failed: false,
+		},
+	}
+	coord.TaskStateUpdated(states)
+	RequireTaskAllowed(t, coord, sideTask)
+	RequireTaskBlocked(t, coord, mainTask)
+
+	// Sidecar is running but not yet healthy, main is still blocked.
+	states = map[string]*structs.TaskState{
+		sideTask.Name: {
+			State:     structs.TaskStateRunning,
+			Failed:    false,
+			StartedAt: time.Now(),
+		},
+		mainTask.Name: {
+			State:  structs.TaskStatePending,
+			Failed: false,
+		},
+	}
+	coord.TaskStateUpdated(states)
+	RequireTaskAllowed(t, coord, sideTask)
+	RequireTaskBlocked(t, coord, mainTask)
+
+	// Sidecar becomes ready, main is allowed to run.
+	coord.TaskReady(sideTask.Name)
+	coord.TaskStateUpdated(states)
+	RequireTaskAllowed(t, coord, sideTask)
+	RequireTaskAllowed(t, coord, mainTask)
+
+	// Readiness is reset on restart.
+	coord.Restart()
+	states = map[string]*structs.TaskState{
+		sideTask.Name: {
+			State:  structs.TaskStatePending,
+			Failed: false,
+		},
+		mainTask.Name: {
+			State:  structs.TaskStatePending,
+			Failed: false,
+		},
+	}
+	coord.TaskStateUpdated(states)
+	states[sideTask.Name] = &structs.TaskState{
+		State:     structs.TaskStateRunning,
+		StartedAt: time.Now(),
+	}
+	coord.TaskStateUpdated(states)
+	RequireTaskBlocked(t, coord, mainTask)
+}
+
 func TestCoordinator_PoststartStartsAfterMain(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/users"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/ryanuber/go-glob"
+)
+
+// AllocMetadataFile is the name of the file in the task's secrets directory
+// the allocation and node metadata are written to.
+const AllocMetadataFile = "alloc_metadata.json"
+
+var _ interfaces.TaskPrestartHook = &allocMetadataHook{}
+var _ interfaces.TaskUpdateHook = &allocMetadataHook{}
+
+// allocMetadata is the content of the alloc metadata file. Unlike the task
+// environment it isn't subject to environment size limits.
+type allocMetadata struct {
+	AllocID    string
+	AllocName  string
+	AllocIndex int
+	Namespace  string
+	JobID      string
+	JobName    string
+	JobVersion uint64
+	TaskGroup  string
+	Task       string
+
+	// Meta is the merged job, group, and task metadata
+	Meta map[string]string
+
+	Node      allocMetadataNode
+	Resources allocMetadataResources
+}
+
+type allocMetadataNode struct {
+	ID         string
+	Name       string
+	Datacenter string
+	NodeClass  string
+	NodePool   string
+
+	// Attributes are the node attributes allowed by the client's
+	// alloc_metadata_node_attributes configuration.
+	Attributes map[string]string
+}
+
+type allocMetadataResources struct {
+	CPU           int64
+	ReservedCores []uint16
+	MemoryMB      int64
+	MemoryMaxMB   int64
+	DiskMB        int64
+}
+
+// allocMetadataHook writes the allocation and node metadata to a JSON file in
+// the task's secrets directory, and rewrites it on in-place updates.
+type allocMetadataHook struct {
+	node      *structs.Node
+	nodeAttrs []string
+	task      string
+	logger    hclog.Logger
+
+	// path and user are set in Prestart
+	path string
+	user string
+	mu   sync.Mutex
+}
+
+func newAllocMetadataHook(node *structs.Node, nodeAttrs []string, task string, logger hclog.Logger) *allocMetadataHook {
+	h := &allocMetadataHook{
+		node:      node,
+		nodeAttrs: nodeAttrs,
+		task:      task,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*allocMetadataHook) Name() string {
+	return "alloc_metadata"
+}
+
+func (h *allocMetadataHook) Prestart(_ context.Context, req *interfaces.TaskPrestartRequest, _ *interfaces.TaskPrestartResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.path = filepath.Join(req.TaskDir.SecretsDir, AllocMetadataFile)
+	h.user = req.Task.User
+	return h.write(req.Alloc)
+}
+
+func (h *allocMetadataHook) Update(_ context.Context, req *interfaces.TaskUpdateRequest, _ *interfaces.TaskUpdateResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.path == "" {
+		// Task hasn't started yet
+		return nil
+	}
+	if err := h.write(req.Alloc); err != nil {
+		// Failing to refresh the file must not fail the update
+		h.logger.Error("failed to update alloc metadata file", "error", err)
+	}
+	return nil
+}
+
+// write renders the metadata of alloc and atomically replaces the file so
+// tasks never read a partial file.
+func (h *allocMetadataHook) write(alloc *structs.Allocation) error {
+	buf, err := json.MarshalIndent(h.metadata(alloc), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode alloc metadata: %w", err)
+	}
+
+	tmp := h.path + ".tmp"
+	if err := users.WriteFileFor(tmp, buf, h.user); err != nil {
+		return fmt.Errorf("failed to write alloc metadata file: %w", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		return fmt.Errorf("failed to write alloc metadata file: %w", err)
+	}
+	return nil
+}
+
+func (h *allocMetadataHook) metadata(alloc *structs.Allocation) *allocMetadata {
+	md := &allocMetadata{
+		AllocID:    alloc.ID,
+		AllocName:  alloc.Name,
+		AllocIndex: int(alloc.Index()),
+		Namespace:  alloc.Namespace,
+		JobID:      alloc.JobID,
+		TaskGroup:  alloc.TaskGroup,
+		Task:       h.task,
+		Meta:       map[string]string{},
+	}
+
+	if alloc.Job != nil {
+		md.JobName = alloc.Job.Name
+		md.JobVersion = alloc.Job.Version
+		if meta := alloc.Job.CombinedTaskMeta(alloc.TaskGroup, h.task); meta != nil {
+			md.Meta = meta
+		}
+	}
+
+	if h.node != nil {
+		md.Node = allocMetadataNode{
+			ID:         h.node.ID,
+			Name:       h.node.Name,
+			Datacenter: h.node.Datacenter,
+			NodeClass:  h.node.NodeClass,
+			NodePool:   h.node.NodePool,
+			Attributes: map[string]string{},
+		}
+		for k, v := range h.node.Attributes {
+			for _, pattern := range h.nodeAttrs {
+				if glob.Glob(pattern, k) {
+					md.Node.Attributes[k] = v
+					break
+				}
+			}
+		}
+	}
+
+	if ar := alloc.AllocatedResources; ar != nil {
+		if tr, ok := ar.Tasks[h.task]; ok {
+			md.Resources.CPU = tr.Cpu.CpuShares
+			md.Resources.ReservedCores = tr.Cpu.ReservedCores
+			md.Resources.MemoryMB = tr.Memory.MemoryMB
+			md.Resources.MemoryMaxMB = tr.Memory.MemoryMaxMB
+		}
+		md.Resources.DiskMB = ar.Shared.DiskMB
+	}
+
+	return md
+}
@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/shoenig/test/must"
+)
+
+func TestAllocMetadataHook(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	alloc.Job.Meta = map[string]string{"owner": "team-a"}
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Meta = map[string]string{"role": "web"}
+
+	node := mock.Node()
+	node.Attributes["kernel.name"] = "linux"
+	node.Attributes["unique.hostname"] = "secret-host"
+
+	h := newAllocMetadataHook(node, []string{"kernel.*", "cpu.arch"}, task.Name, testlog.HCLogger(t))
+
+	secretsDir := t.TempDir()
+	req := &interfaces.TaskPrestartRequest{
+		Alloc:   alloc,
+		Task:    task,
+		TaskDir: &allocdir.TaskDir{SecretsDir: secretsDir},
+	}
+	must.NoError(t, h.Prestart(context.Background(), req, &interfaces.TaskPrestartResponse{}))
+
+	read := func() *allocMetadata {
+		buf, err := os.ReadFile(filepath.Join(secretsDir, AllocMetadataFile))
+		must.NoError(t, err)
+		var md allocMetadata
+		must.NoError(t, json.Unmarshal(buf, &md))
+		return &md
+	}
+
+	md := read()
+	must.Eq(t, alloc.ID, md.AllocID)
+	must.Eq(t, task.Name, md.Task)
+	must.Eq(t, "team-a", md.Meta["owner"])
+	must.Eq(t, "web", md.Meta["role"])
+	must.Eq(t, node.ID, md.Node.ID)
+	must.Eq(t, "linux", md.Node.Attributes["kernel.name"])
+	must.MapNotContainsKey(t, md.Node.Attributes, "unique.hostname")
+	must.Eq(t, alloc.AllocatedResources.Tasks[task.Name].Memory.MemoryMB, md.Resources.MemoryMB)
+
+	// The file is rewritten on in-place updates
+	updated := alloc.Copy()
+	updated.Job.Version++
+	updated.Job.Meta["owner"] = "team-b"
+	must.NoError(t, h.Update(context.Background(), &interfaces.TaskUpdateRequest{Alloc: updated}, &interfaces.TaskUpdateResponse{}))
+
+	md = read()
+	must.Eq(t, updated.Job.Version, md.JobVersion)
+	must.Eq(t, "team-b", md.Meta["owner"])
+}
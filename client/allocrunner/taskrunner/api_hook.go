@@ -9,19 +9,17 @@ import (
 	"net"
 	"net/http"
 	"os"
-	"path/filepath"
 	"sync"
 
 	"github.com/hashicorp/go-hclog"
-	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/config"
-	"github.com/hashicorp/nomad/helper/users"
 )
 
 // apiHook exposes the Task API. The Task API allows task's to access the Nomad
 // HTTP API without having to discover and connect to an agent's address.
-// Instead a unix socket is provided in a standard location. To prevent access
+// Instead a unix socket (or named pipe on Windows) is provided in a standard
+// location. To prevent access
 // by untrusted workloads the Task API always requires authentication even when
 // ACLs are disabled.
 //
@@ -40,6 +38,10 @@ type apiHook struct {
 
 	// Listener is the unix domain socket of the task api for this taks.
 	ln net.Listener
+
+	// env are the environment variables advertising the listener to the task,
+	// if any.
+	env map[string]string
 }
 
 func newAPIHook(shutdownCtx context.Context, srv config.APIListenerRegistrar, logger hclog.Logger) *apiHook {
@@ -61,14 +63,14 @@ func (h *apiHook) Prestart(_ context.Context, req *interfaces.TaskPrestartReques
 
 	if h.ln != nil {
 		// Listener already set. Task is probably restarting.
+		resp.Env = h.env
 		return nil
 	}
 
-	udsPath := apiSocketPath(req.TaskDir)
-	udsln, err := users.SocketFileFor(h.logger, udsPath, req.Task.User)
+	udsln, env, err := listenTaskAPI(h.logger, req)
 	if err != nil {
 		// Soft-fail and let the task fail if it requires the task api.
-		h.logger.Warn("error creating task api socket", "path", udsPath, "error", err)
+		h.logger.Warn("error creating task api listener", "error", err)
 		return nil
 	}
 
@@ -87,6 +89,8 @@ func (h *apiHook) Prestart(_ context.Context, req *interfaces.TaskPrestartReques
 	}()
 
 	h.ln = udsln
+	h.env = env
+	resp.Env = env
 	return nil
 }
 
@@ -101,6 +105,7 @@ func (h *apiHook) Stop(ctx context.Context, req *interfaces.TaskStopRequest, res
 			}
 		}
 		h.ln = nil
+		h.env = nil
 	}
 
 	// Best-effort at cleaining things up. Alloc dir cleanup will remove it if
@@ -109,14 +114,3 @@ func (h *apiHook) Stop(ctx context.Context, req *interfaces.TaskStopRequest, res
 
 	return nil
 }
-
-// apiSocketPath returns the path to the Task API socket.
-//
-// The path needs to be as short as possible because of the low limits on the
-// sun_path char array imposed by the syscall used to create unix sockets.
-//
-// See https://github.com/hashicorp/nomad/pull/13971 for an example of the
-// sadness this causes.
-func apiSocketPath(taskDir *allocdir.TaskDir) string {
-	return filepath.Join(taskDir.SecretsDir, "api.sock")
-}
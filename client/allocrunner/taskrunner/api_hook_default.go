@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !windows
+
+package taskrunner
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/users"
+)
+
+// listenTaskAPI creates the unix domain socket for the Task API in the task's
+// secrets dir. The socket is at a well known path so no environment variables
+// are returned.
+func listenTaskAPI(logger hclog.Logger, req *interfaces.TaskPrestartRequest) (net.Listener, map[string]string, error) {
+	udsPath := apiSocketPath(req.TaskDir)
+	udsln, err := users.SocketFileFor(logger, udsPath, req.Task.User)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating task api socket %q: %w", udsPath, err)
+	}
+	return udsln, nil, nil
+}
+
+// apiSocketPath returns the path to the Task API socket.
+//
+// The path needs to be as short as possible because of the low limits on the
+// sun_path char array imposed by the syscall used to create unix sockets.
+//
+// See https://github.com/hashicorp/nomad/pull/13971 for an example of the
+// sadness this causes.
+func apiSocketPath(taskDir *allocdir.TaskDir) string {
+	return filepath.Join(taskDir.SecretsDir, "api.sock")
+}
@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build windows
+
+package taskrunner
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+
+	winio "github.com/Microsoft/go-winio"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+)
+
+// TaskAPIPipeEnv is the environment variable containing the path of the named
+// pipe serving the Task API on Windows.
+const TaskAPIPipeEnv = "NOMAD_TASK_API_PIPE"
+
+// listenTaskAPI creates a named pipe for the Task API. Named pipes are not
+// created in the filesystem, so the pipe path is advertised to the task via
+// the NOMAD_TASK_API_PIPE environment variable.
+//
+// If the task has a user the pipe's DACL only grants access to that user,
+// SYSTEM, and Administrators. Otherwise any user may connect, matching the
+// permissions of the unix socket on other platforms.
+func listenTaskAPI(logger hclog.Logger, req *interfaces.TaskPrestartRequest) (net.Listener, map[string]string, error) {
+	pipePath := apiPipePath(req.Alloc.ID, req.Task.Name)
+
+	sddl := "D:P(A;;GA;;;SY)(A;;GA;;;BA)"
+	if req.Task.User != "" {
+		sid, err := winio.LookupSidByName(req.Task.User)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error looking up task user %q: %w", req.Task.User, err)
+		}
+		sddl += fmt.Sprintf("(A;;GRGW;;;%s)", sid)
+	} else {
+		sddl += "(A;;GRGW;;;WD)"
+	}
+
+	ln, err := winio.ListenPipe(pipePath, &winio.PipeConfig{
+		SecurityDescriptor: sddl,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating task api pipe %q: %w", pipePath, err)
+	}
+
+	logger.Trace("created task api pipe", "path", pipePath)
+	return ln, map[string]string{TaskAPIPipeEnv: pipePath}, nil
+}
+
+// apiPipePath returns the path to the Task API named pipe. Backslashes are not
+// permitted in pipe names beyond the \\.\pipe\ prefix.
+func apiPipePath(allocID, task string) string {
+	name := strings.ReplaceAll(task, `\`, "_")
+	return `\\.\pipe\nomad-task-api-` + allocID + "-" + name
+}
+
+// apiSocketPath returns the path a Task API unix socket would be created at.
+// Named pipes are used on Windows, so this path is only removed on stop to
+// clean up sockets created by older clients.
+func apiSocketPath(taskDir *allocdir.TaskDir) string {
+	return filepath.Join(taskDir.SecretsDir, "api.sock")
+}
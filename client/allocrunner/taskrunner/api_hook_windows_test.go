@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build windows
+
+package taskrunner
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	winio "github.com/Microsoft/go-winio"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocdir"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// TestAPIHook_NamedPipe asserts that the Task API Hook creates a named pipe and
+// advertises it to the task.
+func TestAPIHook_NamedPipe(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := testAPIListenerRegistrar{
+		cb: func(ln net.Listener) error {
+			conn, err := ln.Accept()
+			if err != nil {
+				return err
+			}
+			if _, err = conn.Write([]byte("ok")); err != nil {
+				return err
+			}
+			conn.Close()
+			return nil
+		},
+	}
+
+	ctx := context.Background()
+	h := newAPIHook(ctx, srv, testlog.HCLogger(t))
+
+	alloc := mock.Alloc()
+	req := &interfaces.TaskPrestartRequest{
+		Alloc: alloc,
+		Task:  &structs.Task{Name: "web"},
+		TaskDir: &allocdir.TaskDir{
+			SecretsDir: t.TempDir(),
+		},
+	}
+	resp := &interfaces.TaskPrestartResponse{}
+	must.NoError(t, h.Prestart(ctx, req, resp))
+	must.NotNil(t, h.ln)
+
+	pipePath := apiPipePath(alloc.ID, "web")
+	must.Eq(t, map[string]string{TaskAPIPipeEnv: pipePath}, resp.Env)
+
+	conn, err := winio.DialPipe(pipePath, nil)
+	must.NoError(t, err)
+	buf, err := io.ReadAll(conn)
+	must.NoError(t, err)
+	must.Eq(t, "ok", string(buf))
+
+	// Restarts must keep advertising the pipe
+	resp = &interfaces.TaskPrestartResponse{}
+	must.NoError(t, h.Prestart(ctx, req, resp))
+	must.Eq(t, pipePath, resp.Env[TaskAPIPipeEnv])
+
+	must.NoError(t, h.Stop(ctx, &interfaces.TaskStopRequest{TaskDir: req.TaskDir}, &interfaces.TaskStopResponse{}))
+	must.Nil(t, h.ln)
+}
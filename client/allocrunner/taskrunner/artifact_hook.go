@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	ci "github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/client/widmgr"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -20,12 +21,17 @@ type artifactHook struct {
 	eventEmitter ti.EventEmitter
 	logger       log.Logger
 	getter       ci.ArtifactGetter
+
+	// widmgr is used to access the signed workload identities that
+	// artifacts are authenticated with.
+	widmgr widmgr.IdentityManager
 }
 
-func newArtifactHook(e ti.EventEmitter, getter ci.ArtifactGetter, logger log.Logger) *artifactHook {
+func newArtifactHook(e ti.EventEmitter, getter ci.ArtifactGetter, widmgr widmgr.IdentityManager, logger log.Logger) *artifactHook {
 	h := &artifactHook{
 		eventEmitter: e,
 		getter:       getter,
+		widmgr:       widmgr,
 	}
 	h.logger = logger.Named(h.Name())
 	return h
@@ -45,7 +51,11 @@ func (h *artifactHook) doWork(req *interfaces.TaskPrestartRequest, resp *interfa
 
 		h.logger.Debug("downloading artifact", "artifact", artifact.GetterSource, "aid", aid)
 
-		if err := h.getter.Get(req.TaskEnv, artifact); err != nil {
+		token, err := h.identityToken(req.Task, artifact)
+		if err == nil {
+			err = h.getter.Get(req.TaskEnv, artifact, token)
+		}
+		if err != nil {
 			wrapped := structs.NewRecoverableError(
 				fmt.Errorf("failed to download artifact %q: %v", artifact.GetterSource, err),
 				true,
@@ -65,6 +75,27 @@ func (h *artifactHook) doWork(req *interfaces.TaskPrestartRequest, resp *interfa
 	}
 }
 
+// identityToken returns the signed workload identity the artifact is
+// authenticated with, or "" if the artifact does not name an identity.
+func (h *artifactHook) identityToken(task *structs.Task, artifact *structs.TaskArtifact) (string, error) {
+	if artifact.GetterIdentity == "" {
+		return "", nil
+	}
+
+	signed, err := h.widmgr.Get(structs.WIHandle{
+		IdentityName:       artifact.GetterIdentity,
+		WorkloadIdentifier: task.Name,
+		WorkloadType:       structs.WorkloadTypeTask,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve signed workload identity %q: %w", artifact.GetterIdentity, err)
+	}
+	if signed == nil {
+		return "", fmt.Errorf("no signed workload identity %q available", artifact.GetterIdentity)
+	}
+	return signed.JWT, nil
+}
+
 func (*artifactHook) Name() string {
 	// Copied in client/state when upgrading from <0.9 schemas, so if you
 	// change it here you also must change it there.
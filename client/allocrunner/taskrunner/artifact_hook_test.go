@@ -18,10 +18,13 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/getter"
 	trtesting "github.com/hashicorp/nomad/client/allocrunner/taskrunner/testing"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/client/testutil"
+	"github.com/hashicorp/nomad/client/widmgr"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/maps"
 )
@@ -36,7 +39,7 @@ func TestTaskRunner_ArtifactHook_Recoverable(t *testing.T) {
 
 	me := &trtesting.MockEmitter{}
 	sbox := getter.TestSandbox(t)
-	artifactHook := newArtifactHook(me, sbox, testlog.HCLogger(t))
+	artifactHook := newArtifactHook(me, sbox, nil, testlog.HCLogger(t))
 
 	req := &interfaces.TaskPrestartRequest{
 		TaskEnv: taskenv.NewEmptyTaskEnv(),
@@ -71,7 +74,7 @@ func TestTaskRunner_ArtifactHook_PartialDone(t *testing.T) {
 
 	me := &trtesting.MockEmitter{}
 	sbox := getter.TestSandbox(t)
-	artifactHook := newArtifactHook(me, sbox, testlog.HCLogger(t))
+	artifactHook := newArtifactHook(me, sbox, nil, testlog.HCLogger(t))
 
 	// Create a source directory with 1 of the 2 artifacts
 	srcdir := t.TempDir()
@@ -161,7 +164,7 @@ func TestTaskRunner_ArtifactHook_ConcurrentDownloadSuccess(t *testing.T) {
 
 	me := &trtesting.MockEmitter{}
 	sbox := getter.TestSandbox(t)
-	artifactHook := newArtifactHook(me, sbox, testlog.HCLogger(t))
+	artifactHook := newArtifactHook(me, sbox, nil, testlog.HCLogger(t))
 
 	// Create a source directory all 7 artifacts
 	srcdir := t.TempDir()
@@ -249,7 +252,7 @@ func TestTaskRunner_ArtifactHook_ConcurrentDownloadFailure(t *testing.T) {
 
 	me := &trtesting.MockEmitter{}
 	sbox := getter.TestSandbox(t)
-	artifactHook := newArtifactHook(me, sbox, testlog.HCLogger(t))
+	artifactHook := newArtifactHook(me, sbox, nil, testlog.HCLogger(t))
 
 	// Create a source directory with 3 of the 4 artifacts
 	srcdir := t.TempDir()
@@ -356,3 +359,60 @@ func TestTaskRunner_ArtifactHook_ConcurrentDownloadFailure(t *testing.T) {
 	require.True(t, resp.Done)
 	require.Len(t, resp.State, 4)
 }
+
+// tokenGetter is an ArtifactGetter recording the identity tokens artifacts are
+// downloaded with.
+type tokenGetter struct {
+	tokens []string
+}
+
+func (g *tokenGetter) Get(_ cinterfaces.EnvReplacer, _ *structs.TaskArtifact, token string) error {
+	g.tokens = append(g.tokens, token)
+	return nil
+}
+
+// TestTaskRunner_ArtifactHook_Identity asserts that artifacts naming a
+// workload identity are downloaded with its signed token.
+func TestTaskRunner_ArtifactHook_Identity(t *testing.T) {
+	ci.Parallel(t)
+
+	task := &structs.Task{
+		Name: "web",
+		Artifacts: []*structs.TaskArtifact{
+			{
+				GetterSource:   "git::https://example.com/repo.git",
+				GetterIdentity: "git",
+			},
+		},
+	}
+	mgr := widmgr.NewMockWIDMgr([]*structs.SignedWorkloadIdentity{{
+		WorkloadIdentityRequest: structs.WorkloadIdentityRequest{
+			WIHandle: structs.WIHandle{
+				IdentityName:       "git",
+				WorkloadIdentifier: "web",
+				WorkloadType:       structs.WorkloadTypeTask,
+			},
+		},
+		JWT: "signed-jwt",
+	}})
+
+	g := &tokenGetter{}
+	artifactHook := newArtifactHook(&trtesting.MockEmitter{}, g, mgr, testlog.HCLogger(t))
+
+	req := &interfaces.TaskPrestartRequest{
+		TaskEnv: taskenv.NewEmptyTaskEnv(),
+		Task:    task,
+	}
+	resp := interfaces.TaskPrestartResponse{}
+	must.NoError(t, artifactHook.Prestart(context.Background(), req, &resp))
+	must.True(t, resp.Done)
+	must.Eq(t, []string{"signed-jwt"}, g.tokens)
+
+	// an identity that was not signed fails the download
+	task.Artifacts[0].GetterIdentity = "other"
+	resp = interfaces.TaskPrestartResponse{}
+	err := artifactHook.Prestart(context.Background(), req, &resp)
+	must.ErrorContains(t, err, `failed to retrieve signed workload identity "other"`)
+	must.True(t, structs.IsRecoverable(err))
+	must.Len(t, 1, g.tokens)
+}
@@ -43,6 +43,16 @@ func (h *DriverHandle) ID() string {
 	return h.taskID
 }
 
+// UpdateResources updates the cpu and memory limits of the running task. It
+// returns an error if the driver does not support resizing running tasks.
+func (h *DriverHandle) UpdateResources(resources *drivers.Resources) error {
+	updater, ok := h.driver.(drivers.ResourceUpdaterDriver)
+	if !ok {
+		return fmt.Errorf("driver does not support updating the resources of running tasks")
+	}
+	return updater.UpdateTaskResources(h.taskID, resources)
+}
+
 func (h *DriverHandle) WaitCh(ctx context.Context) (<-chan *drivers.ExitResult, error) {
 	return h.driver.WaitTask(ctx, h.taskID)
 }
@@ -114,6 +124,18 @@ func (h *DriverHandle) ExecStreaming(ctx context.Context,
 	return stream.Send(drivers.NewExecStreamingResponseExit(result.ExitCode))
 }
 
+// ContainerHealth returns the health status and healthcheck output of the
+// task's container, as reported by the driver. The status is empty if the
+// driver doesn't report the health of the task.
+func (h *DriverHandle) ContainerHealth() (string, string, error) {
+	status, err := h.driver.InspectTask(h.taskID)
+	if err != nil {
+		return "", "", err
+	}
+	return status.DriverAttributes[drivers.TaskStatusAttrHealth],
+		status.DriverAttributes[drivers.TaskStatusAttrHealthOutput], nil
+}
+
 func (h *DriverHandle) Network() *drivers.DriverNetwork {
 	return h.net
 }
@@ -0,0 +1,242 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-getter"
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// cacheTmpPrefix is the prefix of cache entries being written.
+	cacheTmpPrefix = ".tmp-"
+)
+
+// cache is an on-disk cache of downloaded artifacts shared by the allocations
+// of the client. Only artifacts pinned by a sha256 or sha512 checksum are
+// cached, keyed by their checksum, so a cached artifact is always the content
+// that would have been downloaded.
+type cache struct {
+	logger  hclog.Logger
+	dir     string
+	maxSize int64
+
+	lock sync.Mutex
+}
+
+// newCache returns a cache of up to maxSize bytes in dir, or nil if caching is
+// disabled.
+func newCache(dir string, maxSize int64, logger hclog.Logger) *cache {
+	if dir == "" || maxSize <= 0 {
+		return nil
+	}
+	return &cache{
+		logger:  logger.Named("cache"),
+		dir:     dir,
+		maxSize: maxSize,
+	}
+}
+
+// cacheKey returns the cache key of the artifact downloaded from source, and
+// whether it can be cached. Besides the checksum, the key includes the mode,
+// the archive option and the file name of the source as they determine what
+// the download produces.
+func cacheKey(source string, mode getter.ClientMode) (string, bool) {
+	// strip any forced getter, e.g. s3::https://
+	if i := strings.Index(source, "::"); i >= 0 {
+		source = source[i+2:]
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return "", false
+	}
+	q := u.Query()
+
+	checksum := q.Get("checksum")
+	algorithm, value, ok := strings.Cut(checksum, ":")
+	if !ok || value == "" || (algorithm != "sha256" && algorithm != "sha512") {
+		return "", false
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00%s\x00%s", checksum, mode, q.Get("archive"), path.Base(u.Path))
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// lookup returns the path of the cached artifact with the given key.
+func (c *cache) lookup(key string) (string, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry := filepath.Join(c.dir, key)
+	if _, err := os.Stat(entry); err != nil {
+		return "", false
+	}
+
+	// mark the entry as recently used
+	now := time.Now()
+	_ = os.Chtimes(entry, now, now)
+	return filepath.Join(entry, "artifact"), true
+}
+
+// put copies the downloaded artifact at src into the cache under the given
+// key, evicting the least recently used artifacts to stay within the maximum
+// size of the cache.
+func (c *cache) put(key, src string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry := filepath.Join(c.dir, key)
+	if _, err := os.Stat(entry); err == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(c.dir, cacheTmpPrefix+key)
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	if err := copyTree(src, filepath.Join(tmp, "artifact")); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, entry); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+
+	c.purge()
+	return nil
+}
+
+// purge removes the least recently used entries until the cache is within its
+// maximum size. Must be called with the lock held.
+func (c *cache) purge() {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		c.logger.Warn("failed to read artifact cache", "error", err)
+		return
+	}
+
+	type cacheEntry struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	var entries []cacheEntry
+	var total int64
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() || strings.HasPrefix(dirEntry.Name(), cacheTmpPrefix) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		entry := cacheEntry{
+			path:    filepath.Join(c.dir, dirEntry.Name()),
+			modTime: info.ModTime(),
+		}
+		_ = filepath.WalkDir(entry.path, func(_ string, d fs.DirEntry, err error) error {
+			if err == nil && d.Type().IsRegular() {
+				if info, err := d.Info(); err == nil {
+					entry.size += info.Size()
+				}
+			}
+			return nil
+		})
+		entries = append(entries, entry)
+		total += entry.size
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+
+	for _, entry := range entries {
+		if total <= c.maxSize {
+			return
+		}
+		if err := os.RemoveAll(entry.path); err != nil {
+			c.logger.Warn("failed to evict cached artifact", "path", entry.path, "error", err)
+			continue
+		}
+		total -= entry.size
+	}
+}
+
+// copyTree copies the file or directory at src to dst. Directories are merged
+// into an existing dst, as an artifact downloaded into a directory is.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		mode := info.Mode().Perm()
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, mode|0o700)
+		case d.Type().IsRegular():
+			return copyFile(p, target, mode)
+		default:
+			// artifacts are downloaded without symlinks or special files
+			return nil
+		}
+	})
+}
+
+func copyFile(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Chmod(dst, mode)
+}
@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-getter"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/shoenig/test/must"
+)
+
+func TestCacheKey(t *testing.T) {
+	const sum = "sha256:5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03"
+
+	key, ok := cacheKey("https://example.com/app.tar.gz?checksum="+sum, getter.ClientModeAny)
+	must.True(t, ok)
+
+	// the same content downloaded from elsewhere has the same key
+	other, ok := cacheKey("s3::https://s3.amazonaws.com/bucket/app.tar.gz?checksum="+sum, getter.ClientModeAny)
+	must.True(t, ok)
+	must.Eq(t, key, other)
+
+	// but not if it produces a different result
+	other, ok = cacheKey("https://example.com/app.tar.gz?archive=false&checksum="+sum, getter.ClientModeAny)
+	must.True(t, ok)
+	must.NotEq(t, key, other)
+
+	other, ok = cacheKey("https://example.com/app.tar.gz?checksum="+sum, getter.ClientModeFile)
+	must.True(t, ok)
+	must.NotEq(t, key, other)
+
+	// artifacts without a strong checksum are not cached
+	_, ok = cacheKey("https://example.com/app.tar.gz", getter.ClientModeAny)
+	must.False(t, ok)
+	_, ok = cacheKey("https://example.com/app.tar.gz?checksum=md5:b1946ac92492d2347c6235b4d2611184", getter.ClientModeAny)
+	must.False(t, ok)
+	_, ok = cacheKey("https://example.com/app.tar.gz?checksum=file:https://example.com/SHA256SUMS", getter.ClientModeAny)
+	must.False(t, ok)
+}
+
+func TestCache_put_lookup(t *testing.T) {
+	c := newCache(t.TempDir(), 1024, testlog.HCLogger(t))
+
+	_, ok := c.lookup("key")
+	must.False(t, ok)
+
+	src := t.TempDir()
+	must.NoError(t, os.MkdirAll(filepath.Join(src, "bin"), 0o755))
+	must.NoError(t, os.WriteFile(filepath.Join(src, "bin", "app"), []byte("app"), 0o755))
+	must.NoError(t, c.put("key", src))
+
+	cached, ok := c.lookup("key")
+	must.True(t, ok)
+
+	dst := t.TempDir()
+	must.NoError(t, copyTree(cached, dst))
+	b, err := os.ReadFile(filepath.Join(dst, "bin", "app"))
+	must.NoError(t, err)
+	must.Eq(t, "app", string(b))
+
+	info, err := os.Stat(filepath.Join(dst, "bin", "app"))
+	must.NoError(t, err)
+	must.Eq(t, os.FileMode(0o755), info.Mode().Perm())
+}
+
+func TestCache_purge(t *testing.T) {
+	dir := t.TempDir()
+	c := newCache(dir, 1000, testlog.HCLogger(t))
+
+	put := func(key string, size int) {
+		src := filepath.Join(t.TempDir(), "file")
+		must.NoError(t, os.WriteFile(src, []byte(strings.Repeat("x", size)), 0o644))
+		must.NoError(t, c.put(key, src))
+	}
+
+	put("old", 400)
+	past := time.Now().Add(-time.Hour)
+	must.NoError(t, os.Chtimes(filepath.Join(dir, "old"), past, past))
+	put("used", 400)
+	_, ok := c.lookup("old")
+	must.True(t, ok)
+
+	// the least recently used entry is evicted
+	must.NoError(t, os.Chtimes(filepath.Join(dir, "used"), past.Add(-time.Hour), past.Add(-time.Hour)))
+	put("new", 400)
+
+	_, ok = c.lookup("used")
+	must.False(t, ok)
+	_, ok = c.lookup("old")
+	must.True(t, ok)
+	_, ok = c.lookup("new")
+	must.True(t, ok)
+}
+
+func TestCache_disabled(t *testing.T) {
+	must.Nil(t, newCache(t.TempDir(), 0, testlog.HCLogger(t)))
+	must.Nil(t, newCache("", 1024, testlog.HCLogger(t)))
+}
@@ -0,0 +1,150 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// credentialHelperTimeout is the maximum time the credential helper may
+	// take to exchange a workload identity for credentials.
+	credentialHelperTimeout = 30 * time.Second
+)
+
+// credentialRequest is written to the standard input of the credential helper.
+type credentialRequest struct {
+	// Source is the URL of the artifact, with its options applied.
+	Source string `json:"source"`
+
+	// Identity is the name of the workload identity and Token its signed
+	// JWT.
+	Identity string `json:"identity"`
+	Token    string `json:"token"`
+}
+
+// artifactCredentials are read from the standard output of the credential helper.
+// Every field is optional.
+type artifactCredentials struct {
+	// Username and Password are added to the URL of sources downloaded over
+	// HTTP, including git repositories.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// Headers are added to the requests of sources downloaded over HTTP.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// SSHKey is the base64 encoded private key used to clone git repositories
+	// over SSH.
+	SSHKey string `json:"ssh_key,omitempty"`
+}
+
+func (c *artifactCredentials) Equal(o *artifactCredentials) bool {
+	if c == nil || o == nil {
+		return c == o
+	}
+	switch {
+	case c.Username != o.Username:
+		return false
+	case c.Password != o.Password:
+		return false
+	case c.SSHKey != o.SSHKey:
+		return false
+	case !maps.Equal(c.Headers, o.Headers):
+		return false
+	}
+	return true
+}
+
+// exchangeIdentity runs the credential helper to exchange the signed workload
+// identity for the credentials used to download the artifact from source.
+func (s *Sandbox) exchangeIdentity(source, identity, token string) (*artifactCredentials, error) {
+	if s.ac.CredentialHelper == "" {
+		return nil, &Error{
+			URL:         source,
+			Err:         errors.New("artifact identity requires the client to configure an artifact credential_helper"),
+			Recoverable: false,
+		}
+	}
+
+	input, err := json.Marshal(&credentialRequest{
+		Source:   source,
+		Identity: identity,
+		Token:    token,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), credentialHelperTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.ac.CredentialHelper)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, &Error{
+			URL:         source,
+			Err:         fmt.Errorf("credential helper failed: %v: %s", err, strings.TrimSpace(stderr.String())),
+			Recoverable: true,
+		}
+	}
+
+	var creds artifactCredentials
+	if err := json.Unmarshal(stdout.Bytes(), &creds); err != nil {
+		return nil, &Error{
+			URL:         source,
+			Err:         fmt.Errorf("failed to decode credential helper output: %v", err),
+			Recoverable: false,
+		}
+	}
+	return &creds, nil
+}
+
+// authenticatedSource returns the source of the artifact with the username,
+// password and SSH key of the credentials. It is only called by the download
+// sub-process, so the credentials never appear in the errors and logs of the
+// client.
+func (p *parameters) authenticatedSource() string {
+	c := p.Credentials
+	if c == nil {
+		return p.Source
+	}
+
+	source := p.Source
+	if c.Username != "" || c.Password != "" {
+		// keep the forced getter prefix, e.g. git::https://
+		forced, rest, ok := strings.Cut(source, "::")
+		if !ok {
+			forced, rest = "", source
+		}
+		if u, err := url.Parse(rest); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+			u.User = url.UserPassword(c.Username, c.Password)
+			source = u.String()
+			if forced != "" {
+				source = forced + "::" + source
+			}
+		}
+	}
+
+	if c.SSHKey != "" {
+		sep := "?"
+		if strings.Contains(source, "?") {
+			sep = "&"
+		}
+		source += sep + "sshkey=" + url.QueryEscape(c.SSHKey)
+	}
+	return source
+}
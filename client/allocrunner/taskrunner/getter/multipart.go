@@ -0,0 +1,198 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// multipartSize is the size of the byte ranges in which objects are
+	// downloaded from object stores.
+	multipartSize = 16 * 1024 * 1024
+
+	// multipartConcurrency is the number of byte ranges of an object
+	// downloaded at once.
+	multipartConcurrency = 4
+
+	// multipartStateSuffix is appended to the destination of a multipart
+	// download to name the file tracking the completed parts, so that an
+	// interrupted download is resumed by the next attempt.
+	multipartStateSuffix = ".nomad-parts"
+)
+
+// rangeObject is an object in an object store that can be read in byte ranges.
+type rangeObject interface {
+	// Stat returns the size and version of the object. The version ensures
+	// a resumed download continues the same content.
+	Stat(ctx context.Context) (int64, string, error)
+
+	// ReadRange returns a reader of length bytes of the object starting at
+	// offset.
+	ReadRange(ctx context.Context, offset, length int64) (io.ReadCloser, error)
+}
+
+// multipartState is persisted next to the destination of a multipart
+// download while it is in progress.
+type multipartState struct {
+	Version  string `json:"version"`
+	Size     int64  `json:"size"`
+	PartSize int64  `json:"part_size"`
+	Done     []bool `json:"done"`
+}
+
+func newMultipartState(size, partSize int64, version string) *multipartState {
+	parts := (size + partSize - 1) / partSize
+	return &multipartState{
+		Version:  version,
+		Size:     size,
+		PartSize: partSize,
+		Done:     make([]bool, parts),
+	}
+}
+
+// loadMultipartState returns the state of a previous attempt to download the
+// same version of the object to dst, or nil if there is none.
+func loadMultipartState(dst string, size, partSize int64, version string) *multipartState {
+	b, err := os.ReadFile(dst + multipartStateSuffix)
+	if err != nil {
+		return nil
+	}
+	var state multipartState
+	if err := json.Unmarshal(b, &state); err != nil {
+		return nil
+	}
+	expected := newMultipartState(size, partSize, version)
+	if state.Version != expected.Version || state.Size != expected.Size ||
+		state.PartSize != expected.PartSize || len(state.Done) != len(expected.Done) {
+		return nil
+	}
+	if _, err := os.Stat(dst); err != nil {
+		return nil
+	}
+	return &state
+}
+
+func (s *multipartState) save(dst string) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp := dst + multipartStateSuffix + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dst+multipartStateSuffix)
+}
+
+// downloadMultipart downloads obj to dst in concurrent byte ranges of partSize
+// at the rate allowed by limiter. If a previous attempt to download the same
+// version of obj to dst was interrupted, only the ranges it did not complete
+// are downloaded.
+func downloadMultipart(ctx context.Context, obj rangeObject, dst string, partSize int64, limiter *rate.Limiter) error {
+	size, version, err := obj.Stat(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	flags := os.O_RDWR | os.O_CREATE
+	state := loadMultipartState(dst, size, partSize, version)
+	if state == nil {
+		state = newMultipartState(size, partSize, version)
+		flags |= os.O_TRUNC
+	}
+
+	f, err := os.OpenFile(dst, flags, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := f.Truncate(size); err != nil {
+		return err
+	}
+
+	var stateLock sync.Mutex
+	parts := make(chan int)
+	g, gctx := errgroup.WithContext(ctx)
+
+	for i := 0; i < multipartConcurrency; i++ {
+		g.Go(func() error {
+			for part := range parts {
+				offset := int64(part) * state.PartSize
+				length := min(state.PartSize, size-offset)
+				if err := downloadPart(gctx, obj, f, offset, length, limiter); err != nil {
+					return fmt.Errorf("failed to download part %d: %w", part, err)
+				}
+
+				stateLock.Lock()
+				state.Done[part] = true
+				err := state.save(dst)
+				stateLock.Unlock()
+				if err != nil {
+					return fmt.Errorf("failed to save download state: %w", err)
+				}
+			}
+			return nil
+		})
+	}
+
+	g.Go(func() error {
+		defer close(parts)
+		for part, done := range state.Done {
+			if done {
+				continue
+			}
+			select {
+			case parts <- part:
+			case <-gctx.Done():
+				return nil
+			}
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := os.Remove(dst + multipartStateSuffix); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func downloadPart(ctx context.Context, obj rangeObject, f *os.File, offset, length int64, limiter *rate.Limiter) error {
+	rc, err := obj.ReadRange(ctx, offset, length)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	n, err := io.Copy(io.NewOffsetWriter(f, offset), limitReader(ctx, rc, limiter))
+	if err != nil {
+		return err
+	}
+	if n != length {
+		return fmt.Errorf("expected %d bytes but read %d", length, n)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+// memObject is an in-memory rangeObject that counts the ranges read and can
+// be made to fail reading a given offset.
+type memObject struct {
+	data    []byte
+	version string
+
+	lock   sync.Mutex
+	reads  map[int64]int
+	failAt int64
+}
+
+func newMemObject(data []byte, version string) *memObject {
+	return &memObject{
+		data:    data,
+		version: version,
+		reads:   make(map[int64]int),
+		failAt:  -1,
+	}
+}
+
+func (o *memObject) Stat(context.Context) (int64, string, error) {
+	return int64(len(o.data)), o.version, nil
+}
+
+func (o *memObject) ReadRange(_ context.Context, offset, length int64) (io.ReadCloser, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if offset == o.failAt {
+		return nil, errors.New("connection reset")
+	}
+	o.reads[offset]++
+	return io.NopCloser(bytes.NewReader(o.data[offset : offset+length])), nil
+}
+
+func TestDownloadMultipart(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	obj := newMemObject(data, "v1")
+	dst := filepath.Join(t.TempDir(), "sub", "file")
+
+	err := downloadMultipart(context.Background(), obj, dst, 64, nil)
+	must.NoError(t, err)
+
+	b, err := os.ReadFile(dst)
+	must.NoError(t, err)
+	must.Eq(t, data, b)
+	must.MapLen(t, 16, obj.reads)
+
+	_, err = os.Stat(dst + multipartStateSuffix)
+	must.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestDownloadMultipart_resume(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	dst := filepath.Join(t.TempDir(), "file")
+
+	// interrupt the download on the last part
+	obj := newMemObject(data, "v1")
+	obj.failAt = 960
+	err := downloadMultipart(context.Background(), obj, dst, 64, nil)
+	must.ErrorContains(t, err, "connection reset")
+
+	_, err = os.Stat(dst + multipartStateSuffix)
+	must.NoError(t, err)
+
+	// only the parts that were not completed are downloaded on resume
+	resumed := newMemObject(data, "v1")
+	err = downloadMultipart(context.Background(), resumed, dst, 64, nil)
+	must.NoError(t, err)
+	must.MapContainsKey(t, resumed.reads, 960)
+	must.Less(t, 16, len(resumed.reads))
+
+	b, err := os.ReadFile(dst)
+	must.NoError(t, err)
+	must.Eq(t, data, b)
+}
+
+func TestDownloadMultipart_changed(t *testing.T) {
+	dst := filepath.Join(t.TempDir(), "file")
+
+	obj := newMemObject(bytes.Repeat([]byte("a"), 200), "v1")
+	obj.failAt = 128
+	err := downloadMultipart(context.Background(), obj, dst, 64, nil)
+	must.Error(t, err)
+
+	// a new version of the object is downloaded from scratch
+	changed := newMemObject(bytes.Repeat([]byte("b"), 100), "v2")
+	err = downloadMultipart(context.Background(), changed, dst, 64, nil)
+	must.NoError(t, err)
+	must.MapLen(t, 2, changed.reads)
+
+	b, err := os.ReadFile(dst)
+	must.NoError(t, err)
+	must.Eq(t, changed.data, b)
+}
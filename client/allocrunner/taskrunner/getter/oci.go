@@ -0,0 +1,425 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-getter"
+)
+
+const (
+	ociManifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+
+	// ociTitleAnnotation is the name of the file stored in a layer of an
+	// artifact.
+	ociTitleAnnotation = "org.opencontainers.image.title"
+
+	// ociUnpackAnnotation marks a layer storing a directory as a tar+gzip
+	// archive, as pushed by ORAS.
+	ociUnpackAnnotation = "io.deis.oras.content.unpack"
+)
+
+// ociDescriptor describes a blob referenced by a manifest.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociManifest is the part of an OCI image manifest needed to pull the files of
+// an artifact.
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+// files returns the layers of the manifest that store files, as pushed by
+// ORAS.
+func (m *ociManifest) files() []ociDescriptor {
+	var files []ociDescriptor
+	for _, layer := range m.Layers {
+		if layer.Annotations[ociTitleAnnotation] != "" {
+			files = append(files, layer)
+		}
+	}
+	return files
+}
+
+// ociReference is a reference to an artifact in an OCI registry.
+type ociReference struct {
+	// base is the URL of the registry
+	base       *url.URL
+	repository string
+
+	// reference is either a tag or a digest
+	reference string
+}
+
+// parseOCIReference parses an artifact reference from sources such as
+// oci://registry/repository:tag or oci::https://registry/repository@digest.
+// Registries are accessed over plain HTTP for http:// sources.
+func parseOCIReference(u *url.URL) (*ociReference, error) {
+	scheme := "https"
+	if u.Scheme == "http" {
+		scheme = "http"
+	}
+
+	repository := strings.TrimPrefix(u.Path, "/")
+	reference := "latest"
+	if i := strings.Index(repository, "@"); i >= 0 {
+		repository, reference = repository[:i], repository[i+1:]
+	} else if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository, reference = repository[:i], repository[i+1:]
+	}
+
+	if u.Host == "" || repository == "" || reference == "" {
+		return nil, fmt.Errorf("URL is not a valid OCI artifact reference")
+	}
+
+	return &ociReference{
+		base: &url.URL{
+			Scheme: scheme,
+			Host:   u.Host,
+			User:   u.User,
+		},
+		repository: repository,
+		reference:  reference,
+	}, nil
+}
+
+func (r *ociReference) url(kind, name string) string {
+	u := *r.base
+	u.User = nil
+	u.Path = fmt.Sprintf("/v2/%s/%s/%s", r.repository, kind, name)
+	return u.String()
+}
+
+func (r *ociReference) String() string {
+	return r.base.Host + "/" + r.repository + ":" + r.reference
+}
+
+// ociGetter downloads the files of artifacts stored in OCI registries, such as
+// those pushed by ORAS. Each layer of the artifact with a title annotation is
+// downloaded as a file named by the annotation, or unpacked as a directory if
+// it was pushed as one.
+type ociGetter struct {
+	ctx     context.Context
+	timeout time.Duration
+	headers http.Header
+	client  *http.Client
+
+	filesLimit int
+	sizeLimit  int64
+
+	// token is the bearer token issued by the registry's token service
+	token string
+}
+
+func newOCIGetter(ctx context.Context, timeout time.Duration, headers http.Header,
+	client *http.Client, filesLimit int, sizeLimit int64) *ociGetter {
+	return &ociGetter{
+		ctx:        ctx,
+		timeout:    timeout,
+		headers:    headers,
+		client:     client,
+		filesLimit: filesLimit,
+		sizeLimit:  sizeLimit,
+	}
+}
+
+// SetClient is a no-op as the getter does not depend on the configuration of
+// the go-getter client.
+func (g *ociGetter) SetClient(*getter.Client) {}
+
+// ClientMode is always a directory, as an artifact may store multiple files.
+func (g *ociGetter) ClientMode(*url.URL) (getter.ClientMode, error) {
+	return getter.ClientModeDir, nil
+}
+
+func (g *ociGetter) Get(dst string, u *url.URL) error {
+	ctx, cancel := g.context()
+	defer cancel()
+
+	ref, err := parseOCIReference(u)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := g.manifest(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	files := manifest.files()
+	if len(files) == 0 {
+		return fmt.Errorf("OCI artifact %s has no files", ref)
+	}
+
+	for _, file := range files {
+		name := file.Annotations[ociTitleAnnotation]
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("OCI artifact %s file %q escapes the destination", ref, name)
+		}
+		path := filepath.Join(dst, name)
+
+		if file.Annotations[ociUnpackAnnotation] != "true" {
+			if err := g.blob(ctx, ref, file, path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		archive := path + ".tar.gz"
+		if err := g.blob(ctx, ref, file, archive); err != nil {
+			return err
+		}
+		decompressor := &getter.TarGzipDecompressor{
+			FilesLimit:    g.filesLimit,
+			FileSizeLimit: g.sizeLimit,
+		}
+		err := decompressor.Decompress(path, archive, true, umask)
+		os.Remove(archive)
+		if err != nil {
+			return fmt.Errorf("failed to unpack OCI artifact %s directory %q: %w", ref, name, err)
+		}
+	}
+	return nil
+}
+
+func (g *ociGetter) GetFile(dst string, u *url.URL) error {
+	ctx, cancel := g.context()
+	defer cancel()
+
+	ref, err := parseOCIReference(u)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := g.manifest(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	files := manifest.files()
+	if len(files) != 1 {
+		return fmt.Errorf("OCI artifact %s must have exactly one file to be downloaded as a file, found %d", ref, len(files))
+	}
+	return g.blob(ctx, ref, files[0], dst)
+}
+
+func (g *ociGetter) context() (context.Context, context.CancelFunc) {
+	if g.timeout > 0 {
+		return context.WithTimeout(g.ctx, g.timeout)
+	}
+	return context.WithCancel(g.ctx)
+}
+
+// manifest returns the image manifest of the artifact.
+func (g *ociGetter) manifest(ctx context.Context, ref *ociReference) (*ociManifest, error) {
+	resp, err := g.do(ctx, ref, ref.url("manifests", ref.reference),
+		ociManifestMediaType+", "+dockerManifestMediaType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OCI artifact %s manifest: %w", ref, err)
+	}
+	defer resp.Body.Close()
+
+	var manifest ociManifest
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4*1024*1024)).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode OCI artifact %s manifest: %w", ref, err)
+	}
+	return &manifest, nil
+}
+
+// blob downloads a blob of the artifact to path, verifying its digest.
+func (g *ociGetter) blob(ctx context.Context, ref *ociReference, desc ociDescriptor, path string) error {
+	algorithm, expected, ok := strings.Cut(desc.Digest, ":")
+	var h hash.Hash
+	switch {
+	case !ok:
+	case algorithm == "sha256":
+		h = sha256.New()
+	case algorithm == "sha512":
+		h = sha512.New()
+	}
+	if h == nil {
+		return fmt.Errorf("OCI artifact %s has unsupported digest %q", ref, desc.Digest)
+	}
+
+	resp, err := g.do(ctx, ref, ref.url("blobs", desc.Digest), "")
+	if err != nil {
+		return fmt.Errorf("failed to get OCI artifact %s blob %s: %w", ref, desc.Digest, err)
+	}
+	defer resp.Body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	n, err := io.Copy(io.MultiWriter(f, h), resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to download OCI artifact %s blob %s: %w", ref, desc.Digest, err)
+	}
+	if desc.Size > 0 && n != desc.Size {
+		return fmt.Errorf("OCI artifact %s blob %s expected %d bytes but read %d", ref, desc.Digest, desc.Size, n)
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != expected {
+		return fmt.Errorf("OCI artifact %s blob %s has digest %s:%s", ref, desc.Digest, algorithm, actual)
+	}
+	return nil
+}
+
+// do makes a GET request to the registry, authenticating as challenged by the
+// registry if necessary.
+func (g *ociGetter) do(ctx context.Context, ref *ociReference, target, accept string) (*http.Response, error) {
+	send := func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range g.headers {
+			req.Header[k] = v
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if g.token != "" {
+			req.Header.Set("Authorization", "Bearer "+g.token)
+		} else if ref.base.User != nil {
+			password, _ := ref.base.User.Password()
+			req.SetBasicAuth(ref.base.User.Username(), password)
+		}
+		return g.client.Do(req)
+	}
+
+	resp, err := send()
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && g.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+
+		scheme, params := parseAuthChallenge(challenge)
+		if !strings.EqualFold(scheme, "bearer") {
+			return nil, fmt.Errorf("unauthorized")
+		}
+		if g.token, err = g.authenticate(ctx, ref, params); err != nil {
+			return nil, err
+		}
+		if resp, err = send(); err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+	return resp, nil
+}
+
+// authenticate gets a bearer token from the token service of the registry
+// named by a bearer authentication challenge.
+func (g *ociGetter) authenticate(ctx context.Context, ref *ociReference, params map[string]string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Scheme == "" {
+		return "", fmt.Errorf("invalid authentication realm %q", params["realm"])
+	}
+
+	q := realm.Query()
+	if service := params["service"]; service != "" {
+		q.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + ref.repository + ":pull"
+	}
+	q.Set("scope", scope)
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if ref.base.User != nil {
+		password, _ := ref.base.User.Password()
+		req.SetBasicAuth(ref.base.User.Username(), password)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to authenticate: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to authenticate: unexpected response status %q", resp.Status)
+	}
+
+	var out struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1024*1024)).Decode(&out); err != nil {
+		return "", fmt.Errorf("failed to decode authentication token: %w", err)
+	}
+	if out.Token != "" {
+		return out.Token, nil
+	}
+	if out.AccessToken != "" {
+		return out.AccessToken, nil
+	}
+	return "", errors.New("failed to authenticate: no token issued")
+}
+
+// parseAuthChallenge parses a WWW-Authenticate header such as
+// Bearer realm="https://auth.example.com/token",service="example.com"
+func parseAuthChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := make(map[string]string)
+
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				params[key] = value[1:]
+				break
+			}
+			params[key] = value[1 : end+1]
+			rest = strings.TrimPrefix(strings.TrimSpace(value[end+2:]), ",")
+		} else {
+			value, rest, _ = strings.Cut(value, ",")
+			params[key] = strings.TrimSpace(value)
+		}
+	}
+	return scheme, params
+}
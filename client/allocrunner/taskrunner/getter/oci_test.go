@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+// testRegistry serves an artifact with the given files from a registry that
+// requires a bearer token, returning the URL of the artifact.
+func testRegistry(t *testing.T, files map[string]string) string {
+	blobs := make(map[string]string)
+	manifest := ociManifest{MediaType: ociManifestMediaType}
+	for name, content := range files {
+		sum := sha256.Sum256([]byte(content))
+		digest := "sha256:" + hex.EncodeToString(sum[:])
+		blobs[digest] = content
+		manifest.Layers = append(manifest.Layers, ociDescriptor{
+			MediaType:   "application/octet-stream",
+			Digest:      digest,
+			Size:        int64(len(content)),
+			Annotations: map[string]string{ociTitleAnnotation: name},
+		})
+	}
+
+	mux := http.NewServeMux()
+	var srv *httptest.Server
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		must.Eq(t, "repository:team/app:pull", r.URL.Query().Get("scope"))
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": "secret"})
+	})
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+srv.URL+`/token",service="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/team/app/manifests/v1":
+			w.Header().Set("Content-Type", ociManifestMediaType)
+			_ = json.NewEncoder(w).Encode(manifest)
+		default:
+			digest := filepath.Base(r.URL.Path)
+			content, ok := blobs[digest]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(content))
+		}
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv.URL + "/team/app:v1"
+}
+
+func TestOCIGetter_Get(t *testing.T) {
+	source := testRegistry(t, map[string]string{
+		"config.yaml": "port: 8080",
+		"bin/app":     "#!/bin/sh",
+	})
+	u, err := url.Parse(source)
+	must.NoError(t, err)
+
+	g := newOCIGetter(context.Background(), 0, nil, http.DefaultClient, 10, 1024)
+	dst := t.TempDir()
+	must.NoError(t, g.Get(dst, u))
+
+	b, err := os.ReadFile(filepath.Join(dst, "config.yaml"))
+	must.NoError(t, err)
+	must.Eq(t, "port: 8080", string(b))
+
+	b, err = os.ReadFile(filepath.Join(dst, "bin", "app"))
+	must.NoError(t, err)
+	must.Eq(t, "#!/bin/sh", string(b))
+
+	// a file can only be downloaded from an artifact with a single file
+	err = g.GetFile(filepath.Join(dst, "file"), u)
+	must.ErrorContains(t, err, "must have exactly one file")
+}
+
+func TestOCIGetter_GetFile(t *testing.T) {
+	source := testRegistry(t, map[string]string{
+		"config.yaml": "port: 8080",
+	})
+	u, err := url.Parse(source)
+	must.NoError(t, err)
+
+	g := newOCIGetter(context.Background(), 0, nil, http.DefaultClient, 10, 1024)
+	dst := filepath.Join(t.TempDir(), "app.yaml")
+	must.NoError(t, g.GetFile(dst, u))
+
+	b, err := os.ReadFile(dst)
+	must.NoError(t, err)
+	must.Eq(t, "port: 8080", string(b))
+}
+
+func TestOCIGetter_escape(t *testing.T) {
+	source := testRegistry(t, map[string]string{
+		"../escape": "oops",
+	})
+	u, err := url.Parse(source)
+	must.NoError(t, err)
+
+	g := newOCIGetter(context.Background(), 0, nil, http.DefaultClient, 10, 1024)
+	err = g.Get(t.TempDir(), u)
+	must.ErrorContains(t, err, "escapes the destination")
+}
+
+func TestParseOCIReference(t *testing.T) {
+	cases := []struct {
+		source     string
+		base       string
+		repository string
+		reference  string
+	}{
+		{"oci://ghcr.io/team/app:v1", "https://ghcr.io", "team/app", "v1"},
+		{"oci://ghcr.io/team/app", "https://ghcr.io", "team/app", "latest"},
+		{"oci://localhost:5000/app@sha256:abc", "https://localhost:5000", "app", "sha256:abc"},
+		{"http://localhost:5000/app:v2", "http://localhost:5000", "app", "v2"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.source, func(t *testing.T) {
+			u, err := url.Parse(tc.source)
+			must.NoError(t, err)
+			ref, err := parseOCIReference(u)
+			must.NoError(t, err)
+			must.Eq(t, tc.base, ref.base.String())
+			must.Eq(t, tc.repository, ref.repository)
+			must.Eq(t, tc.reference, ref.reference)
+		})
+	}
+}
+
+func TestParseAuthChallenge(t *testing.T) {
+	scheme, params := parseAuthChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:app:pull,push"`)
+	must.Eq(t, "Bearer", scheme)
+	must.Eq(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:app:pull,push",
+	}, params)
+}
@@ -28,17 +28,20 @@ type parameters struct {
 	GitTimeout                  time.Duration `json:"git_timeout"`
 	HgTimeout                   time.Duration `json:"hg_timeout"`
 	S3Timeout                   time.Duration `json:"s3_timeout"`
+	OCITimeout                  time.Duration `json:"oci_timeout"`
 	DecompressionLimitFileCount int           `json:"decompression_limit_file_count"`
 	DecompressionLimitSize      int64         `json:"decompression_limit_size"`
 	DisableFilesystemIsolation  bool          `json:"disable_filesystem_isolation"`
 	SetEnvironmentVariables     string        `json:"set_environment_variables"`
+	BandwidthLimit              int64         `json:"bandwidth_limit"`
 
 	// Artifact
-	Mode        getter.ClientMode   `json:"artifact_mode"`
-	Insecure    bool                `json:"artifact_insecure"`
-	Source      string              `json:"artifact_source"`
-	Destination string              `json:"artifact_destination"`
-	Headers     map[string][]string `json:"artifact_headers"`
+	Mode        getter.ClientMode    `json:"artifact_mode"`
+	Insecure    bool                 `json:"artifact_insecure"`
+	Source      string               `json:"artifact_source"`
+	Destination string               `json:"artifact_destination"`
+	Headers     map[string][]string  `json:"artifact_headers"`
+	Credentials *artifactCredentials `json:"artifact_credentials,omitempty"`
 
 	// Task Filesystem
 	AllocDir string `json:"alloc_dir"`
@@ -70,6 +73,7 @@ func (p *parameters) deadline() time.Duration {
 	maximum = max(maximum, p.GitTimeout)
 	maximum = max(maximum, p.HgTimeout)
 	maximum = max(maximum, p.S3Timeout)
+	maximum = max(maximum, p.OCITimeout)
 	return maximum + 1*time.Minute
 }
 
@@ -92,6 +96,8 @@ func (p *parameters) Equal(o *parameters) bool {
 		return false
 	case p.S3Timeout != o.S3Timeout:
 		return false
+	case p.OCITimeout != o.OCITimeout:
+		return false
 	case p.DecompressionLimitFileCount != o.DecompressionLimitFileCount:
 		return false
 	case p.DecompressionLimitSize != o.DecompressionLimitSize:
@@ -100,6 +106,8 @@ func (p *parameters) Equal(o *parameters) bool {
 		return false
 	case p.SetEnvironmentVariables != o.SetEnvironmentVariables:
 		return false
+	case p.BandwidthLimit != o.BandwidthLimit:
+		return false
 	case p.Mode != o.Mode:
 		return false
 	case p.Insecure != o.Insecure:
@@ -112,6 +120,8 @@ func (p *parameters) Equal(o *parameters) bool {
 		return false
 	case !maps.EqualFunc(p.Headers, o.Headers, headersCompareFn):
 		return false
+	case !p.Credentials.Equal(o.Credentials):
+		return false
 	}
 
 	return true
@@ -130,28 +140,6 @@ const (
 )
 
 func (p *parameters) client(ctx context.Context) *getter.Client {
-	httpGetter := &getter.HttpGetter{
-		Netrc:  true,
-		Header: p.Headers,
-
-		// Do not support the custom X-Terraform-Get header and
-		// associated logic.
-		XTerraformGetDisabled: true,
-
-		// Disable HEAD requests as they can produce corrupt files when
-		// retrying a download of a resource that has changed.
-		// hashicorp/go-getter#219
-		DoNotCheckHeadFirst: true,
-
-		// Read timeout for HTTP operations. Must be long enough to
-		// accommodate large/slow downloads.
-		ReadTimeout: p.HTTPReadTimeout,
-
-		// Maximum download size. Must be large enough to accommodate
-		// large downloads.
-		MaxBytes: p.HTTPMaxBytes,
-	}
-
 	// setup custom decompressors with file count and total size limits
 	decompressors := getter.LimitedDecompressors(
 		p.DecompressionLimitFileCount,
@@ -160,28 +148,13 @@ func (p *parameters) client(ctx context.Context) *getter.Client {
 
 	return &getter.Client{
 		Ctx:             ctx,
-		Src:             p.Source,
+		Src:             p.authenticatedSource(),
 		Dst:             p.Destination,
 		Mode:            p.Mode,
 		Insecure:        p.Insecure,
 		Umask:           umask,
 		DisableSymlinks: true,
 		Decompressors:   decompressors,
-		Getters: map[string]getter.Getter{
-			"git": &getter.GitGetter{
-				Timeout: p.GitTimeout,
-			},
-			"hg": &getter.HgGetter{
-				Timeout: p.HgTimeout,
-			},
-			"gcs": &getter.GCSGetter{
-				Timeout: p.GCSTimeout,
-			},
-			"s3": &getter.S3Getter{
-				Timeout: p.S3Timeout,
-			},
-			"http":  httpGetter,
-			"https": httpGetter,
-		},
+		Getters:         p.providers(ctx),
 	}
 }
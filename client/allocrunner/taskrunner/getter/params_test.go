@@ -22,10 +22,12 @@ const paramsAsJSON = `
   "git_timeout": 3000000000,
   "hg_timeout": 4000000000,
   "s3_timeout": 5000000000,
+  "oci_timeout": 6000000000,
   "decompression_limit_file_count": 3,
   "decompression_limit_size": 98765,
   "disable_filesystem_isolation": true,
   "set_environment_variables": "",
+  "bandwidth_limit": 1024,
   "artifact_mode": 2,
   "artifact_insecure": false,
   "artifact_source": "https://example.com/file.txt",
@@ -44,9 +46,11 @@ var paramsAsStruct = &parameters{
 	GitTimeout:                  3 * time.Second,
 	HgTimeout:                   4 * time.Second,
 	S3Timeout:                   5 * time.Second,
+	OCITimeout:                  6 * time.Second,
 	DecompressionLimitFileCount: 3,
 	DecompressionLimitSize:      98765,
 	DisableFilesystemIsolation:  true,
+	BandwidthLimit:              1024,
 
 	Mode:        getter.ClientModeFile,
 	Source:      "https://example.com/file.txt",
@@ -116,6 +120,9 @@ func TestParameters_client(t *testing.T) {
 	must.Eq(t, fileCountLimit, c.Decompressors["tar.gz"].(*getter.TarGzipDecompressor).FilesLimit)
 	must.Eq(t, fileSizeLimit, c.Decompressors["xz"].(*getter.XzDecompressor).FileSizeLimit)
 	// xz does not support files count limit
+
+	// providers
+	must.MapContainsKeys(t, c.Getters, []string{"git", "hg", "gcs", "s3", "oci", "http", "https"})
 }
 
 func TestParameters_Equal_headers(t *testing.T) {
@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-getter"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// limiterBurst is the maximum number of bytes read at once by a rate
+	// limited download.
+	limiterBurst = 32 * 1024
+)
+
+// providers returns the artifact providers keyed by the URL scheme or forced
+// getter prefix of the sources they download, e.g. "s3" for sources such as
+// s3::https://s3.amazonaws.com/bucket/key.
+func (p *parameters) providers(ctx context.Context) map[string]getter.Getter {
+	limiter := p.limiter()
+
+	httpGetter := &getter.HttpGetter{
+		Netrc:  true,
+		Header: p.Headers,
+
+		// Rate limit the download, and apply the artifact's insecure
+		// setting ourselves as a custom client is used.
+		Client: p.httpClient(limiter),
+
+		// Do not support the custom X-Terraform-Get header and
+		// associated logic.
+		XTerraformGetDisabled: true,
+
+		// Disable HEAD requests as they can produce corrupt files when
+		// retrying a download of a resource that has changed.
+		// hashicorp/go-getter#219
+		DoNotCheckHeadFirst: true,
+
+		// Read timeout for HTTP operations. Must be long enough to
+		// accommodate large/slow downloads.
+		ReadTimeout: p.HTTPReadTimeout,
+
+		// Maximum download size. Must be large enough to accommodate
+		// large downloads.
+		MaxBytes: p.HTTPMaxBytes,
+	}
+
+	return map[string]getter.Getter{
+		"git": &getter.GitGetter{
+			Timeout: p.GitTimeout,
+		},
+		"hg": &getter.HgGetter{
+			Timeout: p.HgTimeout,
+		},
+		"gcs":   newGCSGetter(ctx, p.GCSTimeout, limiter),
+		"s3":    newS3Getter(ctx, p.S3Timeout, limiter),
+		"oci":   newOCIGetter(ctx, p.OCITimeout, p.Headers, p.httpClient(limiter), p.DecompressionLimitFileCount, p.DecompressionLimitSize),
+		"http":  httpGetter,
+		"https": httpGetter,
+	}
+}
+
+// limiter returns the rate limiter shared by the providers of a download, or
+// nil if the download is not rate limited.
+func (p *parameters) limiter() *rate.Limiter {
+	if p.BandwidthLimit <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(p.BandwidthLimit), limiterBurst)
+}
+
+// httpClient returns the HTTP client used by the providers downloading over
+// HTTP, whose response bodies are read at the rate allowed by limiter.
+func (p *parameters) httpClient(limiter *rate.Limiter) *http.Client {
+	transport := cleanhttp.DefaultPooledTransport()
+	if p.Insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &http.Client{
+		Transport: &limitedTransport{
+			base:    transport,
+			limiter: limiter,
+		},
+	}
+}
+
+// limitedTransport is an http.RoundTripper whose response bodies are rate
+// limited.
+type limitedTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = limitReadCloser(req.Context(), resp.Body, t.limiter)
+	return resp, nil
+}
+
+// limitedReader is an io.Reader that reads at the rate allowed by its limiter.
+type limitedReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *rate.Limiter
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if burst := l.limiter.Burst(); len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := l.r.Read(p)
+	if n > 0 {
+		if waitErr := l.limiter.WaitN(l.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+// limitReader returns r read at the rate allowed by limiter, or r itself if
+// limiter is nil.
+func limitReader(ctx context.Context, r io.Reader, limiter *rate.Limiter) io.Reader {
+	if limiter == nil {
+		return r
+	}
+	return &limitedReader{ctx: ctx, r: r, limiter: limiter}
+}
+
+// limitReadCloser is limitReader for an io.ReadCloser.
+func limitReadCloser(ctx context.Context, rc io.ReadCloser, limiter *rate.Limiter) io.ReadCloser {
+	if limiter == nil {
+		return rc
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: limitReader(ctx, rc, limiter),
+		Closer: rc,
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+)
+
+func TestParameters_limiter(t *testing.T) {
+	must.Nil(t, (&parameters{}).limiter())
+
+	limiter := (&parameters{BandwidthLimit: 1024}).limiter()
+	must.NotNil(t, limiter)
+	must.Eq(t, 1024, float64(limiter.Limit()))
+}
+
+func TestLimitReader(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 3*limiterBurst)
+	ctx := context.Background()
+
+	// not limited
+	r := limitReader(ctx, bytes.NewReader(data), nil)
+	_, ok := r.(*bytes.Reader)
+	must.True(t, ok)
+
+	// reading beyond the burst waits for the limit
+	limiter := (&parameters{BandwidthLimit: 2 * limiterBurst}).limiter()
+	start := time.Now()
+	b, err := io.ReadAll(limitReader(ctx, bytes.NewReader(data), limiter))
+	must.NoError(t, err)
+	must.Eq(t, data, b)
+	must.Greater(t, 500*time.Millisecond, time.Since(start))
+}
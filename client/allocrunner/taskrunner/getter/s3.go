@@ -0,0 +1,183 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package getter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/hashicorp/go-getter"
+	"golang.org/x/time/rate"
+)
+
+// s3Getter downloads files from S3 and S3 compatible object stores in
+// concurrent byte ranges, resuming interrupted downloads. Directories are
+// downloaded by the go-getter S3 getter.
+type s3Getter struct {
+	*getter.S3Getter
+
+	ctx     context.Context
+	limiter *rate.Limiter
+}
+
+func newS3Getter(ctx context.Context, timeout time.Duration, limiter *rate.Limiter) *s3Getter {
+	return &s3Getter{
+		S3Getter: &getter.S3Getter{
+			Timeout: timeout,
+		},
+		ctx:     ctx,
+		limiter: limiter,
+	}
+}
+
+func (g *s3Getter) GetFile(dst string, u *url.URL) error {
+	ctx := g.ctx
+	if g.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.Timeout)
+		defer cancel()
+	}
+
+	obj, err := newS3Object(u)
+	if err != nil {
+		return err
+	}
+	return downloadMultipart(ctx, obj, dst, multipartSize, g.limiter)
+}
+
+// s3Object is a rangeObject of an S3 object.
+type s3Object struct {
+	client  *s3.S3
+	bucket  string
+	key     string
+	version string
+
+	// etag is set by Stat and ensures all ranges are read from the same
+	// content.
+	etag string
+}
+
+// newS3Object parses the S3 object from the URL, in the same formats and with
+// the same credential options as the go-getter S3 getter.
+func newS3Object(u *url.URL) (*s3Object, error) {
+	q := u.Query()
+	obj := &s3Object{version: q.Get("version")}
+	cfg := aws.NewConfig()
+
+	if strings.Contains(u.Host, "amazonaws.com") {
+		// Amazon S3 supports both virtual-hosted and path style URLs, with
+		// the region separated by either a dot or a dash.
+		hostParts := strings.Split(u.Host, ".")
+		var region string
+		switch len(hostParts) {
+		case 3:
+			// s3.amazonaws.com/bucket/key or s3-region.amazonaws.com/bucket/key
+			region = strings.TrimPrefix(strings.TrimPrefix(hostParts[0], "s3-"), "s3")
+			if region == "" {
+				region = "us-east-1"
+			}
+			pathParts := strings.SplitN(u.Path, "/", 3)
+			if len(pathParts) != 3 {
+				return nil, fmt.Errorf("URL is not a valid S3 URL")
+			}
+			obj.bucket, obj.key = pathParts[1], pathParts[2]
+		case 4, 5:
+			// bucket.s3-region.amazonaws.com/key or bucket.s3.region.amazonaws.com/key
+			if len(hostParts) == 4 {
+				region = strings.TrimPrefix(strings.TrimPrefix(hostParts[1], "s3-"), "s3")
+			} else {
+				region = hostParts[2]
+			}
+			if region == "" {
+				return nil, fmt.Errorf("URL is not a valid S3 URL")
+			}
+			obj.bucket, obj.key = hostParts[0], strings.TrimPrefix(u.Path, "/")
+		default:
+			return nil, fmt.Errorf("URL is not a valid S3 URL")
+		}
+		cfg = cfg.WithRegion(region)
+	} else {
+		pathParts := strings.SplitN(u.Path, "/", 3)
+		if len(pathParts) != 3 {
+			return nil, fmt.Errorf("URL is not a valid S3 compliant URL")
+		}
+		obj.bucket, obj.key = pathParts[1], pathParts[2]
+
+		region := q.Get("region")
+		if region == "" {
+			region = "us-east-1"
+		}
+		cfg = cfg.WithRegion(region).
+			WithEndpoint(u.Host).
+			WithS3ForcePathStyle(true).
+			WithDisableSSL(u.Scheme == "http")
+	}
+
+	_, hasID := q["aws_access_key_id"]
+	_, hasSecret := q["aws_access_key_secret"]
+	_, hasToken := q["aws_access_token"]
+	if hasID || hasSecret || hasToken {
+		cfg = cfg.WithCredentials(credentials.NewStaticCredentials(
+			q.Get("aws_access_key_id"),
+			q.Get("aws_access_key_secret"),
+			q.Get("aws_access_token"),
+		))
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *cfg,
+		Profile:           q.Get("aws_profile"),
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 session: %w", err)
+	}
+	obj.client = s3.New(sess)
+	return obj, nil
+}
+
+func (o *s3Object) Stat(ctx context.Context) (int64, string, error) {
+	input := &s3.HeadObjectInput{
+		Bucket: aws.String(o.bucket),
+		Key:    aws.String(o.key),
+	}
+	if o.version != "" {
+		input.VersionId = aws.String(o.version)
+	}
+
+	out, err := o.client.HeadObjectWithContext(ctx, input)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read S3 object %s/%s: %w", o.bucket, o.key, err)
+	}
+	o.etag = aws.StringValue(out.ETag)
+	return aws.Int64Value(out.ContentLength), o.etag, nil
+}
+
+func (o *s3Object) ReadRange(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(o.bucket),
+		Key:    aws.String(o.key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	}
+	if o.version != "" {
+		input.VersionId = aws.String(o.version)
+	}
+	if o.etag != "" {
+		input.IfMatch = aws.String(o.etag)
+	}
+
+	out, err := o.client.GetObjectWithContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
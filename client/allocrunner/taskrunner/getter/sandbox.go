@@ -4,27 +4,46 @@
 package getter
 
 import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/interfaces"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
-// New creates a Sandbox with the given ArtifactConfig.
-func New(ac *config.ArtifactConfig, logger hclog.Logger) *Sandbox {
-	return &Sandbox{
-		logger: logger.Named("artifact"),
+// New creates a Sandbox with the given ArtifactConfig. Artifacts pinned by a
+// checksum are cached in cacheDir.
+func New(ac *config.ArtifactConfig, cacheDir string, logger hclog.Logger) *Sandbox {
+	logger = logger.Named("artifact")
+	s := &Sandbox{
+		logger: logger,
 		ac:     ac,
 	}
+	if ac != nil {
+		s.cache = newCache(cacheDir, ac.CacheMaxBytes, logger)
+	}
+	return s
 }
 
 // A Sandbox is used to download artifacts.
 type Sandbox struct {
 	logger hclog.Logger
 	ac     *config.ArtifactConfig
+
+	// cache is nil if caching is disabled
+	cache *cache
+
+	// active is the number of downloads in progress, which share the
+	// bandwidth limit
+	active atomic.Int64
 }
 
-func (s *Sandbox) Get(env interfaces.EnvReplacer, artifact *structs.TaskArtifact) error {
+func (s *Sandbox) Get(env interfaces.EnvReplacer, artifact *structs.TaskArtifact, identityToken string) error {
 	s.logger.Debug("get", "source", artifact.GetterSource, "destination", artifact.RelativeDest)
 
 	source, err := getURL(env, artifact)
@@ -38,11 +57,38 @@ func (s *Sandbox) Get(env interfaces.EnvReplacer, artifact *structs.TaskArtifact
 	}
 
 	mode := getMode(artifact)
-	insecure := isInsecure(artifact)
-	headers := getHeaders(env, artifact)
 	allocDir, taskDir := getWritableDirs(env)
+	params := s.parameters(env, artifact, source, destination, allocDir, taskDir)
+
+	if artifact.GetterIdentity != "" {
+		creds, err := s.exchangeIdentity(source, artifact.GetterIdentity, identityToken)
+		if err != nil {
+			return err
+		}
+		if len(creds.Headers) > 0 && params.Headers == nil {
+			params.Headers = make(http.Header, len(creds.Headers))
+		}
+		for k, v := range creds.Headers {
+			http.Header(params.Headers).Set(k, v)
+		}
+		params.Credentials = creds
+
+		// authenticated artifacts are not cached, so they are never
+		// copied to tasks without access to them
+		return s.download(params)
+	}
+
+	if key, ok := cacheKey(source, mode); ok && s.cache != nil {
+		return s.getCached(key, params)
+	}
+	return s.download(params)
+}
 
-	params := &parameters{
+// parameters returns the parameters of the sandboxed download of the artifact
+// from source to destination, which may only write to allocDir and taskDir.
+func (s *Sandbox) parameters(env interfaces.EnvReplacer, artifact *structs.TaskArtifact,
+	source, destination, allocDir, taskDir string) *parameters {
+	return &parameters{
 		// downloader configuration
 		HTTPReadTimeout:             s.ac.HTTPReadTimeout,
 		HTTPMaxBytes:                s.ac.HTTPMaxBytes,
@@ -50,25 +96,148 @@ func (s *Sandbox) Get(env interfaces.EnvReplacer, artifact *structs.TaskArtifact
 		GitTimeout:                  s.ac.GitTimeout,
 		HgTimeout:                   s.ac.HgTimeout,
 		S3Timeout:                   s.ac.S3Timeout,
+		OCITimeout:                  s.ac.OCITimeout,
 		DecompressionLimitFileCount: s.ac.DecompressionLimitFileCount,
 		DecompressionLimitSize:      s.ac.DecompressionLimitSize,
 		DisableFilesystemIsolation:  s.ac.DisableFilesystemIsolation,
 		SetEnvironmentVariables:     s.ac.SetEnvironmentVariables,
 
 		// artifact configuration
-		Mode:        mode,
-		Insecure:    insecure,
+		Mode:        getMode(artifact),
+		Insecure:    isInsecure(artifact),
 		Source:      source,
 		Destination: destination,
-		Headers:     headers,
+		Headers:     getHeaders(env, artifact),
 
 		// task filesystem
 		AllocDir: allocDir,
 		TaskDir:  taskDir,
 	}
+}
+
+// Prefetch downloads the artifact into the cache ahead of the placement of its
+// task, so the task copies it from the cache. Only the artifacts that would be
+// cached, that don't use a workload identity and whose source, options and
+// headers are not interpolated are prefetched, as there is no task yet.
+func (s *Sandbox) Prefetch(artifact *structs.TaskArtifact) (bool, error) {
+	if s.cache == nil || artifact.GetterIdentity != "" || isInterpolated(artifact) {
+		return false, nil
+	}
+
+	env := literalEnv{}
+	source, err := getURL(env, artifact)
+	if err != nil {
+		return false, err
+	}
+	key, ok := cacheKey(source, getMode(artifact))
+	if !ok {
+		return false, nil
+	}
+	if _, ok := s.cache.lookup(key); ok {
+		return true, nil
+	}
+
+	// download the artifact into a staging directory standing in for the
+	// task directory
+	staging, err := os.MkdirTemp("", "nomad-artifact-")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(staging)
 
-	if err = s.runCmd(params); err != nil {
+	s.logger.Debug("prefetch", "source", artifact.GetterSource)
+	destination := filepath.Join(staging, "artifact")
+	params := s.parameters(env, artifact, source, destination, staging, staging)
+	if err := s.download(params); err != nil {
+		return false, err
+	}
+	if err := s.cache.put(key, destination); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isInterpolated returns true if the source, options or headers of the
+// artifact reference the task environment.
+func isInterpolated(artifact *structs.TaskArtifact) bool {
+	if strings.Contains(artifact.GetterSource, "${") {
+		return true
+	}
+	for _, v := range artifact.GetterOptions {
+		if strings.Contains(v, "${") {
+			return true
+		}
+	}
+	for _, v := range artifact.GetterHeaders {
+		if strings.Contains(v, "${") {
+			return true
+		}
+	}
+	return false
+}
+
+// literalEnv is the environment of prefetched artifacts, which are not
+// interpolated.
+type literalEnv struct{}
+
+func (literalEnv) ReplaceEnv(s string) string { return s }
+
+func (literalEnv) ClientPath(p string, _ bool) (string, bool) { return p, false }
+
+// getCached copies the artifact from the cache, or downloads and caches it.
+func (s *Sandbox) getCached(key string, params *parameters) error {
+	if cached, ok := s.cache.lookup(key); ok {
+		s.logger.Debug("using cached artifact", "source", params.Source)
+		if err := copyTree(cached, params.Destination); err != nil {
+			return &Error{
+				URL:         params.Source,
+				Err:         err,
+				Recoverable: true,
+			}
+		}
+		return nil
+	}
+
+	// download the artifact into a staging directory in the task directory,
+	// which the sandboxed download is allowed to write to
+	staging, err := os.MkdirTemp(params.TaskDir, ".artifact-")
+	if err != nil {
+		return &Error{
+			URL:         params.Source,
+			Err:         err,
+			Recoverable: true,
+		}
+	}
+	defer os.RemoveAll(staging)
+
+	staged := *params
+	staged.Destination = filepath.Join(staging, "artifact")
+	if err := s.download(&staged); err != nil {
 		return err
 	}
+
+	if err := copyTree(staged.Destination, params.Destination); err != nil {
+		return &Error{
+			URL:         params.Source,
+			Err:         err,
+			Recoverable: true,
+		}
+	}
+
+	if err := s.cache.put(key, staged.Destination); err != nil {
+		s.logger.Warn("failed to cache artifact", "source", params.Source, "error", err)
+	}
 	return nil
 }
+
+// download runs the sandboxed download, giving it an even share of the
+// bandwidth limit with the downloads in progress.
+func (s *Sandbox) download(params *parameters) error {
+	active := s.active.Add(1)
+	defer s.active.Add(-1)
+
+	if s.ac.BandwidthLimit > 0 {
+		params.BandwidthLimit = max(s.ac.BandwidthLimit/active, 1)
+	}
+	return s.runCmd(params)
+}
@@ -36,7 +36,7 @@ func TestSandbox_Get_http(t *testing.T) {
 	logger := testlog.HCLogger(t)
 
 	ac := artifactConfig(10 * time.Second)
-	sbox := New(ac, logger)
+	sbox := New(ac, "", logger)
 
 	_, taskDir := SetupDir(t)
 	env := noopTaskEnv(taskDir)
@@ -46,7 +46,7 @@ func TestSandbox_Get_http(t *testing.T) {
 		RelativeDest: "local/downloads",
 	}
 
-	err := sbox.Get(env, artifact)
+	err := sbox.Get(env, artifact, "")
 	must.NoError(t, err)
 
 	b, err := os.ReadFile(filepath.Join(taskDir, "local", "downloads", "go.mod"))
@@ -59,7 +59,7 @@ func TestSandbox_Get_insecure_http(t *testing.T) {
 	logger := testlog.HCLogger(t)
 
 	ac := artifactConfig(10 * time.Second)
-	sbox := New(ac, logger)
+	sbox := New(ac, "", logger)
 
 	_, taskDir := SetupDir(t)
 	env := noopTaskEnv(taskDir)
@@ -74,11 +74,11 @@ func TestSandbox_Get_insecure_http(t *testing.T) {
 		RelativeDest: "local/downloads",
 	}
 
-	err := sbox.Get(env, artifact)
+	err := sbox.Get(env, artifact, "")
 	must.Error(t, err)
 	must.StrContains(t, err.Error(), "x509: certificate signed by unknown authority")
 
 	artifact.GetterInsecure = true
-	err = sbox.Get(env, artifact)
+	err = sbox.Get(env, artifact, "")
 	must.NoError(t, err)
 }
@@ -131,6 +131,29 @@ TRY:
 	return out, c, err
 }
 
+func (l *LazyHandle) ContainerHealth() (string, string, error) {
+	h, err := l.getHandle()
+	if err != nil {
+		return "", "", err
+	}
+
+	// Only retry once
+	first := true
+
+TRY:
+	status, output, err := h.ContainerHealth()
+	if err == bstructs.ErrPluginShutdown && first {
+		first = false
+
+		h, err = l.refreshHandle()
+		if err == nil {
+			goto TRY
+		}
+	}
+
+	return status, output, err
+}
+
 func (l *LazyHandle) Stats(ctx context.Context, interval time.Duration) (<-chan *cstructs.TaskResourceUsage, error) {
 	h, err := l.getHandle()
 	if err != nil {
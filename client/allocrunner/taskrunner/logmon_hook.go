@@ -18,7 +18,9 @@ import (
 	"github.com/hashicorp/nomad/client/logmon"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
 	bstructs "github.com/hashicorp/nomad/plugins/base/structs"
+	"github.com/hashicorp/nomad/plugins/logsink"
 	pstructs "github.com/hashicorp/nomad/plugins/shared/structs"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -193,13 +195,20 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 	}
 
 	err := h.logmon.Start(&logmon.LogConfig{
-		LogDir:        h.config.logDir,
-		StdoutLogFile: fmt.Sprintf("%s.stdout", req.Task.Name),
-		StderrLogFile: fmt.Sprintf("%s.stderr", req.Task.Name),
-		StdoutFifo:    h.config.stdoutFifo,
-		StderrFifo:    h.config.stderrFifo,
-		MaxFiles:      req.Task.LogConfig.MaxFiles,
-		MaxFileSizeMB: req.Task.LogConfig.MaxFileSizeMB,
+		LogDir:         h.config.logDir,
+		StdoutLogFile:  fmt.Sprintf("%s.stdout", req.Task.Name),
+		StderrLogFile:  fmt.Sprintf("%s.stderr", req.Task.Name),
+		StdoutFifo:     h.config.stdoutFifo,
+		StderrFifo:     h.config.stderrFifo,
+		MaxFiles:       req.Task.LogConfig.MaxFiles,
+		MaxFileSizeMB:  req.Task.LogConfig.MaxFileSizeMB,
+		Backpressure:   req.Task.LogConfig.Backpressure,
+		SpillDir:       h.spillDir(),
+		RotateInterval: req.Task.LogConfig.RotateInterval,
+		Compression:    req.Task.LogConfig.Compression,
+		Retention:      req.Task.LogConfig.Retention,
+		Sinks:          h.logSinks(req.Task),
+		Task:           h.logSinkTaskInfo(req.Task),
 	})
 	if err != nil {
 		h.logger.Error("failed to start logmon", "error", err)
@@ -209,6 +218,69 @@ func (h *logmonHook) prestartOneLoop(ctx context.Context, req *interfaces.TaskPr
 	return nil
 }
 
+// logSinks returns the log sink plugins that the task's logs are shipped to:
+// the sinks of the task's logs block, or else the client's default sinks.
+// Sinks unknown to the client are skipped so that the task still runs, with
+// its logs only written to its log files.
+func (h *logmonHook) logSinks(task *structs.Task) []*logmon.LogSinkConfig {
+	sinks := task.LogConfig.Sinks
+	if len(sinks) == 0 {
+		for _, name := range h.runner.clientConfig.LogSinks {
+			sinks = append(sinks, &structs.LogSink{Name: name})
+		}
+	}
+	if len(sinks) == 0 {
+		return nil
+	}
+
+	out := make([]*logmon.LogSinkConfig, 0, len(sinks))
+	for _, sink := range sinks {
+		launch, err := h.runner.clientConfig.PluginLoader.LaunchConfig(sink.Name, base.PluginTypeLogSink)
+		if err != nil {
+			h.logger.Warn("skipping unknown log sink", "log_sink", sink.Name, "error", err)
+			continue
+		}
+
+		cfg := &logmon.LogSinkConfig{
+			Name:       sink.Name,
+			Config:     launch.Config,
+			ApiVersion: launch.ApiVersion,
+			Options:    sink.Options,
+		}
+		if !launch.Internal {
+			cfg.ExePath = launch.ExePath
+			cfg.Args = launch.Args
+		}
+		out = append(out, cfg)
+	}
+	return out
+}
+
+// logSinkTaskInfo returns what identifies the task to its log sinks.
+func (h *logmonHook) logSinkTaskInfo(task *structs.Task) *logsink.TaskInfo {
+	alloc := h.runner.Alloc()
+	info := &logsink.TaskInfo{
+		Namespace: alloc.Namespace,
+		JobID:     alloc.JobID,
+		AllocID:   alloc.ID,
+		TaskGroup: alloc.TaskGroup,
+		TaskName:  task.Name,
+	}
+	if node := h.runner.clientConfig.Node; node != nil {
+		info.NodeID = node.ID
+	}
+	return info
+}
+
+// spillDir returns the directory that the task's logs are spilled to, or an
+// empty string if the client has no log spill directory.
+func (h *logmonHook) spillDir() string {
+	if h.runner.clientConfig.LogSpillDir == "" {
+		return ""
+	}
+	return filepath.Join(h.runner.clientConfig.LogSpillDir, h.runner.allocID)
+}
+
 func (h *logmonHook) Stop(_ context.Context, req *interfaces.TaskStopRequest, _ *interfaces.TaskStopResponse) error {
 	if h.isLoggingDisabled() {
 		return nil
@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	ti "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/client/taskenv"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+var _ interfaces.TaskPrestartHook = &migrateHook{}
+var _ interfaces.TaskPoststartHook = &migrateHook{}
+var _ interfaces.TaskPreKillHook = &migrateHook{}
+
+// migrateHook runs the migrate hooks of the task group's ephemeral disk inside
+// the task: pre_migrate hooks before the task is stopped by the servers, so
+// the disk that is moved or migrated to the replacement allocation holds a
+// consistent application state, and post_restore hooks once the task first
+// starts in the replacement allocation.
+type migrateHook struct {
+	// alloc returns the current version of the allocation, which is updated
+	// before the task is killed
+	alloc  func() *structs.Allocation
+	task   string
+	events ti.EventEmitter
+	logger log.Logger
+
+	// restorePending is set if the post_restore hooks have to run once the
+	// task is started
+	restorePending bool
+
+	// driverExec and taskEnv are set by Poststart
+	driverExec ti.ScriptExecutor
+	taskEnv    *taskenv.TaskEnv
+
+	mu sync.Mutex
+}
+
+func newMigrateHook(alloc func() *structs.Allocation, task string, events ti.EventEmitter, logger log.Logger) *migrateHook {
+	h := &migrateHook{
+		alloc:  alloc,
+		task:   task,
+		events: events,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*migrateHook) Name() string {
+	return "migrate"
+}
+
+// Prestart determines whether the post_restore hooks have to run. It is done
+// after the first start, so the hooks do not run again when the task restarts
+// or the client is restarted.
+func (h *migrateHook) Prestart(_ context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	resp.Done = true
+	if req.Alloc.PreviousAllocation == "" {
+		return nil
+	}
+	h.restorePending = len(h.hooks(req.Alloc, structs.MigrateHookStagePostRestore)) > 0
+	return nil
+}
+
+// Poststart runs the post_restore hooks if the task was started for the
+// first time in an allocation replacing a previous allocation.
+func (h *migrateHook) Poststart(_ context.Context, req *interfaces.TaskPoststartRequest, _ *interfaces.TaskPoststartResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.driverExec = req.DriverExec
+	h.taskEnv = req.TaskEnv
+
+	if !h.restorePending {
+		return nil
+	}
+	h.restorePending = false
+
+	return h.run(h.hooks(h.alloc(), structs.MigrateHookStagePostRestore))
+}
+
+// PreKilling runs the pre_migrate hooks if the task is being stopped because
+// the allocation was stopped by the servers, for example because it is
+// replaced or migrated off a draining node. Task restarts do not run them.
+func (h *migrateHook) PreKilling(_ context.Context, _ *interfaces.TaskPreKillRequest, _ *interfaces.TaskPreKillResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	alloc := h.alloc()
+	if !alloc.ServerTerminalStatus() {
+		return nil
+	}
+	return h.run(h.hooks(alloc, structs.MigrateHookStagePreMigrate))
+}
+
+// hooks returns the migrate hooks of the given stage for the task.
+func (h *migrateHook) hooks(alloc *structs.Allocation, stage string) []*structs.MigrateHook {
+	if alloc == nil || alloc.Job == nil {
+		return nil
+	}
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return nil
+	}
+	return tg.EphemeralDisk.MigrateHooksForTask(stage, h.task)
+}
+
+// run executes the hooks inside the task in order, stopping at the first
+// failure. Must be called with the lock held.
+func (h *migrateHook) run(hooks []*structs.MigrateHook) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	if h.driverExec == nil {
+		return fmt.Errorf("task driver doesn't support the exec operation required by %s hooks", hooks[0].Stage)
+	}
+
+	for _, hook := range hooks {
+		command, args := hook.Command, hook.Args
+		if h.taskEnv != nil {
+			command = h.taskEnv.ReplaceEnv(command)
+			args = h.taskEnv.ParseAndReplace(args)
+		}
+
+		h.logger.Debug("running migrate hook", "stage", hook.Stage, "command", command)
+		output, exitCode, err := h.driverExec.Exec(hook.Timeout, command, args)
+		if err != nil {
+			return fmt.Errorf("%s hook %v failed: %v", hook.Stage, command, err)
+		}
+		if exitCode != 0 {
+			h.logger.Debug("migrate hook failed", "stage", hook.Stage, "command", command,
+				"exit_code", exitCode, "output", string(output))
+			return fmt.Errorf("%s hook %v exited with code %d", hook.Stage, command, exitCode)
+		}
+
+		h.events.EmitEvent(structs.NewTaskEvent(structs.TaskHookMessage).
+			SetDisplayMessage(fmt.Sprintf("Successfully ran %s hook %v", hook.Stage, command)))
+	}
+	return nil
+}
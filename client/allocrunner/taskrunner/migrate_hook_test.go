@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	trtesting "github.com/hashicorp/nomad/client/allocrunner/taskrunner/testing"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// recordingExec is a fake ScriptExecutor that records the commands it runs.
+type recordingExec struct {
+	lock     sync.Mutex
+	commands []string
+	code     int
+}
+
+func (r *recordingExec) Exec(_ time.Duration, cmd string, _ []string) ([]byte, int, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.commands = append(r.commands, cmd)
+	return nil, r.code, nil
+}
+
+func (r *recordingExec) Commands() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.commands
+}
+
+func migrateHookAlloc() *structs.Allocation {
+	alloc := mock.Alloc()
+	alloc.PreviousAllocation = "prev"
+	tg := alloc.Job.TaskGroups[0]
+	tg.EphemeralDisk.Sticky = true
+	tg.EphemeralDisk.MigrateHooks = []*structs.MigrateHook{
+		{
+			Stage:   structs.MigrateHookStagePreMigrate,
+			Task:    tg.Tasks[0].Name,
+			Command: "/bin/flush",
+			Timeout: time.Second,
+		},
+		{
+			Stage:   structs.MigrateHookStagePostRestore,
+			Task:    tg.Tasks[0].Name,
+			Command: "/bin/reopen",
+			Timeout: time.Second,
+		},
+	}
+	return alloc
+}
+
+func TestMigrateHook_PostRestore(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := migrateHookAlloc()
+	exec := &recordingExec{}
+	events := &trtesting.MockEmitter{}
+	h := newMigrateHook(func() *structs.Allocation { return alloc },
+		alloc.Job.TaskGroups[0].Tasks[0].Name, events, testlog.HCLogger(t))
+
+	preReq := &interfaces.TaskPrestartRequest{Alloc: alloc}
+	var preResp interfaces.TaskPrestartResponse
+	must.NoError(t, h.Prestart(context.Background(), preReq, &preResp))
+	must.True(t, preResp.Done)
+
+	postReq := &interfaces.TaskPoststartRequest{DriverExec: exec}
+	must.NoError(t, h.Poststart(context.Background(), postReq, nil))
+	must.Eq(t, []string{"/bin/reopen"}, exec.Commands())
+	must.Len(t, 1, events.Events())
+
+	// a restarted task does not run the hooks again
+	must.NoError(t, h.Poststart(context.Background(), postReq, nil))
+	must.Eq(t, []string{"/bin/reopen"}, exec.Commands())
+}
+
+func TestMigrateHook_PostRestore_NoPreviousAlloc(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := migrateHookAlloc()
+	alloc.PreviousAllocation = ""
+	exec := &recordingExec{}
+	h := newMigrateHook(func() *structs.Allocation { return alloc },
+		alloc.Job.TaskGroups[0].Tasks[0].Name, &trtesting.MockEmitter{}, testlog.HCLogger(t))
+
+	var preResp interfaces.TaskPrestartResponse
+	must.NoError(t, h.Prestart(context.Background(), &interfaces.TaskPrestartRequest{Alloc: alloc}, &preResp))
+	must.NoError(t, h.Poststart(context.Background(), &interfaces.TaskPoststartRequest{DriverExec: exec}, nil))
+	must.SliceEmpty(t, exec.Commands())
+}
+
+func TestMigrateHook_PreMigrate(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := migrateHookAlloc()
+	alloc.PreviousAllocation = ""
+	exec := &recordingExec{}
+	h := newMigrateHook(func() *structs.Allocation { return alloc },
+		alloc.Job.TaskGroups[0].Tasks[0].Name, &trtesting.MockEmitter{}, testlog.HCLogger(t))
+
+	must.NoError(t, h.Poststart(context.Background(), &interfaces.TaskPoststartRequest{DriverExec: exec}, nil))
+
+	// restarting the task does not run the hooks
+	must.NoError(t, h.PreKilling(context.Background(), nil, nil))
+	must.SliceEmpty(t, exec.Commands())
+
+	// stopping the allocation does
+	alloc.DesiredStatus = structs.AllocDesiredStatusStop
+	must.NoError(t, h.PreKilling(context.Background(), nil, nil))
+	must.Eq(t, []string{"/bin/flush"}, exec.Commands())
+
+	// a failing hook is returned as an error
+	exec.code = 1
+	must.ErrorContains(t, h.PreKilling(context.Background(), nil, nil), "exited with code 1")
+}
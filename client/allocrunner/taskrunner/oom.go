@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"fmt"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/client/lib/cgroupslib"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// readOOMKills returns the OOM kill counter of the task's cgroup. If the
+// counter can't be read the last recorded value is returned so that no kill is
+// detected.
+func (tr *TaskRunner) readOOMKills() uint64 {
+	cores := len(tr.getTaskResources().Cpu.ReservedCores) > 0
+	kills, err := cgroupslib.OOMKills(tr.allocID, tr.taskName, cores)
+	if err != nil {
+		tr.logger.Trace("failed to read OOM kill counter", "error", err)
+		return tr.oomKills
+	}
+	return kills
+}
+
+// recordOOMKills stores the OOM kill counter of the task's cgroup so that OOM
+// kills during the next run of the task can be detected.
+func (tr *TaskRunner) recordOOMKills() {
+	tr.oomKills = tr.readOOMKills()
+}
+
+// detectOOMKills returns the number of processes of the task killed by the
+// kernel OOM killer since the task was started, and marks the exit result as
+// OOM killed if there were any. Drivers may report OOM kills themselves, but
+// the cgroup counter also catches kills they miss.
+func (tr *TaskRunner) detectOOMKills(result *drivers.ExitResult) uint64 {
+	var kills uint64
+	if current := tr.readOOMKills(); current > tr.oomKills {
+		kills = current - tr.oomKills
+		tr.oomKills = current
+	}
+
+	if kills > 0 {
+		result.OOMKilled = true
+	}
+	return kills
+}
+
+// handleOOM emits an event describing an OOM kill of the task and applies the
+// task's OOM policy before the restart tracker is consulted.
+func (tr *TaskRunner) handleOOM(kills uint64) {
+	policy := tr.Task().OOMPolicy
+	mode := policy.GetMode()
+	limit := tr.memoryLimitMB()
+
+	event := structs.NewTaskEvent(structs.TaskOOMKilled).
+		SetMemoryLimit(limit)
+	if kills > 0 {
+		event.SetOOMKills(kills)
+		metrics.IncrCounterWithLabels([]string{"client", "allocs", "oom_kills"}, float32(kills), tr.baseLabels)
+	}
+
+	msg := fmt.Sprintf("Task exceeded its memory limit of %d MB", limit)
+	if pid, rss, ok := largestProcess(tr.LatestResourceUsage()); ok {
+		event.SetOOMKilledProcess(pid, rss)
+		msg = fmt.Sprintf("%s; largest process was PID %s using %s", msg, pid, humanize.IBytes(rss))
+	}
+
+	if mode == structs.OOMPolicyModeAdjust {
+		if adjusted := tr.adjustOOMMemory(policy); adjusted > limit {
+			msg = fmt.Sprintf("%s; raising memory limit to %d MB", msg, adjusted)
+			metrics.IncrCounterWithLabels([]string{"client", "allocs", "oom_memory_adjusted"}, 1, tr.baseLabels)
+		} else {
+			msg = fmt.Sprintf("%s; memory limit is already at the OOM policy max of %d MB", msg, policy.MemoryMaxMB)
+		}
+	}
+
+	tr.logger.Warn("task was OOM killed", "oom_policy", mode, "memory_limit_mb", limit, "kills", kills)
+	tr.EmitEvent(event.SetMessage(msg))
+	tr.restartTracker.SetOOMKilled(mode)
+}
+
+// adjustOOMMemory raises the memory limit used for the next start of the task
+// by the increase of the OOM policy, up to its max, and returns the new limit.
+// The raised limit is local to the client and not reserved by the scheduler.
+func (tr *TaskRunner) adjustOOMMemory(policy *structs.OOMPolicy) int64 {
+	limit := tr.memoryLimitMB()
+	adjusted := min(limit+int64(policy.MemoryIncreaseMB), int64(policy.MemoryMaxMB))
+	if adjusted > limit {
+		tr.oomMemoryMB = adjusted
+		return adjusted
+	}
+	return limit
+}
+
+// memoryLimitMB returns the memory limit the task is started with. A limit
+// raised by the OOM policy is ignored once the task has been resized above it.
+func (tr *TaskRunner) memoryLimitMB() int64 {
+	mem := tr.getTaskResources().Memory
+	return max(tr.oomMemoryMB, mem.MemoryMB, mem.MemoryMaxMB)
+}
+
+// oomAdjustedResources returns the resources of the task with the memory
+// limit raised by the OOM policy, if it has been.
+func (tr *TaskRunner) oomAdjustedResources() *structs.AllocatedTaskResources {
+	res := tr.getTaskResources()
+	if tr.oomMemoryMB == 0 {
+		return res
+	}
+	res = res.Copy()
+	res.Memory.MemoryMaxMB = tr.memoryLimitMB()
+	return res
+}
+
+// largestProcess returns the PID and RSS of the process with the largest RSS
+// in the resource usage. As the kernel OOM killer prefers the largest process,
+// this is most likely the process that was killed.
+func largestProcess(ru *cstructs.TaskResourceUsage) (string, uint64, bool) {
+	if ru == nil {
+		return "", 0, false
+	}
+
+	var pid string
+	var rss uint64
+	for p, usage := range ru.Pids {
+		if usage == nil || usage.MemoryStats == nil {
+			continue
+		}
+		if usage.MemoryStats.RSS > rss {
+			pid, rss = p, usage.MemoryStats.RSS
+		}
+	}
+	return pid, rss, pid != ""
+}
@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestTaskRunner_OOM_largestProcess(t *testing.T) {
+	ci.Parallel(t)
+
+	_, _, ok := largestProcess(nil)
+	must.False(t, ok)
+
+	_, _, ok = largestProcess(&cstructs.TaskResourceUsage{})
+	must.False(t, ok)
+
+	pid, rss, ok := largestProcess(&cstructs.TaskResourceUsage{
+		Pids: map[string]*cstructs.ResourceUsage{
+			"10": {MemoryStats: &cstructs.MemoryStats{RSS: 100}},
+			"11": {MemoryStats: &cstructs.MemoryStats{RSS: 300}},
+			"12": {CpuStats: &cstructs.CpuStats{}},
+			"13": nil,
+		},
+	})
+	must.True(t, ok)
+	must.Eq(t, "11", pid)
+	must.Eq(t, 300, rss)
+}
+
+func TestTaskRunner_OOM_adjustMemory(t *testing.T) {
+	ci.Parallel(t)
+
+	tr := &TaskRunner{
+		taskResources: &structs.AllocatedTaskResources{
+			Memory: structs.AllocatedMemoryResources{
+				MemoryMB: 256,
+			},
+		},
+	}
+	policy := &structs.OOMPolicy{
+		Mode:             structs.OOMPolicyModeAdjust,
+		MemoryIncreaseMB: 200,
+		MemoryMaxMB:      512,
+	}
+
+	// Resources are unchanged until the memory is adjusted.
+	must.Eq(t, 256, tr.memoryLimitMB())
+	must.Eq(t, tr.taskResources, tr.oomAdjustedResources())
+
+	must.Eq(t, 456, tr.adjustOOMMemory(policy))
+	must.Eq(t, 456, tr.memoryLimitMB())
+
+	res := tr.oomAdjustedResources()
+	must.Eq(t, 256, res.Memory.MemoryMB)
+	must.Eq(t, 456, res.Memory.MemoryMaxMB)
+	must.Eq(t, 0, tr.taskResources.Memory.MemoryMaxMB)
+
+	// The limit is capped by the policy max.
+	must.Eq(t, 512, tr.adjustOOMMemory(policy))
+	must.Eq(t, 512, tr.adjustOOMMemory(policy))
+	must.Eq(t, 512, tr.oomAdjustedResources().Memory.MemoryMaxMB)
+}
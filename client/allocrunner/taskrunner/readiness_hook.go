@@ -0,0 +1,244 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	tinterfaces "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
+	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/client/serviceregistration/checks/checkstore"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+var _ interfaces.TaskPoststartHook = &readinessHook{}
+var _ interfaces.TaskExitedHook = &readinessHook{}
+var _ interfaces.TaskStopHook = &readinessHook{}
+
+// defaultReadinessCheckInterval is how often the checks of a sidecar waiting
+// to be healthy are evaluated.
+const defaultReadinessCheckInterval = time.Second
+
+type readinessHookConfig struct {
+	alloc      *structs.Allocation
+	task       *structs.Task
+	consul     serviceregistration.Handler
+	checkStore checkstore.Shim
+	lifecycle  tinterfaces.TaskLifecycle
+	events     tinterfaces.EventEmitter
+	readyFn    func()
+	logger     log.Logger
+
+	// checkInterval overrides defaultReadinessCheckInterval for testing
+	checkInterval time.Duration
+}
+
+// readinessHook watches the service checks of a prestart sidecar configured
+// with lifecycle.wait_healthy. Once all of the task's checks are passing the
+// task coordinator is notified so the main tasks may start. If the checks do
+// not pass within the healthy timeout the configured unhealthy action is
+// applied.
+type readinessHook struct {
+	alloc         *structs.Allocation
+	task          *structs.Task
+	consul        serviceregistration.Handler
+	checkStore    checkstore.Shim
+	lifecycle     tinterfaces.TaskLifecycle
+	events        tinterfaces.EventEmitter
+	readyFn       func()
+	checkInterval time.Duration
+	logger        log.Logger
+
+	// cancel stops the watcher started by the last Poststart
+	cancel context.CancelFunc
+	mu     sync.Mutex
+}
+
+func newReadinessHook(c readinessHookConfig) *readinessHook {
+	h := &readinessHook{
+		alloc:         c.alloc,
+		task:          c.task,
+		consul:        c.consul,
+		checkStore:    c.checkStore,
+		lifecycle:     c.lifecycle,
+		events:        c.events,
+		readyFn:       c.readyFn,
+		checkInterval: defaultReadinessCheckInterval,
+	}
+	if c.checkInterval != 0 {
+		h.checkInterval = c.checkInterval
+	}
+	h.logger = c.logger.Named(h.Name())
+	return h
+}
+
+func (*readinessHook) Name() string {
+	return "readiness"
+}
+
+// Poststart starts watching the task's checks. It is called every time the
+// task is started, so a restarted sidecar must become healthy again.
+func (h *readinessHook) Poststart(_ context.Context, _ *interfaces.TaskPoststartRequest, _ *interfaces.TaskPoststartResponse) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cancel != nil {
+		h.cancel()
+	}
+
+	// Cannot use Poststart's context as it is closed after all poststart hooks
+	// have run.
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	go h.watch(ctx)
+	return nil
+}
+
+// Exited stops the watcher as the task is no longer running.
+func (h *readinessHook) Exited(context.Context, *interfaces.TaskExitedRequest, *interfaces.TaskExitedResponse) error {
+	h.stop()
+	return nil
+}
+
+// Stop stops the watcher. Stop hooks must be idempotent.
+func (h *readinessHook) Stop(context.Context, *interfaces.TaskStopRequest, *interfaces.TaskStopResponse) error {
+	h.stop()
+	return nil
+}
+
+func (h *readinessHook) stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cancel != nil {
+		h.cancel()
+		h.cancel = nil
+	}
+}
+
+// watch polls the task's checks until they are all passing, the healthy
+// timeout expires, or ctx is cancelled.
+func (h *readinessHook) watch(ctx context.Context) {
+	timeout := h.task.Lifecycle.GetHealthyTimeout()
+	deadline, deadlineStop := helper.NewSafeTimer(timeout)
+	defer deadlineStop()
+
+	ticker, tickerStop := helper.NewSafeTimer(0)
+	defer tickerStop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			h.unhealthy(ctx, timeout)
+			return
+		case <-ticker.C:
+		}
+
+		if h.healthy() {
+			h.logger.Debug("sidecar is healthy, allowing main tasks to start")
+			h.events.EmitEvent(structs.NewTaskEvent(structs.TaskSidecarHealthy))
+			h.readyFn()
+			return
+		}
+		ticker.Reset(h.checkInterval)
+	}
+}
+
+// unhealthy applies the task's unhealthy action after its checks did not pass
+// within timeout.
+func (h *readinessHook) unhealthy(ctx context.Context, timeout time.Duration) {
+	action := h.task.Lifecycle.GetUnhealthyAction()
+	msg := fmt.Sprintf("Sidecar checks did not pass within %v", timeout)
+	h.logger.Warn("sidecar did not become healthy", "timeout", timeout, "action", action)
+	h.events.EmitEvent(structs.NewTaskEvent(structs.TaskSidecarUnhealthy).SetDisplayMessage(msg))
+
+	var err error
+	switch action {
+	case structs.TaskLifecycleUnhealthyActionIgnore:
+		h.readyFn()
+	case structs.TaskLifecycleUnhealthyActionRestart:
+		err = h.lifecycle.Restart(ctx,
+			structs.NewTaskEvent(structs.TaskRestartSignal).
+				SetDisplayMessage(msg), true)
+	default:
+		err = h.lifecycle.Kill(ctx,
+			structs.NewTaskEvent(structs.TaskKilling).
+				SetFailsTask().
+				SetDisplayMessage(msg))
+	}
+	if err != nil {
+		h.logger.Error("failed to apply unhealthy action", "action", action, "error", err)
+	}
+}
+
+// healthy returns true if every check of the task's services is registered
+// and passing.
+func (h *readinessHook) healthy() bool {
+	var consulChecks, nomadChecks int
+	for _, service := range h.task.Services {
+		if service.Provider == structs.ServiceProviderNomad {
+			nomadChecks += len(service.Checks)
+		} else {
+			consulChecks += len(service.Checks)
+		}
+	}
+
+	if consulChecks > 0 {
+		if h.consul == nil {
+			return false
+		}
+		reg, err := h.consul.AllocRegistrations(h.alloc.ID)
+		if err != nil || reg == nil {
+			return false
+		}
+		taskReg, ok := reg.Tasks[h.task.Name]
+		if !ok {
+			return false
+		}
+
+		found := 0
+		for _, service := range taskReg.Services {
+			for _, check := range service.Checks {
+				if check.Status != api.HealthPassing {
+					return false
+				}
+				found++
+			}
+		}
+		if found < consulChecks {
+			return false
+		}
+	}
+
+	if nomadChecks > 0 {
+		if h.checkStore == nil {
+			return false
+		}
+
+		found := 0
+		for _, result := range h.checkStore.List(h.alloc.ID) {
+			if result.Task != h.task.Name {
+				continue
+			}
+			if result.Status != structs.CheckSuccess {
+				return false
+			}
+			found++
+		}
+		if found < nomadChecks {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	trtesting "github.com/hashicorp/nomad/client/allocrunner/taskrunner/testing"
+	"github.com/hashicorp/nomad/client/serviceregistration"
+	regmock "github.com/hashicorp/nomad/client/serviceregistration/mock"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func testReadinessHook(t *testing.T, status string, lifecycle *structs.TaskLifecycleConfig) (*readinessHook, *trtesting.MockTaskHooks, chan struct{}) {
+	logger := testlog.HCLogger(t)
+
+	alloc := mock.Alloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Lifecycle = lifecycle
+	task.Services = []*structs.Service{{
+		Name:     "proxy",
+		Provider: structs.ServiceProviderConsul,
+		Checks: []*structs.ServiceCheck{{
+			Name: "ready",
+			Type: structs.ServiceCheckTCP,
+		}},
+	}}
+
+	consul := regmock.NewServiceRegistrationHandler(logger)
+	consul.AllocRegistrationsFn = func(string) (*serviceregistration.AllocRegistration, error) {
+		return &serviceregistration.AllocRegistration{
+			Tasks: map[string]*serviceregistration.ServiceRegistrations{
+				task.Name: {
+					Services: map[string]*serviceregistration.ServiceRegistration{
+						"proxy": {
+							Checks: []*consulapi.AgentCheck{{
+								Name:   "ready",
+								Status: status,
+							}},
+						},
+					},
+				},
+			},
+		}, nil
+	}
+
+	readyCh := make(chan struct{}, 1)
+	lifecycleHooks := trtesting.NewMockTaskHooks()
+	h := newReadinessHook(readinessHookConfig{
+		alloc:     alloc,
+		task:      task,
+		consul:    consul,
+		lifecycle: lifecycleHooks,
+		events:    lifecycleHooks,
+		readyFn: func() {
+			readyCh <- struct{}{}
+		},
+		logger:        logger,
+		checkInterval: 10 * time.Millisecond,
+	})
+	return h, lifecycleHooks, readyCh
+}
+
+func TestReadinessHook_Healthy(t *testing.T) {
+	ci.Parallel(t)
+
+	h, _, readyCh := testReadinessHook(t, consulapi.HealthPassing, &structs.TaskLifecycleConfig{
+		Hook:        structs.TaskLifecycleHookPrestart,
+		Sidecar:     true,
+		WaitHealthy: true,
+	})
+
+	ctx := context.Background()
+	must.NoError(t, h.Poststart(ctx, &interfaces.TaskPoststartRequest{}, &interfaces.TaskPoststartResponse{}))
+	defer h.Stop(ctx, nil, nil)
+
+	select {
+	case <-readyCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for sidecar to become ready")
+	}
+}
+
+func TestReadinessHook_UnhealthyFails(t *testing.T) {
+	ci.Parallel(t)
+
+	h, lifecycleHooks, readyCh := testReadinessHook(t, consulapi.HealthCritical, &structs.TaskLifecycleConfig{
+		Hook:           structs.TaskLifecycleHookPrestart,
+		Sidecar:        true,
+		WaitHealthy:    true,
+		HealthyTimeout: 50 * time.Millisecond,
+	})
+
+	ctx := context.Background()
+	must.NoError(t, h.Poststart(ctx, &interfaces.TaskPoststartRequest{}, &interfaces.TaskPoststartResponse{}))
+	defer h.Stop(ctx, nil, nil)
+
+	select {
+	case ev := <-lifecycleHooks.KillCh:
+		must.True(t, ev.FailsTask)
+	case <-readyCh:
+		t.Fatal("unhealthy sidecar should not be ready")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for sidecar to be killed")
+	}
+}
+
+func TestReadinessHook_UnhealthyIgnore(t *testing.T) {
+	ci.Parallel(t)
+
+	h, _, readyCh := testReadinessHook(t, consulapi.HealthCritical, &structs.TaskLifecycleConfig{
+		Hook:            structs.TaskLifecycleHookPrestart,
+		Sidecar:         true,
+		WaitHealthy:     true,
+		HealthyTimeout:  50 * time.Millisecond,
+		UnhealthyAction: structs.TaskLifecycleUnhealthyActionIgnore,
+	})
+
+	ctx := context.Background()
+	must.NoError(t, h.Poststart(ctx, &interfaces.TaskPoststartRequest{}, &interfaces.TaskPoststartResponse{}))
+	defer h.Stop(ctx, nil, nil)
+
+	select {
+	case <-readyCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for sidecar to be marked ready")
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// resize applies changes to the cpu and memory resources of the task made by
+// an in-place update of the allocation. The scheduler only updates resources
+// in-place if the driver of the task supports resizing, so the new limits are
+// applied to the running task. If the task is not running they are used the
+// next time it starts.
+func (tr *TaskRunner) resize(update *structs.Allocation) {
+	if update.AllocatedResources == nil {
+		return
+	}
+	resources, ok := update.AllocatedResources.Tasks[tr.taskName]
+	if !ok {
+		return
+	}
+
+	current := tr.getTaskResources()
+	if !taskResized(current, resources) {
+		return
+	}
+	tr.setTaskResources(resources)
+
+	handle := tr.getDriverHandle()
+	if handle == nil {
+		return
+	}
+
+	ports := update.AllocatedResources.Shared.Ports
+	if err := handle.UpdateResources(tr.driverResources(resources, &ports)); err != nil {
+		tr.logger.Error("failed to resize task", "error", err)
+		tr.EmitEvent(structs.NewTaskEvent(structs.TaskResized).
+			SetMessage(fmt.Sprintf("Failed to update resources of the running task: %v", err)))
+		return
+	}
+
+	tr.logger.Debug("resized task", "cpu", resources.Cpu.CpuShares,
+		"memory", resources.Memory.MemoryMB, "memory_max", resources.Memory.MemoryMaxMB)
+	tr.EmitEvent(structs.NewTaskEvent(structs.TaskResized).
+		SetMessage(fmt.Sprintf("Task resized to %d MHz cpu and %d MB memory",
+			resources.Cpu.CpuShares, resources.Memory.MemoryMB)))
+}
+
+// taskResized returns true if the cpu or memory resources of a task differ.
+func taskResized(a, b *structs.AllocatedTaskResources) bool {
+	return a.Cpu.CpuShares != b.Cpu.CpuShares ||
+		a.Memory.MemoryMB != b.Memory.MemoryMB ||
+		a.Memory.MemoryMaxMB != b.Memory.MemoryMaxMB
+}
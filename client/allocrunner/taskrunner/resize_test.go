@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestTaskRunner_Resize(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.BatchAlloc()
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+	task.Driver = "mock_driver"
+	task.Config = map[string]interface{}{
+		"run_for": "10s",
+	}
+
+	tr, _, cleanup := runTestTaskRunner(t, alloc, task.Name)
+	defer cleanup()
+	testWaitForTaskToStart(t, tr)
+
+	update := alloc.Copy()
+	res := update.AllocatedResources.Tasks[task.Name]
+	res.Cpu.CpuShares = 1000
+	res.Memory.MemoryMB = 512
+	tr.Update(update)
+
+	must.Eq(t, 1000, tr.getTaskResources().Cpu.CpuShares)
+	must.Eq(t, 512, tr.getTaskResources().Memory.MemoryMB)
+
+	var resized *structs.TaskEvent
+	for _, e := range tr.TaskState().Events {
+		if e.Type == structs.TaskResized {
+			resized = e
+		}
+	}
+	must.NotNil(t, resized)
+	must.Eq(t, "Task resized to 1000 MHz cpu and 512 MB memory", resized.DisplayMessage)
+}
+
+func TestTaskRunner_taskResized(t *testing.T) {
+	ci.Parallel(t)
+
+	a := &structs.AllocatedTaskResources{
+		Cpu:    structs.AllocatedCpuResources{CpuShares: 500},
+		Memory: structs.AllocatedMemoryResources{MemoryMB: 256},
+	}
+	must.False(t, taskResized(a, a.Copy()))
+
+	b := a.Copy()
+	b.Cpu.CpuShares = 1000
+	must.True(t, taskResized(a, b))
+
+	b = a.Copy()
+	b.Memory.MemoryMaxMB = 512
+	must.True(t, taskResized(a, b))
+
+	// Changes to other resources are not resizes
+	b = a.Copy()
+	b.Networks = structs.Networks{{Mode: "host"}}
+	must.False(t, taskResized(a, b))
+}
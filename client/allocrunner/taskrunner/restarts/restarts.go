@@ -14,13 +14,13 @@ import (
 )
 
 const (
-	// jitter is the percent of jitter added to restart delays.
-	jitter = 0.25
-
 	ReasonNoRestartsAllowed  = "Policy allows no restarts"
 	ReasonUnrecoverableError = "Error was unrecoverable"
 	ReasonWithinPolicy       = "Restart within policy"
 	ReasonDelay              = "Exceeded allowed attempts, applying a delay"
+	ReasonBudgetDelay        = "Exceeded restart budget, applying a delay"
+	ReasonOOMFail            = `Task was OOM killed and OOM policy mode is "fail"`
+	ReasonOOMIgnored         = "Task was OOM killed, restart not counted against policy"
 )
 
 func NewRestartTracker(policy *structs.RestartPolicy, jobType string, tlc *structs.TaskLifecycleConfig) *RestartTracker {
@@ -67,6 +67,14 @@ type RestartTracker struct {
 	policy           *structs.RestartPolicy
 	rand             *rand.Rand
 	lock             sync.Mutex
+
+	// restarts are the times of restarts counted against the policy's
+	// budget, oldest first.
+	restarts []time.Time
+
+	// oomMode is the OOM policy mode to apply if the last exit was caused by
+	// the OOM killer.
+	oomMode string
 }
 
 // SetPolicy updates the policy used to determine restarts.
@@ -117,6 +125,15 @@ func (r *RestartTracker) SetRestartTriggered(failure bool) *RestartTracker {
 	return r
 }
 
+// SetOOMKilled is used to mark that the most recent exit was caused by the OOM
+// killer, and the mode of the task's OOM policy.
+func (r *RestartTracker) SetOOMKilled(mode string) *RestartTracker {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.oomMode = mode
+	return r
+}
+
 // SetKilled is used to mark that the task has been killed.
 func (r *RestartTracker) SetKilled() *RestartTracker {
 	r.lock.Lock()
@@ -161,6 +178,7 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 		r.restartTriggered = false
 		r.failure = false
 		r.killed = false
+		r.oomMode = ""
 	}()
 
 	// Hot path if task was killed
@@ -175,6 +193,17 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 		return structs.TaskRestarting, 0
 	}
 
+	// Handle exits caused by the OOM killer whose OOM policy overrides the
+	// restart policy.
+	switch r.oomMode {
+	case structs.OOMPolicyModeFail:
+		r.reason = ReasonOOMFail
+		return structs.TaskNotRestarting, 0
+	case structs.OOMPolicyModeIgnore:
+		r.reason = ReasonOOMIgnored
+		return structs.TaskRestarting, r.jitter()
+	}
+
 	// Hot path if no attempts are expected
 	if r.policy.Attempts == 0 {
 		r.reason = ReasonNoRestartsAllowed
@@ -223,20 +252,60 @@ func (r *RestartTracker) GetState() (string, time.Duration) {
 	// If this task has been restarted due to failures more times
 	// than the restart policy allows within an interval fail
 	// according to the restart policy's mode.
+	var delay time.Duration
 	if r.count > r.policy.Attempts {
 		if r.policy.Mode == structs.RestartPolicyModeFail {
 			r.reason = fmt.Sprintf(
 				`Exceeded allowed attempts %d in interval %v and mode is "fail"`,
 				r.policy.Attempts, r.policy.Interval)
 			return structs.TaskNotRestarting, 0
-		} else {
-			r.reason = ReasonDelay
-			return structs.TaskRestarting, r.getDelay()
+		}
+		r.reason = ReasonDelay
+		delay = r.getDelay()
+	} else {
+		r.reason = ReasonWithinPolicy
+		delay = r.jitter()
+	}
+
+	return r.applyBudget(now, delay)
+}
+
+// applyBudget enforces the restart budget over its sliding window. If the
+// budget is exhausted the task either fails or is delayed until the oldest
+// restart leaves the window, according to the restart policy's mode.
+func (r *RestartTracker) applyBudget(now time.Time, delay time.Duration) (string, time.Duration) {
+	if r.policy.Budget <= 0 {
+		r.restarts = nil
+		return structs.TaskRestarting, delay
+	}
+
+	// Drop restarts that have left the window.
+	window := r.policy.BudgetWindow
+	i := 0
+	for i < len(r.restarts) && !r.restarts[i].Add(window).After(now) {
+		i++
+	}
+	r.restarts = r.restarts[i:]
+
+	if len(r.restarts) >= r.policy.Budget {
+		if r.policy.Mode == structs.RestartPolicyModeFail {
+			r.reason = fmt.Sprintf(
+				`Exceeded restart budget %d in window %v and mode is "fail"`,
+				r.policy.Budget, window)
+			return structs.TaskNotRestarting, 0
+		}
+
+		// Only the oldest restarts beyond the budget need to leave the
+		// window before another restart fits within it.
+		oldest := r.restarts[len(r.restarts)-r.policy.Budget]
+		if wait := oldest.Add(window).Sub(now); wait > delay {
+			r.reason = ReasonBudgetDelay
+			delay = wait
 		}
 	}
 
-	r.reason = ReasonWithinPolicy
-	return structs.TaskRestarting, r.jitter()
+	r.restarts = append(r.restarts, now.Add(delay))
+	return structs.TaskRestarting, delay
 }
 
 // getDelay returns the delay time to enter the next interval.
@@ -246,14 +315,38 @@ func (r *RestartTracker) getDelay() time.Duration {
 	return end.Sub(now)
 }
 
+// backoff returns the delay before the current restart attempt. With the
+// exponential delay function the delay is multiplied for every previous
+// restart in the interval, up to the max delay or the interval.
+func (r *RestartTracker) backoff() time.Duration {
+	d := r.policy.Delay
+	if r.policy.DelayFunction != structs.RestartPolicyDelayFunctionExponential {
+		return d
+	}
+
+	multiplier := r.policy.DelayMultiplier
+	if multiplier == 0 {
+		multiplier = structs.RestartPolicyDefaultDelayMultiplier
+	}
+	ceiling := r.policy.MaxDelay
+	if ceiling == 0 {
+		ceiling = r.policy.Interval
+	}
+
+	for i := 1; i < r.count && d < ceiling; i++ {
+		d = time.Duration(float64(d) * multiplier)
+	}
+	return min(d, ceiling)
+}
+
 // jitter returns the delay time plus a jitter.
 func (r *RestartTracker) jitter() time.Duration {
 	// Get the delay and ensure it is valid.
-	d := r.policy.Delay.Nanoseconds()
-	if d == 0 {
+	d := r.backoff().Nanoseconds()
+	if d <= 0 {
 		d = 1
 	}
 
-	j := float64(r.rand.Int63n(d)) * jitter
+	j := float64(r.rand.Int63n(d)) * r.policy.GetJitter()
 	return time.Duration(d + int64(j))
 }
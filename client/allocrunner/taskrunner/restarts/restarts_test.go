@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/plugins/drivers"
 	"github.com/stretchr/testify/require"
@@ -27,7 +28,7 @@ func testPolicy(success bool, mode string) *structs.RestartPolicy {
 // the jitter.
 func withinJitter(expected, actual time.Duration) bool {
 	return float64((actual.Nanoseconds()-expected.Nanoseconds())/
-		expected.Nanoseconds()) <= jitter
+		expected.Nanoseconds()) <= structs.RestartPolicyDefaultJitter
 }
 
 func testExitResult(exit int) *drivers.ExitResult {
@@ -203,6 +204,138 @@ func TestClient_RestartTracker_StartError_Recoverable_Delay(t *testing.T) {
 	}
 }
 
+func TestClient_RestartTracker_ExponentialDelay(t *testing.T) {
+	ci.Parallel(t)
+	p := testPolicy(true, structs.RestartPolicyModeFail)
+	p.Attempts = 6
+	p.DelayFunction = structs.RestartPolicyDelayFunctionExponential
+	p.MaxDelay = 10 * time.Second
+	p.Jitter = pointer.Of(0.0)
+	rt := NewRestartTracker(p, structs.JobTypeService, nil)
+
+	expected := []time.Duration{
+		1 * time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		10 * time.Second,
+		10 * time.Second,
+	}
+	for _, exp := range expected {
+		state, when := rt.SetExitResult(testExitResult(127)).GetState()
+		require.Equal(t, structs.TaskRestarting, state)
+		require.Equal(t, exp, when)
+	}
+
+	// A custom multiplier is honored and the delay is bounded by the interval
+	// when no max delay is set.
+	p = testPolicy(true, structs.RestartPolicyModeFail)
+	p.DelayFunction = structs.RestartPolicyDelayFunctionExponential
+	p.DelayMultiplier = 50
+	p.Jitter = pointer.Of(0.0)
+	rt = NewRestartTracker(p, structs.JobTypeService, nil)
+
+	expected = []time.Duration{1 * time.Second, 50 * time.Second, p.Interval}
+	for _, exp := range expected {
+		state, when := rt.SetExitResult(testExitResult(127)).GetState()
+		require.Equal(t, structs.TaskRestarting, state)
+		require.Equal(t, exp, when)
+	}
+}
+
+func TestClient_RestartTracker_Jitter(t *testing.T) {
+	ci.Parallel(t)
+	p := testPolicy(true, structs.RestartPolicyModeFail)
+	p.Jitter = pointer.Of(1.0)
+	rt := NewRestartTracker(p, structs.JobTypeService, nil)
+
+	for i := 0; i < p.Attempts; i++ {
+		state, when := rt.SetExitResult(testExitResult(127)).GetState()
+		require.Equal(t, structs.TaskRestarting, state)
+		require.GreaterOrEqual(t, when, p.Delay)
+		require.Less(t, when, 2*p.Delay)
+	}
+}
+
+func TestClient_RestartTracker_Budget_Fail(t *testing.T) {
+	ci.Parallel(t)
+	p := testPolicy(true, structs.RestartPolicyModeFail)
+	p.Attempts = 10
+	p.Budget = 2
+	p.BudgetWindow = time.Hour
+	rt := NewRestartTracker(p, structs.JobTypeService, nil)
+
+	for i := 0; i < p.Budget; i++ {
+		state, _ := rt.SetExitResult(testExitResult(127)).GetState()
+		require.Equal(t, structs.TaskRestarting, state)
+	}
+
+	state, _ := rt.SetExitResult(testExitResult(127)).GetState()
+	require.Equal(t, structs.TaskNotRestarting, state)
+	require.Contains(t, rt.GetReason(), "Exceeded restart budget 2")
+}
+
+func TestClient_RestartTracker_Budget_Delay(t *testing.T) {
+	ci.Parallel(t)
+	p := testPolicy(true, structs.RestartPolicyModeDelay)
+	p.Attempts = 10
+	p.Budget = 2
+	p.BudgetWindow = time.Hour
+	rt := NewRestartTracker(p, structs.JobTypeService, nil)
+
+	for i := 0; i < p.Budget; i++ {
+		state, when := rt.SetExitResult(testExitResult(127)).GetState()
+		require.Equal(t, structs.TaskRestarting, state)
+		require.True(t, withinJitter(p.Delay, when))
+	}
+
+	// The next restart waits for the oldest restart to leave the window.
+	state, when := rt.SetExitResult(testExitResult(127)).GetState()
+	require.Equal(t, structs.TaskRestarting, state)
+	require.Equal(t, ReasonBudgetDelay, rt.GetReason())
+	require.Greater(t, when, p.BudgetWindow-time.Minute)
+	require.LessOrEqual(t, when, p.BudgetWindow+2*p.Delay)
+
+	// Restarts that have left the window no longer count.
+	rt.lock.Lock()
+	for i := range rt.restarts {
+		rt.restarts[i] = rt.restarts[i].Add(-2 * p.BudgetWindow)
+	}
+	rt.lock.Unlock()
+
+	state, when = rt.SetExitResult(testExitResult(127)).GetState()
+	require.Equal(t, structs.TaskRestarting, state)
+	require.Equal(t, ReasonWithinPolicy, rt.GetReason())
+	require.True(t, withinJitter(p.Delay, when))
+}
+
+func TestClient_RestartTracker_OOMPolicy(t *testing.T) {
+	ci.Parallel(t)
+
+	// Fail mode fails the task even though restarts remain.
+	p := testPolicy(true, structs.RestartPolicyModeDelay)
+	rt := NewRestartTracker(p, structs.JobTypeService, nil)
+	state, _ := rt.SetExitResult(testExitResult(137)).SetOOMKilled(structs.OOMPolicyModeFail).GetState()
+	require.Equal(t, structs.TaskNotRestarting, state)
+	require.Equal(t, ReasonOOMFail, rt.GetReason())
+
+	// Ignore mode restarts without using up attempts.
+	rt = NewRestartTracker(p, structs.JobTypeService, nil)
+	for i := 0; i < p.Attempts*2; i++ {
+		state, when := rt.SetExitResult(testExitResult(137)).SetOOMKilled(structs.OOMPolicyModeIgnore).GetState()
+		require.Equal(t, structs.TaskRestarting, state)
+		require.Equal(t, ReasonOOMIgnored, rt.GetReason())
+		require.True(t, withinJitter(p.Delay, when))
+	}
+	require.Zero(t, rt.GetCount())
+
+	// Restart mode and later exits follow the restart policy.
+	state, _ = rt.SetExitResult(testExitResult(137)).SetOOMKilled(structs.OOMPolicyModeRestart).GetState()
+	require.Equal(t, structs.TaskRestarting, state)
+	require.Equal(t, ReasonWithinPolicy, rt.GetReason())
+	require.Equal(t, 1, rt.GetCount())
+}
+
 func TestClient_RestartTracker_Lifecycle(t *testing.T) {
 	ci.Parallel(t)
 
@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	tinterfaces "github.com/hashicorp/nomad/client/allocrunner/taskrunner/interfaces"
 	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/client/serviceregistration/checks"
 	"github.com/hashicorp/nomad/client/taskenv"
 	agentconsul "github.com/hashicorp/nomad/command/agent/consul"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -189,6 +190,10 @@ func (h *scriptCheckHook) newScriptChecks() map[string]*scriptCheck {
 	scriptChecks := make(map[string]*scriptCheck)
 	interpolatedTaskServices := taskenv.InterpolateServices(h.taskEnv, h.task.Services)
 	for _, service := range interpolatedTaskServices {
+		// script checks of Nomad services are run by the alloc checks hook
+		if service.Provider == structs.ServiceProviderNomad {
+			continue
+		}
 		for _, check := range service.Checks {
 			if check.Type != structs.ServiceCheckScript {
 				continue
@@ -225,6 +230,9 @@ func (h *scriptCheckHook) newScriptChecks() map[string]*scriptCheck {
 	tg := h.alloc.Job.LookupTaskGroup(h.alloc.TaskGroup)
 	interpolatedGroupServices := taskenv.InterpolateServices(h.taskEnv, tg.Services)
 	for _, service := range interpolatedGroupServices {
+		if service.Provider == structs.ServiceProviderNomad {
+			continue
+		}
 		for _, check := range service.Checks {
 			if check.Type != structs.ServiceCheckScript {
 				continue
@@ -327,6 +335,9 @@ func newScriptCheck(config *scriptCheckConfig) *scriptCheck {
 	sc.Interval = config.check.Interval
 	sc.Timeout = config.check.Timeout
 	sc.exec = config.driverExec
+	if config.check.UseContainerHealth {
+		sc.exec = checks.NewContainerHealthExecutor(config.driverExec)
+	}
 	sc.callback = newScriptCheckCallback(sc)
 	sc.logger = config.logger
 	sc.shutdownCh = config.shutdownCh
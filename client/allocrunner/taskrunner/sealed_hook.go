@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	log "github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/users"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// sealedHook unseals the sealed values of the task with the servers and
+// writes them to files in the task's secrets directory. The cleartext values
+// are only ever stored there.
+type sealedHook struct {
+	alloc      *structs.Allocation
+	rpc        config.RPCer
+	node       *structs.Node
+	secretsDir string
+
+	logger log.Logger
+}
+
+func newSealedHook(tr *TaskRunner, logger log.Logger) *sealedHook {
+	h := &sealedHook{
+		alloc:      tr.Alloc(),
+		rpc:        tr.rpcClient,
+		node:       tr.clientConfig.Node,
+		secretsDir: tr.taskDir.SecretsDir,
+	}
+	h.logger = logger.Named(h.Name())
+	return h
+}
+
+func (*sealedHook) Name() string {
+	return "sealed"
+}
+
+func (h *sealedHook) Prestart(ctx context.Context, req *interfaces.TaskPrestartRequest, resp *interfaces.TaskPrestartResponse) error {
+	if len(req.Task.Sealed) == 0 {
+		resp.Done = true
+		return nil
+	}
+
+	args := structs.KeyringUnsealRequest{
+		AllocID: h.alloc.ID,
+		Task:    req.Task.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    h.alloc.Job.Region,
+			AuthToken: h.node.SecretID,
+		},
+	}
+	var reply structs.KeyringUnsealResponse
+	if err := h.rpc.RPC("Keyring.Unseal", &args, &reply); err != nil {
+		return fmt.Errorf("failed to unseal sealed values: %w", err)
+	}
+
+	// The directory is only accessible to the task user, or to the client if
+	// the task has no user. Windows has no such file modes and users.
+	dir := filepath.Join(h.secretsDir, structs.SealedDir)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create sealed values directory: %w", err)
+	}
+	if req.Task.User != "" && runtime.GOOS != "windows" {
+		uid, _, _, err := users.LookupUnix(req.Task.User)
+		if err != nil {
+			return fmt.Errorf("failed to look up task user: %w", err)
+		}
+		if err := os.Chown(dir, uid, -1); err != nil {
+			return fmt.Errorf("failed to set owner of sealed values directory: %w", err)
+		}
+	}
+	for name, value := range reply.Values {
+		if err := users.WriteFileFor(filepath.Join(dir, name), []byte(value), req.Task.User); err != nil {
+			return fmt.Errorf("failed to write sealed value %q: %w", name, err)
+		}
+	}
+
+	h.logger.Trace("sealed values written", "path", dir, "count", len(reply.Values))
+
+	// Sealed values written successfully; mark as done
+	resp.Done = true
+	return nil
+}
@@ -66,6 +66,8 @@ func (h *taskDirHook) Prestart(ctx context.Context, req *interfaces.TaskPrestart
 	h.runner.EmitEvent(structs.NewTaskEvent(structs.TaskSetup).SetMessage(structs.TaskBuildingTaskDir))
 
 	// Build the task directory structure
+	h.runner.taskDir.EncryptSecrets = h.runner.clientConfig.EncryptSecretsDir
+	h.runner.taskDir.SetDriver(req.Task.Driver)
 	err := h.runner.taskDir.Build(fsi, chroot, req.Task.User)
 	if err != nil {
 		return err
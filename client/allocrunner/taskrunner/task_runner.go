@@ -29,6 +29,8 @@ import (
 	"github.com/hashicorp/nomad/client/pluginmanager/csimanager"
 	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
 	"github.com/hashicorp/nomad/client/serviceregistration"
+	"github.com/hashicorp/nomad/client/serviceregistration/checks"
+	"github.com/hashicorp/nomad/client/serviceregistration/checks/checkstore"
 	"github.com/hashicorp/nomad/client/serviceregistration/wrapper"
 	cstate "github.com/hashicorp/nomad/client/state"
 	cstructs "github.com/hashicorp/nomad/client/structs"
@@ -75,12 +77,17 @@ const (
 )
 
 type TaskRunner struct {
-	// allocID, taskName, taskLeader, and taskResources are immutable so these fields may
+	// allocID, taskName and taskLeader are immutable so these fields may
 	// be accessed without locks
-	allocID       string
-	taskName      string
-	taskLeader    bool
-	taskResources *structs.AllocatedTaskResources
+	allocID    string
+	taskName   string
+	taskLeader bool
+
+	// taskResources are the resources allocated to the task. They change
+	// when the task is resized by an in-place update.
+	// Must acquire taskResourcesLock to access.
+	taskResources     *structs.AllocatedTaskResources
+	taskResourcesLock sync.RWMutex
 
 	alloc     *structs.Allocation
 	allocLock sync.Mutex
@@ -222,6 +229,12 @@ type TaskRunner struct {
 	resourceUsage     *cstructs.TaskResourceUsage
 	resourceUsageLock sync.Mutex
 
+	// oomKills is the OOM kill counter of the task's cgroup when the task was
+	// last started, and oomMemoryMB is the memory limit raised by an OOM
+	// policy in "adjust" mode. Both are only accessed from the Run loop.
+	oomKills    uint64
+	oomMemoryMB int64
+
 	// deviceStatsReporter is used to lookup resource usage for alloc devices
 	deviceStatsReporter cinterfaces.DeviceStatsReporter
 
@@ -250,6 +263,13 @@ type TaskRunner struct {
 	// startConditionMetCh signals the TaskRunner when it should start the task
 	startConditionMetCh <-chan struct{}
 
+	// taskReadyFunc is called when a prestart sidecar that gates the main
+	// tasks on its health becomes ready.
+	taskReadyFunc func()
+
+	// checkStore is used to lookup the status of Nomad service checks
+	checkStore checkstore.Shim
+
 	// waitOnServers defaults to false but will be set true if a restore
 	// fails and the Run method should wait until serversContactedCh is
 	// closed.
@@ -274,6 +294,10 @@ type TaskRunner struct {
 
 	// users manages the pool of dynamic workload users
 	users dynamic.Pool
+
+	// rpcClient is used to make RPCs to the servers, such as unsealing the
+	// task's sealed values
+	rpcClient config.RPCer
 }
 
 type Config struct {
@@ -326,6 +350,13 @@ type Config struct {
 	// StartConditionMetCh signals the TaskRunner when it should start the task
 	StartConditionMetCh <-chan struct{}
 
+	// TaskReadyFunc is called when a prestart sidecar that gates the main
+	// tasks on its health becomes ready.
+	TaskReadyFunc func()
+
+	// CheckStore is used to lookup the status of Nomad service checks
+	CheckStore checkstore.Shim
+
 	// ShutdownDelayCtx is a context from the alloc runner which will
 	// tell us to exit early from shutdown_delay
 	ShutdownDelayCtx context.Context
@@ -352,6 +383,9 @@ type Config struct {
 
 	// Users manages a pool of dynamic workload users
 	Users dynamic.Pool
+
+	// RPCClient is used to make RPCs to the servers
+	RPCClient config.RPCer
 }
 
 func NewTaskRunner(config *Config) (*TaskRunner, error) {
@@ -408,6 +442,8 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 		maxEvents:               defaultMaxEvents,
 		serversContactedCh:      config.ServersContactedCh,
 		startConditionMetCh:     config.StartConditionMetCh,
+		taskReadyFunc:           config.TaskReadyFunc,
+		checkStore:              config.CheckStore,
 		shutdownDelayCtx:        config.ShutdownDelayCtx,
 		shutdownDelayCancelFn:   config.ShutdownDelayCancelFn,
 		serviceRegWrapper:       config.ServiceRegWrapper,
@@ -415,6 +451,7 @@ func NewTaskRunner(config *Config) (*TaskRunner, error) {
 		wranglers:               config.Wranglers,
 		widmgr:                  config.WIDMgr,
 		users:                   config.Users,
+		rpcClient:               config.RPCClient,
 	}
 
 	// Create the logger based on the allocation ID
@@ -799,9 +836,15 @@ func (tr *TaskRunner) handleTaskExitResult(result *drivers.ExitResult) (retryWai
 		return true
 	}
 
+	kills := tr.detectOOMKills(result)
+
 	// Emit Terminated event
 	tr.emitExitResultEvent(result)
 
+	if result.OOMKilled {
+		tr.handleOOM(kills)
+	}
+
 	return false
 }
 
@@ -864,7 +907,7 @@ func (tr *TaskRunner) shouldRestart() (bool, time.Duration) {
 }
 
 func (tr *TaskRunner) assignCgroup(taskConfig *drivers.TaskConfig) {
-	reserveCores := len(tr.taskResources.Cpu.ReservedCores) > 0
+	reserveCores := len(tr.getTaskResources().Cpu.ReservedCores) > 0
 	p := cgroupslib.LinuxResourcesPath(taskConfig.AllocID, taskConfig.Name, reserveCores)
 	taskConfig.Resources.LinuxResources.CpusetCgroupPath = p
 }
@@ -874,6 +917,7 @@ func (tr *TaskRunner) assignCgroup(taskConfig *drivers.TaskConfig) {
 func (tr *TaskRunner) runDriver() error {
 	taskConfig := tr.buildTaskConfig()
 	tr.assignCgroup(taskConfig)
+	tr.recordOOMKills()
 
 	// Build hcl context variables
 	vars, errs, err := tr.envBuilder.Build().AllValues()
@@ -1126,7 +1170,7 @@ func (tr *TaskRunner) buildTaskConfig() *drivers.TaskConfig {
 	task := tr.Task()
 	alloc := tr.Alloc()
 	invocationid := uuid.Short()
-	taskResources := tr.taskResources
+	taskResources := tr.oomAdjustedResources()
 	ports := tr.Alloc().AllocatedResources.Shared.Ports
 	env := tr.envBuilder.Build()
 	tr.networkIsolationLock.Lock()
@@ -1157,36 +1201,17 @@ func (tr *TaskRunner) buildTaskConfig() *drivers.TaskConfig {
 		}
 	}
 
-	memoryLimit := taskResources.Memory.MemoryMB
-	if max := taskResources.Memory.MemoryMaxMB; max > memoryLimit {
-		memoryLimit = max
-	}
-
-	cpusetCpus := make([]string, len(taskResources.Cpu.ReservedCores))
-	for i, v := range taskResources.Cpu.ReservedCores {
-		cpusetCpus[i] = fmt.Sprintf("%d", v)
-	}
-
 	return &drivers.TaskConfig{
-		ID:            fmt.Sprintf("%s/%s/%s", alloc.ID, task.Name, invocationid),
-		Name:          task.Name,
-		JobName:       alloc.Job.Name,
-		JobID:         alloc.Job.ID,
-		TaskGroupName: alloc.TaskGroup,
-		Namespace:     alloc.Namespace,
-		NodeName:      alloc.NodeName,
-		NodeID:        alloc.NodeID,
-		ParentJobID:   alloc.Job.ParentID,
-		Resources: &drivers.Resources{
-			NomadResources: taskResources,
-			LinuxResources: &drivers.LinuxResources{
-				MemoryLimitBytes: memoryLimit * 1024 * 1024,
-				CPUShares:        taskResources.Cpu.CpuShares,
-				CpusetCpus:       strings.Join(cpusetCpus, ","),
-				PercentTicks:     float64(taskResources.Cpu.CpuShares) / float64(tr.clientConfig.Node.NodeResources.Processors.Topology.UsableCompute()),
-			},
-			Ports: &ports,
-		},
+		ID:               fmt.Sprintf("%s/%s/%s", alloc.ID, task.Name, invocationid),
+		Name:             task.Name,
+		JobName:          alloc.Job.Name,
+		JobID:            alloc.Job.ID,
+		TaskGroupName:    alloc.TaskGroup,
+		Namespace:        alloc.Namespace,
+		NodeName:         alloc.NodeName,
+		NodeID:           alloc.NodeID,
+		ParentJobID:      alloc.Job.ParentID,
+		Resources:        tr.driverResources(taskResources, &ports),
 		Devices:          tr.hookResources.getDevices(),
 		Mounts:           tr.hookResources.getMounts(),
 		Env:              env.Map(),
@@ -1201,6 +1226,33 @@ func (tr *TaskRunner) buildTaskConfig() *drivers.TaskConfig {
 	}
 }
 
+// driverResources returns the resources of the task in the form passed to the
+// driver.
+func (tr *TaskRunner) driverResources(taskResources *structs.AllocatedTaskResources,
+	ports *structs.AllocatedPorts) *drivers.Resources {
+
+	memoryLimit := taskResources.Memory.MemoryMB
+	if max := taskResources.Memory.MemoryMaxMB; max > memoryLimit {
+		memoryLimit = max
+	}
+
+	cpusetCpus := make([]string, len(taskResources.Cpu.ReservedCores))
+	for i, v := range taskResources.Cpu.ReservedCores {
+		cpusetCpus[i] = fmt.Sprintf("%d", v)
+	}
+
+	return &drivers.Resources{
+		NomadResources: taskResources,
+		LinuxResources: &drivers.LinuxResources{
+			MemoryLimitBytes: memoryLimit * 1024 * 1024,
+			CPUShares:        taskResources.Cpu.CpuShares,
+			CpusetCpus:       strings.Join(cpusetCpus, ","),
+			PercentTicks:     float64(taskResources.Cpu.CpuShares) / float64(tr.clientConfig.Node.NodeResources.Processors.Topology.UsableCompute()),
+		},
+		Ports: ports,
+	}
+}
+
 // Restore task runner state. Called by AllocRunner.Restore after NewTaskRunner
 // but before Run so no locks need to be acquired.
 func (tr *TaskRunner) Restore() error {
@@ -1444,6 +1496,7 @@ func (tr *TaskRunner) Update(update *structs.Allocation) {
 
 	// Trigger update hooks if not terminal
 	if !update.TerminalStatus() {
+		tr.resize(update)
 		tr.triggerUpdateHooks()
 	}
 }
@@ -1494,7 +1547,7 @@ func (tr *TaskRunner) LatestResourceUsage() *cstructs.TaskResourceUsage {
 
 	// Look up device statistics lazily when fetched, as currently we do not emit any stats for them yet
 	if ru != nil && tr.deviceStatsReporter != nil {
-		deviceResources := tr.taskResources.Devices
+		deviceResources := tr.getTaskResources().Devices
 		ru.ResourceUsage.DeviceStats = tr.deviceStatsReporter.LatestDeviceResourceStats(deviceResources)
 	}
 	return ru
@@ -1623,6 +1676,16 @@ func (tr *TaskRunner) TaskExecHandler() drivermanager.TaskExecHandler {
 	return handle.ExecStreaming
 }
 
+// ScriptExecutor returns the executor running the commands of the script
+// checks of Nomad services in the task, or nil if the task isn't running.
+func (tr *TaskRunner) ScriptExecutor() checks.ScriptExecutor {
+	handle := tr.getDriverHandle()
+	if handle == nil {
+		return nil
+	}
+	return handle
+}
+
 func (tr *TaskRunner) DriverCapabilities() (*drivers.Capabilities, error) {
 	return tr.driver.Capabilities()
 }
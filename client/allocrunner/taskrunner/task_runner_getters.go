@@ -47,6 +47,18 @@ func (tr *TaskRunner) Task() *structs.Task {
 	return tr.task
 }
 
+func (tr *TaskRunner) getTaskResources() *structs.AllocatedTaskResources {
+	tr.taskResourcesLock.RLock()
+	defer tr.taskResourcesLock.RUnlock()
+	return tr.taskResources
+}
+
+func (tr *TaskRunner) setTaskResources(resources *structs.AllocatedTaskResources) {
+	tr.taskResourcesLock.Lock()
+	defer tr.taskResourcesLock.Unlock()
+	tr.taskResources = resources
+}
+
 func (tr *TaskRunner) TaskState() *structs.TaskState {
 	tr.stateLock.Lock()
 	defer tr.stateLock.Unlock()
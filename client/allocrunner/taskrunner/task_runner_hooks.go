@@ -68,8 +68,10 @@ func (tr *TaskRunner) initHooks() {
 		newIdentityHook(tr, hookLogger),
 		newLogMonHook(tr, hookLogger),
 		newDispatchHook(alloc, hookLogger),
+		newSealedHook(tr, hookLogger),
+		newAllocMetadataHook(tr.clientConfig.Node, tr.clientConfig.AllocMetadataNodeAttributes, task.Name, hookLogger),
 		newVolumeHook(tr, hookLogger),
-		newArtifactHook(tr, tr.getter, hookLogger),
+		newArtifactHook(tr, tr.getter, tr.widmgr, hookLogger),
 		newStatsHook(tr, tr.clientConfig.StatsCollectionInterval, hookLogger),
 		newDeviceHook(tr.devicemanager, hookLogger),
 		newAPIHook(tr.shutdownCtx, tr.clientConfig.APIListenerRegistrar, hookLogger),
@@ -174,6 +176,10 @@ func (tr *TaskRunner) initHooks() {
 		}
 	}
 
+	// Always add the migrate hook. The migrate hooks of the ephemeral disk
+	// may be added by an in-place update before the task is stopped.
+	tr.runnerHooks = append(tr.runnerHooks, newMigrateHook(tr.Alloc, task.Name, tr, hookLogger))
+
 	// Always add the script checks hook. A task with no script check hook on
 	// initial registration may be updated to include script checks, which must
 	// be handled with this hook.
@@ -184,6 +190,21 @@ func (tr *TaskRunner) initHooks() {
 		logger: hookLogger,
 	}))
 
+	// If this is a prestart sidecar the main tasks wait on to be healthy, add
+	// the readiness hook.
+	if task.IsPrestart() && task.Lifecycle.Sidecar && task.Lifecycle.WaitsHealthy() && tr.taskReadyFunc != nil {
+		tr.runnerHooks = append(tr.runnerHooks, newReadinessHook(readinessHookConfig{
+			alloc:      tr.Alloc(),
+			task:       tr.Task(),
+			consul:     tr.consulServiceClient,
+			checkStore: tr.checkStore,
+			lifecycle:  tr,
+			events:     tr,
+			readyFn:    tr.taskReadyFunc,
+			logger:     hookLogger,
+		}))
+	}
+
 	// If this task driver has remote capabilities, add the remote task
 	// hook.
 	if tr.driverCapabilities.RemoteTasks {
@@ -242,7 +263,7 @@ func (tr *TaskRunner) prestart() error {
 			Task:          tr.Task(),
 			TaskDir:       tr.taskDir,
 			TaskEnv:       tr.envBuilder.Build(),
-			TaskResources: tr.taskResources,
+			TaskResources: tr.getTaskResources(),
 		}
 
 		origHookState := tr.hookState(name)
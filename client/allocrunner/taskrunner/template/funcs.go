@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package template
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/consul-template/dependency"
+	"github.com/hashicorp/nomad/api"
+)
+
+// extFuncMap returns the template functions Nomad adds to the consul-template
+// functions.
+func extFuncMap(config *TaskTemplateManagerConfig) map[string]interface{} {
+	return map[string]interface{}{
+		"nomadVarTree": nomadVarTreeFunc(config),
+	}
+}
+
+// nomadVarTreeFunc returns the nomadVarTree template function. It reads the
+// items of all the variables listed by nomadVarList, so an entire subtree of
+// variables can be rendered at once:
+//
+//	{{ range $path, $items := nomadVarList "app/config" | nomadVarTree }}
+//
+// The list is a watched dependency of the template, so the template is
+// re-rendered when any variable of the subtree changes.
+func nomadVarTreeFunc(config *TaskTemplateManagerConfig) func([]*dependency.NomadVarMeta) (map[string]map[string]string, error) {
+	client, clientErr := newTemplateNomadClient(config)
+
+	return func(metas []*dependency.NomadVarMeta) (map[string]map[string]string, error) {
+		if clientErr != nil {
+			return nil, clientErr
+		}
+
+		tree := make(map[string]map[string]string, len(metas))
+		for _, meta := range metas {
+			v, _, err := client.Variables().Peek(meta.Path, &api.QueryOptions{Namespace: meta.Namespace})
+			if err != nil {
+				return nil, fmt.Errorf("failed to read variable %q: %w", meta.Path, err)
+			}
+			if v == nil {
+				// deleted since it was listed; the list will be updated
+				continue
+			}
+			tree[meta.Path] = v.Items
+		}
+		return tree, nil
+	}
+}
+
+// newTemplateNomadClient returns a Nomad API client connected to the agent in
+// the same way as the consul-template Nomad client.
+func newTemplateNomadClient(config *TaskTemplateManagerConfig) (*api.Client, error) {
+	conf := api.DefaultConfig()
+	conf.Namespace = config.NomadNamespace
+	conf.SecretID = config.NomadToken
+	if dialer := config.ClientConfig.TemplateDialer; dialer != nil {
+		conf.HttpClient = &http.Client{
+			Transport: &http.Transport{
+				DialContext: dialer.DialContext,
+			},
+		}
+	}
+	return api.NewClient(conf)
+}
@@ -4,7 +4,9 @@
 package template
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -28,6 +30,7 @@ import (
 	trenderer "github.com/hashicorp/nomad/client/allocrunner/taskrunner/template/renderer"
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/taskenv"
+	hargs "github.com/hashicorp/nomad/helper/args"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/subproc"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -38,6 +41,14 @@ const (
 	// consulTemplateSourceName is the source name when using the TaskHooks.
 	consulTemplateSourceName = "Template"
 
+	// TemplateDestinationEnv is interpolated in the arguments of a change
+	// script with the path of the re-rendered template.
+	TemplateDestinationEnv = "NOMAD_TEMPLATE_DESTINATION"
+
+	// TemplateChangedKeysEnv is interpolated in the arguments of a change
+	// script with the comma separated keys changed by the re-render.
+	TemplateChangedKeysEnv = "NOMAD_TEMPLATE_CHANGED_KEYS"
+
 	// missingDepEventLimit is the number of missing dependencies that will be
 	// logged before we switch to showing just the number of missing
 	// dependencies.
@@ -72,6 +83,11 @@ type TaskTemplateManager struct {
 	// actual signal
 	signals map[string]os.Signal
 
+	// renderedKeys are the keys and values last rendered by the templates with
+	// change_mode script, used to pass the changed keys to the change scripts.
+	// Only accessed by handleTemplateRerenders.
+	renderedKeys map[*structs.Template]map[string]string
+
 	// shutdownCh is used to signal and started goroutine to shutdown
 	shutdownCh chan struct{}
 
@@ -412,7 +428,9 @@ WAIT:
 			}
 
 			missingStr := strings.Join(missingSlice, ", ")
-			tm.config.Events.EmitEvent(structs.NewTaskEvent(consulTemplateSourceName).SetDisplayMessage(fmt.Sprintf("Missing: %s", missingStr)))
+			tm.config.Events.EmitEvent(structs.NewTaskEvent(consulTemplateSourceName).
+				SetDisplayMessage(fmt.Sprintf("Missing: %s", missingStr)).
+				SetMissingDependencies(missingStr))
 		}
 	}
 }
@@ -425,6 +443,15 @@ func (tm *TaskTemplateManager) handleTemplateRerenders(allRenderedTime time.Time
 	// A lookup for the last time the template was handled
 	handledRenders := make(map[string]time.Time, len(tm.config.Templates))
 
+	// Record the keys of the first render to diff the re-renders against
+	tm.renderedKeys = make(map[*structs.Template]map[string]string)
+	taskEnv := tm.config.EnvBuilder.Build()
+	for _, tmpl := range tm.config.Templates {
+		if tmpl.ChangeMode == structs.TemplateChangeModeScript {
+			tm.renderedKeys[tmpl] = readTemplateKeys(tmpl, taskEnv)
+		}
+	}
+
 	for {
 		select {
 		case <-tm.shutdownCh:
@@ -451,7 +478,7 @@ func (tm *TaskTemplateManager) onTemplateRendered(handledRenders map[string]time
 
 	var handling []string
 	signals := make(map[string]struct{})
-	scripts := []*structs.ChangeScript{}
+	scripts := []*changeScriptRun{}
 	restart := false
 	var splay time.Duration
 
@@ -497,7 +524,18 @@ func (tm *TaskTemplateManager) onTemplateRendered(handledRenders map[string]time
 			case structs.TemplateChangeModeRestart:
 				restart = true
 			case structs.TemplateChangeModeScript:
-				scripts = append(scripts, tmpl.ChangeScript)
+				keys := readTemplateKeys(tmpl, tm.config.EnvBuilder.Build())
+				changed := changedTemplateKeys(tm.renderedKeys[tmpl], keys)
+				tm.renderedKeys[tmpl] = keys
+				if len(changed) == 0 {
+					// only the formatting of the keys changed
+					continue
+				}
+				scripts = append(scripts, &changeScriptRun{
+					script:      tmpl.ChangeScript,
+					dest:        tmpl.DestPath,
+					changedKeys: changed,
+				})
 			case structs.TemplateChangeModeNoop:
 				continue
 			}
@@ -568,7 +606,33 @@ func (tm *TaskTemplateManager) handleChangeModeSignal(signals map[string]struct{
 	}
 }
 
-func (tm *TaskTemplateManager) handleChangeModeScript(scripts []*structs.ChangeScript) {
+// changeScriptRun is a change script to run for a re-rendered template.
+type changeScriptRun struct {
+	script *structs.ChangeScript
+
+	// dest is the destination of the re-rendered template
+	dest string
+
+	// changedKeys are the sorted keys of the template that were added,
+	// removed or changed by the re-render
+	changedKeys []string
+}
+
+// args returns the arguments of the script, interpolated with the task
+// environment and the variables describing the re-render.
+func (r *changeScriptRun) args(taskEnv *taskenv.TaskEnv) []string {
+	vars := taskEnv.All()
+	vars[TemplateDestinationEnv] = r.dest
+	vars[TemplateChangedKeysEnv] = strings.Join(r.changedKeys, ",")
+
+	args := make([]string, len(r.script.Args))
+	for i, arg := range r.script.Args {
+		args[i] = hargs.ReplaceEnv(arg, vars)
+	}
+	return args
+}
+
+func (tm *TaskTemplateManager) handleChangeModeScript(scripts []*changeScriptRun) {
 	// process script execution concurrently
 	var wg sync.WaitGroup
 	for _, script := range scripts {
@@ -593,24 +657,27 @@ func (tm *TaskTemplateManager) handleScriptError(script *structs.ChangeScript, m
 }
 
 // processScript is used for executing change_mode script and handling errors
-func (tm *TaskTemplateManager) processScript(script *structs.ChangeScript, wg *sync.WaitGroup) {
+func (tm *TaskTemplateManager) processScript(run *changeScriptRun, wg *sync.WaitGroup) {
 	defer wg.Done()
 
+	script := run.script
+	args := run.args(tm.config.EnvBuilder.Build())
+
 	if tm.handle == nil {
 		failureMsg := fmt.Sprintf(
 			"Template failed to run script %v with arguments %v because task driver doesn't support the exec operation",
 			script.Command,
-			script.Args,
+			args,
 		)
 		tm.handleScriptError(script, failureMsg)
 		return
 	}
-	_, exitCode, err := tm.handle.Exec(script.Timeout, script.Command, script.Args)
+	_, exitCode, err := tm.handle.Exec(script.Timeout, script.Command, args)
 	if err != nil {
 		failureMsg := fmt.Sprintf(
 			"Template failed to run script %v with arguments %v on change: %v Exit code: %v",
 			script.Command,
-			script.Args,
+			args,
 			err,
 			exitCode,
 		)
@@ -621,7 +688,7 @@ func (tm *TaskTemplateManager) processScript(script *structs.ChangeScript, wg *s
 		failureMsg := fmt.Sprintf(
 			"Template ran script %v with arguments %v on change but it exited with code code: %v",
 			script.Command,
-			script.Args,
+			args,
 			exitCode,
 		)
 		tm.handleScriptError(script, failureMsg)
@@ -632,7 +699,7 @@ func (tm *TaskTemplateManager) processScript(script *structs.ChangeScript, wg *s
 			fmt.Sprintf(
 				"Template successfully ran script %v with arguments: %v. Exit code: %v",
 				script.Command,
-				script.Args,
+				args,
 				exitCode,
 			)))
 }
@@ -716,6 +783,8 @@ func parseTemplateConfigs(config *TaskTemplateManagerConfig) (map[*ctconf.Templa
 	sandboxEnabled := !config.ClientConfig.TemplateConfig.DisableSandbox
 	taskEnv := config.EnvBuilder.Build()
 
+	funcs := extFuncMap(config)
+
 	ctmpls := make(map[*ctconf.TemplateConfig]*structs.Template, len(config.Templates))
 	for _, tmpl := range config.Templates {
 		var src, dest string
@@ -743,6 +812,7 @@ func parseTemplateConfigs(config *TaskTemplateManagerConfig) (map[*ctconf.Templa
 		ct.RightDelim = &tmpl.RightDelim
 		ct.ErrMissingKey = &tmpl.ErrMissingKey
 		ct.FunctionDenylist = config.ClientConfig.TemplateConfig.FunctionDenylist
+		ct.ExtFuncMap = funcs
 		if sandboxEnabled {
 			ct.SandboxPath = &config.TaskDir
 		}
@@ -1099,6 +1169,51 @@ func RenderFn(taskID, taskDir string, sandboxEnabled bool) func(*renderer.Render
 }
 
 // loadTemplateEnv loads task environment variables from all templates.
+// readTemplateKeys returns the keys and values rendered by the template, if
+// it renders an environment file or a JSON object. Otherwise the whole
+// rendered file is a single key named after the destination.
+func readTemplateKeys(tmpl *structs.Template, taskEnv *taskenv.TaskEnv) map[string]string {
+	// we checked escape before we rendered the file
+	dest, _ := taskEnv.ClientPath(tmpl.DestPath, true)
+	contents, err := os.ReadFile(dest)
+	if err != nil {
+		return nil
+	}
+
+	if vars, err := envparse.Parse(bytes.NewReader(contents)); err == nil && len(vars) > 0 {
+		return vars
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(contents, &obj); err == nil {
+		keys := make(map[string]string, len(obj))
+		for k, v := range obj {
+			keys[k] = string(v)
+		}
+		return keys
+	}
+
+	return map[string]string{filepath.Base(dest): string(contents)}
+}
+
+// changedTemplateKeys returns the sorted keys that were added, removed or
+// changed between two renders of a template.
+func changedTemplateKeys(old, new map[string]string) []string {
+	var changed []string
+	for k, v := range new {
+		if ov, ok := old[k]; !ok || ov != v {
+			changed = append(changed, k)
+		}
+	}
+	for k := range old {
+		if _, ok := new[k]; !ok {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
 func loadTemplateEnv(tmpls []*structs.Template, taskEnv *taskenv.TaskEnv) (map[string]string, error) {
 	all := make(map[string]string, 50)
 	for _, t := range tmpls {
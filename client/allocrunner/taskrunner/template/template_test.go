@@ -2561,3 +2561,80 @@ func TestTaskTemplateManager_writeToFile(t *testing.T) {
 	must.NoError(t, err)
 	must.Eq(t, "hello", string(r))
 }
+
+func TestTaskTemplateManager_readTemplateKeys(t *testing.T) {
+	ci.Parallel(t)
+
+	a := mock.Alloc()
+	task := a.Job.TaskGroups[0].Tasks[0]
+	taskDir := t.TempDir()
+	taskEnv := taskenv.NewBuilder(mock.Node(), a, task, "global").
+		SetClientTaskRoot(taskDir).Build()
+
+	cases := []struct {
+		name     string
+		contents string
+		exp      map[string]string
+	}{
+		{
+			name:     "env",
+			contents: "FOO=1\nBAR=2\n",
+			exp:      map[string]string{"FOO": "1", "BAR": "2"},
+		},
+		{
+			name:     "json",
+			contents: `{"foo": 1, "bar": {"baz": true}}`,
+			exp:      map[string]string{"foo": "1", "bar": `{"baz": true}`},
+		},
+		{
+			name:     "other",
+			contents: "<config><foo/></config>",
+			exp:      map[string]string{"other.conf": "<config><foo/></config>"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dest := tc.name + ".conf"
+			must.NoError(t, os.WriteFile(filepath.Join(taskDir, dest), []byte(tc.contents), 0o644))
+			keys := readTemplateKeys(&structs.Template{DestPath: dest}, taskEnv)
+			must.Eq(t, tc.exp, keys)
+		})
+	}
+}
+
+func TestTaskTemplateManager_changedTemplateKeys(t *testing.T) {
+	ci.Parallel(t)
+
+	old := map[string]string{"a": "1", "b": "2", "c": "3"}
+	new := map[string]string{"a": "1", "b": "4", "d": "5"}
+	must.Eq(t, []string{"b", "c", "d"}, changedTemplateKeys(old, new))
+	must.SliceEmpty(t, changedTemplateKeys(old, old))
+	must.Eq(t, []string{"a", "b", "c"}, changedTemplateKeys(nil, old))
+}
+
+func TestTaskTemplateManager_changeScriptRunArgs(t *testing.T) {
+	ci.Parallel(t)
+
+	a := mock.Alloc()
+	task := a.Job.TaskGroups[0].Tasks[0]
+	taskEnv := taskenv.NewBuilder(mock.Node(), a, task, "global").Build()
+
+	run := &changeScriptRun{
+		script: &structs.ChangeScript{
+			Command: "/bin/reload",
+			Args: []string{
+				"--file=${NOMAD_TEMPLATE_DESTINATION}",
+				"--keys=${NOMAD_TEMPLATE_CHANGED_KEYS}",
+				"--task=${NOMAD_TASK_NAME}",
+			},
+		},
+		dest:        "local/app.env",
+		changedKeys: []string{"DB_HOST", "DB_PORT"},
+	}
+	must.Eq(t, []string{
+		"--file=local/app.env",
+		"--keys=DB_HOST,DB_PORT",
+		"--task=" + task.Name,
+	}, run.args(taskEnv))
+}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/allocdir"
 	"github.com/hashicorp/nomad/client/allocrunner/taskrunner/template/renderer"
 	"github.com/hashicorp/nomad/helper/subproc"
 	"github.com/hashicorp/nomad/helper/winappcontainer"
@@ -37,6 +38,8 @@ func createPlatformSandbox(cfg *TaskTemplateManagerConfig) error {
 		AllowedPaths: []string{
 			thisBin,
 			filepath.Dir(cfg.TaskDir), // give access to the whole alloc working directory
+			// the secrets dir does not inherit ACEs from the task dir
+			filepath.Join(cfg.TaskDir, allocdir.TaskSecrets),
 		},
 	}
 	if cfg.Logger == nil {
@@ -14,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	metrics "github.com/armon/go-metrics"
@@ -40,6 +41,7 @@ import (
 	"github.com/hashicorp/nomad/client/pluginmanager/csimanager"
 	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
 	"github.com/hashicorp/nomad/client/servers"
+	"github.com/hashicorp/nomad/client/servicedns"
 	"github.com/hashicorp/nomad/client/serviceregistration"
 	"github.com/hashicorp/nomad/client/serviceregistration/checks/checkstore"
 	"github.com/hashicorp/nomad/client/serviceregistration/nsd"
@@ -56,6 +58,7 @@ import (
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/pool"
 	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/helper/users/dynamic"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -169,6 +172,12 @@ type Client struct {
 	// at runtime it may be accessed outside of locks.
 	metaStatic map[string]string
 
+	// metaIndex is incremented on every dynamic node metadata update, and
+	// metaUpdateCh is closed and replaced when it is to unblock watchers. Both
+	// are protected by configLock.
+	metaIndex    uint64
+	metaUpdateCh chan struct{}
+
 	logger    hclog.InterceptLogger
 	rpcLogger hclog.Logger
 
@@ -188,6 +197,18 @@ type Client struct {
 	heartbeatLock   sync.Mutex
 	heartbeatStop   *heartbeatStop
 
+	// edgeScheduler restarts failed allocs of pinned system jobs while the
+	// client is disconnected. Nil unless edge mode is enabled.
+	edgeScheduler *edgeScheduler
+
+	// serviceDNS answers DNS queries for Nomad native services. Nil unless
+	// the service DNS interface is enabled.
+	serviceDNS *servicedns.Server
+
+	// evictionManager evicts allocs under host memory or disk pressure. Nil
+	// unless eviction is enabled.
+	evictionManager *evictionManager
+
 	// triggerDiscoveryCh triggers Consul discovery; see triggerDiscovery
 	triggerDiscoveryCh chan struct{}
 
@@ -207,8 +228,16 @@ type Client struct {
 
 	// allocs maps alloc IDs to their AllocRunner. This map includes all
 	// AllocRunners - running and GC'd - until the server GCs them.
-	allocs    map[string]interfaces.AllocRunner
-	allocLock sync.RWMutex
+	allocs *allocRunners
+
+	// allocAddLock serializes adding alloc runners so an alloc is never run
+	// twice.
+	allocAddLock sync.Mutex
+
+	// hydrateCh is a semaphore bounding the number of alloc runners restored
+	// at once, on startup and when lazily restored alloc runners are first
+	// used.
+	hydrateCh chan struct{}
 
 	// allocrunnerFactory is the function called to create new allocrunners
 	allocrunnerFactory config.AllocRunnerFactory
@@ -274,6 +303,9 @@ type Client struct {
 	// in the node automatically
 	garbageCollector *AllocGarbageCollector
 
+	// placements traces the placement of allocations on the client
+	placements *placementSpans
+
 	// clientACLResolver holds the ACL resolution state
 	clientACLResolver
 
@@ -321,6 +353,9 @@ type Client struct {
 	// with a nomad client. Currently only used for CSI.
 	dynamicRegistry dynamicplugins.Registry
 
+	// dynamicHostVolumes tracks the host volumes created for allocations
+	dynamicHostVolumes *dynamicHostVolumes
+
 	// EnterpriseClient is used to set and check enterprise features for clients
 	EnterpriseClient *EnterpriseClient
 
@@ -378,6 +413,12 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 	// Create the logger
 	logger := cfg.Logger.ResetNamedIntercept("client")
 
+	// Artifacts are cached alongside the client state
+	var artifactCacheDir string
+	if cfg.StateDir != "" {
+		artifactCacheDir = filepath.Join(cfg.StateDir, "artifact_cache")
+	}
+
 	// Create the client
 	c := &Client{
 		config:               cfg,
@@ -390,7 +431,7 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		streamingRpcs:        structs.NewStreamingRpcRegistry(),
 		logger:               logger,
 		rpcLogger:            logger.Named("rpc"),
-		allocs:               make(map[string]interfaces.AllocRunner),
+		allocs:               newAllocRunners(),
 		pendingUpdates:       newPendingClientUpdates(),
 		shutdownCh:           make(chan struct{}),
 		triggerDiscoveryCh:   make(chan struct{}),
@@ -402,7 +443,8 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		serversContactedOnce: sync.Once{},
 		registeredCh:         make(chan struct{}),
 		registeredOnce:       sync.Once{},
-		getter:               getter.New(cfg.Artifact, logger),
+		dynamicHostVolumes:   newDynamicHostVolumes(),
+		getter:               getter.New(cfg.Artifact, artifactCacheDir, logger),
 		EnterpriseClient:     newEnterpriseClient(logger),
 		allocrunnerFactory:   cfg.AllocRunnerFactory,
 	}
@@ -451,6 +493,22 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 		return nil, fmt.Errorf("node setup failed: %v", err)
 	}
 
+	// Set up tracing of allocation placements
+	tracer, err := tracing.NewTracer(c.logger, cfg.Tracing, map[string]string{
+		"service.instance.id": cfg.Node.Name,
+		"nomad.region":        cfg.Region,
+		"nomad.datacenter":    cfg.Node.Datacenter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.placements = newPlacementSpans(tracer)
+
+	// Register the host volumes created by a previous run of the client
+	if err := c.setupDynamicHostVolumes(); err != nil {
+		return nil, fmt.Errorf("dynamic host volumes setup failed: %v", err)
+	}
+
 	// Add workload identity signer after node secret has been generated/loaded
 	c.widsigner = widmgr.NewSigner(widmgr.SignerConfig{
 		NodeSecret: c.secretNodeID(),
@@ -483,13 +541,15 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 
 	// Create the cpu core partition manager
 	c.partitions = cgroupslib.GetPartition(
-		c.topology.UsableCores(),
+		c.topology.SharedCores(),
+		c.topology.ExclusiveCores(),
 	)
 
 	// Create the process wranglers
 	wranglers, err := proclib.New(&proclib.Configs{
-		UsableCores: c.topology.UsableCores(),
-		Logger:      c.logger.Named("proclib"),
+		UsableCores:    c.topology.UsableCores(),
+		ExclusiveCores: c.topology.ExclusiveCores(),
+		Logger:         c.logger.Named("proclib"),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize process manager: %w", err)
@@ -529,12 +589,13 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 
 	// Setup the device manager
 	devConfig := &devicemanager.Config{
-		Logger:        c.logger,
-		Loader:        cfg.PluginSingletonLoader,
-		PluginConfig:  cfg.NomadPluginConfig(c.topology),
-		Updater:       c.batchNodeUpdates.updateNodeFromDevices,
-		StatsInterval: cfg.StatsCollectionInterval,
-		State:         c.stateDB,
+		Logger:          c.logger,
+		Loader:          cfg.PluginSingletonLoader,
+		PluginConfig:    cfg.NomadPluginConfig(c.topology),
+		Updater:         c.batchNodeUpdates.updateNodeFromDevices,
+		DevicesVanished: c.devicesVanished,
+		StatsInterval:   cfg.StatsCollectionInterval,
+		State:           c.stateDB,
 	}
 	devManager := devicemanager.New(devConfig)
 	c.devicemanager = devManager
@@ -553,15 +614,38 @@ func NewClient(cfg *config.Config, consulCatalog consul.CatalogAPI, consulProxie
 	// create heartbeatStop. We go after the first attempt to connect to the server, so
 	// that our grace period for connection goes for the full time
 	c.heartbeatStop = newHeartbeatStop(c.getAllocRunner, batchFirstFingerprintsTimeout, logger, c.shutdownCh)
+	c.heartbeatStop.edge = cfg.Edge
 
 	// Watch for disconnection, and heartbeatStopAllocs configured to have a maximum
 	// lifetime when out of touch with the server
 	go c.heartbeatStop.watch()
 
+	// Keep pinned system jobs running while disconnected in edge mode
+	if cfg.Edge != nil {
+		c.edgeScheduler = newEdgeScheduler(cfg.Edge, c.lastHeartbeat, c.getAllocRunners,
+			c.restartAllocLocally, c.triggerNodeEvent, c.logger, c.shutdownCh)
+		go c.edgeScheduler.watch()
+	}
+
+	// Serve DNS records for Nomad native services
+	if cfg.ServiceDNS != nil {
+		c.serviceDNS = servicedns.NewServer(c.logger, cfg.ServiceDNS, cfg.Region, c.RPC)
+		if err := c.serviceDNS.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start service DNS server: %w", err)
+		}
+	}
+
 	// Add the stats collector
 	statsCollector := hoststats.NewHostStatsCollector(c.logger, c.topology, c.GetConfig().AllocDir, c.devicemanager.AllStats)
 	c.hostStatsCollector = statsCollector
 
+	// Evict allocs before the host runs out of memory or disk
+	if cfg.Eviction != nil {
+		c.evictionManager = newEvictionManager(cfg.Eviction, cfg.AllocDir, statsCollector.Stats,
+			c.getAllocRunners, c.triggerNodeEvent, c.logger, c.shutdownCh)
+		go c.evictionManager.watch()
+	}
+
 	// Add the garbage collector
 	gcConfig := &GCConfig{
 		MaxAllocs:           cfg.GCMaxAllocs,
@@ -729,6 +813,13 @@ func (c *Client) init() error {
 
 	c.logger.Info("using alloc directory", "alloc_dir", conf.AllocDir)
 
+	// Ensure the exec recording dir exists if we are configured with one.
+	if conf.ExecRecordingDir != "" {
+		if err := os.MkdirAll(conf.ExecRecordingDir, 0o700); err != nil {
+			return fmt.Errorf("failed creating exec recording dir: %w", err)
+		}
+	}
+
 	reserved := "<none>"
 	if conf.Node != nil && conf.Node.ReservedResources != nil {
 		// Node should always be non-nil due to initialization in the
@@ -898,6 +989,11 @@ func (c *Client) Shutdown() error {
 	// Stop Garbage collector
 	c.garbageCollector.Stop()
 
+	// Stop serving service DNS records
+	if c.serviceDNS != nil {
+		c.serviceDNS.Shutdown()
+	}
+
 	arGroup := group.Group{}
 	if c.GetConfig().DevMode {
 		// In DevMode destroy all the running allocations.
@@ -933,6 +1029,9 @@ func (c *Client) Shutdown() error {
 	// Wait for goroutines to stop
 	c.shutdownGroup.Wait()
 
+	// Export the spans of the allocations being placed
+	c.placements.shutdown()
+
 	// One final save state
 	c.saveState()
 	return c.stateDB.Close()
@@ -1023,16 +1122,17 @@ func (c *Client) Node() *structs.Node {
 }
 
 // getAllocRunner returns an AllocRunner or an UnknownAllocation error if the
-// client has no runner for the given alloc ID.
+// client has no runner for the given alloc ID. Lazily restored alloc runners
+// are hydrated before being returned.
 func (c *Client) getAllocRunner(allocID string) (interfaces.AllocRunner, error) {
-	c.allocLock.RLock()
-	defer c.allocLock.RUnlock()
-
-	ar, ok := c.allocs[allocID]
+	ar, ok := c.allocs.get(allocID)
 	if !ok {
 		return nil, structs.NewErrUnknownAllocation(allocID)
 	}
 
+	if lazy, ok := ar.(*lazyAllocRunner); ok && lazy.runner() == nil {
+		return nil, fmt.Errorf("%w: %s", errAllocRunnerHydration, allocID)
+	}
 	return ar, nil
 }
 
@@ -1228,6 +1328,17 @@ func (c *Client) restoreState() error {
 		return nil
 	}
 
+	// Check the state against the alloc dirs and repair inconsistencies
+	// before any alloc runner uses them
+	report, err := state.Check(c.stateDB, conf.AllocDir, true, c.logger)
+	if err != nil {
+		return err
+	}
+	if len(report.Results) > 0 {
+		c.logger.Warn("repaired inconsistent client state",
+			"problems", len(report.Results), "allocs", report.Allocs, "alloc_dirs", report.AllocDirs)
+	}
+
 	// Restore allocations
 	allocs, allocErrs, err := c.stateDB.GetAllAllocations()
 	if err != nil {
@@ -1240,82 +1351,177 @@ func (c *Client) restoreState() error {
 		c.logger.Error("error restoring alloc", "error", err, "alloc_id", allocID)
 	}
 
-	// Load each alloc back
+	// Restore the allocs in parallel shards. Allocs are partitioned by the
+	// shard of their alloc runner, so restoring a shard doesn't contend with
+	// the other shards. The number of alloc runners restored at once is
+	// bounded so that a client running many allocs doesn't spike its memory
+	// and file handle usage on startup, and each alloc is run as soon as it's
+	// restored rather than once every alloc has been.
+	parallelism := conf.ParallelRestores
+	if parallelism <= 0 {
+		c.logger.Warn("defaulting alloc restore parallelism to 1 due to invalid input value",
+			"parallel_restores", parallelism)
+		parallelism = 1
+	}
+	c.hydrateCh = make(chan struct{}, parallelism)
+
+	var shards [allocRunnerShards][]*structs.Allocation
 	for _, alloc := range allocs {
+		i := allocRunnerShardIndex(alloc.ID)
+		shards[i] = append(shards[i], alloc)
+	}
 
-		// If the alloc has no task state, we most likely stopped the client
-		// after the allocrunner was created but before tasks could
-		// start. Remove the client state so that we can start over with this
-		// alloc if the server still wants to place it here.
-		if !c.hasLocalState(alloc) {
-			c.logger.Warn(
-				"found an alloc without any local state, deleting from client state db",
-				"alloc_id", alloc.ID)
-			c.stateDB.DeleteAllocationBucket(alloc.ID, state.WithBatchMode())
+	start := time.Now()
+	var lazy atomic.Int64
+	var wg sync.WaitGroup
+	for _, shard := range shards {
+		if len(shard) == 0 {
 			continue
 		}
+		wg.Add(1)
+		go func(shard []*structs.Allocation) {
+			defer wg.Done()
+			for _, alloc := range shard {
+				if c.restoreAlloc(alloc) {
+					lazy.Add(1)
+				}
+			}
+		}(shard)
+	}
+	wg.Wait()
+
+	c.logger.Debug("restored allocs", "allocs", len(allocs), "lazy", lazy.Load(),
+		"duration", time.Since(start))
+	return nil
+}
 
-		// On Restore we give up on watching previous allocs because we need the
-		// local AllocRunners initialized first.
-		prevAllocWatcher := allocwatcher.NoopPrevAlloc{}
-		prevAllocMigrator := allocwatcher.NoopPrevAlloc{}
+// restoreAlloc restores the alloc runner of an alloc from the client state
+// and runs it. The alloc runner of allocs that have nothing left to run is
+// only restored once it's first used, in which case restoreAlloc returns
+// true.
+func (c *Client) restoreAlloc(alloc *structs.Allocation) bool {
+	// If the alloc has no task state, we most likely stopped the client
+	// after the allocrunner was created but before tasks could
+	// start. Remove the client state so that we can start over with this
+	// alloc if the server still wants to place it here.
+	if !c.hasLocalState(alloc) {
+		c.logger.Warn(
+			"found an alloc without any local state, deleting from client state db",
+			"alloc_id", alloc.ID)
+		c.stateDB.DeleteAllocationBucket(alloc.ID, state.WithBatchMode())
+		return false
+	}
 
-		arConf := c.newAllocRunnerConfig(alloc, prevAllocWatcher, prevAllocMigrator)
+	allocState, err := c.stateDB.GetAcknowledgedState(alloc.ID)
+	if err != nil {
+		c.logger.Error("error restoring last acknowledged alloc state, will update again",
+			err, "alloc_id", alloc.ID)
+		allocState = nil
+	}
 
-		// ServerContactedCh is used by task runners on restore failures to
-		// wait for servers to be contacted before proceeding with the
-		// restoration process.
-		arConf.ServersContactedCh = c.serversContactedCh
+	// Allocs that were terminal when the client stopped, and whose terminal
+	// state the servers acknowledged, have nothing left to run. Restore
+	// their alloc runner lazily and hand them to the garbage collector.
+	if allocState != nil && allocState.ClientTerminalStatus() {
+		ar := newLazyAllocRunner(alloc, allocState, func() interfaces.AllocRunner {
+			return c.hydrateAlloc(alloc, allocState)
+		})
+		c.allocs.set(alloc.ID, ar)
+		c.garbageCollector.MarkForCollection(alloc.ID, ar)
+		return true
+	}
 
-		ar, err := c.allocrunnerFactory(arConf)
-		if err != nil {
-			c.logger.Error("error running alloc", "error", err, "alloc_id", alloc.ID)
+	// Claim the host volumes created for the alloc so they aren't deleted
+	// while it runs
+	if !alloc.Terminated() {
+		if err := c.claimHostVolumes(alloc); err != nil {
+			c.logger.Error("error claiming host volumes", "error", err, "alloc_id", alloc.ID)
 			c.handleInvalidAllocs(alloc, err)
-			continue
+			return false
 		}
+	}
 
-		// Restore state
-		if err := ar.Restore(); err != nil {
-			c.logger.Error("error restoring alloc", "error", err, "alloc_id", alloc.ID)
-			// Override the status of the alloc to failed
-			ar.SetClientStatus(structs.AllocClientStatusFailed)
-			// Destroy the alloc runner since this is a failed restore
-			ar.Destroy()
-			continue
-		}
+	ar := c.newRestoredAllocRunner(alloc, allocState)
+	if ar == nil {
+		return false
+	}
 
-		allocState, err := c.stateDB.GetAcknowledgedState(alloc.ID)
+	// Maybe mark the alloc for halt on missing server heartbeats
+	if c.heartbeatStop.shouldStop(alloc) {
+		err = c.heartbeatStop.stopAlloc(alloc.ID)
 		if err != nil {
-			c.logger.Error("error restoring last acknowledged alloc state, will update again",
-				err, "alloc_id", alloc.ID)
-		} else {
-			ar.AcknowledgeState(allocState)
+			c.logger.Error("error stopping alloc", "error", err, "alloc_id", alloc.ID)
 		}
+		return false
+	}
 
-		// Maybe mark the alloc for halt on missing server heartbeats
-		if c.heartbeatStop.shouldStop(alloc) {
-			err = c.heartbeatStop.stopAlloc(alloc.ID)
-			if err != nil {
-				c.logger.Error("error stopping alloc", "error", err, "alloc_id", alloc.ID)
-			}
-			continue
-		}
+	c.allocs.set(alloc.ID, ar)
+
+	c.heartbeatStop.allocHook(alloc)
+
+	// The alloc restored successfully, run it!
+	go ar.Run()
+	return false
+}
+
+// hydrateAlloc restores and runs the alloc runner of a lazily restored alloc
+// the first time it's used. It returns nil if the alloc runner failed to
+// restore.
+func (c *Client) hydrateAlloc(alloc *structs.Allocation, allocState *arstate.State) interfaces.AllocRunner {
+	c.logger.Debug("restoring lazily restored alloc", "alloc_id", alloc.ID)
 
-		//XXX is this locking necessary?
-		c.allocLock.Lock()
-		c.allocs[alloc.ID] = ar
-		c.allocLock.Unlock()
+	ar := c.newRestoredAllocRunner(alloc, allocState)
+	if ar == nil {
+		return nil
+	}
+
+	// Run the alloc runner so it runs the cleanup of its terminal tasks and
+	// can be destroyed.
+	go ar.Run()
+	return ar
+}
+
+// newRestoredAllocRunner builds the alloc runner of an alloc and restores its
+// state from the client state. Failed restores are reported to the servers,
+// and nil is returned.
+func (c *Client) newRestoredAllocRunner(alloc *structs.Allocation, allocState *arstate.State) interfaces.AllocRunner {
+	// Bound the number of alloc runners restored at once
+	c.hydrateCh <- struct{}{}
+	defer func() { <-c.hydrateCh }()
+
+	// On Restore we give up on watching previous allocs because we need the
+	// local AllocRunners initialized first.
+	prevAllocWatcher := allocwatcher.NoopPrevAlloc{}
+	prevAllocMigrator := allocwatcher.NoopPrevAlloc{}
+
+	arConf := c.newAllocRunnerConfig(alloc, prevAllocWatcher, prevAllocMigrator)
+
+	// ServerContactedCh is used by task runners on restore failures to
+	// wait for servers to be contacted before proceeding with the
+	// restoration process.
+	arConf.ServersContactedCh = c.serversContactedCh
 
-		c.heartbeatStop.allocHook(alloc)
+	ar, err := c.allocrunnerFactory(arConf)
+	if err != nil {
+		c.logger.Error("error running alloc", "error", err, "alloc_id", alloc.ID)
+		c.handleInvalidAllocs(alloc, err)
+		return nil
 	}
 
-	// All allocs restored successfully, run them!
-	c.allocLock.Lock()
-	for _, ar := range c.allocs {
-		go ar.Run()
+	// Restore state
+	if err := ar.Restore(); err != nil {
+		c.logger.Error("error restoring alloc", "error", err, "alloc_id", alloc.ID)
+		// Override the status of the alloc to failed
+		ar.SetClientStatus(structs.AllocClientStatusFailed)
+		// Destroy the alloc runner since this is a failed restore
+		ar.Destroy()
+		return nil
 	}
-	c.allocLock.Unlock()
-	return nil
+
+	if allocState != nil {
+		ar.AcknowledgeState(allocState)
+	}
+	return ar
 }
 
 // hasLocalState returns true if we have any other associated state
@@ -1393,12 +1599,11 @@ func (c *Client) saveState() error {
 
 // getAllocRunners returns a snapshot of the current set of alloc runners.
 func (c *Client) getAllocRunners() map[string]interfaces.AllocRunner {
-	c.allocLock.RLock()
-	defer c.allocLock.RUnlock()
-	runners := make(map[string]interfaces.AllocRunner, len(c.allocs))
-	for id, ar := range c.allocs {
+	runners := make(map[string]interfaces.AllocRunner, c.allocs.len())
+	c.allocs.forEach(func(id string, ar interfaces.AllocRunner) bool {
 		runners[id] = ar
-	}
+		return true
+	})
 	return runners
 }
 
@@ -1406,13 +1611,12 @@ func (c *Client) getAllocRunners() map[string]interfaces.AllocRunner {
 // fulfill the AllocCounter interface for the GC.
 func (c *Client) NumAllocs() int {
 	n := 0
-	c.allocLock.RLock()
-	for _, a := range c.allocs {
+	c.allocs.forEach(func(_ string, a interfaces.AllocRunner) bool {
 		if !a.IsDestroyed() {
 			n++
 		}
-	}
-	c.allocLock.RUnlock()
+		return true
+	})
 	return n
 }
 
@@ -1615,6 +1819,8 @@ func (c *Client) setupNode() error {
 	if err := c.stateDB.PutNodeMeta(c.metaDynamic); err != nil {
 		return fmt.Errorf("error syncing dynamic node metadata: %w", err)
 	}
+	c.metaIndex = 1
+	c.metaUpdateCh = make(chan struct{})
 
 	c.config = newConfig
 	return nil
@@ -1629,6 +1835,7 @@ func (c *Client) updateNodeFromFingerprint(response *fingerprint.FingerprintResp
 	nodeHasChanged := false
 	newConfig := c.config.Copy()
 
+	var changedAttrs []string
 	for name, newVal := range response.Attributes {
 		oldVal := newConfig.Node.Attributes[name]
 		if oldVal == newVal {
@@ -1636,6 +1843,7 @@ func (c *Client) updateNodeFromFingerprint(response *fingerprint.FingerprintResp
 		}
 
 		nodeHasChanged = true
+		changedAttrs = append(changedAttrs, name)
 		if newVal == "" {
 			delete(newConfig.Node.Attributes, name)
 		} else {
@@ -1686,9 +1894,31 @@ func (c *Client) updateNodeFromFingerprint(response *fingerprint.FingerprintResp
 		c.updateNode()
 	}
 
+	// Attributes that change once the node has registered were picked up by
+	// a periodic fingerprint, so let operators know the node has changed
+	if len(changedAttrs) != 0 && c.hasRegistered() {
+		sort.Strings(changedAttrs)
+		event := structs.NewNodeEvent().
+			SetSubsystem(structs.NodeEventSubsystemFingerprint).
+			SetMessage("Node attributes changed").
+			AddDetail("attributes", strings.Join(changedAttrs, ","))
+		c.triggerNodeEvent(event)
+	}
+
 	return newConfig.Node
 }
 
+// hasRegistered returns whether the node has successfully registered with the
+// servers.
+func (c *Client) hasRegistered() bool {
+	select {
+	case <-c.registeredCh:
+		return true
+	default:
+		return false
+	}
+}
+
 // updateNetworks filters and overrides network speed of host networks based
 // on configured settings
 func updateNetworks(up structs.Networks, c *config.Config) structs.Networks {
@@ -1742,9 +1972,15 @@ func (c *Client) registerAndHeartbeat() {
 	// Start watching changes for node changes
 	go c.watchNodeUpdates()
 
+	// Warn about dynamic port ranges the OS may assign to outbound connections
+	c.checkDynamicPortRanges()
+
 	// Start watching for emitting node events
 	go c.watchNodeEvents()
 
+	// Start reporting the resource usage of the allocations
+	go c.reportUsage()
+
 	// Setup the heartbeat timer, for the initial registration
 	// we want to do this quickly. We want to do it extra quickly
 	// in development mode.
@@ -2162,6 +2398,8 @@ func (c *Client) handleNodeUpdateResponse(resp structs.NodeUpdateResponse) error
 // AllocStateUpdated asynchronously updates the server with the current state
 // of an allocations and its tasks.
 func (c *Client) AllocStateUpdated(alloc *structs.Allocation) {
+	c.placements.update(alloc)
+
 	if alloc.Terminated() {
 		// Terminated, mark for GC if we're still tracking this alloc
 		// runner. If it's not being tracked that means the server has
@@ -2169,6 +2407,7 @@ func (c *Client) AllocStateUpdated(alloc *structs.Allocation) {
 		ar, err := c.getAllocRunner(alloc.ID)
 
 		if err == nil {
+			c.releaseHostVolumes(ar.Alloc())
 			c.garbageCollector.MarkForCollection(alloc.ID, ar)
 
 			// Trigger a GC in case we're over thresholds and just
@@ -2187,6 +2426,7 @@ func (c *Client) AllocStateUpdated(alloc *structs.Allocation) {
 	stripped.ClientDescription = alloc.ClientDescription
 	stripped.DeploymentStatus = alloc.DeploymentStatus
 	stripped.NetworkStatus = alloc.NetworkStatus
+	stripped.PendingReason = alloc.PendingReason
 
 	c.pendingUpdates.add(stripped)
 }
@@ -2242,19 +2482,18 @@ func (c *Client) allocSync() {
 
 			// Record that we've successfully synced these updates so that it's
 			// written to disk
-			c.allocLock.RLock()
 			for _, update := range toSync {
-				if ar, ok := c.allocs[update.ID]; ok {
+				c.allocs.with(update.ID, func(ar interfaces.AllocRunner) {
 					ar.AcknowledgeState(&arstate.State{
 						ClientStatus:      update.ClientStatus,
 						ClientDescription: update.ClientDescription,
 						DeploymentStatus:  update.DeploymentStatus,
 						TaskStates:        update.TaskStates,
 						NetworkStatus:     update.NetworkStatus,
+						PendingReason:     update.PendingReason,
 					})
-				}
+				})
 			}
-			c.allocLock.RUnlock()
 
 			// Successfully updated allocs. Reset ticker to give loop time to
 			// receive new alloc updates. Otherwise if the RPC took the ticker
@@ -2307,13 +2546,18 @@ func (c *Client) watchAllocations(updates chan *allocUpdates) {
 	// new, or updated server side.
 	allocsReq := structs.AllocsGetRequest{
 		QueryOptions: structs.QueryOptions{
-			Region:     c.Region(),
-			AllowStale: true,
-			AuthToken:  c.secretNodeID(),
+			Region:      c.Region(),
+			AllowStale:  true,
+			ReadReplica: c.GetConfig().PreferReadReplicas,
+			AuthToken:   c.secretNodeID(),
 		},
 	}
 	var allocsResp structs.AllocsGetResponse
 
+	// deltaMismatches are the allocations whose delta couldn't be applied to
+	// the version of the alloc runner, which are pulled in full instead.
+	deltaMismatches := make(map[string]struct{})
+
 OUTER:
 	for {
 		// Get the allocation modify index map, blocking for updates. We will
@@ -2375,14 +2619,13 @@ OUTER:
 		// need to pull all the allocations.
 		var pull []string
 		filtered := make(map[string]struct{})
+		deltaBases := make(map[string]*structs.Allocation)
 		var pullIndex uint64
 		for allocID, modifyIndex := range resp.Allocs {
 			// Pull the allocation if we don't have an alloc runner for the
 			// allocation or if the alloc runner requires an updated allocation.
 			//XXX Part of Client alloc index tracking exp
-			c.allocLock.RLock()
-			currentAR, ok := c.allocs[allocID]
-			c.allocLock.RUnlock()
+			currentAR, ok := c.allocs.get(allocID)
 
 			// Ignore alloc updates for allocs that are invalid because of initialization errors
 			c.invalidAllocsLock.Lock()
@@ -2397,6 +2640,13 @@ OUTER:
 					pullIndex = modifyIndex
 				}
 				pull = append(pull, allocID)
+
+				// Ask for a delta of the allocations we already have
+				if _, mismatch := deltaMismatches[allocID]; ok && !mismatch {
+					if base := currentAR.Alloc(); base.Job != nil {
+						deltaBases[allocID] = base
+					}
+				}
 			} else {
 				filtered[allocID] = struct{}{}
 			}
@@ -2404,10 +2654,15 @@ OUTER:
 
 		// Pull the allocations that passed filtering.
 		allocsResp.Allocs = nil
+		allocsResp.Deltas = nil
 		var pulledAllocs map[string]*structs.Allocation
 		if len(pull) != 0 {
 			// Pull the allocations that need to be updated.
 			allocsReq.AllocIDs = pull
+			allocsReq.DeltaBases = make(map[string]uint64, len(deltaBases))
+			for allocID, base := range deltaBases {
+				allocsReq.DeltaBases[allocID] = base.Job.JobModifyIndex
+			}
 			allocsReq.MinQueryIndex = pullIndex - 1
 			allocsResp = structs.AllocsGetResponse{}
 			if err := c.RPC("Alloc.GetAllocs", &allocsReq, &allocsResp); err != nil {
@@ -2451,6 +2706,33 @@ OUTER:
 				alloc.Canonicalize()
 
 				pulledAllocs[alloc.ID] = alloc
+				delete(deltaMismatches, alloc.ID)
+			}
+
+			// Reconstruct the allocations sent as deltas. Deltas are only sent
+			// by servers recent enough to not require canonicalization, and
+			// share their job with the alloc runner's version so they must not
+			// be modified.
+			for _, delta := range allocsResp.Deltas {
+				alloc, err := delta.Apply(deltaBases[delta.Alloc.ID])
+				if err != nil {
+					c.logger.Warn("failed to apply allocation delta; pulling allocation in full",
+						"alloc_id", delta.Alloc.ID, "error", err)
+					metrics.IncrCounter([]string{"client", "allocations", "delta_mismatch"}, 1)
+					deltaMismatches[delta.Alloc.ID] = struct{}{}
+					continue
+				}
+				pulledAllocs[alloc.ID] = alloc
+			}
+
+			// Pull the allocations whose delta couldn't be applied right away,
+			// without updating the MinQueryIndex.
+			if len(deltaMismatches) != 0 {
+				for _, desiredID := range pull {
+					if _, ok := deltaMismatches[desiredID]; ok {
+						continue OUTER
+					}
+				}
 			}
 
 			for _, desiredID := range pull {
@@ -2478,10 +2760,12 @@ OUTER:
 		}
 
 		c.logger.Debug("updated allocations", "index", resp.Index,
-			"total", len(resp.Allocs), "pulled", len(allocsResp.Allocs), "filtered", len(filtered))
+			"total", len(resp.Allocs), "pulled", len(allocsResp.Allocs), "deltas", len(allocsResp.Deltas),
+			"filtered", len(filtered))
 
 		// After the first request, only require monotonically increasing state.
 		req.AllowStale = true
+		req.ReadReplica = c.GetConfig().PreferReadReplicas
 		if resp.Index > req.MinQueryIndex {
 			req.MinQueryIndex = resp.Index
 		}
@@ -2541,12 +2825,11 @@ func (c *Client) watchNodeUpdates() {
 // runAllocs is invoked when we get an updated set of allocations
 func (c *Client) runAllocs(update *allocUpdates) {
 	// Get the existing allocs
-	c.allocLock.RLock()
-	existing := make(map[string]uint64, len(c.allocs))
-	for id, ar := range c.allocs {
+	existing := make(map[string]uint64, c.allocs.len())
+	c.allocs.forEach(func(id string, ar interfaces.AllocRunner) bool {
 		existing[id] = ar.Alloc().AllocModifyIndex
-	}
-	c.allocLock.RUnlock()
+		return true
+	})
 
 	// Diff the existing and updated allocations
 	diff := diffAllocs(existing, update)
@@ -2646,10 +2929,8 @@ func makeFailedAlloc(add *structs.Allocation, err error) *structs.Allocation {
 // removeAlloc is invoked when we should remove an allocation because it has
 // been removed by the server.
 func (c *Client) removeAlloc(allocID string) {
-	c.allocLock.Lock()
-	defer c.allocLock.Unlock()
-
-	ar, ok := c.allocs[allocID]
+	// Stop tracking alloc runner as it's been GC'd by the server
+	ar, ok := c.allocs.remove(allocID)
 	if !ok {
 		c.invalidAllocsLock.Lock()
 		if _, ok := c.invalidAllocs[allocID]; ok {
@@ -2663,8 +2944,7 @@ func (c *Client) removeAlloc(allocID string) {
 		return
 	}
 
-	// Stop tracking alloc runner as it's been GC'd by the server
-	delete(c.allocs, allocID)
+	c.releaseHostVolumes(ar.Alloc())
 
 	// Ensure the GC has a reference and then collect. Collecting through the GC
 	// applies rate limiting
@@ -2706,15 +2986,21 @@ func (c *Client) updateAlloc(update *structs.Allocation) {
 
 // addAlloc is invoked when we should add an allocation
 func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error {
-	c.allocLock.Lock()
-	defer c.allocLock.Unlock()
+	c.allocAddLock.Lock()
+	defer c.allocAddLock.Unlock()
 
 	// Check if we already have an alloc runner
-	if _, ok := c.allocs[alloc.ID]; ok {
+	if _, ok := c.allocs.get(alloc.ID); ok {
 		c.logger.Debug("dropping duplicate add allocation request", "alloc_id", alloc.ID)
 		return nil
 	}
 
+	// Create the host volumes the alloc requests before configuring its
+	// alloc runner, so that its tasks can mount them
+	if err := c.claimHostVolumes(alloc); err != nil {
+		return err
+	}
+
 	// Initialize local copy of alloc before creating the alloc runner so
 	// we can't end up with an alloc runner that does not have an alloc.
 	if err := c.stateDB.PutAllocation(alloc); err != nil {
@@ -2726,15 +3012,16 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 	if len(alloc.PreemptedAllocations) > 0 {
 		preemptedAllocs = make(map[string]allocwatcher.AllocRunnerMeta)
 		for _, palloc := range alloc.PreemptedAllocations {
-			preemptedAllocs[palloc] = c.allocs[palloc]
+			preemptedAllocs[palloc], _ = c.allocs.get(palloc)
 		}
 	}
 
 	// Since only the Client has access to other AllocRunners and the RPC
 	// client, create the previous allocation watcher here.
+	prevRunner, _ := c.allocs.get(alloc.PreviousAllocation)
 	watcherConfig := allocwatcher.Config{
 		Alloc:            alloc,
-		PreviousRunner:   c.allocs[alloc.PreviousAllocation],
+		PreviousRunner:   prevRunner,
 		PreemptedRunners: preemptedAllocs,
 		RPC:              c,
 		Config:           c.GetConfig(),
@@ -2750,7 +3037,8 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 	}
 
 	// Store the alloc runner.
-	c.allocs[alloc.ID] = ar
+	c.allocs.set(alloc.ID, ar)
+	c.placements.start(alloc)
 
 	// Maybe mark the alloc for halt on missing server heartbeats
 	c.heartbeatStop.allocHook(alloc)
@@ -2759,6 +3047,33 @@ func (c *Client) addAlloc(alloc *structs.Allocation, migrateToken string) error
 	return nil
 }
 
+// restartAllocLocally replaces the runner of a terminal allocation with a new
+// runner for the same allocation. It is used by the edge scheduler to restart
+// allocations without the servers, so the allocation ID is kept and the
+// servers learn of the restart through the regular alloc sync.
+func (c *Client) restartAllocLocally(allocID string) error {
+	ar, err := c.getAllocRunner(allocID)
+	if err != nil {
+		return err
+	}
+	alloc := ar.Alloc().Copy()
+
+	// Destroy the terminal alloc runner, going through the garbage collector
+	// if it is already tracking it.
+	if !c.garbageCollector.Collect(allocID) {
+		ar.Destroy()
+		<-ar.DestroyCh()
+	}
+
+	c.allocs.remove(allocID)
+
+	alloc.ClientStatus = structs.AllocClientStatusPending
+	alloc.ClientDescription = edgeRestartDescription
+	alloc.TaskStates = nil
+	alloc.DeploymentStatus = nil
+	return c.addAlloc(alloc, "")
+}
+
 // allocRunnerConfig returns a new AllocRunnerConfig that can be used to start
 // or restore an AllocRunner.
 func (c *Client) newAllocRunnerConfig(
@@ -2852,6 +3167,7 @@ func (c *Client) setupNomadServiceRegistrationHandler() {
 		CheckWatcher: serviceregistration.NewCheckWatcher(
 			c.logger, nsd.NewStatusGetter(c.checkStore),
 		),
+		StatusGetter: nsd.NewStatusGetter(c.checkStore),
 	}
 	c.nomadService = nsd.NewServiceRegistrationHandler(c.logger, &cfg)
 }
@@ -3405,12 +3721,11 @@ func (c *Client) getAllocatedResources(selfNode *structs.Node) *structs.Comparab
 
 // GetTaskEventHandler returns an event handler for the given allocID and task name
 func (c *Client) GetTaskEventHandler(allocID, taskName string) drivermanager.EventHandler {
-	c.allocLock.RLock()
-	defer c.allocLock.RUnlock()
-	if ar, ok := c.allocs[allocID]; ok {
-		return ar.GetTaskEventHandler(taskName)
-	}
-	return nil
+	var handler drivermanager.EventHandler
+	c.allocs.with(allocID, func(ar interfaces.AllocRunner) {
+		handler = ar.GetTaskEventHandler(taskName)
+	})
+	return handler
 }
 
 // pendingClientUpdates are the set of allocation updates that the client is
@@ -3490,11 +3805,9 @@ func (p *pendingClientUpdates) nextBatch(c *Client, updateTicks int) []*structs.
 func (p *pendingClientUpdates) filterAcknowledgedUpdatesLocked(c *Client) ([]*structs.Allocation, bool) {
 	var urgent bool
 	sync := make([]*structs.Allocation, 0, len(p.updates))
-	c.allocLock.RLock()
-	defer c.allocLock.RUnlock()
 
 	for allocID, update := range p.updates {
-		if ar, ok := c.allocs[allocID]; ok {
+		ok := c.allocs.with(allocID, func(ar interfaces.AllocRunner) {
 			switch ar.GetUpdatePriority(update) {
 			case cstructs.AllocUpdatePriorityUrgent:
 				sync = append(sync, update)
@@ -3504,7 +3817,8 @@ func (p *pendingClientUpdates) filterAcknowledgedUpdatesLocked(c *Client) ([]*st
 			case cstructs.AllocUpdatePriorityNone:
 				// update is dropped
 			}
-		} else {
+		})
+		if !ok {
 			// no allocrunner (typically a failed placement), so we need
 			// to send update
 			sync = append(sync, update)
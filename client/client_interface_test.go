@@ -138,6 +138,9 @@ func (ar *emptyAllocRunner) RestartTask(taskName string, taskEvent *structs.Task
 }
 func (ar *emptyAllocRunner) RestartRunning(taskEvent *structs.TaskEvent) error { return nil }
 func (ar *emptyAllocRunner) RestartAll(taskEvent *structs.TaskEvent) error     { return nil }
+func (ar *emptyAllocRunner) KillTask(taskName string, taskEvent *structs.TaskEvent) error {
+	return nil
+}
 
 func (ar *emptyAllocRunner) GetTaskEventHandler(taskName string) drivermanager.EventHandler {
 	return nil
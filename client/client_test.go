@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/client/allocrunner"
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	arstate "github.com/hashicorp/nomad/client/allocrunner/state"
 	trstate "github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/fingerprint"
@@ -610,9 +612,7 @@ func TestClient_WatchAllocs(t *testing.T) {
 
 	// Both allocations should get registered
 	testutil.WaitForResult(func() (bool, error) {
-		c1.allocLock.RLock()
-		num := len(c1.allocs)
-		c1.allocLock.RUnlock()
+		num := c1.allocs.len()
 		return num == 2, nil
 	}, func(err error) {
 		t.Fatalf("err: %v", err)
@@ -634,9 +634,7 @@ func TestClient_WatchAllocs(t *testing.T) {
 
 	// One allocation should get GC'd and removed
 	testutil.WaitForResult(func() (bool, error) {
-		c1.allocLock.RLock()
-		num := len(c1.allocs)
-		c1.allocLock.RUnlock()
+		num := c1.allocs.len()
 		return num == 1, nil
 	}, func(err error) {
 		t.Fatalf("err: %v", err)
@@ -644,9 +642,7 @@ func TestClient_WatchAllocs(t *testing.T) {
 
 	// One allocations should get updated
 	testutil.WaitForResult(func() (bool, error) {
-		c1.allocLock.RLock()
-		ar := c1.allocs[alloc2.ID]
-		c1.allocLock.RUnlock()
+		ar, _ := c1.allocs.get(alloc2.ID)
 		return ar.Alloc().DesiredStatus == structs.AllocDesiredStatusStop, nil
 	}, func(err error) {
 		t.Fatalf("err: %v", err)
@@ -718,12 +714,11 @@ func TestClient_SaveRestoreState(t *testing.T) {
 	// Allocations should be placed
 	must.Wait(t, wait.InitialSuccess(
 		wait.ErrorFunc(func() error {
-			c1.allocLock.RLock()
-			defer c1.allocLock.RUnlock()
-			if len(c1.allocs) != 4 {
+			runners := c1.getAllocRunners()
+			if len(runners) != 4 {
 				return fmt.Errorf("expected 4 alloc runners")
 			}
-			for _, ar := range c1.allocs {
+			for _, ar := range runners {
 				if ar.AllocState().ClientStatus != structs.AllocClientStatusRunning {
 					return fmt.Errorf("expected running client status, got %v",
 						ar.AllocState().ClientStatus)
@@ -863,13 +858,12 @@ func TestClient_SaveRestoreState(t *testing.T) {
 	// Ensure only the expected allocation is running
 	must.Wait(t, wait.InitialSuccess(
 		wait.ErrorFunc(func() error {
-			c2.allocLock.RLock()
-			defer c2.allocLock.RUnlock()
-			if len(c2.allocs) != 3 {
+			runners := c2.getAllocRunners()
+			if len(runners) != 3 {
 				// the GC'd alloc will not have restored AR
 				return fmt.Errorf("expected 3 alloc runners")
 			}
-			for allocID, ar := range c2.allocs {
+			for allocID, ar := range runners {
 				if ar == nil {
 					return fmt.Errorf("nil alloc runner")
 				}
@@ -1005,10 +999,10 @@ func TestClient_AddAllocError(t *testing.T) {
 
 	// Ensure the allocation has been marked as invalid and failed on the server
 	testutil.WaitForResult(func() (bool, error) {
-		c1.allocLock.RLock()
-		ar := c1.allocs[alloc1.ID]
+		ar, _ := c1.allocs.get(alloc1.ID)
+		c1.invalidAllocsLock.Lock()
 		_, isInvalid := c1.invalidAllocs[alloc1.ID]
-		c1.allocLock.RUnlock()
+		c1.invalidAllocsLock.Unlock()
 		if ar != nil {
 			return false, fmt.Errorf("expected nil alloc runner")
 		}
@@ -1945,6 +1939,75 @@ func TestClient_hasLocalState(t *testing.T) {
 	})
 }
 
+func TestClient_restoreState_Parallel(t *testing.T) {
+	ci.Parallel(t)
+
+	c, cleanup := TestClient(t, func(c *config.Config) {
+		c.DevMode = false
+		c.ParallelRestores = 3
+	})
+	defer cleanup()
+
+	// Track the number of alloc runners created, and created at once
+	var lock sync.Mutex
+	var created, running, maxRunning int
+	c.allocrunnerFactory = func(conf *config.AllocRunnerConfig) (interfaces.AllocRunner, error) {
+		lock.Lock()
+		created++
+		running++
+		maxRunning = max(running, maxRunning)
+		lock.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		lock.Lock()
+		running--
+		lock.Unlock()
+		return newEmptyAllocRunnerFunc(conf)
+	}
+
+	for i := 0; i < 10; i++ {
+		alloc := mock.BatchAlloc()
+		taskName := alloc.Job.LookupTaskGroup(alloc.TaskGroup).Tasks[0].Name
+		must.NoError(t, c.stateDB.PutAllocation(alloc))
+		must.NoError(t, c.stateDB.PutTaskRunnerLocalState(alloc.ID, taskName, &trstate.LocalState{}))
+		must.NoError(t, os.MkdirAll(filepath.Join(c.GetConfig().AllocDir, alloc.ID), 0o755))
+	}
+
+	// Allocs whose terminal state was acknowledged are restored lazily
+	var terminal []string
+	for i := 0; i < 5; i++ {
+		alloc := mock.BatchAlloc()
+		taskName := alloc.Job.LookupTaskGroup(alloc.TaskGroup).Tasks[0].Name
+		must.NoError(t, c.stateDB.PutAllocation(alloc))
+		must.NoError(t, c.stateDB.PutTaskRunnerLocalState(alloc.ID, taskName, &trstate.LocalState{}))
+		must.NoError(t, os.MkdirAll(filepath.Join(c.GetConfig().AllocDir, alloc.ID), 0o755))
+		must.NoError(t, c.stateDB.PutAcknowledgedState(alloc.ID, &arstate.State{
+			ClientStatus: structs.AllocClientStatusComplete,
+		}))
+		terminal = append(terminal, alloc.ID)
+	}
+
+	must.NoError(t, c.restoreState())
+	must.MapLen(t, 15, c.getAllocRunners())
+	must.LessEq(t, 3, maxRunning)
+	must.Eq(t, 10, created)
+
+	// Reading the state of a lazily restored alloc doesn't restore its alloc
+	// runner, but using it does
+	ar, ok := c.allocs.get(terminal[0])
+	must.True(t, ok)
+	must.Eq(t, structs.AllocClientStatusComplete, ar.AllocState().ClientStatus)
+	must.Eq(t, terminal[0], ar.Alloc().ID)
+	must.False(t, ar.IsDestroyed())
+	must.Eq(t, 10, created)
+
+	_, err := c.getAllocRunner(terminal[0])
+	must.NoError(t, err)
+	must.Eq(t, 11, created)
+	must.True(t, ar.(*lazyAllocRunner).isHydrated())
+}
+
 func Test_verifiedTasks(t *testing.T) {
 	ci.Parallel(t)
 	logger := testlog.HCLogger(t)
@@ -2082,10 +2145,10 @@ func TestClient_ReconnectAllocs(t *testing.T) {
 	// Ensure the allocation is not invalid on the client and has been marked
 	// running on the server with the new modify index
 	testutil.WaitForResult(func() (result bool, stateErr error) {
-		c1.allocLock.RLock()
-		runner = c1.allocs[unknownAlloc.ID]
+		runner, _ = c1.allocs.get(unknownAlloc.ID)
+		c1.invalidAllocsLock.Lock()
 		_, invalid = c1.invalidAllocs[unknownAlloc.ID]
-		c1.allocLock.RUnlock()
+		c1.invalidAllocsLock.Unlock()
 
 		finalAlloc, stateErr = state.AllocByID(nil, unknownAlloc.ID)
 		result = structs.AllocClientStatusRunning == finalAlloc.ClientStatus
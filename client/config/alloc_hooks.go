@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	sconfig "github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// AllocHookStagePrerun runs the hook before the allocation's tasks start.
+	AllocHookStagePrerun = "prerun"
+
+	// AllocHookStagePostrun runs the hook after the allocation's tasks exit.
+	AllocHookStagePostrun = "postrun"
+
+	// AllocHookStageDestroy runs the hook when the allocation is destroyed
+	// on the client.
+	AllocHookStageDestroy = "destroy"
+
+	// AllocHookFailurePolicyFail fails the allocation if the hook fails
+	// during prerun.
+	AllocHookFailurePolicyFail = "fail"
+
+	// AllocHookFailurePolicyIgnore logs hook failures without affecting the
+	// allocation.
+	AllocHookFailurePolicyIgnore = "ignore"
+
+	// DefaultAllocHookTimeout is how long a hook may run if no timeout is
+	// configured.
+	DefaultAllocHookTimeout = 30 * time.Second
+)
+
+// AllocHookScript is a script the client runs on the host at allocation
+// lifecycle points.
+type AllocHookScript struct {
+	// Name is the unique name of the hook.
+	Name string
+
+	// Command is the path of the executable to run.
+	Command string
+
+	// Args are the arguments passed to the command.
+	Args []string
+
+	// Stages are the allocation lifecycle points the command is run at.
+	Stages []string
+
+	// Timeout is how long the command may run before it is killed.
+	Timeout time.Duration
+
+	// FailurePolicy is the policy for handling a failure of the command.
+	FailurePolicy string
+}
+
+// AllocHookScriptsFromAgent creates the internal read-only copies of the
+// client agent's AllocHookScriptConfigs.
+func AllocHookScriptsFromAgent(hooks []*sconfig.AllocHookScriptConfig) ([]*AllocHookScript, error) {
+	if len(hooks) == 0 {
+		return nil, nil
+	}
+
+	scripts := make([]*AllocHookScript, 0, len(hooks))
+	for _, h := range hooks {
+		script, err := allocHookScriptFromAgent(h)
+		if err != nil {
+			return nil, fmt.Errorf("alloc_hook %q: %v", h.Name, err)
+		}
+		scripts = append(scripts, script)
+	}
+	return scripts, nil
+}
+
+func allocHookScriptFromAgent(h *sconfig.AllocHookScriptConfig) (*AllocHookScript, error) {
+	script := &AllocHookScript{
+		Name:          h.Name,
+		Command:       h.Command,
+		Args:          slices.Clone(h.Args),
+		Timeout:       DefaultAllocHookTimeout,
+		FailurePolicy: AllocHookFailurePolicyFail,
+	}
+
+	if h.Command == "" {
+		return nil, errors.New("command must be set")
+	}
+
+	if len(h.Stages) == 0 {
+		return nil, errors.New("stages must be set")
+	}
+	for _, stage := range h.Stages {
+		switch stage {
+		case AllocHookStagePrerun, AllocHookStagePostrun, AllocHookStageDestroy:
+		default:
+			return nil, fmt.Errorf("invalid stage %q: must be one of %q, %q, or %q",
+				stage, AllocHookStagePrerun, AllocHookStagePostrun, AllocHookStageDestroy)
+		}
+	}
+	script.Stages = slices.Clone(h.Stages)
+
+	if h.Timeout != nil {
+		timeout, err := time.ParseDuration(*h.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout: %v", err)
+		}
+		if timeout <= 0 {
+			return nil, errors.New("timeout must be positive")
+		}
+		script.Timeout = timeout
+	}
+
+	if h.FailurePolicy != nil {
+		switch *h.FailurePolicy {
+		case AllocHookFailurePolicyFail, AllocHookFailurePolicyIgnore:
+			script.FailurePolicy = *h.FailurePolicy
+		default:
+			return nil, fmt.Errorf("failure_policy must be %q or %q, not %q",
+				AllocHookFailurePolicyFail, AllocHookFailurePolicyIgnore, *h.FailurePolicy)
+		}
+	}
+
+	return script, nil
+}
+
+// HasStage returns whether the script runs at the given stage.
+func (a *AllocHookScript) HasStage(stage string) bool {
+	return slices.Contains(a.Stages, stage)
+}
+
+func (a *AllocHookScript) Copy() *AllocHookScript {
+	if a == nil {
+		return nil
+	}
+	na := new(AllocHookScript)
+	*na = *a
+	na.Args = slices.Clone(a.Args)
+	na.Stages = slices.Clone(a.Stages)
+	return na
+}
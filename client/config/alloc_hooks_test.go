@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	sconfig "github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/shoenig/test/must"
+)
+
+func TestAllocHookScriptsFromAgent(t *testing.T) {
+	ci.Parallel(t)
+
+	scripts, err := AllocHookScriptsFromAgent(nil)
+	must.NoError(t, err)
+	must.Nil(t, scripts)
+
+	scripts, err = AllocHookScriptsFromAgent([]*sconfig.AllocHookScriptConfig{
+		{
+			Name:    "register",
+			Command: "/usr/local/bin/register",
+			Stages:  []string{"prerun", "destroy"},
+		},
+		{
+			Name:          "audit",
+			Command:       "/usr/local/bin/audit",
+			Args:          []string{"-v"},
+			Stages:        []string{"postrun"},
+			Timeout:       pointer.Of("5s"),
+			FailurePolicy: pointer.Of("ignore"),
+		},
+	})
+	must.NoError(t, err)
+	must.Eq(t, []*AllocHookScript{
+		{
+			Name:          "register",
+			Command:       "/usr/local/bin/register",
+			Stages:        []string{"prerun", "destroy"},
+			Timeout:       DefaultAllocHookTimeout,
+			FailurePolicy: AllocHookFailurePolicyFail,
+		},
+		{
+			Name:          "audit",
+			Command:       "/usr/local/bin/audit",
+			Args:          []string{"-v"},
+			Stages:        []string{"postrun"},
+			Timeout:       5 * time.Second,
+			FailurePolicy: AllocHookFailurePolicyIgnore,
+		},
+	}, scripts)
+	must.True(t, scripts[0].HasStage(AllocHookStageDestroy))
+	must.False(t, scripts[0].HasStage(AllocHookStagePostrun))
+
+	for _, invalid := range []*sconfig.AllocHookScriptConfig{
+		{Name: "no-command", Stages: []string{"prerun"}},
+		{Name: "no-stages", Command: "/bin/true"},
+		{Name: "bad-stage", Command: "/bin/true", Stages: []string{"prestart"}},
+		{Name: "bad-timeout", Command: "/bin/true", Stages: []string{"prerun"}, Timeout: pointer.Of("soon")},
+		{Name: "zero-timeout", Command: "/bin/true", Stages: []string{"prerun"}, Timeout: pointer.Of("0s")},
+		{Name: "bad-policy", Command: "/bin/true", Stages: []string{"prerun"}, FailurePolicy: pointer.Of("retry")},
+	} {
+		_, err := AllocHookScriptsFromAgent([]*sconfig.AllocHookScriptConfig{invalid})
+		must.Error(t, err, must.Sprint(invalid.Name))
+	}
+}
@@ -21,12 +21,26 @@ type ArtifactConfig struct {
 	GitTimeout time.Duration
 	HgTimeout  time.Duration
 	S3Timeout  time.Duration
+	OCITimeout time.Duration
 
 	DecompressionLimitFileCount int
 	DecompressionLimitSize      int64
 
 	DisableFilesystemIsolation bool
 	SetEnvironmentVariables    string
+
+	// BandwidthLimit is the bytes per second shared by the concurrent
+	// downloads of the client, or 0 if unlimited.
+	BandwidthLimit int64
+
+	// CacheMaxBytes is the maximum size of the artifact cache, or 0 if the
+	// cache is disabled.
+	CacheMaxBytes int64
+
+	// CredentialHelper is the path to the executable exchanging workload
+	// identities for artifact credentials, or "" if authenticated artifacts
+	// are disabled.
+	CredentialHelper string
 }
 
 // ArtifactConfigFromAgent creates a new internal readonly copy of the client
@@ -67,6 +81,21 @@ func ArtifactConfigFromAgent(c *config.ArtifactConfig) (*ArtifactConfig, error)
 		return nil, fmt.Errorf("error parsing DecompressionLimitSize: %w", err)
 	}
 
+	ociTimeout, err := time.ParseDuration(*c.OCITimeout)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing OCITimeout: %w", err)
+	}
+
+	bandwidthLimit, err := humanize.ParseBytes(*c.BandwidthLimit)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing BandwidthLimit: %w", err)
+	}
+
+	cacheMaxSize, err := humanize.ParseBytes(*c.CacheMaxSize)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing CacheMaxSize: %w", err)
+	}
+
 	return &ArtifactConfig{
 		HTTPReadTimeout:             httpReadTimeout,
 		HTTPMaxBytes:                int64(httpMaxSize),
@@ -74,10 +103,14 @@ func ArtifactConfigFromAgent(c *config.ArtifactConfig) (*ArtifactConfig, error)
 		GitTimeout:                  gitTimeout,
 		HgTimeout:                   hgTimeout,
 		S3Timeout:                   s3Timeout,
+		OCITimeout:                  ociTimeout,
 		DecompressionLimitFileCount: *c.DecompressionFileCountLimit,
 		DecompressionLimitSize:      int64(decompressionSizeLimit),
 		DisableFilesystemIsolation:  *c.DisableFilesystemIsolation,
 		SetEnvironmentVariables:     *c.SetEnvironmentVariables,
+		BandwidthLimit:              int64(bandwidthLimit),
+		CacheMaxBytes:               int64(cacheMaxSize),
+		CredentialHelper:            *c.CredentialHelper,
 	}, nil
 }
 
@@ -32,8 +32,10 @@ func TestArtifactConfigFromAgent(t *testing.T) {
 				GitTimeout:                  30 * time.Minute,
 				HgTimeout:                   30 * time.Minute,
 				S3Timeout:                   30 * time.Minute,
+				OCITimeout:                  30 * time.Minute,
 				DecompressionLimitFileCount: 4096,
 				DecompressionLimitSize:      100_000_000_000,
+				CacheMaxBytes:               10_000_000_000,
 			},
 		},
 		{
@@ -108,6 +110,15 @@ func TestArtifactConfigFromAgent(t *testing.T) {
 			},
 			expErr: "error parsing S3Timeout",
 		},
+		{
+			name: "invalid bandwidth limit",
+			config: func() *config.ArtifactConfig {
+				c := config.DefaultArtifactConfig()
+				c.BandwidthLimit = pointer.Of("invalid")
+				return c
+			}(),
+			expErr: "error parsing BandwidthLimit",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -21,6 +21,7 @@ import (
 	"github.com/hashicorp/nomad/client/lib/numalib"
 	"github.com/hashicorp/nomad/client/lib/numalib/hw"
 	"github.com/hashicorp/nomad/client/state"
+	"github.com/hashicorp/nomad/command/agent/flightrecorder"
 	"github.com/hashicorp/nomad/command/agent/host"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/bufconndialer"
@@ -107,6 +108,15 @@ type Config struct {
 	// should be owned  by root with file mode 0o755.
 	AllocMountsDir string
 
+	// LogSpillDir is where task logs are written when the task's log
+	// backpressure policy is "spill" and logmon can't keep up with the task.
+	// Spilling is disabled if empty.
+	LogSpillDir string
+
+	// LogSinks are the names of the log sink plugins that the output of
+	// tasks is shipped to, unless the task configures its own log sinks.
+	LogSinks []string
+
 	// Logger provides a logger to the client
 	Logger log.InterceptLogger
 
@@ -144,6 +154,10 @@ type Config struct {
 	// Servers is a list of known server addresses. These are as "host:port"
 	Servers []string
 
+	// PreferReadReplicas makes the client watch for allocation updates on the
+	// read replicas of the region, if there are any.
+	PreferReadReplicas bool
+
 	// RPCHandler can be provided to avoid network traffic if the
 	// server is running locally.
 	RPCHandler RPCHandler
@@ -211,6 +225,10 @@ type Config struct {
 	// collector will allow.
 	GCParallelDestroys int
 
+	// ParallelRestores is the number of allocations restored in parallel
+	// when the client starts.
+	ParallelRestores int
+
 	// GCDiskUsageThreshold is the disk usage threshold given as a percent
 	// beyond which the Nomad client triggers GC of terminal allocations
 	GCDiskUsageThreshold float64
@@ -243,6 +261,22 @@ type Config struct {
 	// DisableRemoteExec disables remote exec targeting tasks on this client
 	DisableRemoteExec bool
 
+	// RecordExecSessions records the output of exec sessions targeting tasks
+	// on this client.
+	RecordExecSessions bool
+
+	// ExecRecordingDir is the directory exec session recordings are written
+	// to. Defaults to the log dir of the allocation.
+	ExecRecordingDir string
+
+	// EncryptSecretsDir encrypts task secrets and private directories with
+	// the Encrypting File System. Only supported on Windows.
+	EncryptSecretsDir bool
+
+	// AllocMetadataNodeAttributes are glob patterns of the node attributes
+	// written to the alloc metadata file of every task.
+	AllocMetadataNodeAttributes []string
+
 	// TemplateConfig includes configuration for template rendering
 	TemplateConfig *ClientTemplateConfig
 
@@ -314,6 +348,14 @@ type Config struct {
 	// ReservableCores if set overrides the set of reservable cores reported in fingerprinting.
 	ReservableCores []hw.CoreID
 
+	// ExclusiveCores is the pool of cores that are never shared, and are only
+	// reserved by tasks asking for exclusive cores.
+	ExclusiveCores []hw.CoreID
+
+	// ExclusiveIsolatedCores adds the cores isolated by the kernel (isolcpus)
+	// to the pool of exclusive cores.
+	ExclusiveIsolatedCores bool
+
 	// NomadServiceDiscovery determines whether the Nomad native service
 	// discovery client functionality is enabled.
 	NomadServiceDiscovery bool
@@ -330,15 +372,44 @@ type Config struct {
 	// http.Serve(listener) API instead of the net.Dial API.
 	APIListenerRegistrar APIListenerRegistrar
 
+	// Tracing configures the export of spans for the placement of
+	// allocations on the client.
+	Tracing *structsc.TracingConfig
+
+	// FlightRecorder records the latencies of the RPCs made by the client.
+	// It is shared with the server of the agent and is nil if the flight
+	// recorder isn't enabled.
+	FlightRecorder *flightrecorder.Recorder
+
 	// Artifact configuration from the agent's config file.
 	Artifact *ArtifactConfig
 
 	// Drain configuration from the agent's config file.
 	Drain *DrainConfig
 
+	// Edge configures the client-local scheduler used while disconnected
+	// from the servers. Nil if disabled.
+	Edge *EdgeConfig
+
+	// Eviction configures the eviction manager that evicts allocations when
+	// the host runs low on memory or disk. Nil if disabled.
+	Eviction *EvictionConfig
+
+	// ServiceDNS configures the DNS server serving the records of Nomad
+	// native service discovery. Nil if disabled.
+	ServiceDNS *ServiceDNSConfig
+
 	// Uesrs configuration from the agent's config file.
 	Users *UsersConfig
 
+	// DynamicHostVolumes configures creating host volumes requested by jobs,
+	// or is nil if the client doesn't create host volumes.
+	DynamicHostVolumes *DynamicHostVolumesConfig
+
+	// AllocHookScripts are scripts run on the host at allocation lifecycle
+	// points.
+	AllocHookScripts []*AllocHookScript
+
 	// ExtraAllocHooks are run with other allocation hooks, mainly for testing.
 	ExtraAllocHooks []interfaces.RunnerHook
 }
@@ -767,8 +838,17 @@ func (c *Config) Copy() *Config {
 	nc.VaultConfigs = helper.DeepCopyMap(c.VaultConfigs)
 	nc.TemplateConfig = c.TemplateConfig.Copy()
 	nc.ReservableCores = slices.Clone(c.ReservableCores)
+	nc.ExclusiveCores = slices.Clone(c.ExclusiveCores)
+	nc.AllocMetadataNodeAttributes = slices.Clone(c.AllocMetadataNodeAttributes)
+	nc.LogSinks = slices.Clone(c.LogSinks)
 	nc.Artifact = c.Artifact.Copy()
 	nc.Users = c.Users.Copy()
+	nc.Edge = c.Edge.Copy()
+	nc.Eviction = c.Eviction.Copy()
+	nc.ServiceDNS = c.ServiceDNS.Copy()
+	nc.DynamicHostVolumes = c.DynamicHostVolumes.Copy()
+	nc.AllocHookScripts = helper.CopySlice(c.AllocHookScripts)
+	nc.Tracing = c.Tracing.Copy()
 	return &nc
 }
 
@@ -785,6 +865,7 @@ func DefaultConfig() *Config {
 		TLSConfig:               &structsc.TLSConfig{},
 		GCInterval:              1 * time.Minute,
 		GCParallelDestroys:      2,
+		ParallelRestores:        8,
 		GCDiskUsageThreshold:    80,
 		GCInodeUsageThreshold:   70,
 		GCMaxAllocs:             50,
@@ -11,28 +11,27 @@ import (
 )
 
 // GetVaultConfigs returns the set of Vault configurations available for this
-// client. In Nomad CE we only use the default Vault.
-func (c *Config) GetVaultConfigs(logger hclog.Logger) map[string]*structsc.VaultConfig {
-	if c.VaultConfigs["default"] == nil || !c.VaultConfigs["default"].IsEnabled() {
-		return nil
-	}
-
-	if len(c.VaultConfigs) > 1 {
-		logger.Warn("multiple Vault configurations are only supported in Nomad Enterprise")
+// client, keyed by cluster name. Only enabled clusters are returned.
+func (c *Config) GetVaultConfigs(_ hclog.Logger) map[string]*structsc.VaultConfig {
+	var vaultConfigs map[string]*structsc.VaultConfig
+	for name, vaultConfig := range c.VaultConfigs {
+		if vaultConfig == nil || !vaultConfig.IsEnabled() {
+			continue
+		}
+		if vaultConfigs == nil {
+			vaultConfigs = make(map[string]*structsc.VaultConfig, len(c.VaultConfigs))
+		}
+		vaultConfigs[name] = vaultConfig
 	}
-	return c.VaultConfigs
+	return vaultConfigs
 }
 
 // GetConsulConfigs returns the set of Consul configurations the fingerprint needs
-// to check. In Nomad CE we only check the default Consul.
-func (c *Config) GetConsulConfigs(logger hclog.Logger) map[string]*structsc.ConsulConfig {
-	if c.ConsulConfigs["default"] == nil {
+// to check, keyed by cluster name.
+func (c *Config) GetConsulConfigs(_ hclog.Logger) map[string]*structsc.ConsulConfig {
+	if len(c.ConsulConfigs) == 0 {
 		return nil
 	}
 
-	if len(c.ConsulConfigs) > 1 {
-		logger.Warn("multiple Consul configurations are only supported in Nomad Enterprise")
-	}
-
 	return c.ConsulConfigs
 }
@@ -0,0 +1,105 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	sconfig "github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// DynamicHostVolumeCleanupRetain keeps created host volumes once they're
+	// no longer used.
+	DynamicHostVolumeCleanupRetain = "retain"
+
+	// DynamicHostVolumeCleanupDelete deletes created host volumes once no
+	// allocation on the client uses them.
+	DynamicHostVolumeCleanupDelete = "delete"
+)
+
+// DynamicHostVolumesConfig configures the client to create the host volumes
+// requested by jobs.
+type DynamicHostVolumesConfig struct {
+	// Path is the directory under which host volumes are created.
+	Path string
+
+	// MaxSizeMB is the maximum size of a created host volume, or zero if the
+	// size is not limited.
+	MaxSizeMB int
+
+	// Cleanup is the policy for deleting created host volumes.
+	Cleanup string
+
+	// PluginDir is the directory with the executables of external host
+	// volume plugins, or empty if only built-in plugins are used.
+	PluginDir string
+
+	// Plugin is the ID of the host volume plugin that creates the host
+	// volumes requested by jobs.
+	Plugin string
+}
+
+// DynamicHostVolumesConfigFromAgent creates the internal read-only copy of
+// the client agent's DynamicHostVolumesConfig.
+func DynamicHostVolumesConfigFromAgent(c *sconfig.DynamicHostVolumesConfig) (*DynamicHostVolumesConfig, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	conf := &DynamicHostVolumesConfig{
+		Cleanup: DynamicHostVolumeCleanupRetain,
+		Plugin:  structs.HostVolumePluginMkdirID,
+	}
+
+	if c.Path == nil || *c.Path == "" {
+		return nil, errors.New("path must be set")
+	}
+	if !filepath.IsAbs(*c.Path) {
+		return nil, fmt.Errorf("path %q must be absolute", *c.Path)
+	}
+	conf.Path = *c.Path
+
+	if c.MaxSize != nil {
+		if *c.MaxSize < 0 {
+			return nil, errors.New("max_size must not be negative")
+		}
+		conf.MaxSizeMB = *c.MaxSize
+	}
+
+	if c.Cleanup != nil {
+		switch *c.Cleanup {
+		case DynamicHostVolumeCleanupRetain, DynamicHostVolumeCleanupDelete:
+			conf.Cleanup = *c.Cleanup
+		default:
+			return nil, fmt.Errorf("cleanup must be %q or %q, not %q",
+				DynamicHostVolumeCleanupRetain, DynamicHostVolumeCleanupDelete, *c.Cleanup)
+		}
+	}
+
+	if c.PluginDir != nil && *c.PluginDir != "" {
+		if !filepath.IsAbs(*c.PluginDir) {
+			return nil, fmt.Errorf("plugin_dir %q must be absolute", *c.PluginDir)
+		}
+		conf.PluginDir = *c.PluginDir
+	}
+
+	if c.Plugin != nil && *c.Plugin != "" {
+		conf.Plugin = *c.Plugin
+	}
+
+	return conf, nil
+}
+
+func (d *DynamicHostVolumesConfig) Copy() *DynamicHostVolumesConfig {
+	if d == nil {
+		return nil
+	}
+	nd := new(DynamicHostVolumesConfig)
+	*nd = *d
+	return nd
+}
@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/nomad/structs"
+	sconfig "github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/shoenig/test/must"
+)
+
+func TestDynamicHostVolumesConfigFromAgent(t *testing.T) {
+	ci.Parallel(t)
+
+	conf, err := DynamicHostVolumesConfigFromAgent(nil)
+	must.NoError(t, err)
+	must.Nil(t, conf)
+
+	conf, err = DynamicHostVolumesConfigFromAgent(&sconfig.DynamicHostVolumesConfig{
+		Path: pointer.Of("/opt/nomad/volumes"),
+	})
+	must.NoError(t, err)
+	must.Eq(t, &DynamicHostVolumesConfig{
+		Path:    "/opt/nomad/volumes",
+		Cleanup: DynamicHostVolumeCleanupRetain,
+		Plugin:  structs.HostVolumePluginMkdirID,
+	}, conf)
+
+	conf, err = DynamicHostVolumesConfigFromAgent(&sconfig.DynamicHostVolumesConfig{
+		Path:      pointer.Of("/opt/nomad/volumes"),
+		PluginDir: pointer.Of("/opt/nomad/host_volume_plugins"),
+		Plugin:    pointer.Of("lvm"),
+	})
+	must.NoError(t, err)
+	must.Eq(t, "/opt/nomad/host_volume_plugins", conf.PluginDir)
+	must.Eq(t, "lvm", conf.Plugin)
+
+	for _, invalid := range []*sconfig.DynamicHostVolumesConfig{
+		{},
+		{Path: pointer.Of("volumes")},
+		{Path: pointer.Of("/opt/nomad/volumes"), MaxSize: pointer.Of(-1)},
+		{Path: pointer.Of("/opt/nomad/volumes"), Cleanup: pointer.Of("never")},
+		{Path: pointer.Of("/opt/nomad/volumes"), PluginDir: pointer.Of("plugins")},
+	} {
+		_, err := DynamicHostVolumesConfigFromAgent(invalid)
+		must.Error(t, err)
+	}
+}
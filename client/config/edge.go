@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// EdgeConfig configures the client-local scheduler that keeps pinned system
+// jobs running while the client is disconnected from the servers.
+type EdgeConfig struct {
+	// PinnedJobs are the IDs of the system and sysbatch jobs the client keeps
+	// running while disconnected. If empty, all system jobs are pinned.
+	PinnedJobs []string
+
+	// DisconnectGrace is how long the client must fail to heartbeat before it
+	// considers itself disconnected and starts scheduling locally.
+	DisconnectGrace time.Duration
+
+	// RestartDelay is the minimum time between local restarts of the same
+	// allocation.
+	RestartDelay time.Duration
+}
+
+// EdgeConfigFromAgent creates the internal read-only copy of the client
+// agent's EdgeConfig. It returns nil if the local scheduler is disabled.
+func EdgeConfigFromAgent(c *config.EdgeConfig) (*EdgeConfig, error) {
+	if c == nil || c.Enabled == nil || !*c.Enabled {
+		return nil, nil
+	}
+
+	conf := &EdgeConfig{
+		PinnedJobs:      slices.Clone(c.PinnedJobs),
+		DisconnectGrace: time.Minute,
+		RestartDelay:    30 * time.Second,
+	}
+
+	if c.DisconnectGrace != nil {
+		d, err := time.ParseDuration(*c.DisconnectGrace)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing disconnect_grace: %w", err)
+		}
+		conf.DisconnectGrace = d
+	}
+	if c.RestartDelay != nil {
+		d, err := time.ParseDuration(*c.RestartDelay)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing restart_delay: %w", err)
+		}
+		conf.RestartDelay = d
+	}
+
+	return conf, nil
+}
+
+// Copy returns a deep copy of the EdgeConfig.
+func (e *EdgeConfig) Copy() *EdgeConfig {
+	if e == nil {
+		return nil
+	}
+
+	ne := new(EdgeConfig)
+	*ne = *e
+	ne.PinnedJobs = slices.Clone(e.PinnedJobs)
+	return ne
+}
+
+// IsPinned returns true if allocations of the job are kept running by the
+// client-local scheduler.
+func (e *EdgeConfig) IsPinned(jobID string) bool {
+	if e == nil {
+		return false
+	}
+	return len(e.PinnedJobs) == 0 || slices.Contains(e.PinnedJobs, jobID)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// EvictionConfig configures the client eviction manager, which evicts
+// allocations when the host runs low on memory or disk.
+type EvictionConfig struct {
+	// MemoryThreshold is the percentage of host memory in use above which
+	// allocations are evicted.
+	MemoryThreshold float64
+
+	// DiskThreshold is the percentage of the alloc dir disk in use above
+	// which allocations are evicted.
+	DiskThreshold float64
+
+	// GracePeriod is how long a threshold must be exceeded before an
+	// allocation is evicted, and the minimum time between evictions.
+	GracePeriod time.Duration
+}
+
+// EvictionConfigFromAgent creates the internal read-only copy of the client
+// agent's EvictionConfig. It returns nil if the eviction manager is disabled.
+func EvictionConfigFromAgent(c *config.EvictionConfig) (*EvictionConfig, error) {
+	if c == nil || c.Enabled == nil || !*c.Enabled {
+		return nil, nil
+	}
+
+	conf := &EvictionConfig{
+		MemoryThreshold: 95,
+		DiskThreshold:   95,
+		GracePeriod:     30 * time.Second,
+	}
+
+	if c.MemoryThreshold != nil {
+		if *c.MemoryThreshold <= 0 || *c.MemoryThreshold > 100 {
+			return nil, fmt.Errorf("memory_threshold must be between 1 and 100")
+		}
+		conf.MemoryThreshold = float64(*c.MemoryThreshold)
+	}
+	if c.DiskThreshold != nil {
+		if *c.DiskThreshold <= 0 || *c.DiskThreshold > 100 {
+			return nil, fmt.Errorf("disk_threshold must be between 1 and 100")
+		}
+		conf.DiskThreshold = float64(*c.DiskThreshold)
+	}
+	if c.GracePeriod != nil {
+		d, err := time.ParseDuration(*c.GracePeriod)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing grace_period: %w", err)
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("grace_period must not be negative")
+		}
+		conf.GracePeriod = d
+	}
+
+	return conf, nil
+}
+
+// Copy returns a copy of the EvictionConfig.
+func (e *EvictionConfig) Copy() *EvictionConfig {
+	if e == nil {
+		return nil
+	}
+
+	ne := new(EvictionConfig)
+	*ne = *e
+	return ne
+}
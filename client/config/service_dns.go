@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// ServiceDNSConfig configures the DNS server of the client, which serves
+// records for the services registered with Nomad native service discovery.
+type ServiceDNSConfig struct {
+	// Addr is the address the DNS server listens on, over UDP and TCP.
+	Addr string
+
+	// Domain is the domain of the records served by the DNS server, without
+	// the leading or trailing dot.
+	Domain string
+
+	// TTL is the time-to-live of the records served by the DNS server.
+	TTL time.Duration
+
+	// Token is the ACL token used to look up services.
+	Token string
+}
+
+// ServiceDNSConfigFromAgent creates the internal read-only copy of the client
+// agent's ServiceDNSConfig. It returns nil if the DNS server is disabled.
+func ServiceDNSConfigFromAgent(c *config.ServiceDNSConfig) (*ServiceDNSConfig, error) {
+	if c == nil || c.Enabled == nil || !*c.Enabled {
+		return nil, nil
+	}
+
+	address, port, domain := "127.0.0.1", 8600, "nomad"
+	if c.Address != nil {
+		if net.ParseIP(*c.Address) == nil {
+			return nil, fmt.Errorf("invalid address %q", *c.Address)
+		}
+		address = *c.Address
+	}
+	if c.Port != nil {
+		if *c.Port < 0 || *c.Port > 65535 {
+			return nil, fmt.Errorf("invalid port %d", *c.Port)
+		}
+		port = *c.Port
+	}
+	if c.Domain != nil {
+		domain = strings.Trim(*c.Domain, ".")
+		if domain == "" {
+			return nil, fmt.Errorf("domain must not be empty")
+		}
+	}
+
+	conf := &ServiceDNSConfig{
+		Addr:   net.JoinHostPort(address, strconv.Itoa(port)),
+		Domain: domain,
+	}
+
+	if c.TTL != nil {
+		d, err := time.ParseDuration(*c.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing ttl: %w", err)
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("ttl must not be negative")
+		}
+		conf.TTL = d
+	}
+	if c.Token != nil {
+		conf.Token = *c.Token
+	}
+
+	return conf, nil
+}
+
+// Copy returns a copy of the ServiceDNSConfig.
+func (s *ServiceDNSConfig) Copy() *ServiceDNSConfig {
+	if s == nil {
+		return nil
+	}
+
+	ns := new(ServiceDNSConfig)
+	*ns = *s
+	return ns
+}
@@ -47,6 +47,10 @@ type JWTLoginRequest struct {
 	JWT            string
 	AuthMethodName string
 	Meta           map[string]string
+
+	// Partition is the Consul admin partition of the auth method. The
+	// partition of the Consul agent is used if empty.
+	Partition string
 }
 
 // Client is the interface that the nomad client uses to interact with
@@ -108,7 +112,7 @@ func (c *consulClient) DeriveTokenWithJWT(req JWTLoginRequest) (*consulapi.ACLTo
 		AuthMethod:  req.AuthMethodName,
 		BearerToken: req.JWT,
 		Meta:        req.Meta,
-	}, nil)
+	}, &consulapi.WriteOptions{Partition: req.Partition})
 	return t, err
 }
 
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package devicemanager
+
+import (
+	"bytes"
+	"time"
+)
+
+// hotplugSettleTime is how long the device manager waits for device events to
+// stop before re-fingerprinting the device plugins. Plugging a device causes a
+// burst of events and its device nodes are only created once they have been
+// handled.
+const hotplugSettleTime = 2 * time.Second
+
+// hotplugSubsystems are the kernel subsystems of the devices exposed by device
+// plugins, such as USB accelerators and GPUs.
+var hotplugSubsystems = map[string]struct{}{
+	"accel": {},
+	"drm":   {},
+	"pci":   {},
+	"usb":   {},
+	"vfio":  {},
+}
+
+// isHotplugEvent returns whether the kernel uevent message reports a device of
+// one of the hotplugSubsystems being added or removed.
+func isHotplugEvent(msg []byte) bool {
+	var action, subsystem string
+	for _, field := range bytes.Split(msg, []byte{0}) {
+		key, value, ok := bytes.Cut(field, []byte("="))
+		if !ok {
+			// the message header is "action@devpath"
+			continue
+		}
+		switch string(key) {
+		case "ACTION":
+			action = string(value)
+		case "SUBSYSTEM":
+			subsystem = string(value)
+		}
+	}
+
+	switch action {
+	case "add", "remove", "bind", "unbind":
+	default:
+		return false
+	}
+	_, ok := hotplugSubsystems[subsystem]
+	return ok
+}
+
+// handleHotplug re-fingerprints the device plugins once the events received
+// on eventCh have settled for the settle duration.
+func (m *manager) handleHotplug(eventCh <-chan struct{}, settle time.Duration) {
+	timer := time.NewTimer(settle)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-eventCh:
+			timer.Reset(settle)
+		case <-timer.C:
+			m.logger.Debug("devices changed, re-fingerprinting device plugins")
+			m.refingerprint()
+		}
+	}
+}
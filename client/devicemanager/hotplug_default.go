@@ -0,0 +1,10 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !linux
+
+package devicemanager
+
+// watchDevices is a no-op as hot-plugged devices are only detected on Linux.
+// Devices added at runtime are detected when the plugins fingerprint again.
+func (m *manager) watchDevices() {}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build linux
+
+package devicemanager
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// ueventKernelGroup is the netlink multicast group of the uevents sent by the
+// kernel.
+const ueventKernelGroup = 1
+
+// watchDevices listens for the kernel uevents of devices being added or
+// removed and re-fingerprints the device plugins when they occur. It returns
+// when the device manager is shutdown.
+func (m *manager) watchDevices() {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		m.logger.Warn("failed to watch for hot-plugged devices", "error", err)
+		return
+	}
+	defer unix.Close(fd)
+
+	addr := &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: ueventKernelGroup}
+	if err := unix.Bind(fd, addr); err != nil {
+		m.logger.Warn("failed to watch for hot-plugged devices", "error", err)
+		return
+	}
+
+	// Time out reads so the shutdown of the manager is noticed
+	tv := unix.NsecToTimeval(time.Second.Nanoseconds())
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &tv); err != nil {
+		m.logger.Warn("failed to watch for hot-plugged devices", "error", err)
+		return
+	}
+
+	eventCh := make(chan struct{}, 1)
+	go m.handleHotplug(eventCh, hotplugSettleTime)
+
+	notify := func() {
+		select {
+		case eventCh <- struct{}{}:
+		default:
+		}
+	}
+
+	buf := make([]byte, 64*1024)
+	for m.ctx.Err() == nil {
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		switch {
+		case errors.Is(err, unix.EAGAIN), errors.Is(err, unix.EINTR):
+			continue
+		case errors.Is(err, unix.ENOBUFS):
+			// Events were dropped, so re-fingerprint in case any of them
+			// was a device change
+			notify()
+			continue
+		case err != nil:
+			m.logger.Warn("stopped watching for hot-plugged devices", "error", err)
+			return
+		}
+
+		if isHotplugEvent(buf[:n]) {
+			notify()
+		}
+	}
+}
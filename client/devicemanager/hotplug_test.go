@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package devicemanager
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestIsHotplugEvent(t *testing.T) {
+	ci.Parallel(t)
+
+	uevent := func(fields ...string) []byte {
+		return []byte(strings.Join(fields, "\x00"))
+	}
+
+	cases := []struct {
+		name  string
+		msg   []byte
+		match bool
+	}{
+		{
+			name: "usb add",
+			msg: uevent("add@/devices/pci0000:00/0000:00:14.0/usb1/1-2",
+				"ACTION=add", "DEVPATH=/devices/pci0000:00/0000:00:14.0/usb1/1-2", "SUBSYSTEM=usb"),
+			match: true,
+		},
+		{
+			name:  "drm remove",
+			msg:   uevent("remove@/devices/card1", "ACTION=remove", "SUBSYSTEM=drm"),
+			match: true,
+		},
+		{
+			name:  "usb change",
+			msg:   uevent("change@/devices/usb1", "ACTION=change", "SUBSYSTEM=usb"),
+			match: false,
+		},
+		{
+			name:  "block add",
+			msg:   uevent("add@/devices/block/sdb", "ACTION=add", "SUBSYSTEM=block"),
+			match: false,
+		},
+		{
+			name:  "malformed",
+			msg:   []byte("garbage"),
+			match: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			must.Eq(t, tc.match, isHotplugEvent(tc.msg))
+		})
+	}
+}
@@ -53,6 +53,10 @@ type instanceManagerConfig struct {
 	// FingerprintOutCh is used to emit new fingerprinted devices
 	FingerprintOutCh chan<- struct{}
 
+	// DevicesVanished is called with the devices that are no longer
+	// fingerprinted by the plugin
+	DevicesVanished DevicesVanishedFn
+
 	// StatsInterval is the interval at which we collect statistics.
 	StatsInterval time.Duration
 }
@@ -83,6 +87,14 @@ type instanceManager struct {
 	// fingerprintOutCh is used to emit new fingerprinted devices
 	fingerprintOutCh chan<- struct{}
 
+	// devicesVanished is called with the devices that are no longer
+	// fingerprinted by the plugin
+	devicesVanished DevicesVanishedFn
+
+	// refingerprintCh is used to restart fingerprinting, so the plugin
+	// detects devices that were added or removed
+	refingerprintCh chan struct{}
+
 	// plugin is the plugin instance being managed
 	plugin loader.PluginInstance
 
@@ -127,6 +139,8 @@ func newInstanceManager(c *instanceManagerConfig) *instanceManager {
 		pluginConfig:       c.PluginConfig,
 		id:                 c.Id,
 		fingerprintOutCh:   c.FingerprintOutCh,
+		devicesVanished:    c.DevicesVanished,
+		refingerprintCh:    make(chan struct{}, 1),
 		statsInterval:      c.StatsInterval,
 		firstFingerprintCh: make(chan struct{}),
 	}
@@ -221,6 +235,15 @@ func (i *instanceManager) Devices() []*device.DeviceGroup {
 	return i.devices
 }
 
+// Refingerprint restarts the fingerprinting of the plugin, so devices that
+// were added or removed since it last fingerprinted are detected.
+func (i *instanceManager) Refingerprint() {
+	select {
+	case i.refingerprintCh <- struct{}{}:
+	default:
+	}
+}
+
 // WaitForFirstFingerprint waits until either the plugin fingerprints, the
 // passed context is done, or the plugin instance manager is shutdown.
 func (i *instanceManager) WaitForFirstFingerprint(ctx context.Context) {
@@ -329,7 +352,13 @@ func (i *instanceManager) cleanup() {
 
 // fingerprint is a long lived routine used to fingerprint the device
 func (i *instanceManager) fingerprint() {
+	// streamCancel stops the current fingerprint stream
+	streamCancel := func() {}
+	defer func() { streamCancel() }()
+
 START:
+	streamCancel()
+
 	// Get a device plugin
 	devicePlugin, err := i.dispense()
 	if err != nil {
@@ -338,8 +367,11 @@ START:
 		return
 	}
 
-	// Start fingerprinting
-	fingerprintCh, err := devicePlugin.Fingerprint(i.ctx)
+	// Start fingerprinting. The stream is restarted when a re-fingerprint is
+	// requested, as plugins send the current devices when it starts.
+	streamCtx, cancel := context.WithCancel(i.ctx)
+	streamCancel = cancel
+	fingerprintCh, err := devicePlugin.Fingerprint(streamCtx)
 	if err == device.ErrPluginDisabled {
 		i.logger.Info("fingerprinting failed: plugin is not enabled")
 		i.handleFingerprintError()
@@ -356,6 +388,9 @@ START:
 		select {
 		case <-i.ctx.Done():
 			return
+		case <-i.refingerprintCh:
+			i.logger.Debug("restarting fingerprinting after device change")
+			goto START
 		case fresp, ok = <-fingerprintCh:
 		}
 
@@ -428,7 +463,9 @@ func (i *instanceManager) handleFingerprint(f *device.FingerprintResponse) error
 	}
 
 	i.deviceLock.Lock()
-	defer i.deviceLock.Unlock()
+
+	// Find the devices that were removed since the previous fingerprint
+	vanished := vanishedDevices(i.devices, f.Devices)
 
 	// Store the new devices
 	i.devices = f.Devices
@@ -445,9 +482,53 @@ func (i *instanceManager) handleFingerprint(f *device.FingerprintResponse) error
 	default:
 	}
 
+	i.deviceLock.Unlock()
+
+	if len(vanished) != 0 && i.devicesVanished != nil {
+		for _, d := range vanished {
+			i.logger.Warn("devices removed from node", "device", d.ID().String(), "instances", d.DeviceIDs)
+		}
+		i.devicesVanished(vanished)
+	}
+
 	return nil
 }
 
+// vanishedDevices returns the device instances of old that are not part of
+// the devices in current.
+func vanishedDevices(old, current []*device.DeviceGroup) []*structs.AllocatedDeviceResource {
+	present := make(map[structs.DeviceIdTuple]map[string]struct{}, len(current))
+	for _, group := range current {
+		id := structs.DeviceIdTuple{Vendor: group.Vendor, Type: group.Type, Name: group.Name}
+		if present[id] == nil {
+			present[id] = make(map[string]struct{}, len(group.Devices))
+		}
+		for _, d := range group.Devices {
+			present[id][d.ID] = struct{}{}
+		}
+	}
+
+	var vanished []*structs.AllocatedDeviceResource
+	for _, group := range old {
+		id := structs.DeviceIdTuple{Vendor: group.Vendor, Type: group.Type, Name: group.Name}
+		var ids []string
+		for _, d := range group.Devices {
+			if _, ok := present[id][d.ID]; !ok {
+				ids = append(ids, d.ID)
+			}
+		}
+		if len(ids) != 0 {
+			vanished = append(vanished, &structs.AllocatedDeviceResource{
+				Vendor:    group.Vendor,
+				Type:      group.Type,
+				Name:      group.Name,
+				DeviceIDs: ids,
+			})
+		}
+	}
+	return vanished
+}
+
 // collectStats is a long lived goroutine for collecting device statistics. It
 // handles errors by backing off exponentially and retrying.
 func (i *instanceManager) collectStats() {
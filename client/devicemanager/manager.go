@@ -51,6 +51,10 @@ type StateStorage interface {
 // UpdateNodeDevices is a callback for updating the set of devices on a node.
 type UpdateNodeDevicesFn func(devices []*structs.NodeDeviceResource)
 
+// DevicesVanishedFn is a callback for handling devices that were removed from
+// the node while fingerprinted, such as unplugged USB devices.
+type DevicesVanishedFn func(devices []*structs.AllocatedDeviceResource)
+
 // StorePluginReattachFn is used to store plugin reattachment configurations.
 type StorePluginReattachFn func(*plugin.ReattachConfig) error
 
@@ -68,6 +72,10 @@ type Config struct {
 	// Updater is used to update the node when device information changes
 	Updater UpdateNodeDevicesFn
 
+	// DevicesVanished is called with the devices that were removed from the
+	// node, so allocations holding them can be failed
+	DevicesVanished DevicesVanishedFn
+
 	// StatsInterval is the interval at which to collect statistics
 	StatsInterval time.Duration
 
@@ -96,6 +104,10 @@ type manager struct {
 	// updater is used to update the node when device information changes
 	updater UpdateNodeDevicesFn
 
+	// devicesVanished is called with the devices that were removed from the
+	// node
+	devicesVanished DevicesVanishedFn
+
 	// statsInterval is the duration at which to collect statistics
 	statsInterval time.Duration
 
@@ -121,6 +133,7 @@ func New(c *Config) *manager {
 		loader:           c.Loader,
 		pluginConfig:     c.PluginConfig,
 		updater:          c.Updater,
+		devicesVanished:  c.DevicesVanished,
 		statsInterval:    c.StatsInterval,
 		instances:        make(map[loader.PluginID]*instanceManager),
 		reattachConfigs:  make(map[loader.PluginID]*pstructs.ReattachConfig),
@@ -161,12 +174,16 @@ func (m *manager) Run() {
 			PluginConfig:     m.pluginConfig,
 			Id:               &id,
 			FingerprintOutCh: m.fingerprintResCh,
+			DevicesVanished:  m.devicesVanished,
 			StatsInterval:    m.statsInterval,
 		})
 	}
 
 	// Now start the fingerprint handler
 	go m.fingerprint()
+
+	// Re-fingerprint when devices are hot-plugged
+	go m.watchDevices()
 }
 
 // fingerprint is the main fingerprint loop
@@ -195,6 +212,14 @@ func (m *manager) fingerprint() {
 	}
 }
 
+// refingerprint restarts the fingerprinting of all the device plugins, so
+// devices added or removed at runtime are detected.
+func (m *manager) refingerprint() {
+	for _, i := range m.instances {
+		i.Refingerprint()
+	}
+}
+
 // Shutdown cleans up all the plugins
 func (m *manager) Shutdown() {
 	// Cancel the context to stop any requests
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -493,3 +494,81 @@ func TestManager_Run_ShutdownOld(t *testing.T) {
 		t.Fatal(err)
 	})
 }
+
+// Test that devices removed from the node are detected when re-fingerprinting
+func TestManager_Refingerprint_DevicesVanished(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	config, updateCh, catalog := baseTestConfig(t)
+
+	vanishedCh := make(chan []*structs.AllocatedDeviceResource, 1)
+	config.DevicesVanished = func(devices []*structs.AllocatedDeviceResource) {
+		vanishedCh <- devices
+	}
+
+	// The plugin fingerprints the devices currently plugged in
+	var lock sync.Mutex
+	plugged := []*device.DeviceGroup{nvidiaDeviceGroup}
+	fingerprinter := func(ctx context.Context) (<-chan *device.FingerprintResponse, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		return device.StaticFingerprinter(plugged)(ctx)
+	}
+
+	pluginInfo := pluginInfoResponse("nvidia")
+	devicePlugin := &device.MockDevicePlugin{
+		MockPlugin: &base.MockPlugin{
+			PluginInfoF:   base.StaticInfo(pluginInfo),
+			ConfigSchemaF: base.TestConfigSchema(),
+			SetConfigF:    base.NoopSetConfig(),
+		},
+		FingerprintF: fingerprinter,
+		StatsF:       device.StaticStats([]*device.DeviceGroupStats{nvidiaDeviceGroupStats}),
+	}
+	configureCatalogWith(catalog, map[*base.PluginInfoResponse]loader.PluginInstance{
+		pluginInfo: loader.MockBasicExternalPlugin(devicePlugin, device.ApiVersion010),
+	})
+
+	m := New(config)
+	m.Run()
+	defer m.Shutdown()
+
+	select {
+	case devices := <-updateCh:
+		require.Len(devices, 1)
+		require.Len(devices[0].Instances, 2)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the first fingerprint")
+	}
+
+	// Unplug one of the devices
+	lock.Lock()
+	plugged = []*device.DeviceGroup{{
+		Vendor:     nvidiaDeviceGroup.Vendor,
+		Type:       nvidiaDeviceGroup.Type,
+		Name:       nvidiaDeviceGroup.Name,
+		Devices:    nvidiaDeviceGroup.Devices[:1],
+		Attributes: nvidiaDeviceGroup.Attributes,
+	}}
+	lock.Unlock()
+	m.refingerprint()
+
+	select {
+	case devices := <-updateCh:
+		require.Len(devices, 1)
+		require.Len(devices[0].Instances, 1)
+		require.Equal(nvidiaDevice0ID, devices[0].Instances[0].ID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the re-fingerprint")
+	}
+
+	select {
+	case vanished := <-vanishedCh:
+		require.Len(vanished, 1)
+		require.Equal("nvidia", vanished[0].Vendor)
+		require.Equal([]string{nvidiaDevice1ID}, vanished[0].DeviceIDs)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the vanished devices")
+	}
+}
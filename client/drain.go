@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -123,29 +124,31 @@ func (c *Client) pollServerForDrainStatus(ctx context.Context, interval time.Dur
 func (c *Client) pollLocalStatusForDrainStatus(ctx context.Context,
 	interval time.Duration, drainSpec *config.DrainConfig) error {
 
-	// drainIsDone is its own function scope so we can release the allocLock
-	// between poll attempts
+	// drainIsDone is its own function scope so we can release the alloc
+	// runner locks between poll attempts
 	drainIsDone := func() bool {
-		c.allocLock.RLock()
-		defer c.allocLock.RUnlock()
-		for _, runner := range c.allocs {
+		done := true
+		c.allocs.forEach(func(_ string, runner interfaces.AllocRunner) bool {
 
 			// note: allocs in runners should never be nil or have a nil Job but
 			// if they do we can safely assume the runner is done with it
 			alloc := runner.Alloc()
 			if alloc != nil && !alloc.ClientTerminalStatus() {
 				if !drainSpec.IgnoreSystemJobs {
+					done = false
 					return false
 				}
 				if alloc.Job == nil {
-					continue
+					return true
 				}
 				if alloc.Job.Type != structs.JobTypeSystem {
+					done = false
 					return false
 				}
 			}
-		}
-		return true
+			return true
+		})
+		return done
 	}
 
 	timer, stop := helper.NewSafeTimer(0)
@@ -60,9 +60,7 @@ func TestClient_SelfDrainConfig(t *testing.T) {
 		t.Fatal("expected drain complete before deadline")
 	}
 
-	c1.allocLock.RLock()
-	defer c1.allocLock.RUnlock()
-	for _, runner := range c1.allocs {
+	for _, runner := range c1.getAllocRunners() {
 		if runner.Alloc().JobID == sysJobID {
 			must.Eq(t, structs.AllocClientStatusRunning, runner.AllocState().ClientStatus)
 		} else {
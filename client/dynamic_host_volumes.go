@@ -0,0 +1,330 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/hostvolumemanager"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// dynamicHostVolumes tracks the host volumes the client created, either for
+// allocations requesting a host volume with `create = true` or through the
+// host volume API.
+type dynamicHostVolumes struct {
+	// manager creates and deletes volumes with host volume plugins, or is
+	// nil if the client doesn't create host volumes
+	manager *hostvolumemanager.HostVolumeManager
+
+	// claims are the IDs of the allocations on the client using each created
+	// volume, keyed by volume name
+	claims map[string]map[string]struct{}
+
+	lock sync.Mutex
+}
+
+func newDynamicHostVolumes() *dynamicHostVolumes {
+	return &dynamicHostVolumes{
+		claims: make(map[string]map[string]struct{}),
+	}
+}
+
+// setupDynamicHostVolumes registers the host volumes created by a previous
+// run of the client with the node.
+func (c *Client) setupDynamicHostVolumes() error {
+	conf := c.GetConfig().DynamicHostVolumes
+	if conf == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(conf.Path, 0o755); err != nil {
+		return fmt.Errorf("failed to create dynamic host volumes directory: %v", err)
+	}
+	entries, err := os.ReadDir(conf.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read dynamic host volumes directory: %v", err)
+	}
+
+	manager, err := hostvolumemanager.NewHostVolumeManager(c.logger, &hostvolumemanager.Config{
+		PluginDir:  conf.PluginDir,
+		VolumesDir: conf.Path,
+		StateDir:   filepath.Join(c.GetConfig().StateDir, "host_volumes"),
+	})
+	if err != nil {
+		return err
+	}
+
+	c.dynamicHostVolumes.lock.Lock()
+	defer c.dynamicHostVolumes.lock.Unlock()
+
+	c.dynamicHostVolumes.manager = manager
+
+	c.UpdateConfig(func(newConfig *config.Config) {
+		register := func(name, path string) {
+			if _, ok := newConfig.Node.HostVolumes[name]; ok {
+				c.logger.Warn("ignoring dynamic host volume with the same name as a configured host volume", "volume", name)
+				return
+			}
+			if newConfig.Node.HostVolumes == nil {
+				newConfig.Node.HostVolumes = make(map[string]*structs.ClientHostVolumeConfig)
+			}
+			newConfig.Node.HostVolumes[name] = &structs.ClientHostVolumeConfig{
+				Name: name,
+				Path: path,
+			}
+			c.dynamicHostVolumes.claims[name] = make(map[string]struct{})
+		}
+
+		// Volumes created by plugins, which may live outside of the
+		// volumes directory
+		for _, vol := range manager.Volumes() {
+			register(vol.Name, vol.Path)
+		}
+
+		// Directories created before volumes were tracked by the manager
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			name := entry.Name()
+			if _, ok := manager.Get(name); ok {
+				continue
+			}
+			register(name, filepath.Join(conf.Path, name))
+		}
+	})
+
+	return nil
+}
+
+// claimHostVolumes creates the host volumes requested by the allocation that
+// don't exist on the node, and claims all the created host volumes it uses.
+func (c *Client) claimHostVolumes(alloc *structs.Allocation) error {
+	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	if tg == nil {
+		return nil
+	}
+
+	c.dynamicHostVolumes.lock.Lock()
+	defer c.dynamicHostVolumes.lock.Unlock()
+
+	var created []*structs.ClientHostVolumeConfig
+	var createErr error
+	for _, req := range tg.Volumes {
+		if req.Type != structs.VolumeTypeHost || !req.Create {
+			continue
+		}
+
+		name := req.VolumeID(alloc.Name)
+		if claims, ok := c.dynamicHostVolumes.claims[name]; ok {
+			claims[alloc.ID] = struct{}{}
+			continue
+		}
+		if _, ok := c.GetConfig().Node.HostVolumes[name]; ok {
+			// The volume is configured on the client, so it's used as-is
+			continue
+		}
+
+		vol, err := c.createHostVolume(name, req.SizeMB)
+		if err != nil {
+			createErr = fmt.Errorf("failed to create host volume %q: %v", name, err)
+			break
+		}
+		c.dynamicHostVolumes.claims[name] = map[string]struct{}{alloc.ID: {}}
+		created = append(created, vol)
+	}
+
+	if createErr != nil {
+		// The allocation won't run, so it releases its claims while any
+		// volumes created for it stay registered
+		for _, claims := range c.dynamicHostVolumes.claims {
+			delete(claims, alloc.ID)
+		}
+	}
+
+	if len(created) == 0 {
+		return createErr
+	}
+
+	c.UpdateConfig(func(newConfig *config.Config) {
+		if newConfig.Node.HostVolumes == nil {
+			newConfig.Node.HostVolumes = make(map[string]*structs.ClientHostVolumeConfig, len(created))
+		}
+		for _, vol := range created {
+			newConfig.Node.HostVolumes[vol.Name] = vol
+		}
+	})
+	c.updateNode()
+	return createErr
+}
+
+// createHostVolume creates a host volume requested by an allocation with the
+// configured host volume plugin.
+func (c *Client) createHostVolume(name string, sizeMB int) (*structs.ClientHostVolumeConfig, error) {
+	conf := c.GetConfig().DynamicHostVolumes
+	if conf == nil || c.dynamicHostVolumes.manager == nil {
+		return nil, fmt.Errorf("client is not configured to create host volumes")
+	}
+	if name == "." || name == ".." || filepath.Base(name) != name {
+		return nil, fmt.Errorf("invalid volume name")
+	}
+	if conf.MaxSizeMB > 0 && sizeMB > conf.MaxSizeMB {
+		return nil, fmt.Errorf("size %d MB exceeds the maximum of %d MB", sizeMB, conf.MaxSizeMB)
+	}
+
+	plugin := conf.Plugin
+	if plugin == "" {
+		plugin = structs.HostVolumePluginMkdirID
+	}
+	vol, err := c.dynamicHostVolumes.manager.Create(plugin, &hostvolumemanager.CreateRequest{
+		Name:             name,
+		CapacityMinBytes: int64(sizeMB) * structs.BytesInMegabyte,
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &structs.ClientHostVolumeConfig{
+		Name: vol.Name,
+		Path: vol.Path,
+	}, nil
+}
+
+// createHostVolumeForRequest creates a host volume requested through the
+// host volume API and registers it with the node.
+func (c *Client) createHostVolumeForRequest(args *structs.HostVolumeCreateRequest) (*hostvolumemanager.Volume, error) {
+	c.dynamicHostVolumes.lock.Lock()
+	defer c.dynamicHostVolumes.lock.Unlock()
+
+	manager := c.dynamicHostVolumes.manager
+	if manager == nil {
+		return nil, structs.NewErrRPCCoded(http.StatusBadRequest,
+			"client is not configured to create host volumes")
+	}
+	if _, ok := c.GetConfig().Node.HostVolumes[args.Name]; ok {
+		return nil, structs.NewErrRPCCoded(http.StatusConflict,
+			fmt.Sprintf("host volume %q already exists", args.Name))
+	}
+
+	vol, err := manager.Create(args.PluginID, &hostvolumemanager.CreateRequest{
+		Name:             args.Name,
+		CapacityMinBytes: args.RequestedCapacityMinBytes,
+		CapacityMaxBytes: args.RequestedCapacityMaxBytes,
+		Parameters:       args.Parameters,
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	c.dynamicHostVolumes.claims[vol.Name] = make(map[string]struct{})
+	c.UpdateConfig(func(newConfig *config.Config) {
+		if newConfig.Node.HostVolumes == nil {
+			newConfig.Node.HostVolumes = make(map[string]*structs.ClientHostVolumeConfig)
+		}
+		newConfig.Node.HostVolumes[vol.Name] = &structs.ClientHostVolumeConfig{
+			Name: vol.Name,
+			Path: vol.Path,
+		}
+	})
+	c.updateNode()
+	return vol, nil
+}
+
+// deleteHostVolumeForRequest deletes a host volume created by a host volume
+// plugin that no allocation on the client uses.
+func (c *Client) deleteHostVolumeForRequest(args *structs.HostVolumeDeleteRequest) error {
+	c.dynamicHostVolumes.lock.Lock()
+	defer c.dynamicHostVolumes.lock.Unlock()
+
+	manager := c.dynamicHostVolumes.manager
+	if manager == nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest,
+			"client is not configured to create host volumes")
+	}
+	if _, ok := manager.Get(args.Name); !ok {
+		return structs.NewErrRPCCoded(http.StatusNotFound,
+			fmt.Sprintf("host volume %q was not created by a host volume plugin", args.Name))
+	}
+	if claims := c.dynamicHostVolumes.claims[args.Name]; len(claims) > 0 {
+		return structs.NewErrRPCCoded(http.StatusConflict,
+			fmt.Sprintf("host volume %q is in use by %d allocations", args.Name, len(claims)))
+	}
+
+	if err := manager.Delete(args.Name); err != nil {
+		return err
+	}
+
+	delete(c.dynamicHostVolumes.claims, args.Name)
+	c.UpdateConfig(func(newConfig *config.Config) {
+		delete(newConfig.Node.HostVolumes, args.Name)
+	})
+	c.updateNode()
+	return nil
+}
+
+// releaseHostVolumes releases the allocation's claims on created host volumes.
+// If the allocation was stopped and the cleanup policy is to delete unused
+// volumes, volumes without any remaining claims are deleted.
+func (c *Client) releaseHostVolumes(alloc *structs.Allocation) {
+	conf := c.GetConfig().DynamicHostVolumes
+	if conf == nil {
+		return
+	}
+
+	c.dynamicHostVolumes.lock.Lock()
+	defer c.dynamicHostVolumes.lock.Unlock()
+
+	var unused []string
+	for name, claims := range c.dynamicHostVolumes.claims {
+		if _, ok := claims[alloc.ID]; !ok {
+			continue
+		}
+		delete(claims, alloc.ID)
+		if len(claims) > 0 {
+			continue
+		}
+
+		// Volumes created through the API are only deleted through it
+		if vol, ok := c.dynamicHostVolumes.manager.Get(name); ok && !vol.JobRequested {
+			continue
+		}
+		unused = append(unused, name)
+	}
+
+	if conf.Cleanup != config.DynamicHostVolumeCleanupDelete ||
+		alloc.DesiredStatus != structs.AllocDesiredStatusStop || len(unused) == 0 {
+		return
+	}
+
+	c.UpdateConfig(func(newConfig *config.Config) {
+		for _, name := range unused {
+			delete(newConfig.Node.HostVolumes, name)
+		}
+	})
+	c.updateNode()
+
+	for _, name := range unused {
+		delete(c.dynamicHostVolumes.claims, name)
+
+		if _, ok := c.dynamicHostVolumes.manager.Get(name); ok {
+			if err := c.dynamicHostVolumes.manager.Delete(name); err != nil {
+				c.logger.Error("failed to delete host volume", "volume", name, "error", err)
+			}
+			continue
+		}
+
+		path := filepath.Join(conf.Path, name)
+		if err := os.RemoveAll(path); err != nil {
+			c.logger.Error("failed to delete host volume", "volume", name, "path", path, "error", err)
+			continue
+		}
+		c.logger.Info("deleted host volume", "volume", name, "path", path)
+	}
+}
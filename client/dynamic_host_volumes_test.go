@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestClient_DynamicHostVolumes(t *testing.T) {
+	ci.Parallel(t)
+
+	volumesDir := t.TempDir()
+	must.NoError(t, os.Mkdir(filepath.Join(volumesDir, "existing"), 0o755))
+
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.DynamicHostVolumes = &config.DynamicHostVolumesConfig{
+			Path:      volumesDir,
+			MaxSizeMB: 100,
+			Cleanup:   config.DynamicHostVolumeCleanupDelete,
+		}
+	})
+	defer cleanup()
+
+	// Volumes created by a previous run of the client are registered
+	must.MapContainsKey(t, client.GetConfig().Node.HostVolumes, "existing")
+
+	newAlloc := func(sizeMB int) *structs.Allocation {
+		alloc := mock.Alloc()
+		alloc.Job.TaskGroups[0].Volumes = map[string]*structs.VolumeRequest{
+			"data": {
+				Name:   "data",
+				Type:   structs.VolumeTypeHost,
+				Source: "data",
+				Create: true,
+				SizeMB: sizeMB,
+			},
+		}
+		return alloc
+	}
+
+	// Requesting a volume larger than the limit fails
+	must.ErrorContains(t, client.claimHostVolumes(newAlloc(200)), "exceeds the maximum")
+	must.MapNotContainsKey(t, client.GetConfig().Node.HostVolumes, "data")
+
+	alloc1, alloc2 := newAlloc(10), newAlloc(10)
+	must.NoError(t, client.claimHostVolumes(alloc1))
+	must.NoError(t, client.claimHostVolumes(alloc2))
+
+	path := filepath.Join(volumesDir, "data")
+	must.DirExists(t, path)
+	must.Eq(t, &structs.ClientHostVolumeConfig{
+		Name: "data",
+		Path: path,
+	}, client.GetConfig().Node.HostVolumes["data"])
+
+	// The volume is deleted once the last allocation using it is stopped
+	alloc1.DesiredStatus = structs.AllocDesiredStatusStop
+	client.releaseHostVolumes(alloc1)
+	must.DirExists(t, path)
+
+	alloc2.DesiredStatus = structs.AllocDesiredStatusStop
+	client.releaseHostVolumes(alloc2)
+	must.DirNotExists(t, path)
+	must.MapNotContainsKey(t, client.GetConfig().Node.HostVolumes, "data")
+}
+
+func TestClient_HostVolumeEndpoint(t *testing.T) {
+	ci.Parallel(t)
+
+	volumesDir := t.TempDir()
+	client, cleanup := TestClient(t, func(c *config.Config) {
+		c.DynamicHostVolumes = &config.DynamicHostVolumesConfig{
+			Path:    volumesDir,
+			Cleanup: config.DynamicHostVolumeCleanupDelete,
+			Plugin:  structs.HostVolumePluginMkdirID,
+		}
+	})
+	defer cleanup()
+
+	var createResp structs.HostVolumeCreateResponse
+	must.NoError(t, client.ClientRPC("HostVolume.Create", &structs.HostVolumeCreateRequest{
+		Name:     "data",
+		PluginID: structs.HostVolumePluginMkdirID,
+	}, &createResp))
+
+	path := filepath.Join(volumesDir, "data")
+	must.Eq(t, structs.HostVolumeCreateResponse{
+		NodeID: client.NodeID(),
+		Name:   "data",
+		Path:   path,
+	}, createResp)
+	must.DirExists(t, path)
+	must.MapContainsKey(t, client.GetConfig().Node.HostVolumes, "data")
+
+	err := client.ClientRPC("HostVolume.Create", &structs.HostVolumeCreateRequest{
+		Name:     "data",
+		PluginID: structs.HostVolumePluginMkdirID,
+	}, &createResp)
+	must.ErrorContains(t, err, "already exists")
+
+	// Volumes in use can't be deleted, and volumes created through the API
+	// aren't deleted by the cleanup policy
+	alloc := mock.Alloc()
+	alloc.Job.TaskGroups[0].Volumes = map[string]*structs.VolumeRequest{
+		"data": {
+			Name:   "data",
+			Type:   structs.VolumeTypeHost,
+			Source: "data",
+			Create: true,
+		},
+	}
+	must.NoError(t, client.claimHostVolumes(alloc))
+
+	var deleteResp structs.HostVolumeDeleteResponse
+	err = client.ClientRPC("HostVolume.Delete", &structs.HostVolumeDeleteRequest{
+		Name: "data",
+	}, &deleteResp)
+	must.ErrorContains(t, err, "in use")
+
+	alloc.DesiredStatus = structs.AllocDesiredStatusStop
+	client.releaseHostVolumes(alloc)
+	must.DirExists(t, path)
+
+	must.NoError(t, client.ClientRPC("HostVolume.Delete", &structs.HostVolumeDeleteRequest{
+		Name: "data",
+	}, &deleteResp))
+	must.DirNotExists(t, path)
+	must.MapNotContainsKey(t, client.GetConfig().Node.HostVolumes, "data")
+}
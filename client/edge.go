@@ -0,0 +1,187 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// edgeCheckInterval is the maximum interval at which the edge scheduler
+	// checks whether the client is disconnected and for failed allocations.
+	edgeCheckInterval = 5 * time.Second
+
+	// edgeRestartDescription is the client description of allocations
+	// restarted by the edge scheduler.
+	edgeRestartDescription = "Restarted by the client-local scheduler while disconnected"
+)
+
+// edgeScheduler is a restricted client-local scheduler used in edge
+// deployments. While the client is disconnected from the servers for longer
+// than the configured grace period, it restarts failed allocations of pinned
+// system and sysbatch jobs in place so they keep running. It never places new
+// allocations: only allocations the servers already assigned to this client
+// are restarted, so the servers' view is reconciled by the regular alloc sync
+// once the client reconnects.
+type edgeScheduler struct {
+	config       *config.EdgeConfig
+	lastOk       func() time.Time
+	getRunners   func() map[string]interfaces.AllocRunner
+	restartAlloc func(allocID string) error
+	emitEvent    func(*structs.NodeEvent)
+	logger       hclog.Logger
+	shutdownCh   chan struct{}
+
+	// disconnected is true while the scheduler considers the client
+	// disconnected from the servers.
+	disconnected bool
+
+	// lastRestart is the time of the last local restart of each allocation
+	// while disconnected.
+	lastRestart map[string]time.Time
+
+	// restarts is the number of local restarts since the client disconnected.
+	restarts int
+}
+
+func newEdgeScheduler(
+	conf *config.EdgeConfig,
+	lastOk func() time.Time,
+	getRunners func() map[string]interfaces.AllocRunner,
+	restartAlloc func(string) error,
+	emitEvent func(*structs.NodeEvent),
+	logger hclog.Logger,
+	shutdownCh chan struct{}) *edgeScheduler {
+
+	return &edgeScheduler{
+		config:       conf,
+		lastOk:       lastOk,
+		getRunners:   getRunners,
+		restartAlloc: restartAlloc,
+		emitEvent:    emitEvent,
+		logger:       logger.Named("edge_scheduler"),
+		shutdownCh:   shutdownCh,
+		lastRestart:  make(map[string]time.Time),
+	}
+}
+
+// watch is a loop that runs the local scheduler until the client shuts down.
+func (e *edgeScheduler) watch() {
+	interval := edgeCheckInterval
+	if e.config.RestartDelay > 0 && e.config.RestartDelay < interval {
+		interval = e.config.RestartDelay
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.check(time.Now())
+		case <-e.shutdownCh:
+			return
+		}
+	}
+}
+
+// check updates the connection state and, if disconnected, restarts failed
+// allocations of pinned jobs.
+func (e *edgeScheduler) check(now time.Time) {
+	lastOk := e.lastOk()
+	disconnected := !lastOk.IsZero() && now.Sub(lastOk) > e.config.DisconnectGrace
+
+	switch {
+	case disconnected && !e.disconnected:
+		e.logger.Warn("client disconnected from servers, scheduling pinned jobs locally",
+			"last_heartbeat", lastOk)
+		e.disconnected = true
+		e.restarts = 0
+		e.lastRestart = make(map[string]time.Time)
+
+	case !disconnected && e.disconnected:
+		e.logger.Info("client reconnected to servers, local scheduler stopped",
+			"restarts", e.restarts)
+		e.disconnected = false
+		e.emitEvent(structs.NewNodeEvent().
+			SetSubsystem(structs.NodeEventSubsystemScheduler).
+			SetMessage(fmt.Sprintf("Reconnected to servers after %d local allocation restarts", e.restarts)))
+		return
+	}
+
+	if !e.disconnected {
+		return
+	}
+
+	for _, alloc := range e.restartable(e.getRunners()) {
+		if last, ok := e.lastRestart[alloc.ID]; ok && now.Sub(last) < e.config.RestartDelay {
+			continue
+		}
+
+		e.logger.Info("restarting failed allocation of pinned job",
+			"alloc_id", alloc.ID, "job_id", alloc.JobID)
+		if err := e.restartAlloc(alloc.ID); err != nil {
+			e.logger.Error("failed to restart allocation", "alloc_id", alloc.ID, "error", err)
+		}
+		e.lastRestart[alloc.ID] = now
+		e.restarts++
+	}
+}
+
+// restartable returns the allocations that should be restarted locally: the
+// latest allocation of each task group of a pinned job that has failed, or for
+// system jobs, completed.
+func (e *edgeScheduler) restartable(runners map[string]interfaces.AllocRunner) []*structs.Allocation {
+	type groupKey struct {
+		namespace, job, group string
+	}
+
+	latest := make(map[groupKey]*structs.Allocation)
+	statuses := make(map[string]string)
+	for _, ar := range runners {
+		alloc := ar.Alloc()
+		if alloc == nil || alloc.ServerTerminalStatus() || !isEdgePinned(e.config, alloc) {
+			continue
+		}
+
+		key := groupKey{alloc.Namespace, alloc.JobID, alloc.TaskGroup}
+		if prev, ok := latest[key]; !ok || alloc.CreateIndex > prev.CreateIndex {
+			latest[key] = alloc
+		}
+		statuses[alloc.ID] = ar.AllocState().ClientStatus
+	}
+
+	var allocs []*structs.Allocation
+	for _, alloc := range latest {
+		switch statuses[alloc.ID] {
+		case structs.AllocClientStatusFailed:
+			allocs = append(allocs, alloc)
+		case structs.AllocClientStatusComplete:
+			// Sysbatch allocations are expected to complete.
+			if alloc.Job.Type == structs.JobTypeSystem {
+				allocs = append(allocs, alloc)
+			}
+		}
+	}
+	return allocs
+}
+
+// isEdgePinned returns true if the allocation belongs to a system or sysbatch
+// job pinned by the edge scheduler config.
+func isEdgePinned(conf *config.EdgeConfig, alloc *structs.Allocation) bool {
+	if conf == nil || alloc.Job == nil {
+		return false
+	}
+	if alloc.Job.Type != structs.JobTypeSystem && alloc.Job.Type != structs.JobTypeSysBatch {
+		return false
+	}
+	return conf.IsPinned(alloc.JobID)
+}
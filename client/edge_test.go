@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/allocrunner/state"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func testEdgeRunner(alloc *structs.Allocation, status string) interfaces.AllocRunner {
+	return &emptyAllocRunner{
+		alloc:      alloc,
+		allocState: &state.State{ClientStatus: status},
+	}
+}
+
+func TestEdgeScheduler_Restartable(t *testing.T) {
+	ci.Parallel(t)
+
+	failed := mock.SystemAlloc()
+	failed.CreateIndex = 10
+
+	// an older allocation of the same group is ignored
+	older := failed.Copy()
+	older.ID = "older"
+	older.CreateIndex = 5
+
+	complete := mock.SystemAlloc()
+	complete.JobID = "complete"
+
+	running := mock.SystemAlloc()
+	running.JobID = "running"
+
+	service := mock.Alloc()
+
+	e := newEdgeScheduler(&config.EdgeConfig{}, nil, nil, nil, nil,
+		testlog.HCLogger(t), make(chan struct{}))
+
+	allocs := e.restartable(map[string]interfaces.AllocRunner{
+		failed.ID:   testEdgeRunner(failed, structs.AllocClientStatusFailed),
+		older.ID:    testEdgeRunner(older, structs.AllocClientStatusFailed),
+		complete.ID: testEdgeRunner(complete, structs.AllocClientStatusComplete),
+		running.ID:  testEdgeRunner(running, structs.AllocClientStatusRunning),
+		service.ID:  testEdgeRunner(service, structs.AllocClientStatusFailed),
+	})
+
+	ids := []string{}
+	for _, alloc := range allocs {
+		ids = append(ids, alloc.ID)
+	}
+	must.SliceContainsAll(t, []string{failed.ID, complete.ID}, ids)
+
+	// only pinned jobs are restarted
+	e.config.PinnedJobs = []string{complete.JobID}
+	allocs = e.restartable(map[string]interfaces.AllocRunner{
+		failed.ID:   testEdgeRunner(failed, structs.AllocClientStatusFailed),
+		complete.ID: testEdgeRunner(complete, structs.AllocClientStatusComplete),
+	})
+	must.Len(t, 1, allocs)
+	must.Eq(t, complete.ID, allocs[0].ID)
+}
+
+func TestEdgeScheduler_Check(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.SystemAlloc()
+	runners := map[string]interfaces.AllocRunner{
+		alloc.ID: testEdgeRunner(alloc, structs.AllocClientStatusFailed),
+	}
+
+	now := time.Now()
+	lastOk := now
+	restarted := []string{}
+	events := []*structs.NodeEvent{}
+
+	e := newEdgeScheduler(
+		&config.EdgeConfig{
+			DisconnectGrace: time.Minute,
+			RestartDelay:    30 * time.Second,
+		},
+		func() time.Time { return lastOk },
+		func() map[string]interfaces.AllocRunner { return runners },
+		func(allocID string) error {
+			restarted = append(restarted, allocID)
+			return nil
+		},
+		func(ev *structs.NodeEvent) { events = append(events, ev) },
+		testlog.HCLogger(t),
+		make(chan struct{}),
+	)
+
+	// still within the grace period
+	e.check(now.Add(30 * time.Second))
+	must.SliceEmpty(t, restarted)
+
+	// disconnected
+	e.check(now.Add(2 * time.Minute))
+	must.Eq(t, []string{alloc.ID}, restarted)
+
+	// the restart delay has not passed
+	e.check(now.Add(2*time.Minute + 10*time.Second))
+	must.Len(t, 1, restarted)
+
+	e.check(now.Add(3 * time.Minute))
+	must.Len(t, 2, restarted)
+
+	// reconnected
+	lastOk = now.Add(3 * time.Minute)
+	e.check(now.Add(3*time.Minute + time.Second))
+	must.Len(t, 2, restarted)
+	must.Len(t, 1, events)
+	must.Eq(t, structs.NodeEventSubsystemScheduler, events[0].Subsystem)
+}
@@ -0,0 +1,284 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	hclog "github.com/hashicorp/go-hclog"
+
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/hoststats"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// evictionCheckInterval is the interval at which the eviction manager checks
+// the host for memory and disk pressure.
+const evictionCheckInterval = 5 * time.Second
+
+// evictionResource is a host resource the eviction manager monitors.
+type evictionResource string
+
+const (
+	evictionResourceMemory evictionResource = "memory"
+	evictionResourceDisk   evictionResource = "disk"
+)
+
+// evictionCandidate is a running allocation that may be evicted, along with
+// its usage of the resource under pressure.
+type evictionCandidate struct {
+	ar       interfaces.AllocRunner
+	alloc    *structs.Allocation
+	priority int
+
+	// usage and reserved are in bytes
+	usage    uint64
+	reserved uint64
+}
+
+// overage is how many bytes the allocation uses beyond its reservation.
+func (c *evictionCandidate) overage() uint64 {
+	if c.usage <= c.reserved {
+		return 0
+	}
+	return c.usage - c.reserved
+}
+
+// evictionManager evicts allocations when the host runs low on memory or on
+// disk for the alloc dir, before the kernel OOM killer or a full disk takes
+// down the node. If a resource stays above its threshold for the grace
+// period, the lowest priority allocation consuming the most of the resource
+// beyond its reservation is killed and marked as failed so the servers
+// reschedule it according to its reschedule policy.
+type evictionManager struct {
+	config     *config.EvictionConfig
+	allocDir   string
+	hostStats  func() *hoststats.HostStats
+	getRunners func() map[string]interfaces.AllocRunner
+	emitEvent  func(*structs.NodeEvent)
+	logger     hclog.Logger
+	shutdownCh chan struct{}
+
+	// pressureSince is when each resource exceeded its threshold, or when an
+	// allocation was last evicted for it.
+	pressureSince map[evictionResource]time.Time
+
+	// evicted is the set of allocations already evicted.
+	evicted map[string]struct{}
+
+	// diskUsage returns the disk usage of an alloc dir, and may be replaced
+	// for testing.
+	diskUsage func(path string) uint64
+}
+
+func newEvictionManager(
+	conf *config.EvictionConfig,
+	allocDir string,
+	hostStats func() *hoststats.HostStats,
+	getRunners func() map[string]interfaces.AllocRunner,
+	emitEvent func(*structs.NodeEvent),
+	logger hclog.Logger,
+	shutdownCh chan struct{}) *evictionManager {
+
+	return &evictionManager{
+		config:        conf,
+		allocDir:      allocDir,
+		hostStats:     hostStats,
+		getRunners:    getRunners,
+		emitEvent:     emitEvent,
+		logger:        logger.Named("eviction"),
+		shutdownCh:    shutdownCh,
+		pressureSince: make(map[evictionResource]time.Time),
+		evicted:       make(map[string]struct{}),
+		diskUsage:     dirSize,
+	}
+}
+
+// watch is a loop that checks for host pressure until the client shuts down.
+func (e *evictionManager) watch() {
+	ticker := time.NewTicker(evictionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.check(time.Now())
+		case <-e.shutdownCh:
+			return
+		}
+	}
+}
+
+// check evicts an allocation for each resource that has been above its
+// threshold for longer than the grace period.
+func (e *evictionManager) check(now time.Time) {
+	stats := e.hostStats()
+	if stats == nil {
+		return
+	}
+
+	if stats.Memory != nil && stats.Memory.Total > 0 {
+		used := float64(stats.Memory.Total-stats.Memory.Available) / float64(stats.Memory.Total) * 100
+		e.checkResource(now, evictionResourceMemory, used, e.config.MemoryThreshold)
+	}
+	if stats.AllocDirStats != nil {
+		e.checkResource(now, evictionResourceDisk, stats.AllocDirStats.UsedPercent, e.config.DiskThreshold)
+	}
+}
+
+func (e *evictionManager) checkResource(now time.Time, resource evictionResource, used, threshold float64) {
+	if used < threshold {
+		delete(e.pressureSince, resource)
+		return
+	}
+
+	since, ok := e.pressureSince[resource]
+	if !ok {
+		e.logger.Warn("host resource above eviction threshold",
+			"resource", resource, "used_percent", used, "threshold", threshold,
+			"grace_period", e.config.GracePeriod)
+		e.pressureSince[resource] = now
+		since = now
+	}
+	if now.Sub(since) < e.config.GracePeriod {
+		return
+	}
+
+	// Evict at most one allocation per grace period to give the host time to
+	// reclaim the resource
+	e.pressureSince[resource] = now
+
+	victim := e.victim(e.candidates(resource))
+	if victim == nil {
+		e.logger.Warn("no allocation to evict", "resource", resource, "used_percent", used)
+		return
+	}
+	e.evict(victim, resource, used)
+}
+
+// candidates returns the running allocations that may be evicted for the
+// resource along with their usage.
+func (e *evictionManager) candidates(resource evictionResource) []*evictionCandidate {
+	var candidates []*evictionCandidate
+	for id, ar := range e.getRunners() {
+		if _, ok := e.evicted[id]; ok {
+			continue
+		}
+
+		alloc := ar.Alloc()
+		if alloc == nil || alloc.Job == nil || alloc.ServerTerminalStatus() ||
+			ar.AllocState().ClientStatus != structs.AllocClientStatusRunning {
+			continue
+		}
+
+		c := &evictionCandidate{
+			ar:       ar,
+			alloc:    alloc,
+			priority: alloc.Job.Priority,
+		}
+
+		switch resource {
+		case evictionResourceMemory:
+			stats, err := ar.StatsReporter().LatestAllocStats("")
+			if err != nil || stats == nil || stats.ResourceUsage == nil || stats.ResourceUsage.MemoryStats == nil {
+				continue
+			}
+			c.usage = stats.ResourceUsage.MemoryStats.RSS
+			if c.usage == 0 {
+				c.usage = stats.ResourceUsage.MemoryStats.Usage
+			}
+			if res := alloc.AllocatedResources; res != nil {
+				c.reserved = uint64(res.Comparable().Flattened.Memory.MemoryMB) * 1024 * 1024
+			}
+		case evictionResourceDisk:
+			c.usage = e.diskUsage(filepath.Join(e.allocDir, alloc.ID))
+			if res := alloc.AllocatedResources; res != nil {
+				c.reserved = uint64(res.Shared.DiskMB) * 1024 * 1024
+			}
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates
+}
+
+// victim returns the allocation to evict: allocations consuming more than
+// their reservation are evicted first, then lower priority allocations, then
+// the ones with the largest overage or usage.
+func (e *evictionManager) victim(candidates []*evictionCandidate) *evictionCandidate {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if overA, overB := a.overage() > 0, b.overage() > 0; overA != overB {
+			return overA
+		}
+		if a.priority != b.priority {
+			return a.priority < b.priority
+		}
+		if a.overage() != b.overage() {
+			return a.overage() > b.overage()
+		}
+		return a.usage > b.usage
+	})
+	return candidates[0]
+}
+
+// evict kills the tasks of the allocation and marks them as failed.
+func (e *evictionManager) evict(c *evictionCandidate, resource evictionResource, used float64) {
+	msg := fmt.Sprintf("Evicted due to host %s pressure (%.1f%% used)", resource, used)
+	e.logger.Warn("evicting allocation", "alloc_id", c.alloc.ID, "job_id", c.alloc.JobID,
+		"resource", resource, "used_percent", used, "priority", c.priority,
+		"usage", c.usage, "reserved", c.reserved)
+
+	e.evicted[c.alloc.ID] = struct{}{}
+	for task, state := range c.ar.AllocState().TaskStates {
+		if state.State == structs.TaskStateDead {
+			continue
+		}
+		event := structs.NewTaskEvent(structs.TaskKilling).
+			SetKillReason(msg).
+			SetDisplayMessage(msg).
+			SetFailsTask()
+		if err := c.ar.KillTask(task, event); err != nil {
+			e.logger.Error("failed to kill task", "alloc_id", c.alloc.ID, "task", task, "error", err)
+		}
+	}
+
+	metrics.IncrCounterWithLabels([]string{"client", "allocs", "evicted"}, 1, []metrics.Label{
+		{Name: "resource", Value: string(resource)},
+		{Name: "namespace", Value: c.alloc.Namespace},
+		{Name: "job", Value: c.alloc.JobID},
+	})
+	e.emitEvent(structs.NewNodeEvent().
+		SetSubsystem(structs.NodeEventSubsystemScheduler).
+		SetMessage(fmt.Sprintf("Evicted allocation %s due to %s pressure", c.alloc.ID, resource)).
+		AddDetail("alloc_id", c.alloc.ID).
+		AddDetail("job_id", c.alloc.JobID))
+}
+
+// dirSize returns the total size of the files in the directory tree at path.
+// Errors are ignored as files may be removed while walking.
+func dirSize(path string) uint64 {
+	var size uint64
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += uint64(info.Size())
+			}
+		}
+		return nil
+	})
+	return size
+}
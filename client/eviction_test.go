@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/allocrunner/state"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/client/hoststats"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestEvictionManager_Victim(t *testing.T) {
+	ci.Parallel(t)
+
+	e := newEvictionManager(&config.EvictionConfig{}, "", nil, nil, nil,
+		testlog.HCLogger(t), make(chan struct{}))
+
+	within := &evictionCandidate{priority: 10, usage: 100, reserved: 200}
+	highOver := &evictionCandidate{priority: 80, usage: 300, reserved: 200}
+	lowOver := &evictionCandidate{priority: 50, usage: 250, reserved: 200}
+	lowOverMore := &evictionCandidate{priority: 50, usage: 400, reserved: 200}
+
+	must.Nil(t, e.victim(nil))
+
+	// allocations over their reservation are evicted before lower priority
+	// allocations within their reservation
+	must.Eq(t, highOver, e.victim([]*evictionCandidate{within, highOver}))
+
+	// then lower priority allocations, then the largest overage
+	must.Eq(t, lowOverMore, e.victim([]*evictionCandidate{within, highOver, lowOver, lowOverMore}))
+
+	// allocations within their reservation are evicted by priority
+	must.Eq(t, within, e.victim([]*evictionCandidate{
+		within, {priority: 50, usage: 100, reserved: 200},
+	}))
+}
+
+func TestEvictionManager_Check(t *testing.T) {
+	ci.Parallel(t)
+
+	low := mock.Alloc()
+	low.Job.Priority = 10
+	high := mock.Alloc()
+	high.Job.Priority = 90
+	complete := mock.Alloc()
+	complete.Job.Priority = 1
+
+	runners := map[string]interfaces.AllocRunner{}
+	for alloc, status := range map[*structs.Allocation]string{
+		low:      structs.AllocClientStatusRunning,
+		high:     structs.AllocClientStatusRunning,
+		complete: structs.AllocClientStatusComplete,
+	} {
+		runners[alloc.ID] = &emptyAllocRunner{
+			alloc: alloc,
+			allocState: &state.State{
+				ClientStatus: status,
+				TaskStates: map[string]*structs.TaskState{
+					"web": {State: structs.TaskStateRunning},
+				},
+			},
+		}
+	}
+
+	stats := &hoststats.HostStats{
+		Memory:        &hoststats.MemoryStats{Total: 100, Available: 50},
+		AllocDirStats: &hoststats.DiskStats{UsedPercent: 99},
+	}
+	events := []*structs.NodeEvent{}
+
+	e := newEvictionManager(
+		&config.EvictionConfig{
+			MemoryThreshold: 90,
+			DiskThreshold:   90,
+			GracePeriod:     30 * time.Second,
+		},
+		t.TempDir(),
+		func() *hoststats.HostStats { return stats },
+		func() map[string]interfaces.AllocRunner { return runners },
+		func(ev *structs.NodeEvent) { events = append(events, ev) },
+		testlog.HCLogger(t),
+		make(chan struct{}),
+	)
+	e.diskUsage = func(string) uint64 { return 1 }
+
+	// still within the grace period
+	now := time.Now()
+	e.check(now)
+	e.check(now.Add(10 * time.Second))
+	must.SliceEmpty(t, events)
+
+	// the lowest priority running allocation is evicted
+	e.check(now.Add(30 * time.Second))
+	must.Len(t, 1, events)
+	must.Eq(t, low.ID, events[0].Details["alloc_id"])
+
+	// only one allocation is evicted per grace period
+	e.check(now.Add(40 * time.Second))
+	must.Len(t, 1, events)
+
+	e.check(now.Add(time.Minute))
+	must.Len(t, 2, events)
+	must.Eq(t, high.ID, events[1].Details["alloc_id"])
+
+	// the pressure is relieved
+	stats.AllocDirStats.UsedPercent = 50
+	e.check(now.Add(2 * time.Minute))
+	must.Len(t, 2, events)
+}
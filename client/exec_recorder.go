@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/drivers/proto"
+)
+
+const (
+	// execRecordingExt is the extension of exec session recordings, which are
+	// in the asciicast v2 format.
+	execRecordingExt = ".cast"
+
+	// execRecordingWidth and execRecordingHeight are the terminal size in the
+	// header of recordings. Terminal size changes are recorded as events.
+	execRecordingWidth  = 80
+	execRecordingHeight = 24
+)
+
+// execRecordingHeader is the header of an asciicast v2 recording.
+type execRecordingHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Command   string            `json:"command,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// execRecorder wraps an exec stream and records the output of the session,
+// along with terminal size changes, to a file in the asciicast v2 format so
+// it can be replayed with asciinema. Input is not recorded as it may contain
+// secrets, such as passwords, that the terminal doesn't echo.
+type execRecorder struct {
+	drivers.ExecTaskStream
+
+	start time.Time
+	f     *os.File
+	mu    sync.Mutex
+}
+
+// newExecRecorder creates the recording at path and writes its header.
+func newExecRecorder(stream drivers.ExecTaskStream, path, title string, cmd []string) (*execRecorder, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &execRecorder{
+		ExecTaskStream: stream,
+		start:          time.Now(),
+		f:              f,
+	}
+	if err := r.write(execRecordingHeader{
+		Version:   2,
+		Width:     execRecordingWidth,
+		Height:    execRecordingHeight,
+		Timestamp: r.start.Unix(),
+		Command:   strings.Join(cmd, " "),
+		Title:     title,
+	}); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// Send records the output of the session before sending it. Failing to
+// record the output ends the session.
+func (r *execRecorder) Send(m *drivers.ExecTaskStreamingResponseMsg) error {
+	for _, out := range []*proto.ExecTaskStreamingIOOperation{m.Stdout, m.Stderr} {
+		if out == nil || len(out.Data) == 0 {
+			continue
+		}
+		if err := r.event("o", string(out.Data)); err != nil {
+			return fmt.Errorf("failed to record exec session: %w", err)
+		}
+	}
+	return r.ExecTaskStream.Send(m)
+}
+
+// Recv records terminal size changes of the session.
+func (r *execRecorder) Recv() (*drivers.ExecTaskStreamingRequestMsg, error) {
+	m, err := r.ExecTaskStream.Recv()
+	if err != nil {
+		return m, err
+	}
+	if size := m.TtySize; size != nil {
+		if err := r.event("r", fmt.Sprintf("%dx%d", size.Width, size.Height)); err != nil {
+			return nil, fmt.Errorf("failed to record exec session: %w", err)
+		}
+	}
+	return m, nil
+}
+
+// Close closes the recording.
+func (r *execRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.f.Close()
+}
+
+// event records an event of the given type, relative to the start of the
+// session.
+func (r *execRecorder) event(code, data string) error {
+	return r.write([]any{time.Since(r.start).Seconds(), code, data})
+}
+
+func (r *execRecorder) write(v any) error {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.f.Write(append(buf, '\n'))
+	return err
+}
@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/drivers/proto"
+	"github.com/shoenig/test/must"
+)
+
+// testExecStream is an exec stream that replays requests and collects
+// responses.
+type testExecStream struct {
+	requests  []*drivers.ExecTaskStreamingRequestMsg
+	responses []*drivers.ExecTaskStreamingResponseMsg
+}
+
+func (s *testExecStream) Send(m *drivers.ExecTaskStreamingResponseMsg) error {
+	s.responses = append(s.responses, m)
+	return nil
+}
+
+func (s *testExecStream) Recv() (*drivers.ExecTaskStreamingRequestMsg, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	m := s.requests[0]
+	s.requests = s.requests[1:]
+	return m, nil
+}
+
+func TestExecRecorder(t *testing.T) {
+	ci.Parallel(t)
+
+	stream := &testExecStream{
+		requests: []*drivers.ExecTaskStreamingRequestMsg{
+			{TtySize: &proto.ExecTaskStreamingRequest_TerminalSize{Width: 120, Height: 40}},
+			{Stdin: &proto.ExecTaskStreamingIOOperation{Data: []byte("secret\n")}},
+		},
+	}
+
+	path := filepath.Join(t.TempDir(), "web.exec.test.cast")
+	r, err := newExecRecorder(stream, path, "exec test", []string{"/bin/sh", "-i"})
+	must.NoError(t, err)
+
+	for {
+		if _, err := r.Recv(); err != nil {
+			must.ErrorIs(t, err, io.EOF)
+			break
+		}
+	}
+	must.NoError(t, r.Send(&drivers.ExecTaskStreamingResponseMsg{
+		Stdout: &proto.ExecTaskStreamingIOOperation{Data: []byte("hello\n")},
+	}))
+	must.NoError(t, r.Send(&drivers.ExecTaskStreamingResponseMsg{
+		Stderr: &proto.ExecTaskStreamingIOOperation{Data: []byte("oops\n")},
+	}))
+	must.NoError(t, r.Send(drivers.NewExecStreamingResponseExit(0)))
+	must.NoError(t, r.Close())
+
+	// responses are passed through
+	must.Len(t, 3, stream.responses)
+
+	// recordings can't be overwritten
+	_, err = newExecRecorder(stream, path, "exec test", nil)
+	must.ErrorIs(t, err, os.ErrExist)
+
+	f, err := os.Open(path)
+	must.NoError(t, err)
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	must.True(t, scanner.Scan())
+	var header execRecordingHeader
+	must.NoError(t, json.Unmarshal(scanner.Bytes(), &header))
+	must.Eq(t, 2, header.Version)
+	must.Eq(t, "/bin/sh -i", header.Command)
+	must.Eq(t, "exec test", header.Title)
+
+	events := [][]any{}
+	for scanner.Scan() {
+		var event []any
+		must.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		must.Len(t, 3, event)
+		events = append(events, event[1:])
+	}
+
+	// input is not recorded
+	must.Eq(t, [][]any{
+		{"r", "120x40"},
+		{"o", "hello\n"},
+		{"o", "oops\n"},
+	}, events)
+}
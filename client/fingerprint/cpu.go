@@ -81,6 +81,7 @@ func (f *CPUFingerprint) initialize(request *FingerprintRequest) {
 		totalCompute    = request.Config.CpuCompute
 		reservedCompute = f.reservedCompute(request)
 		reservedCores   = idset.From[hw.CoreID](reservedCompute.ReservedCpuCores)
+		exclusiveCores  = idset.From[hw.CoreID](request.Config.ExclusiveCores)
 	)
 
 	if rc := request.Config.ReservableCores; rc != nil {
@@ -90,10 +91,12 @@ func (f *CPUFingerprint) initialize(request *FingerprintRequest) {
 	f.top = numalib.Scan(append(
 		numalib.PlatformScanners(),
 		&numalib.ConfigScanner{
-			ReservableCores: reservableCores,
-			ReservedCores:   reservedCores,
-			TotalCompute:    hw.MHz(totalCompute),
-			ReservedCompute: hw.MHz(reservedCompute.CpuShares),
+			ReservableCores:        reservableCores,
+			ReservedCores:          reservedCores,
+			TotalCompute:           hw.MHz(totalCompute),
+			ReservedCompute:        hw.MHz(reservedCompute.CpuShares),
+			ExclusiveCores:         exclusiveCores,
+			ExclusiveIsolatedCores: request.Config.ExclusiveIsolatedCores,
 		},
 	))
 }
@@ -155,6 +158,9 @@ func (f *CPUFingerprint) setReservableCores(response *FingerprintResponse) {
 		// topology has already reduced to the intersection of usable cores
 		usable := f.top.UsableCores()
 		response.AddAttribute("cpu.reservablecores", f.cores(usable.Size()))
+		if exclusive := f.top.ExclusiveCores(); !exclusive.Empty() {
+			response.AddAttribute("cpu.exclusivecores", f.cores(exclusive.Size()))
+		}
 	default:
 		response.AddAttribute("cpu.reservablecores", "0")
 	}
@@ -6,14 +6,20 @@ package fingerprint
 import (
 	"runtime"
 	"strings"
+	"time"
 
 	log "github.com/hashicorp/go-hclog"
 	"github.com/shirou/gopsutil/v3/host"
 )
 
+const (
+	// hostFingerprintPeriod is the interval at which the host is
+	// fingerprinted after the client starts
+	hostFingerprintPeriod = 5 * time.Minute
+)
+
 // HostFingerprint is used to fingerprint the host
 type HostFingerprint struct {
-	StaticFingerprinter
 	logger log.Logger
 }
 
@@ -51,3 +57,9 @@ func (f *HostFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerprint
 
 	return nil
 }
+
+// Periodic fingerprints the host periodically so that operating system and
+// kernel upgrades applied without restarting the client are picked up.
+func (f *HostFingerprint) Periodic() (bool, time.Duration) {
+	return true, hostFingerprintPeriod
+}
@@ -6,6 +6,7 @@ package fingerprint
 import (
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
 
 	log "github.com/hashicorp/go-hclog"
@@ -128,6 +129,10 @@ func (f *NetworkFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerpr
 	}
 	resp.NodeResources.NodeNetworks = nodeNetResources
 
+	if err := f.setInterfaceAttributes(resp, ifaces); err != nil {
+		return err
+	}
+
 	resp.Detected = true
 
 	return nil
@@ -178,6 +183,8 @@ func (f *NetworkFingerprint) createNodeNetworkResources(ifaces []net.Interface,
 
 				if hostNetwork, ok := conf.HostNetworks[alias]; ok {
 					newAddr.ReservedPorts = hostNetwork.ReservedPorts
+					newAddr.MinDynamicPort = hostNetwork.MinDynamicPort
+					newAddr.MaxDynamicPort = hostNetwork.MaxDynamicPort
 				}
 
 				if newAddr.Alias != "" {
@@ -207,6 +214,68 @@ func (f *NetworkFingerprint) createNodeNetworkResources(ifaces []net.Interface,
 	return nets, nil
 }
 
+// setInterfaceAttributes fingerprints every host network interface that is up
+// with its speed, MTU, hardware address, and addresses as node attributes, so
+// that jobs can select the network interface of a group network, e.g.
+//
+//	network.interface.eth1.speed = 25000
+//	network.interface.eth1.ipv4  = 10.0.1.12
+func (f *NetworkFingerprint) setInterfaceAttributes(resp *FingerprintResponse, ifaces []net.Interface) error {
+	var names []string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		names = append(names, iface.Name)
+
+		attr := func(property string) string {
+			return structs.NodeNetworkInterfaceAttribute(iface.Name, property)
+		}
+
+		resp.AddAttribute(attr("mtu"), strconv.Itoa(iface.MTU))
+		if speed := f.linkSpeed(iface.Name); speed > 0 {
+			resp.AddAttribute(attr("speed"), strconv.Itoa(speed))
+		}
+		if mac := iface.HardwareAddr.String(); mac != "" {
+			resp.AddAttribute(attr("mac"), mac)
+		}
+
+		addrs, err := f.interfaceDetector.Addrs(&iface)
+		if err != nil {
+			return err
+		}
+		var ipv4, ipv6 []string
+		for _, addr := range addrs {
+			var ip net.IP
+			switch v := (addr).(type) {
+			case *net.IPNet:
+				ip = v.IP
+			case *net.IPAddr:
+				ip = v.IP
+			}
+			switch {
+			case ip == nil, ip.IsLinkLocalUnicast(), ip.IsLinkLocalMulticast():
+				continue
+			case ip.To4() != nil:
+				ipv4 = append(ipv4, ip.String())
+			default:
+				ipv6 = append(ipv6, ip.String())
+			}
+		}
+		if len(ipv4) > 0 {
+			resp.AddAttribute(attr(string(structs.NodeNetworkAF_IPv4)), strings.Join(ipv4, ","))
+		}
+		if len(ipv6) > 0 {
+			resp.AddAttribute(attr(string(structs.NodeNetworkAF_IPv6)), strings.Join(ipv6, ","))
+		}
+	}
+
+	if len(names) > 0 {
+		resp.AddAttribute("network.interfaces", strings.Join(names, ","))
+	}
+	return nil
+}
+
 func deriveAddressAliases(iface net.Interface, addr net.IP, config *config.Config) (aliases []string) {
 	for name, conf := range config.HostNetworks {
 		var cidrMatch, ifaceMatch bool
@@ -451,8 +451,13 @@ func TestNetworkFingerPrint_LinkLocal_Disallowed(t *testing.T) {
 		t.Fatalf("expected response to be applicable")
 	}
 
-	if len(response.Attributes) != 0 {
-		t.Fatalf("should not apply attributes")
+	// The interface only has link-local addresses, so only the interface
+	// attributes are applied and not an IP address
+	if _, ok := response.Attributes["unique.network.ip-address"]; ok {
+		t.Fatalf("should not apply ip address attribute")
+	}
+	if _, ok := response.Attributes[structs.NodeNetworkInterfaceAttribute("eth3", "ipv4")]; ok {
+		t.Fatalf("should not apply link-local interface addresses")
 	}
 }
 
@@ -599,3 +604,33 @@ func TestNetworkFingerPrint_HostNetworkReservedPorts(t *testing.T) {
 		})
 	}
 }
+
+func TestNetworkFingerPrint_InterfaceAttributes(t *testing.T) {
+	ci.Parallel(t)
+
+	f := &NetworkFingerprint{logger: testlog.HCLogger(t), interfaceDetector: &NetworkInterfaceDetectorMultipleInterfaces{}}
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+	cfg := &config.Config{NetworkSpeed: 100, NetworkInterface: "eth0"}
+
+	request := &FingerprintRequest{Config: cfg, Node: node}
+	var response FingerprintResponse
+	err := f.Fingerprint(request, &response)
+	require.NoError(t, err)
+
+	attributes := response.Attributes
+	require.Equal(t, "eth0,eth2,eth3,eth4", attributes["network.interfaces"])
+
+	// link local addresses are not fingerprinted
+	require.Equal(t, "1500", attributes["network.interface.eth0.mtu"])
+	require.Equal(t, "17:2c:36:43", attributes["network.interface.eth0.mac"])
+	require.Equal(t, "100.64.0.0", attributes["network.interface.eth0.ipv4"])
+	require.Equal(t, "2001:db8:85a3::", attributes["network.interface.eth0.ipv6"])
+	require.NotContains(t, attributes, "network.interface.eth3.ipv4")
+	require.Equal(t, "100.64.0.0", attributes["network.interface.eth4.ipv4"])
+
+	// interfaces that are down and loopback interfaces are skipped
+	require.NotContains(t, attributes, "network.interface.eth1.mtu")
+	require.NotContains(t, attributes, "network.interface.lo.mtu")
+}
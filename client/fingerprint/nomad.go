@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 // NomadFingerprint is used to fingerprint the Nomad version
@@ -26,6 +27,15 @@ func (f *NomadFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerprin
 	resp.AddAttribute("nomad.version", req.Config.Version.VersionNumber())
 	resp.AddAttribute("nomad.revision", req.Config.Version.Revision)
 	resp.AddAttribute("nomad.service_discovery", strconv.FormatBool(req.Config.NomadServiceDiscovery))
+	if conf := req.Config.DynamicHostVolumes; conf != nil {
+		resp.AddAttribute(structs.NodeAttrDynamicHostVolumes, "true")
+		if conf.MaxSizeMB > 0 {
+			resp.AddAttribute(structs.NodeAttrDynamicHostVolumesMaxSize, strconv.Itoa(conf.MaxSizeMB))
+		}
+		if conf.Plugin != "" {
+			resp.AddAttribute(structs.NodeAttrDynamicHostVolumesPlugin, conf.Plugin)
+		}
+	}
 	resp.Detected = true
 	return nil
 }
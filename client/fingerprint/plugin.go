@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package fingerprint
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper/pluginutils/loader"
+	fpplugin "github.com/hashicorp/nomad/plugins/fingerprint"
+)
+
+const (
+	// pluginFingerprintTimeout is the maximum time a fingerprint plugin is
+	// given to fingerprint the node.
+	pluginFingerprintTimeout = 30 * time.Second
+)
+
+// PluginFingerprint is used to run an external fingerprint plugin as a
+// fingerprinter. The plugin is dispensed on first use and again whenever it
+// has exited.
+type PluginFingerprint struct {
+	name     string
+	dispense func() (loader.PluginInstance, error)
+	logger   log.Logger
+
+	// instance is the currently running plugin
+	instance loader.PluginInstance
+
+	// period is the fingerprint period last reported by the plugin
+	period time.Duration
+
+	// healthy is whether the last fingerprint was healthy
+	healthy bool
+
+	// attributes are the attributes set by the last fingerprint, which are
+	// removed from the node if the plugin stops reporting them
+	attributes map[string]struct{}
+
+	l sync.Mutex
+}
+
+// NewPluginFingerprint returns a fingerprinter for the named fingerprint
+// plugin, using the dispense function to launch the plugin.
+func NewPluginFingerprint(name string, dispense func() (loader.PluginInstance, error), logger log.Logger) *PluginFingerprint {
+	return &PluginFingerprint{
+		name:       name,
+		dispense:   dispense,
+		logger:     logger.Named("fingerprint_plugin").With("plugin", name),
+		healthy:    true,
+		attributes: make(map[string]struct{}),
+	}
+}
+
+func (f *PluginFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintResponse) error {
+	f.l.Lock()
+	defer f.l.Unlock()
+
+	out, err := f.fingerprint()
+	if err != nil {
+		// A failing plugin must not prevent the client from starting, so the
+		// failure is treated as the plugin being unhealthy
+		out = &fpplugin.FingerprintResponse{
+			Period:            f.period,
+			HealthDescription: err.Error(),
+		}
+	}
+
+	if out.Healthy != f.healthy {
+		if out.Healthy {
+			f.logger.Info("fingerprint plugin is healthy")
+		} else {
+			f.logger.Warn("fingerprint plugin is unhealthy", "description", out.HealthDescription)
+		}
+	}
+	f.healthy = out.Healthy
+	f.period = out.Period
+
+	// Attributes of an unhealthy plugin can't be trusted, so they're removed
+	// along with any that the plugin no longer reports
+	attributes := out.Attributes
+	if !out.Healthy {
+		attributes = nil
+	}
+	for name := range f.attributes {
+		if _, ok := attributes[name]; !ok {
+			resp.RemoveAttribute(name)
+			delete(f.attributes, name)
+		}
+	}
+	for name, value := range attributes {
+		resp.AddAttribute(name, value)
+		f.attributes[name] = struct{}{}
+	}
+
+	resp.Detected = len(f.attributes) > 0
+	return nil
+}
+
+// fingerprint calls the plugin, dispensing it first if it isn't running.
+func (f *PluginFingerprint) fingerprint() (*fpplugin.FingerprintResponse, error) {
+	if f.instance == nil || f.instance.Exited() {
+		instance, err := f.dispense()
+		if err != nil {
+			return nil, fmt.Errorf("failed to dispense plugin: %v", err)
+		}
+		f.instance = instance
+	}
+
+	plugin, ok := f.instance.Plugin().(fpplugin.FingerprintPlugin)
+	if !ok {
+		return nil, fmt.Errorf("plugin loaded does not implement the fingerprint interface")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginFingerprintTimeout)
+	defer cancel()
+
+	return plugin.Fingerprint(ctx)
+}
+
+// Periodic returns the fingerprint period last reported by the plugin.
+func (f *PluginFingerprint) Periodic() (bool, time.Duration) {
+	f.l.Lock()
+	defer f.l.Unlock()
+	return f.period > 0, f.period
+}
+
+// Shutdown kills the plugin if it is running.
+func (f *PluginFingerprint) Shutdown() {
+	f.l.Lock()
+	defer f.l.Unlock()
+	if f.instance != nil {
+		f.instance.Kill()
+		f.instance = nil
+	}
+}
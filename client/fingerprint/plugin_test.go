@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package fingerprint
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pluginutils/loader"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/plugins/base"
+	fpplugin "github.com/hashicorp/nomad/plugins/fingerprint"
+	"github.com/shoenig/test/must"
+)
+
+func TestPluginFingerprint(t *testing.T) {
+	ci.Parallel(t)
+
+	mock := &fpplugin.MockFingerprintPlugin{
+		MockPlugin: &base.MockPlugin{},
+		FingerprintF: fpplugin.StaticFingerprinter(&fpplugin.FingerprintResponse{
+			Attributes: map[string]string{
+				"fpga.vendor": "xilinx",
+				"fpga.count":  "2",
+			},
+			Period:  time.Minute,
+			Healthy: true,
+		}),
+	}
+
+	dispensed := 0
+	instance := loader.MockBasicExternalPlugin(mock, fpplugin.ApiVersion010)
+	fp := NewPluginFingerprint("fpga", func() (loader.PluginInstance, error) {
+		dispensed++
+		return instance, nil
+	}, testlog.HCLogger(t))
+
+	var resp FingerprintResponse
+	must.NoError(t, fp.Fingerprint(&FingerprintRequest{}, &resp))
+	must.True(t, resp.Detected)
+	must.Eq(t, map[string]string{
+		"fpga.vendor": "xilinx",
+		"fpga.count":  "2",
+	}, resp.Attributes)
+
+	periodic, period := fp.Periodic()
+	must.True(t, periodic)
+	must.Eq(t, time.Minute, period)
+
+	// Attributes the plugin no longer reports are removed
+	mock.FingerprintF = fpplugin.StaticFingerprinter(&fpplugin.FingerprintResponse{
+		Attributes: map[string]string{"fpga.vendor": "xilinx"},
+		Period:     time.Minute,
+		Healthy:    true,
+	})
+	resp = FingerprintResponse{}
+	must.NoError(t, fp.Fingerprint(&FingerprintRequest{}, &resp))
+	must.Eq(t, map[string]string{
+		"fpga.vendor": "xilinx",
+		"fpga.count":  "",
+	}, resp.Attributes)
+
+	// An unhealthy plugin has all of its attributes removed
+	mock.FingerprintF = fpplugin.StaticFingerprinter(&fpplugin.FingerprintResponse{
+		Attributes:        map[string]string{"fpga.vendor": "xilinx"},
+		Period:            time.Minute,
+		HealthDescription: "card not responding",
+	})
+	resp = FingerprintResponse{}
+	must.NoError(t, fp.Fingerprint(&FingerprintRequest{}, &resp))
+	must.False(t, resp.Detected)
+	must.Eq(t, map[string]string{"fpga.vendor": ""}, resp.Attributes)
+	must.Eq(t, 1, dispensed)
+
+	// A plugin that has exited is dispensed again
+	fp.Shutdown()
+	instance = loader.MockBasicExternalPlugin(mock, fpplugin.ApiVersion010)
+	resp = FingerprintResponse{}
+	must.NoError(t, fp.Fingerprint(&FingerprintRequest{}, &resp))
+	must.Eq(t, 2, dispensed)
+}
+
+func TestPluginFingerprint_Error(t *testing.T) {
+	ci.Parallel(t)
+
+	fp := NewPluginFingerprint("fpga", func() (loader.PluginInstance, error) {
+		return nil, errors.New("plugin not found")
+	}, testlog.HCLogger(t))
+
+	// A plugin failing to fingerprint is unhealthy rather than failing the
+	// client
+	var resp FingerprintResponse
+	must.NoError(t, fp.Fingerprint(&FingerprintRequest{}, &resp))
+	must.False(t, resp.Detected)
+
+	periodic, _ := fp.Periodic()
+	must.False(t, periodic)
+}
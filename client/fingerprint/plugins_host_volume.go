@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package fingerprint
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/hostvolumemanager"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// hostVolumePluginFingerprintPeriod is how often plugins are
+	// fingerprinted, so that the capacity they report stays current for the
+	// scheduler
+	hostVolumePluginFingerprintPeriod = time.Minute
+)
+
+// PluginsHostVolumeFingerprint fingerprints the version and available
+// capacity of the host volume plugins of a client with dynamic host volumes.
+type PluginsHostVolumeFingerprint struct {
+	logger hclog.Logger
+
+	// detected are the IDs of the plugins found by the last fingerprint, so
+	// the attributes of removed plugins can be removed
+	detected map[string]struct{}
+	lock     sync.Mutex
+}
+
+func NewPluginsHostVolumeFingerprint(logger hclog.Logger) Fingerprint {
+	return &PluginsHostVolumeFingerprint{
+		logger:   logger.Named("host_volume_plugins"),
+		detected: make(map[string]struct{}),
+	}
+}
+
+func (f *PluginsHostVolumeFingerprint) Fingerprint(req *FingerprintRequest, resp *FingerprintResponse) error {
+	conf := req.Config.DynamicHostVolumes
+	if conf == nil {
+		return nil
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	plugins := hostvolumemanager.Plugins(f.logger, conf.PluginDir, conf.Path)
+	fps := hostvolumemanager.Fingerprint(f.logger, plugins)
+
+	for id := range f.detected {
+		if _, ok := fps[id]; !ok {
+			resp.RemoveAttribute(structs.HostVolumePluginVersionAttr(id))
+			resp.RemoveAttribute(structs.HostVolumePluginCapacityAttr(id))
+			delete(f.detected, id)
+		}
+	}
+
+	for id, fp := range fps {
+		resp.AddAttribute(structs.HostVolumePluginVersionAttr(id), fp.Version)
+		if fp.CapacityBytes > 0 {
+			resp.AddAttribute(structs.HostVolumePluginCapacityAttr(id),
+				strconv.FormatInt(fp.CapacityBytes, 10))
+		} else {
+			resp.RemoveAttribute(structs.HostVolumePluginCapacityAttr(id))
+		}
+		f.detected[id] = struct{}{}
+	}
+
+	resp.Detected = true
+	return nil
+}
+
+func (f *PluginsHostVolumeFingerprint) Periodic() (bool, time.Duration) {
+	return true, hostVolumePluginFingerprintPeriod
+}
@@ -7,18 +7,28 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
-const bytesPerMegabyte = 1024 * 1024
+const (
+	bytesPerMegabyte = 1024 * 1024
+
+	// storageFingerprintPeriod is the interval at which the storage
+	// capacity is fingerprinted after the client starts
+	storageFingerprintPeriod = time.Minute
+)
 
 // StorageFingerprint is used to measure the amount of storage free for
 // applications that the Nomad agent will run on this machine.
 type StorageFingerprint struct {
-	StaticFingerprinter
 	logger log.Logger
+
+	// detected is set once the disk resources of the node have been
+	// fingerprinted
+	detected bool
 }
 
 func NewStorageFingerprint(logger log.Logger) Fingerprint {
@@ -57,6 +67,15 @@ func (f *StorageFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerpr
 
 	resp.AddAttribute("unique.storage.volume", volume)
 	resp.AddAttribute("unique.storage.bytestotal", strconv.FormatUint(total, 10))
+	resp.Detected = true
+
+	// The free disk is the disk available to allocations, which reserve
+	// their disk from it, so it's only fingerprinted when the client starts
+	// and later fingerprints only pick up changes in capacity
+	if f.detected {
+		return nil
+	}
+
 	resp.AddAttribute("unique.storage.bytesfree", strconv.FormatUint(free, 10))
 
 	// set the disk size for the response
@@ -65,7 +84,13 @@ func (f *StorageFingerprint) Fingerprint(req *FingerprintRequest, resp *Fingerpr
 			DiskMB: int64(free / bytesPerMegabyte),
 		},
 	}
-	resp.Detected = true
+	f.detected = true
 
 	return nil
 }
+
+// Periodic fingerprints the storage capacity periodically so that volumes
+// being resized are picked up without restarting the client.
+func (f *StorageFingerprint) Periodic() (bool, time.Duration) {
+	return true, storageFingerprintPeriod
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
 )
 
 func TestStorageFingerprint(t *testing.T) {
@@ -47,3 +48,28 @@ func TestStorageFingerprint(t *testing.T) {
 		t.Errorf("Expected node.Resources.DiskMB to be non-zero")
 	}
 }
+
+func TestStorageFingerprint_Periodic(t *testing.T) {
+	ci.Parallel(t)
+
+	fp := NewStorageFingerprint(testlog.HCLogger(t))
+	node := &structs.Node{
+		Attributes: make(map[string]string),
+	}
+
+	periodic, period := fp.Periodic()
+	must.True(t, periodic)
+	must.Eq(t, storageFingerprintPeriod, period)
+
+	response := assertFingerprintOK(t, fp, node)
+	must.MapContainsKey(t, response.Attributes, "unique.storage.bytesfree")
+	must.NotNil(t, response.NodeResources)
+
+	// Later fingerprints only report the storage capacity, as the free disk
+	// is reserved by allocations
+	response = assertFingerprintOK(t, fp, node)
+	must.True(t, response.Detected)
+	must.MapContainsKey(t, response.Attributes, "unique.storage.bytestotal")
+	must.MapNotContainsKey(t, response.Attributes, "unique.storage.bytesfree")
+	must.Nil(t, response.NodeResources)
+}
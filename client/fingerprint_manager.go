@@ -10,8 +10,10 @@ import (
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/client/fingerprint"
+	"github.com/hashicorp/nomad/client/lib/numalib"
 	"github.com/hashicorp/nomad/helper/pluginutils/loader"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/base"
 )
 
 // FingerprintManager runs a client fingerprinters on a continuous basis, and
@@ -29,6 +31,9 @@ type FingerprintManager struct {
 
 	reloadableFps map[string]fingerprint.ReloadableFingerprint
 
+	// pluginFps are the fingerprinters running external fingerprint plugins
+	pluginFps []*fingerprint.PluginFingerprint
+
 	// initialResult is used to pass information detected during the first pass
 	// of fingerprinting back to the client
 	initialResult *fingerprint.InitialResult
@@ -108,6 +113,26 @@ func (fm *FingerprintManager) Run() (*fingerprint.InitialResult, error) {
 		return nil, err
 	}
 
+	// Fingerprint plugins run after the built-in fingerprinters so that they
+	// may override the attributes they detect
+	var availablePlugins []string
+	for _, info := range fm.singletonLoader.Catalog()[base.PluginTypeFingerprint] {
+		if _, ok := allowlistFingerprints[info.Name]; allowlistFingerprintsEnabled && !ok {
+			skippedFingerprints = append(skippedFingerprints, info.Name)
+			continue
+		}
+		if _, ok := denylistFingerprints[info.Name]; ok {
+			skippedFingerprints = append(skippedFingerprints, info.Name)
+			continue
+		}
+
+		availablePlugins = append(availablePlugins, info.Name)
+	}
+
+	if len(availablePlugins) != 0 {
+		fm.setupPluginFingerprinters(availablePlugins)
+	}
+
 	if len(skippedFingerprints) != 0 {
 		fm.logger.Debug("fingerprint modules skipped due to allow/denylist",
 			"skipped_fingerprinters", skippedFingerprints)
@@ -164,6 +189,43 @@ func (fm *FingerprintManager) setupFingerprinters(fingerprints []string) error {
 	return nil
 }
 
+// setupPluginFingerprinters fingerprints the node with the given fingerprint
+// plugins. A plugin failing to fingerprint doesn't prevent the client from
+// starting, as the plugin is then considered unhealthy.
+func (fm *FingerprintManager) setupPluginFingerprinters(plugins []string) {
+	pluginConfig := fm.getConfig().NomadPluginConfig(numalib.NoImpl(fm.initialResult.Topology))
+
+	var appliedPlugins []string
+	for _, name := range plugins {
+		name := name
+		f := fingerprint.NewPluginFingerprint(name, func() (loader.PluginInstance, error) {
+			return fm.singletonLoader.Dispense(name, base.PluginTypeFingerprint, pluginConfig, fm.logger)
+		}, fm.logger)
+		fm.pluginFps = append(fm.pluginFps, f)
+
+		detected, err := fm.fingerprint(name, f)
+		if err != nil {
+			fm.logger.Error("error fingerprinting", "error", err, "fingerprinter", name)
+		}
+		if detected {
+			appliedPlugins = append(appliedPlugins, name)
+		}
+
+		if p, _ := f.Periodic(); p {
+			go fm.runFingerprint(f, name)
+		}
+	}
+
+	go func() {
+		<-fm.shutdownCh
+		for _, f := range fm.pluginFps {
+			f.Shutdown()
+		}
+	}()
+
+	fm.logger.Debug("detected fingerprint plugins", "node_attrs", appliedPlugins)
+}
+
 // runFingerprint runs each fingerprinter individually on an ongoing basis
 func (fm *FingerprintManager) runFingerprint(f fingerprint.Fingerprint, name string) {
 	_, period := f.Periodic()
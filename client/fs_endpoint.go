@@ -4,6 +4,7 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
@@ -25,6 +26,7 @@ import (
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/allocdir"
 	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
+	"github.com/hashicorp/nomad/client/logmon/logging"
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -82,6 +84,8 @@ func NewFileSystemEndpoint(c *Client) *FileSystem {
 	f := &FileSystem{c}
 	f.c.streamingRpcs.Register("FileSystem.Logs", f.logs)
 	f.c.streamingRpcs.Register("FileSystem.Stream", f.stream)
+	f.c.streamingRpcs.Register("FileSystem.Archive", f.archive)
+	f.c.streamingRpcs.Register("FileSystem.Extract", f.extract)
 	return f
 }
 
@@ -108,10 +112,10 @@ func (f *FileSystem) List(args *cstructs.FsListRequest, reply *cstructs.FsListRe
 		return err
 	}
 
-	// Check namespace read-fs permission.
+	// Check read-fs permission for the namespace or the task of the path.
 	if aclObj, err := f.c.ResolveToken(args.QueryOptions.AuthToken); err != nil {
 		return err
-	} else if !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS) {
+	} else if !aclObj.AllowJobTaskOperation(alloc.Namespace, alloc.JobID, alloc.TaskForPath(args.Path), acl.NamespaceCapabilityReadFS) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -137,10 +141,10 @@ func (f *FileSystem) Stat(args *cstructs.FsStatRequest, reply *cstructs.FsStatRe
 		return err
 	}
 
-	// Check namespace read-fs permission.
+	// Check read-fs permission for the namespace or the task of the path.
 	if aclObj, err := f.c.ResolveToken(args.QueryOptions.AuthToken); err != nil {
 		return err
-	} else if !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS) {
+	} else if !aclObj.AllowJobTaskOperation(alloc.Namespace, alloc.JobID, alloc.TaskForPath(args.Path), acl.NamespaceCapabilityReadFS) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -197,7 +201,7 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 	if aclObj, err := f.c.ResolveToken(req.QueryOptions.AuthToken); err != nil {
 		handleStreamResultError(err, pointer.Of(int64(http.StatusForbidden)), encoder)
 		return
-	} else if !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS) {
+	} else if !aclObj.AllowJobTaskOperation(alloc.Namespace, alloc.JobID, alloc.TaskForPath(req.Path), acl.NamespaceCapabilityReadFS) {
 		handleStreamResultError(structs.ErrPermissionDenied, pointer.Of(int64(http.StatusForbidden)), encoder)
 		return
 	}
@@ -343,6 +347,154 @@ OUTER:
 	}
 }
 
+// archive is used to stream a tar archive of a file or directory of an
+// allocation.
+func (f *FileSystem) archive(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "file_system", "archive"}, time.Now())
+	defer conn.Close()
+
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	fs, req, code, err := f.archiveRequest(decoder, acl.NamespaceCapabilityReadFS)
+	if err != nil {
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	// Send the archive in frames of at most streamFrameSize bytes.
+	w := bufio.NewWriterSize(&streamPayloadWriter{conn: conn, encoder: encoder}, streamFrameSize)
+	if err := fs.Archive(req.Path, w); err != nil {
+		code := pointer.Of(int64(http.StatusInternalServerError))
+		if os.IsNotExist(err) {
+			code = pointer.Of(int64(http.StatusNotFound))
+		}
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+	if err := w.Flush(); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(http.StatusInternalServerError)), encoder)
+	}
+}
+
+// extract is used to extract a tar archive into a directory of an
+// allocation. The archive is sent in frames following the request, and a
+// single frame is sent back once it has been extracted.
+func (f *FileSystem) extract(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"client", "file_system", "extract"}, time.Now())
+	defer conn.Close()
+
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	fs, req, code, err := f.archiveRequest(decoder, acl.NamespaceCapabilityWriteFS)
+	if err != nil {
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	// Feed the payload of the frames to the extraction until the empty frame
+	// that marks the end of the archive.
+	pr, pw := io.Pipe()
+	go func() {
+		for {
+			var frame cstructs.StreamErrWrapper
+			if err := decoder.Decode(&frame); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			if frame.Error != nil {
+				pw.CloseWithError(frame.Error)
+				return
+			}
+			if len(frame.Payload) == 0 {
+				pw.Close()
+				return
+			}
+			if _, err := pw.Write(frame.Payload); err != nil {
+				return
+			}
+		}
+	}()
+
+	err = fs.Extract(req.Path, pr)
+	pr.CloseWithError(io.ErrClosedPipe)
+	if err != nil {
+		code := pointer.Of(int64(http.StatusBadRequest))
+		if os.IsNotExist(err) {
+			code = pointer.Of(int64(http.StatusNotFound))
+		}
+		handleStreamResultError(err, code, encoder)
+		return
+	}
+
+	encoder.Encode(&cstructs.StreamErrWrapper{})
+}
+
+// archiveRequest decodes and validates the request of the archive and extract
+// endpoints, and checks the token has the given capability. It returns the
+// filesystem of the allocation, or an error with its code.
+func (f *FileSystem) archiveRequest(decoder *codec.Decoder, capability string) (
+	allocdir.AllocDirFS, *cstructs.FsArchiveRequest, *int64, error) {
+
+	var req cstructs.FsArchiveRequest
+	if err := decoder.Decode(&req); err != nil {
+		return nil, nil, pointer.Of(int64(http.StatusInternalServerError)), err
+	}
+
+	if req.AllocID == "" {
+		return nil, nil, pointer.Of(int64(http.StatusBadRequest)), allocIDNotPresentErr
+	}
+
+	ar, err := f.c.getAllocRunner(req.AllocID)
+	if err != nil {
+		return nil, nil, pointer.Of(int64(http.StatusNotFound)), structs.NewErrUnknownAllocation(req.AllocID)
+	}
+	if ar.IsDestroyed() {
+		return nil, nil, pointer.Of(int64(http.StatusNotFound)),
+			fmt.Errorf("state for allocation %s not found on client", req.AllocID)
+	}
+	alloc := ar.Alloc()
+
+	if aclObj, err := f.c.ResolveToken(req.QueryOptions.AuthToken); err != nil {
+		return nil, nil, pointer.Of(int64(http.StatusForbidden)), err
+	} else if !aclObj.AllowJobTaskOperation(alloc.Namespace, alloc.JobID, alloc.TaskForPath(req.Path), capability) {
+		return nil, nil, pointer.Of(int64(http.StatusForbidden)), structs.ErrPermissionDenied
+	}
+
+	if req.Path == "" {
+		return nil, nil, pointer.Of(int64(http.StatusBadRequest)), pathNotPresentErr
+	}
+
+	fs, err := f.c.GetAllocFS(req.AllocID)
+	if err != nil {
+		code := pointer.Of(int64(http.StatusInternalServerError))
+		if structs.IsErrUnknownAllocation(err) {
+			code = pointer.Of(int64(http.StatusNotFound))
+		}
+		return nil, nil, code, err
+	}
+	return fs, &req, nil, nil
+}
+
+// streamPayloadWriter writes each buffer as the payload of a
+// StreamErrWrapper.
+type streamPayloadWriter struct {
+	conn    io.Writer
+	encoder *codec.Encoder
+}
+
+func (w *streamPayloadWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if err := w.encoder.Encode(&cstructs.StreamErrWrapper{Payload: p}); err != nil {
+		return 0, err
+	}
+	w.encoder.Reset(w.conn)
+	return len(p), nil
+}
+
 // logs is is used to stream a task's logs.
 func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 	defer metrics.MeasureSince([]string{"client", "file_system", "logs"}, time.Now())
@@ -385,8 +537,8 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 		return
 	}
 
-	readfs := aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS)
-	logs := aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadLogs)
+	readfs := aclObj.AllowJobTaskOperation(alloc.Namespace, alloc.JobID, req.Task, acl.NamespaceCapabilityReadFS)
+	logs := aclObj.AllowJobTaskOperation(alloc.Namespace, alloc.JobID, req.Task, acl.NamespaceCapabilityReadLogs)
 	if !readfs && !logs {
 		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 		return
@@ -612,7 +764,11 @@ func (f *FileSystem) logsImpl(ctx context.Context, follow, plain bool, offset in
 		}
 
 		p := filepath.Join(logPath, logEntry.Name)
-		err = f.streamFile(ctx, openOffset, p, 0, fs, framer, eofCancelCh, cancelAfterFirstEof)
+		if _, ext := logging.SplitCompressedExt(logEntry.Name); ext != "" {
+			err = f.streamCompressedFile(ctx, openOffset, p, ext, fs, framer)
+		} else {
+			err = f.streamFile(ctx, openOffset, p, 0, fs, framer, eofCancelCh, cancelAfterFirstEof)
+		}
 
 		// Check if the context is cancelled
 		select {
@@ -802,6 +958,60 @@ OUTER:
 	}
 }
 
+// streamCompressedFile streams the decompressed content of a rotated log file
+// compressed by logmon, starting at the offset into the decompressed content.
+// Compressed files are never written to again, so the stream ends at EOF.
+func (f *FileSystem) streamCompressedFile(ctx context.Context, offset int64, path, ext string,
+	fs allocdir.AllocDirFS, framer *sframer.StreamFramer) error {
+
+	file, err := fs.ReadAt(path, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	dec, err := logging.NewDecompressReader(file, ext)
+	if err != nil {
+		return err
+	}
+	defer dec.Close()
+
+	// Skip to the offset in the decompressed content
+	if _, err := io.CopyN(io.Discard, dec, offset); err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+
+	data := make([]byte, streamFrameSize)
+	for {
+		n, readErr := dec.Read(data)
+		offset += int64(n)
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+
+		if n != 0 {
+			if err := framer.Send(path, "", data[:n], offset); err != nil {
+				return parseFramerErr(err)
+			}
+		}
+
+		if readErr == io.EOF {
+			return nil
+		}
+
+		select {
+		case <-framer.ExitCh():
+			return nil
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+	}
+}
+
 // blockUntilNextLog returns a channel that will have data sent when the next
 // log index or anything greater is created.
 func blockUntilNextLog(ctx context.Context, fs allocdir.AllocDirFS, logPath, task, logType string, nextIndex int64) chan error {
@@ -874,10 +1084,12 @@ func (a indexTupleArray) Less(i, j int) bool { return a[i].idx < a[j].idx }
 func (a indexTupleArray) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 
 // logIndexes takes a set of entries and returns a indexTupleArray of
-// the desired log file entries. If the indexes could not be determined, an
-// error is returned.
+// the desired log file entries. Rotated files compressed by logmon are
+// included, unless the uncompressed file still exists while it is being
+// compressed. If the indexes could not be determined, an error is returned.
 func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexTupleArray, error) {
 	var indexes []indexTuple
+	positions := make(map[int64]int)
 	prefix := fmt.Sprintf("%s.%s.", task, logType)
 	for _, entry := range entries {
 		if entry.IsDir {
@@ -889,6 +1101,7 @@ func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexT
 		if idxStr == entry.Name {
 			continue
 		}
+		idxStr, ext := logging.SplitCompressedExt(idxStr)
 
 		// Convert to an int
 		idx, err := strconv.Atoi(idxStr)
@@ -896,6 +1109,15 @@ func logIndexes(entries []*cstructs.AllocFileInfo, task, logType string) (indexT
 			return nil, fmt.Errorf("failed to convert %q to a log index: %v", idxStr, err)
 		}
 
+		// Prefer the uncompressed file if both exist
+		if pos, ok := positions[int64(idx)]; ok {
+			if ext == "" {
+				indexes[pos].entry = entry
+			}
+			continue
+		}
+
+		positions[int64(idx)] = len(indexes)
 		indexes = append(indexes, indexTuple{idx: int64(idx), entry: entry})
 	}
 
@@ -4,6 +4,7 @@
 package client
 
 import (
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -1741,6 +1742,35 @@ func TestFS_findClosest(t *testing.T) {
 	}
 }
 
+func TestFS_logIndexes_Compressed(t *testing.T) {
+	ci.Parallel(t)
+
+	entries := []*cstructs.AllocFileInfo{
+		{Name: "foo.stdout.0.gz", Size: 10},
+		{Name: "foo.stdout.1.zst", Size: 10},
+		{Name: "foo.stdout.2.gz", Size: 10},
+		{Name: "foo.stdout.2", Size: 100},
+		{Name: "foo.stdout.3", Size: 100},
+		{Name: ".compressing.foo.stdout.3.gz", Size: 1},
+	}
+
+	indexes, err := logIndexes(entries, "foo", "stdout")
+	must.NoError(t, err)
+
+	names := make(map[int64]string)
+	for _, index := range indexes {
+		names[index.idx] = index.entry.Name
+	}
+
+	// The uncompressed file is preferred while it is being compressed
+	must.Eq(t, map[int64]string{
+		0: "foo.stdout.0.gz",
+		1: "foo.stdout.1.zst",
+		2: "foo.stdout.2",
+		3: "foo.stdout.3",
+	}, names)
+}
+
 func TestFS_streamFile_NoFile(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1765,6 +1795,47 @@ func TestFS_streamFile_NoFile(t *testing.T) {
 	}
 }
 
+func TestFS_streamCompressedFile(t *testing.T) {
+	ci.Parallel(t)
+
+	c, cleanup := TestClient(t, nil)
+	defer cleanup()
+
+	ad := tempAllocDir(t)
+	must.NoError(t, ad.Build())
+	defer ad.Destroy()
+
+	// Write a gzip compressed file as rotated by logmon
+	streamFile := "foo.stdout.0.gz"
+	f, err := os.Create(filepath.Join(ad.AllocDir, streamFile))
+	must.NoError(t, err)
+	zw := gzip.NewWriter(f)
+	_, err = zw.Write([]byte("helloworld"))
+	must.NoError(t, err)
+	must.NoError(t, zw.Close())
+	must.NoError(t, f.Close())
+
+	frames := make(chan *sframer.StreamFrame, 32)
+	framer := sframer.NewStreamFramer(frames, streamHeartbeatRate, streamBatchWindow, streamFrameSize)
+	framer.Run()
+	defer framer.Destroy()
+
+	err = c.endpoints.FileSystem.streamCompressedFile(
+		context.Background(), 5, streamFile, ".gz", ad, framer)
+	must.NoError(t, err)
+
+	var collected []byte
+	timeout := time.After(10 * time.Duration(testutil.TestMultiplier()) * streamBatchWindow)
+	for string(collected) != "world" {
+		select {
+		case frame := <-frames:
+			collected = append(collected, frame.Data...)
+		case <-timeout:
+			t.Fatalf("failed to stream decompressed data, got %q", collected)
+		}
+	}
+}
+
 func TestFS_streamFile_Modify(t *testing.T) {
 	ci.Parallel(t)
 
@@ -10,6 +10,7 @@ import (
 	hclog "github.com/hashicorp/go-hclog"
 
 	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
+	"github.com/hashicorp/nomad/client/config"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -22,6 +23,10 @@ type heartbeatStop struct {
 	logger        hclog.InterceptLogger
 	shutdownCh    chan struct{}
 	lock          *sync.RWMutex
+
+	// edge is the edge scheduler config. Allocs of jobs it pins are never
+	// stopped on missing heartbeats.
+	edge *config.EdgeConfig
 }
 
 func newHeartbeatStop(
@@ -46,6 +51,10 @@ func newHeartbeatStop(
 // allocHook is called after (re)storing a new AllocRunner in the client. It registers the
 // allocation to be stopped if the taskgroup is configured appropriately
 func (h *heartbeatStop) allocHook(alloc *structs.Allocation) {
+	if isEdgePinned(h.edge, alloc) {
+		return
+	}
+
 	tg := allocTaskGroup(alloc)
 	if tg.GetDisconnectStopTimeout() != nil {
 		h.allocHookCh <- alloc
@@ -55,6 +64,10 @@ func (h *heartbeatStop) allocHook(alloc *structs.Allocation) {
 // shouldStop is called on a restored alloc to determine if lastOk is sufficiently in the
 // past that it should be prevented from restarting
 func (h *heartbeatStop) shouldStop(alloc *structs.Allocation) bool {
+	if isEdgePinned(h.edge, alloc) {
+		return false
+	}
+
 	tg := allocTaskGroup(alloc)
 	timeout := tg.GetDisconnectStopTimeout()
 	if timeout != nil {
@@ -67,7 +67,8 @@ func TestHeartbeatStop_allocHook(t *testing.T) {
 		must.NoError(t, err)
 	})
 
-	must.Nil(t, client.allocs[alloc.ID])
+	_, ok := client.allocs.get(alloc.ID)
+	must.False(t, ok)
 }
 
 // Test using stop_after_client_disconnect, remove after its deprecated  in favor
@@ -122,5 +123,6 @@ func TestHeartbeatStop_allocHook_Disconnect(t *testing.T) {
 		must.NoError(t, err)
 	})
 
-	must.Nil(t, client.allocs[alloc.ID])
+	_, ok := client.allocs.get(alloc.ID)
+	must.False(t, ok)
 }
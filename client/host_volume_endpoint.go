@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// HostVolume endpoint is used for creating and deleting host volumes with
+// host volume plugins on the client.
+type HostVolume struct {
+	c *Client
+}
+
+func newHostVolumeEndpoint(c *Client) *HostVolume {
+	return &HostVolume{c: c}
+}
+
+// Create provisions a host volume with a host volume plugin and registers it
+// with the node.
+func (v *HostVolume) Create(args *structs.HostVolumeCreateRequest, reply *structs.HostVolumeCreateResponse) error {
+	defer metrics.MeasureSince([]string{"client", "host_volume", "create"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := v.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
+	}
+
+	vol, err := v.c.createHostVolumeForRequest(args)
+	if err != nil {
+		return err
+	}
+
+	reply.NodeID = v.c.NodeID()
+	reply.Name = vol.Name
+	reply.Path = vol.Path
+	reply.CapacityBytes = vol.CapacityBytes
+	return nil
+}
+
+// Delete destroys a host volume created by a host volume plugin and
+// deregisters it from the node.
+func (v *HostVolume) Delete(args *structs.HostVolumeDeleteRequest, reply *structs.HostVolumeDeleteResponse) error {
+	defer metrics.MeasureSince([]string{"client", "host_volume", "delete"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := v.c.ResolveToken(args.AuthToken); err != nil {
+		return err
+	} else if !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
+	}
+
+	return v.c.deleteHostVolumeForRequest(args)
+}
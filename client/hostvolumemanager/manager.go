@@ -0,0 +1,292 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package hostvolumemanager provisions and destroys host volumes on the
+// client with host volume plugins, and keeps track of the volumes it created
+// across client restarts.
+package hostvolumemanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// bytesPerMegabyte is the number of bytes per MB
+	bytesPerMegabyte = 1024 * 1024
+
+	// pluginTimeout bounds how long a single plugin operation may run
+	pluginTimeout = 5 * time.Minute
+
+	// fingerprintTimeout bounds how long fingerprinting a plugin may run
+	fingerprintTimeout = 10 * time.Second
+)
+
+// validPluginID matches the file names of external plugins, which become
+// part of the plugin's node attributes
+var validPluginID = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Config configures the HostVolumeManager.
+type Config struct {
+	// PluginDir is the directory with the executables of external host
+	// volume plugins. If empty, only the built-in plugins are available.
+	PluginDir string
+
+	// VolumesDir is the directory under which plugins create volumes.
+	VolumesDir string
+
+	// StateDir is the directory where the manager stores the volumes it
+	// created.
+	StateDir string
+}
+
+// Volume is a host volume created by a plugin.
+type Volume struct {
+	Name          string
+	PluginID      string
+	Path          string
+	CapacityBytes int64
+	Parameters    map[string]string
+
+	// JobRequested is true for volumes created for an allocation's volume
+	// request with create = true, which are subject to the client's cleanup
+	// policy. Volumes created through the API are only deleted through it.
+	JobRequested bool
+}
+
+// HostVolumeManager creates and deletes host volumes with plugins.
+type HostVolumeManager struct {
+	config *Config
+	logger hclog.Logger
+
+	// volumes are the volumes created by the manager, keyed by name
+	volumes map[string]*Volume
+	lock    sync.Mutex
+}
+
+// NewHostVolumeManager returns a HostVolumeManager with the volumes it
+// created before the client restarted.
+func NewHostVolumeManager(logger hclog.Logger, config *Config) (*HostVolumeManager, error) {
+	m := &HostVolumeManager{
+		config:  config,
+		logger:  logger.Named("host_volume_manager"),
+		volumes: make(map[string]*Volume),
+	}
+
+	if err := os.MkdirAll(config.StateDir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create host volume state directory: %w", err)
+	}
+	entries, err := os.ReadDir(config.StateDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read host volume state directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		buf, err := os.ReadFile(filepath.Join(config.StateDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read host volume state: %w", err)
+		}
+		var vol Volume
+		if err := json.Unmarshal(buf, &vol); err != nil {
+			m.logger.Warn("ignoring invalid host volume state", "file", entry.Name(), "error", err)
+			continue
+		}
+		m.volumes[vol.Name] = &vol
+	}
+
+	return m, nil
+}
+
+// Plugins returns the built-in host volume plugins and the external plugins
+// found in pluginDir, keyed by plugin ID. External plugins are the
+// executables in the directory, and their ID is the file name.
+func Plugins(logger hclog.Logger, pluginDir, volumesDir string) map[string]HostVolumePlugin {
+	plugins := map[string]HostVolumePlugin{
+		structs.HostVolumePluginMkdirID: NewHostVolumePluginMkdir(logger, volumesDir),
+	}
+	if pluginDir == "" {
+		return plugins
+	}
+
+	entries, err := os.ReadDir(pluginDir)
+	if err != nil {
+		logger.Warn("failed to read host volume plugin directory", "plugin_dir", pluginDir, "error", err)
+		return plugins
+	}
+	for _, entry := range entries {
+		id := entry.Name()
+		if _, ok := plugins[id]; ok {
+			logger.Warn("ignoring host volume plugin with the ID of a built-in plugin", "plugin_id", id)
+			continue
+		}
+		if !validPluginID.MatchString(id) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode()&0o111 == 0 {
+			continue
+		}
+		plugins[id] = NewHostVolumePluginExternal(
+			logger, id, filepath.Join(pluginDir, id), pluginDir, volumesDir)
+	}
+	return plugins
+}
+
+// Fingerprint fingerprints every available plugin. Plugins that fail to
+// fingerprint are left out.
+func Fingerprint(logger hclog.Logger, plugins map[string]HostVolumePlugin) map[string]*PluginFingerprint {
+	fps := make(map[string]*PluginFingerprint, len(plugins))
+	for id, plugin := range plugins {
+		ctx, cancel := context.WithTimeout(context.Background(), fingerprintTimeout)
+		fp, err := plugin.Fingerprint(ctx)
+		cancel()
+		if err != nil {
+			logger.Warn("failed to fingerprint host volume plugin", "plugin_id", id, "error", err)
+			continue
+		}
+		fps[id] = fp
+	}
+	return fps
+}
+
+// Volumes returns the volumes created by the manager, sorted by name.
+func (m *HostVolumeManager) Volumes() []*Volume {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	vols := make([]*Volume, 0, len(m.volumes))
+	for _, vol := range m.volumes {
+		vols = append(vols, vol)
+	}
+	sort.Slice(vols, func(i, j int) bool { return vols[i].Name < vols[j].Name })
+	return vols
+}
+
+// Get returns the volume with the name if the manager created it.
+func (m *HostVolumeManager) Get(name string) (*Volume, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	vol, ok := m.volumes[name]
+	return vol, ok
+}
+
+// Create creates the volume with the plugin and persists it, so the volume
+// can be registered again after the client restarts.
+func (m *HostVolumeManager) Create(pluginID string, req *CreateRequest, jobRequested bool) (*Volume, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, ok := m.volumes[req.Name]; ok {
+		return nil, fmt.Errorf("host volume %q already exists", req.Name)
+	}
+
+	plugin, ok := Plugins(m.logger, m.config.PluginDir, m.config.VolumesDir)[pluginID]
+	if !ok {
+		return nil, fmt.Errorf("host volume plugin %q not found", pluginID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+	defer cancel()
+	resp, err := plugin.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	vol := &Volume{
+		Name:          req.Name,
+		PluginID:      pluginID,
+		Path:          resp.Path,
+		CapacityBytes: resp.SizeBytes,
+		Parameters:    req.Parameters,
+		JobRequested:  jobRequested,
+	}
+	if err := m.persist(vol); err != nil {
+		// Don't leave behind a volume the client would forget about
+		if delErr := plugin.Delete(ctx, &DeleteRequest{
+			Name:       vol.Name,
+			Path:       vol.Path,
+			Parameters: vol.Parameters,
+		}); delErr != nil {
+			m.logger.Error("failed to delete host volume after failing to persist it",
+				"volume", vol.Name, "error", delErr)
+		}
+		return nil, err
+	}
+	m.volumes[vol.Name] = vol
+
+	m.logger.Info("created host volume", "volume", vol.Name, "plugin_id", pluginID, "path", vol.Path)
+	return vol, nil
+}
+
+// Delete deletes a volume created by the manager with the plugin that
+// created it.
+func (m *HostVolumeManager) Delete(name string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	vol, ok := m.volumes[name]
+	if !ok {
+		return fmt.Errorf("host volume %q not found", name)
+	}
+
+	plugin, ok := Plugins(m.logger, m.config.PluginDir, m.config.VolumesDir)[vol.PluginID]
+	if !ok {
+		return fmt.Errorf("host volume plugin %q not found", vol.PluginID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+	defer cancel()
+	err := plugin.Delete(ctx, &DeleteRequest{
+		Name:       vol.Name,
+		Path:       vol.Path,
+		Parameters: vol.Parameters,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(m.statePath(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove host volume state: %w", err)
+	}
+	delete(m.volumes, name)
+
+	m.logger.Info("deleted host volume", "volume", name, "plugin_id", vol.PluginID)
+	return nil
+}
+
+func (m *HostVolumeManager) persist(vol *Volume) error {
+	buf, err := json.Marshal(vol)
+	if err != nil {
+		return fmt.Errorf("failed to encode host volume state: %w", err)
+	}
+
+	// Write to a temporary file first so a crash can't leave a partially
+	// written state file behind
+	path := m.statePath(vol.Name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf, 0o600); err != nil {
+		return fmt.Errorf("failed to write host volume state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write host volume state: %w", err)
+	}
+	return nil
+}
+
+func (m *HostVolumeManager) statePath(name string) string {
+	return filepath.Join(m.config.StateDir, name+".json")
+}
@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package hostvolumemanager
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// testPluginScript is an external plugin that creates volumes as
+// directories and fails to create volumes named "fail"
+const testPluginScript = `#!/bin/sh
+set -e
+case "$1" in
+  fingerprint)
+    echo '{"version": "0.1.0", "capacity_bytes": 1073741824}'
+    ;;
+  create)
+    if [ "$DHV_VOLUME_NAME" = "fail" ]; then
+      echo "no space left in volume group $(echo "$DHV_PARAMETERS" | cut -d'"' -f4)" >&2
+      exit 1
+    fi
+    mkdir -p "$DHV_VOLUMES_DIR/$DHV_VOLUME_NAME"
+    echo "{\"path\": \"$DHV_VOLUMES_DIR/$DHV_VOLUME_NAME\", \"bytes\": $DHV_CAPACITY_MIN_BYTES}"
+    ;;
+  delete)
+    rm -rf "$DHV_VOLUME_PATH"
+    ;;
+esac
+`
+
+func testManager(t *testing.T, withPlugin bool) (*HostVolumeManager, *Config) {
+	config := &Config{
+		VolumesDir: t.TempDir(),
+		StateDir:   filepath.Join(t.TempDir(), "host_volumes"),
+	}
+	if withPlugin {
+		config.PluginDir = t.TempDir()
+		must.NoError(t, os.WriteFile(
+			filepath.Join(config.PluginDir, "example"), []byte(testPluginScript), 0o755))
+
+		// Non-executables and invalid IDs aren't plugins
+		must.NoError(t, os.WriteFile(
+			filepath.Join(config.PluginDir, "README"), []byte("docs"), 0o644))
+		must.NoError(t, os.WriteFile(
+			filepath.Join(config.PluginDir, "bad.id"), []byte(testPluginScript), 0o755))
+	}
+
+	m, err := NewHostVolumeManager(hclog.NewNullLogger(), config)
+	must.NoError(t, err)
+	return m, config
+}
+
+func TestHostVolumeManager_Mkdir(t *testing.T) {
+	ci.Parallel(t)
+
+	m, config := testManager(t, false)
+
+	plugins := Plugins(hclog.NewNullLogger(), config.PluginDir, config.VolumesDir)
+	must.MapLen(t, 1, plugins)
+	fps := Fingerprint(hclog.NewNullLogger(), plugins)
+	must.Positive(t, fps[structs.HostVolumePluginMkdirID].CapacityBytes)
+
+	vol, err := m.Create(structs.HostVolumePluginMkdirID, &CreateRequest{Name: "data"}, true)
+	must.NoError(t, err)
+	must.Eq(t, filepath.Join(config.VolumesDir, "data"), vol.Path)
+	must.DirExists(t, vol.Path)
+
+	_, err = m.Create(structs.HostVolumePluginMkdirID, &CreateRequest{Name: "data"}, true)
+	must.ErrorContains(t, err, "already exists")
+	_, err = m.Create(structs.HostVolumePluginMkdirID, &CreateRequest{Name: "../data"}, true)
+	must.ErrorContains(t, err, "invalid volume name")
+	_, err = m.Create("missing", &CreateRequest{Name: "other"}, true)
+	must.ErrorContains(t, err, "not found")
+
+	// The volume is restored after a restart
+	restored, err := NewHostVolumeManager(hclog.NewNullLogger(), config)
+	must.NoError(t, err)
+	must.Eq(t, []*Volume{vol}, restored.Volumes())
+
+	must.NoError(t, restored.Delete("data"))
+	must.DirNotExists(t, vol.Path)
+	must.SliceEmpty(t, restored.Volumes())
+	must.ErrorContains(t, restored.Delete("data"), "not found")
+
+	restored, err = NewHostVolumeManager(hclog.NewNullLogger(), config)
+	must.NoError(t, err)
+	must.SliceEmpty(t, restored.Volumes())
+}
+
+func TestHostVolumeManager_External(t *testing.T) {
+	ci.Parallel(t)
+	if runtime.GOOS == "windows" {
+		t.Skip("test plugin is a shell script")
+	}
+
+	m, config := testManager(t, true)
+
+	plugins := Plugins(hclog.NewNullLogger(), config.PluginDir, config.VolumesDir)
+	must.MapLen(t, 2, plugins)
+	must.MapContainsKey(t, plugins, "example")
+
+	fps := Fingerprint(hclog.NewNullLogger(), plugins)
+	must.Eq(t, &PluginFingerprint{
+		Version:       "0.1.0",
+		CapacityBytes: 1073741824,
+	}, fps["example"])
+
+	vol, err := m.Create("example", &CreateRequest{
+		Name:             "data",
+		CapacityMinBytes: 1024,
+	}, false)
+	must.NoError(t, err)
+	must.Eq(t, &Volume{
+		Name:          "data",
+		PluginID:      "example",
+		Path:          filepath.Join(config.VolumesDir, "data"),
+		CapacityBytes: 1024,
+	}, vol)
+	must.DirExists(t, vol.Path)
+
+	// Plugin errors are returned with the plugin's stderr
+	_, err = m.Create("example", &CreateRequest{
+		Name:       "fail",
+		Parameters: map[string]string{"volume_group": "vg0"},
+	}, false)
+	must.ErrorContains(t, err, `plugin "example" failed to create: no space left in volume group vg0`)
+	must.SliceLen(t, 1, m.Volumes())
+
+	must.NoError(t, m.Delete("data"))
+	must.DirNotExists(t, vol.Path)
+}
@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package hostvolumemanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// HostVolumePlugin provisions and destroys host volumes on the client.
+type HostVolumePlugin interface {
+	// Fingerprint returns the version of the plugin and the capacity it has
+	// available for new volumes.
+	Fingerprint(ctx context.Context) (*PluginFingerprint, error)
+
+	// Create provisions the volume and returns the path it's mounted at.
+	Create(ctx context.Context, req *CreateRequest) (*CreateResponse, error)
+
+	// Delete destroys a volume previously created by the plugin.
+	Delete(ctx context.Context, req *DeleteRequest) error
+}
+
+// PluginFingerprint is the result of fingerprinting a host volume plugin.
+type PluginFingerprint struct {
+	// Version is the version of the plugin.
+	Version string `json:"version"`
+
+	// CapacityBytes is the capacity available for new volumes, or zero if
+	// the plugin doesn't report it.
+	CapacityBytes int64 `json:"capacity_bytes"`
+}
+
+// CreateRequest is the volume a plugin is asked to create.
+type CreateRequest struct {
+	Name             string
+	CapacityMinBytes int64
+	CapacityMaxBytes int64
+	Parameters       map[string]string
+}
+
+// CreateResponse is the volume created by a plugin.
+type CreateResponse struct {
+	// Path is the path on the host where the volume is mounted.
+	Path string `json:"path"`
+
+	// SizeBytes is the size of the created volume, if known.
+	SizeBytes int64 `json:"bytes"`
+}
+
+// DeleteRequest is the volume a plugin is asked to delete.
+type DeleteRequest struct {
+	Name       string
+	Path       string
+	Parameters map[string]string
+}
+
+// HostVolumePluginMkdir is the built-in plugin that creates each volume as
+// a directory under the volumes directory.
+type HostVolumePluginMkdir struct {
+	VolumesDir string
+
+	logger hclog.Logger
+}
+
+// NewHostVolumePluginMkdir returns the built-in mkdir plugin creating
+// volumes under volumesDir.
+func NewHostVolumePluginMkdir(logger hclog.Logger, volumesDir string) *HostVolumePluginMkdir {
+	return &HostVolumePluginMkdir{
+		VolumesDir: volumesDir,
+		logger:     logger.With("plugin_id", structs.HostVolumePluginMkdirID),
+	}
+}
+
+func (p *HostVolumePluginMkdir) Fingerprint(_ context.Context) (*PluginFingerprint, error) {
+	usage, err := disk.Usage(p.VolumesDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine free disk space: %w", err)
+	}
+	return &PluginFingerprint{
+		Version:       "1.0.0",
+		CapacityBytes: int64(usage.Free),
+	}, nil
+}
+
+func (p *HostVolumePluginMkdir) Create(_ context.Context, req *CreateRequest) (*CreateResponse, error) {
+	path, err := p.volumePath(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.CapacityMinBytes > 0 {
+		usage, err := disk.Usage(p.VolumesDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine free disk space: %w", err)
+		}
+		if usage.Free < uint64(req.CapacityMinBytes) {
+			return nil, fmt.Errorf("size %d MB exceeds the free disk space of %d MB",
+				req.CapacityMinBytes/bytesPerMegabyte, usage.Free/bytesPerMegabyte)
+		}
+	}
+
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return nil, err
+	}
+
+	p.logger.Debug("created host volume directory", "volume", req.Name, "path", path)
+	return &CreateResponse{
+		Path:      path,
+		SizeBytes: req.CapacityMinBytes,
+	}, nil
+}
+
+func (p *HostVolumePluginMkdir) Delete(_ context.Context, req *DeleteRequest) error {
+	path, err := p.volumePath(req.Name)
+	if err != nil {
+		return err
+	}
+	if req.Path != "" && req.Path != path {
+		return fmt.Errorf("volume path %q is not under %q", req.Path, p.VolumesDir)
+	}
+	return os.RemoveAll(path)
+}
+
+func (p *HostVolumePluginMkdir) volumePath(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || filepath.Base(name) != name {
+		return "", errors.New("invalid volume name")
+	}
+	return filepath.Join(p.VolumesDir, name), nil
+}
@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package hostvolumemanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+const (
+	// Operations passed to external plugins as their first argument and in
+	// the DHV_OPERATION environment variable.
+	operationFingerprint = "fingerprint"
+	operationCreate      = "create"
+	operationDelete      = "delete"
+
+	// Environment variables passed to external plugins.
+	envOperation        = "DHV_OPERATION"
+	envVolumesDir       = "DHV_VOLUMES_DIR"
+	envVolumeName       = "DHV_VOLUME_NAME"
+	envVolumePath       = "DHV_VOLUME_PATH"
+	envCapacityMinBytes = "DHV_CAPACITY_MIN_BYTES"
+	envCapacityMaxBytes = "DHV_CAPACITY_MAX_BYTES"
+	envParameters       = "DHV_PARAMETERS"
+	envPluginDir        = "DHV_PLUGIN_DIR"
+)
+
+// HostVolumePluginExternal is a host volume plugin implemented by an
+// executable in the client's host volume plugin directory. Each operation
+// runs the executable once with the operation as its only argument and the
+// request in DHV_* environment variables. The fingerprint and create
+// operations write their result to stdout as JSON, and a non-zero exit code
+// fails the operation with stderr as the error.
+type HostVolumePluginExternal struct {
+	ID         string
+	Executable string
+	PluginDir  string
+	VolumesDir string
+
+	logger hclog.Logger
+}
+
+// NewHostVolumePluginExternal returns the plugin for the executable.
+func NewHostVolumePluginExternal(logger hclog.Logger, id, executable, pluginDir, volumesDir string) *HostVolumePluginExternal {
+	return &HostVolumePluginExternal{
+		ID:         id,
+		Executable: executable,
+		PluginDir:  pluginDir,
+		VolumesDir: volumesDir,
+		logger:     logger.With("plugin_id", id),
+	}
+}
+
+func (p *HostVolumePluginExternal) Fingerprint(ctx context.Context) (*PluginFingerprint, error) {
+	stdout, err := p.run(ctx, operationFingerprint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var fp PluginFingerprint
+	if err := json.Unmarshal(stdout, &fp); err != nil {
+		return nil, fmt.Errorf("failed to decode fingerprint from plugin %q: %w", p.ID, err)
+	}
+	if fp.Version == "" {
+		return nil, fmt.Errorf("plugin %q fingerprinted without a version", p.ID)
+	}
+	return &fp, nil
+}
+
+func (p *HostVolumePluginExternal) Create(ctx context.Context, req *CreateRequest) (*CreateResponse, error) {
+	params, err := json.Marshal(req.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode parameters: %w", err)
+	}
+
+	stdout, err := p.run(ctx, operationCreate, []string{
+		envVolumeName + "=" + req.Name,
+		envCapacityMinBytes + "=" + strconv.FormatInt(req.CapacityMinBytes, 10),
+		envCapacityMaxBytes + "=" + strconv.FormatInt(req.CapacityMaxBytes, 10),
+		envParameters + "=" + string(params),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp CreateResponse
+	if err := json.Unmarshal(stdout, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode volume from plugin %q: %w", p.ID, err)
+	}
+	if resp.Path == "" {
+		return nil, fmt.Errorf("plugin %q created volume without a path", p.ID)
+	}
+	return &resp, nil
+}
+
+func (p *HostVolumePluginExternal) Delete(ctx context.Context, req *DeleteRequest) error {
+	params, err := json.Marshal(req.Parameters)
+	if err != nil {
+		return fmt.Errorf("failed to encode parameters: %w", err)
+	}
+
+	_, err = p.run(ctx, operationDelete, []string{
+		envVolumeName + "=" + req.Name,
+		envVolumePath + "=" + req.Path,
+		envParameters + "=" + string(params),
+	})
+	return err
+}
+
+// run executes the plugin for the operation and returns its stdout.
+func (p *HostVolumePluginExternal) run(ctx context.Context, op string, env []string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, p.Executable, op)
+	cmd.Env = append(os.Environ(),
+		envOperation+"="+op,
+		envPluginDir+"="+p.PluginDir,
+		envVolumesDir+"="+p.VolumesDir,
+	)
+	cmd.Env = append(cmd.Env, env...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	p.logger.Trace("running host volume plugin", "operation", op)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return nil, fmt.Errorf("plugin %q failed to %s: %s", p.ID, op, msg)
+			}
+		}
+		return nil, fmt.Errorf("plugin %q failed to %s: %w", p.ID, op, err)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ingress
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// resolveTTL is how long the instances of a service are cached before
+	// they are resolved again.
+	resolveTTL = 5 * time.Second
+
+	// failedCooldown is how long an instance that couldn't be reached is
+	// skipped for.
+	failedCooldown = 10 * time.Second
+)
+
+// ErrNoBackends is returned when a service has no instance traffic can be
+// routed to.
+var ErrNoBackends = errors.New("no healthy instances")
+
+// ResolveFunc returns the registrations of the healthy instances of a Nomad
+// service.
+type ResolveFunc func(service string) ([]*structs.ServiceRegistration, error)
+
+// backend is an instance of a service along with the share of the traffic it
+// receives.
+type backend struct {
+	addr   string
+	weight int
+}
+
+// service caches the instances of a service.
+type service struct {
+	backends []backend
+	total    int
+	expires  time.Time
+
+	// next is the position of the next pick within the total weight.
+	next int
+}
+
+// backends balances traffic across the instances of services, in proportion
+// of their weight. Instances whose checks are all failing have no weight and
+// only receive traffic if no other instance is available. Instances that
+// couldn't be reached are skipped for a while.
+type backends struct {
+	resolve ResolveFunc
+
+	lock     sync.Mutex
+	services map[string]*service
+	failed   map[string]time.Time
+}
+
+func newBackends(resolve ResolveFunc) *backends {
+	return &backends{
+		resolve:  resolve,
+		services: make(map[string]*service),
+		failed:   make(map[string]time.Time),
+	}
+}
+
+// next returns the address of the instance of the service the next
+// connection or request should be sent to.
+func (b *backends) next(name string) (string, error) {
+	s, err := b.lookup(name)
+	if err != nil {
+		return "", err
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := time.Now()
+	for i := 0; i < s.total; i++ {
+		addr := s.pick()
+		if until, ok := b.failed[addr]; ok {
+			if now.Before(until) {
+				continue
+			}
+			delete(b.failed, addr)
+		}
+		return addr, nil
+	}
+	return "", fmt.Errorf("service %q: %w", name, ErrNoBackends)
+}
+
+// markFailed skips the instance for the cooldown period.
+func (b *backends) markFailed(addr string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.failed[addr] = time.Now().Add(failedCooldown)
+}
+
+// lookup returns the cached instances of the service, resolving them again
+// once they expire.
+func (b *backends) lookup(name string) (*service, error) {
+	b.lock.Lock()
+	s, ok := b.services[name]
+	b.lock.Unlock()
+	if ok && time.Now().Before(s.expires) {
+		return s, nil
+	}
+
+	regs, err := b.resolve(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve service %q: %w", name, err)
+	}
+	s = newService(regs)
+	if len(s.backends) == 0 {
+		return nil, fmt.Errorf("service %q: %w", name, ErrNoBackends)
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.services[name] = s
+	return s, nil
+}
+
+func newService(regs []*structs.ServiceRegistration) *service {
+	s := &service{expires: time.Now().Add(resolveTTL)}
+	for _, reg := range regs {
+		weight := reg.Weight
+		if weight < 0 {
+			weight = 0
+		}
+		s.backends = append(s.backends, backend{
+			addr:   net.JoinHostPort(reg.Address, strconv.Itoa(reg.Port)),
+			weight: weight,
+		})
+		s.total += weight
+	}
+
+	// Without any passing instance, the traffic is spread evenly.
+	if s.total == 0 {
+		for i := range s.backends {
+			s.backends[i].weight = 1
+		}
+		s.total = len(s.backends)
+	}
+	return s
+}
+
+// pick returns the next instance in weighted round-robin order. It must be
+// called with the lock of the backends held.
+func (s *service) pick() string {
+	pos := s.next
+	s.next = (s.next + 1) % s.total
+	for _, b := range s.backends {
+		if pos < b.weight {
+			return b.addr
+		}
+		pos -= b.weight
+	}
+	return s.backends[len(s.backends)-1].addr
+}
@@ -0,0 +1,269 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// dialTimeout is how long the proxy waits to connect to an instance
+	// before trying another one.
+	dialTimeout = 5 * time.Second
+
+	// dialAttempts is the number of instances a TCP connection is tried
+	// against before it is closed.
+	dialAttempts = 3
+)
+
+// Proxy routes the traffic accepted by the listeners of an ingress block to
+// the instances of Nomad services.
+type Proxy struct {
+	logger   hclog.Logger
+	backends *backends
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	listeners []net.Listener
+	servers   []*http.Server
+	wg        sync.WaitGroup
+}
+
+// NewProxy returns a proxy resolving the instances of services with the
+// resolve function.
+func NewProxy(logger hclog.Logger, resolve ResolveFunc) *Proxy {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Proxy{
+		logger:   logger.Named("ingress"),
+		backends: newBackends(resolve),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start binds the listeners of the ingress block and starts serving them.
+// The addresses of the listeners are indexed by port label. If any listener
+// fails to bind, the listeners already started are stopped.
+func (p *Proxy) Start(ingress *structs.Ingress, addrs map[string]string) error {
+	for _, l := range ingress.Listeners {
+		addr, ok := addrs[l.Port]
+		if !ok {
+			p.Stop()
+			return fmt.Errorf("no address allocated for port %q", l.Port)
+		}
+
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			p.Stop()
+			return fmt.Errorf("failed to bind ingress listener on %s: %w", addr, err)
+		}
+		p.listeners = append(p.listeners, ln)
+
+		switch l.Protocol {
+		case structs.IngressProtocolHTTP:
+			p.serveHTTP(ln, l)
+		default:
+			p.serveTCP(ln, l.Service)
+		}
+		p.logger.Debug("started ingress listener", "address", addr, "protocol", l.Protocol)
+	}
+	return nil
+}
+
+// Stop closes the listeners and the connections being proxied, and waits for
+// them to be closed.
+func (p *Proxy) Stop() {
+	p.cancel()
+	for _, srv := range p.servers {
+		_ = srv.Close()
+	}
+	for _, ln := range p.listeners {
+		_ = ln.Close()
+	}
+	p.wg.Wait()
+}
+
+// serveTCP forwards the connections accepted by the listener to the
+// instances of the service.
+func (p *Proxy) serveTCP(ln net.Listener, service string) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				if p.ctx.Err() == nil {
+					p.logger.Error("failed to accept connection", "error", err)
+				}
+				return
+			}
+
+			p.wg.Add(1)
+			go func() {
+				defer p.wg.Done()
+				p.forward(conn, service)
+			}()
+		}
+	}()
+}
+
+// forward copies the data of the connection to and from an instance of the
+// service until either side closes it.
+func (p *Proxy) forward(conn net.Conn, service string) {
+	defer conn.Close()
+
+	upstream, err := p.dial(service)
+	if err != nil {
+		p.logger.Warn("failed to connect to service", "service", service, "error", err)
+		return
+	}
+	defer upstream.Close()
+
+	// Close both connections when the proxy stops, unblocking the copies.
+	ctx, cancel := context.WithCancel(p.ctx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+		upstream.Close()
+	}()
+
+	done := make(chan struct{}, 2)
+	copyConn := func(dst, src net.Conn) {
+		_, _ = io.Copy(dst, src)
+		if tcp, ok := dst.(*net.TCPConn); ok {
+			_ = tcp.CloseWrite()
+		}
+		done <- struct{}{}
+	}
+	go copyConn(upstream, conn)
+	go copyConn(conn, upstream)
+
+	<-done
+	<-done
+}
+
+// dial connects to an instance of the service, trying other instances if the
+// first ones can't be reached.
+func (p *Proxy) dial(service string) (net.Conn, error) {
+	var err error
+	for i := 0; i < dialAttempts; i++ {
+		var addr string
+		addr, err = p.backends.next(service)
+		if err != nil {
+			return nil, err
+		}
+
+		dialer := net.Dialer{Timeout: dialTimeout}
+		var conn net.Conn
+		conn, err = dialer.DialContext(p.ctx, "tcp", addr)
+		if err == nil {
+			return conn, nil
+		}
+		p.backends.markFailed(addr)
+	}
+	return nil, err
+}
+
+// serveHTTP routes the requests received by the listener to the services of
+// its routes.
+func (p *Proxy) serveHTTP(ln net.Listener, l *structs.IngressListener) {
+	router := &router{proxy: p, listener: l}
+	srv := &http.Server{
+		Handler:           router,
+		ReadHeaderTimeout: 30 * time.Second,
+		ErrorLog:          p.logger.StandardLogger(&hclog.StandardLoggerOptions{InferLevels: true}),
+	}
+	p.servers = append(p.servers, srv)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) && p.ctx.Err() == nil {
+			p.logger.Error("failed to serve ingress listener", "error", err)
+		}
+	}()
+}
+
+// router is the handler of an HTTP ingress listener.
+type router struct {
+	proxy    *Proxy
+	listener *structs.IngressListener
+}
+
+// route returns the service the request should be sent to. Routes matching
+// both the host and the path of the request take precedence over routes only
+// matching one of them, and longer path prefixes take precedence over shorter
+// ones.
+func (r *router) route(req *http.Request) string {
+	host := req.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	service, best := r.listener.Service, -1
+	for _, route := range r.listener.Routes {
+		if route.Host != "" && !strings.EqualFold(route.Host, host) {
+			continue
+		}
+		if !strings.HasPrefix(req.URL.Path, route.PathPrefix) {
+			continue
+		}
+
+		score := len(route.PathPrefix)
+		if route.Host != "" {
+			score += 1 << 16
+		}
+		if score > best {
+			service, best = route.Service, score
+		}
+	}
+	return service
+}
+
+func (r *router) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	service := r.route(req)
+	if service == "" {
+		http.Error(resp, "no route", http.StatusNotFound)
+		return
+	}
+
+	addr, err := r.proxy.backends.next(service)
+	if err != nil {
+		r.proxy.logger.Warn("failed to route request", "service", service, "error", err)
+		http.Error(resp, "no healthy upstream", http.StatusServiceUnavailable)
+		return
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Director: func(out *http.Request) {
+			out.URL.Scheme = "http"
+			out.URL.Host = addr
+			if _, ok := out.Header["User-Agent"]; !ok {
+				// Don't let the transport set its default user agent.
+				out.Header.Set("User-Agent", "")
+			}
+		},
+		ErrorHandler: func(resp http.ResponseWriter, req *http.Request, err error) {
+			if req.Context().Err() == nil {
+				r.proxy.backends.markFailed(addr)
+			}
+			r.proxy.logger.Warn("failed to proxy request", "service", service, "address", addr, "error", err)
+			resp.WriteHeader(http.StatusBadGateway)
+		},
+	}
+	proxy.ServeHTTP(resp, req)
+}
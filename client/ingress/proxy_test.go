@@ -0,0 +1,208 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package ingress
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// testRegistration returns the registration of a service instance listening
+// on the address.
+func testRegistration(t *testing.T, service, addr string, weight int) *structs.ServiceRegistration {
+	host, portStr, err := net.SplitHostPort(addr)
+	must.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	must.NoError(t, err)
+	return &structs.ServiceRegistration{
+		ServiceName: service,
+		Address:     host,
+		Port:        port,
+		Weight:      weight,
+	}
+}
+
+// testResolver resolves the services from a static set of registrations.
+func testResolver(regs ...*structs.ServiceRegistration) ResolveFunc {
+	return func(service string) ([]*structs.ServiceRegistration, error) {
+		var out []*structs.ServiceRegistration
+		for _, reg := range regs {
+			if reg.ServiceName == service {
+				out = append(out, reg)
+			}
+		}
+		return out, nil
+	}
+}
+
+// freeAddr returns a local address nothing listens on.
+func freeAddr(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	addr := ln.Addr().String()
+	must.NoError(t, ln.Close())
+	return addr
+}
+
+func TestProxy_TCP(t *testing.T) {
+	ci.Parallel(t)
+
+	// The echo server prefixes the lines it receives.
+	echo, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	defer echo.Close()
+	go func() {
+		for {
+			conn, err := echo.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					fmt.Fprintf(conn, "echo: %s", line)
+				}
+			}()
+		}
+	}()
+
+	// The unreachable instance is skipped.
+	p := NewProxy(testlog.HCLogger(t), testResolver(
+		testRegistration(t, "db", freeAddr(t), 1),
+		testRegistration(t, "db", echo.Addr().String(), 1),
+	))
+	defer p.Stop()
+
+	addr := freeAddr(t)
+	must.NoError(t, p.Start(&structs.Ingress{
+		Listeners: []*structs.IngressListener{{
+			Port:     "db",
+			Protocol: structs.IngressProtocolTCP,
+			Service:  "db",
+		}},
+	}, map[string]string{"db": addr}))
+
+	for i := 0; i < 3; i++ {
+		conn, err := net.Dial("tcp", addr)
+		must.NoError(t, err)
+		fmt.Fprintf(conn, "hello %d\n", i)
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		must.NoError(t, err)
+		must.Eq(t, fmt.Sprintf("echo: hello %d\n", i), line)
+		conn.Close()
+	}
+}
+
+func TestProxy_HTTP(t *testing.T) {
+	ci.Parallel(t)
+
+	backend := func(name string) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, "%s %s", name, r.URL.Path)
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	web, api, admin := backend("web"), backend("api"), backend("admin")
+
+	p := NewProxy(testlog.HCLogger(t), testResolver(
+		testRegistration(t, "web", web.Listener.Addr().String(), 1),
+		testRegistration(t, "api", api.Listener.Addr().String(), 1),
+		testRegistration(t, "admin", admin.Listener.Addr().String(), 1),
+	))
+	defer p.Stop()
+
+	addr := freeAddr(t)
+	must.NoError(t, p.Start(&structs.Ingress{
+		Listeners: []*structs.IngressListener{{
+			Port:     "http",
+			Protocol: structs.IngressProtocolHTTP,
+			Service:  "web",
+			Routes: []*structs.IngressRoute{
+				{PathPrefix: "/api", Service: "api"},
+				{PathPrefix: "/api/v2", Service: "missing"},
+				{Host: "admin.example.com", Service: "admin"},
+			},
+		}},
+	}, map[string]string{"http": addr}))
+
+	cases := []struct {
+		host   string
+		path   string
+		code   int
+		expect string
+	}{
+		{path: "/", code: http.StatusOK, expect: "web /"},
+		{path: "/api/jobs", code: http.StatusOK, expect: "api /api/jobs"},
+		{host: "admin.example.com", path: "/api", code: http.StatusOK, expect: "admin /api"},
+		{path: "/api/v2/jobs", code: http.StatusServiceUnavailable},
+	}
+	for _, tc := range cases {
+		req, err := http.NewRequest(http.MethodGet, "http://"+addr+tc.path, nil)
+		must.NoError(t, err)
+		if tc.host != "" {
+			req.Host = tc.host
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		must.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		must.NoError(t, err)
+
+		must.Eq(t, tc.code, resp.StatusCode, must.Sprintf("path %s", tc.path))
+		if tc.expect != "" {
+			must.Eq(t, tc.expect, string(body))
+		}
+	}
+}
+
+func TestBackends_Weights(t *testing.T) {
+	ci.Parallel(t)
+
+	b := newBackends(testResolver(
+		&structs.ServiceRegistration{ServiceName: "web", Address: "10.0.0.1", Port: 80, Weight: 3},
+		&structs.ServiceRegistration{ServiceName: "web", Address: "10.0.0.2", Port: 80, Weight: 1},
+		&structs.ServiceRegistration{ServiceName: "web", Address: "10.0.0.3", Port: 80, Weight: 0},
+	))
+
+	counts := make(map[string]int)
+	for i := 0; i < 8; i++ {
+		addr, err := b.next("web")
+		must.NoError(t, err)
+		counts[addr]++
+	}
+	must.Eq(t, map[string]int{"10.0.0.1:80": 6, "10.0.0.2:80": 2}, counts)
+
+	// Failed instances are skipped until their cooldown expires.
+	b.markFailed("10.0.0.1:80")
+	for i := 0; i < 4; i++ {
+		addr, err := b.next("web")
+		must.NoError(t, err)
+		must.Eq(t, "10.0.0.2:80", addr)
+	}
+
+	b.markFailed("10.0.0.2:80")
+	_, err := b.next("web")
+	must.ErrorIs(t, err, ErrNoBackends)
+
+	_, err = b.next("missing")
+	must.ErrorIs(t, err, ErrNoBackends)
+}
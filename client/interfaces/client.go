@@ -40,8 +40,18 @@ type EnvReplacer interface {
 
 // ArtifactGetter is an interface satisfied by the getter package.
 type ArtifactGetter interface {
-	// Get artifact and put it in the task directory.
-	Get(EnvReplacer, *structs.TaskArtifact) error
+	// Get artifact and put it in the task directory. The identity token is
+	// the signed workload identity named by the artifact, or "" if the
+	// artifact does not name one.
+	Get(env EnvReplacer, artifact *structs.TaskArtifact, identityToken string) error
+}
+
+// ArtifactPrefetcher is an interface satisfied by the getter package.
+type ArtifactPrefetcher interface {
+	// Prefetch downloads the artifact into the client's artifact cache
+	// ahead of the placement of its task. It returns false if the artifact
+	// can't be cached, in which case it isn't downloaded.
+	Prefetch(artifact *structs.TaskArtifact) (bool, error)
 }
 
 // ProcessWranglers is an interface satisfied by the proclib package.
@@ -14,3 +14,13 @@ func LinuxResourcesPath(string, string, bool) string {
 func MaybeDisableMemorySwappiness() *uint64 {
 	return nil
 }
+
+// OOMKills does nothing on non-Linux systems
+func OOMKills(string, string, bool) (uint64, error) {
+	return 0, nil
+}
+
+// UpdateLimits does nothing on non-Linux systems
+func UpdateLimits(string, string, bool, int64, int64, uint64) error {
+	return nil
+}
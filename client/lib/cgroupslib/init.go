@@ -10,8 +10,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/lib/idset"
+	"github.com/hashicorp/nomad/client/lib/numalib/hw"
 )
 
 const (
@@ -23,9 +26,10 @@ const (
 )
 
 // Init will initialize the cgroup tree that the Nomad client will use for
-// isolating resources of tasks. cores is the cpuset granted for use by Nomad.
-func Init(log hclog.Logger, cores string) error {
-	log.Info("initializing nomad cgroups", "cores", cores)
+// isolating resources of tasks. cores is the cpuset granted for use by Nomad,
+// and exclusive is the cpuset of the cores that are never shared.
+func Init(log hclog.Logger, cores, exclusive string) error {
+	log.Info("initializing nomad cgroups", "cores", cores, "exclusive", exclusive)
 
 	switch GetMode() {
 	case CG1:
@@ -184,9 +188,51 @@ func Init(log hclog.Logger, cores string) error {
 		log.Debug("partition member nomad.slice/reserve cgroup initialized")
 	}
 
+	// the exclusive cores must be removed from the share partition before
+	// any task is started
+	if strings.TrimSpace(exclusive) != "" {
+		if err := initExclusive(cores, exclusive); err != nil {
+			return fmt.Errorf("failed to write exclusive cores to cpuset partitions: %w", err)
+		}
+		log.Debug("exclusive cores removed from share partition", "exclusive", exclusive)
+	}
+
 	return nil
 }
 
+// initExclusive writes the cpuset of the share and reserve partitions so that
+// the exclusive cores are only part of the reserve partition. Cores reserved
+// by tasks that kept running while the client was restarted are kept in the
+// reserve partition.
+func initExclusive(cores, exclusive string) error {
+	var sharePath, reservePath []string
+	switch GetMode() {
+	case CG1:
+		sharePath = []string{"cpuset", NomadCgroupParent, SharePartition(), cpusetFile}
+		reservePath = []string{"cpuset", NomadCgroupParent, ReservePartition(), cpusetFile}
+	case CG2:
+		sharePath = []string{NomadCgroupParent, SharePartition(), cpusetFile}
+		reservePath = []string{NomadCgroupParent, ReservePartition(), cpusetFile}
+	default:
+		return nil
+	}
+
+	usable := idset.Parse[hw.CoreID](cores)
+	reserve := idset.Parse[hw.CoreID](exclusive)
+	if b, err := os.ReadFile(filepathCG(reservePath...)); err == nil {
+		reserve.InsertSet(idset.Parse[hw.CoreID](string(b)))
+	}
+
+	// a partition cannot use cores outside of the nomad cgroup cpuset
+	reserve = usable.Difference(usable.Difference(reserve))
+	share := usable.Difference(reserve)
+
+	if err := writeCG(share.String(), sharePath...); err != nil {
+		return err
+	}
+	return writeCG(reserve.String(), reservePath...)
+}
+
 // detectMemsCG1 will determine the cpuset.mems value to use for
 // Nomad managed cgroups.
 //
@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build linux
+
+package cgroupslib
+
+import (
+	"strconv"
+)
+
+// UpdateLimits writes the memory and cpu limits of the cgroup of the given
+// task. It is used to resize a running task in-place. The memory hard and soft
+// limits are in bytes, and a soft limit of 0 removes the soft limit.
+func UpdateLimits(allocID, task string, cores bool, memHard, memSoft int64, cpuShares uint64) error {
+	switch GetMode() {
+	case CG1:
+		mem := OpenPath(PathCG1(allocID, task, "memory"))
+		if err := mem.Write("memory.limit_in_bytes", strconv.FormatInt(memHard, 10)); err != nil {
+			return err
+		}
+		soft := "-1"
+		if memSoft > 0 {
+			soft = strconv.FormatInt(memSoft, 10)
+		}
+		if err := mem.Write("memory.soft_limit_in_bytes", soft); err != nil {
+			return err
+		}
+		cpu := OpenPath(PathCG1(allocID, task, "cpu"))
+		return cpu.Write("cpu.shares", strconv.FormatUint(cpuShares, 10))
+	case CG2:
+		ed := OpenPath(pathCG2(allocID, task, cores))
+		if err := ed.Write("memory.max", strconv.FormatInt(memHard, 10)); err != nil {
+			return err
+		}
+		if err := ed.Write("memory.low", strconv.FormatInt(memSoft, 10)); err != nil {
+			return err
+		}
+		return ed.Write("cpu.weight", strconv.FormatUint(cpuWeight(cpuShares), 10))
+	default:
+		return nil
+	}
+}
+
+// cpuWeight converts cgroups v1 cpu shares in the range [2, 262144] to a
+// cgroups v2 cpu weight in the range [1, 10000], the same way as runc does
+// when creating the cgroup.
+func cpuWeight(shares uint64) uint64 {
+	if shares == 0 {
+		return 0
+	}
+	return 1 + ((shares-2)*9999)/262142
+}
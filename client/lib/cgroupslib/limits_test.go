@@ -0,0 +1,19 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build linux
+
+package cgroupslib
+
+import (
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func Test_cpuWeight(t *testing.T) {
+	must.Eq(t, 0, cpuWeight(0))
+	must.Eq(t, 1, cpuWeight(2))
+	must.Eq(t, 39, cpuWeight(1024))
+	must.Eq(t, 10000, cpuWeight(262144))
+}
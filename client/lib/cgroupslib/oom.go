@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build linux
+
+package cgroupslib
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+)
+
+// OOMKills returns the number of processes in the cgroup of the given task
+// that have been killed by the kernel OOM killer. The counter is cumulative
+// for the lifetime of the cgroup.
+//
+// In cgroups v1 this is read from memory.oom_control, which only reports the
+// counter on kernels 4.13 and newer.
+func OOMKills(allocID, task string, cores bool) (uint64, error) {
+	var ed Interface
+	var filename string
+	switch GetMode() {
+	case CG1:
+		ed = OpenPath(PathCG1(allocID, task, "memory"))
+		filename = "memory.oom_control"
+	case CG2:
+		ed = OpenPath(pathCG2(allocID, task, cores))
+		filename = "memory.events"
+	default:
+		return 0, nil
+	}
+
+	content, err := ed.Read(filename)
+	if err != nil {
+		return 0, err
+	}
+	return parseOOMKills(content), nil
+}
+
+// parseOOMKills returns the value of the oom_kill key of a flat keyed cgroup
+// interface file, or 0 if the key is not present.
+func parseOOMKills(content string) uint64 {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[0] != "oom_kill" {
+			continue
+		}
+		if n, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			return n
+		}
+	}
+	return 0
+}
@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build linux
+
+package cgroupslib
+
+import (
+	"testing"
+
+	"github.com/shoenig/test/must"
+)
+
+func Test_parseOOMKills(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		exp     uint64
+	}{
+		{
+			name:    "cgroups v2 memory.events",
+			content: "low 0\nhigh 0\nmax 12\noom 3\noom_kill 2\noom_group_kill 0",
+			exp:     2,
+		},
+		{
+			name:    "cgroups v1 memory.oom_control",
+			content: "oom_kill_disable 0\nunder_oom 0\noom_kill 5",
+			exp:     5,
+		},
+		{
+			name:    "old kernel without counter",
+			content: "oom_kill_disable 0\nunder_oom 0",
+			exp:     0,
+		},
+		{
+			name:    "empty",
+			content: "",
+			exp:     0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			must.Eq(t, tc.exp, parseOOMKills(tc.content))
+		})
+	}
+}
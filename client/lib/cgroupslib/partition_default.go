@@ -11,6 +11,6 @@ import (
 )
 
 // GetPartition creates a no-op Partition that does not do anything.
-func GetPartition(*idset.Set[hw.CoreID]) Partition {
+func GetPartition(_, _ *idset.Set[hw.CoreID]) Partition {
 	return NoopPartition()
 }
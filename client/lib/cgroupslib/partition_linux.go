@@ -16,14 +16,16 @@ import (
 
 // GetPartition creates a Partition suitable for managing cores on this
 // Linux system.
-func GetPartition(cores *idset.Set[hw.CoreID]) Partition {
-	return NewPartition(cores)
+func GetPartition(cores, exclusive *idset.Set[hw.CoreID]) Partition {
+	return NewPartition(cores, exclusive)
 }
 
 // NewPartition creates a cpuset partition manager for managing the books
 // when allocations are created and destroyed. The initial set of cores is
-// the usable set of cores by Nomad.
-func NewPartition(cores *idset.Set[hw.CoreID]) Partition {
+// the usable set of cores by Nomad that are shared, and the exclusive set of
+// cores is the pool of cores that are never shared. Exclusive cores always
+// belong to the reserve partition, even when no task is making use of them.
+func NewPartition(cores, exclusive *idset.Set[hw.CoreID]) Partition {
 	var (
 		sharePath   string
 		reservePath string
@@ -43,8 +45,9 @@ func NewPartition(cores *idset.Set[hw.CoreID]) Partition {
 	return &partition{
 		sharePath:   sharePath,
 		reservePath: reservePath,
-		share:       cores.Copy(),
-		reserve:     idset.Empty[hw.CoreID](),
+		share:       cores.Difference(exclusive),
+		reserve:     exclusive.Copy(),
+		exclusive:   exclusive.Copy(),
 	}
 }
 
@@ -52,9 +55,10 @@ type partition struct {
 	sharePath   string
 	reservePath string
 
-	lock    sync.Mutex
-	share   *idset.Set[hw.CoreID]
-	reserve *idset.Set[hw.CoreID]
+	lock      sync.Mutex
+	share     *idset.Set[hw.CoreID]
+	reserve   *idset.Set[hw.CoreID]
+	exclusive *idset.Set[hw.CoreID]
 }
 
 func (p *partition) Restore(cores *idset.Set[hw.CoreID]) {
@@ -79,8 +83,10 @@ func (p *partition) Release(cores *idset.Set[hw.CoreID]) error {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 
-	p.reserve.RemoveSet(cores)
-	p.share.InsertSet(cores)
+	// exclusive cores are never returned to the share partition
+	released := cores.Difference(p.exclusive)
+	p.reserve.RemoveSet(released)
+	p.share.InsertSet(released)
 
 	return p.write()
 }
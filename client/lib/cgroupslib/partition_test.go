@@ -24,6 +24,7 @@ func testPartition(t *testing.T) *partition {
 		reservePath: reserveFile,
 		share:       idset.From[hw.CoreID]([]hw.CoreID{10, 11, 12, 13, 14, 15, 16, 17, 18, 19}),
 		reserve:     idset.Empty[hw.CoreID](),
+		exclusive:   idset.Empty[hw.CoreID](),
 	}
 }
 
@@ -94,3 +95,25 @@ func TestPartition_Release(t *testing.T) {
 	must.FileContains(t, p.sharePath, "10-19")
 	must.FileContains(t, p.reservePath, "")
 }
+
+func TestPartition_Exclusive(t *testing.T) {
+	p := testPartition(t)
+	p.exclusive = coreset(18, 19)
+	p.share.RemoveSet(p.exclusive)
+	p.reserve.InsertSet(p.exclusive)
+
+	// reserve shared and exclusive cores
+	p.Reserve(coreset(10, 11))
+	p.Reserve(coreset(19))
+	must.FileContains(t, p.sharePath, "12-17")
+	must.FileContains(t, p.reservePath, "10-11,18-19")
+
+	// exclusive cores are not returned to the share partition
+	p.Release(coreset(19))
+	must.FileContains(t, p.sharePath, "12-17")
+	must.FileContains(t, p.reservePath, "10-11,18-19")
+
+	p.Release(coreset(10, 11))
+	must.FileContains(t, p.sharePath, "10-17")
+	must.FileContains(t, p.reservePath, "18-19")
+}
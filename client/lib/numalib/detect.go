@@ -57,6 +57,18 @@ type ConfigScanner struct {
 	// Used to withhold an amount of MHz of CPU bandwidth from being used by
 	// Nomad for scheduling.
 	ReservedCompute hw.MHz
+
+	// ExclusiveCores comes from client.exclusive_cores.
+	//
+	// Used to set aside a pool of cores that are never shared by tasks, and
+	// are only reserved by tasks asking for exclusive cores.
+	ExclusiveCores *idset.Set[hw.CoreID]
+
+	// ExclusiveIsolatedCores comes from client.exclusive_isolated_cores.
+	//
+	// Used to add the cores isolated by the kernel (isolcpus) to the pool of
+	// exclusive cores.
+	ExclusiveIsolatedCores bool
 }
 
 func (cs *ConfigScanner) ScanSystem(top *Topology) {
@@ -76,6 +88,16 @@ func (cs *ConfigScanner) ScanSystem(top *Topology) {
 		}
 	}
 
+	// set aside the pool of exclusive cores
+	for i := 0; i < len(top.Cores); i++ {
+		switch {
+		case cs.ExclusiveCores != nil && cs.ExclusiveCores.Contains(top.Cores[i].ID):
+			top.Cores[i].Exclusive = true
+		case cs.ExclusiveIsolatedCores && top.Cores[i].Isolated:
+			top.Cores[i].Exclusive = true
+		}
+	}
+
 	// set total compute from client configuration
 	top.OverrideTotalCompute = cs.TotalCompute
 
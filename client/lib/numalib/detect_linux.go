@@ -31,6 +31,7 @@ const (
 	sysRoot        = "/sys/devices/system"
 	nodeOnline     = sysRoot + "/node/online"
 	cpuOnline      = sysRoot + "/cpu/online"
+	cpuIsolated    = sysRoot + "/cpu/isolated"
 	distanceFile   = sysRoot + "/node/node%d/distance"
 	cpulistFile    = sysRoot + "/node/node%d/cpulist"
 	cpuMaxFile     = sysRoot + "/cpu/cpu%d/cpufreq/cpuinfo_max_freq"
@@ -58,6 +59,9 @@ func (s *Sysfs) ScanSystem(top *Topology) {
 
 	// detect core performance data
 	s.discoverCores(top, os.ReadFile)
+
+	// detect cores isolated from the kernel scheduler
+	s.discoverIsolated(top, os.ReadFile)
 }
 
 func (*Sysfs) available() bool {
@@ -145,6 +149,18 @@ func (*Sysfs) discoverCores(st *Topology, readerFunc pathReaderFn) {
 	}
 }
 
+func (*Sysfs) discoverIsolated(st *Topology, readerFunc pathReaderFn) {
+	isolated, err := getIDSet[hw.CoreID](cpuIsolated, readerFunc)
+	if err != nil {
+		return
+	}
+	for i := 0; i < len(st.Cores); i++ {
+		if isolated.Contains(st.Cores[i].ID) {
+			st.Cores[i].Isolated = true
+		}
+	}
+}
+
 func getIDSet[T idset.ID](path string, readerFunc pathReaderFn, args ...any) (*idset.Set[T], error) {
 	path = fmt.Sprintf(path, args...)
 	s, err := readerFunc(path)
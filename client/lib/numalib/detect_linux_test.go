@@ -203,3 +203,26 @@ func TestSysfs_discoverCores(t *testing.T) {
 		})
 	}
 }
+
+func TestSysfs_discoverIsolated(t *testing.T) {
+	st := NewTopology(idset.Empty[hw.NodeID](), SLIT{}, []Core{{ID: 0}, {ID: 1}, {ID: 2}, {ID: 3}})
+
+	sy := &Sysfs{}
+	sy.discoverIsolated(st, func(path string) ([]byte, error) {
+		must.Eq(t, "/sys/devices/system/cpu/isolated", path)
+		return []byte("2-3\n"), nil
+	})
+	must.Eq(t, []bool{false, false, true, true}, []bool{
+		st.Cores[0].Isolated, st.Cores[1].Isolated, st.Cores[2].Isolated, st.Cores[3].Isolated,
+	})
+
+	// isolated cores are exclusive if configured so
+	cs := &ConfigScanner{
+		ReservedCores:          idset.From[hw.CoreID]([]hw.CoreID{0}),
+		ExclusiveCores:         idset.From[hw.CoreID]([]hw.CoreID{1}),
+		ExclusiveIsolatedCores: true,
+	}
+	cs.ScanSystem(st)
+	must.Eq(t, idset.From[hw.CoreID]([]hw.CoreID{1, 2, 3}), st.ExclusiveCores())
+	must.True(t, st.SharedCores().Empty())
+}
@@ -77,6 +77,8 @@ type Core struct {
 	ID         hw.CoreID
 	Grade      CoreGrade
 	Disable    bool   // indicates whether Nomad must not use this core
+	Isolated   bool   // indicates whether the kernel isolates this core (isolcpus)
+	Exclusive  bool   // indicates whether this core is in the exclusive pool
 	BaseSpeed  hw.MHz // cpuinfo_base_freq (primary choice)
 	MaxSpeed   hw.MHz // cpuinfo_max_freq (second choice)
 	GuessSpeed hw.MHz // best effort (fallback)
@@ -263,6 +265,32 @@ func (st *Topology) UsableCores() *idset.Set[hw.CoreID] {
 	return result
 }
 
+// SharedCores returns the usable cores that are not in the exclusive pool. Tasks
+// making use of the 'cpu' resource share these cores, and tasks reserving cores
+// without asking for exclusive cores are given some of these cores.
+func (st *Topology) SharedCores() *idset.Set[hw.CoreID] {
+	result := idset.Empty[hw.CoreID]()
+	for _, cpu := range st.Cores {
+		if !cpu.Disable && !cpu.Exclusive {
+			result.Insert(cpu.ID)
+		}
+	}
+	return result
+}
+
+// ExclusiveCores returns the usable cores in the exclusive pool
+// (client.exclusive_cores). These cores are never shared, and are only given
+// to tasks asking for exclusive cores.
+func (st *Topology) ExclusiveCores() *idset.Set[hw.CoreID] {
+	result := idset.Empty[hw.CoreID]()
+	for _, cpu := range st.Cores {
+		if !cpu.Disable && cpu.Exclusive {
+			result.Insert(cpu.ID)
+		}
+	}
+	return result
+}
+
 // CoreSpeeds returns the frequency in MHz of the performance and efficiency
 // core types. If the CPU does not have effiency cores that value will be zero.
 func (st *Topology) CoreSpeeds() (hw.MHz, hw.MHz) {
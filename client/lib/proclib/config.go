@@ -18,4 +18,8 @@ type Configs struct {
 	// UsableCores is the actual set of cpu cores Nomad is able and
 	// allowed to use.
 	UsableCores *idset.Set[hw.CoreID]
+
+	// ExclusiveCores is the set of usable cpu cores that are never shared,
+	// and are only reserved by tasks asking for exclusive cores.
+	ExclusiveCores *idset.Set[hw.CoreID]
 }
@@ -25,7 +25,7 @@ type LinuxWranglerCG1 struct {
 
 func newCG1(c *Configs) (create, error) {
 	logger := c.Logger.Named("cg1")
-	err := cgroupslib.Init(logger, c.UsableCores.String())
+	err := cgroupslib.Init(logger, c.UsableCores.String(), c.ExclusiveCores.String())
 	if err != nil {
 		return nil, err
 	}
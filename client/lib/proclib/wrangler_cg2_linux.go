@@ -22,7 +22,7 @@ type LinuxWranglerCG2 struct {
 
 func newCG2(c *Configs) (create, error) {
 	logger := c.Logger.Named("cg2")
-	err := cgroupslib.Init(logger, c.UsableCores.String())
+	err := cgroupslib.Init(logger, c.UsableCores.String(), c.ExclusiveCores.String())
 	if err != nil {
 		return nil, err
 	}
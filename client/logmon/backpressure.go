@@ -0,0 +1,296 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package logmon
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// backpressureQueueSize is the number of writes from the task that may be
+	// waiting on the log files before the drop or spill policy is applied.
+	// Writes are at most the size of the io.Copy buffer (32KiB).
+	backpressureQueueSize = 256
+
+	// backpressureWatchdogInterval is how often the watchdog checks on the
+	// log writer and reports dropped and spilled logs.
+	backpressureWatchdogInterval = 10 * time.Second
+
+	// backpressureStallTimeout is how long a write to the log files may take
+	// before the watchdog reports the log writer as stalled.
+	backpressureStallTimeout = 10 * time.Second
+
+	// backpressureMinRetryBackoff and backpressureMaxRetryBackoff bound the
+	// wait between retries of failed writes for the block policy.
+	backpressureMinRetryBackoff = 100 * time.Millisecond
+	backpressureMaxRetryBackoff = 5 * time.Second
+)
+
+var errBackpressureWriterClosed = errors.New("log writer closed")
+
+// backpressureStats counts the logs that weren't written to the task's log
+// files. Lines are counted by newlines, so a line split across writes may be
+// counted once per write.
+type backpressureStats struct {
+	droppedLines uint64
+	droppedBytes uint64
+	spilledBytes uint64
+}
+
+// backpressureWriter sits between a task's output pipe and its log rotator,
+// and applies the task's backpressure policy when the log files can't be
+// written as fast as the task writes to the pipe:
+//
+//   - block: writes are passed straight through to the rotator and retried
+//     until they succeed, so a task writing faster than its logs can be
+//     written blocks on its stdout or stderr.
+//   - drop: writes are queued for the rotator, and dropped and counted when
+//     the queue is full or the rotator fails.
+//   - spill: as drop, except that logs are written to a secondary rotator
+//     before they're dropped.
+//
+// A watchdog reports stalled writes and dropped or spilled logs.
+type backpressureWriter struct {
+	policy  string
+	rotator io.WriteCloser
+	logger  hclog.Logger
+
+	// spill is the rotator for the spill policy, and is nil otherwise
+	spill     io.WriteCloser
+	spillLock sync.Mutex
+
+	// queue holds writes waiting on the rotator for the drop and spill
+	// policies, and is nil for the block policy
+	queue       chan []byte
+	queueClosed bool
+	queueLock   sync.Mutex
+
+	// pendingSince is the time in unix nanoseconds that the write currently
+	// waiting on the rotator started, or zero if there is none
+	pendingSince atomic.Int64
+
+	droppedLines atomic.Uint64
+	droppedBytes atomic.Uint64
+	spilledBytes atomic.Uint64
+
+	closeOnce sync.Once
+	doneCh    chan struct{}
+	drainedCh chan struct{}
+}
+
+// newBackpressureWriter returns a writer applying the backpressure policy to
+// writes to the rotator. The spill rotator is only used by the spill policy.
+func newBackpressureWriter(policy string, rotator, spill io.WriteCloser, logger hclog.Logger) *backpressureWriter {
+	w := &backpressureWriter{
+		policy:    policy,
+		rotator:   rotator,
+		logger:    logger.Named("backpressure"),
+		doneCh:    make(chan struct{}),
+		drainedCh: make(chan struct{}),
+	}
+
+	switch policy {
+	case structs.LogBackpressureSpill:
+		w.spill = spill
+		fallthrough
+	case structs.LogBackpressureDrop:
+		w.queue = make(chan []byte, backpressureQueueSize)
+		go w.drain()
+	default:
+		w.policy = structs.LogBackpressureBlock
+		close(w.drainedCh)
+	}
+
+	go w.watchdog()
+	return w
+}
+
+func (w *backpressureWriter) Write(p []byte) (int, error) {
+	if w.queue == nil {
+		return w.writeBlocking(p)
+	}
+
+	// The caller may reuse p once Write returns
+	buf := make([]byte, len(p))
+	copy(buf, p)
+
+	queued, err := w.enqueue(buf)
+	if err != nil {
+		return 0, err
+	}
+	if !queued {
+		w.overflow(buf)
+	}
+	return len(p), nil
+}
+
+// writeBlocking writes to the rotator, retrying failed writes until they
+// succeed or the writer is closed.
+func (w *backpressureWriter) writeBlocking(p []byte) (int, error) {
+	w.pendingSince.Store(time.Now().UnixNano())
+	defer w.pendingSince.Store(0)
+
+	backoff := backpressureMinRetryBackoff
+	written := 0
+	for {
+		n, err := w.rotator.Write(p[written:])
+		written += n
+		if err == nil {
+			return len(p), nil
+		}
+
+		w.logger.Warn("failed to write task logs, retrying", "error", err, "backoff", backoff)
+		select {
+		case <-time.After(backoff):
+		case <-w.doneCh:
+			return written, errBackpressureWriterClosed
+		}
+		backoff = min(backoff*2, backpressureMaxRetryBackoff)
+	}
+}
+
+// enqueue queues the write for the rotator without blocking, returning
+// false if the queue is full.
+func (w *backpressureWriter) enqueue(p []byte) (bool, error) {
+	w.queueLock.Lock()
+	defer w.queueLock.Unlock()
+
+	if w.queueClosed {
+		return false, errBackpressureWriterClosed
+	}
+
+	select {
+	case w.queue <- p:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// drain writes queued writes to the rotator until the queue is closed.
+func (w *backpressureWriter) drain() {
+	defer close(w.drainedCh)
+
+	for p := range w.queue {
+		w.pendingSince.Store(time.Now().UnixNano())
+		n, err := w.rotator.Write(p)
+		w.pendingSince.Store(0)
+
+		if err != nil {
+			w.logger.Debug("failed to write task logs", "error", err)
+			w.overflow(p[n:])
+		}
+	}
+}
+
+// overflow spills or drops logs that couldn't be written to the rotator.
+func (w *backpressureWriter) overflow(p []byte) {
+	if len(p) == 0 {
+		return
+	}
+
+	if w.spill != nil {
+		w.spillLock.Lock()
+		_, err := w.spill.Write(p)
+		w.spillLock.Unlock()
+		if err == nil {
+			w.spilledBytes.Add(uint64(len(p)))
+			return
+		}
+		w.logger.Debug("failed to spill task logs", "error", err)
+	}
+
+	w.droppedBytes.Add(uint64(len(p)))
+	w.droppedLines.Add(uint64(bytes.Count(p, []byte{'\n'})))
+}
+
+// stalled returns true if a write has been waiting on the rotator for longer
+// than the stall timeout.
+func (w *backpressureWriter) stalled(now time.Time) bool {
+	since := w.pendingSince.Load()
+	return since != 0 && now.Sub(time.Unix(0, since)) > backpressureStallTimeout
+}
+
+func (w *backpressureWriter) stats() backpressureStats {
+	return backpressureStats{
+		droppedLines: w.droppedLines.Load(),
+		droppedBytes: w.droppedBytes.Load(),
+		spilledBytes: w.spilledBytes.Load(),
+	}
+}
+
+// watchdog periodically reports stalled writes and the logs dropped or
+// spilled since its last report, until the writer is closed.
+func (w *backpressureWriter) watchdog() {
+	ticker := time.NewTicker(backpressureWatchdogInterval)
+	defer ticker.Stop()
+
+	var stalled bool
+	var last backpressureStats
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.doneCh:
+			return
+		}
+
+		if w.stalled(time.Now()) {
+			if !stalled {
+				w.logger.Warn("writing task logs is stalled", "policy", w.policy)
+			}
+			stalled = true
+		} else if stalled {
+			w.logger.Info("writing task logs resumed")
+			stalled = false
+		}
+
+		current := w.stats()
+		if dropped := current.droppedBytes - last.droppedBytes; dropped > 0 {
+			w.logger.Warn("dropped task logs",
+				"lines", current.droppedLines-last.droppedLines, "bytes", dropped,
+				"total_lines", current.droppedLines, "total_bytes", current.droppedBytes)
+		}
+		if spilled := current.spilledBytes - last.spilledBytes; spilled > 0 {
+			w.logger.Warn("spilled task logs",
+				"bytes", spilled, "total_bytes", current.spilledBytes)
+		}
+		last = current
+	}
+}
+
+// Close writes any queued logs and closes the rotators. It never returns an
+// error.
+func (w *backpressureWriter) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.doneCh)
+
+		if w.queue != nil {
+			w.queueLock.Lock()
+			w.queueClosed = true
+			close(w.queue)
+			w.queueLock.Unlock()
+		}
+		<-w.drainedCh
+
+		if stats := w.stats(); stats.droppedBytes > 0 || stats.spilledBytes > 0 {
+			w.logger.Info("task logs were not all written to the log files",
+				"dropped_lines", stats.droppedLines, "dropped_bytes", stats.droppedBytes,
+				"spilled_bytes", stats.spilledBytes)
+		}
+
+		w.rotator.Close()
+		if w.spill != nil {
+			w.spill.Close()
+		}
+	})
+	return nil
+}
@@ -0,0 +1,198 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package logmon
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// testRotator is an in-memory log rotator. Writes fail while failing is set
+// and block while blockCh is open.
+type testRotator struct {
+	lock    sync.Mutex
+	buf     bytes.Buffer
+	failing bool
+	fails   int
+	blockCh chan struct{}
+	closed  bool
+}
+
+func (r *testRotator) Write(p []byte) (int, error) {
+	r.lock.Lock()
+	blockCh := r.blockCh
+	r.lock.Unlock()
+	if blockCh != nil {
+		<-blockCh
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.failing || r.fails > 0 {
+		r.fails--
+		return 0, errors.New("disk full")
+	}
+	return r.buf.Write(p)
+}
+
+func (r *testRotator) setFailing(failing bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.failing = failing
+}
+
+func (r *testRotator) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.closed = true
+	return nil
+}
+
+func (r *testRotator) String() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.buf.String()
+}
+
+func TestBackpressureWriter_Block(t *testing.T) {
+	ci.Parallel(t)
+
+	rotator := &testRotator{fails: 2}
+	w := newBackpressureWriter(structs.LogBackpressureBlock, rotator, nil, testlog.HCLogger(t))
+
+	// Failed writes are retried until they succeed
+	n, err := w.Write([]byte("hello\n"))
+	must.NoError(t, err)
+	must.Eq(t, 6, n)
+	must.Eq(t, "hello\n", rotator.String())
+	must.Eq(t, backpressureStats{}, w.stats())
+
+	// Closing the writer interrupts the retries
+	rotator.setFailing(true)
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := w.Write([]byte("world\n"))
+		errCh <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	must.NoError(t, w.Close())
+	must.ErrorIs(t, <-errCh, errBackpressureWriterClosed)
+	must.True(t, rotator.closed)
+}
+
+func TestBackpressureWriter_Drop(t *testing.T) {
+	ci.Parallel(t)
+
+	rotator := &testRotator{blockCh: make(chan struct{})}
+	w := newBackpressureWriter(structs.LogBackpressureDrop, rotator, nil, testlog.HCLogger(t))
+
+	// Writes never block the task, and are dropped once the queue is full
+	line := []byte("log line\n")
+	total := backpressureQueueSize + 10
+	for i := 0; i < total; i++ {
+		n, err := w.Write(line)
+		must.NoError(t, err)
+		must.Eq(t, len(line), n)
+	}
+
+	close(rotator.blockCh)
+	must.NoError(t, w.Close())
+
+	stats := w.stats()
+	must.Positive(t, stats.droppedLines)
+	must.Eq(t, stats.droppedLines*uint64(len(line)), stats.droppedBytes)
+	must.Zero(t, stats.spilledBytes)
+
+	written := uint64(len(rotator.String()))
+	must.Eq(t, uint64(total*len(line)), written+stats.droppedBytes)
+
+	_, err := w.Write(line)
+	must.ErrorIs(t, err, errBackpressureWriterClosed)
+}
+
+func TestBackpressureWriter_Spill(t *testing.T) {
+	ci.Parallel(t)
+
+	rotator := &testRotator{failing: true}
+	spill := &testRotator{}
+	w := newBackpressureWriter(structs.LogBackpressureSpill, rotator, spill, testlog.HCLogger(t))
+
+	_, err := w.Write([]byte("one\n"))
+	must.NoError(t, err)
+	_, err = w.Write([]byte("two\n"))
+	must.NoError(t, err)
+	must.NoError(t, w.Close())
+
+	must.Eq(t, "", rotator.String())
+	must.Eq(t, "one\ntwo\n", spill.String())
+	must.Eq(t, backpressureStats{spilledBytes: 8}, w.stats())
+	must.True(t, spill.closed)
+
+	// Logs are dropped if they can't be spilled either
+	rotator = &testRotator{failing: true}
+	spill = &testRotator{failing: true}
+	w = newBackpressureWriter(structs.LogBackpressureSpill, rotator, spill, testlog.HCLogger(t))
+
+	_, err = w.Write([]byte("one\n"))
+	must.NoError(t, err)
+	must.NoError(t, w.Close())
+	must.Eq(t, backpressureStats{droppedLines: 1, droppedBytes: 4}, w.stats())
+}
+
+func TestBackpressureWriter_Stalled(t *testing.T) {
+	ci.Parallel(t)
+
+	w := newBackpressureWriter(structs.LogBackpressureBlock, &testRotator{}, nil, testlog.HCLogger(t))
+	defer w.Close()
+
+	now := time.Now()
+	must.False(t, w.stalled(now))
+
+	w.pendingSince.Store(now.Add(-time.Second).UnixNano())
+	must.False(t, w.stalled(now))
+
+	w.pendingSince.Store(now.Add(-2 * backpressureStallTimeout).UnixNano())
+	must.True(t, w.stalled(now))
+}
+
+func TestTaskLogger_Spill(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	spillDir := filepath.Join(t.TempDir(), "alloc")
+
+	cfg := &LogConfig{
+		LogDir:        dir,
+		StdoutLogFile: "stdout",
+		StdoutFifo:    filepath.Join(dir, "stdout.fifo"),
+		StderrLogFile: "stderr",
+		StderrFifo:    filepath.Join(dir, "stderr.fifo"),
+		MaxFiles:      2,
+		MaxFileSizeMB: 1,
+		Backpressure:  structs.LogBackpressureSpill,
+		SpillDir:      spillDir,
+	}
+	if runtime.GOOS == "windows" {
+		cfg.StdoutFifo = "//./pipe/test-spill.stdout"
+		cfg.StderrFifo = "//./pipe/test-spill.stderr"
+	}
+
+	tl, err := NewTaskLogger(cfg, testlog.HCLogger(t))
+	must.NoError(t, err)
+	defer tl.Close()
+
+	// The spill rotators create their first files up front
+	must.FileExists(t, filepath.Join(spillDir, "stdout.0"))
+	must.FileExists(t, filepath.Join(spillDir, "stderr.0"))
+}
@@ -22,13 +22,29 @@ const logmonRPCTimeout = 1 * time.Minute
 
 func (c *logmonClient) Start(cfg *LogConfig) error {
 	req := &proto.StartRequest{
-		LogDir:         cfg.LogDir,
-		StdoutFileName: cfg.StdoutLogFile,
-		StderrFileName: cfg.StderrLogFile,
-		MaxFiles:       uint32(cfg.MaxFiles),
-		MaxFileSizeMb:  uint32(cfg.MaxFileSizeMB),
-		StdoutFifo:     cfg.StdoutFifo,
-		StderrFifo:     cfg.StderrFifo,
+		LogDir:           cfg.LogDir,
+		StdoutFileName:   cfg.StdoutLogFile,
+		StderrFileName:   cfg.StderrLogFile,
+		MaxFiles:         uint32(cfg.MaxFiles),
+		MaxFileSizeMb:    uint32(cfg.MaxFileSizeMB),
+		StdoutFifo:       cfg.StdoutFifo,
+		StderrFifo:       cfg.StderrFifo,
+		Backpressure:     cfg.Backpressure,
+		SpillDir:         cfg.SpillDir,
+		RotateIntervalNs: int64(cfg.RotateInterval),
+		Compression:      cfg.Compression,
+		RetentionNs:      int64(cfg.Retention),
+		Sinks:            logSinksToProto(cfg.Sinks),
+	}
+	if cfg.Task != nil {
+		req.Task = &proto.TaskInfo{
+			Namespace: cfg.Task.Namespace,
+			JobId:     cfg.Task.JobID,
+			AllocId:   cfg.Task.AllocID,
+			TaskGroup: cfg.Task.TaskGroup,
+			TaskName:  cfg.Task.TaskName,
+			NodeId:    cfg.Task.NodeID,
+		}
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), logmonRPCTimeout)
 	defer cancel()
@@ -37,6 +53,24 @@ func (c *logmonClient) Start(cfg *LogConfig) error {
 	return grpcutils.HandleGrpcErr(err, c.doneCtx)
 }
 
+func logSinksToProto(sinks []*LogSinkConfig) []*proto.LogSink {
+	if len(sinks) == 0 {
+		return nil
+	}
+	out := make([]*proto.LogSink, len(sinks))
+	for i, s := range sinks {
+		out[i] = &proto.LogSink{
+			Name:       s.Name,
+			ExePath:    s.ExePath,
+			Args:       s.Args,
+			Config:     s.Config,
+			ApiVersion: s.ApiVersion,
+			Options:    s.Options,
+		}
+	}
+	return out
+}
+
 func (c *logmonClient) Stop() error {
 	req := &proto.StopRequest{}
 	ctx, cancel := context.WithTimeout(context.Background(), logmonRPCTimeout)
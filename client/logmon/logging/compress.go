@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// gzipExt is the extension of rotated files compressed with gzip
+	gzipExt = ".gz"
+
+	// zstdExt is the extension of rotated files compressed with zstd
+	zstdExt = ".zst"
+
+	// compressingPrefix is the prefix of the name of a rotated file while it
+	// is being compressed, so that neither the rotator nor readers of the
+	// logs see a partially written file
+	compressingPrefix = ".compressing."
+)
+
+// SplitCompressedExt splits the extension of a compressed rotated file from
+// its name. The returned extension is empty if the file isn't compressed.
+func SplitCompressedExt(name string) (string, string) {
+	for _, ext := range []string{gzipExt, zstdExt} {
+		if base, ok := strings.CutSuffix(name, ext); ok {
+			return base, ext
+		}
+	}
+	return name, ""
+}
+
+// NewDecompressReader returns a reader of the decompressed content of a
+// rotated file compressed with the given extension, as returned by
+// SplitCompressedExt.
+func NewDecompressReader(r io.Reader, ext string) (io.ReadCloser, error) {
+	switch ext {
+	case gzipExt:
+		return gzip.NewReader(r)
+	case zstdExt:
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	default:
+		return nil, fmt.Errorf("unknown compressed file extension %q", ext)
+	}
+}
+
+// compressFile compresses the file at path with the given compression and
+// removes the uncompressed file once the compressed file has been written.
+func compressFile(path, compression string) error {
+	var ext string
+	switch compression {
+	case structs.LogCompressionGzip:
+		ext = gzipExt
+	case structs.LogCompressionZstd:
+		ext = zstdExt
+	default:
+		return fmt.Errorf("unknown compression %q", compression)
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmpPath := filepath.Join(filepath.Dir(path), compressingPrefix+filepath.Base(path)+ext)
+	dst, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+	defer dst.Close()
+
+	var w io.WriteCloser
+	switch compression {
+	case structs.LogCompressionGzip:
+		w = gzip.NewWriter(dst)
+	case structs.LogCompressionZstd:
+		w, err = zstd.NewWriter(dst)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := io.Copy(w, src); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path+ext); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -33,12 +33,34 @@ const (
 
 	// newLineDelimiter is the delimiter used for new lines.
 	newLineDelimiter = '\n'
+
+	// retentionInterval is the interval at which rotated files are checked
+	// against the retention duration.
+	retentionInterval = time.Minute
 )
 
+// RotateOptions configures the optional time based rotation, compression and
+// retention of the rotated files.
+type RotateOptions struct {
+	// Interval rotates the current file on the first write after it has been
+	// open for the interval, regardless of its size. Zero disables time
+	// based rotation.
+	Interval time.Duration
+
+	// Compression compresses rotated files, either "gzip" or "zstd". Empty
+	// leaves rotated files uncompressed.
+	Compression string
+
+	// Retention removes rotated files last written to longer ago than the
+	// duration. Zero keeps rotated files until they are purged by MaxFiles.
+	Retention time.Duration
+}
+
 // FileRotator writes bytes to a rotated set of files
 type FileRotator struct {
-	MaxFiles int   // MaxFiles is the maximum number of rotated files allowed in a path
-	FileSize int64 // FileSize is the size a rotated file is allowed to grow
+	MaxFiles int           // MaxFiles is the maximum number of rotated files allowed in a path
+	FileSize int64         // FileSize is the size a rotated file is allowed to grow
+	Options  RotateOptions // Options configures time based rotation, compression and retention
 
 	path         string // path is the path on the file system where the rotated set of files are opened
 	baseFileName string // baseFileName is the base file name of the rotated files
@@ -48,24 +70,35 @@ type FileRotator struct {
 	closed           bool
 	fileLock         sync.Mutex
 
-	currentFile *os.File // currentFile is the file that is currently getting written
-	currentWr   int64    // currentWr is the number of bytes written to the current file
-	bufw        *bufio.Writer
-	bufLock     sync.Mutex
+	currentFile   *os.File  // currentFile is the file that is currently getting written
+	currentWr     int64     // currentWr is the number of bytes written to the current file
+	currentOpened time.Time // currentOpened is the time the current file was opened
+	bufw          *bufio.Writer
+	bufLock       sync.Mutex
 
 	flushTicker *time.Ticker
 	logger      hclog.Logger
 	purgeCh     chan struct{}
 	doneCh      chan struct{}
+
+	compressWg sync.WaitGroup // compressWg tracks the compression of rotated files
 }
 
 // NewFileRotator returns a new file rotator
 func NewFileRotator(path string, baseFile string, maxFiles int,
 	fileSize int64, logger hclog.Logger) (*FileRotator, error) {
+	return NewFileRotatorWithOptions(path, baseFile, maxFiles, fileSize, RotateOptions{}, logger)
+}
+
+// NewFileRotatorWithOptions returns a new file rotator that also rotates,
+// compresses and removes files as configured by the options.
+func NewFileRotatorWithOptions(path string, baseFile string, maxFiles int,
+	fileSize int64, opts RotateOptions, logger hclog.Logger) (*FileRotator, error) {
 	logger = logger.Named("rotator")
 	rotator := &FileRotator{
 		MaxFiles: maxFiles,
 		FileSize: fileSize,
+		Options:  opts,
 
 		path:         path,
 		baseFileName: baseFile,
@@ -93,10 +126,11 @@ func (f *FileRotator) Write(p []byte) (n int, err error) {
 	for n < len(p) {
 		// Check if we still have space in the current file, otherwise close and
 		// open the next file
-		if forceRotate || f.currentWr >= f.FileSize {
+		if forceRotate || f.currentWr >= f.FileSize || f.intervalElapsed() {
 			forceRotate = false
 			f.flushBuffer()
 			f.currentFile.Close()
+			f.compress(f.currentFile.Name())
 			if err := f.nextFile(); err != nil {
 				f.logger.Error("error creating next file", "error", err)
 				return 0, err
@@ -159,6 +193,29 @@ func (f *FileRotator) Write(p []byte) (n int, err error) {
 	return
 }
 
+// intervalElapsed returns true if the current file has been written to and
+// open for longer than the rotation interval.
+func (f *FileRotator) intervalElapsed() bool {
+	return f.Options.Interval > 0 && f.currentWr > 0 &&
+		time.Since(f.currentOpened) >= f.Options.Interval
+}
+
+// compress compresses the rotated file in the background if compression is
+// enabled.
+func (f *FileRotator) compress(path string) {
+	if f.Options.Compression == "" {
+		return
+	}
+
+	f.compressWg.Add(1)
+	go func() {
+		defer f.compressWg.Done()
+		if err := compressFile(path, f.Options.Compression); err != nil && !os.IsNotExist(err) {
+			f.logger.Error("error compressing rotated file", "filename", path, "error", err)
+		}
+	}()
+}
+
 // nextFile opens the next file and purges older files if the number of rotated
 // files is larger than the maximum files configured by the user
 func (f *FileRotator) nextFile() error {
@@ -171,7 +228,12 @@ func (f *FileRotator) nextFile() error {
 				continue
 			}
 		}
+		if f.compressedExists(logFileName) {
+			continue
+		}
+		f.fileLock.Lock()
 		f.logFileIdx = nextFileIdx
+		f.fileLock.Unlock()
 		if err := f.createFile(); err != nil {
 			return err
 		}
@@ -189,6 +251,32 @@ func (f *FileRotator) nextFile() error {
 	return nil
 }
 
+// compressedExists returns true if a compressed version of the log file
+// exists, in which case its index must not be reused.
+func (f *FileRotator) compressedExists(logFileName string) bool {
+	for _, ext := range []string{gzipExt, zstdExt} {
+		if _, err := os.Stat(logFileName + ext); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// fileIndex returns the index of a rotated file from its name, which may have
+// the extension of a compressed file.
+func (f *FileRotator) fileIndex(name string) (int, bool) {
+	name, _ = SplitCompressedExt(name)
+	fileIdx, ok := strings.CutPrefix(name, fmt.Sprintf("%s.", f.baseFileName))
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(fileIdx)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
 // lastFile finds out the rotated file with the largest index in a path.
 func (f *FileRotator) lastFile() error {
 	finfos, err := os.ReadDir(f.path)
@@ -196,22 +284,24 @@ func (f *FileRotator) lastFile() error {
 		return err
 	}
 
-	prefix := fmt.Sprintf("%s.", f.baseFileName)
+	var compressed bool
 	for _, fi := range finfos {
 		if fi.IsDir() {
 			continue
 		}
-		if strings.HasPrefix(fi.Name(), prefix) {
-			fileIdx := strings.TrimPrefix(fi.Name(), prefix)
-			n, err := strconv.Atoi(fileIdx)
-			if err != nil {
-				continue
-			}
-			if n > f.logFileIdx {
+		if n, ok := f.fileIndex(fi.Name()); ok {
+			_, ext := SplitCompressedExt(fi.Name())
+			if n > f.logFileIdx || (n == f.logFileIdx && ext != "") {
 				f.logFileIdx = n
+				compressed = ext != ""
 			}
 		}
 	}
+
+	// A compressed file has been rotated already, so continue with the next
+	if compressed {
+		f.logFileIdx++
+	}
 	if err := f.createFile(); err != nil {
 		return err
 	}
@@ -227,6 +317,7 @@ func (f *FileRotator) createFile() error {
 	}
 
 	f.currentFile = cFile
+	f.currentOpened = time.Now()
 	fi, err := f.currentFile.Stat()
 	if err != nil {
 		return err
@@ -267,59 +358,126 @@ func (f *FileRotator) Close() error {
 		f.currentFile.Close()
 	}
 
+	// Wait for rotated files to be compressed
+	f.compressWg.Wait()
+
 	return nil
 }
 
 // purgeOldFiles removes older files and keeps only the last N files rotated for
-// a file
+// a file. If a retention is configured, rotated files last written to longer
+// ago than the retention are removed as well.
 func (f *FileRotator) purgeOldFiles() {
+	var retentionCh <-chan time.Time
+	if f.Options.Retention > 0 {
+		ticker := time.NewTicker(retentionInterval)
+		defer ticker.Stop()
+		retentionCh = ticker.C
+	}
+
 	for {
 		select {
 		case <-f.purgeCh:
-			var fIndexes []int
-			files, err := os.ReadDir(f.path)
-			if err != nil {
+			if err := f.purge(); err != nil {
 				f.logger.Error("error getting directory listing", "error", err)
 				return
 			}
-			// Inserting all the rotated files in a slice
-			for _, fi := range files {
-				if strings.HasPrefix(fi.Name(), f.baseFileName) {
-					fileIdx := strings.TrimPrefix(fi.Name(), fmt.Sprintf("%s.", f.baseFileName))
-					n, err := strconv.Atoi(fileIdx)
-					if err != nil {
-						f.logger.Error("error extracting file index", "error", err)
-						continue
-					}
-					fIndexes = append(fIndexes, n)
-				}
+		case <-retentionCh:
+			if err := f.purge(); err != nil {
+				f.logger.Error("error getting directory listing", "error", err)
+				return
 			}
+		case <-f.doneCh:
+			return
+		}
+	}
+}
 
-			// Not continuing to delete files if the number of files is not more
-			// than MaxFiles
-			if len(fIndexes) <= f.MaxFiles {
-				continue
+// purge removes the rotated files exceeding MaxFiles or the retention.
+func (f *FileRotator) purge() error {
+	files, err := os.ReadDir(f.path)
+	if err != nil {
+		return err
+	}
+
+	// Inserting all the rotated files in a map keyed by their index, since
+	// a file may exist both uncompressed and compressed while it is being
+	// compressed
+	fileNames := make(map[int][]string)
+	var fIndexes []int
+	for _, fi := range files {
+		if !strings.HasPrefix(fi.Name(), f.baseFileName) {
+			continue
+		}
+		n, ok := f.fileIndex(fi.Name())
+		if !ok {
+			f.logger.Error("error extracting file index", "filename", fi.Name())
+			continue
+		}
+		if _, ok := fileNames[n]; !ok {
+			fIndexes = append(fIndexes, n)
+		}
+		fileNames[n] = append(fileNames[n], fi.Name())
+	}
+	if len(fIndexes) == 0 {
+		return nil
+	}
+
+	// Sorting the file indexes so that we can purge the older files and keep
+	// only the number of files as configured by the user
+	sort.Ints(fIndexes)
+	var toDelete []int
+	if len(fIndexes) > f.MaxFiles {
+		toDelete = fIndexes[0 : len(fIndexes)-f.MaxFiles]
+	}
+
+	// Removing the rotated files past their retention, never touching the
+	// file currently being written
+	if f.Options.Retention > 0 {
+		f.fileLock.Lock()
+		current := f.logFileIdx
+		f.fileLock.Unlock()
+
+		cutoff := time.Now().Add(-f.Options.Retention)
+		for _, fIndex := range fIndexes[len(toDelete):] {
+			if fIndex >= current {
+				break
+			}
+			if f.lastModified(fileNames[fIndex]).Before(cutoff) {
+				toDelete = append(toDelete, fIndex)
 			}
+		}
+	}
 
-			// Sorting the file indexes so that we can purge the older files and keep
-			// only the number of files as configured by the user
-			sort.Ints(fIndexes)
-			toDelete := fIndexes[0 : len(fIndexes)-f.MaxFiles]
-			for _, fIndex := range toDelete {
-				fname := filepath.Join(f.path, fmt.Sprintf("%s.%d", f.baseFileName, fIndex))
-				err := os.RemoveAll(fname)
-				if err != nil {
-					f.logger.Error("error removing file", "filename", fname, "error", err)
-				}
+	for _, fIndex := range toDelete {
+		for _, name := range fileNames[fIndex] {
+			fname := filepath.Join(f.path, name)
+			err := os.RemoveAll(fname)
+			if err != nil {
+				f.logger.Error("error removing file", "filename", fname, "error", err)
 			}
+		}
+	}
 
-			f.fileLock.Lock()
-			f.oldestLogFileIdx = fIndexes[0]
-			f.fileLock.Unlock()
-		case <-f.doneCh:
-			return
+	f.fileLock.Lock()
+	f.oldestLogFileIdx = fIndexes[0]
+	f.fileLock.Unlock()
+	return nil
+}
+
+// lastModified returns the latest modification time of the named files.
+func (f *FileRotator) lastModified(names []string) time.Time {
+	var modTime time.Time
+	for _, name := range names {
+		fi, err := os.Stat(filepath.Join(f.path, name))
+		if err != nil {
+			continue
+		}
+		if fi.ModTime().After(modTime) {
+			modTime = fi.ModTime()
 		}
 	}
+	return modTime
 }
 
 // flushBuffer flushes the buffer
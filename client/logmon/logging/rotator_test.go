@@ -5,12 +5,15 @@ package logging
 
 import (
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/shoenig/test/must"
 	"go.uber.org/goleak"
@@ -275,6 +278,147 @@ func TestFileRotator_PurgeOldFiles(t *testing.T) {
 	})
 }
 
+func TestFileRotator_OpenLastFile_Compressed(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	for _, name := range []string{"redis.stdout.0.gz", "redis.stdout.1.zst"} {
+		f, err := os.Create(filepath.Join(path, name))
+		must.NoError(t, err)
+		f.Close()
+	}
+
+	fr, err := NewFileRotator(path, baseFileName, 10, 10, testlog.HCLogger(t))
+	must.NoError(t, err)
+	defer fr.Close()
+
+	must.Eq(t, filepath.Join(path, "redis.stdout.2"), fr.currentFile.Name())
+}
+
+func TestFileRotator_RotateInterval(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	opts := RotateOptions{Interval: 50 * time.Millisecond}
+	fr, err := NewFileRotatorWithOptions(path, baseFileName, 10, 1024, opts, testlog.HCLogger(t))
+	must.NoError(t, err)
+	defer fr.Close()
+
+	_, err = fr.Write([]byte("abc\n"))
+	must.NoError(t, err)
+
+	time.Sleep(2 * opts.Interval)
+
+	_, err = fr.Write([]byte("def\n"))
+	must.NoError(t, err)
+
+	testutil.WaitForResult(func() (bool, error) {
+		for name, size := range map[string]int64{"redis.stdout.0": 4, "redis.stdout.1": 4} {
+			fname := filepath.Join(path, name)
+			fi, err := os.Stat(fname)
+			if err != nil {
+				return false, fmt.Errorf("failed to stat file %v: %w", fname, err)
+			}
+			if fi.Size() != size {
+				return false, fmt.Errorf("expected size: %v, actual: %v", size, fi.Size())
+			}
+		}
+		return true, nil
+	}, func(err error) {
+		must.NoError(t, err)
+	})
+}
+
+func TestFileRotator_Compression(t *testing.T) {
+	for _, tc := range []struct {
+		compression string
+		ext         string
+	}{
+		{compression: structs.LogCompressionGzip, ext: gzipExt},
+		{compression: structs.LogCompressionZstd, ext: zstdExt},
+	} {
+		t.Run(tc.compression, func(t *testing.T) {
+			defer goleak.VerifyNone(t)
+
+			path := t.TempDir()
+
+			opts := RotateOptions{Compression: tc.compression}
+			fr, err := NewFileRotatorWithOptions(path, baseFileName, 10, 5, opts, testlog.HCLogger(t))
+			must.NoError(t, err)
+
+			str := "abcdefgh"
+			_, err = fr.Write([]byte(str))
+			must.NoError(t, err)
+
+			// Closing waits for the rotated file to be compressed
+			must.NoError(t, fr.Close())
+
+			_, err = os.Stat(filepath.Join(path, "redis.stdout.0"))
+			must.True(t, os.IsNotExist(err))
+
+			f, err := os.Open(filepath.Join(path, "redis.stdout.0"+tc.ext))
+			must.NoError(t, err)
+			defer f.Close()
+
+			r, err := NewDecompressReader(f, tc.ext)
+			must.NoError(t, err)
+			defer r.Close()
+
+			b, err := io.ReadAll(r)
+			must.NoError(t, err)
+			must.Eq(t, "abcde", string(b))
+
+			// The current file is never compressed
+			b, err = os.ReadFile(filepath.Join(path, "redis.stdout.1"))
+			must.NoError(t, err)
+			must.Eq(t, "fgh", string(b))
+		})
+	}
+}
+
+func TestFileRotator_Retention(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	path := t.TempDir()
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, name := range []string{"redis.stdout.0", "redis.stdout.1.gz", "redis.stdout.2"} {
+		fname := filepath.Join(path, name)
+		f, err := os.Create(fname)
+		must.NoError(t, err)
+		f.Close()
+		must.NoError(t, os.Chtimes(fname, old, old))
+	}
+
+	opts := RotateOptions{Retention: time.Hour}
+	fr, err := NewFileRotatorWithOptions(path, baseFileName, 10, 10, opts, testlog.HCLogger(t))
+	must.NoError(t, err)
+	defer fr.Close()
+
+	must.NoError(t, fr.purge())
+
+	files, err := os.ReadDir(path)
+	must.NoError(t, err)
+	must.SliceLen(t, 1, files)
+
+	// The file currently being written is kept regardless of its age
+	must.Eq(t, "redis.stdout.2", files[0].Name())
+}
+
+func TestSplitCompressedExt(t *testing.T) {
+	for name, exp := range map[string][2]string{
+		"redis.stdout.0":     {"redis.stdout.0", ""},
+		"redis.stdout.0.gz":  {"redis.stdout.0", ".gz"},
+		"redis.stdout.0.zst": {"redis.stdout.0", ".zst"},
+	} {
+		base, ext := SplitCompressedExt(name)
+		must.Eq(t, exp[0], base)
+		must.Eq(t, exp[1], ext)
+	}
+}
+
 func BenchmarkRotator(b *testing.B) {
 	kb := 1024
 	for _, inputSize := range []int{kb, 2 * kb, 4 * kb, 8 * kb, 16 * kb, 32 * kb, 64 * kb, 128 * kb, 256 * kb} {
@@ -14,6 +14,8 @@ import (
 	hclog "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/nomad/client/lib/fifo"
 	"github.com/hashicorp/nomad/client/logmon/logging"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/logsink"
 )
 
 const (
@@ -44,6 +46,31 @@ type LogConfig struct {
 
 	// MaxFileSizeMB is the max log file size in MB allowed before rotation occures
 	MaxFileSizeMB int
+
+	// Backpressure is the policy applied when logs can't be written as fast
+	// as the task writes them; one of "block", "drop", or "spill"
+	Backpressure string
+
+	// SpillDir is the host path logs are spilled to for the "spill"
+	// backpressure policy
+	SpillDir string
+
+	// RotateInterval is the interval after which the log file is rotated
+	// regardless of its size
+	RotateInterval time.Duration
+
+	// Compression is the algorithm used to compress rotated log files
+	Compression string
+
+	// Retention is how long rotated log files are kept
+	Retention time.Duration
+
+	// Sinks are the log sink plugins the task's logs are shipped to, in
+	// addition to being written to the log files
+	Sinks []*LogSinkConfig
+
+	// Task identifies the task to the log sink plugins
+	Task *logsink.TaskInfo
 }
 
 type LogMon interface {
@@ -109,6 +136,9 @@ type TaskLogger struct {
 
 	// rotator for stderr
 	lre *logRotatorWrapper
+
+	// shippers of the task's logs to its log sinks
+	shippers []*sinkShipper
 }
 
 // IsRunning will return true as long as one rotator wrapper is still running
@@ -136,33 +166,72 @@ func (tl *TaskLogger) Close() {
 		}()
 	}
 	wg.Wait()
+
+	// Ship the logs written until the rotators were closed
+	tl.closeShippers()
+}
+
+func (tl *TaskLogger) closeShippers() {
+	var wg sync.WaitGroup
+	for _, s := range tl.shippers {
+		wg.Add(1)
+		go func(s *sinkShipper) {
+			s.Close()
+			wg.Done()
+		}(s)
+	}
+	wg.Wait()
 }
 
 func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 	tl := &TaskLogger{config: cfg}
 
-	logFileSize := int64(cfg.MaxFileSizeMB * 1024 * 1024)
-	lro, err := logging.NewFileRotator(cfg.LogDir, cfg.StdoutLogFile,
-		cfg.MaxFiles, logFileSize, logger)
+	policy := cfg.Backpressure
+	if policy == structs.LogBackpressureSpill {
+		if cfg.SpillDir == "" {
+			logger.Warn("log spill directory is not configured, logs will be dropped instead of spilled")
+			policy = structs.LogBackpressureDrop
+		} else if err := os.MkdirAll(cfg.SpillDir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create log spill directory %q: %v", cfg.SpillDir, err)
+		}
+	}
+
+	// A log sink that fails to launch doesn't prevent the task from running,
+	// its logs are still written to the log files
+	for _, sinkCfg := range cfg.Sinks {
+		s, err := newSinkShipper(sinkCfg, cfg.Task, logger)
+		if err != nil {
+			logger.Error("failed to launch log sink", "log_sink", sinkCfg.Name, "error", err)
+			continue
+		}
+		tl.shippers = append(tl.shippers, s)
+	}
+
+	outWriter, err := newTaskLogWriter(cfg, policy, cfg.StdoutLogFile, logger)
 	if err != nil {
+		tl.closeShippers()
 		return nil, fmt.Errorf("failed to create stdout logfile for %q: %v", cfg.StdoutLogFile, err)
 	}
+	outWriter = newSinkWriter(outWriter, logsink.StreamStdout, tl.shippers)
 
-	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, lro)
+	wrapperOut, err := newLogRotatorWrapper(cfg.StdoutFifo, logger, outWriter)
 	if err != nil {
+		tl.closeShippers()
 		return nil, err
 	}
 
 	tl.lro = wrapperOut
 
-	lre, err := logging.NewFileRotator(cfg.LogDir, cfg.StderrLogFile,
-		cfg.MaxFiles, logFileSize, logger)
+	errWriter, err := newTaskLogWriter(cfg, policy, cfg.StderrLogFile, logger)
 	if err != nil {
+		tl.closeShippers()
 		return nil, fmt.Errorf("failed to create stderr logfile for %q: %v", cfg.StderrLogFile, err)
 	}
+	errWriter = newSinkWriter(errWriter, logsink.StreamStderr, tl.shippers)
 
-	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, lre)
+	wrapperErr, err := newLogRotatorWrapper(cfg.StderrFifo, logger, errWriter)
 	if err != nil {
+		tl.closeShippers()
 		return nil, err
 	}
 
@@ -172,6 +241,34 @@ func NewTaskLogger(cfg *LogConfig, logger hclog.Logger) (*TaskLogger, error) {
 
 }
 
+// newTaskLogWriter returns the writer for one of the task's log files, which
+// applies the backpressure policy to writes to the file's rotator.
+func newTaskLogWriter(cfg *LogConfig, policy, fileName string, logger hclog.Logger) (io.WriteCloser, error) {
+	logFileSize := int64(cfg.MaxFileSizeMB * 1024 * 1024)
+	opts := logging.RotateOptions{
+		Interval:    cfg.RotateInterval,
+		Compression: cfg.Compression,
+		Retention:   cfg.Retention,
+	}
+	rotator, err := logging.NewFileRotatorWithOptions(cfg.LogDir, fileName,
+		cfg.MaxFiles, logFileSize, opts, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var spill io.WriteCloser
+	if policy == structs.LogBackpressureSpill {
+		spill, err = logging.NewFileRotator(cfg.SpillDir, fileName,
+			cfg.MaxFiles, logFileSize, logger)
+		if err != nil {
+			rotator.Close()
+			return nil, err
+		}
+	}
+
+	return newBackpressureWriter(policy, rotator, spill, logger.With("file", fileName)), nil
+}
+
 // logRotatorWrapper wraps our log rotator and exposes a pipe that can feed the
 // log rotator data. The processOutWriter should be attached to the process and
 // data will be copied from the reader to the rotator.
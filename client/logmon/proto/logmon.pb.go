@@ -25,16 +25,23 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type StartRequest struct {
-	LogDir               string   `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
-	StdoutFileName       string   `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
-	StderrFileName       string   `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
-	MaxFiles             uint32   `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
-	MaxFileSizeMb        uint32   `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
-	StdoutFifo           string   `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
-	StderrFifo           string   `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	LogDir               string     `protobuf:"bytes,1,opt,name=log_dir,json=logDir,proto3" json:"log_dir,omitempty"`
+	StdoutFileName       string     `protobuf:"bytes,2,opt,name=stdout_file_name,json=stdoutFileName,proto3" json:"stdout_file_name,omitempty"`
+	StderrFileName       string     `protobuf:"bytes,3,opt,name=stderr_file_name,json=stderrFileName,proto3" json:"stderr_file_name,omitempty"`
+	MaxFiles             uint32     `protobuf:"varint,4,opt,name=max_files,json=maxFiles,proto3" json:"max_files,omitempty"`
+	MaxFileSizeMb        uint32     `protobuf:"varint,5,opt,name=max_file_size_mb,json=maxFileSizeMb,proto3" json:"max_file_size_mb,omitempty"`
+	StdoutFifo           string     `protobuf:"bytes,6,opt,name=stdout_fifo,json=stdoutFifo,proto3" json:"stdout_fifo,omitempty"`
+	StderrFifo           string     `protobuf:"bytes,7,opt,name=stderr_fifo,json=stderrFifo,proto3" json:"stderr_fifo,omitempty"`
+	Backpressure         string     `protobuf:"bytes,8,opt,name=backpressure,proto3" json:"backpressure,omitempty"`
+	SpillDir             string     `protobuf:"bytes,9,opt,name=spill_dir,json=spillDir,proto3" json:"spill_dir,omitempty"`
+	RotateIntervalNs     int64      `protobuf:"varint,10,opt,name=rotate_interval_ns,json=rotateIntervalNs,proto3" json:"rotate_interval_ns,omitempty"`
+	Compression          string     `protobuf:"bytes,11,opt,name=compression,proto3" json:"compression,omitempty"`
+	RetentionNs          int64      `protobuf:"varint,12,opt,name=retention_ns,json=retentionNs,proto3" json:"retention_ns,omitempty"`
+	Sinks                []*LogSink `protobuf:"bytes,13,rep,name=sinks,proto3" json:"sinks,omitempty"`
+	Task                 *TaskInfo  `protobuf:"bytes,14,opt,name=task,proto3" json:"task,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *StartRequest) Reset()         { *m = StartRequest{} }
@@ -111,6 +118,55 @@ func (m *StartRequest) GetStderrFifo() string {
 	return ""
 }
 
+func (m *StartRequest) GetBackpressure() string {
+	if m != nil {
+		return m.Backpressure
+	}
+	return ""
+}
+
+func (m *StartRequest) GetSpillDir() string {
+	if m != nil {
+		return m.SpillDir
+	}
+	return ""
+}
+
+func (m *StartRequest) GetRotateIntervalNs() int64 {
+	if m != nil {
+		return m.RotateIntervalNs
+	}
+	return 0
+}
+
+func (m *StartRequest) GetCompression() string {
+	if m != nil {
+		return m.Compression
+	}
+	return ""
+}
+
+func (m *StartRequest) GetRetentionNs() int64 {
+	if m != nil {
+		return m.RetentionNs
+	}
+	return 0
+}
+
+func (m *StartRequest) GetSinks() []*LogSink {
+	if m != nil {
+		return m.Sinks
+	}
+	return nil
+}
+
+func (m *StartRequest) GetTask() *TaskInfo {
+	if m != nil {
+		return m.Task
+	}
+	return nil
+}
+
 type StartResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -204,11 +260,172 @@ func (m *StopResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StopResponse proto.InternalMessageInfo
 
+type LogSink struct {
+	Name                 string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ExePath              string            `protobuf:"bytes,2,opt,name=exe_path,json=exePath,proto3" json:"exe_path,omitempty"`
+	Args                 []string          `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty"`
+	Config               []byte            `protobuf:"bytes,4,opt,name=config,proto3" json:"config,omitempty"`
+	ApiVersion           string            `protobuf:"bytes,5,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	Options              map[string]string `protobuf:"bytes,6,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *LogSink) Reset()         { *m = LogSink{} }
+func (m *LogSink) String() string { return proto.CompactTextString(m) }
+func (*LogSink) ProtoMessage()    {}
+func (*LogSink) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be72d5e24d2ecba6, []int{4}
+}
+
+func (m *LogSink) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogSink.Unmarshal(m, b)
+}
+func (m *LogSink) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogSink.Marshal(b, m, deterministic)
+}
+func (m *LogSink) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogSink.Merge(m, src)
+}
+func (m *LogSink) XXX_Size() int {
+	return xxx_messageInfo_LogSink.Size(m)
+}
+func (m *LogSink) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogSink.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogSink proto.InternalMessageInfo
+
+func (m *LogSink) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LogSink) GetExePath() string {
+	if m != nil {
+		return m.ExePath
+	}
+	return ""
+}
+
+func (m *LogSink) GetArgs() []string {
+	if m != nil {
+		return m.Args
+	}
+	return nil
+}
+
+func (m *LogSink) GetConfig() []byte {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+func (m *LogSink) GetApiVersion() string {
+	if m != nil {
+		return m.ApiVersion
+	}
+	return ""
+}
+
+func (m *LogSink) GetOptions() map[string]string {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type TaskInfo struct {
+	Namespace            string   `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	JobId                string   `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	AllocId              string   `protobuf:"bytes,3,opt,name=alloc_id,json=allocId,proto3" json:"alloc_id,omitempty"`
+	TaskGroup            string   `protobuf:"bytes,4,opt,name=task_group,json=taskGroup,proto3" json:"task_group,omitempty"`
+	TaskName             string   `protobuf:"bytes,5,opt,name=task_name,json=taskName,proto3" json:"task_name,omitempty"`
+	NodeId               string   `protobuf:"bytes,6,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TaskInfo) Reset()         { *m = TaskInfo{} }
+func (m *TaskInfo) String() string { return proto.CompactTextString(m) }
+func (*TaskInfo) ProtoMessage()    {}
+func (*TaskInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_be72d5e24d2ecba6, []int{5}
+}
+
+func (m *TaskInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TaskInfo.Unmarshal(m, b)
+}
+func (m *TaskInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TaskInfo.Marshal(b, m, deterministic)
+}
+func (m *TaskInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TaskInfo.Merge(m, src)
+}
+func (m *TaskInfo) XXX_Size() int {
+	return xxx_messageInfo_TaskInfo.Size(m)
+}
+func (m *TaskInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_TaskInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TaskInfo proto.InternalMessageInfo
+
+func (m *TaskInfo) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *TaskInfo) GetJobId() string {
+	if m != nil {
+		return m.JobId
+	}
+	return ""
+}
+
+func (m *TaskInfo) GetAllocId() string {
+	if m != nil {
+		return m.AllocId
+	}
+	return ""
+}
+
+func (m *TaskInfo) GetTaskGroup() string {
+	if m != nil {
+		return m.TaskGroup
+	}
+	return ""
+}
+
+func (m *TaskInfo) GetTaskName() string {
+	if m != nil {
+		return m.TaskName
+	}
+	return ""
+}
+
+func (m *TaskInfo) GetNodeId() string {
+	if m != nil {
+		return m.NodeId
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*StartRequest)(nil), "hashicorp.nomad.client.logmon.proto.StartRequest")
 	proto.RegisterType((*StartResponse)(nil), "hashicorp.nomad.client.logmon.proto.StartResponse")
 	proto.RegisterType((*StopRequest)(nil), "hashicorp.nomad.client.logmon.proto.StopRequest")
 	proto.RegisterType((*StopResponse)(nil), "hashicorp.nomad.client.logmon.proto.StopResponse")
+	proto.RegisterType((*LogSink)(nil), "hashicorp.nomad.client.logmon.proto.LogSink")
+	proto.RegisterMapType((map[string]string)(nil), "hashicorp.nomad.client.logmon.proto.LogSink.OptionsEntry")
+	proto.RegisterType((*TaskInfo)(nil), "hashicorp.nomad.client.logmon.proto.TaskInfo")
 }
 
 func init() {
@@ -216,27 +433,50 @@ func init() {
 }
 
 var fileDescriptor_be72d5e24d2ecba6 = []byte{
-	// 320 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x90, 0x31, 0x6f, 0xc2, 0x30,
-	0x10, 0x85, 0x1b, 0x0a, 0xa1, 0x1c, 0x0d, 0x45, 0x5e, 0x1a, 0xd1, 0xa1, 0x28, 0x1d, 0xca, 0x14,
-	0x0a, 0xfd, 0x07, 0x55, 0xd5, 0xa9, 0x74, 0x80, 0xad, 0x4b, 0x64, 0xc0, 0x09, 0x96, 0x62, 0x5f,
-	0x6a, 0x1b, 0x09, 0xb1, 0xf6, 0xd7, 0xf6, 0x5f, 0x54, 0x71, 0x4c, 0x94, 0x11, 0x26, 0xeb, 0xee,
-	0x7d, 0x4f, 0xf7, 0xfc, 0x60, 0xbc, 0xc9, 0x39, 0x93, 0x66, 0x9a, 0x63, 0x26, 0x50, 0x4e, 0x0b,
-	0x85, 0x06, 0xdd, 0x10, 0xdb, 0x81, 0x3c, 0xed, 0xa8, 0xde, 0xf1, 0x0d, 0xaa, 0x22, 0x96, 0x28,
-	0xe8, 0x36, 0xae, 0x1c, 0x71, 0x13, 0x8a, 0x7e, 0x5b, 0x70, 0xbb, 0x32, 0x54, 0x99, 0x25, 0xfb,
-	0xd9, 0x33, 0x6d, 0xc8, 0x3d, 0x74, 0x73, 0xcc, 0x92, 0x2d, 0x57, 0xa1, 0x37, 0xf6, 0x26, 0xbd,
-	0xa5, 0x9f, 0x63, 0xf6, 0xce, 0x15, 0x99, 0xc0, 0x50, 0x9b, 0x2d, 0xee, 0x4d, 0x92, 0xf2, 0x9c,
-	0x25, 0x92, 0x0a, 0x16, 0xb6, 0x2c, 0x31, 0xa8, 0xf6, 0x1f, 0x3c, 0x67, 0x5f, 0x54, 0x30, 0x47,
-	0x32, 0xa5, 0x1a, 0xe4, 0x75, 0x4d, 0x32, 0xa5, 0x6a, 0xf2, 0x01, 0x7a, 0x82, 0x1e, 0x2c, 0xa6,
-	0xc3, 0xf6, 0xd8, 0x9b, 0x04, 0xcb, 0x1b, 0x41, 0x0f, 0xa5, 0xae, 0xc9, 0x33, 0x0c, 0x4f, 0x62,
-	0xa2, 0xf9, 0x91, 0x25, 0x62, 0x1d, 0x76, 0x2c, 0x13, 0x38, 0x66, 0xc5, 0x8f, 0x6c, 0xb1, 0x26,
-	0x8f, 0xd0, 0xaf, 0x93, 0xa5, 0x18, 0xfa, 0xf6, 0x14, 0x9c, 0x42, 0xa5, 0xe8, 0x80, 0x2a, 0x50,
-	0x8a, 0x61, 0xb7, 0x06, 0x6c, 0x96, 0x14, 0xa3, 0x3b, 0x08, 0x5c, 0x09, 0xba, 0x40, 0xa9, 0x59,
-	0x14, 0x40, 0x7f, 0x65, 0xb0, 0x70, 0xa5, 0x44, 0x83, 0xb2, 0xa4, 0x72, 0xac, 0xe4, 0xf9, 0x9f,
-	0x07, 0xfe, 0x27, 0x66, 0x0b, 0x94, 0xa4, 0x80, 0x8e, 0xb5, 0x92, 0x59, 0x7c, 0x46, 0xdf, 0x71,
-	0xb3, 0xeb, 0xd1, 0xfc, 0x12, 0x8b, 0x4b, 0x76, 0x45, 0x04, 0xb4, 0xcb, 0x30, 0xe4, 0xe5, 0x4c,
-	0x77, 0xfd, 0x8d, 0xd1, 0xec, 0x02, 0xc7, 0xe9, 0xdc, 0x5b, 0xf7, 0xbb, 0x63, 0xf7, 0x6b, 0xdf,
-	0x3e, 0xaf, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd8, 0xb4, 0x87, 0xfc, 0x7a, 0x02, 0x00, 0x00,
+	// 687 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xcd, 0x72, 0xd3, 0x3a,
+	0x14, 0xbe, 0x6e, 0x12, 0x3b, 0x39, 0x49, 0x7a, 0x33, 0x9a, 0x7b, 0xa9, 0x29, 0x30, 0x98, 0xb0,
+	0x20, 0x8b, 0x92, 0xd2, 0xb0, 0x81, 0xee, 0xe8, 0xf0, 0x33, 0x99, 0x69, 0x0b, 0xe3, 0x30, 0x2c,
+	0xd8, 0x78, 0x94, 0x58, 0x71, 0xd4, 0xd8, 0x3a, 0x46, 0x52, 0x3a, 0x69, 0x1f, 0x84, 0xd7, 0xe0,
+	0x0d, 0x78, 0x1f, 0xde, 0x82, 0x91, 0x6c, 0x87, 0xb0, 0x4b, 0x57, 0xd1, 0xf9, 0xce, 0x77, 0x8e,
+	0x4e, 0xbe, 0xf3, 0xc9, 0x10, 0xcc, 0x52, 0xce, 0x84, 0x3e, 0x4e, 0x31, 0xc9, 0x50, 0x1c, 0xe7,
+	0x12, 0x35, 0x96, 0xc1, 0xd0, 0x06, 0xe4, 0xe9, 0x82, 0xaa, 0x05, 0x9f, 0xa1, 0xcc, 0x87, 0x02,
+	0x33, 0x1a, 0x0f, 0x8b, 0x8a, 0xe1, 0x36, 0xa9, 0xff, 0xb3, 0x0e, 0x9d, 0x89, 0xa6, 0x52, 0x87,
+	0xec, 0xdb, 0x8a, 0x29, 0x4d, 0x0e, 0xc0, 0x4b, 0x31, 0x89, 0x62, 0x2e, 0x7d, 0x27, 0x70, 0x06,
+	0xad, 0xd0, 0x4d, 0x31, 0x79, 0xcb, 0x25, 0x19, 0x40, 0x4f, 0xe9, 0x18, 0x57, 0x3a, 0x9a, 0xf3,
+	0x94, 0x45, 0x82, 0x66, 0xcc, 0xdf, 0xb3, 0x8c, 0xfd, 0x02, 0x7f, 0xcf, 0x53, 0x76, 0x49, 0x33,
+	0x56, 0x32, 0x99, 0x94, 0x5b, 0xcc, 0xda, 0x86, 0xc9, 0xa4, 0xdc, 0x30, 0x1f, 0x40, 0x2b, 0xa3,
+	0x6b, 0x4b, 0x53, 0x7e, 0x3d, 0x70, 0x06, 0xdd, 0xb0, 0x99, 0xd1, 0xb5, 0xc9, 0x2b, 0xf2, 0x0c,
+	0x7a, 0x55, 0x32, 0x52, 0xfc, 0x96, 0x45, 0xd9, 0xd4, 0x6f, 0x58, 0x4e, 0xb7, 0xe4, 0x4c, 0xf8,
+	0x2d, 0xbb, 0x98, 0x92, 0xc7, 0xd0, 0xde, 0x4c, 0x36, 0x47, 0xdf, 0xb5, 0x57, 0x41, 0x35, 0xd4,
+	0x1c, 0x4b, 0x42, 0x31, 0xd0, 0x1c, 0x7d, 0x6f, 0x43, 0xb0, 0xb3, 0xcc, 0x91, 0xf4, 0xa1, 0x33,
+	0xa5, 0xb3, 0x65, 0x2e, 0x99, 0x52, 0x2b, 0xc9, 0xfc, 0xa6, 0x65, 0xfc, 0x85, 0x99, 0x59, 0x55,
+	0xce, 0xd3, 0xd4, 0x4a, 0xd3, 0xb2, 0x84, 0xa6, 0x05, 0x8c, 0x38, 0x47, 0x40, 0x24, 0x6a, 0xaa,
+	0x59, 0xc4, 0x85, 0x66, 0xf2, 0x9a, 0xa6, 0x91, 0x50, 0x3e, 0x04, 0xce, 0xa0, 0x16, 0xf6, 0x8a,
+	0xcc, 0xb8, 0x4c, 0x5c, 0x2a, 0x12, 0x40, 0x7b, 0x86, 0x99, 0xed, 0xcc, 0x51, 0xf8, 0x6d, 0xdb,
+	0x6c, 0x1b, 0x22, 0x4f, 0xa0, 0x23, 0x99, 0x66, 0x42, 0x73, 0x14, 0xa6, 0x53, 0xc7, 0x76, 0x6a,
+	0x6f, 0xb0, 0x4b, 0x45, 0xce, 0xa0, 0xa1, 0xb8, 0x58, 0x2a, 0xbf, 0x1b, 0xd4, 0x06, 0xed, 0xd1,
+	0xd1, 0x70, 0x87, 0x75, 0x0f, 0xcf, 0x31, 0x99, 0x70, 0xb1, 0x0c, 0x8b, 0x52, 0xf2, 0x06, 0xea,
+	0x9a, 0xaa, 0xa5, 0xbf, 0x1f, 0x38, 0x83, 0xf6, 0xe8, 0xf9, 0x4e, 0x2d, 0x3e, 0x53, 0xb5, 0x1c,
+	0x8b, 0x39, 0x86, 0xb6, 0xb4, 0xff, 0x2f, 0x74, 0x4b, 0xff, 0xa8, 0x1c, 0x85, 0x62, 0xfd, 0x2e,
+	0xb4, 0x27, 0x1a, 0xf3, 0xd2, 0x4f, 0xfd, 0x7d, 0xe8, 0x14, 0x61, 0x99, 0xfe, 0xbe, 0x07, 0x5e,
+	0x39, 0x05, 0x21, 0x50, 0xb7, 0xe6, 0x28, 0x8c, 0x66, 0xcf, 0xe4, 0x3e, 0x34, 0xd9, 0x9a, 0x45,
+	0x39, 0xd5, 0x8b, 0xd2, 0x5e, 0x1e, 0x5b, 0xb3, 0x4f, 0x54, 0x2f, 0x0c, 0x9d, 0xca, 0x44, 0xf9,
+	0xb5, 0xa0, 0x66, 0xe8, 0xe6, 0x4c, 0xee, 0x81, 0x3b, 0x43, 0x31, 0xe7, 0x89, 0xb5, 0x4f, 0x27,
+	0x2c, 0x23, 0xb3, 0x72, 0x9a, 0xf3, 0xe8, 0x9a, 0x49, 0x2b, 0x71, 0xa3, 0x58, 0x39, 0xcd, 0xf9,
+	0x97, 0x02, 0x21, 0x13, 0xf0, 0x30, 0x37, 0x52, 0x2a, 0xdf, 0xb5, 0x02, 0xbe, 0xbe, 0x8b, 0x80,
+	0xc3, 0x8f, 0x45, 0xed, 0x3b, 0xa1, 0xe5, 0x4d, 0x58, 0x75, 0x3a, 0x3c, 0x85, 0xce, 0x76, 0x82,
+	0xf4, 0xa0, 0xb6, 0x64, 0x37, 0xe5, 0xff, 0x33, 0x47, 0xf2, 0x1f, 0x34, 0xae, 0x69, 0xba, 0xaa,
+	0x9e, 0x4e, 0x11, 0x9c, 0xee, 0xbd, 0x72, 0xfa, 0x3f, 0x1c, 0x68, 0x56, 0xda, 0x92, 0x87, 0xd0,
+	0x32, 0x6a, 0xa8, 0x9c, 0xce, 0x2a, 0x79, 0xfe, 0x00, 0xe4, 0x7f, 0x70, 0xaf, 0x70, 0x1a, 0xf1,
+	0xb8, 0xea, 0x72, 0x85, 0xd3, 0x71, 0x6c, 0xa4, 0xa3, 0x69, 0x8a, 0x33, 0x93, 0x28, 0xde, 0x9b,
+	0x67, 0xe3, 0x71, 0x4c, 0x1e, 0x01, 0x98, 0x6d, 0x45, 0x89, 0xc4, 0x55, 0x6e, 0xa5, 0x6a, 0x85,
+	0x2d, 0x83, 0x7c, 0x30, 0x80, 0xf1, 0xb6, 0x4d, 0xdb, 0x6d, 0x14, 0x5a, 0x35, 0x0d, 0x60, 0x1f,
+	0xe9, 0x01, 0x78, 0x02, 0x63, 0x66, 0xba, 0x16, 0x4f, 0xcb, 0x35, 0xe1, 0x38, 0x1e, 0xfd, 0x72,
+	0xc0, 0x3d, 0xc7, 0xe4, 0x02, 0x05, 0xc9, 0xa1, 0x61, 0x5d, 0x40, 0x4e, 0x76, 0x52, 0x71, 0xfb,
+	0x8b, 0x73, 0x38, 0xba, 0x4b, 0x49, 0xe9, 0xa2, 0x7f, 0x48, 0x06, 0x75, 0xe3, 0x2b, 0xf2, 0x62,
+	0xc7, 0xea, 0x8d, 0x23, 0x0f, 0x4f, 0xee, 0x50, 0x51, 0x5d, 0x77, 0xe6, 0x7d, 0x6d, 0x58, 0x7c,
+	0xea, 0xda, 0x9f, 0x97, 0xbf, 0x07, 0x00, 0xcd, 0xda, 0xce, 0x2b, 0x80, 0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
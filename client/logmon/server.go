@@ -5,9 +5,11 @@ package logmon
 
 import (
 	"context"
+	"time"
 
 	"github.com/hashicorp/go-plugin"
 	"github.com/hashicorp/nomad/client/logmon/proto"
+	"github.com/hashicorp/nomad/plugins/logsink"
 )
 
 type logmonServer struct {
@@ -17,13 +19,29 @@ type logmonServer struct {
 
 func (s *logmonServer) Start(ctx context.Context, req *proto.StartRequest) (*proto.StartResponse, error) {
 	cfg := &LogConfig{
-		LogDir:        req.LogDir,
-		StdoutLogFile: req.StdoutFileName,
-		StderrLogFile: req.StderrFileName,
-		MaxFiles:      int(req.MaxFiles),
-		MaxFileSizeMB: int(req.MaxFileSizeMb),
-		StdoutFifo:    req.StdoutFifo,
-		StderrFifo:    req.StderrFifo,
+		LogDir:         req.LogDir,
+		StdoutLogFile:  req.StdoutFileName,
+		StderrLogFile:  req.StderrFileName,
+		MaxFiles:       int(req.MaxFiles),
+		MaxFileSizeMB:  int(req.MaxFileSizeMb),
+		StdoutFifo:     req.StdoutFifo,
+		StderrFifo:     req.StderrFifo,
+		Backpressure:   req.Backpressure,
+		SpillDir:       req.SpillDir,
+		RotateInterval: time.Duration(req.RotateIntervalNs),
+		Compression:    req.Compression,
+		Retention:      time.Duration(req.RetentionNs),
+		Sinks:          logSinksFromProto(req.Sinks),
+	}
+	if req.Task != nil {
+		cfg.Task = &logsink.TaskInfo{
+			Namespace: req.Task.Namespace,
+			JobID:     req.Task.JobId,
+			AllocID:   req.Task.AllocId,
+			TaskGroup: req.Task.TaskGroup,
+			TaskName:  req.Task.TaskName,
+			NodeID:    req.Task.NodeId,
+		}
 	}
 
 	err := s.impl.Start(cfg)
@@ -34,6 +52,24 @@ func (s *logmonServer) Start(ctx context.Context, req *proto.StartRequest) (*pro
 	return resp, nil
 }
 
+func logSinksFromProto(sinks []*proto.LogSink) []*LogSinkConfig {
+	if len(sinks) == 0 {
+		return nil
+	}
+	out := make([]*LogSinkConfig, len(sinks))
+	for i, s := range sinks {
+		out[i] = &LogSinkConfig{
+			Name:       s.Name,
+			ExePath:    s.ExePath,
+			Args:       s.Args,
+			Config:     s.Config,
+			ApiVersion: s.ApiVersion,
+			Options:    s.Options,
+		}
+	}
+	return out
+}
+
 func (s *logmonServer) Stop(ctx context.Context, req *proto.StopRequest) (*proto.StopResponse, error) {
 	return &proto.StopResponse{}, s.impl.Stop()
 }
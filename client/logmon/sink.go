@@ -0,0 +1,371 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package logmon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/logsinks"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/logsink"
+)
+
+const (
+	// sinkQueueSize is the number of log lines buffered for a log sink. Lines
+	// written while the buffer is full are dropped rather than blocking the
+	// task.
+	sinkQueueSize = 8192
+
+	// sinkBatchSize is the maximum number of log lines shipped at once.
+	sinkBatchSize = 512
+
+	// sinkFlushInterval is how often buffered log lines are shipped when
+	// fewer than sinkBatchSize lines were written.
+	sinkFlushInterval = 1 * time.Second
+
+	// sinkShipTimeout is how long a log sink has to ship a batch.
+	sinkShipTimeout = 30 * time.Second
+
+	// sinkShipAttempts is how many times a batch is shipped before it's
+	// dropped.
+	sinkShipAttempts = 3
+
+	// sinkShipBackoff is the wait before retrying to ship a batch, doubled
+	// after each attempt.
+	sinkShipBackoff = 1 * time.Second
+
+	// sinkCloseTimeout is how long the buffered log lines are shipped for
+	// when the task logger is closed.
+	sinkCloseTimeout = 10 * time.Second
+
+	// maxSinkLineSize is the size after which a log line without a newline
+	// is shipped as is.
+	maxSinkLineSize = 64 * 1024
+)
+
+// LogSinkConfig is a log sink plugin that the task's logs are shipped to.
+type LogSinkConfig struct {
+	// Name is the name of the log sink plugin
+	Name string
+
+	// ExePath and Args are the command that launches an external plugin. An
+	// empty ExePath is a plugin built into Nomad.
+	ExePath string
+	Args    []string
+
+	// Config is the msgpack encoded plugin configuration
+	Config []byte
+
+	// ApiVersion is the API version to use with the plugin
+	ApiVersion string
+
+	// Options are the options set by the task for the log sink
+	Options map[string]string
+}
+
+// sinkShipper buffers the log lines of a task and ships them in batches to a
+// log sink plugin.
+type sinkShipper struct {
+	sink    logsink.LogSinkPlugin
+	client  *plugin.Client
+	task    *logsink.TaskInfo
+	options map[string]string
+	logger  hclog.Logger
+
+	entries chan *logsink.LogEntry
+	dropped atomic.Uint64
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	closeCh  chan struct{}
+	doneCh   chan struct{}
+	closeOne sync.Once
+}
+
+// newSinkShipper launches the log sink plugin and starts shipping the log
+// lines enqueued to it.
+func newSinkShipper(cfg *LogSinkConfig, task *logsink.TaskInfo, logger hclog.Logger) (*sinkShipper, error) {
+	logger = logger.With("log_sink", cfg.Name)
+
+	sink, client, err := launchLogSink(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	return startSinkShipper(cfg, sink, client, task, logger), nil
+}
+
+// startSinkShipper starts shipping the log lines enqueued to the launched log
+// sink plugin.
+func startSinkShipper(cfg *LogSinkConfig, sink logsink.LogSinkPlugin, client *plugin.Client,
+	task *logsink.TaskInfo, logger hclog.Logger) *sinkShipper {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &sinkShipper{
+		sink:    sink,
+		client:  client,
+		task:    task,
+		options: cfg.Options,
+		logger:  logger,
+		entries: make(chan *logsink.LogEntry, sinkQueueSize),
+		ctx:     ctx,
+		cancel:  cancel,
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// launchLogSink returns the configured log sink plugin, along with the client
+// of its process if it's an external plugin.
+func launchLogSink(cfg *LogSinkConfig, logger hclog.Logger) (logsink.LogSinkPlugin, *plugin.Client, error) {
+	var sink logsink.LogSinkPlugin
+	var client *plugin.Client
+
+	if cfg.ExePath == "" {
+		var ok bool
+		sink, ok = logsinks.New(cfg.Name, logger)
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown log sink %q", cfg.Name)
+		}
+	} else {
+		client = plugin.NewClient(&plugin.ClientConfig{
+			HandshakeConfig: base.Handshake,
+			Plugins: map[string]plugin.Plugin{
+				base.PluginTypeBase:    &base.PluginBase{},
+				base.PluginTypeLogSink: &logsink.PluginLogSink{},
+			},
+			Cmd:              exec.Command(cfg.ExePath, cfg.Args...),
+			AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+			Logger:           logger,
+		})
+
+		rpcClient, err := client.Client()
+		if err != nil {
+			client.Kill()
+			return nil, nil, fmt.Errorf("failed to launch log sink %q: %v", cfg.Name, err)
+		}
+		raw, err := rpcClient.Dispense(base.PluginTypeLogSink)
+		if err != nil {
+			client.Kill()
+			return nil, nil, fmt.Errorf("failed to dispense log sink %q: %v", cfg.Name, err)
+		}
+		sink = raw.(logsink.LogSinkPlugin)
+	}
+
+	err := sink.SetConfig(&base.Config{
+		ApiVersion:   cfg.ApiVersion,
+		PluginConfig: cfg.Config,
+	})
+	if err != nil {
+		if client != nil {
+			client.Kill()
+		}
+		return nil, nil, fmt.Errorf("failed to configure log sink %q: %v", cfg.Name, err)
+	}
+	return sink, client, nil
+}
+
+// enqueue buffers a log line to be shipped, or drops it if the buffer is full
+// so the task is never blocked by a slow or unavailable log sink.
+func (s *sinkShipper) enqueue(entry *logsink.LogEntry) {
+	select {
+	case s.entries <- entry:
+	default:
+		if s.dropped.Add(1) == 1 {
+			s.logger.Warn("log sink is not keeping up, dropping log lines")
+		}
+	}
+}
+
+func (s *sinkShipper) run() {
+	defer close(s.doneCh)
+
+	ticker := time.NewTicker(sinkFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*logsink.LogEntry, 0, sinkBatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			s.ship(batch)
+			batch = make([]*logsink.LogEntry, 0, sinkBatchSize)
+		}
+	}
+
+	for {
+		select {
+		case entry := <-s.entries:
+			batch = append(batch, entry)
+			if len(batch) >= sinkBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-s.closeCh:
+			// Ship the lines left in the buffer before exiting
+			for {
+				select {
+				case entry := <-s.entries:
+					batch = append(batch, entry)
+					if len(batch) >= sinkBatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// ship ships a batch of log lines, retrying with a backoff on failure. The
+// batch is dropped once all attempts failed.
+func (s *sinkShipper) ship(entries []*logsink.LogEntry) {
+	batch := &logsink.LogBatch{
+		Task:    s.task,
+		Options: s.options,
+		Entries: entries,
+	}
+
+	backoff := sinkShipBackoff
+	for attempt := 1; ; attempt++ {
+		if s.ctx.Err() != nil {
+			s.dropped.Add(uint64(len(entries)))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(s.ctx, sinkShipTimeout)
+		err := s.sink.ShipLogs(ctx, batch)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		if attempt >= sinkShipAttempts {
+			s.logger.Warn("failed to ship log lines, dropping them",
+				"lines", len(entries), "error", err)
+			s.dropped.Add(uint64(len(entries)))
+			return
+		}
+		s.logger.Debug("failed to ship log lines, retrying",
+			"attempt", attempt, "error", err)
+
+		select {
+		case <-s.ctx.Done():
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// Close ships the buffered log lines, giving up after sinkCloseTimeout, and
+// stops the log sink plugin.
+func (s *sinkShipper) Close() {
+	s.closeOne.Do(func() {
+		close(s.closeCh)
+
+		select {
+		case <-s.doneCh:
+		case <-time.After(sinkCloseTimeout):
+			s.logger.Warn("timed out shipping log lines")
+		}
+		s.cancel()
+		<-s.doneCh
+
+		if dropped := s.dropped.Load(); dropped > 0 {
+			s.logger.Warn("dropped log lines", "lines", dropped)
+		}
+		if s.client != nil {
+			s.client.Kill()
+		}
+	})
+}
+
+// sinkWriter is the writer of one of the task's log files that also tees
+// complete lines to the log sinks of the task. Writing to the log sinks never
+// blocks, so the backpressure policy of the file is unaffected.
+type sinkWriter struct {
+	io.WriteCloser
+
+	stream   string
+	shippers []*sinkShipper
+
+	// partial is the last line written, until its newline is written
+	partial []byte
+	lock    sync.Mutex
+}
+
+func newSinkWriter(w io.WriteCloser, stream string, shippers []*sinkShipper) io.WriteCloser {
+	if len(shippers) == 0 {
+		return w
+	}
+	return &sinkWriter{
+		WriteCloser: w,
+		stream:      stream,
+		shippers:    shippers,
+	}
+}
+
+func (w *sinkWriter) Write(p []byte) (int, error) {
+	w.tee(p)
+	return w.WriteCloser.Write(p)
+}
+
+func (w *sinkWriter) tee(p []byte) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	now := time.Now()
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.partial = append(w.partial, p...)
+			if len(w.partial) >= maxSinkLineSize {
+				w.enqueue(now, w.partial)
+				w.partial = nil
+			}
+			return
+		}
+
+		line := p[:i]
+		if len(w.partial) > 0 {
+			line = append(w.partial, line...)
+			w.partial = nil
+		}
+		w.enqueue(now, line)
+		p = p[i+1:]
+	}
+}
+
+func (w *sinkWriter) enqueue(ts time.Time, line []byte) {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	for _, s := range w.shippers {
+		s.enqueue(&logsink.LogEntry{
+			Timestamp: ts,
+			Stream:    w.stream,
+			Line:      bytes.Clone(line),
+		})
+	}
+}
+
+func (w *sinkWriter) Close() error {
+	w.lock.Lock()
+	if len(w.partial) > 0 {
+		w.enqueue(time.Now(), w.partial)
+		w.partial = nil
+	}
+	w.lock.Unlock()
+
+	return w.WriteCloser.Close()
+}
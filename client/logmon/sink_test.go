@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package logmon
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/plugins/logsink"
+	"github.com/shoenig/test/must"
+)
+
+// nopWriteCloser discards writes, as a log file rotator would write them.
+type nopWriteCloser struct {
+	closed bool
+}
+
+func (w *nopWriteCloser) Write(p []byte) (int, error) { return len(p), nil }
+func (w *nopWriteCloser) Close() error                { w.closed = true; return nil }
+
+func TestSinkWriter_ShipsLines(t *testing.T) {
+	ci.Parallel(t)
+
+	var lock sync.Mutex
+	var lines []string
+	var shipped *logsink.LogBatch
+	sink := &logsink.MockLogSinkPlugin{
+		ShipLogsF: func(_ context.Context, batch *logsink.LogBatch) error {
+			lock.Lock()
+			defer lock.Unlock()
+			shipped = batch
+			for _, e := range batch.Entries {
+				must.Eq(t, logsink.StreamStderr, e.Stream)
+				lines = append(lines, string(e.Line))
+			}
+			return nil
+		},
+	}
+
+	task := &logsink.TaskInfo{JobID: "example", TaskName: "web"}
+	cfg := &LogSinkConfig{Name: "mock", Options: map[string]string{"team": "storage"}}
+	shipper := startSinkShipper(cfg, sink, nil, task, testlog.HCLogger(t))
+
+	file := &nopWriteCloser{}
+	w := newSinkWriter(file, logsink.StreamStderr, []*sinkShipper{shipper})
+
+	for _, p := range []string{"first\nsec", "ond\r\n", "\nlast"} {
+		n, err := w.Write([]byte(p))
+		must.NoError(t, err)
+		must.Eq(t, len(p), n)
+	}
+	must.NoError(t, w.Close())
+	must.True(t, file.closed)
+	shipper.Close()
+
+	lock.Lock()
+	defer lock.Unlock()
+	must.Eq(t, []string{"first", "second", "", "last"}, lines)
+	must.Eq(t, task, shipped.Task)
+	must.Eq(t, cfg.Options, shipped.Options)
+}
+
+func TestSinkShipper_DropsWhenFull(t *testing.T) {
+	ci.Parallel(t)
+
+	// Block shipping until the buffer of the shipper is full
+	unblock := make(chan struct{})
+	sink := &logsink.MockLogSinkPlugin{
+		ShipLogsF: func(ctx context.Context, _ *logsink.LogBatch) error {
+			select {
+			case <-unblock:
+				return errors.New("unavailable")
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+
+	shipper := startSinkShipper(&LogSinkConfig{Name: "mock"}, sink, nil, nil, testlog.HCLogger(t))
+	w := newSinkWriter(&nopWriteCloser{}, logsink.StreamStdout, []*sinkShipper{shipper})
+
+	// Writes never block, even though nothing can be shipped
+	line := []byte("line\n")
+	for i := 0; i < sinkQueueSize+2*sinkBatchSize; i++ {
+		_, err := w.Write(line)
+		must.NoError(t, err)
+	}
+	must.Positive(t, shipper.dropped.Load())
+
+	close(unblock)
+	must.NoError(t, w.Close())
+	shipper.cancel()
+	shipper.Close()
+}
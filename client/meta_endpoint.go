@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/armon/go-metrics"
+	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"golang.org/x/exp/maps"
 )
@@ -35,10 +36,17 @@ func (n *NodeMeta) Apply(args *structs.NodeMetaApplyRequest, reply *structs.Node
 		return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
 	}
 
-	var stateErr error
+	var stateErr, casErr error
 	var dyn map[string]*string
+	var index uint64
 
 	newNode := n.c.UpdateNode(func(node *structs.Node) {
+		// Compare-and-set must be checked under the same lock as the update
+		// to be atomic.
+		if casErr = args.CheckExpected(node.Meta); casErr != nil {
+			return
+		}
+
 		// First update the Client's state store. This must be done
 		// atomically with updating the metadata inmemory to avoid
 		// bad interleaving between concurrent updates.
@@ -72,8 +80,17 @@ func (n *NodeMeta) Apply(args *structs.NodeMetaApplyRequest, reply *structs.Node
 
 			node.Meta[k] = *v
 		}
+
+		// Notify watchers
+		n.c.metaIndex++
+		index = n.c.metaIndex
+		close(n.c.metaUpdateCh)
+		n.c.metaUpdateCh = make(chan struct{})
 	})
 
+	if casErr != nil {
+		return structs.NewErrRPCCoded(http.StatusConflict, casErr.Error())
+	}
 	if stateErr != nil {
 		return stateErr
 	}
@@ -84,9 +101,13 @@ func (n *NodeMeta) Apply(args *structs.NodeMetaApplyRequest, reply *structs.Node
 	reply.Meta = newNode.Meta
 	reply.Dynamic = dyn
 	reply.Static = n.c.metaStatic
+	reply.Index = index
 	return nil
 }
 
+// Read returns the node metadata. If MinQueryIndex is set the read blocks
+// until the metadata index changes, allowing external controllers to watch
+// for metadata updates.
 func (n *NodeMeta) Read(args *structs.NodeSpecificRequest, reply *structs.NodeMetaResponse) error {
 	defer metrics.MeasureSince([]string{"client", "node_meta", "read"}, time.Now())
 
@@ -97,6 +118,10 @@ func (n *NodeMeta) Read(args *structs.NodeSpecificRequest, reply *structs.NodeMe
 		return structs.ErrPermissionDenied
 	}
 
+	if args.MinQueryIndex > 0 {
+		n.waitForIndexChange(args.MinQueryIndex, args.TimeToBlock())
+	}
+
 	// Must acquire configLock to ensure reads aren't interleaved with
 	// writes
 	n.c.configLock.Lock()
@@ -105,5 +130,33 @@ func (n *NodeMeta) Read(args *structs.NodeSpecificRequest, reply *structs.NodeMe
 	reply.Meta = n.c.config.Node.Meta
 	reply.Dynamic = maps.Clone(n.c.metaDynamic)
 	reply.Static = n.c.metaStatic
+	reply.Index = n.c.metaIndex
 	return nil
 }
+
+// waitForIndexChange blocks until the metadata index differs from index, the
+// timeout is reached, or the client shuts down. Since the index restarts along
+// with the client, any index other than the current one is considered a
+// change rather than only greater ones.
+func (n *NodeMeta) waitForIndexChange(index uint64, timeout time.Duration) {
+	timer, timerStop := helper.NewSafeTimer(timeout)
+	defer timerStop()
+
+	for {
+		n.c.configLock.Lock()
+		current, updateCh := n.c.metaIndex, n.c.metaUpdateCh
+		n.c.configLock.Unlock()
+
+		if current != index {
+			return
+		}
+
+		select {
+		case <-updateCh:
+		case <-timer.C:
+			return
+		case <-n.c.shutdownCh:
+			return
+		}
+	}
+}
@@ -5,6 +5,7 @@ package client
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/ci"
@@ -181,3 +182,90 @@ func TestNodeMeta_unset(t *testing.T) {
 	must.MapNotContainsKey(t, resp.Dynamic, "dynamic_meta")
 	must.MapNotContainsKey(t, resp.Meta, "dynamic_meta")
 }
+
+func TestNodeMeta_CompareAndSet(t *testing.T) {
+	ci.Parallel(t)
+
+	s, cleanupS := nomad.TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanup()
+
+	// Set a key only if it is unset.
+	applyReq := &structs.NodeMetaApplyRequest{
+		NodeID:   c1.NodeID(),
+		Meta:     map[string]*string{"maintenance": pointer.Of("a")},
+		Expected: map[string]*string{"maintenance": nil},
+	}
+	var resp structs.NodeMetaResponse
+	err := c1.ClientRPC("NodeMeta.Apply", applyReq, &resp)
+	must.NoError(t, err)
+	must.Eq(t, "a", resp.Meta["maintenance"])
+	index := resp.Index
+
+	// A concurrent controller fails to set the same key.
+	applyReq.Meta["maintenance"] = pointer.Of("b")
+	err = c1.ClientRPC("NodeMeta.Apply", applyReq, &resp)
+	must.ErrorContains(t, err, `"maintenance" is set to "a"`)
+
+	// Nothing was changed.
+	readReq := &structs.NodeSpecificRequest{NodeID: c1.NodeID()}
+	err = c1.ClientRPC("NodeMeta.Read", readReq, &resp)
+	must.NoError(t, err)
+	must.Eq(t, "a", resp.Meta["maintenance"])
+	must.Eq(t, index, resp.Index)
+
+	// The owner can unset the key.
+	applyReq.Meta["maintenance"] = nil
+	applyReq.Expected["maintenance"] = pointer.Of("a")
+	err = c1.ClientRPC("NodeMeta.Apply", applyReq, &resp)
+	must.NoError(t, err)
+	must.MapNotContainsKey(t, resp.Meta, "maintenance")
+	must.Greater(t, index, resp.Index)
+}
+
+func TestNodeMeta_BlockingRead(t *testing.T) {
+	ci.Parallel(t)
+
+	s, cleanupS := nomad.TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	c1, cleanup := TestClient(t, func(c *config.Config) {
+		c.Servers = []string{s.GetConfig().RPCAddr.String()}
+	})
+	defer cleanup()
+
+	var resp structs.NodeMetaResponse
+	readReq := &structs.NodeSpecificRequest{NodeID: c1.NodeID()}
+	must.NoError(t, c1.ClientRPC("NodeMeta.Read", readReq, &resp))
+	index := resp.Index
+
+	// A blocking read without changes times out.
+	readReq.MinQueryIndex = index
+	readReq.MaxQueryTime = 50 * time.Millisecond
+	must.NoError(t, c1.ClientRPC("NodeMeta.Read", readReq, &resp))
+	must.Eq(t, index, resp.Index)
+
+	// A blocking read returns as soon as the metadata is updated.
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		applyReq := &structs.NodeMetaApplyRequest{
+			NodeID: c1.NodeID(),
+			Meta:   map[string]*string{"foo": pointer.Of("bar")},
+		}
+		var applyResp structs.NodeMetaResponse
+		c1.ClientRPC("NodeMeta.Apply", applyReq, &applyResp)
+	}()
+
+	readReq.MaxQueryTime = 10 * time.Second
+	start := time.Now()
+	must.NoError(t, c1.ClientRPC("NodeMeta.Read", readReq, &resp))
+	must.Less(t, 10*time.Second, time.Since(start))
+	must.Greater(t, index, resp.Index)
+	must.Eq(t, "bar", resp.Meta["foo"])
+}
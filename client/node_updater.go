@@ -6,9 +6,12 @@ package client
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/hashicorp/nomad/client/allocrunner/interfaces"
 	"github.com/hashicorp/nomad/client/devicemanager"
 	"github.com/hashicorp/nomad/client/pluginmanager/csimanager"
 	"github.com/hashicorp/nomad/client/pluginmanager/drivermanager"
@@ -314,6 +317,57 @@ func (c *Client) updateNodeFromDevicesLocked(devices []*structs.NodeDeviceResour
 	return false
 }
 
+// devicesVanished fails the tasks of the running allocations that were
+// allocated devices that have been removed from the node, so the allocations
+// are rescheduled.
+func (c *Client) devicesVanished(devices []*structs.AllocatedDeviceResource) {
+	for _, ar := range c.getAllocRunners() {
+		alloc := ar.Alloc()
+		if alloc.TerminalStatus() || alloc.AllocatedResources == nil {
+			continue
+		}
+
+		for taskName, tr := range alloc.AllocatedResources.Tasks {
+			ids := vanishedDeviceIDs(tr.Devices, devices)
+			if len(ids) == 0 {
+				continue
+			}
+
+			event := structs.NewTaskEvent(structs.TaskDeviceVanished).
+				SetMessage(fmt.Sprintf("Allocated devices %s were removed from the node", strings.Join(ids, ", "))).
+				SetFailsTask()
+
+			c.logger.Warn("killing task holding removed devices",
+				"alloc_id", alloc.ID, "task", taskName, "devices", ids)
+			go func(ar interfaces.AllocRunner, taskName string) {
+				if err := ar.KillTask(taskName, event); err != nil {
+					c.logger.Error("failed to kill task holding removed devices",
+						"alloc_id", ar.Alloc().ID, "task", taskName, "error", err)
+				}
+			}(ar, taskName)
+		}
+	}
+}
+
+// vanishedDeviceIDs returns the IDs of the allocated devices that are part of
+// the vanished devices.
+func vanishedDeviceIDs(allocated, vanished []*structs.AllocatedDeviceResource) []string {
+	var ids []string
+	for _, a := range allocated {
+		for _, v := range vanished {
+			if !a.ID().Equal(v.ID()) {
+				continue
+			}
+			for _, id := range a.DeviceIDs {
+				if slices.Contains(v.DeviceIDs, id) {
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	return ids
+}
+
 // batchNodeUpdates allows for batching multiple Node updates from fingerprinting.
 // Once ready, the batches can be flushed and toggled to stop batching and forward
 // all updates to a configured callback to be performed incrementally
@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/hashicorp/nomad/helper/tracing"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// placementSpans traces the placement of allocations on the client, from when
+// the client receives an allocation until it's running or terminal. The spans
+// continue the trace of the plan that placed the allocation.
+type placementSpans struct {
+	tracer *tracing.Tracer
+
+	// spans holds the span of the allocations that aren't running yet, by
+	// alloc ID.
+	spans map[string]*tracing.Span
+	l     sync.Mutex
+}
+
+func newPlacementSpans(tracer *tracing.Tracer) *placementSpans {
+	return &placementSpans{
+		tracer: tracer,
+		spans:  make(map[string]*tracing.Span),
+	}
+}
+
+// start starts the span of an allocation received by the client.
+func (p *placementSpans) start(alloc *structs.Allocation) {
+	if p == nil || p.tracer == nil {
+		return
+	}
+
+	span := p.tracer.Start("client.alloc_placement", alloc.TraceParent,
+		tracing.String("nomad.namespace", alloc.Namespace),
+		tracing.String("nomad.job.id", alloc.JobID),
+		tracing.String("nomad.alloc.id", alloc.ID),
+		tracing.String("nomad.eval.id", alloc.EvalID),
+		tracing.String("nomad.task_group", alloc.TaskGroup),
+		tracing.String("nomad.node.id", alloc.NodeID),
+	)
+
+	p.l.Lock()
+	defer p.l.Unlock()
+	p.spans[alloc.ID] = span
+}
+
+// update ends the span of the allocation once it's running or terminal. Allocs
+// that fail before running end their span with an error.
+func (p *placementSpans) update(alloc *structs.Allocation) {
+	if p == nil || p.tracer == nil {
+		return
+	}
+	if alloc.ClientStatus != structs.AllocClientStatusRunning && !alloc.ClientTerminalStatus() {
+		return
+	}
+
+	p.l.Lock()
+	span, ok := p.spans[alloc.ID]
+	delete(p.spans, alloc.ID)
+	p.l.Unlock()
+	if !ok {
+		return
+	}
+
+	var err error
+	if alloc.ClientStatus == structs.AllocClientStatusFailed {
+		err = errors.New(alloc.ClientDescription)
+	}
+	span.SetAttributes(tracing.String("nomad.alloc.client_status", alloc.ClientStatus))
+	span.End(err)
+}
+
+// shutdown ends the spans of the allocations that aren't running yet and
+// exports the buffered spans.
+func (p *placementSpans) shutdown() {
+	if p == nil || p.tracer == nil {
+		return
+	}
+
+	p.l.Lock()
+	for id, span := range p.spans {
+		span.End(errors.New("client shut down"))
+		delete(p.spans, id)
+	}
+	p.l.Unlock()
+
+	p.tracer.Shutdown()
+}
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/otlp"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/tracing"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/shoenig/test/must"
+)
+
+func TestPlacementSpans(t *testing.T) {
+	ci.Parallel(t)
+
+	reqCh := make(chan *otlp.ExportTraceServiceRequest, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req otlp.ExportTraceServiceRequest
+		must.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqCh <- &req
+	}))
+	defer collector.Close()
+
+	tracer, err := tracing.NewTracer(testlog.HCLogger(t), &config.TracingConfig{
+		Enabled:        pointer.Of(true),
+		Address:        collector.URL,
+		ExportInterval: time.Hour,
+	}, nil)
+	must.NoError(t, err)
+	p := newPlacementSpans(tracer)
+
+	running := mock.Alloc()
+	running.TraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	failed := mock.Alloc()
+	failed.TraceParent = running.TraceParent
+	pending := mock.Alloc()
+	pending.TraceParent = running.TraceParent
+	for _, alloc := range []*structs.Allocation{running, failed, pending} {
+		alloc.ClientStatus = structs.AllocClientStatusPending
+		p.start(alloc)
+		p.update(alloc)
+	}
+	must.MapLen(t, 3, p.spans)
+
+	running.ClientStatus = structs.AllocClientStatusRunning
+	p.update(running)
+	failed.ClientStatus = structs.AllocClientStatusFailed
+	failed.ClientDescription = "Failed tasks"
+	p.update(failed)
+	must.MapLen(t, 1, p.spans)
+
+	// Shutting down ends the spans of allocs that aren't running yet.
+	p.shutdown()
+	must.MapEmpty(t, p.spans)
+
+	var req *otlp.ExportTraceServiceRequest
+	select {
+	case req = <-reqCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for export")
+	}
+
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	must.Len(t, 3, spans)
+	for _, span := range spans {
+		must.Eq(t, "client.alloc_placement", span.Name)
+		must.Eq(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID)
+		must.Eq(t, "00f067aa0ba902b7", span.ParentSpanID)
+	}
+	must.Nil(t, spans[0].Status)
+	must.Eq(t, otlp.StatusCodeError, spans[1].Status.Code)
+	must.Eq(t, "Failed tasks", spans[1].Status.Message)
+	must.Eq(t, "client shut down", spans[2].Status.Message)
+}
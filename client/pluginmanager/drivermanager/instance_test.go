@@ -40,6 +40,11 @@ func (m *mockedCatalog) Catalog() map[string][]*base.PluginInfoResponse {
 	}
 }
 
+func (m *mockedCatalog) LaunchConfig(name, pluginType string) (*loader.PluginLaunchConfig, error) {
+	args := m.Called(name, pluginType)
+	return nil, args.Error(0)
+}
+
 func (m *mockedCatalog) resetMock() {
 	m.ExpectedCalls = []*mock.Call{}
 	m.Calls = []mock.Call{}
@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// checkDynamicPortRanges emits a node event for each dynamic port range of the
+// client that overlaps the ephemeral port range of the operating system. The
+// kernel assigns the local port of outbound connections from the ephemeral
+// range, so ports allocated to tasks from an overlapping range may already be
+// in use.
+func (c *Client) checkDynamicPortRanges() {
+	ephemeralMin, ephemeralMax, ok := ephemeralPortRange()
+	if !ok {
+		return
+	}
+
+	for _, event := range dynamicPortRangeConflicts(c.GetConfig(), ephemeralMin, ephemeralMax) {
+		c.logger.Warn("dynamic port range overlaps the ephemeral port range",
+			"host_network", event.Details["host_network"],
+			"dynamic_ports", event.Details["dynamic_ports"],
+			"ephemeral_ports", event.Details["ephemeral_ports"])
+		c.triggerNodeEvent(event)
+	}
+}
+
+// dynamicPortRangeConflicts returns a node event for the client's dynamic port
+// range and each host network's dynamic port range that overlaps the given
+// ephemeral port range.
+func dynamicPortRangeConflicts(conf *config.Config, ephemeralMin, ephemeralMax int) []*structs.NodeEvent {
+	var events []*structs.NodeEvent
+	check := func(hostNetwork string, minPort, maxPort int) {
+		if maxPort < ephemeralMin || minPort > ephemeralMax {
+			return
+		}
+
+		event := structs.NewNodeEvent().
+			SetSubsystem(structs.NodeEventSubsystemNetwork).
+			SetMessage("Dynamic port range overlaps the ephemeral port range").
+			AddDetail("dynamic_ports", fmt.Sprintf("%d-%d", minPort, maxPort)).
+			AddDetail("ephemeral_ports", fmt.Sprintf("%d-%d", ephemeralMin, ephemeralMax))
+		if hostNetwork != "" {
+			event.AddDetail("host_network", hostNetwork)
+		}
+		events = append(events, event)
+	}
+
+	check("", conf.MinDynamicPort, conf.MaxDynamicPort)
+
+	names := make([]string, 0, len(conf.HostNetworks))
+	for name := range conf.HostNetworks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		hn := conf.HostNetworks[name]
+		if hn.MinDynamicPort == 0 && hn.MaxDynamicPort == 0 {
+			// The host network uses the client's range, which was checked
+			continue
+		}
+
+		minPort, maxPort := conf.MinDynamicPort, conf.MaxDynamicPort
+		if hn.MinDynamicPort != 0 {
+			minPort = hn.MinDynamicPort
+		}
+		if hn.MaxDynamicPort != 0 {
+			maxPort = hn.MaxDynamicPort
+		}
+		check(name, minPort, maxPort)
+	}
+
+	return events
+}
+
+// parsePortRange parses a port range formatted as "<min> <max>".
+func parsePortRange(s string) (int, int, bool) {
+	var minPort, maxPort string
+	if _, err := fmt.Sscan(s, &minPort, &maxPort); err != nil {
+		return 0, 0, false
+	}
+
+	minValue, err := strconv.Atoi(minPort)
+	if err != nil {
+		return 0, 0, false
+	}
+	maxValue, err := strconv.Atoi(maxPort)
+	if err != nil || minValue > maxValue {
+		return 0, 0, false
+	}
+	return minValue, maxValue, true
+}
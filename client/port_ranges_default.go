@@ -0,0 +1,12 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !linux
+
+package client
+
+// ephemeralPortRange returns the ephemeral port range of the operating
+// system, which is only determined on Linux.
+func ephemeralPortRange() (int, int, bool) {
+	return 0, 0, false
+}
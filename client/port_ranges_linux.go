@@ -0,0 +1,20 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build linux
+
+package client
+
+import (
+	"os"
+)
+
+// ephemeralPortRange returns the ephemeral port range of the operating
+// system, and false if it couldn't be determined.
+func ephemeralPortRange() (int, int, bool) {
+	b, err := os.ReadFile("/proc/sys/net/ipv4/ip_local_port_range")
+	if err != nil {
+		return 0, 0, false
+	}
+	return parsePortRange(string(b))
+}
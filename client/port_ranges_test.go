@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestClient_dynamicPortRangeConflicts(t *testing.T) {
+	ci.Parallel(t)
+
+	conf := config.DefaultConfig()
+	conf.MinDynamicPort = 20000
+	conf.MaxDynamicPort = 32000
+	conf.HostNetworks = map[string]*structs.ClientHostNetworkConfig{
+		"default": {Name: "default"},
+		"private": {Name: "private", MinDynamicPort: 10000, MaxDynamicPort: 15000},
+		"public":  {Name: "public", MaxDynamicPort: 40000},
+	}
+
+	// Only the public network extends into the ephemeral range
+	events := dynamicPortRangeConflicts(conf, 32768, 60999)
+	must.Len(t, 1, events)
+	must.Eq(t, structs.NodeEventSubsystemNetwork, events[0].Subsystem)
+	must.Eq(t, map[string]string{
+		"host_network":    "public",
+		"dynamic_ports":   "20000-40000",
+		"ephemeral_ports": "32768-60999",
+	}, events[0].Details)
+
+	// The node's range and every host network using it overlap
+	events = dynamicPortRangeConflicts(conf, 30000, 60999)
+	must.Len(t, 2, events)
+	must.MapNotContainsKey(t, events[0].Details, "host_network")
+	must.Eq(t, "public", events[1].Details["host_network"])
+
+	must.Len(t, 0, dynamicPortRangeConflicts(conf, 50000, 60999))
+}
+
+func TestClient_parsePortRange(t *testing.T) {
+	ci.Parallel(t)
+
+	minPort, maxPort, ok := parsePortRange("32768\t60999\n")
+	must.True(t, ok)
+	must.Eq(t, 32768, minPort)
+	must.Eq(t, 60999, maxPort)
+
+	for _, s := range []string{"", "32768", "a b", "60999 32768"} {
+		_, _, ok := parsePortRange(s)
+		must.False(t, ok, must.Sprintf("expected %q to be invalid", s))
+	}
+}
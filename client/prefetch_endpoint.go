@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"time"
+
+	"github.com/armon/go-metrics"
+	cinterfaces "github.com/hashicorp/nomad/client/interfaces"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+// Prefetch endpoint is used for pulling the images and artifacts of jobs
+// ahead of the placement of their allocations on the client.
+type Prefetch struct {
+	c *Client
+}
+
+func newPrefetchEndpoint(c *Client) *Prefetch {
+	return &Prefetch{c: c}
+}
+
+// Job starts pulling the images and artifacts of a job in the background. It
+// is only called by the servers when the job is registered.
+func (p *Prefetch) Job(args *structs.PrefetchJobRequest, reply *structs.GenericResponse) error {
+	defer metrics.MeasureSince([]string{"client", "prefetch", "job"}, time.Now())
+
+	go p.c.prefetchJob(args)
+	return nil
+}
+
+// prefetchJob pulls the images of the job with the drivers able to prefetch
+// them, and downloads its artifacts into the artifact cache. Failures are only
+// logged since the tasks pull what they need when they start anyway.
+func (c *Client) prefetchJob(args *structs.PrefetchJobRequest) {
+	logger := c.logger.With("job_id", args.JobID, "namespace", args.RequestNamespace())
+
+	for _, image := range args.Images {
+		driver, err := c.drivermanager.Dispense(image.Driver)
+		if err != nil {
+			logger.Debug("skipping image prefetch", "driver", image.Driver, "error", err)
+			continue
+		}
+		prefetcher, ok := driver.(drivers.ImagePrefetcher)
+		if !ok {
+			continue
+		}
+
+		start := time.Now()
+		if err := prefetcher.PrefetchImage(image.Image); err != nil {
+			logger.Warn("failed to prefetch image", "driver", image.Driver, "image", image.Image, "error", err)
+			continue
+		}
+		logger.Debug("prefetched image", "driver", image.Driver, "image", image.Image, "duration", time.Since(start))
+	}
+
+	prefetcher, ok := c.getter.(cinterfaces.ArtifactPrefetcher)
+	if !ok {
+		return
+	}
+	for _, artifact := range args.Artifacts {
+		cached, err := prefetcher.Prefetch(artifact)
+		if err != nil {
+			logger.Warn("failed to prefetch artifact", "source", artifact.GetterSource, "error", err)
+			continue
+		}
+		if cached {
+			logger.Debug("prefetched artifact", "source", artifact.GetterSource)
+		}
+	}
+}
@@ -28,6 +28,8 @@ type rpcEndpoints struct {
 	Allocations *Allocations
 	Agent       *Agent
 	NodeMeta    *NodeMeta
+	HostVolume  *HostVolume
+	Prefetch    *Prefetch
 }
 
 // ClientRPC is used to make a local, client only RPC call
@@ -58,7 +60,23 @@ func (c *Client) RPC(method string, args any, reply any) error {
 	case <-c.shutdownCh:
 		return nil
 	}
-	return c.rpc(method, args, reply)
+
+	conf := c.GetConfig()
+	if conf.FlightRecorder == nil {
+		return c.rpc(method, args, reply)
+	}
+
+	// Retries may turn blocking queries into non-blocking ones, so check
+	// whether the query blocks before making it.
+	var blocking bool
+	if info, ok := args.(structs.RPCInfo); ok {
+		blocking = info.TimeToBlock() > 0
+	}
+
+	start := time.Now()
+	err := c.rpc(method, args, reply)
+	conf.FlightRecorder.RecordRPC(method, start, blocking, err)
+	return err
 }
 
 // UnauthenticatedRPC special-cases the Node.Register RPC call, forwarding the
@@ -293,6 +311,8 @@ func (c *Client) setupClientRpc(rpcs map[string]interface{}) {
 		c.endpoints.Allocations = NewAllocationsEndpoint(c)
 		c.endpoints.Agent = NewAgentEndpoint(c)
 		c.endpoints.NodeMeta = newNodeMetaEndpoint(c)
+		c.endpoints.HostVolume = newHostVolumeEndpoint(c)
+		c.endpoints.Prefetch = newPrefetchEndpoint(c)
 		c.setupClientRpcServer(c.rpcServer)
 	}
 
@@ -308,6 +328,8 @@ func (c *Client) setupClientRpcServer(server *rpc.Server) {
 	server.Register(c.endpoints.Allocations)
 	server.Register(c.endpoints.Agent)
 	server.Register(c.endpoints.NodeMeta)
+	server.Register(c.endpoints.HostVolume)
+	server.Register(c.endpoints.Prefetch)
 }
 
 // rpcConnListener is a long lived function that listens for new connections
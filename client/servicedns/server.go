@@ -0,0 +1,290 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package servicedns implements a DNS server answering queries for the
+// services registered with Nomad native service discovery.
+//
+// Services are queried by name and namespace, as <service>.<namespace>.<domain>,
+// with the default namespace used for <service>.<domain>. A and AAAA queries
+// return the addresses of the healthy instances of the service, and SRV queries
+// return their ports. The targets of the SRV records are of the form
+// <alloc_id>.<service>.<namespace>.<domain>, which resolve to the addresses of
+// the service instances of the allocation.
+package servicedns
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/miekg/dns"
+)
+
+// RPCFunc is the function used to send RPCs to the servers.
+type RPCFunc func(method string, args, reply any) error
+
+// Server is a DNS server serving the records of Nomad native service
+// discovery. Services are looked up from the servers for each query.
+type Server struct {
+	logger hclog.Logger
+	config *config.ServiceDNSConfig
+	region string
+	rpc    RPCFunc
+
+	// suffix is the fully qualified domain of the records, with a leading
+	// dot.
+	suffix string
+
+	l         sync.Mutex
+	udpServer *dns.Server
+	tcpServer *dns.Server
+}
+
+// NewServer returns a DNS server using the given configuration. The server
+// doesn't listen until Start is called.
+func NewServer(logger hclog.Logger, conf *config.ServiceDNSConfig, region string, rpc RPCFunc) *Server {
+	return &Server{
+		logger: logger.Named("service_dns"),
+		config: conf,
+		region: region,
+		rpc:    rpc,
+		suffix: "." + strings.ToLower(dns.Fqdn(conf.Domain)),
+	}
+}
+
+// Start binds the UDP and TCP listeners of the server and starts serving
+// queries in the background.
+func (s *Server) Start() error {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	udpConn, err := net.ListenPacket("udp", s.config.Addr)
+	if err != nil {
+		return err
+	}
+	tcpListener, err := net.Listen("tcp", s.config.Addr)
+	if err != nil {
+		udpConn.Close()
+		return err
+	}
+
+	s.udpServer = &dns.Server{PacketConn: udpConn, Handler: s}
+	s.tcpServer = &dns.Server{Listener: tcpListener, Handler: s}
+
+	for _, srv := range []*dns.Server{s.udpServer, s.tcpServer} {
+		go func(srv *dns.Server) {
+			if err := srv.ActivateAndServe(); err != nil {
+				s.logger.Error("DNS server stopped", "error", err)
+			}
+		}(srv)
+	}
+
+	s.logger.Info("serving native service discovery records",
+		"address", s.config.Addr, "domain", s.config.Domain)
+	return nil
+}
+
+// Shutdown stops the server.
+func (s *Server) Shutdown() {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	for _, srv := range []*dns.Server{s.udpServer, s.tcpServer} {
+		if srv != nil {
+			if err := srv.Shutdown(); err != nil {
+				s.logger.Warn("failed to shutdown DNS server", "error", err)
+			}
+		}
+	}
+	s.udpServer, s.tcpServer = nil, nil
+}
+
+// ServeDNS answers a DNS query. It implements dns.Handler.
+func (s *Server) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(req)
+	m.Authoritative = true
+
+	if len(req.Question) != 1 {
+		m.SetRcode(req, dns.RcodeFormatError)
+		s.write(w, req, m)
+		return
+	}
+	q := req.Question[0]
+
+	name, ok := s.parseName(q.Name)
+	if !ok {
+		m.Authoritative = false
+		m.SetRcode(req, dns.RcodeRefused)
+		s.write(w, req, m)
+		return
+	}
+
+	services, err := s.lookup(name)
+	switch {
+	case structs.IsErrPermissionDenied(err):
+		m.SetRcode(req, dns.RcodeRefused)
+	case err != nil:
+		s.logger.Warn("failed to look up service", "service", name.service,
+			"namespace", name.namespace, "error", err)
+		m.SetRcode(req, dns.RcodeServerFailure)
+	case len(services) == 0:
+		m.SetRcode(req, dns.RcodeNameError)
+	default:
+		s.answer(m, q, name, services)
+	}
+
+	s.write(w, req, m)
+}
+
+// queryName is a parsed DNS query name.
+type queryName struct {
+	service   string
+	namespace string
+
+	// allocID is set when querying the service instances of an allocation.
+	allocID string
+}
+
+// parseName parses the query name. It returns false if the name isn't in the
+// domain of the server or isn't the name of a service.
+func (s *Server) parseName(qname string) (queryName, bool) {
+	qname = strings.ToLower(qname)
+	if !strings.HasSuffix(qname, s.suffix) {
+		return queryName{}, false
+	}
+
+	labels := strings.Split(strings.TrimSuffix(qname, s.suffix), ".")
+	switch len(labels) {
+	case 1:
+		return queryName{service: labels[0], namespace: structs.DefaultNamespace}, labels[0] != ""
+	case 2:
+		return queryName{service: labels[0], namespace: labels[1]}, true
+	case 3:
+		return queryName{allocID: labels[0], service: labels[1], namespace: labels[2]}, true
+	default:
+		return queryName{}, false
+	}
+}
+
+// lookup returns the healthy instances of the queried service.
+func (s *Server) lookup(name queryName) ([]*structs.ServiceRegistration, error) {
+	args := structs.ServiceRegistrationByNameRequest{
+		ServiceName: name.service,
+		HealthyOnly: true,
+		QueryOptions: structs.QueryOptions{
+			Region:     s.region,
+			Namespace:  name.namespace,
+			AuthToken:  s.config.Token,
+			AllowStale: true,
+		},
+	}
+	var reply structs.ServiceRegistrationByNameResponse
+	if err := s.rpc(structs.ServiceRegistrationGetServiceRPCMethod, &args, &reply); err != nil {
+		return nil, err
+	}
+
+	if name.allocID == "" {
+		return reply.Services, nil
+	}
+
+	var services []*structs.ServiceRegistration
+	for _, service := range reply.Services {
+		if service.AllocID == name.allocID {
+			services = append(services, service)
+		}
+	}
+	return services, nil
+}
+
+// answer adds the records answering the question to the message.
+func (s *Server) answer(m *dns.Msg, q dns.Question, name queryName, services []*structs.ServiceRegistration) {
+	switch q.Qtype {
+	case dns.TypeA, dns.TypeAAAA, dns.TypeANY:
+		seen := make(map[string]struct{}, len(services))
+		for _, service := range services {
+			if _, ok := seen[service.Address]; ok {
+				continue
+			}
+			seen[service.Address] = struct{}{}
+			if rr := s.addressRecord(q.Name, service.Address, q.Qtype); rr != nil {
+				m.Answer = append(m.Answer, rr)
+			}
+		}
+
+	case dns.TypeSRV:
+		seen := make(map[string]struct{}, len(services))
+		for _, service := range services {
+			target := dns.Fqdn(service.Address)
+			ip := net.ParseIP(service.Address)
+			if ip != nil {
+				target = strings.Join([]string{service.AllocID, name.service, name.namespace}, ".") + s.suffix
+			}
+
+			m.Answer = append(m.Answer, &dns.SRV{
+				Hdr:      s.header(q.Name, dns.TypeSRV),
+				Priority: 1,
+				Weight:   1,
+				Port:     uint16(service.Port),
+				Target:   target,
+			})
+
+			if _, ok := seen[target]; ok || ip == nil {
+				continue
+			}
+			seen[target] = struct{}{}
+			if rr := s.addressRecord(target, service.Address, dns.TypeANY); rr != nil {
+				m.Extra = append(m.Extra, rr)
+			}
+		}
+	}
+}
+
+// addressRecord returns the A or AAAA record of the address, or nil if the
+// address isn't an IP address or doesn't match the query type.
+func (s *Server) addressRecord(name, address string, qtype uint16) dns.RR {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return nil
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		if qtype != dns.TypeA && qtype != dns.TypeANY {
+			return nil
+		}
+		return &dns.A{Hdr: s.header(name, dns.TypeA), A: ip4}
+	}
+	if qtype != dns.TypeAAAA && qtype != dns.TypeANY {
+		return nil
+	}
+	return &dns.AAAA{Hdr: s.header(name, dns.TypeAAAA), AAAA: ip}
+}
+
+func (s *Server) header(name string, rrtype uint16) dns.RR_Header {
+	return dns.RR_Header{
+		Name:   name,
+		Rrtype: rrtype,
+		Class:  dns.ClassINET,
+		Ttl:    uint32(s.config.TTL.Seconds()),
+	}
+}
+
+// write sends the response, truncating it to the maximum UDP message size of
+// the request if needed.
+func (s *Server) write(w dns.ResponseWriter, req, m *dns.Msg) {
+	if _, ok := w.LocalAddr().(*net.UDPAddr); ok {
+		size := dns.MinMsgSize
+		if opt := req.IsEdns0(); opt != nil {
+			size = int(opt.UDPSize())
+		}
+		m.Truncate(size)
+	}
+
+	if err := w.WriteMsg(m); err != nil && !errors.Is(err, net.ErrClosed) {
+		s.logger.Debug("failed to write DNS response", "error", err)
+	}
+}
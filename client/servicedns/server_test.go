@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package servicedns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/config"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/miekg/dns"
+	"github.com/shoenig/test/must"
+)
+
+// testServer starts a DNS server looking up services from the given
+// registrations, and returns the address of its UDP listener.
+func testServer(t *testing.T, regs []*structs.ServiceRegistration) string {
+	rpc := func(method string, args, reply any) error {
+		must.Eq(t, structs.ServiceRegistrationGetServiceRPCMethod, method)
+		req := args.(*structs.ServiceRegistrationByNameRequest)
+		must.True(t, req.HealthyOnly)
+		must.Eq(t, "secret", req.AuthToken)
+
+		if req.Namespace == "restricted" {
+			return structs.ErrPermissionDenied
+		}
+
+		resp := reply.(*structs.ServiceRegistrationByNameResponse)
+		for _, reg := range regs {
+			if reg.ServiceName == req.ServiceName && reg.Namespace == req.Namespace {
+				resp.Services = append(resp.Services, reg)
+			}
+		}
+		return nil
+	}
+
+	s := NewServer(testlog.HCLogger(t), &config.ServiceDNSConfig{
+		Addr:   "127.0.0.1:0",
+		Domain: "nomad",
+		TTL:    10 * time.Second,
+		Token:  "secret",
+	}, "global", rpc)
+	must.NoError(t, s.Start())
+	t.Cleanup(s.Shutdown)
+
+	return s.udpServer.PacketConn.LocalAddr().String()
+}
+
+func testQuery(t *testing.T, addr, name string, qtype uint16) *dns.Msg {
+	m := new(dns.Msg)
+	m.SetQuestion(name, qtype)
+	resp, _, err := new(dns.Client).Exchange(m, addr)
+	must.NoError(t, err)
+	return resp
+}
+
+func TestServer_ServeDNS(t *testing.T) {
+	ci.Parallel(t)
+
+	addr := testServer(t, []*structs.ServiceRegistration{
+		{ServiceName: "web", Namespace: "default", AllocID: "a1", Address: "10.0.0.1", Port: 8080},
+		{ServiceName: "web", Namespace: "default", AllocID: "a1", Address: "10.0.0.1", Port: 8081},
+		{ServiceName: "web", Namespace: "default", AllocID: "a2", Address: "fd00::2", Port: 8080},
+		{ServiceName: "db", Namespace: "prod", AllocID: "a3", Address: "db.example.com", Port: 5432},
+	})
+
+	// A records of the default namespace.
+	resp := testQuery(t, addr, "web.nomad.", dns.TypeA)
+	must.Eq(t, dns.RcodeSuccess, resp.Rcode)
+	must.True(t, resp.Authoritative)
+	must.Len(t, 1, resp.Answer)
+	a := resp.Answer[0].(*dns.A)
+	must.Eq(t, "10.0.0.1", a.A.String())
+	must.Eq(t, 10, a.Hdr.Ttl)
+
+	resp = testQuery(t, addr, "web.default.nomad.", dns.TypeAAAA)
+	must.Len(t, 1, resp.Answer)
+	must.Eq(t, "fd00::2", resp.Answer[0].(*dns.AAAA).AAAA.String())
+
+	// SRV records target the instances of the allocations.
+	resp = testQuery(t, addr, "web.default.nomad.", dns.TypeSRV)
+	must.Len(t, 3, resp.Answer)
+	srv := resp.Answer[0].(*dns.SRV)
+	must.Eq(t, 8080, srv.Port)
+	must.Eq(t, "a1.web.default.nomad.", srv.Target)
+	must.Len(t, 2, resp.Extra)
+
+	resp = testQuery(t, addr, "a2.web.default.nomad.", dns.TypeAAAA)
+	must.Len(t, 1, resp.Answer)
+
+	// Hostnames are used as the SRV targets.
+	resp = testQuery(t, addr, "db.prod.nomad.", dns.TypeSRV)
+	must.Len(t, 1, resp.Answer)
+	must.Eq(t, "db.example.com.", resp.Answer[0].(*dns.SRV).Target)
+	must.Len(t, 0, resp.Extra)
+
+	resp = testQuery(t, addr, "db.prod.nomad.", dns.TypeA)
+	must.Eq(t, dns.RcodeSuccess, resp.Rcode)
+	must.Len(t, 0, resp.Answer)
+
+	resp = testQuery(t, addr, "missing.nomad.", dns.TypeA)
+	must.Eq(t, dns.RcodeNameError, resp.Rcode)
+
+	resp = testQuery(t, addr, "web.restricted.nomad.", dns.TypeA)
+	must.Eq(t, dns.RcodeRefused, resp.Rcode)
+
+	resp = testQuery(t, addr, "web.example.com.", dns.TypeA)
+	must.Eq(t, dns.RcodeRefused, resp.Rcode)
+}
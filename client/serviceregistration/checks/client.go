@@ -6,6 +6,7 @@ package checks
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net"
@@ -20,6 +21,10 @@ import (
 	"github.com/hashicorp/nomad/client/serviceregistration"
 	"github.com/hashicorp/nomad/helper/useragent"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"oss.indeed.com/go/libtime"
 )
 
@@ -35,7 +40,13 @@ type Checker interface {
 	Do(context.Context, *QueryContext, *Query) *structs.CheckQueryResult
 }
 
-// New creates a new Checker capable of executing HTTP and TCP checks.
+// ScriptExecutor executes commands in the context of a task, for script checks.
+type ScriptExecutor interface {
+	Exec(timeout time.Duration, cmd string, args []string) ([]byte, int, error)
+}
+
+// New creates a new Checker capable of executing HTTP, TCP, gRPC and script
+// checks.
 func New(log hclog.Logger) Checker {
 	httpClient := cleanhttp.DefaultPooledClient()
 	httpClient.Timeout = maxTimeoutHTTP
@@ -66,6 +77,10 @@ func (c *checker) Do(ctx context.Context, qc *QueryContext, q *Query) *structs.C
 	switch q.Type {
 	case "http":
 		qr = c.checkHTTP(timeout, qc, q)
+	case "grpc":
+		qr = c.checkGRPC(timeout, qc, q)
+	case "script":
+		qr = c.checkScript(qc, q)
 	default:
 		qr = c.checkTCP(timeout, qc, q)
 	}
@@ -223,6 +238,91 @@ func (c *checker) checkHTTP(ctx context.Context, qc *QueryContext, q *Query) *st
 	return qr
 }
 
+func (c *checker) checkGRPC(ctx context.Context, qc *QueryContext, q *Query) *structs.CheckQueryResult {
+	qr := &structs.CheckQueryResult{
+		Mode:      q.Mode,
+		Timestamp: c.now(),
+		Status:    structs.CheckPending,
+	}
+
+	addr, err := address(qc, q)
+	if err != nil {
+		qr.Output = err.Error()
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+
+	creds := insecure.NewCredentials()
+	if q.GRPCUseTLS {
+		creds = credentials.NewTLS(&tls.Config{
+			ServerName:         q.TLSServerName,
+			InsecureSkipVerify: q.TLSSkipVerify,
+		})
+	}
+
+	conn, err := grpc.DialContext(ctx, addr,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUserAgent(useragent.String()),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		qr.Output = fmt.Sprintf("nomad: %s", err.Error())
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	response, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: q.GRPCService,
+	})
+	if err != nil {
+		qr.Output = fmt.Sprintf("nomad: %s", err.Error())
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+
+	if status := response.GetStatus(); status != healthpb.HealthCheckResponse_SERVING {
+		qr.Output = fmt.Sprintf("nomad: grpc status %s", status)
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+
+	qr.Output = "nomad: grpc ok"
+	qr.Status = structs.CheckSuccess
+	return qr
+}
+
+func (c *checker) checkScript(qc *QueryContext, q *Query) *structs.CheckQueryResult {
+	qr := &structs.CheckQueryResult{
+		Mode:      q.Mode,
+		Timestamp: c.now(),
+		Status:    structs.CheckPending,
+	}
+
+	if qc.Exec == nil {
+		qr.Output = "nomad: task is not running"
+		qr.Status = structs.CheckFailure
+		return qr
+	}
+
+	output, code, err := qc.Exec.Exec(q.Timeout, q.Command, q.Args)
+	switch {
+	case err != nil:
+		qr.Output = fmt.Sprintf("nomad: %s", err.Error())
+		qr.Status = structs.CheckFailure
+	case code != 0:
+		qr.Output = limitRead(bytes.NewReader(output))
+		qr.Status = structs.CheckFailure
+	default:
+		// as with http checks, the output is ignored on success
+		qr.Output = "nomad: script ok"
+		qr.Status = structs.CheckSuccess
+	}
+	return qr
+}
+
 const (
 	// outputSizeLimit is the maximum number of bytes to read and store of an http
 	// check output. Set to 3kb which fits in 1 page with room for other fields.
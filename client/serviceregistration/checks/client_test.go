@@ -21,6 +21,9 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/shoenig/test/must"
 	"golang.org/x/exp/maps"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"oss.indeed.com/go/libtime/libtimetest"
 )
 
@@ -538,3 +541,121 @@ func tcpServer(t *testing.T, ctx context.Context, port int) {
 		}
 	}()
 }
+
+// testExecutor is a ScriptExecutor returning a fixed result.
+type testExecutor struct {
+	output []byte
+	code   int
+	err    error
+}
+
+func (e *testExecutor) Exec(_ time.Duration, _ string, _ []string) ([]byte, int, error) {
+	return e.output, e.code, e.err
+}
+
+func TestChecker_Do_Script(t *testing.T) {
+	ci.Parallel(t)
+
+	// create a mock clock so we can assert time is set
+	now := time.Date(2022, 1, 2, 3, 4, 5, 6, time.UTC)
+	clock := libtimetest.NewClockMock(t).NowMock.Return(now)
+
+	query := &Query{
+		Mode:    structs.Healthiness,
+		Type:    "script",
+		Timeout: 100 * time.Millisecond,
+		Command: "/bin/check",
+	}
+
+	cases := []struct {
+		name      string
+		exec      ScriptExecutor
+		expStatus structs.CheckStatus
+		expOutput string
+	}{{
+		name:      "script ok",
+		exec:      &testExecutor{output: []byte("all good")},
+		expStatus: structs.CheckSuccess,
+		expOutput: "nomad: script ok",
+	}, {
+		name:      "script exit code",
+		exec:      &testExecutor{output: []byte("not ready"), code: 2},
+		expStatus: structs.CheckFailure,
+		expOutput: "not ready",
+	}, {
+		name:      "script error",
+		exec:      &testExecutor{err: fmt.Errorf("exec failed")},
+		expStatus: structs.CheckFailure,
+		expOutput: "nomad: exec failed",
+	}, {
+		name:      "task not running",
+		expStatus: structs.CheckFailure,
+		expOutput: "nomad: task is not running",
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(testlog.HCLogger(t))
+			c.(*checker).clock = clock
+
+			qc := &QueryContext{ID: "abc123", Task: "task"}
+			if tc.exec != nil {
+				qc.Exec = tc.exec
+			}
+
+			result := c.Do(context.Background(), qc, query)
+			must.Eq(t, tc.expStatus, result.Status)
+			must.Eq(t, tc.expOutput, result.Output)
+			must.Eq(t, now.Unix(), result.Timestamp)
+		})
+	}
+}
+
+func TestChecker_Do_GRPC(t *testing.T) {
+	ci.Parallel(t)
+
+	// create a grpc server implementing the health protocol
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("serving", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("not-serving", healthpb.HealthCheckResponse_NOT_SERVING)
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, healthServer)
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(srv.Stop)
+
+	addr, port, err := net.SplitHostPort(l.Addr().String())
+	must.NoError(t, err)
+
+	qc := &QueryContext{
+		ID:               "abc123",
+		CustomAddress:    addr,
+		ServicePortLabel: port,
+		NetworkStatus:    mock.NewNetworkStatus(addr),
+	}
+	makeQuery := func(service string) *Query {
+		return &Query{
+			Mode:        structs.Healthiness,
+			Type:        "grpc",
+			Timeout:     time.Second,
+			AddressMode: "auto",
+			PortLabel:   port,
+			GRPCService: service,
+		}
+	}
+
+	c := New(testlog.HCLogger(t))
+
+	result := c.Do(context.Background(), qc, makeQuery("serving"))
+	must.Eq(t, structs.CheckSuccess, result.Status)
+	must.Eq(t, "nomad: grpc ok", result.Output)
+
+	result = c.Do(context.Background(), qc, makeQuery("not-serving"))
+	must.Eq(t, structs.CheckFailure, result.Status)
+	must.Eq(t, "nomad: grpc status NOT_SERVING", result.Output)
+
+	result = c.Do(context.Background(), qc, makeQuery("unknown"))
+	must.Eq(t, structs.CheckFailure, result.Status)
+	must.StrContains(t, result.Output, "NotFound")
+}
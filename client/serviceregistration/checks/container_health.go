@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package checks
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ContainerHealthReporter is implemented by the script executors of tasks
+// whose driver can report the health status of the task's container, as
+// determined by the healthcheck of its image.
+type ContainerHealthReporter interface {
+	ContainerHealth() (status string, output string, err error)
+}
+
+// containerHealthExecutor is the ScriptExecutor of script checks with
+// use_container_health. Rather than running a command in the task, it reports
+// the health status of the task's container with the exit code of a script
+// check: 0 if healthy, 1 (a warning in Consul) while starting, 2 otherwise.
+type containerHealthExecutor struct {
+	exec ScriptExecutor
+}
+
+// NewContainerHealthExecutor returns the ScriptExecutor of script checks with
+// use_container_health in the task of the given executor, or nil if the task
+// isn't running.
+func NewContainerHealthExecutor(exec ScriptExecutor) ScriptExecutor {
+	if exec == nil {
+		return nil
+	}
+	return &containerHealthExecutor{exec: exec}
+}
+
+func (e *containerHealthExecutor) Exec(time.Duration, string, []string) ([]byte, int, error) {
+	reporter, ok := e.exec.(ContainerHealthReporter)
+	if !ok {
+		return nil, 0, errors.New("task driver does not report container health")
+	}
+
+	status, output, err := reporter.ContainerHealth()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch status {
+	case "healthy":
+		return []byte(output), 0, nil
+	case "starting":
+		return []byte(output), 1, nil
+	case "":
+		return nil, 0, errors.New("task container has no healthcheck")
+	default:
+		if output == "" {
+			output = fmt.Sprintf("container is %s", status)
+		}
+		return []byte(output), 2, nil
+	}
+}
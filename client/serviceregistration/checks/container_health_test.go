@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package checks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+// testHealthReporter is a ScriptExecutor reporting a fixed container health.
+type testHealthReporter struct {
+	testExecutor
+	status string
+	output string
+}
+
+func (r *testHealthReporter) ContainerHealth() (string, string, error) {
+	return r.status, r.output, nil
+}
+
+func TestContainerHealthExecutor(t *testing.T) {
+	ci.Parallel(t)
+
+	must.Nil(t, NewContainerHealthExecutor(nil))
+
+	// drivers that don't report the health of containers fail the check
+	_, _, err := NewContainerHealthExecutor(new(testExecutor)).Exec(time.Second, "", nil)
+	must.ErrorContains(t, err, "does not report container health")
+
+	cases := []struct {
+		status string
+		output string
+		code   int
+		exp    string
+		err    string
+	}{
+		{status: "healthy", output: "ok", code: 0, exp: "ok"},
+		{status: "starting", code: 1},
+		{status: "unhealthy", output: "connection refused", code: 2, exp: "connection refused"},
+		{status: "unhealthy", code: 2, exp: "container is unhealthy"},
+		{status: "", err: "task container has no healthcheck"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.status, func(t *testing.T) {
+			exec := NewContainerHealthExecutor(&testHealthReporter{status: tc.status, output: tc.output})
+			output, code, err := exec.Exec(time.Second, "", nil)
+			if tc.err != "" {
+				must.ErrorContains(t, err, tc.err)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.code, code)
+			must.Eq(t, tc.exp, string(output))
+		})
+	}
+}
@@ -5,6 +5,7 @@ package checks
 
 import (
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -18,16 +19,22 @@ func GetCheckQuery(c *structs.ServiceCheck) *Query {
 		protocol = "http"
 	}
 	return &Query{
-		Mode:        structs.GetCheckMode(c),
-		Type:        c.Type,
-		Timeout:     c.Timeout,
-		AddressMode: c.AddressMode,
-		PortLabel:   c.PortLabel,
-		Protocol:    protocol,
-		Path:        c.Path,
-		Method:      c.Method,
-		Headers:     maps.Clone(c.Header),
-		Body:        c.Body,
+		Mode:          structs.GetCheckMode(c),
+		Type:          c.Type,
+		Timeout:       c.Timeout,
+		AddressMode:   c.AddressMode,
+		PortLabel:     c.PortLabel,
+		Protocol:      protocol,
+		Path:          c.Path,
+		Method:        c.Method,
+		Headers:       maps.Clone(c.Header),
+		Body:          c.Body,
+		GRPCService:   c.GRPCService,
+		GRPCUseTLS:    c.GRPCUseTLS,
+		TLSServerName: c.TLSServerName,
+		TLSSkipVerify: c.TLSSkipVerify,
+		Command:       c.Command,
+		Args:          slices.Clone(c.Args),
 	}
 }
 
@@ -35,7 +42,7 @@ func GetCheckQuery(c *structs.ServiceCheck) *Query {
 // amount of information needed to actually execute that check.
 type Query struct {
 	Mode structs.CheckMode // readiness or healthiness
-	Type string            // tcp or http
+	Type string            // tcp, http, grpc or script
 
 	Timeout time.Duration // connection / request timeout
 
@@ -47,6 +54,14 @@ type Query struct {
 	Method   string      // http checks only
 	Headers  http.Header // http checks only
 	Body     string      // http checks only
+
+	GRPCService   string // grpc checks only
+	GRPCUseTLS    bool   // grpc checks only
+	TLSServerName string // grpc checks only
+	TLSSkipVerify bool   // grpc checks only
+
+	Command string   // script checks only
+	Args    []string // script checks only
 }
 
 // A QueryContext contains allocation and service parameters necessary for
@@ -59,6 +74,10 @@ type QueryContext struct {
 	NetworkStatus    structs.NetworkStatus
 	Ports            structs.AllocatedPorts
 
+	// Exec runs the commands of script checks in their task. It is nil if
+	// the task isn't running.
+	Exec ScriptExecutor
+
 	Group   string
 	Task    string
 	Service string
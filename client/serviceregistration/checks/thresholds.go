@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package checks
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Thresholds applies the success_before_passing and failures_before_critical
+// parameters of a check to its successive results, so the status of a check
+// only changes after enough consecutive results agree.
+type Thresholds struct {
+	successBeforePassing   int
+	failuresBeforeCritical int
+
+	successes int
+	failures  int
+	status    structs.CheckStatus
+}
+
+// NewThresholds creates the Thresholds of check c, starting in the pending
+// status.
+func NewThresholds(c *structs.ServiceCheck) *Thresholds {
+	return &Thresholds{
+		successBeforePassing:   c.SuccessBeforePassing,
+		failuresBeforeCritical: c.FailuresBeforeCritical,
+		status:                 structs.CheckPending,
+	}
+}
+
+// Apply sets the status of qr to the status of the check given the
+// consecutive results so far. The status of qr is left unchanged if it
+// reached its threshold.
+func (t *Thresholds) Apply(qr *structs.CheckQueryResult) {
+	switch qr.Status {
+	case structs.CheckSuccess:
+		t.successes++
+		t.failures = 0
+		if t.successes >= t.successBeforePassing {
+			t.status = structs.CheckSuccess
+		}
+	case structs.CheckFailure:
+		t.failures++
+		t.successes = 0
+		if t.failures >= t.failuresBeforeCritical {
+			t.status = structs.CheckFailure
+		}
+	default:
+		return
+	}
+	qr.Status = t.status
+}
@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package checks
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestThresholds_Apply(t *testing.T) {
+	ci.Parallel(t)
+
+	apply := func(th *Thresholds, status structs.CheckStatus) structs.CheckStatus {
+		qr := &structs.CheckQueryResult{Status: status}
+		th.Apply(qr)
+		return qr.Status
+	}
+
+	t.Run("no thresholds", func(t *testing.T) {
+		th := NewThresholds(&structs.ServiceCheck{})
+		must.Eq(t, structs.CheckSuccess, apply(th, structs.CheckSuccess))
+		must.Eq(t, structs.CheckFailure, apply(th, structs.CheckFailure))
+		must.Eq(t, structs.CheckSuccess, apply(th, structs.CheckSuccess))
+	})
+
+	t.Run("success before passing", func(t *testing.T) {
+		th := NewThresholds(&structs.ServiceCheck{SuccessBeforePassing: 2})
+		must.Eq(t, structs.CheckPending, apply(th, structs.CheckSuccess))
+		must.Eq(t, structs.CheckFailure, apply(th, structs.CheckFailure))
+		must.Eq(t, structs.CheckFailure, apply(th, structs.CheckSuccess))
+		must.Eq(t, structs.CheckSuccess, apply(th, structs.CheckSuccess))
+	})
+
+	t.Run("failures before critical", func(t *testing.T) {
+		th := NewThresholds(&structs.ServiceCheck{FailuresBeforeCritical: 3})
+		must.Eq(t, structs.CheckSuccess, apply(th, structs.CheckSuccess))
+		must.Eq(t, structs.CheckSuccess, apply(th, structs.CheckFailure))
+		must.Eq(t, structs.CheckSuccess, apply(th, structs.CheckFailure))
+		must.Eq(t, structs.CheckSuccess, apply(th, structs.CheckSuccess))
+		must.Eq(t, structs.CheckSuccess, apply(th, structs.CheckFailure))
+		must.Eq(t, structs.CheckSuccess, apply(th, structs.CheckFailure))
+		must.Eq(t, structs.CheckFailure, apply(th, structs.CheckFailure))
+	})
+}
@@ -31,6 +31,12 @@ type ServiceRegistrationHandler struct {
 	// registering new ones.
 	registrationEnabled bool
 
+	// weights tracks the registered services and the weights of their checks,
+	// indexed by registration ID, so their weight can be updated as the
+	// statuses of their checks change.
+	weights     map[string]*weightedRegistration
+	weightsLock sync.Mutex
+
 	// shutDownCh coordinates shutting down the handler and any long-running
 	// processes, such as the RPC retry.
 	shutDownCh chan struct{}
@@ -62,6 +68,11 @@ type ServiceRegistrationHandlerCfg struct {
 	// CheckWatcher watches checks of services in the Nomad service provider,
 	// and restarts associated tasks in accordance with their check_restart block.
 	CheckWatcher serviceregistration.CheckWatcher
+
+	// StatusGetter returns the statuses of the checks of services in the
+	// Nomad service provider, which determine the weight of the services. The
+	// weights are not updated if nil.
+	StatusGetter serviceregistration.CheckStatusGetter
 }
 
 // NewServiceRegistrationHandler returns a ready to use
@@ -69,13 +80,18 @@ type ServiceRegistrationHandlerCfg struct {
 // interface.
 func NewServiceRegistrationHandler(log hclog.Logger, cfg *ServiceRegistrationHandlerCfg) serviceregistration.Handler {
 	go cfg.CheckWatcher.Run(context.TODO())
-	return &ServiceRegistrationHandler{
+	s := &ServiceRegistrationHandler{
 		cfg:                 cfg,
 		log:                 log.Named("service_registration.nomad"),
 		registrationEnabled: cfg.Enabled,
 		checkWatcher:        cfg.CheckWatcher,
+		weights:             make(map[string]*weightedRegistration),
 		shutDownCh:          make(chan struct{}),
 	}
+	if cfg.StatusGetter != nil {
+		go s.watchWeights()
+	}
+	return s
 }
 
 func (s *ServiceRegistrationHandler) RegisterWorkload(workload *serviceregistration.WorkloadServices) error {
@@ -90,6 +106,11 @@ func (s *ServiceRegistrationHandler) RegisterWorkload(workload *serviceregistrat
 	var mErr multierror.Error
 
 	registrations := make([]*structs.ServiceRegistration, len(workload.Services))
+	weighted := make([]*weightedRegistration, len(workload.Services))
+
+	// The weight of the services depends on the current status of their
+	// checks.
+	statuses := s.checkStatuses()
 
 	// Iterate over the services and generate a hydrated registration object for
 	// each. All services are part of a single allocation, therefore we cannot
@@ -99,6 +120,9 @@ func (s *ServiceRegistrationHandler) RegisterWorkload(workload *serviceregistrat
 		if err != nil {
 			mErr.Errors = append(mErr.Errors, err)
 		} else if mErr.ErrorOrNil() == nil {
+			weighted[i] = newWeightedRegistration(serviceRegistration, serviceSpec,
+				workload.AllocInfo.AllocID, workload.AllocInfo.Group)
+			serviceRegistration.Weight = weighted[i].weight(statuses)
 			registrations[i] = serviceRegistration
 		}
 	}
@@ -130,7 +154,12 @@ func (s *ServiceRegistrationHandler) RegisterWorkload(workload *serviceregistrat
 
 	var resp structs.ServiceRegistrationUpsertResponse
 
-	return s.cfg.RPCFn(structs.ServiceRegistrationUpsertRPCMethod, &args, &resp)
+	if err := s.cfg.RPCFn(structs.ServiceRegistrationUpsertRPCMethod, &args, &resp); err != nil {
+		return err
+	}
+
+	s.trackWeights(weighted)
+	return nil
 }
 
 // RemoveWorkload iterates the services and removes them from the service
@@ -172,6 +201,9 @@ func (s *ServiceRegistrationHandler) removeWorkload(
 	// Generate the consistent ID for this service, so we know what to remove.
 	id := serviceregistration.MakeAllocServiceID(workload.AllocInfo.AllocID, workload.Name(), serviceSpec)
 
+	// Stop updating the weight of the service.
+	s.untrackWeights(id)
+
 	deleteArgs := structs.ServiceRegistrationDeleteByIDRequest{
 		ID: id,
 		WriteRequest: structs.WriteRequest{
@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nsd
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// weightsPollFrequency is how often the weights of the registered
+	// services are recomputed from the statuses of their checks.
+	weightsPollFrequency = 5 * time.Second
+)
+
+// weightedRegistration is a service registration along with the weights of
+// its checks, indexed by check ID.
+type weightedRegistration struct {
+	registration *structs.ServiceRegistration
+	checks       map[string]int
+}
+
+// newWeightedRegistration returns the weightedRegistration of the service
+// registration and its service spec.
+func newWeightedRegistration(
+	reg *structs.ServiceRegistration, serviceSpec *structs.Service, allocID, group string) *weightedRegistration {
+
+	checks := make(map[string]int, len(serviceSpec.Checks))
+	for _, check := range serviceSpec.Checks {
+		checks[string(structs.NomadCheckID(allocID, group, check))] = check.Weight
+	}
+	return &weightedRegistration{registration: reg, checks: checks}
+}
+
+// weight returns the weight of the service given the statuses of the checks,
+// indexed by check ID. The weight is the sum of the weights of the passing
+// checks, or 1 if the service has no checks.
+func (w *weightedRegistration) weight(statuses map[string]string) int {
+	if len(w.checks) == 0 {
+		return 1
+	}
+
+	weight := 0
+	for id, checkWeight := range w.checks {
+		if statuses[id] == string(structs.CheckSuccess) {
+			weight += checkWeight
+		}
+	}
+	return weight
+}
+
+// checkStatuses returns the current statuses of the checks, indexed by check
+// ID.
+func (s *ServiceRegistrationHandler) checkStatuses() map[string]string {
+	if s.cfg.StatusGetter == nil {
+		return nil
+	}
+	statuses, err := s.cfg.StatusGetter.Get()
+	if err != nil {
+		s.log.Warn("failed to get check statuses", "error", err)
+		return nil
+	}
+	return statuses
+}
+
+// trackWeights starts tracking the weights of the registrations.
+func (s *ServiceRegistrationHandler) trackWeights(registrations []*weightedRegistration) {
+	s.weightsLock.Lock()
+	defer s.weightsLock.Unlock()
+
+	for _, reg := range registrations {
+		s.weights[reg.registration.ID] = reg
+	}
+}
+
+// untrackWeights stops tracking the weight of the registration of ID.
+func (s *ServiceRegistrationHandler) untrackWeights(id string) {
+	s.weightsLock.Lock()
+	defer s.weightsLock.Unlock()
+
+	delete(s.weights, id)
+}
+
+// watchWeights periodically recomputes the weights of the registered services
+// and updates the registrations whose weight changed, until the handler is
+// shut down.
+func (s *ServiceRegistrationHandler) watchWeights() {
+	ticker := time.NewTicker(weightsPollFrequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.shutDownCh:
+			return
+		case <-ticker.C:
+			s.updateWeights()
+		}
+	}
+}
+
+// updateWeights updates the registrations whose weight changed since they
+// were last registered.
+func (s *ServiceRegistrationHandler) updateWeights() {
+	statuses := s.checkStatuses()
+
+	// registered maps the updated registrations to the registrations they
+	// replace.
+	registered := make(map[*structs.ServiceRegistration]*structs.ServiceRegistration)
+
+	s.weightsLock.Lock()
+	var changed []*structs.ServiceRegistration
+	for _, reg := range s.weights {
+		if weight := reg.weight(statuses); weight != reg.registration.Weight {
+			update := reg.registration.Copy()
+			update.Weight = weight
+			changed = append(changed, update)
+			registered[update] = reg.registration
+		}
+	}
+	s.weightsLock.Unlock()
+
+	if len(changed) == 0 {
+		return
+	}
+
+	args := structs.ServiceRegistrationUpsertRequest{
+		Services: changed,
+		WriteRequest: structs.WriteRequest{
+			Region:    s.cfg.Region,
+			AuthToken: s.cfg.NodeSecret,
+		},
+	}
+	var resp structs.ServiceRegistrationUpsertResponse
+	if err := s.cfg.RPCFn(structs.ServiceRegistrationUpsertRPCMethod, &args, &resp); err != nil {
+		// the weights are retried on the next poll
+		s.log.Warn("failed to update service registration weights", "error", err)
+		return
+	}
+
+	// Remember the registered weights, unless the registration was removed or
+	// replaced in the meantime.
+	s.weightsLock.Lock()
+	defer s.weightsLock.Unlock()
+	for _, update := range changed {
+		if reg, ok := s.weights[update.ID]; ok && reg.registration == registered[update] {
+			reg.registration = update
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nsd
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// mockStatusGetter returns fixed check statuses.
+type mockStatusGetter map[string]string
+
+func (g mockStatusGetter) Get() (map[string]string, error) {
+	return g, nil
+}
+
+func TestServiceRegistrationHandler_Weights(t *testing.T) {
+	ci.Parallel(t)
+
+	workload := mockWorkload()
+	check := workload.Services[1].Checks[0]
+	check.Weight = 3
+	checkID := string(structs.NomadCheckID(workload.AllocInfo.AllocID, workload.AllocInfo.Group, check))
+
+	statuses := mockStatusGetter{checkID: string(structs.CheckPending)}
+
+	var upserts [][]*structs.ServiceRegistration
+	h := NewServiceRegistrationHandler(hclog.NewNullLogger(), &ServiceRegistrationHandlerCfg{
+		Enabled:      true,
+		CheckWatcher: new(mockCheckWatcher),
+		StatusGetter: statuses,
+		RPCFn: func(method string, args, _ interface{}) error {
+			if method == structs.ServiceRegistrationUpsertRPCMethod {
+				upserts = append(upserts, args.(*structs.ServiceRegistrationUpsertRequest).Services)
+			}
+			return nil
+		},
+	}).(*ServiceRegistrationHandler)
+	defer h.Shutdown()
+
+	// Services without checks have a weight of 1, and services with checks
+	// the sum of the weights of their passing checks.
+	must.NoError(t, h.RegisterWorkload(workload))
+	must.Len(t, 1, upserts)
+	must.Eq(t, 1, upserts[0][0].Weight)
+	must.Eq(t, 0, upserts[0][1].Weight)
+
+	// Nothing changed.
+	h.updateWeights()
+	must.Len(t, 1, upserts)
+
+	// The registration is updated once the check passes.
+	statuses[checkID] = string(structs.CheckSuccess)
+	h.updateWeights()
+	must.Len(t, 2, upserts)
+	must.Len(t, 1, upserts[1])
+	must.Eq(t, "redis-http", upserts[1][0].ServiceName)
+	must.Eq(t, 3, upserts[1][0].Weight)
+
+	h.updateWeights()
+	must.Len(t, 2, upserts)
+
+	// Removed services are no longer updated.
+	h.RemoveWorkload(workload)
+	statuses[checkID] = string(structs.CheckFailure)
+	h.updateWeights()
+	must.Len(t, 2, upserts)
+}
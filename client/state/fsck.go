@@ -0,0 +1,232 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper"
+)
+
+// QuarantineDir is the directory, relative to the alloc dir, orphaned alloc
+// dirs are moved to by Check.
+const QuarantineDir = ".quarantine"
+
+// CheckProblem is a kind of inconsistency between the client state and the
+// alloc dirs found by Check.
+type CheckProblem string
+
+const (
+	// CheckProblemCorruptAlloc is an allocation whose state cannot be decoded
+	// or is invalid.
+	CheckProblemCorruptAlloc CheckProblem = "corrupt_alloc"
+
+	// CheckProblemCorruptTaskState is a task whose state cannot be decoded.
+	CheckProblemCorruptTaskState CheckProblem = "corrupt_task_state"
+
+	// CheckProblemMissingAllocDir is an allocation in the client state whose
+	// alloc dir does not exist.
+	CheckProblemMissingAllocDir CheckProblem = "missing_alloc_dir"
+
+	// CheckProblemOrphanedAllocDir is an alloc dir without an allocation in
+	// the client state.
+	CheckProblemOrphanedAllocDir CheckProblem = "orphaned_alloc_dir"
+)
+
+// CheckResult is an inconsistency found by Check.
+type CheckResult struct {
+	Problem CheckProblem
+	AllocID string
+	Task    string `json:",omitempty"`
+	Path    string `json:",omitempty"`
+	Error   string `json:",omitempty"`
+
+	// Repair describes how the problem was repaired, or is empty if it was
+	// not.
+	Repair string `json:",omitempty"`
+
+	// RepairError is set if repairing the problem failed.
+	RepairError string `json:",omitempty"`
+}
+
+// CheckReport is the result of Check.
+type CheckReport struct {
+	// Allocs is the number of allocations in the client state.
+	Allocs int
+
+	// AllocDirs is the number of alloc dirs.
+	AllocDirs int
+
+	Results []*CheckResult
+}
+
+// Check validates the client state against the alloc dirs in allocDir. It
+// finds allocations and task states that cannot be decoded, allocations whose
+// alloc dir is missing, and alloc dirs without an allocation.
+//
+// If repair is true, invalid allocations are removed from the client state so
+// the client starts over with them if the servers still want them placed on
+// the node, corrupt task states are removed so their tasks start over, and
+// orphaned alloc dirs are moved to the QuarantineDir for inspection rather
+// than deleted. Check must not be run while allocations are running.
+func Check(db StateDB, allocDir string, repair bool, logger hclog.Logger) (*CheckReport, error) {
+	logger = logger.Named("fsck")
+
+	allocs, allocErrs, err := db.GetAllAllocations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read allocations: %w", err)
+	}
+
+	report := &CheckReport{Allocs: len(allocs) + len(allocErrs)}
+	add := func(result *CheckResult) {
+		report.Results = append(report.Results, result)
+		logger.Warn("found inconsistent client state",
+			"problem", result.Problem, "alloc_id", result.AllocID, "task", result.Task,
+			"path", result.Path, "error", result.Error, "repair", result.Repair,
+			"repair_error", result.RepairError)
+	}
+
+	// known is the set of allocations in the client state after repairs
+	known := make(map[string]struct{}, report.Allocs)
+
+	removeAlloc := func(result *CheckResult) {
+		if !repair {
+			known[result.AllocID] = struct{}{}
+			return
+		}
+		if err := db.DeleteAllocationBucket(result.AllocID); err != nil {
+			known[result.AllocID] = struct{}{}
+			result.RepairError = err.Error()
+			return
+		}
+		result.Repair = "removed allocation from client state"
+	}
+
+	for allocID, err := range allocErrs {
+		result := &CheckResult{
+			Problem: CheckProblemCorruptAlloc,
+			AllocID: allocID,
+			Error:   err.Error(),
+		}
+		removeAlloc(result)
+		add(result)
+	}
+
+	for _, alloc := range allocs {
+		dir := filepath.Join(allocDir, alloc.ID)
+
+		var tasks []string
+		if alloc.Job != nil {
+			if tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup); tg != nil {
+				for _, task := range tg.Tasks {
+					tasks = append(tasks, task.Name)
+				}
+			}
+		}
+		if tasks == nil {
+			result := &CheckResult{
+				Problem: CheckProblemCorruptAlloc,
+				AllocID: alloc.ID,
+				Error:   fmt.Sprintf("task group %q not found", alloc.TaskGroup),
+			}
+			removeAlloc(result)
+			add(result)
+			continue
+		}
+
+		if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+			result := &CheckResult{
+				Problem: CheckProblemMissingAllocDir,
+				AllocID: alloc.ID,
+				Path:    dir,
+			}
+			removeAlloc(result)
+			add(result)
+			continue
+		}
+
+		known[alloc.ID] = struct{}{}
+		for _, task := range tasks {
+			if _, _, err := db.GetTaskRunnerState(alloc.ID, task); err != nil {
+				result := &CheckResult{
+					Problem: CheckProblemCorruptTaskState,
+					AllocID: alloc.ID,
+					Task:    task,
+					Error:   err.Error(),
+				}
+				if repair {
+					if err := db.DeleteTaskBucket(alloc.ID, task); err != nil {
+						result.RepairError = err.Error()
+					} else {
+						result.Repair = "removed task state from client state"
+					}
+				}
+				add(result)
+			}
+		}
+	}
+
+	entries, err := os.ReadDir(allocDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read alloc dir: %w", err)
+	}
+
+	for _, entry := range entries {
+		// Only consider alloc dirs so other files in the alloc dir, such as
+		// the quarantine dir itself, are ignored.
+		if !entry.IsDir() || !helper.IsUUID(entry.Name()) {
+			continue
+		}
+		report.AllocDirs++
+
+		if _, ok := known[entry.Name()]; ok {
+			continue
+		}
+
+		result := &CheckResult{
+			Problem: CheckProblemOrphanedAllocDir,
+			AllocID: entry.Name(),
+			Path:    filepath.Join(allocDir, entry.Name()),
+		}
+		if repair {
+			if dest, err := quarantine(allocDir, entry.Name()); err != nil {
+				result.RepairError = err.Error()
+			} else {
+				result.Repair = fmt.Sprintf("moved to %s", dest)
+			}
+		}
+		add(result)
+	}
+
+	sort.Slice(report.Results, func(i, j int) bool {
+		a, b := report.Results[i], report.Results[j]
+		if a.AllocID != b.AllocID {
+			return a.AllocID < b.AllocID
+		}
+		return a.Task < b.Task
+	})
+	return report, nil
+}
+
+// quarantine moves the alloc dir of allocID to the quarantine dir and returns
+// its new path. The quarantine dir is within the alloc dir so moving doesn't
+// cross file systems.
+func quarantine(allocDir, allocID string) (string, error) {
+	dir := filepath.Join(allocDir, QuarantineDir)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	dest := filepath.Join(dir, fmt.Sprintf("%s-%d", allocID, time.Now().Unix()))
+	if err := os.Rename(filepath.Join(allocDir, allocID), dest); err != nil {
+		return "", err
+	}
+	return dest, nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	trstate "github.com/hashicorp/nomad/client/allocrunner/taskrunner/state"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/shoenig/test/must"
+)
+
+func TestCheck(t *testing.T) {
+	ci.Parallel(t)
+
+	testDB(t, func(t *testing.T, db StateDB) {
+		allocDir := t.TempDir()
+
+		// A healthy allocation
+		healthy := mock.Alloc()
+		must.NoError(t, db.PutAllocation(healthy))
+		task := healthy.Job.TaskGroups[0].Tasks[0].Name
+		must.NoError(t, db.PutTaskRunnerLocalState(healthy.ID, task, &trstate.LocalState{}))
+		must.NoError(t, os.Mkdir(filepath.Join(allocDir, healthy.ID), 0o755))
+
+		// An allocation without an alloc dir
+		missing := mock.Alloc()
+		must.NoError(t, db.PutAllocation(missing))
+
+		// An alloc dir without an allocation
+		orphan := uuid.Generate()
+		must.NoError(t, os.Mkdir(filepath.Join(allocDir, orphan), 0o755))
+
+		// Other files are ignored
+		must.NoError(t, os.Mkdir(filepath.Join(allocDir, "other"), 0o755))
+
+		// Checking only reports problems
+		report, err := Check(db, allocDir, false, testlog.HCLogger(t))
+		must.NoError(t, err)
+		must.Eq(t, 2, report.Allocs)
+		must.Eq(t, 2, report.AllocDirs)
+		must.Len(t, 2, report.Results)
+
+		problems := map[string]CheckProblem{}
+		for _, result := range report.Results {
+			must.Eq(t, "", result.Repair)
+			problems[result.AllocID] = result.Problem
+		}
+		must.Eq(t, map[string]CheckProblem{
+			missing.ID: CheckProblemMissingAllocDir,
+			orphan:     CheckProblemOrphanedAllocDir,
+		}, problems)
+		must.DirExists(t, filepath.Join(allocDir, orphan))
+
+		// Repairing removes the allocation and quarantines the alloc dir
+		report, err = Check(db, allocDir, true, testlog.HCLogger(t))
+		must.NoError(t, err)
+		must.Len(t, 2, report.Results)
+		for _, result := range report.Results {
+			must.NotEq(t, "", result.Repair)
+			must.Eq(t, "", result.RepairError)
+		}
+
+		allocs, _, err := db.GetAllAllocations()
+		must.NoError(t, err)
+		must.Len(t, 1, allocs)
+		must.Eq(t, healthy.ID, allocs[0].ID)
+
+		_, err = os.Stat(filepath.Join(allocDir, orphan))
+		must.True(t, os.IsNotExist(err))
+		quarantined, err := os.ReadDir(filepath.Join(allocDir, QuarantineDir))
+		must.NoError(t, err)
+		must.Len(t, 1, quarantined)
+
+		// Nothing left to repair
+		report, err = Check(db, allocDir, true, testlog.HCLogger(t))
+		must.NoError(t, err)
+		must.SliceEmpty(t, report.Results)
+	})
+}
@@ -137,6 +137,21 @@ type FsStreamRequest struct {
 	structs.QueryOptions
 }
 
+// FsArchiveRequest is the initial request for copying files from or to an
+// allocation as a tar archive. When extracting, the request is followed by
+// StreamErrWrapper frames with the content of the archive, terminated by a
+// frame with an empty payload.
+type FsArchiveRequest struct {
+	// AllocID is the allocation to copy files from or to
+	AllocID string
+
+	// Path is the file or directory to archive, or the directory to extract
+	// the archive into
+	Path string
+
+	structs.QueryOptions
+}
+
 // FsLogsRequest is the initial request for accessing allocation logs.
 type FsLogsRequest struct {
 	// AllocID is the allocation to stream logs from
@@ -193,6 +208,27 @@ type AllocExecRequest struct {
 	// The name of a predefined command to be executed (optional)
 	Action string
 
+	// NoRecord skips recording the session on clients that record exec
+	// sessions. It requires the alloc-exec-unrecorded capability.
+	NoRecord bool
+
+	structs.QueryOptions
+}
+
+// AllocPortForwardRequest is the initial request for forwarding a port of an
+// allocation. It is followed by StreamErrWrapper frames in both directions,
+// whose payloads carry the forwarded data.
+type AllocPortForwardRequest struct {
+	// JobID is the ID of the job requested
+	JobID string
+
+	// AllocID is the allocation to forward the port of
+	AllocID string
+
+	// Port is the port inside the allocation's network namespace to connect
+	// to
+	Port int
+
 	structs.QueryOptions
 }
 
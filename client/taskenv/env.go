@@ -13,6 +13,7 @@ import (
 	"sync"
 
 	"github.com/hashicorp/nomad/client/lib/idset"
+	"github.com/hashicorp/nomad/client/lib/numalib/hw"
 	"github.com/hashicorp/nomad/helper"
 	hargs "github.com/hashicorp/nomad/helper/args"
 	"github.com/hashicorp/nomad/helper/escapingfs"
@@ -76,6 +77,10 @@ const (
 	// AllocIndex is the environment variable for passing the allocation index.
 	AllocIndex = "NOMAD_ALLOC_INDEX"
 
+	// ArrayIndex is the environment variable for passing the index of a job
+	// array the allocation runs.
+	ArrayIndex = "NOMAD_ARRAY_INDEX"
+
 	// Datacenter is the environment variable for passing the datacenter in which the alloc is running.
 	Datacenter = "NOMAD_DC"
 
@@ -149,6 +154,10 @@ type TaskEnv struct {
 	// NodeAttrs is the map of node attributes for interpolation
 	NodeAttrs map[string]string
 
+	// RuntimeAttrs is the map of runtime attributes that are only
+	// interpolated into service tags and meta
+	RuntimeAttrs map[string]string
+
 	// EnvMap is the map of environment variables
 	EnvMap map[string]string
 
@@ -328,6 +337,12 @@ func (t *TaskEnv) ReplaceEnv(arg string) string {
 	return hargs.ReplaceEnv(arg, t.EnvMap, t.NodeAttrs)
 }
 
+// replaceEnvRuntime is like ReplaceEnv but also replaces the runtime
+// attributes, which are only available to service tags and meta.
+func (t *TaskEnv) replaceEnvRuntime(arg string) string {
+	return hargs.ReplaceEnv(arg, t.EnvMap, t.NodeAttrs, t.RuntimeAttrs)
+}
+
 // replaceEnvClient takes an arg and replaces all occurrences of client-specific
 // environment variables and Nomad variables.  If the variable is found in the
 // passed map it is replaced, otherwise the original string is returned.
@@ -418,6 +433,7 @@ type Builder struct {
 	memMaxLimit          int64
 	taskName             string
 	allocIndex           int
+	arrayJob             bool
 	datacenter           string
 	cgroupParent         string
 	namespace            string
@@ -437,6 +453,19 @@ type Builder struct {
 	// otherPorts for tasks in the same alloc
 	otherPorts map[string]string
 
+	// canary is whether the alloc is an unpromoted canary
+	canary bool
+
+	// reservedCores are the cores reserved for the task, or for all tasks in
+	// the alloc if there is no task
+	reservedCores []uint16
+
+	// coreNUMANodes maps the node's cores to their NUMA node
+	coreNUMANodes map[uint16]hw.NodeID
+
+	// interfaceIPs maps the node's network interfaces to their IP address
+	interfaceIPs map[string]string
+
 	// driverNetwork is the network defined by the driver (or nil if none
 	// was defined).
 	driverNetwork *drivers.DriverNetwork
@@ -528,6 +557,9 @@ func (b *Builder) buildEnv(allocDir, localDir, secretsDir string,
 	}
 	if b.allocIndex != -1 {
 		envMap[AllocIndex] = strconv.Itoa(b.allocIndex)
+		if b.arrayJob {
+			envMap[ArrayIndex] = strconv.Itoa(b.allocIndex)
+		}
 	}
 	if b.taskName != "" {
 		envMap[TaskName] = b.taskName
@@ -660,7 +692,33 @@ func (b *Builder) Build() *TaskEnv {
 	envMap, deviceEnvs := b.buildEnv(b.allocDir, b.localDir, b.secretsDir, nodeAttrs)
 	envMapClient, _ := b.buildEnv(b.clientSharedAllocDir, b.clientTaskLocalDir, b.clientTaskSecretsDir, nodeAttrs)
 
-	return NewTaskEnv(envMap, envMapClient, deviceEnvs, nodeAttrs, b.clientTaskRoot, b.clientSharedAllocDir)
+	env := NewTaskEnv(envMap, envMapClient, deviceEnvs, nodeAttrs, b.clientTaskRoot, b.clientSharedAllocDir)
+	env.RuntimeAttrs = b.buildRuntimeAttrs()
+	return env
+}
+
+// buildRuntimeAttrs returns the runtime attributes that can be interpolated
+// into service tags and meta.
+func (b *Builder) buildRuntimeAttrs() map[string]string {
+	attrs := make(map[string]string, 3+len(b.interfaceIPs))
+	attrs[structs.ServiceRuntimeAttrAllocIndex] = strconv.Itoa(b.allocIndex)
+	attrs[structs.ServiceRuntimeAttrCanary] = strconv.FormatBool(b.canary)
+
+	numaNodes := idset.Empty[hw.NodeID]()
+	for _, core := range b.reservedCores {
+		if id, ok := b.coreNUMANodes[core]; ok {
+			numaNodes.Insert(id)
+		}
+	}
+	attrs[structs.ServiceRuntimeAttrNUMANode] = ""
+	if !numaNodes.Empty() {
+		attrs[structs.ServiceRuntimeAttrNUMANode] = numaNodes.String()
+	}
+
+	for iface, ip := range b.interfaceIPs {
+		attrs[structs.ServiceRuntimeAttrInterfaceIP(iface)] = ip
+	}
+	return attrs
 }
 
 // UpdateTask updates the environment based on a new alloc and task.
@@ -764,6 +822,9 @@ func (b *Builder) setAlloc(alloc *structs.Allocation) *Builder {
 	}
 
 	tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+	b.arrayJob = tg.Array != nil
+	b.canary = alloc.DeploymentStatus.IsCanary()
+	b.reservedCores = nil
 
 	b.otherPorts = make(map[string]string, len(tg.Tasks)*2)
 
@@ -784,6 +845,14 @@ func (b *Builder) setAlloc(alloc *structs.Allocation) *Builder {
 			}
 		}
 
+		// Collect the reserved cores of the task, or of all tasks for a
+		// group level builder
+		for taskName, tr := range alloc.AllocatedResources.Tasks {
+			if b.taskName == "" || taskName == b.taskName {
+				b.reservedCores = append(b.reservedCores, tr.Cpu.ReservedCores...)
+			}
+		}
+
 		// COMPAT(1.0): remove in 1.0 when AllocatedPorts can be used exclusively
 		// Add ports from other tasks
 		for taskName, resources := range alloc.AllocatedResources.Tasks {
@@ -852,9 +921,39 @@ func (b *Builder) setNode(n *structs.Node) *Builder {
 	for k, v := range n.Meta {
 		b.nodeAttrs[fmt.Sprintf("%s%s", nodeMetaPrefix, k)] = v
 	}
+
+	// Set up the runtime attributes of the node.
+	b.coreNUMANodes = make(map[uint16]hw.NodeID)
+	b.interfaceIPs = make(map[string]string)
+	if n.NodeResources != nil {
+		if top := n.NodeResources.Processors.Topology; top != nil {
+			for _, core := range top.Cores {
+				b.coreNUMANodes[uint16(core.ID)] = core.NodeID
+			}
+		}
+		for _, nw := range n.NodeResources.NodeNetworks {
+			if ip := interfaceIP(nw); nw.Device != "" && ip != "" {
+				b.interfaceIPs[nw.Device] = ip
+			}
+		}
+	}
 	return b
 }
 
+// interfaceIP returns the IP address of a node network interface, preferring
+// IPv4 addresses.
+func interfaceIP(nw *structs.NodeNetworkResource) string {
+	for _, addr := range nw.Addresses {
+		if addr.Family == structs.NodeNetworkAF_IPv4 {
+			return addr.Address
+		}
+	}
+	if len(nw.Addresses) > 0 {
+		return nw.Addresses[0].Address
+	}
+	return ""
+}
+
 func (b *Builder) SetAllocDir(dir string) *Builder {
 	b.mu.Lock()
 	b.allocDir = dir
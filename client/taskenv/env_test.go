@@ -693,6 +693,23 @@ func TestEnvironment_DashesInTaskName(t *testing.T) {
 	}
 }
 
+func TestEnvironment_ArrayIndex(t *testing.T) {
+	ci.Parallel(t)
+
+	a := mock.Alloc()
+	a.Name = structs.AllocName(a.JobID, a.TaskGroup, 7)
+	task := a.Job.TaskGroups[0].Tasks[0]
+
+	// Only allocations of job arrays have an array index
+	envMap := NewBuilder(mock.Node(), a, task, "global").Build().Map()
+	require.NotContains(t, envMap, ArrayIndex)
+
+	a.Job.TaskGroups[0].Array = &structs.ArrayConfig{Count: 10}
+	envMap = NewBuilder(mock.Node(), a, task, "global").Build().Map()
+	require.Equal(t, "7", envMap[ArrayIndex])
+	require.Equal(t, "7", envMap[AllocIndex])
+}
+
 // TestEnvironment_UpdateTask asserts env vars and task meta are updated when a
 // task is updated.
 func TestEnvironment_UpdateTask(t *testing.T) {
@@ -853,6 +870,51 @@ func TestEnvironment_TasklessBuilder(t *testing.T) {
 	require.Equal("bar", taskEnv.ReplaceEnv("${NOMAD_META_groupt}"))
 }
 
+func TestEnvironment_RuntimeAttrs(t *testing.T) {
+	ci.Parallel(t)
+
+	node := mock.Node()
+	node.NodeResources.Processors.Topology = structs.MockWorkstationTopology()
+
+	alloc := mock.Alloc()
+	alloc.Name = fmt.Sprintf("%s.%s[2]", alloc.JobID, alloc.TaskGroup)
+	alloc.DeploymentStatus = &structs.AllocDeploymentStatus{Canary: true}
+	alloc.AllocatedResources.Tasks["web"].Cpu.ReservedCores = []uint16{1, 3}
+	task := alloc.Job.TaskGroups[0].Tasks[0]
+
+	service := &structs.Service{
+		Name:       "${runtime.canary}",
+		Tags:       []string{"index=${runtime.alloc_index}", "numa=${runtime.numa_node}"},
+		CanaryTags: []string{"canary=${runtime.canary}"},
+		Meta: map[string]string{
+			"ip":      "${runtime.interface.eth0.ip}",
+			"missing": "${runtime.interface.eth9.ip}",
+		},
+	}
+
+	taskEnv := NewBuilder(node, alloc, task, "global").Build()
+	require.Equal(t, "${runtime.canary}", taskEnv.ReplaceEnv("${runtime.canary}"))
+
+	interpolated := InterpolateService(taskEnv, service)
+	require.Equal(t, "${runtime.canary}", interpolated.Name)
+	require.Equal(t, []string{"index=2", "numa=1"}, interpolated.Tags)
+	require.Equal(t, []string{"canary=true"}, interpolated.CanaryTags)
+	require.Equal(t, map[string]string{
+		"ip":      "192.168.0.100",
+		"missing": "${runtime.interface.eth9.ip}",
+	}, interpolated.Meta)
+
+	// A group level builder uses the cores of all tasks, and a promoted
+	// canary is no longer a canary
+	alloc.DeploymentStatus.Canary = false
+	alloc.AllocatedResources.Tasks["web"].Cpu.ReservedCores = []uint16{0, 1}
+	taskEnv = NewBuilder(node, alloc, nil, "global").Build()
+
+	interpolated = InterpolateService(taskEnv, service)
+	require.Equal(t, []string{"index=2", "numa=0-1"}, interpolated.Tags)
+	require.Equal(t, []string{"canary=false"}, interpolated.CanaryTags)
+}
+
 func TestTaskEnv_ClientPath(t *testing.T) {
 	ci.Parallel(t)
 
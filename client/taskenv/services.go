@@ -53,16 +53,44 @@ func InterpolateService(taskEnv *TaskEnv, origService *structs.Service) *structs
 	service.Name = taskEnv.ReplaceEnv(service.Name)
 	service.PortLabel = taskEnv.ReplaceEnv(service.PortLabel)
 	service.Address = taskEnv.ReplaceEnv(service.Address)
-	service.Tags = taskEnv.ParseAndReplace(service.Tags)
-	service.CanaryTags = taskEnv.ParseAndReplace(service.CanaryTags)
-	service.Meta = interpolateMapStringString(taskEnv, service.Meta)
-	service.CanaryMeta = interpolateMapStringString(taskEnv, service.CanaryMeta)
+	service.Tags = interpolateServiceTags(taskEnv, service.Tags)
+	service.CanaryTags = interpolateServiceTags(taskEnv, service.CanaryTags)
+	service.Meta = interpolateServiceMeta(taskEnv, service.Meta)
+	service.CanaryMeta = interpolateServiceMeta(taskEnv, service.CanaryMeta)
 	service.TaggedAddresses = interpolateMapStringString(taskEnv, service.TaggedAddresses)
 	interpolateConnect(taskEnv, service.Connect)
 
 	return service
 }
 
+// interpolateServiceTags interpolates service tags, which may also reference
+// runtime attributes.
+func interpolateServiceTags(taskEnv *TaskEnv, tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	replaced := make([]string, len(tags))
+	for i, tag := range tags {
+		replaced[i] = taskEnv.replaceEnvRuntime(tag)
+	}
+	return replaced
+}
+
+// interpolateServiceMeta interpolates service meta, whose values may also
+// reference runtime attributes.
+func interpolateServiceMeta(taskEnv *TaskEnv, orig map[string]string) map[string]string {
+	if len(orig) == 0 {
+		return nil
+	}
+
+	m := make(map[string]string, len(orig))
+	for k, v := range orig {
+		m[taskEnv.ReplaceEnv(k)] = taskEnv.replaceEnvRuntime(v)
+	}
+	return m
+}
+
 func interpolateMapStringSliceString(taskEnv *TaskEnv, orig map[string][]string) map[string][]string {
 	if len(orig) == 0 {
 		return nil
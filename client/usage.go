@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package client
+
+import (
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// usageDisabledReportInterval is how often clients report the resource usage
+// of their allocations while usage accounting is disabled on the servers, to
+// detect when it is enabled.
+const usageDisabledReportInterval = 10 * time.Minute
+
+// reportUsage is a long lived goroutine that reports the resource usage of
+// the running allocations to the servers, which account it for the jobs.
+func (c *Client) reportUsage() {
+	interval := structs.AllocUsageReportInterval
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-c.shutdownCh:
+			return
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		args := structs.AllocUsageRequest{
+			NodeID:   c.NodeID(),
+			Usage:    c.allocUsage(),
+			Interval: now.Sub(last),
+			WriteRequest: structs.WriteRequest{
+				Region:    c.Region(),
+				AuthToken: c.secretNodeID(),
+			},
+		}
+		last = now
+
+		var resp structs.AllocUsageResponse
+		err := c.RPC("Node.UpdateAllocUsage", &args, &resp)
+		switch {
+		case err != nil && structs.IsErrUnknownMethod(err):
+			// Servers older than the client don't account usage.
+			interval = usageDisabledReportInterval
+		case err != nil:
+			c.logger.Warn("error reporting allocation usage", "error", err)
+			interval = structs.AllocUsageReportInterval
+		case !resp.Enabled:
+			interval = usageDisabledReportInterval
+		default:
+			interval = structs.AllocUsageReportInterval
+		}
+		timer.Reset(interval)
+	}
+}
+
+// allocUsage returns the latest resource usage of the running allocations.
+func (c *Client) allocUsage() []*structs.AllocUsage {
+	var usage []*structs.AllocUsage
+	for id, ar := range c.getAllocRunners() {
+		if ar.AllocState().ClientStatus != structs.AllocClientStatusRunning {
+			continue
+		}
+
+		stats, err := ar.StatsReporter().LatestAllocStats("")
+		if err != nil || stats == nil || stats.ResourceUsage == nil {
+			continue
+		}
+
+		u := &structs.AllocUsage{AllocID: id}
+		if cpu := stats.ResourceUsage.CpuStats; cpu != nil {
+			u.CPU = uint64(cpu.TotalTicks)
+		}
+		if mem := stats.ResourceUsage.MemoryStats; mem != nil {
+			bytes := mem.RSS
+			if bytes == 0 {
+				bytes = mem.Usage
+			}
+			u.MemoryMB = bytes / (1024 * 1024)
+		}
+		usage = append(usage, u)
+	}
+	return usage
+}
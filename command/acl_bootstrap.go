@@ -188,9 +188,27 @@ func outputACLToken(ui cli.Ui, token *api.ACLToken) {
 		fmt.Sprintf("Global|%v", token.Global),
 		fmt.Sprintf("Create Time|%v", token.CreateTime),
 		fmt.Sprintf("Expiry Time |%s", expiryTimeString(token.ExpirationTime)),
+	}
+
+	// Only output the fields of time-bound and approval-gated tokens when
+	// they are set.
+	if token.NotBefore != nil {
+		kvOutput = append(kvOutput, fmt.Sprintf("Not Before|%v", token.NotBefore))
+	}
+	if token.MaxUses > 0 {
+		kvOutput = append(kvOutput, fmt.Sprintf("Uses|%d/%d", token.Uses, token.MaxUses))
+	}
+	if token.Pending {
+		kvOutput = append(kvOutput, "Pending Approval|true")
+	}
+	if token.ApprovedBy != "" {
+		kvOutput = append(kvOutput, fmt.Sprintf("Approved By|%s", token.ApprovedBy))
+	}
+
+	kvOutput = append(kvOutput,
 		fmt.Sprintf("Create Index|%d", token.CreateIndex),
 		fmt.Sprintf("Modify Index|%d", token.ModifyIndex),
-	}
+	)
 
 	// If the token is a management type, make it obvious that it is not
 	// possible to have policies or roles assigned to it and just output the
@@ -67,14 +67,20 @@ func (a *ACLRoleCommand) Run(_ []string) int { return cli.RunResultHelp }
 // formatACLRole formats and converts the ACL role API object into a string KV
 // representation suitable for console output.
 func formatACLRole(aclRole *api.ACLRole) string {
-	return formatKV([]string{
+	kvOutput := []string{
 		fmt.Sprintf("ID|%s", aclRole.ID),
 		fmt.Sprintf("Name|%s", aclRole.Name),
 		fmt.Sprintf("Description|%s", aclRole.Description),
 		fmt.Sprintf("Policies|%s", strings.Join(aclRolePolicyLinkToStringList(aclRole.Policies), ",")),
+	}
+	if aclRole.RequireApproval {
+		kvOutput = append(kvOutput, "Require Approval|true")
+	}
+	kvOutput = append(kvOutput,
 		fmt.Sprintf("Create Index|%d", aclRole.CreateIndex),
 		fmt.Sprintf("Modify Index|%d", aclRole.ModifyIndex),
-	})
+	)
+	return formatKV(kvOutput)
 }
 
 // aclRolePolicyLinkToStringList converts an array of ACL role policy links to
@@ -19,11 +19,12 @@ var _ cli.Command = &ACLRoleCreateCommand{}
 type ACLRoleCreateCommand struct {
 	Meta
 
-	name        string
-	description string
-	policyNames []string
-	json        bool
-	tmpl        string
+	name            string
+	description     string
+	policyNames     []string
+	requireApproval bool
+	json            bool
+	tmpl            string
 }
 
 // Help satisfies the cli.Command Help function.
@@ -51,6 +52,11 @@ ACL Create Options:
     Specifies a policy to associate with the role identified by their name. This
     flag can be specified multiple times and must be specified at least once.
 
+  -require-approval
+    Create the tokens linked to the role pending approval, so they must be
+    approved by an operator other than their creator before they can be used.
+    Existing tokens can't be linked to the role.
+
   -json
     Output the ACL role in a JSON format.
 
@@ -63,11 +69,12 @@ ACL Create Options:
 func (a *ACLRoleCreateCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(a.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-name":        complete.PredictAnything,
-			"-description": complete.PredictAnything,
-			"-policy":      complete.PredictAnything,
-			"-json":        complete.PredictNothing,
-			"-t":           complete.PredictAnything,
+			"-name":             complete.PredictAnything,
+			"-description":      complete.PredictAnything,
+			"-policy":           complete.PredictAnything,
+			"-require-approval": complete.PredictNothing,
+			"-json":             complete.PredictNothing,
+			"-t":                complete.PredictAnything,
 		})
 }
 
@@ -90,6 +97,7 @@ func (a *ACLRoleCreateCommand) Run(args []string) int {
 		a.policyNames = append(a.policyNames, s)
 		return nil
 	}), "policy", "")
+	flags.BoolVar(&a.requireApproval, "require-approval", false, "")
 	flags.BoolVar(&a.json, "json", false, "")
 	flags.StringVar(&a.tmpl, "t", "", "")
 	if err := flags.Parse(args); err != nil {
@@ -116,9 +124,10 @@ func (a *ACLRoleCreateCommand) Run(args []string) int {
 
 	// Set up the ACL with the passed parameters.
 	aclRole := api.ACLRole{
-		Name:        a.name,
-		Description: a.description,
-		Policies:    aclRolePolicyNamesToPolicyLinks(a.policyNames),
+		Name:            a.name,
+		Description:     a.description,
+		Policies:        aclRolePolicyNamesToPolicyLinks(a.policyNames),
+		RequireApproval: a.requireApproval,
 	}
 
 	// Get the HTTP client.
@@ -4,10 +4,12 @@
 package command
 
 import (
+	"flag"
 	"fmt"
 	"strings"
 
 	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 )
@@ -19,12 +21,13 @@ var _ cli.Command = &ACLRoleUpdateCommand{}
 type ACLRoleUpdateCommand struct {
 	Meta
 
-	name        string
-	description string
-	policyNames []string
-	noMerge     bool
-	json        bool
-	tmpl        string
+	name            string
+	description     string
+	policyNames     []string
+	requireApproval flaghelper.BoolValue
+	noMerge         bool
+	json            bool
+	tmpl            string
 }
 
 // Help satisfies the cli.Command Help function.
@@ -52,6 +55,11 @@ Update Options:
     Specifies a policy to associate with the role identified by their name. This
     flag can be specified multiple times.
 
+  -require-approval
+    Whether the tokens linked to the role are created pending approval, so
+    they must be approved by an operator other than their creator before they
+    can be used. Tokens created before it was set are not affected.
+
   -no-merge
     Do not merge the current role information with what is provided to the
     command. Instead overwrite all fields with the exception of the role ID
@@ -70,12 +78,13 @@ Update Options:
 func (a *ACLRoleUpdateCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(a.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-name":        complete.PredictAnything,
-			"-description": complete.PredictAnything,
-			"-no-merge":    complete.PredictNothing,
-			"-policy":      complete.PredictAnything,
-			"-json":        complete.PredictNothing,
-			"-t":           complete.PredictAnything,
+			"-name":             complete.PredictAnything,
+			"-description":      complete.PredictAnything,
+			"-no-merge":         complete.PredictNothing,
+			"-policy":           complete.PredictAnything,
+			"-require-approval": complete.PredictSet("true", "false"),
+			"-json":             complete.PredictNothing,
+			"-t":                complete.PredictAnything,
 		})
 }
 
@@ -98,6 +107,7 @@ func (a *ACLRoleUpdateCommand) Run(args []string) int {
 		a.policyNames = append(a.policyNames, s)
 		return nil
 	}), "policy", "")
+	flags.Var(&a.requireApproval, "require-approval", "")
 	flags.BoolVar(&a.noMerge, "no-merge", false, "")
 	flags.BoolVar(&a.json, "json", false, "")
 	flags.StringVar(&a.tmpl, "t", "", "")
@@ -105,6 +115,11 @@ func (a *ACLRoleUpdateCommand) Run(args []string) int {
 		return 1
 	}
 
+	var requireApprovalSet bool
+	flags.Visit(func(f *flag.Flag) {
+		requireApprovalSet = requireApprovalSet || f.Name == "require-approval"
+	})
+
 	// Check that we got exactly one argument which is expected to be the ACL
 	// role ID.
 	if len(flags.Args()) != 1 {
@@ -153,10 +168,11 @@ func (a *ACLRoleUpdateCommand) Run(args []string) int {
 			Description: a.description,
 			Policies:    aclRolePolicyNamesToPolicyLinks(a.policyNames),
 		}
+		a.requireApproval.Merge(&updatedRole.RequireApproval)
 	default:
 		// Check that the operator specified at least one flag to update the ACL
 		// role with.
-		if len(a.policyNames) == 0 && a.name == "" && a.description == "" {
+		if len(a.policyNames) == 0 && a.name == "" && a.description == "" && !requireApprovalSet {
 			a.Ui.Error("Please provide at least one flag to update the ACL role")
 			a.Ui.Error(commandErrorText(a))
 			return 1
@@ -172,6 +188,7 @@ func (a *ACLRoleUpdateCommand) Run(args []string) int {
 		if a.description != "" {
 			updatedRole.Description = a.description
 		}
+		a.requireApproval.Merge(&updatedRole.RequireApproval)
 
 		// In order to merge the policy updates, we need to identify if the
 		// specified policy names already exist within the ACL role linking.
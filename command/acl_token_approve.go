@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type ACLTokenApproveCommand struct {
+	Meta
+}
+
+func (c *ACLTokenApproveCommand) Help() string {
+	helpText := `
+Usage: nomad acl token approve [options] <token_accessor_id>
+
+  Approve is used to activate a token created with the -require-approval flag.
+  Requires a management token other than the one used to create the token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Approve Options:
+
+  -json
+    Output the ACL token information in JSON format.
+
+  -t
+    Format and display the ACL token information using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ACLTokenApproveCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *ACLTokenApproveCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *ACLTokenApproveCommand) Synopsis() string {
+	return "Approve a pending ACL token"
+}
+
+func (c *ACLTokenApproveCommand) Name() string { return "acl token approve" }
+
+func (c *ACLTokenApproveCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <token_accessor_id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	tokenAccessorID := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Approve the specified token
+	token, _, err := client.ACLTokens().Approve(tokenAccessorID, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error approving token: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, token)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	// Format the output
+	outputACLToken(c.Ui, token)
+	return 0
+}
@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestACLTokenApproveCommand(t *testing.T) {
+	ci.Parallel(t)
+
+	config := func(c *agent.Config) {
+		c.ACL.Enabled = true
+	}
+
+	srv, _, url := testServer(t, true, config)
+	defer srv.Shutdown()
+
+	// Bootstrap an initial ACL token
+	token := srv.RootToken
+	must.NotNil(t, token)
+
+	ui := cli.NewMockUi()
+	cmd := &ACLTokenApproveCommand{Meta: Meta{Ui: ui, flagAddress: url}}
+	state := srv.Agent.Server().State()
+
+	// Create another management token before the pending token, so it's
+	// allowed to approve it
+	approver := mock.ACLManagementToken()
+	must.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 999, []*structs.ACLToken{approver}))
+
+	// Create a pending token created by the root token
+	mockToken := mock.ACLToken()
+	mockToken.Policies = []string{acl.PolicyWrite}
+	mockToken.Pending = true
+	mockToken.CreatedBy = token.AccessorID
+	mockToken.SetHash()
+	must.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{mockToken}))
+
+	// The creator of the token can't approve it
+	code := cmd.Run([]string{"-address=" + url, "-token=" + token.SecretID, mockToken.AccessorID})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "other than its creator")
+
+	ui.ErrorWriter.Reset()
+
+	// Approve the token using the other management token
+	code = cmd.Run([]string{"-address=" + url, "-token=" + approver.SecretID, mockToken.AccessorID})
+	must.Zero(t, code)
+
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, mockToken.AccessorID)
+	must.StrContains(t, out, approver.AccessorID)
+	must.StrNotContains(t, out, "Pending Approval")
+}
@@ -57,6 +57,21 @@ Create Options:
     a time duration such as "5m" and "1h". By default, tokens will be created
     without a TTL and therefore never expire.
 
+  -not-before
+    Specifies the time before which the token can't be used. This takes the
+    form of an RFC 3339 timestamp, or of a time duration such as "30m" relative
+    to the current time.
+
+  -max-uses
+    Specifies the number of requests the token can authenticate before it is
+    exhausted. Only local tokens can have a maximum number of uses. By default,
+    the number of uses is unlimited.
+
+  -require-approval
+    Creates the token in a pending state. Pending tokens can't be used until
+    approved with "nomad acl token approve" by an operator using a different
+    management token.
+
   -json
     Output the ACL token information in JSON format.
 
@@ -69,15 +84,18 @@ Create Options:
 func (c *ACLTokenCreateCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"name":      complete.PredictAnything,
-			"type":      complete.PredictAnything,
-			"global":    complete.PredictNothing,
-			"policy":    complete.PredictAnything,
-			"role-id":   complete.PredictAnything,
-			"role-name": complete.PredictAnything,
-			"ttl":       complete.PredictAnything,
-			"-json":     complete.PredictNothing,
-			"-t":        complete.PredictAnything,
+			"name":             complete.PredictAnything,
+			"type":             complete.PredictAnything,
+			"global":           complete.PredictNothing,
+			"policy":           complete.PredictAnything,
+			"role-id":          complete.PredictAnything,
+			"role-name":        complete.PredictAnything,
+			"ttl":              complete.PredictAnything,
+			"not-before":       complete.PredictAnything,
+			"max-uses":         complete.PredictAnything,
+			"require-approval": complete.PredictNothing,
+			"-json":            complete.PredictNothing,
+			"-t":               complete.PredictAnything,
 		})
 }
 
@@ -92,8 +110,9 @@ func (c *ACLTokenCreateCommand) Synopsis() string {
 func (c *ACLTokenCreateCommand) Name() string { return "acl token create" }
 
 func (c *ACLTokenCreateCommand) Run(args []string) int {
-	var name, tokenType, ttl, tmpl string
-	var global, json bool
+	var name, tokenType, ttl, notBefore, tmpl string
+	var global, requireApproval, json bool
+	var maxUses uint64
 	var policies []string
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -101,6 +120,9 @@ func (c *ACLTokenCreateCommand) Run(args []string) int {
 	flags.StringVar(&tokenType, "type", "client", "")
 	flags.BoolVar(&global, "global", false, "")
 	flags.StringVar(&ttl, "ttl", "", "")
+	flags.StringVar(&notBefore, "not-before", "", "")
+	flags.Uint64Var(&maxUses, "max-uses", 0, "")
+	flags.BoolVar(&requireApproval, "require-approval", false, "")
 	flags.BoolVar(&json, "json", false, "")
 	flags.StringVar(&tmpl, "t", "", "")
 	flags.Var((funcVar)(func(s string) error {
@@ -134,6 +156,8 @@ func (c *ACLTokenCreateCommand) Run(args []string) int {
 		Policies: policies,
 		Roles:    generateACLTokenRoleLinks(c.roleNames, c.roleIDs),
 		Global:   global,
+		MaxUses:  maxUses,
+		Pending:  requireApproval,
 	}
 
 	// If the user set a TTL flag value, convert this to a time duration and
@@ -147,6 +171,15 @@ func (c *ACLTokenCreateCommand) Run(args []string) int {
 		tk.ExpirationTTL = ttlDuration
 	}
 
+	if notBefore != "" {
+		notBeforeTime, err := parseNotBefore(notBefore, time.Now())
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to parse not-before time: %s", err))
+			return 1
+		}
+		tk.NotBefore = &notBeforeTime
+	}
+
 	// Get the HTTP client
 	client, err := c.Meta.Client()
 	if err != nil {
@@ -176,6 +209,19 @@ func (c *ACLTokenCreateCommand) Run(args []string) int {
 	return 0
 }
 
+// parseNotBefore parses the not-before time of a token, given either as an
+// RFC 3339 timestamp or as a duration relative to now.
+func parseNotBefore(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(d).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be an RFC 3339 timestamp or a duration: %q", s)
+	}
+	return t.UTC(), nil
+}
+
 // generateACLTokenRoleLinks takes the command input role links by ID and name
 // and coverts this to the relevant API object. It handles de-duplicating
 // entries to the best effort, so this doesn't need to be done on the leader.
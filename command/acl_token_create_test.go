@@ -6,6 +6,7 @@ package command
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
@@ -118,3 +119,20 @@ func Test_generateACLTokenRoleLinks(t *testing.T) {
 	}
 	must.SliceContainsAll(t, generateACLTokenRoleLinks(inputRoleNames, inputRoleIDs), expectedOutput)
 }
+
+func Test_parseNotBefore(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	out, err := parseNotBefore("1h", now)
+	must.NoError(t, err)
+	must.Eq(t, now.Add(time.Hour), out)
+
+	out, err = parseNotBefore("2023-03-02T10:00:00+02:00", now)
+	must.NoError(t, err)
+	must.Eq(t, time.Date(2023, time.March, 2, 8, 0, 0, 0, time.UTC), out)
+
+	_, err = parseNotBefore("tomorrow", now)
+	must.ErrorContains(t, err, "must be an RFC 3339 timestamp or a duration")
+}
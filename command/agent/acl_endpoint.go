@@ -4,9 +4,11 @@
 package agent
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -177,6 +179,9 @@ func (s *HTTPServer) ACLTokenSpecificRequest(resp http.ResponseWriter, req *http
 	}
 
 	accessor := strings.TrimPrefix(path, "/v1/acl/token/")
+	if accessor, ok := strings.CutSuffix(accessor, "/approve"); ok {
+		return s.aclTokenApprove(resp, req, accessor)
+	}
 	return s.aclTokenCrud(resp, req, accessor)
 }
 
@@ -272,6 +277,31 @@ func (s *HTTPServer) aclTokenUpdate(resp http.ResponseWriter, req *http.Request,
 	return nil, nil
 }
 
+func (s *HTTPServer) aclTokenApprove(resp http.ResponseWriter, req *http.Request,
+	tokenAccessor string) (interface{}, error) {
+	if !(req.Method == http.MethodPut || req.Method == http.MethodPost) {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	if tokenAccessor == "" {
+		return nil, CodedError(400, "Missing Token Accessor")
+	}
+
+	args := structs.ACLTokenApproveRequest{
+		AccessorID: tokenAccessor,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.ACLTokenUpsertResponse
+	if err := s.agent.RPC(structs.ACLApproveTokenRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	if len(out.Tokens) > 0 {
+		return out.Tokens[0], nil
+	}
+	return nil, nil
+}
+
 func (s *HTTPServer) aclTokenDelete(resp http.ResponseWriter, req *http.Request,
 	tokenAccessor string) (interface{}, error) {
 
@@ -327,6 +357,29 @@ func (s *HTTPServer) ExchangeOneTimeToken(resp http.ResponseWriter, req *http.Re
 	return out, nil
 }
 
+// ACLCreateChildIdentityRequest creates a short-lived child identity, scoped
+// to a subset of capabilities, from the workload identity used to authenticate
+// the request. It is callable via the /v1/acl/identity/child HTTP API.
+func (s *HTTPServer) ACLCreateChildIdentityRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if !(req.Method == http.MethodPut || req.Method == http.MethodPost) {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	var args structs.ChildIdentityRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.ChildIdentityResponse
+	if err := s.agent.RPC(structs.ACLCreateChildIdentityRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ACLRoleListRequest performs a listing of ACL roles and is callable via the
 // /v1/acl/roles HTTP API.
 func (s *HTTPServer) ACLRoleListRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -896,3 +949,166 @@ func (s *HTTPServer) ACLLoginRequest(resp http.ResponseWriter, req *http.Request
 	setIndex(resp, out.Index)
 	return out.ACLToken, nil
 }
+
+// ACLOIDCDeviceAuthRequest starts the OIDC device authorization workflow.
+func (s *HTTPServer) ACLOIDCDeviceAuthRequest(_ http.ResponseWriter, req *http.Request) (interface{}, error) {
+
+	// The endpoint only supports PUT or POST requests.
+	if req.Method != http.MethodPost && req.Method != http.MethodPut {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	var args structs.ACLOIDCDeviceAuthRequest
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+
+	var out structs.ACLOIDCDeviceAuthResponse
+	if err := s.agent.RPC(structs.ACLOIDCDeviceAuthRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ACLOIDCCompleteDeviceAuthRequest completes the OIDC device authorization
+// workflow. The response is the ACL token, or a pending response if the user
+// hasn't authorized the device yet.
+func (s *HTTPServer) ACLOIDCCompleteDeviceAuthRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+
+	// The endpoint only supports PUT or POST requests.
+	if req.Method != http.MethodPost && req.Method != http.MethodPut {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	var args structs.ACLOIDCCompleteDeviceAuthRequest
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+
+	var out structs.ACLOIDCCompleteDeviceAuthResponse
+	if err := s.agent.RPC(structs.ACLOIDCCompleteDeviceAuthRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+	setIndex(resp, out.Index)
+	return out, nil
+}
+
+const (
+	// tokenExchangeGrantType is the OAuth grant type of the token exchange
+	// defined in RFC 8693.
+	tokenExchangeGrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+	// tokenTypeJWT, tokenTypeIDToken, and tokenTypeAccessToken are the token
+	// type identifiers defined in RFC 8693.
+	tokenTypeJWT         = "urn:ietf:params:oauth:token-type:jwt"
+	tokenTypeIDToken     = "urn:ietf:params:oauth:token-type:id_token"
+	tokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+// ACLTokenExchangeRequest implements the OAuth 2.0 token exchange grant
+// defined in RFC 8693. It exchanges a JWT, such as the OIDC token of a CI
+// system, for a Nomad ACL token using a JWT auth method. The optional
+// audience parameter names the auth method, otherwise the default JWT auth
+// method is used. Requests and errors use the OAuth formats so standard OAuth
+// clients can use the endpoint.
+func (s *HTTPServer) ACLTokenExchangeRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+
+	// The endpoint only supports POST requests.
+	if req.Method != http.MethodPost {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	if err := req.ParseForm(); err != nil {
+		return writeOAuthError(resp, http.StatusBadRequest, "invalid_request", err.Error())
+	}
+
+	if grantType := req.PostForm.Get("grant_type"); grantType != tokenExchangeGrantType {
+		return writeOAuthError(resp, http.StatusBadRequest, "unsupported_grant_type",
+			fmt.Sprintf("grant type must be %q", tokenExchangeGrantType))
+	}
+	switch req.PostForm.Get("subject_token_type") {
+	case tokenTypeJWT, tokenTypeIDToken:
+	default:
+		return writeOAuthError(resp, http.StatusBadRequest, "invalid_request",
+			fmt.Sprintf("subject token type must be %q or %q", tokenTypeJWT, tokenTypeIDToken))
+	}
+	if requested := req.PostForm.Get("requested_token_type"); requested != "" && requested != tokenTypeAccessToken {
+		return writeOAuthError(resp, http.StatusBadRequest, "invalid_request",
+			fmt.Sprintf("requested token type must be %q", tokenTypeAccessToken))
+	}
+	if len(req.PostForm["audience"]) > 1 {
+		return writeOAuthError(resp, http.StatusBadRequest, "invalid_target", "only one audience is supported")
+	}
+
+	args := structs.ACLLoginRequest{
+		AuthMethodName: req.PostForm.Get("audience"),
+		LoginToken:     req.PostForm.Get("subject_token"),
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	if args.LoginToken == "" {
+		return writeOAuthError(resp, http.StatusBadRequest, "invalid_request", "missing subject token")
+	}
+
+	if args.AuthMethodName == "" {
+		listArgs := structs.ACLAuthMethodListRequest{
+			QueryOptions: structs.QueryOptions{Region: args.Region},
+		}
+		var listOut structs.ACLAuthMethodListResponse
+		if err := s.agent.RPC(structs.ACLListAuthMethodsRPCMethod, &listArgs, &listOut); err != nil {
+			return nil, err
+		}
+		for _, method := range listOut.AuthMethods {
+			if method.Default && method.Type == structs.ACLAuthMethodTypeJWT {
+				args.AuthMethodName = method.Name
+			}
+		}
+		if args.AuthMethodName == "" {
+			return writeOAuthError(resp, http.StatusBadRequest, "invalid_target",
+				"no audience given and no default JWT auth method found")
+		}
+	}
+
+	var out structs.ACLLoginResponse
+	if err := s.agent.RPC(structs.ACLLoginRPCMethod, &args, &out); err != nil {
+		if code, msg, ok := structs.CodeFromRPCCodedErr(err); ok && code < http.StatusInternalServerError {
+			return writeOAuthError(resp, http.StatusBadRequest, "invalid_grant", msg)
+		}
+		return nil, err
+	}
+
+	exchangeResp := struct {
+		AccessToken     string `json:"access_token"`
+		IssuedTokenType string `json:"issued_token_type"`
+		TokenType       string `json:"token_type"`
+		ExpiresIn       int64  `json:"expires_in,omitempty"`
+	}{
+		AccessToken:     out.ACLToken.SecretID,
+		IssuedTokenType: tokenTypeAccessToken,
+		TokenType:       "Bearer",
+	}
+	if out.ACLToken.ExpirationTime != nil {
+		exchangeResp.ExpiresIn = int64(time.Until(*out.ACLToken.ExpirationTime).Seconds())
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(resp).Encode(exchangeResp)
+	return nil, nil
+}
+
+// writeOAuthError writes an error response in the format defined by RFC 6749.
+func writeOAuthError(resp http.ResponseWriter, code int, oauthErr, description string) (interface{}, error) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.Header().Set("Cache-Control", "no-store")
+	resp.WriteHeader(code)
+	_ = json.NewEncoder(resp).Encode(map[string]string{
+		"error":             oauthErr,
+		"error_description": description,
+	})
+	return nil, nil
+}
@@ -5,10 +5,12 @@ package agent
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -2036,3 +2038,94 @@ func TestHTTPServer_ACLLoginRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPServer_ACLTokenExchangeRequest(t *testing.T) {
+	ci.Parallel(t)
+
+	httpACLTest(t, nil, func(testAgent *TestAgent) {
+
+		exchange := func(form url.Values) (*httptest.ResponseRecorder, map[string]any) {
+			req, err := http.NewRequest(http.MethodPost, "/v1/acl/token-exchange",
+				strings.NewReader(form.Encode()))
+			must.NoError(t, err)
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			respW := httptest.NewRecorder()
+
+			obj, err := testAgent.Server.ACLTokenExchangeRequest(respW, req)
+			must.NoError(t, err)
+			must.Nil(t, obj)
+
+			var body map[string]any
+			must.NoError(t, json.Unmarshal(respW.Body.Bytes(), &body))
+			return respW, body
+		}
+
+		// Generate a sample JWT and a default JWT ACL auth method to
+		// validate it.
+		claims := jwt.MapClaims{
+			"iss": "nomad test suite",
+			"iat": time.Now().Unix(),
+			"nbf": time.Now().Unix(),
+			"exp": time.Now().Add(time.Hour).Unix(),
+			"aud": "engineering",
+		}
+		token, pubKey, err := mock.SampleJWTokenWithKeys(claims, nil)
+		must.NoError(t, err)
+
+		mockedAuthMethod := mock.ACLJWTAuthMethod()
+		mockedAuthMethod.Default = true
+		mockedAuthMethod.Config.BoundAudiences = []string{"engineering"}
+		mockedAuthMethod.Config.JWTValidationPubKeys = []string{pubKey}
+		mockedAuthMethod.Config.BoundIssuer = []string{"nomad test suite"}
+		must.NoError(t, testAgent.server.State().UpsertACLAuthMethods(
+			10, []*structs.ACLAuthMethod{mockedAuthMethod}))
+
+		mockACLPolicy := mock.ACLPolicy()
+		must.NoError(t, testAgent.server.State().UpsertACLPolicies(
+			structs.MsgTypeTestSetup, 20, []*structs.ACLPolicy{mockACLPolicy}))
+
+		mockBindingRule := mock.ACLBindingRule()
+		mockBindingRule.AuthMethod = mockedAuthMethod.Name
+		mockBindingRule.BindType = structs.ACLBindingRuleBindTypePolicy
+		mockBindingRule.Selector = ""
+		mockBindingRule.BindName = mockACLPolicy.Name
+		must.NoError(t, testAgent.server.State().UpsertACLBindingRules(
+			30, []*structs.ACLBindingRule{mockBindingRule}, true))
+
+		// Only the token exchange grant is supported.
+		respW, body := exchange(url.Values{
+			"grant_type":         {"client_credentials"},
+			"subject_token":      {token},
+			"subject_token_type": {tokenTypeJWT},
+		})
+		must.Eq(t, http.StatusBadRequest, respW.Code)
+		must.Eq(t, "unsupported_grant_type", body["error"])
+
+		// Invalid subject tokens are rejected.
+		respW, body = exchange(url.Values{
+			"grant_type":         {tokenExchangeGrantType},
+			"subject_token":      {"not-a-jwt"},
+			"subject_token_type": {tokenTypeJWT},
+		})
+		must.Eq(t, http.StatusBadRequest, respW.Code)
+		must.Eq(t, "invalid_grant", body["error"])
+
+		// The default JWT auth method is used without an audience.
+		respW, body = exchange(url.Values{
+			"grant_type":         {tokenExchangeGrantType},
+			"subject_token":      {token},
+			"subject_token_type": {tokenTypeIDToken},
+		})
+		must.Eq(t, http.StatusOK, respW.Code)
+		must.Eq(t, "no-store", respW.Header().Get("Cache-Control"))
+		must.Eq(t, tokenTypeAccessToken, body["issued_token_type"])
+		must.Eq(t, "Bearer", body["token_type"])
+
+		secretID, ok := body["access_token"].(string)
+		must.True(t, ok)
+		aclToken, err := testAgent.server.State().ACLTokenBySecretID(nil, secretID)
+		must.NoError(t, err)
+		must.NotNil(t, aclToken)
+		must.Eq(t, []string{mockACLPolicy.Name}, aclToken.Policies)
+	})
+}
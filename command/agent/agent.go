@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -29,6 +30,7 @@ import (
 	"github.com/hashicorp/nomad/client/state"
 	"github.com/hashicorp/nomad/command/agent/consul"
 	"github.com/hashicorp/nomad/command/agent/event"
+	"github.com/hashicorp/nomad/command/agent/flightrecorder"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/bufconndialer"
 	"github.com/hashicorp/nomad/helper/escapingfs"
@@ -132,6 +134,10 @@ type Agent struct {
 	taskAPIServer *builtinAPI
 
 	inmemSink *metrics.InmemSink
+
+	// flightRecorder records the recent activity of the server and client.
+	// It is nil if the flight recorder isn't enabled.
+	flightRecorder *flightrecorder.Recorder
 }
 
 // NewAgent is used to create a new agent with the given configuration
@@ -154,6 +160,9 @@ func NewAgent(config *Config, logger log.InterceptLogger, logOutput io.Writer, i
 		return nil, fmt.Errorf("Failed to initialize Consul client: %v", err)
 	}
 
+	if err := a.setupFlightRecorder(); err != nil {
+		return nil, err
+	}
 	if err := a.setupServer(); err != nil {
 		return nil, err
 	}
@@ -250,6 +259,27 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		conf.RaftConfig.SnapshotThreshold = uint64(*vPtr)
 	}
 
+	if agentConfig.Server.RaftSnapshotSectionCache != nil {
+		conf.RaftSnapshotSectionCache = *agentConfig.Server.RaftSnapshotSectionCache
+	}
+	if vPtr := agentConfig.Server.RaftSnapshotChunkSize; vPtr != nil {
+		size, err := humanize.ParseBytes(*vPtr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse raft_snapshot_chunk_size: %w", err)
+		}
+		if size < 4096 || size > 64*1024*1024 {
+			return nil, fmt.Errorf("raft_snapshot_chunk_size must be between 4KB and 64MB, got %q", *vPtr)
+		}
+		conf.RaftSnapshotChunkSize = int(size)
+	}
+	if vPtr := agentConfig.Server.RaftSnapshotWriteRate; vPtr != nil {
+		rate, err := humanize.ParseBytes(*vPtr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse raft_snapshot_write_rate: %w", err)
+		}
+		conf.RaftSnapshotWriteRate = int64(rate)
+	}
+
 	conf.RaftConfig.ElectionTimeout *= time.Duration(raftMultiplier)
 	conf.RaftConfig.HeartbeatTimeout *= time.Duration(raftMultiplier)
 	conf.RaftConfig.LeaderLeaseTimeout *= time.Duration(raftMultiplier)
@@ -292,6 +322,10 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 	if agentConfig.Server.NonVotingServer {
 		conf.NonVoter = true
 	}
+	if agentConfig.Server.ReadReplica {
+		conf.ReadReplica = true
+		conf.NonVoter = true
+	}
 	if agentConfig.Server.RedundancyZone != "" {
 		conf.RedundancyZone = agentConfig.Server.RedundancyZone
 	}
@@ -357,8 +391,41 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.JobTrackedVersions = *agentConfig.Server.JobTrackedVersions
 	}
+	conf.JobNotifyAllowedHosts = slices.Clone(agentConfig.Server.JobNotifyAllowedHosts)
+
+	if agentConfig.Server.VariablesTrackedVersions != nil {
+		if *agentConfig.Server.VariablesTrackedVersions < 0 {
+			return nil, fmt.Errorf("variables_tracked_versions must not be negative")
+		}
+		conf.VariablesTrackedVersions = *agentConfig.Server.VariablesTrackedVersions
+	}
+	if retention := agentConfig.Server.VariablesDeleteRetention; retention != "" {
+		dur, err := time.ParseDuration(retention)
+		if err != nil {
+			return nil, fmt.Errorf("variables_delete_retention must be a duration: %w", err)
+		}
+		conf.VariablesDeleteRetention = dur
+	}
 
 	conf.OIDCIssuer = agentConfig.Server.OIDCIssuer
+	conf.KEKProviderConfigs = agentConfig.KEKProviders
+
+	for _, webhook := range agentConfig.Server.AdmissionWebhooks {
+		if err := webhook.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid admission_webhook %q: %v", webhook.Name, err)
+		}
+		webhook = webhook.Copy()
+		webhook.Canonicalize()
+		conf.AdmissionWebhooks = append(conf.AdmissionWebhooks, webhook)
+	}
+
+	if usage := agentConfig.Server.Usage; usage != nil {
+		if err := usage.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid usage configuration: %v", err)
+		}
+		conf.Usage = usage.Copy()
+		conf.Usage.Canonicalize()
+	}
 
 	// Set up the bind addresses
 	rpcAddr, err := net.ResolveTCPAddr("tcp", agentConfig.normalizedAddrs.RPC)
@@ -435,6 +502,10 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		}
 		conf.BatchEvalGCThreshold = dur
 	}
+	if limit := agentConfig.Server.EvalHistoryLimit; limit < 0 {
+		return nil, fmt.Errorf("eval_history_limit must be non-negative, got %d", limit)
+	}
+	conf.EvalHistoryLimit = agentConfig.Server.EvalHistoryLimit
 	if gcThreshold := agentConfig.Server.DeploymentGCThreshold; gcThreshold != "" {
 		dur, err := time.ParseDuration(gcThreshold)
 		if err != nil {
@@ -535,6 +606,7 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 	conf.StatsCollectionInterval = agentConfig.Telemetry.collectionInterval
 	conf.DisableDispatchedJobSummaryMetrics = agentConfig.Telemetry.DisableDispatchedJobSummaryMetrics
 	conf.DisableRPCRateMetricsLabels = agentConfig.Telemetry.DisableRPCRateMetricsLabels
+	conf.Tracing = agentConfig.Telemetry.Tracing.Copy()
 
 	if d, err := time.ParseDuration(agentConfig.Limits.RPCHandshakeTimeout); err != nil {
 		return nil, fmt.Errorf("error parsing rpc_handshake_timeout: %v", err)
@@ -601,6 +673,23 @@ func convertServerConfig(agentConfig *Config) (*nomad.Config, error) {
 		conf.RaftBoltNoFreelistSync = bolt.NoFreelistSync
 	}
 
+	// Set the raft log store parameters
+	if logStore := agentConfig.Server.RaftLogStoreConfig; logStore != nil {
+		switch logStore.Backend {
+		case "", nomad.RaftLogStoreBoltDB, nomad.RaftLogStoreWAL:
+			conf.RaftLogStoreBackend = logStore.Backend
+		default:
+			return nil, fmt.Errorf("raft_logstore backend must be %q or %q, not %q",
+				nomad.RaftLogStoreBoltDB, nomad.RaftLogStoreWAL, logStore.Backend)
+		}
+		if wal := logStore.WAL; wal != nil {
+			if wal.SegmentSizeMB < 0 {
+				return nil, fmt.Errorf("raft_logstore wal segment_size_mb must not be negative")
+			}
+			conf.RaftWALSegmentSizeMB = wal.SegmentSizeMB
+		}
+	}
+
 	// Interpret job_max_source_size as bytes from string value
 	if agentConfig.Server.JobMaxSourceSize == nil {
 		agentConfig.Server.JobMaxSourceSize = pointer.Of("1M")
@@ -712,6 +801,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	}
 
 	conf.Servers = agentConfig.Client.Servers
+	conf.PreferReadReplicas = agentConfig.Client.PreferReadReplicas
 	conf.DevMode = agentConfig.DevMode
 	conf.EnableDebug = agentConfig.EnableDebug
 
@@ -732,6 +822,8 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	if agentConfig.Client.AllocMountsDir != "" {
 		conf.AllocMountsDir = agentConfig.Client.AllocMountsDir
 	}
+	conf.LogSpillDir = agentConfig.Client.LogSpillDir
+	conf.LogSinks = slices.Clone(agentConfig.Client.LogSinks)
 	if agentConfig.Client.NetworkInterface != "" {
 		conf.NetworkInterface = agentConfig.Client.NetworkInterface
 	}
@@ -764,6 +856,10 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	conf.MaxDynamicPort = agentConfig.Client.MaxDynamicPort
 	conf.MinDynamicPort = agentConfig.Client.MinDynamicPort
 	conf.DisableRemoteExec = agentConfig.Client.DisableRemoteExec
+	conf.RecordExecSessions = agentConfig.Client.RecordExecSessions
+	conf.ExecRecordingDir = agentConfig.Client.ExecRecordingDir
+	conf.EncryptSecretsDir = agentConfig.Client.EncryptSecretsDir
+	conf.AllocMetadataNodeAttributes = slices.Clone(agentConfig.Client.AllocMetadataNodeAttributes)
 
 	if agentConfig.Client.TemplateConfig != nil {
 		conf.TemplateConfig = agentConfig.Client.TemplateConfig.Copy()
@@ -831,6 +927,13 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 		)
 	}
 
+	// set aside the pool of exclusive cores
+	if agentConfig.Client.ExclusiveCores != "" {
+		cores := idset.Parse[hw.CoreID](agentConfig.Client.ExclusiveCores)
+		conf.ExclusiveCores = cores.Slice()
+	}
+	conf.ExclusiveIsolatedCores = agentConfig.Client.ExclusiveIsolatedCores
+
 	conf.Version = agentConfig.Version
 
 	// Set the Consul configurations
@@ -854,6 +957,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	conf.StatsCollectionInterval = agentConfig.Telemetry.collectionInterval
 	conf.PublishNodeMetrics = agentConfig.Telemetry.PublishNodeMetrics
 	conf.PublishAllocationMetrics = agentConfig.Telemetry.PublishAllocationMetrics
+	conf.Tracing = agentConfig.Telemetry.Tracing.Copy()
 
 	// Set the TLS related configs
 	conf.TLSConfig = agentConfig.TLSConfig
@@ -862,6 +966,7 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	// Set the GC related configs
 	conf.GCInterval = agentConfig.Client.GCInterval
 	conf.GCParallelDestroys = agentConfig.Client.GCParallelDestroys
+	conf.ParallelRestores = agentConfig.Client.ParallelRestores
 	conf.GCDiskUsageThreshold = agentConfig.Client.GCDiskUsageThreshold
 	conf.GCInodeUsageThreshold = agentConfig.Client.GCInodeUsageThreshold
 	conf.GCMaxAllocs = agentConfig.Client.GCMaxAllocs
@@ -906,11 +1011,74 @@ func convertClientConfig(agentConfig *Config) (*clientconfig.Config, error) {
 	}
 	conf.Drain = drainConfig
 
+	edgeConfig, err := clientconfig.EdgeConfigFromAgent(agentConfig.Client.Edge)
+	if err != nil {
+		return nil, fmt.Errorf("invalid edge config: %v", err)
+	}
+	conf.Edge = edgeConfig
+
+	serviceDNSConfig, err := clientconfig.ServiceDNSConfigFromAgent(agentConfig.Client.ServiceDNS)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service_dns config: %v", err)
+	}
+	conf.ServiceDNS = serviceDNSConfig
+
+	evictionConfig, err := clientconfig.EvictionConfigFromAgent(agentConfig.Client.Eviction)
+	if err != nil {
+		return nil, fmt.Errorf("invalid eviction config: %v", err)
+	}
+	conf.Eviction = evictionConfig
+
 	conf.Users = clientconfig.UsersConfigFromAgent(agentConfig.Client.Users)
 
+	dynamicHostVolumesConfig, err := clientconfig.DynamicHostVolumesConfigFromAgent(agentConfig.Client.DynamicHostVolumes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dynamic_host_volumes config: %v", err)
+	}
+	conf.DynamicHostVolumes = dynamicHostVolumesConfig
+
+	allocHookScripts, err := clientconfig.AllocHookScriptsFromAgent(agentConfig.Client.AllocHooks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alloc_hook config: %v", err)
+	}
+	conf.AllocHookScripts = allocHookScripts
+
 	return conf, nil
 }
 
+// setupFlightRecorder starts the flight recorder if it is enabled. The
+// recorder is shared by the server and client of the agent.
+func (a *Agent) setupFlightRecorder() error {
+	conf := a.config.FlightRecorder
+	if !conf.IsEnabled() {
+		return nil
+	}
+	if err := conf.Validate(); err != nil {
+		return fmt.Errorf("invalid flight_recorder configuration: %v", err)
+	}
+	conf = conf.Copy()
+	conf.Canonicalize()
+
+	dumpDir := conf.DumpDir
+	if dumpDir == "" && a.config.DataDir != "" {
+		dumpDir = filepath.Join(a.config.DataDir, "flight-recorder")
+	}
+	if dumpDir == "" && *conf.DumpOnAnomaly {
+		a.logger.Warn("flight recorder has no dump directory, anomalies will not be dumped")
+	}
+
+	a.flightRecorder = flightrecorder.NewRecorder(a.logger, &flightrecorder.Config{
+		Capacity:            conf.Capacity,
+		GoroutineInterval:   conf.GoroutineInterval,
+		DumpDir:             dumpDir,
+		DumpOnAnomaly:       *conf.DumpOnAnomaly,
+		RPCLatencyThreshold: conf.RPCLatencyThreshold,
+		GoroutineThreshold:  conf.GoroutineThreshold,
+		MinDumpInterval:     conf.MinDumpInterval,
+	})
+	return nil
+}
+
 // setupServer is used to setup the server if enabled
 func (a *Agent) setupServer() error {
 	if !a.config.Server.Enabled {
@@ -934,6 +1102,8 @@ func (a *Agent) setupServer() error {
 		return fmt.Errorf("failed to configure keyring: %v", err)
 	}
 
+	conf.FlightRecorder = a.flightRecorder
+
 	// Create the server
 	server, err := nomad.NewServer(conf,
 		a.consulCatalog,           // self service discovery
@@ -1134,6 +1304,8 @@ func (a *Agent) setupClient() error {
 	a.taskAPIServer = newBuiltinAPI()
 	conf.APIListenerRegistrar = a.taskAPIServer
 
+	conf.FlightRecorder = a.flightRecorder
+
 	nomadClient, err := client.NewClient(conf,
 		a.consulCatalog,     // self service discovery
 		a.consulProxiesFunc, // supported Envoy versions fingerprinting
@@ -1275,6 +1447,14 @@ func (a *Agent) Shutdown() error {
 		a.logger.Error("shutting down Consul client failed", "error", err)
 	}
 
+	a.flightRecorder.Shutdown()
+
+	if closer, ok := a.auditor.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			a.logger.Error("closing audit log failed", "error", err)
+		}
+	}
+
 	a.logger.Info("shutdown complete")
 	a.shutdown = true
 	close(a.shutdownCh)
@@ -1439,6 +1619,11 @@ func (a *Agent) GetMetricsSink() *metrics.InmemSink {
 	return a.inmemSink
 }
 
+// GetFlightRecorder returns the flight recorder of the agent.
+func (a *Agent) GetFlightRecorder() *flightrecorder.Recorder {
+	return a.flightRecorder
+}
+
 func (a *Agent) setupConsuls(cfgs []*config.ConsulConfig) error {
 
 	isClient := false
@@ -7,7 +7,10 @@
 package agent
 
 import (
+	"fmt"
+
 	hclog "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/command/agent/audit"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 )
 
@@ -17,11 +20,18 @@ type EnterpriseAgent struct{}
 
 func (a *Agent) setupEnterpriseAgent(log hclog.Logger) error {
 	// configure eventer
-	a.auditor = &noOpAuditor{}
+	auditor, err := audit.NewAuditor(a.config.Audit, a.config.DataDir, log)
+	if err != nil {
+		return fmt.Errorf("failed to configure audit logging: %v", err)
+	}
+	a.auditor = auditor
 
 	return nil
 }
 
 func (a *Agent) entReloadEventer(cfg *config.AuditConfig) error {
+	if auditor, ok := a.auditor.(*audit.Auditor); ok {
+		return auditor.Reload(cfg)
+	}
 	return nil
 }
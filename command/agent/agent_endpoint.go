@@ -749,6 +749,80 @@ func (s *HTTPServer) AgentHostRequest(resp http.ResponseWriter, req *http.Reques
 	return reply, rpcErr
 }
 
+// AgentFlightRecorderRequest runs on servers and clients, and dumps the flight
+// recorder of the agent to add to the nomad operator debug archive.
+func (s *HTTPServer) AgentFlightRecorderRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	aclObj, err := s.ResolveToken(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check agent read permissions
+	var enableDebug bool
+	if srv := s.agent.Server(); srv != nil {
+		enableDebug = srv.GetConfig().EnableDebug
+	} else {
+		enableDebug = s.agent.Client().GetConfig().EnableDebug
+	}
+
+	if !aclObj.AllowAgentDebug(enableDebug) {
+		return nil, structs.ErrPermissionDenied
+	}
+
+	serverID := req.URL.Query().Get("server_id")
+	nodeID := req.URL.Query().Get("node_id")
+
+	if serverID != "" && nodeID != "" {
+		return nil, CodedError(400, "Can only forward to either client node or server")
+	}
+
+	// If no other node is specified, dump our local flight recorder
+	if serverID == "" && nodeID == "" {
+		dump, err := s.agent.GetFlightRecorder().Dump("requested")
+		if err != nil {
+			return nil, CodedError(404, err.Error())
+		}
+		return structs.FlightRecorderResponse{Dump: dump}, nil
+	}
+
+	args := &structs.FlightRecorderRequest{
+		ServerID: serverID,
+		NodeID:   nodeID,
+	}
+
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	var reply structs.FlightRecorderResponse
+	var rpcErr error
+
+	// If serverID is specified, use that to lookup the RPC interface
+	lookupNodeID := nodeID
+	if serverID != "" {
+		lookupNodeID = serverID
+	}
+
+	// The RPC endpoint actually forwards the request to the correct
+	// agent, but we need to use the correct RPC interface.
+	localClient, remoteClient, localServer := s.rpcHandlerForNode(lookupNodeID)
+
+	// Make the RPC call
+	if localClient {
+		rpcErr = s.agent.Client().ClientRPC("Agent.FlightRecorder", args, &reply)
+	} else if remoteClient {
+		rpcErr = s.agent.Client().RPC("Agent.FlightRecorder", args, &reply)
+	} else if localServer {
+		rpcErr = s.agent.Server().RPC("Agent.FlightRecorder", args, &reply)
+	} else {
+		rpcErr = fmt.Errorf("node not found: %s", nodeID)
+	}
+
+	return reply, rpcErr
+}
+
 // AgentSchedulerWorkerInfoRequest is used to query the running state of the
 // agent's scheduler workers.
 func (s *HTTPServer) AgentSchedulerWorkerInfoRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -30,6 +30,7 @@ import (
 	"github.com/hashicorp/nomad/helper/pool"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
@@ -2080,3 +2081,38 @@ func TestHTTP_AgentSchedulerWorkerConfigRequest_Client(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTP_AgentFlightRecorder(t *testing.T) {
+	ci.Parallel(t)
+
+	httpTest(t, func(c *Config) {
+		c.EnableDebug = true
+		c.FlightRecorder = &config.FlightRecorderConfig{Enabled: pointer.Of(true)}
+	}, func(s *TestAgent) {
+		req, err := http.NewRequest(http.MethodGet, "/v1/agent/flight-recorder", nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.AgentFlightRecorderRequest(respW, req)
+		must.NoError(t, err)
+		dump := obj.(structs.FlightRecorderResponse).Dump
+		must.NotNil(t, dump)
+		must.Eq(t, "requested", dump.Reason)
+
+		// Only GET is allowed
+		req, err = http.NewRequest(http.MethodPut, "/v1/agent/flight-recorder", nil)
+		must.NoError(t, err)
+		_, err = s.Server.AgentFlightRecorderRequest(respW, req)
+		must.ErrorContains(t, err, ErrInvalidMethod)
+	})
+
+	httpTest(t, func(c *Config) {
+		c.EnableDebug = true
+	}, func(s *TestAgent) {
+		req, err := http.NewRequest(http.MethodGet, "/v1/agent/flight-recorder", nil)
+		must.NoError(t, err)
+
+		_, err = s.Server.AgentFlightRecorderRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "flight recorder is not enabled")
+	})
+}
@@ -447,6 +447,56 @@ func TestAgent_ServerConfig_PlanRejectionTracker(t *testing.T) {
 	}
 }
 
+func TestAgent_ServerConfig_AdmissionWebhooks(t *testing.T) {
+	ci.Parallel(t)
+
+	conf := DevConfig(nil)
+	require.NoError(t, conf.normalizeAddrs())
+
+	conf.Server.AdmissionWebhooks = []*config.AdmissionWebhookConfig{
+		{
+			Name:    "team",
+			Type:    config.AdmissionWebhookTypeMutating,
+			Address: "https://webhooks.example.com/team",
+		},
+	}
+
+	serverConfig, err := convertServerConfig(conf)
+	require.NoError(t, err)
+	require.Len(t, serverConfig.AdmissionWebhooks, 1)
+	require.Equal(t, config.DefaultAdmissionWebhookTimeout, serverConfig.AdmissionWebhooks[0].Timeout)
+	require.Equal(t, config.AdmissionWebhookFailurePolicyFail, serverConfig.AdmissionWebhooks[0].FailurePolicy)
+
+	// The agent configuration is not modified
+	require.Zero(t, conf.Server.AdmissionWebhooks[0].Timeout)
+
+	conf.Server.AdmissionWebhooks[0].Type = "bogus"
+	_, err = convertServerConfig(conf)
+	require.ErrorContains(t, err, `invalid admission_webhook "team"`)
+}
+
+func TestAgent_ServerConfig_Usage(t *testing.T) {
+	ci.Parallel(t)
+
+	conf := DevConfig(nil)
+	require.NoError(t, conf.normalizeAddrs())
+
+	serverConfig, err := convertServerConfig(conf)
+	require.NoError(t, err)
+	require.False(t, serverConfig.Usage.IsEnabled())
+
+	conf.Server.Usage = &config.UsageConfig{Enabled: pointer.Of(true)}
+	serverConfig, err = convertServerConfig(conf)
+	require.NoError(t, err)
+	require.True(t, serverConfig.Usage.IsEnabled())
+	require.Equal(t, config.DefaultUsageResolution, serverConfig.Usage.Resolution)
+	require.Equal(t, config.DefaultUsageRetention, serverConfig.Usage.Retention)
+
+	conf.Server.Usage.Resolution = time.Second
+	_, err = convertServerConfig(conf)
+	require.ErrorContains(t, err, "invalid usage configuration")
+}
+
 func TestAgent_ServerConfig_RaftMultiplier_Ok(t *testing.T) {
 	ci.Parallel(t)
 
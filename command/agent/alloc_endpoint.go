@@ -70,7 +70,7 @@ func (s *HTTPServer) AllocsRequest(resp http.ResponseWriter, req *http.Request)
 	for _, alloc := range out.Allocations {
 		alloc.SetEventDisplayMessages()
 	}
-	return out.Allocations, nil
+	return projectFields(out.Allocations, parseFields(req))
 }
 
 func (s *HTTPServer) AllocSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -226,6 +226,8 @@ func (s *HTTPServer) ClientAllocRequest(resp http.ResponseWriter, req *http.Requ
 		return s.allocStats(allocID, resp, req)
 	case "exec":
 		return s.allocExec(allocID, resp, req)
+	case "port-forward":
+		return s.allocPortForward(allocID, resp, req)
 	case "snapshot":
 		if s.agent.Client() == nil {
 			return nil, clientNotRunning
@@ -498,11 +500,20 @@ func (s *HTTPServer) allocExec(allocID string, resp http.ResponseWriter, req *ht
 		}
 	}
 
+	noRecord := false
+	if v := req.URL.Query().Get("no_record"); v != "" {
+		noRecord, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("no_record value is not a boolean: %v", err)
+		}
+	}
+
 	args := cstructs.AllocExecRequest{
-		AllocID: allocID,
-		Task:    task,
-		Cmd:     command,
-		Tty:     ttyB,
+		AllocID:  allocID,
+		Task:     task,
+		Cmd:      command,
+		Tty:      ttyB,
+		NoRecord: noRecord,
 	}
 	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
 
@@ -520,6 +531,34 @@ func (s *HTTPServer) allocExec(allocID string, resp http.ResponseWriter, req *ht
 	return s.execStream(conn, &args)
 }
 
+func (s *HTTPServer) allocPortForward(allocID string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	// Build the request and parse the ACL token
+	port, err := strconv.Atoi(req.URL.Query().Get("port"))
+	if err != nil {
+		return nil, CodedError(400, fmt.Sprintf("port value is not an integer: %v", err))
+	}
+
+	args := cstructs.AllocPortForwardRequest{
+		AllocID: allocID,
+		JobID:   req.URL.Query().Get("job"),
+		Port:    port,
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	conn, err := s.wsUpgrader.Upgrade(resp, req, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upgrade connection: %v", err)
+	}
+
+	if err := readWsHandshake(conn.ReadJSON, req, &args.QueryOptions); err != nil {
+		conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(toWsCode(400), err.Error()))
+		return nil, err
+	}
+
+	return s.portForwardStream(conn, &args)
+}
+
 // readWsHandshake reads the websocket handshake message and sets
 // query authentication token, if request requires a handshake
 func readWsHandshake(readFn func(interface{}) error, req *http.Request, q *structs.QueryOptions) error {
@@ -660,6 +699,111 @@ func (s *HTTPServer) execStreamImpl(ws *websocket.Conn, args *cstructs.AllocExec
 	return nil, result
 }
 
+// portForwardStream finds the appropriate RPC handler and then forwards the
+// binary messages of the websocket to and from the streaming RPC
+func (s *HTTPServer) portForwardStream(ws *websocket.Conn, args *cstructs.AllocPortForwardRequest) (any, error) {
+	method := "Allocations.PortForward"
+
+	// Get the correct handler
+	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(args.AllocID)
+	var handler structs.StreamingRpcHandler
+	var handlerErr error
+	if localClient {
+		handler, handlerErr = s.agent.Client().StreamingRpcHandler(method)
+	} else if remoteClient {
+		handler, handlerErr = s.agent.Client().RemoteStreamingRpcHandler(method)
+	} else if localServer {
+		handler, handlerErr = s.agent.Server().StreamingRpcHandler(method)
+	}
+
+	if handlerErr != nil {
+		return nil, CodedError(500, handlerErr.Error())
+	}
+
+	// Create a pipe connecting the (possibly remote) handler to the http response
+	httpPipe, handlerPipe := net.Pipe()
+	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-ctx.Done()
+		httpPipe.Close()
+	}()
+
+	resultCh := make(chan HTTPCodedError, 1)
+
+	// stream data back to the websocket: this should be the only goroutine
+	// that writes to this websocket connection
+	go func() {
+		defer cancel()
+		errCh := make(chan HTTPCodedError, 2)
+
+		// Send the request
+		if err := encoder.Encode(args); err != nil {
+			resultCh <- s.execStreamHandleError(ws, CodedError(500, err.Error()))
+			return
+		}
+
+		// only start this after we've tried to send the initial args
+		go forwardPortForwardInput(ctx, encoder, ws, errCh)
+
+		for {
+			select {
+			case codedErr := <-errCh:
+				resultCh <- s.execStreamHandleError(ws, codedErr)
+				return
+			default:
+			}
+
+			var res cstructs.StreamErrWrapper
+			if err := decoder.Decode(&res); err != nil {
+				errCh <- CodedError(500, err.Error())
+				continue
+			}
+
+			if err := res.Error; err != nil {
+				code := 500
+				if err.Code != nil {
+					code = int(*err.Code)
+				}
+				errCh <- CodedError(code, err.Error())
+				continue
+			}
+			if err := ws.WriteMessage(websocket.BinaryMessage, res.Payload); err != nil {
+				errCh <- CodedError(500, err.Error())
+				continue
+			}
+		}
+	}()
+
+	// start streaming request to streaming RPC - returns when streaming
+	// completes or errors
+	handler(handlerPipe)
+	cancel()
+
+	result := <-resultCh
+	ws.Close()
+	return nil, result
+}
+
+// forwardPortForwardInput forwards the binary messages of the websocket
+// connection to the streaming RPC connection to client
+func forwardPortForwardInput(ctx context.Context, encoder *codec.Encoder, ws *websocket.Conn, errCh chan<- HTTPCodedError) {
+	for ctx.Err() == nil {
+		_, data, err := ws.ReadMessage()
+		if err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+
+		if err := encoder.Encode(&cstructs.StreamErrWrapper{Payload: data}); err != nil {
+			errCh <- CodedError(500, err.Error())
+			return
+		}
+	}
+}
+
 // execStreamHandleError writes a CloseMessage to the websocket if we get an
 // error that isn't a "close error" caused by the RPC pipe finishing up. Note
 // that this should *only* ever be called in the same goroutine as we're
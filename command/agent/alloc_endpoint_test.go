@@ -95,6 +95,32 @@ func TestHTTP_AllocsList(t *testing.T) {
 	})
 }
 
+func TestHTTP_AllocsList_Fields(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		state := s.Agent.server.State()
+		alloc := mock.Alloc()
+		must.NoError(t, state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+		must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+		// Only the requested fields are returned
+		req, err := http.NewRequest(http.MethodGet, "/v1/allocations?fields=ID,clientstatus", nil)
+		must.NoError(t, err)
+		obj, err := s.Server.AllocsRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+		must.Eq(t, []map[string]any{{
+			"ID":           alloc.ID,
+			"ClientStatus": alloc.ClientStatus,
+		}}, obj.([]map[string]any))
+
+		// Unknown fields are rejected
+		req, err = http.NewRequest(http.MethodGet, "/v1/allocations?fields=ID,Unknown", nil)
+		must.NoError(t, err)
+		_, err = s.Server.AllocsRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, `unknown field "Unknown"`)
+	})
+}
+
 func TestHTTP_AllocsPrefixList(t *testing.T) {
 	ci.Parallel(t)
 	httpTest(t, nil, func(s *TestAgent) {
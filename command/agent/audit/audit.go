@@ -0,0 +1,199 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package audit implements audit logging of the HTTP API of the agent. Each
+// request generates an OperationReceived event before it is processed and an
+// OperationComplete event after, which are written as JSON to the configured
+// sinks unless excluded by a filter.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/command/agent/event"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// Auditor writes audit events to sinks. It implements event.Auditor.
+type Auditor struct {
+	logger  hclog.Logger
+	dataDir string
+
+	l        sync.RWMutex
+	enabled  bool
+	sinks    []*namedSink
+	filters  []*filter
+	enforced bool
+}
+
+// Ensure Auditor is an event.Auditor.
+var _ event.Auditor = (*Auditor)(nil)
+
+// NewAuditor returns an Auditor for the given configuration. The dataDir is
+// used for the default file sink if no sinks are configured.
+func NewAuditor(cfg *config.AuditConfig, dataDir string, logger hclog.Logger) (*Auditor, error) {
+	a := &Auditor{
+		logger:  logger.Named("audit"),
+		dataDir: dataDir,
+	}
+	if err := a.Reload(cfg); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Reload replaces the sinks and filters of the auditor. The previous sinks
+// are closed once the new configuration has been applied successfully.
+func (a *Auditor) Reload(cfg *config.AuditConfig) error {
+	enabled := cfg != nil && cfg.Enabled != nil && *cfg.Enabled
+
+	var sinks []*namedSink
+	var filters []*filter
+	if enabled {
+		sinkCfgs := cfg.Sinks
+		if len(sinkCfgs) == 0 {
+			sinkCfgs = []*config.AuditSink{{
+				Name:              "audit",
+				Type:              SinkTypeFile,
+				DeliveryGuarantee: DeliveryEnforced,
+				Format:            FormatJSON,
+			}}
+		}
+
+		for _, sc := range sinkCfgs {
+			s, err := newSink(sc, a.dataDir, a.logger)
+			if err != nil {
+				closeSinks(sinks)
+				return fmt.Errorf("invalid audit configuration: %w", err)
+			}
+			sinks = append(sinks, s)
+		}
+
+		for _, fc := range cfg.Filters {
+			f, err := newFilter(fc)
+			if err != nil {
+				closeSinks(sinks)
+				return fmt.Errorf("invalid audit configuration: %w", err)
+			}
+			filters = append(filters, f)
+		}
+	}
+
+	enforced := false
+	for _, s := range sinks {
+		enforced = enforced || s.enforced
+	}
+
+	a.l.Lock()
+	old := a.sinks
+	a.enabled = enabled
+	a.sinks = sinks
+	a.filters = filters
+	a.enforced = enforced
+	a.l.Unlock()
+
+	closeSinks(old)
+	return nil
+}
+
+// Event writes an audit event to every sink, unless it is excluded by a
+// filter. It returns an error if writing to a sink with enforced delivery
+// failed.
+func (a *Auditor) Event(ctx context.Context, eventType string, payload interface{}) error {
+	a.l.RLock()
+	defer a.l.RUnlock()
+
+	if !a.enabled {
+		return nil
+	}
+
+	if ev, ok := payload.(*Event); ok {
+		for _, f := range a.filters {
+			if f.matches(ev) {
+				return nil
+			}
+		}
+	}
+
+	buf, err := json.Marshal(&entry{
+		CreatedAt: time.Now(),
+		EventType: eventType,
+		Payload:   payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+	buf = append(buf, '\n')
+
+	var mErr *multierror.Error
+	for _, s := range a.sinks {
+		if err := s.Write(buf); err != nil {
+			if !s.enforced {
+				a.logger.Warn("failed to write audit event", "sink", s.name, "error", err)
+				continue
+			}
+			mErr = multierror.Append(mErr, fmt.Errorf("sink %q: %w", s.name, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Enabled returns whether audit logging is enabled.
+func (a *Auditor) Enabled() bool {
+	a.l.RLock()
+	defer a.l.RUnlock()
+	return a.enabled
+}
+
+// SetEnabled enables or disables audit logging without changing the sinks.
+func (a *Auditor) SetEnabled(enabled bool) {
+	a.l.Lock()
+	defer a.l.Unlock()
+	a.enabled = enabled && len(a.sinks) > 0
+}
+
+// DeliveryEnforced returns whether any sink requires delivery of events to be
+// enforced.
+func (a *Auditor) DeliveryEnforced() bool {
+	a.l.RLock()
+	defer a.l.RUnlock()
+	return a.enabled && a.enforced
+}
+
+// Reopen reopens the files and connections of every sink, such as after the
+// audit log has been moved by an external log rotation tool.
+func (a *Auditor) Reopen() error {
+	a.l.RLock()
+	defer a.l.RUnlock()
+
+	var mErr *multierror.Error
+	for _, s := range a.sinks {
+		if err := s.Reopen(); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("sink %q: %w", s.name, err))
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Close closes every sink.
+func (a *Auditor) Close() error {
+	a.l.Lock()
+	defer a.l.Unlock()
+
+	closeSinks(a.sinks)
+	a.sinks = nil
+	a.enabled = false
+	return nil
+}
+
+func closeSinks(sinks []*namedSink) {
+	for _, s := range sinks {
+		s.Close()
+	}
+}
@@ -0,0 +1,351 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/shoenig/test/must"
+)
+
+func testEvent(method, endpoint string) *Event {
+	req := httptest.NewRequest(method, endpoint, nil)
+	return NewHTTPEvent(req, &Auth{AccessorID: "anonymous"}, "default", "127.0.0.1:4646")
+}
+
+func readEntries(t *testing.T, path string) []map[string]any {
+	t.Helper()
+
+	f, err := os.Open(path)
+	must.NoError(t, err)
+	defer f.Close()
+
+	var entries []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e map[string]any
+		must.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		entries = append(entries, e)
+	}
+	must.NoError(t, scanner.Err())
+	return entries
+}
+
+func TestAuditor_DefaultSink(t *testing.T) {
+	ci.Parallel(t)
+
+	dataDir := t.TempDir()
+	a, err := NewAuditor(&config.AuditConfig{Enabled: pointer.Of(true)}, dataDir, hclog.NewNullLogger())
+	must.NoError(t, err)
+	defer a.Close()
+
+	must.True(t, a.Enabled())
+	must.True(t, a.DeliveryEnforced())
+
+	ev := testEvent("GET", "/v1/job/web/summary")
+	must.NoError(t, a.Event(context.Background(), EventType, ev))
+	must.NoError(t, a.Event(context.Background(), EventType, ev.Complete(403, "Permission denied")))
+
+	entries := readEntries(t, filepath.Join(dataDir, "audit", "audit.log"))
+	must.Len(t, 2, entries)
+	must.Eq(t, "audit", entries[0]["event_type"])
+
+	received := entries[0]["payload"].(map[string]any)
+	must.Eq(t, "OperationReceived", received["stage"])
+	must.MapNotContainsKey(t, received, "response")
+
+	complete := entries[1]["payload"].(map[string]any)
+	must.Eq(t, "OperationComplete", complete["stage"])
+	must.Eq(t, received["id"], complete["id"])
+	must.Eq(t, map[string]any{"status_code": 403.0, "error": "Permission denied"}, complete["response"].(map[string]any))
+}
+
+func TestAuditor_Disabled(t *testing.T) {
+	ci.Parallel(t)
+
+	a, err := NewAuditor(&config.AuditConfig{}, t.TempDir(), hclog.NewNullLogger())
+	must.NoError(t, err)
+	must.False(t, a.Enabled())
+	must.False(t, a.DeliveryEnforced())
+	must.NoError(t, a.Event(context.Background(), EventType, testEvent("GET", "/v1/jobs")))
+
+	// Reloading enables audit logging.
+	path := filepath.Join(t.TempDir(), "audit.log")
+	must.NoError(t, a.Reload(&config.AuditConfig{
+		Enabled: pointer.Of(true),
+		Sinks:   []*config.AuditSink{{Name: "file", Type: SinkTypeFile, Path: path}},
+	}))
+	defer a.Close()
+	must.True(t, a.Enabled())
+	must.NoError(t, a.Event(context.Background(), EventType, testEvent("GET", "/v1/jobs")))
+	must.Len(t, 1, readEntries(t, path))
+}
+
+func TestAuditor_Filters(t *testing.T) {
+	ci.Parallel(t)
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	a, err := NewAuditor(&config.AuditConfig{
+		Enabled: pointer.Of(true),
+		Sinks:   []*config.AuditSink{{Name: "file", Type: SinkTypeFile, Path: path}},
+		Filters: []*config.AuditFilter{
+			{
+				Name:       "metrics",
+				Type:       HTTPEvent,
+				Endpoints:  []string{"/v1/metrics"},
+				Stages:     []string{"*"},
+				Operations: []string{"*"},
+			},
+			{
+				Name:       "received reads",
+				Type:       HTTPEvent,
+				Endpoints:  []string{"/v1/job/*"},
+				Stages:     []string{string(OperationReceived)},
+				Operations: []string{"GET"},
+			},
+		},
+	}, "", hclog.NewNullLogger())
+	must.NoError(t, err)
+	defer a.Close()
+
+	ctx := context.Background()
+
+	// Query parameters are ignored when matching endpoints.
+	metrics := testEvent("GET", "/v1/metrics?format=prometheus")
+	must.NoError(t, a.Event(ctx, EventType, metrics))
+	must.NoError(t, a.Event(ctx, EventType, metrics.Complete(200, "")))
+
+	read := testEvent("GET", "/v1/job/web")
+	must.NoError(t, a.Event(ctx, EventType, read))
+	must.NoError(t, a.Event(ctx, EventType, read.Complete(200, "")))
+
+	write := testEvent("PUT", "/v1/job/web")
+	must.NoError(t, a.Event(ctx, EventType, write))
+
+	entries := readEntries(t, path)
+	must.Len(t, 2, entries)
+	must.Eq(t, "OperationComplete", entries[0]["payload"].(map[string]any)["stage"])
+	must.Eq(t, "PUT", entries[1]["payload"].(map[string]any)["request"].(map[string]any)["operation"])
+}
+
+func TestAuditor_DeliveryGuarantee(t *testing.T) {
+	ci.Parallel(t)
+
+	// Reserve an address with nothing listening on it.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	addr := ln.Addr().String()
+	must.NoError(t, ln.Close())
+
+	for _, tc := range []struct {
+		guarantee string
+		expectErr bool
+	}{
+		{guarantee: DeliveryEnforced, expectErr: true},
+		{guarantee: DeliveryBestEffort, expectErr: false},
+	} {
+		t.Run(tc.guarantee, func(t *testing.T) {
+			a, err := NewAuditor(&config.AuditConfig{
+				Enabled: pointer.Of(true),
+				Sinks: []*config.AuditSink{{
+					Name:              "tcp",
+					Type:              SinkTypeTCP,
+					Address:           addr,
+					DeliveryGuarantee: tc.guarantee,
+				}},
+			}, "", hclog.NewNullLogger())
+			must.NoError(t, err)
+			defer a.Close()
+
+			must.Eq(t, tc.expectErr, a.DeliveryEnforced())
+			err = a.Event(context.Background(), EventType, testEvent("GET", "/v1/jobs"))
+			if tc.expectErr {
+				must.ErrorContains(t, err, `sink "tcp"`)
+			} else {
+				must.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuditor_TCPSink(t *testing.T) {
+	ci.Parallel(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	defer ln.Close()
+
+	lines := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		if scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	a, err := NewAuditor(&config.AuditConfig{
+		Enabled: pointer.Of(true),
+		Sinks: []*config.AuditSink{{
+			Name:    "tcp",
+			Type:    SinkTypeTCP,
+			Address: ln.Addr().String(),
+		}},
+	}, "", hclog.NewNullLogger())
+	must.NoError(t, err)
+	defer a.Close()
+
+	must.NoError(t, a.Event(context.Background(), EventType, testEvent("DELETE", "/v1/job/web")))
+
+	var e map[string]any
+	must.NoError(t, json.Unmarshal([]byte(<-lines), &e))
+	must.Eq(t, "DELETE", e["payload"].(map[string]any)["request"].(map[string]any)["operation"])
+}
+
+func TestAuditor_InvalidConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name   string
+		cfg    *config.AuditConfig
+		expErr string
+	}{
+		{
+			name: "sink type",
+			cfg: &config.AuditConfig{
+				Sinks: []*config.AuditSink{{Name: "s", Type: "kafka"}},
+			},
+			expErr: `unsupported type "kafka"`,
+		},
+		{
+			name: "delivery guarantee",
+			cfg: &config.AuditConfig{
+				Sinks: []*config.AuditSink{{Name: "s", DeliveryGuarantee: "maybe"}},
+			},
+			expErr: `invalid delivery_guarantee "maybe"`,
+		},
+		{
+			name: "tcp address",
+			cfg: &config.AuditConfig{
+				Sinks: []*config.AuditSink{{Name: "s", Type: SinkTypeTCP}},
+			},
+			expErr: "address is required",
+		},
+		{
+			name: "filter type",
+			cfg: &config.AuditConfig{
+				Filters: []*config.AuditFilter{{Name: "f", Type: "RPCEvent"}},
+			},
+			expErr: `unsupported type "RPCEvent"`,
+		},
+		{
+			name: "filter stage",
+			cfg: &config.AuditConfig{
+				Filters: []*config.AuditFilter{{Name: "f", Type: HTTPEvent, Stages: []string{"Done"}}},
+			},
+			expErr: `invalid stage "Done"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cfg.Enabled = pointer.Of(true)
+			_, err := NewAuditor(tc.cfg, t.TempDir(), hclog.NewNullLogger())
+			must.ErrorContains(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestFileSink_Rotate(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	s, err := newFileSink(&config.AuditSink{
+		Path:           filepath.Join(dir, "audit.log"),
+		RotateBytes:    10,
+		RotateMaxFiles: 2,
+	}, "")
+	must.NoError(t, err)
+	defer s.Close()
+
+	for i := 0; i < 5; i++ {
+		must.NoError(t, s.Write([]byte("0123456789\n")))
+	}
+
+	archives, err := filepath.Glob(filepath.Join(dir, "audit-*.log"))
+	must.NoError(t, err)
+	must.Len(t, 2, archives)
+
+	info, err := os.Stat(filepath.Join(dir, "audit.log"))
+	must.NoError(t, err)
+	must.Eq(t, 11, info.Size())
+	must.Eq(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestTCPSink_Redial(t *testing.T) {
+	ci.Parallel(t)
+
+	// Reserve an address with nothing listening on it.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	addr := ln.Addr().String()
+	must.NoError(t, ln.Close())
+
+	s, err := newTCPSink(&config.AuditSink{Address: addr})
+	must.NoError(t, err)
+	defer s.Close()
+
+	must.ErrorContains(t, s.Write([]byte("{}\n")), "failed to connect")
+
+	// Writes fail without dialing again until the redial interval passes
+	must.ErrorContains(t, s.Write([]byte("{}\n")), "is unavailable")
+}
+
+func TestQueuedSink_Full(t *testing.T) {
+	ci.Parallel(t)
+
+	block := make(chan struct{})
+	inner := &blockingSink{block: block}
+	q := newQueuedSink(inner, hclog.NewNullLogger())
+
+	// At most one entry is taken off the queue by the blocked writer, so the
+	// queue fills up and further entries are dropped.
+	var err error
+	for i := 0; i < bestEffortQueueSize+2 && err == nil; i++ {
+		err = q.Write([]byte("{}\n"))
+	}
+	must.ErrorContains(t, err, "queue is full")
+
+	close(block)
+	must.NoError(t, q.Close())
+}
+
+// blockingSink is a sink whose writes block until block is closed.
+type blockingSink struct {
+	block chan struct{}
+}
+
+func (s *blockingSink) Write([]byte) error {
+	<-s.block
+	return nil
+}
+
+func (s *blockingSink) Reopen() error { return nil }
+
+func (s *blockingSink) Close() error { return nil }
@@ -0,0 +1,187 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package audit
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Stage is the stage of the request lifecycle an audit event was generated
+// in.
+type Stage string
+
+const (
+	// OperationReceived is the stage of events generated before a request is
+	// processed.
+	OperationReceived Stage = "OperationReceived"
+
+	// OperationComplete is the stage of events generated after a request has
+	// been processed, but before the response body is returned.
+	OperationComplete Stage = "OperationComplete"
+)
+
+const (
+	// EventType is the event type of audit log entries.
+	EventType = "audit"
+
+	// HTTPEvent is the filter type matching events of HTTP requests.
+	HTTPEvent = "HTTPEvent"
+
+	// eventVersion is the version of the audit event format.
+	eventVersion = 1
+)
+
+// Event is an audit event for an HTTP request.
+type Event struct {
+	ID        string    `json:"id"`
+	Stage     Stage     `json:"stage"`
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	Version   int       `json:"version"`
+	Auth      *Auth     `json:"auth,omitempty"`
+	Request   *Request  `json:"request"`
+	Response  *Response `json:"response,omitempty"`
+}
+
+// Auth is the identity that made the request.
+type Auth struct {
+	AccessorID string    `json:"accessor_id,omitempty"`
+	Name       string    `json:"name,omitempty"`
+	Policies   []string  `json:"policies,omitempty"`
+	Roles      []string  `json:"roles,omitempty"`
+	Global     bool      `json:"global,omitempty"`
+	CreateTime time.Time `json:"create_time"`
+
+	// Workload is set instead of the token fields if the request was
+	// authenticated with a workload identity.
+	Workload *Workload `json:"workload,omitempty"`
+
+	// ClientID is set instead of the token fields if the request was
+	// authenticated as a Nomad client node.
+	ClientID string `json:"client_id,omitempty"`
+}
+
+// Workload is the workload identity that made the request.
+type Workload struct {
+	Namespace    string `json:"namespace"`
+	JobID        string `json:"job_id"`
+	AllocationID string `json:"allocation_id"`
+	TaskName     string `json:"task_name,omitempty"`
+	ServiceName  string `json:"service_name,omitempty"`
+}
+
+// Request is the metadata of an HTTP request.
+type Request struct {
+	ID          string       `json:"id"`
+	Operation   string       `json:"operation"`
+	Endpoint    string       `json:"endpoint"`
+	Namespace   *Namespace   `json:"namespace,omitempty"`
+	RequestMeta *RequestMeta `json:"request_meta"`
+	NodeMeta    *NodeMeta    `json:"node_meta"`
+}
+
+// Namespace is the namespace targeted by a request.
+type Namespace struct {
+	ID string `json:"id"`
+}
+
+// RequestMeta is the metadata of the client that made a request.
+type RequestMeta struct {
+	RemoteAddress string `json:"remote_address"`
+	UserAgent     string `json:"user_agent"`
+}
+
+// NodeMeta is the metadata of the agent that received a request.
+type NodeMeta struct {
+	IP string `json:"ip"`
+}
+
+// Response is the result of a request.
+type Response struct {
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+}
+
+// NewHTTPEvent returns the OperationReceived event for an HTTP request made
+// to the agent listening on nodeAddr.
+func NewHTTPEvent(req *http.Request, auth *Auth, namespace, nodeAddr string) *Event {
+	return &Event{
+		ID:        uuid.Generate(),
+		Stage:     OperationReceived,
+		Type:      EventType,
+		Timestamp: time.Now(),
+		Version:   eventVersion,
+		Auth:      auth,
+		Request: &Request{
+			ID:        uuid.Generate(),
+			Operation: req.Method,
+			Endpoint:  req.URL.RequestURI(),
+			Namespace: &Namespace{ID: namespace},
+			RequestMeta: &RequestMeta{
+				RemoteAddress: req.RemoteAddr,
+				UserAgent:     req.UserAgent(),
+			},
+			NodeMeta: &NodeMeta{IP: nodeAddr},
+		},
+	}
+}
+
+// AuthFromIdentity returns the audit identity of an authenticated request. It
+// returns nil if the request was not authenticated.
+func AuthFromIdentity(ident *structs.AuthenticatedIdentity) *Auth {
+	switch {
+	case ident == nil:
+		return nil
+	case ident.ACLToken != nil:
+		token := ident.ACLToken
+		auth := &Auth{
+			AccessorID: token.AccessorID,
+			Name:       token.Name,
+			Policies:   token.Policies,
+			Global:     token.Global,
+			CreateTime: token.CreateTime,
+		}
+		for _, role := range token.Roles {
+			auth.Roles = append(auth.Roles, role.ID)
+		}
+		return auth
+	case ident.Claims != nil:
+		return &Auth{
+			Workload: &Workload{
+				Namespace:    ident.Claims.Namespace,
+				JobID:        ident.Claims.JobID,
+				AllocationID: ident.Claims.AllocationID,
+				TaskName:     ident.Claims.TaskName,
+				ServiceName:  ident.Claims.ServiceName,
+			},
+		}
+	case ident.ClientID != "":
+		return &Auth{ClientID: ident.ClientID}
+	default:
+		return nil
+	}
+}
+
+// Complete returns a copy of the event for the OperationComplete stage with
+// the given response.
+func (e *Event) Complete(statusCode int, errMsg string) *Event {
+	ne := *e
+	ne.Stage = OperationComplete
+	ne.Response = &Response{
+		StatusCode: statusCode,
+		Error:      errMsg,
+	}
+	return &ne
+}
+
+// entry is the envelope written to sinks for each event.
+type entry struct {
+	CreatedAt time.Time `json:"created_at"`
+	EventType string    `json:"event_type"`
+	Payload   any       `json:"payload"`
+}
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package audit
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	glob "github.com/ryanuber/go-glob"
+)
+
+// filter excludes matching events from being written to sinks.
+type filter struct {
+	name       string
+	endpoints  []string
+	stages     []string
+	operations []string
+}
+
+func newFilter(cfg *config.AuditFilter) (*filter, error) {
+	if cfg.Type != HTTPEvent {
+		return nil, fmt.Errorf("filter %q has unsupported type %q", cfg.Name, cfg.Type)
+	}
+
+	for _, stage := range cfg.Stages {
+		switch Stage(stage) {
+		case OperationReceived, OperationComplete, "*":
+		default:
+			return nil, fmt.Errorf("filter %q has invalid stage %q", cfg.Name, stage)
+		}
+	}
+
+	return &filter{
+		name:       cfg.Name,
+		endpoints:  cfg.Endpoints,
+		stages:     cfg.Stages,
+		operations: cfg.Operations,
+	}, nil
+}
+
+// matches returns true if the event should be filtered out. Query parameters
+// of the endpoint are ignored. An empty list of patterns matches everything.
+func (f *filter) matches(e *Event) bool {
+	endpoint, _, _ := strings.Cut(e.Request.Endpoint, "?")
+
+	return matchAny(f.endpoints, endpoint) &&
+		matchAny(f.stages, string(e.Stage)) &&
+		matchAny(f.operations, e.Request.Operation)
+}
+
+func matchAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if glob.Glob(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
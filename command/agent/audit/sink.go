@@ -0,0 +1,465 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	gsyslog "github.com/hashicorp/go-syslog"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// SinkTypeFile writes events to a file, with optional rotation.
+	SinkTypeFile = "file"
+
+	// SinkTypeSyslog writes events to the local syslog daemon.
+	SinkTypeSyslog = "syslog"
+
+	// SinkTypeTCP writes newline delimited events to a TCP endpoint.
+	SinkTypeTCP = "tcp"
+
+	// DeliveryEnforced halts requests whose events fail to be written.
+	DeliveryEnforced = "enforced"
+
+	// DeliveryBestEffort logs failures to write events but allows the
+	// request to proceed.
+	DeliveryBestEffort = "best-effort"
+
+	// FormatJSON is the only supported output format.
+	FormatJSON = "json"
+
+	defaultRotateDuration = 24 * time.Hour
+	defaultFileMode       = 0o600
+	defaultSyslogFacility = "LOCAL0"
+	defaultSyslogTag      = "nomad-audit"
+	tcpDialTimeout        = 5 * time.Second
+	tcpWriteTimeout       = 5 * time.Second
+	tcpRedialInterval     = 10 * time.Second
+
+	// bestEffortQueueSize is the number of entries buffered for a sink with
+	// best-effort delivery before further entries are dropped.
+	bestEffortQueueSize = 1024
+)
+
+// sink is a destination for audit log entries.
+type sink interface {
+	// Write writes a single encoded entry, including its trailing newline.
+	Write(entry []byte) error
+
+	// Reopen reopens any underlying file or connection.
+	Reopen() error
+
+	// Close releases the resources of the sink.
+	Close() error
+}
+
+// namedSink is a sink along with its configuration.
+type namedSink struct {
+	sink
+	name     string
+	enforced bool
+}
+
+func newSink(cfg *config.AuditSink, dataDir string, logger hclog.Logger) (*namedSink, error) {
+	ns := &namedSink{name: cfg.Name}
+
+	switch cfg.DeliveryGuarantee {
+	case "", DeliveryEnforced:
+		ns.enforced = true
+	case DeliveryBestEffort:
+	default:
+		return nil, fmt.Errorf("sink %q has invalid delivery_guarantee %q", cfg.Name, cfg.DeliveryGuarantee)
+	}
+
+	if cfg.Format != "" && cfg.Format != FormatJSON {
+		return nil, fmt.Errorf("sink %q has unsupported format %q", cfg.Name, cfg.Format)
+	}
+
+	var err error
+	switch cfg.Type {
+	case "", SinkTypeFile:
+		ns.sink, err = newFileSink(cfg, dataDir)
+	case SinkTypeSyslog:
+		ns.sink, err = newSyslogSink(cfg)
+	case SinkTypeTCP:
+		ns.sink, err = newTCPSink(cfg)
+	default:
+		err = fmt.Errorf("unsupported type %q", cfg.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sink %q: %w", cfg.Name, err)
+	}
+
+	// Requests don't wait on sinks with best-effort delivery, so a slow or
+	// unavailable sink can't hold up the HTTP API.
+	if !ns.enforced {
+		ns.sink = newQueuedSink(ns.sink, logger.With("sink", cfg.Name))
+	}
+	return ns, nil
+}
+
+// queuedSink writes entries to a sink from a bounded queue in the background,
+// dropping entries when the queue is full.
+type queuedSink struct {
+	sink   sink
+	logger hclog.Logger
+
+	queue    chan []byte
+	shutdown chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+func newQueuedSink(s sink, logger hclog.Logger) *queuedSink {
+	q := &queuedSink{
+		sink:     s,
+		logger:   logger,
+		queue:    make(chan []byte, bestEffortQueueSize),
+		shutdown: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+func (q *queuedSink) run() {
+	defer close(q.done)
+	for {
+		select {
+		case <-q.shutdown:
+			return
+		case entry := <-q.queue:
+			if err := q.sink.Write(entry); err != nil {
+				q.logger.Warn("failed to write audit event", "error", err)
+			}
+		}
+	}
+}
+
+func (q *queuedSink) Write(entry []byte) error {
+	select {
+	case q.queue <- entry:
+		return nil
+	default:
+		return fmt.Errorf("queue is full, dropping event")
+	}
+}
+
+func (q *queuedSink) Reopen() error {
+	return q.sink.Reopen()
+}
+
+func (q *queuedSink) Close() error {
+	q.once.Do(func() { close(q.shutdown) })
+	<-q.done
+	return q.sink.Close()
+}
+
+// fileSink writes entries to a file, rotating it by size or age.
+type fileSink struct {
+	path           string
+	mode           os.FileMode
+	rotateBytes    int64
+	rotateDuration time.Duration
+	rotateMaxFiles int
+
+	l        sync.Mutex
+	f        *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func newFileSink(cfg *config.AuditSink, dataDir string) (*fileSink, error) {
+	path := cfg.Path
+	if path == "" {
+		if dataDir == "" {
+			return nil, fmt.Errorf("path is required when data_dir is not set")
+		}
+		path = filepath.Join(dataDir, "audit", "audit.log")
+	}
+
+	mode := os.FileMode(defaultFileMode)
+	if cfg.Mode != "" {
+		m, err := strconv.ParseUint(cfg.Mode, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mode %q: %w", cfg.Mode, err)
+		}
+		mode = os.FileMode(m)
+	}
+
+	rotateDuration := cfg.RotateDuration
+	if rotateDuration == 0 {
+		rotateDuration = defaultRotateDuration
+	}
+
+	s := &fileSink{
+		path:           path,
+		mode:           mode,
+		rotateBytes:    int64(cfg.RotateBytes),
+		rotateDuration: rotateDuration,
+		rotateMaxFiles: cfg.RotateMaxFiles,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// open opens the file for appending. The lock must be held.
+func (s *fileSink) open() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, s.mode)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to stat audit log: %w", err)
+	}
+
+	s.f = f
+	s.size = info.Size()
+	s.openedAt = time.Now()
+	return nil
+}
+
+func (s *fileSink) Write(entry []byte) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if s.f == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+
+	n := int64(len(entry))
+	if s.shouldRotate(n) {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := s.f.Write(entry); err != nil {
+		return err
+	}
+	s.size += n
+	return nil
+}
+
+// shouldRotate returns true if writing n more bytes requires the file to be
+// rotated first. The lock must be held.
+func (s *fileSink) shouldRotate(n int64) bool {
+	if s.size == 0 {
+		return false
+	}
+	if s.rotateBytes > 0 && s.size+n > s.rotateBytes {
+		return true
+	}
+	return time.Since(s.openedAt) >= s.rotateDuration
+}
+
+// rotate moves the current file aside with a timestamp suffix, opens a new
+// one, and prunes old archives. The lock must be held.
+func (s *fileSink) rotate() error {
+	if err := s.f.Close(); err != nil {
+		return err
+	}
+	s.f = nil
+
+	ext := filepath.Ext(s.path)
+	base := strings.TrimSuffix(s.path, ext)
+	archive := fmt.Sprintf("%s-%d%s", base, time.Now().UnixNano(), ext)
+	if err := os.Rename(s.path, archive); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+
+	if err := s.open(); err != nil {
+		return err
+	}
+	return s.prune(base, ext)
+}
+
+// prune removes the oldest archives beyond the maximum number of files.
+func (s *fileSink) prune(base, ext string) error {
+	if s.rotateMaxFiles <= 0 {
+		return nil
+	}
+
+	archives, err := filepath.Glob(base + "-*" + ext)
+	if err != nil {
+		return err
+	}
+	if len(archives) <= s.rotateMaxFiles {
+		return nil
+	}
+
+	// Archive names embed the rotation time, so lexical order is age order
+	// for timestamps of the same length.
+	sort.Strings(archives)
+	for _, archive := range archives[:len(archives)-s.rotateMaxFiles] {
+		if err := os.Remove(archive); err != nil {
+			return fmt.Errorf("failed to remove old audit log: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *fileSink) Reopen() error {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if s.f != nil {
+		s.f.Close()
+		s.f = nil
+	}
+	return s.open()
+}
+
+func (s *fileSink) Close() error {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
+
+// syslogSink writes entries to the local syslog daemon.
+type syslogSink struct {
+	logger gsyslog.Syslogger
+}
+
+func newSyslogSink(cfg *config.AuditSink) (*syslogSink, error) {
+	facility := cfg.Facility
+	if facility == "" {
+		facility = defaultSyslogFacility
+	}
+	tag := cfg.Tag
+	if tag == "" {
+		tag = defaultSyslogTag
+	}
+
+	l, err := gsyslog.NewLogger(gsyslog.LOG_INFO, facility, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	return &syslogSink{logger: l}, nil
+}
+
+func (s *syslogSink) Write(entry []byte) error {
+	return s.logger.WriteLevel(gsyslog.LOG_INFO, bytes.TrimSuffix(entry, []byte("\n")))
+}
+
+func (s *syslogSink) Reopen() error { return nil }
+
+func (s *syslogSink) Close() error { return nil }
+
+// tcpSink writes newline delimited entries to a TCP endpoint, reconnecting
+// when a write fails. After a failed connection attempt, writes fail without
+// dialing again until tcpRedialInterval has passed, so an unavailable
+// endpoint doesn't cost every request a dial timeout.
+type tcpSink struct {
+	address string
+
+	l        sync.Mutex
+	conn     net.Conn
+	redialAt time.Time
+}
+
+func newTCPSink(cfg *config.AuditSink) (*tcpSink, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("address is required")
+	}
+	if _, _, err := net.SplitHostPort(cfg.Address); err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", cfg.Address, err)
+	}
+
+	// Connections are established on the first write so an unavailable
+	// endpoint does not prevent the agent from starting.
+	return &tcpSink{address: cfg.Address}, nil
+}
+
+// connect dials the endpoint unless a previous attempt failed recently. The
+// lock must be held.
+func (s *tcpSink) connect() error {
+	if now := time.Now(); now.Before(s.redialAt) {
+		return fmt.Errorf("%s is unavailable, retrying in %s", s.address, s.redialAt.Sub(now).Round(time.Second))
+	}
+
+	conn, err := net.DialTimeout("tcp", s.address, tcpDialTimeout)
+	if err != nil {
+		s.redialAt = time.Now().Add(tcpRedialInterval)
+		return fmt.Errorf("failed to connect to %s: %w", s.address, err)
+	}
+	s.conn = conn
+	return nil
+}
+
+func (s *tcpSink) Write(entry []byte) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	// Retry once on a fresh connection, since the endpoint may have closed an
+	// idle connection. Entries that were partially written are not retried,
+	// since that would leave a truncated line in the stream.
+	for i := 0; ; i++ {
+		fresh := s.conn == nil
+		if fresh {
+			if err := s.connect(); err != nil {
+				return err
+			}
+		}
+
+		if err := s.conn.SetWriteDeadline(time.Now().Add(tcpWriteTimeout)); err != nil {
+			s.conn.Close()
+			s.conn = nil
+			return err
+		}
+		n, err := s.conn.Write(entry)
+		if err == nil {
+			return nil
+		}
+		s.conn.Close()
+		s.conn = nil
+		if n > 0 || fresh || i > 0 {
+			return fmt.Errorf("failed to write to %s: %w", s.address, err)
+		}
+	}
+}
+
+func (s *tcpSink) Reopen() error {
+	return s.Close()
+}
+
+func (s *tcpSink) Close() error {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -26,8 +26,10 @@ import (
 	checkpoint "github.com/hashicorp/go-checkpoint"
 	discover "github.com/hashicorp/go-discover"
 	hclog "github.com/hashicorp/go-hclog"
+	multierror "github.com/hashicorp/go-multierror"
 	gsyslog "github.com/hashicorp/go-syslog"
 	"github.com/hashicorp/logutils"
+	"github.com/hashicorp/nomad/command/agent/metricspush"
 	"github.com/hashicorp/nomad/helper"
 	flaghelper "github.com/hashicorp/nomad/helper/flags"
 	gatedwriter "github.com/hashicorp/nomad/helper/gated-writer"
@@ -38,6 +40,7 @@ import (
 	"github.com/hashicorp/nomad/version"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
+	prometheusclient "github.com/prometheus/client_golang/prometheus"
 )
 
 // gracefulTimeout controls how long we wait before forcefully terminating
@@ -55,9 +58,11 @@ type Command struct {
 	args           []string
 	agent          *Agent
 	httpServers    []*HTTPServer
+	grpcServer     *GRPCServer
 	logFilter      *logutils.LevelFilter
 	logOutput      io.Writer
 	retryJoinErrCh chan struct{}
+	metricsPusher  *metricspush.Pusher
 }
 
 func (c *Command) readConfig() *Config {
@@ -87,10 +92,12 @@ func (c *Command) readConfig() *Config {
 	var devConnectMode bool
 	var devConsulMode bool
 	var devVaultMode bool
+	var devEdgeMode bool
 	flags.BoolVar(&devMode, "dev", false, "")
 	flags.BoolVar(&devConnectMode, "dev-connect", false, "")
 	flags.BoolVar(&devConsulMode, "dev-consul", false, "")
 	flags.BoolVar(&devVaultMode, "dev-vault", false, "")
+	flags.BoolVar(&devEdgeMode, "dev-edge", false, "")
 	flags.BoolVar(&cmdConfig.Server.Enabled, "server", false, "")
 	flags.BoolVar(&cmdConfig.Client.Enabled, "client", false, "")
 
@@ -236,6 +243,7 @@ func (c *Command) readConfig() *Config {
 		connectMode: devConnectMode,
 		consulMode:  devConsulMode,
 		vaultMode:   devVaultMode,
+		edgeMode:    devEdgeMode,
 	}
 	if devConfig.enabled() {
 		err := devConfig.validate()
@@ -473,6 +481,22 @@ func (c *Command) IsValidConfig(config, cmdConfig *Config) bool {
 				hn.Name, hn.ReservedPorts, err))
 			return false
 		}
+
+		// Ensure the dynamic port range is valid, defaulting to the client's
+		// range for the unset bound
+		minPort, maxPort := config.Client.MinDynamicPort, config.Client.MaxDynamicPort
+		if hn.MinDynamicPort != 0 {
+			minPort = hn.MinDynamicPort
+		}
+		if hn.MaxDynamicPort != 0 {
+			maxPort = hn.MaxDynamicPort
+		}
+		if minPort < 0 || minPort > structs.MaxValidPort ||
+			maxPort < 0 || maxPort > structs.MaxValidPort || minPort > maxPort {
+			c.Ui.Error(fmt.Sprintf("Invalid dynamic port range for host_network[%q]: min_dynamic_port=%d and max_dynamic_port=%d",
+				hn.Name, minPort, maxPort))
+			return false
+		}
 	}
 
 	if err := config.Client.Artifact.Validate(); err != nil {
@@ -640,6 +664,15 @@ func (c *Command) setupAgent(config *Config, logger hclog.InterceptLogger, logOu
 	}
 	c.httpServers = httpServers
 
+	// Setup the gRPC server if enabled
+	grpcServer, err := NewGRPCServer(agent, config)
+	if err != nil {
+		agent.Shutdown()
+		c.Ui.Error(fmt.Sprintf("Error starting grpc server: %s", err))
+		return err
+	}
+	c.grpcServer = grpcServer
+
 	for _, vault := range config.Vaults {
 		if vault.Token != "" {
 			logger.Warn("Setting a Vault token in the agent configuration is deprecated and will be removed in Nomad 1.9. Migrate your Vault configuration to use workload identity.", "cluster", vault.Name)
@@ -701,6 +734,7 @@ func (c *Command) AutocompleteFlags() complete.Flags {
 	return map[string]complete.Predictor{
 		"-dev":                         complete.PredictNothing,
 		"-dev-connect":                 complete.PredictNothing,
+		"-dev-edge":                    complete.PredictNothing,
 		"-server":                      complete.PredictNothing,
 		"-client":                      complete.PredictNothing,
 		"-bootstrap-expect":            complete.PredictAnything,
@@ -844,7 +878,17 @@ func (c *Command) Run(args []string) int {
 		return 1
 	}
 
+	// Start pushing metrics to remote endpoints
+	if err := c.setupMetricsPush(config, logger); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing metrics push: %s", err))
+		c.agent.Shutdown()
+		return 1
+	}
+
 	defer func() {
+		if c.metricsPusher != nil {
+			c.metricsPusher.Stop()
+		}
 		c.agent.Shutdown()
 
 		// Shutdown the http server at the end, to ease debugging if
@@ -854,6 +898,7 @@ func (c *Command) Run(args []string) int {
 				srv.Shutdown()
 			}
 		}
+		c.grpcServer.Shutdown()
 	}()
 
 	// Join startup nodes if specified
@@ -1054,14 +1099,15 @@ WAIT:
 	}
 }
 
-// reloadHTTPServer shuts down the existing HTTP server and restarts it. This
-// is helpful when reloading the agent configuration.
+// reloadHTTPServer shuts down the existing HTTP and gRPC servers and restarts
+// them. This is helpful when reloading the agent configuration.
 func (c *Command) reloadHTTPServer() error {
 	c.agent.logger.Info("reloading HTTP server with new TLS configuration")
 
 	for _, srv := range c.httpServers {
 		srv.Shutdown()
 	}
+	c.grpcServer.Shutdown()
 
 	httpServers, err := NewHTTPServers(c.agent, c.agent.config)
 	if err != nil {
@@ -1069,6 +1115,12 @@ func (c *Command) reloadHTTPServer() error {
 	}
 	c.httpServers = httpServers
 
+	grpcServer, err := NewGRPCServer(c.agent, c.agent.config)
+	if err != nil {
+		return err
+	}
+	c.grpcServer = grpcServer
+
 	return nil
 }
 
@@ -1155,6 +1207,42 @@ func (c *Command) handleReload() {
 	}
 }
 
+// setupMetricsPush validates the metrics push endpoints and starts pushing
+// metrics to them.
+func (c *Command) setupMetricsPush(agentConfig *Config, logger hclog.Logger) error {
+	telConfig := agentConfig.Telemetry
+	if telConfig == nil || (len(telConfig.PrometheusRemoteWrite) == 0 && len(telConfig.OTLPMetrics) == 0) {
+		return nil
+	}
+
+	var mErr *multierror.Error
+	canonicalize := func(block string, cfgs []*config.MetricsPushConfig) []*config.MetricsPushConfig {
+		out := make([]*config.MetricsPushConfig, 0, len(cfgs))
+		for _, cfg := range cfgs {
+			cfg = cfg.Copy()
+			cfg.Canonicalize()
+			if err := cfg.Validate(); err != nil {
+				mErr = multierror.Append(mErr, fmt.Errorf("telemetry.%s %q: %v", block, cfg.Name, err))
+			}
+			out = append(out, cfg)
+		}
+		return out
+	}
+	remoteWrite := canonicalize("prometheus_remote_write", telConfig.PrometheusRemoteWrite)
+	otlp := canonicalize("otlp_metrics", telConfig.OTLPMetrics)
+	if err := mErr.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	pusher, err := metricspush.New(logger, prometheusclient.DefaultGatherer, remoteWrite, otlp)
+	if err != nil {
+		return err
+	}
+	pusher.Start()
+	c.metricsPusher = pusher
+	return nil
+}
+
 // setupTelemetry is used ot setup the telemetry sub-systems
 func (c *Command) setupTelemetry(config *Config) (*metrics.InmemSink, error) {
 	/* Setup telemetry
@@ -1213,8 +1301,9 @@ func (c *Command) setupTelemetry(config *Config) (*metrics.InmemSink, error) {
 		fanout = append(fanout, sink)
 	}
 
-	// Configure the prometheus sink
-	if telConfig.PrometheusMetrics {
+	// Configure the prometheus sink. Pushed metrics are read from the
+	// prometheus registry, so the sink is also needed to push metrics.
+	if telConfig.PrometheusMetrics || len(telConfig.PrometheusRemoteWrite) > 0 || len(telConfig.OTLPMetrics) > 0 {
 		promSink, err := prometheus.NewPrometheusSink()
 		if err != nil {
 			return inm, err
@@ -1327,6 +1416,9 @@ func (c *Command) getBindAddrSynopsis() string {
 
 	b := new(strings.Builder)
 	fmt.Fprintf(b, "HTTP: %s", c.agent.config.normalizedAddrs.HTTP)
+	if c.grpcServer != nil {
+		fmt.Fprintf(b, "; gRPC: %s", c.grpcServer.Addr)
+	}
 
 	if c.agent.server != nil {
 		if c.agent.config.normalizedAddrs.RPC != "" {
@@ -1448,6 +1540,11 @@ General Options (clients and servers):
     Starts the agent in development mode with a default Vault configuration
     for Nomad workload identity.
 
+  -dev-edge
+    Starts the agent in development mode with the client-local edge scheduler
+    enabled, keeping system jobs running while the client cannot reach the
+    servers. Uses short grace periods to make disconnections easy to test.
+
 Server Options:
 
   -server
@@ -136,14 +136,19 @@ type Config struct {
 	// for security bulletins
 	DisableAnonymousSignature bool `hcl:"disable_anonymous_signature"`
 
-	// Consuls is a slice derived from multiple `consul` blocks, here to support
-	// features in Nomad Enterprise.
+	// Consuls is a slice derived from multiple `consul` blocks, one for each
+	// named Consul cluster.
 	Consuls []*config.ConsulConfig `hcl:"-"`
 
-	// Vaults is a slice derived from multiple `vault` blocks, here to support
-	// features in Nomad Enterprise.
+	// Vaults is a slice derived from multiple `vault` blocks, one for each
+	// named Vault cluster.
 	Vaults []*config.VaultConfig `hcl:"-"`
 
+	// KEKProviders is a slice derived from the `keyring` blocks, which
+	// configure the providers of the key encryption keys that wrap the
+	// server's root keys.
+	KEKProviders []*structs.KEKProviderConfig `hcl:"-"`
+
 	// UI is used to configure the web UI
 	UI *config.UIConfig `hcl:"ui"`
 
@@ -190,6 +195,9 @@ type Config struct {
 	// Reporting is used to enable go census reporting
 	Reporting *config.ReportingConfig `hcl:"reporting,block"`
 
+	// FlightRecorder configures the flight recorder of the agent.
+	FlightRecorder *config.FlightRecorderConfig `hcl:"flight_recorder"`
+
 	// ExtraKeysHCL is used by hcl to surface unexpected keys
 	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
 }
@@ -226,9 +234,21 @@ type ClientConfig struct {
 	// AllocMountsDir is the directory for storing mounts into allocation data
 	AllocMountsDir string `hcl:"alloc_mounts_dir"`
 
+	// LogSpillDir is the directory that task logs are written to when the
+	// task's log backpressure policy is "spill" and logmon can't keep up.
+	LogSpillDir string `hcl:"log_spill_dir"`
+
+	// LogSinks are the names of the log sink plugins that the output of
+	// tasks is shipped to, unless the task configures its own log sinks.
+	LogSinks []string `hcl:"log_sinks"`
+
 	// Servers is a list of known server addresses. These are as "host:port"
 	Servers []string `hcl:"servers"`
 
+	// PreferReadReplicas makes the client watch for allocation updates on the
+	// read replicas of the region, if there are any.
+	PreferReadReplicas bool `hcl:"prefer_read_replicas"`
+
 	// NodeClass is used to group the node by class
 	NodeClass string `hcl:"node_class"`
 
@@ -275,6 +295,15 @@ type ClientConfig struct {
 	// ReservableCores is used to override detected reservable cpu cores.
 	ReservableCores string `hcl:"reservable_cores"`
 
+	// ExclusiveCores is the set of cpu cores set aside as a pool of cores
+	// that are never shared, and are only reserved by tasks asking for
+	// exclusive cores.
+	ExclusiveCores string `hcl:"exclusive_cores"`
+
+	// ExclusiveIsolatedCores adds the cpu cores isolated by the kernel
+	// (isolcpus) to the pool of exclusive cores.
+	ExclusiveIsolatedCores bool `hcl:"exclusive_isolated_cores"`
+
 	// MaxKillTimeout allows capping the user-specifiable KillTimeout.
 	MaxKillTimeout string `hcl:"max_kill_timeout"`
 
@@ -308,6 +337,10 @@ type ClientConfig struct {
 	// collector will allow.
 	GCParallelDestroys int `hcl:"gc_parallel_destroys"`
 
+	// ParallelRestores is the number of allocations restored in parallel
+	// when the client starts.
+	ParallelRestores int `hcl:"parallel_restores"`
+
 	// GCDiskUsageThreshold is the disk usage threshold given as a percent
 	// beyond which the Nomad client triggers GC of terminal allocations
 	GCDiskUsageThreshold float64 `hcl:"gc_disk_usage_threshold"`
@@ -327,6 +360,22 @@ type ClientConfig struct {
 	// DisableRemoteExec disables remote exec targeting tasks on this client
 	DisableRemoteExec bool `hcl:"disable_remote_exec"`
 
+	// RecordExecSessions records the output of exec sessions targeting tasks
+	// on this client.
+	RecordExecSessions bool `hcl:"record_exec_sessions"`
+
+	// ExecRecordingDir is the directory exec session recordings are written
+	// to. Defaults to the log dir of the allocation.
+	ExecRecordingDir string `hcl:"exec_recording_dir"`
+
+	// EncryptSecretsDir encrypts task secrets and private directories with
+	// the Encrypting File System. Only supported on Windows.
+	EncryptSecretsDir bool `hcl:"encrypt_secrets_dir"`
+
+	// AllocMetadataNodeAttributes are glob patterns of the node attributes
+	// written to the alloc metadata file of every task.
+	AllocMetadataNodeAttributes []string `hcl:"alloc_metadata_node_attributes"`
+
 	// TemplateConfig includes configuration for template rendering
 	TemplateConfig *client.ClientTemplateConfig `hcl:"template"`
 
@@ -383,9 +432,29 @@ type ClientConfig struct {
 	// Drain specifies whether to drain the client on shutdown; ignored in dev mode.
 	Drain *config.DrainConfig `hcl:"drain_on_shutdown"`
 
+	// Edge configures the client-local scheduler that keeps pinned system
+	// jobs running while the client is disconnected from the servers.
+	Edge *config.EdgeConfig `hcl:"edge"`
+
+	// Eviction configures the eviction manager that evicts allocations when
+	// the host runs low on memory or disk.
+	Eviction *config.EvictionConfig `hcl:"eviction"`
+
+	// ServiceDNS configures the DNS server serving the records of Nomad
+	// native service discovery.
+	ServiceDNS *config.ServiceDNSConfig `hcl:"service_dns"`
+
 	// Users is used to configure parameters around operating system users.
 	Users *config.UsersConfig `hcl:"users"`
 
+	// DynamicHostVolumes configures the client to create the host volumes
+	// requested by jobs.
+	DynamicHostVolumes *config.DynamicHostVolumesConfig `hcl:"dynamic_host_volumes"`
+
+	// AllocHooks are scripts the client runs on the host at allocation
+	// lifecycle points.
+	AllocHooks []*config.AllocHookScriptConfig `hcl:"alloc_hook"`
+
 	// ExtraKeysHCL is used by hcl to surface unexpected keys
 	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
 }
@@ -397,6 +466,8 @@ func (c *ClientConfig) Copy() *ClientConfig {
 
 	nc := *c
 	nc.Servers = slices.Clone(c.Servers)
+	nc.AllocMetadataNodeAttributes = slices.Clone(c.AllocMetadataNodeAttributes)
+	nc.LogSinks = slices.Clone(c.LogSinks)
 	nc.Options = maps.Clone(c.Options)
 	nc.Meta = maps.Clone(c.Meta)
 	nc.ChrootEnv = maps.Clone(c.ChrootEnv)
@@ -409,7 +480,12 @@ func (c *ClientConfig) Copy() *ClientConfig {
 	nc.NomadServiceDiscovery = pointer.Copy(c.NomadServiceDiscovery)
 	nc.Artifact = c.Artifact.Copy()
 	nc.Drain = c.Drain.Copy()
+	nc.Edge = c.Edge.Copy()
+	nc.Eviction = c.Eviction.Copy()
+	nc.ServiceDNS = c.ServiceDNS.Copy()
 	nc.Users = c.Users.Copy()
+	nc.DynamicHostVolumes = c.DynamicHostVolumes.Copy()
+	nc.AllocHooks = helper.CopySlice(c.AllocHooks)
 	nc.ExtraKeysHCL = slices.Clone(c.ExtraKeysHCL)
 	return &nc
 }
@@ -532,6 +608,10 @@ type ServerConfig struct {
 	// for GC if the eval belongs to a batch job.
 	BatchEvalGCThreshold string `hcl:"batch_eval_gc_threshold"`
 
+	// EvalHistoryLimit is the number of the most recent terminal evaluations
+	// of each job that are kept when they would otherwise be collected by GC.
+	EvalHistoryLimit int `hcl:"eval_history_limit"`
+
 	// DeploymentGCThreshold controls how "old" a deployment must be to be
 	// collected by GC. Age is not the only requirement for a deployment to be
 	// GCed but the threshold can be used to filter by age.
@@ -622,6 +702,11 @@ type ServerConfig struct {
 	// non-voting member of the cluster to help provide read scalability.
 	NonVotingServer bool `hcl:"non_voting_server"`
 
+	// ReadReplica is whether this server is a read replica: a non-voting
+	// member of the cluster that is never promoted to a voter and serves the
+	// stale reads and event streams that request a read replica.
+	ReadReplica bool `hcl:"read_replica"`
+
 	// (Enterprise-only) RedundancyZone is the redundancy zone to use for this server.
 	RedundancyZone string `hcl:"redundancy_zone"`
 
@@ -677,6 +762,9 @@ type ServerConfig struct {
 	// RaftBoltConfig configures boltdb as used by raft.
 	RaftBoltConfig *RaftBoltConfig `hcl:"raft_boltdb"`
 
+	// RaftLogStoreConfig configures the backend of the raft log store.
+	RaftLogStoreConfig *RaftLogStoreConfig `hcl:"raft_logstore"`
+
 	// RaftSnapshotThreshold controls how many outstanding logs there must be
 	// before we perform a snapshot. This is to prevent excessive snapshotting by
 	// replaying a small set of logs instead. The value passed here is the initial
@@ -696,6 +784,21 @@ type ServerConfig struct {
 	// setting used. This can be tuned during operation using a hot reload.
 	RaftTrailingLogs *int `hcl:"raft_trailing_logs"`
 
+	// RaftSnapshotSectionCache enables caching the encoded sections of
+	// snapshots on disk, so later snapshots reuse the sections of tables that
+	// haven't changed since, such as the allocations of a mostly idle
+	// cluster. Snapshots are still written and transferred in full.
+	RaftSnapshotSectionCache *bool `hcl:"raft_snapshot_section_cache"`
+
+	// RaftSnapshotChunkSize is the size of the chunks snapshots are written
+	// in, for example "1MB".
+	RaftSnapshotChunkSize *string `hcl:"raft_snapshot_chunk_size"`
+
+	// RaftSnapshotWriteRate limits the bytes per second at which snapshots
+	// are written, for example "50MB". This spreads the I/O of snapshotting
+	// large state stores over time. Unlimited if unset.
+	RaftSnapshotWriteRate *string `hcl:"raft_snapshot_write_rate"`
+
 	// JobDefaultPriority is the default Job priority if not specified.
 	JobDefaultPriority *int `hcl:"job_default_priority"`
 
@@ -710,10 +813,32 @@ type ServerConfig struct {
 	// JobTrackedVersions is the number of historic job versions that are kept.
 	JobTrackedVersions *int `hcl:"job_tracked_versions"`
 
+	// JobNotifyAllowedHosts is the hosts that the notify webhooks of jobs are
+	// allowed to target. Hosts starting with "*." allow all the subdomains of
+	// the domain.
+	JobNotifyAllowedHosts []string `hcl:"job_notify_allowed_hosts"`
+
+	// VariablesTrackedVersions is the number of prior versions of each
+	// variable that are kept. Zero disables variable history and soft
+	// deletes.
+	VariablesTrackedVersions *int `hcl:"variables_tracked_versions"`
+
+	// VariablesDeleteRetention is how long deleted variables are kept before
+	// they are permanently removed.
+	VariablesDeleteRetention string `hcl:"variables_delete_retention"`
+
 	// OIDCIssuer if set enables OIDC Discovery and uses this value as the
 	// issuer. Third parties such as AWS IAM OIDC Provider expect the issuer to
 	// be a publically accessible HTTPS URL signed by a trusted well-known CA.
 	OIDCIssuer string `hcl:"oidc_issuer"`
+
+	// AdmissionWebhooks are HTTP endpoints that are called, in order, to
+	// mutate or validate jobs before they are registered.
+	AdmissionWebhooks []*config.AdmissionWebhookConfig `hcl:"admission_webhook"`
+
+	// Usage configures the accounting of the resources reserved and used by
+	// jobs, queryable through the usage API.
+	Usage *config.UsageConfig `hcl:"usage"`
 }
 
 func (s *ServerConfig) Copy() *ServerConfig {
@@ -737,12 +862,20 @@ func (s *ServerConfig) Copy() *ServerConfig {
 	ns.ExtraKeysHCL = slices.Clone(s.ExtraKeysHCL)
 	ns.Search = s.Search.Copy()
 	ns.RaftBoltConfig = s.RaftBoltConfig.Copy()
+	ns.RaftLogStoreConfig = s.RaftLogStoreConfig.Copy()
 	ns.RaftSnapshotInterval = pointer.Copy(s.RaftSnapshotInterval)
 	ns.RaftSnapshotThreshold = pointer.Copy(s.RaftSnapshotThreshold)
 	ns.RaftTrailingLogs = pointer.Copy(s.RaftTrailingLogs)
+	ns.RaftSnapshotSectionCache = pointer.Copy(s.RaftSnapshotSectionCache)
+	ns.RaftSnapshotChunkSize = pointer.Copy(s.RaftSnapshotChunkSize)
+	ns.RaftSnapshotWriteRate = pointer.Copy(s.RaftSnapshotWriteRate)
 	ns.JobDefaultPriority = pointer.Copy(s.JobDefaultPriority)
 	ns.JobMaxPriority = pointer.Copy(s.JobMaxPriority)
 	ns.JobTrackedVersions = pointer.Copy(s.JobTrackedVersions)
+	ns.JobNotifyAllowedHosts = slices.Clone(s.JobNotifyAllowedHosts)
+	ns.VariablesTrackedVersions = pointer.Copy(s.VariablesTrackedVersions)
+	ns.AdmissionWebhooks = helper.CopySlice(s.AdmissionWebhooks)
+	ns.Usage = s.Usage.Copy()
 	return &ns
 }
 
@@ -766,6 +899,68 @@ func (r *RaftBoltConfig) Copy() *RaftBoltConfig {
 	return &nr
 }
 
+// RaftLogStoreConfig is used in servers to configure the backend of the raft
+// log store.
+type RaftLogStoreConfig struct {
+	// Backend is the log store backend, either "boltdb" or "wal". Changing
+	// the backend migrates the existing logs when the server starts.
+	//
+	// Default: "boltdb".
+	Backend string `hcl:"backend"`
+
+	// WAL configures the "wal" backend.
+	WAL *RaftWALConfig `hcl:"wal"`
+}
+
+func (r *RaftLogStoreConfig) Copy() *RaftLogStoreConfig {
+	if r == nil {
+		return nil
+	}
+
+	nr := *r
+	nr.WAL = r.WAL.Copy()
+	return &nr
+}
+
+// Merge returns a new RaftLogStoreConfig where non-empty fields in the
+// argument have higher precedence.
+func (r *RaftLogStoreConfig) Merge(o *RaftLogStoreConfig) *RaftLogStoreConfig {
+	switch {
+	case r == nil:
+		return o.Copy()
+	case o == nil:
+		return r.Copy()
+	}
+
+	result := r.Copy()
+	if o.Backend != "" {
+		result.Backend = o.Backend
+	}
+	if o.WAL != nil {
+		result.WAL = o.WAL.Copy()
+	}
+	return result
+}
+
+// RaftWALConfig is used in servers to configure the "wal" raft log store
+// backend.
+type RaftWALConfig struct {
+	// SegmentSizeMB is the size of the WAL segment files. Segments are
+	// rotated once they reach this size.
+	//
+	// Default: 64.
+	SegmentSizeMB int `hcl:"segment_size_mb"`
+}
+
+func (r *RaftWALConfig) Copy() *RaftWALConfig {
+	if r == nil {
+		return nil
+	}
+
+	nr := *r
+	return &nr
+}
+
 // PlanRejectionTracker is used in servers to configure the plan rejection
 // tracker.
 type PlanRejectionTracker struct {
@@ -973,6 +1168,19 @@ type Telemetry struct {
 	// rate is well-controlled but cardinality of requesters is high.
 	DisableRPCRateMetricsLabels bool `hcl:"disable_rpc_rate_metrics_labels"`
 
+	// PrometheusRemoteWrite are Prometheus remote-write endpoints that metrics
+	// are periodically pushed to, for agents that can't be scraped.
+	PrometheusRemoteWrite []*config.MetricsPushConfig `hcl:"prometheus_remote_write"`
+
+	// OTLPMetrics are OpenTelemetry collector endpoints that metrics are
+	// periodically pushed to using OTLP over HTTP.
+	OTLPMetrics []*config.MetricsPushConfig `hcl:"otlp_metrics"`
+
+	// Tracing configures the export of OpenTelemetry spans for the server
+	// RPCs, the scheduling pipeline, and the placement of allocations on
+	// clients.
+	Tracing *config.TracingConfig `hcl:"tracing"`
+
 	// Circonus: see https://github.com/circonus-labs/circonus-gometrics
 	// for more details on the various configuration options.
 	// Valid configuration combinations:
@@ -1057,6 +1265,9 @@ func (t *Telemetry) Copy() *Telemetry {
 	nt.DataDogTags = slices.Clone(t.DataDogTags)
 	nt.PrefixFilter = slices.Clone(t.PrefixFilter)
 	nt.FilterDefault = pointer.Copy(t.FilterDefault)
+	nt.PrometheusRemoteWrite = helper.CopySlice(t.PrometheusRemoteWrite)
+	nt.OTLPMetrics = helper.CopySlice(t.OTLPMetrics)
+	nt.Tracing = t.Tracing.Copy()
 	nt.ExtraKeysHCL = slices.Clone(t.ExtraKeysHCL)
 	return &nt
 }
@@ -1085,6 +1296,9 @@ type Ports struct {
 	HTTP int `hcl:"http"`
 	RPC  int `hcl:"rpc"`
 	Serf int `hcl:"serf"`
+
+	// GRPC is the port of the gRPC API. The gRPC API is disabled if unset.
+	GRPC int `hcl:"grpc"`
 	// ExtraKeysHCL is used by hcl to surface unexpected keys
 	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
 }
@@ -1105,6 +1319,7 @@ type Addresses struct {
 	HTTP string `hcl:"http"`
 	RPC  string `hcl:"rpc"`
 	Serf string `hcl:"serf"`
+	GRPC string `hcl:"grpc"`
 	// ExtraKeysHCL is used by hcl to surface unexpected keys
 	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
 }
@@ -1126,6 +1341,7 @@ type NormalizedAddrs struct {
 	HTTP []string
 	RPC  string
 	Serf string
+	GRPC string
 }
 
 func (n *NormalizedAddrs) Copy() *NormalizedAddrs {
@@ -1186,6 +1402,7 @@ type devModeConfig struct {
 	connectMode bool
 	consulMode  bool
 	vaultMode   bool
+	edgeMode    bool
 
 	bindAddr string
 	iface    string
@@ -1193,7 +1410,7 @@ type devModeConfig struct {
 
 func (mode *devModeConfig) enabled() bool {
 	return mode.defaultMode || mode.connectMode ||
-		mode.consulMode || mode.vaultMode
+		mode.consulMode || mode.vaultMode || mode.edgeMode
 }
 
 func (mode *devModeConfig) validate() error {
@@ -1312,6 +1529,14 @@ func DevConfig(mode *devModeConfig) *Config {
 			TTL:      pointer.Of(time.Hour),
 		}
 	}
+
+	if mode.edgeMode {
+		conf.Client.Edge = &config.EdgeConfig{
+			Enabled:         pointer.Of(true),
+			DisconnectGrace: pointer.Of("10s"),
+			RestartDelay:    pointer.Of("5s"),
+		}
+	}
 	return conf
 }
 
@@ -1343,6 +1568,7 @@ func DefaultConfig() *Config {
 			Reserved:              &Resources{},
 			GCInterval:            1 * time.Minute,
 			GCParallelDestroys:    2,
+			ParallelRestores:      8,
 			GCDiskUsageThreshold:  80,
 			GCInodeUsageThreshold: 70,
 			GCMaxAllocs:           50,
@@ -1558,6 +1784,11 @@ func (c *Config) Merge(b *Config) *Config {
 		result.Audit = result.Audit.Merge(b.Audit)
 	}
 
+	// Apply the FlightRecorder config
+	if b.FlightRecorder != nil {
+		result.FlightRecorder = result.FlightRecorder.Merge(b.FlightRecorder)
+	}
+
 	// Apply the ports config
 	if result.Ports == nil && b.Ports != nil {
 		ports := *b.Ports
@@ -1588,6 +1819,9 @@ func (c *Config) Merge(b *Config) *Config {
 	// Apply the Vault Configurations
 	result.Vaults = mergeVaultConfigs(result.Vaults, b.Vaults)
 
+	// Apply the keyring provider configurations
+	result.KEKProviders = mergeKEKProviderConfigs(result.KEKProviders, b.KEKProviders)
+
 	// Apply the UI Configuration
 	if result.UI == nil && b.UI != nil {
 		uiConfig := *b.UI
@@ -1640,6 +1874,26 @@ func (c *Config) Merge(b *Config) *Config {
 // mergeVaultConfigs takes two slices of VaultConfig and returns a slice
 // containing the superset of all configurations, and with every configuration
 // with the same name merged
+// mergeKEKProviderConfigs merges the keyring provider configurations. A
+// configuration in right replaces the one in left with the same ID.
+func mergeKEKProviderConfigs(left, right []*structs.KEKProviderConfig) []*structs.KEKProviderConfig {
+	if len(left) == 0 && len(right) == 0 {
+		return nil
+	}
+	results := helper.CopySlice(left)
+	for _, src := range right {
+		i := slices.IndexFunc(results, func(dst *structs.KEKProviderConfig) bool {
+			return dst.ID() == src.ID()
+		})
+		if i >= 0 {
+			results[i] = src.Copy()
+		} else {
+			results = append(results, src.Copy())
+		}
+	}
+	return results
+}
+
 func mergeVaultConfigs(left, right []*config.VaultConfig) []*config.VaultConfig {
 	results := []*config.VaultConfig{}
 
@@ -1723,6 +1977,7 @@ func (c *Config) Copy() *Config {
 	nc.DisableUpdateCheck = pointer.Copy(c.DisableUpdateCheck)
 	nc.Consuls = helper.CopySlice(c.Consuls)
 	nc.Vaults = helper.CopySlice(c.Vaults)
+	nc.KEKProviders = helper.CopySlice(c.KEKProviders)
 	nc.UI = c.UI.Copy()
 
 	nc.NomadConfig = c.NomadConfig.Copy()
@@ -1738,6 +1993,7 @@ func (c *Config) Copy() *Config {
 	nc.Limits = c.Limits.Copy()
 	nc.Audit = c.Audit.Copy()
 	nc.Reporting = c.Reporting.Copy()
+	nc.FlightRecorder = c.FlightRecorder.Copy()
 	nc.ExtraKeysHCL = slices.Clone(c.ExtraKeysHCL)
 	return &nc
 }
@@ -1771,11 +2027,20 @@ func (c *Config) normalizeAddrs() error {
 	}
 	c.Addresses.Serf = addr
 
+	addr, err = normalizeBind(c.Addresses.GRPC, c.BindAddr)
+	if err != nil {
+		return fmt.Errorf("Failed to parse gRPC address: %v", err)
+	}
+	c.Addresses.GRPC = addr
+
 	c.normalizedAddrs = &NormalizedAddrs{
 		HTTP: joinHostPorts(httpAddrs, strconv.Itoa(c.Ports.HTTP)),
 		RPC:  net.JoinHostPort(c.Addresses.RPC, strconv.Itoa(c.Ports.RPC)),
 		Serf: net.JoinHostPort(c.Addresses.Serf, strconv.Itoa(c.Ports.Serf)),
 	}
+	if c.Ports.GRPC != 0 {
+		c.normalizedAddrs.GRPC = net.JoinHostPort(c.Addresses.GRPC, strconv.Itoa(c.Ports.GRPC))
+	}
 
 	addr, err = normalizeAdvertise(c.AdvertiseAddrs.HTTP, httpAddrs[0], c.Ports.HTTP, c.DevMode)
 	if err != nil {
@@ -2047,6 +2312,9 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.BatchEvalGCThreshold != "" {
 		result.BatchEvalGCThreshold = b.BatchEvalGCThreshold
 	}
+	if b.EvalHistoryLimit != 0 {
+		result.EvalHistoryLimit = b.EvalHistoryLimit
+	}
 	if b.DeploymentGCThreshold != "" {
 		result.DeploymentGCThreshold = b.DeploymentGCThreshold
 	}
@@ -2107,6 +2375,9 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.NonVotingServer {
 		result.NonVotingServer = true
 	}
+	if b.ReadReplica {
+		result.ReadReplica = true
+	}
 	if b.RedundancyZone != "" {
 		result.RedundancyZone = b.RedundancyZone
 	}
@@ -2165,6 +2436,8 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 		}
 	}
 
+	result.RaftLogStoreConfig = s.RaftLogStoreConfig.Merge(b.RaftLogStoreConfig)
+
 	if b.RaftSnapshotThreshold != nil {
 		result.RaftSnapshotThreshold = pointer.Of(*b.RaftSnapshotThreshold)
 	}
@@ -2176,15 +2449,42 @@ func (s *ServerConfig) Merge(b *ServerConfig) *ServerConfig {
 	if b.RaftTrailingLogs != nil {
 		result.RaftTrailingLogs = pointer.Of(*b.RaftTrailingLogs)
 	}
+	if b.RaftSnapshotSectionCache != nil {
+		result.RaftSnapshotSectionCache = pointer.Of(*b.RaftSnapshotSectionCache)
+	}
+	if b.RaftSnapshotChunkSize != nil {
+		result.RaftSnapshotChunkSize = pointer.Of(*b.RaftSnapshotChunkSize)
+	}
+	if b.RaftSnapshotWriteRate != nil {
+		result.RaftSnapshotWriteRate = pointer.Of(*b.RaftSnapshotWriteRate)
+	}
 
 	if b.JobTrackedVersions != nil {
 		result.JobTrackedVersions = b.JobTrackedVersions
 	}
+	if b.JobNotifyAllowedHosts != nil {
+		result.JobNotifyAllowedHosts = slices.Clone(b.JobNotifyAllowedHosts)
+	}
+
+	if b.VariablesTrackedVersions != nil {
+		result.VariablesTrackedVersions = pointer.Of(*b.VariablesTrackedVersions)
+	}
+	if b.VariablesDeleteRetention != "" {
+		result.VariablesDeleteRetention = b.VariablesDeleteRetention
+	}
 
 	if b.OIDCIssuer != "" {
 		result.OIDCIssuer = b.OIDCIssuer
 	}
 
+	if len(b.AdmissionWebhooks) != 0 {
+		result.AdmissionWebhooks = config.AdmissionWebhookSliceMerge(s.AdmissionWebhooks, b.AdmissionWebhooks)
+	}
+
+	if b.Usage != nil {
+		result.Usage = s.Usage.Merge(b.Usage)
+	}
+
 	// Add the schedulers
 	result.EnabledSchedulers = append(result.EnabledSchedulers, b.EnabledSchedulers...)
 
@@ -2217,6 +2517,12 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.AllocMountsDir != "" {
 		result.AllocMountsDir = b.AllocMountsDir
 	}
+	if b.LogSpillDir != "" {
+		result.LogSpillDir = b.LogSpillDir
+	}
+	if len(b.LogSinks) != 0 {
+		result.LogSinks = b.LogSinks
+	}
 	if b.NodeClass != "" {
 		result.NodeClass = b.NodeClass
 	}
@@ -2265,6 +2571,12 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.ReservableCores != "" {
 		result.ReservableCores = b.ReservableCores
 	}
+	if b.ExclusiveCores != "" {
+		result.ExclusiveCores = b.ExclusiveCores
+	}
+	if b.ExclusiveIsolatedCores {
+		result.ExclusiveIsolatedCores = true
+	}
 	if b.GCInterval != 0 {
 		result.GCInterval = b.GCInterval
 	}
@@ -2274,6 +2586,9 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 	if b.GCParallelDestroys != 0 {
 		result.GCParallelDestroys = b.GCParallelDestroys
 	}
+	if b.ParallelRestores != 0 {
+		result.ParallelRestores = b.ParallelRestores
+	}
 	if b.GCDiskUsageThreshold != 0 {
 		result.GCDiskUsageThreshold = b.GCDiskUsageThreshold
 	}
@@ -2292,12 +2607,31 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 		result.DisableRemoteExec = b.DisableRemoteExec
 	}
 
+	if b.RecordExecSessions {
+		result.RecordExecSessions = b.RecordExecSessions
+	}
+
+	if b.ExecRecordingDir != "" {
+		result.ExecRecordingDir = b.ExecRecordingDir
+	}
+
+	if b.EncryptSecretsDir {
+		result.EncryptSecretsDir = b.EncryptSecretsDir
+	}
+
+	if len(b.AllocMetadataNodeAttributes) != 0 {
+		result.AllocMetadataNodeAttributes = b.AllocMetadataNodeAttributes
+	}
+
 	if b.TemplateConfig != nil {
 		result.TemplateConfig = b.TemplateConfig
 	}
 
 	// Add the servers
 	result.Servers = append(result.Servers, b.Servers...)
+	if b.PreferReadReplicas {
+		result.PreferReadReplicas = true
+	}
 
 	// Add the options map values
 	if result.Options == nil {
@@ -2333,6 +2667,10 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 		result.HostVolumes = structs.HostVolumeSliceMerge(a.HostVolumes, b.HostVolumes)
 	}
 
+	if len(b.AllocHooks) != 0 {
+		result.AllocHooks = config.AllocHookScriptSliceMerge(a.AllocHooks, b.AllocHooks)
+	}
+
 	if b.CNIPath != "" {
 		result.CNIPath = b.CNIPath
 	}
@@ -2372,7 +2710,11 @@ func (a *ClientConfig) Merge(b *ClientConfig) *ClientConfig {
 
 	result.Artifact = a.Artifact.Merge(b.Artifact)
 	result.Drain = a.Drain.Merge(b.Drain)
+	result.Edge = a.Edge.Merge(b.Edge)
+	result.Eviction = a.Eviction.Merge(b.Eviction)
+	result.ServiceDNS = a.ServiceDNS.Merge(b.ServiceDNS)
 	result.Users = a.Users.Merge(b.Users)
+	result.DynamicHostVolumes = a.DynamicHostVolumes.Merge(b.DynamicHostVolumes)
 
 	return &result
 }
@@ -2470,6 +2812,16 @@ func (a *Telemetry) Merge(b *Telemetry) *Telemetry {
 		result.DisableRPCRateMetricsLabels = b.DisableRPCRateMetricsLabels
 	}
 
+	if len(b.PrometheusRemoteWrite) != 0 {
+		result.PrometheusRemoteWrite = config.MetricsPushSliceMerge(a.PrometheusRemoteWrite, b.PrometheusRemoteWrite)
+	}
+	if len(b.OTLPMetrics) != 0 {
+		result.OTLPMetrics = config.MetricsPushSliceMerge(a.OTLPMetrics, b.OTLPMetrics)
+	}
+	if b.Tracing != nil {
+		result.Tracing = a.Tracing.Merge(b.Tracing)
+	}
+
 	return &result
 }
 
@@ -2486,6 +2838,9 @@ func (a *Ports) Merge(b *Ports) *Ports {
 	if b.Serf != 0 {
 		result.Serf = b.Serf
 	}
+	if b.GRPC != 0 {
+		result.GRPC = b.GRPC
+	}
 	return &result
 }
 
@@ -2502,6 +2857,9 @@ func (a *Addresses) Merge(b *Addresses) *Addresses {
 	if b.Serf != "" {
 		result.Serf = b.Serf
 	}
+	if b.GRPC != "" {
+		result.GRPC = b.GRPC
+	}
 	return &result
 }
 
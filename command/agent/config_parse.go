@@ -91,6 +91,18 @@ func ParseConfigFile(path string) (*Config, error) {
 			return nil, fmt.Errorf("error parsing 'consul': %w", err)
 		}
 	}
+	matches = list.Filter("keyring")
+	if len(matches.Items) > 0 {
+		if err := parseKeyringProviders(c, matches); err != nil {
+			return nil, fmt.Errorf("error parsing 'keyring': %w", err)
+		}
+	}
+	matches = list.Filter("telemetry")
+	if len(matches.Items) > 0 {
+		if err := parseMetricsPushRelabel(c, matches); err != nil {
+			return nil, fmt.Errorf("error parsing 'telemetry': %w", err)
+		}
+	}
 
 	// convert strings to time.Durations
 	tds := []durationConversionMap{
@@ -217,6 +229,51 @@ func ParseConfigFile(path string) (*Config, error) {
 			fmt.Sprintf("audit.sink.%d", i), &sink.RotateDuration, &sink.RotateDurationHCL, nil})
 	}
 
+	// Add admission webhooks for time.Duration parsing
+	for i, webhook := range c.Server.AdmissionWebhooks {
+		tds = append(tds, durationConversionMap{
+			fmt.Sprintf("server.admission_webhook.%d.timeout", i), &webhook.Timeout, &webhook.TimeoutHCL, nil})
+	}
+
+	// Add metrics push endpoints for time.Duration parsing
+	for i, push := range c.Telemetry.PrometheusRemoteWrite {
+		tds = append(tds,
+			durationConversionMap{fmt.Sprintf("telemetry.prometheus_remote_write.%d.interval", i), &push.Interval, &push.IntervalHCL, nil},
+			durationConversionMap{fmt.Sprintf("telemetry.prometheus_remote_write.%d.timeout", i), &push.Timeout, &push.TimeoutHCL, nil},
+		)
+	}
+	for i, push := range c.Telemetry.OTLPMetrics {
+		tds = append(tds,
+			durationConversionMap{fmt.Sprintf("telemetry.otlp_metrics.%d.interval", i), &push.Interval, &push.IntervalHCL, nil},
+			durationConversionMap{fmt.Sprintf("telemetry.otlp_metrics.%d.timeout", i), &push.Timeout, &push.TimeoutHCL, nil},
+		)
+	}
+
+	// Add usage accounting for time.Duration parsing
+	if usage := c.Server.Usage; usage != nil {
+		tds = append(tds,
+			durationConversionMap{"server.usage.resolution", &usage.Resolution, &usage.ResolutionHCL, nil},
+			durationConversionMap{"server.usage.retention", &usage.Retention, &usage.RetentionHCL, nil},
+		)
+	}
+
+	// Add flight recorder for time.Duration parsing
+	if recorder := c.FlightRecorder; recorder != nil {
+		tds = append(tds,
+			durationConversionMap{"flight_recorder.goroutine_interval", &recorder.GoroutineInterval, &recorder.GoroutineIntervalHCL, nil},
+			durationConversionMap{"flight_recorder.rpc_latency_threshold", &recorder.RPCLatencyThreshold, &recorder.RPCLatencyThresholdHCL, nil},
+			durationConversionMap{"flight_recorder.min_dump_interval", &recorder.MinDumpInterval, &recorder.MinDumpIntervalHCL, nil},
+		)
+	}
+
+	// Add tracing for time.Duration parsing
+	if tracing := c.Telemetry.Tracing; tracing != nil {
+		tds = append(tds,
+			durationConversionMap{"telemetry.tracing.export_interval", &tracing.ExportInterval, &tracing.ExportIntervalHCL, nil},
+			durationConversionMap{"telemetry.tracing.timeout", &tracing.Timeout, &tracing.TimeoutHCL, nil},
+		)
+	}
+
 	// convert strings to time.Durations
 	err = convertDurations(tds)
 	if err != nil {
@@ -297,6 +354,12 @@ func extraKeys(c *Config) error {
 		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, "host_volume")
 	}
 
+	// Remove AllocHook extra keys
+	for _, h := range c.Client.AllocHooks {
+		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, h.Name)
+		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, "alloc_hook")
+	}
+
 	// Remove HostNetwork extra keys
 	for _, hn := range c.Client.HostNetworks {
 		helper.RemoveEqualFold(&c.Client.ExtraKeysHCL, hn.Name)
@@ -323,6 +386,30 @@ func extraKeys(c *Config) error {
 		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, k)
 	}
 
+	// Remove metrics push extra keys
+	for _, p := range c.Telemetry.PrometheusRemoteWrite {
+		for _, k := range []string{p.Name, "prometheus_remote_write", "headers", "labels", "relabel"} {
+			helper.RemoveEqualFold(&c.Telemetry.ExtraKeysHCL, k)
+		}
+	}
+	for _, p := range c.Telemetry.OTLPMetrics {
+		for _, k := range []string{p.Name, "otlp_metrics", "headers", "labels", "relabel"} {
+			helper.RemoveEqualFold(&c.Telemetry.ExtraKeysHCL, k)
+		}
+	}
+
+	// Remove tracing extra keys
+	if c.Telemetry.Tracing != nil {
+		helper.RemoveEqualFold(&c.Telemetry.ExtraKeysHCL, "tracing")
+		helper.RemoveEqualFold(&c.Telemetry.Tracing.ExtraKeysHCL, "headers")
+	}
+
+	// Remove AdmissionWebhooks extra keys
+	for _, w := range c.Server.AdmissionWebhooks {
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, w.Name)
+		helper.RemoveEqualFold(&c.Server.ExtraKeysHCL, "admission_webhook")
+	}
+
 	for _, k := range []string{"datadog_tags"} {
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, k)
 		helper.RemoveEqualFold(&c.ExtraKeysHCL, "telemetry")
@@ -335,6 +422,10 @@ func extraKeys(c *Config) error {
 	// will incorrectly report them as extra keys, of which there may be multiple
 	c.ExtraKeysHCL = slices.DeleteFunc(c.ExtraKeysHCL, func(s string) bool { return s == "vault" })
 	c.ExtraKeysHCL = slices.DeleteFunc(c.ExtraKeysHCL, func(s string) bool { return s == "consul" })
+	c.ExtraKeysHCL = slices.DeleteFunc(c.ExtraKeysHCL, func(s string) bool { return s == "keyring" })
+	for _, p := range c.KEKProviders {
+		helper.RemoveEqualFold(&c.ExtraKeysHCL, string(p.Provider))
+	}
 
 	if len(c.ExtraKeysHCL) == 0 {
 		c.ExtraKeysHCL = nil
@@ -436,6 +527,118 @@ func parseVaults(c *Config, list *ast.ObjectList) error {
 	return nil
 }
 
+// parseKeyringProviders decodes the `keyring` blocks. The block label is the
+// provider and every field other than name and active is passed to the
+// provider as its configuration.
+func parseKeyringProviders(c *Config, list *ast.ObjectList) error {
+	for _, obj := range list.Items {
+		if len(obj.Keys) == 0 {
+			return fmt.Errorf("keyring block requires a provider label")
+		}
+		provider, ok := obj.Keys[0].Token.Value().(string)
+		if !ok {
+			return fmt.Errorf("keyring block has an invalid provider label")
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, obj.Val); err != nil {
+			return err
+		}
+
+		p := &structs.KEKProviderConfig{
+			Provider: structs.KEKProvider(provider),
+			Config:   map[string]string{},
+		}
+		for k, v := range m {
+			switch k {
+			case "name":
+				if err := mapstructure.WeakDecode(v, &p.Name); err != nil {
+					return fmt.Errorf("keyring %q name: %w", provider, err)
+				}
+			case "active":
+				if err := mapstructure.WeakDecode(v, &p.Active); err != nil {
+					return fmt.Errorf("keyring %q active: %w", provider, err)
+				}
+			default:
+				var value string
+				if err := mapstructure.WeakDecode(v, &value); err != nil {
+					return fmt.Errorf("keyring %q %s: %w", provider, k, err)
+				}
+				p.Config[k] = value
+			}
+		}
+		if err := p.Validate(); err != nil {
+			return err
+		}
+
+		c.KEKProviders = mergeKEKProviderConfigs(c.KEKProviders, []*structs.KEKProviderConfig{p})
+	}
+
+	return nil
+}
+
+// parseMetricsPushRelabel decodes the `relabel` blocks of the metrics push
+// endpoints. The hcl.Decode method can't parse these correctly as HCL1 because
+// they don't have labels, which would result in one rule per field.
+func parseMetricsPushRelabel(c *Config, list *ast.ObjectList) error {
+	for _, telemetry := range list.Items {
+		ot, ok := telemetry.Val.(*ast.ObjectType)
+		if !ok {
+			return fmt.Errorf("should be an object")
+		}
+
+		for _, block := range []struct {
+			name    string
+			configs []*config.MetricsPushConfig
+		}{
+			{"prometheus_remote_write", c.Telemetry.PrometheusRemoteWrite},
+			{"otlp_metrics", c.Telemetry.OTLPMetrics},
+		} {
+			// JSON nests the named blocks inside an object rather than
+			// flattening the name into the keys of the block.
+			var blocks []*ast.ObjectItem
+			for _, obj := range ot.List.Filter(block.name).Items {
+				if len(obj.Keys) > 0 {
+					blocks = append(blocks, obj)
+				} else if inner, ok := obj.Val.(*ast.ObjectType); ok {
+					blocks = append(blocks, inner.List.Items...)
+				}
+			}
+
+			for _, obj := range blocks {
+				name, ok := obj.Keys[0].Token.Value().(string)
+				if !ok {
+					return fmt.Errorf("%s block has an invalid name", block.name)
+				}
+				pushVal, ok := obj.Val.(*ast.ObjectType)
+				if !ok {
+					return fmt.Errorf("%s %q should be an object", block.name, name)
+				}
+
+				var rules []*config.MetricsRelabelConfig
+				for _, item := range pushVal.List.Filter("relabel").Items {
+					var rule config.MetricsRelabelConfig
+					if err := hcl.DecodeObject(&rule, item.Val); err != nil {
+						return fmt.Errorf("%s %q relabel: %w", block.name, name, err)
+					}
+					rules = append(rules, &rule)
+				}
+				if len(rules) == 0 {
+					continue
+				}
+
+				for _, push := range block.configs {
+					if push.Name == name {
+						push.Relabel = append(push.Relabel, rules...)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
 // parseConsuls decodes the `consul` blocks. The hcl.Decode method can't parse
 // these correctly as HCL1 because they don't have labels, which would result in
 // all the blocks getting merged regardless of name.
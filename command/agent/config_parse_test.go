@@ -5,6 +5,7 @@ package agent
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"sort"
 	"testing"
@@ -48,6 +49,8 @@ var basicConfig = &Config{
 		StateDir:       "/tmp/client-state",
 		AllocDir:       "/tmp/alloc",
 		AllocMountsDir: "/tmp/mounts",
+		LogSpillDir:    "/tmp/log-spill",
+		LogSinks:       []string{"loki"},
 		Servers:        []string{"a.b.c:80", "127.0.0.1:1234"},
 		NodeClass:      "linux-medium-64bit",
 		ServerJoin: &ServerJoin{
@@ -84,6 +87,7 @@ var basicConfig = &Config{
 		GCInterval:            6 * time.Second,
 		GCIntervalHCL:         "6s",
 		GCParallelDestroys:    6,
+		ParallelRestores:      4,
 		GCDiskUsageThreshold:  82,
 		GCInodeUsageThreshold: 91,
 		GCMaxAllocs:           50,
@@ -107,6 +111,7 @@ var basicConfig = &Config{
 		EnabledSchedulers:         []string{"test"},
 		NodeGCThreshold:           "12h",
 		EvalGCThreshold:           "12h",
+		EvalHistoryLimit:          20,
 		JobGCInterval:             "3m",
 		JobGCThreshold:            "12h",
 		DeploymentGCThreshold:     "12h",
@@ -1080,3 +1085,111 @@ func TestConfig_MultipleConsul(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_KeyringProviders(t *testing.T) {
+	ci.Parallel(t)
+
+	path := filepath.Join(t.TempDir(), "keyring.hcl")
+	must.NoError(t, os.WriteFile(path, []byte(`
+keyring "aead" {}
+
+keyring "awskms" {
+  active     = true
+  region     = "us-east-1"
+  kms_key_id = "alias/nomad"
+}
+
+keyring "transit" {
+  name     = "other"
+  key_name = "nomad"
+}
+`), 0o644))
+
+	fc, err := LoadConfig(path)
+	must.NoError(t, err)
+	must.Eq(t, []*structs.KEKProviderConfig{
+		{Provider: structs.KEKProviderAEAD, Config: map[string]string{}},
+		{
+			Provider: structs.KEKProviderAWSKMS,
+			Active:   true,
+			Config:   map[string]string{"region": "us-east-1", "kms_key_id": "alias/nomad"},
+		},
+		{
+			Provider: structs.KEKProviderVaultTransit,
+			Name:     "other",
+			Config:   map[string]string{"key_name": "nomad"},
+		},
+	}, fc.KEKProviders)
+
+	// merging a configuration replaces the provider with the same ID
+	cfg := DefaultConfig().Merge(fc)
+	cfg = cfg.Merge(&Config{KEKProviders: []*structs.KEKProviderConfig{
+		{Provider: structs.KEKProviderAEAD, Active: true},
+	}})
+	must.Len(t, 3, cfg.KEKProviders)
+	must.True(t, cfg.KEKProviders[0].Active)
+
+	must.NoError(t, os.WriteFile(path, []byte(`keyring "hsm" {}`), 0o644))
+	_, err = LoadConfig(path)
+	must.ErrorContains(t, err, `unknown keyring provider "hsm"`)
+}
+
+func TestConfig_MetricsPush(t *testing.T) {
+	ci.Parallel(t)
+
+	for _, suffix := range []string{"hcl", "json"} {
+		t.Run(suffix, func(t *testing.T) {
+			fc, err := LoadConfig("testdata/metrics-push." + suffix)
+			must.NoError(t, err)
+
+			cfg := DefaultConfig().Merge(fc)
+			must.Eq(t, []*config.MetricsPushConfig{{
+				Name:         "mimir",
+				Address:      "https://mimir.example.com/api/v1/push",
+				Interval:     15 * time.Second,
+				IntervalHCL:  "15s",
+				Timeout:      5 * time.Second,
+				TimeoutHCL:   "5s",
+				PrefixFilter: []string{"+nomad.client", "-nomad.client.host"},
+				Headers:      map[string]string{"X-Scope-OrgID": "edge"},
+				Labels:       map[string]string{"cluster": "edge-1"},
+				Relabel: []*config.MetricsRelabelConfig{
+					{SourceLabels: []string{"datacenter"}, TargetLabel: "dc"},
+					{Regex: "datacenter", Action: config.RelabelActionLabelDrop},
+				},
+			}}, cfg.Telemetry.PrometheusRemoteWrite)
+			must.Eq(t, []*config.MetricsPushConfig{{
+				Name:          "collector",
+				Address:       "http://127.0.0.1:4318/v1/metrics",
+				FilterDefault: pointer.Of(false),
+				CAFile:        "/etc/nomad.d/collector-ca.pem",
+			}}, cfg.Telemetry.OTLPMetrics)
+
+			for _, push := range cfg.Telemetry.PrometheusRemoteWrite {
+				push.Canonicalize()
+				must.NoError(t, push.Validate())
+			}
+		})
+	}
+}
+
+func TestConfig_Usage(t *testing.T) {
+	ci.Parallel(t)
+
+	for _, suffix := range []string{"hcl", "json"} {
+		t.Run(suffix, func(t *testing.T) {
+			fc, err := LoadConfig("testdata/usage." + suffix)
+			must.NoError(t, err)
+
+			cfg := DefaultConfig().Merge(fc)
+			must.Eq(t, &config.UsageConfig{
+				Enabled:       pointer.Of(true),
+				Resolution:    15 * time.Minute,
+				ResolutionHCL: "15m",
+				Retention:     720 * time.Hour,
+				RetentionHCL:  "720h",
+			}, cfg.Server.Usage)
+			must.NoError(t, cfg.Server.Usage.Validate())
+		})
+	}
+}
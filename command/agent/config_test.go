@@ -334,6 +334,7 @@ func TestConfig_Merge(t *testing.T) {
 			},
 			GCInterval:            6 * time.Second,
 			GCParallelDestroys:    6,
+			ParallelRestores:      4,
 			GCDiskUsageThreshold:  71,
 			GCInodeUsageThreshold: 86,
 			NomadServiceDiscovery: pointer.Of(false),
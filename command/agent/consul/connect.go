@@ -4,6 +4,7 @@
 package consul
 
 import (
+	"encoding/json"
 	"fmt"
 	"maps"
 	"net"
@@ -149,7 +150,7 @@ func connectSidecarProxy(info structs.AllocInfo, proxy *structs.ConsulProxy, cPo
 	return &api.AgentServiceConnectProxyConfig{
 		LocalServiceAddress: proxy.LocalServiceAddress,
 		LocalServicePort:    proxy.LocalServicePort,
-		Config:              connectProxyConfig(proxy.Config, cPort, info),
+		Config:              connectProxyConfig(connectEnvoyConfig(proxy.Envoy, proxy.Config), cPort, info),
 		Upstreams:           connectUpstreams(proxy.Upstreams),
 		Expose:              expose,
 	}, nil
@@ -237,6 +238,152 @@ func connectMeshGateway(in structs.ConsulMeshGateway) api.MeshGatewayConfig {
 	return gw
 }
 
+// connectEnvoyConfig returns the proxy config with the Envoy keys set from the
+// envoy block of the proxy. The envoy block is validated not to set the keys
+// of the proxy config, except for the extra static clusters that the tracing
+// collector cluster is appended to.
+func connectEnvoyConfig(envoy *structs.ConsulEnvoyConfig, cfg map[string]interface{}) map[string]interface{} {
+	if envoy == nil {
+		return cfg
+	}
+
+	cfg = maps.Clone(cfg)
+	if cfg == nil {
+		cfg = make(map[string]interface{})
+	}
+
+	if st := envoy.Stats; st != nil {
+		if st.StatsdURL != "" {
+			cfg[structs.EnvoyConfigStatsdURL] = st.StatsdURL
+		}
+		if st.DogstatsdURL != "" {
+			cfg[structs.EnvoyConfigDogstatsdURL] = st.DogstatsdURL
+		}
+		if st.PrometheusBindAddr != "" {
+			cfg[structs.EnvoyConfigPrometheusBindAddr] = st.PrometheusBindAddr
+		}
+		if len(st.Tags) > 0 {
+			cfg[structs.EnvoyConfigStatsTags] = slices.Clone(st.Tags)
+		}
+		if st.FlushInterval > 0 {
+			cfg[structs.EnvoyConfigStatsFlushInterval] = st.FlushInterval.String()
+		}
+	}
+
+	if t := envoy.Tracing; t != nil {
+		tracer, cluster := connectEnvoyTracing(t)
+		cfg[structs.EnvoyConfigTracingJSON] = tracer
+		if extra, ok := cfg[structs.EnvoyConfigExtraClustersJSON].(string); ok && strings.TrimSpace(extra) != "" {
+			cluster = extra + "," + cluster
+		}
+		cfg[structs.EnvoyConfigExtraClustersJSON] = cluster
+	}
+
+	if envoy.BootstrapTemplate != "" {
+		cfg[structs.EnvoyConfigBootstrapTemplate] = envoy.BootstrapTemplate
+	}
+
+	return cfg
+}
+
+const (
+	// envoyTracingCluster is the name of the static cluster of the trace
+	// collector added to the Envoy bootstrap configuration.
+	envoyTracingCluster = "nomad_tracing_collector"
+
+	// envoyZipkinPath is the default path of the Zipkin collector endpoint.
+	envoyZipkinPath = "/api/v2/spans"
+)
+
+// connectEnvoyTracing returns the JSON of the Envoy tracer for the tracing
+// block, and of the static cluster of its collector.
+func connectEnvoyTracing(t *structs.ConsulEnvoyTracing) (string, string) {
+	var name string
+	var config map[string]interface{}
+
+	switch t.Provider {
+	case structs.EnvoyTracingZipkin:
+		path := t.Path
+		if path == "" {
+			path = envoyZipkinPath
+		}
+		name = "envoy.tracers.zipkin"
+		config = map[string]interface{}{
+			"@type":                      "type.googleapis.com/envoy.config.trace.v3.ZipkinConfig",
+			"collector_cluster":          envoyTracingCluster,
+			"collector_endpoint":         path,
+			"collector_endpoint_version": "HTTP_JSON",
+		}
+	case structs.EnvoyTracingDatadog:
+		name = "envoy.tracers.datadog"
+		config = map[string]interface{}{
+			"@type":             "type.googleapis.com/envoy.config.trace.v3.DatadogConfig",
+			"collector_cluster": envoyTracingCluster,
+		}
+	case structs.EnvoyTracingOpenTelemetry:
+		name = "envoy.tracers.opentelemetry"
+		config = map[string]interface{}{
+			"@type": "type.googleapis.com/envoy.config.trace.v3.OpenTelemetryConfig",
+			"grpc_service": map[string]interface{}{
+				"envoy_grpc": map[string]interface{}{
+					"cluster_name": envoyTracingCluster,
+				},
+			},
+		}
+	}
+	if t.ServiceName != "" && t.Provider != structs.EnvoyTracingZipkin {
+		config["service_name"] = t.ServiceName
+	}
+
+	// the address was validated when the job was submitted
+	host, portStr, _ := net.SplitHostPort(t.Address)
+	port, _ := strconv.Atoi(portStr)
+
+	cluster := map[string]interface{}{
+		"name":            envoyTracingCluster,
+		"type":            "STRICT_DNS",
+		"connect_timeout": "5s",
+		"load_assignment": map[string]interface{}{
+			"cluster_name": envoyTracingCluster,
+			"endpoints": []interface{}{map[string]interface{}{
+				"lb_endpoints": []interface{}{map[string]interface{}{
+					"endpoint": map[string]interface{}{
+						"address": map[string]interface{}{
+							"socket_address": map[string]interface{}{
+								"address":    host,
+								"port_value": port,
+							},
+						},
+					},
+				}},
+			}},
+		},
+	}
+	if t.Provider == structs.EnvoyTracingOpenTelemetry {
+		// the OpenTelemetry collector is a gRPC service
+		cluster["typed_extension_protocol_options"] = map[string]interface{}{
+			"envoy.extensions.upstreams.http.v3.HttpProtocolOptions": map[string]interface{}{
+				"@type": "type.googleapis.com/envoy.extensions.upstreams.http.v3.HttpProtocolOptions",
+				"explicit_http_config": map[string]interface{}{
+					"http2_protocol_options": map[string]interface{}{},
+				},
+			},
+		}
+	}
+
+	tracer := map[string]interface{}{
+		"http": map[string]interface{}{
+			"name":         name,
+			"typed_config": config,
+		},
+	}
+
+	// marshaling maps of strings and ints can't fail
+	tracerJSON, _ := json.Marshal(tracer)
+	clusterJSON, _ := json.Marshal(cluster)
+	return string(tracerJSON), string(clusterJSON)
+}
+
 func connectProxyConfig(cfg map[string]interface{}, port int, info structs.AllocInfo) map[string]interface{} {
 	if cfg == nil {
 		cfg = make(map[string]interface{})
@@ -426,6 +426,60 @@ func TestConnect_connectProxyConfig(t *testing.T) {
 	})
 }
 
+func TestConnect_connectEnvoyConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	t.Run("nil envoy", func(t *testing.T) {
+		cfg := map[string]interface{}{"foo": "bar"}
+		must.Eq(t, cfg, connectEnvoyConfig(nil, cfg))
+	})
+
+	t.Run("stats and bootstrap template", func(t *testing.T) {
+		cfg := map[string]interface{}{"foo": "bar"}
+		must.Eq(t, map[string]interface{}{
+			"foo":                        "bar",
+			"envoy_statsd_url":           "udp://127.0.0.1:8125",
+			"envoy_prometheus_bind_addr": "0.0.0.0:9102",
+			"envoy_stats_tags":           []string{"env=prod"},
+			"envoy_stats_flush_interval": "5s",
+			"envoy_bootstrap_json_tpl":   "{}",
+		}, connectEnvoyConfig(&structs.ConsulEnvoyConfig{
+			Stats: &structs.ConsulEnvoyStats{
+				StatsdURL:          "udp://127.0.0.1:8125",
+				PrometheusBindAddr: "0.0.0.0:9102",
+				Tags:               []string{"env=prod"},
+				FlushInterval:      5 * time.Second,
+			},
+			BootstrapTemplate: "{}",
+		}, cfg))
+
+		// the proxy config of the job is not modified
+		must.MapLen(t, 1, cfg)
+	})
+
+	t.Run("tracing", func(t *testing.T) {
+		cfg := connectEnvoyConfig(&structs.ConsulEnvoyConfig{
+			Tracing: &structs.ConsulEnvoyTracing{
+				Provider:    structs.EnvoyTracingDatadog,
+				Address:     "datadog-agent:8126",
+				ServiceName: "redis",
+			},
+		}, map[string]interface{}{
+			"envoy_extra_static_clusters_json": `{"name": "other"}`,
+		})
+
+		must.Eq(t, `{"http":{"name":"envoy.tracers.datadog","typed_config":{`+
+			`"@type":"type.googleapis.com/envoy.config.trace.v3.DatadogConfig",`+
+			`"collector_cluster":"nomad_tracing_collector","service_name":"redis"}}}`,
+			cfg["envoy_tracing_json"])
+		must.Eq(t, `{"name": "other"},{"connect_timeout":"5s",`+
+			`"load_assignment":{"cluster_name":"nomad_tracing_collector","endpoints":[{"lb_endpoints":[`+
+			`{"endpoint":{"address":{"socket_address":{"address":"datadog-agent","port_value":8126}}}}]}]},`+
+			`"name":"nomad_tracing_collector","type":"STRICT_DNS"}`,
+			cfg["envoy_extra_static_clusters_json"])
+	})
+}
+
 func TestConnect_getConnectPort(t *testing.T) {
 	ci.Parallel(t)
 
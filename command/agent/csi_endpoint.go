@@ -100,6 +100,9 @@ func (s *HTTPServer) CSIVolumeSpecificRequest(resp http.ResponseWriter, req *htt
 			if tokens[1] == "create" {
 				return s.csiVolumeCreate(resp, req)
 			}
+			if tokens[1] == "expand" {
+				return s.csiVolumeExpand(id, resp, req)
+			}
 		case http.MethodDelete:
 			if tokens[1] == "detach" {
 				return s.csiVolumeDetach(id, resp, req)
@@ -182,6 +185,31 @@ func (s *HTTPServer) csiVolumeCreate(resp http.ResponseWriter, req *http.Request
 	return out, nil
 }
 
+func (s *HTTPServer) csiVolumeExpand(id string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodPut {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.CSIVolumeExpandRequest{}
+	if err := decodeBody(req, &args); err != nil {
+		return err, CodedError(400, err.Error())
+	}
+	args.VolumeID = id
+	if len(args.Secrets) == 0 {
+		args.Secrets = parseCSISecrets(req)
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.CSIVolumeExpandResponse
+	if err := s.agent.RPC("CSIVolume.Expand", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+
+	return out, nil
+}
+
 func (s *HTTPServer) csiVolumeDeregister(id string, resp http.ResponseWriter, req *http.Request) (interface{}, error) {
 	if req.Method != http.MethodDelete {
 		return nil, CodedError(405, ErrInvalidMethod)
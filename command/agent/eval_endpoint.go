@@ -36,6 +36,8 @@ func (s *HTTPServer) evalsListRequest(resp http.ResponseWriter, req *http.Reques
 	query := req.URL.Query()
 	args.FilterEvalStatus = query.Get("status")
 	args.FilterJobID = query.Get("job")
+	args.FilterTriggeredBy = query.Get("triggered_by")
+	args.FilterNodeID = query.Get("node_id")
 
 	var out structs.EvalListResponse
 	if err := s.agent.RPC("Eval.List", &args, &out); err != nil {
@@ -167,3 +169,57 @@ func (s *HTTPServer) EvalsCountRequest(resp http.ResponseWriter, req *http.Reque
 	setMeta(resp, &out.QueryMeta)
 	return &out, nil
 }
+
+func (s *HTTPServer) EvalsPendingSummaryRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	args := structs.EvalPendingSummaryRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+	args.FilterJobID = req.URL.Query().Get("job")
+
+	var out structs.EvalPendingSummaryResponse
+	if err := s.agent.RPC("Eval.PendingSummary", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Summaries == nil {
+		out.Summaries = make([]*structs.JobPendingSummary, 0)
+	}
+	return out.Summaries, nil
+}
+
+func (s *HTTPServer) EvalsBumpPriorityRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodPut && req.Method != http.MethodPost {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	var args structs.EvalBumpPriorityRequest
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+
+	numIDs := len(args.EvalIDs)
+	if numIDs == 0 {
+		return nil, CodedError(http.StatusBadRequest, "at least one evaluation ID must be provided")
+	}
+	if numIDs > structs.MaxUUIDsPerWriteRequest {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf(
+			"request includes %v evaluation IDs, must be %v or fewer",
+			numIDs, structs.MaxUUIDsPerWriteRequest))
+	}
+
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var reply structs.EvalBumpPriorityResponse
+	if err := s.agent.RPC(structs.EvalBumpPriorityRPCMethod, &args, &reply); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, reply.Index)
+	return reply, nil
+}
@@ -421,3 +421,38 @@ func TestHTTP_EvalCount(t *testing.T) {
 
 	})
 }
+
+func TestHTTP_EvalPendingSummary(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		// Directly manipulate the state
+		state := s.Agent.server.State()
+		eval1 := mock.BlockedEval()
+		eval2 := mock.Eval()
+		err := state.UpsertEvals(structs.MsgTypeTestSetup, 1000, []*structs.Evaluation{eval1, eval2})
+		must.NoError(t, err)
+
+		// only blocked evals are summarized
+		req, err := http.NewRequest(http.MethodGet, "/v1/evaluations/pending", nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.EvalsPendingSummaryRequest(respW, req)
+		must.NoError(t, err)
+
+		must.NotEq(t, "", respW.Result().Header.Get("X-Nomad-Index"),
+			must.Sprint("missing index"))
+		summaries := obj.([]*structs.JobPendingSummary)
+		must.Len(t, 1, summaries)
+		must.Eq(t, eval1.ID, summaries[0].EvalID)
+		must.MapContainsKey(t, summaries[0].TaskGroups, "cache")
+
+		// filtered by job
+		req, err = http.NewRequest(http.MethodGet,
+			fmt.Sprintf("/v1/evaluations/pending?job=%s", eval2.JobID), nil)
+		must.NoError(t, err)
+		respW = httptest.NewRecorder()
+		obj, err = s.Server.EvalsPendingSummaryRequest(respW, req)
+		must.NoError(t, err)
+		must.SliceEmpty(t, obj.([]*structs.JobPendingSummary))
+	})
+}
@@ -51,17 +51,7 @@ func (s *HTTPServer) EventStream(resp http.ResponseWriter, req *http.Request) (i
 	// Set region, namespace and authtoken to args
 	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
 
-	// Determine the RPC handler to use to find a server
-	var handler structs.StreamingRpcHandler
-	var handlerErr error
-	if server := s.agent.Server(); server != nil {
-		handler, handlerErr = server.StreamingRpcHandler("Event.Stream")
-	} else if client := s.agent.Client(); client != nil {
-		handler, handlerErr = client.RemoteStreamingRpcHandler("Event.Stream")
-	} else {
-		handlerErr = fmt.Errorf("misconfigured connection")
-	}
-
+	handler, handlerErr := s.eventStreamHandler()
 	if handlerErr != nil {
 		return nil, CodedError(500, handlerErr.Error())
 	}
@@ -133,6 +123,17 @@ func (s *HTTPServer) EventStream(resp http.ResponseWriter, req *http.Request) (i
 	return nil, codedErr
 }
 
+// eventStreamHandler returns the RPC handler to use to stream events from a
+// server.
+func (s *HTTPServer) eventStreamHandler() (structs.StreamingRpcHandler, error) {
+	if server := s.agent.Server(); server != nil {
+		return server.StreamingRpcHandler("Event.Stream")
+	} else if client := s.agent.Client(); client != nil {
+		return client.RemoteStreamingRpcHandler("Event.Stream")
+	}
+	return nil, fmt.Errorf("misconfigured connection")
+}
+
 func parseEventTopics(query url.Values) (map[structs.Topic][]string, error) {
 	raw, ok := query["topic"]
 	if !ok {
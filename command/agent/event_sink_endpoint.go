@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) EventSinksRequest(resp http.ResponseWriter, req *http.Request) (any, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	args := structs.EventSinkListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.EventSinkListResponse
+	if err := s.agent.RPC("EventSink.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Sinks == nil {
+		out.Sinks = make([]*structs.EventSinkStub, 0)
+	}
+	return out.Sinks, nil
+}
+
+func (s *HTTPServer) EventSinkSpecificRequest(resp http.ResponseWriter, req *http.Request) (any, error) {
+	id := strings.TrimPrefix(req.URL.Path, "/v1/event/sink/")
+	if id == "" {
+		return nil, CodedError(http.StatusBadRequest, "Missing event sink ID")
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		return s.eventSinkQuery(resp, req, id)
+	case http.MethodPut, http.MethodPost:
+		return s.eventSinkUpsert(resp, req, id)
+	case http.MethodDelete:
+		return s.eventSinkDelete(resp, req, id)
+	default:
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) eventSinkQuery(resp http.ResponseWriter, req *http.Request, id string) (any, error) {
+	args := structs.EventSinkSpecificRequest{
+		ID: id,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleEventSinkResponse
+	if err := s.agent.RPC("EventSink.GetEventSink", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Sink == nil {
+		return nil, CodedError(http.StatusNotFound, "event sink not found")
+	}
+	return out.Sink, nil
+}
+
+func (s *HTTPServer) eventSinkUpsert(resp http.ResponseWriter, req *http.Request, id string) (any, error) {
+	var sink structs.EventSink
+	if err := decodeBody(req, &sink); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+
+	if sink.ID != id {
+		return nil, CodedError(http.StatusBadRequest, "Event sink ID does not match request path")
+	}
+
+	args := structs.EventSinkUpsertRequest{
+		Sinks: []*structs.EventSink{&sink},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("EventSink.UpsertEventSinks", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) eventSinkDelete(resp http.ResponseWriter, req *http.Request, id string) (any, error) {
+	args := structs.EventSinkDeleteRequest{
+		IDs: []string{id},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("EventSink.DeleteEventSinks", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return nil, nil
+}
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestHTTP_EventSink_CRUD(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		sink := mock.EventSink()
+
+		// Register the sink.
+		req, err := http.NewRequest(http.MethodPut, "/v1/event/sink/"+sink.ID, encodeReq(sink))
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.EventSinkSpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.NotEq(t, "", respW.Header().Get("X-Nomad-Index"))
+
+		// IDs must match the path.
+		req, err = http.NewRequest(http.MethodPut, "/v1/event/sink/other", encodeReq(sink))
+		must.NoError(t, err)
+		_, err = s.Server.EventSinkSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "does not match request path")
+
+		// List the sinks.
+		req, err = http.NewRequest(http.MethodGet, "/v1/event/sinks", nil)
+		must.NoError(t, err)
+		obj, err := s.Server.EventSinksRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+		stubs := obj.([]*structs.EventSinkStub)
+		must.Len(t, 1, stubs)
+		must.Eq(t, sink.ID, stubs[0].ID)
+
+		// Read the sink.
+		req, err = http.NewRequest(http.MethodGet, "/v1/event/sink/"+sink.ID, nil)
+		must.NoError(t, err)
+		obj, err = s.Server.EventSinkSpecificRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+		must.Eq(t, sink.Address, obj.(*structs.EventSink).Address)
+
+		// Deregister the sink.
+		req, err = http.NewRequest(http.MethodDelete, "/v1/event/sink/"+sink.ID, nil)
+		must.NoError(t, err)
+		_, err = s.Server.EventSinkSpecificRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+
+		req, err = http.NewRequest(http.MethodGet, "/v1/event/sink/"+sink.ID, nil)
+		must.NoError(t, err)
+		_, err = s.Server.EventSinkSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "event sink not found")
+	})
+}
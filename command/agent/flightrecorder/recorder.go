@@ -0,0 +1,370 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package flightrecorder implements an always-on recorder of the recent
+// activity of an agent. It keeps ring buffers of RPC latencies, eval broker
+// stats, leadership changes, and goroutine snapshots, which can be dumped on
+// demand or automatically when an anomaly is detected, so that captures taken
+// after an incident include the moments before it.
+package flightrecorder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/hashicorp/go-hclog"
+)
+
+const (
+	// maxGoroutineSnapshots is the maximum number of goroutine snapshots
+	// kept. Snapshots are much larger than the other samples, so fewer of
+	// them are kept.
+	maxGoroutineSnapshots = 10
+
+	// maxDumps is the number of automatic dumps kept in the dump directory.
+	maxDumps = 10
+
+	// checkInterval is how often the goroutine count is checked against the
+	// goroutine threshold.
+	checkInterval = time.Second
+
+	// dumpFilePrefix is the prefix of the files automatic dumps are written
+	// to.
+	dumpFilePrefix = "flight-recorder-"
+)
+
+// ErrDisabled is returned when dumping a flight recorder which isn't enabled.
+var ErrDisabled = errors.New("flight recorder is not enabled")
+
+// Config is the configuration of a flight recorder.
+type Config struct {
+	// Capacity is the number of samples kept in each buffer.
+	Capacity int
+
+	// GoroutineInterval is how often goroutines are snapshotted.
+	GoroutineInterval time.Duration
+
+	// DumpDir is the directory automatic dumps are written to. Automatic
+	// dumps are disabled if empty.
+	DumpDir string
+
+	// DumpOnAnomaly enables automatic dumps when an anomaly is detected.
+	DumpOnAnomaly bool
+
+	// RPCLatencyThreshold is the latency above which a non-blocking RPC is
+	// an anomaly. Zero disables the check.
+	RPCLatencyThreshold time.Duration
+
+	// GoroutineThreshold is the number of goroutines above which the agent
+	// is in an anomalous state. Zero disables the check.
+	GoroutineThreshold int
+
+	// MinDumpInterval is the minimum time between two automatic dumps.
+	MinDumpInterval time.Duration
+}
+
+// RPCSample is the latency of a single RPC.
+type RPCSample struct {
+	Time     time.Time
+	Method   string
+	Duration time.Duration
+
+	// Blocking is true for blocking queries, whose latency includes the time
+	// spent waiting for changes.
+	Blocking bool
+
+	// Error is the error returned by the RPC, if any.
+	Error string `json:",omitempty"`
+}
+
+// EvalBrokerSample is a sample of the eval broker stats of the leader.
+type EvalBrokerSample struct {
+	Time       time.Time
+	Ready      int
+	Unacked    int
+	Pending    int
+	Waiting    int
+	Cancelable int
+}
+
+// LeadershipEvent is a change of the Raft leadership of a server.
+type LeadershipEvent struct {
+	Time   time.Time
+	Leader bool
+}
+
+// GoroutineSnapshot is a snapshot of the goroutines of the agent.
+type GoroutineSnapshot struct {
+	Time  time.Time
+	Count int
+
+	// Stacks is the goroutine profile in its debug=1 text format, which
+	// groups goroutines with identical stacks.
+	Stacks string
+}
+
+// Dump is the content of the flight recorder buffers at a point in time.
+type Dump struct {
+	Time time.Time
+
+	// Reason is why the dump was taken, such as the anomaly detected.
+	Reason string
+
+	RPCs       []RPCSample
+	EvalBroker []EvalBrokerSample
+	Leadership []LeadershipEvent
+	Goroutines []GoroutineSnapshot
+}
+
+// Recorder is the flight recorder of an agent. A nil Recorder is valid and
+// records nothing, so callers don't need to check whether it is enabled.
+type Recorder struct {
+	config *Config
+	logger log.Logger
+
+	rpcs       *ring[RPCSample]
+	evalBroker *ring[EvalBrokerSample]
+	leadership *ring[LeadershipEvent]
+	goroutines *ring[GoroutineSnapshot]
+
+	// leader is whether the last leadership event was acquiring it.
+	leader bool
+
+	// lastDump is when the last automatic dump was written.
+	lastDump time.Time
+
+	// anomalyCh receives the anomalies detected while recording, which are
+	// dumped by the run goroutine.
+	anomalyCh chan string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	l sync.Mutex
+}
+
+// NewRecorder returns a flight recorder which records until it is shut down.
+func NewRecorder(logger log.Logger, config *Config) *Recorder {
+	r := &Recorder{
+		config:     config,
+		logger:     logger.Named("flight_recorder"),
+		rpcs:       newRing[RPCSample](config.Capacity),
+		evalBroker: newRing[EvalBrokerSample](config.Capacity),
+		leadership: newRing[LeadershipEvent](config.Capacity),
+		goroutines: newRing[GoroutineSnapshot](min(config.Capacity, maxGoroutineSnapshots)),
+		anomalyCh:  make(chan string, 1),
+	}
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+
+	go r.run()
+	return r
+}
+
+// Shutdown stops the goroutine snapshots and the automatic dumps.
+func (r *Recorder) Shutdown() {
+	if r == nil {
+		return
+	}
+	r.cancel()
+}
+
+// RecordRPC records the latency of an RPC. Non-blocking RPCs slower than the
+// RPC latency threshold are anomalies.
+func (r *Recorder) RecordRPC(method string, start time.Time, blocking bool, err error) {
+	if r == nil {
+		return
+	}
+
+	now := time.Now()
+	sample := RPCSample{
+		Time:     start,
+		Method:   method,
+		Duration: now.Sub(start),
+		Blocking: blocking,
+	}
+	if err != nil {
+		sample.Error = err.Error()
+	}
+
+	r.l.Lock()
+	r.rpcs.push(sample)
+	r.l.Unlock()
+
+	if threshold := r.config.RPCLatencyThreshold; threshold > 0 && !blocking && sample.Duration > threshold {
+		r.anomaly(fmt.Sprintf("RPC %s took %v", method, sample.Duration))
+	}
+}
+
+// RecordEvalBroker records a sample of the eval broker stats.
+func (r *Recorder) RecordEvalBroker(sample EvalBrokerSample) {
+	if r == nil {
+		return
+	}
+
+	sample.Time = time.Now()
+
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.evalBroker.push(sample)
+}
+
+// RecordLeadership records a change of the Raft leadership of the server.
+// Losing the leadership is an anomaly.
+func (r *Recorder) RecordLeadership(leader bool) {
+	if r == nil {
+		return
+	}
+
+	r.l.Lock()
+	r.leadership.push(LeadershipEvent{Time: time.Now(), Leader: leader})
+	lost := r.leader && !leader
+	r.leader = leader
+	r.l.Unlock()
+
+	if lost {
+		r.anomaly("leadership lost")
+	}
+}
+
+// Dump returns the content of the flight recorder buffers.
+func (r *Recorder) Dump(reason string) (*Dump, error) {
+	if r == nil {
+		return nil, ErrDisabled
+	}
+
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	return &Dump{
+		Time:       time.Now(),
+		Reason:     reason,
+		RPCs:       r.rpcs.slice(),
+		EvalBroker: r.evalBroker.slice(),
+		Leadership: r.leadership.slice(),
+		Goroutines: r.goroutines.slice(),
+	}, nil
+}
+
+// anomaly notifies the run goroutine of an anomaly, unless one is already
+// waiting to be dumped.
+func (r *Recorder) anomaly(reason string) {
+	if !r.config.DumpOnAnomaly || r.config.DumpDir == "" {
+		return
+	}
+
+	select {
+	case r.anomalyCh <- reason:
+	default:
+	}
+}
+
+// run is the long lived goroutine that snapshots goroutines, checks the
+// goroutine count, and writes the automatic dumps.
+func (r *Recorder) run() {
+	r.snapshotGoroutines()
+
+	snapshotTicker := time.NewTicker(r.config.GoroutineInterval)
+	defer snapshotTicker.Stop()
+	checkTicker := time.NewTicker(checkInterval)
+	defer checkTicker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-snapshotTicker.C:
+			r.snapshotGoroutines()
+		case <-checkTicker.C:
+			if threshold := r.config.GoroutineThreshold; threshold > 0 {
+				if n := runtime.NumGoroutine(); n > threshold {
+					r.anomaly(fmt.Sprintf("%d goroutines running", n))
+				}
+			}
+		case reason := <-r.anomalyCh:
+			r.dumpAnomaly(reason)
+		}
+	}
+}
+
+// snapshotGoroutines records a snapshot of the goroutines.
+func (r *Recorder) snapshotGoroutines() {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		r.logger.Warn("failed to snapshot goroutines", "error", err)
+		return
+	}
+
+	snapshot := GoroutineSnapshot{
+		Time:   time.Now(),
+		Count:  runtime.NumGoroutine(),
+		Stacks: buf.String(),
+	}
+
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.goroutines.push(snapshot)
+}
+
+// dumpAnomaly writes a dump to the dump directory, unless the last dump was
+// written less than the minimum dump interval ago.
+func (r *Recorder) dumpAnomaly(reason string) {
+	if since := time.Since(r.lastDump); since < r.config.MinDumpInterval {
+		r.logger.Debug("skipping dump of anomaly", "reason", reason, "last_dump", since)
+		return
+	}
+	r.lastDump = time.Now()
+
+	// Include the goroutines at the time of the anomaly.
+	r.snapshotGoroutines()
+
+	dump, _ := r.Dump(reason)
+	path, err := r.writeDump(dump)
+	if err != nil {
+		r.logger.Error("failed to dump flight recorder", "reason", reason, "error", err)
+		return
+	}
+	r.logger.Warn("anomaly detected, dumped flight recorder", "reason", reason, "path", path)
+}
+
+// writeDump writes a dump to the dump directory, removing the oldest dumps
+// beyond the maximum number of dumps kept.
+func (r *Recorder) writeDump(dump *Dump) (string, error) {
+	if err := os.MkdirAll(r.config.DumpDir, 0o700); err != nil {
+		return "", err
+	}
+
+	buf, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	// The timestamp has a fixed width, so dumps sort by time.
+	path := filepath.Join(r.config.DumpDir,
+		fmt.Sprintf("%s%d.json", dumpFilePrefix, dump.Time.UnixNano()))
+	if err := os.WriteFile(path, buf, 0o600); err != nil {
+		return "", err
+	}
+
+	dumps, err := filepath.Glob(filepath.Join(r.config.DumpDir, dumpFilePrefix+"*.json"))
+	if err != nil {
+		return path, nil
+	}
+	sort.Strings(dumps)
+	for len(dumps) > maxDumps {
+		if err := os.Remove(dumps[0]); err != nil {
+			r.logger.Warn("failed to remove old flight recorder dump", "path", dumps[0], "error", err)
+		}
+		dumps = dumps[1:]
+	}
+	return path, nil
+}
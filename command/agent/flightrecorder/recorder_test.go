@@ -0,0 +1,135 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package flightrecorder
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+)
+
+func testConfig(t *testing.T) *Config {
+	return &Config{
+		Capacity:            3,
+		GoroutineInterval:   time.Hour,
+		DumpDir:             t.TempDir(),
+		DumpOnAnomaly:       true,
+		RPCLatencyThreshold: time.Hour,
+		MinDumpInterval:     time.Hour,
+	}
+}
+
+func TestRing(t *testing.T) {
+	ci.Parallel(t)
+
+	r := newRing[int](3)
+	must.SliceEmpty(t, r.slice())
+
+	r.push(1)
+	r.push(2)
+	must.Eq(t, []int{1, 2}, r.slice())
+
+	r.push(3)
+	r.push(4)
+	r.push(5)
+	must.Eq(t, []int{3, 4, 5}, r.slice())
+}
+
+func TestRecorder_Dump(t *testing.T) {
+	ci.Parallel(t)
+
+	r := NewRecorder(testlog.HCLogger(t), testConfig(t))
+	defer r.Shutdown()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		r.RecordRPC(fmt.Sprintf("Job.List%d", i), start, false, nil)
+	}
+	r.RecordRPC("Job.Register", start, false, errors.New("permission denied"))
+	r.RecordEvalBroker(EvalBrokerSample{Ready: 2, Unacked: 1})
+	r.RecordLeadership(true)
+
+	dump, err := r.Dump("requested")
+	must.NoError(t, err)
+	must.Eq(t, "requested", dump.Reason)
+
+	// Only the latest samples are kept.
+	must.Len(t, 3, dump.RPCs)
+	must.Eq(t, "Job.List3", dump.RPCs[0].Method)
+	must.Eq(t, "permission denied", dump.RPCs[2].Error)
+
+	must.Len(t, 1, dump.EvalBroker)
+	must.Eq(t, 2, dump.EvalBroker[0].Ready)
+	must.Len(t, 1, dump.Leadership)
+	must.True(t, dump.Leadership[0].Leader)
+
+	// A goroutine snapshot is taken when the recorder starts.
+	must.Wait(t, wait.InitialSuccess(
+		wait.ErrorFunc(func() error {
+			dump, _ := r.Dump("")
+			if len(dump.Goroutines) != 1 {
+				return fmt.Errorf("expected 1 goroutine snapshot, got %d", len(dump.Goroutines))
+			}
+			if dump.Goroutines[0].Count == 0 {
+				return errors.New("expected goroutines in snapshot")
+			}
+			return nil
+		}),
+		wait.Timeout(5*time.Second),
+		wait.Gap(10*time.Millisecond),
+	))
+
+	var disabled *Recorder
+	disabled.RecordRPC("Job.List", start, false, nil)
+	_, err = disabled.Dump("requested")
+	must.ErrorIs(t, err, ErrDisabled)
+}
+
+func TestRecorder_DumpOnAnomaly(t *testing.T) {
+	ci.Parallel(t)
+
+	config := testConfig(t)
+	config.RPCLatencyThreshold = time.Second
+	r := NewRecorder(testlog.HCLogger(t), config)
+	defer r.Shutdown()
+
+	// Blocking queries are not anomalies.
+	r.RecordRPC("Job.List", time.Now().Add(-time.Minute), true, nil)
+	r.RecordRPC("Job.Register", time.Now(), false, nil)
+	r.RecordLeadership(false)
+
+	r.RecordRPC("Job.Register", time.Now().Add(-time.Minute), false, nil)
+
+	pattern := filepath.Join(config.DumpDir, dumpFilePrefix+"*.json")
+	must.Wait(t, wait.InitialSuccess(
+		wait.ErrorFunc(func() error {
+			dumps, err := filepath.Glob(pattern)
+			if err != nil {
+				return err
+			}
+			if len(dumps) != 1 {
+				return fmt.Errorf("expected 1 dump, got %d", len(dumps))
+			}
+			return nil
+		}),
+		wait.Timeout(5*time.Second),
+		wait.Gap(10*time.Millisecond),
+	))
+
+	// Further anomalies are not dumped until the minimum dump interval has
+	// passed.
+	r.RecordLeadership(true)
+	r.RecordLeadership(false)
+	time.Sleep(100 * time.Millisecond)
+	dumps, err := filepath.Glob(pattern)
+	must.NoError(t, err)
+	must.Len(t, 1, dumps)
+}
@@ -0,0 +1,38 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package flightrecorder
+
+// ring is a fixed size buffer which overwrites its oldest item once full.
+type ring[T any] struct {
+	items []T
+	next  int
+	full  bool
+}
+
+func newRing[T any](capacity int) *ring[T] {
+	return &ring[T]{items: make([]T, capacity)}
+}
+
+// push adds an item to the buffer, overwriting the oldest item if the buffer
+// is full.
+func (r *ring[T]) push(item T) {
+	r.items[r.next] = item
+	r.next = (r.next + 1) % len(r.items)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// slice returns a copy of the items of the buffer, from oldest to newest.
+func (r *ring[T]) slice() []T {
+	if !r.full {
+		out := make([]T, r.next)
+		copy(out, r.items[:r.next])
+		return out
+	}
+
+	out := make([]T, 0, len(r.items))
+	out = append(out, r.items[r.next:]...)
+	return append(out, r.items[:r.next]...)
+}
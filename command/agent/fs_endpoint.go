@@ -16,9 +16,14 @@ import (
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/hashicorp/go-msgpack/codec"
 	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
+// fsUploadFrameSize is the maximum size of the payload of the frames used to
+// send uploaded files.
+const fsUploadFrameSize = 64 * 1024
+
 var (
 	allocIDNotPresentErr  = CodedError(400, "must provide a valid alloc id")
 	fileNameNotPresentErr = CodedError(400, "must provide a file name")
@@ -41,6 +46,10 @@ func (s *HTTPServer) FsRequest(resp http.ResponseWriter, req *http.Request) (int
 		return s.wrapUntrustedContent(s.FileCatRequest)(resp, req)
 	case strings.HasPrefix(path, "stream/"):
 		return s.Stream(resp, req)
+	case strings.HasPrefix(path, "archive/"):
+		return s.FileArchiveRequest(resp, req)
+	case strings.HasPrefix(path, "extract/"):
+		return s.FileExtractRequest(resp, req)
 	case strings.HasPrefix(path, "logs/"):
 		// Logs are *trusted* content because the endpoint
 		// explicitly sets the Content-Type to text/plain or
@@ -198,6 +207,64 @@ func (s *HTTPServer) FileCatRequest(resp http.ResponseWriter, req *http.Request)
 	return s.fsStreamImpl(resp, req, "FileSystem.Stream", fsReq, fsReq.AllocID)
 }
 
+// FileArchiveRequest streams a tar archive of a file or directory of an
+// allocation.
+func (s *HTTPServer) FileArchiveRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var allocID, path string
+	if allocID = strings.TrimPrefix(req.URL.Path, "/v1/client/fs/archive/"); allocID == "" {
+		return nil, allocIDNotPresentErr
+	}
+	if path = req.URL.Query().Get("path"); path == "" {
+		return nil, fileNameNotPresentErr
+	}
+
+	// Create the request arguments
+	fsReq := &cstructs.FsArchiveRequest{
+		AllocID: allocID,
+		Path:    path,
+	}
+	s.parse(resp, req, &fsReq.QueryOptions.Region, &fsReq.QueryOptions)
+
+	// Force the Content-Type since the archive is binary.
+	resp.Header().Set("Content-Type", "application/x-tar")
+
+	// Make the request
+	return s.fsStreamImpl(resp, req, "FileSystem.Archive", fsReq, fsReq.AllocID)
+}
+
+// FileExtractRequest extracts the tar archive in the request body into a
+// directory of an allocation.
+func (s *HTTPServer) FileExtractRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	if req.Method != http.MethodPut && req.Method != http.MethodPost {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var allocID, path string
+	if allocID = strings.TrimPrefix(req.URL.Path, "/v1/client/fs/extract/"); allocID == "" {
+		return nil, allocIDNotPresentErr
+	}
+	if path = req.URL.Query().Get("path"); path == "" {
+		return nil, fileNameNotPresentErr
+	}
+
+	// Create the request arguments
+	fsReq := &cstructs.FsArchiveRequest{
+		AllocID: allocID,
+		Path:    path,
+	}
+	s.parse(resp, req, &fsReq.QueryOptions.Region, &fsReq.QueryOptions)
+
+	// Make the request
+	if err := s.fsUploadFrom(req.Context(), req.Body, "FileSystem.Extract", fsReq, fsReq.AllocID); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
 // Stream streams the content of a file blocking on EOF.
 // The parameters are:
 //   - path: path to file to stream.
@@ -345,37 +412,38 @@ func (s *HTTPServer) Logs(resp http.ResponseWriter, req *http.Request) (interfac
 func (s *HTTPServer) fsStreamImpl(resp http.ResponseWriter,
 	req *http.Request, method string, args interface{}, allocID string) (interface{}, error) {
 
-	// Get the correct handler
-	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(allocID)
-	var handler structs.StreamingRpcHandler
-	var handlerErr error
-	if localClient {
-		handler, handlerErr = s.agent.Client().StreamingRpcHandler(method)
-	} else if remoteClient {
-		handler, handlerErr = s.agent.Client().RemoteStreamingRpcHandler(method)
-	} else if localServer {
-		handler, handlerErr = s.agent.Server().StreamingRpcHandler(method)
+	// Create an output that gets flushed on every write
+	output := ioutils.NewWriteFlusher(resp)
+
+	if err := s.fsStreamTo(req.Context(), output, method, args, allocID); err != nil {
+		return nil, err
 	}
+	return nil, nil
+}
 
-	if handlerErr != nil {
-		return nil, CodedError(500, handlerErr.Error())
+// fsStreamTo makes a streaming filesystem call that serializes the args and
+// copies the payload of each StreamErrWrapper result to output until the
+// stream ends or the context is cancelled.
+func (s *HTTPServer) fsStreamTo(reqCtx context.Context, output io.Writer,
+	method string, args interface{}, allocID string) HTTPCodedError {
+
+	handler, codedErr := s.fsStreamingHandler(method, allocID)
+	if codedErr != nil {
+		return codedErr
 	}
 
-	// Create a pipe connecting the (possibly remote) handler to the http response
+	// Create a pipe connecting the (possibly remote) handler to the output
 	httpPipe, handlerPipe := net.Pipe()
 	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
 	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
 
 	// Create a goroutine that closes the pipe if the connection closes.
-	ctx, cancel := context.WithCancel(req.Context())
+	ctx, cancel := context.WithCancel(reqCtx)
 	go func() {
 		<-ctx.Done()
 		httpPipe.Close()
 	}()
 
-	// Create an output that gets flushed on every write
-	output := ioutils.NewWriteFlusher(resp)
-
 	// Create a channel that decodes the results
 	errCh := make(chan HTTPCodedError)
 	go func() {
@@ -421,7 +489,7 @@ func (s *HTTPServer) fsStreamImpl(resp http.ResponseWriter,
 
 	handler(handlerPipe)
 	cancel()
-	codedErr := <-errCh
+	codedErr = <-errCh
 
 	// Ignore EOF and ErrClosedPipe errors.
 	if codedErr != nil &&
@@ -430,5 +498,97 @@ func (s *HTTPServer) fsStreamImpl(resp http.ResponseWriter,
 			strings.Contains(codedErr.Error(), "EOF")) {
 		codedErr = nil
 	}
-	return nil, codedErr
+	return codedErr
+}
+
+// fsUploadFrom makes a streaming filesystem call that serializes the args,
+// sends the content of input as the payload of StreamErrWrapper frames
+// terminated by an empty frame, and then waits for a single StreamErrWrapper
+// with the result.
+func (s *HTTPServer) fsUploadFrom(reqCtx context.Context, input io.Reader,
+	method string, args interface{}, allocID string) HTTPCodedError {
+
+	handler, codedErr := s.fsStreamingHandler(method, allocID)
+	if codedErr != nil {
+		return codedErr
+	}
+
+	// Create a pipe connecting the input to the (possibly remote) handler
+	httpPipe, handlerPipe := net.Pipe()
+	decoder := codec.NewDecoder(httpPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(httpPipe, structs.MsgpackHandle)
+
+	// Create a goroutine that closes the pipe if the connection closes.
+	ctx, cancel := context.WithCancel(reqCtx)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		httpPipe.Close()
+	}()
+
+	go handler(handlerPipe)
+
+	// Send the request
+	if err := encoder.Encode(args); err != nil {
+		return CodedError(500, err.Error())
+	}
+
+	// Send the input concurrently with waiting for the result, since the
+	// handler may fail before reading all of it.
+	go func() {
+		buf := make([]byte, fsUploadFrameSize)
+		for {
+			n, err := input.Read(buf)
+			if n > 0 {
+				if err := encoder.Encode(&cstructs.StreamErrWrapper{Payload: buf[:n]}); err != nil {
+					return
+				}
+				encoder.Reset(httpPipe)
+			}
+			if err == io.EOF {
+				encoder.Encode(&cstructs.StreamErrWrapper{})
+				return
+			}
+			if err != nil {
+				encoder.Encode(&cstructs.StreamErrWrapper{
+					Error: cstructs.NewRpcError(err, pointer.Of(int64(400))),
+				})
+				return
+			}
+		}
+	}()
+
+	var res cstructs.StreamErrWrapper
+	if err := decoder.Decode(&res); err != nil {
+		return CodedError(500, err.Error())
+	}
+	if err := res.Error; err != nil {
+		code := 500
+		if err.Code != nil {
+			code = int(*err.Code)
+		}
+		return CodedError(code, err.Error())
+	}
+	return nil
+}
+
+// fsStreamingHandler returns the handler of the streaming filesystem method
+// for the allocation, which may be the local client, a remote client, or the
+// local server.
+func (s *HTTPServer) fsStreamingHandler(method, allocID string) (structs.StreamingRpcHandler, HTTPCodedError) {
+	localClient, remoteClient, localServer := s.rpcHandlerForAlloc(allocID)
+	var handler structs.StreamingRpcHandler
+	var handlerErr error
+	if localClient {
+		handler, handlerErr = s.agent.Client().StreamingRpcHandler(method)
+	} else if remoteClient {
+		handler, handlerErr = s.agent.Client().RemoteStreamingRpcHandler(method)
+	} else if localServer {
+		handler, handlerErr = s.agent.Server().StreamingRpcHandler(method)
+	}
+
+	if handlerErr != nil {
+		return nil, CodedError(500, handlerErr.Error())
+	}
+	return handler, nil
 }
@@ -0,0 +1,476 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-msgpack/codec"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/hashicorp/nomad/api"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/command/agent/proto"
+	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/hashicorp/nomad/nomad/stream"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// GRPCTokenMetadata is the request metadata key of the ACL token of gRPC API
+// requests.
+const GRPCTokenMetadata = "x-nomad-token"
+
+// GRPCServer serves the gRPC API of the agent, which covers the endpoints of
+// the HTTP API used by high volume integrations.
+type GRPCServer struct {
+	proto.UnimplementedNomadServer
+
+	agent *Agent
+
+	// http is used to share the request handling of the HTTP API, such as
+	// finding the agent that can stream the logs of an allocation.
+	http *HTTPServer
+
+	server   *grpc.Server
+	listener net.Listener
+	logger   hclog.Logger
+	Addr     string
+}
+
+// NewGRPCServer starts the gRPC server if the grpc port is set in the agent
+// configuration. It returns nil if the gRPC API is disabled.
+func NewGRPCServer(agent *Agent, config *Config) (*GRPCServer, error) {
+	if config.Ports.GRPC == 0 {
+		return nil, nil
+	}
+
+	lnAddr, err := net.ResolveTCPAddr("tcp", config.normalizedAddrs.GRPC)
+	if err != nil {
+		return nil, err
+	}
+	ln, err := config.Listener("tcp", lnAddr.IP.String(), lnAddr.Port)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start gRPC listener: %v", err)
+	}
+
+	// The gRPC API uses the TLS configuration of the HTTP API
+	var opts []grpc.ServerOption
+	if config.TLSConfig.EnableHTTP {
+		tlsConf, err := tlsutil.NewTLSConfiguration(config.TLSConfig, config.TLSConfig.VerifyHTTPSClient, true)
+		if err != nil {
+			ln.Close()
+			return nil, fmt.Errorf("failed to initialize gRPC server TLS configuration: %s", err)
+		}
+		tlsConfig, err := tlsConf.IncomingTLSConfig()
+		if err != nil {
+			ln.Close()
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	logger := agent.logger.Named("grpc")
+	srv := &GRPCServer{
+		agent:    agent,
+		http:     &HTTPServer{agent: agent, logger: agent.httpLogger},
+		server:   grpc.NewServer(opts...),
+		listener: ln,
+		logger:   logger,
+		Addr:     ln.Addr().String(),
+	}
+	proto.RegisterNomadServer(srv.server, srv)
+
+	go srv.server.Serve(ln)
+	return srv, nil
+}
+
+// Shutdown stops the gRPC server, closing any open streams.
+func (s *GRPCServer) Shutdown() {
+	if s != nil {
+		s.logger.Debug("shutting down gRPC server")
+		s.server.Stop()
+	}
+}
+
+// EventStream streams events from the event stream, with the payload of the
+// events encoded with msgpack.
+func (s *GRPCServer) EventStream(req *proto.EventStreamRequest, out proto.Nomad_EventStreamServer) error {
+	query := url.Values{}
+	for _, topic := range req.Topics {
+		query.Add("topic", topic)
+	}
+	topics, err := parseEventTopics(query)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid topic query: %v", err)
+	}
+
+	args := &structs.EventStreamRequest{
+		Topics:       topics,
+		Index:        int(req.Index),
+		Msgpack:      true,
+		QueryOptions: s.queryOptions(out.Context(), req.Region, req.Namespace),
+	}
+
+	handler, err := s.http.eventStreamHandler()
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	grpcPipe, handlerPipe := net.Pipe()
+	decoder := codec.NewDecoder(grpcPipe, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(grpcPipe, structs.MsgpackHandle)
+
+	// Close the pipe when the client goes away
+	ctx, cancel := context.WithCancel(out.Context())
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		grpcPipe.Close()
+	}()
+
+	errs, errCtx := errgroup.WithContext(ctx)
+	errs.Go(func() error {
+		defer cancel()
+
+		if err := encoder.Encode(args); err != nil {
+			return CodedError(500, err.Error())
+		}
+
+		for {
+			select {
+			case <-errCtx.Done():
+				return nil
+			default:
+			}
+
+			var res structs.EventStreamWrapper
+			if err := decoder.Decode(&res); err != nil {
+				return CodedError(500, err.Error())
+			}
+			decoder.Reset(grpcPipe)
+
+			if err := res.Error; err != nil {
+				code := 500
+				if err.Code != nil {
+					code = int(*err.Code)
+				}
+				return CodedError(code, err.Error())
+			}
+
+			// gRPC streams have their own keepalives so heartbeats are dropped
+			if res.Event == nil || bytes.Equal(res.Event.Data, stream.JsonHeartbeat.Data) {
+				continue
+			}
+
+			events, err := decodeGRPCEvents(res.Event.Data)
+			if err != nil {
+				return CodedError(500, err.Error())
+			}
+			if err := out.Send(events); err != nil {
+				return err
+			}
+		}
+	})
+
+	handler(handlerPipe)
+	cancel()
+
+	err = errs.Wait()
+	if err != nil && strings.Contains(err.Error(), io.ErrClosedPipe.Error()) {
+		err = nil
+	}
+	return grpcError(err)
+}
+
+// grpcEvents is an event stream frame with the payload of each event left
+// encoded, so the payloads are passed on without being decoded.
+type grpcEvents struct {
+	Index  uint64
+	Events []struct {
+		Topic      structs.Topic
+		Type       string
+		Key        string
+		Namespace  string
+		FilterKeys []string
+		Index      uint64
+		Payload    codec.Raw
+	}
+}
+
+// decodeGRPCEvents decodes an event stream frame. Frames are JSON encoded by
+// servers that don't support msgpack encoded events.
+func decodeGRPCEvents(data []byte) (*proto.EventStreamResponse, error) {
+	var h codec.Handle = structs.MsgpackHandle
+	encoding := proto.Encoding_MSGPACK
+	if len(data) > 0 && data[0] == '{' {
+		h = structs.JsonHandle
+		encoding = proto.Encoding_JSON
+	}
+
+	var frame grpcEvents
+	if err := codec.NewDecoderBytes(data, h).Decode(&frame); err != nil {
+		return nil, fmt.Errorf("failed to decode events: %v", err)
+	}
+
+	resp := &proto.EventStreamResponse{
+		Index:  frame.Index,
+		Events: make([]*proto.Event, 0, len(frame.Events)),
+	}
+	for _, e := range frame.Events {
+		resp.Events = append(resp.Events, &proto.Event{
+			Topic:           string(e.Topic),
+			Type:            e.Type,
+			Key:             e.Key,
+			Namespace:       e.Namespace,
+			FilterKeys:      e.FilterKeys,
+			Index:           e.Index,
+			Payload:         e.Payload,
+			PayloadEncoding: encoding,
+		})
+	}
+	return resp, nil
+}
+
+// RegisterJob registers the JSON encoded job of the request.
+func (s *GRPCServer) RegisterJob(ctx context.Context, req *proto.RegisterJobRequest) (*proto.RegisterJobResponse, error) {
+	var job api.Job
+	if err := json.Unmarshal(req.Job, &job); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to decode job: %v", err)
+	}
+	if job.ID == nil {
+		return nil, status.Error(codes.InvalidArgument, "Job ID hasn't been provided")
+	}
+
+	requestRegion, jobRegion := regionForJob(&job, "", req.Region, s.agent.GetConfig().Region)
+	namespace := namespaceForJob(job.Namespace, "", req.Namespace)
+
+	sJob := ApiJobToStructJob(&job)
+	sJob.Region = jobRegion
+	sJob.Namespace = namespace
+
+	args := structs.JobRegisterRequest{
+		Job:            sJob,
+		EnforceIndex:   req.EnforceIndex,
+		JobModifyIndex: req.JobModifyIndex,
+		PolicyOverride: req.PolicyOverride,
+		PreserveCounts: req.PreserveCounts,
+		WriteRequest: structs.WriteRequest{
+			Region:    requestRegion,
+			Namespace: namespace,
+			AuthToken: grpcToken(ctx),
+		},
+	}
+
+	var out structs.JobRegisterResponse
+	if err := s.agent.RPC("Job.Register", &args, &out); err != nil {
+		return nil, grpcError(err)
+	}
+	return &proto.RegisterJobResponse{
+		EvalId:          out.EvalID,
+		EvalCreateIndex: out.EvalCreateIndex,
+		JobModifyIndex:  out.JobModifyIndex,
+		Warnings:        out.Warnings,
+		Index:           out.Index,
+	}, nil
+}
+
+// GetAllocation returns the status of an allocation.
+func (s *GRPCServer) GetAllocation(ctx context.Context, req *proto.GetAllocationRequest) (*proto.GetAllocationResponse, error) {
+	args := structs.AllocSpecificRequest{
+		AllocID:      req.AllocId,
+		QueryOptions: s.queryOptions(ctx, req.Region, req.Namespace),
+	}
+
+	var out structs.SingleAllocResponse
+	if err := s.agent.RPC("Alloc.GetAlloc", &args, &out); err != nil {
+		return nil, grpcError(err)
+	}
+	if out.Alloc == nil {
+		return nil, status.Error(codes.NotFound, "alloc not found")
+	}
+	return &proto.GetAllocationResponse{
+		Allocation: grpcAllocation(out.Alloc.Stub(nil)),
+		Index:      out.Index,
+	}, nil
+}
+
+// ListJobAllocations returns the status of the allocations of a job.
+func (s *GRPCServer) ListJobAllocations(ctx context.Context, req *proto.ListJobAllocationsRequest) (*proto.ListJobAllocationsResponse, error) {
+	args := structs.JobSpecificRequest{
+		JobID:        req.JobId,
+		All:          req.All,
+		QueryOptions: s.queryOptions(ctx, req.Region, req.Namespace),
+	}
+
+	var out structs.JobAllocationsResponse
+	if err := s.agent.RPC("Job.Allocations", &args, &out); err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &proto.ListJobAllocationsResponse{
+		Allocations: make([]*proto.Allocation, 0, len(out.Allocations)),
+		Index:       out.Index,
+	}
+	for _, alloc := range out.Allocations {
+		resp.Allocations = append(resp.Allocations, grpcAllocation(alloc))
+	}
+	return resp, nil
+}
+
+func grpcAllocation(alloc *structs.AllocListStub) *proto.Allocation {
+	a := &proto.Allocation{
+		Id:                 alloc.ID,
+		Name:               alloc.Name,
+		Namespace:          alloc.Namespace,
+		NodeId:             alloc.NodeID,
+		NodeName:           alloc.NodeName,
+		JobId:              alloc.JobID,
+		JobVersion:         alloc.JobVersion,
+		TaskGroup:          alloc.TaskGroup,
+		DesiredStatus:      alloc.DesiredStatus,
+		DesiredDescription: alloc.DesiredDescription,
+		ClientStatus:       alloc.ClientStatus,
+		ClientDescription:  alloc.ClientDescription,
+		TaskStates:         make(map[string]*proto.TaskState, len(alloc.TaskStates)),
+		CreateIndex:        alloc.CreateIndex,
+		ModifyIndex:        alloc.ModifyIndex,
+		CreateTime:         alloc.CreateTime,
+		ModifyTime:         alloc.ModifyTime,
+	}
+	for name, state := range alloc.TaskStates {
+		ts := &proto.TaskState{
+			State:    state.State,
+			Failed:   state.Failed,
+			Restarts: state.Restarts,
+		}
+		if !state.StartedAt.IsZero() {
+			ts.StartedAt = state.StartedAt.UnixNano()
+		}
+		if !state.FinishedAt.IsZero() {
+			ts.FinishedAt = state.FinishedAt.UnixNano()
+		}
+		a.TaskStates[name] = ts
+	}
+	return a
+}
+
+// StreamLogs streams the logs of a task from the client running the
+// allocation.
+func (s *GRPCServer) StreamLogs(req *proto.StreamLogsRequest, out proto.Nomad_StreamLogsServer) error {
+	if req.AllocId == "" {
+		return grpcError(allocIDNotPresentErr)
+	}
+	if req.Task == "" {
+		return grpcError(taskNotPresentErr)
+	}
+	switch req.Type {
+	case "stdout", "stderr":
+	default:
+		return grpcError(logTypeNotPresentErr)
+	}
+
+	origin := req.Origin
+	switch origin {
+	case "start", "end":
+	case "":
+		origin = "start"
+	default:
+		return grpcError(invalidOrigin)
+	}
+
+	args := &cstructs.FsLogsRequest{
+		AllocID:      req.AllocId,
+		Task:         req.Task,
+		LogType:      req.Type,
+		Offset:       req.Offset,
+		Origin:       origin,
+		PlainText:    true,
+		Follow:       req.Follow,
+		QueryOptions: s.queryOptions(out.Context(), req.Region, req.Namespace),
+	}
+
+	if err := s.http.fsStreamTo(out.Context(), &grpcLogWriter{out: out}, "FileSystem.Logs", args, req.AllocId); err != nil {
+		return grpcError(err)
+	}
+	return nil
+}
+
+// grpcLogWriter sends the logs written to it to a StreamLogs stream.
+type grpcLogWriter struct {
+	out proto.Nomad_StreamLogsServer
+}
+
+func (w *grpcLogWriter) Write(p []byte) (int, error) {
+	// Messages may be retained by the stream after Send returns
+	if err := w.out.Send(&proto.StreamLogsResponse{Data: bytes.Clone(p)}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// queryOptions returns the query options of a request, defaulting the region
+// and namespace as the HTTP API does.
+func (s *GRPCServer) queryOptions(ctx context.Context, region, namespace string) structs.QueryOptions {
+	q := structs.QueryOptions{
+		Region:    region,
+		Namespace: namespace,
+		AuthToken: grpcToken(ctx),
+	}
+	if q.Region == "" {
+		q.Region = s.agent.GetConfig().Region
+	}
+	if q.Namespace == "" {
+		q.Namespace = structs.DefaultNamespace
+	}
+	return q
+}
+
+// grpcToken returns the ACL token set in the request metadata.
+func grpcToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if token := md.Get(GRPCTokenMetadata); len(token) > 0 {
+		return strings.TrimSpace(token[0])
+	}
+	return ""
+}
+
+// grpcError converts an error returned by an RPC or HTTP handler to a gRPC
+// status error.
+func grpcError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	code, msg := errCodeFromHandler(err)
+	switch code {
+	case http.StatusBadRequest:
+		return status.Error(codes.InvalidArgument, msg)
+	case http.StatusForbidden:
+		return status.Error(codes.PermissionDenied, msg)
+	case http.StatusNotFound:
+		return status.Error(codes.NotFound, msg)
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
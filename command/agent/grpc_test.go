@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/command/agent/proto"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPC_RegisterJob(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		srv := &GRPCServer{agent: s.Agent, http: s.Server, logger: s.Agent.logger}
+
+		job := MockJob()
+		buf, err := json.Marshal(job)
+		must.NoError(t, err)
+
+		resp, err := srv.RegisterJob(context.Background(), &proto.RegisterJobRequest{Job: buf})
+		must.NoError(t, err)
+		must.NotEq(t, "", resp.EvalId)
+		must.Positive(t, resp.Index)
+
+		_, err = srv.RegisterJob(context.Background(), &proto.RegisterJobRequest{Job: []byte("{")})
+		must.Eq(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestGRPC_Allocations(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		srv := &GRPCServer{agent: s.Agent, http: s.Server, logger: s.Agent.logger}
+
+		state := s.Agent.server.State()
+		alloc := mock.Alloc()
+		alloc.TaskStates = map[string]*structs.TaskState{
+			"web": {State: structs.TaskStateRunning, Restarts: 2},
+		}
+		must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 999, nil, alloc.Job))
+		must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(GRPCTokenMetadata, "ignored"))
+		resp, err := srv.GetAllocation(ctx, &proto.GetAllocationRequest{AllocId: alloc.ID})
+		must.NoError(t, err)
+		must.Eq(t, alloc.ID, resp.Allocation.Id)
+		must.Eq(t, alloc.JobID, resp.Allocation.JobId)
+		must.Eq(t, structs.TaskStateRunning, resp.Allocation.TaskStates["web"].State)
+		must.Eq(t, 2, resp.Allocation.TaskStates["web"].Restarts)
+		must.Eq(t, 1000, resp.Index)
+
+		_, err = srv.GetAllocation(ctx, &proto.GetAllocationRequest{AllocId: "6d0b2d1b-4e80-2a3e-8c40-41a1a2f8a6a1"})
+		must.Eq(t, codes.NotFound, status.Code(err))
+
+		list, err := srv.ListJobAllocations(ctx, &proto.ListJobAllocationsRequest{JobId: alloc.JobID})
+		must.NoError(t, err)
+		must.Len(t, 1, list.Allocations)
+		must.Eq(t, alloc.ID, list.Allocations[0].Id)
+	})
+}
+
+func TestGRPC_StreamLogs_Invalid(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := &GRPCServer{}
+	err := srv.StreamLogs(&proto.StreamLogsRequest{AllocId: "foo", Task: "web", Type: "stdin"}, nil)
+	must.Eq(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPC_DecodeEvents(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := mock.Alloc()
+	events := &structs.Events{
+		Index: 10,
+		Events: []structs.Event{{
+			Topic:      structs.TopicAllocation,
+			Type:       structs.TypeAllocationUpdated,
+			Key:        alloc.ID,
+			Namespace:  alloc.Namespace,
+			FilterKeys: []string{alloc.JobID},
+			Index:      10,
+			Payload:    &structs.AllocationEvent{Allocation: alloc},
+		}},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		handle   codec.Handle
+		encoding proto.Encoding
+	}{
+		{"msgpack", structs.MsgpackHandle, proto.Encoding_MSGPACK},
+		{"json", structs.JsonHandle, proto.Encoding_JSON},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			must.NoError(t, codec.NewEncoder(&buf, tc.handle).Encode(events))
+
+			resp, err := decodeGRPCEvents(buf.Bytes())
+			must.NoError(t, err)
+			must.Eq(t, 10, resp.Index)
+			must.Len(t, 1, resp.Events)
+
+			event := resp.Events[0]
+			must.Eq(t, string(structs.TopicAllocation), event.Topic)
+			must.Eq(t, alloc.ID, event.Key)
+			must.Eq(t, []string{alloc.JobID}, event.FilterKeys)
+			must.Eq(t, tc.encoding, event.PayloadEncoding)
+
+			var payload structs.AllocationEvent
+			must.NoError(t, codec.NewDecoderBytes(event.Payload, tc.handle).Decode(&payload))
+			must.Eq(t, alloc.ID, payload.Allocation.ID)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// HostVolumeSpecificRequest handles creating host volumes with
+// PUT /v1/volume/host/create and deleting them with
+// DELETE /v1/volume/host/<name>?node_id=<node>.
+func (s *HTTPServer) HostVolumeSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	name := strings.TrimPrefix(req.URL.Path, "/v1/volume/host/")
+	switch {
+	case name == "create":
+		if req.Method != http.MethodPut && req.Method != http.MethodPost {
+			return nil, CodedError(405, ErrInvalidMethod)
+		}
+		return s.hostVolumeCreate(resp, req)
+	case name != "" && !strings.Contains(name, "/"):
+		if req.Method != http.MethodDelete {
+			return nil, CodedError(405, ErrInvalidMethod)
+		}
+		return s.hostVolumeDelete(resp, req, name)
+	default:
+		return nil, CodedError(404, "volume not found")
+	}
+}
+
+func (s *HTTPServer) hostVolumeCreate(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+	args := structs.HostVolumeCreateRequest{}
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+
+	const method = "HostVolume.Create"
+	var reply structs.HostVolumeCreateResponse
+	if err := s.hostVolumeRPC(method, args.NodeID, &args, &reply); err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (s *HTTPServer) hostVolumeDelete(resp http.ResponseWriter, req *http.Request, name string) (interface{}, error) {
+	args := structs.HostVolumeDeleteRequest{Name: name}
+	s.parse(resp, req, &args.QueryOptions.Region, &args.QueryOptions)
+	parseNode(req, &args.NodeID)
+	if args.NodeID == "" {
+		return nil, CodedError(http.StatusBadRequest, "missing node_id")
+	}
+
+	const method = "HostVolume.Delete"
+	var reply structs.HostVolumeDeleteResponse
+	if err := s.hostVolumeRPC(method, args.NodeID, &args, &reply); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// hostVolumeRPC makes the host volume RPC on the local client if it's the
+// target node, and otherwise through the servers, which pick the node if
+// none is given.
+func (s *HTTPServer) hostVolumeRPC(method, nodeID string, args, reply interface{}) error {
+	useLocalClient, useClientRPC, useServerRPC := s.rpcHandlerForNode(nodeID)
+	if nodeID == "" {
+		useLocalClient = false
+		useServerRPC = s.agent.Server() != nil
+		useClientRPC = !useServerRPC && s.agent.Client() != nil
+	}
+
+	var rpcErr error
+	if useLocalClient {
+		rpcErr = s.agent.Client().ClientRPC(method, args, reply)
+	} else if useClientRPC {
+		rpcErr = s.agent.Client().RPC(method, args, reply)
+	} else if useServerRPC {
+		rpcErr = s.agent.Server().RPC(method, args, reply)
+	} else {
+		rpcErr = CodedError(400, "No local Node and node_id not provided")
+	}
+
+	if rpcErr != nil && structs.IsErrNoNodeConn(rpcErr) {
+		rpcErr = CodedError(404, rpcErr.Error())
+	}
+	return rpcErr
+}
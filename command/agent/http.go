@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
@@ -33,6 +34,7 @@ import (
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client"
 	"github.com/hashicorp/nomad/command/agent/event"
+	"github.com/hashicorp/nomad/command/agent/flightrecorder"
 	"github.com/hashicorp/nomad/helper/noxssrw"
 	"github.com/hashicorp/nomad/helper/tlsutil"
 	"github.com/hashicorp/nomad/nomad"
@@ -90,6 +92,7 @@ type RPCer interface {
 	Stats() map[string]map[string]string
 	GetConfig() *Config
 	GetMetricsSink() *metrics.InmemSink
+	GetFlightRecorder() *flightrecorder.Recorder
 }
 
 // HTTPServer is used to wrap an Agent and expose it over an HTTP interface
@@ -390,11 +393,16 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/node/pools", s.wrap(s.NodePoolsRequest))
 	s.mux.HandleFunc("/v1/node/pool/", s.wrap(s.NodePoolSpecificRequest))
 
+	s.mux.HandleFunc("/v1/job-templates", s.wrap(s.JobTemplatesRequest))
+	s.mux.HandleFunc("/v1/job-template/", s.wrap(s.JobTemplateSpecificRequest))
+
 	s.mux.HandleFunc("/v1/allocations", s.wrap(s.AllocsRequest))
 	s.mux.HandleFunc("/v1/allocation/", s.wrap(s.AllocSpecificRequest))
 
 	s.mux.HandleFunc("/v1/evaluations", s.wrap(s.EvalsRequest))
 	s.mux.HandleFunc("/v1/evaluations/count", s.wrap(s.EvalsCountRequest))
+	s.mux.HandleFunc("/v1/evaluations/pending", s.wrap(s.EvalsPendingSummaryRequest))
+	s.mux.HandleFunc("/v1/evaluations/bump", s.wrap(s.EvalsBumpPriorityRequest))
 	s.mux.HandleFunc("/v1/evaluation/", s.wrap(s.EvalSpecificRequest))
 
 	s.mux.HandleFunc("/v1/deployments", s.wrap(s.DeploymentsRequest))
@@ -404,6 +412,7 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/volumes/external", s.wrap(s.CSIExternalVolumesRequest))
 	s.mux.HandleFunc("/v1/volumes/snapshot", s.wrap(s.CSISnapshotsRequest))
 	s.mux.HandleFunc("/v1/volume/csi/", s.wrap(s.CSIVolumeSpecificRequest))
+	s.mux.HandleFunc("/v1/volume/host/", s.wrap(s.HostVolumeSpecificRequest))
 	s.mux.HandleFunc("/v1/plugins", s.wrap(s.CSIPluginsRequest))
 	s.mux.HandleFunc("/v1/plugin/csi/", s.wrap(s.CSIPluginSpecificRequest))
 
@@ -416,6 +425,7 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/acl/tokens", s.wrap(s.ACLTokensRequest))
 	s.mux.HandleFunc("/v1/acl/token", s.wrap(s.ACLTokenSpecificRequest))
 	s.mux.HandleFunc("/v1/acl/token/", s.wrap(s.ACLTokenSpecificRequest))
+	s.mux.HandleFunc("/v1/acl/identity/child", s.wrap(s.ACLCreateChildIdentityRequest))
 
 	// Register our ACL role handlers.
 	s.mux.HandleFunc("/v1/acl/roles", s.wrap(s.ACLRoleListRequest))
@@ -435,7 +445,10 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	// Register out ACL OIDC SSO and auth handlers.
 	s.mux.HandleFunc("/v1/acl/oidc/auth-url", s.wrap(s.ACLOIDCAuthURLRequest))
 	s.mux.HandleFunc("/v1/acl/oidc/complete-auth", s.wrap(s.ACLOIDCCompleteAuthRequest))
+	s.mux.HandleFunc("/v1/acl/oidc/device-auth", s.wrap(s.ACLOIDCDeviceAuthRequest))
+	s.mux.HandleFunc("/v1/acl/oidc/complete-device-auth", s.wrap(s.ACLOIDCCompleteDeviceAuthRequest))
 	s.mux.HandleFunc("/v1/acl/login", s.wrap(s.ACLLoginRequest))
+	s.mux.HandleFunc("/v1/acl/token-exchange", s.wrap(s.ACLTokenExchangeRequest))
 
 	s.mux.Handle("/v1/client/fs/", wrapCORS(s.wrap(s.FsRequest)))
 	s.mux.HandleFunc("/v1/client/gc", s.wrap(s.ClientGCRequest))
@@ -453,6 +466,7 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/agent/keyring/", s.wrap(s.KeyringOperationRequest))
 	s.mux.HandleFunc("/v1/agent/health", s.wrap(s.HealthRequest))
 	s.mux.HandleFunc("/v1/agent/host", s.wrap(s.AgentHostRequest))
+	s.mux.HandleFunc("/v1/agent/flight-recorder", s.wrap(s.AgentFlightRecorderRequest))
 
 	// Register our service registration handlers.
 	s.mux.HandleFunc("/v1/services", s.wrap(s.ServiceRegistrationListRequest))
@@ -488,6 +502,11 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/operator/autopilot/health", s.wrap(s.OperatorServerHealth))
 	s.mux.HandleFunc("/v1/operator/snapshot", s.wrap(s.SnapshotRequest))
 	s.mux.HandleFunc("/v1/operator/upgrade-check/", s.wrap(s.UpgradeCheckRequest))
+	s.mux.HandleFunc("/v1/operator/state/usage", s.wrap(s.OperatorStateUsage))
+	s.mux.HandleFunc("/v1/operator/maintenance", s.wrap(s.OperatorMaintenanceWindows))
+	s.mux.HandleFunc("/v1/operator/maintenance/", s.wrap(s.OperatorMaintenanceWindowSpecific))
+	s.mux.HandleFunc("/v1/operator/upgrade/clients", s.wrap(s.OperatorClientUpgrades))
+	s.mux.HandleFunc("/v1/operator/upgrade/client/", s.wrap(s.OperatorClientUpgradeSpecific))
 
 	s.mux.HandleFunc("/v1/system/gc", s.wrap(s.GarbageCollectRequest))
 	s.mux.HandleFunc("/v1/system/reconcile/summaries", s.wrap(s.ReconcileJobSummaries))
@@ -495,6 +514,10 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 	s.mux.HandleFunc("/v1/operator/scheduler/configuration", s.wrap(s.OperatorSchedulerConfiguration))
 
 	s.mux.HandleFunc("/v1/event/stream", s.wrap(s.EventStream))
+	s.mux.HandleFunc("/v1/event/sinks", s.wrap(s.EventSinksRequest))
+	s.mux.HandleFunc("/v1/event/sink/", s.wrap(s.EventSinkSpecificRequest))
+
+	s.mux.HandleFunc("/v1/usage", s.wrap(s.UsageRequest))
 
 	s.mux.HandleFunc("/v1/namespaces", s.wrap(s.NamespacesRequest))
 	s.mux.HandleFunc("/v1/namespace", s.wrap(s.NamespaceCreateRequest))
@@ -502,6 +525,8 @@ func (s *HTTPServer) registerHandlers(enableDebug bool) {
 
 	s.mux.Handle("/v1/vars", wrapCORS(s.wrap(s.VariablesListRequest)))
 	s.mux.Handle("/v1/var/", wrapCORSWithAllowedMethods(s.wrap(s.VariableSpecificRequest), "HEAD", "GET", "PUT", "DELETE"))
+	s.mux.HandleFunc("/v1/var-grants", s.wrap(s.VariableGrantsRequest))
+	s.mux.HandleFunc("/v1/var-grant/", s.wrap(s.VariableGrantSpecificRequest))
 
 	// OIDC Handlers
 	s.mux.HandleFunc(structs.JWKSPath, s.wrap(s.JWKSRequest))
@@ -901,9 +926,22 @@ func parseWait(resp http.ResponseWriter, req *http.Request, b *structs.QueryOpti
 	return false
 }
 
-// parseConsistency is used to parse the ?stale query params.
+// parseConsistency is used to parse the ?stale and ?read_replica query params.
 func parseConsistency(resp http.ResponseWriter, req *http.Request, b *structs.QueryOptions) {
 	query := req.URL.Query()
+	if replicaVal, ok := query["read_replica"]; ok {
+		if len(replicaVal) == 0 || replicaVal[0] == "" {
+			b.ReadReplica = true
+		} else {
+			replicaQuery, err := strconv.ParseBool(replicaVal[0])
+			if err != nil {
+				resp.WriteHeader(http.StatusBadRequest)
+				_, _ = resp.Write([]byte(fmt.Sprintf("Expect `true` or `false` for `read_replica` query string parameter, got %s", replicaVal[0])))
+				return
+			}
+			b.ReadReplica = replicaQuery
+		}
+	}
 	if staleVal, ok := query["stale"]; ok {
 		if len(staleVal) == 0 || staleVal[0] == "" {
 			b.AllowStale = true
@@ -1083,6 +1121,67 @@ func parseNodeListStubFields(req *http.Request) (*structs.NodeStubFields, error)
 	return fields, nil
 }
 
+// parseFields parses the fields query parameter, a comma separated list of
+// the fields to include in the objects of a list response.
+func parseFields(req *http.Request) []string {
+	var fields []string
+	for _, field := range strings.Split(req.URL.Query().Get("fields"), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// projectFields returns the items of list, a slice of pointers to structs,
+// as maps of the given top-level fields so the fields the caller doesn't
+// need aren't encoded. Field names are case insensitive. The list is returned
+// unchanged if no fields are given.
+func projectFields(list any, fields []string) (any, error) {
+	if len(fields) == 0 {
+		return list, nil
+	}
+
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("cannot project fields of %T", list)
+	}
+
+	elem := v.Type().Elem()
+	if elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot project fields of %T", list)
+	}
+
+	// Resolve the field names once for all items
+	indexes := make(map[string][]int, len(fields))
+	for _, name := range fields {
+		field, ok := elem.FieldByNameFunc(func(f string) bool {
+			return strings.EqualFold(f, name)
+		})
+		if !ok || !field.IsExported() {
+			return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("unknown field %q", name))
+		}
+		indexes[field.Name] = field.Index
+	}
+
+	out := make([]map[string]any, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		item := reflect.Indirect(v.Index(i))
+		if !item.IsValid() {
+			continue
+		}
+		projected := make(map[string]any, len(indexes))
+		for name, index := range indexes {
+			projected[name] = item.FieldByIndex(index).Interface()
+		}
+		out = append(out, projected)
+	}
+	return out, nil
+}
+
 // parseWriteRequest is a convenience method for endpoints that need to parse a
 // write request.
 func (s *HTTPServer) parseWriteRequest(req *http.Request, w *structs.WriteRequest) {
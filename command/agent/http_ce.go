@@ -8,8 +8,15 @@ package agent
 
 import (
 	"net/http"
+
+	"github.com/hashicorp/nomad/command/agent/audit"
+	"github.com/hashicorp/nomad/nomad/structs"
 )
 
+// errAuditDelivery is returned when a request is halted because its audit
+// event could not be written to a sink with enforced delivery.
+const errAuditDelivery = "failed to write audit event"
+
 // registerEnterpriseHandlers is a no-op for the oss release
 func (s *HTTPServer) registerEnterpriseHandlers() {
 	s.mux.HandleFunc("/v1/sentinel/policies", s.wrap(s.entOnly))
@@ -32,15 +39,145 @@ func (s *HTTPServer) entOnly(resp http.ResponseWriter, req *http.Request) (inter
 
 // auditHandler wraps the passed handlerFn
 func (s *HTTPServer) auditHandler(h handlerFn) handlerFn {
-	return h
+	return func(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+		ev, err := s.auditReceived(req)
+		if err != nil {
+			return nil, err
+		}
+
+		obj, rspErr := h(resp, req)
+		if err := s.auditComplete(req, ev, rspErr); err != nil {
+			return nil, err
+		}
+		return obj, rspErr
+	}
 }
 
 // auditHTTPHandler wraps  the passed handlerByteFn
 func (s *HTTPServer) auditNonJSONHandler(h handlerByteFn) handlerByteFn {
-	return h
+	return func(resp http.ResponseWriter, req *http.Request) ([]byte, error) {
+		ev, err := s.auditReceived(req)
+		if err != nil {
+			return nil, err
+		}
+
+		obj, rspErr := h(resp, req)
+		if err := s.auditComplete(req, ev, rspErr); err != nil {
+			return nil, err
+		}
+		return obj, rspErr
+	}
 }
 
 // auditHTTPHandler wraps the passed http.Handler
 func (s *HTTPServer) auditHTTPHandler(h http.Handler) http.Handler {
-	return h
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		ev, err := s.auditReceived(req)
+		if err != nil {
+			code, errMsg := errCodeFromHandler(err)
+			resp.WriteHeader(code)
+			resp.Write([]byte(errMsg))
+			return
+		}
+		if ev == nil {
+			h.ServeHTTP(resp, req)
+			return
+		}
+
+		rw := &auditResponseWriter{ResponseWriter: resp}
+		h.ServeHTTP(rw, req)
+
+		// The response has already been written, so failures can only be
+		// logged.
+		if err := s.eventAuditor.Event(req.Context(), audit.EventType, ev.Complete(rw.statusCode(), "")); err != nil {
+			s.logger.Error("failed to write audit event", "method", req.Method, "path", req.URL.String(), "error", err)
+		}
+	})
+}
+
+// auditReceived writes the OperationReceived audit event for the request. It
+// returns nil if audit logging is disabled, and an error if the request must
+// be halted because the event could not be delivered.
+func (s *HTTPServer) auditReceived(req *http.Request) (*audit.Event, error) {
+	if s.eventAuditor == nil || !s.eventAuditor.Enabled() {
+		return nil, nil
+	}
+
+	var namespace string
+	parseNamespace(req, &namespace)
+
+	ev := audit.NewHTTPEvent(req, s.auditAuth(req), namespace, s.Addr)
+	if err := s.eventAuditor.Event(req.Context(), audit.EventType, ev); err != nil {
+		s.logger.Error("failed to write audit event", "method", req.Method, "path", req.URL.String(), "error", err)
+		return nil, CodedError(http.StatusInternalServerError, errAuditDelivery)
+	}
+	return ev, nil
+}
+
+// auditComplete writes the OperationComplete audit event for the request with
+// the result of the handler.
+func (s *HTTPServer) auditComplete(req *http.Request, ev *audit.Event, rspErr error) error {
+	if ev == nil {
+		return nil
+	}
+
+	code, errMsg := errCodeFromHandler(rspErr)
+	if code == 0 {
+		code = http.StatusOK
+	}
+
+	if err := s.eventAuditor.Event(req.Context(), audit.EventType, ev.Complete(code, errMsg)); err != nil {
+		s.logger.Error("failed to write audit event", "method", req.Method, "path", req.URL.String(), "error", err)
+		return CodedError(http.StatusInternalServerError, errAuditDelivery)
+	}
+	return nil
+}
+
+// auditAuth resolves the identity that made the request. The identity is
+// resolved from the state of the server or the ACL cache of the client, so
+// auditing doesn't cost an RPC per request. Failures to resolve the identity
+// are not fatal since the event still records the request.
+func (s *HTTPServer) auditAuth(req *http.Request) *audit.Auth {
+	var secret string
+	s.parseToken(req, &secret)
+
+	var ident *structs.AuthenticatedIdentity
+	var err error
+	if srv := s.agent.Server(); srv != nil {
+		ident, err = srv.ResolveIdentity(secret)
+	} else {
+		ident, err = s.agent.Client().ResolveIdentity(secret)
+	}
+	if err != nil {
+		s.logger.Debug("failed to resolve identity for audit event", "error", err)
+		return nil
+	}
+	return audit.AuthFromIdentity(ident)
+}
+
+// auditResponseWriter records the status code written by an http.Handler.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	code int
+}
+
+func (w *auditResponseWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *auditResponseWriter) statusCode() int {
+	if w.code == 0 {
+		return http.StatusOK
+	}
+	return w.code
 }
@@ -16,6 +16,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
@@ -511,6 +512,22 @@ func TestParseConsistency(t *testing.T) {
 	resp = httptest.NewRecorder()
 	parseConsistency(resp, req, &b)
 	must.False(t, b.AllowStale)
+
+	b = structs.QueryOptions{}
+	req, err = http.NewRequest(http.MethodGet, "/v1/catalog/nodes?read_replica", nil)
+	must.NoError(t, err)
+	resp = httptest.NewRecorder()
+	parseConsistency(resp, req, &b)
+	must.True(t, b.ReadReplica)
+	must.True(t, b.AllowStaleRead())
+
+	b = structs.QueryOptions{}
+	req, err = http.NewRequest(http.MethodGet, "/v1/catalog/nodes?read_replica=random", nil)
+	must.NoError(t, err)
+	resp = httptest.NewRecorder()
+	parseConsistency(resp, req, &b)
+	must.False(t, b.ReadReplica)
+	must.EqOp(t, 400, resp.Code)
 }
 
 func TestParseRegion(t *testing.T) {
@@ -1557,6 +1574,85 @@ func benchmarkJsonEncoding(b *testing.B, handle *codec.JsonHandle) {
 	}
 }
 
+func TestHTTPServer_AuditLog(t *testing.T) {
+	ci.Parallel(t)
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	httpACLTest(t, func(c *Config) {
+		c.Audit = &config.AuditConfig{
+			Enabled: pointer.Of(true),
+			Sinks: []*config.AuditSink{{
+				Name:              "file",
+				Type:              "file",
+				DeliveryGuarantee: "enforced",
+				Path:              path,
+			}},
+			Filters: []*config.AuditFilter{{
+				Name:      "status",
+				Type:      "HTTPEvent",
+				Endpoints: []string{"/v1/status/*"},
+			}},
+		}
+	}, func(s *TestAgent) {
+		// Requests with the root token succeed and are attributed to it.
+		req := httptest.NewRequest(http.MethodGet, "/v1/jobs?prefix=web", nil)
+		setToken(req, s.RootToken)
+		respW := httptest.NewRecorder()
+		s.Server.wrap(s.Server.JobsRequest)(respW, req)
+		must.Eq(t, http.StatusOK, respW.Code)
+
+		// Requests without a token are denied.
+		req = httptest.NewRequest(http.MethodGet, "/v1/jobs", nil)
+		respW = httptest.NewRecorder()
+		s.Server.wrap(s.Server.JobsRequest)(respW, req)
+		must.Eq(t, http.StatusForbidden, respW.Code)
+
+		// Filtered requests are not audited.
+		req = httptest.NewRequest(http.MethodGet, "/v1/status/leader", nil)
+		s.Server.wrap(s.Server.StatusLeaderRequest)(httptest.NewRecorder(), req)
+
+		raw, err := os.ReadFile(path)
+		must.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(raw)), "\n")
+		must.Len(t, 4, lines)
+
+		type entry struct {
+			Payload struct {
+				ID    string
+				Stage string
+				Auth  struct {
+					AccessorID string `json:"accessor_id"`
+				}
+				Request struct {
+					Operation string
+					Endpoint  string
+				}
+				Response *struct {
+					StatusCode int    `json:"status_code"`
+					Error      string `json:"error"`
+				}
+			}
+		}
+		entries := make([]entry, len(lines))
+		for i, line := range lines {
+			must.NoError(t, json.Unmarshal([]byte(line), &entries[i]))
+		}
+
+		must.Eq(t, "OperationReceived", entries[0].Payload.Stage)
+		must.Eq(t, s.RootToken.AccessorID, entries[0].Payload.Auth.AccessorID)
+		must.Eq(t, "/v1/jobs?prefix=web", entries[0].Payload.Request.Endpoint)
+		must.Nil(t, entries[0].Payload.Response)
+
+		must.Eq(t, "OperationComplete", entries[1].Payload.Stage)
+		must.Eq(t, entries[0].Payload.ID, entries[1].Payload.ID)
+		must.Eq(t, http.StatusOK, entries[1].Payload.Response.StatusCode)
+
+		must.Eq(t, "anonymous", entries[3].Payload.Auth.AccessorID)
+		must.Eq(t, http.StatusForbidden, entries[3].Payload.Response.StatusCode)
+		must.Eq(t, structs.ErrPermissionDenied.Error(), entries[3].Payload.Response.Error)
+	})
+}
+
 func httpTest(t testing.TB, cb func(c *Config), f func(srv *TestAgent)) {
 	s := makeHTTPServer(t, cb)
 	defer s.Shutdown()
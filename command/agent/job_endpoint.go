@@ -10,12 +10,14 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang/snappy"
 	"github.com/gorilla/websocket"
 	"github.com/hashicorp/nomad/acl"
 	api "github.com/hashicorp/nomad/api"
 	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/jobspec"
 	"github.com/hashicorp/nomad/jobspec2"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -91,6 +93,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/versions"):
 		jobID := strings.TrimSuffix(path, "/versions")
 		return s.jobVersions(resp, req, jobID)
+	case strings.HasSuffix(path, "/diff"):
+		jobID := strings.TrimSuffix(path, "/diff")
+		return s.jobVersionDiff(resp, req, jobID)
 	case strings.HasSuffix(path, "/revert"):
 		jobID := strings.TrimSuffix(path, "/revert")
 		return s.jobRevert(resp, req, jobID)
@@ -118,6 +123,9 @@ func (s *HTTPServer) JobSpecificRequest(resp http.ResponseWriter, req *http.Requ
 	case strings.HasSuffix(path, "/action"):
 		jobID := strings.TrimSuffix(path, "/action")
 		return s.jobRunAction(resp, req, jobID)
+	case strings.HasSuffix(path, "/logs"):
+		jobID := strings.TrimSuffix(path, "/logs")
+		return s.jobLogs(resp, req, jobID)
 	default:
 		return s.jobCRUD(resp, req, path)
 	}
@@ -182,6 +190,7 @@ func (s *HTTPServer) jobPlan(resp http.ResponseWriter, req *http.Request,
 		Job:            sJob,
 		Diff:           args.Diff,
 		PolicyOverride: args.PolicyOverride,
+		WhatIf:         ApiJobPlanWhatIfToStructs(args.WhatIf),
 		WriteRequest:   *writeReq,
 	}
 
@@ -270,7 +279,7 @@ func (s *HTTPServer) jobAllocations(resp http.ResponseWriter, req *http.Request,
 	for _, alloc := range out.Allocations {
 		alloc.SetEventDisplayMessages()
 	}
-	return out.Allocations, nil
+	return projectFields(out.Allocations, parseFields(req))
 }
 
 func (s *HTTPServer) jobEvaluations(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
@@ -714,6 +723,36 @@ func (s *HTTPServer) jobVersions(resp http.ResponseWriter, req *http.Request, jo
 	return out, nil
 }
 
+func (s *HTTPServer) jobVersionDiff(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.JobVersionDiffRequest{
+		JobID: jobID,
+	}
+	for param, version := range map[string]**uint64{"from": &args.FromVersion, "to": &args.ToVersion} {
+		if v := req.URL.Query().Get(param); v != "" {
+			parsed, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return nil, CodedError(400, fmt.Sprintf("Failed to parse value of %q (%v) as a version: %v", param, v, err))
+			}
+			*version = &parsed
+		}
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobVersionDiffResponse
+	if err := s.agent.RPC("Job.GetJobVersionDiff", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	return out, nil
+}
+
 func (s *HTTPServer) jobRevert(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
 
 	if req.Method != "PUT" && req.Method != "POST" {
@@ -929,6 +968,7 @@ func (s *HTTPServer) apiJobAndRequestToStructs(job *api.Job, req *http.Request,
 	}
 
 	s.parseToken(req, &writeReq.AuthToken)
+	parseIdempotencyToken(req, &writeReq.IdempotencyToken)
 
 	queryRegion := req.URL.Query().Get("region")
 	requestRegion, jobRegion := regionForJob(
@@ -1030,6 +1070,7 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 		Type:           *job.Type,
 		Priority:       *job.Priority,
 		AllAtOnce:      *job.AllAtOnce,
+		Prefetch:       job.Prefetch != nil && *job.Prefetch,
 		Datacenters:    job.Datacenters,
 		NodePool:       *job.NodePool,
 		Payload:        job.Payload,
@@ -1081,9 +1122,20 @@ func ApiJobToStructJob(job *api.Job) *structs.Job {
 
 	if job.ParameterizedJob != nil {
 		j.ParameterizedJob = &structs.ParameterizedJobConfig{
-			Payload:      job.ParameterizedJob.Payload,
-			MetaRequired: job.ParameterizedJob.MetaRequired,
-			MetaOptional: job.ParameterizedJob.MetaOptional,
+			Payload:       job.ParameterizedJob.Payload,
+			MetaRequired:  job.ParameterizedJob.MetaRequired,
+			MetaOptional:  job.ParameterizedJob.MetaOptional,
+			MaxConcurrent: job.ParameterizedJob.MaxConcurrent,
+			QueueLimit:    job.ParameterizedJob.QueueLimit,
+		}
+	}
+
+	if job.Notify != nil {
+		j.Notify = &structs.JobNotify{
+			Webhook: job.Notify.Webhook,
+			Secret:  job.Notify.Secret,
+			Headers: job.Notify.Headers,
+			On:      job.Notify.On,
 		}
 	}
 
@@ -1127,13 +1179,7 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 	tg.Services = ApiServicesToStructs(taskGroup.Services, true)
 	tg.Consul = apiConsulToStructs(taskGroup.Consul)
 
-	tg.RestartPolicy = &structs.RestartPolicy{
-		Attempts:        *taskGroup.RestartPolicy.Attempts,
-		Interval:        *taskGroup.RestartPolicy.Interval,
-		Delay:           *taskGroup.RestartPolicy.Delay,
-		Mode:            *taskGroup.RestartPolicy.Mode,
-		RenderTemplates: *taskGroup.RestartPolicy.RenderTemplates,
-	}
+	tg.RestartPolicy = apiRestartPolicyToStructs(taskGroup.RestartPolicy)
 
 	if taskGroup.PreventRescheduleOnLost == nil {
 		tg.PreventRescheduleOnLost = false
@@ -1179,6 +1225,17 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		}
 	}
 
+	if taskGroup.Array != nil {
+		tg.Array = &structs.ArrayConfig{
+			Count:       *taskGroup.Array.Count,
+			Concurrency: *taskGroup.Array.Concurrency,
+		}
+	}
+
+	if taskGroup.Ingress != nil {
+		tg.Ingress = ApiIngressToStructs(taskGroup.Ingress)
+	}
+
 	if taskGroup.Migrate != nil {
 		tg.Migrate = &structs.MigrateStrategy{
 			MaxParallel:     *taskGroup.Migrate.MaxParallel,
@@ -1198,6 +1255,19 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 		Migrate: *taskGroup.EphemeralDisk.Migrate,
 	}
 
+	if l := len(taskGroup.EphemeralDisk.MigrateHooks); l != 0 {
+		tg.EphemeralDisk.MigrateHooks = make([]*structs.MigrateHook, l)
+		for i, hook := range taskGroup.EphemeralDisk.MigrateHooks {
+			tg.EphemeralDisk.MigrateHooks[i] = &structs.MigrateHook{
+				Stage:   hook.Stage,
+				Task:    *hook.Task,
+				Command: *hook.Command,
+				Args:    slices.Clone(hook.Args),
+				Timeout: *hook.Timeout,
+			}
+		}
+	}
+
 	if len(taskGroup.Spreads) > 0 {
 		tg.Spreads = []*structs.Spread{}
 		for _, spread := range taskGroup.Spreads {
@@ -1222,6 +1292,9 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 				AttachmentMode: structs.CSIVolumeAttachmentMode(v.AttachmentMode),
 				AccessMode:     structs.CSIVolumeAccessMode(v.AccessMode),
 				PerAlloc:       v.PerAlloc,
+				Create:         v.Create,
+				SizeMB:         v.SizeMB,
+				Affinity:       v.Affinity,
 			}
 
 			if v.MountOptions != nil {
@@ -1237,13 +1310,16 @@ func ApiTgToStructsTG(job *structs.Job, taskGroup *api.TaskGroup, tg *structs.Ta
 
 	if taskGroup.Update != nil {
 		tg.Update = &structs.UpdateStrategy{
-			Stagger:          *taskGroup.Update.Stagger,
-			MaxParallel:      *taskGroup.Update.MaxParallel,
-			HealthCheck:      *taskGroup.Update.HealthCheck,
-			MinHealthyTime:   *taskGroup.Update.MinHealthyTime,
-			HealthyDeadline:  *taskGroup.Update.HealthyDeadline,
-			ProgressDeadline: *taskGroup.Update.ProgressDeadline,
-			Canary:           *taskGroup.Update.Canary,
+			Stagger:           *taskGroup.Update.Stagger,
+			MaxParallel:       *taskGroup.Update.MaxParallel,
+			HealthCheck:       *taskGroup.Update.HealthCheck,
+			MinHealthyTime:    *taskGroup.Update.MinHealthyTime,
+			HealthyDeadline:   *taskGroup.Update.HealthyDeadline,
+			ProgressDeadline:  *taskGroup.Update.ProgressDeadline,
+			Canary:            *taskGroup.Update.Canary,
+			Strategy:          *taskGroup.Update.Strategy,
+			CanaryConstraints: ApiConstraintsToStructs(taskGroup.Update.CanaryConstraints),
+			CanaryPlacement:   dereferenceString(taskGroup.Update.CanaryPlacement),
 		}
 
 		// boolPtr fields may be nil, others will have pointers to default values via Canonicalize
@@ -1283,6 +1359,7 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 	structsTask.Leader = apiTask.Leader
 	structsTask.Config = apiTask.Config
 	structsTask.Env = apiTask.Env
+	structsTask.Sealed = apiTask.Sealed
 	structsTask.Meta = apiTask.Meta
 	structsTask.KillTimeout = *apiTask.KillTimeout
 	structsTask.ShutdownDelay = apiTask.ShutdownDelay
@@ -1310,12 +1387,14 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 	}
 
 	if apiTask.RestartPolicy != nil {
-		structsTask.RestartPolicy = &structs.RestartPolicy{
-			Attempts:        *apiTask.RestartPolicy.Attempts,
-			Interval:        *apiTask.RestartPolicy.Interval,
-			Delay:           *apiTask.RestartPolicy.Delay,
-			Mode:            *apiTask.RestartPolicy.Mode,
-			RenderTemplates: *apiTask.RestartPolicy.RenderTemplates,
+		structsTask.RestartPolicy = apiRestartPolicyToStructs(apiTask.RestartPolicy)
+	}
+
+	if apiTask.OOMPolicy != nil {
+		structsTask.OOMPolicy = &structs.OOMPolicy{
+			Mode:             *apiTask.OOMPolicy.Mode,
+			MemoryIncreaseMB: *apiTask.OOMPolicy.MemoryIncreaseMB,
+			MemoryMaxMB:      *apiTask.OOMPolicy.MemoryMaxMB,
 		}
 	}
 
@@ -1353,15 +1432,18 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 	if len(apiTask.Artifacts) > 0 {
 		structsTask.Artifacts = []*structs.TaskArtifact{}
 		for _, ta := range apiTask.Artifacts {
-			structsTask.Artifacts = append(structsTask.Artifacts,
-				&structs.TaskArtifact{
-					GetterSource:   *ta.GetterSource,
-					GetterOptions:  maps.Clone(ta.GetterOptions),
-					GetterHeaders:  maps.Clone(ta.GetterHeaders),
-					GetterMode:     *ta.GetterMode,
-					GetterInsecure: *ta.GetterInsecure,
-					RelativeDest:   *ta.RelativeDest,
-				})
+			artifact := &structs.TaskArtifact{
+				GetterSource:   *ta.GetterSource,
+				GetterOptions:  maps.Clone(ta.GetterOptions),
+				GetterHeaders:  maps.Clone(ta.GetterHeaders),
+				GetterMode:     *ta.GetterMode,
+				GetterInsecure: *ta.GetterInsecure,
+				RelativeDest:   *ta.RelativeDest,
+			}
+			if ta.GetterIdentity != nil {
+				artifact.GetterIdentity = *ta.GetterIdentity
+			}
+			structsTask.Artifacts = append(structsTask.Artifacts, artifact)
 		}
 	}
 
@@ -1416,8 +1498,11 @@ func ApiTaskToStructsTask(job *structs.Job, group *structs.TaskGroup,
 
 	if apiTask.Lifecycle != nil {
 		structsTask.Lifecycle = &structs.TaskLifecycleConfig{
-			Hook:    apiTask.Lifecycle.Hook,
-			Sidecar: apiTask.Lifecycle.Sidecar,
+			Hook:            apiTask.Lifecycle.Hook,
+			Sidecar:         apiTask.Lifecycle.Sidecar,
+			WaitHealthy:     apiTask.Lifecycle.WaitHealthy,
+			HealthyTimeout:  dereferenceDuration(apiTask.Lifecycle.HealthyTimeout),
+			UnhealthyAction: apiTask.Lifecycle.UnhealthyAction,
 		}
 	}
 
@@ -1475,6 +1560,35 @@ func ApiActionToStructsAction(job *structs.Job, action *api.Action) *structs.Act
 	}
 }
 
+// apiRestartPolicyToStructs converts a canonicalized api restart policy. The
+// backoff and budget fields are optional and may be left unset.
+func apiRestartPolicyToStructs(in *api.RestartPolicy) *structs.RestartPolicy {
+	out := &structs.RestartPolicy{
+		Attempts:        *in.Attempts,
+		Interval:        *in.Interval,
+		Delay:           *in.Delay,
+		Mode:            *in.Mode,
+		RenderTemplates: *in.RenderTemplates,
+		Jitter:          pointer.Copy(in.Jitter),
+	}
+	if in.DelayFunction != nil {
+		out.DelayFunction = *in.DelayFunction
+	}
+	if in.DelayMultiplier != nil {
+		out.DelayMultiplier = *in.DelayMultiplier
+	}
+	if in.MaxDelay != nil {
+		out.MaxDelay = *in.MaxDelay
+	}
+	if in.Budget != nil {
+		out.Budget = *in.Budget
+	}
+	if in.BudgetWindow != nil {
+		out.BudgetWindow = *in.BudgetWindow
+	}
+	return out
+}
+
 func ApiResourcesToStructs(in *api.Resources) *structs.Resources {
 	if in == nil {
 		return nil
@@ -1489,6 +1603,10 @@ func ApiResourcesToStructs(in *api.Resources) *structs.Resources {
 		out.Cores = *in.Cores
 	}
 
+	if in.ExclusiveCores != nil {
+		out.ExclusiveCores = *in.ExclusiveCores
+	}
+
 	if in.MemoryMaxMB != nil {
 		out.MemoryMaxMB = *in.MemoryMaxMB
 	}
@@ -1538,6 +1656,10 @@ func ApiNetworkResourceToStructs(in []*api.NetworkResource) []*structs.NetworkRe
 			MBits:    nw.Megabits(),
 		}
 
+		if len(nw.Interfaces) > 0 {
+			out[i].Interfaces = slices.Clone(nw.Interfaces)
+		}
+
 		if nw.DNS != nil {
 			out[i].DNS = &structs.DNSConfig{
 				Servers:  nw.DNS.Servers,
@@ -1573,6 +1695,27 @@ func ApiPortToStructs(in api.Port) structs.Port {
 	}
 }
 
+func ApiIngressToStructs(in *api.Ingress) *structs.Ingress {
+	out := &structs.Ingress{
+		Listeners: make([]*structs.IngressListener, len(in.Listeners)),
+	}
+	for i, l := range in.Listeners {
+		out.Listeners[i] = &structs.IngressListener{
+			Port:     l.Port,
+			Protocol: l.Protocol,
+			Service:  l.Service,
+		}
+		for _, r := range l.Routes {
+			out.Listeners[i].Routes = append(out.Listeners[i].Routes, &structs.IngressRoute{
+				Host:       r.Host,
+				PathPrefix: r.PathPrefix,
+				Service:    r.Service,
+			})
+		}
+	}
+	return out
+}
+
 func ApiServicesToStructs(in []*api.Service, group bool) []*structs.Service {
 	if len(in) == 0 {
 		return nil
@@ -1627,6 +1770,8 @@ func ApiServicesToStructs(in []*api.Service, group bool) []*structs.Service {
 					SuccessBeforePassing:   check.SuccessBeforePassing,
 					FailuresBeforeCritical: check.FailuresBeforeCritical,
 					FailuresBeforeWarning:  check.FailuresBeforeWarning,
+					Weight:                 check.Weight,
+					UseContainerHealth:     check.UseContainerHealth,
 					OnUpdate:               onUpdate,
 				}
 
@@ -1671,6 +1816,7 @@ func apiWorkloadIdentityToStructs(in *api.WorkloadIdentity) *structs.WorkloadIde
 		File:         in.File,
 		ServiceName:  in.ServiceName,
 		TTL:          in.TTL,
+		ExtraClaims:  maps.Clone(in.ExtraClaims),
 	}
 }
 
@@ -1867,10 +2013,45 @@ func apiConnectSidecarServiceProxyToStructs(in *api.ConsulProxy) *structs.Consul
 		LocalServicePort:    in.LocalServicePort,
 		Upstreams:           apiUpstreamsToStructs(in.Upstreams),
 		Expose:              apiConsulExposeConfigToStructs(expose),
+		Envoy:               apiConsulEnvoyConfigToStructs(in.Envoy),
 		Config:              maps.Clone(in.Config),
 	}
 }
 
+func apiConsulEnvoyConfigToStructs(in *api.ConsulEnvoyConfig) *structs.ConsulEnvoyConfig {
+	if in == nil {
+		return nil
+	}
+
+	out := &structs.ConsulEnvoyConfig{
+		Concurrency:       in.Concurrency,
+		BootstrapTemplate: in.BootstrapTemplate,
+	}
+
+	if stats := in.Stats; stats != nil {
+		out.Stats = &structs.ConsulEnvoyStats{
+			StatsdURL:          stats.StatsdURL,
+			DogstatsdURL:       stats.DogstatsdURL,
+			PrometheusBindAddr: stats.PrometheusBindAddr,
+			Tags:               slices.Clone(stats.Tags),
+		}
+		if stats.FlushInterval != nil {
+			out.Stats.FlushInterval = *stats.FlushInterval
+		}
+	}
+
+	if tracing := in.Tracing; tracing != nil {
+		out.Tracing = &structs.ConsulEnvoyTracing{
+			Provider:    tracing.Provider,
+			Address:     tracing.Address,
+			Path:        tracing.Path,
+			ServiceName: tracing.ServiceName,
+		}
+	}
+
+	return out
+}
+
 func apiUpstreamsToStructs(in []*api.ConsulUpstream) []structs.ConsulUpstream {
 	if len(in) == 0 {
 		return nil
@@ -1970,12 +2151,32 @@ func apiLogConfigToStructs(in *api.LogConfig) *structs.LogConfig {
 	}
 
 	return &structs.LogConfig{
-		Disabled:      dereferenceBool(in.Disabled),
-		MaxFiles:      dereferenceInt(in.MaxFiles),
-		MaxFileSizeMB: dereferenceInt(in.MaxFileSizeMB),
+		Disabled:       dereferenceBool(in.Disabled),
+		MaxFiles:       dereferenceInt(in.MaxFiles),
+		MaxFileSizeMB:  dereferenceInt(in.MaxFileSizeMB),
+		Backpressure:   dereferenceString(in.Backpressure),
+		RotateInterval: dereferenceDuration(in.RotateInterval),
+		Compression:    dereferenceString(in.Compression),
+		Retention:      dereferenceDuration(in.Retention),
+		Sinks:          apiLogSinksToStructs(in.Sinks),
 	}
 }
 
+func apiLogSinksToStructs(in []*api.LogSink) []*structs.LogSink {
+	if len(in) == 0 {
+		return nil
+	}
+
+	out := make([]*structs.LogSink, len(in))
+	for i, sink := range in {
+		out[i] = &structs.LogSink{
+			Name:    sink.Name,
+			Options: maps.Clone(sink.Options),
+		}
+	}
+	return out
+}
+
 func dereferenceBool(in *bool) bool {
 	if in == nil {
 		return false
@@ -1990,6 +2191,20 @@ func dereferenceInt(in *int) int {
 	return *in
 }
 
+func dereferenceString(in *string) string {
+	if in == nil {
+		return ""
+	}
+	return *in
+}
+
+func dereferenceDuration(in *time.Duration) time.Duration {
+	if in == nil {
+		return 0
+	}
+	return *in
+}
+
 func ApiConstraintsToStructs(in []*api.Constraint) []*structs.Constraint {
 	if in == nil {
 		return nil
@@ -2053,6 +2268,27 @@ func ApiSpreadToStructs(a1 *api.Spread) *structs.Spread {
 	return ret
 }
 
+func ApiJobPlanWhatIfToStructs(in *api.JobPlanWhatIf) *structs.JobPlanWhatIf {
+	if in == nil {
+		return nil
+	}
+
+	out := &structs.JobPlanWhatIf{
+		DrainNodes: slices.Clone(in.DrainNodes),
+	}
+	for _, nodes := range in.AddNodes {
+		if nodes == nil {
+			continue
+		}
+		out.AddNodes = append(out.AddNodes, &structs.JobPlanWhatIfNodes{
+			NodeClass:  nodes.NodeClass,
+			Count:      nodes.Count,
+			Datacenter: nodes.Datacenter,
+		})
+	}
+	return out
+}
+
 // validateEvalPriorityOpt ensures the supplied evaluation priority override
 // value is within acceptable bounds.
 func validateEvalPriorityOpt(priority int) HTTPCodedError {
@@ -2606,6 +2606,12 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 			MetaRequired: []string{"a", "b"},
 			MetaOptional: []string{"c", "d"},
 		},
+		Notify: &api.JobNotify{
+			Webhook: "https://example.com/hook",
+			Secret:  "secret",
+			Headers: map[string]string{"X-Team": "data"},
+			On:      []string{"failed"},
+		},
 		Payload: []byte("payload"),
 		Meta: map[string]string{
 			"foo": "bar",
@@ -2680,6 +2686,13 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					SizeMB:  pointer.Of(100),
 					Sticky:  pointer.Of(true),
 					Migrate: pointer.Of(true),
+					MigrateHooks: []*api.MigrateHook{{
+						Stage:   api.MigrateHookStagePreMigrate,
+						Task:    pointer.Of("task1"),
+						Command: pointer.Of("/bin/flush"),
+						Args:    []string{"--all"},
+						Timeout: pointer.Of(time.Minute),
+					}},
 				},
 				Update: &api.UpdateStrategy{
 					HealthCheck:      pointer.Of(structs.UpdateStrategyHealthCheck_Checks),
@@ -2920,8 +2933,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								GetterOptions: map[string]string{
 									"a": "b",
 								},
-								GetterMode:   pointer.Of("dir"),
-								RelativeDest: pointer.Of("dest"),
+								GetterMode:     pointer.Of("dir"),
+								RelativeDest:   pointer.Of("dest"),
+								GetterIdentity: pointer.Of("artifacts"),
 							},
 						},
 						Vault: &api.Vault{
@@ -3034,6 +3048,12 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 			MetaRequired: []string{"a", "b"},
 			MetaOptional: []string{"c", "d"},
 		},
+		Notify: &structs.JobNotify{
+			Webhook: "https://example.com/hook",
+			Secret:  "secret",
+			Headers: map[string]string{"X-Team": "data"},
+			On:      []string{"failed"},
+		},
 		Payload: []byte("payload"),
 		Meta: map[string]string{
 			"foo": "bar",
@@ -3109,6 +3129,13 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					SizeMB:  100,
 					Sticky:  true,
 					Migrate: true,
+					MigrateHooks: []*structs.MigrateHook{{
+						Stage:   structs.MigrateHookStagePreMigrate,
+						Task:    "task1",
+						Command: "/bin/flush",
+						Args:    []string{"--all"},
+						Timeout: time.Minute,
+					}},
 				},
 				Update: &structs.UpdateStrategy{
 					Stagger:          1 * time.Second,
@@ -3120,6 +3147,7 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 					AutoRevert:       true,
 					AutoPromote:      false,
 					Canary:           1,
+					Strategy:         structs.UpdateStrategyRolling,
 				},
 				Meta: map[string]string{
 					"key": "value",
@@ -3365,8 +3393,9 @@ func TestJobs_ApiJobToStructsJob(t *testing.T) {
 								GetterOptions: map[string]string{
 									"a": "b",
 								},
-								GetterMode:   "dir",
-								RelativeDest: "dest",
+								GetterMode:     "dir",
+								RelativeDest:   "dest",
+								GetterIdentity: "artifacts",
 							},
 						},
 						Vault: &structs.Vault{
@@ -3698,8 +3727,9 @@ func TestJobs_ApiJobToStructsJobUpdate(t *testing.T) {
 				},
 			}, {
 				Update: &api.UpdateStrategy{
-					Canary:      pointer.Of(3),
-					AutoPromote: pointer.Of(true),
+					Canary:          pointer.Of(3),
+					AutoPromote:     pointer.Of(true),
+					CanaryPlacement: pointer.Of(structs.CanaryPlacementDistinct),
 				},
 			},
 		},
@@ -3731,6 +3761,7 @@ func TestJobs_ApiJobToStructsJobUpdate(t *testing.T) {
 		AutoRevert:       true,
 		AutoPromote:      false,
 		Canary:           2,
+		Strategy:         "rolling",
 	}
 
 	group2 := structs.UpdateStrategy{
@@ -3743,6 +3774,8 @@ func TestJobs_ApiJobToStructsJobUpdate(t *testing.T) {
 		AutoRevert:       false,
 		AutoPromote:      true,
 		Canary:           3,
+		Strategy:         "rolling",
+		CanaryPlacement:  "distinct",
 	}
 
 	require.Equal(t, jobUpdate, structsJob.Update)
@@ -3818,17 +3851,37 @@ func TestConversion_dereferenceInt(t *testing.T) {
 	require.Equal(t, 42, dereferenceInt(pointer.Of(42)))
 }
 
+func TestConversion_dereferenceString(t *testing.T) {
+	ci.Parallel(t)
+	must.Eq(t, "", dereferenceString(nil))
+	must.Eq(t, "drop", dereferenceString(pointer.Of("drop")))
+}
+
 func TestConversion_apiLogConfigToStructs(t *testing.T) {
 	ci.Parallel(t)
 	must.Nil(t, apiLogConfigToStructs(nil))
 	must.Eq(t, &structs.LogConfig{
-		Disabled:      true,
-		MaxFiles:      2,
-		MaxFileSizeMB: 8,
+		Disabled:       true,
+		MaxFiles:       2,
+		MaxFileSizeMB:  8,
+		Backpressure:   structs.LogBackpressureSpill,
+		RotateInterval: time.Hour,
+		Compression:    structs.LogCompressionZstd,
+		Retention:      24 * time.Hour,
+		Sinks: []*structs.LogSink{
+			{Name: "loki", Options: map[string]string{"team": "web"}},
+		},
 	}, apiLogConfigToStructs(&api.LogConfig{
-		Disabled:      pointer.Of(true),
-		MaxFiles:      pointer.Of(2),
-		MaxFileSizeMB: pointer.Of(8),
+		Disabled:       pointer.Of(true),
+		MaxFiles:       pointer.Of(2),
+		MaxFileSizeMB:  pointer.Of(8),
+		Backpressure:   pointer.Of("spill"),
+		RotateInterval: pointer.Of(time.Hour),
+		Compression:    pointer.Of("zstd"),
+		Retention:      pointer.Of(24 * time.Hour),
+		Sinks: []*api.LogSink{
+			{Name: "loki", Options: map[string]string{"team": "web"}},
+		},
 	}))
 
 	// COMPAT(1.6.0): verify backwards compatibility fixes
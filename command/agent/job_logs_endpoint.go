@@ -0,0 +1,234 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/hashicorp/nomad/api"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// jobLogMaxLineBytes is the size at which a log line without a trailing
+// newline is emitted as is, so a task writing without newlines can't grow the
+// line buffer without bound.
+const jobLogMaxLineBytes = 64 * 1024
+
+// jobLogs streams the stdout and stderr of every task of every allocation of a
+// job as newline delimited JSON encoded api.JobLogLine objects. Task restarts
+// are followed by the underlying log stream of each task. When following,
+// allocations placed after the request started, such as replacements, are
+// found with a blocking query and streamed as well.
+func (s *HTTPServer) jobLogs(resp http.ResponseWriter, req *http.Request, jobID string) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	var follow bool
+	var err error
+
+	q := req.URL.Query()
+	if followStr := q.Get("follow"); followStr != "" {
+		if follow, err = strconv.ParseBool(followStr); err != nil {
+			return nil, CodedError(400, fmt.Sprintf("failed to parse follow field to boolean: %v", err))
+		}
+	}
+
+	task := q.Get("task")
+
+	var logTypes []string
+	switch logType := q.Get("type"); logType {
+	case "stdout", "stderr":
+		logTypes = []string{logType}
+	case "":
+		logTypes = []string{"stdout", "stderr"}
+	default:
+		return nil, CodedError(400, "log type must be stdout or stderr")
+	}
+
+	var offset int64
+	if offsetString := q.Get("offset"); offsetString != "" {
+		if offset, err = strconv.ParseInt(offsetString, 10, 64); err != nil {
+			return nil, CodedError(400, fmt.Sprintf("error parsing offset: %v", err))
+		}
+	}
+
+	origin := q.Get("origin")
+	switch origin {
+	case "start", "end":
+	case "":
+		origin = "start"
+	default:
+		return nil, invalidOrigin
+	}
+
+	args := structs.JobSpecificRequest{
+		JobID: jobID,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var allocs structs.JobAllocationsResponse
+	if err := s.agent.RPC("Job.Allocations", &args, &allocs); err != nil {
+		return nil, err
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	enc := &jobLogEncoder{enc: json.NewEncoder(ioutils.NewWriteFlusher(resp))}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	started := make(map[string]struct{})
+	for {
+		for _, alloc := range allocs.Allocations {
+			for taskName := range alloc.TaskStates {
+				if task != "" && taskName != task {
+					continue
+				}
+				for _, logType := range logTypes {
+					key := alloc.ID + "/" + taskName + "/" + logType
+					if _, ok := started[key]; ok {
+						continue
+					}
+					started[key] = struct{}{}
+
+					fsReq := &cstructs.FsLogsRequest{
+						AllocID:      alloc.ID,
+						Task:         taskName,
+						LogType:      logType,
+						Offset:       offset,
+						Origin:       origin,
+						PlainText:    true,
+						Follow:       follow,
+						QueryOptions: args.QueryOptions,
+					}
+					fsReq.MinQueryIndex = 0
+					w := &jobLogWriter{
+						enc:       enc,
+						allocID:   alloc.ID,
+						taskGroup: alloc.TaskGroup,
+						task:      taskName,
+						stream:    logType,
+					}
+
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						s.streamJobTaskLogs(ctx, w, fsReq)
+					}()
+				}
+			}
+		}
+
+		if !follow {
+			break
+		}
+
+		args.MinQueryIndex = allocs.Index
+		allocs = structs.JobAllocationsResponse{}
+		if err := s.agent.RPC("Job.Allocations", &args, &allocs); err != nil {
+			s.logger.Debug("failed to watch job allocations for logs", "job_id", jobID, "error", err)
+			break
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	wg.Wait()
+	return nil, nil
+}
+
+// streamJobTaskLogs streams a single log stream of a task into w. Errors are
+// logged rather than returned, as the allocation may have been garbage
+// collected or not have started the task yet, and should not end the streams
+// of the other allocations.
+func (s *HTTPServer) streamJobTaskLogs(ctx context.Context, w *jobLogWriter, fsReq *cstructs.FsLogsRequest) {
+	if err := s.fsStreamTo(ctx, w, "FileSystem.Logs", fsReq, fsReq.AllocID); err != nil {
+		s.logger.Debug("failed to stream task logs", "alloc_id", fsReq.AllocID,
+			"task", fsReq.Task, "type", fsReq.LogType, "error", err)
+	}
+	if err := w.Flush(); err != nil {
+		s.logger.Debug("failed to write task logs", "alloc_id", fsReq.AllocID,
+			"task", fsReq.Task, "type", fsReq.LogType, "error", err)
+	}
+}
+
+// jobLogEncoder serializes the log lines of concurrent task streams onto a
+// single response.
+type jobLogEncoder struct {
+	l   sync.Mutex
+	enc *json.Encoder
+}
+
+func (e *jobLogEncoder) Encode(line *api.JobLogLine) error {
+	e.l.Lock()
+	defer e.l.Unlock()
+	return e.enc.Encode(line)
+}
+
+// jobLogWriter splits the plain text output of a single task log stream into
+// lines and encodes each line along with where it came from.
+type jobLogWriter struct {
+	enc       *jobLogEncoder
+	allocID   string
+	taskGroup string
+	task      string
+	stream    string
+	buf       []byte
+}
+
+func (w *jobLogWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.emit(w.buf[:i]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[i+1:]
+	}
+
+	if len(w.buf) >= jobLogMaxLineBytes {
+		if err := w.Flush(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush emits any buffered output that has not been terminated by a newline.
+func (w *jobLogWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.emit(w.buf)
+	w.buf = nil
+	return err
+}
+
+func (w *jobLogWriter) emit(line []byte) error {
+	return w.enc.Encode(&api.JobLogLine{
+		Timestamp: time.Now().UTC(),
+		AllocID:   w.allocID,
+		TaskGroup: w.taskGroup,
+		Task:      w.task,
+		Stream:    w.stream,
+		Line:      string(bytes.TrimSuffix(line, []byte{'\r'})),
+	})
+}
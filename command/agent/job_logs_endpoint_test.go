@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestHTTP_JobLogs_BadRequest(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		cases := map[string]string{
+			"/v1/job/foo/logs?type=stdin":    "log type must be stdout or stderr",
+			"/v1/job/foo/logs?follow=maybe":  "failed to parse follow",
+			"/v1/job/foo/logs?origin=middle": invalidOrigin.Error(),
+			"/v1/job/foo/logs?offset=abc":    "error parsing offset",
+		}
+		for path, expected := range cases {
+			req, err := http.NewRequest(http.MethodGet, path, nil)
+			must.NoError(t, err)
+			_, err = s.Server.JobSpecificRequest(httptest.NewRecorder(), req)
+			must.ErrorContains(t, err, expected)
+		}
+	})
+}
+
+func TestJobLogWriter(t *testing.T) {
+	ci.Parallel(t)
+
+	var buf bytes.Buffer
+	w := &jobLogWriter{
+		enc:       &jobLogEncoder{enc: json.NewEncoder(&buf)},
+		allocID:   "alloc",
+		taskGroup: "group",
+		task:      "task",
+		stream:    "stderr",
+	}
+
+	_, err := w.Write([]byte("first\r\nsec"))
+	must.NoError(t, err)
+	_, err = w.Write([]byte("ond\nthi"))
+	must.NoError(t, err)
+	must.NoError(t, w.Flush())
+
+	_, err = w.Write([]byte(strings.Repeat("x", jobLogMaxLineBytes)))
+	must.NoError(t, err)
+
+	dec := json.NewDecoder(&buf)
+	var lines []*api.JobLogLine
+	for dec.More() {
+		var line api.JobLogLine
+		must.NoError(t, dec.Decode(&line))
+		lines = append(lines, &line)
+	}
+
+	must.Len(t, 4, lines)
+	must.Eq(t, "first", lines[0].Line)
+	must.Eq(t, "second", lines[1].Line)
+	must.Eq(t, "thi", lines[2].Line)
+	must.Eq(t, jobLogMaxLineBytes, len(lines[3].Line))
+	for _, line := range lines {
+		must.Eq(t, "alloc", line.AllocID)
+		must.Eq(t, "group", line.TaskGroup)
+		must.Eq(t, "task", line.Task)
+		must.Eq(t, "stderr", line.Stream)
+		must.False(t, line.Timestamp.IsZero())
+	}
+}
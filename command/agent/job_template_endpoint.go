@@ -0,0 +1,210 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/jobspec2"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) JobTemplatesRequest(resp http.ResponseWriter, req *http.Request) (any, error) {
+	switch req.Method {
+	case http.MethodGet:
+		return s.jobTemplateList(resp, req)
+	case http.MethodPut, http.MethodPost:
+		return s.jobTemplateUpsert(resp, req, "")
+	default:
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) JobTemplateSpecificRequest(resp http.ResponseWriter, req *http.Request) (any, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/job-template/")
+	switch {
+	case strings.HasSuffix(path, "/render"):
+		name := strings.TrimSuffix(path, "/render")
+		return s.jobTemplateRender(resp, req, name)
+	default:
+		return s.jobTemplateCRUD(resp, req, path)
+	}
+}
+
+func (s *HTTPServer) jobTemplateCRUD(resp http.ResponseWriter, req *http.Request, name string) (any, error) {
+	switch req.Method {
+	case http.MethodGet:
+		return s.jobTemplateQuery(resp, req, name)
+	case http.MethodPut, http.MethodPost:
+		return s.jobTemplateUpsert(resp, req, name)
+	case http.MethodDelete:
+		return s.jobTemplateDelete(resp, req, name)
+	default:
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) jobTemplateList(resp http.ResponseWriter, req *http.Request) (any, error) {
+	args := structs.JobTemplateListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.JobTemplateListResponse
+	if err := s.agent.RPC("JobTemplate.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Templates == nil {
+		out.Templates = make([]*structs.JobTemplateStub, 0)
+	}
+	return out.Templates, nil
+}
+
+func (s *HTTPServer) jobTemplateQuery(resp http.ResponseWriter, req *http.Request, name string) (any, error) {
+	tmpl, err := s.getJobTemplate(resp, req, name)
+	if err != nil {
+		return nil, err
+	}
+	if tmpl == nil {
+		return nil, nil
+	}
+	return tmpl, nil
+}
+
+// getJobTemplate fetches a job template and sets the query meta of the
+// response. It returns nil without an error if the query was already handled.
+func (s *HTTPServer) getJobTemplate(resp http.ResponseWriter, req *http.Request, name string) (*structs.JobTemplate, error) {
+	args := structs.JobTemplateSpecificRequest{
+		Name: name,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleJobTemplateResponse
+	if err := s.agent.RPC("JobTemplate.GetJobTemplate", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Template == nil {
+		return nil, CodedError(http.StatusNotFound, "job template not found")
+	}
+	return out.Template, nil
+}
+
+func (s *HTTPServer) jobTemplateUpsert(resp http.ResponseWriter, req *http.Request, name string) (any, error) {
+	var tmpl structs.JobTemplate
+	if err := decodeBody(req, &tmpl); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+
+	if name != "" && tmpl.Name != name {
+		return nil, CodedError(http.StatusBadRequest, "Job template name does not match request path")
+	}
+
+	args := structs.JobTemplateUpsertRequest{
+		Templates: []*structs.JobTemplate{&tmpl},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("JobTemplate.UpsertJobTemplates", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) jobTemplateDelete(resp http.ResponseWriter, req *http.Request, name string) (any, error) {
+	args := structs.JobTemplateDeleteRequest{
+		Names: []string{name},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("JobTemplate.DeleteJobTemplates", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+// jobTemplateRender renders a job from a job template and the given variable
+// values. The job is not registered, so running it still requires the
+// submit-job capability in its namespace.
+func (s *HTTPServer) jobTemplateRender(resp http.ResponseWriter, req *http.Request, name string) (any, error) {
+	if req.Method != http.MethodPut && req.Method != http.MethodPost {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	var namespace string
+	parseNamespace(req, &namespace)
+
+	aclObj, err := s.ResolveToken(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the token can run the template and parse jobs in the namespace.
+	if !aclObj.AllowJobTemplateOperation(name, acl.JobTemplateCapabilityRun) {
+		return nil, structs.ErrPermissionDenied
+	}
+	if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityParseJob) &&
+		!aclObj.AllowNsOp(namespace, acl.NamespaceCapabilitySubmitJob) {
+		return nil, structs.ErrPermissionDenied
+	}
+
+	args := &api.JobTemplateRenderRequest{}
+	if err := decodeBody(req, &args); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+
+	tmpl, err := s.getJobTemplate(resp, req, name)
+	if err != nil || tmpl == nil {
+		return nil, err
+	}
+
+	// Sort the variable flags so errors about them are stable.
+	argVars := make([]string, 0, len(args.VariableFlags))
+	for k, v := range args.VariableFlags {
+		argVars = append(argVars, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(argVars)
+
+	job, err := jobspec2.ParseWithConfig(&jobspec2.ParseConfig{
+		Path:       tmpl.Name + ".nomad.hcl",
+		Body:       []byte(tmpl.Source),
+		AllowFS:    false,
+		ArgVars:    argVars,
+		VarContent: args.Variables,
+		Strict:     true,
+	})
+	if err != nil {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Failed to render job template: %v", err))
+	}
+
+	if args.Canonicalize {
+		job.Canonicalize()
+	}
+
+	return &api.JobTemplateRenderResponse{
+		Job: job,
+		Submission: &api.JobSubmission{
+			Source:        tmpl.Source,
+			Format:        "hcl2",
+			VariableFlags: args.VariableFlags,
+			Variables:     args.Variables,
+		},
+	}, nil
+}
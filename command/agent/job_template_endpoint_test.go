@@ -0,0 +1,115 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestHTTP_JobTemplate_CRUD(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		tmpl := mock.JobTemplate()
+
+		// Create the template.
+		req, err := http.NewRequest(http.MethodPut, "/v1/job-template/"+tmpl.Name, encodeReq(tmpl))
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+		_, err = s.Server.JobTemplateSpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.NotEq(t, "", respW.Header().Get("X-Nomad-Index"))
+
+		// Names must match the path.
+		req, err = http.NewRequest(http.MethodPut, "/v1/job-template/other", encodeReq(tmpl))
+		must.NoError(t, err)
+		_, err = s.Server.JobTemplateSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "does not match request path")
+
+		// List the templates.
+		req, err = http.NewRequest(http.MethodGet, "/v1/job-templates", nil)
+		must.NoError(t, err)
+		obj, err := s.Server.JobTemplatesRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+		stubs := obj.([]*structs.JobTemplateStub)
+		must.Len(t, 1, stubs)
+		must.Eq(t, tmpl.Name, stubs[0].Name)
+
+		// Read the template.
+		req, err = http.NewRequest(http.MethodGet, "/v1/job-template/"+tmpl.Name, nil)
+		must.NoError(t, err)
+		obj, err = s.Server.JobTemplateSpecificRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+		must.Eq(t, tmpl.Source, obj.(*structs.JobTemplate).Source)
+
+		// Delete the template.
+		req, err = http.NewRequest(http.MethodDelete, "/v1/job-template/"+tmpl.Name, nil)
+		must.NoError(t, err)
+		_, err = s.Server.JobTemplateSpecificRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+
+		req, err = http.NewRequest(http.MethodGet, "/v1/job-template/"+tmpl.Name, nil)
+		must.NoError(t, err)
+		_, err = s.Server.JobTemplateSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "job template not found")
+	})
+}
+
+func TestHTTP_JobTemplate_Render(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		tmpl := mock.JobTemplate()
+		args := structs.JobTemplateUpsertRequest{
+			Templates:    []*structs.JobTemplate{tmpl},
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.GenericResponse
+		must.NoError(t, s.Agent.RPC("JobTemplate.UpsertJobTemplates", &args, &resp))
+
+		path := "/v1/job-template/" + tmpl.Name + "/render"
+
+		// Variables without defaults must be set.
+		req, err := http.NewRequest(http.MethodPut, path, encodeReq(&api.JobTemplateRenderRequest{}))
+		must.NoError(t, err)
+		_, err = s.Server.JobTemplateSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "Failed to render job template")
+
+		// Variables are type checked.
+		req, err = http.NewRequest(http.MethodPut, path, encodeReq(&api.JobTemplateRenderRequest{
+			VariableFlags: map[string]string{"image": "nginx:1.25", "count": "many"},
+		}))
+		must.NoError(t, err)
+		_, err = s.Server.JobTemplateSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "Failed to render job template")
+
+		req, err = http.NewRequest(http.MethodPut, path, encodeReq(&api.JobTemplateRenderRequest{
+			VariableFlags: map[string]string{"image": "nginx:1.25"},
+			Variables:     `count = 3`,
+		}))
+		must.NoError(t, err)
+		obj, err := s.Server.JobTemplateSpecificRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+
+		out := obj.(*api.JobTemplateRenderResponse)
+		must.Eq(t, "web", *out.Job.ID)
+		must.Eq(t, 3, *out.Job.TaskGroups[0].Count)
+		must.Eq(t, "nginx:1.25", out.Job.TaskGroups[0].Tasks[0].Config["image"])
+		must.Eq(t, tmpl.Source, out.Submission.Source)
+		must.Eq(t, "hcl2", out.Submission.Format)
+
+		// Unknown templates are not found.
+		req, err = http.NewRequest(http.MethodPut, "/v1/job-template/missing/render",
+			encodeReq(&api.JobTemplateRenderRequest{}))
+		must.NoError(t, err)
+		_, err = s.Server.JobTemplateSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "job template not found")
+	})
+}
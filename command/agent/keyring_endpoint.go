@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/go-jose/go-jose/v3"
+	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -128,6 +129,13 @@ func (s *HTTPServer) KeyringRequest(resp http.ResponseWriter, req *http.Request)
 		default:
 			return nil, CodedError(405, ErrInvalidMethod)
 		}
+	case strings.HasPrefix(path, "seal"):
+		switch req.Method {
+		case http.MethodPost, http.MethodPut:
+			return s.keyringSealRequest(resp, req)
+		default:
+			return nil, CodedError(405, ErrInvalidMethod)
+		}
 	default:
 		return nil, CodedError(405, ErrInvalidMethod)
 	}
@@ -187,3 +195,25 @@ func (s *HTTPServer) keyringDeleteRequest(resp http.ResponseWriter, req *http.Re
 	setIndex(resp, out.Index)
 	return out, nil
 }
+
+func (s *HTTPServer) keyringSealRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
+
+	var in api.KeyringSealRequest
+	if err := decodeBody(req, &in); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+
+	args := structs.KeyringSealRequest{
+		JobID:     in.JobID,
+		TaskGroup: in.TaskGroup,
+		Task:      in.Task,
+		Values:    in.Values,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.KeyringSealResponse
+	if err := s.agent.RPC("Keyring.Seal", &args, &out); err != nil {
+		return nil, err
+	}
+	return &api.KeyringSealResponse{Values: out.Values}, nil
+}
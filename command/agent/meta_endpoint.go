@@ -51,6 +51,7 @@ func (s *HTTPServer) nodeMetaRead(resp http.ResponseWriter, req *http.Request) (
 		return nil, rpcErr
 	}
 
+	setIndex(resp, reply.Index)
 	return reply, nil
 }
 
@@ -90,6 +91,6 @@ func (s *HTTPServer) nodeMetaApply(resp http.ResponseWriter, req *http.Request)
 		return nil, rpcErr
 	}
 
+	setIndex(resp, reply.Index)
 	return reply, nil
-
 }
@@ -0,0 +1,244 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package metricspush
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// nameLabel is the label that holds the metric name during relabeling.
+const nameLabel = "__name__"
+
+// point is a single sample of a metric family. Summaries and histograms are
+// kept whole so they can be encoded natively by OTLP, and are expanded into
+// multiple series for remote-write.
+type point struct {
+	name   string
+	help   string
+	kind   dto.MetricType
+	labels map[string]string
+
+	// value is set for counters, gauges and untyped metrics.
+	value float64
+
+	// sum and count are set for summaries and histograms.
+	sum   float64
+	count uint64
+
+	quantiles []quantile
+	buckets   []bucket
+}
+
+type quantile struct {
+	quantile float64
+	value    float64
+}
+
+// bucket is a histogram bucket with a cumulative count.
+type bucket struct {
+	upperBound float64
+	count      uint64
+}
+
+// pointsFromFamilies converts gathered metric families into points.
+func pointsFromFamilies(families []*dto.MetricFamily) []*point {
+	var points []*point
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			p := &point{
+				name:   mf.GetName(),
+				help:   mf.GetHelp(),
+				kind:   mf.GetType(),
+				labels: make(map[string]string, len(m.GetLabel())),
+			}
+			for _, l := range m.GetLabel() {
+				p.labels[l.GetName()] = l.GetValue()
+			}
+
+			switch p.kind {
+			case dto.MetricType_COUNTER:
+				p.value = m.GetCounter().GetValue()
+			case dto.MetricType_GAUGE:
+				p.value = m.GetGauge().GetValue()
+			case dto.MetricType_SUMMARY:
+				s := m.GetSummary()
+				p.sum = s.GetSampleSum()
+				p.count = s.GetSampleCount()
+				for _, q := range s.GetQuantile() {
+					p.quantiles = append(p.quantiles, quantile{q.GetQuantile(), q.GetValue()})
+				}
+			case dto.MetricType_HISTOGRAM:
+				h := m.GetHistogram()
+				p.sum = h.GetSampleSum()
+				p.count = h.GetSampleCount()
+				for _, b := range h.GetBucket() {
+					p.buckets = append(p.buckets, bucket{b.GetUpperBound(), b.GetCumulativeCount()})
+				}
+			default:
+				p.kind = dto.MetricType_UNTYPED
+				p.value = m.GetUntyped().GetValue()
+			}
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// prefixFilter allows or blocks metrics by the prefix of their name. Prefixes
+// are configured in the dotted form of Nomad metric names and matched
+// against the underscored form of Prometheus metric names.
+type prefixFilter struct {
+	allowed       []string
+	blocked       []string
+	filterDefault bool
+}
+
+func newPrefixFilter(filters []string, filterDefault bool) *prefixFilter {
+	f := &prefixFilter{filterDefault: filterDefault}
+	for _, rule := range filters {
+		prefix := strings.ReplaceAll(rule[1:], ".", "_")
+		switch rule[0] {
+		case '+':
+			f.allowed = append(f.allowed, prefix)
+		case '-':
+			f.blocked = append(f.blocked, prefix)
+		}
+	}
+	return f
+}
+
+// allow returns whether a metric is pushed. The longest matching prefix
+// decides, and blocked prefixes win ties.
+func (f *prefixFilter) allow(name string) bool {
+	allowLen, blockLen := -1, -1
+	for _, p := range f.allowed {
+		if strings.HasPrefix(name, p) && len(p) > allowLen {
+			allowLen = len(p)
+		}
+	}
+	for _, p := range f.blocked {
+		if strings.HasPrefix(name, p) && len(p) > blockLen {
+			blockLen = len(p)
+		}
+	}
+
+	switch {
+	case allowLen == -1 && blockLen == -1:
+		return f.filterDefault
+	case blockLen >= allowLen:
+		return false
+	default:
+		return true
+	}
+}
+
+// relabelRule is a compiled relabel configuration.
+type relabelRule struct {
+	sourceLabels []string
+	separator    string
+	regex        *regexp.Regexp
+	targetLabel  string
+	replacement  string
+	action       string
+}
+
+func newRelabelRules(cfgs []*config.MetricsRelabelConfig) ([]*relabelRule, error) {
+	rules := make([]*relabelRule, 0, len(cfgs))
+	for i, cfg := range cfgs {
+		re, err := regexp.Compile("^(?:" + cfg.Regex + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("relabel %d: invalid regex: %v", i, err)
+		}
+		rules = append(rules, &relabelRule{
+			sourceLabels: cfg.SourceLabels,
+			separator:    cfg.Separator,
+			regex:        re,
+			targetLabel:  cfg.TargetLabel,
+			replacement:  cfg.Replacement,
+			action:       cfg.Action,
+		})
+	}
+	return rules, nil
+}
+
+// relabel applies the rules to the point in order. It returns false if the
+// point was dropped.
+func relabel(p *point, rules []*relabelRule) bool {
+	if len(rules) == 0 {
+		return true
+	}
+
+	labels := make(map[string]string, len(p.labels)+1)
+	for k, v := range p.labels {
+		labels[k] = v
+	}
+	labels[nameLabel] = p.name
+
+	for _, r := range rules {
+		values := make([]string, len(r.sourceLabels))
+		for i, l := range r.sourceLabels {
+			values[i] = labels[l]
+		}
+		value := strings.Join(values, r.separator)
+
+		switch r.action {
+		case config.RelabelActionKeep:
+			if !r.regex.MatchString(value) {
+				return false
+			}
+		case config.RelabelActionDrop:
+			if r.regex.MatchString(value) {
+				return false
+			}
+		case config.RelabelActionLabelDrop:
+			for k := range labels {
+				if k != nameLabel && r.regex.MatchString(k) {
+					delete(labels, k)
+				}
+			}
+		case config.RelabelActionLabelKeep:
+			for k := range labels {
+				if k != nameLabel && !r.regex.MatchString(k) {
+					delete(labels, k)
+				}
+			}
+		default:
+			match := r.regex.FindStringSubmatchIndex(value)
+			if match == nil {
+				continue
+			}
+			res := string(r.regex.ExpandString(nil, r.replacement, value, match))
+			if res == "" {
+				delete(labels, r.targetLabel)
+			} else {
+				labels[r.targetLabel] = res
+			}
+		}
+	}
+
+	name := labels[nameLabel]
+	if name == "" {
+		return false
+	}
+	delete(labels, nameLabel)
+	p.name = name
+	p.labels = labels
+	return true
+}
+
+// sortedLabelNames returns the label names in lexical order.
+func sortedLabelNames(labels map[string]string) []string {
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
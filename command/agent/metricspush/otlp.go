@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package metricspush
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+
+	"github.com/hashicorp/nomad/helper/otlp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// encodeOTLP encodes the points as an OTLP/HTTP JSON metrics export request.
+// The external labels are sent as resource attributes.
+func encodeOTLP(points []*point, externalLabels map[string]string, timestampNs int64) ([]byte, error) {
+	resourceLabels := map[string]string{"service.name": "nomad"}
+	for k, v := range externalLabels {
+		resourceLabels[k] = v
+	}
+
+	ts := strconv.FormatInt(timestampNs, 10)
+	scope := &otlp.ScopeMetrics{Scope: otlp.Scope{Name: "nomad"}}
+	byName := map[string]*otlp.Metric{}
+
+	for _, p := range points {
+		// Relabeling may change the kind of metric a name refers to, so the
+		// kind is part of the key.
+		key := p.name + "/" + p.kind.String()
+		m, ok := byName[key]
+		if !ok {
+			m = &otlp.Metric{Name: p.name, Description: p.help}
+			byName[key] = m
+			scope.Metrics = append(scope.Metrics, m)
+		}
+
+		// NaN and infinite values are not representable in JSON.
+		if math.IsNaN(p.value) || math.IsInf(p.value, 0) {
+			continue
+		}
+
+		attrs := otlp.StringAttributes(p.labels)
+		switch p.kind {
+		case dto.MetricType_COUNTER:
+			if m.Sum == nil {
+				m.Sum = &otlp.Sum{
+					AggregationTemporality: otlp.AggregationTemporalityCumulative,
+					IsMonotonic:            true,
+				}
+			}
+			m.Sum.DataPoints = append(m.Sum.DataPoints, &otlp.NumberDataPoint{
+				Attributes: attrs, TimeUnixNano: ts, AsDouble: p.value,
+			})
+		case dto.MetricType_SUMMARY:
+			if m.Summary == nil {
+				m.Summary = &otlp.Summary{}
+			}
+			dp := &otlp.SummaryDataPoint{
+				Attributes:     attrs,
+				TimeUnixNano:   ts,
+				Count:          strconv.FormatUint(p.count, 10),
+				Sum:            p.sum,
+				QuantileValues: []*otlp.QuantileValue{},
+			}
+			for _, q := range p.quantiles {
+				// Summaries without observations report NaN quantiles.
+				if math.IsNaN(q.value) || math.IsInf(q.value, 0) {
+					continue
+				}
+				dp.QuantileValues = append(dp.QuantileValues, &otlp.QuantileValue{
+					Quantile: q.quantile, Value: q.value,
+				})
+			}
+			m.Summary.DataPoints = append(m.Summary.DataPoints, dp)
+		case dto.MetricType_HISTOGRAM:
+			if m.Histogram == nil {
+				m.Histogram = &otlp.Histogram{
+					AggregationTemporality: otlp.AggregationTemporalityCumulative,
+				}
+			}
+			m.Histogram.DataPoints = append(m.Histogram.DataPoints, otlpHistogramPoint(p, attrs, ts))
+		default:
+			if m.Gauge == nil {
+				m.Gauge = &otlp.Gauge{}
+			}
+			m.Gauge.DataPoints = append(m.Gauge.DataPoints, &otlp.NumberDataPoint{
+				Attributes: attrs, TimeUnixNano: ts, AsDouble: p.value,
+			})
+		}
+	}
+
+	return json.Marshal(&otlp.ExportMetricsServiceRequest{
+		ResourceMetrics: []*otlp.ResourceMetrics{{
+			Resource:     otlp.Resource{Attributes: otlp.StringAttributes(resourceLabels)},
+			ScopeMetrics: []*otlp.ScopeMetrics{scope},
+		}},
+	})
+}
+
+// otlpHistogramPoint converts the cumulative Prometheus buckets into the
+// per-bucket counts used by OTLP. The +Inf bucket is implied by OTLP.
+func otlpHistogramPoint(p *point, attrs []*otlp.KeyValue, ts string) *otlp.HistogramDataPoint {
+	dp := &otlp.HistogramDataPoint{
+		Attributes:     attrs,
+		TimeUnixNano:   ts,
+		Count:          strconv.FormatUint(p.count, 10),
+		Sum:            p.sum,
+		BucketCounts:   []string{},
+		ExplicitBounds: []float64{},
+	}
+
+	var prev uint64
+	for _, b := range p.buckets {
+		if math.IsInf(b.upperBound, 1) {
+			break
+		}
+		dp.ExplicitBounds = append(dp.ExplicitBounds, b.upperBound)
+		dp.BucketCounts = append(dp.BucketCounts, strconv.FormatUint(b.count-prev, 10))
+		prev = b.count
+	}
+	dp.BucketCounts = append(dp.BucketCounts, strconv.FormatUint(p.count-prev, 10))
+	return dp
+}
@@ -0,0 +1,220 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package metricspush pushes the agent's metrics to remote endpoints using
+// the Prometheus remote-write protocol or OTLP/HTTP. Pushing is useful when
+// agents can not be scraped, such as clients behind NAT.
+package metricspush
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// ProtocolRemoteWrite is the Prometheus remote-write protocol.
+	ProtocolRemoteWrite = "prometheus_remote_write"
+
+	// ProtocolOTLP is the OTLP/HTTP protocol with JSON encoding.
+	ProtocolOTLP = "otlp"
+
+	// maxErrorBody is the amount of a failed response body that is logged.
+	maxErrorBody = 512
+)
+
+// Pusher periodically gathers metrics and pushes them to each configured
+// endpoint.
+type Pusher struct {
+	logger   hclog.Logger
+	gatherer prometheus.Gatherer
+	targets  []*target
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// target is a single push endpoint.
+type target struct {
+	protocol string
+	config   *config.MetricsPushConfig
+	client   *http.Client
+	filter   *prefixFilter
+	relabel  []*relabelRule
+	logger   hclog.Logger
+}
+
+// New returns a Pusher for the remote-write and OTLP endpoints. The
+// configurations must already be validated and canonicalized. Metrics are
+// read from gatherer, which is usually prometheus.DefaultGatherer.
+func New(logger hclog.Logger, gatherer prometheus.Gatherer,
+	remoteWrite, otlp []*config.MetricsPushConfig) (*Pusher, error) {
+
+	p := &Pusher{
+		logger:   logger.Named("metrics_push"),
+		gatherer: gatherer,
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+
+	add := func(protocol string, cfgs []*config.MetricsPushConfig) error {
+		for _, cfg := range cfgs {
+			t, err := newTarget(p.logger, protocol, cfg)
+			if err != nil {
+				return fmt.Errorf("%s %q: %v", protocol, cfg.Name, err)
+			}
+			p.targets = append(p.targets, t)
+		}
+		return nil
+	}
+	if err := add(ProtocolRemoteWrite, remoteWrite); err != nil {
+		return nil, err
+	}
+	if err := add(ProtocolOTLP, otlp); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func newTarget(logger hclog.Logger, protocol string, cfg *config.MetricsPushConfig) (*target, error) {
+	rules, err := newRelabelRules(cfg.Relabel)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConf := &tls.Config{
+		InsecureSkipVerify: cfg.TLSSkipVerify,
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_file: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failed to parse ca_file %q", cfg.CAFile)
+		}
+		tlsConf.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConf
+
+	filterDefault := true
+	if cfg.FilterDefault != nil {
+		filterDefault = *cfg.FilterDefault
+	}
+
+	return &target{
+		protocol: protocol,
+		config:   cfg,
+		client:   &http.Client{Transport: transport, Timeout: cfg.Timeout},
+		filter:   newPrefixFilter(cfg.PrefixFilter, filterDefault),
+		relabel:  rules,
+		logger:   logger.With("protocol", protocol, "name", cfg.Name),
+	}, nil
+}
+
+// Start starts pushing to every endpoint on its own interval.
+func (p *Pusher) Start() {
+	for _, t := range p.targets {
+		p.wg.Add(1)
+		go p.run(t)
+	}
+}
+
+// Stop stops pushing and waits for in-flight pushes to be cancelled.
+func (p *Pusher) Stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+func (p *Pusher) run(t *target) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(t.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := p.push(p.ctx, t); err != nil && p.ctx.Err() == nil {
+			t.logger.Warn("failed to push metrics", "error", err)
+		}
+	}
+}
+
+// push gathers, filters, relabels and sends the current metrics to the
+// target.
+func (p *Pusher) push(ctx context.Context, t *target) error {
+	families, err := p.gatherer.Gather()
+	if err != nil && len(families) == 0 {
+		return fmt.Errorf("failed to gather metrics: %v", err)
+	}
+
+	var points []*point
+	for _, pt := range pointsFromFamilies(families) {
+		if !t.filter.allow(pt.name) || !relabel(pt, t.relabel) {
+			continue
+		}
+		points = append(points, pt)
+	}
+
+	now := time.Now()
+	var body []byte
+	headers := http.Header{}
+	switch t.protocol {
+	case ProtocolRemoteWrite:
+		body, err = encodeRemoteWrite(points, t.config.Labels, now.UnixMilli())
+		if err != nil {
+			return fmt.Errorf("failed to encode metrics: %v", err)
+		}
+		headers.Set("Content-Encoding", "snappy")
+		headers.Set("Content-Type", "application/x-protobuf")
+		headers.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	case ProtocolOTLP:
+		body, err = encodeOTLP(points, t.config.Labels, now.UnixNano())
+		if err != nil {
+			return fmt.Errorf("failed to encode metrics: %v", err)
+		}
+		headers.Set("Content-Type", "application/json")
+	}
+	headers.Set("User-Agent", "Nomad")
+	for k, v := range t.config.Headers {
+		headers.Set(k, v)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.config.Address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = headers
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
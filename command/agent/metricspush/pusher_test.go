@@ -0,0 +1,326 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package metricspush
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/otlp"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/shoenig/test/must"
+)
+
+func testRegistry(t *testing.T) *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+
+	allocs := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nomad_client_allocations_running",
+		Help: "Running allocations",
+	}, []string{"node_id", "datacenter"})
+	allocs.WithLabelValues("node1", "dc1").Set(3)
+	must.NoError(t, reg.Register(allocs))
+
+	rpcs := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "nomad_client_rpc",
+	})
+	rpcs.Add(10)
+	must.NoError(t, reg.Register(rpcs))
+
+	runtime := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "nomad_runtime_num_goroutines",
+	})
+	runtime.Set(100)
+	must.NoError(t, reg.Register(runtime))
+
+	hist := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "nomad_client_latency",
+		Buckets: []float64{1, 5},
+	})
+	hist.Observe(0.5)
+	hist.Observe(2)
+	hist.Observe(10)
+	must.NoError(t, reg.Register(hist))
+
+	return reg
+}
+
+// decodeRemoteWrite decodes a remote-write request into a map of series
+// keyed by their sorted labels.
+func decodeRemoteWrite(t *testing.T, body []byte) map[string]float64 {
+	buf, err := snappy.Decode(nil, body)
+	must.NoError(t, err)
+
+	var req prompb.WriteRequest
+	must.NoError(t, req.Unmarshal(buf))
+
+	out := map[string]float64{}
+	for _, ts := range req.Timeseries {
+		var key string
+		for _, l := range ts.Labels {
+			key += l.Name + "=" + l.Value + ","
+		}
+		must.Len(t, 1, ts.Samples)
+		out[key] = ts.Samples[0].Value
+	}
+	return out
+}
+
+func TestPusher_RemoteWrite(t *testing.T) {
+	ci.Parallel(t)
+
+	reqCh := make(chan *http.Request, 1)
+	bodyCh := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		select {
+		case reqCh <- r:
+			bodyCh <- body
+		default:
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	cfg := &config.MetricsPushConfig{
+		Name:         "mimir",
+		Address:      srv.URL,
+		Headers:      map[string]string{"X-Scope-OrgID": "edge"},
+		Labels:       map[string]string{"cluster": "edge-1"},
+		PrefixFilter: []string{"-nomad.runtime"},
+		Relabel: []*config.MetricsRelabelConfig{
+			{SourceLabels: []string{"datacenter"}, TargetLabel: "dc"},
+			{Regex: "datacenter", Action: config.RelabelActionLabelDrop},
+		},
+	}
+	cfg.Canonicalize()
+	cfg.Interval = 10 * time.Millisecond
+	must.NoError(t, cfg.Validate())
+
+	p, err := New(testlog.HCLogger(t), testRegistry(t), []*config.MetricsPushConfig{cfg}, nil)
+	must.NoError(t, err)
+	p.Start()
+	defer p.Stop()
+
+	var req *http.Request
+	select {
+	case req = <-reqCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for push")
+	}
+	body := <-bodyCh
+
+	must.Eq(t, "snappy", req.Header.Get("Content-Encoding"))
+	must.Eq(t, "application/x-protobuf", req.Header.Get("Content-Type"))
+	must.Eq(t, "edge", req.Header.Get("X-Scope-OrgID"))
+
+	series := decodeRemoteWrite(t, body)
+	must.Eq(t, map[string]float64{
+		"__name__=nomad_client_allocations_running,cluster=edge-1,dc=dc1,node_id=node1,": 3,
+		"__name__=nomad_client_rpc,cluster=edge-1,":                                      10,
+		"__name__=nomad_client_latency_bucket,cluster=edge-1,le=1,":                      1,
+		"__name__=nomad_client_latency_bucket,cluster=edge-1,le=5,":                      2,
+		"__name__=nomad_client_latency_bucket,cluster=edge-1,le=+Inf,":                   3,
+		"__name__=nomad_client_latency_sum,cluster=edge-1,":                              12.5,
+		"__name__=nomad_client_latency_count,cluster=edge-1,":                            3,
+	}, series)
+}
+
+func TestPusher_OTLP(t *testing.T) {
+	ci.Parallel(t)
+
+	bodyCh := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		select {
+		case bodyCh <- body:
+		default:
+		}
+	}))
+	defer srv.Close()
+
+	cfg := &config.MetricsPushConfig{
+		Name:          "collector",
+		Address:       srv.URL + "/v1/metrics",
+		Labels:        map[string]string{"cluster": "edge-1"},
+		PrefixFilter:  []string{"+nomad.client"},
+		FilterDefault: pointer.Of(false),
+	}
+	cfg.Canonicalize()
+	cfg.Interval = 10 * time.Millisecond
+
+	p, err := New(testlog.HCLogger(t), testRegistry(t), nil, []*config.MetricsPushConfig{cfg})
+	must.NoError(t, err)
+	p.Start()
+	defer p.Stop()
+
+	var body []byte
+	select {
+	case body = <-bodyCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for push")
+	}
+
+	var req otlp.ExportMetricsServiceRequest
+	must.NoError(t, json.Unmarshal(body, &req))
+	must.Len(t, 1, req.ResourceMetrics)
+	rm := req.ResourceMetrics[0]
+	must.Eq(t, []*otlp.KeyValue{
+		otlp.String("cluster", "edge-1"),
+		otlp.String("service.name", "nomad"),
+	}, rm.Resource.Attributes)
+
+	metrics := map[string]*otlp.Metric{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+	must.MapLen(t, 3, metrics)
+	must.MapNotContainsKey(t, metrics, "nomad_runtime_num_goroutines")
+
+	allocs := metrics["nomad_client_allocations_running"]
+	must.NotNil(t, allocs.Gauge)
+	must.Eq(t, 3, allocs.Gauge.DataPoints[0].AsDouble)
+	must.Eq(t, "Running allocations", allocs.Description)
+
+	rpcs := metrics["nomad_client_rpc"]
+	must.NotNil(t, rpcs.Sum)
+	must.True(t, rpcs.Sum.IsMonotonic)
+	must.Eq(t, 10, rpcs.Sum.DataPoints[0].AsDouble)
+
+	latency := metrics["nomad_client_latency"]
+	must.NotNil(t, latency.Histogram)
+	dp := latency.Histogram.DataPoints[0]
+	must.Eq(t, "3", dp.Count)
+	must.Eq(t, []float64{1, 5}, dp.ExplicitBounds)
+	must.Eq(t, []string{"1", "1", "1"}, dp.BucketCounts)
+}
+
+func TestPusher_ErrorResponse(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "out of order sample", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	cfg := &config.MetricsPushConfig{Name: "mimir", Address: srv.URL}
+	cfg.Canonicalize()
+
+	p, err := New(testlog.HCLogger(t), testRegistry(t), []*config.MetricsPushConfig{cfg}, nil)
+	must.NoError(t, err)
+
+	err = p.push(p.ctx, p.targets[0])
+	must.ErrorContains(t, err, "unexpected response code 400: out of order sample")
+}
+
+func TestPrefixFilter(t *testing.T) {
+	ci.Parallel(t)
+
+	f := newPrefixFilter([]string{"+nomad.client", "-nomad.client.host", "+nomad.client.host.cpu"}, false)
+	must.True(t, f.allow("nomad_client_allocs"))
+	must.False(t, f.allow("nomad_client_host_memory_used"))
+	must.True(t, f.allow("nomad_client_host_cpu_user"))
+	must.False(t, f.allow("nomad_nomad_rpc"))
+
+	f = newPrefixFilter(nil, true)
+	must.True(t, f.allow("nomad_nomad_rpc"))
+}
+
+func TestRelabel(t *testing.T) {
+	ci.Parallel(t)
+
+	newPoint := func() *point {
+		return &point{
+			name:   "nomad_client_allocs_memory_usage",
+			labels: map[string]string{"task": "web", "task_group": "api", "alloc_id": "1234"},
+		}
+	}
+
+	cases := []struct {
+		name   string
+		rules  []*config.MetricsRelabelConfig
+		keep   bool
+		expect *point
+	}{
+		{
+			name:   "none",
+			keep:   true,
+			expect: newPoint(),
+		},
+		{
+			name: "keep",
+			rules: []*config.MetricsRelabelConfig{
+				{SourceLabels: []string{"__name__"}, Regex: "nomad_client_allocs_.*", Action: "keep"},
+			},
+			keep:   true,
+			expect: newPoint(),
+		},
+		{
+			name: "keep anchored",
+			rules: []*config.MetricsRelabelConfig{
+				{SourceLabels: []string{"__name__"}, Regex: "allocs", Action: "keep"},
+			},
+			keep: false,
+		},
+		{
+			name: "drop",
+			rules: []*config.MetricsRelabelConfig{
+				{SourceLabels: []string{"task_group", "task"}, Regex: "api;web", Action: "drop"},
+			},
+			keep: false,
+		},
+		{
+			name: "replace and labeldrop",
+			rules: []*config.MetricsRelabelConfig{
+				{SourceLabels: []string{"task_group", "task"}, Separator: "/", TargetLabel: "workload", Replacement: "$1/$2", Regex: "(.*)/(.*)"},
+				{Regex: "task.*|alloc_id", Action: "labeldrop"},
+			},
+			keep: true,
+			expect: &point{
+				name:   "nomad_client_allocs_memory_usage",
+				labels: map[string]string{"workload": "api/web"},
+			},
+		},
+		{
+			name: "rename metric",
+			rules: []*config.MetricsRelabelConfig{
+				{SourceLabels: []string{"__name__"}, Regex: "nomad_client_(.*)", TargetLabel: "__name__", Replacement: "edge_$1"},
+				{Regex: "task", Action: "labelkeep"},
+			},
+			keep: true,
+			expect: &point{
+				name:   "edge_allocs_memory_usage",
+				labels: map[string]string{"task": "web"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, r := range tc.rules {
+				r.Canonicalize()
+				must.NoError(t, r.Validate())
+			}
+			rules, err := newRelabelRules(tc.rules)
+			must.NoError(t, err)
+
+			p := newPoint()
+			must.Eq(t, tc.keep, relabel(p, rules))
+			if tc.keep {
+				must.Eq(t, tc.expect.name, p.name)
+				must.Eq(t, tc.expect.labels, p.labels)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package metricspush
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/golang/snappy"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// encodeRemoteWrite encodes the points as a snappy compressed Prometheus
+// remote-write WriteRequest. Summaries and histograms are expanded into the
+// series Prometheus would expose for them when scraped.
+func encodeRemoteWrite(points []*point, externalLabels map[string]string, timestampMs int64) ([]byte, error) {
+	req := &prompb.WriteRequest{}
+	for _, p := range points {
+		labels := make(map[string]string, len(p.labels)+len(externalLabels))
+		for k, v := range externalLabels {
+			labels[k] = v
+		}
+		for k, v := range p.labels {
+			labels[k] = v
+		}
+
+		series := func(name string, value float64, extra ...string) {
+			ls := make(map[string]string, len(labels)+1+len(extra)/2)
+			for k, v := range labels {
+				ls[k] = v
+			}
+			for i := 0; i+1 < len(extra); i += 2 {
+				ls[extra[i]] = extra[i+1]
+			}
+			ls[nameLabel] = name
+			req.Timeseries = append(req.Timeseries, timeSeries(ls, value, timestampMs))
+		}
+
+		switch p.kind {
+		case dto.MetricType_SUMMARY:
+			for _, q := range p.quantiles {
+				series(p.name, q.value, "quantile", formatFloat(q.quantile))
+			}
+			series(p.name+"_sum", p.sum)
+			series(p.name+"_count", float64(p.count))
+		case dto.MetricType_HISTOGRAM:
+			infSeen := false
+			for _, b := range p.buckets {
+				if math.IsInf(b.upperBound, 1) {
+					infSeen = true
+				}
+				series(p.name+"_bucket", float64(b.count), "le", formatFloat(b.upperBound))
+			}
+			if !infSeen {
+				series(p.name+"_bucket", float64(p.count), "le", "+Inf")
+			}
+			series(p.name+"_sum", p.sum)
+			series(p.name+"_count", float64(p.count))
+		default:
+			series(p.name, p.value)
+		}
+	}
+
+	buf, err := req.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return snappy.Encode(nil, buf), nil
+}
+
+// timeSeries returns a series with a single sample. The remote-write protocol
+// requires labels to be sorted by name.
+func timeSeries(labels map[string]string, value float64, timestampMs int64) prompb.TimeSeries {
+	ts := prompb.TimeSeries{
+		Labels:  make([]prompb.Label, 0, len(labels)),
+		Samples: []prompb.Sample{{Value: value, Timestamp: timestampMs}},
+	}
+	for _, name := range sortedLabelNames(labels) {
+		ts.Labels = append(ts.Labels, prompb.Label{Name: name, Value: labels[name]})
+	}
+	return ts
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -39,7 +39,7 @@ func (s *HTTPServer) NodesRequest(resp http.ResponseWriter, req *http.Request) (
 		out.Nodes = make([]*structs.NodeListStub, 0)
 	}
 
-	return out.Nodes, nil
+	return projectFields(out.Nodes, parseFields(req))
 }
 
 func (s *HTTPServer) NodeSpecificRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -57,6 +57,9 @@ func (s *HTTPServer) NodeSpecificRequest(resp http.ResponseWriter, req *http.Req
 	case strings.HasSuffix(path, "/eligibility"):
 		nodeName := strings.TrimSuffix(path, "/eligibility")
 		return s.nodeToggleEligibility(resp, req, nodeName)
+	case strings.HasSuffix(path, "/hardware"):
+		nodeName := strings.TrimSuffix(path, "/hardware")
+		return s.nodeHardware(resp, req, nodeName)
 	case strings.HasSuffix(path, "/purge"):
 		nodeName := strings.TrimSuffix(path, "/purge")
 		return s.nodePurge(resp, req, nodeName)
@@ -133,6 +136,7 @@ func (s *HTTPServer) nodeToggleDrain(resp http.ResponseWriter, req *http.Request
 			DrainSpec: structs.DrainSpec{
 				Deadline:         drainRequest.DrainSpec.Deadline,
 				IgnoreSystemJobs: drainRequest.DrainSpec.IgnoreSystemJobs,
+				CSIDetachTimeout: drainRequest.DrainSpec.CSIDetachTimeout,
 			},
 		}
 	}
@@ -194,6 +198,32 @@ func (s *HTTPServer) nodeQuery(resp http.ResponseWriter, req *http.Request,
 	return out.Node, nil
 }
 
+// nodeHardware returns the hardware inventory of the node built from its
+// fingerprints.
+func (s *HTTPServer) nodeHardware(resp http.ResponseWriter, req *http.Request,
+	nodeID string) (interface{}, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+	args := structs.NodeSpecificRequest{
+		NodeID: nodeID,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleNodeResponse
+	if err := s.agent.RPC("Node.GetNode", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Node == nil {
+		return nil, CodedError(404, "node not found")
+	}
+	return out.Node.Hardware(), nil
+}
+
 func (s *HTTPServer) nodePurge(resp http.ResponseWriter, req *http.Request, nodeID string) (interface{}, error) {
 	if req.Method != http.MethodPut && req.Method != http.MethodPost {
 		return nil, CodedError(405, ErrInvalidMethod)
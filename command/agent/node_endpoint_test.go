@@ -11,8 +11,10 @@ import (
 
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -573,3 +575,38 @@ func TestHTTP_NodeQuery(t *testing.T) {
 		}
 	})
 }
+
+func TestHTTP_NodeHardware(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		node := mock.Node()
+		node.Attributes["cpu.modelname"] = "AMD EPYC"
+		args := structs.NodeRegisterRequest{
+			Node:         node,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.NodeUpdateResponse
+		must.NoError(t, s.Agent.RPC("Node.Register", &args, &resp))
+
+		req, err := http.NewRequest(http.MethodGet, "/v1/node/"+node.ID+"/hardware", nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+
+		obj, err := s.Server.NodeSpecificRequest(respW, req)
+		must.NoError(t, err)
+		must.NotEq(t, "", respW.Header().Get("X-Nomad-Index"))
+
+		hw := obj.(*structs.NodeHardware)
+		must.Eq(t, node.ID, hw.NodeID)
+		must.Eq(t, "AMD EPYC", hw.CPU.ModelName)
+		must.Eq(t, 4, hw.CPU.Cores)
+		must.Len(t, 1, hw.NICs)
+		must.Eq(t, "eth0", hw.NICs[0].Device)
+
+		// an unknown node is not found
+		req, err = http.NewRequest(http.MethodGet, "/v1/node/"+uuid.Generate()+"/hardware", nil)
+		must.NoError(t, err)
+		_, err = s.Server.NodeSpecificRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "node not found")
+	})
+}
@@ -550,3 +550,243 @@ func (s *HTTPServer) upgradeCheckVaultWorkloadIdentity(resp http.ResponseWriter,
 	setMeta(resp, &out.QueryMeta)
 	return out, nil
 }
+
+// OperatorStateUsage is used to get the object counts and estimated memory
+// usage of the tables of the servers' state store.
+func (s *HTTPServer) OperatorStateUsage(resp http.ResponseWriter, req *http.Request) (any, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(405, ErrInvalidMethod)
+	}
+
+	args := structs.StateUsageRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.StateUsageResponse
+	if err := s.agent.RPC("Operator.StateUsage", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	return out, nil
+}
+
+// OperatorMaintenanceWindows is used to list and create the maintenance
+// windows of the nodes.
+func (s *HTTPServer) OperatorMaintenanceWindows(resp http.ResponseWriter, req *http.Request) (any, error) {
+	switch req.Method {
+	case http.MethodGet:
+		return s.maintenanceWindowList(resp, req)
+	case http.MethodPut, http.MethodPost:
+		return s.maintenanceWindowUpsert(resp, req, "")
+	default:
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+}
+
+// OperatorMaintenanceWindowSpecific is used to update and delete a
+// maintenance window.
+func (s *HTTPServer) OperatorMaintenanceWindowSpecific(resp http.ResponseWriter, req *http.Request) (any, error) {
+	id := strings.TrimPrefix(req.URL.Path, "/v1/operator/maintenance/")
+	if id == "" {
+		return nil, CodedError(http.StatusBadRequest, "missing maintenance window ID")
+	}
+
+	switch req.Method {
+	case http.MethodPut, http.MethodPost:
+		return s.maintenanceWindowUpsert(resp, req, id)
+	case http.MethodDelete:
+		return s.maintenanceWindowDelete(resp, req, id)
+	default:
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) maintenanceWindowList(resp http.ResponseWriter, req *http.Request) (any, error) {
+	args := structs.MaintenanceWindowListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.MaintenanceWindowListResponse
+	if err := s.agent.RPC("Operator.MaintenanceWindowList", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Windows == nil {
+		out.Windows = make([]*structs.MaintenanceWindow, 0)
+	}
+	return out.Windows, nil
+}
+
+func (s *HTTPServer) maintenanceWindowUpsert(resp http.ResponseWriter, req *http.Request, id string) (any, error) {
+	var window structs.MaintenanceWindow
+	if err := decodeBody(req, &window); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+
+	if id != "" {
+		if window.ID != "" && window.ID != id {
+			return nil, CodedError(http.StatusBadRequest, "Maintenance window ID does not match request path")
+		}
+		window.ID = id
+	}
+
+	args := structs.MaintenanceWindowUpsertRequest{
+		Windows: []*structs.MaintenanceWindow{&window},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.MaintenanceWindowUpsertResponse
+	if err := s.agent.RPC("Operator.MaintenanceWindowUpsert", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	if len(out.Windows) == 0 {
+		return nil, nil
+	}
+	return out.Windows[0], nil
+}
+
+func (s *HTTPServer) maintenanceWindowDelete(resp http.ResponseWriter, req *http.Request, id string) (any, error) {
+	args := structs.MaintenanceWindowDeleteRequest{
+		IDs: []string{id},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("Operator.MaintenanceWindowDelete", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+// OperatorClientUpgrades is used to list and start the rolling upgrades of the
+// client agents.
+func (s *HTTPServer) OperatorClientUpgrades(resp http.ResponseWriter, req *http.Request) (any, error) {
+	switch req.Method {
+	case http.MethodGet:
+		return s.clientUpgradeList(resp, req)
+	case http.MethodPut, http.MethodPost:
+		return s.clientUpgradeStart(resp, req)
+	default:
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+}
+
+// OperatorClientUpgradeSpecific is used to read a client upgrade, and to
+// pause, resume or cancel it.
+func (s *HTTPServer) OperatorClientUpgradeSpecific(resp http.ResponseWriter, req *http.Request) (any, error) {
+	path := strings.TrimPrefix(req.URL.Path, "/v1/operator/upgrade/client/")
+	id, action, _ := strings.Cut(path, "/")
+	if id == "" {
+		return nil, CodedError(http.StatusBadRequest, "missing client upgrade ID")
+	}
+
+	switch action {
+	case "":
+		if req.Method != http.MethodGet {
+			return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+		}
+		return s.clientUpgradeGet(resp, req, id)
+	case "pause":
+		return s.clientUpgradeUpdateStatus(resp, req, id, structs.ClientUpgradeStatusPaused)
+	case "resume":
+		return s.clientUpgradeUpdateStatus(resp, req, id, structs.ClientUpgradeStatusRunning)
+	case "cancel":
+		return s.clientUpgradeUpdateStatus(resp, req, id, structs.ClientUpgradeStatusCancelled)
+	default:
+		return nil, CodedError(http.StatusNotFound, fmt.Sprintf("Path %s not found", req.URL.Path))
+	}
+}
+
+func (s *HTTPServer) clientUpgradeList(resp http.ResponseWriter, req *http.Request) (any, error) {
+	args := structs.ClientUpgradeListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.ClientUpgradeListResponse
+	if err := s.agent.RPC("Operator.ClientUpgradeList", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Upgrades == nil {
+		out.Upgrades = make([]*structs.ClientUpgrade, 0)
+	}
+	return out.Upgrades, nil
+}
+
+func (s *HTTPServer) clientUpgradeGet(resp http.ResponseWriter, req *http.Request, id string) (any, error) {
+	args := structs.ClientUpgradeSpecificRequest{ID: id}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.ClientUpgradeResponse
+	if err := s.agent.RPC("Operator.ClientUpgradeGet", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Upgrade == nil {
+		return nil, CodedError(http.StatusNotFound, "client upgrade not found")
+	}
+	return out.Upgrade, nil
+}
+
+func (s *HTTPServer) clientUpgradeStart(resp http.ResponseWriter, req *http.Request) (any, error) {
+	var upgrade structs.ClientUpgrade
+	if err := decodeBody(req, &upgrade); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+
+	args := structs.ClientUpgradeStartRequest{
+		Upgrade: &upgrade,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.ClientUpgradeResponse
+	if err := s.agent.RPC("Operator.ClientUpgradeStart", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return out.Upgrade, nil
+}
+
+func (s *HTTPServer) clientUpgradeUpdateStatus(resp http.ResponseWriter, req *http.Request, id, status string) (any, error) {
+	if req.Method != http.MethodPut && req.Method != http.MethodPost {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	var body struct {
+		Reason string
+	}
+	if req.ContentLength != 0 {
+		if err := decodeBody(req, &body); err != nil {
+			return nil, CodedError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	args := structs.ClientUpgradeUpdateStatusRequest{
+		ID:     id,
+		Status: status,
+		Reason: body.Reason,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.ClientUpgradeResponse
+	if err := s.agent.RPC("Operator.ClientUpgradeUpdateStatus", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return out.Upgrade, nil
+}
@@ -103,5 +103,12 @@ func ApiScalingPolicyToStructs(count int, ap *api.ScalingPolicy) *structs.Scalin
 	} else {
 		p.Min = int64(count)
 	}
+	for _, schedule := range ap.Schedules {
+		p.Schedules = append(p.Schedules, &structs.ScalingSchedule{
+			Cron:     schedule.Cron,
+			Count:    schedule.Count,
+			TimeZone: schedule.TimeZone,
+		})
+	}
 	return &p
 }
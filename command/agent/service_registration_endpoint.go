@@ -93,9 +93,15 @@ func (s *HTTPServer) ServiceRegistrationRequest(resp http.ResponseWriter, req *h
 func (s *HTTPServer) serviceGetRequest(
 	resp http.ResponseWriter, req *http.Request, serviceName string) (interface{}, error) {
 
+	healthyOnly, err := parseBool(req, "healthy")
+	if err != nil {
+		return nil, err
+	}
+
 	args := structs.ServiceRegistrationByNameRequest{
 		ServiceName: serviceName,
 		Choose:      req.URL.Query().Get("choose"),
+		HealthyOnly: healthyOnly != nil && *healthyOnly,
 	}
 	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
 		return nil, nil
@@ -46,6 +46,8 @@ client {
   state_dir        = "/tmp/client-state"
   alloc_dir        = "/tmp/alloc"
   alloc_mounts_dir = "/tmp/mounts"
+  log_spill_dir    = "/tmp/log-spill"
+  log_sinks        = ["loki"]
   servers          = ["a.b.c:80", "127.0.0.1:1234"]
   node_class       = "linux-medium-64bit"
 
@@ -92,6 +94,7 @@ client {
 
   gc_interval              = "6s"
   gc_parallel_destroys     = 6
+  parallel_restores        = 4
   gc_disk_usage_threshold  = 82
   gc_inode_usage_threshold = 91
   gc_max_allocs            = 50
@@ -119,6 +122,7 @@ server {
   job_gc_interval               = "3m"
   job_gc_threshold              = "12h"
   eval_gc_threshold             = "12h"
+  eval_history_limit            = 20
   deployment_gc_threshold       = "12h"
   csi_volume_claim_gc_interval  = "3m"
   csi_volume_claim_gc_threshold = "12h"
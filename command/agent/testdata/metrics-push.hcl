@@ -0,0 +1,35 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: BUSL-1.1
+
+telemetry {
+  prometheus_remote_write "mimir" {
+    address       = "https://mimir.example.com/api/v1/push"
+    interval      = "15s"
+    timeout       = "5s"
+    prefix_filter = ["+nomad.client", "-nomad.client.host"]
+
+    headers {
+      X-Scope-OrgID = "edge"
+    }
+
+    labels {
+      cluster = "edge-1"
+    }
+
+    relabel {
+      source_labels = ["datacenter"]
+      target_label  = "dc"
+    }
+
+    relabel {
+      regex  = "datacenter"
+      action = "labeldrop"
+    }
+  }
+
+  otlp_metrics "collector" {
+    address        = "http://127.0.0.1:4318/v1/metrics"
+    filter_default = false
+    ca_file        = "/etc/nomad.d/collector-ca.pem"
+  }
+}
@@ -0,0 +1,12 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: BUSL-1.1
+
+server {
+  enabled = true
+
+  usage {
+    enabled    = true
+    resolution = "15m"
+    retention  = "720h"
+  }
+}
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) UsageRequest(resp http.ResponseWriter, req *http.Request) (any, error) {
+	if req.Method != http.MethodGet {
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+
+	args := structs.UsageListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	query := req.URL.Query()
+	args.JobID = query.Get("job")
+	args.GroupBy = query.Get("group_by")
+
+	var err error
+	if args.Start, err = parseUsageTime(query.Get("start")); err != nil {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Failed to parse start: %v", err))
+	}
+	if args.End, err = parseUsageTime(query.Get("end")); err != nil {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("Failed to parse end: %v", err))
+	}
+
+	var out structs.UsageListResponse
+	if err := s.agent.RPC("Usage.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Usage == nil {
+		out.Usage = make([]*structs.UsageRecord, 0)
+	}
+	return out.Usage, nil
+}
+
+// parseUsageTime parses a time given either in RFC 3339 format or as Unix
+// nanoseconds, returning it in Unix nanoseconds.
+func parseUsageTime(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if nanos, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return nanos, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, err
+	}
+	return t.UnixNano(), nil
+}
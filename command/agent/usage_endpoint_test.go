@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestHTTP_UsageRequest(t *testing.T) {
+	ci.Parallel(t)
+	httpTest(t, nil, func(s *TestAgent) {
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		store := s.Agent.server.State()
+		must.NoError(t, store.UpsertUsage(structs.MsgTypeTestSetup, 1000, &structs.UsageUpsertRequest{
+			Usage: []*structs.UsageRecord{
+				{Namespace: "default", JobID: "web", Start: start.UnixNano(), ReservedCPU: 10},
+				{Namespace: "default", JobID: "web", Start: start.Add(time.Hour).UnixNano(), ReservedCPU: 20},
+				{Namespace: "default", JobID: "api", Start: start.UnixNano(), ReservedCPU: 30},
+			},
+		}))
+
+		req, err := http.NewRequest(http.MethodGet, "/v1/usage?job=web&start=2024-01-01T01:00:00Z", nil)
+		must.NoError(t, err)
+		respW := httptest.NewRecorder()
+		obj, err := s.Server.UsageRequest(respW, req)
+		must.NoError(t, err)
+		must.Eq(t, "1000", respW.Header().Get("X-Nomad-Index"))
+
+		records := obj.([]*structs.UsageRecord)
+		must.Len(t, 1, records)
+		must.Eq(t, 20, records[0].ReservedCPU)
+
+		req, err = http.NewRequest(http.MethodGet, "/v1/usage?group_by=namespace", nil)
+		must.NoError(t, err)
+		obj, err = s.Server.UsageRequest(httptest.NewRecorder(), req)
+		must.NoError(t, err)
+		records = obj.([]*structs.UsageRecord)
+		must.Len(t, 2, records)
+		must.Eq(t, 40, records[0].ReservedCPU)
+
+		req, err = http.NewRequest(http.MethodGet, "/v1/usage?start=yesterday", nil)
+		must.NoError(t, err)
+		_, err = s.Server.UsageRequest(httptest.NewRecorder(), req)
+		must.ErrorContains(t, err, "Failed to parse start")
+	})
+}
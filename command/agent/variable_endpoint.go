@@ -19,6 +19,10 @@ var (
 
 	acquireLockQueryParam = string(structs.VarOpLockAcquire)
 	releaseLockQueryParam = string(structs.VarOpLockRelease)
+
+	versionQueryParam  = "version"
+	versionsQueryParam = "versions"
+	restoreQueryParam  = "restore"
 )
 
 func (s *HTTPServer) VariablesListRequest(resp http.ResponseWriter, req *http.Request) (interface{}, error) {
@@ -52,6 +56,9 @@ func (s *HTTPServer) VariableSpecificRequest(resp http.ResponseWriter, req *http
 
 	switch req.Method {
 	case http.MethodGet:
+		if _, ok := req.URL.Query()[versionsQueryParam]; ok {
+			return s.variableVersions(resp, req, path)
+		}
 		return s.variableQuery(resp, req, path)
 	case http.MethodPut, http.MethodPost:
 		urlParams := req.URL.Query()
@@ -66,6 +73,13 @@ func (s *HTTPServer) VariableSpecificRequest(resp http.ResponseWriter, req *http
 			return nil, CodedError(http.StatusBadRequest, "CAS can't be used with lock operations")
 		}
 
+		if _, ok := urlParams[restoreQueryParam]; ok {
+			if cq != "" || lockOperation != "" {
+				return nil, CodedError(http.StatusBadRequest, "restore can't be used with CAS or lock operations")
+			}
+			return s.variableRestore(resp, req, path)
+		}
+
 		if lockOperation == "" {
 			return s.variableUpsert(resp, req, path)
 		}
@@ -150,6 +164,13 @@ func (s *HTTPServer) variableQuery(resp http.ResponseWriter, req *http.Request,
 	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
 		return nil, CodedError(http.StatusBadRequest, "failed to parse parameters")
 	}
+	if vq := req.URL.Query().Get(versionQueryParam); vq != "" {
+		version, err := strconv.ParseUint(vq, 10, 64)
+		if err != nil {
+			return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("can not parse version: %v", err))
+		}
+		args.Version = version
+	}
 	var out structs.VariablesReadResponse
 	if err := s.agent.RPC(structs.VariablesReadRPCMethod, &args, &out); err != nil {
 		return nil, err
@@ -163,6 +184,50 @@ func (s *HTTPServer) variableQuery(resp http.ResponseWriter, req *http.Request,
 	return out.Data, nil
 }
 
+func (s *HTTPServer) variableVersions(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+	args := structs.VariablesVersionsRequest{
+		Path: path,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, CodedError(http.StatusBadRequest, "failed to parse parameters")
+	}
+	var out structs.VariablesVersionsResponse
+	if err := s.agent.RPC(structs.VariablesVersionsRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+
+	if len(out.Versions) == 0 {
+		return nil, CodedError(http.StatusNotFound, "variable not found")
+	}
+	return out.Versions, nil
+}
+
+func (s *HTTPServer) variableRestore(resp http.ResponseWriter, req *http.Request,
+	path string) (interface{}, error) {
+
+	version, err := strconv.ParseUint(req.URL.Query().Get(restoreQueryParam), 10, 64)
+	if err != nil {
+		return nil, CodedError(http.StatusBadRequest, fmt.Sprintf("can not parse restore version: %v", err))
+	}
+
+	args := structs.VariablesRestoreRequest{
+		Path:    path,
+		Version: version,
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.VariablesRestoreResponse
+	if err := s.agent.RPC(structs.VariablesRestoreRPCMethod, &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return out.VarMeta, nil
+}
+
 func (s *HTTPServer) variableUpsert(resp http.ResponseWriter, req *http.Request,
 	path string) (interface{}, error) {
 
@@ -294,8 +294,12 @@ func TestHTTP_Variables(t *testing.T) {
 				// Check that written varible does not equal the input to rule out input mutation
 				must.NotEqual(t, svU.VariableMetadata, out.VariableMetadata)
 
+				// Updates write a new version of the variable
+				must.Eq(t, sv.Version+1, out.Version)
+
 				// Update the input token with the updated metadata so that we
 				// can use a simple equality check
+				svU.Version = out.Version
 				svU.ModifyIndex = out.ModifyIndex
 				svU.ModifyTime = out.ModifyTime
 				must.Eq(t, &svU, out)
@@ -373,8 +377,12 @@ func TestHTTP_Variables(t *testing.T) {
 				must.NotEq(t, sv, out)
 				must.NotEqual(t, svU.VariableMetadata, out.VariableMetadata)
 
+				// Updates write a new version of the variable
+				must.Eq(t, sv.Version+1, out.Version)
+
 				// Update the input token with the updated metadata so that we
 				// can use a simple equality check
+				svU.Version = out.Version
 				svU.CreateIndex, svU.ModifyIndex = out.CreateIndex, out.ModifyIndex
 				svU.CreateTime, svU.ModifyTime = out.CreateTime, out.ModifyTime
 				must.Eq(t, svU.VariableMetadata, out.VariableMetadata)
@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package agent
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func (s *HTTPServer) VariableGrantsRequest(resp http.ResponseWriter, req *http.Request) (any, error) {
+	switch req.Method {
+	case http.MethodGet:
+		return s.variableGrantList(resp, req)
+	case http.MethodPut, http.MethodPost:
+		return s.variableGrantUpsert(resp, req, "")
+	default:
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) VariableGrantSpecificRequest(resp http.ResponseWriter, req *http.Request) (any, error) {
+	name := strings.TrimPrefix(req.URL.Path, "/v1/var-grant/")
+	if name == "" {
+		return nil, CodedError(http.StatusBadRequest, "missing variable grant name")
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		return s.variableGrantQuery(resp, req, name)
+	case http.MethodPut, http.MethodPost:
+		return s.variableGrantUpsert(resp, req, name)
+	case http.MethodDelete:
+		return s.variableGrantDelete(resp, req, name)
+	default:
+		return nil, CodedError(http.StatusMethodNotAllowed, ErrInvalidMethod)
+	}
+}
+
+func (s *HTTPServer) variableGrantList(resp http.ResponseWriter, req *http.Request) (any, error) {
+	args := structs.VariableGrantListRequest{}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.VariableGrantListResponse
+	if err := s.agent.RPC("VariableGrant.List", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Grants == nil {
+		out.Grants = make([]*structs.VariableGrant, 0)
+	}
+	return out.Grants, nil
+}
+
+func (s *HTTPServer) variableGrantQuery(resp http.ResponseWriter, req *http.Request, name string) (any, error) {
+	args := structs.VariableGrantSpecificRequest{
+		Name: name,
+	}
+	if s.parse(resp, req, &args.Region, &args.QueryOptions) {
+		return nil, nil
+	}
+
+	var out structs.SingleVariableGrantResponse
+	if err := s.agent.RPC("VariableGrant.GetVariableGrant", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setMeta(resp, &out.QueryMeta)
+	if out.Grant == nil {
+		return nil, CodedError(http.StatusNotFound, "variable grant not found")
+	}
+	return out.Grant, nil
+}
+
+func (s *HTTPServer) variableGrantUpsert(resp http.ResponseWriter, req *http.Request, name string) (any, error) {
+	var grant structs.VariableGrant
+	if err := decodeBody(req, &grant); err != nil {
+		return nil, CodedError(http.StatusBadRequest, err.Error())
+	}
+
+	if name != "" && grant.Name != name {
+		return nil, CodedError(http.StatusBadRequest, "Variable grant name does not match request path")
+	}
+
+	args := structs.VariableGrantUpsertRequest{
+		Grants: []*structs.VariableGrant{&grant},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("VariableGrant.UpsertVariableGrants", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return nil, nil
+}
+
+func (s *HTTPServer) variableGrantDelete(resp http.ResponseWriter, req *http.Request, name string) (any, error) {
+	args := structs.VariableGrantDeleteRequest{
+		Names: []string{name},
+	}
+	s.parseWriteRequest(req, &args.WriteRequest)
+
+	var out structs.GenericResponse
+	if err := s.agent.RPC("VariableGrant.DeleteVariableGrants", &args, &out); err != nil {
+		return nil, err
+	}
+
+	setIndex(resp, out.Index)
+	return nil, nil
+}
@@ -0,0 +1,452 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type AllocCpCommand struct {
+	Meta
+}
+
+func (c *AllocCpCommand) Help() string {
+	helpText := `
+Usage: nomad alloc cp [options] <src> <dest>
+
+  Copies files and directories between the local machine and an allocation
+  directory. Either the source or the destination must refer to an allocation
+  in the form <allocation>:<path>, where the path is relative to the root of
+  the alloc dir. Directories are copied recursively.
+
+  If the destination is an existing directory the source is copied into it,
+  otherwise the source is copied to the destination path. Files can't be
+  copied from or to the secrets and private directories of tasks.
+
+  When ACLs are enabled, copying from an allocation requires a token with the
+  'read-fs', 'read-job', and 'list-jobs' capabilities for the allocation's
+  namespace. Copying to an allocation requires the 'write-fs' capability
+  instead of 'read-fs', which isn't granted by the 'write' policy.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Cp Options:
+
+  -job
+    Use a random allocation from the specified job ID or prefix.
+
+  -task <task-name>
+    Resolve the path in the allocation relative to the directory of the task.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *AllocCpCommand) Synopsis() string {
+	return "Copy files between the local machine and an allocation"
+}
+
+func (c *AllocCpCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-job":  complete.PredictAnything,
+			"-task": complete.PredictAnything,
+		})
+}
+
+func (c *AllocCpCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(complete.PredictFiles("*"),
+		complete.PredictFunc(func(a complete.Args) []string {
+			client, err := c.Meta.Client()
+			if err != nil {
+				return nil
+			}
+
+			resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Allocs, nil)
+			if err != nil {
+				return []string{}
+			}
+			return resp.Matches[contexts.Allocs]
+		}))
+}
+
+func (c *AllocCpCommand) Name() string { return "alloc cp" }
+
+func (c *AllocCpCommand) Run(args []string) int {
+	var job bool
+	var task string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&job, "job", false, "")
+	flags.StringVar(&task, "task", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <src> <dest>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	src, dest := parseAllocCpPath(args[0]), parseAllocCpPath(args[1])
+	if src.alloc != "" && dest.alloc != "" {
+		c.Ui.Error("Copying between allocations is not supported")
+		return 1
+	}
+	if src.alloc == "" && dest.alloc == "" {
+		c.Ui.Error("Either the source or the destination must be in the form <allocation>:<path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	remote := src
+	if dest.alloc != "" {
+		remote = dest
+	}
+	if task != "" {
+		remote.path = path.Join(task, remote.path)
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %v", err))
+		return 1
+	}
+
+	alloc, code := c.lookupAlloc(client, remote.alloc, job)
+	if code != 0 {
+		return code
+	}
+	if task != "" {
+		if err := validateTaskExistsInAllocation(task, alloc); err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+	}
+
+	var stats *allocCpStats
+	if src.alloc != "" {
+		stats, err = c.download(client, alloc, remote.path, dest.path)
+	} else {
+		stats, err = c.upload(client, alloc, src.path, remote.path)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error copying files: %v", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Copied %d file(s) (%s) from %q to %q",
+		stats.files, humanize.IBytes(uint64(stats.bytes)), args[0], args[1]))
+	return 0
+}
+
+// lookupAlloc resolves the allocation by ID prefix, or picks a random
+// allocation of the job if byJob is set. It returns a non-zero exit code on
+// failure.
+func (c *AllocCpCommand) lookupAlloc(client *api.Client, id string, byJob bool) (*api.Allocation, int) {
+	allocID := id
+	if byJob {
+		jobID, ns, err := c.JobIDByPrefix(client, id, nil)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return nil, 1
+		}
+
+		allocID, err = getRandomJobAllocID(client, jobID, "", ns)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error fetching allocations: %v", err))
+			return nil, 1
+		}
+	}
+
+	if len(allocID) == 1 {
+		c.Ui.Error("Alloc ID must contain at least two characters.")
+		return nil, 1
+	}
+
+	allocID = sanitizeUUIDPrefix(allocID)
+	allocs, _, err := client.Allocations().PrefixList(allocID)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %v", err))
+		return nil, 1
+	}
+	if len(allocs) == 0 {
+		c.Ui.Error(fmt.Sprintf("No allocation(s) with prefix or id %q found", allocID))
+		return nil, 1
+	}
+	if len(allocs) > 1 {
+		out := formatAllocListStubs(allocs, false, shortId)
+		c.Ui.Error(fmt.Sprintf("Prefix matched multiple allocations\n\n%s", out))
+		return nil, 1
+	}
+
+	q := &api.QueryOptions{Namespace: allocs[0].Namespace}
+	alloc, _, err := client.Allocations().Info(allocs[0].ID, q)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocation: %s", err))
+		return nil, 1
+	}
+	return alloc, 0
+}
+
+// download copies the file or directory at the path of the allocation to the
+// local destination.
+func (c *AllocCpCommand) download(client *api.Client, alloc *api.Allocation, src, dest string) (*allocCpStats, error) {
+	archive, err := client.AllocFS().Archive(alloc, src, &api.QueryOptions{Namespace: alloc.Namespace})
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	// Copy into the destination if it's an existing directory, otherwise the
+	// root of the archive is renamed to the destination.
+	root, rename := dest, ""
+	if fi, err := os.Stat(dest); err != nil || !fi.IsDir() {
+		root, rename = filepath.Dir(dest), filepath.Base(dest)
+	}
+	return extractAllocCpArchive(archive, root, rename)
+}
+
+// upload copies the local file or directory to the path of the allocation.
+func (c *AllocCpCommand) upload(client *api.Client, alloc *api.Allocation, src, dest string) (*allocCpStats, error) {
+	if _, err := os.Lstat(src); err != nil {
+		return nil, err
+	}
+
+	// Copy into the destination if it's an existing directory, otherwise the
+	// source is renamed to the destination.
+	q := &api.QueryOptions{Namespace: alloc.Namespace}
+	name := filepath.Base(src)
+	if fi, _, err := client.AllocFS().Stat(alloc, dest, q); err != nil || !fi.IsDir {
+		dest, name = path.Dir(dest), path.Base(dest)
+	}
+
+	stats := &allocCpStats{}
+	pr, pw := io.Pipe()
+	archiveErrCh := make(chan error, 1)
+	go func() {
+		err := writeAllocCpArchive(pw, src, name, stats)
+		pw.CloseWithError(err)
+		archiveErrCh <- err
+	}()
+
+	_, err := client.AllocFS().Extract(alloc, dest, pr, &api.WriteOptions{Namespace: alloc.Namespace})
+	pr.Close()
+
+	// Prefer the error of reading the local files, since it also fails the
+	// upload.
+	if archiveErr := <-archiveErrCh; archiveErr != nil && !errors.Is(archiveErr, io.ErrClosedPipe) {
+		return nil, archiveErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// allocCpPath is a source or destination of alloc cp. The alloc is empty for
+// local paths.
+type allocCpPath struct {
+	alloc string
+	path  string
+}
+
+// parseAllocCpPath parses a path in the form <allocation>:<path>. Arguments
+// whose prefix contains a path separator, or is a single character like
+// Windows drive letters, are local paths.
+func parseAllocCpPath(arg string) allocCpPath {
+	alloc, p, ok := strings.Cut(arg, ":")
+	if !ok || len(alloc) < 2 || strings.ContainsAny(alloc, `/\`) {
+		return allocCpPath{path: arg}
+	}
+	if p == "" {
+		p = "/"
+	}
+	return allocCpPath{alloc: alloc, path: p}
+}
+
+// allocCpStats counts the files and bytes copied.
+type allocCpStats struct {
+	files int
+	bytes int64
+}
+
+// writeAllocCpArchive writes a tar archive of the local file or directory,
+// naming its root with the given name.
+func writeAllocCpArchive(w io.Writer, src, name string, stats *allocCpStats) error {
+	tw := tar.NewWriter(w)
+
+	walkFn := func(p string, fileInfo os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		link := ""
+		if fileInfo.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(fileInfo, filepath.ToSlash(link))
+		if err != nil {
+			return err
+		}
+		hdr.Name = path.Join(name, filepath.ToSlash(rel))
+		if fileInfo.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if !fileInfo.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		n, err := io.Copy(tw, f)
+		stats.files++
+		stats.bytes += n
+		return err
+	}
+
+	if err := filepath.Walk(src, walkFn); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// extractAllocCpArchive extracts a tar archive of an allocation into the
+// local root directory. If rename is set, it replaces the name of the root
+// entry of the archive. Entries and symlinks can't point outside of the copied
+// tree.
+func extractAllocCpArchive(r io.Reader, root, rename string) (*allocCpStats, error) {
+	stats := &allocCpStats{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return stats, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("invalid archive entry %q", hdr.Name)
+		}
+		if rename != "" {
+			first, rest, _ := strings.Cut(name, "/")
+			name = path.Join(rename, rest)
+			if first == "." {
+				return nil, fmt.Errorf("invalid archive entry %q", hdr.Name)
+			}
+		}
+		rel := filepath.FromSlash(name)
+		target := filepath.Join(root, rel)
+		if err := checkAllocCpParents(root, rel); err != nil {
+			return nil, err
+		}
+
+		mode := hdr.FileInfo().Mode().Perm()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, mode); err != nil {
+				return nil, err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return nil, err
+			}
+			if fi, err := os.Lstat(target); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+				if err := os.Remove(target); err != nil {
+					return nil, err
+				}
+			}
+			f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+			if err != nil {
+				return nil, err
+			}
+			n, err := io.Copy(f, tr)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return nil, err
+			}
+			stats.files++
+			stats.bytes += n
+		case tar.TypeSymlink:
+			link := filepath.FromSlash(hdr.Linkname)
+			resolved, err := filepath.Rel(root, filepath.Join(filepath.Dir(target), link))
+			if err != nil || filepath.IsAbs(link) || resolved == ".." ||
+				strings.HasPrefix(resolved, ".."+string(filepath.Separator)) {
+				return nil, fmt.Errorf("archive entry %q links outside of the destination", hdr.Name)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return nil, err
+			}
+			if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+			if err := os.Symlink(link, target); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("archive entry %q has unsupported type %q", hdr.Name, hdr.Typeflag)
+		}
+	}
+}
+
+// checkAllocCpParents returns an error if any parent directory of the path
+// relative to root is a symlink, so extracted files can't be written outside
+// of the destination through links.
+func checkAllocCpParents(root, rel string) error {
+	dir := root
+	parts := strings.Split(filepath.Dir(rel), string(filepath.Separator))
+	for _, part := range parts {
+		if part == "." {
+			continue
+		}
+		dir = filepath.Join(dir, part)
+		fi, err := os.Lstat(dir)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("cannot extract %q through symlink %q", rel, dir)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestAllocCpCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &AllocCpCommand{}
+}
+
+func TestAllocCpCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &AllocCpCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"./local", "/tmp/other"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "must be in the form <allocation>:<path>")
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"abcd:/a", "efgh:/b"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Copying between allocations is not supported")
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=nope", "abcd:/alloc/data", "."})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Error querying allocation")
+}
+
+func TestAllocCpCommand_ParsePath(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		arg      string
+		expected allocCpPath
+	}{
+		{"abcd:/alloc/data", allocCpPath{alloc: "abcd", path: "/alloc/data"}},
+		{"abcd:web/local", allocCpPath{alloc: "abcd", path: "web/local"}},
+		{"abcd:", allocCpPath{alloc: "abcd", path: "/"}},
+		{"local/file", allocCpPath{path: "local/file"}},
+		{"./abcd:efgh", allocCpPath{path: "./abcd:efgh"}},
+		{`C:\Users\file`, allocCpPath{path: `C:\Users\file`}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.arg, func(t *testing.T) {
+			must.Eq(t, tc.expected, parseAllocCpPath(tc.arg))
+		})
+	}
+}
+
+func TestAllocCpCommand_ArchiveRoundTrip(t *testing.T) {
+	ci.Parallel(t)
+
+	src := filepath.Join(t.TempDir(), "conf")
+	must.NoError(t, os.MkdirAll(filepath.Join(src, "nested"), 0o755))
+	must.NoError(t, os.WriteFile(filepath.Join(src, "a.txt"), []byte("a"), 0o644))
+	must.NoError(t, os.WriteFile(filepath.Join(src, "nested", "b.txt"), []byte("bb"), 0o644))
+	must.NoError(t, os.Symlink("a.txt", filepath.Join(src, "link")))
+
+	var buf bytes.Buffer
+	stats := &allocCpStats{}
+	must.NoError(t, writeAllocCpArchive(&buf, src, "renamed", stats))
+	must.Eq(t, &allocCpStats{files: 2, bytes: 3}, stats)
+
+	// Extracting into a directory renames the root of the archive
+	dest := t.TempDir()
+	stats, err := extractAllocCpArchive(bytes.NewReader(buf.Bytes()), dest, "copy")
+	must.NoError(t, err)
+	must.Eq(t, &allocCpStats{files: 2, bytes: 3}, stats)
+
+	out, err := os.ReadFile(filepath.Join(dest, "copy", "nested", "b.txt"))
+	must.NoError(t, err)
+	must.Eq(t, "bb", string(out))
+	link, err := os.Readlink(filepath.Join(dest, "copy", "link"))
+	must.NoError(t, err)
+	must.Eq(t, "a.txt", link)
+
+	// Without renaming the archive keeps its root name
+	_, err = extractAllocCpArchive(bytes.NewReader(buf.Bytes()), dest, "")
+	must.NoError(t, err)
+	must.FileExists(t, filepath.Join(dest, "renamed", "a.txt"))
+}
+
+func TestAllocCpCommand_ExtractEscapes(t *testing.T) {
+	ci.Parallel(t)
+
+	archive := func(hdrs ...*tar.Header) *bytes.Buffer {
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for _, hdr := range hdrs {
+			must.NoError(t, tw.WriteHeader(hdr))
+		}
+		must.NoError(t, tw.Close())
+		return &buf
+	}
+
+	dest := t.TempDir()
+	_, err := extractAllocCpArchive(archive(&tar.Header{
+		Name: "../escape", Typeflag: tar.TypeReg, Mode: 0o644}), dest, "")
+	must.ErrorContains(t, err, "invalid archive entry")
+
+	_, err = extractAllocCpArchive(archive(&tar.Header{
+		Name: "root/link", Typeflag: tar.TypeSymlink, Linkname: "../../etc"}), dest, "")
+	must.ErrorContains(t, err, "links outside of the destination")
+
+	// Files can't be written through symlinks of the archive
+	_, err = extractAllocCpArchive(archive(
+		&tar.Header{Name: "root/", Typeflag: tar.TypeDir, Mode: 0o755},
+		&tar.Header{Name: "root/out", Typeflag: tar.TypeSymlink, Linkname: "."},
+		&tar.Header{Name: "root/out/file", Typeflag: tar.TypeReg, Mode: 0o644},
+	), dest, "")
+	must.ErrorContains(t, err, "through symlink")
+}
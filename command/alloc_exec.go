@@ -27,6 +27,9 @@ type AllocExecCommand struct {
 	Stdin  io.Reader
 	Stdout io.WriteCloser
 	Stderr io.WriteCloser
+
+	// noRecord skips recording the session
+	noRecord bool
 }
 
 func (l *AllocExecCommand) Help() string {
@@ -65,6 +68,10 @@ Exec Specific Options:
     character is only recognized at the beginning of a line.  The escape character
     followed by a dot ('.') closes the connection.  Setting the character to
     'none' disables any escapes and makes the session fully transparent.
+
+  -no-record
+    Do not record the session on clients configured to record exec sessions.
+    Requires the 'alloc-exec-unrecorded' capability.
   `
 	return strings.TrimSpace(helpText)
 }
@@ -76,11 +83,12 @@ func (l *AllocExecCommand) Synopsis() string {
 func (l *AllocExecCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(l.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"--task": complete.PredictAnything,
-			"-job":   complete.PredictAnything,
-			"-i":     complete.PredictNothing,
-			"-t":     complete.PredictNothing,
-			"-e":     complete.PredictSet("none", "~"),
+			"--task":     complete.PredictAnything,
+			"-job":       complete.PredictAnything,
+			"-i":         complete.PredictNothing,
+			"-t":         complete.PredictNothing,
+			"-e":         complete.PredictSet("none", "~"),
+			"-no-record": complete.PredictNothing,
 		})
 }
 
@@ -112,6 +120,7 @@ func (l *AllocExecCommand) Run(args []string) int {
 	flags.BoolVar(&ttyOpt, "t", isTty(), "")
 	flags.StringVar(&escapeChar, "e", "~", "")
 	flags.StringVar(&task, "task", "", "")
+	flags.BoolVar(&l.noRecord, "no-record", false, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -297,8 +306,13 @@ func (l *AllocExecCommand) execImpl(client *api.Client, alloc *api.Allocation, t
 		}
 	}()
 
+	var q *api.QueryOptions
+	if l.noRecord {
+		q = &api.QueryOptions{Params: map[string]string{"no_record": "true"}}
+	}
+
 	return client.Allocations().Exec(ctx,
-		alloc, task, tty, command, stdin, stdout, stderr, sizeCh, nil)
+		alloc, task, tty, command, stdin, stdout, stderr, sizeCh, q)
 }
 
 // setRawTerminal sets the stream terminal in raw mode, so process captures
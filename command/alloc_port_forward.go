@@ -0,0 +1,274 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type AllocPortForwardCommand struct {
+	Meta
+}
+
+func (l *AllocPortForwardCommand) Help() string {
+	helpText := `
+Usage: nomad alloc port-forward [options] <allocation> <[local_port:]port>...
+
+  Forward one or more local ports to ports in the network namespace of the
+  given allocation. Connections to a local port are tunnelled to the port of
+  the allocation over the Nomad API, so services of allocations using bridge
+  networking can be reached without exposing their ports.
+
+  If only a port is given, the same port is used locally. A local port of 0
+  selects a random free port. The command forwards connections until it is
+  interrupted.
+
+  When ACLs are enabled, this command requires a token with the 'alloc-exec',
+  'read-job', and 'list-jobs' capabilities for the allocation's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Port Forward Specific Options:
+
+  -job
+    Use a random allocation from the specified job ID or prefix.
+
+  -bind <address>
+    Sets the local address to listen on. Defaults to "127.0.0.1".
+  `
+	return strings.TrimSpace(helpText)
+}
+
+func (l *AllocPortForwardCommand) Synopsis() string {
+	return "Forward local ports to an allocation"
+}
+
+func (l *AllocPortForwardCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(l.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-job":  complete.PredictAnything,
+			"-bind": complete.PredictAnything,
+		})
+}
+
+func (l *AllocPortForwardCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := l.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Allocs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Allocs]
+	})
+}
+
+func (l *AllocPortForwardCommand) Name() string { return "alloc port-forward" }
+
+func (l *AllocPortForwardCommand) Run(args []string) int {
+	var job bool
+	var bind string
+
+	flags := l.Meta.FlagSet(l.Name(), FlagSetClient)
+	flags.Usage = func() { l.Ui.Output(l.Help()) }
+	flags.BoolVar(&job, "job", false, "")
+	flags.StringVar(&bind, "bind", "127.0.0.1", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+
+	if len(args) < 1 {
+		if job {
+			l.Ui.Error("A job ID is required")
+		} else {
+			l.Ui.Error("An allocation ID is required")
+		}
+		l.Ui.Error(commandErrorText(l))
+		return 1
+	}
+
+	if !job && len(args[0]) == 1 {
+		l.Ui.Error("Alloc ID must contain at least two characters")
+		return 1
+	}
+
+	if len(args) < 2 {
+		l.Ui.Error("At least one port is required")
+		l.Ui.Error(commandErrorText(l))
+		return 1
+	}
+
+	mappings := make([]portMapping, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		m, err := parsePortMapping(arg)
+		if err != nil {
+			l.Ui.Error(err.Error())
+			return 1
+		}
+		mappings = append(mappings, m)
+	}
+
+	client, err := l.Meta.Client()
+	if err != nil {
+		l.Ui.Error(fmt.Sprintf("Error initializing client: %v", err))
+		return 1
+	}
+
+	var allocStub *api.AllocationListStub
+	if job {
+		jobID, ns, err := l.JobIDByPrefix(client, args[0], nil)
+		if err != nil {
+			l.Ui.Error(err.Error())
+			return 1
+		}
+
+		allocStub, err = getRandomJobAlloc(client, jobID, "", ns)
+		if err != nil {
+			l.Ui.Error(fmt.Sprintf("Error fetching allocations: %v", err))
+			return 1
+		}
+	} else {
+		allocID := args[0]
+		allocs, _, err := client.Allocations().PrefixList(sanitizeUUIDPrefix(allocID))
+		if err != nil {
+			l.Ui.Error(fmt.Sprintf("Error querying allocation: %v", err))
+			return 1
+		}
+
+		if len(allocs) == 0 {
+			l.Ui.Error(fmt.Sprintf("No allocation(s) with prefix or id %q found", allocID))
+			return 1
+		}
+
+		if len(allocs) > 1 {
+			out := formatAllocListStubs(allocs, false, shortId)
+			l.Ui.Error(fmt.Sprintf("Prefix matched multiple allocations\n\n%s", out))
+			return 1
+		}
+
+		allocStub = allocs[0]
+	}
+
+	q := &api.QueryOptions{Namespace: allocStub.Namespace}
+	alloc, _, err := client.Allocations().Info(allocStub.ID, q)
+	if err != nil {
+		l.Ui.Error(fmt.Sprintf("Error querying allocation: %s", err))
+		return 1
+	}
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	listeners := make([]net.Listener, 0, len(mappings))
+	defer func() {
+		for _, ln := range listeners {
+			ln.Close()
+		}
+	}()
+
+	for _, m := range mappings {
+		ln, err := net.Listen("tcp", net.JoinHostPort(bind, strconv.Itoa(m.local)))
+		if err != nil {
+			l.Ui.Error(fmt.Sprintf("Error listening on local port %d: %v", m.local, err))
+			return 1
+		}
+		listeners = append(listeners, ln)
+
+		l.Ui.Output(fmt.Sprintf("Forwarding from %s to port %d of allocation %s",
+			ln.Addr(), m.remote, limit(alloc.ID, shortId)))
+	}
+
+	var wg sync.WaitGroup
+	for i, ln := range listeners {
+		wg.Add(1)
+		go func(ln net.Listener, port int) {
+			defer wg.Done()
+			l.forward(ctx, client, alloc, ln, port, q)
+		}(ln, mappings[i].remote)
+	}
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+
+	<-signalCh
+	cancelFn()
+	for _, ln := range listeners {
+		ln.Close()
+	}
+	wg.Wait()
+
+	return 0
+}
+
+// forward accepts connections on the listener and forwards each of them to
+// the port of the allocation until the listener is closed.
+func (l *AllocPortForwardCommand) forward(ctx context.Context, client *api.Client,
+	alloc *api.Allocation, ln net.Listener, port int, q *api.QueryOptions) {
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := client.Allocations().PortForward(ctx, alloc, port, conn, q)
+			if err != nil && ctx.Err() == nil {
+				l.Ui.Error(fmt.Sprintf("Error forwarding connection from %s to port %d: %v",
+					conn.RemoteAddr(), port, err))
+			}
+		}()
+	}
+}
+
+// portMapping is a local port forwarded to a port of an allocation.
+type portMapping struct {
+	local  int
+	remote int
+}
+
+// parsePortMapping parses a port mapping of the form "[local_port:]port".
+func parsePortMapping(s string) (portMapping, error) {
+	local, remote, found := strings.Cut(s, ":")
+	if !found {
+		local, remote = s, s
+	}
+
+	var m portMapping
+	var err error
+	if m.local, err = strconv.Atoi(local); err != nil || m.local < 0 || m.local > 65535 {
+		return m, fmt.Errorf("Invalid local port in %q", s)
+	}
+	if m.remote, err = strconv.Atoi(remote); err != nil || m.remote < 1 || m.remote > 65535 {
+		return m, fmt.Errorf("Invalid port in %q", s)
+	}
+	return m, nil
+}
@@ -0,0 +1,100 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+// static check
+var _ cli.Command = &AllocPortForwardCommand{}
+
+func TestAllocPortForwardCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	cases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			"alloc id missing",
+			[]string{},
+			`An allocation ID is required`,
+		},
+		{
+			"alloc id too short",
+			[]string{"-address=" + url, "2", "8080"},
+			`Alloc ID must contain at least two characters`,
+		},
+		{
+			"port missing",
+			[]string{"-address=" + url, "26470238-5CF2-438F-8772-DC67CFB0705C"},
+			`At least one port is required`,
+		},
+		{
+			"invalid port",
+			[]string{"-address=" + url, "26470238-5CF2-438F-8772-DC67CFB0705C", "8080:http"},
+			`Invalid port in "8080:http"`,
+		},
+		{
+			"alloc not found",
+			[]string{"-address=" + url, "26470238-5CF2-438F-8772-DC67CFB0705C", "8080"},
+			`No allocation(s) with prefix or id "26470238-5CF2-438F-8772-DC67CFB0705C"`,
+		},
+		{
+			"job not found",
+			[]string{"-address=" + url, "-job", "example", "8080"},
+			`No job(s) with prefix or ID "example" found`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			cmd := &AllocPortForwardCommand{Meta: Meta{Ui: ui}}
+
+			code := cmd.Run(c.args)
+			must.One(t, code)
+			must.StrContains(t, ui.ErrorWriter.String(), c.expectedError)
+		})
+	}
+}
+
+func TestAllocPortForwardCommand_parsePortMapping(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		input  string
+		exp    portMapping
+		expErr string
+	}{
+		{input: "8080", exp: portMapping{local: 8080, remote: 8080}},
+		{input: "9090:80", exp: portMapping{local: 9090, remote: 80}},
+		{input: "0:80", exp: portMapping{local: 0, remote: 80}},
+		{input: ":80", expErr: `Invalid local port in ":80"`},
+		{input: "80:0", expErr: `Invalid port in "80:0"`},
+		{input: "70000", expErr: `Invalid local port in "70000"`},
+		{input: "http", expErr: `Invalid local port in "http"`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.input, func(t *testing.T) {
+			m, err := parsePortMapping(tc.input)
+			if tc.expErr != "" {
+				must.EqError(t, err, tc.expErr)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.exp.local, m.local)
+			must.Eq(t, tc.exp.remote, m.remote)
+		})
+	}
+}
@@ -304,6 +304,15 @@ func formatAllocBasicInfo(alloc *api.Allocation, client *api.Client, uuidLength
 		}
 	}
 
+	if reason := alloc.PendingReason; reason != nil && alloc.ClientStatus == api.AllocClientStatusPending {
+		pending := reason.Stage
+		if reason.Task != "" {
+			pending += fmt.Sprintf(" (task %q)", reason.Task)
+		}
+		basic = append(basic,
+			fmt.Sprintf("Pending Reason|%s: %s", pending, formatPendingReasonMessage(reason)))
+	}
+
 	if alloc.RescheduleTracker != nil && len(alloc.RescheduleTracker.Events) > 0 {
 		attempts, total := alloc.RescheduleInfo(time.Unix(0, alloc.ModifyTime))
 		// Show this section only if the reschedule policy limits the number of attempts
@@ -894,3 +903,24 @@ FOUND:
 		c.Ui.Output("") // line padding to next block
 	}
 }
+
+// formatPendingReasonMessage formats the message of a pending reason with the
+// details not already part of the message.
+func formatPendingReasonMessage(reason *api.AllocPendingReason) string {
+	keys := make([]string, 0, len(reason.Details))
+	for k, v := range reason.Details {
+		if !strings.Contains(reason.Message, v) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return reason.Message
+	}
+
+	sort.Strings(keys)
+	details := make([]string, len(keys))
+	for i, k := range keys {
+		details[i] = fmt.Sprintf("%s=%s", k, reason.Details[k])
+	}
+	return fmt.Sprintf("%s (%s)", reason.Message, strings.Join(details, ", "))
+}
@@ -17,6 +17,18 @@ var JobConnect []byte
 //go:embed connect-short.nomad.hcl
 var JobConnectShort []byte
 
+//go:embed driver-docker.nomad.hcl
+var JobDriverDocker []byte
+
+//go:embed driver-exec.nomad.hcl
+var JobDriverExec []byte
+
+//go:embed driver-java.nomad.hcl
+var JobDriverJava []byte
+
+//go:embed driver-raw_exec.nomad.hcl
+var JobDriverRawExec []byte
+
 //go:embed pool.nomad.hcl
 var NodePoolSpec []byte
 
@@ -0,0 +1,51 @@
+job "example" {
+  type = "service"
+
+  update {
+    max_parallel     = 1
+    health_check     = "checks"
+    min_healthy_time = "10s"
+    healthy_deadline = "5m"
+    auto_revert      = true
+  }
+
+  group "web" {
+    count = 2
+
+    network {
+      port "http" {}
+    }
+
+    service {
+      name     = "example-web"
+      port     = "http"
+      provider = "nomad"
+
+      check {
+        type     = "tcp"
+        interval = "10s"
+        timeout  = "2s"
+      }
+    }
+
+    task "server" {
+      driver = "exec"
+
+      # The binary runs in a chroot, so it must be downloaded into the task
+      # directory or be available in the chroot of the client.
+      artifact {
+        source = "https://example.com/server.tar.gz"
+      }
+
+      config {
+        command = "local/server"
+        args    = ["-listen", ":${NOMAD_PORT_http}"]
+      }
+
+      resources {
+        cpu    = 100
+        memory = 128
+      }
+    }
+  }
+}
@@ -0,0 +1,52 @@
+job "example" {
+  type = "service"
+
+  update {
+    max_parallel     = 1
+    health_check     = "checks"
+    min_healthy_time = "30s"
+    healthy_deadline = "5m"
+    auto_revert      = true
+  }
+
+  group "web" {
+    count = 2
+
+    network {
+      port "http" {}
+    }
+
+    service {
+      name     = "example-web"
+      port     = "http"
+      provider = "nomad"
+
+      check {
+        type     = "http"
+        path     = "/health"
+        interval = "10s"
+        timeout  = "2s"
+      }
+    }
+
+    task "server" {
+      driver = "java"
+
+      artifact {
+        source = "https://example.com/server.jar"
+      }
+
+      config {
+        jar_path    = "local/server.jar"
+        jvm_options = ["-Xmx384m", "-Xms128m"]
+        args        = ["--port", "${NOMAD_PORT_http}"]
+      }
+
+      # Leave room above the JVM heap for the memory used outside of it.
+      resources {
+        cpu    = 500
+        memory = 512
+      }
+    }
+  }
+}
@@ -0,0 +1,47 @@
+job "example" {
+  type = "service"
+
+  update {
+    max_parallel     = 1
+    health_check     = "checks"
+    min_healthy_time = "10s"
+    healthy_deadline = "5m"
+    auto_revert      = true
+  }
+
+  group "web" {
+    count = 1
+
+    network {
+      port "http" {}
+    }
+
+    service {
+      name     = "example-web"
+      port     = "http"
+      provider = "nomad"
+
+      check {
+        type     = "tcp"
+        interval = "10s"
+        timeout  = "2s"
+      }
+    }
+
+    task "server" {
+      # raw_exec runs the command as the user of the Nomad client without
+      # isolation, and must be enabled in the client configuration.
+      driver = "raw_exec"
+
+      config {
+        command = "python3"
+        args    = ["-m", "http.server", "${NOMAD_PORT_http}"]
+      }
+
+      resources {
+        cpu    = 100
+        memory = 128
+      }
+    }
+  }
+}
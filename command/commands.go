@@ -215,6 +215,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"acl token approve": func() (cli.Command, error) {
+			return &ACLTokenApproveCommand{
+				Meta: meta,
+			}, nil
+		},
 		"acl token delete": func() (cli.Command, error) {
 			return &ACLTokenDeleteCommand{
 				Meta: meta,
@@ -245,6 +250,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"alloc cp": func() (cli.Command, error) {
+			return &AllocCpCommand{
+				Meta: meta,
+			}, nil
+		},
 		"alloc exec": func() (cli.Command, error) {
 			return &AllocExecCommand{
 				Meta: meta,
@@ -270,6 +280,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"alloc port-forward": func() (cli.Command, error) {
+			return &AllocPortForwardCommand{
+				Meta: meta,
+			}, nil
+		},
 		"alloc restart": func() (cli.Command, error) {
 			return &AllocRestartCommand{
 				Meta: meta,
@@ -368,6 +383,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"eval bump": func() (cli.Command, error) {
+			return &EvalBumpCommand{
+				Meta: meta,
+			}, nil
+		},
 		"eval delete": func() (cli.Command, error) {
 			return &EvalDeleteCommand{
 				Meta: meta,
@@ -383,6 +403,31 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"event": func() (cli.Command, error) {
+			return &EventCommand{
+				Meta: meta,
+			}, nil
+		},
+		"event sink": func() (cli.Command, error) {
+			return &EventSinkCommand{
+				Meta: meta,
+			}, nil
+		},
+		"event sink deregister": func() (cli.Command, error) {
+			return &EventSinkDeregisterCommand{
+				Meta: meta,
+			}, nil
+		},
+		"event sink list": func() (cli.Command, error) {
+			return &EventSinkListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"event sink register": func() (cli.Command, error) {
+			return &EventSinkRegisterCommand{
+				Meta: meta,
+			}, nil
+		},
 		"eval-status": func() (cli.Command, error) {
 			return &EvalStatusCommand{
 				Meta: meta,
@@ -428,6 +473,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job logs": func() (cli.Command, error) {
+			return &JobLogsCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job restart": func() (cli.Command, error) {
 			// Use a *cli.ConcurrentUi because this command spawns several
 			// goroutines that write to the terminal concurrently.
@@ -466,6 +516,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job lint": func() (cli.Command, error) {
+			return &JobLintCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job periodic": func() (cli.Command, error) {
 			return &JobPeriodicCommand{
 				Meta: meta,
@@ -506,6 +561,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job schema": func() (cli.Command, error) {
+			return &JobSchemaCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job status": func() (cli.Command, error) {
 			return &JobStatusCommand{
 				Meta: meta,
@@ -516,6 +576,31 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"job template": func() (cli.Command, error) {
+			return &JobTemplateCommand{
+				Meta: meta,
+			}, nil
+		},
+		"job template apply": func() (cli.Command, error) {
+			return &JobTemplateApplyCommand{
+				Meta: meta,
+			}, nil
+		},
+		"job template delete": func() (cli.Command, error) {
+			return &JobTemplateDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"job template info": func() (cli.Command, error) {
+			return &JobTemplateInfoCommand{
+				Meta: meta,
+			}, nil
+		},
+		"job template list": func() (cli.Command, error) {
+			return &JobTemplateListCommand{
+				Meta: meta,
+			}, nil
+		},
 		"job validate": func() (cli.Command, error) {
 			return &JobValidateCommand{
 				Meta: meta,
@@ -696,6 +781,16 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 			}, nil
 		},
 
+		"operator client": func() (cli.Command, error) {
+			return &OperatorClientCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator client fsck": func() (cli.Command, error) {
+			return &OperatorClientFsckCommand{
+				Meta: meta,
+			}, nil
+		},
 		"operator client-state": func() (cli.Command, error) {
 			return &OperatorClientStateCommand{
 				Meta: meta,
@@ -737,6 +832,26 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 			}, nil
 		},
 
+		"operator maintenance": func() (cli.Command, error) {
+			return &OperatorMaintenanceCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator maintenance create": func() (cli.Command, error) {
+			return &OperatorMaintenanceCreateCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator maintenance delete": func() (cli.Command, error) {
+			return &OperatorMaintenanceDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator maintenance list": func() (cli.Command, error) {
+			return &OperatorMaintenanceListCommand{
+				Meta: meta,
+			}, nil
+		},
 		"operator metrics": func() (cli.Command, error) {
 			return &OperatorMetricsCommand{
 				Meta: meta,
@@ -779,11 +894,26 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"operator raft surgery": func() (cli.Command, error) {
+			return &OperatorRaftSurgeryCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator raft verify": func() (cli.Command, error) {
+			return &OperatorRaftVerifyCommand{
+				Meta: meta,
+			}, nil
+		},
 		"operator scheduler": func() (cli.Command, error) {
 			return &OperatorSchedulerCommand{
 				Meta: meta,
 			}, nil
 		},
+		"operator scheduler bench": func() (cli.Command, error) {
+			return &OperatorSchedulerBenchCommand{
+				Meta: meta,
+			}, nil
+		},
 		"operator scheduler get-config": func() (cli.Command, error) {
 			return &OperatorSchedulerGetConfig{
 				Meta: meta,
@@ -794,6 +924,16 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"operator upgrade": func() (cli.Command, error) {
+			return &OperatorUpgradeCommand{
+				Meta: meta,
+			}, nil
+		},
+		"operator upgrade clients": func() (cli.Command, error) {
+			return &OperatorUpgradeClientsCommand{
+				Meta: meta,
+			}, nil
+		},
 		"operator root keyring": func() (cli.Command, error) {
 			return &OperatorRootKeyringCommand{
 				Meta: meta,
@@ -1116,6 +1256,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"top": func() (cli.Command, error) {
+			return &TopCommand{
+				Meta: meta,
+			}, nil
+		},
 		"ui": func() (cli.Command, error) {
 			return &UiCommand{
 				Meta: meta,
@@ -1163,6 +1308,36 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"var grant": func() (cli.Command, error) {
+			return &VarGrantCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var grant apply": func() (cli.Command, error) {
+			return &VarGrantApplyCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var grant delete": func() (cli.Command, error) {
+			return &VarGrantDeleteCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var grant list": func() (cli.Command, error) {
+			return &VarGrantListCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var restore": func() (cli.Command, error) {
+			return &VarRestoreCommand{
+				Meta: meta,
+			}, nil
+		},
+		"var versions": func() (cli.Command, error) {
+			return &VarVersionsCommand{
+				Meta: meta,
+			}, nil
+		},
 		"version": func() (cli.Command, error) {
 			return &VersionCommand{
 				Version: version.GetVersion(),
@@ -1199,6 +1374,11 @@ func Commands(metaPtr *Meta, agentUi cli.Ui) map[string]cli.CommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"volume expand": func() (cli.Command, error) {
+			return &VolumeExpandCommand{
+				Meta: meta,
+			}, nil
+		},
 		"volume create": func() (cli.Command, error) {
 			return &VolumeCreateCommand{
 				Meta: meta,
@@ -186,9 +186,35 @@ func (c *DeploymentStatusCommand) Run(args []string) int {
 		return 0
 	}
 	c.Ui.Output(c.Colorize().Color(formatDeployment(client, deploy, length)))
+
+	// Show what changed in the job version being deployed. Errors are
+	// ignored since the job may have been purged or the server may not
+	// support diffing versions.
+	q := &api.QueryOptions{Namespace: deploy.Namespace}
+	if diff, _, err := client.Jobs().VersionDiff(deploy.JobID, nil, &deploy.JobVersion, q); err == nil {
+		if changes := formatDeploymentJobChanges(diff.Summary); changes != "" {
+			c.Ui.Output(c.Colorize().Color(changes))
+		}
+	}
 	return 0
 }
 
+// formatDeploymentJobChanges formats the summary of the changes of the job
+// version of a deployment from its previous version.
+func formatDeploymentJobChanges(summary *api.JobDiffSummary) string {
+	if summary == nil || len(summary.Changes) == 0 {
+		return ""
+	}
+
+	rows := make([]string, len(summary.Changes)+1)
+	rows[0] = "Category|Type|Path"
+	for i, change := range summary.Changes {
+		rows[i+1] = fmt.Sprintf("%s|%s|%s", change.Category, change.Type, change.Path)
+	}
+	return fmt.Sprintf("\n[bold]Job Changes (version %d to %d)[reset]\n%s",
+		summary.FromVersion, summary.ToVersion, formatList(rows))
+}
+
 func (c *DeploymentStatusCommand) monitor(client *api.Client, deployID string, index uint64, wait time.Duration, verbose bool) (status string, err error) {
 	if isStdoutTerminal() {
 		return c.ttyMonitor(client, deployID, index, wait, verbose)
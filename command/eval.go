@@ -34,6 +34,10 @@ Usage: nomad eval <subcommand> [options] [args]
 
       $ nomad eval delete <eval-id>
 
+  Raise the priority of pending evaluations:
+
+      $ nomad eval bump -priority=90 <eval-id>
+
   Please see the individual subcommand help for detailed usage information.
 `
 
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type EvalBumpCommand struct {
+	Meta
+}
+
+func (c *EvalBumpCommand) Help() string {
+	helpText := `
+Usage: nomad eval bump [options] <evaluation> [<evaluation>...]
+
+  Raise the priority of pending or blocked evaluations, so they are processed
+  before evaluations of lower priority. The pending or blocked follow-up
+  evaluations of the evaluations are bumped as well, and follow-up
+  evaluations created later inherit the new priority. If ACLs are enabled,
+  this command requires a token with the 'operator:write' capability.
+
+  This command is meant to be used during incidents, when there is a backlog
+  of evaluations and the evaluations of critical jobs need to jump the queue.
+  The priority of a job is not changed. Bumps are recorded on the evaluations
+  and published to the event stream.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsNoNamespace) + `
+
+Eval Bump Options:
+
+  -priority
+    The new priority of the evaluations. It must be higher than their current
+    priority. Required.
+`
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EvalBumpCommand) Synopsis() string {
+	return "Raise the priority of pending evaluations"
+}
+
+func (c *EvalBumpCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-priority": complete.PredictAnything,
+		})
+}
+
+func (c *EvalBumpCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Evals, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Evals]
+	})
+}
+
+func (c *EvalBumpCommand) Name() string { return "eval bump" }
+
+func (c *EvalBumpCommand) Run(args []string) int {
+	var priority int
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.IntVar(&priority, "priority", 0, "")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) == 0 {
+		c.Ui.Error("This command takes at least one argument: <evaluation>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if priority <= 0 {
+		c.Ui.Error("The -priority flag is required and must be positive")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Resolve the evaluation ID prefixes
+	evalIDs := make([]string, 0, len(args))
+	for _, evalID := range args {
+		if len(evalID) == 1 {
+			c.Ui.Error("Identifier must contain at least two characters.")
+			return 1
+		}
+
+		evalID = sanitizeUUIDPrefix(evalID)
+		evals, _, err := client.Evaluations().PrefixList(evalID)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying evaluation: %v", err))
+			return 1
+		}
+		if len(evals) == 0 {
+			c.Ui.Error(fmt.Sprintf("No evaluation(s) with prefix or id %q found", evalID))
+			return 1
+		}
+		if len(evals) > 1 {
+			c.Ui.Error(fmt.Sprintf("Prefix matched multiple evaluations\n\n%s", formatEvalList(evals, false)))
+			return 1
+		}
+		evalIDs = append(evalIDs, evals[0].ID)
+	}
+
+	bumped, _, err := client.Evaluations().BumpPriority(evalIDs, priority, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error bumping evaluations: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully bumped the priority of %d evaluation(s) to %d", len(bumped), priority))
+	for _, id := range bumped {
+		c.Ui.Output(fmt.Sprintf("  %s", id))
+	}
+	return 0
+}
@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestEvalBumpCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &EvalBumpCommand{}
+}
+
+func TestEvalBumpCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &EvalBumpCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on missing evaluations
+	must.One(t, cmd.Run([]string{"-address=" + url, "-priority=90"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "This command takes at least one argument")
+	ui.ErrorWriter.Reset()
+
+	// Fails on missing priority
+	must.One(t, cmd.Run([]string{"-address=" + url, "fa3a8c37-eac3-00c7-3410-5ba3f7318fd8"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "The -priority flag is required")
+	ui.ErrorWriter.Reset()
+
+	// Fails on unknown evaluations
+	must.One(t, cmd.Run([]string{"-address=" + url, "-priority=90", "fa3a8c37-eac3-00c7-3410-5ba3f7318fd8"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "No evaluation(s) with prefix or id")
+}
@@ -47,6 +47,13 @@ Eval List Options:
   -status
     Only show evaluations with this status.
 
+  -triggered-by
+    Only show evaluations with this trigger reason, such as "job-register"
+    or "node-update".
+
+  -node
+    Only show evaluations for this node ID.
+
   -json
     Output the evaluation in its JSON format.
 
@@ -64,14 +71,16 @@ func (c *EvalListCommand) Synopsis() string {
 func (c *EvalListCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-json":       complete.PredictNothing,
-			"-t":          complete.PredictAnything,
-			"-verbose":    complete.PredictNothing,
-			"-filter":     complete.PredictAnything,
-			"-job":        complete.PredictAnything,
-			"-status":     complete.PredictAnything,
-			"-per-page":   complete.PredictAnything,
-			"-page-token": complete.PredictAnything,
+			"-json":         complete.PredictNothing,
+			"-t":            complete.PredictAnything,
+			"-verbose":      complete.PredictNothing,
+			"-filter":       complete.PredictAnything,
+			"-job":          complete.PredictAnything,
+			"-status":       complete.PredictAnything,
+			"-triggered-by": complete.PredictAnything,
+			"-node":         complete.PredictAnything,
+			"-per-page":     complete.PredictAnything,
+			"-page-token":   complete.PredictAnything,
 		})
 }
 
@@ -95,7 +104,7 @@ func (c *EvalListCommand) Name() string { return "eval list" }
 func (c *EvalListCommand) Run(args []string) int {
 	var monitor, verbose, json bool
 	var perPage int
-	var tmpl, pageToken, filter, filterJobID, filterStatus string
+	var tmpl, pageToken, filter, filterJobID, filterStatus, filterTriggeredBy, filterNodeID string
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -108,6 +117,8 @@ func (c *EvalListCommand) Run(args []string) int {
 	flags.StringVar(&filter, "filter", "", "")
 	flags.StringVar(&filterJobID, "job", "", "")
 	flags.StringVar(&filterStatus, "status", "", "")
+	flags.StringVar(&filterTriggeredBy, "triggered-by", "", "")
+	flags.StringVar(&filterNodeID, "node", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -139,6 +150,12 @@ func (c *EvalListCommand) Run(args []string) int {
 	if filterStatus != "" {
 		opts.Params["status"] = filterStatus
 	}
+	if filterTriggeredBy != "" {
+		opts.Params["triggered_by"] = filterTriggeredBy
+	}
+	if filterNodeID != "" {
+		opts.Params["node_id"] = filterNodeID
+	}
 
 	evals, qm, err := client.Evaluations().List(opts)
 	if err != nil {
@@ -217,6 +217,12 @@ func (c *EvalStatusCommand) Run(args []string) int {
 			fmt.Sprintf("Wait Until|%s", formatTime(eval.WaitUntil)))
 	}
 
+	if bump := eval.PriorityBump; bump != nil {
+		basic = append(basic,
+			fmt.Sprintf("Priority Bumped|from %d by %s at %s",
+				bump.PreviousPriority, bump.BumpedBy, formatUnixNanoTime(bump.BumpTime)))
+	}
+
 	if verbose {
 		// NextEval, PreviousEval, BlockedEval
 		basic = append(basic,
@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type EventSinkCommand struct {
+	Meta
+}
+
+func (c *EventSinkCommand) Name() string {
+	return "event sink"
+}
+
+func (c *EventSinkCommand) Synopsis() string {
+	return "Interact with event sinks"
+}
+
+func (c *EventSinkCommand) Help() string {
+	helpText := `
+Usage: nomad event sink <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with event sinks. Event
+  sinks are managed by the leader, which delivers the events matching their
+  topics to a webhook, a Kafka topic, or a NATS subject.
+
+  Register or update an event sink:
+
+    $ nomad event sink register <path>
+
+  List event sinks:
+
+    $ nomad event sink list
+
+  Deregister an event sink:
+
+    $ nomad event sink deregister <id>
+
+  Please refer to individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EventSinkCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func formatEventSinkList(sinks []*api.EventSinkListStub) string {
+	out := make([]string, len(sinks)+1)
+	out[0] = "ID|Type|Address|Topics|LatestIndex"
+	for i, s := range sinks {
+		out[i+1] = fmt.Sprintf("%s|%s|%s|%s|%d",
+			s.ID,
+			s.Type,
+			s.Address,
+			formatEventSinkTopics(s.Topics),
+			s.LatestIndex,
+		)
+	}
+	return formatList(out)
+}
+
+// formatEventSinkTopics formats the topics of an event sink as a sorted list
+// of topic[keys].
+func formatEventSinkTopics(topics map[api.Topic][]string) string {
+	out := make([]string, 0, len(topics))
+	for topic, keys := range topics {
+		out = append(out, fmt.Sprintf("%s[%s]", topic, strings.Join(keys, ",")))
+	}
+	sort.Strings(out)
+	return strings.Join(out, " ")
+}
+
+func eventSinkPredictor(factory ApiClientFactory) complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := factory()
+		if err != nil {
+			return nil
+		}
+
+		sinks, _, err := client.EventSinks().PrefixList(a.Last, nil)
+		if err != nil {
+			return nil
+		}
+
+		ids := make([]string, len(sinks))
+		for i, s := range sinks {
+			ids[i] = s.ID
+		}
+		return ids
+	})
+}
@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type EventSinkListCommand struct {
+	Meta
+}
+
+func (c *EventSinkListCommand) Name() string {
+	return "event sink list"
+}
+
+func (c *EventSinkListCommand) Synopsis() string {
+	return "List event sinks"
+}
+
+func (c *EventSinkListCommand) Help() string {
+	helpText := `
+Usage: nomad event sink list [options]
+
+  List is used to list the registered event sinks and the index of the last
+  event delivered to each of them.
+
+  If ACLs are enabled, this command requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+List Options:
+
+  -json
+    Output the event sinks in JSON format.
+
+  -page-token
+    Where to start pagination.
+
+  -per-page
+    How many results to show per page. If not specified, or set to 0, all
+    results are returned.
+
+  -prefix
+    Only list the event sinks whose ID starts with the given prefix.
+
+  -t
+    Format and display the event sinks using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EventSinkListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json":       complete.PredictNothing,
+			"-page-token": complete.PredictAnything,
+			"-per-page":   complete.PredictAnything,
+			"-prefix":     complete.PredictAnything,
+			"-t":          complete.PredictAnything,
+		})
+}
+
+func (c *EventSinkListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *EventSinkListCommand) Run(args []string) int {
+	var json bool
+	var perPage int
+	var tmpl, pageToken, prefix string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&pageToken, "page-token", "", "")
+	flags.IntVar(&perPage, "per-page", 0, "")
+	flags.StringVar(&prefix, "prefix", "", "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we don't have any arguments.
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	// Make list request.
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	opts := &api.QueryOptions{
+		Prefix:    prefix,
+		PerPage:   int32(perPage),
+		NextToken: pageToken,
+	}
+	sinks, qm, err := client.EventSinks().List(opts)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying event sinks: %s", err))
+		return 1
+	}
+
+	// Format output if requested.
+	if json || tmpl != "" {
+		out, err := Format(json, tmpl, sinks)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error formatting output: %s", err))
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if len(sinks) == 0 {
+		c.Ui.Output("No event sinks found")
+		return 0
+	}
+
+	c.Ui.Output(formatEventSinkList(sinks))
+
+	if qm.NextToken != "" {
+		c.Ui.Output(fmt.Sprintf(`
+Results have been paginated. To get the next page run:
+
+%s -page-token %s`, argsWithoutPageToken(os.Args), qm.NextToken))
+	}
+
+	return 0
+}
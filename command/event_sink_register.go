@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type EventSinkRegisterCommand struct {
+	Meta
+}
+
+func (c *EventSinkRegisterCommand) Name() string {
+	return "event sink register"
+}
+
+func (c *EventSinkRegisterCommand) Synopsis() string {
+	return "Register or update an event sink"
+}
+
+func (c *EventSinkRegisterCommand) Help() string {
+	helpText := `
+Usage: nomad event sink register [options] <path>
+
+  Register is used to register a new event sink or update an existing one. The
+  event sink is read from the JSON file at the given path. If the path is "-"
+  the event sink is read from stdin.
+
+  Updating an event sink keeps its delivery progress. New event sinks receive
+  the events that happen after they are registered.
+
+  If ACLs are enabled, this command requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault)
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *EventSinkRegisterCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *EventSinkRegisterCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*.json")
+}
+
+func (c *EventSinkRegisterCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we only have one argument.
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	path := args[0]
+
+	// Read the event sink.
+	var buf []byte
+	var err error
+	if path == "-" {
+		buf, err = io.ReadAll(os.Stdin)
+	} else {
+		buf, err = os.ReadFile(path)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading event sink: %s", err))
+		return 1
+	}
+
+	var sink api.EventSink
+	if err := json.Unmarshal(buf, &sink); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing event sink: %s", err))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if _, err := client.EventSinks().Register(&sink, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error registering event sink: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully registered %q event sink!", sink.ID))
+	return 0
+}
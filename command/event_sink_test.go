@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+const testEventSinkSource = `{
+  "ID": "my-sink",
+  "Type": "webhook",
+  "Address": "http://127.0.0.1:8080/events",
+  "Topics": {
+    "Job": ["*"],
+    "Node": ["*"]
+  }
+}`
+
+func TestEventSinkCommands_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &EventSinkCommand{}
+	var _ cli.Command = &EventSinkRegisterCommand{}
+	var _ cli.Command = &EventSinkListCommand{}
+	var _ cli.Command = &EventSinkDeregisterCommand{}
+}
+
+func TestEventSinkCommands_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start test server.
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	path := filepath.Join(t.TempDir(), "sink.json")
+	must.NoError(t, os.WriteFile(path, []byte(testEventSinkSource), 0o644))
+
+	// Register the sink.
+	ui := cli.NewMockUi()
+	registerCmd := &EventSinkRegisterCommand{Meta: Meta{Ui: ui}}
+	code := registerCmd.Run([]string{"-address", url, path})
+	must.Eq(t, 0, code, must.Sprint(ui.ErrorWriter.String()))
+	must.StrContains(t, ui.OutputWriter.String(), `Successfully registered "my-sink" event sink!`)
+
+	// List the sinks.
+	ui = cli.NewMockUi()
+	listCmd := &EventSinkListCommand{Meta: Meta{Ui: ui}}
+	code = listCmd.Run([]string{"-address", url})
+	must.Eq(t, 0, code)
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, "my-sink")
+	must.StrContains(t, out, "Job[*] Node[*]")
+
+	// Invalid sinks are rejected.
+	must.NoError(t, os.WriteFile(path, []byte(`{"ID": "bad", "Type": "smtp"}`), 0o644))
+	ui = cli.NewMockUi()
+	registerCmd = &EventSinkRegisterCommand{Meta: Meta{Ui: ui}}
+	code = registerCmd.Run([]string{"-address", url, path})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "invalid event sink")
+
+	// Deregister the sink.
+	ui = cli.NewMockUi()
+	deregisterCmd := &EventSinkDeregisterCommand{Meta: Meta{Ui: ui}}
+	code = deregisterCmd.Run([]string{"-address", url, "my-sink"})
+	must.Eq(t, 0, code)
+	must.StrContains(t, ui.OutputWriter.String(), `Successfully deregistered "my-sink" event sink!`)
+
+	ui = cli.NewMockUi()
+	listCmd = &EventSinkListCommand{Meta: Meta{Ui: ui}}
+	code = listCmd.Run([]string{"-address", url})
+	must.Eq(t, 0, code)
+	must.StrContains(t, ui.OutputWriter.String(), "No event sinks found")
+}
@@ -405,6 +405,9 @@ type JobGetter struct {
 	Strict   bool
 	JSON     bool
 
+	// StrictFields rejects unknown fields in JSON and HCL2 job files.
+	StrictFields bool
+
 	// The fields below can be overwritten for tests
 	testStdin io.Reader
 }
@@ -513,7 +516,11 @@ func (j *JobGetter) Get(jpath string) (*api.JobSubmission, *api.Job, error) {
 			api.Job
 		}{}
 
-		if err := json.NewDecoder(jobfile).Decode(&eitherJob); err != nil {
+		dec := json.NewDecoder(jobfile)
+		if j.StrictFields {
+			dec.DisallowUnknownFields()
+		}
+		if err := dec.Decode(&eitherJob); err != nil {
 			return nil, nil, fmt.Errorf("Failed to parse JSON job: %w", err)
 		}
 
@@ -542,13 +549,14 @@ func (j *JobGetter) Get(jpath string) (*api.JobSubmission, *api.Job, error) {
 
 		// we are parsing HCL2, whether from a file or stdio
 		jobStruct, err = jobspec2.ParseWithConfig(&jobspec2.ParseConfig{
-			Path:     pathName,
-			Body:     source.Bytes(),
-			ArgVars:  j.Vars,
-			AllowFS:  true,
-			VarFiles: j.VarFiles,
-			Envs:     osEnv,
-			Strict:   j.Strict,
+			Path:         pathName,
+			Body:         source.Bytes(),
+			ArgVars:      j.Vars,
+			AllowFS:      true,
+			VarFiles:     j.VarFiles,
+			Envs:         osEnv,
+			Strict:       j.Strict,
+			StrictFields: j.StrictFields,
 		})
 
 		var varFileCat string
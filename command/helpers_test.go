@@ -440,6 +440,30 @@ unsedVar2 = "from-varfile"
 	must.Eq(t, expected, j.Datacenters)
 }
 
+// TestJobGetter_JSON_StrictFields asserts unknown fields in JSON job files are
+// only rejected when StrictFields is set
+func TestJobGetter_JSON_StrictFields(t *testing.T) {
+	ci.Parallel(t)
+
+	fh, err := os.CreateTemp("", "nomad")
+	must.NoError(t, err)
+	defer os.Remove(fh.Name())
+	defer fh.Close()
+
+	_, err = fh.WriteString(`{"Job": {"ID": "example", "TaskGroup": [{"Name": "group1"}]}}`)
+	must.NoError(t, err)
+
+	jg := &JobGetter{JSON: true}
+	_, j, err := jg.Get(fh.Name())
+	must.NoError(t, err)
+	must.Eq(t, "example", *j.ID)
+	must.SliceEmpty(t, j.TaskGroups)
+
+	jg = &JobGetter{JSON: true, StrictFields: true}
+	_, _, err = jg.Get(fh.Name())
+	must.ErrorContains(t, err, `unknown field "TaskGroup"`)
+}
+
 // Test StructJob with jobfile from HTTP Server
 func TestJobGetter_HTTPServer(t *testing.T) {
 	ci.Parallel(t)
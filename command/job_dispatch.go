@@ -33,7 +33,8 @@ Usage: nomad job dispatch [options] <parameterized job> [input source]
 
   Upon successful creation, the dispatched job ID will be printed and the
   triggered evaluation will be monitored. This can be disabled by supplying the
-  detach flag.
+  detach flag. If the parameterized job is at its max_concurrent limit, the
+  dispatched job is queued instead and no evaluation is monitored.
 
   When ACLs are enabled, this command requires a token with the 'dispatch-job'
   capability for the job's namespace. The 'list-jobs' capability is required to
@@ -66,6 +67,11 @@ Dispatch Options:
   -id-prefix-template
     Optional prefix template for dispatched job IDs.
 
+  -priority
+    Override the priority of the dispatched job. Queued dispatched jobs with a
+    higher priority are run first. Defaults to the priority of the
+    parameterized job.
+
   -verbose
     Display full information.
 `
@@ -81,6 +87,7 @@ func (c *JobDispatchCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-meta":              complete.PredictAnything,
 			"-detach":            complete.PredictNothing,
+			"-priority":          complete.PredictNothing,
 			"-idempotency-token": complete.PredictAnything,
 			"-verbose":           complete.PredictNothing,
 		})
@@ -117,6 +124,7 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	var idempotencyToken string
 	var meta []string
 	var idPrefixTemplate string
+	var priority int
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
@@ -125,6 +133,7 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	flags.StringVar(&idempotencyToken, "idempotency-token", "", "")
 	flags.Var((*flaghelper.StringFlag)(&meta), "meta", "")
 	flags.StringVar(&idPrefixTemplate, "id-prefix-template", "", "")
+	flags.IntVar(&priority, "priority", 0, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -195,14 +204,20 @@ func (c *JobDispatchCommand) Run(args []string) int {
 		IdempotencyToken: idempotencyToken,
 		Namespace:        namespace,
 	}
-	resp, _, err := client.Jobs().Dispatch(jobID, metaMap, payload, idPrefixTemplate, w)
+	resp, _, err := client.Jobs().DispatchOpts(&api.JobDispatchRequest{
+		JobID:            jobID,
+		Meta:             metaMap,
+		Payload:          payload,
+		IdPrefixTemplate: idPrefixTemplate,
+		Priority:         priority,
+	}, w)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Failed to dispatch job: %s", err))
 		return 1
 	}
 
-	// See if an evaluation was created. If the job is periodic there will be no
-	// eval.
+	// See if an evaluation was created. If the job is periodic or queued there
+	// will be no eval.
 	evalCreated := resp.EvalID != ""
 
 	basic := []string{
@@ -211,6 +226,9 @@ func (c *JobDispatchCommand) Run(args []string) int {
 	if evalCreated {
 		basic = append(basic, fmt.Sprintf("Evaluation ID|%s", limit(resp.EvalID, length)))
 	}
+	if resp.Queued {
+		basic = append(basic, "Queued|true")
+	}
 	c.Ui.Output(formatKV(basic))
 
 	// Nothing to do
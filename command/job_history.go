@@ -40,7 +40,10 @@ General Options:
 History Options:
 
   -p
-    Display the difference between each job and its predecessor.
+    Display the difference between each job and its predecessor. Combined with
+    -json or -t, the machine readable summaries of the differences are output
+    instead of the job versions. Each summary lists the changed fields and the
+    categories of the changes, such as "image", "config", or "count".
 
   -full
     Display the full job definition for each version.
@@ -113,8 +116,8 @@ func (c *JobHistoryCommand) Run(args []string) int {
 		return 1
 	}
 
-	if (json || len(tmpl) != 0) && (diff || full) {
-		c.Ui.Error("-json and -t are exclusive with -p and -full")
+	if (json || len(tmpl) != 0) && full {
+		c.Ui.Error("-json and -t are exclusive with -full")
 		return 1
 	}
 
@@ -136,11 +139,22 @@ func (c *JobHistoryCommand) Run(args []string) int {
 	q := &api.QueryOptions{Namespace: namespace}
 
 	// Prefix lookup matched a single job
-	versions, diffs, _, err := client.Jobs().Versions(jobID, diff, q)
+	resp, _, err := client.Jobs().VersionsWithDiffs(jobID, q)
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error retrieving job versions: %s", err))
 		return 1
 	}
+	versions := resp.Versions
+	var diffs []*api.JobDiff
+	var summaries []*api.JobDiffSummary
+	if diff {
+		diffs = resp.Diffs
+
+		// Older servers don't return the summaries
+		if len(resp.DiffSummaries) == len(diffs) {
+			summaries = resp.DiffSummaries
+		}
+	}
 
 	f, err := DataFormat("json", "")
 	if err != nil {
@@ -157,7 +171,8 @@ func (c *JobHistoryCommand) Run(args []string) int {
 		}
 
 		var job *api.Job
-		var diff *api.JobDiff
+		var jobDiff *api.JobDiff
+		var summary *api.JobDiffSummary
 		var nextVersion uint64
 		for i, v := range versions {
 			if *v.Version != version {
@@ -166,13 +181,20 @@ func (c *JobHistoryCommand) Run(args []string) int {
 
 			job = v
 			if i+1 <= len(diffs) {
-				diff = diffs[i]
+				jobDiff = diffs[i]
 				nextVersion = *versions[i+1].Version
 			}
+			if i+1 <= len(summaries) {
+				summary = summaries[i]
+			}
 		}
 
 		if json || len(tmpl) > 0 {
-			out, err := Format(json, tmpl, job)
+			var data any = job
+			if diff {
+				data = summary
+			}
+			out, err := Format(json, tmpl, data)
 			if err != nil {
 				c.Ui.Error(err.Error())
 				return 1
@@ -182,14 +204,18 @@ func (c *JobHistoryCommand) Run(args []string) int {
 			return 0
 		}
 
-		if err := c.formatJobVersion(job, diff, nextVersion, full); err != nil {
+		if err := c.formatJobVersion(job, jobDiff, summary, nextVersion, full); err != nil {
 			c.Ui.Error(err.Error())
 			return 1
 		}
 
 	} else {
 		if json || len(tmpl) > 0 {
-			out, err := Format(json, tmpl, versions)
+			var data any = versions
+			if diff {
+				data = summaries
+			}
+			out, err := Format(json, tmpl, data)
 			if err != nil {
 				c.Ui.Error(err.Error())
 				return 1
@@ -199,7 +225,7 @@ func (c *JobHistoryCommand) Run(args []string) int {
 			return 0
 		}
 
-		if err := c.formatJobVersions(versions, diffs, full); err != nil {
+		if err := c.formatJobVersions(versions, diffs, summaries, full); err != nil {
 			c.Ui.Error(err.Error())
 			return 1
 		}
@@ -219,7 +245,7 @@ func parseVersion(input string) (uint64, bool, error) {
 	return u, true, err
 }
 
-func (c *JobHistoryCommand) formatJobVersions(versions []*api.Job, diffs []*api.JobDiff, full bool) error {
+func (c *JobHistoryCommand) formatJobVersions(versions []*api.Job, diffs []*api.JobDiff, summaries []*api.JobDiffSummary, full bool) error {
 	vLen := len(versions)
 	dLen := len(diffs)
 	if dLen != 0 && vLen != dLen+1 {
@@ -228,13 +254,17 @@ func (c *JobHistoryCommand) formatJobVersions(versions []*api.Job, diffs []*api.
 
 	for i, version := range versions {
 		var diff *api.JobDiff
+		var summary *api.JobDiffSummary
 		var nextVersion uint64
 		if i+1 <= dLen {
 			diff = diffs[i]
 			nextVersion = *versions[i+1].Version
 		}
+		if i+1 <= len(summaries) {
+			summary = summaries[i]
+		}
 
-		if err := c.formatJobVersion(version, diff, nextVersion, full); err != nil {
+		if err := c.formatJobVersion(version, diff, summary, nextVersion, full); err != nil {
 			return err
 		}
 
@@ -247,7 +277,7 @@ func (c *JobHistoryCommand) formatJobVersions(versions []*api.Job, diffs []*api.
 	return nil
 }
 
-func (c *JobHistoryCommand) formatJobVersion(job *api.Job, diff *api.JobDiff, nextVersion uint64, full bool) error {
+func (c *JobHistoryCommand) formatJobVersion(job *api.Job, diff *api.JobDiff, summary *api.JobDiffSummary, nextVersion uint64, full bool) error {
 	if job == nil {
 		return fmt.Errorf("Error printing job history for non-existing job or job version")
 	}
@@ -258,6 +288,10 @@ func (c *JobHistoryCommand) formatJobVersion(job *api.Job, diff *api.JobDiff, ne
 		fmt.Sprintf("Submit Date|%v", formatTime(time.Unix(0, *job.SubmitTime))),
 	}
 
+	if summary != nil && len(summary.Categories) > 0 {
+		basic = append(basic, fmt.Sprintf("Changes|%s", strings.Join(summary.Categories, ", ")))
+	}
+
 	if diff != nil {
 		//diffStr := fmt.Sprintf("Difference between version %d and %d:", *job.Version, nextVersion)
 		basic = append(basic, fmt.Sprintf("Diff|\n%s", strings.TrimSpace(formatJobDiff(diff, false))))
@@ -6,6 +6,7 @@ package command
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/hashicorp/nomad/api"
@@ -19,6 +20,14 @@ const (
 	DefaultInitName = "example.nomad.hcl"
 )
 
+// jobInitDriverTemplates are the built-in job templates for each task driver.
+var jobInitDriverTemplates = map[string][]byte{
+	"docker":   asset.JobDriverDocker,
+	"exec":     asset.JobDriverExec,
+	"java":     asset.JobDriverJava,
+	"raw_exec": asset.JobDriverRawExec,
+}
+
 // JobInitCommand generates a new job template that you can customize to your
 // liking, like vagrant init
 type JobInitCommand struct {
@@ -41,6 +50,12 @@ Init Options:
   -connect
     If the connect flag is set, the jobspec includes Consul Connect integration.
 
+  -driver=<driver>
+    Emits a jobspec for a service using the given task driver, with an update
+    block, health checks, and resources set so that it passes 'nomad job lint'.
+    Supported drivers are ` + jobInitDriversList() + `. This option cannot be
+    used with '-short', '-connect', or '-template'.
+
   -template
     Specifies a predefined template to initialize. Must be a Nomad Variable that
     lives at nomad/job-templates/<template>
@@ -59,7 +74,8 @@ func (c *JobInitCommand) Synopsis() string {
 func (c *JobInitCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-short": complete.PredictNothing,
+			"-short":  complete.PredictNothing,
+			"-driver": complete.PredictSet(jobInitDrivers()...),
 		})
 }
 
@@ -72,6 +88,7 @@ func (c *JobInitCommand) Name() string { return "job init" }
 func (c *JobInitCommand) Run(args []string) int {
 	var short bool
 	var connect bool
+	var driver string
 	var template string
 	var listTemplates bool
 
@@ -79,6 +96,7 @@ func (c *JobInitCommand) Run(args []string) int {
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.BoolVar(&short, "short", false, "")
 	flags.BoolVar(&connect, "connect", false, "")
+	flags.StringVar(&driver, "driver", "", "")
 	flags.StringVar(&template, "template", "", "The name of the job template variable to initialize")
 	flags.BoolVar(&listTemplates, "list-templates", false, "")
 
@@ -95,6 +113,18 @@ func (c *JobInitCommand) Run(args []string) int {
 		return 1
 	}
 
+	if driver != "" {
+		if _, ok := jobInitDriverTemplates[driver]; !ok {
+			c.Ui.Error(fmt.Sprintf("Unsupported -driver value %q: valid options are %s", driver, jobInitDriversList()))
+			return 1
+		}
+		if short || connect || template != "" || listTemplates {
+			c.Ui.Error("The -driver option cannot be used with -short, -connect, -template, or -list-templates")
+			c.Ui.Error(commandErrorText(c))
+			return 1
+		}
+	}
+
 	filename := DefaultInitName
 	if len(args) == 1 {
 		filename = args[0]
@@ -170,6 +200,8 @@ func (c *JobInitCommand) Run(args []string) int {
 			return 1
 		}
 
+	} else if driver != "" {
+		jobSpec = jobInitDriverTemplates[driver]
 	} else {
 		switch {
 		case connect && !short:
@@ -194,3 +226,24 @@ func (c *JobInitCommand) Run(args []string) int {
 	c.Ui.Output(fmt.Sprintf("Example job file written to %s", filename))
 	return 0
 }
+
+// jobInitDrivers returns the sorted names of the drivers with a built-in
+// template.
+func jobInitDrivers() []string {
+	drivers := make([]string, 0, len(jobInitDriverTemplates))
+	for driver := range jobInitDriverTemplates {
+		drivers = append(drivers, driver)
+	}
+	sort.Strings(drivers)
+	return drivers
+}
+
+// jobInitDriversList returns the drivers with a built-in template formatted
+// for help and error messages.
+func jobInitDriversList() string {
+	drivers := jobInitDrivers()
+	for i, driver := range drivers {
+		drivers[i] = fmt.Sprintf("%q", driver)
+	}
+	return strings.Join(drivers, ", ")
+}
@@ -6,6 +6,7 @@ package command
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -218,3 +219,38 @@ func TestInitCommand_customFilename(t *testing.T) {
 		t.Fatalf("expect file exists error, got: %s", out)
 	}
 }
+
+func TestInitCommand_driver(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	dir := t.TempDir()
+
+	cmd := &JobInitCommand{Meta: Meta{Ui: ui}}
+	must.One(t, cmd.Run([]string{"-driver=nope", filepath.Join(dir, "nope.nomad.hcl")}))
+	must.StrContains(t, ui.ErrorWriter.String(), "Unsupported -driver value")
+	ui.ErrorWriter.Reset()
+
+	must.One(t, cmd.Run([]string{"-driver=docker", "-short", filepath.Join(dir, "short.nomad.hcl")}))
+	must.StrContains(t, ui.ErrorWriter.String(), "cannot be used with")
+
+	for _, driver := range jobInitDrivers() {
+		t.Run(driver, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			filename := filepath.Join(dir, driver+".nomad.hcl")
+
+			cmd := &JobInitCommand{Meta: Meta{Ui: ui}}
+			must.Zero(t, cmd.Run([]string{"-driver=" + driver, filename}))
+
+			content, err := os.ReadFile(filename)
+			must.NoError(t, err)
+			must.Eq(t, string(jobInitDriverTemplates[driver]), string(content))
+			must.StrNotContains(t, string(content), "\t")
+
+			// The templates are examples of good practices so they must pass
+			// the lint rules.
+			lint := &JobLintCommand{Meta: Meta{Ui: ui}}
+			must.Zero(t, lint.Run([]string{filename}), must.Sprint(ui.OutputWriter.String()))
+			must.StrContains(t, ui.OutputWriter.String(), "No lint findings")
+		})
+	}
+}
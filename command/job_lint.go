@@ -0,0 +1,480 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/posener/complete"
+)
+
+// jobLintSeverity is the severity of a lint finding. It is a string so that
+// findings are output by name, since the JSON codec used by Format doesn't
+// use custom marshalers.
+type jobLintSeverity string
+
+const (
+	jobLintSeverityInfo    jobLintSeverity = "info"
+	jobLintSeverityWarning jobLintSeverity = "warning"
+	jobLintSeverityError   jobLintSeverity = "error"
+)
+
+var jobLintSeverityNames = []string{"info", "warning", "error"}
+
+func (s jobLintSeverity) String() string {
+	return string(s)
+}
+
+// rank orders severities. Higher values are more severe.
+func (s jobLintSeverity) rank() int {
+	return slices.Index(jobLintSeverityNames, string(s))
+}
+
+// parseJobLintSeverity parses the name of a severity level.
+func parseJobLintSeverity(s string) (jobLintSeverity, error) {
+	for _, name := range jobLintSeverityNames {
+		if strings.EqualFold(s, name) {
+			return jobLintSeverity(name), nil
+		}
+	}
+	return "", fmt.Errorf("valid options are %q, %q, and %q",
+		jobLintSeverityNames[0], jobLintSeverityNames[1], jobLintSeverityNames[2])
+}
+
+// jobLintFinding is a problem found in a job by a lint rule.
+type jobLintFinding struct {
+	Rule     string
+	Severity jobLintSeverity
+	Group    string `json:",omitempty"`
+	Task     string `json:",omitempty"`
+	Message  string
+}
+
+// location returns where the finding is in the job.
+func (f *jobLintFinding) location() string {
+	switch {
+	case f.Group != "" && f.Task != "":
+		return fmt.Sprintf("group %q task %q", f.Group, f.Task)
+	case f.Group != "":
+		return fmt.Sprintf("group %q", f.Group)
+	default:
+		return "job"
+	}
+}
+
+// jobLintRule is a static check of a job specification.
+type jobLintRule struct {
+	Name        string
+	Severity    jobLintSeverity
+	Description string
+
+	// check returns the findings of the rule for the job. The rule name and
+	// severity are set by the caller.
+	check func(job *api.Job) []*jobLintFinding
+}
+
+// jobLintRules are the rules run by job lint, in the order their findings
+// are reported.
+var jobLintRules = []*jobLintRule{
+	{
+		Name:        "missing-update",
+		Severity:    jobLintSeverityWarning,
+		Description: "Service job groups without an update block replace all their allocations at once when the job changes.",
+		check:       jobLintMissingUpdate,
+	},
+	{
+		Name:        "missing-health-checks",
+		Severity:    jobLintSeverityWarning,
+		Description: "Services without checks can't be used to verify the health of deployments and stay registered while failing.",
+		check:       jobLintMissingHealthChecks,
+	},
+	{
+		Name:        "missing-resources",
+		Severity:    jobLintSeverityWarning,
+		Description: "Tasks without resources get small defaults that are rarely what they need.",
+		check:       jobLintMissingResources,
+	},
+	{
+		Name:        "latest-image-tag",
+		Severity:    jobLintSeverityWarning,
+		Description: "Images without a tag or with the latest tag may run different code in each allocation.",
+		check:       jobLintLatestImageTag,
+	},
+}
+
+type JobLintCommand struct {
+	Meta
+	JobGetter
+}
+
+func (c *JobLintCommand) Help() string {
+	helpText := `
+Usage: nomad job lint [options] <path>
+
+  Checks a job file for configurations that are valid but likely to cause
+  problems at runtime, such as services without health checks, tasks without
+  resources, or images using the latest tag. Unlike 'nomad job validate', the
+  job file is checked locally and no Nomad agent is required.
+
+  If the supplied path is "-", the jobfile is read from stdin. Otherwise it is
+  read from the file at the supplied path or downloaded and read from URL
+  specified. Files with the .json extension are parsed as JSON.
+
+  The command exits with code 2 if there are findings with a severity of at
+  least the value of '-fail-on', and 1 if the job file can't be parsed.
+
+Lint Options:
+
+  -disable=<rule>
+    Do not run the given rule. Can be specified multiple times.
+
+  -fail-on=<info|warning|error>
+    Minimum severity of the findings that cause the command to fail. Defaults
+    to "warning".
+
+  -hcl1
+    Parses the job file as HCLv1.
+
+  -hcl2-strict
+    Whether an error should be produced from the HCL2 parser where a variable
+    has been supplied which is not defined within the root variables. Defaults
+    to true, but ignored if "-hcl1" is also defined.
+
+  -json
+    Output the findings in their JSON format.
+
+  -list-rules
+    List the lint rules and their severity instead of checking a job file.
+
+  -severity=<info|warning|error>
+    Minimum severity of the findings to report. Defaults to "info".
+
+  -t
+    Format and display the findings using a Go template.
+
+  -var 'key=value'
+    Variable for template, can be used multiple times.
+
+  -var-file=path
+    Path to HCL2 file containing user variables.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobLintCommand) Synopsis() string {
+	return "Check a job file for common configuration mistakes"
+}
+
+func (c *JobLintCommand) AutocompleteFlags() complete.Flags {
+	rules := make([]string, 0, len(jobLintRules))
+	for _, rule := range jobLintRules {
+		rules = append(rules, rule.Name)
+	}
+
+	return complete.Flags{
+		"-disable":     complete.PredictSet(rules...),
+		"-fail-on":     complete.PredictSet(jobLintSeverityNames...),
+		"-hcl1":        complete.PredictNothing,
+		"-hcl2-strict": complete.PredictNothing,
+		"-json":        complete.PredictNothing,
+		"-list-rules":  complete.PredictNothing,
+		"-severity":    complete.PredictSet(jobLintSeverityNames...),
+		"-t":           complete.PredictAnything,
+		"-var":         complete.PredictAnything,
+		"-var-file":    complete.PredictFiles("*.var"),
+	}
+}
+
+func (c *JobLintCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(
+		complete.PredictFiles("*.nomad"),
+		complete.PredictFiles("*.hcl"),
+		complete.PredictFiles("*.json"),
+	)
+}
+
+func (c *JobLintCommand) Name() string { return "job lint" }
+
+func (c *JobLintCommand) Run(args []string) int {
+	var json, listRules bool
+	var tmpl, severityStr, failOnStr string
+	var disabled flaghelper.StringFlag
+
+	flagSet := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flagSet.Usage = func() { c.Ui.Output(c.Help()) }
+	flagSet.Var(&disabled, "disable", "")
+	flagSet.StringVar(&failOnStr, "fail-on", jobLintSeverityWarning.String(), "")
+	flagSet.BoolVar(&c.JobGetter.HCL1, "hcl1", false, "")
+	flagSet.BoolVar(&c.JobGetter.Strict, "hcl2-strict", true, "")
+	flagSet.BoolVar(&json, "json", false, "")
+	flagSet.BoolVar(&listRules, "list-rules", false, "")
+	flagSet.StringVar(&severityStr, "severity", jobLintSeverityInfo.String(), "")
+	flagSet.StringVar(&tmpl, "t", "", "")
+	flagSet.Var(&c.JobGetter.Vars, "var", "")
+	flagSet.Var(&c.JobGetter.VarFiles, "var-file", "")
+
+	if err := flagSet.Parse(args); err != nil {
+		return 1
+	}
+
+	if listRules {
+		c.listRules()
+		return 0
+	}
+
+	// Check that we got exactly one job file
+	args = flagSet.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	severity, err := parseJobLintSeverity(severityStr)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid -severity value %q: %s", severityStr, err))
+		return 1
+	}
+	failOn, err := parseJobLintSeverity(failOnStr)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid -fail-on value %q: %s", failOnStr, err))
+		return 1
+	}
+
+	enabled := make([]*jobLintRule, 0, len(jobLintRules))
+	skip := make(map[string]bool, len(disabled))
+	for _, name := range disabled {
+		skip[name] = true
+	}
+	for _, rule := range jobLintRules {
+		if skip[rule.Name] {
+			delete(skip, rule.Name)
+			continue
+		}
+		enabled = append(enabled, rule)
+	}
+	for name := range skip {
+		c.Ui.Error(fmt.Sprintf("Invalid -disable value %q: unknown rule", name))
+		return 1
+	}
+
+	if c.JobGetter.HCL1 {
+		c.JobGetter.Strict = false
+	}
+	c.JobGetter.JSON = strings.EqualFold(filepath.Ext(args[0]), ".json")
+	if err := c.JobGetter.Validate(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid job options: %s", err))
+		return 1
+	}
+
+	_, job, err := c.JobGetter.Get(args[0])
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
+		return 1
+	}
+
+	findings := make([]*jobLintFinding, 0)
+	for _, finding := range jobLint(job, enabled) {
+		if finding.Severity.rank() >= severity.rank() {
+			findings = append(findings, finding)
+		}
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, findings)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+	} else {
+		c.outputFindings(findings)
+	}
+
+	for _, finding := range findings {
+		if finding.Severity.rank() >= failOn.rank() {
+			return 2
+		}
+	}
+	return 0
+}
+
+// outputFindings prints the findings in a table.
+func (c *JobLintCommand) outputFindings(findings []*jobLintFinding) {
+	if len(findings) == 0 {
+		c.Ui.Output(c.Colorize().Color("[bold][green]No lint findings[reset]"))
+		return
+	}
+
+	out := make([]string, len(findings)+1)
+	out[0] = "Severity|Rule|Location|Message"
+	for i, finding := range findings {
+		out[i+1] = fmt.Sprintf("%s|%s|%s|%s",
+			finding.Severity,
+			finding.Rule,
+			finding.location(),
+			finding.Message,
+		)
+	}
+	c.Ui.Output(formatList(out))
+}
+
+// listRules prints the available lint rules.
+func (c *JobLintCommand) listRules() {
+	out := make([]string, len(jobLintRules)+1)
+	out[0] = "Rule|Severity|Description"
+	for i, rule := range jobLintRules {
+		out[i+1] = fmt.Sprintf("%s|%s|%s", rule.Name, rule.Severity, rule.Description)
+	}
+	c.Ui.Output(formatList(out))
+}
+
+// jobLint runs the rules against the job and returns their findings.
+func jobLint(job *api.Job, rules []*jobLintRule) []*jobLintFinding {
+	var findings []*jobLintFinding
+	for _, rule := range rules {
+		for _, finding := range rule.check(job) {
+			finding.Rule = rule.Name
+			finding.Severity = rule.Severity
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// jobLintGroupName returns the name of the group, which may be missing from
+// JSON job files.
+func jobLintGroupName(tg *api.TaskGroup) string {
+	if tg.Name == nil {
+		return ""
+	}
+	return *tg.Name
+}
+
+// jobLintType returns the type of the job, which defaults to service.
+func jobLintType(job *api.Job) string {
+	if job.Type == nil || *job.Type == "" {
+		return api.JobTypeService
+	}
+	return *job.Type
+}
+
+func jobLintMissingUpdate(job *api.Job) []*jobLintFinding {
+	if jobLintType(job) != api.JobTypeService || job.Update != nil {
+		return nil
+	}
+
+	var findings []*jobLintFinding
+	for _, tg := range job.TaskGroups {
+		if tg.Update != nil {
+			continue
+		}
+		findings = append(findings, &jobLintFinding{
+			Group:   jobLintGroupName(tg),
+			Message: "No update block, so changes to the group replace all its allocations at once",
+		})
+	}
+	return findings
+}
+
+func jobLintMissingHealthChecks(job *api.Job) []*jobLintFinding {
+	switch jobLintType(job) {
+	case api.JobTypeService, api.JobTypeSystem:
+	default:
+		return nil
+	}
+
+	var findings []*jobLintFinding
+	check := func(group, task string, services []*api.Service) {
+		for _, service := range services {
+			if len(service.Checks) > 0 {
+				continue
+			}
+			findings = append(findings, &jobLintFinding{
+				Group:   group,
+				Task:    task,
+				Message: fmt.Sprintf("Service %q has no health checks", service.Name),
+			})
+		}
+	}
+	for _, tg := range job.TaskGroups {
+		check(jobLintGroupName(tg), "", tg.Services)
+		for _, task := range tg.Tasks {
+			check(jobLintGroupName(tg), task.Name, task.Services)
+		}
+	}
+	return findings
+}
+
+func jobLintMissingResources(job *api.Job) []*jobLintFinding {
+	var findings []*jobLintFinding
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			r := task.Resources
+			if r != nil && (r.CPU != nil || r.Cores != nil) && r.MemoryMB != nil {
+				continue
+			}
+
+			var missing string
+			switch {
+			case r == nil || (r.CPU == nil && r.Cores == nil && r.MemoryMB == nil):
+				missing = "No resources set"
+			case r.MemoryMB == nil:
+				missing = "No memory set"
+			default:
+				missing = "No cpu or cores set"
+			}
+			findings = append(findings, &jobLintFinding{
+				Group:   jobLintGroupName(tg),
+				Task:    task.Name,
+				Message: missing + ", so the task uses the default values",
+			})
+		}
+	}
+	return findings
+}
+
+func jobLintLatestImageTag(job *api.Job) []*jobLintFinding {
+	var findings []*jobLintFinding
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			switch task.Driver {
+			case "docker", "podman":
+			default:
+				continue
+			}
+
+			image, ok := task.Config["image"].(string)
+			if !ok || !jobLintImageIsLatest(image) {
+				continue
+			}
+			findings = append(findings, &jobLintFinding{
+				Group:   jobLintGroupName(tg),
+				Task:    task.Name,
+				Message: fmt.Sprintf("Image %q is not pinned to a version", image),
+			})
+		}
+	}
+	return findings
+}
+
+// jobLintImageIsLatest returns true if the image reference has no tag or uses
+// the latest tag. Images pinned by digest or interpolated at runtime are not
+// considered latest.
+func jobLintImageIsLatest(image string) bool {
+	if image == "" || strings.Contains(image, "@") || strings.Contains(image, "${") {
+		return false
+	}
+
+	// The tag follows the last colon, unless the colon is the port of the
+	// registry.
+	name := image[strings.LastIndex(image, "/")+1:]
+	i := strings.LastIndex(name, ":")
+	return i == -1 || name[i+1:] == "latest"
+}
@@ -0,0 +1,132 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobLintCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobLintCommand{}
+}
+
+const jobLintTestJob = `
+job "example" {
+  group "web" {
+    service {
+      name     = "web"
+      provider = "nomad"
+    }
+
+    task "server" {
+      driver = "docker"
+
+      config {
+        image = "nginx"
+      }
+    }
+
+    task "sidecar" {
+      driver = "docker"
+
+      config {
+        image = "registry.example.com:5000/envoy:1.28"
+      }
+
+      resources {
+        cpu = 100
+      }
+    }
+  }
+}
+`
+
+func TestJobLintCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	path := filepath.Join(t.TempDir(), "example.nomad.hcl")
+	must.NoError(t, os.WriteFile(path, []byte(jobLintTestJob), 0o600))
+
+	ui := cli.NewMockUi()
+	cmd := &JobLintCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	must.One(t, cmd.Run([]string{}))
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	must.One(t, cmd.Run([]string{"-severity=bad", path}))
+	must.StrContains(t, ui.ErrorWriter.String(), "Invalid -severity value")
+	ui.ErrorWriter.Reset()
+
+	must.One(t, cmd.Run([]string{"-disable=bad", path}))
+	must.StrContains(t, ui.ErrorWriter.String(), "unknown rule")
+	ui.ErrorWriter.Reset()
+
+	// Findings at or above -fail-on make the command fail
+	must.Eq(t, 2, cmd.Run([]string{path}))
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, "missing-update")
+	must.StrContains(t, out, `Service "web" has no health checks`)
+	must.StrContains(t, out, "No resources set")
+	must.StrContains(t, out, "No memory set")
+	must.StrContains(t, out, `Image "nginx" is not pinned to a version`)
+	must.StrNotContains(t, out, "envoy")
+	ui.OutputWriter.Reset()
+
+	must.Zero(t, cmd.Run([]string{"-fail-on=error", path}))
+	ui.OutputWriter.Reset()
+
+	// Findings can be filtered by rule and severity
+	must.Zero(t, cmd.Run([]string{"-severity=error", path}))
+	must.StrContains(t, ui.OutputWriter.String(), "No lint findings")
+	ui.OutputWriter.Reset()
+
+	must.Zero(t, cmd.Run([]string{
+		"-disable=missing-update",
+		"-disable=missing-health-checks",
+		"-disable=missing-resources",
+		"-disable=latest-image-tag",
+		path,
+	}))
+	must.StrContains(t, ui.OutputWriter.String(), "No lint findings")
+	ui.OutputWriter.Reset()
+
+	// JSON output
+	must.Eq(t, 2, cmd.Run([]string{"-json", "-disable=missing-resources", path}))
+	var findings []map[string]string
+	must.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &findings))
+	must.Eq(t, []map[string]string{
+		{"Rule": "missing-update", "Severity": "warning", "Group": "web", "Message": "No update block, so changes to the group replace all its allocations at once"},
+		{"Rule": "missing-health-checks", "Severity": "warning", "Group": "web", "Message": `Service "web" has no health checks`},
+		{"Rule": "latest-image-tag", "Severity": "warning", "Group": "web", "Task": "server", "Message": `Image "nginx" is not pinned to a version`},
+	}, findings)
+}
+
+func TestJobLintCommand_ImageIsLatest(t *testing.T) {
+	ci.Parallel(t)
+
+	for image, latest := range map[string]bool{
+		"nginx":                                true,
+		"nginx:latest":                         true,
+		"nginx:1.25":                           false,
+		"registry.example.com:5000/nginx":      true,
+		"registry.example.com:5000/nginx:1.25": false,
+		"nginx@sha256:0123456789abcdef":        false,
+		"nginx:${NOMAD_META_version}":          false,
+		"docker.io/library/nginx:latest":       true,
+		"docker.io/library/nginx:1.25-alpine":  false,
+		"":                                     false,
+	} {
+		must.Eq(t, latest, jobLintImageIsLatest(image), must.Sprint(image))
+	}
+}
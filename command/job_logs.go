@@ -0,0 +1,197 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/posener/complete"
+)
+
+type JobLogsCommand struct {
+	Meta
+}
+
+func (c *JobLogsCommand) Help() string {
+	helpText := `
+Usage: nomad job logs [options] <job>
+
+  Stream the stdout and stderr of the tasks of every allocation of a job. Each
+  line is prefixed with the allocation and task it was written by. When
+  following, allocations placed after the command started, such as those
+  replacing failed or updated allocations, are streamed as well.
+
+  When ACLs are enabled, this command requires a token with the 'read-job' and
+  'read-logs' capabilities for the job's namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Logs Options:
+
+  -f
+    Causes the output to not stop when the end of the logs are reached, but
+    rather to wait for additional output from existing and new allocations.
+
+  -task <task>
+    Only stream the logs of tasks with the given name.
+
+  -stdout
+    Only stream the stdout of tasks.
+
+  -stderr
+    Only stream the stderr of tasks.
+
+  -tail
+    Start each task's log stream at the end of its logs rather than at the
+    beginning, skipping the existing output.
+
+  -json
+    Output each log line as a JSON object.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobLogsCommand) Synopsis() string {
+	return "Stream the logs of all allocations of a job"
+}
+
+func (c *JobLogsCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-f":      complete.PredictNothing,
+			"-task":   complete.PredictAnything,
+			"-stdout": complete.PredictNothing,
+			"-stderr": complete.PredictNothing,
+			"-tail":   complete.PredictNothing,
+			"-json":   complete.PredictNothing,
+		})
+}
+
+func (c *JobLogsCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Jobs]
+	})
+}
+
+func (c *JobLogsCommand) Name() string { return "job logs" }
+
+func (c *JobLogsCommand) Run(args []string) int {
+	var follow, stdout, stderr, tail, jsonOutput bool
+	var task string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&follow, "f", false, "")
+	flags.StringVar(&task, "task", "", "")
+	flags.BoolVar(&stdout, "stdout", false, "")
+	flags.BoolVar(&stderr, "stderr", false, "")
+	flags.BoolVar(&tail, "tail", false, "")
+	flags.BoolVar(&jsonOutput, "json", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got exactly one job
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <job>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if stdout && stderr {
+		c.Ui.Error("The -stdout and -stderr flags are mutually exclusive")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	opts := &api.JobLogsOptions{
+		Follow: follow,
+		Task:   task,
+		Origin: api.OriginStart,
+	}
+	if stdout {
+		opts.LogType = api.FSLogNameStdout
+	}
+	if stderr {
+		opts.LogType = api.FSLogNameStderr
+	}
+	if tail {
+		opts.Origin = api.OriginEnd
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Check if the job exists
+	jobIDPrefix := strings.TrimSpace(args[0])
+	jobID, namespace, err := c.JobIDByPrefix(client, jobIDPrefix, nil)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	cancel := make(chan struct{})
+	defer close(cancel)
+
+	q := &api.QueryOptions{Namespace: namespace}
+	lines, errCh := client.Jobs().Logs(jobID, opts, cancel, q)
+
+	// Trap user signals, so we know when to exit and cancel the log stream
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+
+	for {
+		select {
+		case <-signalCh:
+			return 0
+		case err := <-errCh:
+			c.Ui.Error(fmt.Sprintf("Error streaming job logs: %s", err))
+			return 1
+		case line, ok := <-lines:
+			if !ok {
+				return 0
+			}
+			if jsonOutput {
+				out, err := json.Marshal(line)
+				if err != nil {
+					c.Ui.Error(fmt.Sprintf("Error formatting log line: %s", err))
+					return 1
+				}
+				c.Ui.Output(string(out))
+				continue
+			}
+
+			out := fmt.Sprintf("%s/%s %s", limit(line.AllocID, shortId), line.Task, line.Line)
+			if line.Stream == api.FSLogNameStderr {
+				c.Ui.Warn(out)
+			} else {
+				c.Ui.Output(out)
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobLogsCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobLogsCommand{}
+}
+
+func TestJobLogsCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	srv, _, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &JobLogsCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+
+	ui.ErrorWriter.Reset()
+
+	// Fails on conflicting streams
+	code = cmd.Run([]string{"-stdout", "-stderr", "foo"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "mutually exclusive")
+
+	ui.ErrorWriter.Reset()
+
+	// Bad job name
+	code = cmd.Run([]string{"-address=" + url, "foo"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "No job(s) with prefix or ID \"foo\" found")
+}
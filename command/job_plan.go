@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/scheduler"
 	"github.com/posener/complete"
@@ -97,9 +99,26 @@ Plan Options:
     has been supplied which is not defined within the root variables. Defaults
     to true, but ignored if "-hcl1" is also defined.
 
+  -strict
+    Whether an error should be produced for unknown fields in JSON and HCL2
+    job files, including those the HCL2 parser would otherwise ignore. Defaults
+    to false.
+
   -policy-override
     Sets the flag to force override any soft mandatory Sentinel policies.
 
+  -add-nodes <class>:<count>
+    Plans the job as if <count> nodes of node class <class> were added to the
+    cluster. The hypothetical nodes are copies of an existing node of the class
+    and are never added to the cluster. Can be specified multiple times. When
+    ACLs are enabled, this option requires a token with the 'node:read'
+    capability.
+
+  -drain-node <node-id>
+    Plans the job as if the node with the given ID or ID prefix was drained.
+    The node is never drained. Can be specified multiple times. When ACLs are
+    enabled, this option requires a token with the 'node:read' capability.
+
   -vault-token
     Used to validate if the user submitting the job has permission to run the job
     according to its Vault policies. A Vault token must be supplied if the vault
@@ -137,10 +156,13 @@ func (c *JobPlanCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-diff":            complete.PredictNothing,
 			"-policy-override": complete.PredictNothing,
+			"-add-nodes":       complete.PredictAnything,
+			"-drain-node":      complete.PredictAnything,
 			"-verbose":         complete.PredictNothing,
 			"-json":            complete.PredictNothing,
 			"-hcl1":            complete.PredictNothing,
 			"-hcl2-strict":     complete.PredictNothing,
+			"-strict":          complete.PredictNothing,
 			"-vault-token":     complete.PredictAnything,
 			"-vault-namespace": complete.PredictAnything,
 			"-var":             complete.PredictAnything,
@@ -160,15 +182,19 @@ func (c *JobPlanCommand) Name() string { return "job plan" }
 func (c *JobPlanCommand) Run(args []string) int {
 	var diff, policyOverride, verbose bool
 	var vaultToken, vaultNamespace string
+	var addNodes, drainNodes flaghelper.StringFlag
 
 	flagSet := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flagSet.Usage = func() { c.Ui.Output(c.Help()) }
 	flagSet.BoolVar(&diff, "diff", true, "")
 	flagSet.BoolVar(&policyOverride, "policy-override", false, "")
+	flagSet.Var(&addNodes, "add-nodes", "")
+	flagSet.Var(&drainNodes, "drain-node", "")
 	flagSet.BoolVar(&verbose, "verbose", false, "")
 	flagSet.BoolVar(&c.JobGetter.JSON, "json", false, "")
 	flagSet.BoolVar(&c.JobGetter.HCL1, "hcl1", false, "")
 	flagSet.BoolVar(&c.JobGetter.Strict, "hcl2-strict", true, "")
+	flagSet.BoolVar(&c.JobGetter.StrictFields, "strict", false, "")
 	flagSet.StringVar(&vaultToken, "vault-token", "", "")
 	flagSet.StringVar(&vaultNamespace, "vault-namespace", "", "")
 	flagSet.Var(&c.JobGetter.Vars, "var", "")
@@ -240,6 +266,13 @@ func (c *JobPlanCommand) Run(args []string) int {
 		job.VaultNamespace = pointer.Of(vaultNamespace)
 	}
 
+	// Seal the sealed values of the job's tasks, so the plan compares them
+	// with the sealed values of the registered job
+	if _, err := sealJob(client, job); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error sealing job: %s", err))
+		return 255
+	}
+
 	// Setup the options
 	opts := &api.PlanOptions{
 		// Always request the diff so we can tell if there are changes.
@@ -248,6 +281,17 @@ func (c *JobPlanCommand) Run(args []string) int {
 	if policyOverride {
 		opts.PolicyOverride = true
 	}
+	if len(addNodes) > 0 || len(drainNodes) > 0 {
+		opts.WhatIf = &api.JobPlanWhatIf{DrainNodes: drainNodes}
+		for _, arg := range addNodes {
+			nodes, err := parseWhatIfAddNodes(arg)
+			if err != nil {
+				c.Ui.Error(fmt.Sprintf("Invalid -add-nodes value %q: %s", arg, err))
+				return 255
+			}
+			opts.WhatIf.AddNodes = append(opts.WhatIf.AddNodes, nodes)
+		}
+	}
 
 	if job.IsMultiregion() {
 		return c.multiregionPlan(client, job, opts, diff, verbose)
@@ -324,6 +368,13 @@ func (c *JobPlanCommand) outputPlannedJob(job *api.Job, resp *api.JobPlanRespons
 	c.Ui.Output(c.Colorize().Color(formatDryRun(resp, job)))
 	c.Ui.Output("")
 
+	// Print the placements on hypothetical nodes if there are any
+	if len(resp.WhatIfPlacements) > 0 {
+		c.Ui.Output(c.Colorize().Color("[bold]What-if nodes:[reset]"))
+		c.Ui.Output(formatWhatIfPlacements(resp.WhatIfPlacements))
+		c.Ui.Output("")
+	}
+
 	// Print any warnings if there are any
 	if resp.Warnings != "" {
 		c.Ui.Output(
@@ -335,6 +386,13 @@ func (c *JobPlanCommand) outputPlannedJob(job *api.Job, resp *api.JobPlanRespons
 		c.addPreemptions(resp)
 	}
 
+	// Print the number of clients prefetching the job's images and artifacts
+	if resp.Annotations != nil && len(resp.Annotations.PrefetchNodes) > 0 {
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+			"[bold]Prefetch:[reset] %d node(s) will pull the images and artifacts of the job\n",
+			len(resp.Annotations.PrefetchNodes))))
+	}
+
 	return getExitCode(resp)
 }
 
@@ -398,6 +456,41 @@ type namespaceIdPair struct {
 	namespace string
 }
 
+// parseWhatIfAddNodes parses the <class>:<count> value of the -add-nodes flag.
+func parseWhatIfAddNodes(arg string) (*api.JobPlanWhatIfNodes, error) {
+	i := strings.LastIndex(arg, ":")
+	if i < 1 {
+		return nil, fmt.Errorf("must be in the format <class>:<count>")
+	}
+
+	count, err := strconv.Atoi(arg[i+1:])
+	if err != nil || count < 1 {
+		return nil, fmt.Errorf("count must be a positive integer")
+	}
+
+	return &api.JobPlanWhatIfNodes{
+		NodeClass: arg[:i],
+		Count:     count,
+	}, nil
+}
+
+// formatWhatIfPlacements lists the number of allocations placed on each
+// hypothetical node.
+func formatWhatIfPlacements(placements map[string]int) string {
+	names := make([]string, 0, len(placements))
+	for name := range placements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([]string, 0, len(names)+1)
+	rows = append(rows, "Node Name|Placements")
+	for _, name := range names {
+		rows = append(rows, fmt.Sprintf("%s|%d", name, placements[name]))
+	}
+	return formatList(rows)
+}
+
 // getExitCode returns 0:
 // * 0: No allocations created or destroyed.
 // * 1: Allocations created or destroyed.
@@ -619,7 +712,11 @@ func formatTaskDiff(task *api.TaskDiff, startPrefix, taskPrefix int, verbose boo
 func formatObjectDiff(diff *api.ObjectDiff, startPrefix, keyPrefix int) string {
 	start := strings.Repeat(" ", startPrefix)
 	marker, markerLen := getDiffString(diff.Type)
-	out := fmt.Sprintf("%s%s%s%s {\n", start, marker, strings.Repeat(" ", keyPrefix), diff.Name)
+	out := fmt.Sprintf("%s%s%s%s {", start, marker, strings.Repeat(" ", keyPrefix), diff.Name)
+	if len(diff.Annotations) != 0 {
+		out += fmt.Sprintf(" (%s)", colorAnnotations(diff.Annotations))
+	}
+	out += "\n"
 
 	// Determine the length of the longest name and longest diff marker to
 	// properly align names and values
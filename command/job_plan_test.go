@@ -345,3 +345,16 @@ func TestPlanCommand_JSON(t *testing.T) {
 	must.Eq(t, 255, code)
 	must.StrContains(t, ui.ErrorWriter.String(), "Error during plan: Put")
 }
+
+func TestPlanCommand_parseWhatIfAddNodes(t *testing.T) {
+	ci.Parallel(t)
+
+	nodes, err := parseWhatIfAddNodes("c5.4xlarge:2")
+	must.NoError(t, err)
+	must.Eq(t, &api.JobPlanWhatIfNodes{NodeClass: "c5.4xlarge", Count: 2}, nodes)
+
+	for _, arg := range []string{"c5.4xlarge", ":2", "c5.4xlarge:0", "c5.4xlarge:two"} {
+		_, err := parseWhatIfAddNodes(arg)
+		must.Error(t, err, must.Sprintf("expected %q to be invalid", arg))
+	}
+}
@@ -5,14 +5,18 @@ package command
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"math"
 	"os"
 	"os/signal"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	humanize "github.com/dustin/go-humanize"
@@ -21,6 +25,7 @@ import (
 	"github.com/hashicorp/go-set/v2"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/posener/complete"
 )
 
@@ -41,6 +46,26 @@ const (
 	// jobRestartOnErrorAks is the special token used to indicate that the
 	// command should ask user for confirmation when a batch has errors.
 	jobRestartOnErrorAsk = "ask"
+
+	// jobRestartCheckpointPathFmt is the path of the variable where the
+	// progress of a job restart is saved.
+	jobRestartCheckpointPathFmt = "nomad/jobs/%s/restart-checkpoint"
+
+	// jobRestartStatusRunning and jobRestartStatusPaused are the statuses of
+	// a restart checkpoint.
+	jobRestartStatusRunning = "running"
+	jobRestartStatusPaused  = "paused"
+
+	// The health_check values of an update block.
+	jobRestartHealthCheckChecks     = "checks"
+	jobRestartHealthCheckTaskStates = "task_states"
+	jobRestartHealthCheckManual     = "manual"
+
+	// The task states and check status used to determine if an allocation
+	// is healthy.
+	jobRestartTaskStateRunning = "running"
+	jobRestartTaskStateDead    = "dead"
+	jobRestartCheckSuccess     = "success"
 )
 
 var (
@@ -50,6 +75,14 @@ var (
 	// Use ^...$ to make sure we're matching over the entire input to avoid
 	// partial matches such as 10%20%.
 	jobRestartBatchSizeValueRegex = regexp.MustCompile(`^(\d+)%?$`)
+
+	// jobRestartVariablePathRegex validates that the restart checkpoint
+	// path of a job is a valid variable path.
+	jobRestartVariablePathRegex = regexp.MustCompile(`^[a-zA-Z0-9-_~/]{1,128}$`)
+
+	// jobRestartHealthPollInterval is how often the health of restarted
+	// allocations is checked.
+	jobRestartHealthPollInterval = time.Second
 )
 
 // ErrJobRestartPlacementFailure is an error that indicates a placement failure
@@ -87,19 +120,32 @@ type JobRestartCommand struct {
 	batchSizePercent bool
 	batchWait        time.Duration
 	batchWaitAsk     bool
+	exclude          []string
 	groups           *set.Set[string]
 	jobID            string
 	noShutdownDelay  bool
 	onError          string
+	pause            bool
 	reschedule       bool
+	resume           bool
 	tasks            *set.Set[string]
 	verbose          bool
+	waitHealthy      bool
 	length           int
 
 	// canceled is set to true when the user gives a negative answer to any of
 	// the questions.
 	canceled bool
 
+	// paused is set to true when the restart is paused by another invocation
+	// of the command.
+	paused bool
+
+	// checkpoint is the progress of the restart, saved after each batch so
+	// the restart can be resumed if it is paused or interrupted. It is nil if
+	// the progress can't be saved.
+	checkpoint *jobRestartCheckpoint
+
 	// sigsCh is used to subscribe to signals from the operating system.
 	sigsCh chan os.Signal
 }
@@ -135,15 +181,25 @@ Usage: nomad job restart [options] <job>
   scheduler to create replacement allocations that may be placed in different
   clients. The command waits until the new allocations have client status
   'ready' before proceeding with the remaining batches. Services health checks
-  are not taken into account.
+  are not taken into account unless '-wait-healthy' is set.
 
   By default the command restarts all running tasks in-place with one
   allocation per batch.
 
+  The progress of the restart is saved after each batch in the variable
+  'nomad/jobs/<job>/restart-checkpoint'. If the command is interrupted or the
+  restart is paused with '-pause', it can be continued later with '-resume',
+  skipping the allocations that were already restarted. The progress is not
+  saved in the job itself since modifying the job would create a new version
+  and replace its allocations. The checkpoint is deleted once the restart
+  finishes.
+
   When ACLs are enabled, this command requires a token with the
   'alloc-lifecycle' and 'read-job' capabilities for the job's namespace. The
   'list-jobs' capability is required to run the command with a job prefix
-  instead of the exact job ID.
+  instead of the exact job ID. Saving the progress of the restart requires
+  the 'read' and 'write' variable capabilities for the checkpoint path; the
+  restart proceeds without them but can't be resumed.
 
 General Options:
 
@@ -167,6 +223,10 @@ Restart Options:
     is a time duration all remaining batches will use this new value. Defaults
     to 0.
 
+  -exclude=<alloc-id>
+    Do not restart the given allocation. The value may be a prefix of the
+    allocation ID. Can be specified multiple times.
+
   -group=<group-name>
     Only restart allocations for the given group. Can be specified multiple
     times. If no group is set all allocations for the job are restarted.
@@ -182,6 +242,12 @@ Restart Options:
     batch. If 'ask' the command stops and waits for user confirmation on how to
     proceed. If 'fail' the command exits immediately. Defaults to 'ask'.
 
+  -pause
+    Pause the restart of the job that is in progress. The command running the
+    restart stops once its current batch completes, and the restart can be
+    continued with '-resume'. This option cannot be used with other restart
+    options.
+
   -reschedule
     If set, allocations are stopped and rescheduled instead of restarted
     in-place. Since the group is not modified the restart does not create a new
@@ -190,6 +256,12 @@ Restart Options:
     '-task'. Only jobs of type 'batch', 'service', and 'system' can be
     rescheduled.
 
+  -resume
+    Resume a paused or interrupted restart of the job. The restart continues
+    with the options it was started with and skips the allocations already
+    restarted, so only '-batch-wait', '-on-error', '-yes', and '-verbose' may
+    be set.
+
   -task=<task-name>
     Specify the task to restart. Can be specified multiple times. If groups are
     also specified the task must exist in at least one of them. If no task is
@@ -198,6 +270,16 @@ Restart Options:
     used instead. This option cannot be used with '-all-tasks' or
     '-reschedule'.
 
+  -wait-healthy
+    Wait for the restarted or rescheduled allocations to be healthy before
+    proceeding with the next batch, as defined by the 'update' block of their
+    group. Allocations are healthy when their tasks have been running for
+    'min_healthy_time' and, if 'health_check' is 'checks', their Nomad service
+    checks are passing. The batch fails if allocations are not healthy within
+    'healthy_deadline'. Consul checks are not taken into account.
+    Groups with 'health_check' set to 'manual' and jobs of type 'batch' or
+    'sysbatch' are not waited on.
+
   -yes
     Automatic yes to prompts. If set, the command automatically restarts
     multi-region jobs only in the region targeted by the command, ignores batch
@@ -220,10 +302,14 @@ func (c *JobRestartCommand) AutocompleteFlags() complete.Flags {
 			"-all-tasks":         complete.PredictNothing,
 			"-batch-size":        complete.PredictAnything,
 			"-batch-wait":        complete.PredictAnything,
+			"-exclude":           complete.PredictAnything,
 			"-no-shutdown-delay": complete.PredictNothing,
 			"-on-error":          complete.PredictSet(jobRestartOnErrorAsk, jobRestartOnErrorFail),
+			"-pause":             complete.PredictNothing,
 			"-reschedule":        complete.PredictNothing,
+			"-resume":            complete.PredictNothing,
 			"-task":              complete.PredictAnything,
+			"-wait-healthy":      complete.PredictNothing,
 			"-yes":               complete.PredictNothing,
 			"-verbose":           complete.PredictNothing,
 		})
@@ -272,8 +358,20 @@ func (c *JobRestartCommand) Run(args []string) int {
 	}
 
 	c.jobID = *job.ID
+	namespace := api.DefaultNamespace
 	if job.Namespace != nil {
-		c.client.SetNamespace(*job.Namespace)
+		namespace = *job.Namespace
+		c.client.SetNamespace(namespace)
+	}
+
+	if c.pause {
+		return c.pauseRestart()
+	}
+
+	// Restore the options and progress of the restart being resumed.
+	if err := c.loadCheckpoint(namespace); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
 	}
 
 	// Handle SIGINT to prevent accidental cancellations of the long-lived
@@ -335,10 +433,17 @@ func (c *JobRestartCommand) Run(args []string) int {
 
 	// Exit early if there's nothing to do.
 	if len(restartAllocs) == 0 {
+		c.deleteCheckpoint()
 		c.Ui.Output("No allocations to restart")
 		return 0
 	}
 
+	// Save the checkpoint so the restart can be resumed if interrupted.
+	if err := c.startCheckpoint(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
 	// Calculate absolute batch size based on the number of eligible
 	// allocations. Round values up to increase parallelism.
 	if c.batchSizePercent {
@@ -354,13 +459,26 @@ func (c *JobRestartCommand) Run(args []string) int {
 	// restartErr accumulates the errors that happen in each batch.
 	var restartErr *multierror.Error
 
-	// Restart allocations in batches.
+	// Restart allocations in batches. batchRestarted collects the IDs of the
+	// allocations of the batch that were restarted successfully.
 	batch := multierror.Group{}
+	var batchRestarted []string
+	var batchLock sync.Mutex
 	for restartCount, alloc := range restartAllocs {
 		// Block and wait before each iteration if the command is handling an
 		// interrupt signal.
 		<-activeCh
 
+		// Stop before starting a new batch if the restart was paused.
+		if restartCount > 0 && restartCount%c.batchSize == 0 && c.isPaused() {
+			c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+				"[bold]==> %s: Job restart paused[reset]",
+				formatTime(time.Now()),
+			)))
+			c.paused = true
+			break
+		}
+
 		// Make sure there are not active deployments to prevent the restart
 		// process from interfering with it.
 		err := c.ensureNoActiveDeployment()
@@ -389,7 +507,14 @@ func (c *JobRestartCommand) Run(args []string) int {
 		// goroutine at each iteration.
 		batch.Go(func(allocStubWithJob AllocationListStubWithJob) func() error {
 			return func() error {
-				return c.handleAlloc(allocStubWithJob)
+				if err := c.handleAlloc(allocStubWithJob); err != nil {
+					return err
+				}
+
+				batchLock.Lock()
+				defer batchLock.Unlock()
+				batchRestarted = append(batchRestarted, allocStubWithJob.ID)
+				return nil
 			}
 		}(alloc))
 
@@ -410,6 +535,14 @@ func (c *JobRestartCommand) Run(args []string) int {
 				batchErr = batchMerr.ErrorOrNil()
 			}
 
+			// Save the progress so the restart can be resumed. Stop if the
+			// restart was taken over by another invocation of the command.
+			if err := c.saveCheckpoint(batchRestarted); err != nil {
+				restartErr = multierror.Append(restartErr, err)
+				break
+			}
+			batchRestarted = nil
+
 			// Block if the command is handling an interrupt signal.
 			<-activeCh
 
@@ -459,6 +592,7 @@ func (c *JobRestartCommand) Run(args []string) int {
 						formatTime(time.Now()),
 					)))
 					c.canceled = true
+					c.pauseCheckpoint()
 					break
 				}
 			}
@@ -486,20 +620,25 @@ func (c *JobRestartCommand) Run(args []string) int {
 				formatTime(time.Now()),
 			)))
 		}
+		c.outputResumeHint()
 
 		restartErr.ErrorFormat = c.errorFormat(0)
 		c.Ui.Error(fmt.Sprintf("\n%s", restartErr))
 		return 1
 	}
 
-	if !c.canceled {
-		c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
-			"[bold]==> %s: Job restart finished[reset]",
-			formatTime(time.Now()),
-		)))
-
-		c.Ui.Output("\nJob restarted successfully!")
+	if c.canceled || c.paused {
+		c.outputResumeHint()
+		return 0
 	}
+
+	c.deleteCheckpoint()
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+		"[bold]==> %s: Job restart finished[reset]",
+		formatTime(time.Now()),
+	)))
+
+	c.Ui.Output("\nJob restarted successfully!")
 	return 0
 }
 
@@ -521,8 +660,18 @@ func (c *JobRestartCommand) parseAndValidate(args []string) (int, error) {
 	flags.StringVar(&batchWaitStr, "batch-wait", "0s", "")
 	flags.StringVar(&c.onError, "on-error", jobRestartOnErrorAsk, "")
 	flags.BoolVar(&c.noShutdownDelay, "no-shutdown-delay", false, "")
+	flags.BoolVar(&c.pause, "pause", false, "")
 	flags.BoolVar(&c.reschedule, "reschedule", false, "")
+	flags.BoolVar(&c.resume, "resume", false, "")
 	flags.BoolVar(&c.verbose, "verbose", false, "")
+	flags.BoolVar(&c.waitHealthy, "wait-healthy", false, "")
+	flags.Var((funcVar)(func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return errors.New("allocation ID must not be empty")
+		}
+		c.exclude = append(c.exclude, strings.TrimSpace(s))
+		return nil
+	}), "exclude", "")
 	flags.Var((funcVar)(func(s string) error {
 		groups = append(groups, s)
 		return nil
@@ -551,25 +700,43 @@ func (c *JobRestartCommand) parseAndValidate(args []string) (int, error) {
 	}
 	c.jobID = strings.TrimSpace(args[0])
 
-	// Parse and validate -batch-size.
-	matches := jobRestartBatchSizeValueRegex.FindStringSubmatch(batchSizeStr)
-	if len(matches) != 2 {
-		return 1, fmt.Errorf(
-			"Invalid -batch-size value %q: batch size must be an integer or a percentage",
-			batchSizeStr,
-		)
+	// -pause only updates the checkpoint of the restart in progress, and
+	// -resume uses the options the restart was started with.
+	if c.pause && c.resume {
+		return 1, fmt.Errorf("The -pause option cannot be used with -resume")
 	}
+	if c.pause || c.resume {
+		option := "-resume"
+		if c.pause {
+			option = "-pause"
+		}
 
-	c.batchSizePercent = strings.HasSuffix(batchSizeStr, "%")
-	c.batchSize, err = strconv.Atoi(matches[1])
-	if err != nil {
-		return 1, fmt.Errorf("Invalid -batch-size value %q: %w", batchSizeStr, err)
+		var conflict string
+		flags.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "all-tasks", "batch-size", "exclude", "group", "no-shutdown-delay",
+				"reschedule", "task", "wait-healthy":
+				if conflict == "" {
+					conflict = f.Name
+				}
+			case "batch-wait", "on-error", "yes":
+				if c.pause && conflict == "" {
+					conflict = f.Name
+				}
+			}
+		})
+		if conflict != "" {
+			return 1, fmt.Errorf("The %s option cannot be used with -%s", option, conflict)
+		}
+		if c.pause {
+			return 0, nil
+		}
 	}
-	if c.batchSize == 0 {
-		return 1, fmt.Errorf(
-			"Invalid -batch-size value %q: number value must be greater than zero",
-			batchSizeStr,
-		)
+
+	// Parse and validate -batch-size.
+	c.batchSize, c.batchSizePercent, err = parseJobRestartBatchSize(batchSizeStr)
+	if err != nil {
+		return 1, err
 	}
 
 	// Parse and validate -batch-wait.
@@ -624,6 +791,30 @@ func (c *JobRestartCommand) parseAndValidate(args []string) (int, error) {
 	return 0, nil
 }
 
+// parseJobRestartBatchSize parses a -batch-size value into its number and
+// whether it is a percentage.
+func parseJobRestartBatchSize(batchSizeStr string) (int, bool, error) {
+	matches := jobRestartBatchSizeValueRegex.FindStringSubmatch(batchSizeStr)
+	if len(matches) != 2 {
+		return 0, false, fmt.Errorf(
+			"Invalid -batch-size value %q: batch size must be an integer or a percentage",
+			batchSizeStr,
+		)
+	}
+
+	batchSize, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, false, fmt.Errorf("Invalid -batch-size value %q: %w", batchSizeStr, err)
+	}
+	if batchSize == 0 {
+		return 0, false, fmt.Errorf(
+			"Invalid -batch-size value %q: number value must be greater than zero",
+			batchSizeStr,
+		)
+	}
+	return batchSize, strings.HasSuffix(batchSizeStr, "%"), nil
+}
+
 // filterAllocs returns a slice of the allocations that should be restarted.
 func (c *JobRestartCommand) filterAllocs(stubs []AllocationListStubWithJob) []AllocationListStubWithJob {
 	result := []AllocationListStubWithJob{}
@@ -657,6 +848,31 @@ func (c *JobRestartCommand) filterAllocs(stubs []AllocationListStubWithJob) []Al
 			continue
 		}
 
+		// Skip allocations that were excluded.
+		if c.isExcluded(stub.ID) {
+			if c.verbose {
+				c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+					"[dark_gray]    %s: Skipping allocation %q because it was excluded[reset]",
+					formatTime(time.Now()),
+					shortAllocID,
+				)))
+			}
+			continue
+		}
+
+		// Skip allocations that were already restarted before the restart
+		// was paused or interrupted.
+		if c.checkpoint.hasRestarted(stub) {
+			if c.verbose {
+				c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+					"[dark_gray]    %s: Skipping allocation %q because it was already restarted[reset]",
+					formatTime(time.Now()),
+					shortAllocID,
+				)))
+			}
+			continue
+		}
+
 		// Skip allocations for groups that were not requested.
 		if c.groups.Size() > 0 {
 			if !c.groups.Contains(stub.TaskGroup) {
@@ -699,6 +915,16 @@ func (c *JobRestartCommand) filterAllocs(stubs []AllocationListStubWithJob) []Al
 	return result
 }
 
+// isExcluded returns true if the allocation was excluded with -exclude.
+func (c *JobRestartCommand) isExcluded(allocID string) bool {
+	for _, prefix := range c.exclude {
+		if strings.HasPrefix(allocID, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // ensureNoActiveDeployment returns an error if the job has an active
 // deployment.
 func (c *JobRestartCommand) ensureNoActiveDeployment() error {
@@ -860,7 +1086,8 @@ func (c *JobRestartCommand) askQuestion(question string, onError bool, cb func(s
 }
 
 // handleAlloc stops or restarts an allocation in-place. Blocks until the
-// allocation  is done restarting or the rescheduled allocation is running.
+// allocation  is done restarting or the rescheduled allocation is running, and
+// is healthy if -wait-healthy is set.
 func (c *JobRestartCommand) handleAlloc(alloc AllocationListStubWithJob) error {
 	var err error
 	if c.reschedule {
@@ -869,6 +1096,9 @@ func (c *JobRestartCommand) handleAlloc(alloc AllocationListStubWithJob) error {
 	} else {
 		err = c.restartAlloc(alloc)
 	}
+	if err == nil && c.waitHealthy {
+		err = c.waitAllocHealthy(alloc)
+	}
 	if err != nil {
 		msg := fmt.Sprintf("Error restarting allocation %q:", limit(alloc.ID, c.length))
 		if mErr, ok := err.(*multierror.Error); ok {
@@ -1247,3 +1477,547 @@ func (a *AllocationListStubWithJob) IsRunning() bool {
 func (a *AllocationListStubWithJob) isSystemJob() bool {
 	return a.Job != nil && a.Job.Type != nil && *a.Job.Type == api.JobTypeSystem
 }
+
+// updateStrategy returns the update strategy of the allocation's group, with
+// default values for the fields that are not set, or nil if the job type
+// doesn't support updates.
+func (a *AllocationListStubWithJob) updateStrategy() *api.UpdateStrategy {
+	if a.Job == nil || a.Job.Type == nil {
+		return nil
+	}
+	switch *a.Job.Type {
+	case api.JobTypeService, api.JobTypeSystem:
+	default:
+		return nil
+	}
+
+	update := api.DefaultUpdateStrategy()
+	update.Merge(a.Job.Update)
+	if tg := a.Job.LookupTaskGroup(a.TaskGroup); tg != nil {
+		update.Merge(tg.Update)
+	}
+	return update
+}
+
+// waitAllocHealthy blocks until the allocation, or its replacement if it was
+// rescheduled, is healthy as defined by the update block of its group.
+func (c *JobRestartCommand) waitAllocHealthy(alloc AllocationListStubWithJob) error {
+	update := alloc.updateStrategy()
+	if update == nil || *update.HealthCheck == jobRestartHealthCheckManual {
+		if c.verbose {
+			c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+				"[dark_gray]    %s: Not waiting for allocation %q to be healthy because its group has no health checks[reset]",
+				formatTime(time.Now()),
+				limit(alloc.ID, c.length),
+			)))
+		}
+		return nil
+	}
+
+	allocID := alloc.ID
+	deadline := time.Now().Add(*update.HealthyDeadline)
+	for {
+		current, _, err := c.client.Allocations().Info(allocID, nil)
+		if err != nil {
+			return fmt.Errorf("Failed to retrieve allocation %q: %w", limit(allocID, c.length), err)
+		}
+
+		// Follow the replacements of rescheduled allocations.
+		if current.NextAllocation != "" {
+			allocID = current.NextAllocation
+			continue
+		}
+
+		var checks api.AllocCheckStatuses
+		if *update.HealthCheck == jobRestartHealthCheckChecks {
+			checks, err = c.client.Allocations().Checks(allocID, nil)
+			if err != nil {
+				return fmt.Errorf("Failed to retrieve checks of allocation %q: %w", limit(allocID, c.length), err)
+			}
+		}
+
+		healthy, err := jobRestartAllocHealthy(current, checks, *update.MinHealthyTime, time.Now())
+		if err != nil {
+			return err
+		}
+		if healthy {
+			c.Ui.Output(fmt.Sprintf(
+				"    %s: Allocation %q is healthy",
+				formatTime(time.Now()),
+				limit(allocID, c.length),
+			))
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Allocation %q is not healthy after %s", limit(allocID, c.length), *update.HealthyDeadline)
+		}
+		time.Sleep(jobRestartHealthPollInterval)
+	}
+}
+
+// jobRestartAllocHealthy returns true if the tasks of the allocation have
+// been running for at least minHealthyTime since they last started, and all
+// the given checks passed since then. It returns an error if the allocation
+// or one of its tasks failed.
+func jobRestartAllocHealthy(alloc *api.Allocation, checks api.AllocCheckStatuses, minHealthyTime time.Duration, now time.Time) (bool, error) {
+	switch alloc.ClientStatus {
+	case api.AllocClientStatusRunning:
+	case api.AllocClientStatusFailed, api.AllocClientStatusLost:
+		return false, fmt.Errorf("Allocation %q is %q", limit(alloc.ID, shortId), alloc.ClientStatus)
+	default:
+		return false, nil
+	}
+
+	var started time.Time
+	for name, state := range alloc.TaskStates {
+		if state.Failed {
+			return false, fmt.Errorf("Task %q failed", name)
+		}
+
+		switch state.State {
+		case jobRestartTaskStateRunning:
+			taskStarted := state.StartedAt
+			if state.LastRestart.After(taskStarted) {
+				taskStarted = state.LastRestart
+			}
+			if taskStarted.After(started) {
+				started = taskStarted
+			}
+		case jobRestartTaskStateDead:
+			// Tasks such as prestart tasks are expected to complete.
+		default:
+			return false, nil
+		}
+	}
+	if started.IsZero() || now.Sub(started) < minHealthyTime {
+		return false, nil
+	}
+
+	// Check results from before the tasks started may be stale.
+	for _, check := range checks {
+		if check.Status != jobRestartCheckSuccess || time.Unix(check.Timestamp, 0).Before(started.Truncate(time.Second)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// jobRestartCheckpoint is the progress of a job restart. It is saved in a
+// variable so a restart that is paused or interrupted can be resumed by
+// another invocation of the command.
+type jobRestartCheckpoint struct {
+	// Status is either running or paused.
+	Status string
+
+	// Owner identifies the invocation of the command running the restart, to
+	// detect when the restart is resumed elsewhere.
+	Owner string
+
+	// StartTime is when the restart was started.
+	StartTime time.Time
+
+	// Options are the options the restart was started with.
+	Options jobRestartOptions
+
+	// Restarted are the IDs of the allocations already restarted or
+	// rescheduled.
+	Restarted *set.Set[string]
+
+	// namespace, path, and modifyIndex identify the variable the checkpoint
+	// is saved in.
+	namespace   string
+	path        string
+	modifyIndex uint64
+}
+
+// jobRestartOptions are the options of a restart that are saved in its
+// checkpoint and restored when it is resumed.
+type jobRestartOptions struct {
+	AllTasks        bool     `json:",omitempty"`
+	BatchSize       string   `json:",omitempty"`
+	Exclude         []string `json:",omitempty"`
+	Groups          []string `json:",omitempty"`
+	NoShutdownDelay bool     `json:",omitempty"`
+	Reschedule      bool     `json:",omitempty"`
+	Tasks           []string `json:",omitempty"`
+	WaitHealthy     bool     `json:",omitempty"`
+}
+
+// jobRestartCheckpointPath returns the path of the variable the restart
+// checkpoint of the job is saved in, and false if the job ID can't be used
+// in a variable path.
+func jobRestartCheckpointPath(jobID string) (string, bool) {
+	path := fmt.Sprintf(jobRestartCheckpointPathFmt, jobID)
+	return path, jobRestartVariablePathRegex.MatchString(path)
+}
+
+// hasRestarted returns true if the allocation was restarted before the
+// restart was paused or interrupted. Allocations created after a reschedule
+// started are replacements, so they are considered restarted too.
+func (cp *jobRestartCheckpoint) hasRestarted(alloc AllocationListStubWithJob) bool {
+	if cp == nil {
+		return false
+	}
+	if cp.Restarted.Contains(alloc.ID) {
+		return true
+	}
+	return cp.Options.Reschedule && alloc.CreateTime >= cp.StartTime.UnixNano()
+}
+
+// variable encodes the checkpoint into a variable.
+func (cp *jobRestartCheckpoint) variable() (*api.Variable, error) {
+	options, err := json.Marshal(cp.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	restarted := cp.Restarted.Slice()
+	sort.Strings(restarted)
+
+	return &api.Variable{
+		Namespace:   cp.namespace,
+		Path:        cp.path,
+		ModifyIndex: cp.modifyIndex,
+		Items: api.VariableItems{
+			"status":     cp.Status,
+			"owner":      cp.Owner,
+			"start_time": cp.StartTime.Format(time.RFC3339Nano),
+			"options":    string(options),
+			"restarted":  strings.Join(restarted, ","),
+		},
+	}, nil
+}
+
+// decodeJobRestartCheckpoint decodes a checkpoint from its variable.
+func decodeJobRestartCheckpoint(v *api.Variable) (*jobRestartCheckpoint, error) {
+	cp := &jobRestartCheckpoint{
+		Status:      v.Items["status"],
+		Owner:       v.Items["owner"],
+		Restarted:   set.New[string](0),
+		namespace:   v.Namespace,
+		path:        v.Path,
+		modifyIndex: v.ModifyIndex,
+	}
+
+	var err error
+	cp.StartTime, err = time.Parse(time.RFC3339Nano, v.Items["start_time"])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid restart checkpoint %q: %w", v.Path, err)
+	}
+	if err := json.Unmarshal([]byte(v.Items["options"]), &cp.Options); err != nil {
+		return nil, fmt.Errorf("Invalid restart checkpoint %q: %w", v.Path, err)
+	}
+	if restarted := v.Items["restarted"]; restarted != "" {
+		cp.Restarted.InsertSlice(strings.Split(restarted, ","))
+	}
+	return cp, nil
+}
+
+// options returns the options of the restart to save in its checkpoint.
+func (c *JobRestartCommand) options() jobRestartOptions {
+	batchSize := strconv.Itoa(c.batchSize)
+	if c.batchSizePercent {
+		batchSize += "%"
+	}
+
+	groups := c.groups.Slice()
+	sort.Strings(groups)
+	tasks := c.tasks.Slice()
+	sort.Strings(tasks)
+
+	return jobRestartOptions{
+		AllTasks:        c.allTasks,
+		BatchSize:       batchSize,
+		Exclude:         c.exclude,
+		Groups:          groups,
+		NoShutdownDelay: c.noShutdownDelay,
+		Reschedule:      c.reschedule,
+		Tasks:           tasks,
+		WaitHealthy:     c.waitHealthy,
+	}
+}
+
+// applyOptions sets the options of the restart being resumed.
+func (c *JobRestartCommand) applyOptions(o jobRestartOptions) error {
+	var err error
+	c.batchSize, c.batchSizePercent, err = parseJobRestartBatchSize(o.BatchSize)
+	if err != nil {
+		return err
+	}
+
+	c.allTasks = o.AllTasks
+	c.exclude = o.Exclude
+	c.groups = set.From(o.Groups)
+	c.noShutdownDelay = o.NoShutdownDelay
+	c.reschedule = o.Reschedule
+	c.tasks = set.From(o.Tasks)
+	c.waitHealthy = o.WaitHealthy
+	return nil
+}
+
+// readCheckpoint reads a restart checkpoint. It returns nil if there is
+// none.
+func (c *JobRestartCommand) readCheckpoint(path string) (*jobRestartCheckpoint, error) {
+	v, _, err := c.client.Variables().Read(path, nil)
+	if errors.Is(err, api.ErrVariablePathNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeJobRestartCheckpoint(v)
+}
+
+// writeCheckpoint saves the checkpoint, unless it was modified since it was
+// last read or written.
+func (c *JobRestartCommand) writeCheckpoint() error {
+	v, err := c.checkpoint.variable()
+	if err != nil {
+		return err
+	}
+
+	out, _, err := c.client.Variables().CheckedUpdate(v, nil)
+	if err != nil {
+		return err
+	}
+	c.checkpoint.modifyIndex = out.ModifyIndex
+	return nil
+}
+
+// loadCheckpoint restores the options and progress of the restart being
+// resumed. For new restarts, it makes sure there isn't an unfinished restart
+// for the job.
+func (c *JobRestartCommand) loadCheckpoint(namespace string) error {
+	path, ok := jobRestartCheckpointPath(c.jobID)
+	if !ok {
+		if c.resume {
+			return fmt.Errorf("Restarts of job %q can't be resumed because its ID can't be used in a variable path", c.jobID)
+		}
+		c.Ui.Warn(fmt.Sprintf(
+			"Warning: the progress of the restart will not be saved because job ID %q can't be used in a variable path",
+			c.jobID,
+		))
+		return nil
+	}
+
+	existing, err := c.readCheckpoint(path)
+	if err != nil {
+		if c.resume {
+			return fmt.Errorf("Error reading restart checkpoint %q: %w", path, err)
+		}
+		c.Ui.Warn(fmt.Sprintf(
+			"Warning: the progress of the restart will not be saved: error reading restart checkpoint %q: %v",
+			path, err,
+		))
+		return nil
+	}
+
+	if !c.resume {
+		if existing != nil {
+			return fmt.Errorf(
+				"Job %q has an unfinished restart started at %s. Use -resume to continue it, "+
+					"or delete its checkpoint with 'nomad var purge -namespace=%s %s' to start a new restart.",
+				c.jobID, formatTime(existing.StartTime), namespace, path,
+			)
+		}
+
+		c.checkpoint = &jobRestartCheckpoint{
+			Restarted: set.New[string](0),
+			namespace: namespace,
+			path:      path,
+		}
+		return nil
+	}
+
+	if existing == nil {
+		return fmt.Errorf("Job %q has no restart to resume", c.jobID)
+	}
+	if err := c.applyOptions(existing.Options); err != nil {
+		return fmt.Errorf("Invalid restart checkpoint %q: %w", path, err)
+	}
+	c.checkpoint = existing
+
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+		"[bold]==> %s: Resuming restart started at %s with %s already restarted[reset]",
+		formatTime(time.Now()),
+		formatTime(existing.StartTime),
+		english.Plural(existing.Restarted.Size(), "allocation", "allocations"),
+	)))
+	return nil
+}
+
+// startCheckpoint saves the checkpoint of a new restart, or takes over the
+// checkpoint of the restart being resumed.
+func (c *JobRestartCommand) startCheckpoint() error {
+	if c.checkpoint == nil {
+		return nil
+	}
+
+	c.checkpoint.Status = jobRestartStatusRunning
+	c.checkpoint.Owner = uuid.Generate()
+	if !c.resume {
+		c.checkpoint.StartTime = time.Now()
+		c.checkpoint.Options = c.options()
+	}
+
+	err := c.writeCheckpoint()
+	if err == nil {
+		return nil
+	}
+
+	var conflict api.ErrCASConflict
+	if c.resume || errors.As(err, &conflict) {
+		return fmt.Errorf("Error saving restart checkpoint %q: %w", c.checkpoint.path, err)
+	}
+	c.Ui.Warn(fmt.Sprintf(
+		"Warning: the progress of the restart will not be saved: error saving restart checkpoint %q: %v",
+		c.checkpoint.path, err,
+	))
+	c.checkpoint = nil
+	return nil
+}
+
+// saveCheckpoint adds the allocations to the restarted allocations of the
+// checkpoint and saves it, keeping the pause if the restart was paused in the
+// meantime. It returns an error if the restart was resumed elsewhere or its
+// checkpoint deleted, and only warns about other errors so the restart can
+// proceed.
+func (c *JobRestartCommand) saveCheckpoint(restarted []string) error {
+	if c.checkpoint == nil {
+		return nil
+	}
+	c.checkpoint.Restarted.InsertSlice(restarted)
+
+	for {
+		err := c.writeCheckpoint()
+		if err == nil {
+			return nil
+		}
+
+		var conflict api.ErrCASConflict
+		if errors.As(err, &conflict) {
+			latest, err := c.readCheckpoint(c.checkpoint.path)
+			if err == nil {
+				switch {
+				case latest == nil:
+					err = fmt.Errorf("Restart checkpoint %q was deleted", c.checkpoint.path)
+					c.checkpoint = nil
+					return err
+				case latest.Owner != c.checkpoint.Owner:
+					c.checkpoint = nil
+					return errors.New("Job restart was resumed by another invocation of the command")
+				}
+
+				if latest.Status == jobRestartStatusPaused {
+					c.checkpoint.Status = jobRestartStatusPaused
+				}
+				c.checkpoint.modifyIndex = latest.modifyIndex
+				continue
+			}
+		}
+
+		c.Ui.Warn(fmt.Sprintf(
+			"    %s: Failed to save restart checkpoint %q: %v",
+			formatTime(time.Now()),
+			c.checkpoint.path,
+			err,
+		))
+		return nil
+	}
+}
+
+// isPaused returns true if the restart was paused.
+func (c *JobRestartCommand) isPaused() bool {
+	if c.checkpoint == nil {
+		return false
+	}
+	if c.checkpoint.Status == jobRestartStatusPaused {
+		return true
+	}
+
+	latest, err := c.readCheckpoint(c.checkpoint.path)
+	return err == nil && latest != nil && latest.Status == jobRestartStatusPaused
+}
+
+// pauseCheckpoint marks the restart as paused after the user canceled it, so
+// it can be resumed later.
+func (c *JobRestartCommand) pauseCheckpoint() {
+	if c.checkpoint == nil {
+		return
+	}
+	c.checkpoint.Status = jobRestartStatusPaused
+	if err := c.saveCheckpoint(nil); err != nil {
+		c.Ui.Warn(err.Error())
+	}
+}
+
+// deleteCheckpoint deletes the checkpoint of a finished restart.
+func (c *JobRestartCommand) deleteCheckpoint() {
+	if c.checkpoint == nil || c.checkpoint.Owner == "" {
+		// The checkpoint of a new restart is only saved once it starts.
+		return
+	}
+
+	_, err := c.client.Variables().CheckedDelete(c.checkpoint.path, c.checkpoint.modifyIndex, nil)
+	if err != nil {
+		c.Ui.Warn(fmt.Sprintf("Failed to delete restart checkpoint %q: %v", c.checkpoint.path, err))
+	}
+}
+
+// outputResumeHint tells the user how to resume the restart.
+func (c *JobRestartCommand) outputResumeHint() {
+	if c.checkpoint == nil || c.checkpoint.Owner == "" {
+		return
+	}
+
+	c.Ui.Output(fmt.Sprintf(
+		"\nThe restart can be resumed with:\n    nomad job restart -namespace=%s -resume %s",
+		c.checkpoint.namespace,
+		c.jobID,
+	))
+}
+
+// pauseRestart pauses the restart of the job in progress. The command
+// running the restart stops once its current batch completes.
+func (c *JobRestartCommand) pauseRestart() int {
+	path, ok := jobRestartCheckpointPath(c.jobID)
+	if !ok {
+		c.Ui.Error(fmt.Sprintf("Job %q has no restart to pause", c.jobID))
+		return 1
+	}
+
+	for {
+		cp, err := c.readCheckpoint(path)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error reading restart checkpoint %q: %s", path, err))
+			return 1
+		}
+		if cp == nil {
+			c.Ui.Error(fmt.Sprintf("Job %q has no restart to pause", c.jobID))
+			return 1
+		}
+		if cp.Status == jobRestartStatusPaused {
+			c.Ui.Output(fmt.Sprintf("Restart of job %q is already paused", c.jobID))
+			return 0
+		}
+
+		cp.Status = jobRestartStatusPaused
+		c.checkpoint = cp
+		err = c.writeCheckpoint()
+
+		// Retry if the restart saved its progress in the meantime.
+		var conflict api.ErrCASConflict
+		if errors.As(err, &conflict) {
+			continue
+		}
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error saving restart checkpoint %q: %s", path, err))
+			return 1
+		}
+
+		c.Ui.Output(fmt.Sprintf(
+			"Restart of job %q will pause once its current batch completes", c.jobID))
+		return 0
+	}
+}
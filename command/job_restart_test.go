@@ -194,6 +194,49 @@ func TestJobRestartCommand_parseAndValidate(t *testing.T) {
 				length:    fullId,
 			},
 		},
+		{
+			name: "exclude",
+			args: []string{"-exclude", "abc", "-exclude", " def ", "my-job"},
+			expectedCmd: &JobRestartCommand{
+				jobID:     "my-job",
+				batchSize: 1,
+				exclude:   []string{"abc", "def"},
+			},
+		},
+		{
+			name: "wait healthy",
+			args: []string{"-wait-healthy", "my-job"},
+			expectedCmd: &JobRestartCommand{
+				jobID:       "my-job",
+				batchSize:   1,
+				waitHealthy: true,
+			},
+		},
+		{
+			name: "resume",
+			args: []string{"-resume", "-batch-wait", "1s", "my-job"},
+			expectedCmd: &JobRestartCommand{
+				jobID:     "my-job",
+				batchSize: 1,
+				batchWait: time.Second,
+				resume:    true,
+			},
+		},
+		{
+			name:        "resume conflicts with batch size",
+			args:        []string{"-resume", "-batch-size", "2", "my-job"},
+			expectedErr: "The -resume option cannot be used with -batch-size",
+		},
+		{
+			name:        "pause conflicts with resume",
+			args:        []string{"-pause", "-resume", "my-job"},
+			expectedErr: "The -pause option cannot be used with -resume",
+		},
+		{
+			name:        "pause conflicts with yes",
+			args:        []string{"-pause", "-yes", "my-job"},
+			expectedErr: "The -pause option cannot be used with -yes",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1539,6 +1582,18 @@ func TestJobRestartCommand_filterAllocs(t *testing.T) {
 				allocs["job_v2_group_2_stop_running"],
 			},
 		},
+		{
+			name: "skip by exclude",
+			args: []string{
+				"-group", "group_1",
+				"-exclude", "job_v1",
+			},
+			expectedAllocs: []AllocationListStubWithJob{
+				allocs["job_v2_group_1_run_running"],
+				allocs["job_v2_group_1_run_complete"],
+				allocs["job_v2_group_1_stop_running"],
+			},
+		},
 		{
 			name:           "no matches by group",
 			args:           []string{"-group", "group_404"},
@@ -1663,6 +1718,263 @@ func TestJobRestartCommand_onErrorFail(t *testing.T) {
 // false positives.
 //
 // The restarts map contains values structured as group:task:<expect restart?>.
+func TestJobRestartCommand_checkpoint(t *testing.T) {
+	ci.Parallel(t)
+
+	startTime := time.Now()
+	cp := &jobRestartCheckpoint{
+		Status:    jobRestartStatusPaused,
+		Owner:     "owner",
+		StartTime: startTime,
+		Options: jobRestartOptions{
+			BatchSize:   "50%",
+			Groups:      []string{"web"},
+			Reschedule:  true,
+			WaitHealthy: true,
+		},
+		Restarted:   set.From([]string{"b", "a"}),
+		namespace:   "prod",
+		path:        "nomad/jobs/example/restart-checkpoint",
+		modifyIndex: 10,
+	}
+
+	v, err := cp.variable()
+	must.NoError(t, err)
+	must.Eq(t, "a,b", v.Items["restarted"])
+
+	got, err := decodeJobRestartCheckpoint(v)
+	must.NoError(t, err)
+	must.Eq(t, cp.Status, got.Status)
+	must.Eq(t, cp.Owner, got.Owner)
+	must.True(t, cp.StartTime.Equal(got.StartTime))
+	must.Eq(t, cp.Options, got.Options)
+	must.True(t, cp.Restarted.Equal(got.Restarted))
+	must.Eq(t, cp.namespace, got.namespace)
+	must.Eq(t, cp.path, got.path)
+	must.Eq(t, cp.modifyIndex, got.modifyIndex)
+
+	// Allocations created after the reschedule started are replacements.
+	stub := func(id string, created time.Time) AllocationListStubWithJob {
+		return AllocationListStubWithJob{AllocationListStub: &api.AllocationListStub{
+			ID:         id,
+			CreateTime: created.UnixNano(),
+		}}
+	}
+	must.True(t, got.hasRestarted(stub("a", startTime.Add(-time.Hour))))
+	must.False(t, got.hasRestarted(stub("c", startTime.Add(-time.Hour))))
+	must.True(t, got.hasRestarted(stub("d", startTime.Add(time.Second))))
+
+	var noCheckpoint *jobRestartCheckpoint
+	must.False(t, noCheckpoint.hasRestarted(stub("a", startTime)))
+
+	_, ok := jobRestartCheckpointPath("example")
+	must.True(t, ok)
+	_, ok = jobRestartCheckpointPath("my job")
+	must.False(t, ok)
+}
+
+func TestJobRestartCommand_allocHealthy(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now()
+	started := now.Add(-time.Minute)
+
+	testCases := []struct {
+		name           string
+		clientStatus   string
+		taskStates     map[string]*api.TaskState
+		checks         api.AllocCheckStatuses
+		expected       bool
+		expectedErr    string
+		minHealthyTime time.Duration
+	}{
+		{
+			name:         "running",
+			clientStatus: api.AllocClientStatusRunning,
+			taskStates: map[string]*api.TaskState{
+				"main":     {State: jobRestartTaskStateRunning, StartedAt: started},
+				"prestart": {State: jobRestartTaskStateDead, StartedAt: started},
+			},
+			expected: true,
+		},
+		{
+			name:         "pending",
+			clientStatus: api.AllocClientStatusPending,
+		},
+		{
+			name:         "failed",
+			clientStatus: api.AllocClientStatusFailed,
+			expectedErr:  "is \"failed\"",
+		},
+		{
+			name:         "task failed",
+			clientStatus: api.AllocClientStatusRunning,
+			taskStates: map[string]*api.TaskState{
+				"main": {State: jobRestartTaskStateDead, Failed: true},
+			},
+			expectedErr: "Task \"main\" failed",
+		},
+		{
+			name:         "task pending",
+			clientStatus: api.AllocClientStatusRunning,
+			taskStates: map[string]*api.TaskState{
+				"main": {State: "pending"},
+			},
+		},
+		{
+			name:         "min healthy time",
+			clientStatus: api.AllocClientStatusRunning,
+			taskStates: map[string]*api.TaskState{
+				"main": {State: jobRestartTaskStateRunning, StartedAt: started.Add(-time.Hour), LastRestart: started},
+			},
+			minHealthyTime: 2 * time.Minute,
+		},
+		{
+			name:         "checks passing",
+			clientStatus: api.AllocClientStatusRunning,
+			taskStates: map[string]*api.TaskState{
+				"main": {State: jobRestartTaskStateRunning, StartedAt: started},
+			},
+			checks: api.AllocCheckStatuses{
+				"check": {Status: jobRestartCheckSuccess, Timestamp: now.Unix()},
+			},
+			expected: true,
+		},
+		{
+			name:         "checks failing",
+			clientStatus: api.AllocClientStatusRunning,
+			taskStates: map[string]*api.TaskState{
+				"main": {State: jobRestartTaskStateRunning, StartedAt: started},
+			},
+			checks: api.AllocCheckStatuses{
+				"check": {Status: "failure", Timestamp: now.Unix()},
+			},
+		},
+		{
+			name:         "checks stale",
+			clientStatus: api.AllocClientStatusRunning,
+			taskStates: map[string]*api.TaskState{
+				"main": {State: jobRestartTaskStateRunning, StartedAt: started},
+			},
+			checks: api.AllocCheckStatuses{
+				"check": {Status: jobRestartCheckSuccess, Timestamp: started.Add(-time.Hour).Unix()},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			alloc := &api.Allocation{
+				ID:           "e4f7a1cb-2c56-4d2b-9b1a-0b1d2c6a7e10",
+				ClientStatus: tc.clientStatus,
+				TaskStates:   tc.taskStates,
+			}
+
+			healthy, err := jobRestartAllocHealthy(alloc, tc.checks, tc.minHealthyTime, now)
+			if tc.expectedErr != "" {
+				must.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.expected, healthy)
+		})
+	}
+}
+
+func TestJobRestartCommand_resume(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start client and server and wait for node to be ready.
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	waitForNodes(t, client)
+
+	// Register test job and wait for its allocs to be running.
+	jobID := "test_job_restart_resume"
+	job := testJob(jobID)
+	job.TaskGroups[0].Count = pointer.Of(3)
+	job.TaskGroups[0].Tasks[0].Config["run_for"] = "1m"
+
+	ui := cli.NewMockUi()
+	resp, _, err := client.Jobs().Register(job, nil)
+	must.NoError(t, err)
+
+	code := waitForSuccess(ui, client, fullId, t, resp.EvalID)
+	must.Zero(t, code)
+
+	allocStubs, _, err := client.Jobs().Allocations(jobID, true, nil)
+	must.NoError(t, err)
+	for _, alloc := range allocStubs {
+		waitForAllocRunning(t, client, alloc.ID)
+	}
+
+	// Starting a restart while another one is unfinished is not allowed.
+	path, _ := jobRestartCheckpointPath(jobID)
+	cp := &jobRestartCheckpoint{
+		Status:    jobRestartStatusRunning,
+		Owner:     "other",
+		StartTime: time.Now(),
+		Options: jobRestartOptions{
+			AllTasks:  true,
+			BatchSize: "2",
+		},
+		Restarted: set.From([]string{allocStubs[0].ID}),
+		namespace: api.DefaultNamespace,
+		path:      path,
+	}
+	v, err := cp.variable()
+	must.NoError(t, err)
+	_, _, err = client.Variables().Create(v, nil)
+	must.NoError(t, err)
+
+	ui = cli.NewMockUi()
+	cmd := &JobRestartCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address", url, "-yes", jobID})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "has an unfinished restart")
+
+	// Pause the restart.
+	ui = cli.NewMockUi()
+	cmd = &JobRestartCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address", url, "-pause", jobID})
+	must.Zero(t, code)
+	must.StrContains(t, ui.OutputWriter.String(), "will pause")
+
+	v, _, err = client.Variables().Read(path, nil)
+	must.NoError(t, err)
+	must.Eq(t, jobRestartStatusPaused, v.Items["status"])
+
+	// Resume the restart and verify only the remaining allocations are
+	// restarted, with the options from the checkpoint.
+	ui = cli.NewMockUi()
+	cmd = &JobRestartCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address", url, "-yes", "-resume", jobID})
+	must.Zero(t, code, must.Sprintf(
+		"stdout: %s\n\nstderr: %s\n",
+		ui.OutputWriter.String(),
+		ui.ErrorWriter.String()),
+	)
+	must.True(t, cmd.allTasks)
+	must.Eq(t, 2, cmd.batchSize)
+
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, "Resuming restart")
+	must.StrContains(t, out, "Restarting 2 allocations")
+	must.StrNotContains(t, out, allocStubs[0].ID[:shortId])
+
+	// The checkpoint is deleted once the restart finishes.
+	_, _, err = client.Variables().Read(path, nil)
+	must.ErrorIs(t, err, api.ErrVariablePathNotFound)
+
+	// There's nothing left to resume.
+	ui = cli.NewMockUi()
+	cmd = &JobRestartCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address", url, "-yes", "-resume", jobID})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "has no restart to resume")
+}
+
 func waitTasksRestarted(
 	t *testing.T,
 	client *api.Client,
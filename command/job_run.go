@@ -6,6 +6,7 @@ package command
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"regexp"
 	"strconv"
@@ -30,6 +31,7 @@ type JobRunCommand struct {
 func (c *JobRunCommand) Help() string {
 	helpText := `
 Usage: nomad job run [options] <path>
+       nomad job run [options] -template=<name>
 Alias: nomad run
 
   Starts running a new job or updates an existing job using
@@ -40,6 +42,10 @@ Alias: nomad run
   it is read from the file at the supplied path or downloaded and
   read from URL specified.
 
+  If the -template flag is set, the job is rendered from the named job
+  template registered in the cluster instead, using the values given with the
+  -var and -var-file flags for the variables of the template.
+
   Upon successful job submission, this command will immediately
   enter an interactive monitor. This is useful to watch Nomad's
   internals make scheduling decisions and place the submitted work
@@ -67,7 +73,8 @@ Alias: nomad run
   capability for the job's namespace. Jobs that mount CSI volumes require a
   token with the 'csi-mount-volume' capability for the volume's
   namespace. Jobs that mount host volumes require a token with the
-  'host_volume' capability for that volume.
+  'host_volume' capability for that volume. Jobs run from a job template also
+  require a token with the 'run' capability for the template.
 
 General Options:
 
@@ -92,6 +99,12 @@ Run Options:
     Override the priority of the evaluations produced as a result of this job
     submission. By default, this is set to the priority of the job.
 
+  -idempotency-token
+    Optional identifier used to prevent registering more than one version of
+    the job when the command is retried. If the job was already registered with
+    the same token, the command returns the evaluation of that registration
+    without updating the job.
+
   -json
     Parses the job file as JSON. If the outer object has a Job field, such as
     from "nomad job inspect" or "nomad run -output", the value of the field is
@@ -105,6 +118,15 @@ Run Options:
     has been supplied which is not defined within the root variables. Defaults
     to true, but ignored if "-hcl1" is also defined.
 
+  -strict
+    Whether an error should be produced for unknown fields in JSON and HCL2
+    job files, including those the HCL2 parser would otherwise ignore. Defaults
+    to false.
+
+  -template
+    Run the job from the named job template instead of a job file. The values
+    of the -var and -var-file flags are passed to the template.
+
   -output
     Output the JSON that would be submitted to the HTTP API without submitting
     the job.
@@ -164,22 +186,25 @@ func (c *JobRunCommand) Synopsis() string {
 func (c *JobRunCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-check-index":      complete.PredictNothing,
-			"-detach":           complete.PredictNothing,
-			"-verbose":          complete.PredictNothing,
-			"-consul-token":     complete.PredictNothing,
-			"-consul-namespace": complete.PredictAnything,
-			"-vault-token":      complete.PredictAnything,
-			"-vault-namespace":  complete.PredictAnything,
-			"-output":           complete.PredictNothing,
-			"-policy-override":  complete.PredictNothing,
-			"-preserve-counts":  complete.PredictNothing,
-			"-json":             complete.PredictNothing,
-			"-hcl1":             complete.PredictNothing,
-			"-hcl2-strict":      complete.PredictNothing,
-			"-var":              complete.PredictAnything,
-			"-var-file":         complete.PredictFiles("*.var"),
-			"-eval-priority":    complete.PredictNothing,
+			"-check-index":       complete.PredictNothing,
+			"-detach":            complete.PredictNothing,
+			"-verbose":           complete.PredictNothing,
+			"-consul-token":      complete.PredictNothing,
+			"-consul-namespace":  complete.PredictAnything,
+			"-vault-token":       complete.PredictAnything,
+			"-vault-namespace":   complete.PredictAnything,
+			"-output":            complete.PredictNothing,
+			"-policy-override":   complete.PredictNothing,
+			"-preserve-counts":   complete.PredictNothing,
+			"-json":              complete.PredictNothing,
+			"-hcl1":              complete.PredictNothing,
+			"-hcl2-strict":       complete.PredictNothing,
+			"-strict":            complete.PredictNothing,
+			"-template":          jobTemplatePredictor(c.Client),
+			"-var":               complete.PredictAnything,
+			"-var-file":          complete.PredictFiles("*.var"),
+			"-eval-priority":     complete.PredictNothing,
+			"-idempotency-token": complete.PredictAnything,
 		})
 }
 
@@ -195,7 +220,8 @@ func (c *JobRunCommand) Name() string { return "job run" }
 
 func (c *JobRunCommand) Run(args []string) int {
 	var detach, verbose, output, override, preserveCounts bool
-	var checkIndexStr, consulToken, consulNamespace, vaultToken, vaultNamespace string
+	var checkIndexStr, consulToken, consulNamespace, vaultToken, vaultNamespace, template string
+	var idempotencyToken string
 	var evalPriority int
 
 	flagSet := c.Meta.FlagSet(c.Name(), FlagSetClient)
@@ -208,7 +234,10 @@ func (c *JobRunCommand) Run(args []string) int {
 	flagSet.BoolVar(&c.JobGetter.JSON, "json", false, "")
 	flagSet.BoolVar(&c.JobGetter.HCL1, "hcl1", false, "")
 	flagSet.BoolVar(&c.JobGetter.Strict, "hcl2-strict", true, "")
+	flagSet.BoolVar(&c.JobGetter.StrictFields, "strict", false, "")
 	flagSet.StringVar(&checkIndexStr, "check-index", "", "")
+	flagSet.StringVar(&template, "template", "", "")
+	flagSet.StringVar(&idempotencyToken, "idempotency-token", "", "")
 	flagSet.StringVar(&consulToken, "consul-token", "", "")
 	flagSet.StringVar(&consulNamespace, "consul-namespace", "", "")
 	flagSet.StringVar(&vaultToken, "vault-token", "", "")
@@ -227,9 +256,20 @@ func (c *JobRunCommand) Run(args []string) int {
 		length = fullId
 	}
 
-	// Check that we got exactly one argument
+	// Check that we got exactly one argument, or none if the job is rendered
+	// from a template.
 	args = flagSet.Args()
-	if len(args) != 1 {
+	if template != "" {
+		if len(args) != 0 {
+			c.Ui.Error("This command takes no arguments when -template is set")
+			c.Ui.Error(commandErrorText(c))
+			return 1
+		}
+		if c.JobGetter.JSON || c.JobGetter.HCL1 {
+			c.Ui.Error("The -json and -hcl1 flags cannot be used with -template")
+			return 1
+		}
+	} else if len(args) != 1 {
 		c.Ui.Error("This command takes one argument: <path>")
 		c.Ui.Error(commandErrorText(c))
 		return 1
@@ -244,8 +284,15 @@ func (c *JobRunCommand) Run(args []string) int {
 		return 1
 	}
 
-	// Get Job struct from Jobfile
-	sub, job, err := c.JobGetter.Get(args[0])
+	// Get Job struct from the job template or Jobfile
+	var sub *api.JobSubmission
+	var job *api.Job
+	var err error
+	if template != "" {
+		sub, job, err = c.renderJobTemplate(template)
+	} else {
+		sub, job, err = c.JobGetter.Get(args[0])
+	}
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error getting job struct: %s", err))
 		return 1
@@ -301,6 +348,16 @@ func (c *JobRunCommand) Run(args []string) int {
 		job.VaultNamespace = pointer.Of(vaultNamespace)
 	}
 
+	// Seal the sealed values of the job's tasks before they are output or
+	// submitted. The job source may contain their cleartext, so it is only
+	// submitted for jobs without sealed values.
+	if sealed, err := sealJob(client, job); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error sealing job: %s", err))
+		return 1
+	} else if sealed {
+		sub = nil
+	}
+
 	if output {
 		req := struct {
 			Job *api.Job
@@ -337,7 +394,9 @@ func (c *JobRunCommand) Run(args []string) int {
 	}
 
 	// Submit the job
-	resp, _, err := client.Jobs().RegisterOpts(job, opts, nil)
+	resp, _, err := client.Jobs().RegisterOpts(job, opts, &api.WriteOptions{
+		IdempotencyToken: idempotencyToken,
+	})
 	if err != nil {
 		if strings.Contains(err.Error(), api.RegisterEnforceIndexErrPrefix) {
 			// Format the error specially if the error is due to index
@@ -400,3 +459,30 @@ func parseCheckIndex(input string) (uint64, bool, error) {
 	u, err := strconv.ParseUint(input, 10, 64)
 	return u, true, err
 }
+
+// renderJobTemplate renders the job from the named job template, using the
+// values of the -var and -var-file flags and NOMAD_VAR_ environment variables.
+func (c *JobRunCommand) renderJobTemplate(name string) (*api.JobSubmission, *api.Job, error) {
+	variables, err := extractVarFiles(c.JobGetter.VarFiles)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to read var file(s): %w", err)
+	}
+
+	// Variables declared by -var flags take precedence over the environment.
+	varFlags := extractJobSpecEnvVars(os.Environ())
+	maps.Copy(varFlags, extractVarFlags(c.JobGetter.Vars))
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error initializing client: %w", err)
+	}
+
+	resp, _, err := client.JobTemplates().Render(name, &api.JobTemplateRenderRequest{
+		VariableFlags: varFlags,
+		Variables:     variables,
+	}, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error rendering job template %q: %w", name, err)
+	}
+	return resp.Submission, resp.Job, nil
+}
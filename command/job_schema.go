@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/jobspec2"
+	"github.com/posener/complete"
+)
+
+// JobSchemaCommand outputs the schema of the job specification as JSON.
+type JobSchemaCommand struct {
+	Meta
+}
+
+func (c *JobSchemaCommand) Help() string {
+	helpText := `
+Usage: nomad job schema [options]
+
+  Outputs the schema of the HCL2 job specification as JSON. The schema lists
+  the labels, attributes and nested blocks accepted by each block of a job
+  file, and is intended for editor and IDE integrations.
+
+  The schema is generated from the job specification of this Nomad binary and
+  does not require a running agent.
+
+Schema Options:
+
+  -compact
+    Outputs the schema without indentation.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobSchemaCommand) Synopsis() string {
+	return "Output the job specification schema as JSON"
+}
+
+func (c *JobSchemaCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-compact": complete.PredictNothing,
+	}
+}
+
+func (c *JobSchemaCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *JobSchemaCommand) Name() string { return "job schema" }
+
+func (c *JobSchemaCommand) Run(args []string) int {
+	var compact bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetNone)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&compact, "compact", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	var out []byte
+	var err error
+	if compact {
+		out, err = json.Marshal(jobspec2.Schema())
+	} else {
+		out, err = json.MarshalIndent(jobspec2.Schema(), "", "  ")
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error encoding job schema: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(string(out))
+	return 0
+}
@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/jobspec2"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobSchemaCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobSchemaCommand{}
+}
+
+func TestJobSchemaCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &JobSchemaCommand{Meta: Meta{Ui: ui}}
+
+	// Fails on misuse
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-compact"})
+	must.Zero(t, code)
+
+	var schema jobspec2.SchemaBlock
+	must.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &schema))
+	must.MapContainsKeys(t, schema.Blocks, []string{"job", "variable"})
+	must.Eq(t, []string{"name"}, schema.Blocks["job"].Labels)
+	must.MapContainsKey(t, schema.Blocks["job"].Blocks, "group")
+}
@@ -0,0 +1,52 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// sealJob replaces the cleartext sealed values of the job's tasks with values
+// sealed by the cluster keyring, so that the cleartext is never submitted to
+// the servers. It returns true if the job has sealed values, in which case the
+// job source must not be submitted either.
+func sealJob(client *api.Client, job *api.Job) (bool, error) {
+	hasSealed := false
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			if len(task.Sealed) == 0 {
+				continue
+			}
+			hasSealed = true
+
+			// Values that are already sealed, for example from the output of
+			// job inspect, are submitted as they are
+			values := make(map[string]string, len(task.Sealed))
+			for name, value := range task.Sealed {
+				if !structs.IsSealedValue(value) {
+					values[name] = value
+				}
+			}
+			if len(values) == 0 {
+				continue
+			}
+
+			sealed, _, err := client.Keyring().Seal(&api.KeyringSealRequest{
+				JobID:     *job.ID,
+				TaskGroup: *tg.Name,
+				Task:      task.Name,
+				Values:    values,
+			}, nil)
+			if err != nil {
+				return true, fmt.Errorf("failed to seal values of task %q: %w", task.Name, err)
+			}
+			maps.Copy(task.Sealed, sealed)
+		}
+	}
+	return hasSealed, nil
+}
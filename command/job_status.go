@@ -6,6 +6,7 @@ package command
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -410,6 +411,7 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 	if err := c.outputJobSummary(client, job); err != nil {
 		return err
 	}
+	c.outputArrayIndices(job, jobAllocs)
 
 	// Determine latest evaluation with failures whose follow up hasn't
 	// completed, this is done while formatting
@@ -462,6 +464,12 @@ func (c *JobStatusCommand) outputJobInfo(client *api.Client, job *api.Job) error
 	// Format the allocs
 	c.Ui.Output(c.Colorize().Color("\n[bold]Allocations[reset]"))
 	c.Ui.Output(formatAllocListStubs(jobAllocs, c.verbose, c.length))
+
+	// Format what the pending allocs are blocked on
+	if pending := formatPendingAllocs(jobAllocs, c.length); pending != "" {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Pending Allocations[reset]"))
+		c.Ui.Output(pending)
+	}
 	return nil
 }
 
@@ -535,6 +543,28 @@ func formatAllocListStubs(stubs []*api.AllocationListStub, verbose bool, uuidLen
 	return formatList(allocs)
 }
 
+// formatPendingAllocs formats the stage each pending allocation is blocked on,
+// or returns an empty string if no pending allocation reports one.
+func formatPendingAllocs(stubs []*api.AllocationListStub, uuidLength int) string {
+	rows := []string{"ID|Task|Stage|Reason"}
+	for _, alloc := range stubs {
+		reason := alloc.PendingReason
+		if reason == nil || alloc.ClientStatus != api.AllocClientStatusPending {
+			continue
+		}
+		rows = append(rows, fmt.Sprintf("%s|%s|%s|%s",
+			limit(alloc.ID, uuidLength),
+			reason.Task,
+			reason.Stage,
+			formatPendingReasonMessage(reason)))
+	}
+
+	if len(rows) == 1 {
+		return ""
+	}
+	return formatList(rows)
+}
+
 func formatAllocList(allocations []*api.Allocation, verbose bool, uuidLength int) string {
 	if len(allocations) == 0 {
 		return "No allocations placed"
@@ -632,6 +662,110 @@ func (c *JobStatusCommand) outputJobSummary(client *api.Client, job *api.Job) er
 	return nil
 }
 
+// outputArrayIndices displays the status of each index of the job arrays in
+// the job, based on the most recent allocation of each index.
+func (c *JobStatusCommand) outputArrayIndices(job *api.Job, allocs []*api.AllocationListStub) {
+	var arrays []*api.TaskGroup
+	for _, tg := range job.TaskGroups {
+		if tg.Array != nil {
+			arrays = append(arrays, tg)
+		}
+	}
+	if len(arrays) == 0 {
+		return
+	}
+
+	// Find the most recent allocation of each index
+	latest := make(map[string]map[int]*api.AllocationListStub)
+	for _, alloc := range allocs {
+		idx, ok := allocNameIndex(alloc.Name)
+		if !ok {
+			continue
+		}
+		byIndex, ok := latest[alloc.TaskGroup]
+		if !ok {
+			byIndex = make(map[int]*api.AllocationListStub)
+			latest[alloc.TaskGroup] = byIndex
+		}
+		if prev, ok := byIndex[idx]; !ok || prev.CreateIndex < alloc.CreateIndex {
+			byIndex[idx] = alloc
+		}
+	}
+
+	summaries := []string{"Task Group|Concurrency|Pending|Running|Complete|Failed"}
+	var failures []string
+	for _, tg := range arrays {
+		var pending, running, complete int
+		var failed []int
+		for idx := 0; idx < *tg.Array.Count; idx++ {
+			alloc, ok := latest[*tg.Name][idx]
+			switch {
+			case !ok || alloc.ClientStatus == api.AllocClientStatusPending ||
+				alloc.ClientStatus == api.AllocClientStatusLost:
+				pending++
+			case alloc.ClientStatus == api.AllocClientStatusComplete:
+				complete++
+			case alloc.ClientStatus == api.AllocClientStatusFailed:
+				failed = append(failed, idx)
+			default:
+				running++
+			}
+		}
+
+		concurrency := "unlimited"
+		if *tg.Array.Concurrency > 0 {
+			concurrency = strconv.Itoa(*tg.Array.Concurrency)
+		}
+		summaries = append(summaries, fmt.Sprintf("%s|%s|%d|%d|%d|%d",
+			*tg.Name, concurrency, pending, running, complete, len(failed)))
+		if len(failed) > 0 {
+			failures = append(failures, fmt.Sprintf("%s|%s", *tg.Name, formatIndexRanges(failed)))
+		}
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Array Indices[reset]"))
+	c.Ui.Output(formatList(summaries))
+
+	if len(failures) > 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Failed Array Indices[reset]"))
+		c.Ui.Output(formatList(append([]string{"Task Group|Indices"}, failures...)))
+		c.Ui.Output(fmt.Sprintf("\nRetry the failed indices with \"nomad job eval -force-reschedule %s\"", *job.ID))
+	}
+}
+
+// allocNameIndex returns the index from an allocation name of the form
+// "<job>.<group>[<index>]".
+func allocNameIndex(name string) (int, bool) {
+	l := strings.LastIndexByte(name, '[')
+	if l == -1 || !strings.HasSuffix(name, "]") {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(name[l+1 : len(name)-1])
+	if err != nil {
+		return 0, false
+	}
+	return idx, true
+}
+
+// formatIndexRanges formats a sorted list of indices as a compact list of
+// ranges, such as "1,3-5,9".
+func formatIndexRanges(indices []int) string {
+	var ranges []string
+	for i := 0; i < len(indices); {
+		j := i
+		for j+1 < len(indices) && indices[j+1] == indices[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(indices[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", indices[i], indices[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ",")
+}
+
 // outputReschedulingEvals displays eval IDs and time for any
 // delayed evaluations by task group
 func (c *JobStatusCommand) outputReschedulingEvals(client *api.Client, job *api.Job, allocListStubs []*api.AllocationListStub, uuidLength int) error {
@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/command/agent"
+	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
@@ -490,6 +491,73 @@ namespace "default" {
 	}
 }
 
+func TestJobStatusCommand_ArrayIndices(t *testing.T) {
+	ci.Parallel(t)
+
+	ui := cli.NewMockUi()
+	cmd := &JobStatusCommand{Meta: Meta{Ui: ui}}
+
+	job := &api.Job{
+		ID: pointer.Of("example"),
+		TaskGroups: []*api.TaskGroup{{
+			Name: pointer.Of("work"),
+			Array: &api.ArrayConfig{
+				Count:       pointer.Of(8),
+				Concurrency: pointer.Of(2),
+			},
+		}},
+	}
+	allocs := []*api.AllocationListStub{
+		{Name: "example.work[0]", TaskGroup: "work", ClientStatus: "complete", CreateIndex: 1},
+		{Name: "example.work[1]", TaskGroup: "work", ClientStatus: "failed", CreateIndex: 2},
+		{Name: "example.work[2]", TaskGroup: "work", ClientStatus: "failed", CreateIndex: 3},
+		{Name: "example.work[3]", TaskGroup: "work", ClientStatus: "failed", CreateIndex: 4},
+		{Name: "example.work[3]", TaskGroup: "work", ClientStatus: "complete", CreateIndex: 5},
+		{Name: "example.work[4]", TaskGroup: "work", ClientStatus: "running", CreateIndex: 6},
+		{Name: "example.work[6]", TaskGroup: "work", ClientStatus: "failed", CreateIndex: 7},
+	}
+
+	cmd.outputArrayIndices(job, allocs)
+	out := ui.OutputWriter.String()
+	must.RegexMatch(t, regexp.MustCompile(`work\s+2\s+2\s+1\s+2\s+3`), out)
+	must.RegexMatch(t, regexp.MustCompile(`work\s+1-2,6`), out)
+	must.StrContains(t, out, "nomad job eval -force-reschedule example")
+}
+
+func TestJobStatusCommand_formatIndexRanges(t *testing.T) {
+	ci.Parallel(t)
+
+	must.Eq(t, "", formatIndexRanges(nil))
+	must.Eq(t, "4", formatIndexRanges([]int{4}))
+	must.Eq(t, "0-2,5,7-8", formatIndexRanges([]int{0, 1, 2, 5, 7, 8}))
+}
+
+func TestJobStatusCommand_formatPendingAllocs(t *testing.T) {
+	ci.Parallel(t)
+
+	stubs := []*api.AllocationListStub{
+		{
+			ID:           "0d9c2d3e-49a0-4dfd-b3b8-3b2c8a3e1f3c",
+			ClientStatus: api.AllocClientStatusRunning,
+		},
+		{
+			ID:           "6b3f7e52-5d1c-4a3f-9d43-bd7f8c55a5e6",
+			ClientStatus: api.AllocClientStatusPending,
+			PendingReason: &api.AllocPendingReason{
+				Stage:   api.AllocPendingStageImagePull,
+				Task:    "web",
+				Message: "Downloading image",
+				Details: map[string]string{"image": "redis:7"},
+			},
+		},
+	}
+	must.Eq(t, "", formatPendingAllocs(stubs[:1], 8))
+
+	out := formatPendingAllocs(stubs, 8)
+	must.StrContains(t, out, "ID        Task  Stage       Reason")
+	must.StrContains(t, out, "6b3f7e52  web   image-pull  Downloading image (image=redis:7)")
+}
+
 func waitForSuccess(ui cli.Ui, client *api.Client, length int, t *testing.T, evalId string) int {
 	mon := newMonitor(ui, client, length)
 	monErr := mon.monitor(evalId)
@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type JobTemplateCommand struct {
+	Meta
+}
+
+func (c *JobTemplateCommand) Name() string {
+	return "job template"
+}
+
+func (c *JobTemplateCommand) Synopsis() string {
+	return "Interact with job templates"
+}
+
+func (c *JobTemplateCommand) Help() string {
+	helpText := `
+Usage: nomad job template <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with job templates. Job
+  templates are parameterized HCL2 jobspecs registered by operators. Their
+  variable blocks declare the parameters users provide to run jobs from the
+  template with "nomad job run -template".
+
+  Create or update a job template:
+
+    $ nomad job template apply <name> <path>
+
+  List all job templates:
+
+    $ nomad job template list
+
+  Fetch information on an existing job template:
+
+    $ nomad job template info <name>
+
+  Delete a job template:
+
+    $ nomad job template delete <name>
+
+  Please refer to individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobTemplateCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func formatJobTemplateList(templates []*api.JobTemplateListStub) string {
+	out := make([]string, len(templates)+1)
+	out[0] = "Name|Description"
+	for i, t := range templates {
+		out[i+1] = fmt.Sprintf("%s|%s",
+			t.Name,
+			t.Description,
+		)
+	}
+	return formatList(out)
+}
+
+func jobTemplatePredictor(factory ApiClientFactory) complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := factory()
+		if err != nil {
+			return nil
+		}
+
+		templates, _, err := client.JobTemplates().PrefixList(a.Last, nil)
+		if err != nil {
+			return nil
+		}
+
+		names := make([]string, len(templates))
+		for i, t := range templates {
+			names[i] = t.Name
+		}
+		return names
+	})
+}
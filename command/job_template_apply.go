@@ -0,0 +1,120 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type JobTemplateApplyCommand struct {
+	Meta
+}
+
+func (c *JobTemplateApplyCommand) Name() string {
+	return "job template apply"
+}
+
+func (c *JobTemplateApplyCommand) Synopsis() string {
+	return "Create or update a job template"
+}
+
+func (c *JobTemplateApplyCommand) Help() string {
+	helpText := `
+Usage: nomad job template apply [options] <name> <path>
+
+  Apply is used to create or update a job template. The template is read from
+  the HCL2 jobspec at the given path. If the path is "-" the template is read
+  from stdin.
+
+  The variable blocks of the jobspec declare the parameters of the template,
+  with their types, defaults, and validation rules. Variables without a default
+  must be set when running a job from the template.
+
+  If ACLs are enabled, this command requires a token with the 'write'
+  capability in a 'job_template' policy that matches the template name.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Apply Options:
+
+  -description
+    A description of the job template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobTemplateApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-description": complete.PredictAnything,
+		})
+}
+
+func (c *JobTemplateApplyCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictOr(
+		complete.PredictFiles("*.nomad"),
+		complete.PredictFiles("*.hcl"),
+	)
+}
+
+func (c *JobTemplateApplyCommand) Run(args []string) int {
+	var description string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&description, "description", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got the name and path.
+	args = flags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("This command takes two arguments: <name> <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	name, path := args[0], args[1]
+
+	// Read the template source.
+	var source []byte
+	var err error
+	if path == "-" {
+		source, err = io.ReadAll(os.Stdin)
+	} else {
+		source, err = os.ReadFile(path)
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error reading job template: %s", err))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	tmpl := &api.JobTemplate{
+		Name:        name,
+		Description: description,
+		Source:      string(source),
+	}
+	if _, err := client.JobTemplates().Register(tmpl, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error applying job template: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully applied job template %q!", name))
+	return 0
+}
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type JobTemplateDeleteCommand struct {
+	Meta
+}
+
+func (c *JobTemplateDeleteCommand) Name() string {
+	return "job template delete"
+}
+
+func (c *JobTemplateDeleteCommand) Synopsis() string {
+	return "Delete a job template"
+}
+
+func (c *JobTemplateDeleteCommand) Help() string {
+	helpText := `
+Usage: nomad job template delete [options] <name>
+
+  Delete is used to remove a job template. Jobs that were run from the template
+  are not affected.
+
+  If ACLs are enabled, this command requires a token with the 'delete'
+  capability in a 'job_template' policy that matches the template name.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault)
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobTemplateDeleteCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *JobTemplateDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return jobTemplatePredictor(c.Client)
+}
+
+func (c *JobTemplateDeleteCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we only have one argument.
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	name := args[0]
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if _, err := client.JobTemplates().Delete(name, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error deleting job template: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully deleted job template %q!", name))
+	return 0
+}
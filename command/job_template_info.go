@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type JobTemplateInfoCommand struct {
+	Meta
+}
+
+func (c *JobTemplateInfoCommand) Name() string {
+	return "job template info"
+}
+
+func (c *JobTemplateInfoCommand) Synopsis() string {
+	return "Fetch information on a job template"
+}
+
+func (c *JobTemplateInfoCommand) Help() string {
+	helpText := `
+Usage: nomad job template info [options] <name>
+
+  Info is used to fetch information about an existing job template, including
+  its source.
+
+  If ACLs are enabled, this command requires a token with the 'read' or 'run'
+  capability in a 'job_template' policy that matches the template name.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Info Options:
+
+  -json
+    Output the job template in its JSON format.
+
+  -t
+    Format and display the job template using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *JobTemplateInfoCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		})
+}
+
+func (c *JobTemplateInfoCommand) AutocompleteArgs() complete.Predictor {
+	return jobTemplatePredictor(c.Client)
+}
+
+func (c *JobTemplateInfoCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we only have one argument.
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	template, _, err := client.JobTemplates().Info(args[0], nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error retrieving job template: %s", err))
+		return 1
+	}
+
+	// Format output if requested.
+	if json || tmpl != "" {
+		out, err := Format(json, tmpl, template)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	basic := []string{
+		fmt.Sprintf("Name|%s", template.Name),
+		fmt.Sprintf("Description|%s", template.Description),
+		fmt.Sprintf("Create Index|%d", template.CreateIndex),
+		fmt.Sprintf("Modify Index|%d", template.ModifyIndex),
+	}
+	c.Ui.Output(formatKV(basic))
+
+	c.Ui.Output(c.Colorize().Color("\n[bold]Source[reset]"))
+	c.Ui.Output(strings.TrimSpace(template.Source))
+	return 0
+}
@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+const testJobTemplateSource = `
+variable "count" {
+  type    = number
+  default = 1
+}
+
+job "job1" {
+  type = "service"
+
+  group "group1" {
+    count = var.count
+
+    task "task1" {
+      driver = "mock_driver"
+
+      config {
+        run_for = "10ms"
+      }
+    }
+  }
+}
+`
+
+func TestJobTemplateCommands_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &JobTemplateCommand{}
+	var _ cli.Command = &JobTemplateApplyCommand{}
+	var _ cli.Command = &JobTemplateDeleteCommand{}
+	var _ cli.Command = &JobTemplateInfoCommand{}
+	var _ cli.Command = &JobTemplateListCommand{}
+}
+
+func TestJobTemplateCommands_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start test server.
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	waitForNodes(t, client)
+
+	path := filepath.Join(t.TempDir(), "web.nomad.hcl")
+	must.NoError(t, os.WriteFile(path, []byte(testJobTemplateSource), 0o644))
+
+	// Apply the template.
+	ui := cli.NewMockUi()
+	applyCmd := &JobTemplateApplyCommand{Meta: Meta{Ui: ui}}
+	code := applyCmd.Run([]string{"-address", url, "-description", "web service", "web", path})
+	must.Eq(t, 0, code, must.Sprint(ui.ErrorWriter.String()))
+	must.StrContains(t, ui.OutputWriter.String(), `Successfully applied job template "web"!`)
+
+	// List the templates.
+	ui = cli.NewMockUi()
+	listCmd := &JobTemplateListCommand{Meta: Meta{Ui: ui}}
+	code = listCmd.Run([]string{"-address", url})
+	must.Eq(t, 0, code)
+	must.StrContains(t, ui.OutputWriter.String(), "web service")
+
+	// Fetch the template.
+	ui = cli.NewMockUi()
+	infoCmd := &JobTemplateInfoCommand{Meta: Meta{Ui: ui}}
+	code = infoCmd.Run([]string{"-address", url, "web"})
+	must.Eq(t, 0, code)
+	must.StrContains(t, ui.OutputWriter.String(), `job "job1"`)
+
+	// Run a job from the template.
+	ui = cli.NewMockUi()
+	runCmd := &JobRunCommand{Meta: Meta{Ui: ui}}
+	code = runCmd.Run([]string{"-address", url, "-detach", "-template", "web", "-var", "count=2"})
+	must.Eq(t, 0, code, must.Sprint(ui.ErrorWriter.String()))
+
+	job, _, err := client.Jobs().Info("job1", nil)
+	must.NoError(t, err)
+	must.Eq(t, 2, *job.TaskGroups[0].Count)
+
+	sub, _, err := client.Jobs().Submission("job1", 0, nil)
+	must.NoError(t, err)
+	must.Eq(t, "2", sub.VariableFlags["count"])
+
+	// Paths cannot be set along with a template.
+	ui = cli.NewMockUi()
+	runCmd = &JobRunCommand{Meta: Meta{Ui: ui}}
+	code = runCmd.Run([]string{"-address", url, "-template", "web", path})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "takes no arguments when -template is set")
+
+	// Delete the template.
+	ui = cli.NewMockUi()
+	deleteCmd := &JobTemplateDeleteCommand{Meta: Meta{Ui: ui}}
+	code = deleteCmd.Run([]string{"-address", url, "web"})
+	must.Eq(t, 0, code)
+
+	ui = cli.NewMockUi()
+	infoCmd = &JobTemplateInfoCommand{Meta: Meta{Ui: ui}}
+	code = infoCmd.Run([]string{"-address", url, "web"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "not found")
+}
@@ -59,6 +59,11 @@ Validate Options:
     has been supplied which is not defined within the root variables. Defaults
     to true, but ignored if "-hcl1" is also defined.
 
+  -strict
+    Whether an error should be produced for unknown fields in JSON and HCL2
+    job files, including those the HCL2 parser would otherwise ignore. Defaults
+    to false.
+
   -vault-token
     Used to validate if the user submitting the job has permission to run the job
     according to its Vault policies. A Vault token must be supplied if the vault
@@ -92,6 +97,7 @@ func (c *JobValidateCommand) AutocompleteFlags() complete.Flags {
 	return complete.Flags{
 		"-hcl1":            complete.PredictNothing,
 		"-hcl2-strict":     complete.PredictNothing,
+		"-strict":          complete.PredictNothing,
 		"-vault-token":     complete.PredictAnything,
 		"-vault-namespace": complete.PredictAnything,
 		"-var":             complete.PredictAnything,
@@ -117,6 +123,7 @@ func (c *JobValidateCommand) Run(args []string) int {
 	flagSet.BoolVar(&c.JobGetter.JSON, "json", false, "")
 	flagSet.BoolVar(&c.JobGetter.HCL1, "hcl1", false, "")
 	flagSet.BoolVar(&c.JobGetter.Strict, "hcl2-strict", true, "")
+	flagSet.BoolVar(&c.JobGetter.StrictFields, "strict", false, "")
 	flagSet.StringVar(&vaultToken, "vault-token", "", "")
 	flagSet.StringVar(&vaultNamespace, "vault-namespace", "", "")
 	flagSet.Var(&c.JobGetter.Vars, "var", "")
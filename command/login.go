@@ -5,10 +5,12 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/cap/util"
 	"github.com/mitchellh/cli"
@@ -28,7 +30,9 @@ type LoginCommand struct {
 	authMethodType string // deprecated in 1.5.2, left for backwards compat
 	authMethodName string
 	callbackAddr   string
+	oidcDevice     bool
 	loginToken     string
+	loginTokenFile string
 
 	template string
 	json     bool
@@ -56,10 +60,20 @@ Login Options:
     The address to use for the local OIDC callback server. This should be given
     in the form of <IP>:<PORT> and defaults to "localhost:4649".
 
+  -oidc-device
+    Use the OIDC device authorization flow instead of opening a browser. The
+    command prints a URL and code to enter on another device, which is useful
+    on headless machines. The OIDC provider must support the device
+    authorization grant.
+
   -login-token
     Login token used for authentication that will be exchanged for a Nomad ACL
     Token. It is only required if using auth method type other than OIDC. 
 
+  -login-token-file
+    Path to a file containing the login token, such as the OIDC token written
+    by a CI system. Can't be used together with -login-token.
+
   -json
     Output the ACL token in JSON format.
 
@@ -79,7 +93,9 @@ func (l *LoginCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-method":             complete.PredictAnything,
 			"-oidc-callback-addr": complete.PredictAnything,
+			"-oidc-device":        complete.PredictNothing,
 			"-login-token":        complete.PredictAnything,
+			"-login-token-file":   complete.PredictFiles("*"),
 			"-json":               complete.PredictNothing,
 			"-t":                  complete.PredictAnything,
 		})
@@ -96,7 +112,9 @@ func (l *LoginCommand) Run(args []string) int {
 	flags.StringVar(&l.authMethodName, "method", "", "")
 	flags.StringVar(&l.authMethodType, "type", "", "")
 	flags.StringVar(&l.loginToken, "login-token", "", "")
+	flags.StringVar(&l.loginTokenFile, "login-token-file", "", "")
 	flags.StringVar(&l.callbackAddr, "oidc-callback-addr", "localhost:4649", "")
+	flags.BoolVar(&l.oidcDevice, "oidc-device", false, "")
 	flags.BoolVar(&l.json, "json", false, "")
 	flags.StringVar(&l.template, "t", "", "")
 	if err := flags.Parse(args); err != nil {
@@ -110,6 +128,19 @@ func (l *LoginCommand) Run(args []string) int {
 		return 1
 	}
 
+	if l.loginTokenFile != "" {
+		if l.loginToken != "" {
+			l.Ui.Error("Only one of -login-token and -login-token-file can be set")
+			return 1
+		}
+		tokenBytes, err := os.ReadFile(l.loginTokenFile)
+		if err != nil {
+			l.Ui.Error(fmt.Sprintf("Error reading login token file: %s", err))
+			return 1
+		}
+		l.loginToken = strings.TrimSpace(string(tokenBytes))
+	}
+
 	client, err := l.Meta.Client()
 	if err != nil {
 		l.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
@@ -178,6 +209,9 @@ func (l *LoginCommand) Run(args []string) int {
 	switch methodType {
 	case api.ACLAuthMethodTypeOIDC:
 		authFn = l.loginOIDC
+		if l.oidcDevice {
+			authFn = l.loginOIDCDevice
+		}
 	case api.ACLAuthMethodTypeJWT:
 		authFn = l.loginJWT
 	default:
@@ -262,6 +296,60 @@ func (l *LoginCommand) loginOIDC(ctx context.Context, client *api.Client) (*api.
 	return token, err
 }
 
+func (l *LoginCommand) loginOIDCDevice(ctx context.Context, client *api.Client) (*api.ACLToken, error) {
+
+	deviceAuth, _, err := client.ACLAuth().DeviceAuth(
+		&api.ACLOIDCDeviceAuthRequest{AuthMethodName: l.authMethodName}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Ui.Output(fmt.Sprintf(strings.TrimSpace(oidcDeviceVisitURLMsg)+"\n",
+		deviceAuth.VerificationURI, deviceAuth.UserCode))
+	if deviceAuth.VerificationURIComplete != "" {
+		l.Ui.Output(fmt.Sprintf("Alternatively, visit the following URL:\n\n%s\n",
+			deviceAuth.VerificationURIComplete))
+	}
+
+	var expired <-chan time.Time
+	if deviceAuth.ExpiresIn > 0 {
+		expiryTimer := time.NewTimer(deviceAuth.ExpiresIn)
+		defer expiryTimer.Stop()
+		expired = expiryTimer.C
+	}
+
+	// Poll until the user authorizes the device, the device code expires, or
+	// the user interrupts the login process via CTRL-C.
+	interval := deviceAuth.Interval
+	completeArgs := api.ACLOIDCCompleteDeviceAuthRequest{
+		AuthMethodName: l.authMethodName,
+		DeviceCode:     deviceAuth.DeviceCode,
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-expired:
+			return nil, errors.New("device code expired before the device was authorized")
+		case <-time.After(interval):
+		}
+
+		resp, _, err := client.ACLAuth().CompleteDeviceAuth(&completeArgs, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !resp.Pending {
+			return resp.ACLToken, nil
+		}
+
+		// The device authorization grant requires increasing the interval
+		// by five seconds when asked to slow down.
+		if resp.SlowDown {
+			interval += 5 * time.Second
+		}
+	}
+}
+
 func (l *LoginCommand) loginJWT(ctx context.Context, client *api.Client) (*api.ACLToken, error) {
 	authArgs := api.ACLLoginRequest{
 		AuthMethodName: l.authMethodName,
@@ -280,6 +368,16 @@ Automatic opening of the OIDC provider for login has failed. To complete the
 authentication, please visit your provider using the URL below:
 
 %s
+`
+
+	// oidcDeviceVisitURLMsg is a message to show users when performing the
+	// OIDC device authorization flow.
+	oidcDeviceVisitURLMsg = `
+To complete the authentication, visit the following URL on any device:
+
+%s
+
+and enter the code: %s
 `
 )
 
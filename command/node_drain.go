@@ -71,6 +71,12 @@ Node Drain Options:
     Ignore system allows the drain to complete without stopping system job
     allocations. By default system jobs are stopped last.
 
+  -csi-detach-timeout <duration>
+    Set how long to wait for the CSI volumes of drained allocations to be
+    unpublished from the node before forcing their claims to be released. By
+    default the drain waits until the volumes are unpublished or the deadline
+    is reached.
+
   -keep-ineligible
     Keep ineligible will maintain the node's scheduling ineligibility even if
     the drain is being disabled. This is useful when an existing drain is being
@@ -99,18 +105,19 @@ func (c *NodeDrainCommand) Synopsis() string {
 func (c *NodeDrainCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-disable":         complete.PredictNothing,
-			"-enable":          complete.PredictNothing,
-			"-deadline":        complete.PredictAnything,
-			"-detach":          complete.PredictNothing,
-			"-force":           complete.PredictNothing,
-			"-no-deadline":     complete.PredictNothing,
-			"-ignore-system":   complete.PredictNothing,
-			"-keep-ineligible": complete.PredictNothing,
-			"-m":               complete.PredictNothing,
-			"-meta":            complete.PredictNothing,
-			"-self":            complete.PredictNothing,
-			"-yes":             complete.PredictNothing,
+			"-disable":            complete.PredictNothing,
+			"-enable":             complete.PredictNothing,
+			"-deadline":           complete.PredictAnything,
+			"-detach":             complete.PredictNothing,
+			"-force":              complete.PredictNothing,
+			"-no-deadline":        complete.PredictNothing,
+			"-ignore-system":      complete.PredictNothing,
+			"-csi-detach-timeout": complete.PredictAnything,
+			"-keep-ineligible":    complete.PredictNothing,
+			"-m":                  complete.PredictNothing,
+			"-meta":               complete.PredictNothing,
+			"-self":               complete.PredictNothing,
+			"-yes":                complete.PredictNothing,
 		})
 }
 
@@ -135,7 +142,7 @@ func (c *NodeDrainCommand) Run(args []string) int {
 	var enable, disable, detach, force,
 		noDeadline, ignoreSystem, keepIneligible,
 		self, autoYes, monitor bool
-	var deadline, message string
+	var deadline, csiDetachTimeout, message string
 	var metaVars flaghelper.StringFlag
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
@@ -147,6 +154,7 @@ func (c *NodeDrainCommand) Run(args []string) int {
 	flags.BoolVar(&force, "force", false, "Force immediate drain")
 	flags.BoolVar(&noDeadline, "no-deadline", false, "Drain node with no deadline")
 	flags.BoolVar(&ignoreSystem, "ignore-system", false, "Do not drain system job allocations from the node")
+	flags.StringVar(&csiDetachTimeout, "csi-detach-timeout", "", "Time to wait for CSI volumes to be unpublished")
 	flags.BoolVar(&keepIneligible, "keep-ineligible", false, "Do not update the nodes scheduling eligibility")
 	flags.BoolVar(&self, "self", false, "")
 	flags.BoolVar(&autoYes, "yes", false, "Automatic yes to prompts.")
@@ -181,7 +189,7 @@ func (c *NodeDrainCommand) Run(args []string) int {
 	}
 
 	// Validate a compatible set of flags were set
-	if disable && (deadline != "" || force || noDeadline || ignoreSystem) {
+	if disable && (deadline != "" || force || noDeadline || ignoreSystem || csiDetachTimeout != "") {
 		c.Ui.Error("-disable can't be combined with flags configuring drain strategy")
 		c.Ui.Error(commandErrorText(c))
 		return 1
@@ -219,6 +227,22 @@ func (c *NodeDrainCommand) Run(args []string) int {
 		d = defaultDrainDuration
 	}
 
+	// Parse the CSI detach timeout
+	var detachTimeout time.Duration
+	if csiDetachTimeout != "" {
+		dur, err := time.ParseDuration(csiDetachTimeout)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to parse CSI detach timeout %q: %v", csiDetachTimeout, err))
+			return 1
+		}
+		if dur <= 0 {
+			c.Ui.Error("A positive CSI detach timeout must be given")
+			return 1
+		}
+
+		detachTimeout = dur
+	}
+
 	// Get the HTTP client
 	client, err := c.Meta.Client()
 	if err != nil {
@@ -311,6 +335,7 @@ func (c *NodeDrainCommand) Run(args []string) int {
 		spec = &api.DrainSpec{
 			Deadline:         d,
 			IgnoreSystemJobs: ignoreSystem,
+			CSIDetachTimeout: detachTimeout,
 		}
 	}
 
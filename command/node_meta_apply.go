@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/posener/complete"
 )
@@ -18,7 +19,7 @@ type NodeMetaApplyCommand struct {
 
 func (c *NodeMetaApplyCommand) Help() string {
 	helpText := `
-Usage: nomad node meta apply [-node-id ...] [-unset ...] key1=value1 ... kN=vN
+Usage: nomad node meta apply [-node-id ...] [-unset ...] [-expect ...] key1=value1 ... kN=vN
 
 	Modify a node's metadata. This command only applies to client agents, and can
 	be used to update the scheduling metadata the node registers.
@@ -39,8 +40,19 @@ Node Meta Apply Options:
   -unset key1,...,keyN
     Unset the comma separated list of keys.
 
+  -expect key=value
+    Only apply the changes if the key currently has the given value. May be
+    specified multiple times. If any expected value does not match, no changes
+    are applied and the command fails. This allows controllers to coordinate
+    on node metadata without overwriting each other's changes.
+
+  -expect-unset key1,...,keyN
+    Only apply the changes if the comma separated list of keys are currently
+    unset.
+
   Example:
     $ nomad node meta apply -unset testing,tempvar ready=1 role=preinit-db
+    $ nomad node meta apply -expect-unset maintenance maintenance=drain-123
 `
 	return strings.TrimSpace(helpText)
 }
@@ -52,12 +64,15 @@ func (c *NodeMetaApplyCommand) Synopsis() string {
 func (c *NodeMetaApplyCommand) Name() string { return "node meta apply" }
 
 func (c *NodeMetaApplyCommand) Run(args []string) int {
-	var unset, nodeID string
+	var unset, expectUnset, nodeID string
+	var expect flaghelper.StringFlag
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.StringVar(&unset, "unset", "", "")
 	flags.StringVar(&nodeID, "node-id", "", "")
+	flags.Var(&expect, "expect", "")
+	flags.StringVar(&expectUnset, "expect-unset", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -94,6 +109,11 @@ func (c *NodeMetaApplyCommand) Run(args []string) int {
 		Meta:   meta,
 	}
 
+	if len(expect) > 0 || expectUnset != "" {
+		req.Expected = parseMapFromArgs(expect)
+		applyNodeMetaUnset(req.Expected, expectUnset)
+	}
+
 	if _, err := client.Nodes().Meta().Apply(&req, nil); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error applying dynamic node metadata: %s", err))
 		return 1
@@ -105,8 +125,10 @@ func (c *NodeMetaApplyCommand) Run(args []string) int {
 func (c *NodeMetaApplyCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
 		complete.Flags{
-			"-node-id": complete.PredictNothing,
-			"-unset":   complete.PredictNothing,
+			"-node-id":      complete.PredictNothing,
+			"-unset":        complete.PredictNothing,
+			"-expect":       complete.PredictAnything,
+			"-expect-unset": complete.PredictNothing,
 		})
 }
 
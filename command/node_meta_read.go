@@ -4,10 +4,12 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
 
+	"github.com/hashicorp/nomad/api"
 	"github.com/posener/complete"
 )
 
@@ -17,7 +19,7 @@ type NodeMetaReadCommand struct {
 
 func (c *NodeMetaReadCommand) Help() string {
 	helpText := `
-Usage: nomad node meta read [-json] [-node-id ...]
+Usage: nomad node meta read [-json] [-node-id ...] [-watch]
 
   Read a node's metadata. This command only works on client agents. The node
   status command can be used to retrieve node metadata from any agent.
@@ -41,6 +43,10 @@ Node Meta Options:
   -json
     Output the node metadata in its JSON format.
 
+  -watch
+    Output the node metadata again every time it changes, until interrupted.
+    Changes are observed as soon as they are applied on the node.
+
   -t
     Format and display node metadata using a Go template.
 
@@ -58,13 +64,14 @@ func (c *NodeMetaReadCommand) Name() string { return "node meta read" }
 
 func (c *NodeMetaReadCommand) Run(args []string) int {
 	var nodeID, tmpl string
-	var json bool
+	var json, watch bool
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.StringVar(&nodeID, "node-id", "", "")
 	flags.StringVar(&tmpl, "t", "", "")
 	flags.BoolVar(&json, "json", false, "")
+	flags.BoolVar(&watch, "watch", false, "")
 
 	if err := flags.Parse(args); err != nil {
 		return 1
@@ -92,6 +99,27 @@ func (c *NodeMetaReadCommand) Run(args []string) int {
 		return 1
 	}
 
+	if code := c.outputMeta(meta, json, tmpl); code != 0 || !watch {
+		return code
+	}
+
+	// Watch for changes after the metadata already output
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q := &api.QueryOptions{WaitIndex: meta.Index}
+	for meta := range client.Nodes().Meta().Watch(ctx, nodeID, q) {
+		if !json && len(tmpl) == 0 {
+			c.Ui.Output("")
+		}
+		if code := c.outputMeta(meta, json, tmpl); code != 0 {
+			return code
+		}
+	}
+	return 0
+}
+
+func (c *NodeMetaReadCommand) outputMeta(meta *api.NodeMetaResponse, json bool, tmpl string) int {
 	if json || len(tmpl) > 0 {
 		out, err := Format(json, tmpl, meta)
 		if err != nil {
@@ -137,6 +165,7 @@ func (c *NodeMetaReadCommand) AutocompleteFlags() complete.Flags {
 			"-node-id": complete.PredictAnything,
 			"-json":    complete.PredictNothing,
 			"-t":       complete.PredictAnything,
+			"-watch":   complete.PredictNothing,
 		})
 }
 
@@ -37,6 +37,8 @@ type NodeStatusCommand struct {
 	list_allocs bool
 	self        bool
 	stats       bool
+	hardware    bool
+	heatmap     bool
 	json        bool
 	perPage     int
 	pageToken   string
@@ -72,6 +74,15 @@ Node Status Options:
   -stats
     Display detailed resource usage statistics.
 
+  -hardware
+    Display the hardware inventory of the node: its CPU, memory, disks,
+    network interfaces, devices and NUMA layout.
+
+  -heatmap
+    Display a heatmap of the allocation density of the nodes of each node
+    pool instead of the list of nodes. The density of a node is the highest
+    share of its CPU or memory allocated to running allocations.
+
   -allocs
     Display a count of running allocations for each node.
 
@@ -115,6 +126,8 @@ func (c *NodeStatusCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-allocs":     complete.PredictNothing,
 			"-filter":     complete.PredictAnything,
+			"-hardware":   complete.PredictNothing,
+			"-heatmap":    complete.PredictNothing,
 			"-json":       complete.PredictNothing,
 			"-per-page":   complete.PredictAnything,
 			"-page-token": complete.PredictAnything,
@@ -156,6 +169,8 @@ func (c *NodeStatusCommand) Run(args []string) int {
 	flags.BoolVar(&c.list_allocs, "allocs", false, "")
 	flags.BoolVar(&c.self, "self", false, "")
 	flags.BoolVar(&c.stats, "stats", false, "")
+	flags.BoolVar(&c.hardware, "hardware", false, "")
+	flags.BoolVar(&c.heatmap, "heatmap", false, "")
 	flags.BoolVar(&c.json, "json", false, "")
 	flags.StringVar(&c.tmpl, "t", "", "")
 	flags.StringVar(&c.filter, "filter", "", "")
@@ -193,6 +208,10 @@ func (c *NodeStatusCommand) Run(args []string) int {
 			c.Ui.Error("-quiet cannot be used with -verbose or -json")
 			return 1
 		}
+		if c.heatmap && (c.quiet || c.json || len(c.tmpl) > 0) {
+			c.Ui.Error("-heatmap cannot be used with -quiet, -json or -t")
+			return 1
+		}
 
 		// Set up the options to capture any filter passed and pagination
 		// details.
@@ -208,6 +227,10 @@ func (c *NodeStatusCommand) Run(args []string) int {
 			opts.Params = map[string]string{"os": "true"}
 		}
 
+		if c.heatmap {
+			return c.outputHeatmap(client, &opts)
+		}
+
 		// Query the node info
 		nodes, qm, err := client.Nodes().List(&opts)
 		if err != nil {
@@ -442,6 +465,23 @@ func nodeNetworkNames(n *api.Node) []string {
 	return networks
 }
 
+// formatDynamicPortRange formats the dynamic port range of a host network,
+// which is empty if the host network uses the node's range.
+func formatDynamicPortRange(info *api.HostNetworkInfo) string {
+	if info.MinDynamicPort == 0 && info.MaxDynamicPort == 0 {
+		return ""
+	}
+
+	minPort, maxPort := "", ""
+	if info.MinDynamicPort != 0 {
+		minPort = strconv.Itoa(info.MinDynamicPort)
+	}
+	if info.MaxDynamicPort != 0 {
+		maxPort = strconv.Itoa(info.MaxDynamicPort)
+	}
+	return minPort + "-" + maxPort
+}
+
 func formatDrain(n *api.Node) string {
 	if n.DrainStrategy != nil {
 		b := new(strings.Builder)
@@ -540,6 +580,15 @@ func (c *NodeStatusCommand) formatNode(client *api.Client, node *api.Node) int {
 		c.outputNodeDriverInfo(node)
 	}
 
+	if c.hardware {
+		hw, _, err := client.Nodes().Hardware(node.ID, nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying node hardware: %s", err))
+			return 1
+		}
+		c.outputNodeHardware(hw)
+	}
+
 	// Emit node events
 	c.outputNodeStatusEvents(node)
 
@@ -650,13 +699,14 @@ func (c *NodeStatusCommand) outputNodeNetworkInfo(node *api.Node) {
 	sort.Strings(names)
 
 	output := make([]string, 0, len(names)+1)
-	output = append(output, "Name|CIDR|Interface|ReservedPorts")
+	output = append(output, "Name|CIDR|Interface|ReservedPorts|DynamicPorts")
 
 	if len(names) > 0 {
 		c.Ui.Output(c.Colorize().Color("\n[bold]Host Networks"))
 		for _, hostNetworkName := range names {
 			info := node.HostNetworks[hostNetworkName]
-			output = append(output, fmt.Sprintf("%s|%v|%s|%s", hostNetworkName, info.CIDR, info.Interface, info.ReservedPorts))
+			output = append(output, fmt.Sprintf("%s|%v|%s|%s|%s", hostNetworkName,
+				info.CIDR, info.Interface, info.ReservedPorts, formatDynamicPortRange(info)))
 		}
 		c.Ui.Output(formatList(output))
 	}
@@ -724,6 +774,70 @@ func (c *NodeStatusCommand) outputNodeCSIVolumeInfo(client *api.Client, node *ap
 	}
 }
 
+func (c *NodeStatusCommand) outputNodeHardware(hw *api.NodeHardware) {
+	c.Ui.Output(c.Colorize().Color("\n[bold]Hardware[reset]"))
+	cores := strconv.Itoa(hw.CPU.Cores)
+	if hw.CPU.EfficiencyCores > 0 {
+		cores = fmt.Sprintf("%d (%d performance, %d efficiency)",
+			hw.CPU.Cores, hw.CPU.PerformanceCores, hw.CPU.EfficiencyCores)
+	}
+	c.Ui.Output(formatKV([]string{
+		fmt.Sprintf("CPU Model|%s", hw.CPU.ModelName),
+		fmt.Sprintf("CPU Sockets|%d", hw.CPU.Sockets),
+		fmt.Sprintf("CPU Cores|%s", cores),
+		fmt.Sprintf("CPU Compute|%d MHz", hw.CPU.TotalCompute),
+		fmt.Sprintf("Memory|%s", humanize.IBytes(uint64(hw.MemoryMB)*bytesPerMegabyte)),
+	}))
+
+	if len(hw.Disks) > 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Disks[reset]"))
+		out := []string{"Volume|Size|Free"}
+		for _, disk := range hw.Disks {
+			out = append(out, fmt.Sprintf("%s|%s|%s", disk.Volume,
+				humanize.IBytes(disk.TotalBytes), humanize.IBytes(disk.FreeBytes)))
+		}
+		c.Ui.Output(formatList(out))
+	}
+
+	if len(hw.NICs) > 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Network Interfaces[reset]"))
+		out := []string{"Device|MAC Address|Speed|Addresses"}
+		for _, nic := range hw.NICs {
+			speed := "<unknown>"
+			if nic.SpeedMbits > 0 {
+				speed = fmt.Sprintf("%d Mbit/s", nic.SpeedMbits)
+			}
+			out = append(out, fmt.Sprintf("%s|%s|%s|%s", nic.Device, nic.MacAddress,
+				speed, strings.Join(nic.Addresses, ",")))
+		}
+		c.Ui.Output(formatList(out))
+	}
+
+	if len(hw.Devices) > 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]Devices[reset]"))
+		out := []string{"Device Group|Instances|Healthy"}
+		for _, device := range hw.Devices {
+			out = append(out, fmt.Sprintf("%s/%s/%s|%d|%d",
+				device.Vendor, device.Type, device.Name, device.Instances, device.Healthy))
+		}
+		c.Ui.Output(formatList(out))
+	}
+
+	if len(hw.NUMA) > 0 {
+		c.Ui.Output(c.Colorize().Color("\n[bold]NUMA Nodes[reset]"))
+		out := []string{"ID|Cores|Compute|Distances"}
+		for _, node := range hw.NUMA {
+			distances := make([]string, 0, len(node.Distances))
+			for _, d := range node.Distances {
+				distances = append(distances, strconv.Itoa(int(d)))
+			}
+			out = append(out, fmt.Sprintf("%d|%s|%d MHz|%s",
+				node.ID, node.Cores, node.Compute, strings.Join(distances, " ")))
+		}
+		c.Ui.Output(formatList(out))
+	}
+}
+
 func (c *NodeStatusCommand) outputNodeDriverInfo(node *api.Node) {
 	c.Ui.Output(c.Colorize().Color("\n[bold]Drivers"))
 
@@ -1049,3 +1163,170 @@ func formatNodeStubList(nodes []*api.NodeListStub, verbose bool) string {
 
 	return formatList(out)
 }
+
+const (
+	// heatmapWidth is the number of nodes in a row of the heatmap.
+	heatmapWidth = 32
+
+	// heatmapNotReady is the cell of nodes that are not ready.
+	heatmapNotReady = "x"
+)
+
+// heatmapShades are the cells of the heatmap for increasing allocation
+// density, each covering an equal share of the density range.
+var heatmapShades = []string{"·", "░", "▒", "▓", "█"}
+
+// nodeDensity is the share of the allocatable resources of a node allocated
+// to running allocations.
+type nodeDensity struct {
+	Node   *api.NodeListStub
+	CPU    float64
+	Memory float64
+}
+
+// Max returns the highest share of the resources of the node allocated.
+func (d *nodeDensity) Max() float64 {
+	return math.Max(d.CPU, d.Memory)
+}
+
+// outputHeatmap outputs the heatmap of allocation density of the nodes
+// matching the list options, grouped by node pool.
+func (c *NodeStatusCommand) outputHeatmap(client *api.Client, opts *api.QueryOptions) int {
+	if opts.Params == nil {
+		opts.Params = map[string]string{}
+	}
+	opts.Params["resources"] = "true"
+
+	nodes, _, err := client.Nodes().List(opts)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying node status: %s", err))
+		return 1
+	}
+	if len(nodes) == 0 {
+		c.Ui.Output("No nodes registered")
+		return 0
+	}
+
+	allocs, _, err := client.Allocations().List(&api.QueryOptions{
+		Namespace: api.AllNamespacesNamespace,
+		Params:    map[string]string{"resources": "true"},
+	})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying allocations: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(formatHeatmap(nodeDensities(nodes, allocs)))
+	return 0
+}
+
+// nodeDensities returns the allocation density of the nodes, grouped by node
+// pool and sorted by node name.
+func nodeDensities(nodes []*api.NodeListStub, allocs []*api.AllocationListStub) map[string][]*nodeDensity {
+	type allocated struct {
+		cpu, memory int64
+	}
+	usage := make(map[string]*allocated, len(nodes))
+	for _, alloc := range allocs {
+		if alloc.DesiredStatus != api.AllocDesiredStatusRun || alloc.AllocatedResources == nil {
+			continue
+		}
+		if alloc.ClientStatus != api.AllocClientStatusRunning &&
+			alloc.ClientStatus != api.AllocClientStatusPending {
+			continue
+		}
+		u, ok := usage[alloc.NodeID]
+		if !ok {
+			u = &allocated{}
+			usage[alloc.NodeID] = u
+		}
+		for _, task := range alloc.AllocatedResources.Tasks {
+			u.cpu += task.Cpu.CpuShares
+			u.memory += task.Memory.MemoryMB
+		}
+	}
+
+	pools := make(map[string][]*nodeDensity)
+	for _, node := range nodes {
+		d := &nodeDensity{Node: node}
+		if u, ok := usage[node.ID]; ok && node.NodeResources != nil {
+			cpu := node.NodeResources.Cpu.CpuShares
+			memory := node.NodeResources.Memory.MemoryMB
+			if res := node.ReservedResources; res != nil {
+				cpu -= int64(res.Cpu.CpuShares)
+				memory -= int64(res.Memory.MemoryMB)
+			}
+			if cpu > 0 {
+				d.CPU = float64(u.cpu) / float64(cpu)
+			}
+			if memory > 0 {
+				d.Memory = float64(u.memory) / float64(memory)
+			}
+		}
+		pools[node.NodePool] = append(pools[node.NodePool], d)
+	}
+
+	for _, densities := range pools {
+		sort.Slice(densities, func(i, j int) bool {
+			return densities[i].Node.Name < densities[j].Node.Name
+		})
+	}
+	return pools
+}
+
+// heatmapCell returns the heatmap cell of the node.
+func heatmapCell(d *nodeDensity) string {
+	if d.Node.Status != api.NodeStatusReady {
+		return heatmapNotReady
+	}
+	i := int(d.Max() * float64(len(heatmapShades)))
+	return heatmapShades[max(0, min(i, len(heatmapShades)-1))]
+}
+
+// formatHeatmap returns the heatmap of the node pools with a row of cells for
+// every heatmapWidth nodes, followed by its legend.
+func formatHeatmap(pools map[string][]*nodeDensity) string {
+	names := make([]string, 0, len(pools))
+	for name := range pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		densities := pools[name]
+
+		var cpu, memory float64
+		var ready int
+		for _, d := range densities {
+			if d.Node.Status == api.NodeStatusReady {
+				cpu += d.CPU
+				memory += d.Memory
+				ready++
+			}
+		}
+		if ready > 0 {
+			cpu, memory = cpu/float64(ready), memory/float64(ready)
+		}
+
+		fmt.Fprintf(&b, "Node Pool %q: %d nodes, %d ready, average CPU %.0f%%, average memory %.0f%%\n",
+			name, len(densities), ready, cpu*100, memory*100)
+		for i, d := range densities {
+			b.WriteString(heatmapCell(d))
+			if (i+1)%heatmapWidth == 0 || i == len(densities)-1 {
+				b.WriteString("\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	step := 100 / len(heatmapShades)
+	legend := make([]string, 0, len(heatmapShades)+1)
+	for i, shade := range heatmapShades {
+		legend = append(legend, fmt.Sprintf("%s %d-%d%%", shade, i*step, (i+1)*step))
+	}
+	legend[len(legend)-1] = fmt.Sprintf("%s %d%%+", heatmapShades[len(heatmapShades)-1], 100-step)
+	legend = append(legend, fmt.Sprintf("%s not ready", heatmapNotReady))
+	b.WriteString("Legend: " + strings.Join(legend, "  "))
+	return b.String()
+}
@@ -295,3 +295,68 @@ func TestNodeStatusCommand_FormatDrain(t *testing.T) {
 	node.DrainStrategy.IgnoreSystemJobs = true
 	must.Eq(t, "true; 1970-01-01T00:00:01Z deadline; ignoring system jobs", formatDrain(node))
 }
+
+func TestNodeStatusCommand_Heatmap(t *testing.T) {
+	ci.Parallel(t)
+
+	node := func(id, pool, status string) *api.NodeListStub {
+		return &api.NodeListStub{
+			ID:       id,
+			Name:     id,
+			NodePool: pool,
+			Status:   status,
+			NodeResources: &api.NodeResources{
+				Cpu:    api.NodeCpuResources{CpuShares: 1100},
+				Memory: api.NodeMemoryResources{MemoryMB: 2048},
+			},
+			ReservedResources: &api.NodeReservedResources{
+				Cpu: api.NodeReservedCpuResources{CpuShares: 100},
+			},
+		}
+	}
+	alloc := func(nodeID, status string, cpu, memory int64) *api.AllocationListStub {
+		return &api.AllocationListStub{
+			NodeID:        nodeID,
+			DesiredStatus: api.AllocDesiredStatusRun,
+			ClientStatus:  status,
+			AllocatedResources: &api.AllocatedResources{
+				Tasks: map[string]*api.AllocatedTaskResources{
+					"web": {
+						Cpu:    api.AllocatedCpuResources{CpuShares: cpu},
+						Memory: api.AllocatedMemoryResources{MemoryMB: memory},
+					},
+				},
+			},
+		}
+	}
+
+	nodes := []*api.NodeListStub{
+		node("b", "default", api.NodeStatusReady),
+		node("a", "default", api.NodeStatusReady),
+		node("c", "default", api.NodeStatusDown),
+		node("d", "gpu", api.NodeStatusReady),
+	}
+	allocs := []*api.AllocationListStub{
+		alloc("a", api.AllocClientStatusRunning, 500, 256),
+		alloc("a", api.AllocClientStatusPending, 400, 256),
+		alloc("b", api.AllocClientStatusComplete, 1000, 2048),
+		alloc("d", api.AllocClientStatusRunning, 100, 1024),
+	}
+
+	pools := nodeDensities(nodes, allocs)
+	must.MapLen(t, 2, pools)
+	must.Len(t, 3, pools["default"])
+
+	a := pools["default"][0]
+	must.Eq(t, "a", a.Node.ID)
+	must.Eq(t, 0.9, a.CPU)
+	must.Eq(t, 0.25, a.Memory)
+	must.Eq(t, 0.9, a.Max())
+	must.Eq(t, 0.0, pools["default"][1].Max())
+	must.Eq(t, 0.5, pools["gpu"][0].Max())
+
+	out := formatHeatmap(pools)
+	must.StrContains(t, out, "Node Pool \"default\": 3 nodes, 2 ready, average CPU 45%, average memory 12%\n█·x\n")
+	must.StrContains(t, out, "Node Pool \"gpu\": 1 nodes, 1 ready, average CPU 10%, average memory 50%\n▒\n")
+	must.StrContains(t, out, "Legend: · 0-20%")
+}
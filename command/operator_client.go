@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorClientCommand struct {
+	Meta
+}
+
+func (c *OperatorClientCommand) Help() string {
+	helpText := `
+Usage: nomad operator client <subcommand> [options]
+
+  This command groups subcommands for inspecting and repairing the data
+  directory of Nomad clients.
+
+  Check the client state against the alloc dirs:
+
+      $ nomad operator client fsck /var/nomad/data
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorClientCommand) Synopsis() string {
+	return "Provides tools for the client data directory"
+}
+
+func (c *OperatorClientCommand) Name() string { return "operator client" }
+
+func (c *OperatorClientCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
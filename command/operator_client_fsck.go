@@ -0,0 +1,174 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/client/state"
+	"github.com/posener/complete"
+)
+
+type OperatorClientFsckCommand struct {
+	Meta
+}
+
+func (c *OperatorClientFsckCommand) Help() string {
+	helpText := `
+Usage: nomad operator client fsck [options] <path to nomad data dir>
+
+  Checks the client state store against the allocation directories in the data
+  directory. It finds allocations and task states that cannot be decoded,
+  allocations whose directory is missing, and allocation directories that do
+  not belong to any allocation. The same check is run, with -repair, every
+  time a client starts.
+
+  The command exits with status 2 if problems are found and not repaired.
+
+  This command requires file system permissions to access the data directory on
+  disk. The Nomad client locks access to the data directory, so this command
+  cannot be run on a data directory that is being used by a running Nomad
+  client.
+
+Options:
+
+  -repair
+    Repair the problems found. Invalid allocations are removed from the client
+    state, corrupt task states are removed so the tasks start over, and
+    orphaned allocation directories are moved to the ".quarantine" directory of
+    the alloc dir so they can be inspected.
+
+  -state-dir=<path>
+    The client state directory. Defaults to the "client" directory of the data
+    directory.
+
+  -alloc-dir=<path>
+    The client alloc directory. Defaults to the "alloc" directory of the data
+    directory.
+
+  -json
+    Output the results in JSON format.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorClientFsckCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-repair":    complete.PredictNothing,
+		"-state-dir": complete.PredictDirs("*"),
+		"-alloc-dir": complete.PredictDirs("*"),
+		"-json":      complete.PredictNothing,
+	}
+}
+
+func (c *OperatorClientFsckCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictDirs("*")
+}
+
+func (c *OperatorClientFsckCommand) Synopsis() string {
+	return "Check and repair the client data directory"
+}
+
+func (c *OperatorClientFsckCommand) Name() string { return "operator client fsck" }
+
+func (c *OperatorClientFsckCommand) Run(args []string) int {
+	var repair, json bool
+	var stateDir, allocDir string
+
+	flags := c.Meta.FlagSet(c.Name(), 0)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&repair, "repair", false, "")
+	flags.StringVar(&stateDir, "state-dir", "", "")
+	flags.StringVar(&allocDir, "alloc-dir", "", "")
+	flags.BoolVar(&json, "json", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if stateDir == "" {
+		stateDir = filepath.Join(args[0], "client")
+	}
+	if allocDir == "" {
+		allocDir = filepath.Join(args[0], "alloc")
+	}
+
+	logger := hclog.NewNullLogger()
+	db, err := state.NewBoltStateDB(logger, stateDir)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to open client state: %v", err))
+		return 1
+	}
+	defer db.Close()
+
+	report, err := state.Check(db, allocDir, repair, logger)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error checking client state: %v", err))
+		return 1
+	}
+
+	unrepaired := 0
+	for _, result := range report.Results {
+		if result.Repair == "" {
+			unrepaired++
+		}
+	}
+
+	if json {
+		out, err := Format(true, "", report)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+	} else {
+		c.Ui.Output(formatKV([]string{
+			fmt.Sprintf("State Dir|%s", stateDir),
+			fmt.Sprintf("Alloc Dir|%s", allocDir),
+			fmt.Sprintf("Allocations|%d", report.Allocs),
+			fmt.Sprintf("Alloc Dirs|%d", report.AllocDirs),
+		}))
+
+		if len(report.Results) > 0 {
+			rows := []string{"Alloc ID|Task|Problem|Detail|Repair"}
+			for _, result := range report.Results {
+				detail := result.Error
+				if detail == "" {
+					detail = result.Path
+				}
+				repairMsg := result.Repair
+				if result.RepairError != "" {
+					repairMsg = fmt.Sprintf("failed: %s", result.RepairError)
+				}
+				rows = append(rows, fmt.Sprintf("%s|%s|%s|%s|%s",
+					result.AllocID, result.Task, result.Problem, detail, repairMsg))
+			}
+			c.Ui.Output(c.Colorize().Color("\n[bold]Problems[reset]"))
+			c.Ui.Output(formatList(rows))
+		}
+
+		switch {
+		case unrepaired > 0 && !repair:
+			c.Ui.Output("\nRun with -repair to repair the problems found")
+		case len(report.Results) == 0:
+			c.Ui.Output("\nClient data directory verified successfully")
+		}
+	}
+
+	if unrepaired > 0 {
+		return 2
+	}
+	return 0
+}
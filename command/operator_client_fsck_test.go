@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client/state"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestOperatorClientFsckCommand(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &OperatorClientFsckCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.Eq(t, 1, code)
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	dir := t.TempDir()
+	stateDir := filepath.Join(dir, "client")
+	must.NoError(t, os.Mkdir(stateDir, 0o700))
+
+	// an empty data dir is valid
+	code = cmd.Run([]string{dir})
+	must.Eq(t, 0, code)
+	must.StrContains(t, ui.OutputWriter.String(), "verified successfully")
+	ui.OutputWriter.Reset()
+
+	// an allocation without an alloc dir is reported
+	db, err := state.NewBoltStateDB(testlog.HCLogger(t), stateDir)
+	must.NoError(t, err)
+	alloc := structs.MockAlloc()
+	must.NoError(t, db.PutAllocation(alloc))
+	must.NoError(t, db.Close())
+
+	code = cmd.Run([]string{dir})
+	must.Eq(t, 2, code)
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, alloc.ID)
+	must.StrContains(t, out, string(state.CheckProblemMissingAllocDir))
+	ui.OutputWriter.Reset()
+
+	// and repaired
+	code = cmd.Run([]string{"-repair", dir})
+	must.Eq(t, 0, code)
+	must.StrContains(t, ui.OutputWriter.String(), "removed allocation")
+	ui.OutputWriter.Reset()
+
+	code = cmd.Run([]string{dir})
+	must.Eq(t, 0, code)
+}
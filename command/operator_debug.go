@@ -725,6 +725,7 @@ func (c *OperatorDebugCommand) collect(client *api.Client) error {
 	c.collectVault(clusterDir, vaultAddr)
 
 	c.collectAgentHosts(client)
+	c.collectFlightRecorders(client)
 	c.collectPeriodicPprofs(client)
 
 	c.collectPeriodic(client)
@@ -939,6 +940,53 @@ func (c *OperatorDebugCommand) collectAgentHost(path, id string, client *api.Cli
 	c.reportErr(writeResponseToFile(host, c.newFile(path, "agent-host.json")))
 }
 
+// collectFlightRecorders calls collectFlightRecorder for each selected node
+func (c *OperatorDebugCommand) collectFlightRecorders(client *api.Client) {
+	for _, n := range c.nodeIDs {
+		c.collectFlightRecorder(clientDir, n, client)
+	}
+
+	for _, n := range c.serverIDs {
+		c.collectFlightRecorder(serverDir, n, client)
+	}
+}
+
+// collectFlightRecorder gets the agent flight recorder dump, which holds the
+// recent activity of the agent from before the capture started
+func (c *OperatorDebugCommand) collectFlightRecorder(path, id string, client *api.Client) {
+	var recorder *api.FlightRecorderResponse
+	var err error
+	if path == serverDir {
+		recorder, err = client.Agent().FlightRecorder(id, "", c.queryOpts())
+	} else {
+		recorder, err = client.Agent().FlightRecorder("", id, c.queryOpts())
+	}
+
+	if isRedirectError(err) {
+		c.Ui.Warn(fmt.Sprintf("%s/%s: /v1/agent/flight-recorder unavailable on this agent", path, id))
+		return
+	}
+
+	if err != nil {
+		// The flight recorder is disabled by default
+		if strings.Contains(err.Error(), "flight recorder is not enabled") {
+			c.verboseOutf("%s/%s: flight recorder is not enabled", path, id)
+			return
+		}
+
+		c.Ui.Error(fmt.Sprintf("%s/%s: Failed to retrieve flight recorder, err: %v", path, id, err))
+
+		if strings.Contains(err.Error(), api.PermissionDeniedErrorContent) {
+			// Drop a hint to help the operator resolve the error
+			c.Ui.Warn("Flight recorder retrieval requires agent:read ACL or enable_debug=true.  See https://developer.hashicorp.com/nomad/api-docs/agent#flight-recorder for more information.")
+		}
+		return
+	}
+
+	path = filepath.Join(path, id)
+	c.reportErr(writeResponseToFile(recorder, c.newFile(path, "flight-recorder.json")))
+}
+
 func (c *OperatorDebugCommand) collectPeriodicPprofs(client *api.Client) {
 
 	pprofNodeIDs := []string{}
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+)
+
+// Ensure OperatorMaintenanceCommand satisfies the cli.Command interface.
+var _ cli.Command = &OperatorMaintenanceCommand{}
+
+type OperatorMaintenanceCommand struct {
+	Meta
+}
+
+func (o *OperatorMaintenanceCommand) Help() string {
+	helpText := `
+Usage: nomad operator maintenance <subcommand> [options]
+
+  This command groups subcommands for managing the maintenance windows of the
+  nodes. The nodes of a maintenance window are drained when the window starts
+  and marked eligible for scheduling again when the window ends.
+
+  Drain the nodes of the "gpu" class for two hours tonight:
+
+      $ nomad operator maintenance create -node-class=gpu \
+          -start=2024-03-04T22:00:00Z -duration=2h
+
+  List the maintenance windows:
+
+      $ nomad operator maintenance list
+
+  Delete a maintenance window:
+
+      $ nomad operator maintenance delete <id>
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (o *OperatorMaintenanceCommand) Synopsis() string {
+	return "Manage the maintenance windows of nodes"
+}
+
+func (o *OperatorMaintenanceCommand) Name() string { return "operator maintenance" }
+
+func (o *OperatorMaintenanceCommand) Run(_ []string) int { return cli.RunResultHelp }
+
+func formatMaintenanceWindows(windows []*api.MaintenanceWindow, length int) string {
+	out := make([]string, len(windows)+1)
+	out[0] = "ID|Status|Start|End|Nodes|Node Classes|Description"
+	for i, w := range windows {
+		out[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s",
+			limit(w.ID, length),
+			w.Status,
+			formatTime(w.Start),
+			formatTime(w.End),
+			formatMaintenanceWindowList(w.NodeIDs, length),
+			formatMaintenanceWindowList(w.NodeClasses, 0),
+			w.Description,
+		)
+	}
+	return formatList(out)
+}
+
+func formatMaintenanceWindowList(values []string, length int) string {
+	if len(values) == 0 {
+		return "<none>"
+	}
+	if length > 0 {
+		short := make([]string, len(values))
+		for i, v := range values {
+			short[i] = limit(v, length)
+		}
+		values = short
+	}
+	return strings.Join(values, ",")
+}
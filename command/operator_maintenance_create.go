@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/posener/complete"
+)
+
+type OperatorMaintenanceCreateCommand struct {
+	Meta
+}
+
+func (c *OperatorMaintenanceCreateCommand) Name() string {
+	return "operator maintenance create"
+}
+
+func (c *OperatorMaintenanceCreateCommand) Synopsis() string {
+	return "Create a maintenance window"
+}
+
+func (c *OperatorMaintenanceCreateCommand) Help() string {
+	helpText := `
+Usage: nomad operator maintenance create [options]
+
+  Create is used to declare a maintenance window for a set of nodes. When the
+  window starts, the servers drain the nodes with the given deadline. When the
+  window ends, the nodes are marked eligible for scheduling again.
+
+  If ACLs are enabled, this command requires a token with the 'operator:write'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+Create Options:
+
+  -node=<id>
+    The ID of a node drained during the window. This flag may be repeated.
+
+  -node-class=<class>
+    The class of the nodes drained during the window. This flag may be
+    repeated.
+
+  -start=<time>
+    The time the window starts, in RFC 3339 format. Defaults to now.
+
+  -end=<time>
+    The time the window ends, in RFC 3339 format.
+
+  -duration=<duration>
+    The duration of the window, as an alternative to -end.
+
+  -deadline=<duration>
+    The deadline of the drain of the nodes, after which their remaining
+    allocations are stopped. Defaults to 1h. Zero means no deadline.
+
+  -ignore-system
+    Keep the allocations of system jobs running on the drained nodes.
+
+  -description=<description>
+    A human-friendly description of the window.
+
+  -json
+    Output the created maintenance window in JSON format.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorMaintenanceCreateCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-node":          complete.PredictAnything,
+			"-node-class":    complete.PredictAnything,
+			"-start":         complete.PredictAnything,
+			"-end":           complete.PredictAnything,
+			"-duration":      complete.PredictAnything,
+			"-deadline":      complete.PredictAnything,
+			"-ignore-system": complete.PredictNothing,
+			"-description":   complete.PredictAnything,
+			"-json":          complete.PredictNothing,
+		})
+}
+
+func (c *OperatorMaintenanceCreateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorMaintenanceCreateCommand) Run(args []string) int {
+	var nodeIDs, nodeClasses flaghelper.StringFlag
+	var start, end, description string
+	var duration, deadline time.Duration
+	var ignoreSystem, json bool
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.Var(&nodeIDs, "node", "")
+	flags.Var(&nodeClasses, "node-class", "")
+	flags.StringVar(&start, "start", "", "")
+	flags.StringVar(&end, "end", "", "")
+	flags.DurationVar(&duration, "duration", 0, "")
+	flags.DurationVar(&deadline, "deadline", time.Hour, "")
+	flags.BoolVar(&ignoreSystem, "ignore-system", false, "")
+	flags.StringVar(&description, "description", "", "")
+	flags.BoolVar(&json, "json", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we don't have any arguments.
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	window := &api.MaintenanceWindow{
+		Description:      description,
+		NodeIDs:          nodeIDs,
+		NodeClasses:      nodeClasses,
+		Deadline:         deadline,
+		IgnoreSystemJobs: ignoreSystem,
+	}
+	if len(window.NodeIDs) == 0 && len(window.NodeClasses) == 0 {
+		c.Ui.Error("At least one of -node or -node-class is required")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	window.Start = time.Now().UTC()
+	if start != "" {
+		t, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error parsing -start: %s", err))
+			return 1
+		}
+		window.Start = t
+	}
+	switch {
+	case end != "" && duration != 0:
+		c.Ui.Error("Only one of -end or -duration may be set")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	case end != "":
+		t, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error parsing -end: %s", err))
+			return 1
+		}
+		window.End = t
+	case duration > 0:
+		window.End = window.Start.Add(duration)
+	default:
+		c.Ui.Error("One of -end or -duration is required")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	created, _, err := client.Operator().UpsertMaintenanceWindow(window, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating maintenance window: %s", err))
+		return 1
+	}
+
+	if json {
+		out, err := Format(json, "", created)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error formatting output: %s", err))
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully created maintenance window %q!", created.ID))
+	return 0
+}
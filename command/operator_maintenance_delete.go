@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type OperatorMaintenanceDeleteCommand struct {
+	Meta
+}
+
+func (c *OperatorMaintenanceDeleteCommand) Name() string {
+	return "operator maintenance delete"
+}
+
+func (c *OperatorMaintenanceDeleteCommand) Synopsis() string {
+	return "Delete a maintenance window"
+}
+
+func (c *OperatorMaintenanceDeleteCommand) Help() string {
+	helpText := `
+Usage: nomad operator maintenance delete [options] <id>
+
+  Delete is used to remove a maintenance window. The ID may be a prefix of the
+  window ID. Deleting an active window ends it: the nodes it drained are marked
+  eligible for scheduling again.
+
+  If ACLs are enabled, this command requires a token with the 'operator:write'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace)
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorMaintenanceDeleteCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *OperatorMaintenanceDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorMaintenanceDeleteCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we only have one argument.
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	prefix := args[0]
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	// Resolve the ID prefix to a single window.
+	windows, _, err := client.Operator().MaintenanceWindows(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying maintenance windows: %s", err))
+		return 1
+	}
+	var matches []string
+	for _, w := range windows {
+		if strings.HasPrefix(w.ID, prefix) {
+			matches = append(matches, w.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		c.Ui.Error(fmt.Sprintf("No maintenance window with prefix %q found", prefix))
+		return 1
+	case 1:
+	default:
+		c.Ui.Error(fmt.Sprintf("Prefix %q matched multiple maintenance windows:\n\n%s",
+			prefix, strings.Join(matches, "\n")))
+		return 1
+	}
+
+	if _, err := client.Operator().DeleteMaintenanceWindow(matches[0], nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error deleting maintenance window: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully deleted maintenance window %q!", matches[0]))
+	return 0
+}
@@ -0,0 +1,122 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type OperatorMaintenanceListCommand struct {
+	Meta
+}
+
+func (c *OperatorMaintenanceListCommand) Name() string {
+	return "operator maintenance list"
+}
+
+func (c *OperatorMaintenanceListCommand) Synopsis() string {
+	return "List the maintenance windows of nodes"
+}
+
+func (c *OperatorMaintenanceListCommand) Help() string {
+	helpText := `
+Usage: nomad operator maintenance list [options]
+
+  List is used to list the maintenance windows of the nodes, sorted by start
+  time. Completed windows are listed until they are deleted.
+
+  If ACLs are enabled, this command requires a token with the 'operator:read'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault|usageOptsNoNamespace) + `
+
+List Options:
+
+  -json
+    Output the maintenance windows in JSON format.
+
+  -t
+    Format and display the maintenance windows using a Go template.
+
+  -verbose
+    Display full information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorMaintenanceListCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json":    complete.PredictNothing,
+			"-t":       complete.PredictAnything,
+			"-verbose": complete.PredictNothing,
+		})
+}
+
+func (c *OperatorMaintenanceListCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorMaintenanceListCommand) Run(args []string) int {
+	var json, verbose bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+	flags.BoolVar(&verbose, "verbose", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we don't have any arguments.
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	windows, _, err := client.Operator().MaintenanceWindows(nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error querying maintenance windows: %s", err))
+		return 1
+	}
+
+	// Format output if requested.
+	if json || tmpl != "" {
+		out, err := Format(json, tmpl, windows)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error formatting output: %s", err))
+			return 1
+		}
+
+		c.Ui.Output(out)
+		return 0
+	}
+
+	if len(windows) == 0 {
+		c.Ui.Output("No maintenance windows found")
+		return 0
+	}
+
+	length := shortId
+	if verbose {
+		length = fullId
+	}
+	c.Ui.Output(formatMaintenanceWindows(windows, length))
+	return 0
+}
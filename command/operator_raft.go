@@ -42,6 +42,14 @@ Usage: nomad operator raft <subcommand> [options]
 
       $ nomad operator raft state /var/nomad/data
 
+  Delete an object that prevents the server from restoring its raft snapshot:
+
+      $ nomad operator raft surgery delete -type=alloc -id=<id> /var/nomad/data
+
+  Verify the integrity of the raft logs and snapshots in the data directory:
+
+      $ nomad operator raft verify /var/nomad/data
+
   Please see the individual subcommand help for detailed usage information.
 
 
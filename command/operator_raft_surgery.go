@@ -0,0 +1,247 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/helper/raftutil"
+	"github.com/posener/complete"
+)
+
+type OperatorRaftSurgeryCommand struct {
+	Meta
+}
+
+func (c *OperatorRaftSurgeryCommand) Help() string {
+	helpText := `
+Usage: nomad operator raft surgery <action> [options] <path to nomad data dir>
+
+  Inspects and edits the objects in the newest raft snapshot of a server's data
+  directory, for disaster recovery when a bad object prevents the server from
+  restoring its state. The action is one of:
+
+    list    List the objects of a type with their encoded size.
+    dump    Display an object in JSON format.
+    delete  Delete an object. Deleting a job also deletes its versions,
+            summary, and submission.
+    patch   Apply a JSON patch to an object. Fields in the patch replace the
+            fields of the object, and nested objects are merged.
+
+  The snapshot records are edited without restoring the snapshot, so the
+  command works even if the server fails to restore it. The edited snapshot is
+  written as a new snapshot with the same index, which the server restores
+  instead of the original snapshot when it starts. Raft log entries after the
+  snapshot are applied on top of it, so edits to objects that are modified by
+  these entries may be overwritten.
+
+  Always stop every server and back up their data directories first. Edit the
+  data directory of one server and let the other servers recover from it, as
+  servers with diverging state cause data loss.
+
+  This command requires file system permissions to access the data directory on
+  disk. The Nomad server locks access to the data directory, so this command
+  cannot be run on a data directory that is being used by a running Nomad server.
+
+  This is a low-level debugging tool and not subject to Nomad's usual backward
+  compatibility guarantees.
+
+Options:
+
+  -type=<type>
+    The type of the objects: alloc, deployment, eval, job, or node. Required.
+
+  -namespace=<namespace>
+    The namespace of the object. Defaults to "default". The list action accepts
+    "*" to list the objects of all namespaces.
+
+  -id=<id>
+    The full ID of the object. Required for the dump, delete, and patch actions.
+
+  -patch=<path>
+    The path to the file holding the JSON patch, or "-" to read it from stdin.
+    Required for the patch action.
+
+  -dry-run
+    Display the changes of the delete and patch actions without writing them.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorRaftSurgeryCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-type":      complete.PredictSet(raftutil.SurgeryTypes()...),
+		"-namespace": complete.PredictAnything,
+		"-id":        complete.PredictAnything,
+		"-patch":     complete.PredictFiles("*.json"),
+		"-dry-run":   complete.PredictNothing,
+	}
+}
+
+func (c *OperatorRaftSurgeryCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFiles("*")
+}
+
+func (c *OperatorRaftSurgeryCommand) Synopsis() string {
+	return "Inspect and edit the raft snapshot of a stopped server"
+}
+
+func (c *OperatorRaftSurgeryCommand) Name() string { return "operator raft surgery" }
+
+func (c *OperatorRaftSurgeryCommand) Run(args []string) int {
+	if len(args) < 1 {
+		c.Ui.Error("This command takes two arguments: <action> <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	action := args[0]
+	if !slices.Contains([]string{"list", "dump", "delete", "patch"}, action) {
+		c.Ui.Error(fmt.Sprintf("Unknown action %q", action))
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	var objType, namespace, id, patchPath string
+	var dryRun bool
+
+	flags := c.Meta.FlagSet(c.Name(), 0)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&objType, "type", "", "")
+	flags.StringVar(&namespace, "namespace", "default", "")
+	flags.StringVar(&id, "id", "", "")
+	flags.StringVar(&patchPath, "patch", "", "")
+	flags.BoolVar(&dryRun, "dry-run", false, "")
+
+	if err := flags.Parse(args[1:]); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to parse args: %v", err))
+		return 1
+	}
+	args = flags.Args()
+
+	if len(args) != 1 {
+		c.Ui.Error("This command takes two arguments: <action> <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if !slices.Contains(raftutil.SurgeryTypes(), objType) {
+		c.Ui.Error(fmt.Sprintf("The -type flag must be one of: %s",
+			strings.Join(raftutil.SurgeryTypes(), ", ")))
+		return 1
+	}
+	if action != "list" && id == "" {
+		c.Ui.Error(fmt.Sprintf("The -id flag is required for the %s action", action))
+		return 1
+	}
+	if action == "patch" && patchPath == "" {
+		c.Ui.Error("The -patch flag is required for the patch action")
+		return 1
+	}
+
+	raftPath, err := raftutil.FindRaftDir(args[0])
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	surgery, err := raftutil.NewSnapshotSurgery(raftPath)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error opening snapshot: %v", err))
+		return 1
+	}
+
+	meta := surgery.Meta()
+	c.Ui.Info(fmt.Sprintf("Using snapshot %s (index %d, term %d)", meta.ID, meta.Index, meta.Term))
+	if n := surgery.LogsAfterSnapshot(); n > 0 && action != "list" && action != "dump" {
+		c.Ui.Warn(fmt.Sprintf(
+			"Warning: %d raft log entries after the snapshot will be applied on top of it", n))
+	}
+
+	switch action {
+	case "list":
+		if namespace == "*" {
+			namespace = ""
+		}
+		return c.list(surgery, objType, namespace)
+	case "dump":
+		obj, err := surgery.Get(objType, namespace, id)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error reading %s: %v", objType, err))
+			return 1
+		}
+		return c.outputJSON(obj)
+	case "delete":
+		deleted, err := surgery.Delete(objType, namespace, id, dryRun)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error deleting %s: %v", objType, err))
+			return 1
+		}
+		for _, obj := range deleted {
+			c.Ui.Output(fmt.Sprintf("Deleted %s %q (%s)", obj.Type, obj.ID, humanize.IBytes(uint64(obj.Size))))
+		}
+	case "patch":
+		patch, err := c.readPatch(patchPath)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error reading patch: %v", err))
+			return 1
+		}
+		_, after, err := surgery.Patch(objType, namespace, id, patch, dryRun)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error patching %s: %v", objType, err))
+			return 1
+		}
+		if code := c.outputJSON(after); code != 0 {
+			return code
+		}
+	}
+
+	if dryRun {
+		c.Ui.Output("Dry run: the snapshot was not modified")
+	} else {
+		c.Ui.Output(fmt.Sprintf("Wrote snapshot %s", surgery.Meta().ID))
+	}
+	return 0
+}
+
+func (c *OperatorRaftSurgeryCommand) list(surgery *raftutil.SnapshotSurgery, objType, namespace string) int {
+	objects, err := surgery.List(objType, namespace)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error listing %s objects: %v", objType, err))
+		return 1
+	}
+	if len(objects) == 0 {
+		c.Ui.Output(fmt.Sprintf("No %s objects found", objType))
+		return 0
+	}
+
+	rows := make([]string, 0, len(objects)+1)
+	rows = append(rows, "Namespace|ID|Size")
+	for _, obj := range objects {
+		rows = append(rows, fmt.Sprintf("%s|%s|%s",
+			obj.Namespace, obj.ID, humanize.IBytes(uint64(obj.Size))))
+	}
+	c.Ui.Output(formatList(rows))
+	return 0
+}
+
+func (c *OperatorRaftSurgeryCommand) readPatch(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func (c *OperatorRaftSurgeryCommand) outputJSON(obj any) int {
+	out, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to encode output: %v", err))
+		return 1
+	}
+	c.Ui.Output(string(out))
+	return 0
+}
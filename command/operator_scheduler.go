@@ -31,6 +31,10 @@ Usage: nomad operator scheduler <subcommand> [options]
 
       $ nomad operator scheduler set-config -scheduler-algorithm=spread
 
+  Benchmark the scheduler against a synthetic cluster of 5000 nodes:
+
+      $ nomad operator scheduler bench -nodes=5000
+
   Please see the individual subcommand help for detailed usage information.
 `
 	return strings.TrimSpace(helpText)
@@ -0,0 +1,226 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler/benchmarks"
+	"github.com/hashicorp/nomad/version"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+// Ensure OperatorSchedulerBenchCommand satisfies the cli.Command interface.
+var _ cli.Command = &OperatorSchedulerBenchCommand{}
+
+type OperatorSchedulerBenchCommand struct {
+	Meta
+}
+
+func (c *OperatorSchedulerBenchCommand) Help() string {
+	helpText := `
+Usage: nomad operator scheduler bench [options]
+
+  Benchmark the scheduler of this Nomad binary against a synthetic cluster.
+
+  The benchmark generates the requested number of nodes and a single job in
+  an in-memory state store, and then repeatedly processes the evaluation that
+  places the job, measuring the latency and placement throughput of the
+  scheduler. Plans are not applied, so every iteration places the whole job.
+  The benchmark runs entirely within this command and does not contact a Nomad
+  agent.
+
+  Comparing the output of this command between Nomad versions can be used to
+  detect scheduler performance regressions.
+
+  Benchmark both scheduler algorithms with a spread job on 5000 nodes:
+
+      $ nomad operator scheduler bench -nodes=5000 -spread \
+          -algorithm=binpack,spread
+
+Cluster Options:
+
+  -nodes=<count>
+    Number of nodes in the synthetic cluster. Defaults to 1000.
+
+  -datacenters=<count>
+    Number of datacenters the nodes are spread across. Defaults to 2.
+
+  -racks=<count>
+    Number of distinct values of the node's "rack" metadata, which spread
+    jobs are spread across. Defaults to 25.
+
+  -node-cores=<count>
+    Number of 3000 MHz cores of each node. Defaults to 8.
+
+  -node-memory=<MB>
+    Memory of each node in MB. Defaults to 32768.
+
+Job Options:
+
+  -type=<type>
+    Type of the job, which selects the scheduler to benchmark. One of
+    "service", "batch", "system" or "sysbatch". Defaults to "service".
+
+  -groups=<count>
+    Number of task groups in the job. Defaults to 1.
+
+  -count=<count>
+    Count of each task group. Ignored for system and sysbatch jobs, which are
+    placed on every node. Defaults to 500.
+
+  -cpu=<MHz>
+    CPU of each allocation in MHz. Defaults to 500.
+
+  -memory=<MB>
+    Memory of each allocation in MB. Defaults to 256.
+
+  -spread
+    Spread the allocations of the job across the racks.
+
+Benchmark Options:
+
+  -algorithm=<algorithms>
+    Comma separated list of the scheduler algorithms to benchmark. Each
+    algorithm is run against its own copy of the cluster. Defaults to
+    "binpack".
+
+  -iterations=<count>
+    Number of times the evaluation is processed for each algorithm. Defaults
+    to 10.
+
+  -json
+    Output the benchmark results in their JSON format.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorSchedulerBenchCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-nodes":       complete.PredictAnything,
+		"-datacenters": complete.PredictAnything,
+		"-racks":       complete.PredictAnything,
+		"-node-cores":  complete.PredictAnything,
+		"-node-memory": complete.PredictAnything,
+		"-type":        complete.PredictSet("service", "batch", "system", "sysbatch"),
+		"-groups":      complete.PredictAnything,
+		"-count":       complete.PredictAnything,
+		"-cpu":         complete.PredictAnything,
+		"-memory":      complete.PredictAnything,
+		"-spread":      complete.PredictNothing,
+		"-algorithm":   complete.PredictSet("binpack", "spread"),
+		"-iterations":  complete.PredictAnything,
+		"-json":        complete.PredictNothing,
+	}
+}
+
+func (c *OperatorSchedulerBenchCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorSchedulerBenchCommand) Synopsis() string {
+	return "Benchmark the scheduler against a synthetic cluster"
+}
+
+func (c *OperatorSchedulerBenchCommand) Name() string { return "operator scheduler bench" }
+
+func (c *OperatorSchedulerBenchCommand) Run(args []string) int {
+	var algorithms string
+	var json bool
+
+	cfg := benchmarks.DefaultConfig()
+
+	flags := c.Meta.FlagSet(c.Name(), 0)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.IntVar(&cfg.Cluster.Nodes, "nodes", cfg.Cluster.Nodes, "")
+	flags.IntVar(&cfg.Cluster.Datacenters, "datacenters", cfg.Cluster.Datacenters, "")
+	flags.IntVar(&cfg.Cluster.Racks, "racks", cfg.Cluster.Racks, "")
+	flags.IntVar(&cfg.Cluster.NodeCores, "node-cores", cfg.Cluster.NodeCores, "")
+	flags.IntVar(&cfg.Cluster.NodeMemoryMB, "node-memory", cfg.Cluster.NodeMemoryMB, "")
+	flags.StringVar(&cfg.Job.Type, "type", cfg.Job.Type, "")
+	flags.IntVar(&cfg.Job.Groups, "groups", cfg.Job.Groups, "")
+	flags.IntVar(&cfg.Job.Count, "count", cfg.Job.Count, "")
+	flags.IntVar(&cfg.Job.CPU, "cpu", cfg.Job.CPU, "")
+	flags.IntVar(&cfg.Job.MemoryMB, "memory", cfg.Job.MemoryMB, "")
+	flags.BoolVar(&cfg.Job.Spread, "spread", false, "")
+	flags.StringVar(&algorithms, "algorithm", string(structs.SchedulerAlgorithmBinpack), "")
+	flags.IntVar(&cfg.Iterations, "iterations", cfg.Iterations, "")
+	flags.BoolVar(&json, "json", false, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got no arguments
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	cfg.Algorithms = nil
+	for _, algorithm := range strings.Split(algorithms, ",") {
+		cfg.Algorithms = append(cfg.Algorithms,
+			structs.SchedulerAlgorithm(strings.TrimSpace(algorithm)))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid benchmark configuration: %s", err))
+		return 1
+	}
+
+	if !json {
+		c.Ui.Output(fmt.Sprintf("Benchmarking %d %s allocations on %d nodes...",
+			cfg.Job.Count*cfg.Job.Groups, cfg.Job.Type, cfg.Cluster.Nodes))
+	}
+
+	results, err := benchmarks.Run(cfg)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error running scheduler benchmark: %s", err))
+		return 1
+	}
+
+	if json {
+		out, err := Format(true, "", results)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatKV([]string{
+		fmt.Sprintf("Nomad Version|%s", version.GetVersion().FullVersionNumber(false)),
+		fmt.Sprintf("Scheduler Version|%d", results[0].SchedulerVersion),
+		fmt.Sprintf("Job Type|%s", cfg.Job.Type),
+		fmt.Sprintf("Nodes|%d", cfg.Cluster.Nodes),
+		fmt.Sprintf("Iterations|%d", cfg.Iterations),
+	}))
+	c.Ui.Output(c.Colorize().Color("\n[bold]Results[reset]"))
+	c.Ui.Output(formatBenchResults(results))
+	return 0
+}
+
+func formatBenchResults(results []*benchmarks.Result) string {
+	rows := make([]string, len(results)+1)
+	rows[0] = "Algorithm|Placed|Failed|Min|Mean|P50|P90|P99|Max|Placements/s"
+	for i, r := range results {
+		rows[i+1] = fmt.Sprintf("%s|%d|%d|%s|%s|%s|%s|%s|%s|%.1f",
+			r.Algorithm, r.Placements, r.FailedPlacements,
+			formatBenchDuration(r.Min), formatBenchDuration(r.Mean),
+			formatBenchDuration(r.P50), formatBenchDuration(r.P90),
+			formatBenchDuration(r.P99), formatBenchDuration(r.Max),
+			r.PlacementsPerSecond)
+	}
+	return formatList(rows)
+}
+
+func formatBenchDuration(d time.Duration) string {
+	return d.Round(10 * time.Microsecond).String()
+}
@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/scheduler/benchmarks"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestOperatorSchedulerBenchCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &OperatorSchedulerBenchCommand{}
+}
+
+func TestOperatorSchedulerBenchCommand_Run(t *testing.T) {
+	ci.Parallel(t)
+
+	ui := cli.NewMockUi()
+	c := &OperatorSchedulerBenchCommand{Meta: Meta{Ui: ui}}
+
+	args := []string{"-nodes=10", "-count=20", "-iterations=2", "-algorithm=binpack,spread"}
+	must.Zero(t, c.Run(args))
+	s := ui.OutputWriter.String()
+	must.StrContains(t, s, "Benchmarking 20 service allocations on 10 nodes")
+	must.StrContains(t, s, "Scheduler Version = 1")
+	must.StrContains(t, s, "Algorithm")
+	must.StrContains(t, s, "binpack    20")
+	must.StrContains(t, s, "spread     20")
+	ui.ErrorWriter.Reset()
+	ui.OutputWriter.Reset()
+
+	// Request JSON output and test.
+	must.Zero(t, c.Run(append(args, "-json")))
+	var results []*benchmarks.Result
+	must.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &results))
+	must.Len(t, 2, results)
+	must.Eq(t, 20, results[0].Placements)
+	ui.ErrorWriter.Reset()
+	ui.OutputWriter.Reset()
+
+	// Test an invalid configuration.
+	must.One(t, c.Run([]string{"-type=bogus", "-algorithm=bogus"}))
+	s = ui.ErrorWriter.String()
+	must.StrContains(t, s, `invalid job type "bogus"`)
+	must.StrContains(t, s, `invalid scheduler algorithm "bogus"`)
+	ui.ErrorWriter.Reset()
+
+	// Test that arguments are rejected.
+	must.One(t, c.Run([]string{"foo"}))
+	must.StrContains(t, ui.ErrorWriter.String(), "This command takes no arguments")
+}
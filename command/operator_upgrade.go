@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+type OperatorUpgradeCommand struct {
+	Meta
+}
+
+func (c *OperatorUpgradeCommand) Help() string {
+	helpText := `
+Usage: nomad operator upgrade <subcommand> [options]
+
+  This command groups subcommands for coordinating upgrades of Nomad agents.
+
+  Restart the client agents of a node pool two at a time:
+
+      $ nomad operator upgrade clients -node-pool=prod -max-parallel=2 \
+          -exec='ssh $NOMAD_UPGRADE_NODE_ADDRESS sudo systemctl restart nomad'
+
+  Please see the individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorUpgradeCommand) Synopsis() string {
+	return "Coordinate upgrades of Nomad agents"
+}
+
+func (c *OperatorUpgradeCommand) Name() string { return "operator upgrade" }
+
+func (c *OperatorUpgradeCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
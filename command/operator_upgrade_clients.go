@@ -0,0 +1,407 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dustin/go-humanize/english"
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+const (
+	// upgradeClientsDefaultHealthTimeout is the default amount of time a
+	// client has to become healthy after it is selected to be restarted.
+	upgradeClientsDefaultHealthTimeout = 5 * time.Minute
+)
+
+// OperatorUpgradeClientsCommand is the implementation for the command that
+// performs a rolling restart of client agents.
+type OperatorUpgradeClientsCommand struct {
+	Meta
+
+	client *api.Client
+
+	// Configuration values read and parsed from command flags.
+	nodePool      string
+	maxParallel   int
+	execCmd       string
+	version       string
+	healthTimeout time.Duration
+	cancel        bool
+}
+
+func (c *OperatorUpgradeClientsCommand) Help() string {
+	helpText := `
+Usage: nomad operator upgrade clients [options]
+
+  Perform a rolling restart of the client agents in a node pool, for example to
+  roll out a new Nomad version.
+
+  The rolling restart is coordinated by the servers. The servers select the
+  nodes to restart in batches, mark them ineligible for scheduling so no new
+  allocations are placed on them, and wait for them to become healthy again.
+  Their allocations are not drained and keep running while the agents restart.
+  A node is healthy once it re-registered with the servers, is ready, reports
+  the version given in '-version', and every allocation that was running before
+  the restart is running again. The node is then marked eligible again and the
+  next node is restarted.
+
+  Nomad does not restart agents itself. Instead, this command follows the
+  upgrade and runs the command given in '-exec' locally once for each node the
+  servers select, which is expected to install the new version and restart the
+  agent (for example over SSH or with a configuration management tool). The
+  following environment variables are set for the command:
+
+    NOMAD_UPGRADE_NODE_ID       The ID of the node being upgraded.
+    NOMAD_UPGRADE_NODE_NAME     The name of the node being upgraded.
+    NOMAD_UPGRADE_NODE_ADDRESS  The IP address of the node being upgraded.
+
+  If a node does not become healthy within '-health-timeout', or its restart
+  command fails, the servers pause the upgrade and mark the nodes they marked
+  ineligible eligible again. Interrupting this command pauses the upgrade as
+  well. Running the same command again resumes a paused upgrade, restarting the
+  nodes that failed. Only one upgrade can be in progress at a time, and it must
+  be cancelled with '-cancel' before an upgrade of another node pool or version
+  can be started.
+
+  Nodes that are down or disconnected are skipped. Nodes that were already
+  ineligible are left ineligible.
+
+  When ACLs are enabled, this command requires a token with the
+  'operator:write' and 'node:write' capabilities.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Upgrade Options:
+
+  -cancel
+    Cancel the upgrade in progress instead of starting or resuming one.
+
+  -exec=<command>
+    Command to run for each node to restart its client agent. Required unless
+    -cancel is set.
+
+  -health-timeout=<duration>
+    Amount of time a node has to become healthy after it is selected to be
+    restarted. Defaults to 5m.
+
+  -max-parallel=<n>
+    Number of nodes to upgrade at once. Defaults to 1.
+
+  -node-pool=<pool>
+    Only upgrade nodes in the given node pool. If not set, all nodes are
+    upgraded.
+
+  -version=<version>
+    Version of Nomad the nodes are expected to run after the restart. Nodes
+    that already report this version are skipped.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *OperatorUpgradeClientsCommand) Synopsis() string {
+	return "Perform a rolling restart of client agents"
+}
+
+func (c *OperatorUpgradeClientsCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-cancel":         complete.PredictNothing,
+			"-exec":           complete.PredictAnything,
+			"-health-timeout": complete.PredictAnything,
+			"-max-parallel":   complete.PredictAnything,
+			"-node-pool":      nodePoolPredictor(c.Client, nil),
+			"-version":        complete.PredictAnything,
+		})
+}
+
+func (c *OperatorUpgradeClientsCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *OperatorUpgradeClientsCommand) Name() string { return "operator upgrade clients" }
+
+func (c *OperatorUpgradeClientsCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&c.cancel, "cancel", false, "")
+	flags.StringVar(&c.execCmd, "exec", "", "")
+	flags.DurationVar(&c.healthTimeout, "health-timeout", upgradeClientsDefaultHealthTimeout, "")
+	flags.IntVar(&c.maxParallel, "max-parallel", 1, "")
+	flags.StringVar(&c.nodePool, "node-pool", "", "")
+	flags.StringVar(&c.version, "version", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	if len(flags.Args()) != 0 {
+		c.Ui.Error("This command takes no arguments")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if c.execCmd == "" && !c.cancel {
+		c.Ui.Error("The -exec flag is required")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if c.maxParallel < 1 {
+		c.Ui.Error(fmt.Sprintf("Invalid -max-parallel value %d: must be at least 1", c.maxParallel))
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if c.healthTimeout <= 0 {
+		c.Ui.Error("The -health-timeout value must be greater than zero")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	var err error
+	c.client, err = c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	active, err := c.activeUpgrade()
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if c.cancel {
+		if active == nil {
+			c.Ui.Output("No client upgrade in progress")
+			return 0
+		}
+		if _, _, err := c.client.Operator().CancelClientUpgrade(active.ID, "", nil); err != nil {
+			c.Ui.Error(fmt.Sprintf("Error cancelling client upgrade: %s", err))
+			return 1
+		}
+		c.Ui.Output(fmt.Sprintf("Cancelled client upgrade %q", limit(active.ID, shortId)))
+		return 0
+	}
+
+	upgrade, err := c.startOrResume(active)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if upgrade.Status == api.ClientUpgradeStatusComplete {
+		c.Ui.Output("No nodes to upgrade")
+		return 0
+	}
+
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+		"[bold]==> %s: Upgrading %s in client upgrade %q[reset]",
+		formatTime(time.Now()),
+		english.Plural(len(upgrade.Nodes), "node", "nodes"),
+		limit(upgrade.ID, shortId),
+	)))
+
+	return c.monitor(upgrade)
+}
+
+// activeUpgrade returns the client upgrade in progress, if any.
+func (c *OperatorUpgradeClientsCommand) activeUpgrade() (*api.ClientUpgrade, error) {
+	upgrades, _, err := c.client.Operator().ClientUpgrades(nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying client upgrades: %s", err)
+	}
+	for _, upgrade := range upgrades {
+		switch upgrade.Status {
+		case api.ClientUpgradeStatusRunning, api.ClientUpgradeStatusPaused:
+			return upgrade, nil
+		}
+	}
+	return nil, nil
+}
+
+// startOrResume starts a client upgrade, or resumes the upgrade in progress
+// if it upgrades the same nodes to the same version.
+func (c *OperatorUpgradeClientsCommand) startOrResume(active *api.ClientUpgrade) (*api.ClientUpgrade, error) {
+	if active == nil {
+		upgrade, _, err := c.client.Operator().StartClientUpgrade(&api.ClientUpgrade{
+			NodePool:      c.nodePool,
+			Version:       c.version,
+			MaxParallel:   c.maxParallel,
+			HealthTimeout: c.healthTimeout,
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("Error starting client upgrade: %s", err)
+		}
+		return upgrade, nil
+	}
+
+	if active.NodePool != c.nodePool || active.Version != c.version {
+		return nil, fmt.Errorf("Client upgrade %q of node pool %q to version %q is %s. "+
+			"Cancel it with -cancel to start another upgrade.",
+			limit(active.ID, shortId), active.NodePool, active.Version, active.Status)
+	}
+	if active.Status == api.ClientUpgradeStatusRunning {
+		c.Ui.Output(fmt.Sprintf("Following client upgrade %q in progress", limit(active.ID, shortId)))
+		return active, nil
+	}
+
+	upgrade, _, err := c.client.Operator().ResumeClientUpgrade(active.ID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error resuming client upgrade: %s", err)
+	}
+	c.Ui.Output(fmt.Sprintf("Resuming client upgrade %q", limit(active.ID, shortId)))
+	return upgrade, nil
+}
+
+// monitor follows the upgrade until it completes, is paused or is cancelled,
+// running the restart command for every node the servers select. The upgrade
+// is paused if the command is interrupted.
+func (c *OperatorUpgradeClientsCommand) monitor(upgrade *api.ClientUpgrade) int {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalCh)
+	go func() {
+		select {
+		case <-signalCh:
+			c.pause(upgrade.ID, "interrupted")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	restarted := make(map[string]bool)
+	reported := make(map[string]string)
+	q := &api.QueryOptions{}
+	q = q.WithContext(ctx)
+	for {
+		for _, node := range upgrade.Nodes {
+			c.reportNode(node, reported)
+
+			if node.Status == api.ClientUpgradeNodeStatusRestarting && !restarted[node.NodeID] {
+				restarted[node.NodeID] = true
+				go c.restartNode(ctx, upgrade, node)
+			}
+		}
+
+		switch upgrade.Status {
+		case api.ClientUpgradeStatusComplete:
+			c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+				"[bold]==> %s: Finished upgrading %s[reset]",
+				formatTime(time.Now()),
+				english.Plural(len(upgrade.Nodes), "node", "nodes"),
+			)))
+			return 0
+		case api.ClientUpgradeStatusPaused:
+			c.Ui.Error(c.Colorize().Color(fmt.Sprintf(
+				"[bold]==> %s: Upgrade paused[reset]\n%s",
+				formatTime(time.Now()), upgrade.StatusDescription,
+			)))
+			c.Ui.Output("Re-run the command once the error is resolved to resume.")
+			return 1
+		case api.ClientUpgradeStatusCancelled:
+			c.Ui.Error(c.Colorize().Color(fmt.Sprintf(
+				"[bold]==> %s: Upgrade cancelled[reset]\n%s",
+				formatTime(time.Now()), upgrade.StatusDescription,
+			)))
+			return 1
+		}
+
+		q.WaitIndex = upgrade.ModifyIndex
+		latest, _, err := c.client.Operator().ClientUpgrade(upgrade.ID, q)
+		if err != nil {
+			if ctx.Err() != nil {
+				// The upgrade was paused on interrupt, so report it as such.
+				latest, _, err = c.client.Operator().ClientUpgrade(upgrade.ID, nil)
+			}
+			if err != nil {
+				c.Ui.Error(fmt.Sprintf("Error querying client upgrade: %s", err))
+				return 1
+			}
+		}
+		upgrade = latest
+	}
+}
+
+// reportNode outputs the status of a node of the upgrade when it changes.
+func (c *OperatorUpgradeClientsCommand) reportNode(node *api.ClientUpgradeNode, reported map[string]string) {
+	if reported[node.NodeID] == node.Status {
+		return
+	}
+	reported[node.NodeID] = node.Status
+
+	var msg string
+	switch node.Status {
+	case api.ClientUpgradeNodeStatusRestarting:
+		msg = fmt.Sprintf("Restarting node %q with %s running", node.Name,
+			english.Plural(len(node.RunningAllocs), "allocation", "allocations"))
+	case api.ClientUpgradeNodeStatusHealthy:
+		msg = fmt.Sprintf("Node %q upgraded", node.Name)
+	case api.ClientUpgradeNodeStatusSkipped:
+		msg = fmt.Sprintf("Skipped node %q: %s", node.Name, node.StatusDescription)
+	case api.ClientUpgradeNodeStatusFailed:
+		msg = fmt.Sprintf("Node %q failed: %s", node.Name, node.StatusDescription)
+	default:
+		return
+	}
+	c.Ui.Output(fmt.Sprintf("    %s: %s", formatTime(time.Now()), msg))
+}
+
+// restartNode runs the restart command of a node, pausing the upgrade if it
+// fails.
+func (c *OperatorUpgradeClientsCommand) restartNode(ctx context.Context, upgrade *api.ClientUpgrade, n *api.ClientUpgradeNode) {
+	ctx, cancel := context.WithTimeout(ctx, upgrade.HealthTimeout)
+	defer cancel()
+
+	node, _, err := c.client.Nodes().Info(n.NodeID, nil)
+	if err != nil {
+		c.pause(upgrade.ID, fmt.Sprintf("failed to read node %s: %v", n.Name, err))
+		return
+	}
+
+	if out, err := c.execRestart(ctx, node); err != nil {
+		if ctx.Err() == context.Canceled {
+			return
+		}
+		c.Ui.Error(fmt.Sprintf("    %s: Restart command of node %q failed: %v\n%s",
+			formatTime(time.Now()), n.Name, err, out))
+		c.pause(upgrade.ID, fmt.Sprintf("restart command of node %s failed: %v", n.Name, err))
+	}
+}
+
+// pause pauses the upgrade with the given reason.
+func (c *OperatorUpgradeClientsCommand) pause(id, reason string) {
+	if _, _, err := c.client.Operator().PauseClientUpgrade(id, reason, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error pausing client upgrade: %s", err))
+	}
+}
+
+// execRestart runs the user provided command that restarts the client agent.
+func (c *OperatorUpgradeClientsCommand) execRestart(ctx context.Context, node *api.Node) ([]byte, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", c.execCmd)
+	} else {
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", c.execCmd)
+	}
+
+	cmd.Env = append(os.Environ(),
+		"NOMAD_UPGRADE_NODE_ID="+node.ID,
+		"NOMAD_UPGRADE_NODE_NAME="+node.Name,
+		"NOMAD_UPGRADE_NODE_ADDRESS="+node.Attributes["unique.network.ip-address"],
+	)
+	return cmd.CombinedOutput()
+}
@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestOperatorUpgradeClientsCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &OperatorUpgradeClientsCommand{}
+}
+
+func TestOperatorUpgradeClientsCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name   string
+		args   []string
+		expErr string
+	}{
+		{
+			name:   "missing exec",
+			args:   []string{},
+			expErr: "The -exec flag is required",
+		},
+		{
+			name:   "invalid max parallel",
+			args:   []string{"-exec=true", "-max-parallel=0"},
+			expErr: "Invalid -max-parallel value 0",
+		},
+		{
+			name:   "invalid health timeout",
+			args:   []string{"-exec=true", "-health-timeout=0s"},
+			expErr: "The -health-timeout value must be greater than zero",
+		},
+		{
+			name:   "unexpected argument",
+			args:   []string{"-exec=true", "node"},
+			expErr: "This command takes no arguments",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			cmd := &OperatorUpgradeClientsCommand{Meta: Meta{Ui: ui}}
+
+			code := cmd.Run(tc.args)
+			must.One(t, code)
+			must.StrContains(t, ui.ErrorWriter.String(), tc.expErr)
+		})
+	}
+}
+
+func TestOperatorUpgradeClientsCommand_NoNodes(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorUpgradeClientsCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, "-exec=true"})
+	must.Zero(t, code)
+	must.StrContains(t, ui.OutputWriter.String(), "No nodes to upgrade")
+}
+
+func TestOperatorUpgradeClientsCommand_CancelNone(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	ui := cli.NewMockUi()
+	cmd := &OperatorUpgradeClientsCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, "-cancel"})
+	must.Zero(t, code)
+	must.StrContains(t, ui.OutputWriter.String(), "No client upgrade in progress")
+}
@@ -259,10 +259,10 @@ func TestStatusCommand_Run_HostNetwork(t *testing.T) {
 			}},
 			verbose: true,
 			assertions: func(out string) {
-				verboseHostNetworksHeadRegexpStr := `Name\s+CIDR\s+Interface\s+ReservedPorts\n`
+				verboseHostNetworksHeadRegexpStr := `Name\s+CIDR\s+Interface\s+ReservedPorts\s+DynamicPorts\n`
 				must.RegexMatch(t, regexp.MustCompile(verboseHostNetworksHeadRegexpStr), out)
 
-				verboseHostNetworksBodyRegexpStr := `internal\s+127\.0\.0\.1/8\s+lo\s+<none>\n`
+				verboseHostNetworksBodyRegexpStr := `internal\s+127\.0\.0\.1/8\s+lo\s+<none>\s+<none>\n`
 				must.RegexMatch(t, regexp.MustCompile(verboseHostNetworksBodyRegexpStr), out)
 			},
 		},
@@ -274,10 +274,10 @@ func TestStatusCommand_Run_HostNetwork(t *testing.T) {
 			}},
 			verbose: true,
 			assertions: func(out string) {
-				verboseHostNetworksHeadRegexpStr := `Name\s+CIDR\s+Interface\s+ReservedPorts\n`
+				verboseHostNetworksHeadRegexpStr := `Name\s+CIDR\s+Interface\s+ReservedPorts\s+DynamicPorts\n`
 				must.RegexMatch(t, regexp.MustCompile(verboseHostNetworksHeadRegexpStr), out)
 
-				verboseHostNetworksBodyRegexpStr := `public\s+10\.199\.0\.200/24\s+<none>\s+<none>\n`
+				verboseHostNetworksBodyRegexpStr := `public\s+10\.199\.0\.200/24\s+<none>\s+<none>\s+<none>\n`
 				must.RegexMatch(t, regexp.MustCompile(verboseHostNetworksBodyRegexpStr), out)
 			},
 		},
@@ -290,10 +290,10 @@ func TestStatusCommand_Run_HostNetwork(t *testing.T) {
 			}},
 			verbose: true,
 			assertions: func(out string) {
-				verboseHostNetworksHeadRegexpStr := `Name\s+CIDR\s+Interface\s+ReservedPorts\n`
+				verboseHostNetworksHeadRegexpStr := `Name\s+CIDR\s+Interface\s+ReservedPorts\s+DynamicPorts\n`
 				must.RegexMatch(t, regexp.MustCompile(verboseHostNetworksHeadRegexpStr), out)
 
-				verboseHostNetworksBodyRegexpStr := `public\s+10\.199\.0\.200/24\s+<none>\s+8080,8081\n`
+				verboseHostNetworksBodyRegexpStr := `public\s+10\.199\.0\.200/24\s+<none>\s+8080,8081\s+<none>\n`
 				must.RegexMatch(t, regexp.MustCompile(verboseHostNetworksBodyRegexpStr), out)
 			},
 		},
@@ -0,0 +1,1247 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	"github.com/moby/term"
+	"github.com/posener/complete"
+)
+
+const (
+	// topMaxConcurrentStats is the maximum number of node or allocation stats
+	// requests in flight during a refresh.
+	topMaxConcurrentStats = 8
+
+	// topMaxLogLines is the number of log lines kept in follow mode.
+	topMaxLogLines = 1000
+
+	// topLogOffset is how far from the end of the logs follow mode starts.
+	topLogOffset = 16 * 1024
+
+	// ANSI escape sequences used to draw the interactive view.
+	topEnterScreen = "\x1b[?1049h\x1b[?25l"
+	topExitScreen  = "\x1b[?25h\x1b[?1049l"
+	topHome        = "\x1b[H"
+	topClearLine   = "\x1b[K"
+	topClearBelow  = "\x1b[J"
+	topReverse     = "\x1b[7m"
+	topReset       = "\x1b[0m"
+)
+
+// topEventTopics are the event stream topics that trigger a refresh.
+var topEventTopics = map[api.Topic][]string{
+	api.TopicJob:        {"*"},
+	api.TopicAllocation: {"*"},
+	api.TopicDeployment: {"*"},
+	api.TopicEvaluation: {"*"},
+	api.TopicNode:       {"*"},
+}
+
+type TopCommand struct {
+	Meta
+}
+
+func (c *TopCommand) Help() string {
+	helpText := `
+Usage: nomad top [options] [<job>]
+
+  Top displays a live view of the cluster: the resource usage of the nodes,
+  the depth of the evaluation queue, the progress of active deployments and
+  the status of the jobs. The view is refreshed when the event stream reports
+  changes, and at least every refresh interval to update resource usage.
+
+  Selecting a job drills down to the resource usage of its allocations and the
+  progress of its latest deployment, and selecting an allocation drills down
+  to the resource usage and events of its tasks. From the allocation view, the
+  logs of a task can be followed. If a job is given, top starts in the view of
+  that job.
+
+  Keys:
+
+    up, k           Select the previous row
+    down, j         Select the next row
+    enter, right    Drill down into the selected job or allocation, or follow
+                    the logs of the selected task
+    esc, left       Return to the previous view
+    f               Follow the logs of the selected task
+    s               Switch between stdout and stderr when following logs
+    q, ctrl+c       Quit
+
+  When ACLs are enabled, this command requires a token with the 'read-job'
+  capability for the namespace, and the 'node:read' capability to display the
+  resource usage of the nodes. Following logs requires the 'read-logs'
+  capability.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Top Options:
+
+  -interval
+    How often to refresh resource usage. Defaults to 2s.
+
+  -once
+    Print the view once and exit. This is the default if the output is not a
+    terminal.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *TopCommand) Synopsis() string {
+	return "Display a live view of cluster, job and allocation usage"
+}
+
+func (c *TopCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-interval": complete.PredictAnything,
+			"-once":     complete.PredictNothing,
+		})
+}
+
+func (c *TopCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Jobs, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Jobs]
+	})
+}
+
+func (c *TopCommand) Name() string { return "top" }
+
+func (c *TopCommand) Run(args []string) int {
+	var once bool
+	var interval time.Duration
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&once, "once", false, "")
+	flags.DurationVar(&interval, "interval", 2*time.Second, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got either no arguments or exactly one
+	args = flags.Args()
+	if len(args) > 1 {
+		c.Ui.Error("This command takes either no arguments or one: <job>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if interval <= 0 {
+		c.Ui.Error("The -interval flag must be positive")
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	m := newTopModel()
+	if len(args) == 1 {
+		job, _, err := client.Jobs().Info(strings.TrimSpace(args[0]), nil)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying job: %s", err))
+			return 1
+		}
+		m.view = topViewJob
+		m.namespace = *job.Namespace
+		m.jobID = *job.ID
+	}
+
+	if once || !isTty() {
+		ctx, cancel := context.WithTimeout(context.Background(), topFetchTimeout(interval))
+		defer cancel()
+
+		m.update(fetchTop(ctx, client, m.request()))
+		if m.snap.err != nil {
+			c.Ui.Error(fmt.Sprintf("Error querying %s: %s", m.snap.subject, m.snap.err))
+			return 1
+		}
+		c.Ui.Output(strings.Join(m.screen().layout(0, 0, false), "\n"))
+		return 0
+	}
+
+	m.interactive = true
+	if err := c.interactive(client, m, interval); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	return 0
+}
+
+// interactive runs the interactive view until the user quits.
+func (c *TopCommand) interactive(client *api.Client, m *topModel, interval time.Duration) error {
+	restoreIn, err := setRawTerminal(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("Error setting up terminal: %s", err)
+	}
+	defer restoreIn()
+	if restoreOut, err := setRawTerminalOutput(os.Stdout); err == nil {
+		defer restoreOut()
+	}
+
+	out := os.Stdout
+	fmt.Fprint(out, topEnterScreen)
+	defer fmt.Fprint(out, topExitScreen)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	keyCh := make(chan topKey, 16)
+	go readTopKeys(os.Stdin, keyCh)
+
+	resizeCh := make(chan os.Signal, 1)
+	setupWindowNotification(resizeCh)
+
+	eventCh := client.EventStream().Subscribe(ctx, topEventTopics, 0, nil, nil)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// Only one refresh is in flight at a time, and refreshes requested in the
+	// meantime are coalesced into the next one
+	snapCh := make(chan *topSnapshot, 1)
+	var fetching, pending bool
+	refresh := func() {
+		if fetching {
+			pending = true
+			return
+		}
+		fetching = true
+		req := m.request()
+		go func() {
+			fetchCtx, fetchCancel := context.WithTimeout(ctx, topFetchTimeout(interval))
+			defer fetchCancel()
+			snapCh <- fetchTop(fetchCtx, client, req)
+		}()
+	}
+
+	var logCh <-chan *api.StreamFrame
+	var logErrCh <-chan error
+	var logCancel chan struct{}
+	stopLogs := func() {
+		if logCancel != nil {
+			close(logCancel)
+			logCancel, logCh, logErrCh = nil, nil, nil
+		}
+	}
+	defer stopLogs()
+	followLogs := func() {
+		stopLogs()
+		m.logs.reset()
+		logCancel = make(chan struct{})
+		logCh, logErrCh = client.AllocFS().Logs(m.followAlloc, true, m.task, m.logType,
+			api.OriginEnd, topLogOffset, logCancel, nil)
+	}
+
+	draw := func() {
+		width, height := topTerminalSize(out)
+		lines := m.screen().layout(width, height, true)
+		fmt.Fprint(out, topHome+strings.Join(lines, topClearLine+"\r\n")+topClearLine+topClearBelow)
+	}
+
+	refresh()
+	draw()
+	for {
+		select {
+		case key := <-keyCh:
+			switch m.handleKey(key) {
+			case topActionQuit:
+				return nil
+			case topActionRefresh:
+				refresh()
+			case topActionFollow:
+				followLogs()
+			case topActionUnfollow:
+				stopLogs()
+			}
+			draw()
+
+		case snap := <-snapCh:
+			fetching = false
+			m.update(snap)
+			if pending {
+				pending = false
+				refresh()
+			}
+			draw()
+
+		case events, ok := <-eventCh:
+			if !ok {
+				eventCh = nil
+				continue
+			}
+			if events.Err != nil {
+				m.streamErr = events.Err
+				draw()
+				continue
+			}
+			m.streamErr = nil
+			if !events.IsHeartbeat() {
+				refresh()
+			}
+
+		case <-ticker.C:
+			refresh()
+
+		case <-resizeCh:
+			draw()
+
+		case frame, ok := <-logCh:
+			if !ok {
+				logCh = nil
+				continue
+			}
+			if frame.IsHeartbeat() {
+				continue
+			}
+			m.logs.write(frame.Data)
+			draw()
+
+		case err := <-logErrCh:
+			logErrCh = nil
+			if err != nil {
+				m.logs.err = err
+				draw()
+			}
+		}
+	}
+}
+
+// topFetchTimeout is how long a refresh may take, so unreachable clients
+// don't stall the view.
+func topFetchTimeout(interval time.Duration) time.Duration {
+	if interval < 5*time.Second {
+		return 5 * time.Second
+	}
+	return interval
+}
+
+// topTerminalSize returns the size of the terminal, or a default size if it
+// can't be determined.
+func topTerminalSize(out io.Writer) (int, int) {
+	if fd, isTerminal := term.GetFdInfo(out); isTerminal {
+		if s, err := term.GetWinsize(fd); err == nil && s.Width > 0 && s.Height > 0 {
+			return int(s.Width), int(s.Height)
+		}
+	}
+	return 80, 24
+}
+
+// topView is one of the views of the top command.
+type topView int
+
+const (
+	topViewCluster topView = iota
+	topViewJob
+	topViewAlloc
+	topViewLogs
+	topNumViews
+)
+
+// topKey is a key press handled by the top command.
+type topKey int
+
+const (
+	topKeyUp topKey = iota
+	topKeyDown
+	topKeyEnter
+	topKeyBack
+	topKeyFollow
+	topKeyStream
+	topKeyQuit
+)
+
+// topAction is what the interactive loop does after a key press.
+type topAction int
+
+const (
+	topActionNone topAction = iota
+	topActionRefresh
+	topActionFollow
+	topActionUnfollow
+	topActionQuit
+)
+
+// readTopKeys reads key presses from the terminal until it is closed.
+func readTopKeys(r io.Reader, keyCh chan<- topKey) {
+	buf := make([]byte, 64)
+	for {
+		n, err := r.Read(buf)
+		if err != nil {
+			return
+		}
+		for _, key := range parseTopKeys(buf[:n]) {
+			keyCh <- key
+		}
+	}
+}
+
+// parseTopKeys parses the keys from the bytes read from a terminal in raw
+// mode. Unknown keys are ignored.
+func parseTopKeys(b []byte) []topKey {
+	var keys []topKey
+	for i := 0; i < len(b); i++ {
+		switch b[i] {
+		case 0x1b:
+			// Arrow keys are sent as ESC [ A-D, and a lone ESC is the escape key
+			if i+2 < len(b) && (b[i+1] == '[' || b[i+1] == 'O') {
+				switch b[i+2] {
+				case 'A':
+					keys = append(keys, topKeyUp)
+				case 'B':
+					keys = append(keys, topKeyDown)
+				case 'C':
+					keys = append(keys, topKeyEnter)
+				case 'D':
+					keys = append(keys, topKeyBack)
+				}
+				i += 2
+				continue
+			}
+			keys = append(keys, topKeyBack)
+		case 'k':
+			keys = append(keys, topKeyUp)
+		case 'j':
+			keys = append(keys, topKeyDown)
+		case '\r', '\n':
+			keys = append(keys, topKeyEnter)
+		case 0x7f, 0x08:
+			keys = append(keys, topKeyBack)
+		case 'f':
+			keys = append(keys, topKeyFollow)
+		case 's':
+			keys = append(keys, topKeyStream)
+		case 'q', 0x03:
+			keys = append(keys, topKeyQuit)
+		}
+	}
+	return keys
+}
+
+// topRequest is what to fetch to refresh a view.
+type topRequest struct {
+	gen       uint64
+	view      topView
+	namespace string
+	jobID     string
+	allocID   string
+}
+
+// topSnapshot is the data of a view fetched from the API.
+type topSnapshot struct {
+	gen uint64
+
+	cluster *topClusterData
+	job     *topJobData
+	alloc   *topAllocData
+
+	// subject is what was fetched, for error messages
+	subject string
+	err     error
+	time    time.Time
+}
+
+type topClusterData struct {
+	nodes       []*api.NodeListStub
+	nodeStats   map[string]*api.HostStats
+	nodesErr    error
+	pendingEval int
+	blockedEval int
+	evalsErr    error
+	deployments []*api.Deployment
+	jobs        []*api.JobListStub
+}
+
+type topJobData struct {
+	job        *api.Job
+	deployment *api.Deployment
+	allocs     []*api.AllocationListStub
+	stats      map[string]*api.AllocResourceUsage
+}
+
+type topAllocData struct {
+	alloc *api.Allocation
+	stats *api.AllocResourceUsage
+}
+
+// fetchTop fetches the data of the requested view.
+func fetchTop(ctx context.Context, client *api.Client, req topRequest) *topSnapshot {
+	snap := &topSnapshot{gen: req.gen, time: time.Now()}
+	switch req.view {
+	case topViewCluster:
+		snap.subject = "jobs"
+		snap.cluster, snap.err = fetchTopCluster(ctx, client)
+	case topViewJob:
+		snap.subject = "job"
+		snap.job, snap.err = fetchTopJob(ctx, client, req.namespace, req.jobID)
+	case topViewAlloc, topViewLogs:
+		snap.subject = "allocation"
+		snap.alloc, snap.err = fetchTopAlloc(ctx, client, req.namespace, req.allocID)
+	}
+	return snap
+}
+
+func fetchTopCluster(ctx context.Context, client *api.Client) (*topClusterData, error) {
+	q := (&api.QueryOptions{}).WithContext(ctx)
+
+	data := &topClusterData{nodeStats: make(map[string]*api.HostStats)}
+
+	jobs, _, err := client.Jobs().List(q)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].Namespace != jobs[j].Namespace {
+			return jobs[i].Namespace < jobs[j].Namespace
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+	data.jobs = jobs
+
+	// Deployments are only informational, so errors are ignored
+	deployQ := *q
+	deployQ.Filter = fmt.Sprintf(`Status != %q and Status != %q and Status != %q`,
+		api.DeploymentStatusSuccessful, api.DeploymentStatusFailed, api.DeploymentStatusCancelled)
+	if deployments, _, err := client.Deployments().List(&deployQ); err == nil {
+		data.deployments = deployments
+	}
+
+	for _, count := range []struct {
+		status string
+		out    *int
+	}{
+		{"pending", &data.pendingEval},
+		{"blocked", &data.blockedEval},
+	} {
+		evalQ := *q
+		evalQ.Filter = fmt.Sprintf("Status == %q", count.status)
+		resp, _, err := client.Evaluations().Count(&evalQ)
+		if err != nil {
+			data.evalsErr = err
+			break
+		}
+		*count.out = resp.Count
+	}
+
+	// The nodes and their usage require the node:read capability, so they
+	// are left out rather than failing the view
+	data.nodes, _, data.nodesErr = client.Nodes().List(q)
+	if data.nodesErr != nil {
+		return data, nil
+	}
+
+	var ready []string
+	for _, node := range data.nodes {
+		if node.Status == api.NodeStatusReady {
+			ready = append(ready, node.ID)
+		}
+	}
+	stats := make([]*api.HostStats, len(ready))
+	topParallel(len(ready), func(i int) {
+		if s, err := client.Nodes().Stats(ready[i], q); err == nil {
+			stats[i] = s
+		}
+	})
+	for i, s := range stats {
+		if s != nil {
+			data.nodeStats[ready[i]] = s
+		}
+	}
+
+	return data, nil
+}
+
+func fetchTopJob(ctx context.Context, client *api.Client, namespace, jobID string) (*topJobData, error) {
+	q := (&api.QueryOptions{Namespace: namespace}).WithContext(ctx)
+
+	job, _, err := client.Jobs().Info(jobID, q)
+	if err != nil {
+		return nil, err
+	}
+	allocs, _, err := client.Jobs().Allocations(jobID, false, q)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(allocs, func(i, j int) bool { return allocs[i].CreateIndex > allocs[j].CreateIndex })
+
+	data := &topJobData{
+		job:    job,
+		allocs: allocs,
+		stats:  make(map[string]*api.AllocResourceUsage),
+	}
+
+	// Jobs without deployments return no deployment rather than an error
+	data.deployment, _, _ = client.Jobs().LatestDeployment(jobID, q)
+
+	var running []*api.AllocationListStub
+	for _, alloc := range allocs {
+		if alloc.ClientStatus == api.AllocClientStatusRunning {
+			running = append(running, alloc)
+		}
+	}
+	stats := make([]*api.AllocResourceUsage, len(running))
+	topParallel(len(running), func(i int) {
+		alloc := &api.Allocation{ID: running[i].ID, NodeID: running[i].NodeID}
+		if s, err := client.Allocations().Stats(alloc, q); err == nil {
+			stats[i] = s
+		}
+	})
+	for i, s := range stats {
+		if s != nil {
+			data.stats[running[i].ID] = s
+		}
+	}
+
+	return data, nil
+}
+
+func fetchTopAlloc(ctx context.Context, client *api.Client, namespace, allocID string) (*topAllocData, error) {
+	q := (&api.QueryOptions{Namespace: namespace}).WithContext(ctx)
+
+	alloc, _, err := client.Allocations().Info(allocID, q)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &topAllocData{alloc: alloc}
+	if alloc.ClientStatus == api.AllocClientStatusRunning {
+		if stats, err := client.Allocations().Stats(alloc, q); err == nil {
+			data.stats = stats
+		}
+	}
+	return data, nil
+}
+
+// topParallel calls fn for each index up to n, with at most
+// topMaxConcurrentStats calls in flight.
+func topParallel(n int, fn func(int)) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, topMaxConcurrentStats)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// topModel is the state of the top command: the current view, the selected
+// rows and the last fetched data.
+type topModel struct {
+	view        topView
+	interactive bool
+
+	// namespace, jobID and allocID identify the job and allocation being
+	// viewed
+	namespace string
+	jobID     string
+	allocID   string
+
+	// cursor is the selected row of each view, so returning to a view
+	// restores its selection
+	cursor [topNumViews]int
+
+	// gen is incremented when the view changes, so snapshots fetched for a
+	// previous view are dropped
+	gen  uint64
+	snap *topSnapshot
+
+	// task, logType and followAlloc are the logs being followed
+	task        string
+	logType     string
+	followAlloc *api.Allocation
+	logs        topLogBuffer
+
+	// streamErr is the last event stream error
+	streamErr error
+}
+
+func newTopModel() *topModel {
+	return &topModel{view: topViewCluster, logType: "stdout"}
+}
+
+func (m *topModel) request() topRequest {
+	return topRequest{
+		gen:       m.gen,
+		view:      m.view,
+		namespace: m.namespace,
+		jobID:     m.jobID,
+		allocID:   m.allocID,
+	}
+}
+
+// update replaces the data of the view with the snapshot, unless it was
+// fetched for a previous view.
+func (m *topModel) update(snap *topSnapshot) {
+	if snap.gen != m.gen {
+		return
+	}
+
+	// Keep showing the last data if a refresh fails
+	if snap.err != nil && m.snap != nil && m.snap.err == nil {
+		m.snap.err = snap.err
+		return
+	}
+	m.snap = snap
+	if n := m.rows(); m.cursor[m.view] >= n {
+		m.cursor[m.view] = n - 1
+	}
+	if m.cursor[m.view] < 0 {
+		m.cursor[m.view] = 0
+	}
+}
+
+// setView switches to a view, dropping the data of the previous view.
+func (m *topModel) setView(view topView) {
+	m.view = view
+	m.gen++
+	m.snap = nil
+}
+
+// rows is the number of selectable rows of the view.
+func (m *topModel) rows() int {
+	if m.snap == nil {
+		return 0
+	}
+	switch {
+	case m.snap.cluster != nil:
+		return len(m.snap.cluster.jobs)
+	case m.snap.job != nil:
+		return len(m.snap.job.allocs)
+	case m.snap.alloc != nil:
+		return len(topTaskNames(m.snap.alloc.alloc))
+	}
+	return 0
+}
+
+// handleKey updates the model for a key press, and returns what the
+// interactive loop must do.
+func (m *topModel) handleKey(key topKey) topAction {
+	cursor := &m.cursor[m.view]
+
+	switch key {
+	case topKeyQuit:
+		return topActionQuit
+
+	case topKeyUp:
+		if *cursor > 0 {
+			*cursor--
+		}
+
+	case topKeyDown:
+		if *cursor < m.rows()-1 {
+			*cursor++
+		}
+
+	case topKeyEnter:
+		if m.snap == nil || m.rows() == 0 {
+			return topActionNone
+		}
+		switch m.view {
+		case topViewCluster:
+			job := m.snap.cluster.jobs[*cursor]
+			m.namespace, m.jobID = job.Namespace, job.ID
+			m.cursor[topViewJob] = 0
+			m.setView(topViewJob)
+			return topActionRefresh
+		case topViewJob:
+			m.allocID = m.snap.job.allocs[*cursor].ID
+			m.cursor[topViewAlloc] = 0
+			m.setView(topViewAlloc)
+			return topActionRefresh
+		case topViewAlloc:
+			return m.follow()
+		}
+
+	case topKeyFollow:
+		if m.view == topViewAlloc && m.snap != nil && m.rows() > 0 {
+			return m.follow()
+		}
+
+	case topKeyStream:
+		if m.view == topViewLogs {
+			if m.logType == "stdout" {
+				m.logType = "stderr"
+			} else {
+				m.logType = "stdout"
+			}
+			return topActionFollow
+		}
+
+	case topKeyBack:
+		switch m.view {
+		case topViewLogs:
+			// The allocation view shares the data of the logs view
+			m.view = topViewAlloc
+			return topActionUnfollow
+		case topViewAlloc:
+			m.setView(topViewJob)
+			return topActionRefresh
+		case topViewJob:
+			m.setView(topViewCluster)
+			return topActionRefresh
+		}
+	}
+
+	return topActionNone
+}
+
+// follow switches to following the logs of the selected task.
+func (m *topModel) follow() topAction {
+	alloc := m.snap.alloc.alloc
+	m.task = topTaskNames(alloc)[m.cursor[topViewAlloc]]
+	m.followAlloc = alloc
+	m.logType = "stdout"
+	m.view = topViewLogs
+	return topActionFollow
+}
+
+// topLogBuffer keeps the last lines of the logs being followed.
+type topLogBuffer struct {
+	lines   []string
+	partial string
+	err     error
+}
+
+func (b *topLogBuffer) reset() {
+	*b = topLogBuffer{}
+}
+
+func (b *topLogBuffer) write(data []byte) {
+	parts := strings.Split(b.partial+string(data), "\n")
+	b.partial = parts[len(parts)-1]
+	for _, line := range parts[:len(parts)-1] {
+		b.lines = append(b.lines, strings.TrimRight(line, "\r"))
+	}
+	if len(b.lines) > topMaxLogLines {
+		b.lines = b.lines[len(b.lines)-topMaxLogLines:]
+	}
+}
+
+// topScreen is a rendered view: lines of text followed by an optional table
+// whose rows can be selected.
+type topScreen struct {
+	title string
+	lines []string
+
+	// table is formatted with formatList, so its first row is the header
+	table  []string
+	cursor int
+
+	// tail keeps the last lines rather than the first if they don't fit
+	tail bool
+
+	footer string
+}
+
+// layout lays out the screen for a terminal of the given size, scrolling the
+// table to keep the selected row visible. A zero width or height disables
+// truncation, and highlight reverses the colors of the selected row.
+func (s *topScreen) layout(width, height int, highlight bool) []string {
+	out := []string{s.title, ""}
+
+	var footer []string
+	if s.footer != "" {
+		footer = []string{"", s.footer}
+	}
+
+	lines := s.lines
+	if height > 0 && s.tail {
+		if avail := height - len(out) - len(footer); len(lines) > avail {
+			lines = lines[len(lines)-max(avail, 0):]
+		}
+	}
+	out = append(out, lines...)
+
+	selected := -1
+	if len(s.table) > 0 {
+		if len(s.lines) > 0 {
+			out = append(out, "")
+		}
+		out = append(out, s.table[0])
+
+		rows := s.table[1:]
+		first := 0
+		if height > 0 {
+			avail := max(height-len(out)-len(footer), 1)
+			if s.cursor >= avail {
+				first = s.cursor - avail + 1
+			}
+			rows = rows[first:min(first+avail, len(rows))]
+		}
+		if highlight && s.cursor >= first && s.cursor < first+len(rows) {
+			selected = len(out) + s.cursor - first
+		}
+		out = append(out, rows...)
+	}
+
+	if height > 0 && len(out) > height-len(footer) {
+		out = out[:max(height-len(footer), 0)]
+	}
+	out = append(out, footer...)
+
+	for i, line := range out {
+		if width > 0 {
+			line = topTruncate(line, width)
+		}
+		if i == selected {
+			line = topReverse + line + strings.Repeat(" ", max(width-len([]rune(line)), 0)) + topReset
+		}
+		out[i] = line
+	}
+	return out
+}
+
+// topTruncate truncates the line to the width of the terminal.
+func topTruncate(line string, width int) string {
+	runes := []rune(line)
+	if len(runes) <= width {
+		return line
+	}
+	return string(runes[:width])
+}
+
+// screen renders the current view.
+func (m *topModel) screen() *topScreen {
+	s := &topScreen{cursor: m.cursor[m.view]}
+
+	switch m.view {
+	case topViewCluster:
+		s.title = "Cluster"
+		s.footer = "[enter] job  [j/k] select  [q] quit"
+	case topViewJob:
+		s.title = fmt.Sprintf("Job %q", m.jobID)
+		s.footer = "[enter] allocation  [esc] back  [j/k] select  [q] quit"
+	case topViewAlloc:
+		s.title = fmt.Sprintf("Allocation %q", limit(m.allocID, shortId))
+		s.footer = "[enter/f] follow logs  [esc] back  [j/k] select  [q] quit"
+	case topViewLogs:
+		s.title = fmt.Sprintf("Allocation %q task %q %s (following)", limit(m.allocID, shortId), m.task, m.logType)
+		s.footer = "[s] stdout/stderr  [esc] back  [q] quit"
+	}
+	if !m.interactive {
+		s.footer = ""
+	}
+
+	if m.snap != nil {
+		s.title = fmt.Sprintf("%s - updated %s", s.title, m.snap.time.Format("15:04:05"))
+	}
+	if m.streamErr != nil && s.footer != "" {
+		s.footer = fmt.Sprintf("%s  (event stream: %v)", s.footer, m.streamErr)
+	}
+
+	if m.view == topViewLogs {
+		s.tail = true
+		s.lines = append([]string{}, m.logs.lines...)
+		if m.logs.partial != "" {
+			s.lines = append(s.lines, m.logs.partial)
+		}
+		if m.logs.err != nil {
+			s.lines = append(s.lines, fmt.Sprintf("Error reading logs: %v", m.logs.err))
+		}
+		return s
+	}
+
+	switch {
+	case m.snap == nil:
+		s.lines = []string{"Loading..."}
+	case m.snap.cluster != nil:
+		s.lines, s.table = renderTopCluster(m.snap.cluster)
+	case m.snap.job != nil:
+		s.lines, s.table = renderTopJob(m.snap.job)
+	case m.snap.alloc != nil:
+		s.lines, s.table = renderTopAlloc(m.snap.alloc)
+	}
+	if m.snap != nil && m.snap.err != nil {
+		s.lines = append([]string{fmt.Sprintf("Error querying %s: %v", m.snap.subject, m.snap.err), ""}, s.lines...)
+	}
+	return s
+}
+
+func renderTopCluster(data *topClusterData) ([]string, []string) {
+	var ready, down, disconnected, ineligible, draining int
+	for _, node := range data.nodes {
+		switch node.Status {
+		case api.NodeStatusReady:
+			ready++
+		case api.NodeStatusDown:
+			down++
+		case api.NodeStatusDisconnected:
+			disconnected++
+		}
+		if node.SchedulingEligibility == api.NodeSchedulingIneligible {
+			ineligible++
+		}
+		if node.Drain {
+			draining++
+		}
+	}
+
+	var cpuTicks float64
+	var memUsed, memTotal uint64
+	for _, stats := range data.nodeStats {
+		cpuTicks += stats.CPUTicksConsumed
+		if stats.Memory != nil {
+			memUsed += stats.Memory.Total - stats.Memory.Available
+			memTotal += stats.Memory.Total
+		}
+	}
+
+	summary := []string{}
+	if data.nodesErr != nil {
+		summary = append(summary, fmt.Sprintf("Nodes|Unavailable: %v", data.nodesErr))
+	} else {
+		summary = append(summary,
+			fmt.Sprintf("Nodes|%d ready, %d down, %d disconnected, %d ineligible, %d draining",
+				ready, down, disconnected, ineligible, draining),
+			fmt.Sprintf("CPU|%.0f MHz used (%d/%d nodes reporting)", cpuTicks, len(data.nodeStats), ready),
+			fmt.Sprintf("Memory|%s", topUsage(memUsed, memTotal)),
+		)
+	}
+	if data.evalsErr != nil {
+		summary = append(summary, fmt.Sprintf("Evaluations|Unavailable: %v", data.evalsErr))
+	} else {
+		summary = append(summary, fmt.Sprintf("Evaluations|%d pending, %d blocked", data.pendingEval, data.blockedEval))
+	}
+
+	lines := strings.Split(formatKV(summary), "\n")
+	lines = append(lines, "", "Active Deployments")
+	if len(data.deployments) == 0 {
+		lines = append(lines, "No active deployments")
+	} else {
+		deploys := []string{"ID|Job ID|Namespace|Status|Progress"}
+		for _, d := range data.deployments {
+			deploys = append(deploys, fmt.Sprintf("%s|%s|%s|%s|%s",
+				limit(d.ID, shortId), d.JobID, d.Namespace, d.Status, topDeploymentProgress(d)))
+		}
+		lines = append(lines, strings.Split(formatList(deploys), "\n")...)
+	}
+	lines = append(lines, "", "Jobs")
+
+	if len(data.jobs) == 0 {
+		return append(lines, "No jobs"), nil
+	}
+	jobs := []string{"ID|Namespace|Type|Priority|Status|Queued|Starting|Running|Failed|Lost"}
+	for _, job := range data.jobs {
+		var sum api.TaskGroupSummary
+		if job.JobSummary != nil {
+			for _, tg := range job.JobSummary.Summary {
+				sum.Queued += tg.Queued
+				sum.Starting += tg.Starting
+				sum.Running += tg.Running
+				sum.Failed += tg.Failed
+				sum.Lost += tg.Lost
+			}
+		}
+		jobs = append(jobs, fmt.Sprintf("%s|%s|%s|%d|%s|%d|%d|%d|%d|%d",
+			job.ID, job.Namespace, job.Type, job.Priority, getStatusString(job.Status, &job.Stop),
+			sum.Queued, sum.Starting, sum.Running, sum.Failed, sum.Lost))
+	}
+	return lines, strings.Split(formatList(jobs), "\n")
+}
+
+func renderTopJob(data *topJobData) ([]string, []string) {
+	job := data.job
+
+	var cpuTicks float64
+	var memUsed uint64
+	for _, stats := range data.stats {
+		cpu, mem := topResourceUsage(stats.ResourceUsage)
+		cpuTicks += cpu
+		memUsed += mem
+	}
+
+	status := map[string]int{}
+	for _, alloc := range data.allocs {
+		status[alloc.ClientStatus]++
+	}
+
+	summary := []string{
+		fmt.Sprintf("ID|%s", *job.ID),
+		fmt.Sprintf("Namespace|%s", *job.Namespace),
+		fmt.Sprintf("Type|%s", *job.Type),
+		fmt.Sprintf("Priority|%d", *job.Priority),
+		fmt.Sprintf("Status|%s", getStatusString(*job.Status, job.Stop)),
+		fmt.Sprintf("Allocations|%d running, %d pending, %d failed, %d lost",
+			status[api.AllocClientStatusRunning], status[api.AllocClientStatusPending],
+			status[api.AllocClientStatusFailed], status[api.AllocClientStatusLost]),
+		fmt.Sprintf("CPU|%.0f MHz used", cpuTicks),
+		fmt.Sprintf("Memory|%s used", humanize.IBytes(memUsed)),
+	}
+	if d := data.deployment; d != nil {
+		summary = append(summary, fmt.Sprintf("Deployment|%s %s %s",
+			limit(d.ID, shortId), d.Status, topDeploymentProgress(d)))
+	}
+	lines := strings.Split(formatKV(summary), "\n")
+	lines = append(lines, "", "Allocations")
+
+	if len(data.allocs) == 0 {
+		return append(lines, "No allocations placed"), nil
+	}
+	allocs := []string{"ID|Node Name|Task Group|Version|Desired|Status|Healthy|CPU|Memory"}
+	for _, alloc := range data.allocs {
+		healthy := "-"
+		if ds := alloc.DeploymentStatus; ds != nil && ds.Healthy != nil {
+			healthy = fmt.Sprintf("%t", *ds.Healthy)
+		}
+		cpu, mem := "-", "-"
+		if stats, ok := data.stats[alloc.ID]; ok {
+			ticks, used := topResourceUsage(stats.ResourceUsage)
+			cpu = fmt.Sprintf("%.0f MHz", ticks)
+			mem = humanize.IBytes(used)
+		}
+		allocs = append(allocs, fmt.Sprintf("%s|%s|%s|%d|%s|%s|%s|%s|%s",
+			limit(alloc.ID, shortId), alloc.NodeName, alloc.TaskGroup, alloc.JobVersion,
+			alloc.DesiredStatus, alloc.ClientStatus, healthy, cpu, mem))
+	}
+	return lines, strings.Split(formatList(allocs), "\n")
+}
+
+func renderTopAlloc(data *topAllocData) ([]string, []string) {
+	alloc := data.alloc
+
+	var cpuTicks float64
+	var memUsed uint64
+	if data.stats != nil {
+		cpuTicks, memUsed = topResourceUsage(data.stats.ResourceUsage)
+	}
+	var cpuShares, memoryMB int64
+	if res := alloc.AllocatedResources; res != nil {
+		for _, task := range res.Tasks {
+			cpuShares += task.Cpu.CpuShares
+			memoryMB += task.Memory.MemoryMB
+		}
+	}
+
+	summary := []string{
+		fmt.Sprintf("ID|%s", alloc.ID),
+		fmt.Sprintf("Name|%s", alloc.Name),
+		fmt.Sprintf("Node Name|%s", alloc.NodeName),
+		fmt.Sprintf("Job ID|%s", alloc.JobID),
+		fmt.Sprintf("Client Status|%s", alloc.ClientStatus),
+		fmt.Sprintf("Desired Status|%s", alloc.DesiredStatus),
+		fmt.Sprintf("CPU|%.0f/%d MHz", cpuTicks, cpuShares),
+		fmt.Sprintf("Memory|%s", topUsage(memUsed, uint64(memoryMB)*bytesPerMegabyte)),
+	}
+	lines := strings.Split(formatKV(summary), "\n")
+	lines = append(lines, "", "Tasks")
+
+	names := topTaskNames(alloc)
+	if len(names) == 0 {
+		return append(lines, "No tasks started"), nil
+	}
+	tasks := []string{"Name|State|Restarts|CPU|Memory|Last Event"}
+	for _, name := range names {
+		state := alloc.TaskStates[name]
+
+		cpu, mem := "-", "-"
+		if data.stats != nil {
+			if stats, ok := data.stats.Tasks[name]; ok {
+				ticks, used := topResourceUsage(stats.ResourceUsage)
+				cpu = fmt.Sprintf("%.0f MHz", ticks)
+				mem = humanize.IBytes(used)
+			}
+		}
+
+		event := ""
+		if n := len(state.Events); n > 0 {
+			last := state.Events[n-1]
+			event = fmt.Sprintf("%s: %s", last.Type, last.DisplayMessage)
+		}
+		tasks = append(tasks, fmt.Sprintf("%s|%s|%d|%s|%s|%s",
+			name, state.State, state.Restarts, cpu, mem, event))
+	}
+	return lines, strings.Split(formatList(tasks), "\n")
+}
+
+// topTaskNames returns the sorted names of the tasks of the allocation that
+// have a state.
+func topTaskNames(alloc *api.Allocation) []string {
+	names := make([]string, 0, len(alloc.TaskStates))
+	for name := range alloc.TaskStates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// topResourceUsage returns the CPU usage in MHz and the memory usage in bytes.
+func topResourceUsage(usage *api.ResourceUsage) (float64, uint64) {
+	if usage == nil {
+		return 0, 0
+	}
+	var cpu float64
+	var mem uint64
+	if usage.CpuStats != nil {
+		cpu = usage.CpuStats.TotalTicks
+	}
+	if ms := usage.MemoryStats; ms != nil {
+		mem = ms.RSS
+		if mem == 0 {
+			mem = ms.Usage
+		}
+	}
+	return cpu, mem
+}
+
+// topUsage formats the memory usage against the total.
+func topUsage(used, total uint64) string {
+	if total == 0 {
+		return fmt.Sprintf("%s used", humanize.IBytes(used))
+	}
+	return fmt.Sprintf("%s/%s (%.1f%%)", humanize.IBytes(used), humanize.IBytes(total),
+		float64(used)/float64(total)*100)
+}
+
+// topDeploymentProgress formats the healthy allocations of the deployment
+// against the desired total as a progress bar.
+func topDeploymentProgress(d *api.Deployment) string {
+	var healthy, desired int
+	for _, state := range d.TaskGroups {
+		healthy += state.HealthyAllocs
+		desired += state.DesiredTotal
+	}
+	return topProgressBar(healthy, desired, 20)
+}
+
+// topProgressBar returns a progress bar of the given width.
+func topProgressBar(done, total, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = min(done*width/total, width)
+	}
+	return fmt.Sprintf("[%s%s] %d/%d",
+		strings.Repeat("#", filled), strings.Repeat(".", width-filled), done, total)
+}
@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestTopCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &TopCommand{}
+}
+
+func TestTopCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	ui := cli.NewMockUi()
+	cmd := &TopCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"some", "bad", "args"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), commandErrorText(cmd))
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-interval=0s"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "-interval flag must be positive")
+	ui.ErrorWriter.Reset()
+
+	code = cmd.Run([]string{"-address=nope", "-once"})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "Error querying jobs")
+}
+
+func TestTopCommand_Once(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, _, url := testServer(t, false, nil)
+	defer srv.Shutdown()
+
+	state := srv.Agent.Server().State()
+	alloc := mock.Alloc()
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, alloc.Job))
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+
+	ui := cli.NewMockUi()
+	cmd := &TopCommand{Meta: Meta{Ui: ui}}
+
+	code := cmd.Run([]string{"-address=" + url, "-once"})
+	must.Zero(t, code)
+	out := ui.OutputWriter.String()
+	must.StrContains(t, out, "Evaluations")
+	must.StrContains(t, out, "No active deployments")
+	must.StrContains(t, out, alloc.JobID)
+	ui.OutputWriter.Reset()
+
+	code = cmd.Run([]string{"-address=" + url, "-once", alloc.JobID})
+	must.Zero(t, code)
+	out = ui.OutputWriter.String()
+	must.StrContains(t, out, "Allocations")
+	must.StrContains(t, out, alloc.ID[:shortId])
+}
+
+func TestTopCommand_ParseKeys(t *testing.T) {
+	ci.Parallel(t)
+
+	must.Eq(t, []topKey{topKeyUp, topKeyDown, topKeyEnter, topKeyBack},
+		parseTopKeys([]byte("\x1b[A\x1b[B\x1b[C\x1b[D")))
+	must.Eq(t, []topKey{topKeyDown, topKeyUp, topKeyEnter, topKeyBack, topKeyFollow, topKeyStream, topKeyQuit},
+		parseTopKeys([]byte("jk\r\x1bfsq")))
+	must.Eq(t, []topKey{topKeyQuit}, parseTopKeys([]byte{0x03}))
+	must.Nil(t, parseTopKeys([]byte("xyz")))
+}
+
+func TestTopCommand_Navigation(t *testing.T) {
+	ci.Parallel(t)
+
+	m := newTopModel()
+	m.update(&topSnapshot{cluster: &topClusterData{
+		jobs: []*api.JobListStub{
+			{ID: "api", Namespace: "default"},
+			{ID: "web", Namespace: "prod"},
+		},
+	}})
+
+	must.Eq(t, topActionNone, m.handleKey(topKeyDown))
+	must.Eq(t, topActionNone, m.handleKey(topKeyDown))
+	must.Eq(t, 1, m.cursor[topViewCluster])
+
+	// Drilling down drops the data of the previous view
+	must.Eq(t, topActionRefresh, m.handleKey(topKeyEnter))
+	must.Eq(t, topViewJob, m.view)
+	must.Eq(t, "web", m.jobID)
+	must.Eq(t, "prod", m.namespace)
+	must.Nil(t, m.snap)
+
+	// Snapshots fetched for a previous view are dropped
+	m.update(&topSnapshot{gen: m.gen - 1, cluster: &topClusterData{}})
+	must.Nil(t, m.snap)
+
+	m.update(&topSnapshot{gen: m.gen, job: &topJobData{
+		allocs: []*api.AllocationListStub{{ID: "1234"}},
+	}})
+	must.Eq(t, topActionRefresh, m.handleKey(topKeyEnter))
+	must.Eq(t, topViewAlloc, m.view)
+	must.Eq(t, "1234", m.allocID)
+
+	alloc := &api.Allocation{ID: "1234", TaskStates: map[string]*api.TaskState{
+		"web":     {State: "running"},
+		"sidecar": {State: "running"},
+	}}
+	m.update(&topSnapshot{gen: m.gen, alloc: &topAllocData{alloc: alloc}})
+	m.handleKey(topKeyDown)
+	must.Eq(t, topActionFollow, m.handleKey(topKeyFollow))
+	must.Eq(t, topViewLogs, m.view)
+	must.Eq(t, "web", m.task)
+	must.Eq(t, "stdout", m.logType)
+
+	must.Eq(t, topActionFollow, m.handleKey(topKeyStream))
+	must.Eq(t, "stderr", m.logType)
+
+	// Going back restores the selection of each view
+	must.Eq(t, topActionUnfollow, m.handleKey(topKeyBack))
+	must.Eq(t, topViewAlloc, m.view)
+	must.Eq(t, topActionRefresh, m.handleKey(topKeyBack))
+	must.Eq(t, topActionRefresh, m.handleKey(topKeyBack))
+	must.Eq(t, topViewCluster, m.view)
+	must.Eq(t, 1, m.cursor[topViewCluster])
+
+	must.Eq(t, topActionQuit, m.handleKey(topKeyQuit))
+}
+
+func TestTopCommand_Layout(t *testing.T) {
+	ci.Parallel(t)
+
+	s := &topScreen{
+		title:  "Cluster",
+		lines:  []string{"summary"},
+		table:  []string{"ID", "a", "b", "c", "d", "e"},
+		cursor: 4,
+		footer: "keys",
+	}
+
+	// Without a size everything is laid out
+	out := s.layout(0, 0, false)
+	must.Eq(t, []string{"Cluster", "", "summary", "", "ID", "a", "b", "c", "d", "e", "", "keys"}, out)
+
+	// The table scrolls to keep the selected row visible
+	out = s.layout(4, 9, true)
+	must.Eq(t, []string{"Clus", "", "summ", "", "ID", "d", topReverse + "e   " + topReset, "", "keys"}, out)
+
+	// Logs keep their last lines
+	s = &topScreen{title: "Logs", lines: []string{"1", "2", "3", "4"}, tail: true}
+	must.Eq(t, []string{"Logs", "", "3", "4"}, s.layout(10, 4, true))
+}
+
+func TestTopCommand_LogBuffer(t *testing.T) {
+	ci.Parallel(t)
+
+	var b topLogBuffer
+	b.write([]byte("one\r\ntw"))
+	b.write([]byte("o\nthree"))
+	must.Eq(t, []string{"one", "two"}, b.lines)
+	must.Eq(t, "three", b.partial)
+
+	b.write([]byte(strings.Repeat("x\n", topMaxLogLines)))
+	must.Len(t, topMaxLogLines, b.lines)
+	must.Eq(t, "threex", b.lines[0])
+}
+
+func TestTopCommand_ProgressBar(t *testing.T) {
+	ci.Parallel(t)
+
+	must.Eq(t, "[..........] 0/0", topProgressBar(0, 0, 10))
+	must.Eq(t, "[#####.....] 2/4", topProgressBar(2, 4, 10))
+	must.Eq(t, "[##########] 5/4", topProgressBar(5, 4, 10))
+}
@@ -60,6 +60,18 @@ Usage: nomad var <subcommand> [options] [args]
 
       $ nomad var purge <path>
 
+  List the versions of a variable:
+
+      $ nomad var versions <path>
+
+  Restore a prior version of a variable:
+
+      $ nomad var restore -version <version> <path>
+
+  Share variables with the workloads of other namespaces:
+
+      $ nomad var grant apply -path <path> -grant-namespace <namespace> <name>
+
   Please see the individual subcommand help for detailed usage information.
 `
 
@@ -109,6 +121,15 @@ func renderSVAsUiTable(sv *api.Variable, c VarUI) {
 	if sv.CreateTime != sv.ModifyTime {
 		meta = append(meta, fmt.Sprintf("Modify Time|%v", time.Unix(0, sv.ModifyTime)))
 	}
+	if sv.ExpireTime != 0 {
+		meta = append(meta, fmt.Sprintf("Expire Time|%v", formatUnixNanoTime(sv.ExpireTime)))
+	}
+	if sv.DeleteTime != 0 {
+		meta = append(meta, fmt.Sprintf("Delete Time|%v", formatUnixNanoTime(sv.DeleteTime)))
+	}
+	if sv.Version != 0 {
+		meta = append(meta, fmt.Sprintf("Version|%v", sv.Version))
+	}
 	meta = append(meta, fmt.Sprintf("Check Index|%v", sv.ModifyIndex))
 	ui := c.GetConcurrentUI()
 	ui.Output(formatKV(meta))
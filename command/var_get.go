@@ -49,6 +49,12 @@ Get Options:
   -template
      Template to render output with. Required when output is "go-template".
 
+  -version <version>
+     Get the given version of the variable instead of its current version.
+     Prior versions of deleted variables can be read until the server's
+     deleted variables retention period has passed. Use 'nomad var versions'
+     to list the versions of a variable.
+
 `
 	return strings.TrimSpace(helpText)
 }
@@ -58,6 +64,7 @@ func (c *VarGetCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-out":      complete.PredictSet("go-template", "hcl", "json", "none", "table"),
 			"-template": complete.PredictAnything,
+			"-version":  complete.PredictAnything,
 		},
 	)
 }
@@ -74,12 +81,14 @@ func (c *VarGetCommand) Name() string { return "var get" }
 
 func (c *VarGetCommand) Run(args []string) int {
 	var out, item string
+	var version uint64
 
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 
 	flags.StringVar(&item, "item", "", "")
 	flags.StringVar(&c.tmpl, "template", "", "")
+	flags.Uint64Var(&version, "version", 0, "")
 
 	if fileInfo, _ := os.Stdout.Stat(); (fileInfo.Mode() & os.ModeCharDevice) != 0 {
 		flags.StringVar(&c.outFmt, "out", "table", "")
@@ -123,7 +132,12 @@ func (c *VarGetCommand) Run(args []string) int {
 		Namespace: c.Meta.namespace,
 	}
 
-	sv, _, err := client.Variables().Read(path, qo)
+	var sv *api.Variable
+	if version != 0 {
+		sv, _, err = client.Variables().ReadVersion(path, version, qo)
+	} else {
+		sv, _, err = client.Variables().Read(path, qo)
+	}
 	if err != nil {
 		if err.Error() == "variable not found" {
 			c.Ui.Warn(errVariableNotFound)
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+type VarGrantCommand struct {
+	Meta
+}
+
+func (c *VarGrantCommand) Name() string {
+	return "var grant"
+}
+
+func (c *VarGrantCommand) Synopsis() string {
+	return "Interact with variable grants"
+}
+
+func (c *VarGrantCommand) Help() string {
+	helpText := `
+Usage: nomad var grant <subcommand> [options] [args]
+
+  This command groups subcommands for interacting with variable grants.
+  Variable grants share the variables at a path of a namespace with the
+  workloads of other namespaces, which can then read them, for example with
+  the "nomadVar" template function and the "path@namespace" syntax.
+
+  Create or update a variable grant:
+
+    $ nomad var grant apply -namespace=platform -path="shared/*" \
+        -grant-namespace=team-a <name>
+
+  List the variable grants of a namespace:
+
+    $ nomad var grant list -namespace=platform
+
+  Delete a variable grant:
+
+    $ nomad var grant delete -namespace=platform <name>
+
+  Please refer to individual subcommand help for detailed usage information.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarGrantCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func formatVarGrantList(grants []*api.VariableGrant) string {
+	out := make([]string, len(grants)+1)
+	out[0] = "Namespace|Name|Path|Granted Namespaces|Jobs"
+	for i, g := range grants {
+		jobs := "<all>"
+		if len(g.Jobs) > 0 {
+			jobs = strings.Join(g.Jobs, ",")
+		}
+		out[i+1] = fmt.Sprintf("%s|%s|%s|%s|%s",
+			g.Namespace,
+			g.Name,
+			g.Path,
+			strings.Join(g.Namespaces, ","),
+			jobs,
+		)
+	}
+	return formatList(out)
+}
+
+func varGrantPredictor(factory ApiClientFactory) complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := factory()
+		if err != nil {
+			return nil
+		}
+
+		grants, _, err := client.VariableGrants().List(nil)
+		if err != nil {
+			return nil
+		}
+
+		var names []string
+		for _, g := range grants {
+			if strings.HasPrefix(g.Name, a.Last) {
+				names = append(names, g.Name)
+			}
+		}
+		return names
+	})
+}
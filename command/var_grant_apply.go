@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/posener/complete"
+)
+
+type VarGrantApplyCommand struct {
+	Meta
+}
+
+func (c *VarGrantApplyCommand) Name() string {
+	return "var grant apply"
+}
+
+func (c *VarGrantApplyCommand) Synopsis() string {
+	return "Create or update a variable grant"
+}
+
+func (c *VarGrantApplyCommand) Help() string {
+	helpText := `
+Usage: nomad var grant apply [options] <name>
+
+  Apply is used to create or update a variable grant. The grant shares the
+  variables of the command's namespace that match its path with the workloads
+  of the granted namespaces. Granted workloads can read and list the variables,
+  but not modify them. Grants don't apply to ACL tokens.
+
+  If ACLs are enabled, this command requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Apply Options:
+
+  -description
+    A human-friendly description of the grant.
+
+  -grant-namespace
+    A namespace whose workloads are granted access to the variables. The "*"
+    namespace grants access to the workloads of all the namespaces. This
+    option must be specified at least once and can be specified multiple
+    times.
+
+  -job
+    Restricts the grant to the workloads of the given job of the granted
+    namespaces. This option can be specified multiple times.
+
+  -path
+    The path of the shared variables. It may contain glob patterns, such as
+    "shared/*". Required.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarGrantApplyCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-description":     complete.PredictAnything,
+			"-grant-namespace": NamespacePredictor(c.Meta.Client, nil),
+			"-job":             complete.PredictAnything,
+			"-path":            complete.PredictAnything,
+		})
+}
+
+func (c *VarGrantApplyCommand) AutocompleteArgs() complete.Predictor {
+	return varGrantPredictor(c.Client)
+}
+
+func (c *VarGrantApplyCommand) Run(args []string) int {
+	var description, path string
+	var namespaces, jobs []string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&description, "description", "", "")
+	flags.Var((*flaghelper.StringFlag)(&namespaces), "grant-namespace", "")
+	flags.Var((*flaghelper.StringFlag)(&jobs), "job", "")
+	flags.StringVar(&path, "path", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we only have one argument.
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	name := args[0]
+
+	if path == "" {
+		c.Ui.Error("The -path option is required")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if len(namespaces) == 0 {
+		c.Ui.Error("The -grant-namespace option must be specified at least once")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	if c.Meta.namespace == "*" {
+		c.Ui.Error(errWildcardNamespaceNotAllowed)
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	grant := &api.VariableGrant{
+		Name:        name,
+		Description: description,
+		Path:        path,
+		Namespaces:  namespaces,
+		Jobs:        jobs,
+	}
+	if _, err := client.VariableGrants().Register(grant, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error applying variable grant: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully applied variable grant %q!", name))
+	return 0
+}
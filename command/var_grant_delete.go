@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/posener/complete"
+)
+
+type VarGrantDeleteCommand struct {
+	Meta
+}
+
+func (c *VarGrantDeleteCommand) Name() string {
+	return "var grant delete"
+}
+
+func (c *VarGrantDeleteCommand) Synopsis() string {
+	return "Delete a variable grant"
+}
+
+func (c *VarGrantDeleteCommand) Help() string {
+	helpText := `
+Usage: nomad var grant delete [options] <name>
+
+  Delete is used to remove a variable grant of the command's namespace. The
+  workloads of the granted namespaces immediately lose access to the shared
+  variables.
+
+  If ACLs are enabled, this command requires a management token.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault)
+
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarGrantDeleteCommand) AutocompleteFlags() complete.Flags {
+	return c.Meta.AutocompleteFlags(FlagSetClient)
+}
+
+func (c *VarGrantDeleteCommand) AutocompleteArgs() complete.Predictor {
+	return varGrantPredictor(c.Client)
+}
+
+func (c *VarGrantDeleteCommand) Run(args []string) int {
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we only have one argument.
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <name>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	name := args[0]
+
+	if c.Meta.namespace == "*" {
+		c.Ui.Error(errWildcardNamespaceNotAllowed)
+		return 1
+	}
+
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	if _, err := client.VariableGrants().Delete(name, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error deleting variable grant: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully deleted variable grant %q!", name))
+	return 0
+}
@@ -21,7 +21,10 @@ func (c *VarPurgeCommand) Help() string {
 	helpText := `
 Usage: nomad var purge [options] <path>
 
-  Purge is used to permanently delete an existing variable.
+  Purge is used to delete an existing variable. Unless variable history is
+  disabled on the servers, the deleted variable is kept until the servers'
+  deleted variables retention period has passed and can be restored with
+  'nomad var restore' until then.
 
   If ACLs are enabled, this command requires a token with the 'variables:destroy'
   capability for the target variable's namespace and path.
@@ -13,6 +13,7 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-set/v2"
@@ -98,6 +99,11 @@ Var put Options:
      Template to render output with. Required when format is "go-template",
      invalid for other formats.
 
+  -ttl
+     Time-to-live of the variable, such as "1h". The variable is deleted once
+     the TTL has passed since it was last written. The TTL overrides any TTL
+     in the variable specification.
+
   -verbose
      Provides additional information via standard error to preserve standard
      output (stdout) for redirected output.
@@ -111,6 +117,7 @@ func (c *VarPutCommand) AutocompleteFlags() complete.Flags {
 		complete.Flags{
 			"-in":  complete.PredictSet("hcl", "json"),
 			"-out": complete.PredictSet("none", "hcl", "json", "go-template", "table"),
+			"-ttl": complete.PredictAnything,
 		},
 	)
 }
@@ -127,7 +134,7 @@ func (c *VarPutCommand) Name() string { return "var put" }
 
 func (c *VarPutCommand) Run(args []string) int {
 	var force, enforce, doVerbose bool
-	var path, checkIndexStr string
+	var path, checkIndexStr, ttlStr string
 	var checkIndex uint64
 	var err error
 
@@ -139,6 +146,7 @@ func (c *VarPutCommand) Run(args []string) int {
 	flags.StringVar(&checkIndexStr, "check-index", "", "")
 	flags.StringVar(&c.inFmt, "in", "json", "")
 	flags.StringVar(&c.tmpl, "template", "", "")
+	flags.StringVar(&ttlStr, "ttl", "", "")
 
 	if fileInfo, _ := os.Stdout.Stat(); (fileInfo.Mode() & os.ModeCharDevice) != 0 {
 		flags.StringVar(&c.outFmt, "out", "none", "")
@@ -306,6 +314,16 @@ func (c *VarPutCommand) Run(args []string) int {
 			sv.Items[k] = vs
 		}
 	}
+
+	if ttlStr != "" {
+		ttl, err := time.ParseDuration(ttlStr)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Error parsing ttl value %q: %v", ttlStr, err))
+			return 1
+		}
+		sv.TTL = ttl
+	}
+
 	// Get the HTTP client
 	client, err := c.Meta.Client()
 	if err != nil {
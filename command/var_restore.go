@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarRestoreCommand struct {
+	Meta
+}
+
+func (c *VarRestoreCommand) Help() string {
+	helpText := `
+Usage: nomad var restore [options] <path>
+
+  The 'var restore' command restores a prior version of a variable. The items
+  of the prior version are written as a new version of the variable, so the
+  restore can itself be undone. Deleted variables can be restored until the
+  servers' deleted variables retention period has passed.
+
+  If ACLs are enabled, this command requires a token with the 'variables:write'
+  capability for the target variable's namespace and path.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Restore Options:
+
+  -version <version>
+    The prior version of the variable to restore. Required. Use
+    'nomad var versions' to list the versions of a variable.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarRestoreCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-version": complete.PredictAnything,
+		},
+	)
+}
+
+func (c *VarRestoreCommand) AutocompleteArgs() complete.Predictor {
+	return VariablePathPredictor(c.Meta.Client)
+}
+
+func (c *VarRestoreCommand) Synopsis() string {
+	return "Restore a prior version of a variable"
+}
+
+func (c *VarRestoreCommand) Name() string { return "var restore" }
+
+func (c *VarRestoreCommand) Run(args []string) int {
+	var version uint64
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.Uint64Var(&version, "version", 0, "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if version == 0 {
+		c.Ui.Error("The -version option is required")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if c.Meta.namespace == "*" {
+		c.Ui.Error(errWildcardNamespaceNotAllowed)
+		return 1
+	}
+
+	path := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	meta, _, err := client.Variables().Restore(path, version, &api.WriteOptions{
+		Namespace: c.Meta.namespace,
+	})
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error restoring variable: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf(
+		"Successfully restored version %d of variable %q as version %d!",
+		version, path, meta.Version))
+	return 0
+}
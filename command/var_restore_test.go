@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestVarRestoreCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &VarRestoreCommand{}
+}
+
+func TestVarRestoreCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	t.Run("bad_args", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarRestoreCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-version=1", "some", "bad", "args"})
+		out := ui.ErrorWriter.String()
+		must.One(t, code)
+		must.StrContains(t, out, commandErrorText(cmd))
+	})
+	t.Run("missing_version", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarRestoreCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"foo"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), "The -version option is required")
+	})
+	t.Run("bad_address", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarRestoreCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=nope", "-version=1", "foo"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), "restoring variable")
+		must.Eq(t, "", ui.OutputWriter.String())
+	})
+}
+
+func TestVarRestoreCommand_Online(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Write the variable twice and then delete it
+	sv := testVariable()
+	sv, _, err := client.Variables().Create(sv, nil)
+	must.NoError(t, err)
+	sv.Items["k3"] = "v3"
+	_, _, err = client.Variables().Update(sv, nil)
+	must.NoError(t, err)
+	_, err = client.Variables().Delete(sv.Path, nil)
+	must.NoError(t, err)
+
+	ui := cli.NewMockUi()
+	cmd := &VarRestoreCommand{Meta: Meta{Ui: ui}}
+	code := cmd.Run([]string{"-address=" + url, "-version=1", sv.Path})
+	must.Zero(t, code, must.Sprintf("stderr: %s", ui.ErrorWriter.String()))
+	must.StrContains(t, ui.OutputWriter.String(), "as version 3")
+
+	restored, _, err := client.Variables().Read(sv.Path, nil)
+	must.NoError(t, err)
+	must.Eq(t, 3, restored.Version)
+	must.MapNotContainsKey(t, restored.Items, "k3")
+
+	// Unknown versions can't be restored
+	ui = cli.NewMockUi()
+	cmd = &VarRestoreCommand{Meta: Meta{Ui: ui}}
+	code = cmd.Run([]string{"-address=" + url, "-version=10", sv.Path})
+	must.One(t, code)
+	must.StrContains(t, ui.ErrorWriter.String(), "not found")
+}
@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/posener/complete"
+)
+
+type VarVersionsCommand struct {
+	Meta
+}
+
+func (c *VarVersionsCommand) Help() string {
+	helpText := `
+Usage: nomad var versions [options] <path>
+
+  The 'var versions' command lists the current and prior versions of a
+  variable, newest first. The versions of a deleted variable are listed until
+  the servers' deleted variables retention period has passed.
+
+  If ACLs are enabled, this command requires a token with the 'variables:list'
+  capability for the target variable's namespace and path.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Versions Options:
+
+  -json
+    Output the versions in their JSON format.
+
+  -t
+    Format and display the versions using a Go template.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VarVersionsCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-json": complete.PredictNothing,
+			"-t":    complete.PredictAnything,
+		},
+	)
+}
+
+func (c *VarVersionsCommand) AutocompleteArgs() complete.Predictor {
+	return VariablePathPredictor(c.Meta.Client)
+}
+
+func (c *VarVersionsCommand) Synopsis() string {
+	return "List the versions of a variable"
+}
+
+func (c *VarVersionsCommand) Name() string { return "var versions" }
+
+func (c *VarVersionsCommand) Run(args []string) int {
+	var json bool
+	var tmpl string
+
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.BoolVar(&json, "json", false, "")
+	flags.StringVar(&tmpl, "t", "", "")
+
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	// Check that we got one argument
+	args = flags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("This command takes one argument: <path>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+
+	if c.Meta.namespace == "*" {
+		c.Ui.Error(errWildcardNamespaceNotAllowed)
+		return 1
+	}
+
+	path := args[0]
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	versions, _, err := client.Variables().Versions(path, &api.QueryOptions{
+		Namespace: c.Meta.namespace,
+	})
+	if err != nil {
+		if errors.Is(err, api.ErrVariablePathNotFound) {
+			c.Ui.Warn(errVariableNotFound)
+			return 1
+		}
+		c.Ui.Error(fmt.Sprintf("Error retrieving variable versions: %s", err))
+		return 1
+	}
+
+	if json || len(tmpl) > 0 {
+		out, err := Format(json, tmpl, versions)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		c.Ui.Output(out)
+		return 0
+	}
+
+	c.Ui.Output(formatVarVersions(versions))
+	return 0
+}
+
+func formatVarVersions(versions []*api.VariableMetadata) string {
+	rows := make([]string, len(versions)+1)
+	rows[0] = "Version|Status|Last Updated"
+	for i, v := range versions {
+		status := "current"
+		switch {
+		case v.DeleteTime != 0:
+			status = fmt.Sprintf("deleted %s", formatUnixNanoTime(v.DeleteTime))
+		case i > 0:
+			status = "prior"
+		}
+		rows[i+1] = fmt.Sprintf("%d|%s|%s",
+			v.Version,
+			status,
+			formatUnixNanoTime(v.ModifyTime),
+		)
+	}
+	return formatList(rows)
+}
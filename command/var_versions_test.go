@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/mitchellh/cli"
+	"github.com/shoenig/test/must"
+)
+
+func TestVarVersionsCommand_Implements(t *testing.T) {
+	ci.Parallel(t)
+	var _ cli.Command = &VarVersionsCommand{}
+}
+
+func TestVarVersionsCommand_Fails(t *testing.T) {
+	ci.Parallel(t)
+	t.Run("bad_args", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarVersionsCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"some", "bad", "args"})
+		out := ui.ErrorWriter.String()
+		must.One(t, code)
+		must.StrContains(t, out, commandErrorText(cmd))
+	})
+	t.Run("bad_address", func(t *testing.T) {
+		ci.Parallel(t)
+		ui := cli.NewMockUi()
+		cmd := &VarVersionsCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=nope", "foo"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), "retrieving variable versions")
+		must.Eq(t, "", ui.OutputWriter.String())
+	})
+}
+
+func TestVarVersionsCommand_Online(t *testing.T) {
+	ci.Parallel(t)
+
+	// Create a server
+	srv, client, url := testServer(t, true, nil)
+	defer srv.Shutdown()
+
+	// Write the variable twice so that it has a prior version
+	sv := testVariable()
+	sv, _, err := client.Variables().Create(sv, nil)
+	must.NoError(t, err)
+	sv.Items["k3"] = "v3"
+	_, _, err = client.Variables().Update(sv, nil)
+	must.NoError(t, err)
+
+	t.Run("table", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarVersionsCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, sv.Path})
+		must.Zero(t, code, must.Sprintf("stderr: %s", ui.ErrorWriter.String()))
+		out := ui.OutputWriter.String()
+		must.StrContains(t, out, "current")
+		must.StrContains(t, out, "prior")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarVersionsCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "-json", sv.Path})
+		must.Zero(t, code, must.Sprintf("stderr: %s", ui.ErrorWriter.String()))
+
+		var versions []*api.VariableMetadata
+		must.NoError(t, json.Unmarshal(ui.OutputWriter.Bytes(), &versions))
+		must.Len(t, 2, versions)
+		must.Eq(t, 2, versions[0].Version)
+		must.Eq(t, 1, versions[1].Version)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := &VarVersionsCommand{Meta: Meta{Ui: ui}}
+		code := cmd.Run([]string{"-address=" + url, "does/not/exist"})
+		must.One(t, code)
+		must.StrContains(t, ui.ErrorWriter.String(), errVariableNotFound)
+	})
+}
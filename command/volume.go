@@ -35,6 +35,10 @@ Usage: nomad volume <subcommand> [options]
 
       $ nomad volume detach <vol id> <node id>
 
+  Expand a volume:
+
+      $ nomad volume expand -capacity-min 20GiB <vol id>
+
   Create an external volume and register it:
 
       $ nomad volume create <input>
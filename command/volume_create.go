@@ -20,13 +20,15 @@ func (c *VolumeCreateCommand) Help() string {
 	helpText := `
 Usage: nomad volume create [options] <input>
 
-  Creates a volume in an external storage provider and registers it in Nomad.
+  Creates a volume in an external storage provider and registers it in Nomad,
+  or creates a host volume on a client with a host volume plugin.
 
   If the supplied path is "-" the volume file is read from stdin. Otherwise, it
   is read from the file at the supplied path.
 
   When ACLs are enabled, this command requires a token with the
-  'csi-write-volume' capability for the volume's namespace.
+  'csi-write-volume' capability for the volume's namespace. Creating a host
+  volume requires a token with the 'node:write' capability.
 
 General Options:
 
@@ -101,6 +103,8 @@ func (c *VolumeCreateCommand) Run(args []string) int {
 	case "csi":
 		code := c.csiCreate(client, ast)
 		return code
+	case "host":
+		return c.hostCreate(client, ast)
 	default:
 		c.Ui.Error(fmt.Sprintf("Error unknown volume type: %s", volType))
 		return 1
@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/mitchellh/mapstructure"
+)
+
+func (c *VolumeCreateCommand) hostCreate(client *api.Client, ast *ast.File) int {
+	req, err := hostDecodeVolume(ast)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error decoding the volume definition: %s", err))
+		return 1
+	}
+
+	resp, _, err := client.HostVolumes().Create(req, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error creating volume: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Created host volume %s on node %s at %s",
+		resp.Name, resp.NodeID, resp.Path))
+	if resp.CapacityBytes > 0 {
+		c.Ui.Output(fmt.Sprintf("Capacity: %s", humanize.IBytes(uint64(resp.CapacityBytes))))
+	}
+	return 0
+}
+
+func hostDecodeVolume(input *ast.File) (*api.HostVolumeCreateRequest, error) {
+	list, ok := input.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("error parsing: root should be an object")
+	}
+
+	valid := []string{
+		"type", "name", "plugin_id", "node_id", "node_pool",
+		"capacity_min", "capacity_max", "parameters",
+	}
+	if err := helper.CheckHCLKeys(list, valid); err != nil {
+		return nil, err
+	}
+
+	// Decode the full thing into a map[string]interface for ease
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, list); err != nil {
+		return nil, err
+	}
+
+	// Need to manually parse these fields
+	delete(m, "capacity_max")
+	delete(m, "capacity_min")
+	delete(m, "type")
+
+	var spec struct {
+		Name       string            `mapstructure:"name"`
+		PluginID   string            `mapstructure:"plugin_id"`
+		NodeID     string            `mapstructure:"node_id"`
+		NodePool   string            `mapstructure:"node_pool"`
+		Parameters map[string]string `mapstructure:"parameters"`
+	}
+	if err := mapstructure.WeakDecode(m, &spec); err != nil {
+		return nil, err
+	}
+
+	req := &api.HostVolumeCreateRequest{
+		Name:       spec.Name,
+		PluginID:   spec.PluginID,
+		NodeID:     spec.NodeID,
+		NodePool:   spec.NodePool,
+		Parameters: spec.Parameters,
+	}
+
+	capacityMin, err := parseCapacityBytes(list.Filter("capacity_min"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid capacity_min: %v", err)
+	}
+	req.RequestedCapacityMinBytes = capacityMin
+	capacityMax, err := parseCapacityBytes(list.Filter("capacity_max"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid capacity_max: %v", err)
+	}
+	req.RequestedCapacityMaxBytes = capacityMax
+
+	return req, nil
+}
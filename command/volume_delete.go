@@ -28,9 +28,14 @@ Usage: nomad volume delete [options] <vol id>
   unpublished. If the volume no longer exists, this command will silently
   return without an error.
 
+  With -type host, deletes a host volume created by a host volume plugin on
+  the node given by -node. Deleting will fail if an allocation on the node
+  uses the volume.
+
   When ACLs are enabled, this command requires a token with the
   'csi-write-volume' and 'csi-read-volume' capabilities for the volume's
-  namespace.
+  namespace. Deleting a host volume requires a token with the 'node:write'
+  capability.
 
 General Options:
 
@@ -41,13 +46,23 @@ Delete Options:
   -secret
     Secrets to pass to the plugin to delete the snapshot. Accepts multiple
     flags in the form -secret key=value
+
+  -type <type>
+    Type of the volume to delete, either "csi" or "host". Defaults to "csi".
+
+  -node <node id>
+    ID of the node with the host volume to delete. Required with -type host.
 `
 	return strings.TrimSpace(helpText)
 }
 
 func (c *VolumeDeleteCommand) AutocompleteFlags() complete.Flags {
 	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
-		complete.Flags{})
+		complete.Flags{
+			"-secret": complete.PredictAnything,
+			"-type":   complete.PredictSet("csi", "host"),
+			"-node":   complete.PredictAnything,
+		})
 }
 
 func (c *VolumeDeleteCommand) AutocompleteArgs() complete.Predictor {
@@ -80,9 +95,12 @@ func (c *VolumeDeleteCommand) Name() string { return "volume delete" }
 
 func (c *VolumeDeleteCommand) Run(args []string) int {
 	var secretsArgs flaghelper.StringFlag
+	var volType, nodeID string
 	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
 	flags.Usage = func() { c.Ui.Output(c.Help()) }
 	flags.Var(&secretsArgs, "secret", "secrets for snapshot, ex. -secret key=value")
+	flags.StringVar(&volType, "type", "csi", "")
+	flags.StringVar(&nodeID, "node", "", "")
 
 	if err := flags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing arguments %s", err))
@@ -105,6 +123,15 @@ func (c *VolumeDeleteCommand) Run(args []string) int {
 		return 1
 	}
 
+	switch strings.ToLower(volType) {
+	case "csi":
+	case "host":
+		return c.deleteHostVolume(client, nodeID, volID)
+	default:
+		c.Ui.Error(fmt.Sprintf("Error unknown volume type: %s", volType))
+		return 1
+	}
+
 	secrets := api.CSISecrets{}
 	for _, kv := range secretsArgs {
 		if key, value, found := strings.Cut(kv, "="); found {
@@ -127,3 +154,24 @@ func (c *VolumeDeleteCommand) Run(args []string) int {
 	c.Ui.Output(fmt.Sprintf("Successfully deleted volume %q!", volID))
 	return 0
 }
+
+func (c *VolumeDeleteCommand) deleteHostVolume(client *api.Client, nodeID, name string) int {
+	if nodeID == "" {
+		c.Ui.Error("The -node flag is required to delete a host volume")
+		return 1
+	}
+
+	node, err := lookupNodeID(client.Nodes(), nodeID)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if _, err := client.HostVolumes().Delete(node, name, nil); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error deleting volume: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Successfully deleted host volume %q on node %s", name, node))
+	return 0
+}
@@ -0,0 +1,160 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/hashicorp/nomad/api"
+	"github.com/hashicorp/nomad/api/contexts"
+	flaghelper "github.com/hashicorp/nomad/helper/flags"
+	"github.com/posener/complete"
+)
+
+type VolumeExpandCommand struct {
+	Meta
+}
+
+func (c *VolumeExpandCommand) Help() string {
+	helpText := `
+Usage: nomad volume expand [options] <vol id>
+
+  Expand a CSI volume registered with Nomad. The storage provider grows the
+  volume to at least the requested capacity and, if the plugin requires it,
+  the filesystem of the volume is expanded on the nodes it is mounted on. The
+  allocations using the volume keep running while it is expanded. Volumes
+  cannot be shrunk.
+
+  When ACLs are enabled, this command requires a token with the
+  'csi-write-volume' and 'csi-read-volume' capabilities for the volume's
+  namespace.
+
+General Options:
+
+  ` + generalOptionsUsage(usageOptsDefault) + `
+
+Expand Options:
+
+  -capacity-min
+    The minimum capacity of the volume after expansion, in bytes or with a
+    unit such as "20GiB". Required.
+
+  -capacity-max
+    The maximum capacity of the volume after expansion. Defaults to the
+    current maximum capacity of the volume, or no maximum if the current one
+    is less than -capacity-min.
+
+  -secret
+    Secrets to pass to the plugin to expand the volume. Accepts multiple
+    flags in the form -secret key=value
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *VolumeExpandCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.Meta.AutocompleteFlags(FlagSetClient),
+		complete.Flags{
+			"-capacity-min": complete.PredictAnything,
+			"-capacity-max": complete.PredictAnything,
+			"-secret":       complete.PredictNothing,
+		})
+}
+
+func (c *VolumeExpandCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		client, err := c.Meta.Client()
+		if err != nil {
+			return nil
+		}
+
+		resp, _, err := client.Search().PrefixSearch(a.Last, contexts.Volumes, nil)
+		if err != nil {
+			return []string{}
+		}
+		return resp.Matches[contexts.Volumes]
+	})
+}
+
+func (c *VolumeExpandCommand) Synopsis() string {
+	return "Expand a volume"
+}
+
+func (c *VolumeExpandCommand) Name() string { return "volume expand" }
+
+func (c *VolumeExpandCommand) Run(args []string) int {
+	var capacityMin, capacityMax string
+	var secretsArgs flaghelper.StringFlag
+	flags := c.Meta.FlagSet(c.Name(), FlagSetClient)
+	flags.Usage = func() { c.Ui.Output(c.Help()) }
+	flags.StringVar(&capacityMin, "capacity-min", "", "")
+	flags.StringVar(&capacityMax, "capacity-max", "", "")
+	flags.Var(&secretsArgs, "secret", "secrets for the plugin, ex. -secret key=value")
+
+	if err := flags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing arguments %s", err))
+		return 1
+	}
+
+	// Check that we get exactly one argument
+	args = flags.Args()
+	if l := len(args); l != 1 {
+		c.Ui.Error("This command takes one argument: <vol id>")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	volID := args[0]
+
+	if capacityMin == "" {
+		c.Ui.Error("The -capacity-min flag is required")
+		c.Ui.Error(commandErrorText(c))
+		return 1
+	}
+	minBytes, err := humanize.ParseBytes(capacityMin)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Invalid -capacity-min value: %s", err))
+		return 1
+	}
+	var maxBytes uint64
+	if capacityMax != "" {
+		maxBytes, err = humanize.ParseBytes(capacityMax)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Invalid -capacity-max value: %s", err))
+			return 1
+		}
+	}
+
+	secrets := api.CSISecrets{}
+	for _, kv := range secretsArgs {
+		if key, value, found := strings.Cut(kv, "="); found {
+			secrets[key] = value
+		} else {
+			c.Ui.Error("Secret must be in the format: -secret key=value")
+			return 1
+		}
+	}
+
+	// Get the HTTP client
+	client, err := c.Meta.Client()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error initializing client: %s", err))
+		return 1
+	}
+
+	resp, _, err := client.CSIVolumes().Expand(&api.CSIVolumeExpandRequest{
+		VolumeID:             volID,
+		RequestedCapacityMin: int64(minBytes),
+		RequestedCapacityMax: int64(maxBytes),
+		Secrets:              secrets,
+	}, nil)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Error expanding volume: %s", err))
+		return 1
+	}
+
+	c.Ui.Output(fmt.Sprintf("Volume %q expanded to %s",
+		volID, humanize.IBytes(uint64(resp.CapacityBytes))))
+	return 0
+}
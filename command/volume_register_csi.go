@@ -53,6 +53,7 @@ func csiDecodeVolume(input *ast.File) (*api.CSIVolume, error) {
 	delete(m, "capacity_max")
 	delete(m, "capacity_min")
 	delete(m, "topology_request")
+	delete(m, "snapshot_schedule")
 	delete(m, "type")
 
 	// Decode the rest
@@ -163,6 +164,40 @@ func csiDecodeVolume(input *ast.File) (*api.CSIVolume, error) {
 		}
 	}
 
+	schedObj := list.Filter("snapshot_schedule")
+	if len(schedObj.Items) > 0 {
+		for _, o := range schedObj.Elem().Items {
+			valid := []string{"interval", "retain", "parameters"}
+			if err := helper.CheckHCLKeys(o.Val, valid); err != nil {
+				return nil, err
+			}
+
+			ot, ok := o.Val.(*ast.ObjectType)
+			if !ok {
+				break
+			}
+
+			var m map[string]interface{}
+			if err := hcl.DecodeObject(&m, ot.List); err != nil {
+				return nil, err
+			}
+			var schedule *api.CSIVolumeSnapshotSchedule
+			dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+				DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+				WeaklyTypedInput: true,
+				Result:           &schedule,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err := dec.Decode(m); err != nil {
+				return nil, fmt.Errorf("invalid snapshot_schedule: %v", err)
+			}
+			vol.SnapshotSchedule = schedule
+			break
+		}
+	}
+
 	return vol, nil
 }
 
@@ -5,6 +5,7 @@ package command
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/hcl"
 	"github.com/hashicorp/nomad/api"
@@ -186,6 +187,45 @@ topology_request {
 			Topologies: nil,
 		},
 		err: "",
+	}, {
+		name: "volume snapshot schedule",
+		hcl: `
+id              = "testvolume"
+name            = "test"
+type            = "csi"
+plugin_id       = "myplugin"
+
+capability {
+  access_mode     = "single-node-writer"
+  attachment_mode = "file-system"
+}
+
+snapshot_schedule {
+  interval = "24h"
+  retain   = 7
+
+  parameters {
+    type = "incremental"
+  }
+}
+`,
+		expected: &api.CSIVolume{
+			ID:       "testvolume",
+			Name:     "test",
+			PluginID: "myplugin",
+			RequestedCapabilities: []*api.CSIVolumeCapability{
+				{
+					AccessMode:     api.CSIVolumeAccessModeSingleNodeWriter,
+					AttachmentMode: api.CSIVolumeAttachmentModeFilesystem,
+				},
+			},
+			SnapshotSchedule: &api.CSIVolumeSnapshotSchedule{
+				Interval:   24 * time.Hour,
+				Retain:     7,
+				Parameters: map[string]string{"type": "incremental"},
+			},
+		},
+		err: "",
 	},
 	}
 
@@ -203,3 +243,42 @@ topology_request {
 		})
 	}
 }
+
+func TestHostVolumeDecode(t *testing.T) {
+	ci.Parallel(t)
+
+	ast, err := hcl.ParseString(`
+type         = "host"
+name         = "database"
+plugin_id    = "lvm"
+node_pool    = "prod"
+capacity_min = "10GiB"
+capacity_max = "20GiB"
+
+parameters {
+  volume_group = "nomad"
+}
+`)
+	must.NoError(t, err)
+
+	req, err := hostDecodeVolume(ast)
+	must.NoError(t, err)
+	must.Eq(t, &api.HostVolumeCreateRequest{
+		Name:                      "database",
+		PluginID:                  "lvm",
+		NodePool:                  "prod",
+		RequestedCapacityMinBytes: 10 * 1024 * 1024 * 1024,
+		RequestedCapacityMaxBytes: 20 * 1024 * 1024 * 1024,
+		Parameters:                map[string]string{"volume_group": "nomad"},
+	}, req)
+
+	ast, err = hcl.ParseString(`
+type      = "host"
+name      = "database"
+plugin_id = "lvm"
+capacity  = "10GiB"
+`)
+	must.NoError(t, err)
+	_, err = hostDecodeVolume(ast)
+	must.ErrorContains(t, err, "invalid key: capacity")
+}
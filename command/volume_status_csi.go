@@ -9,6 +9,7 @@ import (
 	"io"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/dustin/go-humanize"
 	"github.com/hashicorp/nomad/api"
@@ -246,6 +247,12 @@ func (c *VolumeStatusCommand) formatBasic(vol *api.CSIVolume) (string, error) {
 		full = append(full, topo)
 	}
 
+	if vol.SnapshotSchedule != nil {
+		schedBanner := c.Colorize().Color("\n[bold]Snapshot Schedule[reset]")
+		full = append(full, schedBanner)
+		full = append(full, c.formatSnapshotSchedule(vol))
+	}
+
 	// Format the allocs
 	banner := c.Colorize().Color("\n[bold]Allocations[reset]")
 	allocs := formatAllocListStubs(vol.Allocations, c.verbose, c.length)
@@ -255,6 +262,24 @@ func (c *VolumeStatusCommand) formatBasic(vol *api.CSIVolume) (string, error) {
 	return strings.Join(full, "\n"), nil
 }
 
+func (c *VolumeStatusCommand) formatSnapshotSchedule(vol *api.CSIVolume) string {
+	schedule := vol.SnapshotSchedule
+	out := []string{formatKV([]string{
+		fmt.Sprintf("Interval|%s", schedule.Interval),
+		fmt.Sprintf("Retain|%d", schedule.Retain),
+	})}
+
+	if len(vol.ScheduledSnapshots) > 0 {
+		rows := []string{"Snapshot ID|Created"}
+		for _, snap := range vol.ScheduledSnapshots {
+			rows = append(rows, fmt.Sprintf("%s|%s",
+				snap.ID, formatTime(time.Unix(snap.CreateTime, 0))))
+		}
+		out = append(out, "", formatList(rows))
+	}
+	return strings.Join(out, "\n")
+}
+
 func (c *VolumeStatusCommand) formatTopology(vol *api.CSIVolume) string {
 	rows := []string{"Topology|Segments"}
 	for i, t := range vol.Topologies {
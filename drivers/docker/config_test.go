@@ -214,6 +214,7 @@ config {
   auth_soft_fail = true
   cap_add = ["CAP_SYS_NICE"]
   cap_drop = ["CAP_SYS_ADMIN", "CAP_SYS_TIME"]
+  cdi_devices = ["nvidia.com/gpu=0"]
   command = "/bin/bash"
   cpu_hard_limit = true
   cpu_cfs_period = 20
@@ -364,6 +365,7 @@ config {
 		AuthSoftFail: true,
 		CapAdd:       []string{"CAP_SYS_NICE"},
 		CapDrop:      []string{"CAP_SYS_ADMIN", "CAP_SYS_TIME"},
+		CDIDevices:   []string{"nvidia.com/gpu=0"},
 		Command:      "/bin/bash",
 		CPUHardLimit: true,
 		CPUCFSPeriod: 20,
@@ -29,6 +29,7 @@ import (
 	"github.com/hashicorp/nomad/client/taskenv"
 	"github.com/hashicorp/nomad/drivers/docker/docklog"
 	"github.com/hashicorp/nomad/drivers/shared/capabilities"
+	"github.com/hashicorp/nomad/drivers/shared/cdi"
 	"github.com/hashicorp/nomad/drivers/shared/eventer"
 	"github.com/hashicorp/nomad/drivers/shared/hostnames"
 	"github.com/hashicorp/nomad/drivers/shared/resolvconf"
@@ -65,6 +66,9 @@ var (
 
 	// Nvidia-container-runtime environment variable names
 	nvidiaVisibleDevices = "NVIDIA_VISIBLE_DEVICES"
+
+	// cdiDeviceDriver is the docker device driver of CDI devices
+	cdiDeviceDriver = "cdi"
 )
 
 const (
@@ -254,6 +258,7 @@ func (d *Driver) RecoverTask(handle *drivers.TaskHandle) error {
 		waitCh:                make(chan struct{}),
 		removeContainerOnExit: d.config.GC.Container,
 		net:                   handleState.DriverNetwork,
+		emitEvent:             d.emitEventFunc(handle.Config),
 	}
 
 	if loggingIsEnabled(d.config, handle.Config) {
@@ -444,6 +449,7 @@ CREATE:
 		waitCh:                make(chan struct{}),
 		removeContainerOnExit: d.config.GC.Container,
 		net:                   net,
+		emitEvent:             d.emitEventFunc(cfg),
 	}
 
 	if err := handle.SetDriverState(h.buildState()); err != nil {
@@ -873,6 +879,21 @@ func parseSecurityOpts(securityOpts []string) ([]string, error) {
 	return securityOpts, nil
 }
 
+// cpuLimits computes the cpu_cfs_period and cpu_quota values passed along to
+// the docker host config when cpu_hard_limit is set. A cfs period of 0 uses the
+// period of the task resources.
+func cpuLimits(cfsPeriod int64, res *drivers.LinuxResources) (period, quota int64, err error) {
+	if cfsPeriod < 0 || cfsPeriod > 1000000 {
+		return 0, 0, fmt.Errorf("invalid value for cpu_cfs_period")
+	}
+	period = cfsPeriod
+	if period == 0 {
+		period = res.CPUPeriod
+	}
+	quota = int64(res.PercentTicks*float64(period)) * int64(runtime.NumCPU())
+	return period, quota, nil
+}
+
 // memoryLimits computes the memory and memory_reservation values passed along to
 // the docker host config. These fields represent hard and soft/reserved memory
 // limits from docker's perspective, respectively.
@@ -1015,15 +1036,12 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 	// multiply the time by the number of cores available
 	// See https://access.redhat.com/documentation/en-us/red_hat_enterprise_linux/6/html/resource_management_guide/sec-cpu
 	if driverConfig.CPUHardLimit {
-		numCores := runtime.NumCPU()
-		if driverConfig.CPUCFSPeriod < 0 || driverConfig.CPUCFSPeriod > 1000000 {
-			return c, fmt.Errorf("invalid value for cpu_cfs_period")
-		}
-		if driverConfig.CPUCFSPeriod == 0 {
-			driverConfig.CPUCFSPeriod = task.Resources.LinuxResources.CPUPeriod
+		period, quota, err := cpuLimits(driverConfig.CPUCFSPeriod, task.Resources.LinuxResources)
+		if err != nil {
+			return c, err
 		}
-		hostConfig.CPUPeriod = driverConfig.CPUCFSPeriod
-		hostConfig.CPUQuota = int64(task.Resources.LinuxResources.PercentTicks*float64(driverConfig.CPUCFSPeriod)) * int64(numCores)
+		hostConfig.CPUPeriod = period
+		hostConfig.CPUQuota = quota
 	}
 
 	// Windows does not support MemorySwap/MemorySwappiness #2193
@@ -1100,6 +1118,20 @@ func (d *Driver) createContainerConfig(task *drivers.TaskConfig, driverConfig *T
 		})
 	}
 
+	// Setup devices from CDI specifications, which the docker daemon resolves
+	// with its CDI device driver
+	if len(driverConfig.CDIDevices) > 0 {
+		for _, device := range driverConfig.CDIDevices {
+			if _, _, err := cdi.ParseDeviceName(device); err != nil {
+				return c, err
+			}
+		}
+		hostConfig.DeviceRequests = append(hostConfig.DeviceRequests, docker.DeviceRequest{
+			Driver:    cdiDeviceDriver,
+			DeviceIDs: driverConfig.CDIDevices,
+		})
+	}
+
 	// Setup mounts
 	for _, m := range driverConfig.Mounts {
 		hm, err := d.toDockerMount(&m, task)
@@ -1650,6 +1682,10 @@ func (d *Driver) InspectTask(taskID string) (*drivers.TaskStatus, error) {
 		NetworkOverride: h.net,
 		ExitResult:      h.ExitResult(),
 	}
+	if health, output := containerHealth(container); health != "" {
+		status.DriverAttributes[drivers.TaskStatusAttrHealth] = health
+		status.DriverAttributes[drivers.TaskStatusAttrHealthOutput] = output
+	}
 
 	status.State = drivers.TaskStateUnknown
 	if container.State.Running {
@@ -1692,6 +1728,51 @@ func (d *Driver) SignalTask(taskID string, signal string) error {
 	return h.Signal(context.Background(), sig)
 }
 
+var _ drivers.ResourceUpdaterDriver = (*Driver)(nil)
+
+// UpdateTaskResources updates the memory and cpu limits of the container of a
+// running task, so the task can be resized without being restarted.
+func (d *Driver) UpdateTaskResources(taskID string, resources *drivers.Resources) error {
+	h, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	if runtime.GOOS == "windows" {
+		return fmt.Errorf("updating the resources of running containers is not supported on windows")
+	}
+
+	var driverConfig TaskConfig
+	if err := h.task.DecodeDriverConfig(&driverConfig); err != nil {
+		return fmt.Errorf("failed to decode driver config: %v", err)
+	}
+
+	memory, memoryReservation := memoryLimits(driverConfig.MemoryHardLimit, resources.NomadResources.Memory)
+	opts := docker.UpdateContainerOptions{
+		Memory:            int(memory),
+		MemorySwap:        int(memory),
+		MemoryReservation: int(memoryReservation),
+		CPUShares:         int(resources.LinuxResources.CPUShares),
+	}
+	if driverConfig.CPUHardLimit {
+		period, quota, err := cpuLimits(driverConfig.CPUCFSPeriod, resources.LinuxResources)
+		if err != nil {
+			return err
+		}
+		opts.CPUPeriod = int(period)
+		opts.CPUQuota = int(quota)
+	}
+
+	if err := h.dockerClient.UpdateContainer(h.containerID, opts); err != nil {
+		return fmt.Errorf("failed to update container %q: %v", h.containerID, err)
+	}
+
+	h.logger.Debug("updated container resources", "memory", opts.Memory,
+		"memory_reservation", opts.MemoryReservation, "cpu_shares", opts.CPUShares,
+		"cpu_quota", opts.CPUQuota, "cpu_period", opts.CPUPeriod)
+	return nil
+}
+
 func (d *Driver) ExecTask(taskID string, cmd []string, timeout time.Duration) (*drivers.ExecTaskResult, error) {
 	h, ok := d.tasks.Get(taskID)
 	if !ok {
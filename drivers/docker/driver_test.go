@@ -1125,6 +1125,31 @@ func TestDockerDriver_CreateContainerConfig_User(t *testing.T) {
 	require.Equal(t, task.User, c.Config.User)
 }
 
+func TestDockerDriver_CreateContainerConfig_CDIDevices(t *testing.T) {
+	ci.Parallel(t)
+
+	task, cfg, _ := dockerTask(t)
+
+	cfg.CDIDevices = []string{"nvidia.com/gpu=0", "nvidia.com/gpu=1"}
+	must.NoError(t, task.EncodeConcreteDriverConfig(cfg))
+
+	dh := dockerDriverHarness(t, nil)
+	driver := dh.Impl().(*Driver)
+
+	c, err := driver.createContainerConfig(task, cfg, "org/repo:0.1")
+	must.NoError(t, err)
+	must.Eq(t, []docker.DeviceRequest{{
+		Driver:    "cdi",
+		DeviceIDs: []string{"nvidia.com/gpu=0", "nvidia.com/gpu=1"},
+	}}, c.HostConfig.DeviceRequests)
+
+	cfg.CDIDevices = []string{"/dev/nvidia0"}
+	must.NoError(t, task.EncodeConcreteDriverConfig(cfg))
+
+	_, err = driver.createContainerConfig(task, cfg, "org/repo:0.1")
+	must.ErrorContains(t, err, "must be of the form vendor.com/class=name")
+}
+
 func TestDockerDriver_CreateContainerConfig_Labels(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2912,6 +2937,32 @@ func TestDockerDriver_memoryLimits(t *testing.T) {
 	}
 }
 
+func TestDockerDriver_cpuLimits(t *testing.T) {
+	ci.Parallel(t)
+
+	res := &drivers.LinuxResources{
+		CPUPeriod:    100000,
+		PercentTicks: 0.5,
+	}
+	numCores := int64(runtime.NumCPU())
+
+	period, quota, err := cpuLimits(0, res)
+	must.NoError(t, err)
+	must.Eq(t, 100000, period)
+	must.Eq(t, 50000*numCores, quota)
+
+	period, quota, err = cpuLimits(20000, res)
+	must.NoError(t, err)
+	must.Eq(t, 20000, period)
+	must.Eq(t, 10000*numCores, quota)
+
+	_, _, err = cpuLimits(-1, res)
+	must.Error(t, err)
+
+	_, _, err = cpuLimits(1000001, res)
+	must.Error(t, err)
+}
+
 func TestDockerDriver_parseSignal(t *testing.T) {
 	ci.Parallel(t)
 
@@ -129,6 +129,9 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 	d.setDetected(true)
 	fp.Attributes["driver.docker"] = pstructs.NewBoolAttribute(true)
 	fp.Attributes["driver.docker.version"] = pstructs.NewStringAttribute(env.Get("Version"))
+	if runtime.GOOS != "windows" {
+		fp.Attributes["driver.docker.resize"] = pstructs.NewBoolAttribute(true)
+	}
 	if d.config.AllowPrivileged {
 		fp.Attributes["driver.docker.privileged.enabled"] = pstructs.NewBoolAttribute(true)
 	}
@@ -48,6 +48,9 @@ type taskHandle struct {
 	removeContainerOnExit bool
 	net                   *drivers.DriverNetwork
 
+	// emitEvent emits task events, such as changes of the container health
+	emitEvent LogEventFn
+
 	exitResult     *drivers.ExitResult
 	exitResultLock sync.Mutex
 }
@@ -275,10 +278,35 @@ func (h *taskHandle) startCpusetFixer() {
 	}).watch()
 }
 
+// startHealthWatcher starts emitting task events for the changes of the health
+// status of the container, if it has a healthcheck.
+func (h *taskHandle) startHealthWatcher() {
+	if h.emitEvent == nil {
+		return
+	}
+
+	go (&healthWatcher{
+		doneCh: h.doneCh,
+		logger: h.logger,
+		emit:   h.emitEvent,
+		inspect: func() (string, string, error) {
+			container, err := h.dockerClient.InspectContainerWithOptions(docker.InspectContainerOptions{
+				ID: h.containerID,
+			})
+			if err != nil {
+				return "", "", err
+			}
+			status, output := containerHealth(container)
+			return status, output, nil
+		},
+	}).watch()
+}
+
 func (h *taskHandle) run() {
 	defer h.shutdownLogger()
 
 	h.startCpusetFixer()
+	h.startHealthWatcher()
 
 	exitCode, werr := h.infinityClient.WaitContainer(h.containerID)
 	if werr != nil {
@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package docker
+
+import (
+	"strings"
+	"time"
+
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/plugins/drivers"
+)
+
+const (
+	// healthPollPeriod is how often we inspect a container to see if the
+	// health status reported by its healthcheck has changed
+	healthPollPeriod = 5 * time.Second
+)
+
+// containerHealth returns the health status of the container and the output
+// of its last healthcheck, or empty strings if the container has no
+// healthcheck.
+func containerHealth(container *docker.Container) (string, string) {
+	health := container.State.Health
+	if health.Status == "" {
+		return "", ""
+	}
+
+	var output string
+	if n := len(health.Log); n > 0 {
+		output = strings.TrimSpace(health.Log[n-1].Output)
+	}
+	return health.Status, output
+}
+
+// healthWatcher emits a task event each time the health status of a container
+// changes, as determined by the healthcheck of the container's image (or the
+// healthcheck set when creating the container).
+type healthWatcher struct {
+	doneCh  <-chan bool
+	logger  hclog.Logger
+	emit    LogEventFn
+	inspect func() (string, string, error)
+
+	previous string
+}
+
+func (w *healthWatcher) watch() {
+	ticks, cancel := helper.NewSafeTimer(healthPollPeriod)
+	defer cancel()
+
+	for {
+		select {
+		case <-w.doneCh:
+			return
+		case <-ticks.C:
+			if !w.poll() {
+				return
+			}
+			ticks.Reset(healthPollPeriod)
+		}
+	}
+}
+
+// poll inspects the health of the container and emits an event if its status
+// changed. It returns false once it's known the container has no healthcheck,
+// in which case there is nothing left to watch.
+func (w *healthWatcher) poll() bool {
+	status, output, err := w.inspect()
+	if err != nil {
+		w.logger.Debug("failed to inspect container health", "error", err)
+		return true
+	}
+	if status == "" {
+		return false
+	}
+	if status == w.previous {
+		return true
+	}
+	w.previous = status
+
+	annotations := map[string]string{
+		drivers.TaskStatusAttrHealth: status,
+	}
+	if output != "" {
+		annotations[drivers.TaskStatusAttrHealthOutput] = output
+	}
+	w.emit("Container health is "+status, annotations)
+	return true
+}
@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package docker
+
+import (
+	"testing"
+
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/shoenig/test/must"
+)
+
+func Test_containerHealth(t *testing.T) {
+	ci.Parallel(t)
+
+	status, output := containerHealth(&docker.Container{})
+	must.Eq(t, "", status)
+	must.Eq(t, "", output)
+
+	status, output = containerHealth(&docker.Container{
+		State: docker.State{
+			Health: docker.Health{
+				Status: "unhealthy",
+				Log: []docker.HealthCheck{
+					{ExitCode: 0, Output: "ok\n"},
+					{ExitCode: 1, Output: "connection refused\n"},
+				},
+			},
+		},
+	})
+	must.Eq(t, "unhealthy", status)
+	must.Eq(t, "connection refused", output)
+}
+
+func Test_healthWatcher_poll(t *testing.T) {
+	ci.Parallel(t)
+
+	var status string
+	var events []string
+	w := &healthWatcher{
+		logger: testlog.HCLogger(t),
+		emit: func(msg string, annotations map[string]string) {
+			events = append(events, msg)
+		},
+		inspect: func() (string, string, error) {
+			return status, "", nil
+		},
+	}
+
+	// only changes of the health status are emitted
+	for _, status = range []string{"starting", "starting", "healthy", "healthy", "unhealthy"} {
+		must.True(t, w.poll())
+	}
+	must.Eq(t, []string{
+		"Container health is starting",
+		"Container health is healthy",
+		"Container health is unhealthy",
+	}, events)
+
+	// containers without healthcheck aren't watched
+	status = ""
+	must.False(t, w.poll())
+}
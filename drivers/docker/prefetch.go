@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package docker
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// prefetchCallerID is the caller holding the reference of a prefetched
+	// image while it is pulled.
+	prefetchCallerID = "prefetch"
+
+	// prefetchPullTimeout is the timeout of the pull of a prefetched image,
+	// which is the default image_pull_timeout of tasks.
+	prefetchPullTimeout = 5 * time.Minute
+)
+
+// PrefetchImage pulls an image ahead of the placement of the tasks using it.
+// Only the registry credentials of the plugin configuration are used. The
+// prefetched image isn't referenced by any task, so it is removed after the
+// configured image_delay if image garbage collection is enabled and no task
+// uses it by then.
+func (d *Driver) PrefetchImage(image string) error {
+	client, err := d.getDockerClient()
+	if err != nil {
+		return err
+	}
+
+	repo, tag := parseDockerImage(image)
+	if tag != "latest" {
+		if dockerImage, _ := client.InspectImage(image); dockerImage != nil {
+			return nil
+		}
+	}
+
+	authOptions, err := d.resolveRegistryAuthentication(&TaskConfig{Image: image}, repo)
+	if err != nil {
+		return fmt.Errorf("Failed to find docker auth for repo %q: %v", repo, err)
+	}
+
+	d.logger.Debug("prefetching image", "image_ref", dockerImageRef(repo, tag))
+	id, err := d.coordinator.PullImage(image, authOptions, prefetchCallerID,
+		func(string, map[string]string) {}, prefetchPullTimeout, d.config.pullActivityTimeoutDuration)
+	if err != nil {
+		return err
+	}
+
+	d.coordinator.RemoveImage(id, prefetchCallerID)
+	return nil
+}
@@ -83,6 +83,7 @@ var (
 			hclspec.NewAttr("allow_caps", "list(string)", false),
 			hclspec.NewLiteral(capabilities.HCLSpecLiteral),
 		),
+		"allow_mount_sources": hclspec.NewAttr("allow_mount_sources", "list(string)", false),
 	})
 
 	// taskConfigSpec is the hcl specification for the driver config section of
@@ -94,6 +95,7 @@ var (
 		"ipc_mode": hclspec.NewAttr("ipc_mode", "string", false),
 		"cap_add":  hclspec.NewAttr("cap_add", "list(string)", false),
 		"cap_drop": hclspec.NewAttr("cap_drop", "list(string)", false),
+		"mount":    hclspec.NewBlockList("mount", mountBodySpec),
 	})
 
 	// driverCapabilities represents the RPC response for what features are
@@ -159,6 +161,10 @@ type Config struct {
 	// AllowCaps configures which Linux Capabilities are enabled for tasks
 	// running on this node.
 	AllowCaps []string `codec:"allow_caps"`
+
+	// AllowMountSources are the host paths tasks may bind mount, along with
+	// the paths within them. Tasks can't bind mount host paths if empty.
+	AllowMountSources []string `codec:"allow_mount_sources"`
 }
 
 func (c *Config) validate() error {
@@ -179,6 +185,12 @@ func (c *Config) validate() error {
 		return fmt.Errorf("allow_caps configured with capabilities not supported by system: %s", badCaps)
 	}
 
+	for _, source := range c.AllowMountSources {
+		if !filepath.IsAbs(source) {
+			return fmt.Errorf("allow_mount_sources must be absolute paths, got %q", source)
+		}
+	}
+
 	return nil
 }
 
@@ -203,6 +215,9 @@ type TaskConfig struct {
 
 	// CapDrop is a set of linux capabilities to disable.
 	CapDrop []string `codec:"cap_drop"`
+
+	// Mounts are the bind and tmpfs mounts of the task.
+	Mounts []Mount `codec:"mount"`
 }
 
 func (tc *TaskConfig) validate() error {
@@ -364,6 +379,7 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 	}
 
 	fp.Attributes["driver.exec"] = pstructs.NewBoolAttribute(true)
+	fp.Attributes["driver.exec.resize"] = pstructs.NewBoolAttribute(true)
 	d.setFingerprintSuccess()
 	return fp
 }
@@ -437,6 +453,15 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		return nil, nil, fmt.Errorf("failed driver config validation: %v", err)
 	}
 
+	mounts := make([]*drivers.MountConfig, 0, len(driverConfig.Mounts))
+	for _, mount := range driverConfig.Mounts {
+		mountConfig, err := mount.mountConfig(d.config.AllowMountSources)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed driver config validation: %v", err)
+		}
+		mounts = append(mounts, mountConfig)
+	}
+
 	d.logger.Info("starting task", "driver_cfg", hclog.Fmt("%+v", driverConfig))
 	handle := drivers.NewTaskHandle(taskHandleVersion)
 	handle.Config = cfg
@@ -461,6 +486,8 @@ func (d *Driver) StartTask(cfg *drivers.TaskConfig) (*drivers.TaskHandle, *drive
 		user = "nobody"
 	}
 
+	cfg.Mounts = append(cfg.Mounts, mounts...)
+
 	if cfg.DNS != nil {
 		dnsMount, err := resolvconf.GenerateDNSMount(cfg.TaskDir().Dir, cfg.DNS)
 		if err != nil {
@@ -644,6 +671,41 @@ func (d *Driver) SignalTask(taskID string, signal string) error {
 	return handle.exec.Signal(sig)
 }
 
+var _ drivers.ResourceUpdaterDriver = (*Driver)(nil)
+
+// UpdateTaskResources updates the memory and cpu limits of the cgroup of a
+// running task, so the task can be resized without being restarted.
+func (d *Driver) UpdateTaskResources(taskID string, resources *drivers.Resources) error {
+	handle, ok := d.tasks.Get(taskID)
+	if !ok {
+		return drivers.ErrTaskNotFound
+	}
+
+	// Memory limits are set the same way as by the executor when the task
+	// is started
+	mem := resources.NomadResources.Memory
+	memHard, memSoft := mem.MemoryMaxMB, mem.MemoryMB
+	if memHard <= 0 {
+		memHard = mem.MemoryMB
+		memSoft = 0
+	}
+
+	// Clamp the cpu shares to the range accepted by the kernel, as done by
+	// the executor
+	cpuShares := min(max(resources.LinuxResources.CPUShares, 2), 262_144)
+
+	cores := len(resources.NomadResources.Cpu.ReservedCores) > 0
+	err := cgroupslib.UpdateLimits(handle.taskConfig.AllocID, handle.taskConfig.Name, cores,
+		memHard*1024*1024, memSoft*1024*1024, uint64(cpuShares))
+	if err != nil {
+		return fmt.Errorf("failed to update cgroup limits: %v", err)
+	}
+
+	handle.logger.Debug("updated task resources", "memory", memHard,
+		"memory_reservation", memSoft, "cpu_shares", cpuShares)
+	return nil
+}
+
 func (d *Driver) ExecTask(taskID string, cmd []string, timeout time.Duration) (*drivers.ExecTaskResult, error) {
 	if len(cmd) == 0 {
 		return nil, fmt.Errorf("error cmd must have at least one value")
@@ -727,11 +727,36 @@ func TestConfig_ParseAllHCL(t *testing.T) {
 config {
   command = "/bin/bash"
   args = ["-c", "echo hello"]
+
+  mount {
+    source   = "/srv/data"
+    target   = "/data"
+    readonly = true
+  }
+
+  mount {
+    type   = "tmpfs"
+    target = "/scratch"
+  }
 }`
 
 	expected := &TaskConfig{
 		Command: "/bin/bash",
 		Args:    []string{"-c", "echo hello"},
+		Mounts: []Mount{
+			{
+				Type:            "bind",
+				Source:          "/srv/data",
+				Target:          "/data",
+				ReadOnly:        true,
+				PropagationMode: "private",
+			},
+			{
+				Type:            "tmpfs",
+				Target:          "/scratch",
+				PropagationMode: "private",
+			},
+		},
 	}
 
 	var tc *TaskConfig
@@ -870,6 +895,23 @@ func TestDriver_Config_validate(t *testing.T) {
 			}).validate())
 		}
 	})
+
+	t.Run("allow_mount_sources", func(t *testing.T) {
+		for _, tc := range []struct {
+			ams []string
+			exp error
+		}{
+			{ams: nil, exp: nil},
+			{ams: []string{"/srv", "/opt/data"}, exp: nil},
+			{ams: []string{"/srv", "data"}, exp: errors.New(`allow_mount_sources must be absolute paths, got "data"`)},
+		} {
+			require.Equal(t, tc.exp, (&Config{
+				DefaultModePID:    "private",
+				DefaultModeIPC:    "private",
+				AllowMountSources: tc.ams,
+			}).validate())
+		}
+	})
 }
 
 func TestDriver_TaskConfig_validate(t *testing.T) {
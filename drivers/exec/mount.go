@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package exec
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/nomad/helper/escapingfs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+)
+
+const (
+	// mountTypeBind is the type of a mount of a host path into the task.
+	mountTypeBind = drivers.MountTypeBind
+
+	// mountTypeTmpfs is the type of a tmpfs mount, which is charged to the
+	// memory of the task.
+	mountTypeTmpfs = drivers.MountTypeTmpfs
+)
+
+// mountBodySpec is the hcl specification of a mount block of a task.
+var mountBodySpec = hclspec.NewObject(map[string]*hclspec.Spec{
+	"type": hclspec.NewDefault(
+		hclspec.NewAttr("type", "string", false),
+		hclspec.NewLiteral(`"bind"`),
+	),
+	"source":   hclspec.NewAttr("source", "string", false),
+	"target":   hclspec.NewAttr("target", "string", true),
+	"readonly": hclspec.NewAttr("readonly", "bool", false),
+	"propagation_mode": hclspec.NewDefault(
+		hclspec.NewAttr("propagation_mode", "string", false),
+		hclspec.NewLiteral(`"private"`),
+	),
+})
+
+// Mount is a bind or tmpfs mount of a task.
+type Mount struct {
+	// Type is either "bind" or "tmpfs".
+	Type string `codec:"type"`
+
+	// Source is the host path of a bind mount, which must be within one of
+	// the mount sources allowed by the plugin configuration.
+	Source string `codec:"source"`
+
+	// Target is the absolute path of the mount within the task's chroot.
+	Target string `codec:"target"`
+
+	// ReadOnly mounts the source in read-only mode.
+	ReadOnly bool `codec:"readonly"`
+
+	// PropagationMode is the propagation mode of a bind mount, which takes
+	// the same values as the propagation_mode of a volume_mount.
+	PropagationMode string `codec:"propagation_mode"`
+}
+
+// mountConfig validates the mount against the sources allowed by the plugin
+// configuration and returns the mount of the executor.
+func (m *Mount) mountConfig(allowed []string) (*drivers.MountConfig, error) {
+	if !filepath.IsAbs(m.Target) {
+		return nil, fmt.Errorf("mount target %q must be an absolute path", m.Target)
+	}
+
+	switch m.Type {
+	case mountTypeTmpfs:
+		if m.Source != "" {
+			return nil, fmt.Errorf("tmpfs mount %q must not set a source", m.Target)
+		}
+		return &drivers.MountConfig{
+			TaskPath: m.Target,
+			Readonly: m.ReadOnly,
+			Type:     drivers.MountTypeTmpfs,
+		}, nil
+	case mountTypeBind:
+	default:
+		return nil, fmt.Errorf("mount type must be %q or %q, got %q", mountTypeBind, mountTypeTmpfs, m.Type)
+	}
+
+	switch m.PropagationMode {
+	case structs.VolumeMountPropagationPrivate,
+		structs.VolumeMountPropagationHostToTask,
+		structs.VolumeMountPropagationBidirectional:
+	default:
+		return nil, fmt.Errorf("mount propagation_mode must be %q, %q or %q, got %q",
+			structs.VolumeMountPropagationPrivate, structs.VolumeMountPropagationHostToTask,
+			structs.VolumeMountPropagationBidirectional, m.PropagationMode)
+	}
+
+	if !filepath.IsAbs(m.Source) {
+		return nil, fmt.Errorf("mount source %q must be an absolute path", m.Source)
+	}
+
+	// resolve symlinks so a link can't point out of the allowed sources
+	source, err := filepath.EvalSymlinks(m.Source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve mount source %q: %v", m.Source, err)
+	}
+	if !mountSourceAllowed(source, allowed) {
+		return nil, fmt.Errorf("mount source %q is not within the allow_mount_sources of the exec plugin", m.Source)
+	}
+
+	return &drivers.MountConfig{
+		TaskPath:        m.Target,
+		HostPath:        source,
+		Readonly:        m.ReadOnly,
+		PropagationMode: m.PropagationMode,
+		Type:            drivers.MountTypeBind,
+	}, nil
+}
+
+// mountSourceAllowed returns true if the host path is one of the allowed mount
+// sources or is within one of them.
+func mountSourceAllowed(source string, allowed []string) bool {
+	for _, dir := range allowed {
+		if !escapingfs.PathEscapesSandbox(filepath.Clean(dir), source) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package exec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/shoenig/test/must"
+)
+
+func TestMount_mountConfig(t *testing.T) {
+	ci.Parallel(t)
+
+	allowed, err := filepath.EvalSymlinks(t.TempDir())
+	must.NoError(t, err)
+	data := filepath.Join(allowed, "data")
+	must.NoError(t, os.Mkdir(data, 0o755))
+
+	other, err := filepath.EvalSymlinks(t.TempDir())
+	must.NoError(t, err)
+	link := filepath.Join(allowed, "link")
+	must.NoError(t, os.Symlink(other, link))
+
+	for _, tc := range []struct {
+		name   string
+		mount  Mount
+		exp    *drivers.MountConfig
+		expErr string
+	}{
+		{
+			name:  "bind",
+			mount: Mount{Type: "bind", Source: data, Target: "/data", ReadOnly: true, PropagationMode: "host-to-task"},
+			exp:   &drivers.MountConfig{HostPath: data, TaskPath: "/data", Readonly: true, PropagationMode: "host-to-task", Type: "bind"},
+		},
+		{
+			name:  "tmpfs",
+			mount: Mount{Type: "tmpfs", Target: "/scratch", PropagationMode: "private"},
+			exp:   &drivers.MountConfig{TaskPath: "/scratch", Type: "tmpfs"},
+		},
+		{
+			name:   "source not allowed",
+			mount:  Mount{Type: "bind", Source: other, Target: "/data", PropagationMode: "private"},
+			expErr: "is not within the allow_mount_sources",
+		},
+		{
+			name:   "symlink out of allowed source",
+			mount:  Mount{Type: "bind", Source: link, Target: "/data", PropagationMode: "private"},
+			expErr: "is not within the allow_mount_sources",
+		},
+		{
+			name:   "relative target",
+			mount:  Mount{Type: "bind", Source: data, Target: "data", PropagationMode: "private"},
+			expErr: "must be an absolute path",
+		},
+		{
+			name:   "tmpfs with source",
+			mount:  Mount{Type: "tmpfs", Source: data, Target: "/scratch"},
+			expErr: "must not set a source",
+		},
+		{
+			name:   "bad propagation mode",
+			mount:  Mount{Type: "bind", Source: data, Target: "/data", PropagationMode: "rshared"},
+			expErr: "propagation_mode must be",
+		},
+		{
+			name:   "bad type",
+			mount:  Mount{Type: "volume", Target: "/data"},
+			expErr: "mount type must be",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mc, err := tc.mount.mountConfig([]string{allowed})
+			if tc.expErr != "" {
+				must.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.exp, mc)
+		})
+	}
+}
@@ -346,6 +346,7 @@ func (d *Driver) buildFingerprint() *drivers.Fingerprint {
 	} else {
 		health = drivers.HealthStateHealthy
 		attrs["driver.mock"] = pstructs.NewBoolAttribute(true)
+		attrs["driver.mock.resize"] = pstructs.NewBoolAttribute(true)
 		desc = drivers.DriverHealthy
 	}
 
@@ -640,6 +641,15 @@ func (d *Driver) SignalTask(taskID string, signal string) error {
 	return errors.New(h.command.SignalErr)
 }
 
+var _ drivers.ResourceUpdaterDriver = (*Driver)(nil)
+
+func (d *Driver) UpdateTaskResources(taskID string, resources *drivers.Resources) error {
+	if _, ok := d.tasks.Get(taskID); !ok {
+		return drivers.ErrTaskNotFound
+	}
+	return nil
+}
+
 func (d *Driver) ExecTask(taskID string, cmd []string, timeout time.Duration) (*drivers.ExecTaskResult, error) {
 	h, ok := d.tasks.Get(taskID)
 	if !ok {
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package cdi validates the names of devices described by Container Device
+// Interface (CDI) specifications. Container runtimes supporting CDI inject the
+// devices, mounts and environment of a device from the specifications vendors
+// install on the host, so task drivers only need to pass the device names.
+package cdi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseDeviceName splits a fully-qualified CDI device name of the form
+// "vendor.com/class=name" into its kind ("vendor.com/class") and name, and
+// validates each part as defined by the CDI specification.
+func ParseDeviceName(device string) (string, string, error) {
+	kind, name, ok := strings.Cut(device, "=")
+	if !ok || kind == "" || name == "" {
+		return "", "", fmt.Errorf("CDI device %q must be of the form vendor.com/class=name", device)
+	}
+
+	vendor, class, ok := strings.Cut(kind, "/")
+	if !ok {
+		return "", "", fmt.Errorf("CDI device %q must be of the form vendor.com/class=name", device)
+	}
+	if err := validateName(vendor, "._-"); err != nil {
+		return "", "", fmt.Errorf("invalid vendor of CDI device %q: %v", device, err)
+	}
+	if err := validateName(class, "_-"); err != nil {
+		return "", "", fmt.Errorf("invalid class of CDI device %q: %v", device, err)
+	}
+	if err := validateDevice(name); err != nil {
+		return "", "", fmt.Errorf("invalid name of CDI device %q: %v", device, err)
+	}
+	return kind, name, nil
+}
+
+// validateName validates a vendor or class name, which must start with a
+// letter, end with a letter or digit, and otherwise only contain letters,
+// digits and the given punctuation.
+func validateName(s, punct string) error {
+	if s == "" {
+		return fmt.Errorf("empty name")
+	}
+	if !isLetter(rune(s[0])) {
+		return fmt.Errorf("%q must start with a letter", s)
+	}
+	if !isAlphaNumeric(rune(s[len(s)-1])) {
+		return fmt.Errorf("%q must end with a letter or digit", s)
+	}
+	for _, c := range s {
+		if !isAlphaNumeric(c) && !strings.ContainsRune(punct, c) {
+			return fmt.Errorf("%q contains invalid character %q", s, c)
+		}
+	}
+	return nil
+}
+
+// validateDevice validates a device name, which must start and end with a
+// letter or digit, and otherwise only contain letters, digits, '_', '-', '.'
+// and ':'.
+func validateDevice(s string) error {
+	if !isAlphaNumeric(rune(s[0])) {
+		return fmt.Errorf("%q must start with a letter or digit", s)
+	}
+	if !isAlphaNumeric(rune(s[len(s)-1])) {
+		return fmt.Errorf("%q must end with a letter or digit", s)
+	}
+	for _, c := range s {
+		if !isAlphaNumeric(c) && !strings.ContainsRune("_-.:", c) {
+			return fmt.Errorf("%q contains invalid character %q", s, c)
+		}
+	}
+	return nil
+}
+
+func isLetter(c rune) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isAlphaNumeric(c rune) bool {
+	return isLetter(c) || ('0' <= c && c <= '9')
+}
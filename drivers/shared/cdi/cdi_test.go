@@ -0,0 +1,47 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cdi
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestParseDeviceName(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		device string
+		kind   string
+		name   string
+		err    string
+	}{
+		{device: "nvidia.com/gpu=0", kind: "nvidia.com/gpu", name: "0"},
+		{device: "nvidia.com/gpu=all", kind: "nvidia.com/gpu", name: "all"},
+		{device: "vendor.example.com/net-card=eth_1:0", kind: "vendor.example.com/net-card", name: "eth_1:0"},
+		{device: "/dev/nvidia0", err: "must be of the form"},
+		{device: "nvidia.com/gpu", err: "must be of the form"},
+		{device: "nvidia.com=gpu0", err: "must be of the form"},
+		{device: "nvidia.com/gpu=", err: "must be of the form"},
+		{device: "1vendor.com/gpu=0", err: "must start with a letter"},
+		{device: "vendor.com/gpu.x=0", err: "invalid character"},
+		{device: "vendor.com/gpu=0/1", err: "invalid character"},
+		{device: "vendor.com/gpu=-0", err: "must start with a letter or digit"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.device, func(t *testing.T) {
+			kind, name, err := ParseDeviceName(tc.device)
+			if tc.err != "" {
+				must.ErrorContains(t, err, tc.err)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.kind, kind)
+			must.Eq(t, tc.name, name)
+		})
+	}
+}
@@ -664,7 +664,11 @@ func configureIsolation(cfg *runc.Config, command *ExecCommand) error {
 	}
 
 	if len(command.Mounts) > 0 {
-		cfg.Mounts = append(cfg.Mounts, cmdMounts(command.Mounts)...)
+		mounts, err := cmdMounts(command.Mounts)
+		if err != nil {
+			return err
+		}
+		cfg.Mounts = append(cfg.Mounts, mounts...)
 	}
 
 	return nil
@@ -856,14 +860,37 @@ var userMountToUnixMount = map[string]int{
 }
 
 // cmdMounts converts a list of driver.MountConfigs into excutor.Mounts.
-func cmdMounts(mounts []*drivers.MountConfig) []*runc.Mount {
+func cmdMounts(mounts []*drivers.MountConfig) ([]*runc.Mount, error) {
 	if len(mounts) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	r := make([]*runc.Mount, len(mounts))
 
 	for i, m := range mounts {
+		if m.IsTmpfs() {
+			flags := unix.MS_NOSUID | unix.MS_NODEV
+			if m.Readonly {
+				flags |= unix.MS_RDONLY
+			}
+
+			r[i] = &runc.Mount{
+				Source:      "tmpfs",
+				Destination: m.TaskPath,
+				Device:      "tmpfs",
+				Flags:       flags,
+				Data:        "mode=1777",
+			}
+			continue
+		}
+
+		if m.Type != "" && m.Type != drivers.MountTypeBind {
+			return nil, fmt.Errorf("mount %q has unknown type %q", m.TaskPath, m.Type)
+		}
+		if m.HostPath == "" {
+			return nil, fmt.Errorf("bind mount %q must have a host path", m.TaskPath)
+		}
+
 		flags := unix.MS_BIND
 		if m.Readonly {
 			flags |= unix.MS_RDONLY
@@ -878,7 +905,7 @@ func cmdMounts(mounts []*drivers.MountConfig) []*runc.Mount {
 		}
 	}
 
-	return r
+	return r, nil
 }
 
 // lookupTaskBin finds the file `bin`, searching in order:
@@ -911,6 +938,9 @@ func lookupTaskBin(command *ExecCommand) (string, string, error) {
 
 	// Check in our mounts
 	for _, mount := range command.Mounts {
+		if mount.IsTmpfs() {
+			continue
+		}
 		taskPath, hostPath, err = getPathInMount(mount.HostPath, mount.TaskPath, bin)
 		if err == nil {
 			return taskPath, hostPath, nil
@@ -772,6 +772,10 @@ func TestExecutor_cmdMounts(t *testing.T) {
 			TaskPath: "/task/path-rw",
 			Readonly: false,
 		},
+		{
+			TaskPath: "/task/tmpfs",
+			Type:     drivers.MountTypeTmpfs,
+		},
 	}
 
 	expected := []*lconfigs.Mount{
@@ -789,7 +793,21 @@ func TestExecutor_cmdMounts(t *testing.T) {
 			Device:           "bind",
 			PropagationFlags: []int{unix.MS_PRIVATE | unix.MS_REC},
 		},
+		{
+			Source:      "tmpfs",
+			Destination: "/task/tmpfs",
+			Flags:       unix.MS_NOSUID | unix.MS_NODEV,
+			Device:      "tmpfs",
+			Data:        "mode=1777",
+		},
 	}
 
-	require.EqualValues(t, expected, cmdMounts(input))
+	mounts, err := cmdMounts(input)
+	require.NoError(t, err)
+	require.EqualValues(t, expected, mounts)
+
+	// A bind mount without a host path is rejected rather than becoming a
+	// tmpfs mount
+	_, err = cmdMounts([]*drivers.MountConfig{{TaskPath: "/task/path"}})
+	require.ErrorContains(t, err, "must have a host path")
 }
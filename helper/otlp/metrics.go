@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package otlp
+
+// AggregationTemporalityCumulative is the OTLP value for cumulative sums and
+// histograms.
+const AggregationTemporalityCumulative = 2
+
+// ExportMetricsServiceRequest is the body of an OTLP/HTTP metrics export.
+type ExportMetricsServiceRequest struct {
+	ResourceMetrics []*ResourceMetrics `json:"resourceMetrics"`
+}
+
+type ResourceMetrics struct {
+	Resource     Resource        `json:"resource"`
+	ScopeMetrics []*ScopeMetrics `json:"scopeMetrics"`
+}
+
+type ScopeMetrics struct {
+	Scope   Scope     `json:"scope"`
+	Metrics []*Metric `json:"metrics"`
+}
+
+type Metric struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Gauge       *Gauge     `json:"gauge,omitempty"`
+	Sum         *Sum       `json:"sum,omitempty"`
+	Summary     *Summary   `json:"summary,omitempty"`
+	Histogram   *Histogram `json:"histogram,omitempty"`
+}
+
+type Gauge struct {
+	DataPoints []*NumberDataPoint `json:"dataPoints"`
+}
+
+type Sum struct {
+	DataPoints             []*NumberDataPoint `json:"dataPoints"`
+	AggregationTemporality int                `json:"aggregationTemporality"`
+	IsMonotonic            bool               `json:"isMonotonic"`
+}
+
+type NumberDataPoint struct {
+	Attributes   []*KeyValue `json:"attributes"`
+	TimeUnixNano string      `json:"timeUnixNano"`
+	AsDouble     float64     `json:"asDouble"`
+}
+
+type Summary struct {
+	DataPoints []*SummaryDataPoint `json:"dataPoints"`
+}
+
+type SummaryDataPoint struct {
+	Attributes     []*KeyValue      `json:"attributes"`
+	TimeUnixNano   string           `json:"timeUnixNano"`
+	Count          string           `json:"count"`
+	Sum            float64          `json:"sum"`
+	QuantileValues []*QuantileValue `json:"quantileValues"`
+}
+
+type QuantileValue struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
+}
+
+type Histogram struct {
+	DataPoints             []*HistogramDataPoint `json:"dataPoints"`
+	AggregationTemporality int                   `json:"aggregationTemporality"`
+}
+
+type HistogramDataPoint struct {
+	Attributes     []*KeyValue `json:"attributes"`
+	TimeUnixNano   string      `json:"timeUnixNano"`
+	Count          string      `json:"count"`
+	Sum            float64     `json:"sum"`
+	BucketCounts   []string    `json:"bucketCounts"`
+	ExplicitBounds []float64   `json:"explicitBounds"`
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package otlp contains the subset of the OTLP/HTTP JSON encoding used by
+// the Nomad exporters of metrics and traces. Trace and span IDs are hex
+// encoded and 64-bit integers are encoded as strings, as required by the
+// OTLP JSON mapping.
+package otlp
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Resource describes the agent that produced the telemetry.
+type Resource struct {
+	Attributes []*KeyValue `json:"attributes"`
+}
+
+// Scope describes the component that produced the telemetry.
+type Scope struct {
+	Name string `json:"name"`
+}
+
+// KeyValue is an attribute of a resource, span or data point.
+type KeyValue struct {
+	Key   string   `json:"key"`
+	Value AnyValue `json:"value"`
+}
+
+// AnyValue is the value of an attribute. Exactly one field is set.
+type AnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// String returns a string attribute.
+func String(key, value string) *KeyValue {
+	return &KeyValue{Key: key, Value: AnyValue{StringValue: &value}}
+}
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) *KeyValue {
+	return &KeyValue{Key: key, Value: AnyValue{BoolValue: &value}}
+}
+
+// Int64 returns an integer attribute.
+func Int64(key string, value int64) *KeyValue {
+	i := strconv.FormatInt(value, 10)
+	return &KeyValue{Key: key, Value: AnyValue{IntValue: &i}}
+}
+
+// Float64 returns a floating point attribute.
+func Float64(key string, value float64) *KeyValue {
+	return &KeyValue{Key: key, Value: AnyValue{DoubleValue: &value}}
+}
+
+// StringAttributes returns the labels as string attributes, sorted by key.
+func StringAttributes(labels map[string]string) []*KeyValue {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]*KeyValue, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, String(k, labels[k]))
+	}
+	return attrs
+}
@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package otlp
+
+// The OTLP span kinds and status codes.
+const (
+	SpanKindInternal = 1
+	SpanKindServer   = 2
+	SpanKindClient   = 3
+	SpanKindProducer = 4
+	SpanKindConsumer = 5
+
+	StatusCodeOk    = 1
+	StatusCodeError = 2
+)
+
+// ExportTraceServiceRequest is the body of an OTLP/HTTP trace export.
+type ExportTraceServiceRequest struct {
+	ResourceSpans []*ResourceSpans `json:"resourceSpans"`
+}
+
+type ResourceSpans struct {
+	Resource   Resource      `json:"resource"`
+	ScopeSpans []*ScopeSpans `json:"scopeSpans"`
+}
+
+type ScopeSpans struct {
+	Scope Scope   `json:"scope"`
+	Spans []*Span `json:"spans"`
+}
+
+type Span struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []*KeyValue `json:"attributes"`
+	Status            *Status     `json:"status,omitempty"`
+}
+
+type Status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
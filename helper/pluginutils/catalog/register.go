@@ -9,6 +9,9 @@ import (
 	"github.com/hashicorp/nomad/drivers/java"
 	"github.com/hashicorp/nomad/drivers/qemu"
 	"github.com/hashicorp/nomad/drivers/rawexec"
+	"github.com/hashicorp/nomad/logsinks/cloudwatch"
+	"github.com/hashicorp/nomad/logsinks/elasticsearch"
+	"github.com/hashicorp/nomad/logsinks/loki"
 )
 
 // This file is where all builtin plugins should be registered in the catalog.
@@ -20,4 +23,7 @@ func init() {
 	Register(qemu.PluginID, qemu.PluginConfig)
 	Register(java.PluginID, java.PluginConfig)
 	RegisterDeferredConfig(docker.PluginID, docker.PluginConfig, docker.PluginLoader)
+	Register(loki.PluginID, loki.PluginConfig)
+	Register(elasticsearch.PluginID, elasticsearch.PluginConfig)
+	Register(cloudwatch.PluginID, cloudwatch.PluginConfig)
 }
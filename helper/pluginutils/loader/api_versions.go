@@ -7,13 +7,17 @@ import (
 	"github.com/hashicorp/nomad/plugins/base"
 	"github.com/hashicorp/nomad/plugins/device"
 	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/fingerprint"
+	"github.com/hashicorp/nomad/plugins/logsink"
 )
 
 var (
 	// AgentSupportedApiVersions is the set of API versions supported by the
 	// Nomad agent by plugin type.
 	AgentSupportedApiVersions = map[string][]string{
-		base.PluginTypeDevice: {device.ApiVersion010},
-		base.PluginTypeDriver: {drivers.ApiVersion010},
+		base.PluginTypeDevice:      {device.ApiVersion010},
+		base.PluginTypeDriver:      {drivers.ApiVersion010},
+		base.PluginTypeFingerprint: {fingerprint.ApiVersion010},
+		base.PluginTypeLogSink:     {logsink.ApiVersion010},
 	}
 )
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"slices"
 
 	log "github.com/hashicorp/go-hclog"
 	plugin "github.com/hashicorp/go-plugin"
@@ -16,6 +17,8 @@ import (
 	"github.com/hashicorp/nomad/plugins/base"
 	"github.com/hashicorp/nomad/plugins/device"
 	"github.com/hashicorp/nomad/plugins/drivers"
+	"github.com/hashicorp/nomad/plugins/fingerprint"
+	"github.com/hashicorp/nomad/plugins/logsink"
 	"github.com/hashicorp/nomad/plugins/shared/hclspec"
 )
 
@@ -30,6 +33,28 @@ type PluginCatalog interface {
 
 	// Catalog returns the catalog of all plugins keyed by plugin type
 	Catalog() map[string][]*base.PluginInfoResponse
+
+	// LaunchConfig returns what's needed to launch and configure the plugin
+	// given its name and type from another process.
+	LaunchConfig(name, pluginType string) (*PluginLaunchConfig, error)
+}
+
+// PluginLaunchConfig is used to launch and configure a plugin outside of the
+// agent, such as the log sink plugins launched by logmon.
+type PluginLaunchConfig struct {
+	// Internal is whether the plugin is built into Nomad, in which case it is
+	// launched by its name rather than by ExePath.
+	Internal bool
+
+	// ExePath and Args are the command that launches an external plugin.
+	ExePath string
+	Args    []string
+
+	// Config is the msgpack encoded plugin configuration.
+	Config []byte
+
+	// ApiVersion is the API version to use with the plugin.
+	ApiVersion string
 }
 
 // InternalPluginConfig is used to configure launching an internal plugin.
@@ -190,6 +215,27 @@ func (l *PluginLoader) Dispense(name, pluginType string, config *base.AgentConfi
 	return instance, nil
 }
 
+// LaunchConfig returns what's needed to launch and configure a plugin from
+// another process.
+func (l *PluginLoader) LaunchConfig(name, pluginType string) (*PluginLaunchConfig, error) {
+	id := PluginID{
+		Name:       name,
+		PluginType: pluginType,
+	}
+	pinfo, ok := l.plugins[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown plugin with name %q and type %q", name, pluginType)
+	}
+
+	return &PluginLaunchConfig{
+		Internal:   pinfo.factory != nil,
+		ExePath:    pinfo.exePath,
+		Args:       slices.Clone(pinfo.args),
+		Config:     slices.Clone(pinfo.msgpackConfig),
+		ApiVersion: pinfo.apiVersion,
+	}, nil
+}
+
 // Reattach reattaches to a previously launched external plugin.
 func (l *PluginLoader) Reattach(name, pluginType string, config *plugin.ReattachConfig) (PluginInstance, error) {
 	return l.dispensePlugin(pluginType, "", "", nil, config, l.logger)
@@ -275,6 +321,10 @@ func getPluginMap(pluginType string, logger log.Logger) map[string]plugin.Plugin
 		pmap[base.PluginTypeDevice] = &device.PluginDevice{}
 	case base.PluginTypeDriver:
 		pmap[base.PluginTypeDriver] = drivers.NewDriverPlugin(nil, logger)
+	case base.PluginTypeFingerprint:
+		pmap[base.PluginTypeFingerprint] = &fingerprint.PluginFingerprint{}
+	case base.PluginTypeLogSink:
+		pmap[base.PluginTypeLogSink] = &logsink.PluginLogSink{}
 	}
 
 	return pmap
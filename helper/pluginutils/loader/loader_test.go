@@ -1005,6 +1005,49 @@ func TestPluginLoader_Dispense_Internal(t *testing.T) {
 	require.Equal(device.ApiVersion010, mock.negotiatedApiVersion)
 }
 
+func TestPluginLoader_LaunchConfig(t *testing.T) {
+	ci.Parallel(t)
+	require := require.New(t)
+
+	// Create the plugin
+	plugin := "mock-device"
+	pluginVersion := "v0.0.1"
+	h := newHarness(t, []string{plugin})
+
+	logger := testlog.HCLogger(t)
+	logger.SetLevel(log.Trace)
+	args := []string{"-plugin", "-name", plugin,
+		"-type", base.PluginTypeDevice, "-version", pluginVersion, "-api-version", device.ApiVersion010}
+	lconfig := &PluginLoaderConfig{
+		Logger:            logger,
+		PluginDir:         h.pluginDir(),
+		SupportedVersions: supportedApiVersions,
+		Configs: []*config.PluginConfig{
+			{
+				Name: plugin,
+				Args: args,
+				Config: map[string]interface{}{
+					"res_key": "set_config_worked",
+				},
+			},
+		},
+	}
+
+	l, err := NewPluginLoader(lconfig)
+	require.NoError(err)
+
+	lc, err := l.LaunchConfig(plugin, base.PluginTypeDevice)
+	require.NoError(err)
+	require.False(lc.Internal)
+	require.Equal(filepath.Join(h.pluginDir(), plugin), lc.ExePath)
+	require.Equal(args, lc.Args)
+	require.NotEmpty(lc.Config)
+	require.Equal(device.ApiVersion010, lc.ApiVersion)
+
+	_, err = l.LaunchConfig(plugin, base.PluginTypeDriver)
+	require.ErrorContains(err, "unknown plugin")
+}
+
 func TestPluginLoader_Dispense_NoConfigSchema_External(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
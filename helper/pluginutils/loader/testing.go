@@ -18,6 +18,8 @@ type MockCatalog struct {
 	DispenseF func(name, pluginType string, cfg *base.AgentConfig, logger log.Logger) (PluginInstance, error)
 	ReattachF func(name, pluginType string, config *plugin.ReattachConfig) (PluginInstance, error)
 	CatalogF  func() map[string][]*base.PluginInfoResponse
+
+	LaunchConfigF func(name, pluginType string) (*PluginLaunchConfig, error)
 }
 
 func (m *MockCatalog) Dispense(name, pluginType string, cfg *base.AgentConfig, logger log.Logger) (PluginInstance, error) {
@@ -32,6 +34,10 @@ func (m *MockCatalog) Catalog() map[string][]*base.PluginInfoResponse {
 	return m.CatalogF()
 }
 
+func (m *MockCatalog) LaunchConfig(name, pluginType string) (*PluginLaunchConfig, error) {
+	return m.LaunchConfigF(name, pluginType)
+}
+
 // MockInstance provides a mock PluginInstance to be used for testing
 type MockInstance struct {
 	InternalPlugin  bool
@@ -50,6 +50,12 @@ func (s *SingletonLoader) Catalog() map[string][]*base.PluginInfoResponse {
 	return s.loader.Catalog()
 }
 
+// LaunchConfig returns what's needed to launch and configure a plugin from
+// another process. Plugins launched this way aren't singletons.
+func (s *SingletonLoader) LaunchConfig(name, pluginType string) (*loader.PluginLaunchConfig, error) {
+	return s.loader.LaunchConfig(name, pluginType)
+}
+
 // Dispense returns the plugin given its name and type. This will also
 // configure the plugin. If there is an instance of an already running plugin,
 // this is used.
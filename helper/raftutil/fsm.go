@@ -95,6 +95,8 @@ func dummyFSM(logger hclog.Logger) (nomadFSM, error) {
 		// running agents, it does not impact the creation of the FSM for this
 		// dummy implementation.
 		JobTrackedVersions: 6,
+
+		VariablesTrackedVersions: 10,
 	}
 
 	return nomad.NewFSM(fsmConfig)
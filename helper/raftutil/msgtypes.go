@@ -65,6 +65,24 @@ var msgTypeNames = map[structs.MessageType]string{
 	structs.ACLBindingRulesDeleteRequestType:             "ACLBindingRulesDeleteRequestType",
 	structs.NodePoolUpsertRequestType:                    "NodePoolUpsertRequestType",
 	structs.NodePoolDeleteRequestType:                    "NodePoolDeleteRequestType",
+	structs.VariablesExpireRequestType:                   "VariablesExpireRequestType",
 	structs.NamespaceUpsertRequestType:                   "NamespaceUpsertRequestType",
 	structs.NamespaceDeleteRequestType:                   "NamespaceDeleteRequestType",
+	structs.EvalBumpPriorityRequestType:                  "EvalBumpPriorityRequestType",
+	structs.JobTemplateUpsertRequestType:                 "JobTemplateUpsertRequestType",
+	structs.JobTemplateDeleteRequestType:                 "JobTemplateDeleteRequestType",
+	structs.EventSinkRegisterRequestType:                 "EventSinkRegisterRequestType",
+	structs.EventSinkDeregisterRequestType:               "EventSinkDeregisterRequestType",
+	structs.EventSinkProgressRequestType:                 "EventSinkProgressRequestType",
+	structs.UsageUpsertRequestType:                       "UsageUpsertRequestType",
+	structs.ACLTokenUseRequestType:                       "ACLTokenUseRequestType",
+	structs.VariablesPurgeDeletedRequestType:             "VariablesPurgeDeletedRequestType",
+	structs.VariableGrantUpsertRequestType:               "VariableGrantUpsertRequestType",
+	structs.VariableGrantDeleteRequestType:               "VariableGrantDeleteRequestType",
+	structs.CSIVolumeSnapshotsUpdateRequestType:          "CSIVolumeSnapshotsUpdateRequestType",
+	structs.MaintenanceWindowUpsertRequestType:           "MaintenanceWindowUpsertRequestType",
+	structs.MaintenanceWindowDeleteRequestType:           "MaintenanceWindowDeleteRequestType",
+	structs.JobNotifyRequestType:                         "JobNotifyRequestType",
+	structs.JobDispatchReleaseRequestType:                "JobDispatchReleaseRequestType",
+	structs.ClientUpgradeUpsertRequestType:               "ClientUpgradeUpsertRequestType",
 }
@@ -0,0 +1,413 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package raftutil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/raft"
+
+	"github.com/hashicorp/nomad/nomad"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// surgeryType describes a type of object that can be operated on by snapshot
+// surgery.
+type surgeryType struct {
+	snapType   nomad.SnapshotType
+	namespaced bool
+	newObject  func() any
+
+	// related are the objects that belong to the object and are deleted
+	// with it
+	related []surgeryRelated
+}
+
+// surgeryRelated is a type of object that belongs to another object.
+type surgeryRelated struct {
+	name     string
+	snapType nomad.SnapshotType
+
+	// idField is the field holding the ID of the object it belongs to
+	idField string
+}
+
+var surgeryTypes = map[string]surgeryType{
+	"job": {
+		snapType:   nomad.JobSnapshot,
+		namespaced: true,
+		newObject:  func() any { return new(structs.Job) },
+		related: []surgeryRelated{
+			{name: "job-version", snapType: nomad.JobVersionSnapshot, idField: "ID"},
+			{name: "job-summary", snapType: nomad.JobSummarySnapshot, idField: "JobID"},
+			{name: "job-submission", snapType: nomad.JobSubmissionSnapshot, idField: "JobID"},
+		},
+	},
+	"alloc": {
+		snapType:   nomad.AllocSnapshot,
+		namespaced: true,
+		newObject:  func() any { return new(structs.Allocation) },
+	},
+	"eval": {
+		snapType:   nomad.EvalSnapshot,
+		namespaced: true,
+		newObject:  func() any { return new(structs.Evaluation) },
+	},
+	"deployment": {
+		snapType:   nomad.DeploymentSnapshot,
+		namespaced: true,
+		newObject:  func() any { return new(structs.Deployment) },
+	},
+	"node": {
+		snapType:  nomad.NodeSnapshot,
+		newObject: func() any { return new(structs.Node) },
+	},
+}
+
+// SurgeryTypes returns the types of objects that snapshot surgery supports.
+func SurgeryTypes() []string {
+	types := make([]string, 0, len(surgeryTypes))
+	for t := range surgeryTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// SurgeryObject identifies an object in a snapshot.
+type SurgeryObject struct {
+	Type      string
+	Namespace string
+	ID        string
+
+	// Size is the size of the encoded object in bytes
+	Size int
+}
+
+// snapshotRecord is a record of an FSM snapshot. Raw holds the encoded
+// record, which is written back unchanged unless the record is patched.
+type snapshotRecord struct {
+	snapType nomad.SnapshotType
+	raw      []byte
+	value    any
+}
+
+// field returns the string field of the generically decoded record.
+func (r *snapshotRecord) field(name string) string {
+	m, ok := r.value.(map[string]any)
+	if !ok {
+		return ""
+	}
+	s, _ := m[name].(string)
+	return s
+}
+
+// SnapshotSurgery lists, dumps, deletes, and patches objects in the latest
+// snapshot of a raft directory. It operates on the encoded records of the
+// snapshot rather than restoring it, so it works even if an object prevents
+// the FSM from restoring the snapshot. Modifications are written as a new
+// snapshot with the same index, which takes precedence over the original one.
+type SnapshotSurgery struct {
+	snaps *raft.FileSnapshotStore
+	meta  *raft.SnapshotMeta
+
+	logLastIdx uint64
+}
+
+// NewSnapshotSurgery opens the latest snapshot of the raft directory. It fails
+// if a running server holds the raft database of the directory open.
+func NewSnapshotSurgery(raftPath string) (*SnapshotSurgery, error) {
+	store, _, lastIdx, err := RaftStateInfo(filepath.Join(raftPath, "raft.db"))
+	if err != nil {
+		return nil, err
+	}
+	store.Close()
+
+	snaps, err := raft.NewFileSnapshotStoreWithLogger(raftPath, 1000, hclog.L())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot dir: %w", err)
+	}
+	snapshots, err := snaps.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	if len(snapshots) == 0 {
+		return nil, errors.New("no snapshots found")
+	}
+
+	return &SnapshotSurgery{
+		snaps:      snaps,
+		meta:       snapshots[0],
+		logLastIdx: lastIdx,
+	}, nil
+}
+
+// Meta returns the metadata of the snapshot being operated on.
+func (s *SnapshotSurgery) Meta() *raft.SnapshotMeta {
+	return s.meta
+}
+
+// LogsAfterSnapshot returns the number of raft log entries after the snapshot,
+// which are applied on top of the snapshot when the server starts.
+func (s *SnapshotSurgery) LogsAfterSnapshot() uint64 {
+	if s.logLastIdx <= s.meta.Index {
+		return 0
+	}
+	return s.logLastIdx - s.meta.Index
+}
+
+// List returns the objects of the type in the namespace, or in all namespaces
+// if namespace is empty.
+func (s *SnapshotSurgery) List(objType, namespace string) ([]*SurgeryObject, error) {
+	st, ok := surgeryTypes[objType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported object type %q", objType)
+	}
+
+	var objects []*SurgeryObject
+	err := s.walk(func(rec *snapshotRecord) error {
+		if rec.snapType != st.snapType {
+			return nil
+		}
+		obj := &SurgeryObject{
+			Type: objType,
+			ID:   rec.field("ID"),
+			Size: len(rec.raw),
+		}
+		if st.namespaced {
+			obj.Namespace = rec.field("Namespace")
+			if namespace != "" && obj.Namespace != namespace {
+				return nil
+			}
+		}
+		objects = append(objects, obj)
+		return nil
+	})
+	return objects, err
+}
+
+// Get returns the object with the ID in the namespace.
+func (s *SnapshotSurgery) Get(objType, namespace, id string) (any, error) {
+	st, ok := surgeryTypes[objType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported object type %q", objType)
+	}
+
+	var obj any
+	err := s.walk(func(rec *snapshotRecord) error {
+		if obj != nil || !s.matches(st, rec, namespace, id) {
+			return nil
+		}
+		obj = st.newObject()
+		return codec.NewDecoderBytes(rec.raw, structs.MsgpackHandle).Decode(obj)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, fmt.Errorf("%s %q not found", objType, id)
+	}
+	return obj, nil
+}
+
+// Delete removes the object with the ID in the namespace, and the objects that
+// belong to it, from the snapshot. It returns the removed objects. If dryRun
+// is true the snapshot isn't modified.
+func (s *SnapshotSurgery) Delete(objType, namespace, id string, dryRun bool) ([]*SurgeryObject, error) {
+	st, ok := surgeryTypes[objType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported object type %q", objType)
+	}
+
+	var deleted []*SurgeryObject
+	changed := func() bool { return len(deleted) > 0 }
+	err := s.rewrite(dryRun, changed, func(rec *snapshotRecord) ([]byte, error) {
+		if s.matches(st, rec, namespace, id) {
+			deleted = append(deleted, &SurgeryObject{
+				Type: objType, Namespace: rec.field("Namespace"), ID: id, Size: len(rec.raw)})
+			return nil, nil
+		}
+		for _, related := range st.related {
+			if rec.snapType == related.snapType &&
+				rec.field(related.idField) == id && rec.field("Namespace") == namespace {
+				deleted = append(deleted, &SurgeryObject{
+					Type: related.name, Namespace: namespace, ID: id, Size: len(rec.raw)})
+				return nil, nil
+			}
+		}
+		return rec.raw, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(deleted) == 0 {
+		return nil, fmt.Errorf("%s %q not found", objType, id)
+	}
+	return deleted, nil
+}
+
+// Patch applies the JSON patch to the object with the ID in the namespace.
+// Fields in the patch replace the fields of the object, and nested objects are
+// merged. It returns the object before and after the patch. If dryRun is true
+// the snapshot isn't modified.
+func (s *SnapshotSurgery) Patch(objType, namespace, id string, patch []byte, dryRun bool) (any, any, error) {
+	st, ok := surgeryTypes[objType]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported object type %q", objType)
+	}
+
+	var before, after any
+	changed := func() bool { return after != nil }
+	err := s.rewrite(dryRun, changed, func(rec *snapshotRecord) ([]byte, error) {
+		if after != nil || !s.matches(st, rec, namespace, id) {
+			return rec.raw, nil
+		}
+
+		before = st.newObject()
+		if err := codec.NewDecoderBytes(rec.raw, structs.MsgpackHandle).Decode(before); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", objType, err)
+		}
+		after = st.newObject()
+		if err := codec.NewDecoderBytes(rec.raw, structs.MsgpackHandle).Decode(after); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", objType, err)
+		}
+		if err := json.Unmarshal(patch, after); err != nil {
+			return nil, fmt.Errorf("failed to apply patch: %w", err)
+		}
+
+		var buf []byte
+		if err := codec.NewEncoderBytes(&buf, structs.MsgpackHandle).Encode(after); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", objType, err)
+		}
+		return buf, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if after == nil {
+		return nil, nil, fmt.Errorf("%s %q not found", objType, id)
+	}
+	return before, after, nil
+}
+
+func (s *SnapshotSurgery) matches(st surgeryType, rec *snapshotRecord, namespace, id string) bool {
+	if rec.snapType != st.snapType || rec.field("ID") != id {
+		return false
+	}
+	return !st.namespaced || rec.field("Namespace") == namespace
+}
+
+// walk calls fn for every record of the snapshot.
+func (s *SnapshotSurgery) walk(fn func(*snapshotRecord) error) error {
+	return s.read(func([]byte) error { return nil }, fn)
+}
+
+// read calls headerFn with the encoded snapshot header, and then fn for every
+// record of the snapshot.
+func (s *SnapshotSurgery) read(headerFn func([]byte) error, fn func(*snapshotRecord) error) error {
+	_, source, err := s.snaps.Open(s.meta.ID)
+	if err != nil {
+		return fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer source.Close()
+
+	// The decoder reads exactly one record at a time, so everything it reads
+	// through the tee is the encoded record
+	r := bufio.NewReader(source)
+	var raw bytes.Buffer
+	dec := codec.NewDecoder(io.TeeReader(r, &raw), structs.MsgpackHandle)
+
+	var header any
+	if err := dec.Decode(&header); err != nil {
+		return fmt.Errorf("failed to decode snapshot header: %w", err)
+	}
+	if err := headerFn(raw.Bytes()); err != nil {
+		return err
+	}
+
+	for {
+		msgType, err := r.ReadByte()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		raw.Reset()
+		rec := &snapshotRecord{snapType: nomad.SnapshotType(msgType)}
+		if err := dec.Decode(&rec.value); err != nil {
+			return fmt.Errorf("failed to decode record of snapshot type %d: %w", msgType, err)
+		}
+		rec.raw = raw.Bytes()
+
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewrite writes a new snapshot with the records returned by fn, which
+// returns nil to drop a record. The new snapshot is discarded if changed
+// returns false once all records are read. If dryRun is true the records are
+// read but no snapshot is written.
+func (s *SnapshotSurgery) rewrite(dryRun bool, changed func() bool, fn func(*snapshotRecord) ([]byte, error)) error {
+	if dryRun {
+		return s.walk(func(rec *snapshotRecord) error {
+			_, err := fn(rec)
+			return err
+		})
+	}
+
+	sink, err := s.snaps.Create(s.meta.Version, s.meta.Index, s.meta.Term,
+		s.meta.Configuration, s.meta.ConfigurationIndex, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	w := bufio.NewWriter(sink)
+
+	err = s.read(func(header []byte) error {
+		_, err := w.Write(header)
+		return err
+	}, func(rec *snapshotRecord) error {
+		raw, err := fn(rec)
+		if err != nil || raw == nil {
+			return err
+		}
+		w.WriteByte(byte(rec.snapType))
+		_, err = w.Write(raw)
+		return err
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		sink.Cancel()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if !changed() {
+		sink.Cancel()
+		return nil
+	}
+	if err := sink.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	// Further operations apply to the new snapshot
+	snapshots, err := s.snaps.List()
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	s.meta = snapshots[0]
+	return nil
+}
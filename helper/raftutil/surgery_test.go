@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package raftutil
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	"github.com/shoenig/test/must"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// surgeryTestDir returns a raft directory with a snapshot of a state with a
+// job and two of its allocations.
+func surgeryTestDir(t *testing.T) (string, *structs.Job, []*structs.Allocation) {
+	t.Helper()
+
+	dir := t.TempDir()
+	store, err := raftboltdb.NewBoltStore(filepath.Join(dir, "raft.db"))
+	must.NoError(t, err)
+	must.NoError(t, store.Close())
+
+	fsm, err := dummyFSM(hclog.NewNullLogger())
+	must.NoError(t, err)
+
+	job := mock.Job()
+	must.NoError(t, fsm.State().UpsertJob(structs.MsgTypeTestSetup, 10, nil, job))
+	allocs := []*structs.Allocation{mock.Alloc(), mock.Alloc()}
+	for _, alloc := range allocs {
+		alloc.Job = job
+		alloc.JobID = job.ID
+	}
+	must.NoError(t, fsm.State().UpsertAllocs(structs.MsgTypeTestSetup, 11, allocs))
+
+	snaps, err := raft.NewFileSnapshotStoreWithLogger(dir, 10, hclog.NewNullLogger())
+	must.NoError(t, err)
+	sink, err := snaps.Create(raft.SnapshotVersionMax, 11, 2, raft.Configuration{}, 1, nil)
+	must.NoError(t, err)
+	snap, err := fsm.Snapshot()
+	must.NoError(t, err)
+	must.NoError(t, snap.Persist(sink))
+	must.NoError(t, sink.Close())
+
+	return dir, job, allocs
+}
+
+// restoreSurgeryTestDir restores the newest snapshot of the raft directory.
+func restoreSurgeryTestDir(t *testing.T, dir string) *state.StateStore {
+	t.Helper()
+
+	snaps, err := raft.NewFileSnapshotStoreWithLogger(dir, 10, hclog.NewNullLogger())
+	must.NoError(t, err)
+	list, err := snaps.List()
+	must.NoError(t, err)
+	_, source, err := snaps.Open(list[0].ID)
+	must.NoError(t, err)
+
+	fsm, err := dummyFSM(hclog.NewNullLogger())
+	must.NoError(t, err)
+	must.NoError(t, fsm.Restore(source))
+	return fsm.State()
+}
+
+func TestSnapshotSurgery_ListDump(t *testing.T) {
+	ci.Parallel(t)
+
+	dir, job, allocs := surgeryTestDir(t)
+	surgery, err := NewSnapshotSurgery(dir)
+	must.NoError(t, err)
+	must.Eq(t, 11, surgery.Meta().Index)
+	must.Eq(t, 0, surgery.LogsAfterSnapshot())
+
+	objects, err := surgery.List("alloc", "")
+	must.NoError(t, err)
+	must.Len(t, 2, objects)
+	must.Eq(t, structs.DefaultNamespace, objects[0].Namespace)
+	must.Positive(t, objects[0].Size)
+
+	objects, err = surgery.List("alloc", "other")
+	must.NoError(t, err)
+	must.Len(t, 0, objects)
+
+	obj, err := surgery.Get("alloc", structs.DefaultNamespace, allocs[1].ID)
+	must.NoError(t, err)
+	must.Eq(t, allocs[1].ID, obj.(*structs.Allocation).ID)
+	must.Eq(t, job.ID, obj.(*structs.Allocation).JobID)
+
+	_, err = surgery.Get("alloc", "other", allocs[1].ID)
+	must.ErrorContains(t, err, "not found")
+	_, err = surgery.List("volume", "")
+	must.EqError(t, err, `unsupported object type "volume"`)
+}
+
+func TestSnapshotSurgery_Delete(t *testing.T) {
+	ci.Parallel(t)
+
+	dir, job, allocs := surgeryTestDir(t)
+	surgery, err := NewSnapshotSurgery(dir)
+	must.NoError(t, err)
+	original := surgery.Meta().ID
+
+	// A dry run doesn't write a snapshot
+	deleted, err := surgery.Delete("alloc", structs.DefaultNamespace, allocs[0].ID, true)
+	must.NoError(t, err)
+	must.Len(t, 1, deleted)
+	must.Eq(t, original, surgery.Meta().ID)
+
+	deleted, err = surgery.Delete("alloc", structs.DefaultNamespace, allocs[0].ID, false)
+	must.NoError(t, err)
+	must.Len(t, 1, deleted)
+	must.NotEq(t, original, surgery.Meta().ID)
+	must.Eq(t, 11, surgery.Meta().Index)
+
+	store := restoreSurgeryTestDir(t, dir)
+	out, err := store.AllocByID(nil, allocs[0].ID)
+	must.NoError(t, err)
+	must.Nil(t, out)
+	out, err = store.AllocByID(nil, allocs[1].ID)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+
+	// Deleting a job deletes its versions and summary too
+	deleted, err = surgery.Delete("job", job.Namespace, job.ID, false)
+	must.NoError(t, err)
+	must.SliceContainsFunc(t, deleted, "job-summary", func(obj *SurgeryObject, name string) bool {
+		return obj.Type == name
+	})
+
+	store = restoreSurgeryTestDir(t, dir)
+	outJob, err := store.JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Nil(t, outJob)
+	summary, err := store.JobSummaryByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Nil(t, summary)
+
+	// Deleting an object that doesn't exist doesn't write a snapshot
+	current := surgery.Meta().ID
+	_, err = surgery.Delete("job", job.Namespace, job.ID, false)
+	must.ErrorContains(t, err, "not found")
+	must.Eq(t, current, surgery.Meta().ID)
+}
+
+func TestSnapshotSurgery_Patch(t *testing.T) {
+	ci.Parallel(t)
+
+	dir, job, _ := surgeryTestDir(t)
+	surgery, err := NewSnapshotSurgery(dir)
+	must.NoError(t, err)
+
+	before, after, err := surgery.Patch("job", job.Namespace, job.ID,
+		[]byte(`{"Priority": 90, "Meta": {"patched": "true"}}`), false)
+	must.NoError(t, err)
+	must.Eq(t, job.Priority, before.(*structs.Job).Priority)
+	must.Eq(t, 90, after.(*structs.Job).Priority)
+
+	store := restoreSurgeryTestDir(t, dir)
+	out, err := store.JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, 90, out.Priority)
+	must.Eq(t, "true", out.Meta["patched"])
+	must.Eq(t, job.Meta["owner"], out.Meta["owner"])
+	must.Eq(t, job.TaskGroups[0].Name, out.TaskGroups[0].Name)
+
+	_, _, err = surgery.Patch("job", job.Namespace, job.ID, []byte(`{"Priority": "high"}`), false)
+	must.ErrorContains(t, err, "failed to apply patch")
+}
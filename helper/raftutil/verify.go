@@ -0,0 +1,201 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package raftutil
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad"
+	"github.com/hashicorp/raft"
+	raftwal "github.com/hashicorp/raft-wal"
+)
+
+// VerifyResult is the result of verifying the raft logs and snapshots of a
+// server.
+type VerifyResult struct {
+	// Backend is the raft log store backend found.
+	Backend string
+
+	// FirstIndex and LastIndex are the indexes of the first and last logs.
+	FirstIndex uint64
+	LastIndex  uint64
+
+	// LastTerm is the term of the last log.
+	LastTerm uint64
+
+	// CurrentTerm is the current term persisted in the stable store.
+	CurrentTerm uint64
+
+	// Snapshots are the snapshots found, newest first.
+	Snapshots []*VerifiedSnapshot
+
+	// Errors are the integrity problems found.
+	Errors []string
+}
+
+// VerifiedSnapshot describes a snapshot whose checksum was verified.
+type VerifiedSnapshot struct {
+	ID    string
+	Index uint64
+	Term  uint64
+	Size  int64
+}
+
+// FindRaftLogStore finds the raft directory under the path p and returns it,
+// along with the log store backend in use.
+func FindRaftLogStore(p string) (dir string, backend string, err error) {
+	for _, candidate := range []string{filepath.Join(p, "server", "raft"), filepath.Join(p, "raft"), p} {
+		if _, err := os.Stat(filepath.Join(candidate, "wal")); err == nil {
+			return candidate, nomad.RaftLogStoreWAL, nil
+		}
+		if _, err := os.Stat(filepath.Join(candidate, "raft.db")); err == nil {
+			return candidate, nomad.RaftLogStoreBoltDB, nil
+		}
+	}
+
+	dir, err = FindRaftDir(p)
+	if err != nil {
+		return "", "", err
+	}
+	return dir, nomad.RaftLogStoreBoltDB, nil
+}
+
+// VerifyRaftState checks the integrity of the raft logs and snapshots in the
+// raft directory dir. Errors are only returned if the state can't be read at
+// all; integrity problems are reported in the result.
+func VerifyRaftState(dir, backend string) (*VerifyResult, error) {
+	result := &VerifyResult{Backend: backend}
+
+	if err := verifyLogs(dir, backend, result); err != nil {
+		return nil, err
+	}
+	if err := verifySnapshots(dir, result); err != nil {
+		return nil, err
+	}
+
+	// The logs must continue where the newest snapshot ends, or the state
+	// can't be restored
+	var snapshotIndex uint64
+	if len(result.Snapshots) > 0 {
+		snapshotIndex = result.Snapshots[0].Index
+	}
+	if result.LastIndex > 0 && result.FirstIndex > snapshotIndex+1 {
+		result.addError("logs start at index %d but the newest snapshot ends at index %d",
+			result.FirstIndex, snapshotIndex)
+	}
+
+	return result, nil
+}
+
+func (r *VerifyResult) addError(format string, args ...any) {
+	r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+}
+
+// verifyLogs reads every log in the log store, and checks the logs are
+// contiguous and their terms never decrease.
+func verifyLogs(dir, backend string, result *VerifyResult) error {
+	var store interface {
+		raft.LogStore
+		raft.StableStore
+		io.Closer
+	}
+
+	switch backend {
+	case nomad.RaftLogStoreWAL:
+		wal, err := raftwal.Open(filepath.Join(dir, "wal"))
+		if err != nil {
+			return fmt.Errorf("failed to open raft logs: %v", err)
+		}
+		store = wal
+	default:
+		bolt, _, _, err := RaftStateInfo(filepath.Join(dir, "raft.db"))
+		if err != nil {
+			return err
+		}
+		store = bolt
+	}
+	defer store.Close()
+
+	first, err := store.FirstIndex()
+	if err != nil {
+		return fmt.Errorf("failed to fetch first index: %v", err)
+	}
+	last, err := store.LastIndex()
+	if err != nil {
+		return fmt.Errorf("failed to fetch last index: %v", err)
+	}
+	result.FirstIndex, result.LastIndex = first, last
+
+	result.CurrentTerm, err = store.GetUint64([]byte("CurrentTerm"))
+	if err != nil && err.Error() != "not found" {
+		return fmt.Errorf("failed to fetch current term: %v", err)
+	}
+
+	if last == 0 {
+		return nil
+	}
+
+	var prevTerm uint64
+	for i := first; i <= last; i++ {
+		var log raft.Log
+		if err := store.GetLog(i, &log); err != nil {
+			result.addError("failed to read log at index %d: %v", i, err)
+			continue
+		}
+		if log.Index != i {
+			result.addError("log at index %d has index %d", i, log.Index)
+		}
+		if log.Term < prevTerm {
+			result.addError("log at index %d has term %d lower than the previous term %d",
+				i, log.Term, prevTerm)
+		}
+		prevTerm = log.Term
+	}
+	result.LastTerm = prevTerm
+
+	if result.CurrentTerm < result.LastTerm {
+		result.addError("current term %d is lower than the term %d of the last log",
+			result.CurrentTerm, result.LastTerm)
+	}
+
+	return nil
+}
+
+// verifySnapshots opens every snapshot, which verifies its checksum.
+func verifySnapshots(dir string, result *VerifyResult) error {
+	// The store only lists as many snapshots as it retains, so retain all of
+	// them. Nothing is reaped unless a snapshot is created.
+	snaps, err := raft.NewFileSnapshotStoreWithLogger(dir, math.MaxInt, hclog.NewNullLogger())
+	if err != nil {
+		return fmt.Errorf("failed to open snapshots: %v", err)
+	}
+
+	metas, err := snaps.List()
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %v", err)
+	}
+
+	for _, meta := range metas {
+		_, rc, err := snaps.Open(meta.ID)
+		if err != nil {
+			result.addError("snapshot %s is corrupt: %v", meta.ID, err)
+			continue
+		}
+		rc.Close()
+
+		result.Snapshots = append(result.Snapshots, &VerifiedSnapshot{
+			ID:    meta.ID,
+			Index: meta.Index,
+			Term:  meta.Term,
+			Size:  meta.Size,
+		})
+	}
+
+	return nil
+}
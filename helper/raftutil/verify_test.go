@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package raftutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	"github.com/shoenig/test/must"
+)
+
+// writeRaftState writes logs from first to last and a snapshot at
+// snapshotIndex to a new raft directory.
+func writeRaftState(t *testing.T, first, last, snapshotIndex uint64) string {
+	dir := t.TempDir()
+
+	store, err := raftboltdb.NewBoltStore(filepath.Join(dir, "raft.db"))
+	must.NoError(t, err)
+	var logs []*raft.Log
+	for i := first; i <= last; i++ {
+		logs = append(logs, &raft.Log{Index: i, Term: 1 + i/5, Type: raft.LogNoop})
+	}
+	must.NoError(t, store.StoreLogs(logs))
+	must.NoError(t, store.SetUint64([]byte("CurrentTerm"), 1+last/5))
+	must.NoError(t, store.Close())
+
+	snaps, err := raft.NewFileSnapshotStoreWithLogger(dir, 1, hclog.NewNullLogger())
+	must.NoError(t, err)
+	sink, err := snaps.Create(raft.SnapshotVersionMax, snapshotIndex, 1, raft.Configuration{}, 1, nil)
+	must.NoError(t, err)
+	_, err = sink.Write([]byte("state"))
+	must.NoError(t, err)
+	must.NoError(t, sink.Close())
+
+	return dir
+}
+
+func TestVerifyRaftState(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := writeRaftState(t, 5, 20, 10)
+
+	found, backend, err := FindRaftLogStore(dir)
+	must.NoError(t, err)
+	must.Eq(t, dir, found)
+	must.Eq(t, nomad.RaftLogStoreBoltDB, backend)
+
+	result, err := VerifyRaftState(dir, backend)
+	must.NoError(t, err)
+	must.SliceEmpty(t, result.Errors)
+	must.Eq(t, 5, result.FirstIndex)
+	must.Eq(t, 20, result.LastIndex)
+	must.Eq(t, 5, result.LastTerm)
+	must.Eq(t, 5, result.CurrentTerm)
+	must.Len(t, 1, result.Snapshots)
+	must.Eq(t, 10, result.Snapshots[0].Index)
+}
+
+func TestVerifyRaftState_Gap(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := writeRaftState(t, 5, 20, 2)
+
+	result, err := VerifyRaftState(dir, nomad.RaftLogStoreBoltDB)
+	must.NoError(t, err)
+	must.Eq(t, []string{"logs start at index 5 but the newest snapshot ends at index 2"}, result.Errors)
+}
+
+func TestVerifyRaftState_CorruptSnapshot(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := writeRaftState(t, 5, 20, 10)
+
+	states, err := filepath.Glob(filepath.Join(dir, "snapshots", "*", "state.bin"))
+	must.NoError(t, err)
+	must.Len(t, 1, states)
+	must.NoError(t, os.WriteFile(states[0], []byte("corrupt"), 0o644))
+
+	result, err := VerifyRaftState(dir, nomad.RaftLogStoreBoltDB)
+	must.NoError(t, err)
+	must.Len(t, 2, result.Errors)
+	must.StrContains(t, result.Errors[0], "is corrupt: CRC mismatch")
+	must.StrContains(t, result.Errors[1], "logs start at index 5 but the newest snapshot ends at index 0")
+}
@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper/otlp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// maxErrorBody is the amount of a failed response body that is logged.
+const maxErrorBody = 512
+
+// exporter is an OpenTelemetry span exporter that sends spans to an
+// OTLP/HTTP endpoint with JSON encoding.
+type exporter struct {
+	logger  hclog.Logger
+	address string
+	headers map[string]string
+	client  *http.Client
+}
+
+// ExportSpans sends a batch of spans. Failed batches are logged and counted
+// rather than retried, so a slow or unavailable endpoint doesn't hold back
+// spans in memory.
+func (e *exporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	if err := e.send(ctx, spans); err != nil {
+		e.logger.Warn("failed to export spans", "spans", len(spans), "error", err)
+		metrics.IncrCounter([]string{"nomad", "tracing", "dropped_spans"}, float32(len(spans)))
+	}
+	return nil
+}
+
+// Shutdown implements sdktrace.SpanExporter. The exporter holds no state that
+// needs to be released.
+func (e *exporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func (e *exporter) send(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	body, err := json.Marshal(encodeSpans(spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Nomad")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// encodeSpans returns the OTLP trace export request of the spans. The spans
+// of a batch all come from the same tracer provider, so they share a resource
+// and an instrumentation scope.
+func encodeSpans(spans []sdktrace.ReadOnlySpan) *otlp.ExportTraceServiceRequest {
+	resource := &otlp.ResourceSpans{
+		Resource: otlp.Resource{Attributes: encodeAttributes(spans[0].Resource().Attributes())},
+	}
+	scope := &otlp.ScopeSpans{
+		Scope: otlp.Scope{Name: spans[0].InstrumentationScope().Name},
+		Spans: make([]*otlp.Span, 0, len(spans)),
+	}
+	resource.ScopeSpans = []*otlp.ScopeSpans{scope}
+
+	for _, s := range spans {
+		sc := s.SpanContext()
+		span := &otlp.Span{
+			TraceID:           sc.TraceID().String(),
+			SpanID:            sc.SpanID().String(),
+			Name:              s.Name(),
+			Kind:              int(s.SpanKind()),
+			StartTimeUnixNano: strconv.FormatInt(s.StartTime().UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.EndTime().UnixNano(), 10),
+			Attributes:        encodeAttributes(s.Attributes()),
+		}
+		if parent := s.Parent(); parent.HasSpanID() {
+			span.ParentSpanID = parent.SpanID().String()
+		}
+
+		// The OpenTelemetry and OTLP status codes have different values.
+		switch status := s.Status(); status.Code {
+		case codes.Error:
+			span.Status = &otlp.Status{Code: otlp.StatusCodeError, Message: status.Description}
+		case codes.Ok:
+			span.Status = &otlp.Status{Code: otlp.StatusCodeOk}
+		}
+		scope.Spans = append(scope.Spans, span)
+	}
+
+	return &otlp.ExportTraceServiceRequest{ResourceSpans: []*otlp.ResourceSpans{resource}}
+}
+
+func encodeAttributes(attrs []attribute.KeyValue) []*otlp.KeyValue {
+	kvs := make([]*otlp.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		key := string(a.Key)
+		switch a.Value.Type() {
+		case attribute.BOOL:
+			kvs = append(kvs, otlp.Bool(key, a.Value.AsBool()))
+		case attribute.INT64:
+			kvs = append(kvs, otlp.Int64(key, a.Value.AsInt64()))
+		case attribute.FLOAT64:
+			kvs = append(kvs, otlp.Float64(key, a.Value.AsFloat64()))
+		default:
+			kvs = append(kvs, otlp.String(key, a.Value.Emit()))
+		}
+	}
+	return kvs
+}
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute is a key/value pair describing a span.
+type Attribute = attribute.KeyValue
+
+// String returns a string attribute.
+func String(key, value string) Attribute {
+	return attribute.String(key, value)
+}
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute {
+	return attribute.Int(key, value)
+}
+
+// Bool returns a boolean attribute.
+func Bool(key string, value bool) Attribute {
+	return attribute.Bool(key, value)
+}
+
+// Span is a timed operation within a trace. All methods are safe to call on a
+// nil Span, which is returned when tracing is disabled, so callers don't need
+// to check whether tracing is enabled.
+type Span struct {
+	tracer *Tracer
+
+	// ctx holds the OpenTelemetry span, so children can be started from it
+	ctx  context.Context
+	span trace.Span
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(attrs...)
+}
+
+// End completes the span. If err is not nil the span's status is set to
+// error. Calling End more than once has no effect.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	if err != nil {
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+// Child starts a span whose parent is this span.
+func (s *Span) Child(name string, attrs ...Attribute) *Span {
+	if s == nil {
+		return nil
+	}
+	return s.tracer.start(s.ctx, name, trace.WithAttributes(attrs...))
+}
+
+// ChildAt starts a span whose parent is this span, with the given start
+// time.
+func (s *Span) ChildAt(name string, start time.Time, attrs ...Attribute) *Span {
+	if s == nil {
+		return nil
+	}
+	return s.tracer.start(s.ctx, name, trace.WithTimestamp(start), trace.WithAttributes(attrs...))
+}
+
+// TraceParent returns the span context as a W3C traceparent header value, so
+// it can be stored with objects or sent with RPCs that continue the trace
+// elsewhere.
+func (s *Span) TraceParent() string {
+	if s == nil {
+		return ""
+	}
+	return s.tracer.inject(s.ctx)
+}
+
+// TraceID returns the hex encoded ID of the span's trace.
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return s.span.SpanContext().TraceID().String()
+}
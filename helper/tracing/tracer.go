@@ -0,0 +1,193 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package tracing traces Nomad with the OpenTelemetry SDK. Spans are exported
+// in batches to an OTLP/HTTP endpoint with JSON encoding, and their context is
+// propagated between components and across RPCs in the W3C traceparent
+// format.
+package tracing
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// maxBatchSize is the number of spans that triggers an export before
+	// the export interval elapses.
+	maxBatchSize = 512
+
+	// maxQueueSize is the number of ended spans buffered for export. Spans
+	// are dropped when the queue is full.
+	maxQueueSize = 4096
+
+	// scopeName is the instrumentation scope of the spans created by Nomad.
+	scopeName = "github.com/hashicorp/nomad"
+
+	// traceParentHeader is the key of the W3C trace context propagated with
+	// evaluations, plans and RPCs.
+	traceParentHeader = "traceparent"
+)
+
+// Tracer starts spans and exports them. All methods are safe to call on a
+// nil Tracer, which is used when tracing is disabled.
+type Tracer struct {
+	provider   *sdktrace.TracerProvider
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	timeout    time.Duration
+}
+
+// NewTracer returns a Tracer that exports spans to the configured endpoint.
+// The resource attributes describe the agent that created the spans. It
+// returns nil if tracing isn't enabled.
+func NewTracer(logger hclog.Logger, cfg *config.TracingConfig, resource map[string]string) (*Tracer, error) {
+	if !cfg.IsEnabled() {
+		return nil, nil
+	}
+
+	cfg = cfg.Copy()
+	cfg.Canonicalize()
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tracing configuration: %v", err)
+	}
+
+	tlsConf := &tls.Config{
+		InsecureSkipVerify: cfg.TLSSkipVerify,
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tracing ca_file: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("failed to parse tracing ca_file %q", cfg.CAFile)
+		}
+		tlsConf.RootCAs = pool
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConf
+
+	exporter := &exporter{
+		logger:  logger.Named("tracing"),
+		address: cfg.Address,
+		headers: cfg.Headers,
+		client:  &http.Client{Transport: transport, Timeout: cfg.Timeout},
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("service.name", "nomad")}
+	keys := make([]string, 0, len(resource))
+	for k := range resource {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attrs = append(attrs, attribute.String(k, resource[k]))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter,
+			sdktrace.WithBatchTimeout(cfg.ExportInterval),
+			sdktrace.WithExportTimeout(cfg.Timeout),
+			sdktrace.WithMaxExportBatchSize(maxBatchSize),
+			sdktrace.WithMaxQueueSize(maxQueueSize),
+		),
+		// Spans follow the sampling decision of their parent, so a trace
+		// is either exported in full or not at all.
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(*cfg.SampleRatio))),
+		sdktrace.WithResource(sdkresource.NewSchemaless(attrs...)),
+	)
+
+	return &Tracer{
+		provider:   provider,
+		tracer:     provider.Tracer(scopeName),
+		propagator: propagation.TraceContext{},
+		timeout:    cfg.Timeout,
+	}, nil
+}
+
+// Start starts a span. If traceParent is a valid W3C traceparent the span
+// continues that trace and follows its sampling decision, otherwise the span
+// starts a new trace.
+func (t *Tracer) Start(name, traceParent string, attrs ...Attribute) *Span {
+	return t.StartAt(name, traceParent, time.Now(), attrs...)
+}
+
+// StartAt starts a span with the given start time, which is used for spans
+// covering time spent before they could be created, such as time spent in a
+// queue.
+func (t *Tracer) StartAt(name, traceParent string, start time.Time, attrs ...Attribute) *Span {
+	if t == nil {
+		return nil
+	}
+	return t.start(t.extract(traceParent), name,
+		trace.WithTimestamp(start), trace.WithAttributes(attrs...))
+}
+
+// StartRPC starts the server span of an RPC, which continues the trace of
+// the caller if traceParent is a valid W3C traceparent. The span is named
+// Service/Method, following the OpenTelemetry conventions for RPC spans.
+func (t *Tracer) StartRPC(method, traceParent string) *Span {
+	if t == nil {
+		return nil
+	}
+
+	name := method
+	attrs := []Attribute{attribute.String("rpc.system", "nomad")}
+	if service, m, ok := strings.Cut(method, "."); ok {
+		name = service + "/" + m
+		attrs = append(attrs, attribute.String("rpc.service", service), attribute.String("rpc.method", m))
+	} else {
+		attrs = append(attrs, attribute.String("rpc.method", method))
+	}
+	return t.start(t.extract(traceParent), name,
+		trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+}
+
+// Shutdown exports any buffered spans and stops the tracer.
+func (t *Tracer) Shutdown() {
+	if t == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+	_ = t.provider.Shutdown(ctx)
+}
+
+func (t *Tracer) start(ctx context.Context, name string, opts ...trace.SpanStartOption) *Span {
+	ctx, span := t.tracer.Start(ctx, name, opts...)
+	return &Span{tracer: t, ctx: ctx, span: span}
+}
+
+// extract returns a context holding the remote span context of the W3C
+// traceparent, or an empty context if it isn't valid.
+func (t *Tracer) extract(traceParent string) context.Context {
+	ctx := context.Background()
+	if traceParent == "" {
+		return ctx
+	}
+	return t.propagator.Extract(ctx, propagation.MapCarrier{traceParentHeader: traceParent})
+}
+
+// inject returns the W3C traceparent of the span context in ctx.
+func (t *Tracer) inject(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	t.propagator.Inject(ctx, carrier)
+	return carrier.Get(traceParentHeader)
+}
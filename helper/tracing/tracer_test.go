@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/otlp"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/shoenig/test/must"
+)
+
+func TestTracer_Disabled(t *testing.T) {
+	ci.Parallel(t)
+
+	tracer, err := NewTracer(testlog.HCLogger(t), &config.TracingConfig{}, nil)
+	must.NoError(t, err)
+	must.Nil(t, tracer)
+
+	// A nil tracer and its nil spans are safe to use.
+	span := tracer.Start("Job.Register", "")
+	must.Nil(t, span)
+	span.SetAttributes(String("nomad.job.id", "example"))
+	must.Nil(t, span.Child("scheduler.process"))
+	must.Eq(t, "", span.TraceParent())
+	span.End(nil)
+	tracer.Shutdown()
+}
+
+func TestTracer_Export(t *testing.T) {
+	ci.Parallel(t)
+
+	reqCh := make(chan *otlp.ExportTraceServiceRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		must.Eq(t, "application/json", r.Header.Get("Content-Type"))
+		must.Eq(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		body, err := io.ReadAll(r.Body)
+		must.NoError(t, err)
+		var req otlp.ExportTraceServiceRequest
+		must.NoError(t, json.Unmarshal(body, &req))
+		reqCh <- &req
+	}))
+	defer srv.Close()
+
+	tracer, err := NewTracer(testlog.HCLogger(t), &config.TracingConfig{
+		Enabled:        pointer.Of(true),
+		Address:        srv.URL + "/v1/traces",
+		Headers:        map[string]string{"Authorization": "Bearer secret"},
+		ExportInterval: time.Hour,
+	}, map[string]string{"nomad.region": "global"})
+	must.NoError(t, err)
+
+	root := tracer.Start("Job.Register", "", String("nomad.job.id", "example"))
+	child := tracer.StartAt("eval_broker.wait", root.TraceParent(), time.Now().Add(-time.Second))
+	child.SetAttributes(Int("nomad.eval.priority", 50), Bool("nomad.eval.blocked", false))
+	child.End(errors.New("eval failed"))
+	root.End(nil)
+	root.End(nil)
+
+	// Shutting down exports the buffered spans.
+	tracer.Shutdown()
+
+	var req *otlp.ExportTraceServiceRequest
+	select {
+	case req = <-reqCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for export")
+	}
+
+	must.Len(t, 1, req.ResourceSpans)
+	rs := req.ResourceSpans[0]
+	must.Len(t, 2, rs.Resource.Attributes)
+	must.Eq(t, "nomad.region", rs.Resource.Attributes[0].Key)
+	must.Eq(t, "service.name", rs.Resource.Attributes[1].Key)
+	must.Eq(t, "nomad", *rs.Resource.Attributes[1].Value.StringValue)
+
+	spans := rs.ScopeSpans[0].Spans
+	must.Len(t, 2, spans)
+
+	must.Eq(t, "eval_broker.wait", spans[0].Name)
+	must.Eq(t, otlp.SpanKindInternal, spans[0].Kind)
+	must.Eq(t, root.TraceID(), spans[0].TraceID)
+	must.Eq(t, spans[1].SpanID, spans[0].ParentSpanID)
+	must.Eq(t, otlp.StatusCodeError, spans[0].Status.Code)
+	must.Eq(t, "eval failed", spans[0].Status.Message)
+	must.Eq(t, "50", *spans[0].Attributes[0].Value.IntValue)
+	must.False(t, *spans[0].Attributes[1].Value.BoolValue)
+
+	must.Eq(t, "Job.Register", spans[1].Name)
+	must.Eq(t, "", spans[1].ParentSpanID)
+	must.Nil(t, spans[1].Status)
+	must.Eq(t, "example", *spans[1].Attributes[0].Value.StringValue)
+}
+
+func TestTracer_Sampling(t *testing.T) {
+	ci.Parallel(t)
+
+	tracer, err := NewTracer(testlog.HCLogger(t), &config.TracingConfig{
+		Enabled:     pointer.Of(true),
+		Address:     "http://127.0.0.1:4318/v1/traces",
+		SampleRatio: pointer.Of(0.0),
+	}, nil)
+	must.NoError(t, err)
+	defer tracer.Shutdown()
+
+	// Unsampled spans still propagate their context and sampling decision.
+	root := tracer.Start("Job.Register", "")
+	must.StrHasSuffix(t, "-00", root.TraceParent())
+	child := root.Child("scheduler.process")
+	must.StrHasSuffix(t, "-00", child.TraceParent())
+	must.Eq(t, root.TraceID(), child.TraceID())
+
+	// Parents that are sampled are followed regardless of the ratio.
+	sampled := tracer.Start("scheduler.process", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	must.StrHasPrefix(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-", sampled.TraceParent())
+	must.StrHasSuffix(t, "-01", sampled.TraceParent())
+	must.Eq(t, "4bf92f3577b34da6a3ce929d0e0e4736", sampled.TraceID())
+
+	// Invalid trace contexts start a new trace.
+	invalid := tracer.Start("scheduler.process", "00-00000000000000000000000000000000-00f067aa0ba902b7-01")
+	must.NotEq(t, "00000000000000000000000000000000", invalid.TraceID())
+	must.StrHasSuffix(t, "-00", invalid.TraceParent())
+}
+
+func TestTracer_StartRPC(t *testing.T) {
+	ci.Parallel(t)
+
+	reqCh := make(chan *otlp.ExportTraceServiceRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req otlp.ExportTraceServiceRequest
+		must.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		reqCh <- &req
+	}))
+	defer srv.Close()
+
+	tracer, err := NewTracer(testlog.HCLogger(t), &config.TracingConfig{
+		Enabled:        pointer.Of(true),
+		Address:        srv.URL,
+		ExportInterval: time.Hour,
+	}, nil)
+	must.NoError(t, err)
+
+	caller := tracer.Start("Job.Register", "")
+	span := tracer.StartRPC("Job.Register", caller.TraceParent())
+	span.End(nil)
+	caller.End(nil)
+	tracer.Shutdown()
+
+	var req *otlp.ExportTraceServiceRequest
+	select {
+	case req = <-reqCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for export")
+	}
+
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	must.Len(t, 2, spans)
+	must.Eq(t, "Job/Register", spans[0].Name)
+	must.Eq(t, otlp.SpanKindServer, spans[0].Kind)
+	must.Eq(t, caller.TraceID(), spans[0].TraceID)
+	must.Eq(t, spans[1].SpanID, spans[0].ParentSpanID)
+	must.Eq(t, "Job", *spans[0].Attributes[1].Value.StringValue)
+	must.Eq(t, "Register", *spans[0].Attributes[2].Value.StringValue)
+}
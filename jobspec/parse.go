@@ -435,11 +435,16 @@ func parseUpdate(result **api.UpdateStrategy, list *ast.ObjectList) error {
 		"auto_revert",
 		"auto_promote",
 		"canary",
+		"strategy",
+		"canary_placement",
+		"canary_constraint",
 	}
 	if err := checkHCLKeys(o.Val, valid); err != nil {
 		return err
 	}
 
+	delete(m, "canary_constraint")
+
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
 		WeaklyTypedInput: true,
@@ -448,7 +453,22 @@ func parseUpdate(result **api.UpdateStrategy, list *ast.ObjectList) error {
 	if err != nil {
 		return err
 	}
-	return dec.Decode(m)
+	if err := dec.Decode(m); err != nil {
+		return err
+	}
+
+	// Parse canary constraints
+	if ot, ok := o.Val.(*ast.ObjectType); ok {
+		if co := ot.List.Filter("canary_constraint"); len(co.Items) > 0 {
+			if *result == nil {
+				*result = &api.UpdateStrategy{}
+			}
+			if err := parseConstraints(&(*result).CanaryConstraints, co); err != nil {
+				return multierror.Prefix(err, "canary_constraint ->")
+			}
+		}
+	}
+	return nil
 }
 
 func parseDisconnect(result **api.DisconnectStrategy, list *ast.ObjectList) error {
@@ -50,6 +50,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			"task",
 			"ephemeral_disk",
 			"update",
+			"array",
 			"disconnect",
 			"reschedule",
 			"vault",
@@ -80,6 +81,7 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 		delete(m, "restart")
 		delete(m, "ephemeral_disk")
 		delete(m, "update")
+		delete(m, "array")
 		delete(m, "disconnect")
 		delete(m, "vault")
 		delete(m, "migrate")
@@ -170,6 +172,13 @@ func parseGroups(result *api.Job, list *ast.ObjectList) error {
 			}
 		}
 
+		// If we have a job array, then parse that
+		if o := listVal.Filter("array"); len(o.Items) > 0 {
+			if err := parseArray(&g.Array, o); err != nil {
+				return multierror.Prefix(err, fmt.Sprintf("'%s', array ->", n))
+			}
+		}
+
 		// If we have an disconnect strategy, then parse that
 		if o := listVal.Filter("disconnect"); len(o.Items) > 0 {
 			if err := parseDisconnect(&g.Disconnect, o); err != nil {
@@ -296,6 +305,7 @@ func parseEphemeralDisk(result **api.EphemeralDisk, list *ast.ObjectList) error
 		"sticky",
 		"size",
 		"migrate",
+		"hook",
 	}
 	if err := checkHCLKeys(obj.Val, valid); err != nil {
 		return err
@@ -305,16 +315,103 @@ func parseEphemeralDisk(result **api.EphemeralDisk, list *ast.ObjectList) error
 	if err := hcl.DecodeObject(&m, obj.Val); err != nil {
 		return err
 	}
+	delete(m, "hook")
 
 	var ephemeralDisk api.EphemeralDisk
 	if err := mapstructure.WeakDecode(m, &ephemeralDisk); err != nil {
 		return err
 	}
+
+	var listVal *ast.ObjectList
+	if ot, ok := obj.Val.(*ast.ObjectType); ok {
+		listVal = ot.List
+	} else {
+		return fmt.Errorf("ephemeral_disk should be an object")
+	}
+
+	if o := listVal.Filter("hook"); len(o.Items) > 0 {
+		if err := parseMigrateHooks(&ephemeralDisk.MigrateHooks, o); err != nil {
+			return multierror.Prefix(err, "hook ->")
+		}
+	}
 	*result = &ephemeralDisk
 
 	return nil
 }
 
+func parseMigrateHooks(result *[]*api.MigrateHook, list *ast.ObjectList) error {
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("hook should have exactly one stage label")
+		}
+		stage := item.Keys[0].Token.Value().(string)
+
+		valid := []string{
+			"task",
+			"command",
+			"args",
+			"timeout",
+		}
+		if err := checkHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("'%s' ->", stage))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		hook := &api.MigrateHook{Stage: stage}
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           hook,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return err
+		}
+
+		*result = append(*result, hook)
+	}
+
+	return nil
+}
+
+func parseArray(result **api.ArrayConfig, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'array' block allowed")
+	}
+
+	// Get our array object
+	obj := list.Items[0]
+
+	// Check for invalid keys
+	valid := []string{
+		"count",
+		"concurrency",
+	}
+	if err := checkHCLKeys(obj.Val, valid); err != nil {
+		return err
+	}
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, obj.Val); err != nil {
+		return err
+	}
+
+	var array api.ArrayConfig
+	if err := mapstructure.WeakDecode(m, &array); err != nil {
+		return err
+	}
+	*result = &array
+
+	return nil
+}
+
 func parseRestartPolicy(final **api.RestartPolicy, list *ast.ObjectList) error {
 	list = list.Elem()
 	if len(list.Items) > 1 {
@@ -331,6 +428,12 @@ func parseRestartPolicy(final **api.RestartPolicy, list *ast.ObjectList) error {
 		"delay",
 		"mode",
 		"render_templates",
+		"delay_function",
+		"delay_multiplier",
+		"max_delay",
+		"jitter",
+		"budget",
+		"budget_window",
 	}
 	if err := checkHCLKeys(obj.Val, valid); err != nil {
 		return err
@@ -415,6 +518,7 @@ func parseScalingPolicy(item *ast.ObjectItem) (*api.ScalingPolicy, error) {
 		"policy",
 		"enabled",
 		"type",
+		"schedule",
 	}
 	if err := checkHCLKeys(item.Val, valid); err != nil {
 		return nil, err
@@ -425,6 +529,7 @@ func parseScalingPolicy(item *ast.ObjectItem) (*api.ScalingPolicy, error) {
 		return nil, err
 	}
 	delete(m, "policy")
+	delete(m, "schedule")
 
 	var result api.ScalingPolicy
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
@@ -453,5 +558,27 @@ func parseScalingPolicy(item *ast.ObjectItem) (*api.ScalingPolicy, error) {
 		}
 	}
 
+	// Parse the schedules
+	for _, o := range listVal.Filter("schedule").Elem().Items {
+		valid := []string{
+			"cron",
+			"count",
+			"time_zone",
+		}
+		if err := checkHCLKeys(o.Val, valid); err != nil {
+			return nil, multierror.Prefix(err, "schedule ->")
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, o.Val); err != nil {
+			return nil, err
+		}
+		var schedule api.ScalingSchedule
+		if err := mapstructure.WeakDecode(m, &schedule); err != nil {
+			return nil, err
+		}
+		result.Schedules = append(result.Schedules, &schedule)
+	}
+
 	return &result, nil
 }
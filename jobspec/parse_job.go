@@ -34,6 +34,7 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 	delete(m, "affinity")
 	delete(m, "meta")
 	delete(m, "migrate")
+	delete(m, "notify")
 	delete(m, "parameterized")
 	delete(m, "periodic")
 	delete(m, "reschedule")
@@ -73,8 +74,10 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		"migrate",
 		"name",
 		"namespace",
+		"notify",
 		"parameterized",
 		"periodic",
+		"prefetch",
 		"priority",
 		"region",
 		"reschedule",
@@ -132,6 +135,13 @@ func parseJob(result *api.Job, list *ast.ObjectList) error {
 		}
 	}
 
+	// If we have a notify block, then parse that
+	if o := listVal.Filter("notify"); len(o.Items) > 0 {
+		if err := parseJobNotify(&result.Notify, o); err != nil {
+			return multierror.Prefix(err, "notify ->")
+		}
+	}
+
 	// If we have a reschedule block, then parse that
 	if o := listVal.Filter("reschedule"); len(o.Items) > 0 {
 		if err := parseReschedulePolicy(&result.Reschedule, o); err != nil {
@@ -291,6 +301,8 @@ func parseParameterizedJob(result **api.ParameterizedJobConfig, list *ast.Object
 		"payload",
 		"meta_required",
 		"meta_optional",
+		"max_concurrent",
+		"queue_limit",
 	}
 	if err := checkHCLKeys(o.Val, valid); err != nil {
 		return err
@@ -305,3 +317,38 @@ func parseParameterizedJob(result **api.ParameterizedJobConfig, list *ast.Object
 	*result = &d
 	return nil
 }
+
+func parseJobNotify(result **api.JobNotify, list *ast.ObjectList) error {
+	list = list.Elem()
+	if len(list.Items) > 1 {
+		return fmt.Errorf("only one 'notify' block allowed per job")
+	}
+
+	// Get our resource object
+	o := list.Items[0]
+
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, o.Val); err != nil {
+		return err
+	}
+
+	// Check for invalid keys
+	valid := []string{
+		"webhook",
+		"secret",
+		"headers",
+		"on",
+	}
+	if err := checkHCLKeys(o.Val, valid); err != nil {
+		return err
+	}
+
+	// Build the notify block
+	var n api.JobNotify
+	if err := mapstructure.WeakDecode(m, &n); err != nil {
+		return err
+	}
+
+	*result = &n
+	return nil
+}
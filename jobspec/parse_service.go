@@ -769,6 +769,7 @@ func parseProxy(o *ast.ObjectItem) (*api.ConsulProxy, error) {
 		"local_service_port",
 		"upstreams",
 		"expose",
+		"envoy",
 		"config",
 	}
 
@@ -784,6 +785,7 @@ func parseProxy(o *ast.ObjectItem) (*api.ConsulProxy, error) {
 
 	delete(m, "upstreams")
 	delete(m, "expose")
+	delete(m, "envoy")
 	delete(m, "config")
 
 	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
@@ -796,7 +798,7 @@ func parseProxy(o *ast.ObjectItem) (*api.ConsulProxy, error) {
 		return nil, fmt.Errorf("proxy: %v", err)
 	}
 
-	// Parse upstreams, expose, and config
+	// Parse upstreams, expose, envoy, and config
 
 	var listVal *ast.ObjectList
 	if ot, ok := o.Val.(*ast.ObjectType); ok {
@@ -827,6 +829,16 @@ func parseProxy(o *ast.ObjectItem) (*api.ConsulProxy, error) {
 		}
 	}
 
+	if eo := listVal.Filter("envoy"); len(eo.Items) > 1 {
+		return nil, fmt.Errorf("only 1 envoy object supported")
+	} else if len(eo.Items) == 1 {
+		if e, err := parseEnvoy(eo.Items[0]); err != nil {
+			return nil, err
+		} else {
+			proxy.Envoy = e
+		}
+	}
+
 	// If we have config, then parse that
 	if o := listVal.Filter("config"); len(o.Items) > 1 {
 		return nil, fmt.Errorf("only 1 meta object supported")
@@ -852,6 +864,100 @@ func parseProxy(o *ast.ObjectItem) (*api.ConsulProxy, error) {
 	return &proxy, nil
 }
 
+func parseEnvoy(eo *ast.ObjectItem) (*api.ConsulEnvoyConfig, error) {
+	valid := []string{
+		"concurrency",
+		"stats",
+		"tracing",
+		"bootstrap_template",
+	}
+
+	if err := checkHCLKeys(eo.Val, valid); err != nil {
+		return nil, multierror.Prefix(err, "envoy ->")
+	}
+
+	var envoy api.ConsulEnvoyConfig
+	var m map[string]interface{}
+	if err := hcl.DecodeObject(&m, eo.Val); err != nil {
+		return nil, err
+	}
+
+	delete(m, "stats")
+	delete(m, "tracing")
+
+	if err := mapstructure.WeakDecode(m, &envoy); err != nil {
+		return nil, fmt.Errorf("envoy: %v", err)
+	}
+
+	var listVal *ast.ObjectList
+	if eoType, ok := eo.Val.(*ast.ObjectType); ok {
+		listVal = eoType.List
+	} else {
+		return nil, fmt.Errorf("envoy: should be an object")
+	}
+
+	if so := listVal.Filter("stats"); len(so.Items) > 1 {
+		return nil, fmt.Errorf("only 1 stats object supported")
+	} else if len(so.Items) == 1 {
+		valid := []string{
+			"statsd_url",
+			"dogstatsd_url",
+			"prometheus_bind_addr",
+			"tags",
+			"flush_interval",
+		}
+		if err := checkHCLKeys(so.Items[0].Val, valid); err != nil {
+			return nil, multierror.Prefix(err, "stats ->")
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, so.Items[0].Val); err != nil {
+			return nil, err
+		}
+
+		var stats api.ConsulEnvoyStats
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &stats,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := dec.Decode(m); err != nil {
+			return nil, fmt.Errorf("stats: %v", err)
+		}
+		envoy.Stats = &stats
+	}
+
+	if to := listVal.Filter("tracing"); len(to.Items) > 1 {
+		return nil, fmt.Errorf("only 1 tracing object supported")
+	} else if len(to.Items) == 1 {
+		valid := []string{
+			"provider",
+			"address",
+			"path",
+			"service_name",
+		}
+		if err := checkHCLKeys(to.Items[0].Val, valid); err != nil {
+			return nil, multierror.Prefix(err, "tracing ->")
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, to.Items[0].Val); err != nil {
+			return nil, err
+		}
+
+		var tracing api.ConsulEnvoyTracing
+		if err := mapstructure.WeakDecode(m, &tracing); err != nil {
+			return nil, fmt.Errorf("tracing: %v", err)
+		}
+		envoy.Tracing = &tracing
+	}
+
+	return &envoy, nil
+}
+
 func parseExpose(eo *ast.ObjectItem) (*api.ConsulExposeConfig, error) {
 	valid := []string{
 		"path", // an array of path blocks
@@ -1031,6 +1137,8 @@ func parseChecks(service *api.Service, checkObjs *ast.ObjectList) error {
 			"failures_before_warning",
 			"on_update",
 			"body",
+			"weight",
+			"use_container_health",
 		}
 		if err := checkHCLKeys(co.Val, valid); err != nil {
 			return multierror.Prefix(err, "check ->")
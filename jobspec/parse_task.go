@@ -40,7 +40,9 @@ var (
 		"identity",
 		"lifecycle",
 		"leader",
+		"oom_policy",
 		"restart",
+		"sealed",
 		"service",
 		"template",
 		"vault",
@@ -112,8 +114,10 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 	delete(m, "identity")
 	delete(m, "logs")
 	delete(m, "meta")
+	delete(m, "oom_policy")
 	delete(m, "resources")
 	delete(m, "restart")
+	delete(m, "sealed")
 	delete(m, "service")
 	delete(m, "template")
 	delete(m, "vault")
@@ -149,6 +153,19 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		}
 	}
 
+	// If we have sealed values, then parse them
+	if o := listVal.Filter("sealed"); len(o.Items) > 0 {
+		for _, o := range o.Elem().Items {
+			var m map[string]interface{}
+			if err := hcl.DecodeObject(&m, o.Val); err != nil {
+				return nil, err
+			}
+			if err := mapstructure.WeakDecode(m, &t.Sealed); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	if o := listVal.Filter("service"); len(o.Items) > 0 {
 		services, err := parseServices(o)
 		if err != nil {
@@ -265,6 +282,11 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 			"max_file_size",
 			"enabled", // COMPAT(1.6.0): remove in favor of disabled
 			"disabled",
+			"backpressure",
+			"rotate_interval",
+			"compression",
+			"retention",
+			"sink",
 		}
 		if err := checkHCLKeys(logsBlock.Val, valid); err != nil {
 			return nil, multierror.Prefix(err, "logs ->")
@@ -273,12 +295,29 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		if err := hcl.DecodeObject(&m, logsBlock.Val); err != nil {
 			return nil, err
 		}
+		delete(m, "sink")
 
 		var log api.LogConfig
-		if err := mapstructure.WeakDecode(m, &log); err != nil {
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           &log,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := dec.Decode(m); err != nil {
 			return nil, err
 		}
 
+		if ot, ok := logsBlock.Val.(*ast.ObjectType); ok {
+			if o := ot.List.Filter("sink"); len(o.Items) > 0 {
+				if err := parseLogSinks(&log.Sinks, o); err != nil {
+					return nil, multierror.Prefix(err, "logs ->")
+				}
+			}
+		}
+
 		t.LogConfig = &log
 	}
 
@@ -366,6 +405,9 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		valid := []string{
 			"hook",
 			"sidecar",
+			"wait_healthy",
+			"healthy_timeout",
+			"unhealthy_action",
 		}
 		if err := checkHCLKeys(lifecycleBlock.Val, valid); err != nil {
 			return nil, multierror.Prefix(err, "lifecycle ->")
@@ -376,7 +418,44 @@ func parseTask(item *ast.ObjectItem, keys []string) (*api.Task, error) {
 		}
 
 		t.Lifecycle = &api.TaskLifecycle{}
-		if err := mapstructure.WeakDecode(m, t.Lifecycle); err != nil {
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+			Result:           t.Lifecycle,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := dec.Decode(m); err != nil {
+			return nil, err
+		}
+	}
+
+	// If we have an oom_policy block parse that
+	if o := listVal.Filter("oom_policy"); len(o.Items) > 0 {
+		if len(o.Items) > 1 {
+			return nil, fmt.Errorf("only one oom_policy block is allowed in a task. Number of oom_policy blocks found: %d", len(o.Items))
+		}
+
+		var m map[string]interface{}
+		oomBlock := o.Items[0]
+
+		// Check for invalid keys
+		valid := []string{
+			"mode",
+			"memory_increase",
+			"memory_max",
+		}
+		if err := checkHCLKeys(oomBlock.Val, valid); err != nil {
+			return nil, multierror.Prefix(err, "oom_policy ->")
+		}
+
+		if err := hcl.DecodeObject(&m, oomBlock.Val); err != nil {
+			return nil, err
+		}
+
+		t.OOMPolicy = &api.OOMPolicy{}
+		if err := mapstructure.WeakDecode(m, t.OOMPolicy); err != nil {
 			return nil, err
 		}
 	}
@@ -392,6 +471,7 @@ func parseArtifacts(result *[]*api.TaskArtifact, list *ast.ObjectList) error {
 			"headers",
 			"mode",
 			"destination",
+			"identity",
 		}
 		if err := checkHCLKeys(o.Val, valid); err != nil {
 			return err
@@ -629,6 +709,7 @@ func parseResources(result *api.Resources, list *ast.ObjectList) error {
 		"network",
 		"device",
 		"cores",
+		"exclusive_cores",
 	}
 	if err := checkHCLKeys(listVal, valid); err != nil {
 		return multierror.Prefix(err, "resources ->")
@@ -796,3 +877,40 @@ func parseIdentity(out *api.WorkloadIdentity, list *ast.ObjectList) error {
 
 	return nil
 }
+
+func parseLogSinks(result *[]*api.LogSink, list *ast.ObjectList) error {
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("sink should have exactly one plugin name label")
+		}
+		name := item.Keys[0].Token.Value().(string)
+
+		valid := []string{
+			"options",
+		}
+		if err := checkHCLKeys(item.Val, valid); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("sink '%s' ->", name))
+		}
+
+		var m map[string]interface{}
+		if err := hcl.DecodeObject(&m, item.Val); err != nil {
+			return err
+		}
+
+		sink := &api.LogSink{Name: name}
+		dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			WeaklyTypedInput: true,
+			Result:           sink,
+		})
+		if err != nil {
+			return err
+		}
+		if err := dec.Decode(m); err != nil {
+			return err
+		}
+
+		*result = append(*result, sink)
+	}
+
+	return nil
+}
@@ -41,6 +41,9 @@ func uint64ToPtr(u uint64) *uint64 {
 func int64ToPtr(i int64) *int64 {
 	return &i
 }
+func float64ToPtr(f float64) *float64 {
+	return &f
+}
 
 func TestParse(t *testing.T) {
 	ci.Parallel(t)
@@ -201,6 +204,12 @@ func TestParse(t *testing.T) {
 							Delay:           timeToPtr(15 * time.Second),
 							Mode:            stringToPtr("delay"),
 							RenderTemplates: boolToPtr(false),
+							DelayFunction:   stringToPtr("exponential"),
+							DelayMultiplier: float64ToPtr(1.5),
+							MaxDelay:        timeToPtr(1 * time.Minute),
+							Jitter:          float64ToPtr(0.1),
+							Budget:          intToPtr(20),
+							BudgetWindow:    timeToPtr(2 * time.Hour),
 						},
 						Spreads: []*api.Spread{
 							{
@@ -235,6 +244,20 @@ func TestParse(t *testing.T) {
 						EphemeralDisk: &api.EphemeralDisk{
 							Sticky: boolToPtr(true),
 							SizeMB: intToPtr(150),
+							MigrateHooks: []*api.MigrateHook{
+								{
+									Stage:   "pre_migrate",
+									Task:    stringToPtr("binstore"),
+									Command: stringToPtr("/bin/flush"),
+									Args:    []string{"--all"},
+									Timeout: timeToPtr(time.Minute),
+								},
+								{
+									Stage:   "post_restore",
+									Task:    stringToPtr("binstore"),
+									Command: stringToPtr("/bin/reopen"),
+								},
+							},
 						},
 						Update: &api.UpdateStrategy{
 							MaxParallel:      intToPtr(3),
@@ -245,6 +268,7 @@ func TestParse(t *testing.T) {
 							AutoRevert:       boolToPtr(false),
 							AutoPromote:      boolToPtr(false),
 							Canary:           intToPtr(2),
+							CanaryPlacement:  stringToPtr("distinct"),
 						},
 						Migrate: &api.MigrateStrategy{
 							MaxParallel:     intToPtr(2),
@@ -283,6 +307,11 @@ func TestParse(t *testing.T) {
 								RestartPolicy: &api.RestartPolicy{
 									Attempts: intToPtr(10),
 								},
+								OOMPolicy: &api.OOMPolicy{
+									Mode:             stringToPtr("adjust"),
+									MemoryIncreaseMB: intToPtr(128),
+									MemoryMaxMB:      intToPtr(1024),
+								},
 								Services: []*api.Service{
 									{
 										Tags:       []string{"foo", "bar"},
@@ -356,9 +385,22 @@ func TestParse(t *testing.T) {
 								KillTimeout:   timeToPtr(22 * time.Second),
 								ShutdownDelay: 11 * time.Second,
 								LogConfig: &api.LogConfig{
-									MaxFiles:      intToPtr(14),
-									MaxFileSizeMB: intToPtr(101),
-									Disabled:      boolToPtr(false),
+									MaxFiles:       intToPtr(14),
+									MaxFileSizeMB:  intToPtr(101),
+									Disabled:       boolToPtr(false),
+									Backpressure:   stringToPtr("drop"),
+									RotateInterval: timeToPtr(24 * time.Hour),
+									Compression:    stringToPtr("gzip"),
+									Retention:      timeToPtr(168 * time.Hour),
+									Sinks: []*api.LogSink{
+										{
+											Name:    "loki",
+											Options: map[string]string{"team": "storage"},
+										},
+										{
+											Name: "cloudwatch",
+										},
+									},
 								},
 								Artifacts: []*api.TaskArtifact{
 									{
@@ -366,6 +408,7 @@ func TestParse(t *testing.T) {
 										GetterOptions: map[string]string{
 											"checksum": "md5:b8a4f3f72ecab0510a6a31e997461c5f",
 										},
+										GetterIdentity: stringToPtr("artifacts"),
 									},
 									{
 										GetterSource: stringToPtr("http://bar.com/artifact"),
@@ -869,9 +912,11 @@ func TestParse(t *testing.T) {
 				Name: stringToPtr("parameterized_job"),
 
 				ParameterizedJob: &api.ParameterizedJobConfig{
-					Payload:      "required",
-					MetaRequired: []string{"foo", "bar"},
-					MetaOptional: []string{"baz", "bam"},
+					Payload:       "required",
+					MetaRequired:  []string{"foo", "bar"},
+					MetaOptional:  []string{"baz", "bam"},
+					MaxConcurrent: 10,
+					QueueLimit:    1000,
 				},
 
 				TaskGroups: []*api.TaskGroup{
@@ -891,6 +936,34 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"job-notify.hcl",
+			&api.Job{
+				ID:   stringToPtr("notify"),
+				Name: stringToPtr("notify"),
+				Type: stringToPtr("batch"),
+
+				Notify: &api.JobNotify{
+					Webhook: "https://ci.example.com/hooks/nomad",
+					Secret:  "s3cr3t",
+					Headers: map[string]string{"X-Team": "data"},
+					On:      []string{"failed"},
+				},
+
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("foo"),
+						Tasks: []*api.Task{
+							{
+								Name:   "bar",
+								Driver: "docker",
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"job-with-kill-signal.hcl",
 			&api.Job{
@@ -1340,6 +1413,39 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"tg-service-proxy-envoy.hcl",
+			&api.Job{
+				ID:   stringToPtr("group_service_proxy_envoy"),
+				Name: stringToPtr("group_service_proxy_envoy"),
+				TaskGroups: []*api.TaskGroup{{
+					Name: stringToPtr("group"),
+					Services: []*api.Service{{
+						Name: "example",
+						Connect: &api.ConsulConnect{
+							SidecarService: &api.ConsulSidecarService{
+								Proxy: &api.ConsulProxy{
+									Envoy: &api.ConsulEnvoyConfig{
+										Concurrency:       2,
+										BootstrapTemplate: "{}",
+										Stats: &api.ConsulEnvoyStats{
+											StatsdURL:     "udp://127.0.0.1:8125",
+											Tags:          []string{"env=prod"},
+											FlushInterval: timeToPtr(5 * time.Second),
+										},
+										Tracing: &api.ConsulEnvoyTracing{
+											Provider: "zipkin",
+											Address:  "zipkin.service.consul:9411",
+										},
+									},
+								},
+							},
+						},
+					}},
+				}},
+			},
+			false,
+		},
 		{
 			"tg-service-connect-sidecar_task-name.hcl",
 			&api.Job{
@@ -1775,6 +1881,35 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"tg-scaling-policy-schedule.hcl",
+			&api.Job{
+				ID:   stringToPtr("elastic"),
+				Name: stringToPtr("elastic"),
+				TaskGroups: []*api.TaskGroup{
+					{
+						Name: stringToPtr("group"),
+						Scaling: &api.ScalingPolicy{
+							Type: "horizontal",
+							Min:  int64ToPtr(2),
+							Max:  int64ToPtr(10),
+							Schedules: []*api.ScalingSchedule{
+								{
+									Cron:     "0 8 * * 1-5",
+									Count:    8,
+									TimeZone: "Europe/Berlin",
+								},
+								{
+									Cron:  "0 20 * * *",
+									Count: 2,
+								},
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"tg-scaling-policy-missing-max.hcl",
 			nil,
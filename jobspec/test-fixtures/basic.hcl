@@ -108,6 +108,12 @@ job "binstore-storagelocker" {
       delay            = "15s"
       mode             = "delay"
       render_templates = false
+      delay_function   = "exponential"
+      delay_multiplier = 1.5
+      max_delay        = "1m"
+      jitter           = 0.1
+      budget           = 20
+      budget_window    = "2h"
     }
 
     reschedule {
@@ -118,6 +124,18 @@ job "binstore-storagelocker" {
     ephemeral_disk {
       sticky = true
       size   = 150
+
+      hook "pre_migrate" {
+        task    = "binstore"
+        command = "/bin/flush"
+        args    = ["--all"]
+        timeout = "1m"
+      }
+
+      hook "post_restore" {
+        task    = "binstore"
+        command = "/bin/reopen"
+      }
     }
 
     update {
@@ -129,6 +147,7 @@ job "binstore-storagelocker" {
       auto_revert       = false
       auto_promote      = false
       canary            = 2
+      canary_placement  = "distinct"
     }
 
     migrate {
@@ -202,10 +221,28 @@ job "binstore-storagelocker" {
         attempts = 10
       }
 
+      oom_policy {
+        mode            = "adjust"
+        memory_increase = 128
+        memory_max      = 1024
+      }
+
       logs {
-        disabled      = false
-        max_files     = 14
-        max_file_size = 101
+        disabled        = false
+        max_files       = 14
+        max_file_size   = 101
+        backpressure    = "drop"
+        rotate_interval = "24h"
+        compression     = "gzip"
+        retention       = "168h"
+
+        sink "loki" {
+          options = {
+            team = "storage"
+          }
+        }
+
+        sink "cloudwatch" {}
       }
 
       env {
@@ -295,7 +332,8 @@ job "binstore-storagelocker" {
       shutdown_delay = "11s"
 
       artifact {
-        source = "http://foo.com/artifact"
+        source   = "http://foo.com/artifact"
+        identity = "artifacts"
 
         options {
           checksum = "md5:b8a4f3f72ecab0510a6a31e997461c5f"
@@ -0,0 +1,22 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "notify" {
+  type = "batch"
+
+  notify {
+    webhook = "https://ci.example.com/hooks/nomad"
+    secret  = "s3cr3t"
+    on      = ["failed"]
+
+    headers = {
+      "X-Team" = "data"
+    }
+  }
+
+  group "foo" {
+    task "bar" {
+      driver = "docker"
+    }
+  }
+}
@@ -3,9 +3,11 @@
 
 job "parameterized_job" {
   parameterized {
-    payload       = "required"
-    meta_required = ["foo", "bar"]
-    meta_optional = ["baz", "bam"]
+    payload        = "required"
+    meta_required  = ["foo", "bar"]
+    meta_optional  = ["baz", "bam"]
+    max_concurrent = 10
+    queue_limit    = 1000
   }
 
   group "foo" {
@@ -0,0 +1,22 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "elastic" {
+  group "group" {
+    scaling {
+      min = 2
+      max = 10
+
+      schedule {
+        cron      = "0 8 * * 1-5"
+        count     = 8
+        time_zone = "Europe/Berlin"
+      }
+
+      schedule {
+        cron  = "0 20 * * *"
+        count = 2
+      }
+    }
+  }
+}
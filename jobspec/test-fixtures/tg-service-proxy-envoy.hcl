@@ -0,0 +1,32 @@
+# Copyright (c) HashiCorp, Inc.
+# SPDX-License-Identifier: MPL-2.0
+
+job "group_service_proxy_envoy" {
+  group "group" {
+    service {
+      name = "example"
+
+      connect {
+        sidecar_service {
+          proxy {
+            envoy {
+              concurrency        = 2
+              bootstrap_template = "{}"
+
+              stats {
+                statsd_url     = "udp://127.0.0.1:8125"
+                tags           = ["env=prod"]
+                flush_interval = "5s"
+              }
+
+              tracing {
+                provider = "zipkin"
+                address  = "zipkin.service.consul:9411"
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}
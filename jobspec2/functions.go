@@ -5,6 +5,8 @@ package jobspec2
 
 import (
 	"fmt"
+	"net/netip"
+	"strings"
 
 	"github.com/hashicorp/go-cty-funcs/cidr"
 	"github.com/hashicorp/go-cty-funcs/crypto"
@@ -35,6 +37,7 @@ func Functions(basedir string, allowFS bool) map[string]function.Function {
 		"ceil":            stdlib.CeilFunc,
 		"chomp":           stdlib.ChompFunc,
 		"chunklist":       stdlib.ChunklistFunc,
+		"cidrcontains":    cidrContainsFunc,
 		"cidrhost":        cidr.HostFunc,
 		"cidrnetmask":     cidr.NetmaskFunc,
 		"cidrsubnet":      cidr.SubnetFunc,
@@ -48,6 +51,7 @@ func Functions(basedir string, allowFS bool) map[string]function.Function {
 		"csvdecode":       stdlib.CSVDecodeFunc,
 		"distinct":        stdlib.DistinctFunc,
 		"element":         stdlib.ElementFunc,
+		"endswith":        endsWithFunc,
 		"flatten":         stdlib.FlattenFunc,
 		"floor":           stdlib.FloorFunc,
 		"format":          stdlib.FormatFunc,
@@ -71,6 +75,8 @@ func Functions(basedir string, allowFS bool) map[string]function.Function {
 		"pow":             stdlib.PowFunc,
 		"range":           stdlib.RangeFunc,
 		"reverse":         stdlib.ReverseFunc,
+		"regex":           stdlib.RegexFunc,
+		"regexall":        stdlib.RegexAllFunc,
 		"replace":         stdlib.ReplaceFunc,
 		"regex_replace":   stdlib.RegexReplaceFunc,
 		"rsadecrypt":      crypto.RsaDecryptFunc,
@@ -84,9 +90,12 @@ func Functions(basedir string, allowFS bool) map[string]function.Function {
 		"slice":           stdlib.SliceFunc,
 		"sort":            stdlib.SortFunc,
 		"split":           stdlib.SplitFunc,
+		"startswith":      startsWithFunc,
+		"strcontains":     strContainsFunc,
 		"strlen":          stdlib.StrlenFunc,
 		"strrev":          stdlib.ReverseFunc,
 		"substr":          stdlib.SubstrFunc,
+		"sum":             sumFunc,
 		"timeadd":         stdlib.TimeAddFunc,
 		"title":           stdlib.TitleFunc,
 		"trim":            stdlib.TrimFunc,
@@ -103,6 +112,9 @@ func Functions(basedir string, allowFS bool) map[string]function.Function {
 		"yamlencode":      ctyyaml.YAMLEncodeFunc,
 		"zipmap":          stdlib.ZipmapFunc,
 
+		// aliases, regex_replace already replaces all matches
+		"regex_replace_all": stdlib.RegexReplaceFunc,
+
 		// filesystem calls
 		"abspath":    guardFS(allowFS, filesystem.AbsPathFunc),
 		"basename":   guardFS(allowFS, filesystem.BasenameFunc),
@@ -135,3 +147,76 @@ func guardFS(allowFS bool, fn function.Function) function.Function {
 
 	return function.New(spec)
 }
+
+// startsWithFunc returns whether a string starts with a prefix.
+var startsWithFunc = stringPredicateFunc("prefix", strings.HasPrefix)
+
+// endsWithFunc returns whether a string ends with a suffix.
+var endsWithFunc = stringPredicateFunc("suffix", strings.HasSuffix)
+
+// strContainsFunc returns whether a string contains a substring.
+var strContainsFunc = stringPredicateFunc("substr", strings.Contains)
+
+func stringPredicateFunc(param string, fn func(string, string) bool) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{Name: "str", Type: cty.String},
+			{Name: param, Type: cty.String},
+		},
+		Type: function.StaticReturnType(cty.Bool),
+		Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+			return cty.BoolVal(fn(args[0].AsString(), args[1].AsString())), nil
+		},
+	})
+}
+
+// sumFunc returns the sum of a list of numbers, or 0 for an empty list.
+var sumFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "list", Type: cty.List(cty.Number)},
+	},
+	Type: function.StaticReturnType(cty.Number),
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		sum := cty.Zero
+		for it := args[0].ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			if v.IsNull() {
+				return cty.NilVal, fmt.Errorf("sum of a list with null values")
+			}
+			sum = sum.Add(v)
+		}
+		return sum, nil
+	},
+})
+
+// cidrContainsFunc returns whether an IP address or a CIDR subnet is
+// contained in a CIDR prefix.
+var cidrContainsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{Name: "prefix", Type: cty.String},
+		{Name: "address", Type: cty.String},
+	},
+	Type: function.StaticReturnType(cty.Bool),
+	Impl: func(args []cty.Value, _ cty.Type) (cty.Value, error) {
+		prefix, err := netip.ParsePrefix(args[0].AsString())
+		if err != nil {
+			return cty.NilVal, function.NewArgError(0, err)
+		}
+
+		address := args[1].AsString()
+		if strings.Contains(address, "/") {
+			subnet, err := netip.ParsePrefix(address)
+			if err != nil {
+				return cty.NilVal, function.NewArgError(1, err)
+			}
+			return cty.BoolVal(subnet.Bits() >= prefix.Bits() &&
+				prefix.Contains(subnet.Masked().Addr())), nil
+		}
+
+		addr, err := netip.ParseAddr(address)
+		if err != nil {
+			return cty.NilVal, function.NewArgError(1, err)
+		}
+		return cty.BoolVal(prefix.Contains(addr)), nil
+	},
+})
@@ -78,6 +78,10 @@ type ParseConfig struct {
 
 	Strict bool
 
+	// StrictFields rejects unknown attributes and blocks in places where the
+	// parser would otherwise ignore them.
+	StrictFields bool
+
 	// parsedVarFiles represent parsed HCL AST of the passed EnvVars
 	parsedVarFiles []*hcl.File
 }
@@ -117,7 +121,13 @@ func decode(c *jobConfig) error {
 		return diags
 	}
 
-	diags = append(diags, c.decodeBody(file.Body)...)
+	body, moreDiags := c.resolveImports(file)
+	diags = append(diags, moreDiags...)
+	if diags.HasErrors() {
+		return diags
+	}
+
+	diags = append(diags, c.decodeBody(body)...)
 
 	if diags.HasErrors() {
 		var str strings.Builder
@@ -141,6 +151,98 @@ func decode(c *jobConfig) error {
 	return nil
 }
 
+// importedFileSchema is the schema of files included with import blocks. They
+// can share variables, locals, and functions, but can't define jobs.
+var importedFileSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: importLabel},
+		{Type: variablesLabel},
+		{Type: variableLabel, LabelNames: []string{"name"}},
+		{Type: localsLabel},
+		{Type: functionLabel, LabelNames: []string{"name"}},
+	},
+}
+
+// resolveImports parses the files included by the import blocks of the job
+// file, recursively, and returns the body of the job file merged with them.
+// Each file is only included once, so diamond or circular imports are
+// allowed. Imports require filesystem access, so they are resolved by the CLI
+// at submit time.
+func (c *jobConfig) resolveImports(file *hcl.File) (hcl.Body, hcl.Diagnostics) {
+	seen := map[string]bool{}
+	if c.ParseConfig.Path != "" {
+		if abs, err := filepath.Abs(c.ParseConfig.Path); err == nil {
+			seen[abs] = true
+		}
+	}
+
+	files := []*hcl.File{file}
+	diags := c.collectImports(file, c.ParseConfig.BaseDir, seen, &files)
+	if len(files) == 1 {
+		return file.Body, diags
+	}
+	return hcl.MergeFiles(files), diags
+}
+
+func (c *jobConfig) collectImports(file *hcl.File, baseDir string, seen map[string]bool, files *[]*hcl.File) hcl.Diagnostics {
+	content, _, diags := file.Body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: importLabel}},
+	})
+
+	for _, block := range content.Blocks {
+		if !c.ParseConfig.AllowFS {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Imports disabled",
+				Detail:   "Import blocks require filesystem access and can only be resolved when parsing a job file locally.",
+				Subject:  block.DefRange.Ptr(),
+			})
+			continue
+		}
+
+		var imp struct {
+			Source string `hcl:"source"`
+		}
+		moreDiags := hclDecoder.DecodeBody(block.Body, nil, &imp)
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			continue
+		}
+
+		path := imp.Source
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		imported, moreDiags := parseFile(path)
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		_, moreDiags = imported.Body.Content(importedFileSchema)
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			continue
+		}
+
+		*files = append(*files, imported)
+		if c.importedSources == nil {
+			c.importedSources = map[string][]byte{}
+		}
+		c.importedSources[path] = imported.Bytes
+
+		diags = append(diags, c.collectImports(imported, filepath.Dir(path), seen, files)...)
+	}
+	return diags
+}
+
 func parseFile(path string) (*hcl.File, hcl.Diagnostics) {
 	body, err := os.ReadFile(path)
 	if err != nil {
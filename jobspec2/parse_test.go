@@ -5,6 +5,7 @@ package jobspec2
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -1132,3 +1133,182 @@ func TestIdentity(t *testing.T) {
 	must.Eq(t, "sighup", altID.ChangeSignal)
 	must.Eq(t, 2*time.Hour, altID.TTL)
 }
+
+func TestParse_StrictFields(t *testing.T) {
+	ci.Parallel(t)
+
+	hcl := `
+job "example" {
+  group "group" {
+    vault {
+      polices = ["unknown"]
+    }
+
+    task "task" {
+      driver = "docker"
+    }
+  }
+}
+`
+
+	_, err := ParseWithConfig(&ParseConfig{
+		Path:    "input.hcl",
+		Body:    []byte(hcl),
+		AllowFS: false,
+	})
+	must.NoError(t, err)
+
+	_, err = ParseWithConfig(&ParseConfig{
+		Path:         "input.hcl",
+		Body:         []byte(hcl),
+		AllowFS:      false,
+		StrictFields: true,
+	})
+	must.ErrorContains(t, err, `An argument named "polices" is not expected here`)
+}
+
+func TestParse_Imports(t *testing.T) {
+	ci.Parallel(t)
+
+	path := "test-fixtures/imports/job.nomad.hcl"
+	body, err := os.ReadFile(path)
+	must.NoError(t, err)
+
+	out, err := ParseWithConfig(&ParseConfig{
+		Path:    path,
+		Body:    body,
+		ArgVars: []string{"nginx_version=1.26"},
+		AllowFS: true,
+	})
+	must.NoError(t, err)
+
+	must.Eq(t, []string{"dc1"}, out.Datacenters)
+	task := out.TaskGroups[0].Tasks[0]
+	must.Eq(t, "registry.example.com/nginx:1.26", task.Config["image"])
+	must.Eq(t, map[string]string{
+		"LOG_LEVEL": "info",
+		"PORT":      "8080",
+		"REGION":    "${node.region}",
+	}, task.Env)
+
+	// Imports are resolved by the CLI and can't be used without filesystem
+	// access.
+	_, err = ParseWithConfig(&ParseConfig{
+		Path:    path,
+		Body:    body,
+		AllowFS: false,
+	})
+	must.ErrorContains(t, err, "Imports disabled")
+
+	t.Run("imported files can't define jobs", func(t *testing.T) {
+		dir := t.TempDir()
+		must.NoError(t, os.WriteFile(filepath.Join(dir, "other.hcl"), []byte(`job "other" {}`), 0o644))
+
+		_, err := ParseWithConfig(&ParseConfig{
+			Path:    filepath.Join(dir, "job.hcl"),
+			Body:    []byte(`import { source = "other.hcl" }` + "\n" + `job "example" {}`),
+			AllowFS: true,
+		})
+		must.ErrorContains(t, err, `Blocks of type "job" are not expected here`)
+	})
+}
+
+func TestParse_UserFunctions(t *testing.T) {
+	ci.Parallel(t)
+
+	hcl := `
+variable "prefix" {
+  default = "svc"
+}
+
+function "service_name" {
+  params = [name]
+  result = lower("${var.prefix}-${name}")
+}
+
+function "tags" {
+  params         = [env]
+  variadic_param = extra
+  result         = concat(["env:${env}"], extra)
+}
+
+job "example" {
+  group "group" {
+    service {
+      name = service_name("API")
+      tags = tags("prod", "canary")
+    }
+
+    task "task" {
+      driver = "docker"
+    }
+  }
+}
+`
+
+	out, err := ParseWithConfig(&ParseConfig{
+		Path: "input.hcl",
+		Body: []byte(hcl),
+	})
+	must.NoError(t, err)
+
+	service := out.TaskGroups[0].Services[0]
+	must.Eq(t, "svc-api", service.Name)
+	must.Eq(t, []string{"env:prod", "canary"}, service.Tags)
+
+	_, err = ParseWithConfig(&ParseConfig{
+		Path: "input.hcl",
+		Body: []byte(`
+function "upper" {
+  params = [s]
+  result = s
+}
+
+job "example" {}
+`),
+	})
+	must.ErrorContains(t, err, `Function "upper" conflicts with a builtin function`)
+}
+
+func TestParse_ExtraFunctions(t *testing.T) {
+	ci.Parallel(t)
+
+	hcl := `
+job "example" {
+  meta {
+    starts   = startswith("nomad-client", "nomad")
+    ends     = endswith("nomad-client", "server")
+    contains = strcontains("nomad-client", "-cl")
+    sum      = sum([1, 2, 3.5])
+    replaced = regex_replace_all("a-b-c", "-", "_")
+    match    = regex("v([0-9]+)", "v42")[0]
+    matches  = length(regexall("[0-9]", "a1b2c3"))
+    in_cidr  = cidrcontains("10.0.0.0/8", "10.1.2.3")
+    in_net   = cidrcontains("10.0.0.0/8", "10.1.0.0/16")
+    out_net  = cidrcontains("10.0.0.0/16", "10.0.0.0/8")
+    yaml     = yamlencode({ a = 1 })
+    host     = cidrhost("10.0.0.0/24", 5)
+  }
+}
+`
+
+	out, err := ParseWithConfig(&ParseConfig{
+		Path: "input.hcl",
+		Body: []byte(hcl),
+	})
+	must.NoError(t, err)
+	must.Eq(t, map[string]string{
+		"starts":   "true",
+		"ends":     "false",
+		"contains": "true",
+		"sum":      "6.5",
+		"replaced": "a_b_c",
+		"match":    "42",
+		"matches":  "3",
+		"in_cidr":  "true",
+		"in_net":   "true",
+		"out_net":  "false",
+		"yaml":     "\"a\": 1\n",
+		"host":     "10.0.0.5",
+	}, out.Meta)
+}
@@ -0,0 +1,201 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobspec2
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/nomad/api"
+)
+
+// SchemaBlock describes the labels, attributes and nested blocks accepted by
+// a block of a job specification.
+type SchemaBlock struct {
+	Labels     []string                    `json:",omitempty"`
+	Attributes map[string]*SchemaAttribute `json:",omitempty"`
+	Blocks     map[string]*SchemaBlock     `json:",omitempty"`
+
+	// Repeated is set if the block may be defined more than once.
+	Repeated bool `json:",omitempty"`
+
+	// FreeForm is set if the block accepts arbitrary attributes, such as
+	// meta, env and the task driver config.
+	FreeForm bool `json:",omitempty"`
+}
+
+// SchemaAttribute describes an attribute of a job specification block.
+type SchemaAttribute struct {
+	Type     string
+	Required bool `json:",omitempty"`
+}
+
+// Schema returns the schema of a job specification file, as accepted by the
+// parser. It is intended for editor and IDE integrations.
+func Schema() *SchemaBlock {
+	job := reflectSchema(reflect.TypeOf(api.Job{}))
+	job.Labels = []string{"name"}
+
+	// Blocks decoded outside of the api structs, see decodeJob,
+	// decodeTaskGroup and decodeTask.
+	vault := reflectSchema(reflect.TypeOf(api.Vault{}))
+	job.Blocks[vaultLabel] = vault
+
+	task := job.Blocks["group"].Blocks[taskLabel]
+	job.Blocks[taskLabel] = task
+	job.Blocks["group"].Blocks[vaultLabel] = vault
+
+	scaling := reflectSchema(reflect.TypeOf(api.ScalingPolicy{}))
+	scaling.Labels = []string{"name"}
+	scaling.Repeated = true
+	task.Blocks["scaling"] = scaling
+
+	return &SchemaBlock{
+		Blocks: map[string]*SchemaBlock{
+			"job": job,
+			variableLabel: {
+				Labels: []string{"name"},
+				Attributes: map[string]*SchemaAttribute{
+					"description": {Type: "string"},
+					"default":     {Type: "any"},
+					"type":        {Type: "type"},
+				},
+				Blocks: map[string]*SchemaBlock{
+					"validation": {
+						Attributes: map[string]*SchemaAttribute{
+							"condition":     {Type: "bool", Required: true},
+							"error_message": {Type: "string", Required: true},
+						},
+						Repeated: true,
+					},
+				},
+				Repeated: true,
+			},
+			variablesLabel: {FreeForm: true, Repeated: true},
+			localsLabel:    {FreeForm: true, Repeated: true},
+		},
+	}
+}
+
+// specSchemas holds the schemas of the types decoded with a custom hcldec
+// spec rather than their hcl struct tags.
+var specSchemas = map[reflect.Type]hcldec.ObjectSpec{
+	reflect.TypeOf(api.Affinity{}):   affinitySpec,
+	reflect.TypeOf(api.Constraint{}): constraintSpec,
+}
+
+// reflectSchema builds the schema of a block from the hcl struct tags of t.
+func reflectSchema(t reflect.Type) *SchemaBlock {
+	b := &SchemaBlock{
+		Attributes: map[string]*SchemaAttribute{},
+		Blocks:     map[string]*SchemaBlock{},
+	}
+
+	if spec, ok := specSchemas[t]; ok {
+		for name, s := range spec {
+			attr := s.(*hcldec.AttrSpec)
+			b.Attributes[name] = &SchemaAttribute{
+				Type:     attr.Type.FriendlyName(),
+				Required: attr.Required,
+			}
+		}
+		return b
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("hcl")
+		if !ok || tag == "-" {
+			continue
+		}
+
+		name, kind, _ := strings.Cut(tag, ",")
+		switch kind {
+		case "label":
+			if name == "" {
+				name = "name"
+			}
+			b.Labels = append(b.Labels, name)
+		case "block":
+			b.Blocks[name] = reflectBlockSchema(field.Type)
+		case "remain":
+		default:
+			b.Attributes[name] = &SchemaAttribute{
+				Type:     schemaType(field.Type),
+				Required: kind == "",
+			}
+		}
+	}
+
+	return b
+}
+
+// reflectBlockSchema returns the schema of a block field of type t.
+func reflectBlockSchema(t reflect.Type) *SchemaBlock {
+	repeated := false
+	if t.Kind() == reflect.Slice {
+		repeated = true
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var b *SchemaBlock
+	switch t.Kind() {
+	case reflect.Map:
+		elem := t.Elem()
+		if elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			b = &SchemaBlock{FreeForm: true}
+			break
+		}
+
+		// Maps of structs are decoded from blocks labeled with the map key.
+		b = reflectSchema(elem)
+		if len(b.Labels) == 0 {
+			b.Labels = []string{"name"}
+		}
+		repeated = true
+	case reflect.Struct:
+		b = reflectSchema(t)
+	default:
+		panic(fmt.Sprintf("unsupported block type %s", t))
+	}
+
+	b.Repeated = repeated
+	return b
+}
+
+// schemaType returns the HCL type name of an attribute field of type t.
+func schemaType(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return "duration"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice:
+		return fmt.Sprintf("list(%s)", schemaType(t.Elem()))
+	case reflect.Map:
+		return fmt.Sprintf("map(%s)", schemaType(t.Elem()))
+	default:
+		return "any"
+	}
+}
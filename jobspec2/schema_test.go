@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jobspec2
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestSchema(t *testing.T) {
+	ci.Parallel(t)
+
+	schema := Schema()
+	must.MapContainsKeys(t, schema.Blocks, []string{"job", "variable", "variables", "locals"})
+
+	job := schema.Blocks["job"]
+	must.Eq(t, []string{"name"}, job.Labels)
+	must.Eq(t, &SchemaAttribute{Type: "list(string)"}, job.Attributes["datacenters"])
+	must.Eq(t, &SchemaAttribute{Type: "number"}, job.Attributes["priority"])
+
+	group := job.Blocks["group"]
+	must.True(t, group.Repeated)
+	must.Eq(t, []string{"name"}, group.Labels)
+	must.NotNil(t, group.Blocks["vault"])
+
+	task := group.Blocks["task"]
+	must.Eq(t, task, job.Blocks["task"])
+	must.Eq(t, &SchemaAttribute{Type: "duration"}, task.Attributes["kill_timeout"])
+	must.True(t, task.Blocks["config"].FreeForm)
+	must.Eq(t, []string{"name"}, task.Blocks["scaling"].Labels)
+
+	constraint := job.Blocks["constraint"]
+	must.True(t, constraint.Repeated)
+	must.Eq(t, &SchemaAttribute{Type: "bool"}, constraint.Attributes["distinct_hosts"])
+
+	volume := group.Blocks["volume"]
+	must.True(t, volume.Repeated)
+	must.Eq(t, []string{"name"}, volume.Labels)
+}
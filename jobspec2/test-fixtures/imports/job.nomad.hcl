@@ -0,0 +1,18 @@
+import {
+  source = "shared/common.hcl"
+}
+
+job "imports" {
+  datacenters = [local.datacenter]
+
+  group "web" {
+    task "web" {
+      driver = "docker"
+      env    = merge(local.common_env, { PORT = "8080" })
+
+      config {
+        image = image_ref("nginx", var.nginx_version)
+      }
+    }
+  }
+}
@@ -0,0 +1,15 @@
+import {
+  source = "registry.hcl"
+}
+
+variable "nginx_version" {
+  default = "1.27"
+}
+
+locals {
+  datacenter = "dc1"
+  common_env = {
+    LOG_LEVEL = "info"
+    REGION    = "${node.region}"
+  }
+}
@@ -0,0 +1,13 @@
+# Imports are included once, so cycles are allowed
+import {
+  source = "common.hcl"
+}
+
+locals {
+  registry = "registry.example.com"
+}
+
+function "image_ref" {
+  params = [name, tag]
+  result = "${local.registry}/${name}:${tag}"
+}
@@ -9,9 +9,11 @@ import (
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/ext/dynblock"
+	"github.com/hashicorp/hcl/v2/ext/userfunc"
 	"github.com/hashicorp/nomad/api"
 	"github.com/hashicorp/nomad/jobspec2/hclutil"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
 )
 
 const (
@@ -20,6 +22,8 @@ const (
 	localsLabel    = "locals"
 	vaultLabel     = "vault"
 	taskLabel      = "task"
+	importLabel    = "import"
+	functionLabel  = "function"
 
 	inputVariablesAccessor = "var"
 	localsAccessor         = "local"
@@ -38,6 +42,13 @@ type jobConfig struct {
 	LocalVariables Variables
 
 	LocalBlocks []*LocalBlock
+
+	// UserFunctions are the functions defined by function blocks.
+	UserFunctions map[string]function.Function
+
+	// importedSources is the content of the imported files by their path,
+	// used to render undefined variables of imported expressions.
+	importedSources map[string][]byte
 }
 
 func newJobConfig(parseConfig *ParseConfig) *jobConfig {
@@ -54,11 +65,17 @@ var jobConfigSchema = &hcl.BodySchema{
 		{Type: variablesLabel},
 		{Type: variableLabel, LabelNames: []string{"name"}},
 		{Type: localsLabel},
+		{Type: importLabel},
 		{Type: "job", LabelNames: []string{"name"}},
 	},
 }
 
 func (c *jobConfig) decodeBody(body hcl.Body) hcl.Diagnostics {
+	body, diags := c.decodeUserFunctions(body)
+	if diags.HasErrors() {
+		return diags
+	}
+
 	content, diags := body.Content(jobConfigSchema)
 	if len(diags) != 0 {
 		return diags
@@ -87,6 +104,30 @@ func (c *jobConfig) decodeBody(body hcl.Body) hcl.Diagnostics {
 	return diags
 }
 
+// decodeUserFunctions decodes the function blocks of the body and returns the
+// remaining body. User functions are evaluated in the context of the job, so
+// they can reference variables, locals, and other functions, but can't
+// replace the builtin functions.
+func (c *jobConfig) decodeUserFunctions(body hcl.Body) (hcl.Body, hcl.Diagnostics) {
+	funcs, remain, diags := userfunc.DecodeUserFunctions(body, functionLabel, c.EvalContext)
+	if diags.HasErrors() {
+		return remain, diags
+	}
+
+	builtins := Functions(c.ParseConfig.BaseDir, c.ParseConfig.AllowFS)
+	for name := range funcs {
+		if _, ok := builtins[name]; ok {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid function name",
+				Detail:   fmt.Sprintf("Function %q conflicts with a builtin function.", name),
+			})
+		}
+	}
+	c.UserFunctions = funcs
+	return remain, diags
+}
+
 // decodeInputVariables looks in the found blocks for 'variables' and
 // 'variable' blocks. It should be called firsthand so that other blocks can
 // use the variables.
@@ -285,6 +326,10 @@ func (c *jobConfig) decodeJob(content *hcl.BodyContent, ctx *hcl.EvalContext) hc
 		diags = append(diags, c.decodeTopLevelExtras(extra, ctx)...)
 		diags = append(diags, hclDecoder.DecodeBody(remain, ctx, c.Job)...)
 
+		if c.ParseConfig.StrictFields {
+			diags = append(diags, validateGroupVaults(remain, ctx)...)
+		}
+
 		if metaAttr != nil {
 			c.Job.Meta = metaAttr
 		}
@@ -302,17 +347,45 @@ func (c *jobConfig) decodeJob(content *hcl.BodyContent, ctx *hcl.EvalContext) hc
 
 }
 
+// validateGroupVaults decodes the vault blocks of the groups in the job body
+// and returns their diagnostics. decodeTaskGroup drops these diagnostics, so
+// unknown attributes in group vault blocks are otherwise ignored.
+func validateGroupVaults(body hcl.Body, ctx *hcl.EvalContext) hcl.Diagnostics {
+	content, _, diags := body.PartialContent(&hcl.BodySchema{
+		Blocks: []hcl.BlockHeaderSchema{{Type: "group", LabelNames: []string{"name"}}},
+	})
+
+	for _, group := range content.Blocks {
+		gc, _, moreDiags := group.Body.PartialContent(&hcl.BodySchema{
+			Blocks: []hcl.BlockHeaderSchema{{Type: vaultLabel}},
+		})
+		diags = append(diags, moreDiags...)
+
+		for _, b := range gc.Blocks {
+			diags = append(diags, hclDecoder.DecodeBody(b.Body, ctx, &api.Vault{})...)
+		}
+	}
+	return diags
+}
+
 func (c *jobConfig) EvalContext() *hcl.EvalContext {
 	vars, _ := c.InputVariables.Values()
 	locals, _ := c.LocalVariables.Values()
+	funcs := Functions(c.ParseConfig.BaseDir, c.ParseConfig.AllowFS)
+	for name, fn := range c.UserFunctions {
+		funcs[name] = fn
+	}
 	return &hcl.EvalContext{
-		Functions: Functions(c.ParseConfig.BaseDir, c.ParseConfig.AllowFS),
+		Functions: funcs,
 		Variables: map[string]cty.Value{
 			inputVariablesAccessor: cty.ObjectVal(vars),
 			localsAccessor:         cty.ObjectVal(locals),
 		},
 		UndefinedVariable: func(t hcl.Traversal) (cty.Value, hcl.Diagnostics) {
 			body := c.ParseConfig.Body
+			if src, ok := c.importedSources[t.SourceRange().Filename]; ok {
+				body = src
+			}
 			start := t.SourceRange().Start.Byte
 			end := t.SourceRange().End.Byte
 
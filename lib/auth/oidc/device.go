@@ -0,0 +1,219 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package oidc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// deviceCodeGrantType is the grant type used to exchange a device code
+	// for tokens, as defined in RFC 8628.
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// maxResponseSize is the maximum size of the responses read from the
+	// OIDC provider.
+	maxResponseSize = 1 << 20
+)
+
+var (
+	// ErrAuthorizationPending is returned by DeviceFlow.Token when the user
+	// hasn't authorized the device yet.
+	ErrAuthorizationPending = errors.New("authorization pending")
+
+	// ErrSlowDown is returned by DeviceFlow.Token when the provider asks for
+	// the polling interval to be increased.
+	ErrSlowDown = errors.New("slow down")
+)
+
+// DeviceAuthorization is the response of the device authorization endpoint
+// of an OIDC provider, as defined in RFC 8628.
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// DeviceToken is the set of tokens issued by the OIDC provider once the user
+// has authorized the device.
+type DeviceToken struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+}
+
+// DeviceFlow implements the OAuth 2.0 device authorization grant against the
+// OIDC provider of an auth method. The cap library doesn't support this
+// grant, so the requests are made directly to the endpoints advertised by the
+// provider discovery document.
+type DeviceFlow struct {
+	client       *http.Client
+	clientID     string
+	clientSecret string
+	scopes       []string
+
+	deviceAuthURL string
+	tokenURL      string
+}
+
+// NewDeviceFlow discovers the endpoints of the OIDC provider of the auth
+// method and returns a DeviceFlow using them. It returns an error if the
+// provider doesn't support the device authorization grant.
+func NewDeviceFlow(ctx context.Context, authMethod *structs.ACLAuthMethod) (*DeviceFlow, error) {
+	client, err := deviceHTTPClient(authMethod.Config.DiscoveryCaPem)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &DeviceFlow{
+		client:       client,
+		clientID:     authMethod.Config.OIDCClientID,
+		clientSecret: authMethod.Config.OIDCClientSecret,
+		scopes:       append([]string{"openid"}, authMethod.Config.OIDCScopes...),
+	}
+
+	discoveryURL := strings.TrimSuffix(authMethod.Config.OIDCDiscoveryURL, "/") +
+		"/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var discovery struct {
+		DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+		TokenEndpoint               string `json:"token_endpoint"`
+	}
+	if err := d.do(req, &discovery); err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC provider: %w", err)
+	}
+	if discovery.DeviceAuthorizationEndpoint == "" || discovery.TokenEndpoint == "" {
+		return nil, errors.New("OIDC provider does not support the device authorization grant")
+	}
+
+	d.deviceAuthURL = discovery.DeviceAuthorizationEndpoint
+	d.tokenURL = discovery.TokenEndpoint
+	return d, nil
+}
+
+// Authorize requests a device and user code from the provider.
+func (d *DeviceFlow) Authorize(ctx context.Context) (*DeviceAuthorization, error) {
+	form := url.Values{"scope": {strings.Join(d.scopes, " ")}}
+
+	var auth DeviceAuthorization
+	if err := d.post(ctx, d.deviceAuthURL, form, &auth); err != nil {
+		return nil, fmt.Errorf("failed to request device authorization: %w", err)
+	}
+	if auth.DeviceCode == "" || auth.UserCode == "" || auth.VerificationURI == "" {
+		return nil, errors.New("invalid device authorization response from OIDC provider")
+	}
+	return &auth, nil
+}
+
+// Token exchanges the device code for tokens. It returns
+// ErrAuthorizationPending or ErrSlowDown if the user hasn't authorized the
+// device yet, in which case the caller should try again later.
+func (d *DeviceFlow) Token(ctx context.Context, deviceCode string) (*DeviceToken, error) {
+	form := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {deviceCode},
+	}
+
+	var token DeviceToken
+	if err := d.post(ctx, d.tokenURL, form, &token); err != nil {
+		return nil, err
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("OIDC provider did not return an ID token")
+	}
+	return &token, nil
+}
+
+// post sends the form to the provider, authenticating with the client
+// credentials of the auth method.
+func (d *DeviceFlow) post(ctx context.Context, endpoint string, form url.Values, out any) error {
+	form.Set("client_id", d.clientID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if d.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(d.clientID), url.QueryEscape(d.clientSecret))
+	}
+	return d.do(req, out)
+}
+
+// do sends the request and decodes the JSON response. OAuth errors returned
+// by the provider are converted to errors, with the pending states of the
+// device flow returned as ErrAuthorizationPending and ErrSlowDown.
+func (d *DeviceFlow) do(req *http.Request, out any) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var oauthErr struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		if json.Unmarshal(body, &oauthErr) != nil || oauthErr.Error == "" {
+			return fmt.Errorf("unexpected response code %d", resp.StatusCode)
+		}
+
+		switch oauthErr.Error {
+		case "authorization_pending":
+			return ErrAuthorizationPending
+		case "slow_down":
+			return ErrSlowDown
+		}
+		if oauthErr.Description != "" {
+			return fmt.Errorf("%s: %s", oauthErr.Error, oauthErr.Description)
+		}
+		return errors.New(oauthErr.Error)
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+// deviceHTTPClient returns the HTTP client used to talk to the provider,
+// trusting the discovery CA certificates of the auth method if set.
+func deviceHTTPClient(caPEMs []string) (*http.Client, error) {
+	client := cleanhttp.DefaultClient()
+	if len(caPEMs) == 0 {
+		return client, nil
+	}
+
+	pool := x509.NewCertPool()
+	for _, pem := range caPEMs {
+		if !pool.AppendCertsFromPEM([]byte(pem)) {
+			return nil, errors.New("failed to parse OIDC discovery CA certificate")
+		}
+	}
+	client.Transport.(*http.Transport).TLSClientConfig = &tls.Config{RootCAs: pool}
+	return client, nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// testDeviceProvider starts an OIDC provider supporting the device
+// authorization grant. The token endpoint returns the given OAuth errors
+// before issuing the tokens.
+func testDeviceProvider(t *testing.T, tokenErrs ...string) *httptest.Server {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	writeJSON := func(w http.ResponseWriter, code int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(v)
+	}
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                        srv.URL,
+			"device_authorization_endpoint": srv.URL + "/device",
+			"token_endpoint":                srv.URL + "/token",
+		})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		if id, secret, _ := r.BasicAuth(); id != "nomad" || secret != "secret" {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+			return
+		}
+		must.Eq(t, "openid groups", r.FormValue("scope"))
+		writeJSON(w, http.StatusOK, map[string]any{
+			"device_code":      "device-code",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": srv.URL + "/activate",
+			"expires_in":       600,
+			"interval":         1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		must.Eq(t, deviceCodeGrantType, r.FormValue("grant_type"))
+		if r.FormValue("device_code") != "device-code" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{
+				"error":             "invalid_grant",
+				"error_description": "unknown device code",
+			})
+			return
+		}
+		if len(tokenErrs) > 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": tokenErrs[0]})
+			tokenErrs = tokenErrs[1:]
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{
+			"access_token": "access-token",
+			"id_token":     "id-token",
+			"token_type":   "Bearer",
+		})
+	})
+
+	return srv
+}
+
+func TestDeviceFlow(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := testDeviceProvider(t, "authorization_pending", "slow_down")
+
+	authMethod := &structs.ACLAuthMethod{
+		Name: "device",
+		Type: structs.ACLAuthMethodTypeOIDC,
+		Config: &structs.ACLAuthMethodConfig{
+			OIDCDiscoveryURL: srv.URL,
+			OIDCClientID:     "nomad",
+			OIDCClientSecret: "secret",
+			OIDCScopes:       []string{"groups"},
+		},
+	}
+
+	ctx := context.Background()
+	deviceFlow, err := NewDeviceFlow(ctx, authMethod)
+	must.NoError(t, err)
+
+	deviceAuth, err := deviceFlow.Authorize(ctx)
+	must.NoError(t, err)
+	must.Eq(t, "device-code", deviceAuth.DeviceCode)
+	must.Eq(t, "ABCD-EFGH", deviceAuth.UserCode)
+	must.Eq(t, srv.URL+"/activate", deviceAuth.VerificationURI)
+	must.Eq(t, 600, deviceAuth.ExpiresIn)
+
+	_, err = deviceFlow.Token(ctx, deviceAuth.DeviceCode)
+	must.ErrorIs(t, err, ErrAuthorizationPending)
+	_, err = deviceFlow.Token(ctx, deviceAuth.DeviceCode)
+	must.ErrorIs(t, err, ErrSlowDown)
+
+	token, err := deviceFlow.Token(ctx, deviceAuth.DeviceCode)
+	must.NoError(t, err)
+	must.Eq(t, "id-token", token.IDToken)
+	must.Eq(t, "access-token", token.AccessToken)
+
+	_, err = deviceFlow.Token(ctx, "unknown")
+	must.EqError(t, err, "invalid_grant: unknown device code")
+
+	// Providers without a device authorization endpoint are rejected.
+	authMethod.Config.OIDCDiscoveryURL = srv.URL + "/missing"
+	_, err = NewDeviceFlow(ctx, authMethod)
+	must.ErrorContains(t, err, "failed to discover OIDC provider")
+}
@@ -0,0 +1,295 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package cloudwatch implements a log sink plugin that ships the logs of
+// tasks to Amazon CloudWatch Logs.
+package cloudwatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper/pluginutils/loader"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/logsink"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+)
+
+const (
+	// pluginName is the name of the plugin
+	pluginName = "cloudwatch"
+
+	// logGroupOption and logStreamOption are the task options that set the
+	// log group and log stream of its log lines
+	logGroupOption  = "log_group"
+	logStreamOption = "log_stream"
+
+	// maxEventSize is the maximum size of a log event, including the 26 bytes
+	// of overhead CloudWatch counts for each event
+	maxEventSize  = 256 * 1024
+	eventOverhead = 26
+
+	// maxBatchSize and maxBatchEvents are the limits of a PutLogEvents call
+	maxBatchSize   = 1024 * 1024
+	maxBatchEvents = 10000
+)
+
+var (
+	// PluginID is the cloudwatch plugin metadata registered in the plugin
+	// catalog.
+	PluginID = loader.PluginID{
+		Name:       pluginName,
+		PluginType: base.PluginTypeLogSink,
+	}
+
+	// PluginConfig is the cloudwatch factory function registered in the
+	// plugin catalog.
+	PluginConfig = &loader.InternalPluginConfig{
+		Factory: func(_ context.Context, l hclog.Logger) interface{} { return NewCloudWatchSink(l) },
+	}
+
+	// pluginInfo is the response returned for the PluginInfo RPC
+	pluginInfo = &base.PluginInfoResponse{
+		Type:              base.PluginTypeLogSink,
+		PluginApiVersions: []string{logsink.ApiVersion010},
+		PluginVersion:     "0.1.0",
+		Name:              pluginName,
+	}
+
+	// configSpec is the hcl specification returned by the ConfigSchema RPC
+	configSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"log_group": hclspec.NewAttr("log_group", "string", false),
+		"create_log_group": hclspec.NewDefault(
+			hclspec.NewAttr("create_log_group", "bool", false),
+			hclspec.NewLiteral("false"),
+		),
+		"region":        hclspec.NewAttr("region", "string", false),
+		"endpoint":      hclspec.NewAttr("endpoint", "string", false),
+		"access_key":    hclspec.NewAttr("access_key", "string", false),
+		"secret_key":    hclspec.NewAttr("secret_key", "string", false),
+		"session_token": hclspec.NewAttr("session_token", "string", false),
+	})
+)
+
+// Config is the client configuration of the cloudwatch log sink.
+type Config struct {
+	// LogGroup is the log group that log lines are written to, unless the
+	// task sets the "log_group" option.
+	LogGroup string `codec:"log_group"`
+
+	// CreateLogGroup creates missing log groups, in addition to the log
+	// streams that are always created.
+	CreateLogGroup bool `codec:"create_log_group"`
+
+	// Region and Endpoint override the region and endpoint of CloudWatch
+	// Logs found in the environment.
+	Region   string `codec:"region"`
+	Endpoint string `codec:"endpoint"`
+
+	// AccessKey, SecretKey and SessionToken are static credentials used
+	// instead of the credentials found in the environment.
+	AccessKey    string `codec:"access_key"`
+	SecretKey    string `codec:"secret_key"`
+	SessionToken string `codec:"session_token"`
+}
+
+// CloudWatchSink is a log sink plugin that puts log lines into CloudWatch
+// Logs, with a log stream for each task.
+type CloudWatchSink struct {
+	logger hclog.Logger
+
+	config *Config
+	client cloudwatchlogsiface.CloudWatchLogsAPI
+	lock   sync.RWMutex
+}
+
+// NewCloudWatchSink returns a new cloudwatch log sink.
+func NewCloudWatchSink(logger hclog.Logger) logsink.LogSinkPlugin {
+	return &CloudWatchSink{
+		logger: logger.Named(pluginName),
+		config: new(Config),
+	}
+}
+
+func (s *CloudWatchSink) PluginInfo() (*base.PluginInfoResponse, error) {
+	return pluginInfo, nil
+}
+
+func (s *CloudWatchSink) ConfigSchema() (*hclspec.Spec, error) {
+	return configSpec, nil
+}
+
+func (s *CloudWatchSink) SetConfig(cfg *base.Config) error {
+	var config Config
+	if len(cfg.PluginConfig) != 0 {
+		if err := base.MsgPackDecode(cfg.PluginConfig, &config); err != nil {
+			return err
+		}
+	}
+
+	awsConfig := aws.NewConfig()
+	if config.Region != "" {
+		awsConfig = awsConfig.WithRegion(config.Region)
+	}
+	if config.Endpoint != "" {
+		awsConfig = awsConfig.WithEndpoint(config.Endpoint)
+	}
+	if config.AccessKey != "" {
+		awsConfig = awsConfig.WithCredentials(credentials.NewStaticCredentials(
+			config.AccessKey, config.SecretKey, config.SessionToken))
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsConfig,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create AWS session: %w", err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.config = &config
+	s.client = cloudwatchlogs.New(sess)
+	return nil
+}
+
+// ShipLogs puts the batch of log lines into the task's log stream, creating
+// the log stream the first time it's missing.
+func (s *CloudWatchSink) ShipLogs(ctx context.Context, batch *logsink.LogBatch) error {
+	s.lock.RLock()
+	config, client := s.config, s.client
+	s.lock.RUnlock()
+
+	if client == nil {
+		return fmt.Errorf("cloudwatch log sink is not configured")
+	}
+
+	group := config.LogGroup
+	if v := batch.Options[logGroupOption]; v != "" {
+		group = v
+	}
+	if group == "" {
+		return fmt.Errorf("cloudwatch log group is not configured")
+	}
+	stream := logStreamName(batch)
+
+	for _, events := range logEvents(batch.Entries) {
+		input := &cloudwatchlogs.PutLogEventsInput{
+			LogGroupName:  aws.String(group),
+			LogStreamName: aws.String(stream),
+			LogEvents:     events,
+		}
+		_, err := client.PutLogEventsWithContext(ctx, input)
+		if isAWSError(err, cloudwatchlogs.ErrCodeResourceNotFoundException) {
+			if err := s.createLogStream(ctx, client, config, group, stream); err != nil {
+				return err
+			}
+			_, err = client.PutLogEventsWithContext(ctx, input)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to put log events to cloudwatch: %w", err)
+		}
+	}
+	return nil
+}
+
+// createLogStream creates the log stream, and its log group if allowed by the
+// configuration.
+func (s *CloudWatchSink) createLogStream(ctx context.Context, client cloudwatchlogsiface.CloudWatchLogsAPI,
+	config *Config, group, stream string) error {
+
+	createStream := func() error {
+		_, err := client.CreateLogStreamWithContext(ctx, &cloudwatchlogs.CreateLogStreamInput{
+			LogGroupName:  aws.String(group),
+			LogStreamName: aws.String(stream),
+		})
+		if isAWSError(err, cloudwatchlogs.ErrCodeResourceAlreadyExistsException) {
+			return nil
+		}
+		return err
+	}
+
+	err := createStream()
+	if isAWSError(err, cloudwatchlogs.ErrCodeResourceNotFoundException) && config.CreateLogGroup {
+		s.logger.Debug("creating log group", "log_group", group)
+		_, err = client.CreateLogGroupWithContext(ctx, &cloudwatchlogs.CreateLogGroupInput{
+			LogGroupName: aws.String(group),
+		})
+		if err == nil || isAWSError(err, cloudwatchlogs.ErrCodeResourceAlreadyExistsException) {
+			err = createStream()
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create cloudwatch log stream %q in log group %q: %w", stream, group, err)
+	}
+	return nil
+}
+
+// logStreamName returns the name of the task's log stream, either set by the
+// task's options or named after the task and its allocation.
+func logStreamName(batch *logsink.LogBatch) string {
+	if v := batch.Options[logStreamOption]; v != "" {
+		return v
+	}
+	t := batch.Task
+	if t == nil {
+		return "nomad"
+	}
+	return strings.Join([]string{t.Namespace, t.JobID, t.TaskName, t.AllocID}, "/")
+}
+
+// logEvents converts log lines to log events ordered by time, split into
+// batches within the limits of PutLogEvents. Empty lines are skipped as
+// CloudWatch rejects empty events, and lines too large for an event are
+// truncated.
+func logEvents(entries []*logsink.LogEntry) [][]*cloudwatchlogs.InputLogEvent {
+	events := make([]*cloudwatchlogs.InputLogEvent, 0, len(entries))
+	for _, entry := range entries {
+		line := entry.Line
+		if len(line) == 0 {
+			continue
+		}
+		if len(line) > maxEventSize-eventOverhead {
+			line = line[:maxEventSize-eventOverhead]
+		}
+		events = append(events, &cloudwatchlogs.InputLogEvent{
+			Timestamp: aws.Int64(entry.Timestamp.UnixMilli()),
+			Message:   aws.String(string(line)),
+		})
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return *events[i].Timestamp < *events[j].Timestamp
+	})
+
+	var batches [][]*cloudwatchlogs.InputLogEvent
+	var size int
+	start := 0
+	for i, event := range events {
+		eventSize := len(*event.Message) + eventOverhead
+		if i > start && (size+eventSize > maxBatchSize || i-start == maxBatchEvents) {
+			batches = append(batches, events[start:i])
+			start, size = i, 0
+		}
+		size += eventSize
+	}
+	if start < len(events) {
+		batches = append(batches, events[start:])
+	}
+	return batches
+}
+
+func isAWSError(err error, code string) bool {
+	var aerr awserr.Error
+	return errors.As(err, &aerr) && aerr.Code() == code
+}
@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package cloudwatch
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/plugins/logsink"
+	"github.com/shoenig/test/must"
+)
+
+// mockLogsClient is a CloudWatch Logs client with the given log groups and
+// streams, keyed by group.
+type mockLogsClient struct {
+	cloudwatchlogsiface.CloudWatchLogsAPI
+
+	streams map[string]map[string][]*cloudwatchlogs.InputLogEvent
+}
+
+func (m *mockLogsClient) PutLogEventsWithContext(_ aws.Context, in *cloudwatchlogs.PutLogEventsInput, _ ...request.Option) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	streams, ok := m.streams[*in.LogGroupName]
+	if !ok {
+		return nil, awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "log group does not exist", nil)
+	}
+	if _, ok := streams[*in.LogStreamName]; !ok {
+		return nil, awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "log stream does not exist", nil)
+	}
+	streams[*in.LogStreamName] = append(streams[*in.LogStreamName], in.LogEvents...)
+	return &cloudwatchlogs.PutLogEventsOutput{}, nil
+}
+
+func (m *mockLogsClient) CreateLogStreamWithContext(_ aws.Context, in *cloudwatchlogs.CreateLogStreamInput, _ ...request.Option) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	streams, ok := m.streams[*in.LogGroupName]
+	if !ok {
+		return nil, awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "log group does not exist", nil)
+	}
+	streams[*in.LogStreamName] = nil
+	return &cloudwatchlogs.CreateLogStreamOutput{}, nil
+}
+
+func (m *mockLogsClient) CreateLogGroupWithContext(_ aws.Context, in *cloudwatchlogs.CreateLogGroupInput, _ ...request.Option) (*cloudwatchlogs.CreateLogGroupOutput, error) {
+	m.streams[*in.LogGroupName] = map[string][]*cloudwatchlogs.InputLogEvent{}
+	return &cloudwatchlogs.CreateLogGroupOutput{}, nil
+}
+
+func TestCloudWatchSink_ShipLogs(t *testing.T) {
+	ci.Parallel(t)
+
+	client := &mockLogsClient{streams: map[string]map[string][]*cloudwatchlogs.InputLogEvent{
+		"nomad": {},
+	}}
+	sink := &CloudWatchSink{
+		logger: testlog.HCLogger(t),
+		config: &Config{LogGroup: "nomad"},
+		client: client,
+	}
+
+	now := time.UnixMilli(1700000000000)
+	batch := &logsink.LogBatch{
+		Task: &logsink.TaskInfo{
+			Namespace: "default",
+			JobID:     "example",
+			AllocID:   "a8198d79-cfdb-6593-a999-1e9adabcba2e",
+			TaskName:  "redis",
+		},
+		Entries: []*logsink.LogEntry{
+			{Timestamp: now.Add(time.Second), Stream: logsink.StreamStdout, Line: []byte("accepted")},
+			{Timestamp: now, Stream: logsink.StreamStderr, Line: []byte("ready")},
+			{Timestamp: now, Stream: logsink.StreamStdout, Line: []byte{}},
+		},
+	}
+
+	// The task's log stream is created on the first batch
+	must.NoError(t, sink.ShipLogs(context.Background(), batch))
+	events := client.streams["nomad"]["default/example/redis/a8198d79-cfdb-6593-a999-1e9adabcba2e"]
+	must.Len(t, 2, events)
+	must.Eq(t, "ready", *events[0].Message)
+	must.Eq(t, 1700000000000, *events[0].Timestamp)
+	must.Eq(t, "accepted", *events[1].Message)
+
+	// Missing log groups are only created if allowed
+	batch.Options = map[string]string{"log_group": "redis", "log_stream": "cache"}
+	must.ErrorContains(t, sink.ShipLogs(context.Background(), batch), `failed to create cloudwatch log stream "cache" in log group "redis"`)
+
+	sink.config.CreateLogGroup = true
+	must.NoError(t, sink.ShipLogs(context.Background(), batch))
+	must.Len(t, 2, client.streams["redis"]["cache"])
+}
+
+func TestCloudWatchSink_logEvents(t *testing.T) {
+	ci.Parallel(t)
+
+	line := []byte(strings.Repeat("a", 300*1024))
+	entries := make([]*logsink.LogEntry, 5)
+	for i := range entries {
+		entries[i] = &logsink.LogEntry{Timestamp: time.Now(), Line: line}
+	}
+
+	// Lines are truncated to the maximum event size, and batches hold at most
+	// 1MB of events
+	batches := logEvents(entries)
+	must.Len(t, 2, batches)
+	must.Len(t, 4, batches[0])
+	must.Len(t, 1, batches[1])
+	must.Eq(t, maxEventSize-eventOverhead, len(*batches[1][0].Message))
+}
@@ -0,0 +1,279 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package elasticsearch implements a log sink plugin that ships the logs of
+// tasks to Elasticsearch.
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper/pluginutils/loader"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/logsink"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+)
+
+const (
+	// pluginName is the name of the plugin
+	pluginName = "elasticsearch"
+
+	// defaultIndex is the index that log lines are written to if neither the
+	// client nor the task set one
+	defaultIndex = "nomad-logs"
+
+	// indexOption is the task option that sets the index of its log lines
+	indexOption = "index"
+
+	// requestTimeout is how long indexing a batch of log lines may take
+	requestTimeout = 30 * time.Second
+)
+
+var (
+	// PluginID is the elasticsearch plugin metadata registered in the plugin
+	// catalog.
+	PluginID = loader.PluginID{
+		Name:       pluginName,
+		PluginType: base.PluginTypeLogSink,
+	}
+
+	// PluginConfig is the elasticsearch factory function registered in the
+	// plugin catalog.
+	PluginConfig = &loader.InternalPluginConfig{
+		Factory: func(_ context.Context, l hclog.Logger) interface{} { return NewElasticsearchSink(l) },
+	}
+
+	// pluginInfo is the response returned for the PluginInfo RPC
+	pluginInfo = &base.PluginInfoResponse{
+		Type:              base.PluginTypeLogSink,
+		PluginApiVersions: []string{logsink.ApiVersion010},
+		PluginVersion:     "0.1.0",
+		Name:              pluginName,
+	}
+
+	// configSpec is the hcl specification returned by the ConfigSchema RPC
+	configSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"address": hclspec.NewAttr("address", "string", false),
+		"index": hclspec.NewDefault(
+			hclspec.NewAttr("index", "string", false),
+			hclspec.NewLiteral(`"`+defaultIndex+`"`),
+		),
+		"username": hclspec.NewAttr("username", "string", false),
+		"password": hclspec.NewAttr("password", "string", false),
+		"api_key":  hclspec.NewAttr("api_key", "string", false),
+	})
+)
+
+// Config is the client configuration of the elasticsearch log sink.
+type Config struct {
+	// Address is the base URL of Elasticsearch, such as
+	// "https://elasticsearch:9200".
+	Address string `codec:"address"`
+
+	// Index is the index or data stream that log lines are written to,
+	// unless the task sets the "index" option.
+	Index string `codec:"index"`
+
+	// Username and Password are used for basic authentication.
+	Username string `codec:"username"`
+	Password string `codec:"password"`
+
+	// APIKey is the encoded API key used to authenticate, instead of the
+	// username and password.
+	APIKey string `codec:"api_key"`
+}
+
+// ElasticsearchSink is a log sink plugin that indexes log lines with the
+// Elasticsearch bulk API.
+type ElasticsearchSink struct {
+	logger hclog.Logger
+	client *http.Client
+
+	config     *Config
+	configLock sync.RWMutex
+}
+
+// NewElasticsearchSink returns a new elasticsearch log sink.
+func NewElasticsearchSink(logger hclog.Logger) logsink.LogSinkPlugin {
+	return &ElasticsearchSink{
+		logger: logger.Named(pluginName),
+		client: cleanhttp.DefaultPooledClient(),
+		config: &Config{Index: defaultIndex},
+	}
+}
+
+func (s *ElasticsearchSink) PluginInfo() (*base.PluginInfoResponse, error) {
+	return pluginInfo, nil
+}
+
+func (s *ElasticsearchSink) ConfigSchema() (*hclspec.Spec, error) {
+	return configSpec, nil
+}
+
+func (s *ElasticsearchSink) SetConfig(cfg *base.Config) error {
+	var config Config
+	if len(cfg.PluginConfig) != 0 {
+		if err := base.MsgPackDecode(cfg.PluginConfig, &config); err != nil {
+			return err
+		}
+	}
+	if config.Index == "" {
+		config.Index = defaultIndex
+	}
+
+	s.configLock.Lock()
+	defer s.configLock.Unlock()
+	s.config = &config
+	return nil
+}
+
+// document is the document indexed for each log line.
+type document struct {
+	Timestamp string            `json:"@timestamp"`
+	Message   string            `json:"message"`
+	Stream    string            `json:"stream"`
+	Nomad     *taskFields       `json:"nomad,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+type taskFields struct {
+	Namespace string `json:"namespace"`
+	Job       string `json:"job"`
+	TaskGroup string `json:"task_group"`
+	Task      string `json:"task"`
+	AllocID   string `json:"alloc_id"`
+	NodeID    string `json:"node_id"`
+}
+
+// bulkResponse is the part of the bulk API response needed to find the log
+// lines that failed to be indexed.
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// ShipLogs indexes the batch of log lines with a single bulk request. Log
+// lines rejected by Elasticsearch, such as for mapping conflicts, are logged
+// and dropped rather than retried.
+func (s *ElasticsearchSink) ShipLogs(ctx context.Context, batch *logsink.LogBatch) error {
+	s.configLock.RLock()
+	config := s.config
+	s.configLock.RUnlock()
+
+	if config.Address == "" {
+		return fmt.Errorf("elasticsearch address is not configured")
+	}
+	if len(batch.Entries) == 0 {
+		return nil
+	}
+
+	index := config.Index
+	labels := maps.Clone(batch.Options)
+	if v := labels[indexOption]; v != "" {
+		index = v
+	}
+	delete(labels, indexOption)
+
+	var fields *taskFields
+	if t := batch.Task; t != nil {
+		fields = &taskFields{
+			Namespace: t.Namespace,
+			Job:       t.JobID,
+			TaskGroup: t.TaskGroup,
+			Task:      t.TaskName,
+			AllocID:   t.AllocID,
+			NodeID:    t.NodeID,
+		}
+	}
+
+	// The bulk API takes an action line followed by the document for each
+	// log line
+	action, err := json.Marshal(map[string]any{"create": map[string]string{"_index": index}})
+	if err != nil {
+		return err
+	}
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, entry := range batch.Entries {
+		body.Write(action)
+		body.WriteByte('\n')
+		err := enc.Encode(&document{
+			Timestamp: entry.Timestamp.UTC().Format(time.RFC3339Nano),
+			Message:   string(entry.Line),
+			Stream:    entry.Stream,
+			Nomad:     fields,
+			Labels:    labels,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimSuffix(config.Address, "/")+"/_bulk", &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	switch {
+	case config.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+config.APIKey)
+	case config.Username != "":
+		req.SetBasicAuth(config.Username, config.Password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to index logs in elasticsearch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to index logs in elasticsearch: %s: %s",
+			resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var bulk bulkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&bulk); err != nil {
+		return fmt.Errorf("failed to decode elasticsearch bulk response: %w", err)
+	}
+	if bulk.Errors {
+		var failed int
+		var reason string
+		for _, item := range bulk.Items {
+			for _, result := range item {
+				if result.Error == nil {
+					continue
+				}
+				if failed == 0 {
+					reason = fmt.Sprintf("%s: %s", result.Error.Type, result.Error.Reason)
+				}
+				failed++
+			}
+		}
+		s.logger.Warn("elasticsearch rejected log lines",
+			"index", index, "rejected", failed, "lines", len(batch.Entries), "reason", reason)
+	}
+	return nil
+}
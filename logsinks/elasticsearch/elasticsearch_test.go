@@ -0,0 +1,116 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package elasticsearch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/logsink"
+	"github.com/shoenig/test/must"
+)
+
+func TestElasticsearchSink_ShipLogs(t *testing.T) {
+	ci.Parallel(t)
+
+	var lines []map[string]any
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		must.Eq(t, "/_bulk", r.URL.Path)
+		auth = r.Header.Get("Authorization")
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var line map[string]any
+			must.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			lines = append(lines, line)
+		}
+		w.Write([]byte(`{"errors":false,"items":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	sink := NewElasticsearchSink(testlog.HCLogger(t))
+	var config []byte
+	must.NoError(t, base.MsgPackEncode(&config, &Config{
+		Address: srv.URL,
+		APIKey:  "c2VjcmV0",
+	}))
+	must.NoError(t, sink.SetConfig(&base.Config{PluginConfig: config}))
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	err := sink.ShipLogs(context.Background(), &logsink.LogBatch{
+		Task: &logsink.TaskInfo{
+			Namespace: "default",
+			JobID:     "example",
+			AllocID:   "a8198d79-cfdb-6593-a999-1e9adabcba2e",
+			TaskGroup: "cache",
+			TaskName:  "redis",
+			NodeID:    "c5ea5fa4-fc47-3e4c-0ab8-1f8b2c4a4e7a",
+		},
+		Options: map[string]string{"index": "redis-logs", "team": "storage"},
+		Entries: []*logsink.LogEntry{
+			{Timestamp: now, Stream: logsink.StreamStdout, Line: []byte("ready")},
+			{Timestamp: now, Stream: logsink.StreamStderr, Line: []byte("oom")},
+		},
+	})
+	must.NoError(t, err)
+	must.Eq(t, "ApiKey c2VjcmV0", auth)
+
+	must.Len(t, 4, lines)
+	must.Eq(t, map[string]any{"create": map[string]any{"_index": "redis-logs"}}, lines[0])
+	must.Eq(t, map[string]any{
+		"@timestamp": "2024-03-01T12:00:00Z",
+		"message":    "ready",
+		"stream":     "stdout",
+		"nomad": map[string]any{
+			"namespace":  "default",
+			"job":        "example",
+			"task_group": "cache",
+			"task":       "redis",
+			"alloc_id":   "a8198d79-cfdb-6593-a999-1e9adabcba2e",
+			"node_id":    "c5ea5fa4-fc47-3e4c-0ab8-1f8b2c4a4e7a",
+		},
+		"labels": map[string]any{"team": "storage"},
+	}, lines[1])
+	must.Eq(t, "oom", lines[3]["message"])
+	must.Eq(t, "stderr", lines[3]["stream"])
+}
+
+func TestElasticsearchSink_ShipLogs_Errors(t *testing.T) {
+	ci.Parallel(t)
+
+	status := http.StatusTooManyRequests
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			http.Error(w, "rejected execution", status)
+			return
+		}
+		w.Write([]byte(`{"errors":true,"items":[{"create":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	sink := NewElasticsearchSink(testlog.HCLogger(t))
+	var config []byte
+	must.NoError(t, base.MsgPackEncode(&config, &Config{Address: srv.URL}))
+	must.NoError(t, sink.SetConfig(&base.Config{PluginConfig: config}))
+
+	batch := &logsink.LogBatch{
+		Entries: []*logsink.LogEntry{{Stream: logsink.StreamStdout, Line: []byte("ready")}},
+	}
+
+	// Failed requests are returned so the batch is retried
+	err := sink.ShipLogs(context.Background(), batch)
+	must.ErrorContains(t, err, "429 Too Many Requests: rejected execution")
+
+	// Rejected log lines are dropped
+	status = http.StatusOK
+	must.NoError(t, sink.ShipLogs(context.Background(), batch))
+}
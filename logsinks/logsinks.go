@@ -0,0 +1,33 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package logsinks contains the log sink plugins built into Nomad.
+package logsinks
+
+import (
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/logsinks/cloudwatch"
+	"github.com/hashicorp/nomad/logsinks/elasticsearch"
+	"github.com/hashicorp/nomad/logsinks/loki"
+	"github.com/hashicorp/nomad/plugins/logsink"
+)
+
+// builtins are the factories of the log sink plugins built into Nomad, keyed
+// by plugin name.
+var builtins = map[string]func(hclog.Logger) logsink.LogSinkPlugin{
+	cloudwatch.PluginID.Name:    cloudwatch.NewCloudWatchSink,
+	elasticsearch.PluginID.Name: elasticsearch.NewElasticsearchSink,
+	loki.PluginID.Name:          loki.NewLokiSink,
+}
+
+// New returns the log sink plugin built into Nomad with the given name, or
+// false if there is none. Unlike the plugin catalog, it doesn't import the
+// task drivers, so it can be used by logmon to run the built-in log sinks in
+// its own process.
+func New(name string, logger hclog.Logger) (logsink.LogSinkPlugin, bool) {
+	factory, ok := builtins[name]
+	if !ok {
+		return nil, false
+	}
+	return factory(logger), true
+}
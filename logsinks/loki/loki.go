@@ -0,0 +1,219 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+// Package loki implements a log sink plugin that ships the logs of tasks to
+// Grafana Loki.
+package loki
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/helper/pluginutils/hclutils"
+	"github.com/hashicorp/nomad/helper/pluginutils/loader"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/logsink"
+	"github.com/hashicorp/nomad/plugins/shared/hclspec"
+)
+
+const (
+	// pluginName is the name of the plugin
+	pluginName = "loki"
+
+	// pushPath is the path of the Loki push API
+	pushPath = "/loki/api/v1/push"
+
+	// requestTimeout is how long pushing a batch of log lines may take
+	requestTimeout = 30 * time.Second
+)
+
+var (
+	// PluginID is the loki plugin metadata registered in the plugin catalog.
+	PluginID = loader.PluginID{
+		Name:       pluginName,
+		PluginType: base.PluginTypeLogSink,
+	}
+
+	// PluginConfig is the loki factory function registered in the plugin
+	// catalog.
+	PluginConfig = &loader.InternalPluginConfig{
+		Factory: func(_ context.Context, l hclog.Logger) interface{} { return NewLokiSink(l) },
+	}
+
+	// pluginInfo is the response returned for the PluginInfo RPC
+	pluginInfo = &base.PluginInfoResponse{
+		Type:              base.PluginTypeLogSink,
+		PluginApiVersions: []string{logsink.ApiVersion010},
+		PluginVersion:     "0.1.0",
+		Name:              pluginName,
+	}
+
+	// configSpec is the hcl specification returned by the ConfigSchema RPC
+	configSpec = hclspec.NewObject(map[string]*hclspec.Spec{
+		"address":   hclspec.NewAttr("address", "string", false),
+		"tenant_id": hclspec.NewAttr("tenant_id", "string", false),
+		"username":  hclspec.NewAttr("username", "string", false),
+		"password":  hclspec.NewAttr("password", "string", false),
+		"labels":    hclspec.NewAttr("labels", "list(map(string))", false),
+	})
+)
+
+// Config is the client configuration of the loki log sink.
+type Config struct {
+	// Address is the base URL of Loki, such as "http://loki:3100".
+	Address string `codec:"address"`
+
+	// TenantID is sent as the X-Scope-OrgID header to multi-tenant Lokis.
+	TenantID string `codec:"tenant_id"`
+
+	// Username and Password are used for basic authentication.
+	Username string `codec:"username"`
+	Password string `codec:"password"`
+
+	// Labels are added to the labels of every log stream.
+	Labels hclutils.MapStrStr `codec:"labels"`
+}
+
+// LokiSink is a log sink plugin that pushes log lines to Loki.
+type LokiSink struct {
+	logger hclog.Logger
+	client *http.Client
+
+	config     *Config
+	configLock sync.RWMutex
+}
+
+// NewLokiSink returns a new loki log sink.
+func NewLokiSink(logger hclog.Logger) logsink.LogSinkPlugin {
+	return &LokiSink{
+		logger: logger.Named(pluginName),
+		client: cleanhttp.DefaultPooledClient(),
+		config: new(Config),
+	}
+}
+
+func (s *LokiSink) PluginInfo() (*base.PluginInfoResponse, error) {
+	return pluginInfo, nil
+}
+
+func (s *LokiSink) ConfigSchema() (*hclspec.Spec, error) {
+	return configSpec, nil
+}
+
+func (s *LokiSink) SetConfig(cfg *base.Config) error {
+	var config Config
+	if len(cfg.PluginConfig) != 0 {
+		if err := base.MsgPackDecode(cfg.PluginConfig, &config); err != nil {
+			return err
+		}
+	}
+
+	s.configLock.Lock()
+	defer s.configLock.Unlock()
+	s.config = &config
+	return nil
+}
+
+// pushRequest is the body of a request to the Loki push API.
+type pushRequest struct {
+	Streams []*stream `json:"streams"`
+}
+
+type stream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// ShipLogs pushes the batch of log lines to Loki, with a log stream for each
+// of the task's output streams.
+func (s *LokiSink) ShipLogs(ctx context.Context, batch *logsink.LogBatch) error {
+	s.configLock.RLock()
+	config := s.config
+	s.configLock.RUnlock()
+
+	if config.Address == "" {
+		return fmt.Errorf("loki address is not configured")
+	}
+
+	req := &pushRequest{}
+	streams := make(map[string]*stream, 2)
+	for _, entry := range batch.Entries {
+		st, ok := streams[entry.Stream]
+		if !ok {
+			st = &stream{Stream: streamLabels(config.Labels, batch, entry.Stream)}
+			streams[entry.Stream] = st
+			req.Streams = append(req.Streams, st)
+		}
+		st.Values = append(st.Values, [2]string{
+			strconv.FormatInt(entry.Timestamp.UnixNano(), 10),
+			string(entry.Line),
+		})
+	}
+	if len(req.Streams) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimSuffix(config.Address, "/")+pushPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if config.TenantID != "" {
+		httpReq.Header.Set("X-Scope-OrgID", config.TenantID)
+	}
+	if config.Username != "" {
+		httpReq.SetBasicAuth(config.Username, config.Password)
+	}
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to push logs to loki: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to push logs to loki: %s: %s",
+			resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// streamLabels returns the labels of the log stream of a task's output
+// stream. The options of the task take precedence over the labels of the
+// client configuration.
+func streamLabels(labels map[string]string, batch *logsink.LogBatch, streamName string) map[string]string {
+	out := maps.Clone(labels)
+	if out == nil {
+		out = make(map[string]string, 5+len(batch.Options))
+	}
+	if t := batch.Task; t != nil {
+		out["namespace"] = t.Namespace
+		out["job"] = t.JobID
+		out["task_group"] = t.TaskGroup
+		out["task"] = t.TaskName
+	}
+	out["stream"] = streamName
+	maps.Copy(out, batch.Options)
+	return out
+}
@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/logsink"
+	"github.com/shoenig/test/must"
+)
+
+func TestLokiSink_ShipLogs(t *testing.T) {
+	ci.Parallel(t)
+
+	var pushed pushRequest
+	var tenant, user string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		must.Eq(t, pushPath, r.URL.Path)
+		tenant = r.Header.Get("X-Scope-OrgID")
+		user, _, _ = r.BasicAuth()
+		must.NoError(t, json.NewDecoder(r.Body).Decode(&pushed))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	sink := NewLokiSink(testlog.HCLogger(t))
+	var config []byte
+	must.NoError(t, base.MsgPackEncode(&config, &Config{
+		Address:  srv.URL,
+		TenantID: "platform",
+		Username: "nomad",
+		Labels:   map[string]string{"cluster": "east", "team": "infra"},
+	}))
+	must.NoError(t, sink.SetConfig(&base.Config{PluginConfig: config}))
+
+	now := time.Unix(1700000000, 42)
+	err := sink.ShipLogs(context.Background(), &logsink.LogBatch{
+		Task: &logsink.TaskInfo{
+			Namespace: "default",
+			JobID:     "example",
+			TaskGroup: "cache",
+			TaskName:  "redis",
+		},
+		Options: map[string]string{"team": "storage"},
+		Entries: []*logsink.LogEntry{
+			{Timestamp: now, Stream: logsink.StreamStdout, Line: []byte("ready")},
+			{Timestamp: now, Stream: logsink.StreamStderr, Line: []byte("oom")},
+			{Timestamp: now.Add(time.Second), Stream: logsink.StreamStdout, Line: []byte("accepted")},
+		},
+	})
+	must.NoError(t, err)
+	must.Eq(t, "platform", tenant)
+	must.Eq(t, "nomad", user)
+
+	must.Len(t, 2, pushed.Streams)
+	must.Eq(t, map[string]string{
+		"cluster":    "east",
+		"team":       "storage",
+		"namespace":  "default",
+		"job":        "example",
+		"task_group": "cache",
+		"task":       "redis",
+		"stream":     "stdout",
+	}, pushed.Streams[0].Stream)
+	must.Eq(t, [][2]string{
+		{"1700000000000000042", "ready"},
+		{"1700000001000000042", "accepted"},
+	}, pushed.Streams[0].Values)
+	must.Eq(t, "stderr", pushed.Streams[1].Stream["stream"])
+	must.Eq(t, [][2]string{{"1700000000000000042", "oom"}}, pushed.Streams[1].Values)
+}
+
+func TestLokiSink_ShipLogs_Error(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "entry too far behind", http.StatusBadRequest)
+	}))
+	t.Cleanup(srv.Close)
+
+	sink := NewLokiSink(testlog.HCLogger(t))
+	var config []byte
+	must.NoError(t, base.MsgPackEncode(&config, &Config{Address: srv.URL}))
+	must.NoError(t, sink.SetConfig(&base.Config{PluginConfig: config}))
+
+	err := sink.ShipLogs(context.Background(), &logsink.LogBatch{
+		Entries: []*logsink.LogEntry{{Stream: logsink.StreamStdout, Line: []byte("ready")}},
+	})
+	must.ErrorContains(t, err, "400 Bad Request: entry too far behind")
+}
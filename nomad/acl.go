@@ -36,6 +36,21 @@ func (s *Server) ResolveToken(secretID string) (*acl.ACL, error) {
 	return s.auth.ResolveToken(secretID)
 }
 
+func (s *Server) ResolveIdentity(bearerToken string) (*structs.AuthenticatedIdentity, error) {
+	return s.auth.ResolveIdentity(bearerToken)
+}
+
+// consumeACLTokenUse records a use of the ACL token with the given accessor ID
+// through the leader.
+func (s *Server) consumeACLTokenUse(accessorID string) error {
+	args := &structs.ACLTokenUseRequest{
+		AccessorID:   accessorID,
+		WriteRequest: structs.WriteRequest{Region: s.config.Region},
+	}
+	var reply structs.GenericResponse
+	return s.RPC(structs.ACLConsumeTokenUseRPCMethod, args, &reply)
+}
+
 func (s *Server) ResolvePoliciesForClaims(claims *structs.IdentityClaims) ([]*structs.ACLPolicy, error) {
 	return s.auth.ResolvePoliciesForClaims(claims)
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-set/v2"
+	"golang.org/x/oauth2"
 
 	policy "github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/helper"
@@ -52,6 +53,15 @@ const (
 	// aclLoginRequestExpiryTime is the deadline used when performing HTTP
 	// requests to external APIs during the validation of bearer tokens.
 	aclLoginRequestExpiryTime = 60 * time.Second
+
+	// aclOIDCDeviceRequestExpiryTime is the deadline used when performing
+	// HTTP requests to the OIDC provider during the device authorization
+	// flow.
+	aclOIDCDeviceRequestExpiryTime = 60 * time.Second
+
+	// aclOIDCDeviceDefaultInterval is the polling interval of the device
+	// authorization flow when the OIDC provider doesn't specify one.
+	aclOIDCDeviceDefaultInterval = 5 * time.Second
 )
 
 // ACL endpoint is used for manipulating ACL tokens and policies
@@ -638,6 +648,21 @@ func (a *ACL) UpsertTokens(args *structs.ACLTokenUpsertRequest, reply *structs.A
 		return structs.ErrPermissionDenied
 	}
 
+	// Record the creator of new tokens, so the lineage of a token is known
+	// when approving pending tokens. New tokens inherit the identity of their
+	// creator.
+	creator := args.GetIdentity().GetACLToken()
+	for _, token := range args.Tokens {
+		if token.AccessorID == "" {
+			token.CreatedBy = ""
+			token.AuthIdentity = ""
+			if creator != nil {
+				token.CreatedBy = creator.AccessorID
+				token.AuthIdentity = creator.AuthIdentity
+			}
+		}
+	}
+
 	// Snapshot the state so we can perform lookups against the accessor ID if
 	// needed. Do it here, so we only need to do this once no matter how many
 	// tokens we are upserting.
@@ -687,6 +712,21 @@ func (a *ACL) upsertTokens(
 			return structs.NewErrRPCCodedf(http.StatusBadRequest, "token %d invalid: %v", idx, err)
 		}
 
+		// The not-before time, maximum uses, and approval of a token can't be
+		// updated, and its uses are only counted by the servers.
+		if existingToken != nil {
+			token.NotBefore = existingToken.NotBefore
+			token.MaxUses = existingToken.MaxUses
+			token.Uses = existingToken.Uses
+			token.Pending = existingToken.Pending
+			token.CreatedBy = existingToken.CreatedBy
+			token.ApprovedBy = existingToken.ApprovedBy
+			token.AuthIdentity = existingToken.AuthIdentity
+		} else {
+			token.Uses = 0
+			token.ApprovedBy = ""
+		}
+
 		var normalizedRoleLinks []*structs.ACLTokenRoleLink
 		uniqueRoleIDs := make(map[string]struct{})
 
@@ -720,6 +760,20 @@ func (a *ACL) upsertTokens(
 				return structs.NewErrRPCCodedf(http.StatusBadRequest, "cannot find role %s", roleIdentifier)
 			}
 
+			// Tokens linked to roles requiring approval are created pending,
+			// and existing tokens can't be linked to them since they were
+			// never approved.
+			if existing.RequireApproval {
+				switch {
+				case existingToken == nil:
+					token.Pending = true
+				case !existingToken.Pending && !existingToken.HasRole(existing.ID):
+					return structs.NewErrRPCCodedf(http.StatusBadRequest,
+						"token %d invalid: role %s requires approval and can only be linked to new tokens",
+						idx, existing.Name)
+				}
+			}
+
 			// Ensure the role ID is written to the object and that the name is
 			// emptied as it is possible the role name is updated in the future.
 			roleLink.ID = existing.ID
@@ -848,6 +902,160 @@ func (a *ACL) DeleteTokens(args *structs.ACLTokenDeleteRequest, reply *structs.G
 	return nil
 }
 
+// ApproveToken is used to approve a pending token. Tokens must be approved by
+// an operator other than the one who created them.
+func (a *ACL) ApproveToken(args *structs.ACLTokenApproveRequest, reply *structs.ACLTokenUpsertResponse) error {
+	// Ensure ACLs are enabled, and always flow modification requests to the authoritative region
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+	authErr := a.srv.Authenticate(a.ctx, args)
+	if args.AccessorID == "" {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "must specify a token")
+	}
+
+	if done, err := a.srv.forward(structs.ACLApproveTokenRPCMethod, args, args, reply); done {
+		return err
+	}
+	a.srv.MeasureRPCRate("acl", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "approve_token"}, time.Now())
+
+	// Check management level permissions
+	if acl, err := a.srv.ResolveACL(args); err != nil {
+		return err
+	} else if acl == nil || !acl.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	token, err := a.srv.State().ACLTokenByAccessorID(nil, args.AccessorID)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusInternalServerError, "token lookup failed: %v", err)
+	}
+	if token == nil {
+		return structs.NewErrRPCCodedf(http.StatusNotFound, "cannot find token %s", args.AccessorID)
+	}
+
+	// Force the request to the authoritative region if the token is global
+	if token.Global && a.srv.config.Region != a.srv.config.AuthoritativeRegion {
+		args.Region = a.srv.config.AuthoritativeRegion
+		_, err := a.srv.forward(structs.ACLApproveTokenRPCMethod, args, args, reply)
+		return err
+	}
+
+	if !token.Pending {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "token %s is not pending approval", args.AccessorID)
+	}
+	approver := args.GetIdentity().GetACLToken()
+	if approver == nil {
+		return structs.NewErrRPCCoded(http.StatusForbidden,
+			"token must be approved by an operator other than its creator")
+	}
+	if err := a.checkTokenApprover(token, approver); err != nil {
+		return err
+	}
+
+	token = token.Copy()
+	token.Pending = false
+	token.ApprovedBy = approver.AccessorID
+	token.SetHash()
+
+	// Update via Raft
+	req := &structs.ACLTokenUpsertRequest{
+		Tokens:       []*structs.ACLToken{token},
+		WriteRequest: args.WriteRequest,
+	}
+	_, index, err := a.srv.raftApply(structs.ACLTokenUpsertRequestType, req)
+	if err != nil {
+		return err
+	}
+
+	out, err := a.srv.State().ACLTokenByAccessorID(nil, args.AccessorID)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusInternalServerError, "token lookup failed: %v", err)
+	}
+	reply.Tokens = []*structs.ACLToken{out}
+	reply.Index = index
+	return nil
+}
+
+// maxTokenLineageDepth bounds the number of creators followed when checking
+// the lineage of a token.
+const maxTokenLineageDepth = 64
+
+// checkTokenApprover returns an error if the approver may be held by the
+// operator who created the pending token. The approver must not be the token
+// used to create it, must not share its auth method identity, and must not
+// descend from the token used to create it. Tokens without an auth method
+// identity must also predate the pending token, since the operator may have
+// created them in a way whose lineage isn't recorded.
+func (a *ACL) checkTokenApprover(token, approver *structs.ACLToken) error {
+	conflict := func(reason string) error {
+		return structs.NewErrRPCCodedf(http.StatusForbidden,
+			"token must be approved by an operator other than its creator: %s", reason)
+	}
+
+	if approver.AccessorID == token.AccessorID || approver.AccessorID == token.CreatedBy {
+		return conflict("approver created the token")
+	}
+	if approver.AuthIdentity != "" && approver.AuthIdentity == token.AuthIdentity {
+		return conflict(fmt.Sprintf("approver has the same identity %q", approver.AuthIdentity))
+	}
+	if approver.AuthIdentity == "" && approver.CreateIndex >= token.CreateIndex {
+		return conflict("approver was created after the token")
+	}
+
+	if token.CreatedBy == "" {
+		return nil
+	}
+	snap, err := a.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	creatorID := approver.CreatedBy
+	for i := 0; i < maxTokenLineageDepth && creatorID != ""; i++ {
+		if creatorID == token.CreatedBy {
+			return conflict("approver was created by the creator of the token")
+		}
+		creator, err := snap.ACLTokenByAccessorID(nil, creatorID)
+		if err != nil {
+			return structs.NewErrRPCCodedf(http.StatusInternalServerError, "token lookup failed: %v", err)
+		}
+		if creator == nil {
+			break
+		}
+		creatorID = creator.CreatedBy
+	}
+	return nil
+}
+
+// ConsumeTokenUse is used by the servers to record a use of a token with a
+// maximum number of uses. It fails if the token has no uses left.
+func (a *ACL) ConsumeTokenUse(args *structs.ACLTokenUseRequest, reply *structs.GenericResponse) error {
+	aclObj, err := a.srv.AuthenticateServerOnly(a.ctx, args)
+	a.srv.MeasureRPCRate("acl", structs.RateMetricWrite, args)
+	if err != nil || !aclObj.AllowServerOp() {
+		return structs.ErrPermissionDenied
+	}
+
+	if done, err := a.srv.forward(structs.ACLConsumeTokenUseRPCMethod, args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "consume_token_use"}, time.Now())
+
+	// Update via Raft
+	_, index, err := a.srv.raftApply(structs.ACLTokenUseRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	// Update the index
+	reply.Index = index
+	return nil
+}
+
 // ListTokens is used to list the tokens
 func (a *ACL) ListTokens(args *structs.ACLTokenListRequest, reply *structs.ACLTokenListResponse) error {
 	if !a.srv.config.ACLEnabled {
@@ -2195,6 +2403,52 @@ func (a *ACL) WhoAmI(args *structs.GenericRequest, reply *structs.ACLWhoAmIRespo
 	return nil
 }
 
+// CreateChildIdentity signs a short-lived child identity for the workload
+// identity used to authenticate the request. The child identity is scoped to a
+// subset of the workload's namespace capabilities so that tasks can hand less
+// privileged credentials to other processes, such as sidecars, via the Task
+// API. Like WhoAmI, workload identities are verified even if ACLs are disabled.
+func (a *ACL) CreateChildIdentity(args *structs.ChildIdentityRequest, reply *structs.ChildIdentityResponse) error {
+	authErr := a.srv.Authenticate(a.ctx, args)
+	if done, err := a.srv.forward(structs.ACLCreateChildIdentityRPCMethod, args, args, reply); done {
+		return err
+	}
+	a.srv.MeasureRPCRate("acl", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "acl", "create_child_identity"}, time.Now())
+
+	if !a.srv.config.ACLEnabled {
+		if claims, _ := a.srv.VerifyClaim(args.AuthToken); claims != nil {
+			args.SetIdentity(&structs.AuthenticatedIdentity{Claims: claims})
+		}
+	}
+
+	// Child identities may only be created from workload identities and
+	// never from other child identities.
+	parent := args.GetIdentity().GetClaims()
+	if parent == nil || parent.Scope != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	args.Canonicalize()
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid child identity request: %v", err)
+	}
+
+	scope := &structs.IdentityScope{Capabilities: args.Capabilities}
+	claims := structs.NewChildIdentityClaims(parent, scope, args.TTL, time.Now())
+	token, _, err := a.srv.encrypter.SignClaims(claims)
+	if err != nil {
+		return fmt.Errorf("failed to sign child identity: %w", err)
+	}
+
+	reply.JWT = token
+	reply.Expiration = claims.Expiry.Time()
+	return nil
+}
+
 // UpsertBindingRules creates or updates ACL binding rules held within Nomad.
 func (a *ACL) UpsertBindingRules(
 	args *structs.ACLBindingRulesUpsertRequest, reply *structs.ACLBindingRulesUpsertResponse) error {
@@ -2755,72 +3009,198 @@ func (a *ACL) OIDCCompleteAuth(
 		}
 	}
 
-	// Generate the data used by the go-bexpr selector that is an internal
-	// representation of the claims that can be understood by Nomad.
-	oidcInternalClaims, err := auth.SelectorData(authMethod, idTokenClaims, userClaims)
+	token, err := a.issueLoginToken(authMethod, stateSnapshot, idTokenClaims, userClaims)
 	if err != nil {
 		return err
 	}
+	reply.ACLToken = token
+	return nil
+}
 
-	// Create a new binder object based on the current state snapshot to
-	// provide consistency within the RPC handler.
-	oidcBinder := auth.NewBinder(stateSnapshot)
+// OIDCDeviceAuth starts the OIDC device authorization workflow, for logins
+// from machines without a browser. The user visits the returned verification
+// URI on another device and enters the user code. Once this has been
+// completed, OIDCCompleteDeviceAuth can be used to obtain the ACL token.
+func (a *ACL) OIDCDeviceAuth(args *structs.ACLOIDCDeviceAuthRequest, reply *structs.ACLOIDCDeviceAuthResponse) error {
 
-	// Generate the role and policy bindings that will be assigned to the ACL
-	// token. Ensure we have at least 1 role or policy, otherwise the RPC will
-	// fail anyway.
-	tokenBindings, err := oidcBinder.Bind(authMethod, auth.NewIdentity(authMethod.Config, oidcInternalClaims))
+	// The OIDC flow can only be used when the Nomad cluster has ACL enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
+	}
+
+	// Perform the initial forwarding within the region. This ensures we
+	// respect stale queries.
+	if done, err := a.srv.forward(structs.ACLOIDCDeviceAuthRPCMethod, args, args, reply); done {
+		return err
+	}
+
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_device_auth"}, time.Now())
+
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid OIDC device-auth request: %v", err)
+	}
+
+	stateSnapshot, err := a.srv.fsm.State().Snapshot()
 	if err != nil {
 		return err
 	}
-	if tokenBindings.None() && !tokenBindings.Management {
-		return structs.NewErrRPCCoded(http.StatusBadRequest, "no role or policy bindings matched")
+
+	authMethod, err := a.oidcDeviceAuthMethod(stateSnapshot, args.AuthMethodName)
+	if err != nil {
+		return err
 	}
 
-	// Build our token RPC request. The RPC handler includes a lot of specific
-	// logic, so we do not want to call Raft directly or copy that here. In the
-	// future we should try and extract out the logic into an interface, or at
-	// least a separate function.
-	name, err := formatTokenName(authMethod.TokenNameFormat, structs.ACLAuthMethodTypeOIDC, authMethod.Name, oidcInternalClaims.Value)
+	// If the authentication method generates global ACL tokens, we need to
+	// forward the request onto the authoritative regional leader, so the
+	// same region completes the flow.
+	if authMethod.TokenLocalityIsGlobal() {
+		args.Region = a.srv.config.AuthoritativeRegion
+
+		if done, err := a.srv.forward(structs.ACLOIDCDeviceAuthRPCMethod, args, args, reply); done {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), aclOIDCDeviceRequestExpiryTime)
+	defer cancel()
+
+	deviceFlow, err := oidc.NewDeviceFlow(ctx, authMethod)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to start device authorization: %v", err)
+	}
+	deviceAuth, err := deviceFlow.Authorize(ctx)
 	if err != nil {
 		return err
 	}
 
-	token := structs.ACLToken{
-		Name:          name,
-		Global:        authMethod.TokenLocalityIsGlobal(),
-		ExpirationTTL: authMethod.MaxTokenTTL,
+	reply.DeviceCode = deviceAuth.DeviceCode
+	reply.UserCode = deviceAuth.UserCode
+	reply.VerificationURI = deviceAuth.VerificationURI
+	reply.VerificationURIComplete = deviceAuth.VerificationURIComplete
+	reply.ExpiresIn = time.Duration(deviceAuth.ExpiresIn) * time.Second
+	reply.Interval = time.Duration(deviceAuth.Interval) * time.Second
+	if reply.Interval <= 0 {
+		reply.Interval = aclOIDCDeviceDefaultInterval
 	}
+	return nil
+}
 
-	if tokenBindings.Management {
-		token.Type = structs.ACLManagementToken
-	} else {
-		token.Type = structs.ACLClientToken
-		token.Policies = tokenBindings.Policies
-		token.Roles = tokenBindings.Roles
+// OIDCCompleteDeviceAuth completes the OIDC device authorization workflow. It
+// exchanges the device code for the OIDC provider tokens, and those for a
+// Nomad ACL token, using the configured ACL role and policy claims to provide
+// authorization. If the user hasn't authorized the device yet, the reply is
+// marked as pending and the caller should try again after the interval.
+func (a *ACL) OIDCCompleteDeviceAuth(
+	args *structs.ACLOIDCCompleteDeviceAuthRequest, reply *structs.ACLOIDCCompleteDeviceAuthResponse) error {
+
+	// The OIDC flow can only be used when the Nomad cluster has ACL enabled.
+	if !a.srv.config.ACLEnabled {
+		return aclDisabled
 	}
 
-	tokenUpsertRequest := structs.ACLTokenUpsertRequest{
-		Tokens: []*structs.ACLToken{&token},
-		WriteRequest: structs.WriteRequest{
-			Region:    a.srv.Region(),
-			AuthToken: a.srv.getLeaderAcl(),
-		},
+	// Perform the initial forwarding within the region. This ensures we
+	// respect stale queries.
+	if done, err := a.srv.forward(structs.ACLOIDCCompleteDeviceAuthRPCMethod, args, args, reply); done {
+		return err
 	}
 
-	var tokenUpsertReply structs.ACLTokenUpsertResponse
+	defer metrics.MeasureSince([]string{"nomad", "acl", "oidc_complete_device_auth"}, time.Now())
 
-	if err := a.upsertTokens(&tokenUpsertRequest, &tokenUpsertReply, stateSnapshot); err != nil {
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid OIDC complete-device-auth request: %v", err)
+	}
+
+	stateSnapshot, err := a.srv.fsm.State().Snapshot()
+	if err != nil {
 		return err
 	}
 
-	// The way the UpsertTokens RPC currently works, if we get no error, then
-	// we will have exactly the same number of tokens returned as we sent. It
-	// is therefore safe to assume we have 1 token.
-	reply.ACLToken = tokenUpsertReply.Tokens[0]
+	authMethod, err := a.oidcDeviceAuthMethod(stateSnapshot, args.AuthMethodName)
+	if err != nil {
+		return err
+	}
+
+	// If the authentication method generates global ACL tokens, we need to
+	// forward the request onto the authoritative regional leader.
+	if authMethod.TokenLocalityIsGlobal() {
+		args.Region = a.srv.config.AuthoritativeRegion
+
+		if done, err := a.srv.forward(structs.ACLOIDCCompleteDeviceAuthRPCMethod, args, args, reply); done {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), aclOIDCDeviceRequestExpiryTime)
+	defer cancel()
+
+	deviceFlow, err := oidc.NewDeviceFlow(ctx, authMethod)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to complete device authorization: %v", err)
+	}
+
+	deviceToken, err := deviceFlow.Token(ctx, args.DeviceCode)
+	switch {
+	case errors.Is(err, oidc.ErrAuthorizationPending):
+		reply.Pending = true
+		return nil
+	case errors.Is(err, oidc.ErrSlowDown):
+		reply.Pending = true
+		reply.SlowDown = true
+		return nil
+	case err != nil:
+		return structs.NewErrRPCCodedf(http.StatusUnauthorized, "failed to exchange device code with provider: %v", err)
+	}
+
+	// The ID token isn't returned through a redirect, so it's verified
+	// against the provider keys like a JWT. It must be issued to the client
+	// of the auth method unless other audiences are bound.
+	methodConf := authMethod.Config.Copy()
+	if len(methodConf.BoundAudiences) == 0 {
+		methodConf.BoundAudiences = []string{methodConf.OIDCClientID}
+	}
+	idTokenClaims, err := jwt.Validate(ctx, deviceToken.IDToken, methodConf)
+	if err != nil {
+		return structs.NewErrRPCCodedf(http.StatusUnauthorized, "unable to validate ID token: %v", err)
+	}
+
+	var userClaims map[string]interface{}
+	if !authMethod.Config.OIDCDisableUserInfo && deviceToken.AccessToken != "" {
+		oidcProvider, err := a.oidcProviderCache.Get(authMethod)
+		if err != nil {
+			return fmt.Errorf("failed to generate OIDC provider: %v", err)
+		}
+		subject, _ := idTokenClaims["sub"].(string)
+		userTokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: deviceToken.AccessToken})
+		if err := oidcProvider.UserInfo(ctx, userTokenSource, subject, &userClaims); err != nil {
+			return fmt.Errorf("failed to retrieve the user info claims: %v", err)
+		}
+	}
+
+	token, err := a.issueLoginToken(authMethod, stateSnapshot, idTokenClaims, userClaims)
+	if err != nil {
+		return err
+	}
+	reply.ACLToken = token
 	return nil
 }
 
+// oidcDeviceAuthMethod looks up the named OIDC auth method used for the
+// device authorization flow.
+func (a *ACL) oidcDeviceAuthMethod(stateSnapshot *state.StateSnapshot, name string) (*structs.ACLAuthMethod, error) {
+	authMethod, err := stateSnapshot.GetACLAuthMethodByName(nil, name)
+	if err != nil {
+		return nil, err
+	}
+	if authMethod == nil {
+		return nil, structs.NewErrRPCCodedf(http.StatusBadRequest, "auth-method %q not found", name)
+	}
+	if authMethod.Type != structs.ACLAuthMethodTypeOIDC {
+		return nil, structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"device authorization is only supported by %s auth-methods", structs.ACLAuthMethodTypeOIDC)
+	}
+	return authMethod, nil
+}
+
 // Login RPC performs non-interactive auth using a given AuthMethod. This method
 // can not be used for OIDC login flow.
 func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginResponse) error {
@@ -2911,32 +3291,48 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginRespon
 		)
 	}
 
-	// Create a new binder object based on the current state snapshot to
-	// provide consistency within the RPC handler.
-	jwtBinder := auth.NewBinder(stateSnapshot)
+	token, err := a.issueLoginToken(authMethod, stateSnapshot, claims, nil)
+	if err != nil {
+		return err
+	}
+	reply.ACLToken = token
+	return nil
+}
+
+// issueLoginToken creates the ACL token for a successful login to the auth
+// method, binding the roles and policies matching the claims of the user.
+func (a *ACL) issueLoginToken(authMethod *structs.ACLAuthMethod, stateSnapshot *state.StateSnapshot,
+	claims, userClaims map[string]interface{}) (*structs.ACLToken, error) {
 
 	// Generate the data used by the go-bexpr selector that is an internal
 	// representation of the claims that can be understood by Nomad.
-	jwtClaims, err := auth.SelectorData(authMethod, claims, nil)
+	internalClaims, err := auth.SelectorData(authMethod, claims, userClaims)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	tokenBindings, err := jwtBinder.Bind(authMethod, auth.NewIdentity(authMethod.Config, jwtClaims))
+	// Create a new binder object based on the current state snapshot to
+	// provide consistency within the RPC handler.
+	binder := auth.NewBinder(stateSnapshot)
+
+	// Generate the role and policy bindings that will be assigned to the ACL
+	// token. Ensure we have at least 1 role or policy, otherwise the RPC will
+	// fail anyway.
+	tokenBindings, err := binder.Bind(authMethod, auth.NewIdentity(authMethod.Config, internalClaims))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if tokenBindings.None() && !tokenBindings.Management {
-		return structs.NewErrRPCCoded(http.StatusBadRequest, "no role or policy bindings matched")
+		return nil, structs.NewErrRPCCoded(http.StatusBadRequest, "no role or policy bindings matched")
 	}
 
 	// Build our token RPC request. The RPC handler includes a lot of specific
 	// logic, so we do not want to call Raft directly or copy that here. In the
 	// future we should try and extract out the logic into an interface, or at
 	// least a separate function.
-	name, err := formatTokenName(authMethod.TokenNameFormat, structs.ACLAuthMethodTypeJWT, authMethod.Name, jwtClaims.Value)
+	name, err := formatTokenName(authMethod.TokenNameFormat, authMethod.Type, authMethod.Name, internalClaims.Value)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	token := structs.ACLToken{
@@ -2945,6 +3341,12 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginRespon
 		ExpirationTTL: authMethod.MaxTokenTTL,
 	}
 
+	// Record who the token was issued to, so tokens issued to the same
+	// operator can't approve each other.
+	if subject, ok := claims["sub"].(string); ok && subject != "" {
+		token.AuthIdentity = authMethod.Name + "/" + subject
+	}
+
 	if tokenBindings.Management {
 		token.Type = structs.ACLManagementToken
 	} else {
@@ -2964,15 +3366,13 @@ func (a *ACL) Login(args *structs.ACLLoginRequest, reply *structs.ACLLoginRespon
 	var tokenUpsertReply structs.ACLTokenUpsertResponse
 
 	if err := a.upsertTokens(&tokenUpsertRequest, &tokenUpsertReply, stateSnapshot); err != nil {
-		return err
+		return nil, err
 	}
 
 	// The way the UpsertTokens RPC currently works, if we get no error, then
 	// we will have exactly the same number of tokens returned as we sent. It
 	// is therefore safe to assume we have 1 token.
-	reply.ACLToken = tokenUpsertReply.Tokens[0]
-
-	return nil
+	return tokenUpsertReply.Tokens[0], nil
 }
 
 func formatTokenName(format, authType, authName string, claims map[string]string) (string, error) {
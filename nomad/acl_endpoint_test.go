@@ -15,6 +15,7 @@ import (
 	capOIDC "github.com/hashicorp/cap/oidc"
 	"github.com/hashicorp/go-memdb"
 	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
@@ -1448,6 +1449,284 @@ func TestACLEndpoint_DeleteTokens_WithNonexistentToken(t *testing.T) {
 	assert.Contains(err.Error(), expectedError)
 }
 
+func TestACLEndpoint_ApproveToken(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Tokens of other operators, created before the pending token
+	client := mock.ACLToken()
+	approver := mock.ACLManagementToken()
+	must.NoError(t, s1.fsm.State().UpsertACLTokens(structs.MsgTypeTestSetup, 1,
+		[]*structs.ACLToken{client, approver}))
+
+	// Create a pending token with the root token
+	upsertReq := &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{{
+			Name:    "jit",
+			Type:    structs.ACLManagementToken,
+			Pending: true,
+		}},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var upsertResp structs.ACLTokenUpsertResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertTokensRPCMethod, upsertReq, &upsertResp))
+	must.Len(t, 1, upsertResp.Tokens)
+	pending := upsertResp.Tokens[0]
+	must.True(t, pending.Pending)
+	must.Eq(t, root.AccessorID, pending.CreatedBy)
+
+	// The pending token can't be used
+	whoAmIReq := &structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: pending.SecretID},
+	}
+	var whoAmIResp structs.ACLWhoAmIResponse
+	err := msgpackrpc.CallWithCodec(codec, "ACL.WhoAmI", whoAmIReq, &whoAmIResp)
+	must.EqError(t, err, structs.ErrTokenPending.Error())
+
+	// The creator can't approve the token
+	approveReq := &structs.ACLTokenApproveRequest{
+		AccessorID: pending.AccessorID,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var approveResp structs.ACLTokenUpsertResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLApproveTokenRPCMethod, approveReq, &approveResp)
+	must.ErrorContains(t, err, "other than its creator")
+
+	// Nor can a token created after the pending token, which the creator may
+	// hold
+	newer := mock.ACLManagementToken()
+	must.NoError(t, s1.fsm.State().UpsertACLTokens(structs.MsgTypeTestSetup, 1000,
+		[]*structs.ACLToken{newer}))
+	approveReq.AuthToken = newer.SecretID
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLApproveTokenRPCMethod, approveReq, &approveResp)
+	must.ErrorContains(t, err, "approver was created after the token")
+
+	// Nor a client token
+	approveReq.AuthToken = client.SecretID
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLApproveTokenRPCMethod, approveReq, &approveResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Another operator approves the token, which can then be used
+	approveReq.AuthToken = approver.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLApproveTokenRPCMethod, approveReq, &approveResp))
+	must.Len(t, 1, approveResp.Tokens)
+	must.False(t, approveResp.Tokens[0].Pending)
+	must.Eq(t, approver.AccessorID, approveResp.Tokens[0].ApprovedBy)
+
+	// The approval changes the hash of the token, so it's replicated
+	must.NotEq(t, pending.Hash, approveResp.Tokens[0].Hash)
+
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.WhoAmI", whoAmIReq, &whoAmIResp))
+	must.Eq(t, pending.AccessorID, whoAmIResp.Identity.ACLToken.AccessorID)
+
+	// The token can't be approved twice
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLApproveTokenRPCMethod, approveReq, &approveResp)
+	must.ErrorContains(t, err, "is not pending approval")
+
+	// Updating the token doesn't reset its approval
+	update := approveResp.Tokens[0].Copy()
+	update.Name = "jit-updated"
+	update.Pending = true
+	upsertReq.Tokens = []*structs.ACLToken{update}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertTokensRPCMethod, upsertReq, &upsertResp))
+	must.False(t, upsertResp.Tokens[0].Pending)
+}
+
+func TestACLEndpoint_ApproveToken_Identity(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	// The requester logged in with an auth method, and created a token with
+	// their login token before requesting access
+	requester := mock.ACLManagementToken()
+	requester.AuthIdentity = "okta/alice"
+	must.NoError(t, store.UpsertACLTokens(structs.MsgTypeTestSetup, 1, []*structs.ACLToken{requester}))
+
+	upsertReq := &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{{Name: "spare", Type: structs.ACLManagementToken}},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: requester.SecretID,
+		},
+	}
+	var upsertResp structs.ACLTokenUpsertResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertTokensRPCMethod, upsertReq, &upsertResp))
+	spare := upsertResp.Tokens[0]
+	must.Eq(t, requester.AccessorID, spare.CreatedBy)
+	must.Eq(t, "okta/alice", spare.AuthIdentity)
+
+	upsertReq.Tokens = []*structs.ACLToken{{Name: "jit", Type: structs.ACLManagementToken, Pending: true}}
+	var pendingResp structs.ACLTokenUpsertResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertTokensRPCMethod, upsertReq, &pendingResp))
+	pending := pendingResp.Tokens[0]
+	must.Eq(t, "okta/alice", pending.AuthIdentity)
+
+	approveReq := &structs.ACLTokenApproveRequest{
+		AccessorID:   pending.AccessorID,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var approveResp structs.ACLTokenUpsertResponse
+
+	// The token the requester created before the request can't approve it
+	approveReq.AuthToken = spare.SecretID
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLApproveTokenRPCMethod, approveReq, &approveResp)
+	must.ErrorContains(t, err, "approver has the same identity")
+
+	// Nor can a token that descends from it without the identity
+	descendant := mock.ACLManagementToken()
+	descendant.CreatedBy = requester.AccessorID
+	must.NoError(t, store.UpsertACLTokens(structs.MsgTypeTestSetup, 2, []*structs.ACLToken{descendant}))
+	grandchild := mock.ACLManagementToken()
+	grandchild.CreatedBy = descendant.AccessorID
+	must.NoError(t, store.UpsertACLTokens(structs.MsgTypeTestSetup, 3, []*structs.ACLToken{grandchild}))
+
+	approveReq.AuthToken = grandchild.SecretID
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLApproveTokenRPCMethod, approveReq, &approveResp)
+	must.ErrorContains(t, err, "approver was created by the creator of the token")
+
+	// Nor can another login of the requester
+	relogin := mock.ACLManagementToken()
+	relogin.AuthIdentity = "okta/alice"
+	must.NoError(t, store.UpsertACLTokens(structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{relogin}))
+
+	approveReq.AuthToken = relogin.SecretID
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLApproveTokenRPCMethod, approveReq, &approveResp)
+	must.ErrorContains(t, err, "approver has the same identity")
+
+	// Another operator who logged in after the request can approve it
+	other := mock.ACLManagementToken()
+	other.AuthIdentity = "okta/bob"
+	must.NoError(t, store.UpsertACLTokens(structs.MsgTypeTestSetup, 1001, []*structs.ACLToken{other}))
+
+	approveReq.AuthToken = other.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLApproveTokenRPCMethod, approveReq, &approveResp))
+	must.Eq(t, other.AccessorID, approveResp.Tokens[0].ApprovedBy)
+}
+
+func TestACLEndpoint_UpsertTokens_RoleRequireApproval(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	policy := mock.ACLPolicy()
+	must.NoError(t, store.UpsertACLPolicies(structs.MsgTypeTestSetup, 10, []*structs.ACLPolicy{policy}))
+
+	role := mock.ACLRole()
+	role.Policies = []*structs.ACLRolePolicyLink{{Name: policy.Name}}
+	role.RequireApproval = true
+	role.SetHash()
+	must.NoError(t, store.UpsertACLRoles(structs.MsgTypeTestSetup, 20, []*structs.ACLRole{role}, false))
+
+	// Tokens linked to the role are created pending
+	upsertReq := &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{{
+			Name:  "jit",
+			Type:  structs.ACLClientToken,
+			Roles: []*structs.ACLTokenRoleLink{{Name: role.Name}},
+		}},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var upsertResp structs.ACLTokenUpsertResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertTokensRPCMethod, upsertReq, &upsertResp))
+	must.True(t, upsertResp.Tokens[0].Pending)
+	must.Eq(t, root.AccessorID, upsertResp.Tokens[0].CreatedBy)
+
+	// Existing tokens can't be linked to the role
+	upsertReq.Tokens = []*structs.ACLToken{{
+		Name:     "existing",
+		Type:     structs.ACLClientToken,
+		Policies: []string{policy.Name},
+	}}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertTokensRPCMethod, upsertReq, &upsertResp))
+	existing := upsertResp.Tokens[0].Copy()
+	must.False(t, existing.Pending)
+
+	existing.Roles = []*structs.ACLTokenRoleLink{{ID: role.ID}}
+	upsertReq.Tokens = []*structs.ACLToken{existing}
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLUpsertTokensRPCMethod, upsertReq, &upsertResp)
+	must.ErrorContains(t, err, "requires approval and can only be linked to new tokens")
+}
+
+func TestACLEndpoint_TokenUsage(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Global tokens can't have a maximum number of uses
+	upsertReq := &structs.ACLTokenUpsertRequest{
+		Tokens: []*structs.ACLToken{{
+			Type:    structs.ACLManagementToken,
+			Global:  true,
+			MaxUses: 2,
+		}},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: root.SecretID,
+		},
+	}
+	var upsertResp structs.ACLTokenUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLUpsertTokensRPCMethod, upsertReq, &upsertResp)
+	must.ErrorContains(t, err, "tokens with maximum uses must be local")
+
+	// The uses of a local token are counted until it's exhausted
+	upsertReq.Tokens = []*structs.ACLToken{{
+		Type:    structs.ACLManagementToken,
+		MaxUses: 2,
+	}}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertTokensRPCMethod, upsertReq, &upsertResp))
+	token := upsertResp.Tokens[0]
+
+	whoAmIReq := &structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: token.SecretID},
+	}
+	var whoAmIResp structs.ACLWhoAmIResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.WhoAmI", whoAmIReq, &whoAmIResp))
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.WhoAmI", whoAmIReq, &whoAmIResp))
+	err = msgpackrpc.CallWithCodec(codec, "ACL.WhoAmI", whoAmIReq, &whoAmIResp)
+	must.EqError(t, err, structs.ErrTokenExhausted.Error())
+
+	out, err := s1.fsm.State().ACLTokenByAccessorID(nil, token.AccessorID)
+	must.NoError(t, err)
+	must.Eq(t, 2, out.Uses)
+
+	// Tokens can't be used before their not-before time
+	notBefore := time.Now().Add(time.Hour).UTC()
+	upsertReq.Tokens = []*structs.ACLToken{{
+		Type:      structs.ACLManagementToken,
+		NotBefore: &notBefore,
+	}}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLUpsertTokensRPCMethod, upsertReq, &upsertResp))
+
+	whoAmIReq.AuthToken = upsertResp.Tokens[0].SecretID
+	err = msgpackrpc.CallWithCodec(codec, "ACL.WhoAmI", whoAmIReq, &whoAmIResp)
+	must.EqError(t, err, structs.ErrTokenNotYetValid.Error())
+}
+
 func TestACLEndpoint_Bootstrap(t *testing.T) {
 	ci.Parallel(t)
 	s1, cleanupS1 := TestServer(t, func(c *Config) {
@@ -1958,6 +2237,85 @@ func TestACLEndpoint_WhoAmI(t *testing.T) {
 	must.Eq(t, alloc.ID, resp3.Identity.Claims.AllocationID)
 }
 
+func TestACLEndpoint_CreateChildIdentity(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	t.Cleanup(cleanupS1)
+	codec := rpcClient(t, s1)
+	testutil.WaitForKeyring(t, s1.RPC, "global")
+
+	alloc := mock.Alloc()
+	must.NoError(t, s1.fsm.State().UpsertAllocs(
+		structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc}))
+
+	policy := mock.ACLPolicy()
+	policy.Rules = `namespace "default" { capabilities = ["read-job", "submit-job"] }`
+	policy.JobACL = &structs.JobACL{
+		Namespace: alloc.Namespace,
+		JobID:     alloc.JobID,
+	}
+	policy.SetHash()
+	must.NoError(t, s1.fsm.State().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 1001, []*structs.ACLPolicy{policy}))
+
+	claims := structs.NewIdentityClaims(alloc.Job, alloc,
+		wiHandle, // see encrypter_test.go
+		alloc.LookupTask("web").Identity, time.Now())
+	parentJWT, _, err := s1.encrypter.SignClaims(claims)
+	must.NoError(t, err)
+
+	req := &structs.ChildIdentityRequest{
+		Capabilities: []string{"read-job", "alloc-exec"},
+		TTL:          time.Minute,
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: parentJWT},
+	}
+	var resp structs.ChildIdentityResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ACLCreateChildIdentityRPCMethod, req, &resp))
+	must.NotEq(t, "", resp.JWT)
+	must.True(t, resp.Expiration.Before(time.Now().Add(time.Minute+time.Second)))
+
+	// The child identity is restricted to the capabilities granted to both it
+	// and the workload
+	child := &structs.GenericRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: resp.JWT},
+	}
+	var policyResp structs.ACLPolicySetResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "ACL.GetClaimPolicies", child, &policyResp))
+	must.MapLen(t, 1, policyResp.Policies)
+	for _, p := range policyResp.Policies {
+		parsed, err := acl.Parse(p.Rules)
+		must.NoError(t, err)
+		must.Len(t, 1, parsed.Namespaces)
+		must.Eq(t, []string{acl.NamespaceCapabilityReadJob}, parsed.Namespaces[0].Capabilities)
+	}
+
+	// Child identities can not create further child identities
+	req.AuthToken = resp.JWT
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLCreateChildIdentityRPCMethod, req, &resp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Invalid capabilities and TTLs are rejected
+	req.AuthToken = parentJWT
+	req.Capabilities = []string{"not-a-capability"}
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLCreateChildIdentityRPCMethod, req, &resp)
+	must.ErrorContains(t, err, "invalid capabilities")
+
+	req.Capabilities = nil
+	req.TTL = 2 * structs.ChildIdentityMaxTTL
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLCreateChildIdentityRPCMethod, req, &resp)
+	must.ErrorContains(t, err, "ttl must be")
+
+	// ACL tokens can not create child identities
+	token := mock.ACLToken()
+	must.NoError(t, s1.fsm.State().UpsertACLTokens(
+		structs.MsgTypeTestSetup, 1002, []*structs.ACLToken{token}))
+	req.AuthToken = token.SecretID
+	req.TTL = 0
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLCreateChildIdentityRPCMethod, req, &resp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+}
+
 func TestACLEndpoint_OneTimeToken(t *testing.T) {
 	ci.Parallel(t)
 
@@ -3625,6 +3983,56 @@ func TestACL_OIDCAuthURL(t *testing.T) {
 	must.StrContains(t, escapedURL, "&state=st_")
 }
 
+func TestACL_OIDCDeviceAuth(t *testing.T) {
+	ci.Parallel(t)
+
+	testServer, _, testServerCleanupFn := TestACLServer(t, nil)
+	defer testServerCleanupFn()
+	codec := rpcClient(t, testServer)
+	testutil.WaitForLeader(t, testServer.RPC)
+
+	// Send an empty request to ensure the RPC handler runs the validation
+	// func.
+	deviceAuthReq := structs.ACLOIDCDeviceAuthRequest{
+		WriteRequest: structs.WriteRequest{
+			Region: DefaultRegion,
+		},
+	}
+
+	var deviceAuthResp structs.ACLOIDCDeviceAuthResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.ACLOIDCDeviceAuthRPCMethod, &deviceAuthReq, &deviceAuthResp)
+	must.ErrorContains(t, err, "400")
+	must.ErrorContains(t, err, "invalid OIDC device-auth request")
+
+	// Send a request for an auth method that does not exist within state.
+	deviceAuthReq.AuthMethodName = "test-oidc-auth-method"
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCDeviceAuthRPCMethod, &deviceAuthReq, &deviceAuthResp)
+	must.ErrorContains(t, err, "auth-method \"test-oidc-auth-method\" not found")
+
+	// The device flow can't be used with JWT auth methods.
+	jwtAuthMethod := mock.ACLJWTAuthMethod()
+	must.NoError(t, testServer.fsm.State().UpsertACLAuthMethods(10, []*structs.ACLAuthMethod{jwtAuthMethod}))
+
+	deviceAuthReq.AuthMethodName = jwtAuthMethod.Name
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCDeviceAuthRPCMethod, &deviceAuthReq, &deviceAuthResp)
+	must.ErrorContains(t, err, "device authorization is only supported by OIDC auth-methods")
+
+	completeReq := structs.ACLOIDCCompleteDeviceAuthRequest{
+		AuthMethodName: jwtAuthMethod.Name,
+		WriteRequest: structs.WriteRequest{
+			Region: DefaultRegion,
+		},
+	}
+
+	var completeResp structs.ACLOIDCCompleteDeviceAuthResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCCompleteDeviceAuthRPCMethod, &completeReq, &completeResp)
+	must.ErrorContains(t, err, "missing device code")
+
+	completeReq.DeviceCode = "device-code"
+	err = msgpackrpc.CallWithCodec(codec, structs.ACLOIDCCompleteDeviceAuthRPCMethod, &completeReq, &completeResp)
+	must.ErrorContains(t, err, "device authorization is only supported by OIDC auth-methods")
+}
+
 func TestACL_OIDCCompleteAuth(t *testing.T) {
 	ci.Parallel(t)
 
@@ -3927,6 +4335,7 @@ func TestACL_Login(t *testing.T) {
 	must.Eq(t, mockACLRole.Name, completeAuthResp4.ACLToken.Roles[0].Name)
 	must.Eq(t, mockACLRole.ID, completeAuthResp4.ACLToken.Roles[0].ID)
 	must.Eq(t, mockedAuthMethod.Type+"-"+mockedAuthMethod.Name, completeAuthResp4.ACLToken.Name)
+	must.Eq(t, mockedAuthMethod.Name+"/"+user, completeAuthResp4.ACLToken.AuthIdentity)
 
 	// Create a binding rule which generates management tokens. This should
 	// override the other rules, giving us a management token when we next
@@ -253,7 +253,11 @@ func (a *Alloc) GetAllocs(args *structs.AllocsGetRequest,
 
 			// Setup the output
 			if thresholdMet {
-				reply.Allocs = allocs
+				var err error
+				reply.Allocs, reply.Deltas, err = allocDeltas(allocs, args.DeltaBases)
+				if err != nil {
+					return err
+				}
 				reply.Index = maxIndex
 			} else {
 				// Use the last index that affected the allocs table
@@ -272,6 +276,35 @@ func (a *Alloc) GetAllocs(args *structs.AllocsGetRequest,
 	return a.srv.blockingRPC(&opts)
 }
 
+// allocDeltas splits the allocations between the ones sent in full and the
+// ones sent as deltas to a client that has a version of them with the same job.
+func allocDeltas(allocs []*structs.Allocation, bases map[string]uint64) ([]*structs.Allocation, []*structs.AllocationDelta, error) {
+	if len(bases) == 0 {
+		return allocs, nil, nil
+	}
+
+	full := make([]*structs.Allocation, 0, len(allocs))
+	var deltas []*structs.AllocationDelta
+	for _, alloc := range allocs {
+		jobModifyIndex, ok := bases[alloc.ID]
+		if !ok {
+			full = append(full, alloc)
+			continue
+		}
+
+		delta, err := structs.NewAllocationDelta(alloc, jobModifyIndex)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to compute delta of allocation %s: %w", alloc.ID, err)
+		}
+		if delta == nil {
+			full = append(full, alloc)
+			continue
+		}
+		deltas = append(deltas, delta)
+	}
+	return full, deltas, nil
+}
+
 // Stop is used to stop an allocation and migrate it to another node.
 func (a *Alloc) Stop(args *structs.AllocStopRequest, reply *structs.AllocStopResponse) error {
 
@@ -660,6 +693,18 @@ func (a *Alloc) signIdentities(
 	now time.Time,
 ) error {
 	claims := structs.NewIdentityClaims(alloc.Job, alloc, &idReq.WIHandle, wid, now)
+
+	// Extra claims may interpolate the node the allocation runs on and the
+	// task meta.
+	if len(wid.ExtraClaims) > 0 {
+		node, err := a.srv.State().NodeByID(nil, alloc.NodeID)
+		if err != nil {
+			return err
+		}
+		taskMeta := alloc.Job.CombinedTaskMeta(alloc.TaskGroup, claims.TaskName)
+		claims.ExtraClaims = wid.InterpolateExtraClaims(taskMeta, node)
+	}
+
 	token, _, err := a.srv.encrypter.SignClaims(claims)
 	if err != nil {
 		return err
@@ -981,6 +981,72 @@ func TestAllocEndpoint_GetAllocs(t *testing.T) {
 	}
 }
 
+func TestAllocEndpoint_GetAllocs_Deltas(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	alloc := mock.Alloc()
+	alloc2 := mock.Alloc()
+	state := s1.fsm.State()
+	must.NoError(t, state.UpsertJobSummary(998, mock.JobSummary(alloc.JobID)))
+	must.NoError(t, state.UpsertJobSummary(999, mock.JobSummary(alloc2.JobID)))
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, []*structs.Allocation{alloc, alloc2}))
+
+	node := mock.Node()
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1001, node))
+
+	// Pull the allocations in full, as a client does the first time
+	get := &structs.AllocsGetRequest{
+		AllocIDs: []string{alloc.ID, alloc2.ID},
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: node.SecretID,
+		},
+	}
+	var resp structs.AllocsGetResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.GetAllocs", get, &resp))
+	must.Len(t, 2, resp.Allocs)
+	must.Len(t, 0, resp.Deltas)
+
+	bases := make(map[string]*structs.Allocation)
+	for _, a := range resp.Allocs {
+		bases[a.ID] = a
+	}
+
+	// Only the allocation whose job is unchanged is returned as a delta
+	get.DeltaBases = map[string]uint64{
+		alloc.ID:  alloc.Job.JobModifyIndex,
+		alloc2.ID: alloc2.Job.JobModifyIndex + 1,
+	}
+	resp = structs.AllocsGetResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Alloc.GetAllocs", get, &resp))
+	must.Len(t, 1, resp.Allocs)
+	must.Eq(t, alloc2.ID, resp.Allocs[0].ID)
+	must.Len(t, 1, resp.Deltas)
+
+	delta := resp.Deltas[0]
+	must.Eq(t, alloc.ID, delta.Alloc.ID)
+	must.Nil(t, delta.Alloc.Job)
+	must.Nil(t, delta.Alloc.AllocatedResources)
+
+	// The client reconstructs the allocation from its version
+	out, err := delta.Apply(bases[alloc.ID])
+	must.NoError(t, err)
+	must.Eq(t, alloc.ID, out.ID)
+	must.Eq(t, bases[alloc.ID].Job, out.Job)
+	must.Eq(t, bases[alloc.ID].AllocatedResources, out.AllocatedResources)
+
+	// A version with different resources fails the checksum verification
+	base := bases[alloc.ID].Copy()
+	base.AllocatedResources.Shared.DiskMB++
+	_, err = delta.Apply(base)
+	must.ErrorContains(t, err, "checksum mismatch")
+}
+
 func TestAllocEndpoint_GetAllocs_Blocking(t *testing.T) {
 	ci.Parallel(t)
 
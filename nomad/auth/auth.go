@@ -56,6 +56,10 @@ type Authenticator struct {
 	// encrypter is a pointer to the server's Encrypter that can be used to
 	// verify claims
 	encrypter Encrypter
+
+	// consumeTokenUse records a use of an ACL token with a maximum number of
+	// uses, failing if the token is exhausted
+	consumeTokenUse TokenUseConsumer
 }
 
 type AuthenticatorConfig struct {
@@ -66,8 +70,15 @@ type AuthenticatorConfig struct {
 	VerifyTLS      bool
 	Region         string
 	Encrypter      Encrypter
+
+	// ConsumeTokenUseFn is optional, if not set the uses of ACL tokens with
+	// a maximum number of uses are not counted
+	ConsumeTokenUseFn TokenUseConsumer
 }
 
+// TokenUseConsumer records a use of the ACL token with the accessor ID.
+type TokenUseConsumer func(accessorID string) error
+
 func NewAuthenticator(cfg *AuthenticatorConfig) *Authenticator {
 	return &Authenticator{
 		aclsEnabled:          cfg.AclsEnabled,
@@ -78,6 +89,7 @@ func NewAuthenticator(cfg *AuthenticatorConfig) *Authenticator {
 		region:               cfg.Region,
 		aclCache:             structs.NewACLCache[*acl.ACL](aclCacheSize),
 		encrypter:            cfg.Encrypter,
+		consumeTokenUse:      cfg.ConsumeTokenUseFn,
 		validServerCertNames: []string{"server." + cfg.Region + ".nomad"},
 		validClientCertNames: []string{
 			"client." + cfg.Region + ".nomad",
@@ -117,10 +129,16 @@ func (s *Authenticator) Authenticate(ctx RPCContext, args structs.RequestWithIde
 	case err == nil:
 		// ACLs are enabled and we have a non-anonymous token, so set that as
 		// our identity and return
+		if err := s.consumeUse(aclToken, args); err != nil {
+			return err
+		}
 		args.SetIdentity(&structs.AuthenticatedIdentity{ACLToken: aclToken})
 		return nil
 
-	case errors.Is(err, structs.ErrTokenExpired):
+	case errors.Is(err, structs.ErrTokenExpired),
+		errors.Is(err, structs.ErrTokenPending),
+		errors.Is(err, structs.ErrTokenNotYetValid),
+		errors.Is(err, structs.ErrTokenExhausted):
 		return err
 
 	case errors.Is(err, structs.ErrTokenInvalid):
@@ -200,6 +218,29 @@ func (s *Authenticator) Authenticate(ctx RPCContext, args structs.RequestWithIde
 	return nil
 }
 
+// consumeUse records a use of tokens with a maximum number of uses. Requests
+// are only counted by the first server to authenticate them, and not once
+// more after being forwarded. Each use costs a round trip to the leader and a
+// Raft write, which isn't batched because the use must be committed before the
+// request is served for the limit to hold, so these tokens are only meant for
+// a handful of uses.
+func (s *Authenticator) consumeUse(aclToken *structs.ACLToken, args structs.RequestWithIdentity) error {
+	if aclToken.MaxUses == 0 || s.consumeTokenUse == nil {
+		return nil
+	}
+	if info, ok := args.(structs.RPCInfo); ok && info.IsForwarded() {
+		return nil
+	}
+
+	if err := s.consumeTokenUse(aclToken.AccessorID); err != nil {
+		if structs.IsErrTokenExhausted(err) {
+			return structs.ErrTokenExhausted
+		}
+		return fmt.Errorf("could not record token use: %w", err)
+	}
+	return nil
+}
+
 // ResolveACL is an authentication wrapper which handles resolving ACL tokens,
 // Workload Identities, or client secrets into acl.ACL objects. Exclusively
 // server-to-server or client-to-server requests should be using
@@ -382,9 +423,11 @@ func validateCertificateForNames(cert *x509.Certificate, expectedNames []string)
 
 }
 
-// IdentityToACLClaim returns an ACLClaim suitable for checking permissions
+// IdentityToACLClaim returns an ACLClaim suitable for checking permissions.
+// Child identities are scoped to explicit capabilities so they never get the
+// implicit permissions of a claim.
 func IdentityToACLClaim(ai *structs.AuthenticatedIdentity, store *state.StateStore) *acl.ACLClaim {
-	if ai == nil || ai.Claims == nil {
+	if ai == nil || ai.Claims == nil || ai.Claims.Scope != nil {
 		return nil
 	}
 
@@ -447,6 +490,27 @@ func (s *Authenticator) ResolveToken(secretID string) (*acl.ACL, error) {
 	return resolveTokenFromSnapshotCache(snap, s.aclCache, secretID)
 }
 
+// ResolveIdentity resolves the identity of a bearer token, either an ACL
+// token's secret or a workload identity, from the local state. Unlike
+// Authenticate it doesn't consume a use of the token, so it can be used to
+// record who made a request without counting against the token's limits.
+func (s *Authenticator) ResolveIdentity(bearerToken string) (*structs.AuthenticatedIdentity, error) {
+	aclToken, err := s.resolveSecretToken(bearerToken)
+	switch {
+	case err == nil:
+		return &structs.AuthenticatedIdentity{ACLToken: aclToken}, nil
+	case errors.Is(err, structs.ErrTokenInvalid):
+		// if it's not a UUID it might be an identity claim
+		claims, err := s.VerifyClaim(bearerToken)
+		if err != nil {
+			return nil, err
+		}
+		return &structs.AuthenticatedIdentity{Claims: claims}, nil
+	default:
+		return nil, err
+	}
+}
+
 // VerifyClaim asserts that the token is valid and that the resulting allocation
 // ID belongs to a non-terminal allocation. This should usually not be called by
 // RPC handlers, and exists only to support the ACL.WhoAmI endpoint.
@@ -511,8 +575,8 @@ func resolveTokenFromSnapshotCache(snap *state.StateSnapshot, cache *structs.ACL
 		if token == nil {
 			return nil, structs.ErrTokenNotFound
 		}
-		if token.IsExpired(time.Now().UTC()) {
-			return nil, structs.ErrTokenExpired
+		if err := token.Usable(time.Now().UTC()); err != nil {
+			return nil, err
 		}
 	}
 
@@ -619,8 +683,8 @@ func (s *Authenticator) resolveSecretToken(secretID string) (*structs.ACLToken,
 	if token == nil {
 		return nil, structs.ErrTokenNotFound
 	}
-	if token.IsExpired(time.Now().UTC()) {
-		return nil, structs.ErrTokenExpired
+	if err := token.Usable(time.Now().UTC()); err != nil {
+		return nil, err
 	}
 
 	return token, nil
@@ -672,5 +736,47 @@ func (s *Authenticator) ResolvePoliciesForClaims(claims *structs.IdentityClaims)
 		}
 	}
 
+	if claims.Scope != nil {
+		return s.scopePolicies(claims, policies)
+	}
+
 	return policies, nil
 }
+
+// scopePolicies restricts the policies of a workload to the scope of a child
+// identity. The result is a single policy granting the scoped namespace
+// capabilities that the workload's own policies also grant.
+func (s *Authenticator) scopePolicies(claims *structs.IdentityClaims, policies []*structs.ACLPolicy) ([]*structs.ACLPolicy, error) {
+	aclObj, err := structs.CompileACLObject(s.aclCache, policies)
+	if err != nil {
+		return nil, err
+	}
+
+	var modifyIndex uint64
+	for _, policy := range policies {
+		modifyIndex = max(modifyIndex, policy.ModifyIndex)
+	}
+
+	scope := &structs.IdentityScope{}
+	for _, capability := range claims.Scope.Capabilities {
+		if aclObj.AllowNamespaceOperation(claims.Namespace, capability) {
+			scope.Capabilities = append(scope.Capabilities, capability)
+		}
+	}
+	if len(scope.Capabilities) == 0 {
+		return []*structs.ACLPolicy{}, nil
+	}
+	slices.Sort(scope.Capabilities)
+
+	// The name must be unique per scope as it is used along with the modify
+	// index to cache compiled ACL objects.
+	policy := &structs.ACLPolicy{
+		Name: fmt.Sprintf("child-identity:%s:%s",
+			claims.Namespace, strings.Join(scope.Capabilities, ",")),
+		Rules:       scope.PolicyRules(claims.Namespace),
+		CreateIndex: modifyIndex,
+		ModifyIndex: modifyIndex,
+	}
+	policy.SetHash()
+	return []*structs.ACLPolicy{policy}, nil
+}
@@ -1153,6 +1153,65 @@ func TestResolveClaims(t *testing.T) {
 
 }
 
+func TestResolveClaims_Scoped(t *testing.T) {
+	ci.Parallel(t)
+
+	auth := testDefaultAuthenticator(t)
+
+	alloc := mock.Alloc()
+	must.NoError(t, auth.getState().UpsertAllocs(
+		structs.MsgTypeTestSetup, 100, []*structs.Allocation{alloc}))
+
+	policy := mock.ACLPolicy()
+	policy.Rules = `namespace "default" { capabilities = ["read-job", "list-jobs"] }`
+	policy.JobACL = &structs.JobACL{
+		Namespace: alloc.Namespace,
+		JobID:     alloc.JobID,
+	}
+	policy.SetHash()
+	must.NoError(t, auth.getState().UpsertACLPolicies(
+		structs.MsgTypeTestSetup, 101, []*structs.ACLPolicy{policy}))
+
+	claims := &structs.IdentityClaims{
+		Namespace:    alloc.Namespace,
+		JobID:        alloc.JobID,
+		AllocationID: alloc.ID,
+		TaskName:     alloc.Job.TaskGroups[0].Tasks[0].Name,
+		Scope: &structs.IdentityScope{
+			Capabilities: []string{"read-job", "submit-job"},
+		},
+	}
+
+	// Only the capabilities in both the scope and the workload's policies
+	// are granted
+	policies, err := auth.ResolvePoliciesForClaims(claims)
+	must.NoError(t, err)
+	must.Len(t, 1, policies)
+	must.Eq(t, 101, policies[0].ModifyIndex)
+
+	aclObj, err := auth.resolveClaims(claims)
+	must.NoError(t, err)
+	must.True(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityReadJob))
+	must.False(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityListJobs))
+	must.False(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilitySubmitJob))
+
+	// An empty scope grants no capabilities
+	claims.Scope.Capabilities = nil
+	policies, err = auth.ResolvePoliciesForClaims(claims)
+	must.NoError(t, err)
+	must.Len(t, 0, policies)
+
+	aclObj, err = auth.resolveClaims(claims)
+	must.NoError(t, err)
+	must.False(t, aclObj.AllowNamespaceOperation("default", acl.NamespaceCapabilityReadJob))
+
+	// Scoped claims never get the implicit permissions of a workload
+	ident := &structs.AuthenticatedIdentity{Claims: claims}
+	must.Nil(t, IdentityToACLClaim(ident, auth.getState()))
+	claims.Scope = nil
+	must.NotNil(t, IdentityToACLClaim(ident, auth.getState()))
+}
+
 func testStateStore(t *testing.T) *state.StateStore {
 	sconfig := &state.StateStoreConfig{
 		Logger:             testlog.HCLogger(t),
@@ -23,6 +23,10 @@ const (
 	// AutopilotRZTag is the Serf tag to use for the custom version value
 	// when passing the server metadata to Autopilot.
 	AutopilotVersionTag = "ap_version"
+
+	// ReadReplicaTag is the Serf tag marking a server as a read replica, which
+	// autopilot must never promote to a voter.
+	ReadReplicaTag = "read_replica"
 )
 
 // AutopilotDelegate is a Nomad delegate for autopilot operations. It implements
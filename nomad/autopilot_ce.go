@@ -7,13 +7,35 @@
 package nomad
 
 import (
+	"slices"
+
+	"github.com/hashicorp/raft"
 	autopilot "github.com/hashicorp/raft-autopilot"
 
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 func (s *Server) autopilotPromoter() autopilot.Promoter {
-	return autopilot.DefaultPromoter()
+	return new(readReplicaPromoter)
+}
+
+// readReplicaPromoter promotes stable non-voting servers to voters like the
+// default promoter, except for read replicas which always remain non-voters.
+type readReplicaPromoter struct {
+	autopilot.StablePromoter
+}
+
+func (p *readReplicaPromoter) CalculatePromotionsAndDemotions(c *autopilot.Config, s *autopilot.State) autopilot.RaftChanges {
+	changes := p.StablePromoter.CalculatePromotionsAndDemotions(c, s)
+	changes.Promotions = slices.DeleteFunc(changes.Promotions, func(id raft.ServerID) bool {
+		srv, ok := s.Servers[id]
+		if !ok {
+			return false
+		}
+		_, readReplica := srv.Server.Meta[ReadReplicaTag]
+		return readReplica
+	})
+	return changes
 }
 
 // autopilotServerExt returns the autopilot-enterprise.Server extensions needed
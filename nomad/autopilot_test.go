@@ -308,3 +308,46 @@ func TestAutopilot_PromoteNonVoter(t *testing.T) {
 	}, func(err error) { must.NoError(t, err) })
 
 }
+
+func TestAutopilot_ReadReplicaNotPromoted(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // reduces test log noise
+		c.RaftConfig.ProtocolVersion = 3
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // reduces test log noise
+		c.BootstrapExpect = 0
+		c.RaftConfig.ProtocolVersion = 3
+		c.ReadReplica = true
+	})
+	defer cleanupS2()
+	TestJoin(t, s1, s2)
+
+	testutil.WaitForResultUntil(10*time.Second, func() (bool, error) {
+		future := s1.raft.GetConfiguration()
+		if err := future.Error(); err != nil {
+			return false, err
+		}
+		servers := future.Configuration().Servers
+		if len(servers) != 2 {
+			return false, fmt.Errorf("expected 2 servers, got: %v", servers)
+		}
+		return true, nil
+	}, func(err error) { must.NoError(t, err) })
+
+	// The read replica stays a non-voter well past the stabilization time
+	// after which autopilot promotes non-voters
+	time.Sleep(10 * s1.config.AutopilotConfig.ServerStabilizationTime)
+	future := s1.raft.GetConfiguration()
+	must.NoError(t, future.Error())
+	for _, server := range future.Configuration().Servers {
+		if server.ID == raft.ServerID(s2.config.NodeID) {
+			must.Eq(t, raft.Nonvoter, server.Suffrage)
+		}
+	}
+}
@@ -0,0 +1,380 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// builtinAutoscalerInterval is how often the leader looks for scaling
+	// policies that are due for evaluation by the built-in autoscaler
+	builtinAutoscalerInterval = 10 * time.Second
+
+	// builtinAutoscalerTolerance is the relative deviation from the target
+	// utilization within which the built-in autoscaler doesn't scale, which
+	// prevents the count from flapping around the target
+	builtinAutoscalerTolerance = 0.1
+)
+
+// builtinAutoscaler evaluates the horizontal scaling policies that set a
+// target CPU or memory utilization, and scales their task groups so the
+// utilization of their running allocations approaches the target. It also
+// applies the scaling schedules of the policies. It runs on the leader only.
+type builtinAutoscaler struct {
+	srv    *Server
+	logger log.Logger
+
+	// lastEvaluated is the time each policy was last evaluated, by policy ID
+	lastEvaluated map[string]time.Time
+
+	// allocStats and scale are the RPCs used to read the resource usage of
+	// an allocation and to scale a task group, which tests replace
+	allocStats func(*structs.Allocation) (*cstructs.AllocResourceUsage, error)
+	scale      func(*structs.JobScaleRequest) error
+}
+
+func newBuiltinAutoscaler(srv *Server) *builtinAutoscaler {
+	a := &builtinAutoscaler{
+		srv:           srv,
+		logger:        srv.logger.Named("autoscaler"),
+		lastEvaluated: map[string]time.Time{},
+	}
+	a.allocStats = a.allocStatsRPC
+	a.scale = a.scaleRPC
+	return a
+}
+
+// runBuiltinAutoscaler periodically evaluates the scaling policies of the
+// built-in autoscaler until the leadership is lost.
+func (s *Server) runBuiltinAutoscaler(stopCh chan struct{}) {
+	a := newBuiltinAutoscaler(s)
+	ticker := time.NewTicker(builtinAutoscalerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			a.evaluate(time.Now())
+		}
+	}
+}
+
+// evaluate evaluates every enabled policy of the built-in autoscaler whose
+// evaluation interval has elapsed.
+func (a *builtinAutoscaler) evaluate(now time.Time) {
+	snap, err := a.srv.State().Snapshot()
+	if err != nil {
+		a.logger.Error("failed to get state", "error", err)
+		return
+	}
+	iter, err := snap.ScalingPoliciesByTypePrefix(nil, structs.ScalingPolicyTypeHorizontal)
+	if err != nil {
+		a.logger.Error("failed to list scaling policies", "error", err)
+		return
+	}
+
+	seen := map[string]struct{}{}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		policy := raw.(*structs.ScalingPolicy)
+		if !policy.Enabled {
+			continue
+		}
+
+		if len(policy.Schedules) > 0 {
+			scaled, err := a.evaluateSchedules(snap, policy, now)
+			if err != nil {
+				a.logger.Warn("failed to evaluate scaling schedules", "policy_id", policy.ID,
+					"namespace", policy.Target[structs.ScalingTargetNamespace],
+					"job_id", policy.Target[structs.ScalingTargetJob],
+					"group", policy.Target[structs.ScalingTargetGroup], "error", err)
+			}
+			if scaled {
+				continue
+			}
+		}
+
+		config, err := policy.BuiltinPolicy()
+		if err != nil {
+			a.logger.Warn("invalid scaling policy", "policy_id", policy.ID, "error", err)
+			continue
+		}
+		if config == nil {
+			continue
+		}
+
+		seen[policy.ID] = struct{}{}
+		if now.Sub(a.lastEvaluated[policy.ID]) < config.EvaluationInterval {
+			continue
+		}
+		a.lastEvaluated[policy.ID] = now
+
+		if err := a.evaluatePolicy(snap, policy, config, now); err != nil {
+			a.logger.Warn("failed to evaluate scaling policy", "policy_id", policy.ID,
+				"namespace", policy.Target[structs.ScalingTargetNamespace],
+				"job_id", policy.Target[structs.ScalingTargetJob],
+				"group", policy.Target[structs.ScalingTargetGroup], "error", err)
+		}
+	}
+
+	// Forget the policies that were deleted
+	for id := range a.lastEvaluated {
+		if _, ok := seen[id]; !ok {
+			delete(a.lastEvaluated, id)
+		}
+	}
+}
+
+// evaluatePolicy computes the count of the policy's task group that brings
+// the utilization of its allocations to the target, and scales the group if
+// the count differs and the cooldown since the last scaling event elapsed.
+func (a *builtinAutoscaler) evaluatePolicy(snap *state.StateSnapshot, policy *structs.ScalingPolicy,
+	config *structs.BuiltinScalingPolicy, now time.Time) error {
+
+	namespace := policy.Target[structs.ScalingTargetNamespace]
+	jobID := policy.Target[structs.ScalingTargetJob]
+	group := policy.Target[structs.ScalingTargetGroup]
+
+	job, err := snap.JobByID(nil, namespace, jobID)
+	if err != nil {
+		return err
+	}
+	if job == nil || job.Stop {
+		return nil
+	}
+	tg := job.LookupTaskGroup(group)
+	if tg == nil {
+		return nil
+	}
+
+	events, _, err := snap.ScalingEventsByJob(nil, namespace, jobID)
+	if err != nil {
+		return err
+	}
+	if last := events[group]; len(last) > 0 && now.Sub(time.Unix(0, last[0].Time)) < config.Cooldown {
+		return nil
+	}
+
+	allocs, err := snap.AllocsByJob(nil, namespace, jobID, false)
+	if err != nil {
+		return err
+	}
+
+	var cpuUsed, cpuAllocated, memoryUsed, memoryAllocated float64
+	var sampled int
+	for _, alloc := range allocs {
+		if alloc.TaskGroup != group || alloc.ClientStatus != structs.AllocClientStatusRunning ||
+			alloc.AllocatedResources == nil {
+			continue
+		}
+		usage, err := a.allocStats(alloc)
+		if err != nil {
+			a.logger.Debug("failed to get allocation stats", "alloc_id", alloc.ID, "error", err)
+			continue
+		}
+		if usage == nil || usage.ResourceUsage == nil {
+			continue
+		}
+		if cpu := usage.ResourceUsage.CpuStats; cpu != nil {
+			cpuUsed += cpu.TotalTicks
+		}
+		if memory := usage.ResourceUsage.MemoryStats; memory != nil {
+			used := memory.RSS
+			if used == 0 {
+				used = memory.Usage
+			}
+			memoryUsed += float64(used)
+		}
+		for _, task := range alloc.AllocatedResources.Tasks {
+			cpuAllocated += float64(task.Cpu.CpuShares)
+			memoryAllocated += float64(task.Memory.MemoryMB) * 1024 * 1024
+		}
+		sampled++
+	}
+	if sampled == 0 {
+		return nil
+	}
+
+	count := int64(tg.Count)
+	desired := int64(-1)
+	meta := map[string]interface{}{}
+	if config.TargetCPU > 0 && cpuAllocated > 0 {
+		utilization := cpuUsed / cpuAllocated * 100
+		meta["cpu_utilization"] = math.Round(utilization*100) / 100
+		desired = max(desired, builtinAutoscalerCount(count, utilization, config.TargetCPU))
+	}
+	if config.TargetMemory > 0 && memoryAllocated > 0 {
+		utilization := memoryUsed / memoryAllocated * 100
+		meta["memory_utilization"] = math.Round(utilization*100) / 100
+		desired = max(desired, builtinAutoscalerCount(count, utilization, config.TargetMemory))
+	}
+	if desired < 0 {
+		return nil
+	}
+	desired = min(max(desired, policy.Min), policy.Max)
+	if desired == count {
+		return nil
+	}
+
+	metrics.IncrCounterWithLabels([]string{"nomad", "autoscaler", "scale"}, 1, []metrics.Label{
+		{Name: "namespace", Value: namespace},
+		{Name: "job", Value: jobID},
+		{Name: "task_group", Value: group},
+	})
+	a.logger.Info("scaling task group", "namespace", namespace, "job_id", jobID,
+		"group", group, "count", count, "desired", desired)
+
+	meta["policy_id"] = policy.ID
+	return a.scale(&structs.JobScaleRequest{
+		JobID: jobID,
+		Target: map[string]string{
+			structs.ScalingTargetNamespace: namespace,
+			structs.ScalingTargetJob:       jobID,
+			structs.ScalingTargetGroup:     group,
+		},
+		Count:   &desired,
+		Message: fmt.Sprintf("scaled from %d to %d by the built-in autoscaler", count, desired),
+		Meta:    meta,
+		WriteRequest: structs.WriteRequest{
+			Region:    a.srv.Region(),
+			Namespace: namespace,
+		},
+	})
+}
+
+// evaluateSchedules scales the policy's task group to the count of the
+// schedule that matched most recently, if it matched after the job was last
+// submitted or scaled. When several schedules match at the same time the
+// largest count wins. It returns whether the group was scaled.
+func (a *builtinAutoscaler) evaluateSchedules(snap *state.StateSnapshot, policy *structs.ScalingPolicy,
+	now time.Time) (bool, error) {
+
+	namespace := policy.Target[structs.ScalingTargetNamespace]
+	jobID := policy.Target[structs.ScalingTargetJob]
+	group := policy.Target[structs.ScalingTargetGroup]
+
+	job, err := snap.JobByID(nil, namespace, jobID)
+	if err != nil {
+		return false, err
+	}
+	if job == nil || job.Stop {
+		return false, nil
+	}
+	tg := job.LookupTaskGroup(group)
+	if tg == nil {
+		return false, nil
+	}
+
+	// Schedules that matched before the last change of the job or scaling
+	// event of the group were either applied already or overridden
+	since := time.Unix(0, job.SubmitTime)
+	events, _, err := snap.ScalingEventsByJob(nil, namespace, jobID)
+	if err != nil {
+		return false, err
+	}
+	if last := events[group]; len(last) > 0 {
+		if t := time.Unix(0, last[0].Time); t.After(since) {
+			since = t
+		}
+	}
+
+	var active *structs.ScalingSchedule
+	var activeTime time.Time
+	for _, schedule := range policy.Schedules {
+		t, err := schedule.LastMatch(since, now)
+		if err != nil {
+			return false, err
+		}
+		if t.IsZero() {
+			continue
+		}
+		if active == nil || t.After(activeTime) ||
+			(t.Equal(activeTime) && schedule.Count > active.Count) {
+			active = schedule
+			activeTime = t
+		}
+	}
+	if active == nil {
+		return false, nil
+	}
+
+	count := int64(tg.Count)
+	desired := min(max(active.Count, policy.Min), policy.Max)
+	if desired == count {
+		return false, nil
+	}
+
+	metrics.IncrCounterWithLabels([]string{"nomad", "autoscaler", "scale"}, 1, []metrics.Label{
+		{Name: "namespace", Value: namespace},
+		{Name: "job", Value: jobID},
+		{Name: "task_group", Value: group},
+	})
+	a.logger.Info("scaling task group on schedule", "namespace", namespace, "job_id", jobID,
+		"group", group, "count", count, "desired", desired, "cron", active.Cron)
+
+	err = a.scale(&structs.JobScaleRequest{
+		JobID: jobID,
+		Target: map[string]string{
+			structs.ScalingTargetNamespace: namespace,
+			structs.ScalingTargetJob:       jobID,
+			structs.ScalingTargetGroup:     group,
+		},
+		Count:   &desired,
+		Message: fmt.Sprintf("scaled from %d to %d by the scaling schedule %q", count, desired, active.Cron),
+		Meta: map[string]interface{}{
+			"policy_id":     policy.ID,
+			"schedule":      active.Cron,
+			"schedule_time": activeTime.Format(time.RFC3339),
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    a.srv.Region(),
+			Namespace: namespace,
+		},
+	})
+	return err == nil, err
+}
+
+// builtinAutoscalerCount returns the count that brings the utilization to the
+// target, or the current count if the utilization is within the tolerance of
+// the target.
+func builtinAutoscalerCount(count int64, utilization, target float64) int64 {
+	factor := utilization / target
+	if math.Abs(factor-1) <= builtinAutoscalerTolerance {
+		return count
+	}
+	return int64(math.Ceil(float64(count) * factor))
+}
+
+func (a *builtinAutoscaler) allocStatsRPC(alloc *structs.Allocation) (*cstructs.AllocResourceUsage, error) {
+	req := &cstructs.AllocStatsRequest{
+		AllocID: alloc.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    a.srv.Region(),
+			Namespace: alloc.Namespace,
+			AuthToken: a.srv.getLeaderAcl(),
+		},
+	}
+	var resp cstructs.AllocStatsResponse
+	if err := a.srv.RPC("ClientAllocations.Stats", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Stats, nil
+}
+
+func (a *builtinAutoscaler) scaleRPC(req *structs.JobScaleRequest) error {
+	req.AuthToken = a.srv.getLeaderAcl()
+	var resp structs.JobRegisterResponse
+	return a.srv.RPC("Job.Scale", req, &resp)
+}
@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+
+	"github.com/hashicorp/nomad/ci"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+)
+
+func TestBuiltinAutoscaler_Evaluate(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	job, policy := mock.JobWithScalingPolicy()
+	policy.Min = 1
+	policy.Max = 15
+	policy.Policy = map[string]interface{}{
+		structs.ScalingPolicyTargetCPU:          50,
+		structs.ScalingPolicyCooldown:           "10m",
+		structs.ScalingPolicyEvaluationInterval: "1m",
+	}
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+
+	allocs := []*structs.Allocation{mock.Alloc(), mock.Alloc(), mock.Alloc()}
+	for _, alloc := range allocs {
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.ClientStatus = structs.AllocClientStatusRunning
+	}
+	allocs[2].ClientStatus = structs.AllocClientStatusFailed
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1001, allocs))
+
+	// Each allocation has 500 MHz of CPU allocated
+	var cpuTicks float64
+	var requested []string
+	var scaled *structs.JobScaleRequest
+	a := newBuiltinAutoscaler(s1)
+	a.allocStats = func(alloc *structs.Allocation) (*cstructs.AllocResourceUsage, error) {
+		requested = append(requested, alloc.ID)
+		return &cstructs.AllocResourceUsage{
+			ResourceUsage: &cstructs.ResourceUsage{
+				CpuStats: &cstructs.CpuStats{TotalTicks: cpuTicks},
+			},
+		}, nil
+	}
+	a.scale = func(req *structs.JobScaleRequest) error {
+		scaled = req
+		return nil
+	}
+
+	// A utilization of 80% for a target of 50% scales the group from 10 to
+	// 16 allocations, which is capped by the maximum. Only the running
+	// allocations are considered.
+	now := time.Now()
+	cpuTicks = 400
+	a.evaluate(now)
+	must.SliceContainsAll(t, []string{allocs[0].ID, allocs[1].ID}, requested)
+	must.NotNil(t, scaled)
+	must.Eq(t, 15, *scaled.Count)
+	must.Eq(t, job.TaskGroups[0].Name, scaled.Target[structs.ScalingTargetGroup])
+	must.Eq(t, 80.0, scaled.Meta["cpu_utilization"])
+
+	// The policy isn't evaluated again before its evaluation interval
+	scaled = nil
+	a.evaluate(now.Add(30 * time.Second))
+	must.Nil(t, scaled)
+
+	// A utilization within the tolerance of the target doesn't scale
+	cpuTicks = 260
+	a.evaluate(now.Add(time.Minute))
+	must.Nil(t, scaled)
+
+	// A low utilization scales in
+	cpuTicks = 100
+	a.evaluate(now.Add(2 * time.Minute))
+	must.NotNil(t, scaled)
+	must.Eq(t, 4, *scaled.Count)
+
+	// A recent scaling event of the group prevents scaling
+	scaled = nil
+	must.NoError(t, store.UpsertScalingEvent(1002, &structs.ScalingEventRequest{
+		Namespace:    job.Namespace,
+		JobID:        job.ID,
+		TaskGroup:    job.TaskGroups[0].Name,
+		ScalingEvent: &structs.ScalingEvent{Time: now.Add(3 * time.Minute).UnixNano()},
+	}))
+	a.evaluate(now.Add(4 * time.Minute))
+	must.Nil(t, scaled)
+
+	// The group scales again once the cooldown elapsed
+	a.evaluate(now.Add(14 * time.Minute))
+	must.NotNil(t, scaled)
+}
+
+func TestBuiltinAutoscaler_Schedules(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	start := time.Date(2024, 3, 4, 6, 0, 0, 0, time.UTC)
+	job, policy := mock.JobWithScalingPolicy()
+	job.SubmitTime = start.UnixNano()
+	policy.Min = 1
+	policy.Max = 12
+	policy.Schedules = []*structs.ScalingSchedule{
+		{Cron: "0 8 * * *", Count: 15},
+		{Cron: "0 20 * * *", Count: 3},
+		{Cron: "0 21 * * *", Count: 5, TimeZone: "Europe/Berlin"},
+	}
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+
+	var scaled *structs.JobScaleRequest
+	a := newBuiltinAutoscaler(s1)
+	a.scale = func(req *structs.JobScaleRequest) error {
+		scaled = req
+		return nil
+	}
+
+	// No schedule matched since the job was submitted
+	a.evaluate(start.Add(time.Hour))
+	must.Nil(t, scaled)
+
+	// The count of a schedule is capped by the maximum
+	a.evaluate(start.Add(2*time.Hour + time.Minute))
+	must.NotNil(t, scaled)
+	must.Eq(t, 12, *scaled.Count)
+	must.Eq(t, "0 8 * * *", scaled.Meta["schedule"])
+
+	// A scaling event after the schedule matched prevents applying it again
+	scaled = nil
+	must.NoError(t, store.UpsertScalingEvent(1001, &structs.ScalingEventRequest{
+		Namespace:    job.Namespace,
+		JobID:        job.ID,
+		TaskGroup:    job.TaskGroups[0].Name,
+		ScalingEvent: &structs.ScalingEvent{Time: start.Add(2*time.Hour + time.Minute).UnixNano()},
+	}))
+	a.evaluate(start.Add(3 * time.Hour))
+	must.Nil(t, scaled)
+
+	// Schedules matching at the same time scale to the largest count
+	a.evaluate(start.Add(14*time.Hour + time.Minute))
+	must.NotNil(t, scaled)
+	must.Eq(t, 5, *scaled.Count)
+	must.Eq(t, "0 21 * * *", scaled.Meta["schedule"])
+}
+
+func TestBuiltinAutoscalerCount(t *testing.T) {
+	ci.Parallel(t)
+
+	must.Eq(t, 10, builtinAutoscalerCount(10, 52, 50))
+	must.Eq(t, 10, builtinAutoscalerCount(10, 46, 50))
+	must.Eq(t, 16, builtinAutoscalerCount(10, 80, 50))
+	must.Eq(t, 3, builtinAutoscalerCount(10, 11, 50))
+	must.Eq(t, 0, builtinAutoscalerCount(10, 0, 50))
+}
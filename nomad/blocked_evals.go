@@ -231,6 +231,28 @@ func (b *BlockedEvals) processBlock(eval *structs.Evaluation, token string) {
 	b.captured[eval.ID] = wrapped
 }
 
+// UpdatePriority replaces a blocked evaluation with the passed copy of it with
+// a different priority, so it is enqueued with its new priority once
+// unblocked.
+func (b *BlockedEvals) UpdatePriority(eval *structs.Evaluation) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	if !b.enabled {
+		return
+	}
+
+	if w, ok := b.captured[eval.ID]; ok {
+		b.captured[eval.ID] = wrappedEval{eval: eval, token: w.token}
+	} else if w, ok := b.escaped[eval.ID]; ok {
+		b.escaped[eval.ID] = wrappedEval{eval: eval, token: w.token}
+	}
+
+	if w, ok := b.system.Get(eval.ID); ok {
+		w.eval = eval
+	}
+}
+
 // processBlockJobDuplicate handles the case where the new eval is for a job
 // that we are already tracking. If the eval is a duplicate, we add the older
 // evaluation by Raft index to the list of duplicates such that it can be
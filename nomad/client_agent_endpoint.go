@@ -481,6 +481,69 @@ func (a *Agent) Host(args *structs.HostDataRequest, reply *structs.HostDataRespo
 	return nil
 }
 
+// FlightRecorder dumps the flight recorder of the agent for the `debug`
+// command.
+func (a *Agent) FlightRecorder(args *structs.FlightRecorderRequest, reply *structs.FlightRecorderResponse) error {
+	authErr := a.srv.Authenticate(nil, args)
+	a.srv.MeasureRPCRate("agent", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	aclObj, err := a.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !aclObj.AllowAgentDebug(a.srv.GetConfig().EnableDebug) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Forward to different region if necessary, as in Host.
+	region := args.RequestRegion()
+	if region == "" {
+		return fmt.Errorf("missing target RPC")
+	}
+
+	if region != a.srv.config.Region {
+		// Mark that we are forwarding
+		args.SetForwarded()
+		return a.srv.forwardRegion(region, "Agent.FlightRecorder", args, reply)
+	}
+
+	// Targeting a client node, forward request to node
+	if args.NodeID != "" {
+		client, srv, err := a.findClientConn(args.NodeID)
+		if err != nil {
+			return err
+		}
+
+		if srv != nil {
+			return a.srv.forwardServer(srv, "Agent.FlightRecorder", args, reply)
+		}
+
+		return NodeRpc(client.Session, "Agent.FlightRecorder", args, reply)
+	}
+
+	// Handle serverID not equal to ours
+	if args.ServerID != "" {
+		srv, err := a.forwardFor(args.ServerID, region)
+		if err != nil {
+			return err
+		}
+		if srv != nil {
+			return a.srv.forwardServer(srv, "Agent.FlightRecorder", args, reply)
+		}
+	}
+
+	dump, err := a.srv.config.FlightRecorder.Dump("requested")
+	if err != nil {
+		return structs.NewErrRPCCoded(404, err.Error())
+	}
+
+	reply.AgentID = a.srv.serf.LocalMember().Name
+	reply.Dump = dump
+	return nil
+}
+
 // findClientConn is a helper that returns a connection to the client node or, if the client
 // is connected to a different server, a serverParts describing the server to which the
 // client bound RPC should be forwarded.
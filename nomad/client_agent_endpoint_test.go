@@ -21,7 +21,9 @@ import (
 	"github.com/hashicorp/nomad/client/config"
 	sframer "github.com/hashicorp/nomad/client/lib/streamframer"
 	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/command/agent/flightrecorder"
 	"github.com/hashicorp/nomad/command/agent/pprof"
+	"github.com/hashicorp/nomad/helper/testlog"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -1023,3 +1025,58 @@ func TestAgentHost_ACLDebugRequired(t *testing.T) {
 	err := s.RPC("Agent.Host", &req, &resp)
 	must.EqError(t, err, structs.ErrPermissionDenied.Error())
 }
+
+func TestAgentFlightRecorder(t *testing.T) {
+	ci.Parallel(t)
+
+	recorder := flightrecorder.NewRecorder(testlog.HCLogger(t), &flightrecorder.Config{
+		Capacity:          16,
+		GoroutineInterval: time.Hour,
+	})
+	defer recorder.Shutdown()
+
+	s, cleanupS := TestServer(t, func(c *Config) {
+		c.EnableDebug = true
+		c.FlightRecorder = recorder
+	})
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	req := structs.FlightRecorderRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var resp structs.FlightRecorderResponse
+
+	// The server records acquiring the leadership once the leader loop
+	// notices it.
+	testutil.WaitForResult(func() (bool, error) {
+		if err := s.RPC("Agent.FlightRecorder", &req, &resp); err != nil {
+			return false, err
+		}
+		if len(resp.Dump.Leadership) == 0 {
+			return false, fmt.Errorf("expected leadership events")
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	must.Eq(t, s.serf.LocalMember().Name, resp.AgentID)
+	must.True(t, resp.Dump.Leadership[0].Leader)
+
+	// The RPCs made while waiting for the leader are recorded.
+	var methods []string
+	for _, sample := range resp.Dump.RPCs {
+		methods = append(methods, sample.Method)
+	}
+	must.SliceContains(t, methods, "Status.Leader")
+
+	// Servers without a flight recorder can't be dumped.
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.EnableDebug = true
+	})
+	defer cleanupS2()
+	testutil.WaitForLeader(t, s2.RPC)
+
+	err := s2.RPC("Agent.FlightRecorder", &req, &resp)
+	must.ErrorContains(t, err, "flight recorder is not enabled")
+}
@@ -1309,6 +1309,52 @@ OUTER:
 	}
 }
 
+func TestAlloc_PortForward_TerminalAlloc(t *testing.T) {
+	ci.Parallel(t)
+
+	// Start a Nomad server.
+	s, cleanupS := TestServer(t, nil)
+	defer cleanupS()
+	testutil.WaitForLeader(t, s.RPC)
+
+	// Create an alloc with terminal status.
+	alloc := mock.Alloc()
+	alloc.ClientStatus = nstructs.AllocClientStatusComplete
+
+	// Upsert the job and allocation.
+	state := s.State()
+	must.NoError(t, state.UpsertJob(nstructs.MsgTypeTestSetup, 999, nil, alloc.Job))
+	must.NoError(t, state.UpsertAllocs(nstructs.MsgTypeTestSetup, 1003, []*nstructs.Allocation{alloc}))
+
+	// Make the port forward request.
+	req := &cstructs.AllocPortForwardRequest{
+		AllocID:      alloc.ID,
+		Port:         8080,
+		QueryOptions: nstructs.QueryOptions{Region: "global"},
+	}
+
+	// Get the handler.
+	handler, err := s.StreamingRpcHandler("Allocations.PortForward")
+	must.NoError(t, err)
+
+	// Create a pipe.
+	p1, p2 := net.Pipe()
+	defer p1.Close()
+	defer p2.Close()
+
+	// Start the handler and send the request.
+	go handler(p2)
+	encoder := codec.NewEncoder(p1, nstructs.MsgpackHandle)
+	must.NoError(t, encoder.Encode(req))
+
+	p1.SetReadDeadline(time.Now().Add(3 * time.Second))
+	decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
+	var msg cstructs.StreamErrWrapper
+	must.NoError(t, decoder.Decode(&msg))
+	must.NotNil(t, msg.Error)
+	must.ErrorContains(t, msg.Error, "port forward not possible")
+}
+
 func decodeFrames(t *testing.T, p1 net.Conn, frames chan<- *drivers.ExecTaskStreamingResponseMsg, errCh chan<- error) {
 	// Start the decoder
 	decoder := codec.NewDecoder(p1, nstructs.MsgpackHandle)
@@ -35,6 +35,8 @@ func NewFileSystemEndpoint(srv *Server) *FileSystem {
 func (f *FileSystem) register() {
 	f.srv.streamingRpcs.Register("FileSystem.Logs", f.logs)
 	f.srv.streamingRpcs.Register("FileSystem.Stream", f.stream)
+	f.srv.streamingRpcs.Register("FileSystem.Archive", f.archive)
+	f.srv.streamingRpcs.Register("FileSystem.Extract", f.extract)
 }
 
 // handleStreamResultError is a helper for sending an error with a potential
@@ -137,12 +139,12 @@ func (f *FileSystem) List(args *cstructs.FsListRequest, reply *cstructs.FsListRe
 		return err
 	}
 
-	// Check namespace filesystem read permissions
-	allowNsOp := acl.NamespaceValidator(acl.NamespaceCapabilityReadFS)
+	// Check filesystem read permissions for the namespace or the task of the
+	// path
 	aclObj, err := f.srv.ResolveACL(args)
 	if err != nil {
 		return err
-	} else if !allowNsOp(aclObj, alloc.Namespace) {
+	} else if !aclObj.AllowJobTaskOperation(alloc.Namespace, alloc.JobID, alloc.TaskForPath(args.Path), acl.NamespaceCapabilityReadFS) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -197,10 +199,11 @@ func (f *FileSystem) Stat(args *cstructs.FsStatRequest, reply *cstructs.FsStatRe
 		return err
 	}
 
-	// Check filesystem read permissions
+	// Check filesystem read permissions for the namespace or the task of the
+	// path
 	if aclObj, err := f.srv.ResolveACL(args); err != nil {
 		return err
-	} else if !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS) {
+	} else if !aclObj.AllowJobTaskOperation(alloc.Namespace, alloc.JobID, alloc.TaskForPath(args.Path), acl.NamespaceCapabilityReadFS) {
 		return structs.ErrPermissionDenied
 	}
 
@@ -273,11 +276,11 @@ func (f *FileSystem) stream(conn io.ReadWriteCloser) {
 		return
 	}
 
-	// Check namespace read-fs permissions.
+	// Check read-fs permissions for the namespace or the task of the path.
 	if aclObj, err := f.srv.ResolveACL(&args); err != nil {
 		handleStreamResultError(err, nil, encoder)
 		return
-	} else if !aclObj.AllowNsOp(alloc.Namespace, acl.NamespaceCapabilityReadFS) {
+	} else if !aclObj.AllowJobTaskOperation(alloc.Namespace, alloc.JobID, alloc.TaskForPath(args.Path), acl.NamespaceCapabilityReadFS) {
 		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 		return
 	}
@@ -398,14 +401,13 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 		return
 	}
 
-	// Check namespace read-logs *or* read-fs permissions.
-	allowNsOp := acl.NamespaceValidator(
-		acl.NamespaceCapabilityReadFS, acl.NamespaceCapabilityReadLogs)
+	// Check read-logs *or* read-fs permissions for the namespace or the task.
 	aclObj, err := f.srv.ResolveACL(&args)
 	if err != nil {
 		handleStreamResultError(err, nil, encoder)
 		return
-	} else if !allowNsOp(aclObj, alloc.Namespace) {
+	} else if !aclObj.AllowJobTaskOperation(alloc.Namespace, alloc.JobID, args.Task, acl.NamespaceCapabilityReadFS) &&
+		!aclObj.AllowJobTaskOperation(alloc.Namespace, alloc.JobID, args.Task, acl.NamespaceCapabilityReadLogs) {
 		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
 		return
 	}
@@ -473,3 +475,143 @@ func (f *FileSystem) logs(conn io.ReadWriteCloser) {
 
 	structs.Bridge(conn, clientConn)
 }
+
+// archive is used to stream a tar archive of a file or directory of an
+// allocation.
+func (f *FileSystem) archive(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"nomad", "file_system", "archive"}, time.Now())
+	f.archiveImpl(conn, "FileSystem.Archive", structs.RateMetricRead, acl.NamespaceCapabilityReadFS)
+}
+
+// extract is used to extract a tar archive into a directory of an
+// allocation.
+func (f *FileSystem) extract(conn io.ReadWriteCloser) {
+	defer metrics.MeasureSince([]string{"nomad", "file_system", "extract"}, time.Now())
+	f.archiveImpl(conn, "FileSystem.Extract", structs.RateMetricWrite, acl.NamespaceCapabilityWriteFS)
+}
+
+// archiveImpl forwards an archive or extract request to the client running
+// the allocation after checking the token has the given capability, and then
+// bridges the connections so the archive can flow in either direction.
+func (f *FileSystem) archiveImpl(conn io.ReadWriteCloser, method, rateMetric, capability string) {
+	defer conn.Close()
+
+	// Decode the arguments
+	var args cstructs.FsArchiveRequest
+	decoder := codec.NewDecoder(conn, structs.MsgpackHandle)
+	encoder := codec.NewEncoder(conn, structs.MsgpackHandle)
+
+	if err := decoder.Decode(&args); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(500)), encoder)
+		return
+	}
+
+	authErr := f.srv.Authenticate(nil, &args)
+
+	// Check if we need to forward to a different region
+	if r := args.RequestRegion(); r != f.srv.Region() {
+		forwardRegionStreamingRpc(f.srv, conn, encoder, &args, method,
+			args.AllocID, &args.QueryOptions)
+		return
+	}
+	f.srv.MeasureRPCRate("file_system", rateMetric, &args)
+	if authErr != nil {
+		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		return
+	}
+
+	// Verify the arguments.
+	if args.AllocID == "" {
+		handleStreamResultError(errors.New("missing AllocID"), pointer.Of(int64(400)), encoder)
+		return
+	}
+
+	// Retrieve the allocation
+	snap, err := f.srv.State().Snapshot()
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	alloc, err := getAlloc(snap, args.AllocID)
+	if structs.IsErrUnknownAllocation(err) {
+		handleStreamResultError(structs.NewErrUnknownAllocation(args.AllocID), pointer.Of(int64(404)), encoder)
+		return
+	}
+	if err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	// Check permissions for the namespace or the task of the path.
+	if aclObj, err := f.srv.ResolveACL(&args); err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	} else if !aclObj.AllowJobTaskOperation(alloc.Namespace, alloc.JobID, alloc.TaskForPath(args.Path), capability) {
+		handleStreamResultError(structs.ErrPermissionDenied, nil, encoder)
+		return
+	}
+
+	nodeID := alloc.NodeID
+
+	// Make sure Node is valid and new enough to support RPC
+	node, err := snap.NodeByID(nil, nodeID)
+	if err != nil {
+		handleStreamResultError(err, pointer.Of(int64(500)), encoder)
+		return
+	}
+
+	if node == nil {
+		err := fmt.Errorf("Unknown node %q", nodeID)
+		handleStreamResultError(err, pointer.Of(int64(400)), encoder)
+		return
+	}
+
+	if err := nodeSupportsRpc(node); err != nil {
+		handleStreamResultError(err, pointer.Of(int64(400)), encoder)
+		return
+	}
+
+	// Get the connection to the client either by forwarding to another server
+	// or creating a direct stream
+	var clientConn net.Conn
+	state, ok := f.srv.getNodeConn(nodeID)
+	if !ok {
+		// Determine the Server that has a connection to the node.
+		srv, err := f.srv.serverWithNodeConn(nodeID, f.srv.Region())
+		if err != nil {
+			var code *int64
+			if structs.IsErrNoNodeConn(err) {
+				code = pointer.Of(int64(404))
+			}
+			handleStreamResultError(err, code, encoder)
+			return
+		}
+
+		// Get a connection to the server
+		conn, err := f.srv.streamingRpc(srv, method)
+		if err != nil {
+			handleStreamResultError(err, nil, encoder)
+			return
+		}
+
+		clientConn = conn
+	} else {
+		stream, err := NodeStreamingRpc(state.Session, method)
+		if err != nil {
+			handleStreamResultError(err, nil, encoder)
+			return
+		}
+		clientConn = stream
+	}
+	defer clientConn.Close()
+
+	// Send the request.
+	outEncoder := codec.NewEncoder(clientConn, structs.MsgpackHandle)
+	if err := outEncoder.Encode(args); err != nil {
+		handleStreamResultError(err, nil, encoder)
+		return
+	}
+
+	structs.Bridge(conn, clientConn)
+}
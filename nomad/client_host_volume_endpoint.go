@@ -0,0 +1,179 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// HostVolume endpoint is used for creating and deleting host volumes on
+// clients with host volume plugins.
+type HostVolume struct {
+	srv    *Server
+	logger log.Logger
+}
+
+func newHostVolumeEndpoint(srv *Server) *HostVolume {
+	return &HostVolume{
+		srv:    srv,
+		logger: srv.logger.Named("host_volume"),
+	}
+}
+
+// Create creates a host volume on the requested node. If no node is
+// requested, the volume is created on the ready node with the plugin that
+// reports the most available capacity.
+func (v *HostVolume) Create(args *structs.HostVolumeCreateRequest, reply *structs.HostVolumeCreateResponse) error {
+	const method = "HostVolume.Create"
+
+	// Prevent infinite loop between leader and
+	// follower-with-the-target-node-connection.
+	args.QueryOptions.AllowStale = true
+
+	authErr := v.srv.Authenticate(nil, args)
+	if done, err := v.srv.forward(method, args, args, reply); done {
+		return err
+	}
+	v.srv.MeasureRPCRate("host_volume", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "host_volume", "create"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := v.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
+	}
+
+	if args.NodeID == "" {
+		snap, err := v.srv.State().Snapshot()
+		if err != nil {
+			return err
+		}
+		nodeID, err := pickHostVolumeNode(snap, args)
+		if err != nil {
+			return err
+		}
+		args.NodeID = nodeID
+	}
+
+	return v.srv.forwardClientRPC(method, args.NodeID, args, reply)
+}
+
+// Delete deletes a host volume created by a host volume plugin on the node.
+func (v *HostVolume) Delete(args *structs.HostVolumeDeleteRequest, reply *structs.HostVolumeDeleteResponse) error {
+	const method = "HostVolume.Delete"
+
+	// Prevent infinite loop between leader and
+	// follower-with-the-target-node-connection.
+	args.QueryOptions.AllowStale = true
+
+	authErr := v.srv.Authenticate(nil, args)
+	if done, err := v.srv.forward(method, args, args, reply); done {
+		return err
+	}
+	v.srv.MeasureRPCRate("host_volume", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "host_volume", "delete"}, time.Now())
+
+	// Check node write permissions
+	if aclObj, err := v.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.AllowNodeWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
+	}
+	if args.NodeID == "" {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, "missing node ID")
+	}
+
+	return v.srv.forwardClientRPC(method, args.NodeID, args, reply)
+}
+
+// pickHostVolumeNode returns the ready node in the requested node pool that
+// has the requested host volume plugin fingerprinted, doesn't already have a
+// host volume with the name, and reports the most capacity available for the
+// plugin. Nodes whose plugin doesn't report its capacity are only picked if
+// no node reports enough capacity.
+func pickHostVolumeNode(snap *state.StateSnapshot, args *structs.HostVolumeCreateRequest) (string, error) {
+	ws := memdb.NewWatchSet()
+
+	var iter memdb.ResultIterator
+	var err error
+	if args.NodePool != "" {
+		iter, err = snap.NodesByNodePool(ws, args.NodePool)
+	} else {
+		iter, err = snap.Nodes(ws)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	versionAttr := structs.HostVolumePluginVersionAttr(args.PluginID)
+	capacityAttr := structs.HostVolumePluginCapacityAttr(args.PluginID)
+
+	var best, unknown string
+	var bestCapacity int64
+	for {
+		raw := iter.Next()
+		if raw == nil {
+			break
+		}
+		node := raw.(*structs.Node)
+		if !node.Ready() {
+			continue
+		}
+		if _, ok := node.Attributes[versionAttr]; !ok {
+			continue
+		}
+		if _, ok := node.HostVolumes[args.Name]; ok {
+			continue
+		}
+
+		capacity, ok := node.Attributes[capacityAttr]
+		if !ok {
+			if unknown == "" {
+				unknown = node.ID
+			}
+			continue
+		}
+		capacityBytes, err := strconv.ParseInt(capacity, 10, 64)
+		if err != nil || capacityBytes < args.RequestedCapacityMinBytes {
+			continue
+		}
+		if best == "" || capacityBytes > bestCapacity {
+			best, bestCapacity = node.ID, capacityBytes
+		}
+	}
+
+	switch {
+	case best != "":
+		return best, nil
+	case unknown != "":
+		return unknown, nil
+	default:
+		return "", structs.NewErrRPCCoded(http.StatusBadRequest, fmt.Sprintf(
+			"no ready node has host volume plugin %q with enough capacity", args.PluginID))
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestHostVolume_pickHostVolumeNode(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+
+	withPlugin := func(capacity string) *structs.Node {
+		node := mock.Node()
+		node.Attributes[structs.HostVolumePluginVersionAttr("lvm")] = "0.1.0"
+		if capacity != "" {
+			node.Attributes[structs.HostVolumePluginCapacityAttr("lvm")] = capacity
+		}
+		return node
+	}
+
+	small := withPlugin("1000")
+	large := withPlugin("5000")
+	unknown := withPlugin("")
+	down := withPlugin("9000")
+	down.Status = structs.NodeStatusDown
+	existing := withPlugin("9000")
+	existing.HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"data": {Name: "data"},
+	}
+	other := withPlugin("9000")
+	other.NodePool = "other"
+	without := mock.Node()
+
+	for i, node := range []*structs.Node{small, large, unknown, down, existing, other, without} {
+		must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(1000+i), node))
+	}
+
+	pick := func(req *structs.HostVolumeCreateRequest) (string, error) {
+		snap, err := store.Snapshot()
+		must.NoError(t, err)
+		return pickHostVolumeNode(snap, req)
+	}
+
+	// The node with the most capacity is picked, ignoring nodes that are
+	// down or already have the volume
+	nodeID, err := pick(&structs.HostVolumeCreateRequest{
+		Name:     "data",
+		PluginID: "lvm",
+		NodePool: structs.NodePoolDefault,
+	})
+	must.NoError(t, err)
+	must.Eq(t, large.ID, nodeID)
+
+	// Without a node pool every pool is considered
+	nodeID, err = pick(&structs.HostVolumeCreateRequest{
+		Name:     "data",
+		PluginID: "lvm",
+	})
+	must.NoError(t, err)
+	must.Eq(t, other.ID, nodeID)
+
+	// Nodes that don't report their capacity are the fallback
+	nodeID, err = pick(&structs.HostVolumeCreateRequest{
+		Name:                      "data",
+		PluginID:                  "lvm",
+		NodePool:                  structs.NodePoolDefault,
+		RequestedCapacityMinBytes: 8000,
+	})
+	must.NoError(t, err)
+	must.Eq(t, unknown.ID, nodeID)
+
+	_, err = pick(&structs.HostVolumeCreateRequest{
+		Name:     "data",
+		PluginID: "zfs",
+	})
+	must.ErrorContains(t, err, `no ready node has host volume plugin "zfs"`)
+}
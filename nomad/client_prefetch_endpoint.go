@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Prefetch endpoint is used to make clients pull the images and artifacts of
+// jobs ahead of the placement of their allocations.
+type Prefetch struct {
+	srv    *Server
+	logger log.Logger
+}
+
+func newPrefetchEndpoint(srv *Server) *Prefetch {
+	return &Prefetch{
+		srv:    srv,
+		logger: srv.logger.Named("prefetch"),
+	}
+}
+
+// Job makes the node of the request pull the images and artifacts of a job.
+// The node starts the downloads in the background and replies right away.
+func (p *Prefetch) Job(args *structs.PrefetchJobRequest, reply *structs.GenericResponse) error {
+	const method = "Prefetch.Job"
+
+	// Prevent infinite loop between leader and
+	// follower-with-the-target-node-connection.
+	args.QueryOptions.AllowStale = true
+
+	authErr := p.srv.Authenticate(nil, args)
+	if done, err := p.srv.forward(method, args, args, reply); done {
+		return err
+	}
+	p.srv.MeasureRPCRate("prefetch", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "prefetch", "job"}, time.Now())
+
+	// Check submit job permissions
+	if aclObj, err := p.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	return p.srv.forwardClientRPC(method, args.NodeID, args, reply)
+}
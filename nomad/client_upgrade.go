@@ -0,0 +1,344 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// clientUpgradeInterval is how often the leader checks the progress of
+	// the client upgrades.
+	clientUpgradeInterval = 5 * time.Second
+)
+
+// runClientUpgrades periodically advances the client upgrades in progress,
+// and marks the nodes of the paused and cancelled upgrades eligible again. It
+// runs until the stop channel is closed, when the server loses leadership.
+func (s *Server) runClientUpgrades(stopCh chan struct{}) {
+	ticker := time.NewTicker(clientUpgradeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			s.applyClientUpgrades(time.Now())
+		}
+	}
+}
+
+// applyClientUpgrades advances the client upgrades according to the given
+// time.
+func (s *Server) applyClientUpgrades(now time.Time) {
+	s.clientUpgradeLock.Lock()
+	defer s.clientUpgradeLock.Unlock()
+
+	snap, err := s.State().Snapshot()
+	if err != nil {
+		s.logger.Error("failed to get state for client upgrades", "error", err)
+		return
+	}
+
+	iter, err := snap.ClientUpgrades(nil)
+	if err != nil {
+		s.logger.Error("failed to list client upgrades", "error", err)
+		return
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		upgrade := raw.(*structs.ClientUpgrade)
+
+		var err error
+		switch upgrade.Status {
+		case structs.ClientUpgradeStatusComplete:
+			continue
+		case structs.ClientUpgradeStatusRunning:
+			err = s.stepClientUpgrade(snap, upgrade.Copy(), now)
+		default:
+			err = s.restoreClientUpgradeEligibility(snap, upgrade.Copy())
+		}
+		if err != nil {
+			s.logger.Error("failed to apply client upgrade", "upgrade_id", upgrade.ID, "error", err)
+		}
+	}
+}
+
+// stepClientUpgrade checks the health of the restarting nodes of the
+// upgrade, and selects the next nodes to restart once there is room in the
+// batch. The upgrade is paused as soon as a node fails to become healthy.
+func (s *Server) stepClientUpgrade(snap *state.StateSnapshot, upgrade *structs.ClientUpgrade, now time.Time) error {
+	var mErr *multierror.Error
+	changed := false
+
+	for _, n := range upgrade.NodesWithStatus(structs.ClientUpgradeNodeStatusRestarting) {
+		status, reason, err := clientUpgradeNodeHealth(snap, upgrade, n)
+		if err != nil {
+			return err
+		}
+
+		switch status {
+		case structs.ClientUpgradeNodeStatusHealthy:
+			if _, err := s.markClientUpgradeNodeEligible(snap, n); err != nil {
+				mErr = multierror.Append(mErr, err)
+				continue
+			}
+			s.logger.Info("node healthy after client upgrade", "node_id", n.NodeID, "upgrade_id", upgrade.ID)
+			n.Status = structs.ClientUpgradeNodeStatusHealthy
+			n.StatusDescription = ""
+		case structs.ClientUpgradeNodeStatusRestarting:
+			if now.Sub(n.RestartTime) < upgrade.HealthTimeout {
+				if n.StatusDescription != reason {
+					n.StatusDescription = reason
+					changed = true
+				}
+				continue
+			}
+			reason = "timed out waiting for node to become healthy: " + reason
+			fallthrough
+		default:
+			s.logger.Warn("pausing client upgrade after node failed", "node_id", n.NodeID,
+				"upgrade_id", upgrade.ID, "reason", reason)
+			n.Status = structs.ClientUpgradeNodeStatusFailed
+			n.StatusDescription = reason
+			upgrade.Status = structs.ClientUpgradeStatusPaused
+			upgrade.StatusDescription = fmt.Sprintf("node %s failed: %s", n.Name, reason)
+		}
+		changed = true
+	}
+
+	if upgrade.Status == structs.ClientUpgradeStatusRunning {
+		restarting := len(upgrade.NodesWithStatus(structs.ClientUpgradeNodeStatusRestarting))
+		for _, n := range upgrade.NodesWithStatus(structs.ClientUpgradeNodeStatusPending) {
+			if restarting >= upgrade.MaxParallel {
+				break
+			}
+			restart, err := s.restartClientUpgradeNode(snap, upgrade, n, now)
+			if err != nil {
+				mErr = multierror.Append(mErr, err)
+				break
+			}
+			if restart {
+				restarting++
+			}
+			changed = true
+		}
+
+		if restarting == 0 && len(upgrade.NodesWithStatus(structs.ClientUpgradeNodeStatusPending)) == 0 {
+			s.logger.Info("client upgrade complete", "upgrade_id", upgrade.ID)
+			upgrade.Status = structs.ClientUpgradeStatusComplete
+			changed = true
+		}
+	}
+
+	// Don't wait for the next pass to restore the eligibility of the nodes
+	// of an upgrade that was just paused.
+	if upgrade.Status == structs.ClientUpgradeStatusPaused {
+		if _, err := s.restoreClientUpgradeNodes(snap, upgrade); err != nil {
+			mErr = multierror.Append(mErr, err)
+		}
+	}
+
+	if changed {
+		if err := s.upsertClientUpgrade(upgrade); err != nil {
+			mErr = multierror.Append(mErr, err)
+		}
+	}
+	return mErr.ErrorOrNil()
+}
+
+// restartClientUpgradeNode selects a pending node of the upgrade to be
+// restarted, marking it ineligible for scheduling. It returns false if the
+// node was skipped instead.
+func (s *Server) restartClientUpgradeNode(snap *state.StateSnapshot, upgrade *structs.ClientUpgrade,
+	n *structs.ClientUpgradeNode, now time.Time) (bool, error) {
+
+	node, err := snap.NodeByID(nil, n.NodeID)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case node == nil:
+		n.Status = structs.ClientUpgradeNodeStatusSkipped
+		n.StatusDescription = "node was deregistered"
+		return false, nil
+	case node.Status != structs.NodeStatusReady:
+		n.Status = structs.ClientUpgradeNodeStatusSkipped
+		n.StatusDescription = fmt.Sprintf("node status is %q", node.Status)
+		return false, nil
+	case upgrade.Version != "" && node.Attributes[structs.ClientUpgradeVersionAttr] == upgrade.Version:
+		n.Status = structs.ClientUpgradeNodeStatusSkipped
+		n.StatusDescription = fmt.Sprintf("node already reports version %q", upgrade.Version)
+		return false, nil
+	}
+
+	allocs, err := snap.AllocsByNode(nil, node.ID)
+	if err != nil {
+		return false, err
+	}
+	n.RunningAllocs = nil
+	for _, alloc := range allocs {
+		if alloc.ClientStatus == structs.AllocClientStatusRunning {
+			n.RunningAllocs = append(n.RunningAllocs, alloc.ID)
+		}
+	}
+
+	// Stop placing allocations on the node while it restarts. Nodes that
+	// were already ineligible are left as they are once restarted. The node
+	// is recorded as marked ineligible before it is, so it can't be left
+	// ineligible if recording the upgrade fails afterwards.
+	n.RestartIndex = node.ModifyIndex
+	if node.SchedulingEligibility == structs.NodeSchedulingEligible {
+		n.MarkedIneligible = true
+		if err := s.upsertClientUpgrade(upgrade); err != nil {
+			return false, err
+		}
+		index, err := s.updateClientUpgradeEligibility(node.ID, structs.NodeSchedulingIneligible)
+		if err != nil {
+			return false, err
+		}
+		n.RestartIndex = index
+	}
+
+	s.logger.Info("restarting node for client upgrade", "node_id", node.ID, "upgrade_id", upgrade.ID)
+	n.Status = structs.ClientUpgradeNodeStatusRestarting
+	n.StatusDescription = ""
+	n.RestartTime = now
+	return true, nil
+}
+
+// clientUpgradeNodeHealth returns the status of a restarting node of the
+// upgrade along with the reason it isn't healthy. A node is healthy once it
+// re-registered after it was selected to be restarted, is ready, reports the
+// version of the upgrade, and all of the allocations that were running before
+// the restart are running again. It has failed if it was deregistered or one
+// of these allocations wasn't restored, and is still restarting otherwise.
+func clientUpgradeNodeHealth(snap *state.StateSnapshot, upgrade *structs.ClientUpgrade,
+	n *structs.ClientUpgradeNode) (string, string, error) {
+
+	node, err := snap.NodeByID(nil, n.NodeID)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch {
+	case node == nil:
+		return structs.ClientUpgradeNodeStatusFailed, "node was deregistered", nil
+	case node.ModifyIndex <= n.RestartIndex:
+		return structs.ClientUpgradeNodeStatusRestarting, "node has not re-registered", nil
+	case node.Status != structs.NodeStatusReady:
+		return structs.ClientUpgradeNodeStatusRestarting, fmt.Sprintf("node status is %q", node.Status), nil
+	case upgrade.Version != "" && node.Attributes[structs.ClientUpgradeVersionAttr] != upgrade.Version:
+		return structs.ClientUpgradeNodeStatusRestarting,
+			fmt.Sprintf("node reports version %q", node.Attributes[structs.ClientUpgradeVersionAttr]), nil
+	}
+
+	var pending []string
+	for _, allocID := range n.RunningAllocs {
+		alloc, err := snap.AllocByID(nil, allocID)
+		if err != nil {
+			return "", "", err
+		}
+		if alloc == nil || alloc.ClientTerminalStatus() {
+			return structs.ClientUpgradeNodeStatusFailed,
+				fmt.Sprintf("allocation %s was not restored after restart", allocID), nil
+		}
+		if alloc.ClientStatus != structs.AllocClientStatusRunning {
+			pending = append(pending, allocID)
+		}
+	}
+	if len(pending) > 0 {
+		slices.Sort(pending)
+		return structs.ClientUpgradeNodeStatusRestarting,
+			fmt.Sprintf("waiting for allocations to be running: %s", strings.Join(pending, ", ")), nil
+	}
+	return structs.ClientUpgradeNodeStatusHealthy, "", nil
+}
+
+// restoreClientUpgradeEligibility marks the nodes of a paused or cancelled
+// upgrade that the upgrade marked ineligible eligible again, and records it.
+func (s *Server) restoreClientUpgradeEligibility(snap *state.StateSnapshot, upgrade *structs.ClientUpgrade) error {
+	changed, err := s.restoreClientUpgradeNodes(snap, upgrade)
+	if changed {
+		if uErr := s.upsertClientUpgrade(upgrade); uErr != nil {
+			return uErr
+		}
+	}
+	return err
+}
+
+// restoreClientUpgradeNodes marks the nodes of the upgrade that the upgrade
+// marked ineligible eligible again, without recording the upgrade. It returns
+// true if any node was restored.
+func (s *Server) restoreClientUpgradeNodes(snap *state.StateSnapshot, upgrade *structs.ClientUpgrade) (bool, error) {
+	var mErr *multierror.Error
+	changed := false
+	for _, n := range upgrade.Nodes {
+		restored, err := s.markClientUpgradeNodeEligible(snap, n)
+		if err != nil {
+			mErr = multierror.Append(mErr, err)
+		}
+		changed = changed || restored
+	}
+	return changed, mErr.ErrorOrNil()
+}
+
+// markClientUpgradeNodeEligible marks a node eligible for scheduling again if
+// the upgrade marked it ineligible, and returns true if it did. Nodes that
+// were drained in the meantime are left to the operator that drained them.
+func (s *Server) markClientUpgradeNodeEligible(snap *state.StateSnapshot, n *structs.ClientUpgradeNode) (bool, error) {
+	if !n.MarkedIneligible {
+		return false, nil
+	}
+
+	node, err := snap.NodeByID(nil, n.NodeID)
+	if err != nil {
+		return false, err
+	}
+	if node != nil && node.DrainStrategy == nil && node.SchedulingEligibility != structs.NodeSchedulingEligible {
+		if _, err := s.updateClientUpgradeEligibility(node.ID, structs.NodeSchedulingEligible); err != nil {
+			return false, err
+		}
+	}
+	n.MarkedIneligible = false
+	return true, nil
+}
+
+// updateClientUpgradeEligibility sets the scheduling eligibility of a node,
+// and returns the index of the update.
+func (s *Server) updateClientUpgradeEligibility(nodeID, eligibility string) (uint64, error) {
+	req := &structs.NodeUpdateEligibilityRequest{
+		NodeID:      nodeID,
+		Eligibility: eligibility,
+		WriteRequest: structs.WriteRequest{
+			Region:    s.Region(),
+			AuthToken: s.getLeaderAcl(),
+		},
+	}
+	var resp structs.NodeEligibilityUpdateResponse
+	if err := s.RPC("Node.UpdateEligibility", req, &resp); err != nil {
+		return 0, fmt.Errorf("failed to update eligibility of node %s: %w", nodeID, err)
+	}
+	return resp.Index, nil
+}
+
+// upsertClientUpgrade records the client upgrade in raft.
+func (s *Server) upsertClientUpgrade(upgrade *structs.ClientUpgrade) error {
+	req := &structs.ClientUpgradeUpsertRequest{
+		Upgrade: upgrade,
+	}
+	if _, _, err := s.raftApply(structs.ClientUpgradeUpsertRequestType, req); err != nil {
+		return fmt.Errorf("failed to update client upgrade: %w", err)
+	}
+	return nil
+}
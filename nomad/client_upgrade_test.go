@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/shoenig/test/must"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+)
+
+func TestServer_applyClientUpgrades(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	first := mock.Node()
+	first.Name = "client-1"
+	second := mock.Node()
+	second.Name = "client-2"
+	upgraded := mock.Node()
+	upgraded.Name = "client-0"
+	upgraded.Attributes[structs.ClientUpgradeVersionAttr] = "2.0.0"
+	for i, node := range []*structs.Node{first, second, upgraded} {
+		must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(1000+i), node))
+	}
+
+	alloc := mock.Alloc()
+	alloc.NodeID = first.ID
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1010, []*structs.Allocation{alloc}))
+
+	// Nodes that already run the version are left out of the upgrade
+	start := &structs.ClientUpgradeStartRequest{
+		Upgrade: &structs.ClientUpgrade{
+			Version:       "2.0.0",
+			MaxParallel:   1,
+			HealthTimeout: time.Minute,
+		},
+		WriteRequest: structs.WriteRequest{Region: s1.config.Region},
+	}
+	var startResp structs.ClientUpgradeResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.ClientUpgradeStart", start, &startResp))
+	upgradeID := startResp.Upgrade.ID
+	must.Len(t, 2, startResp.Upgrade.Nodes)
+	must.Eq(t, first.ID, startResp.Upgrade.Nodes[0].NodeID)
+	must.Eq(t, second.ID, startResp.Upgrade.Nodes[1].NodeID)
+
+	// Only one upgrade can be in progress
+	err := msgpackrpc.CallWithCodec(codec, "Operator.ClientUpgradeStart", start, &startResp)
+	must.ErrorContains(t, err, "is already running")
+
+	getNode := func(id string) *structs.Node {
+		node, err := store.NodeByID(nil, id)
+		must.NoError(t, err)
+		return node
+	}
+	getUpgrade := func() *structs.ClientUpgrade {
+		out, err := store.ClientUpgradeByID(nil, upgradeID)
+		must.NoError(t, err)
+		return out
+	}
+
+	// The first node is marked ineligible to be restarted
+	now := time.Now()
+	s1.applyClientUpgrades(now)
+	upgrade := getUpgrade()
+	must.Eq(t, structs.ClientUpgradeNodeStatusRestarting, upgrade.Nodes[0].Status)
+	must.True(t, upgrade.Nodes[0].MarkedIneligible)
+	must.Eq(t, []string{alloc.ID}, upgrade.Nodes[0].RunningAllocs)
+	must.Eq(t, structs.ClientUpgradeNodeStatusPending, upgrade.Nodes[1].Status)
+	must.Eq(t, structs.NodeSchedulingIneligible, getNode(first.ID).SchedulingEligibility)
+
+	// It isn't healthy until it re-registers with the new version
+	s1.applyClientUpgrades(now)
+	must.Eq(t, "node has not re-registered", getUpgrade().Nodes[0].StatusDescription)
+
+	restarted := getNode(first.ID).Copy()
+	restarted.Attributes[structs.ClientUpgradeVersionAttr] = "2.0.0"
+	must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 2000, restarted))
+
+	// Once healthy it is marked eligible again, and the next node restarted
+	s1.applyClientUpgrades(now)
+	upgrade = getUpgrade()
+	must.Eq(t, structs.ClientUpgradeNodeStatusHealthy, upgrade.Nodes[0].Status)
+	must.False(t, upgrade.Nodes[0].MarkedIneligible)
+	must.Eq(t, structs.NodeSchedulingEligible, getNode(first.ID).SchedulingEligibility)
+	must.Eq(t, structs.ClientUpgradeNodeStatusRestarting, upgrade.Nodes[1].Status)
+	must.Eq(t, structs.NodeSchedulingIneligible, getNode(second.ID).SchedulingEligibility)
+
+	// A node that doesn't become healthy in time pauses the upgrade, and is
+	// marked eligible again
+	s1.applyClientUpgrades(now.Add(2 * time.Minute))
+	upgrade = getUpgrade()
+	must.Eq(t, structs.ClientUpgradeStatusPaused, upgrade.Status)
+	must.StrContains(t, upgrade.StatusDescription, "timed out waiting for node to become healthy")
+	must.Eq(t, structs.ClientUpgradeNodeStatusFailed, upgrade.Nodes[1].Status)
+	must.False(t, upgrade.Nodes[1].MarkedIneligible)
+	must.Eq(t, structs.NodeSchedulingEligible, getNode(second.ID).SchedulingEligibility)
+
+	// Resuming the upgrade restarts the failed node again
+	update := &structs.ClientUpgradeUpdateStatusRequest{
+		ID:           upgradeID,
+		Status:       structs.ClientUpgradeStatusRunning,
+		WriteRequest: structs.WriteRequest{Region: s1.config.Region},
+	}
+	var updateResp structs.ClientUpgradeResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.ClientUpgradeUpdateStatus", update, &updateResp))
+	must.Eq(t, structs.ClientUpgradeNodeStatusPending, updateResp.Upgrade.Nodes[1].Status)
+
+	s1.applyClientUpgrades(now)
+	must.Eq(t, structs.NodeSchedulingIneligible, getNode(second.ID).SchedulingEligibility)
+
+	// Cancelling the upgrade marks the node eligible again
+	update.Status = structs.ClientUpgradeStatusCancelled
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.ClientUpgradeUpdateStatus", update, &updateResp))
+	must.Eq(t, structs.ClientUpgradeStatusCancelled, getUpgrade().Status)
+	must.False(t, getUpgrade().Nodes[1].MarkedIneligible)
+	must.Eq(t, structs.NodeSchedulingEligible, getNode(second.ID).SchedulingEligibility)
+}
@@ -13,6 +13,7 @@ import (
 
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/memberlist"
+	"github.com/hashicorp/nomad/command/agent/flightrecorder"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/helper/uuid"
@@ -101,10 +102,27 @@ type Config struct {
 	// RaftTimeout is applied to any network traffic for raft. Defaults to 10s.
 	RaftTimeout time.Duration
 
+	// RaftSnapshotSectionCache enables reusing the cached sections of the
+	// previous FSM snapshot for tables that haven't changed since.
+	RaftSnapshotSectionCache bool
+
+	// RaftSnapshotChunkSize is the size of the chunks FSM snapshots are
+	// written in.
+	RaftSnapshotChunkSize int
+
+	// RaftSnapshotWriteRate limits the rate in bytes per second at which FSM
+	// snapshots are written. Zero means no limit.
+	RaftSnapshotWriteRate int64
+
 	// (Enterprise-only) NonVoter is used to prevent this server from being added
 	// as a voting member of the Raft cluster.
 	NonVoter bool
 
+	// ReadReplica makes this server a non-voting member of the Raft cluster
+	// that is never promoted to a voter. Read replicas serve the stale reads
+	// and event streams that request them, offloading the voters.
+	ReadReplica bool
+
 	// (Enterprise-only) RedundancyZone is the redundancy zone to use for this server.
 	RedundancyZone string
 
@@ -169,6 +187,11 @@ type Config struct {
 	// for GC if the eval belongs to a batch job.
 	BatchEvalGCThreshold time.Duration
 
+	// EvalHistoryLimit is the number of the most recent terminal evaluations
+	// of each job that are kept by eval GC once they're older than the GC
+	// thresholds. Zero disables the history.
+	EvalHistoryLimit int
+
 	// JobGCInterval is how often we dispatch a job to GC jobs that are
 	// available for garbage collection.
 	JobGCInterval time.Duration
@@ -235,6 +258,10 @@ type Config struct {
 	// rekey any variables associated with a key in the Rekeying state
 	VariablesRekeyInterval time.Duration
 
+	// VariablesExpirationGCInterval is how often we dispatch a job to GC
+	// variables whose TTL has expired.
+	VariablesExpirationGCInterval time.Duration
+
 	// EvalNackTimeout controls how long we allow a sub-scheduler to
 	// work on an evaluation before we consider it failed and Nack it.
 	// This allows that evaluation to be handed to another sub-scheduler
@@ -307,9 +334,9 @@ type Config struct {
 	// of all the heartbeats.
 	FailoverHeartbeatTTL time.Duration
 
-	// ConsulConfigs is a map of Consul configurations, here to support features
-	// in Nomad Enterprise. The default Consul config pointer above will be
-	// found in this map under the name "default"
+	// ConsulConfigs is a map of Consul configurations, keyed by cluster name.
+	// The default Consul config pointer above will be found in this map under
+	// the name "default"
 	ConsulConfigs map[string]*config.ConsulConfig
 
 	// VaultConfigs is a map of Vault configurations, here to support features
@@ -407,6 +434,14 @@ type Config struct {
 	// RaftBoltNoFreelistSync configures whether freelist syncing is enabled.
 	RaftBoltNoFreelistSync bool
 
+	// RaftLogStoreBackend is the backend of the raft log store, either
+	// "boltdb" or "wal".
+	RaftLogStoreBackend string
+
+	// RaftWALSegmentSizeMB is the size of the segment files of the "wal" raft
+	// log store backend.
+	RaftWALSegmentSizeMB int
+
 	// AgentShutdown is used to call agent.Shutdown from the context of a Server
 	// It is used primarily for licensing
 	AgentShutdown func() error
@@ -424,12 +459,47 @@ type Config struct {
 	// JobTrackedVersions is the number of historic Job versions that are kept.
 	JobTrackedVersions int
 
+	// JobNotifyAllowedHosts is the hosts that the notify webhooks of jobs are
+	// allowed to target. Jobs with a webhook are rejected if empty.
+	JobNotifyAllowedHosts []string
+
+	// VariablesTrackedVersions is the number of prior versions of each
+	// variable that are kept. Zero disables variable history and soft
+	// deletes.
+	VariablesTrackedVersions int
+
+	// VariablesDeleteRetention is how long deleted variables are kept, so
+	// that they can be restored, before they are permanently removed.
+	VariablesDeleteRetention time.Duration
+
 	Reporting *config.ReportingConfig
 
 	// OIDCIssuer is the URL for the OIDC Issuer field in Workload Identity JWTs.
 	// If this is not configured the /.well-known/openid-configuration endpoint
 	// will not be available.
 	OIDCIssuer string
+
+	// KEKProviderConfigs configure the providers for the key encryption keys
+	// that wrap the root keys in the keystore. The active provider wraps new
+	// keys.
+	KEKProviderConfigs []*structs.KEKProviderConfig
+
+	// AdmissionWebhooks are HTTP endpoints that are called, in order, to
+	// mutate or validate jobs before they are registered.
+	AdmissionWebhooks []*config.AdmissionWebhookConfig
+
+	// Tracing configures the export of spans for the RPC handlers and the
+	// scheduling pipeline.
+	Tracing *config.TracingConfig
+
+	// Usage configures the accounting of the resources reserved and used by
+	// jobs. Usage accounting is disabled when nil.
+	Usage *config.UsageConfig
+
+	// FlightRecorder records the RPC latencies, eval broker stats, and
+	// leadership changes of the server. It is shared with the client of the
+	// agent and is nil if the flight recorder isn't enabled.
+	FlightRecorder *flightrecorder.Recorder
 }
 
 func (c *Config) Copy() *Config {
@@ -458,6 +528,10 @@ func (c *Config) Copy() *Config {
 	nc.AutopilotConfig = c.AutopilotConfig.Copy()
 	nc.LicenseConfig = c.LicenseConfig.Copy()
 	nc.SearchConfig = c.SearchConfig.Copy()
+	nc.KEKProviderConfigs = helper.CopySlice(c.KEKProviderConfigs)
+	nc.AdmissionWebhooks = helper.CopySlice(c.AdmissionWebhooks)
+	nc.Tracing = c.Tracing.Copy()
+	nc.Usage = c.Usage.Copy()
 
 	return &nc
 }
@@ -569,6 +643,7 @@ func DefaultConfig() *Config {
 		RootKeyGCThreshold:               1 * time.Hour,
 		RootKeyRotationThreshold:         720 * time.Hour, // 30 days
 		VariablesRekeyInterval:           10 * time.Minute,
+		VariablesExpirationGCInterval:    1 * time.Minute,
 		EvalNackTimeout:                  60 * time.Second,
 		EvalDeliveryLimit:                3,
 		EvalNackInitialReenqueueDelay:    1 * time.Second,
@@ -618,6 +693,8 @@ func DefaultConfig() *Config {
 		JobDefaultPriority:       structs.JobDefaultPriority,
 		JobMaxPriority:           structs.JobDefaultMaxPriority,
 		JobTrackedVersions:       structs.JobDefaultTrackedVersions,
+		VariablesTrackedVersions: structs.VariableDefaultTrackedVersions,
+		VariablesDeleteRetention: 7 * 24 * time.Hour,
 	}
 
 	// Enable all known schedulers by default
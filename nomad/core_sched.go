@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -66,6 +67,11 @@ func (c *CoreScheduler) Process(eval *structs.Evaluation) error {
 		return c.rootKeyRotateOrGC(eval)
 	case structs.CoreJobVariablesRekey:
 		return c.variablesRekey(eval)
+	case structs.CoreJobVariablesExpiredGC:
+		if err := c.expiredVariablesGC(eval); err != nil {
+			return err
+		}
+		return c.deletedVariablesGC(eval)
 	case structs.CoreJobForceGC:
 		return c.forceGC(eval)
 	default:
@@ -102,6 +108,12 @@ func (c *CoreScheduler) forceGC(eval *structs.Evaluation) error {
 	if err := c.rootKeyGC(eval); err != nil {
 		return err
 	}
+	if err := c.expiredVariablesGC(eval); err != nil {
+		return err
+	}
+	if err := c.deletedVariablesGC(eval); err != nil {
+		return err
+	}
 	// Node GC must occur after the others to ensure the allocations are
 	// cleared.
 	return c.nodeGC(eval)
@@ -246,6 +258,16 @@ func (c *CoreScheduler) evalGC(eval *structs.Evaluation) error {
 	batchOldThreshold := c.getThreshold(eval, "eval",
 		"batch_eval_gc_threshold", c.srv.config.BatchEvalGCThreshold)
 
+	// Keep the most recent evaluations of each job as history, unless GC was
+	// forced
+	var history map[string]struct{}
+	if limit := c.srv.config.EvalHistoryLimit; limit > 0 && eval.JobID != structs.CoreJobForceGC {
+		history, err = c.evalHistory(limit)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Collect the allocations and evaluations to GC
 	var gcAlloc, gcEval []string
 	for raw := iter.Next(); raw != nil; raw = iter.Next() {
@@ -261,6 +283,12 @@ func (c *CoreScheduler) evalGC(eval *structs.Evaluation) error {
 			return err
 		}
 
+		// Evaluations kept as history may still have their allocations
+		// collected
+		if _, ok := history[eval.ID]; ok {
+			gc = false
+		}
+
 		if gc {
 			gcEval = append(gcEval, eval.ID)
 		}
@@ -277,6 +305,47 @@ func (c *CoreScheduler) evalGC(eval *structs.Evaluation) error {
 	return c.evalReap(gcEval, gcAlloc)
 }
 
+// evalHistory returns the IDs of the most recent terminal evaluations of each
+// job, up to the limit per job. Evaluations of jobs that no longer exist are
+// not included.
+func (c *CoreScheduler) evalHistory(limit int) (map[string]struct{}, error) {
+	ws := memdb.NewWatchSet()
+	iter, err := c.snap.Evals(ws, false)
+	if err != nil {
+		return nil, err
+	}
+
+	byJob := make(map[structs.NamespacedID][]*structs.Evaluation)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		eval := raw.(*structs.Evaluation)
+		if !eval.TerminalStatus() {
+			continue
+		}
+		key := structs.NamespacedID{ID: eval.JobID, Namespace: eval.Namespace}
+		byJob[key] = append(byJob[key], eval)
+	}
+
+	history := make(map[string]struct{})
+	for key, evals := range byJob {
+		job, err := c.snap.JobByID(ws, key.Namespace, key.ID)
+		if err != nil {
+			return nil, err
+		}
+		if job == nil {
+			continue
+		}
+
+		sort.Slice(evals, func(i, j int) bool {
+			return evals[i].CreateIndex > evals[j].CreateIndex
+		})
+		for _, eval := range evals[:min(limit, len(evals))] {
+			history[eval.ID] = struct{}{}
+		}
+	}
+
+	return history, nil
+}
+
 // gcEval returns whether the eval should be garbage collected given a raft
 // threshold index. The eval disqualifies for garbage collection if it or its
 // allocs are not older than the threshold. If the eval should be garbage
@@ -795,6 +864,57 @@ func (c *CoreScheduler) expiredOneTimeTokenGC(eval *structs.Evaluation) error {
 	return c.srv.RPC("ACL.ExpireOneTimeTokens", req, &structs.GenericResponse{})
 }
 
+// expiredVariablesGC is used to delete variables whose TTL has expired. The
+// RPC is only made if the snapshot holds at least one expired variable, so
+// that the raft log isn't written to needlessly.
+func (c *CoreScheduler) expiredVariablesGC(eval *structs.Evaluation) error {
+	iter, err := c.snap.VariablesByExpired(nil)
+	if err != nil {
+		return err
+	}
+
+	raw := iter.Next()
+	if raw == nil || !raw.(*structs.VariableEncrypted).IsExpired(time.Now()) {
+		return nil
+	}
+
+	req := &structs.VariablesExpireRequest{
+		WriteRequest: structs.WriteRequest{
+			Region:    c.srv.Region(),
+			AuthToken: eval.LeaderACL,
+		},
+	}
+	return c.srv.RPC(structs.VariablesExpireRPCMethod, req, &structs.GenericResponse{})
+}
+
+// deletedVariablesGC is used to permanently remove the deleted variables whose
+// retention period has passed. The RPC is only made if the snapshot holds at
+// least one such variable, so that the raft log isn't written to needlessly.
+func (c *CoreScheduler) deletedVariablesGC(eval *structs.Evaluation) error {
+	if !ServersMeetMinimumVersion(c.srv.Members(), c.srv.Region(), minVariableHistoryVersion, false) {
+		return nil
+	}
+
+	iter, err := c.snap.VariableVersionsByDeleted(nil)
+	if err != nil {
+		return err
+	}
+
+	threshold := time.Now().Add(-c.srv.config.VariablesDeleteRetention)
+	raw := iter.Next()
+	if raw == nil || raw.(*structs.VariableEncrypted).DeleteTime > threshold.UnixNano() {
+		return nil
+	}
+
+	req := &structs.VariablesPurgeDeletedRequest{
+		WriteRequest: structs.WriteRequest{
+			Region:    c.srv.Region(),
+			AuthToken: eval.LeaderACL,
+		},
+	}
+	return c.srv.RPC(structs.VariablesPurgeDeletedRPCMethod, req, &structs.GenericResponse{})
+}
+
 // expiredACLTokenGC handles running the garbage collector for expired ACL
 // tokens. It can be used for both local and global tokens and includes
 // behaviour to account for periodic and user actioned garbage collection
@@ -1026,6 +1146,20 @@ func (c *CoreScheduler) variablesRekey(eval *structs.Evaluation) error {
 			return err
 		}
 
+		// Prior versions of variables can only be re-keyed in place, so
+		// they're left on the old key until all servers support it
+		if !ServersMeetMinimumVersion(c.srv.Members(), c.srv.Region(), minVariableHistoryVersion, false) {
+			continue
+		}
+		versionIter, err := c.snap.GetVariableVersionsByKeyID(ws, keyMeta.KeyID)
+		if err != nil {
+			return err
+		}
+		err = c.rotateVariables(versionIter, eval)
+		if err != nil {
+			return err
+		}
+
 	}
 
 	return nil
@@ -1044,6 +1178,11 @@ func (c *CoreScheduler) rotateVariables(iter memdb.ResultIterator, eval *structs
 		},
 	}
 
+	// Re-keying in place doesn't create a new version of each variable
+	if ServersMeetMinimumVersion(c.srv.Members(), c.srv.Region(), minVariableHistoryVersion, false) {
+		args.Op = structs.VarOpRekey
+	}
+
 	// We may have to work on a very large number of variables. There's no
 	// BatchApply RPC because it makes for an awkward API around conflict
 	// detection, and even if we did, we'd be blocking this scheduler goroutine
@@ -115,6 +115,69 @@ func TestCoreScheduler_EvalGC(t *testing.T) {
 }
 
 // Tests GC behavior on allocations being rescheduled
+func TestCoreScheduler_EvalGC_History(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.EvalHistoryLimit = 2
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// COMPAT Remove in 0.6: Reset the FSM time table since we reconcile which sets index 0
+	s1.fsm.timetable.table = make([]TimeTableEntry, 1, 10)
+
+	store := s1.fsm.State()
+	job := mock.Job()
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 999, nil, job))
+
+	// Insert three terminal evals for the job, and one for a job that no
+	// longer exists
+	var evals []*structs.Evaluation
+	for i := 0; i < 3; i++ {
+		eval := mock.Eval()
+		eval.JobID = job.ID
+		eval.Status = structs.EvalStatusComplete
+		must.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, uint64(1000+i), []*structs.Evaluation{eval}))
+		evals = append(evals, eval)
+	}
+	orphan := mock.Eval()
+	orphan.Status = structs.EvalStatusComplete
+	must.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, 1003, []*structs.Evaluation{orphan}))
+
+	// Update the time tables so all the evals are old enough to GC
+	tt := s1.fsm.TimeTable()
+	tt.Witness(2000, time.Now().UTC().Add(-1*s1.config.EvalGCThreshold))
+
+	snap, err := store.Snapshot()
+	must.NoError(t, err)
+	core := NewCoreScheduler(s1, snap)
+	must.NoError(t, core.Process(s1.coreJobEval(structs.CoreJobEvalGC, 2000)))
+
+	// Only the two most recent evals of the job are kept
+	ws := memdb.NewWatchSet()
+	for i, expectKept := range []bool{false, true, true} {
+		out, err := store.EvalByID(ws, evals[i].ID)
+		must.NoError(t, err)
+		must.Eq(t, expectKept, out != nil, must.Sprintf("eval %d", i))
+	}
+	out, err := store.EvalByID(ws, orphan.ID)
+	must.NoError(t, err)
+	must.Nil(t, out)
+
+	// Forced GC ignores the history
+	snap, err = store.Snapshot()
+	must.NoError(t, err)
+	core = NewCoreScheduler(s1, snap)
+	must.NoError(t, core.Process(s1.coreJobEval(structs.CoreJobForceGC, 2001)))
+
+	for _, eval := range evals {
+		out, err := store.EvalByID(ws, eval.ID)
+		must.NoError(t, err)
+		must.Nil(t, out)
+	}
+}
+
 func TestCoreScheduler_EvalGC_ReschedulingAllocs(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2634,6 +2697,51 @@ func TestCoreScheduler_VariablesRekey(t *testing.T) {
 
 }
 
+func TestCoreScheduler_ExpiredVariablesGC(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, cleanup := TestServer(t, nil)
+	defer cleanup()
+	testutil.WaitForLeader(t, srv.RPC)
+
+	store := srv.fsm.State()
+
+	// insert a variable that has expired and one that hasn't
+	expired := mock.VariableEncrypted()
+	expired.TTL = time.Minute
+	expired.ExpireTime = time.Now().Add(-time.Second).UnixNano()
+	setResp := store.VarSet(1000, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: expired,
+	})
+	must.NoError(t, setResp.Error)
+
+	unexpired := mock.VariableEncrypted()
+	unexpired.Path = expired.Path + "/unexpired"
+	unexpired.TTL = time.Hour
+	unexpired.ExpireTime = time.Now().Add(time.Hour).UnixNano()
+	setResp = store.VarSet(1001, &structs.VarApplyStateRequest{
+		Op:  structs.VarOpSet,
+		Var: unexpired,
+	})
+	must.NoError(t, setResp.Error)
+
+	// run the core job
+	snap, err := store.Snapshot()
+	must.NoError(t, err)
+	core := NewCoreScheduler(srv, snap)
+	eval := srv.coreJobEval(structs.CoreJobVariablesExpiredGC, 2000)
+	must.NoError(t, core.Process(eval))
+
+	out, err := store.GetVariable(nil, expired.Namespace, expired.Path)
+	must.NoError(t, err)
+	must.Nil(t, out)
+
+	out, err = store.GetVariable(nil, unexpired.Namespace, unexpired.Path)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+}
+
 func TestCoreScheduler_FailLoop(t *testing.T) {
 	ci.Parallel(t)
 
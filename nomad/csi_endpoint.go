@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"strings"
 	"time"
@@ -366,6 +367,11 @@ func (v *CSIVolume) Register(args *structs.CSIVolumeRegisterRequest, reply *stru
 			}
 		}
 
+		// The scheduled snapshots of new volumes are controlled by Nomad.
+		if existingVol == nil {
+			vol.ScheduledSnapshots = nil
+		}
+
 		if err := v.controllerValidateVolume(args, vol, plugin); err != nil {
 			return err
 		}
@@ -1176,6 +1182,91 @@ func (v *CSIVolume) createVolume(vol *structs.CSIVolume, plugin *structs.CSIPlug
 	vol.Capacity = cResp.CapacityBytes
 	vol.Context = cResp.VolumeContext
 	vol.Topologies = cResp.Topologies
+	vol.ScheduledSnapshots = nil
+	return nil
+}
+
+// Expand grows the capacity of a registered volume. The controller plugin
+// expands the volume in the storage provider and, if the plugin requires it,
+// the node plugins of the nodes claiming the volume expand its filesystem,
+// without detaching it from the allocations using it.
+func (v *CSIVolume) Expand(args *structs.CSIVolumeExpandRequest, reply *structs.CSIVolumeExpandResponse) error {
+
+	authErr := v.srv.Authenticate(v.ctx, args)
+	if done, err := v.srv.forward("CSIVolume.Expand", args, args, reply); done {
+		return err
+	}
+	v.srv.MeasureRPCRate("csi_volume", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "volume", "expand"}, time.Now())
+
+	allowVolume := acl.NamespaceValidator(acl.NamespaceCapabilityCSIWriteVolume)
+	aclObj, err := v.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !allowVolume(aclObj, args.RequestNamespace()) || !aclObj.AllowPluginRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.VolumeID == "" {
+		return fmt.Errorf("missing volume ID")
+	}
+	if args.RequestedCapacityMin <= 0 {
+		return fmt.Errorf("missing requested capacity")
+	}
+
+	plugin, vol, err := v.volAndPluginLookup(args.RequestNamespace(), args.VolumeID)
+	if err != nil {
+		return err
+	}
+	if plugin == nil {
+		return fmt.Errorf("volume %q has no controller plugin", args.VolumeID)
+	}
+	if args.RequestedCapacityMin < vol.Capacity {
+		return fmt.Errorf("requested capacity (%s) less than current (%s)",
+			humanize.Bytes(uint64(args.RequestedCapacityMin)),
+			humanize.Bytes(uint64(vol.Capacity)))
+	}
+
+	// Keep the maximum capacity of the volume unless the request raises it
+	// or the new minimum exceeds it.
+	capacityMax := args.RequestedCapacityMax
+	if capacityMax == 0 && vol.RequestedCapacityMax >= args.RequestedCapacityMin {
+		capacityMax = vol.RequestedCapacityMax
+	}
+
+	// The request secrets are merged onto the volume secrets for the plugin
+	// RPCs, but are not stored.
+	vol = vol.Copy()
+	secrets := vol.Secrets
+	vol.Secrets = maps.Clone(secrets)
+	maps.Copy(vol.Secrets, args.Secrets)
+
+	err = v.expandVolume(vol, plugin, &csi.CapacityRange{
+		RequiredBytes: args.RequestedCapacityMin,
+		LimitBytes:    capacityMax,
+	})
+	if err != nil {
+		return err
+	}
+	vol.Secrets = secrets
+
+	regArgs := &structs.CSIVolumeRegisterRequest{
+		Volumes:      []*structs.CSIVolume{vol},
+		WriteRequest: args.WriteRequest,
+	}
+	_, index, err := v.srv.raftApply(structs.CSIVolumeRegisterRequestType, regArgs)
+	if err != nil {
+		v.logger.Error("csi raft apply failed", "error", err, "method", "expand")
+		return err
+	}
+
+	reply.CapacityBytes = vol.Capacity
+	reply.Index = index
+	v.srv.setQueryMeta(&reply.QueryMeta)
 	return nil
 }
 
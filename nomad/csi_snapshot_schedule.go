@@ -0,0 +1,137 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// csiSnapshotScheduleInterval is how often the leader checks for volumes
+	// whose snapshot schedule is due.
+	csiSnapshotScheduleInterval = time.Minute
+)
+
+// scheduleCSISnapshots periodically takes the snapshots of the CSI volumes
+// that have a snapshot schedule. It runs until the stop channel is closed,
+// when the server loses leadership.
+func (s *Server) scheduleCSISnapshots(stopCh chan struct{}) {
+	ticker := time.NewTicker(csiSnapshotScheduleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			s.runCSISnapshotSchedules(time.Now())
+		}
+	}
+}
+
+// runCSISnapshotSchedules takes a snapshot of every volume whose schedule is
+// due at the given time.
+func (s *Server) runCSISnapshotSchedules(now time.Time) {
+	snap, err := s.State().Snapshot()
+	if err != nil {
+		s.logger.Error("failed to get state for CSI snapshot schedules", "error", err)
+		return
+	}
+
+	iter, err := snap.CSIVolumes(nil)
+	if err != nil {
+		s.logger.Error("failed to list CSI volumes", "error", err)
+		return
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		vol := raw.(*structs.CSIVolume)
+		if !vol.SnapshotDue(now) {
+			continue
+		}
+		if err := s.snapshotCSIVolume(snap, vol, now); err != nil {
+			s.logger.Error("failed to run CSI volume snapshot schedule",
+				"volume_id", vol.ID, "namespace", vol.Namespace, "error", err)
+		}
+	}
+}
+
+// snapshotCSIVolume takes a snapshot of the volume, deletes the scheduled
+// snapshots beyond the retention of the schedule, and records the retained
+// snapshots in raft.
+func (s *Server) snapshotCSIVolume(snap *state.StateSnapshot, vol *structs.CSIVolume, now time.Time) error {
+	plugin, err := snap.CSIPluginByID(nil, vol.PluginID)
+	if err != nil {
+		return fmt.Errorf("error querying plugin %q: %v", vol.PluginID, err)
+	}
+	if plugin == nil {
+		return fmt.Errorf("no such plugin %q", vol.PluginID)
+	}
+	if !plugin.HasControllerCapability(structs.CSIControllerSupportsCreateDeleteSnapshot) {
+		return fmt.Errorf("plugin %q does not support snapshot", vol.PluginID)
+	}
+
+	// The controller RPCs are serialized with the ones of the CSIVolume
+	// endpoint.
+	endpoint := NewCSIVolumeEndpoint(s, nil)
+	schedule := vol.SnapshotSchedule
+
+	cReq := &cstructs.ClientCSIControllerCreateSnapshotRequest{
+		ExternalSourceVolumeID: vol.ExternalID,
+		Name:                   fmt.Sprintf("%s-%d", vol.ID, now.Unix()),
+		Secrets:                vol.Secrets,
+		Parameters:             schedule.Parameters,
+	}
+	cReq.PluginID = plugin.ID
+	cResp := &cstructs.ClientCSIControllerCreateSnapshotResponse{}
+	err = endpoint.serializedControllerRPC(plugin.ID, func() error {
+		return s.RPC("ClientCSI.ControllerCreateSnapshot", cReq, cResp)
+	})
+	if err != nil {
+		return fmt.Errorf("could not create snapshot: %v", err)
+	}
+
+	snapshots := append(vol.Copy().ScheduledSnapshots, &structs.CSIVolumeScheduledSnapshot{
+		ID:         cResp.ID,
+		CreateTime: now.Unix(),
+	})
+
+	// Delete the oldest snapshots beyond the retention. Snapshots that fail
+	// to be deleted are kept and retried on the next run.
+	for schedule.Retain > 0 && len(snapshots) > schedule.Retain {
+		dReq := &cstructs.ClientCSIControllerDeleteSnapshotRequest{
+			ID:      snapshots[0].ID,
+			Secrets: vol.Secrets,
+		}
+		dReq.PluginID = plugin.ID
+		err = endpoint.serializedControllerRPC(plugin.ID, func() error {
+			return s.RPC("ClientCSI.ControllerDeleteSnapshot", dReq,
+				&cstructs.ClientCSIControllerDeleteSnapshotResponse{})
+		})
+		if err != nil {
+			s.logger.Warn("failed to delete expired CSI volume snapshot",
+				"volume_id", vol.ID, "snapshot_id", snapshots[0].ID, "error", err)
+			break
+		}
+		snapshots = snapshots[1:]
+	}
+
+	req := &structs.CSIVolumeSnapshotsUpdateRequest{
+		VolumeID:  vol.ID,
+		Snapshots: snapshots,
+		WriteRequest: structs.WriteRequest{
+			Region:    s.config.Region,
+			Namespace: vol.Namespace,
+		},
+	}
+	if _, _, err := s.raftApply(structs.CSIVolumeSnapshotsUpdateRequestType, req); err != nil {
+		return fmt.Errorf("failed to record snapshots: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/client"
+	cconfig "github.com/hashicorp/nomad/client/config"
+	cstructs "github.com/hashicorp/nomad/client/structs"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestServer_runCSISnapshotSchedules(t *testing.T) {
+	ci.Parallel(t)
+	srv, shutdown := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+
+	testutil.WaitForLeader(t, srv.RPC)
+
+	fake := newMockClientCSI()
+	fake.NextCreateSnapshotResponse = &cstructs.ClientCSIControllerCreateSnapshotResponse{
+		ID:                     "snap-3",
+		ExternalSourceVolumeID: "vol-12345",
+		IsReady:                true,
+	}
+
+	c, cleanup := client.TestClientWithRPCs(t,
+		func(c *cconfig.Config) {
+			c.Servers = []string{srv.config.RPCAddr.String()}
+		},
+		map[string]interface{}{"CSI": fake},
+	)
+	defer cleanup()
+
+	testutil.WaitForResult(func() (bool, error) {
+		nodes := srv.connectedNodes()
+		return len(nodes) == 1, nil
+	}, func(err error) {
+		t.Fatalf("should have a client")
+	})
+
+	state := srv.fsm.State()
+	index := uint64(1000)
+
+	node := c.UpdateConfig(func(c *cconfig.Config) {
+		c.Node.CSIControllerPlugins = map[string]*structs.CSIInfo{
+			"minnie": {
+				PluginID: "minnie",
+				Healthy:  true,
+				ControllerInfo: &structs.CSIControllerInfo{
+					SupportsCreateDeleteSnapshot: true,
+				},
+				RequiresControllerPlugin: true,
+			},
+		}
+	}).Node
+	index++
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, index, node))
+
+	now := time.Now()
+	vol := &structs.CSIVolume{
+		ID:             "test-volume0",
+		Namespace:      structs.DefaultNamespace,
+		AccessMode:     structs.CSIVolumeAccessModeMultiNodeSingleWriter,
+		AttachmentMode: structs.CSIVolumeAttachmentModeFilesystem,
+		PluginID:       "minnie",
+		ExternalID:     "vol-12345",
+		SnapshotSchedule: &structs.CSIVolumeSnapshotSchedule{
+			Interval: time.Hour,
+			Retain:   2,
+		},
+		ScheduledSnapshots: []*structs.CSIVolumeScheduledSnapshot{
+			{ID: "snap-1", CreateTime: now.Add(-3 * time.Hour).Unix()},
+			{ID: "snap-2", CreateTime: now.Add(-2 * time.Hour).Unix()},
+		},
+	}
+	index++
+	must.NoError(t, state.UpsertCSIVolume(index, []*structs.CSIVolume{vol}))
+
+	// The schedule is due, so a snapshot is taken and the oldest one is
+	// deleted.
+	srv.runCSISnapshotSchedules(now)
+
+	got, err := state.CSIVolumeByID(nil, vol.Namespace, vol.ID)
+	must.NoError(t, err)
+	must.Eq(t, []*structs.CSIVolumeScheduledSnapshot{
+		{ID: "snap-2", CreateTime: now.Add(-2 * time.Hour).Unix()},
+		{ID: "snap-3", CreateTime: now.Unix()},
+	}, got.ScheduledSnapshots)
+
+	// The schedule isn't due until the interval elapses.
+	fake.NextCreateSnapshotResponse = &cstructs.ClientCSIControllerCreateSnapshotResponse{ID: "snap-4"}
+	srv.runCSISnapshotSchedules(now.Add(30 * time.Minute))
+
+	got, err = state.CSIVolumeByID(nil, vol.Namespace, vol.ID)
+	must.NoError(t, err)
+	must.Len(t, 2, got.ScheduledSnapshots)
+	must.Eq(t, "snap-3", got.ScheduledSnapshots[1].ID)
+}
@@ -4,6 +4,7 @@
 package nomad
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -538,6 +540,145 @@ func TestDeploymentEndpoint_Promote(t *testing.T) {
 	assert.True(dout.TaskGroups["web"].Promoted, "web group should be promoted")
 }
 
+// Tests that promoting a blue/green deployment switches the traffic of Nomad
+// services to the new version and stops the allocations of the previous one
+func TestDeploymentEndpoint_Promote_BlueGreen(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	// Register the previous version of the job, and the new version which
+	// uses the blue/green update strategy
+	j := mock.Job()
+	j.TaskGroups[0].Count = 2
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 999, nil, j))
+	blue, err := state.JobByID(nil, j.Namespace, j.ID)
+	must.NoError(t, err)
+
+	j2 := j.Copy()
+	j2.TaskGroups[0].Tasks[0].Config["command"] = "/bin/other"
+	j2.TaskGroups[0].Update = structs.DefaultUpdateStrategy.Copy()
+	j2.TaskGroups[0].Update.Strategy = structs.UpdateStrategyBlueGreen
+	must.NoError(t, state.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, j2))
+	green, err := state.JobByID(nil, j.Namespace, j.ID)
+	must.NoError(t, err)
+
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.JobVersion = green.Version
+	d.JobCreateIndex = green.CreateIndex
+	d.JobModifyIndex = green.JobModifyIndex
+	d.TaskGroups["web"].DesiredCanaries = 2
+	d.TaskGroups["web"].DesiredTotal = 2
+
+	// Place both versions of the job on separate nodes, with a service
+	// registration for each allocation
+	var allocs []*structs.Allocation
+	var services []*structs.ServiceRegistration
+	for i, job := range []*structs.Job{blue, blue, green, green} {
+		node := mock.Node()
+		must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(1001+i), node))
+
+		a := mock.Alloc()
+		a.NodeID = node.ID
+		a.Job = job
+		a.JobID = job.ID
+		a.Name = structs.AllocName(job.ID, "web", uint(i%2))
+		a.ClientStatus = structs.AllocClientStatusRunning
+		if job == green {
+			a.DeploymentID = d.ID
+			a.DeploymentStatus = &structs.AllocDeploymentStatus{
+				Healthy: pointer.Of(true),
+				Canary:  true,
+			}
+			d.TaskGroups["web"].PlacedCanaries = append(d.TaskGroups["web"].PlacedCanaries, a.ID)
+		}
+		allocs = append(allocs, a)
+
+		reg := mock.ServiceRegistrations()[0]
+		reg.ID = fmt.Sprintf("_nomad-task-%s-group-web-web-http", a.ID)
+		reg.ServiceName = "web"
+		reg.JobID = job.ID
+		reg.AllocID = a.ID
+		reg.NodeID = node.ID
+		reg.Weight = 1
+		services = append(services, reg)
+	}
+	must.NoError(t, state.UpsertDeployment(1010, d))
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1011, allocs))
+	must.NoError(t, state.UpsertServiceRegistrations(structs.MsgTypeTestSetup, 1012, services))
+
+	// weights returns the weight of the service of each allocation
+	weights := func() map[string]int {
+		req := &structs.ServiceRegistrationByNameRequest{
+			ServiceName: "web",
+			QueryOptions: structs.QueryOptions{
+				Namespace: j.Namespace,
+				Region:    "global",
+			},
+		}
+		var resp structs.ServiceRegistrationByNameResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.ServiceRegistrationGetServiceRPCMethod, req, &resp))
+		must.Len(t, 4, resp.Services)
+
+		out := make(map[string]int, len(resp.Services))
+		for _, reg := range resp.Services {
+			out[reg.AllocID] = reg.Weight
+		}
+		return out
+	}
+
+	// The new version gets no traffic until the deployment is promoted
+	must.Eq(t, map[string]int{
+		allocs[0].ID: 1,
+		allocs[1].ID: 1,
+		allocs[2].ID: 0,
+		allocs[3].ID: 0,
+	}, weights())
+
+	req := &structs.DeploymentPromoteRequest{
+		DeploymentID: d.ID,
+		All:          true,
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var resp structs.DeploymentUpdateResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Deployment.Promote", req, &resp))
+
+	// The traffic switches to the new version with the promotion
+	must.Eq(t, map[string]int{
+		allocs[0].ID: 0,
+		allocs[1].ID: 0,
+		allocs[2].ID: 1,
+		allocs[3].ID: 1,
+	}, weights())
+
+	// The allocations of the previous version are stopped by the evaluation
+	// of the promotion
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool {
+			for _, a := range allocs[:2] {
+				out, err := state.AllocByID(nil, a.ID)
+				must.NoError(t, err)
+				if out.DesiredStatus != structs.AllocDesiredStatusStop {
+					return false
+				}
+			}
+			return true
+		}),
+		wait.Timeout(10*time.Second),
+		wait.Gap(50*time.Millisecond),
+	))
+	for _, a := range allocs[2:] {
+		out, err := state.AllocByID(nil, a.ID)
+		must.NoError(t, err)
+		must.Eq(t, structs.AllocDesiredStatusRun, out.DesiredStatus)
+	}
+}
+
 func TestDeploymentEndpoint_Promote_ACL(t *testing.T) {
 	ci.Parallel(t)
 
@@ -66,11 +66,11 @@ type deploymentWatcher struct {
 	deploymentTriggers
 
 	// DeploymentRPC holds methods for interacting with peer regions
-	// in enterprise edition
+	// in multiregion deployments
 	DeploymentRPC
 
 	// JobRPC holds methods for interacting with peer regions
-	// in enterprise edition
+	// in multiregion deployments
 	JobRPC
 
 	// state is the state that is watched for state changes.
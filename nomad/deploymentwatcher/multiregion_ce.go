@@ -6,26 +6,290 @@
 
 package deploymentwatcher
 
-import "github.com/hashicorp/nomad/nomad/structs"
+import (
+	"fmt"
 
-// DeploymentRPC and JobRPC hold methods for interacting with peer regions
-// in enterprise edition.
-type DeploymentRPC interface{}
-type JobRPC interface{}
+	"github.com/hashicorp/nomad/nomad/structs"
+)
 
+// DeploymentRPC holds the methods used to coordinate the deployments of peer
+// regions in a multiregion deployment.
+type DeploymentRPC interface {
+	Run(args *structs.DeploymentRunRequest, reply *structs.DeploymentUpdateResponse) error
+	Unblock(args *structs.DeploymentUnblockRequest, reply *structs.DeploymentUpdateResponse) error
+	Fail(args *structs.DeploymentFailRequest, reply *structs.DeploymentUpdateResponse) error
+}
+
+// JobRPC holds the methods used to find the deployments of peer regions in a
+// multiregion deployment.
+type JobRPC interface {
+	LatestDeployment(args *structs.JobSpecificRequest, reply *structs.SingleDeploymentResponse) error
+}
+
+// regionDeployment is the latest deployment of the job in a region of a
+// multiregion deployment. The deployment is nil if the region has none.
+type regionDeployment struct {
+	region string
+	d      *structs.Deployment
+}
+
+// nextRegion moves a multiregion deployment along once this region's
+// deployment is blocked or has failed. Regions are deployed in the order they
+// are declared, with up to max_parallel regions running at a time:
+//
+//   - blocked: pending regions are run while there are free slots, and once
+//     every region is blocked they are all unblocked.
+//   - failed: the regions to fail are chosen by the on_failure strategy. With
+//     fail_local the remaining regions carry on as though this region was
+//     blocked, but are left blocked for an operator to unblock.
 func (w *deploymentWatcher) nextRegion(status string) error {
+	d := w.getDeployment()
+	if !d.IsMultiregion || !w.j.IsMultiregion() {
+		return nil
+	}
+
+	switch status {
+	case structs.DeploymentStatusBlocked:
+	case structs.DeploymentStatusFailed:
+		// A deployment that is already failed was failed by an operator or by
+		// a peer region, and the failure isn't propagated any further.
+		if w.getStatus() == structs.DeploymentStatusFailed {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	token, err := w.multiregionToken()
+	if err != nil {
+		return err
+	}
+
+	regions, err := w.regionDeployments(token)
+	if err != nil {
+		return err
+	}
+
+	if status == structs.DeploymentStatusFailed {
+		strategy := w.j.Multiregion.Strategy
+		if strategy == nil || strategy.OnFailure != structs.MultiregionOnFailureFailLocal {
+			return w.failRegions(regions, token)
+		}
+
+		// This region's failure isn't committed yet, so it mustn't be counted
+		// as running
+		for _, r := range regions {
+			if r.region == w.j.Region && r.d != nil {
+				r.d = r.d.Copy()
+				r.d.Status = structs.DeploymentStatusFailed
+			}
+		}
+	}
+
+	return w.runRegions(regions, token)
+}
+
+// runRegions runs pending regions while fewer than max_parallel regions are
+// running, and unblocks every region once they're all blocked.
+func (w *deploymentWatcher) runRegions(regions []*regionDeployment, token string) error {
+	maxParallel := len(regions)
+	if strategy := w.j.Multiregion.Strategy; strategy != nil && strategy.MaxParallel > 0 {
+		maxParallel = min(strategy.MaxParallel, maxParallel)
+	}
+
+	running, allBlocked := 0, true
+	var pending []*regionDeployment
+	for _, r := range regions {
+		if r.d == nil {
+			continue
+		}
+		switch r.d.Status {
+		case structs.DeploymentStatusRunning, structs.DeploymentStatusPaused:
+			running++
+			allBlocked = false
+		case structs.DeploymentStatusInitializing, structs.DeploymentStatusPending:
+			pending = append(pending, r)
+			allBlocked = false
+		case structs.DeploymentStatusBlocked, structs.DeploymentStatusUnblocking,
+			structs.DeploymentStatusSuccessful:
+		default:
+			allBlocked = false
+		}
+	}
+
+	for _, r := range pending {
+		if running >= maxParallel {
+			return nil
+		}
+
+		req := &structs.DeploymentRunRequest{
+			DeploymentID: r.d.ID,
+			WriteRequest: w.regionWriteRequest(r.region, token),
+		}
+		if err := w.Run(req, &structs.DeploymentUpdateResponse{}); err != nil {
+			return fmt.Errorf("failed to run deployment in region %q: %w", r.region, err)
+		}
+		w.logger.Debug("ran deployment in peer region", "region", r.region)
+		running++
+	}
+
+	if !allBlocked {
+		return nil
+	}
+
+	for _, r := range regions {
+		if r.d == nil || r.d.Status != structs.DeploymentStatusBlocked {
+			continue
+		}
+
+		req := &structs.DeploymentUnblockRequest{
+			DeploymentID: r.d.ID,
+			WriteRequest: w.regionWriteRequest(r.region, token),
+		}
+		if err := w.Unblock(req, &structs.DeploymentUpdateResponse{}); err != nil {
+			return fmt.Errorf("failed to unblock deployment in region %q: %w", r.region, err)
+		}
+	}
+	w.logger.Debug("unblocked multiregion deployment")
 	return nil
 }
 
+// failRegions fails the active deployments of the regions chosen by the
+// on_failure strategy: the regions after this one by default, or every
+// region with fail_all. Each region rolls back according to its own update
+// block.
+func (w *deploymentWatcher) failRegions(regions []*regionDeployment, token string) error {
+	failAll := w.j.Multiregion.Strategy != nil &&
+		w.j.Multiregion.Strategy.OnFailure == structs.MultiregionOnFailureFailAll
+
+	after := false
+	for _, r := range regions {
+		if r.region == w.j.Region {
+			after = true
+			continue
+		}
+		if !failAll && !after {
+			continue
+		}
+		if r.d == nil || !r.d.Active() {
+			continue
+		}
+
+		req := &structs.DeploymentFailRequest{
+			DeploymentID: r.d.ID,
+			WriteRequest: w.regionWriteRequest(r.region, token),
+		}
+		if err := w.Fail(req, &structs.DeploymentUpdateResponse{}); err != nil {
+			return fmt.Errorf("failed to fail deployment in region %q: %w", r.region, err)
+		}
+		w.logger.Debug("failed deployment in peer region", "region", r.region)
+	}
+
+	return nil
+}
+
+// regionDeployments returns the latest multiregion deployment of the job in
+// each of its regions, in region order.
+func (w *deploymentWatcher) regionDeployments(token string) ([]*regionDeployment, error) {
+	regions := make([]*regionDeployment, 0, len(w.j.Multiregion.Regions))
+	for _, region := range w.j.Multiregion.Regions {
+		r := &regionDeployment{region: region.Name}
+		regions = append(regions, r)
+
+		if region.Name == w.j.Region {
+			r.d = w.getDeployment()
+			continue
+		}
+
+		req := &structs.JobSpecificRequest{
+			JobID: w.j.ID,
+			QueryOptions: structs.QueryOptions{
+				Region:    region.Name,
+				Namespace: w.j.Namespace,
+				AuthToken: token,
+			},
+		}
+		var resp structs.SingleDeploymentResponse
+		if err := w.LatestDeployment(req, &resp); err != nil {
+			return nil, fmt.Errorf("failed to get deployment in region %q: %w", region.Name, err)
+		}
+		if resp.Deployment != nil && resp.Deployment.IsMultiregion {
+			r.d = resp.Deployment
+		}
+	}
+
+	return regions, nil
+}
+
+// multiregionToken returns the secret of the ACL token that registered the
+// job, which is used to authenticate with the peer regions. ACL tokens are
+// only available in peer regions if they're global.
+func (w *deploymentWatcher) multiregionToken() (string, error) {
+	if w.j.NomadTokenID == "" {
+		return "", nil
+	}
+
+	token, err := w.state.ACLTokenByAccessorID(nil, w.j.NomadTokenID)
+	if err != nil {
+		return "", err
+	}
+	if token == nil {
+		return "", fmt.Errorf("ACL token %q that registered the job not found", w.j.NomadTokenID)
+	}
+	return token.SecretID, nil
+}
+
+func (w *deploymentWatcher) regionWriteRequest(region, token string) structs.WriteRequest {
+	return structs.WriteRequest{
+		Region:    region,
+		Namespace: w.j.Namespace,
+		AuthToken: token,
+	}
+}
+
 // RunDeployment is used to run a pending multiregion deployment.  In
 // single-region deployments, the pending state is unused.
 func (w *deploymentWatcher) RunDeployment(req *structs.DeploymentRunRequest, resp *structs.DeploymentUpdateResponse) error {
+	switch w.getStatus() {
+	case structs.DeploymentStatusInitializing, structs.DeploymentStatusPending:
+	default:
+		// The deployment was already run, possibly by another region
+		return nil
+	}
+
+	// Create an evaluation so the scheduler makes the placements it held
+	// while the deployment was pending
+	update := w.getDeploymentStatusUpdate(structs.DeploymentStatusRunning, structs.DeploymentStatusDescriptionRunning)
+	eval := w.getEval()
+	i, err := w.upsertDeploymentStatusUpdate(update, eval, nil)
+	if err != nil {
+		return err
+	}
+
+	// Build the response
+	resp.EvalID = eval.ID
+	resp.EvalCreateIndex = i
+	resp.DeploymentModifyIndex = i
+	resp.Index = i
 	return nil
 }
 
 // UnblockDeployment is used to unblock a multiregion deployment.  In
 // single-region deployments, the blocked state is unused.
 func (w *deploymentWatcher) UnblockDeployment(req *structs.DeploymentUnblockRequest, resp *structs.DeploymentUpdateResponse) error {
+	if w.getStatus() != structs.DeploymentStatusBlocked {
+		return fmt.Errorf("deployment is %s, not blocked", w.getStatus())
+	}
+
+	update := w.getDeploymentStatusUpdate(structs.DeploymentStatusSuccessful, structs.DeploymentStatusDescriptionSuccessful)
+	i, err := w.upsertDeploymentStatusUpdate(update, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	// Build the response
+	resp.DeploymentModifyIndex = i
+	resp.Index = i
 	return nil
 }
 
@@ -0,0 +1,246 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+//go:build !ent
+// +build !ent
+
+package deploymentwatcher
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+	mocker "github.com/stretchr/testify/mock"
+)
+
+// mockRegionRPC serves the latest deployment of each region and records the
+// regions the deployment RPCs are sent to.
+type mockRegionRPC struct {
+	lock        sync.Mutex
+	deployments map[string]*structs.Deployment
+	runs        []string
+	unblocks    []string
+	fails       []string
+}
+
+func (m *mockRegionRPC) LatestDeployment(args *structs.JobSpecificRequest, reply *structs.SingleDeploymentResponse) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	reply.Deployment = m.deployments[args.Region]
+	return nil
+}
+
+func (m *mockRegionRPC) Run(args *structs.DeploymentRunRequest, reply *structs.DeploymentUpdateResponse) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.runs = append(m.runs, args.Region)
+	return nil
+}
+
+func (m *mockRegionRPC) Unblock(args *structs.DeploymentUnblockRequest, reply *structs.DeploymentUpdateResponse) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.unblocks = append(m.unblocks, args.Region)
+	return nil
+}
+
+func (m *mockRegionRPC) Fail(args *structs.DeploymentFailRequest, reply *structs.DeploymentUpdateResponse) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.fails = append(m.fails, args.Region)
+	return nil
+}
+
+func TestDeploymentWatcher_NextRegion(t *testing.T) {
+	ci.Parallel(t)
+
+	regionDeploy := func(status string) *structs.Deployment {
+		d := mock.Deployment()
+		d.IsMultiregion = true
+		d.Status = status
+		return d
+	}
+
+	cases := []struct {
+		name        string
+		maxParallel int
+		onFailure   string
+		status      string
+		local       string
+		peers       map[string]string
+		expRuns     []string
+		expUnblocks []string
+		expFails    []string
+	}{
+		{
+			name:        "blocked runs next pending region",
+			maxParallel: 1,
+			status:      structs.DeploymentStatusBlocked,
+			local:       structs.DeploymentStatusBlocked,
+			peers: map[string]string{
+				"east":  structs.DeploymentStatusPending,
+				"north": structs.DeploymentStatusPending,
+			},
+			expRuns: []string{"east"},
+		},
+		{
+			name:        "blocked runs pending regions up to max parallel",
+			maxParallel: 2,
+			status:      structs.DeploymentStatusBlocked,
+			local:       structs.DeploymentStatusBlocked,
+			peers: map[string]string{
+				"east":  structs.DeploymentStatusPending,
+				"north": structs.DeploymentStatusPending,
+			},
+			expRuns: []string{"east", "north"},
+		},
+		{
+			name:        "blocked waits on running regions",
+			maxParallel: 1,
+			status:      structs.DeploymentStatusBlocked,
+			local:       structs.DeploymentStatusBlocked,
+			peers: map[string]string{
+				"east":  structs.DeploymentStatusBlocked,
+				"north": structs.DeploymentStatusRunning,
+			},
+		},
+		{
+			name:        "last blocked region unblocks all regions",
+			maxParallel: 1,
+			status:      structs.DeploymentStatusBlocked,
+			local:       structs.DeploymentStatusBlocked,
+			peers: map[string]string{
+				"east":  structs.DeploymentStatusBlocked,
+				"north": structs.DeploymentStatusBlocked,
+			},
+			expUnblocks: []string{"west", "east", "north"},
+		},
+		{
+			name:   "failed fails later regions by default",
+			status: structs.DeploymentStatusFailed,
+			local:  structs.DeploymentStatusRunning,
+			peers: map[string]string{
+				"east":  structs.DeploymentStatusPending,
+				"north": structs.DeploymentStatusPending,
+			},
+			expFails: []string{"east", "north"},
+		},
+		{
+			name:      "failed fails all regions",
+			onFailure: structs.MultiregionOnFailureFailAll,
+			status:    structs.DeploymentStatusFailed,
+			local:     structs.DeploymentStatusRunning,
+			peers: map[string]string{
+				"east":  structs.DeploymentStatusBlocked,
+				"north": structs.DeploymentStatusSuccessful,
+			},
+			expFails: []string{"east"},
+		},
+		{
+			name:        "failed locally runs next pending region",
+			maxParallel: 1,
+			onFailure:   structs.MultiregionOnFailureFailLocal,
+			status:      structs.DeploymentStatusFailed,
+			local:       structs.DeploymentStatusRunning,
+			peers: map[string]string{
+				"east":  structs.DeploymentStatusPending,
+				"north": structs.DeploymentStatusPending,
+			},
+			expRuns: []string{"east"},
+		},
+		{
+			name:      "failed locally leaves regions blocked",
+			onFailure: structs.MultiregionOnFailureFailLocal,
+			status:    structs.DeploymentStatusFailed,
+			local:     structs.DeploymentStatusRunning,
+			peers: map[string]string{
+				"east":  structs.DeploymentStatusBlocked,
+				"north": structs.DeploymentStatusBlocked,
+			},
+		},
+		{
+			name:   "failure by peer is not propagated",
+			status: structs.DeploymentStatusFailed,
+			local:  structs.DeploymentStatusFailed,
+			peers: map[string]string{
+				"east":  structs.DeploymentStatusPending,
+				"north": structs.DeploymentStatusPending,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := mock.MultiregionJob()
+			job.Region = "west"
+			job.Multiregion.Strategy.MaxParallel = tc.maxParallel
+			job.Multiregion.Strategy.OnFailure = tc.onFailure
+			job.Multiregion.Regions = append(job.Multiregion.Regions,
+				&structs.MultiregionRegion{Name: "north", Datacenters: []string{"north-1"}})
+
+			rpc := &mockRegionRPC{deployments: map[string]*structs.Deployment{}}
+			for region, status := range tc.peers {
+				rpc.deployments[region] = regionDeploy(status)
+			}
+
+			d := regionDeploy(tc.local)
+			w := &deploymentWatcher{
+				DeploymentRPC: rpc,
+				JobRPC:        rpc,
+				state:         state.TestStateStore(t),
+				deploymentID:  d.ID,
+				d:             d,
+				j:             job,
+				logger:        testlog.HCLogger(t),
+			}
+
+			must.NoError(t, w.nextRegion(tc.status))
+			must.Eq(t, tc.expRuns, rpc.runs)
+			must.Eq(t, tc.expUnblocks, rpc.unblocks)
+			must.Eq(t, tc.expFails, rpc.fails)
+		})
+	}
+}
+
+func TestWatcher_RunDeployment(t *testing.T) {
+	ci.Parallel(t)
+	w, m := defaultTestDeploymentWatcher(t)
+
+	// Create a job and a pending deployment
+	j := mock.Job()
+	d := mock.Deployment()
+	d.JobID = j.ID
+	d.Status = structs.DeploymentStatusPending
+	d.StatusDescription = structs.DeploymentStatusDescriptionPendingForPeer
+	must.NoError(t, m.state.UpsertJob(structs.MsgTypeTestSetup, m.nextIndex(), nil, j))
+	must.NoError(t, m.state.UpsertDeployment(m.nextIndex(), d))
+
+	// require that we get a call to UpsertDeploymentStatusUpdate
+	matchConfig := &matchDeploymentStatusUpdateConfig{
+		DeploymentID:      d.ID,
+		Status:            structs.DeploymentStatusRunning,
+		StatusDescription: structs.DeploymentStatusDescriptionRunning,
+		Eval:              true,
+	}
+	matcher := matchDeploymentStatusUpdateRequest(matchConfig)
+	m.On("UpdateDeploymentStatus", mocker.MatchedBy(matcher)).Return(nil)
+
+	w.SetEnabled(true, m.state)
+	testutil.WaitForResult(func() (bool, error) { return 1 == watchersCount(w), nil },
+		func(err error) { must.Eq(t, 1, watchersCount(w), must.Sprint("Should have 1 deployment")) })
+
+	req := &structs.DeploymentRunRequest{DeploymentID: d.ID}
+	var resp structs.DeploymentUpdateResponse
+	must.NoError(t, w.RunDeployment(req, &resp))
+	must.NotEq(t, "", resp.EvalID)
+
+	must.Eq(t, 1, watchersCount(w), must.Sprint("Deployment should still be active"))
+	m.AssertCalled(t, "UpdateDeploymentStatus", mocker.MatchedBy(matcher))
+}
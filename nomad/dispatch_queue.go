@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// dispatchQueueInterval is the minimum time between two releases of the
+	// queued dispatched jobs, to avoid releasing them on every change of the
+	// dispatched jobs of a busy parameterized job.
+	dispatchQueueInterval = 1 * time.Second
+)
+
+// runDispatchQueues releases the queued dispatched jobs of parameterized jobs
+// that are below their concurrency limit, whenever a job is queued or a job
+// of a parameterized job with queued jobs changes. It runs until the stop
+// channel is closed, when the server loses leadership.
+func (s *Server) runDispatchQueues(stopCh chan struct{}) {
+	for {
+		ws := memdb.NewWatchSet()
+		ws.Add(stopCh)
+		s.releaseQueuedDispatches(ws)
+		ws.Watch(nil)
+
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(dispatchQueueInterval):
+		}
+	}
+}
+
+// releaseQueuedDispatches creates the evaluations of the queued dispatched
+// jobs that can run.
+func (s *Server) releaseQueuedDispatches(ws memdb.WatchSet) {
+	s.dispatchQueueLock.Lock()
+	defer s.dispatchQueueLock.Unlock()
+
+	snap, err := s.State().Snapshot()
+	if err != nil {
+		s.logger.Error("failed to get state for dispatch queues", "error", err)
+		return
+	}
+
+	iter, err := snap.JobsByDispatchQueued(ws)
+	if err != nil {
+		s.logger.Error("failed to list queued dispatched jobs", "error", err)
+		return
+	}
+
+	// Find the parameterized jobs with queued dispatched jobs
+	parents := make(map[structs.NamespacedID]struct{})
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		parents[structs.NamespacedID{Namespace: job.Namespace, ID: job.ParentID}] = struct{}{}
+	}
+
+	for parent := range parents {
+		if err := s.releaseDispatchQueue(ws, snap, parent); err != nil {
+			s.logger.Error("failed to release queued dispatched jobs",
+				"namespace", parent.Namespace, "job_id", parent.ID, "error", err)
+		}
+	}
+}
+
+// releaseDispatchQueue creates the evaluations of the queued jobs dispatched
+// by the parameterized job, up to its concurrency limit. The queued jobs of
+// parameterized jobs that were purged or had their limit removed are all
+// released. The watch set is notified when the parameterized job or its
+// dispatched jobs change.
+func (s *Server) releaseDispatchQueue(ws memdb.WatchSet, snap *state.StateSnapshot, parent structs.NamespacedID) error {
+	running, queued, err := dispatchedJobs(ws, snap, parent.Namespace, parent.ID)
+	if err != nil {
+		return err
+	}
+
+	release := len(queued)
+	job, err := snap.JobByID(ws, parent.Namespace, parent.ID)
+	if err != nil {
+		return err
+	}
+	if job != nil && job.ParameterizedJob != nil && job.ParameterizedJob.MaxConcurrent > 0 {
+		release = min(release, job.ParameterizedJob.MaxConcurrent-running)
+	}
+	if release <= 0 {
+		return nil
+	}
+
+	now := time.Now().UnixNano()
+	evals := make([]*structs.Evaluation, 0, release)
+	for _, queuedJob := range queued[:release] {
+		evals = append(evals, &structs.Evaluation{
+			ID:             uuid.Generate(),
+			Namespace:      queuedJob.Namespace,
+			Priority:       queuedJob.Priority,
+			Type:           queuedJob.Type,
+			TriggeredBy:    structs.EvalTriggerJobRegister,
+			JobID:          queuedJob.ID,
+			JobModifyIndex: queuedJob.JobModifyIndex,
+			Status:         structs.EvalStatusPending,
+			CreateTime:     now,
+			ModifyTime:     now,
+		})
+	}
+
+	s.logger.Debug("releasing queued dispatched jobs",
+		"namespace", parent.Namespace, "job_id", parent.ID, "released", release, "queued", len(queued))
+	update := &structs.JobDispatchReleaseRequest{
+		Evals:        evals,
+		WriteRequest: structs.WriteRequest{Region: s.Region()},
+	}
+	_, _, err = s.raftApply(structs.JobDispatchReleaseRequestType, update)
+	return err
+}
+
+// dispatchedJobs returns the number of running jobs dispatched by the
+// parameterized job, and its queued dispatched jobs in the order they are
+// released: highest priority first, then oldest first. Stopped dispatched
+// jobs are neither running nor queued.
+func dispatchedJobs(ws memdb.WatchSet, snap *state.StateSnapshot, namespace, parentID string) (int, []*structs.Job, error) {
+	iter, err := snap.JobsByIDPrefix(ws, namespace, parentID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var running int
+	var queued []*structs.Job
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		if job.ParentID != parentID || !job.Dispatched || job.Stop ||
+			job.Status == structs.JobStatusDead {
+			continue
+		}
+
+		if job.DispatchQueued {
+			queued = append(queued, job)
+		} else {
+			running++
+		}
+	}
+
+	slices.SortStableFunc(queued, func(a, b *structs.Job) int {
+		if a.Priority != b.Priority {
+			return cmp.Compare(b.Priority, a.Priority)
+		}
+		return cmp.Compare(a.CreateIndex, b.CreateIndex)
+	})
+	return running, queued, nil
+}
@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+)
+
+func TestServer_DispatchQueue(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	job := mock.BatchJob()
+	job.ParameterizedJob = &structs.ParameterizedJobConfig{
+		MaxConcurrent: 1,
+		QueueLimit:    2,
+	}
+	regReq := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var regResp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", regReq, &regResp))
+
+	dispatch := func(priority int) (*structs.JobDispatchResponse, error) {
+		req := &structs.JobDispatchRequest{
+			JobID:    job.ID,
+			Priority: priority,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobDispatchResponse
+		err := msgpackrpc.CallWithCodec(codec, "Job.Dispatch", req, &resp)
+		return &resp, err
+	}
+
+	// The first dispatched job runs and the next ones are queued
+	running, err := dispatch(0)
+	must.NoError(t, err)
+	must.False(t, running.Queued)
+	must.NotEq(t, "", running.EvalID)
+
+	low, err := dispatch(40)
+	must.NoError(t, err)
+	must.True(t, low.Queued)
+	must.Eq(t, "", low.EvalID)
+
+	high, err := dispatch(80)
+	must.NoError(t, err)
+	must.True(t, high.Queued)
+
+	_, err = dispatch(0)
+	must.ErrorContains(t, err, "is full")
+
+	_, err = dispatch(200)
+	must.ErrorContains(t, err, "dispatch priority must be between")
+
+	snap, err := store.Snapshot()
+	must.NoError(t, err)
+	count, queued, err := dispatchedJobs(nil, snap, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, 1, count)
+	must.Len(t, 2, queued)
+	must.Eq(t, high.DispatchedJobID, queued[0].ID)
+	must.Eq(t, low.DispatchedJobID, queued[1].ID)
+	must.Eq(t, 80, queued[0].Priority)
+
+	hasEvals := func(jobID string) bool {
+		evals, err := store.EvalsByJob(nil, job.Namespace, jobID)
+		must.NoError(t, err)
+		return len(evals) > 0
+	}
+
+	// Completing the running dispatched job releases the queued job with the
+	// highest priority
+	eval, err := store.EvalByID(nil, running.EvalID)
+	must.NoError(t, err)
+	eval = eval.Copy()
+	eval.Status = structs.EvalStatusComplete
+	must.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, running.Index+100,
+		[]*structs.Evaluation{eval}))
+
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool { return hasEvals(high.DispatchedJobID) }),
+		wait.Timeout(5*time.Second),
+		wait.Gap(50*time.Millisecond),
+	))
+	must.False(t, hasEvals(low.DispatchedJobID))
+
+	// The released job is no longer queued
+	released, err := store.JobByID(nil, job.Namespace, high.DispatchedJobID)
+	must.NoError(t, err)
+	must.False(t, released.DispatchQueued)
+
+	iter, err := store.JobsByDispatchQueued(nil)
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	must.Eq(t, low.DispatchedJobID, raw.(*structs.Job).ID)
+	must.Nil(t, iter.Next())
+}
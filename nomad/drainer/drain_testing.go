@@ -123,6 +123,26 @@ func (m *MockRaftApplierShim) NodesDrainComplete(
 	return index, err
 }
 
+// CSIVolumesClaimRelease mocks a write to raft as a state store update
+func (m *MockRaftApplierShim) CSIVolumesClaimRelease(
+	claims []structs.CSIVolumeClaimRequest) (uint64, error) {
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	index, _ := m.state.LatestIndex()
+	index++
+
+	for _, req := range claims {
+		err := m.state.CSIVolumeClaim(index, req.RequestNamespace(),
+			req.VolumeID, req.ToClaim())
+		if err != nil {
+			return index, err
+		}
+	}
+	return index, nil
+}
+
 func testNodeDrainWatcher(t *testing.T) (*nodeDrainWatcher, *state.StateStore, *NodeDrainer) {
 	t.Helper()
 	store := state.TestStateStore(t)
@@ -21,6 +21,10 @@ var (
 	// stateReadErrorDelay is the delay to apply before retrying reading state
 	// when there is an error
 	stateReadErrorDelay = 1 * time.Second
+
+	// csiDetachCheckInterval is how often nodes waiting on the CSI volumes of
+	// drained allocations to be unpublished are checked
+	csiDetachCheckInterval = 5 * time.Second
 )
 
 const (
@@ -49,6 +53,7 @@ const (
 type RaftApplier interface {
 	AllocUpdateDesiredTransition(allocs map[string]*structs.DesiredTransition, evals []*structs.Evaluation) (uint64, error)
 	NodesDrainComplete(nodes []string, event *structs.NodeEvent) (uint64, error)
+	CSIVolumesClaimRelease(claims []structs.CSIVolumeClaimRequest) (uint64, error)
 }
 
 // NodeTracker is the interface to notify an object that is tracking draining
@@ -226,12 +231,17 @@ func (n *NodeDrainer) flush(state *state.StateStore) {
 // run is a long lived event handler that receives changes from the relevant
 // watchers and takes action based on them.
 func (n *NodeDrainer) run(ctx context.Context) {
+	detachTicker := time.NewTicker(csiDetachCheckInterval)
+	defer detachTicker.Stop()
+
 	for {
 		select {
 		case <-n.ctx.Done():
 			return
 		case nodes := <-n.deadlineNotifier.NextBatch():
 			n.handleDeadlinedNodes(nodes)
+		case <-detachTicker.C:
+			n.handleDetachingNodes()
 		case req := <-n.jobWatcher.Drain():
 			n.handleJobAllocDrain(req)
 		case allocs := <-n.jobWatcher.Migrated():
@@ -300,7 +310,6 @@ func (n *NodeDrainer) handleMigratedAllocs(allocs []*structs.Allocation) {
 	}
 
 	var done []string
-	var remainingAllocs []*structs.Allocation
 
 	// For each node, check if it is now done
 	n.l.RLock()
@@ -321,6 +330,82 @@ func (n *NodeDrainer) handleMigratedAllocs(allocs []*structs.Allocation) {
 		}
 
 		done = append(done, node)
+	}
+	n.l.RUnlock()
+
+	n.completeDrains(done)
+}
+
+// handleDetachingNodes checks the nodes that are waiting on the CSI volumes of
+// drained allocations to be unpublished. Nodes whose volumes have been
+// unpublished are marked as done draining, and the claims of nodes that have
+// waited longer than their CSI detach timeout are released without waiting
+// on the node plugin.
+func (n *NodeDrainer) handleDetachingNodes() {
+	now := time.Now()
+
+	var done []string
+	var claims []structs.CSIVolumeClaimRequest
+
+	n.l.RLock()
+	for node, draining := range n.nodes {
+		since := draining.DetachingSince()
+		if since.IsZero() {
+			continue
+		}
+
+		isDone, err := draining.IsDone()
+		if err != nil {
+			n.logger.Error("error checking if node is done draining", "node_id", node, "error", err)
+			continue
+		}
+
+		if isDone {
+			done = append(done, node)
+			continue
+		}
+
+		timeout := draining.GetNode().DrainStrategy.CSIDetachTimeout
+		if timeout <= 0 || now.Sub(since) < timeout {
+			continue
+		}
+
+		pending, err := draining.PendingDetaches()
+		if err != nil {
+			n.logger.Error("error retrieving CSI volume claims on draining node", "node_id", node, "error", err)
+			continue
+		}
+
+		n.logger.Warn("CSI volumes not unpublished from draining node before timeout, releasing claims",
+			"node_id", node, "num_claims", len(pending))
+		claims = append(claims, pending...)
+	}
+	n.l.RUnlock()
+
+	if len(claims) > 0 {
+		if _, err := n.raft.CSIVolumesClaimRelease(claims); err != nil {
+			n.logger.Error("failed to release CSI volume claims on draining nodes", "error", err)
+		}
+	}
+
+	n.completeDrains(done)
+}
+
+// completeDrains stops the allocations remaining on nodes that are done
+// draining, such as those of system jobs, and then marks the nodes as done.
+func (n *NodeDrainer) completeDrains(done []string) {
+	if len(done) == 0 {
+		return
+	}
+
+	var remainingAllocs []*structs.Allocation
+
+	n.l.RLock()
+	for _, node := range done {
+		draining, ok := n.nodes[node]
+		if !ok {
+			continue
+		}
 
 		remaining, err := draining.RemainingAllocs()
 		if err != nil {
@@ -15,7 +15,12 @@ import (
 type drainingNode struct {
 	state *state.StateStore
 	node  *structs.Node
-	l     sync.RWMutex
+
+	// detachingSince is when the node was first found to be waiting only on
+	// the CSI volumes of drained allocations to be unpublished.
+	detachingSince time.Time
+
+	l sync.RWMutex
 }
 
 func NewDrainingNode(node *structs.Node, state *state.StateStore) *drainingNode {
@@ -52,10 +57,11 @@ func (n *drainingNode) DeadlineTime() (bool, time.Time) {
 
 // IsDone returns if the node is done draining batch and service allocs. System
 // allocs must be stopped before marking drain complete unless they're being
-// ignored.
+// ignored. The CSI volumes of the drained allocs must also be unpublished from
+// the node, so that the node plugins are not stopped before they are done.
 func (n *drainingNode) IsDone() (bool, error) {
-	n.l.RLock()
-	defer n.l.RUnlock()
+	n.l.Lock()
+	defer n.l.Unlock()
 
 	// Should never happen
 	if n.node == nil || n.node.DrainStrategy == nil {
@@ -81,9 +87,121 @@ func (n *drainingNode) IsDone() (bool, error) {
 		}
 	}
 
+	claims, err := n.pendingDetaches(allocs)
+	if err != nil {
+		return false, err
+	}
+	if len(claims) != 0 {
+		if n.detachingSince.IsZero() {
+			n.detachingSince = time.Now()
+		}
+		return false, nil
+	}
+
 	return true, nil
 }
 
+// DetachingSince returns when the node was first found to be waiting only on
+// the CSI volumes of drained allocations to be unpublished, or the zero time
+// if it isn't.
+func (n *drainingNode) DetachingSince() time.Time {
+	n.l.RLock()
+	defer n.l.RUnlock()
+	return n.detachingSince
+}
+
+// PendingDetaches returns the claims of drained allocations on CSI volumes
+// that have yet to be unpublished from the node. The claims are returned as
+// requests that release them without waiting on the node plugin.
+func (n *drainingNode) PendingDetaches() ([]structs.CSIVolumeClaimRequest, error) {
+	n.l.RLock()
+	defer n.l.RUnlock()
+
+	// Should never happen
+	if n.node == nil || n.node.DrainStrategy == nil {
+		return nil, fmt.Errorf("node doesn't have a drain strategy set")
+	}
+
+	allocs, err := n.state.AllocsByNode(nil, n.node.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.pendingDetaches(allocs)
+}
+
+func (n *drainingNode) pendingDetaches(allocs []*structs.Allocation) ([]structs.CSIVolumeClaimRequest, error) {
+	var claims []structs.CSIVolumeClaimRequest
+	for _, alloc := range allocs {
+		// Only the volumes of allocs that have been drained are checked, as
+		// system and plugin jobs are stopped after the drain is done.
+		if alloc.Job.Type == structs.JobTypeSystem || alloc.Job.IsPlugin() ||
+			!alloc.ClientTerminalStatus() {
+			continue
+		}
+
+		tg := alloc.Job.LookupTaskGroup(alloc.TaskGroup)
+		if tg == nil {
+			continue
+		}
+
+		for _, req := range tg.Volumes {
+			if req.Type != structs.VolumeTypeCSI {
+				continue
+			}
+
+			source := req.Source
+			if req.PerAlloc {
+				source = source + structs.AllocSuffix(alloc.Name)
+			}
+
+			vol, err := n.state.CSIVolumeByID(nil, alloc.Namespace, source)
+			if err != nil {
+				return nil, err
+			}
+			if vol == nil {
+				continue
+			}
+
+			claim := attachedClaim(vol, alloc.ID)
+			if claim == nil {
+				continue
+			}
+
+			claims = append(claims, structs.CSIVolumeClaimRequest{
+				VolumeID:     vol.ID,
+				AllocationID: alloc.ID,
+				NodeID:       n.node.ID,
+				Claim:        claim.Mode,
+				State:        structs.CSIVolumeClaimStateNodeDetached,
+				WriteRequest: structs.WriteRequest{
+					Namespace: vol.Namespace,
+				},
+			})
+		}
+	}
+
+	return claims, nil
+}
+
+// attachedClaim returns the claim of the alloc on the volume if it has yet to
+// be unpublished from the node.
+func attachedClaim(vol *structs.CSIVolume, allocID string) *structs.CSIVolumeClaim {
+	if claim, ok := vol.PastClaims[allocID]; ok {
+		switch claim.State {
+		case structs.CSIVolumeClaimStateNodeDetached,
+			structs.CSIVolumeClaimStateControllerDetached,
+			structs.CSIVolumeClaimStateReadyToFree:
+			return nil
+		}
+		return claim
+	}
+	if claim, ok := vol.ReadClaims[allocID]; ok {
+		return claim
+	}
+	return vol.WriteClaims[allocID]
+}
+
 // RemainingAllocs returns the set of allocations remaining on a node that
 // still need to be drained.
 func (n *drainingNode) RemainingAllocs() ([]*structs.Allocation, error) {
@@ -219,3 +219,56 @@ func TestDrainingNode_Table(t *testing.T) {
 		})
 	}
 }
+
+// TestDrainingNode_CSIDetach asserts that a node isn't done draining until the
+// CSI volumes of its drained allocs have been unpublished from it.
+func TestDrainingNode_CSIDetach(t *testing.T) {
+	ci.Parallel(t)
+
+	dn := testDrainingNode(t)
+
+	alloc := mock.Alloc()
+	alloc.NodeID = dn.node.ID
+	alloc.ClientStatus = structs.AllocClientStatusComplete
+	alloc.Job.TaskGroups[0].Volumes = map[string]*structs.VolumeRequest{
+		"data": {
+			Name:   "data",
+			Type:   structs.VolumeTypeCSI,
+			Source: "vol0",
+		},
+	}
+
+	vol := mock.CSIVolume(mock.CSIPlugin())
+	vol.ID = "vol0"
+	vol.WriteClaims[alloc.ID] = &structs.CSIVolumeClaim{
+		AllocationID: alloc.ID,
+		NodeID:       dn.node.ID,
+		Mode:         structs.CSIVolumeClaimWrite,
+		State:        structs.CSIVolumeClaimStateTaken,
+	}
+
+	require.Nil(t, dn.state.UpsertJob(structs.MsgTypeTestSetup, 101, nil, alloc.Job))
+	require.Nil(t, dn.state.UpsertAllocs(structs.MsgTypeTestSetup, 102, []*structs.Allocation{alloc}))
+	require.Nil(t, dn.state.UpsertCSIVolume(103, []*structs.CSIVolume{vol}))
+
+	// The claim is still attached, so the drain waits on it
+	assertDrainingNode(t, dn, false, 0, 0)
+	require.False(t, dn.DetachingSince().IsZero())
+
+	claims, err := dn.PendingDetaches()
+	require.NoError(t, err)
+	require.Len(t, claims, 1)
+	require.Equal(t, vol.ID, claims[0].VolumeID)
+	require.Equal(t, alloc.ID, claims[0].AllocationID)
+	require.Equal(t, structs.CSIVolumeClaimStateNodeDetached, claims[0].State)
+
+	// Releasing the claims completes the drain
+	raft := &MockRaftApplierShim{state: dn.state}
+	_, err = raft.CSIVolumesClaimRelease(claims)
+	require.NoError(t, err)
+
+	assertDrainingNode(t, dn, true, 0, 0)
+	claims, err = dn.PendingDetaches()
+	require.NoError(t, err)
+	require.Empty(t, claims)
+}
@@ -44,3 +44,11 @@ func (d drainerShim) AllocUpdateDesiredTransition(allocs map[string]*structs.Des
 	_, index, err := d.s.raftApply(structs.AllocUpdateDesiredTransitionRequestType, args)
 	return index, err
 }
+
+func (d drainerShim) CSIVolumesClaimRelease(claims []structs.CSIVolumeClaimRequest) (uint64, error) {
+	args := &structs.CSIVolumeClaimBatchRequest{
+		Claims: claims,
+	}
+	_, index, err := d.s.raftApply(structs.CSIVolumeClaimBatchRequestType, args)
+	return index, err
+}
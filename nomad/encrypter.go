@@ -51,6 +51,14 @@ type Encrypter struct {
 
 	keyring map[string]*keyset
 	lock    sync.RWMutex
+
+	// providers are the configured providers of the key encryption keys,
+	// keyed by provider ID. The aead provider doesn't use an external KMS
+	// and its entry is nil.
+	providers map[string]kekProvider
+
+	// activeProviderID is the ID of the provider that wraps new keys
+	activeProviderID string
 }
 
 // keyset contains the key material for variable encryption and workload
@@ -74,15 +82,59 @@ func NewEncrypter(srv *Server, keystorePath string) (*Encrypter, error) {
 		keystorePath: keystorePath,
 		keyring:      make(map[string]*keyset),
 		issuer:       srv.GetConfig().OIDCIssuer,
+		providers:    make(map[string]kekProvider),
+	}
+
+	err := encrypter.setupProviders(srv.GetConfig().KEKProviderConfigs)
+	if err != nil {
+		return nil, err
 	}
 
-	err := encrypter.loadKeystore()
+	err = encrypter.loadKeystore()
 	if err != nil {
 		return nil, err
 	}
 	return encrypter, nil
 }
 
+// setupProviders creates the configured providers of the key encryption
+// keys. Without any configuration the aead provider is used, which stores the
+// key encryption keys on disk alongside the root keys.
+func (e *Encrypter) setupProviders(configs []*structs.KEKProviderConfig) error {
+	if len(configs) == 0 {
+		configs = []*structs.KEKProviderConfig{{
+			Provider: structs.KEKProviderAEAD,
+			Active:   true,
+		}}
+	}
+
+	for _, conf := range configs {
+		if err := conf.Validate(); err != nil {
+			return err
+		}
+		id := conf.ID()
+		if _, ok := e.providers[id]; ok {
+			return fmt.Errorf("duplicate keyring provider %q", id)
+		}
+		provider, err := newKEKProvider(conf)
+		if err != nil {
+			return fmt.Errorf("could not configure keyring provider %q: %w", id, err)
+		}
+		e.providers[id] = provider
+
+		if conf.Active || len(configs) == 1 {
+			if e.activeProviderID != "" {
+				return fmt.Errorf("only one keyring provider can be active")
+			}
+			e.activeProviderID = id
+		}
+	}
+	if e.activeProviderID == "" {
+		return fmt.Errorf("one keyring provider must be active")
+	}
+	return nil
+}
+
 func (e *Encrypter) loadKeystore() error {
 
 	if err := os.MkdirAll(e.keystorePath, 0o700); err != nil {
@@ -128,6 +180,13 @@ func (e *Encrypter) loadKeystore() error {
 // root key, and returns the cipher text (including the nonce), and
 // the key ID used to encrypt it
 func (e *Encrypter) Encrypt(cleartext []byte) ([]byte, string, error) {
+	return e.EncryptBound(cleartext, "")
+}
+
+// EncryptBound is like Encrypt, but also includes the binding in the
+// signature inputs, so the cipher text can only be decrypted with the same
+// binding.
+func (e *Encrypter) EncryptBound(cleartext []byte, binding string) ([]byte, string, error) {
 
 	keyset, err := e.activeKeySet()
 	if err != nil {
@@ -140,7 +199,7 @@ func (e *Encrypter) Encrypt(cleartext []byte) ([]byte, string, error) {
 	}
 
 	keyID := keyset.rootKey.Meta.KeyID
-	additional := []byte(keyID) // include the keyID in the signature inputs
+	additional := []byte(keyID + binding) // include the keyID in the signature inputs
 
 	// we use the nonce as the dst buffer so that the ciphertext is
 	// appended to that buffer and we always keep the nonce and
@@ -153,6 +212,12 @@ func (e *Encrypter) Encrypt(cleartext []byte) ([]byte, string, error) {
 // Decrypt takes an encrypted buffer and then root key ID. It extracts
 // the nonce, decrypts the content, and returns the cleartext data.
 func (e *Encrypter) Decrypt(ciphertext []byte, keyID string) ([]byte, error) {
+	return e.DecryptBound(ciphertext, keyID, "")
+}
+
+// DecryptBound decrypts cipher text encrypted by EncryptBound with the same
+// binding.
+func (e *Encrypter) DecryptBound(ciphertext []byte, keyID, binding string) ([]byte, error) {
 	e.lock.RLock()
 	defer e.lock.RUnlock()
 
@@ -162,8 +227,11 @@ func (e *Encrypter) Decrypt(ciphertext []byte, keyID string) ([]byte, error) {
 	}
 
 	nonceSize := keyset.cipher.NonceSize()
-	nonce := ciphertext[:nonceSize] // nonce was stored alongside ciphertext
-	additional := []byte(keyID)     // keyID was included in the signature inputs
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+	nonce := ciphertext[:nonceSize]       // nonce was stored alongside ciphertext
+	additional := []byte(keyID + binding) // keyID was included in the signature inputs
 
 	return keyset.cipher.Open(nil, nonce, ciphertext[nonceSize:], additional)
 }
@@ -223,7 +291,16 @@ func (e *Encrypter) SignClaims(claims *structs.IdentityClaims) (string, string,
 		}
 	}
 
-	raw, err := jwt.Signed(sig).Claims(claims).CompactSerialize()
+	builder := jwt.Signed(sig).Claims(claims)
+	if len(claims.ExtraClaims) > 0 {
+		// The builder only accepts structs and maps of interface values
+		extra := make(map[string]any, len(claims.ExtraClaims))
+		for name, value := range claims.ExtraClaims {
+			extra[name] = value
+		}
+		builder = builder.Claims(extra)
+	}
+	raw, err := builder.CompactSerialize()
 	if err != nil {
 		return "", "", err
 	}
@@ -403,7 +480,19 @@ func (e *Encrypter) saveKeyToStore(rootKey *structs.RootKey) error {
 	kekWrapper := &structs.KeyEncryptionKeyWrapper{
 		Meta:                       rootKey.Meta,
 		EncryptedDataEncryptionKey: rootBlob.Ciphertext,
-		KeyEncryptionKey:           kek,
+		ProviderID:                 e.activeProviderID,
+	}
+
+	// Wrap the KEK with the active provider's KMS, if any, so it never
+	// touches the disk in cleartext
+	if provider := e.providers[e.activeProviderID]; provider != nil {
+		wrappedKEK, err := provider.wrap(e.srv.shutdownCtx, kek)
+		if err != nil {
+			return fmt.Errorf("failed to wrap key encryption key: %w", err)
+		}
+		kekWrapper.WrappedKeyEncryptionKey = wrappedKEK
+	} else {
+		kekWrapper.KeyEncryptionKey = kek
 	}
 
 	// Only keysets created after 1.7.0 will contain an RSA key.
@@ -446,9 +535,28 @@ func (e *Encrypter) loadKeyFromStore(path string) (*structs.RootKey, error) {
 		return nil, err
 	}
 
+	// Keys written before keyring providers existed have no provider ID and
+	// were wrapped by the aead provider
+	providerID := kekWrapper.ProviderID
+	if providerID == "" {
+		providerID = string(structs.KEKProviderAEAD)
+	}
+
+	kek := kekWrapper.KeyEncryptionKey
+	if len(kekWrapper.WrappedKeyEncryptionKey) > 0 {
+		provider := e.providers[providerID]
+		if provider == nil {
+			return nil, fmt.Errorf("key is wrapped by keyring provider %q, which is not configured", providerID)
+		}
+		kek, err = provider.unwrap(e.srv.shutdownCtx, kekWrapper.WrappedKeyEncryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to unwrap key encryption key: %w", err)
+		}
+	}
+
 	// the errors that bubble up from this library can be a bit opaque, so make
 	// sure we wrap them with as much context as possible
-	wrapper, err := e.newKMSWrapper(meta.KeyID, kekWrapper.KeyEncryptionKey)
+	wrapper, err := e.newKMSWrapper(meta.KeyID, kek)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create key wrapper cipher: %w", err)
 	}
@@ -472,11 +580,24 @@ func (e *Encrypter) loadKeyFromStore(path string) (*structs.RootKey, error) {
 		}
 	}
 
-	return &structs.RootKey{
+	rootKey := &structs.RootKey{
 		Meta:   meta,
 		Key:    key,
 		RSAKey: rsaKey,
-	}, nil
+	}
+
+	// Wrap keys again that were wrapped by a provider that is no longer
+	// active, so that the inactive provider can be removed
+	if providerID != e.activeProviderID {
+		if err := e.saveKeyToStore(rootKey); err != nil {
+			return nil, fmt.Errorf("unable to wrap key with keyring provider %q: %w",
+				e.activeProviderID, err)
+		}
+		e.srv.logger.Named("keyring").Info("wrapped root key with active keyring provider",
+			"key_id", meta.KeyID, "previous_provider", providerID, "provider", e.activeProviderID)
+	}
+
+	return rootKey, nil
 }
 
 // GetPublicKey returns the public signing key for the requested key id or an
@@ -508,9 +629,8 @@ func (e *Encrypter) GetPublicKey(keyID string) (*structs.KeyringPublicKey, error
 }
 
 // newKMSWrapper returns a go-kms-wrapping interface the caller can use to
-// encrypt the RootKey with a key encryption key (KEK). This is a bit of
-// security theatre for local on-disk key material, but gives us a shim for
-// external KMS providers in the future.
+// encrypt the RootKey with a key encryption key (KEK). The KEK itself is
+// wrapped by the active keyring provider's KMS, if one is configured.
 func (e *Encrypter) newKMSWrapper(keyID string, kek []byte) (kms.Wrapper, error) {
 	wrapper := aead.NewWrapper()
 	wrapper.SetConfig(context.Background(),
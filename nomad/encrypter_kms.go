@@ -0,0 +1,260 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	awskms "github.com/aws/aws-sdk-go/service/kms"
+	vapi "github.com/hashicorp/vault/api"
+	"google.golang.org/api/cloudkms/v1"
+	"google.golang.org/api/option"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// kekProvider wraps and unwraps the key encryption keys (KEK) of the keystore
+// with an external KMS, so that the key material on disk can't be decrypted
+// without access to the KMS.
+type kekProvider interface {
+	wrap(ctx context.Context, kek []byte) ([]byte, error)
+	unwrap(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// newKEKProvider returns the provider for an external KMS configuration. The
+// aead provider has no external KMS and returns nil.
+func newKEKProvider(conf *structs.KEKProviderConfig) (kekProvider, error) {
+	switch conf.Provider {
+	case structs.KEKProviderAEAD:
+		return nil, nil
+	case structs.KEKProviderAWSKMS:
+		return newAWSKMSProvider(conf.Config)
+	case structs.KEKProviderGCPCloudKMS:
+		return newGCPCloudKMSProvider(conf.Config)
+	case structs.KEKProviderVaultTransit:
+		return newVaultTransitProvider(conf.Config)
+	default:
+		return nil, fmt.Errorf("unknown keyring provider %q", conf.Provider)
+	}
+}
+
+// kekConfigValue returns the value of the key from the provider configuration,
+// falling back to the environment variable if set.
+func kekConfigValue(config map[string]string, key, envVar string) string {
+	if v := config[key]; v != "" {
+		return v
+	}
+	if envVar != "" {
+		return os.Getenv(envVar)
+	}
+	return ""
+}
+
+// awsKMSProvider wraps keys with a symmetric AWS KMS key.
+type awsKMSProvider struct {
+	client *awskms.KMS
+	keyID  string
+}
+
+func newAWSKMSProvider(config map[string]string) (*awsKMSProvider, error) {
+	keyID := kekConfigValue(config, "kms_key_id", "AWSKMS_WRAPPER_KEY_ID")
+	if keyID == "" {
+		return nil, fmt.Errorf("awskms keyring requires kms_key_id")
+	}
+
+	cfg := aws.NewConfig()
+	if region := kekConfigValue(config, "region", "AWS_REGION"); region != "" {
+		cfg = cfg.WithRegion(region)
+	}
+	if endpoint := kekConfigValue(config, "endpoint", "AWS_KMS_ENDPOINT"); endpoint != "" {
+		cfg = cfg.WithEndpoint(endpoint)
+	}
+	if accessKey := config["access_key"]; accessKey != "" {
+		cfg = cfg.WithCredentials(credentials.NewStaticCredentials(
+			accessKey, config["secret_key"], config["session_token"]))
+	}
+
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *cfg,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+	}
+	return &awsKMSProvider{client: awskms.New(sess), keyID: keyID}, nil
+}
+
+func (p *awsKMSProvider) wrap(ctx context.Context, kek []byte) ([]byte, error) {
+	out, err := p.client.EncryptWithContext(ctx, &awskms.EncryptInput{
+		KeyId:     aws.String(p.keyID),
+		Plaintext: kek,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap key with AWS KMS: %w", err)
+	}
+	return out.CiphertextBlob, nil
+}
+
+func (p *awsKMSProvider) unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	out, err := p.client.DecryptWithContext(ctx, &awskms.DecryptInput{
+		KeyId:          aws.String(p.keyID),
+		CiphertextBlob: wrapped,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap key with AWS KMS: %w", err)
+	}
+	return out.Plaintext, nil
+}
+
+// gcpCloudKMSProvider wraps keys with a symmetric GCP Cloud KMS crypto key.
+type gcpCloudKMSProvider struct {
+	service *cloudkms.Service
+	name    string
+}
+
+func newGCPCloudKMSProvider(config map[string]string) (*gcpCloudKMSProvider, error) {
+	project := kekConfigValue(config, "project", "GOOGLE_PROJECT")
+	region := kekConfigValue(config, "region", "GOOGLE_REGION")
+	keyRing := kekConfigValue(config, "key_ring", "GCPCKMS_WRAPPER_KEY_RING")
+	cryptoKey := kekConfigValue(config, "crypto_key", "GCPCKMS_WRAPPER_CRYPTO_KEY")
+	if region == "" {
+		region = "global"
+	}
+	if project == "" || keyRing == "" || cryptoKey == "" {
+		return nil, fmt.Errorf("gcpckms keyring requires project, key_ring, and crypto_key")
+	}
+
+	var opts []option.ClientOption
+	if creds := kekConfigValue(config, "credentials", "GOOGLE_CREDENTIALS"); creds != "" {
+		opts = append(opts, option.WithCredentialsFile(creds))
+	}
+	service, err := cloudkms.NewService(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCP Cloud KMS client: %w", err)
+	}
+
+	return &gcpCloudKMSProvider{
+		service: service,
+		name: fmt.Sprintf("projects/%s/locations/%s/keyRings/%s/cryptoKeys/%s",
+			project, region, keyRing, cryptoKey),
+	}, nil
+}
+
+func (p *gcpCloudKMSProvider) wrap(ctx context.Context, kek []byte) ([]byte, error) {
+	out, err := p.service.Projects.Locations.KeyRings.CryptoKeys.Encrypt(p.name,
+		&cloudkms.EncryptRequest{
+			Plaintext: base64.StdEncoding.EncodeToString(kek),
+		}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap key with GCP Cloud KMS: %w", err)
+	}
+	return base64.StdEncoding.DecodeString(out.Ciphertext)
+}
+
+func (p *gcpCloudKMSProvider) unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	out, err := p.service.Projects.Locations.KeyRings.CryptoKeys.Decrypt(p.name,
+		&cloudkms.DecryptRequest{
+			Ciphertext: base64.StdEncoding.EncodeToString(wrapped),
+		}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap key with GCP Cloud KMS: %w", err)
+	}
+	return base64.StdEncoding.DecodeString(out.Plaintext)
+}
+
+// vaultTransitProvider wraps keys with a key of the Vault transit secrets
+// engine.
+type vaultTransitProvider struct {
+	client    *vapi.Client
+	mountPath string
+	keyName   string
+}
+
+func newVaultTransitProvider(config map[string]string) (*vaultTransitProvider, error) {
+	keyName := kekConfigValue(config, "key_name", "VAULT_TRANSIT_SEAL_KEY_NAME")
+	if keyName == "" {
+		return nil, fmt.Errorf("transit keyring requires key_name")
+	}
+	mountPath := kekConfigValue(config, "mount_path", "VAULT_TRANSIT_SEAL_MOUNT_PATH")
+	if mountPath == "" {
+		mountPath = "transit"
+	}
+
+	cfg := vapi.DefaultConfig()
+	if cfg.Error != nil {
+		return nil, fmt.Errorf("failed to configure Vault client: %w", cfg.Error)
+	}
+	if addr := config["address"]; addr != "" {
+		cfg.Address = addr
+	}
+	tlsConfig := &vapi.TLSConfig{
+		CACert:        config["tls_ca_cert"],
+		ClientCert:    config["tls_client_cert"],
+		ClientKey:     config["tls_client_key"],
+		TLSServerName: config["tls_server_name"],
+		Insecure:      config["tls_skip_verify"] == "true",
+	}
+	if err := cfg.ConfigureTLS(tlsConfig); err != nil {
+		return nil, fmt.Errorf("failed to configure Vault TLS: %w", err)
+	}
+
+	client, err := vapi.NewClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Vault client: %w", err)
+	}
+	if token := config["token"]; token != "" {
+		client.SetToken(token)
+	}
+	if namespace := config["namespace"]; namespace != "" {
+		client.SetNamespace(namespace)
+	}
+
+	return &vaultTransitProvider{
+		client:    client,
+		mountPath: mountPath,
+		keyName:   keyName,
+	}, nil
+}
+
+func (p *vaultTransitProvider) wrap(ctx context.Context, kek []byte) ([]byte, error) {
+	secret, err := p.client.Logical().WriteWithContext(ctx,
+		p.mountPath+"/encrypt/"+p.keyName, map[string]any{
+			"plaintext": base64.StdEncoding.EncodeToString(kek),
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap key with Vault transit: %w", err)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("failed to wrap key with Vault transit: empty response")
+	}
+	ciphertext, ok := secret.Data["ciphertext"].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to wrap key with Vault transit: missing ciphertext")
+	}
+	return []byte(ciphertext), nil
+}
+
+func (p *vaultTransitProvider) unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	secret, err := p.client.Logical().WriteWithContext(ctx,
+		p.mountPath+"/decrypt/"+p.keyName, map[string]any{
+			"ciphertext": string(wrapped),
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap key with Vault transit: %w", err)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("failed to unwrap key with Vault transit: empty response")
+	}
+	plaintext, ok := secret.Data["plaintext"].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to unwrap key with Vault transit: missing plaintext")
+	}
+	return base64.StdEncoding.DecodeString(plaintext)
+}
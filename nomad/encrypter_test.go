@@ -85,6 +85,152 @@ func TestEncrypter_LoadSave(t *testing.T) {
 	}
 }
 
+// testKEKProvider is a kekProvider that wraps keys by XORing them with a
+// fixed byte, so tests can tell wrapped keys from cleartext ones
+type testKEKProvider struct {
+	pad byte
+}
+
+func (p *testKEKProvider) wrap(_ context.Context, kek []byte) ([]byte, error) {
+	out := make([]byte, len(kek))
+	for i := range kek {
+		out[i] = kek[i] ^ p.pad
+	}
+	return out, nil
+}
+
+func (p *testKEKProvider) unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	return p.wrap(ctx, wrapped)
+}
+
+// TestEncrypter_KEKProviders exercises wrapping the key encryption keys with
+// an external provider and migrating keys between providers
+func TestEncrypter_KEKProviders(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, cleanupSrv := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0
+	})
+	t.Cleanup(cleanupSrv)
+
+	tmpDir := t.TempDir()
+	encrypter, err := NewEncrypter(srv, tmpDir)
+	must.NoError(t, err)
+	must.Eq(t, "aead", encrypter.activeProviderID)
+
+	readWrapper := func(keyID string) *structs.KeyEncryptionKeyWrapper {
+		t.Helper()
+		raw, err := os.ReadFile(filepath.Join(tmpDir, keyID+".nks.json"))
+		must.NoError(t, err)
+		kekWrapper := &structs.KeyEncryptionKeyWrapper{}
+		must.NoError(t, json.Unmarshal(raw, kekWrapper))
+		return kekWrapper
+	}
+
+	// keys saved by the aead provider store the KEK in cleartext
+	key, err := structs.NewRootKey(structs.EncryptionAlgorithmAES256GCM)
+	must.NoError(t, err)
+	must.NoError(t, encrypter.saveKeyToStore(key))
+	kekWrapper := readWrapper(key.Meta.KeyID)
+	must.Eq(t, "aead", kekWrapper.ProviderID)
+	must.Len(t, 32, kekWrapper.KeyEncryptionKey)
+	must.Len(t, 0, kekWrapper.WrappedKeyEncryptionKey)
+
+	// activating an external provider wraps the key again on load
+	encrypter.providers["test"] = &testKEKProvider{pad: 0x5a}
+	encrypter.activeProviderID = "test"
+
+	gotKey, err := encrypter.loadKeyFromStore(
+		filepath.Join(tmpDir, key.Meta.KeyID+".nks.json"))
+	must.NoError(t, err)
+	must.Eq(t, key.Key, gotKey.Key)
+
+	kekWrapper = readWrapper(key.Meta.KeyID)
+	must.Eq(t, "test", kekWrapper.ProviderID)
+	must.Len(t, 0, kekWrapper.KeyEncryptionKey)
+	must.Len(t, 32, kekWrapper.WrappedKeyEncryptionKey)
+
+	gotKey, err = encrypter.loadKeyFromStore(
+		filepath.Join(tmpDir, key.Meta.KeyID+".nks.json"))
+	must.NoError(t, err)
+	must.Eq(t, key.Key, gotKey.Key)
+	must.Eq(t, key.RSAKey, gotKey.RSAKey)
+
+	// keys wrapped by a provider that is no longer configured can't be loaded
+	delete(encrypter.providers, "test")
+	encrypter.activeProviderID = "aead"
+	_, err = encrypter.loadKeyFromStore(
+		filepath.Join(tmpDir, key.Meta.KeyID+".nks.json"))
+	must.EqError(t, err, `key is wrapped by keyring provider "test", which is not configured`)
+}
+
+func TestEncrypter_setupProviders(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name      string
+		configs   []*structs.KEKProviderConfig
+		expActive string
+		expErr    string
+	}{
+		{
+			name:      "default",
+			expActive: "aead",
+		},
+		{
+			name: "single provider is active",
+			configs: []*structs.KEKProviderConfig{
+				{Provider: structs.KEKProviderAEAD, Name: "example"},
+			},
+			expActive: "aead.example",
+		},
+		{
+			name: "duplicate",
+			configs: []*structs.KEKProviderConfig{
+				{Provider: structs.KEKProviderAEAD, Active: true},
+				{Provider: structs.KEKProviderAEAD},
+			},
+			expErr: `duplicate keyring provider "aead"`,
+		},
+		{
+			name: "multiple active",
+			configs: []*structs.KEKProviderConfig{
+				{Provider: structs.KEKProviderAEAD, Active: true},
+				{Provider: structs.KEKProviderAEAD, Name: "other", Active: true},
+			},
+			expErr: "only one keyring provider can be active",
+		},
+		{
+			name: "none active",
+			configs: []*structs.KEKProviderConfig{
+				{Provider: structs.KEKProviderAEAD},
+				{Provider: structs.KEKProviderAEAD, Name: "other"},
+			},
+			expErr: "one keyring provider must be active",
+		},
+		{
+			name: "unknown provider",
+			configs: []*structs.KEKProviderConfig{
+				{Provider: "hsm"},
+			},
+			expErr: `unknown keyring provider "hsm"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := &Encrypter{providers: map[string]kekProvider{}}
+			err := e.setupProviders(tc.configs)
+			if tc.expErr != "" {
+				must.EqError(t, err, tc.expErr)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.expActive, e.activeProviderID)
+		})
+	}
+}
+
 // TestEncrypter_Restore exercises the entire reload of a keystore,
 // including pairing metadata with key material
 func TestEncrypter_Restore(t *testing.T) {
@@ -469,6 +615,38 @@ func TestEncrypter_SignVerify_Issuer(t *testing.T) {
 	must.Eq(t, testIssuer, got.Issuer)
 }
 
+// TestEncrypter_SignVerify_ExtraClaims asserts that the extra claims of an
+// identity are added to the top level of the signed JWT.
+func TestEncrypter_SignVerify_ExtraClaims(t *testing.T) {
+
+	ci.Parallel(t)
+	srv, shutdown := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+
+	alloc := mock.Alloc()
+	claims := structs.NewIdentityClaims(alloc.Job, alloc, wiHandle, alloc.LookupTask("web").Identity, time.Now())
+	claims.ExtraClaims = map[string]string{"team": "web", "node_class": "large"}
+	e := srv.encrypter
+
+	out, _, err := e.SignClaims(claims)
+	must.NoError(t, err)
+
+	got, err := e.VerifyClaim(out)
+	must.NoError(t, err)
+	must.Eq(t, alloc.ID, got.AllocationID)
+
+	parsed, err := jwt.ParseSigned(out)
+	must.NoError(t, err)
+	var raw map[string]any
+	must.NoError(t, parsed.UnsafeClaimsWithoutVerification(&raw))
+	must.Eq(t, "web", raw["team"].(string))
+	must.Eq(t, "large", raw["node_class"].(string))
+	must.Eq(t, alloc.ID, raw["nomad_allocation_id"].(string))
+}
+
 func TestEncrypter_SignVerify_AlgNone(t *testing.T) {
 
 	ci.Parallel(t)
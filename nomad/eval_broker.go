@@ -16,6 +16,7 @@ import (
 	metrics "github.com/armon/go-metrics"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/broker"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/lib/delayheap"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -112,6 +113,10 @@ type EvalBroker struct {
 	// compounding after the first Nack.
 	subsequentNackDelay time.Duration
 
+	// tracer traces the evaluations passing through the broker. It's nil
+	// if tracing is disabled.
+	tracer *tracing.Tracer
+
 	l sync.RWMutex
 }
 
@@ -120,6 +125,10 @@ type unackEval struct {
 	Eval      *structs.Evaluation
 	Token     string
 	NackTimer *time.Timer
+
+	// Span covers the time from the dequeue of the evaluation until it's
+	// acknowledged.
+	Span *tracing.Span
 }
 
 // ReadyEvaluations is a list of ready evaluations across multiple jobs. We
@@ -252,6 +261,13 @@ func (b *EvalBroker) processEnqueue(eval *structs.Evaluation, token string) {
 		b.evals[eval.ID] = 0
 	}
 
+	b.tracer.Start("eval_broker.enqueue", eval.TraceParent,
+		tracing.String("nomad.eval.id", eval.ID),
+		tracing.String("nomad.eval.type", eval.Type),
+		tracing.Int("nomad.eval.priority", eval.Priority),
+		tracing.Bool("nomad.eval.delayed", eval.Wait > 0 || !eval.WaitUntil.IsZero()),
+	).End(nil)
+
 	// Check if we need to enforce a wait
 	if eval.Wait > 0 {
 		b.processWaitingEnqueue(eval)
@@ -346,6 +362,48 @@ func (b *EvalBroker) enqueueLocked(eval *structs.Evaluation, sched string) {
 	}
 }
 
+// UpdatePriority replaces a queued evaluation with the passed copy of it with
+// a different priority, so it is dequeued in its new order. Evaluations that
+// are not queued, such as those being processed by a scheduler, are ignored.
+func (b *EvalBroker) UpdatePriority(eval *structs.Evaluation) {
+	b.l.Lock()
+	defer b.l.Unlock()
+
+	if !b.enabled {
+		return
+	}
+
+	// Check the evaluations ready to be dequeued
+	if ready, ok := b.ready[eval.Type]; ok {
+		for i, e := range ready {
+			if e.ID == eval.ID {
+				ready[i] = eval
+				heap.Fix(&ready, i)
+				return
+			}
+		}
+	}
+
+	// Check the evaluations pending behind another evaluation of the job
+	namespacedID := structs.NewNamespacedID(eval.JobID, eval.Namespace)
+	if pending, ok := b.pending[namespacedID]; ok {
+		for i, e := range pending {
+			if e.ID == eval.ID {
+				pending[i] = eval
+				heap.Fix(&pending, i)
+				return
+			}
+		}
+	}
+
+	// Check the evaluations waiting to be enqueued
+	if _, ok := b.stats.DelayedEvals[eval.ID]; ok {
+		if err := b.delayHeap.Update(&evalWrapper{eval}, eval.WaitUntil); err == nil {
+			b.stats.DelayedEvals[eval.ID] = eval
+		}
+	}
+}
+
 // Dequeue is used to perform a blocking dequeue. The next available evalution
 // is returned as well as a unique token identifier for this dequeue. The token
 // changes on leadership election to ensure a Dequeue prior to a leadership
@@ -460,16 +518,21 @@ func (b *EvalBroker) dequeueForSched(sched string) (*structs.Evaluation, string,
 		b.Nack(eval.ID, token)
 	})
 
+	// Increment the dequeue count
+	b.evals[eval.ID] += 1
+
 	// Add to the unack queue
 	b.unack[eval.ID] = &unackEval{
 		Eval:      eval,
 		Token:     token,
 		NackTimer: nackTimer,
+		Span: b.tracer.Start("eval_broker.unacked", eval.TraceParent,
+			tracing.String("nomad.eval.id", eval.ID),
+			tracing.String("nomad.eval.type", sched),
+			tracing.Int("nomad.eval.dequeues", b.evals[eval.ID]),
+		),
 	}
 
-	// Increment the dequeue count
-	b.evals[eval.ID] += 1
-
 	// Update the stats
 	b.stats.TotalReady -= 1
 	b.stats.TotalUnacked += 1
@@ -574,6 +637,7 @@ func (b *EvalBroker) Ack(evalID, token string) error {
 	if !unack.NackTimer.Stop() {
 		return fmt.Errorf("Evaluation ID Ack'd after Nack timer expiration")
 	}
+	unack.Span.End(nil)
 
 	// Update the stats
 	b.stats.TotalUnacked -= 1
@@ -648,6 +712,7 @@ func (b *EvalBroker) Nack(evalID, token string) error {
 
 	// Stop the timer, doesn't matter if we've missed it
 	unack.NackTimer.Stop()
+	unack.Span.End(errors.New("evaluation was nacked"))
 
 	// Cleanup
 	delete(b.unack, evalID)
@@ -736,6 +801,7 @@ func (b *EvalBroker) flush() {
 	// Cancel any Nack timers
 	for _, unack := range b.unack {
 		unack.NackTimer.Stop()
+		unack.Span.End(errors.New("eval broker was disabled"))
 	}
 
 	// Cancel any time wait evals
@@ -710,6 +710,44 @@ func TestEvalBroker_Dequeue_FIFO(t *testing.T) {
 	}
 }
 
+// Ensure a queued eval is dequeued in the order of its updated priority
+func TestEvalBroker_UpdatePriority(t *testing.T) {
+	ci.Parallel(t)
+	b := testBroker(t, 0)
+	b.SetEnabled(true)
+
+	eval1 := mock.Eval()
+	eval1.Priority = 50
+	b.Enqueue(eval1)
+
+	eval2 := mock.Eval()
+	eval2.Priority = 70
+	b.Enqueue(eval2)
+
+	// A pending eval of the same job as eval1
+	eval3 := mock.Eval()
+	eval3.JobID = eval1.JobID
+	eval3.Priority = 50
+	b.Enqueue(eval3)
+
+	bumped := eval1.Copy()
+	bumped.Priority = 90
+	b.UpdatePriority(bumped)
+
+	pending := eval3.Copy()
+	pending.Priority = 90
+	b.UpdatePriority(pending)
+
+	out, token, err := b.Dequeue(defaultSched, time.Second)
+	must.NoError(t, err)
+	must.Eq(t, bumped, out)
+	must.NoError(t, b.Ack(out.ID, token))
+
+	out, _, err = b.Dequeue(defaultSched, time.Second)
+	must.NoError(t, err)
+	must.Eq(t, pending, out)
+}
+
 // Ensure fairness between schedulers
 func TestEvalBroker_Dequeue_Fairness(t *testing.T) {
 	ci.Parallel(t)
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/armon/go-metrics"
@@ -549,6 +550,110 @@ func (e *Eval) Delete(
 	return nil
 }
 
+// BumpPriority is used by operators to raise the priority of pending or
+// blocked evaluations during incidents, so the evaluations of critical jobs
+// are processed before other queued evaluations. The pending or blocked
+// follow-up evaluations of the evaluations are bumped as well.
+func (e *Eval) BumpPriority(args *structs.EvalBumpPriorityRequest, reply *structs.EvalBumpPriorityResponse) error {
+
+	authErr := e.srv.Authenticate(e.ctx, args)
+	if done, err := e.srv.forward(structs.EvalBumpPriorityRPCMethod, args, args, reply); done {
+		return err
+	}
+	e.srv.MeasureRPCRate("eval", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "bump_priority"}, time.Now())
+
+	// Jumping the queue affects the evaluations of all jobs, so it requires
+	// operator write.
+	if aclObj, err := e.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.AllowOperatorWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if len(args.EvalIDs) == 0 {
+		return errors.New("at least one evaluation ID must be provided")
+	}
+	if args.Priority < structs.JobMinPriority || args.Priority > e.srv.config.JobMaxPriority {
+		return fmt.Errorf("priority must be between [%d, %d]",
+			structs.JobMinPriority, e.srv.config.JobMaxPriority)
+	}
+
+	snap, err := e.srv.State().Snapshot()
+	if err != nil {
+		return fmt.Errorf("failed to lookup state snapshot: %v", err)
+	}
+	ws := memdb.NewWatchSet()
+
+	// Validate the requested evaluations, failing the whole call if any of
+	// them can't be bumped.
+	bumped := make(map[string]struct{}, len(args.EvalIDs))
+	var evals []*structs.Evaluation
+	for _, evalID := range args.EvalIDs {
+		eval, err := snap.EvalByID(ws, evalID)
+		if err != nil {
+			return fmt.Errorf("failed to lookup eval: %v", err)
+		}
+		if eval == nil {
+			return fmt.Errorf("eval %s not found", evalID)
+		}
+		if eval.TerminalStatus() {
+			return fmt.Errorf("eval %s is %s and can't be bumped", eval.ID, eval.Status)
+		}
+		if eval.Priority >= args.Priority {
+			return fmt.Errorf("eval %s already has priority %d", eval.ID, eval.Priority)
+		}
+		if _, ok := bumped[eval.ID]; !ok {
+			bumped[eval.ID] = struct{}{}
+			evals = append(evals, eval)
+		}
+	}
+
+	// Add the follow-up evaluations that haven't been processed yet. They
+	// are created with the priority of the evaluation they follow, so later
+	// follow-ups inherit the bumped priority.
+	var evalIDs []string
+	for len(evals) > 0 {
+		eval := evals[0]
+		evals = evals[1:]
+		evalIDs = append(evalIDs, eval.ID)
+
+		jobEvals, err := snap.EvalsByJob(ws, eval.Namespace, eval.JobID)
+		if err != nil {
+			return fmt.Errorf("failed to lookup evals of job %q: %v", eval.JobID, err)
+		}
+		for _, followUp := range jobEvals {
+			if followUp.PreviousEval != eval.ID || followUp.TerminalStatus() {
+				continue
+			}
+			if _, ok := bumped[followUp.ID]; ok || followUp.Priority >= args.Priority {
+				continue
+			}
+			bumped[followUp.ID] = struct{}{}
+			evals = append(evals, followUp)
+		}
+	}
+
+	args.EvalIDs = evalIDs
+	args.BumpedBy = args.GetIdentity().String()
+	args.BumpTime = time.Now().UTC().UnixNano()
+
+	_, index, err := e.srv.raftApply(structs.EvalBumpPriorityRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	e.logger.Info("evaluation priority bumped",
+		"eval_ids", evalIDs, "priority", args.Priority, "bumped_by", args.BumpedBy)
+
+	reply.EvalIDs = evalIDs
+	reply.Index = index
+	return nil
+}
+
 // deleteEvalsByFilter deletes evaluations in batches based on the filter. It
 // returns a count, the index, and any error
 func (e *Eval) deleteEvalsByFilter(args *structs.EvalDeleteRequest) (int, uint64, error) {
@@ -663,8 +768,7 @@ func (e *Eval) List(args *structs.EvalListRequest, reply *structs.EvalListRespon
 
 	if args.Filter != "" {
 		// Check for incompatible filtering.
-		hasLegacyFilter := args.FilterJobID != "" || args.FilterEvalStatus != ""
-		if hasLegacyFilter {
+		if args.HasLegacyFilter() {
 			return structs.ErrIncompatibleFiltering
 		}
 	}
@@ -761,6 +865,122 @@ func (e *Eval) List(args *structs.EvalListRequest, reply *structs.EvalListRespon
 	return e.srv.blockingRPC(&opts)
 }
 
+// PendingSummary is used to summarize the blocked evaluations of jobs, to
+// explain why their allocations can't be placed.
+func (e *Eval) PendingSummary(args *structs.EvalPendingSummaryRequest, reply *structs.EvalPendingSummaryResponse) error {
+
+	authErr := e.srv.Authenticate(e.ctx, args)
+	if done, err := e.srv.forward("Eval.PendingSummary", args, args, reply); done {
+		return err
+	}
+	e.srv.MeasureRPCRate("eval", structs.RateMetricList, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "eval", "pending_summary"}, time.Now())
+
+	namespace := args.RequestNamespace()
+
+	// Check for read-job permissions
+	aclObj, err := e.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+	allow := aclObj.AllowNsOpFunc(acl.NamespaceCapabilityReadJob)
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			// Get the namespaces the user is allowed to access.
+			allowableNamespaces, err := allowedNSes(aclObj, store, allow)
+			if err == structs.ErrPermissionDenied {
+				// return empty summaries if token isn't authorized for any
+				// namespace, matching other endpoints
+				reply.Summaries = make([]*structs.JobPendingSummary, 0)
+			} else if err != nil {
+				return err
+			} else {
+				summaries, err := pendingSummaries(ws, store, namespace, args.FilterJobID, allowableNamespaces)
+				if err != nil {
+					return err
+				}
+				reply.Summaries = summaries
+			}
+
+			// Use the last index that affected the evals table
+			index, err := store.Index("evals")
+			if err != nil {
+				return err
+			}
+			reply.Index = index
+
+			// Set the query response
+			e.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return e.srv.blockingRPC(&opts)
+}
+
+// pendingSummaries returns the pending summaries of the jobs with blocked
+// evaluations, sorted by namespace and job ID.
+func pendingSummaries(ws memdb.WatchSet, store *state.StateStore, namespace, jobID string,
+	allowableNamespaces map[string]bool) ([]*structs.JobPendingSummary, error) {
+
+	var iter memdb.ResultIterator
+	var err error
+	if namespace == structs.AllNamespacesSentinel {
+		iter, err = store.Evals(ws, state.SortDefault)
+	} else {
+		iter, err = store.EvalsByNamespace(ws, namespace)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the latest blocked eval of each job
+	blocked := make(map[structs.NamespacedID]*structs.Evaluation)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		eval := raw.(*structs.Evaluation)
+		if eval.Status != structs.EvalStatusBlocked {
+			continue
+		}
+		if jobID != "" && eval.JobID != jobID {
+			continue
+		}
+		if allowableNamespaces != nil && !allowableNamespaces[eval.Namespace] {
+			continue
+		}
+
+		key := structs.NamespacedID{ID: eval.JobID, Namespace: eval.Namespace}
+		if existing, ok := blocked[key]; ok && existing.CreateIndex > eval.CreateIndex {
+			continue
+		}
+		blocked[key] = eval
+	}
+
+	summaries := make([]*structs.JobPendingSummary, 0, len(blocked))
+	for key, eval := range blocked {
+		jobSummary, err := store.JobSummaryByID(ws, key.Namespace, key.ID)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, structs.NewJobPendingSummary(eval, jobSummary))
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Namespace != summaries[j].Namespace {
+			return summaries[i].Namespace < summaries[j].Namespace
+		}
+		return summaries[i].JobID < summaries[j].JobID
+	})
+	return summaries, nil
+}
+
 // Count is used to get a list of the evaluations in the system
 func (e *Eval) Count(args *structs.EvalCountRequest, reply *structs.EvalCountResponse) error {
 
@@ -958,6 +958,83 @@ func TestEvalEndpoint_Delete(t *testing.T) {
 
 }
 
+func TestEvalEndpoint_BumpPriority(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	// A queued eval of another job with a higher priority, a queued eval
+	// and a blocked follow-up of it
+	other := mock.Eval()
+	other.Priority = 70
+	stuck := mock.Eval()
+	followUp := mock.BlockedEval()
+	followUp.Namespace = stuck.Namespace
+	followUp.JobID = stuck.JobID
+	followUp.Priority = stuck.Priority
+	followUp.PreviousEval = stuck.ID
+
+	must.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, 1000,
+		[]*structs.Evaluation{other, stuck, followUp}))
+	s1.evalBroker.Enqueue(other)
+	s1.evalBroker.Enqueue(stuck)
+	s1.blockedEvals.Block(followUp)
+
+	bump := func(priority int, evalIDs ...string) (*structs.EvalBumpPriorityResponse, error) {
+		req := &structs.EvalBumpPriorityRequest{
+			EvalIDs:      evalIDs,
+			Priority:     priority,
+			WriteRequest: structs.WriteRequest{Region: "global"},
+		}
+		var resp structs.EvalBumpPriorityResponse
+		err := msgpackrpc.CallWithCodec(codec, structs.EvalBumpPriorityRPCMethod, req, &resp)
+		return &resp, err
+	}
+
+	_, err := bump(90, uuid.Generate())
+	must.ErrorContains(t, err, "not found")
+
+	_, err = bump(stuck.Priority, stuck.ID)
+	must.ErrorContains(t, err, "already has priority")
+
+	_, err = bump(structs.JobDefaultMaxPriority+1, stuck.ID)
+	must.ErrorContains(t, err, "priority must be between")
+
+	// Bump the stuck eval and its follow-up
+	resp, err := bump(90, stuck.ID)
+	must.NoError(t, err)
+	must.NonZero(t, resp.Index)
+	must.SliceContainsAll(t, []string{stuck.ID, followUp.ID}, resp.EvalIDs)
+
+	for _, id := range resp.EvalIDs {
+		eval, err := store.EvalByID(nil, id)
+		must.NoError(t, err)
+		must.Eq(t, 90, eval.Priority)
+		must.NotNil(t, eval.PriorityBump)
+		must.Eq(t, 50, eval.PriorityBump.PreviousPriority)
+		must.NotEq(t, "", eval.PriorityBump.BumpedBy)
+	}
+
+	// The bumped eval jumps the queue
+	eval, _, err := s1.evalBroker.Dequeue(defaultSched, time.Second)
+	must.NoError(t, err)
+	must.Eq(t, stuck.ID, eval.ID)
+	must.Eq(t, 90, eval.Priority)
+
+	// The blocked follow-up is enqueued with the new priority once unblocked
+	s1.blockedEvals.l.RLock()
+	blocked, ok := s1.blockedEvals.captured[followUp.ID]
+	s1.blockedEvals.l.RUnlock()
+	must.True(t, ok)
+	must.Eq(t, 90, blocked.eval.Priority)
+}
+
 func TestEvalEndpoint_List(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1328,10 +1405,12 @@ func TestEvalEndpoint_List_PaginationFiltering(t *testing.T) {
 	// in the order that the state store will return them from the
 	// iterator (sorted by create index), for ease of writing tests
 	mocks := []struct {
-		ids       []string
-		namespace string
-		jobID     string
-		status    string
+		ids         []string
+		namespace   string
+		jobID       string
+		status      string
+		triggeredBy string
+		nodeID      string
 	}{
 		{ids: []string{"aaaa1111-3350-4b4b-d185-0e1992ed43e9"}, jobID: "example"},                    // 0
 		{ids: []string{"aaaaaa22-3350-4b4b-d185-0e1992ed43e9"}, jobID: "example"},                    // 1
@@ -1339,10 +1418,16 @@ func TestEvalEndpoint_List_PaginationFiltering(t *testing.T) {
 		{ids: []string{"aaaaaaaa-3350-4b4b-d185-0e1992ed43e9"}, jobID: "example", status: "blocked"}, // 3
 		{ids: []string{"aaaaaabb-3350-4b4b-d185-0e1992ed43e9"}},                                      // 4
 		{ids: []string{"aaaaaacc-3350-4b4b-d185-0e1992ed43e9"}},                                      // 5
-		{ids: []string{"aaaaaadd-3350-4b4b-d185-0e1992ed43e9"}, jobID: "example"},                    // 6
-		{ids: []string{"aaaaaaee-3350-4b4b-d185-0e1992ed43e9"}, jobID: "example"},                    // 7
-		{ids: []string{"aaaaaaff-3350-4b4b-d185-0e1992ed43e9"}},                                      // 8
-		{ids: []string{"00000111-3350-4b4b-d185-0e1992ed43e9"}},                                      // 9
+		{ // 6
+			ids:   []string{"aaaaaadd-3350-4b4b-d185-0e1992ed43e9"},
+			jobID: "example", triggeredBy: "node-update", nodeID: "node-1",
+		},
+		{ // 7
+			ids:   []string{"aaaaaaee-3350-4b4b-d185-0e1992ed43e9"},
+			jobID: "example", triggeredBy: "node-update",
+		},
+		{ids: []string{"aaaaaaff-3350-4b4b-d185-0e1992ed43e9"}}, // 8
+		{ids: []string{"00000111-3350-4b4b-d185-0e1992ed43e9"}}, // 9
 		{ids: []string{ // 10
 			"00000222-3350-4b4b-d185-0e1992ed43e9",
 			"00000333-3350-4b4b-d185-0e1992ed43e9",
@@ -1368,6 +1453,8 @@ func TestEvalEndpoint_List_PaginationFiltering(t *testing.T) {
 			if m.status != "" { // defaults to "pending"
 				eval.Status = m.status
 			}
+			eval.TriggeredBy = m.triggeredBy
+			eval.NodeID = m.nodeID
 			evals = append(evals, eval)
 			evalsInTx = append(evalsInTx, eval)
 		}
@@ -1386,6 +1473,8 @@ func TestEvalEndpoint_List_PaginationFiltering(t *testing.T) {
 		nextToken         string
 		filterJobID       string
 		filterStatus      string
+		filterTriggeredBy string
+		filterNodeID      string
 		filter            string
 		pageSize          int32
 		expectedNextToken string
@@ -1605,13 +1694,47 @@ func TestEvalEndpoint_List_PaginationFiltering(t *testing.T) {
 				"bbbb1111-3350-4b4b-d185-0e1992ed43e9",
 			},
 		},
+		{
+			name:              "test25 size-1 page-1 filter by trigger",
+			pageSize:          1,
+			filterTriggeredBy: "node-update",
+			expectedNextToken: "1007.aaaaaaee-3350-4b4b-d185-0e1992ed43e9",
+			expectedIDs: []string{
+				"aaaaaadd-3350-4b4b-d185-0e1992ed43e9",
+			},
+		},
+		{
+			name:              "test26 size-1 page-2 filter by trigger",
+			pageSize:          1,
+			filterTriggeredBy: "node-update",
+			nextToken:         "1007.aaaaaaee-3350-4b4b-d185-0e1992ed43e9",
+			expectedIDs: []string{
+				"aaaaaaee-3350-4b4b-d185-0e1992ed43e9",
+			},
+		},
+		{
+			name:              "test27 filter by trigger and node",
+			filterTriggeredBy: "node-update",
+			filterNodeID:      "node-1",
+			expectedIDs: []string{
+				"aaaaaadd-3350-4b4b-d185-0e1992ed43e9",
+			},
+		},
+		{
+			name:              "test28 incompatible filtering by trigger",
+			filter:            `JobID == "example"`,
+			filterTriggeredBy: "node-update",
+			expectedError:     structs.ErrIncompatibleFiltering.Error(),
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			req := &structs.EvalListRequest{
-				FilterJobID:      tc.filterJobID,
-				FilterEvalStatus: tc.filterStatus,
+				FilterJobID:       tc.filterJobID,
+				FilterEvalStatus:  tc.filterStatus,
+				FilterTriggeredBy: tc.filterTriggeredBy,
+				FilterNodeID:      tc.filterNodeID,
 				QueryOptions: structs.QueryOptions{
 					Region:    "global",
 					Namespace: tc.namespace,
@@ -1760,6 +1883,136 @@ func TestEvalEndpoint_Count(t *testing.T) {
 
 }
 
+func TestEvalEndpoint_PendingSummary(t *testing.T) {
+	ci.Parallel(t)
+	s1, _, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	index := uint64(100)
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	// Create non-default namespace
+	nondefaultNS := mock.Namespace()
+	nondefaultNS.Name = "non-default"
+	must.NoError(t, store.UpsertNamespaces(index, []*structs.Namespace{nondefaultNS}))
+
+	// Only the latest blocked eval of a job is summarized
+	older := mock.BlockedEval()
+	older.JobID = "example"
+	newer := mock.BlockedEval()
+	newer.JobID = "example"
+	complete := mock.Eval()
+	complete.JobID = "example"
+	other := mock.BlockedEval()
+	other.Namespace = nondefaultNS.Name
+
+	index++
+	must.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, index, []*structs.Evaluation{older}))
+	index++
+	must.NoError(t, store.UpsertEvals(structs.MsgTypeTestSetup, index,
+		[]*structs.Evaluation{newer, complete, other}))
+
+	jobSummary := mock.JobSummary("example")
+	jobSummary.Summary["cache"] = structs.TaskGroupSummary{Queued: 3}
+	index++
+	must.NoError(t, store.UpsertJobSummary(index, jobSummary))
+
+	index++
+	aclToken := mock.CreatePolicyAndToken(t, store, index, "test-read-any",
+		mock.NamespacePolicy("*", "read", nil)).SecretID
+	limitedACLToken := mock.CreatePolicyAndToken(t, store, index, "test-read-limited",
+		mock.NamespacePolicy("default", "read", nil)).SecretID
+
+	cases := []struct {
+		name          string
+		namespace     string
+		jobID         string
+		token         string
+		expectedEvals []string
+		expectedErr   string
+	}{
+		{
+			name:          "default namespace",
+			namespace:     structs.DefaultNamespace,
+			token:         aclToken,
+			expectedEvals: []string{newer.ID},
+		},
+		{
+			name:          "wildcard namespace with read-any ACL",
+			namespace:     "*",
+			token:         aclToken,
+			expectedEvals: []string{newer.ID, other.ID},
+		},
+		{
+			name:          "wildcard namespace with limited-read ACL",
+			namespace:     "*",
+			token:         limitedACLToken,
+			expectedEvals: []string{newer.ID},
+		},
+		{
+			name:          "filter by job",
+			namespace:     "*",
+			jobID:         other.JobID,
+			token:         aclToken,
+			expectedEvals: []string{other.ID},
+		},
+		{
+			name:        "no permission",
+			namespace:   nondefaultNS.Name,
+			token:       limitedACLToken,
+			expectedErr: structs.ErrPermissionDenied.Error(),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &structs.EvalPendingSummaryRequest{
+				FilterJobID: tc.jobID,
+				QueryOptions: structs.QueryOptions{
+					Region:    "global",
+					Namespace: tc.namespace,
+					AuthToken: tc.token,
+				},
+			}
+			var resp structs.EvalPendingSummaryResponse
+			err := msgpackrpc.CallWithCodec(codec, "Eval.PendingSummary", req, &resp)
+			if tc.expectedErr != "" {
+				must.EqError(t, err, tc.expectedErr)
+				return
+			}
+			must.NoError(t, err)
+
+			gotEvals := []string{}
+			for _, summary := range resp.Summaries {
+				gotEvals = append(gotEvals, summary.EvalID)
+			}
+			must.Eq(t, tc.expectedEvals, gotEvals)
+		})
+	}
+
+	// The summary explains the failed placements of the task group
+	req := &structs.EvalPendingSummaryRequest{
+		FilterJobID: "example",
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: aclToken,
+		},
+	}
+	var resp structs.EvalPendingSummaryResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Eval.PendingSummary", req, &resp))
+	must.Len(t, 1, resp.Summaries)
+
+	tgSummary := resp.Summaries[0].TaskGroups["cache"]
+	must.NotNil(t, tgSummary)
+	must.Eq(t, 3, tgSummary.Queued)
+	must.Eq(t, []string{
+		"No nodes were eligible for evaluation",
+		`Dimension "memory" exhausted on 1 nodes`,
+	}, tgSummary.Reasons)
+}
+
 func TestEvalEndpoint_Allocations(t *testing.T) {
 	ci.Parallel(t)
 
@@ -50,6 +50,17 @@ func (e *Event) stream(conn io.ReadWriteCloser) {
 		return
 	}
 
+	// forward to a read replica if requested and available
+	if args.ReadReplica && !e.srv.config.ReadReplica {
+		if replica := e.srv.findReadReplica(); replica != nil {
+			err := e.forwardStreamingRPCToServer(replica, "Event.Stream", args, conn)
+			if err != nil {
+				handleJsonResultError(err, pointer.Of(int64(500)), encoder)
+			}
+			return
+		}
+	}
+
 	e.srv.MeasureRPCRate("event", structs.RateMetricRead, &args)
 	if authErr != nil {
 		handleJsonResultError(structs.ErrPermissionDenied, pointer.Of(int64(403)), encoder)
@@ -97,7 +108,12 @@ func (e *Event) stream(conn io.ReadWriteCloser) {
 		cancel()
 	}()
 
-	jsonStream := stream.NewJsonStream(ctx, 30*time.Second)
+	var jsonStream *stream.JsonStream
+	if args.Msgpack {
+		jsonStream = stream.NewMsgpackStream(ctx, 30*time.Second)
+	} else {
+		jsonStream = stream.NewJsonStream(ctx, 30*time.Second)
+	}
 	errCh := make(chan error)
 	go func() {
 		defer cancel()
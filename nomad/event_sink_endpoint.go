@@ -0,0 +1,233 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/state/paginator"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// EventSink endpoint is used for event sink management. Event sinks can
+// receive events of every topic, so all operations require a management
+// token.
+type EventSink struct {
+	srv *Server
+	ctx *RPCContext
+}
+
+func NewEventSinkEndpoint(srv *Server, ctx *RPCContext) *EventSink {
+	return &EventSink{srv: srv, ctx: ctx}
+}
+
+// List is used to retrieve the event sinks. It supports prefix listing,
+// pagination, and filtering.
+func (e *EventSink) List(args *structs.EventSinkListRequest, reply *structs.EventSinkListResponse) error {
+	authErr := e.srv.Authenticate(e.ctx, args)
+	if done, err := e.srv.forward("EventSink.List", args, args, reply); done {
+		return err
+	}
+	e.srv.MeasureRPCRate("event_sink", structs.RateMetricList, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "event_sink", "list"}, time.Now())
+
+	if aclObj, err := e.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup blocking query.
+	sort := state.SortOption(args.Reverse)
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			var err error
+			var iter memdb.ResultIterator
+
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = store.EventSinksByIDPrefix(ws, prefix, sort)
+			} else {
+				iter, err = store.EventSinks(ws, sort)
+			}
+			if err != nil {
+				return err
+			}
+
+			pageOpts := paginator.StructsTokenizerOptions{WithID: true}
+			tokenizer := paginator.NewStructsTokenizer(iter, pageOpts)
+
+			var sinks []*structs.EventSinkStub
+			pager, err := paginator.NewPaginator(iter, tokenizer, nil, args.QueryOptions,
+				func(raw interface{}) error {
+					sink := raw.(*structs.EventSink)
+					sinks = append(sinks, sink.Stub())
+					return nil
+				})
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to create result paginator: %v", err)
+			}
+
+			nextToken, err := pager.Page()
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to read result page: %v", err)
+			}
+
+			reply.QueryMeta.NextToken = nextToken
+			reply.Sinks = sinks
+
+			// Use the last index that affected the event sinks table.
+			index, err := store.Index(state.TableEventSinks)
+			if err != nil {
+				return err
+			}
+			reply.Index = max(1, index)
+
+			// Set the query response.
+			e.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return e.srv.blockingRPC(&opts)
+}
+
+// GetEventSink returns the specific event sink requested or nil if the event
+// sink doesn't exist.
+func (e *EventSink) GetEventSink(args *structs.EventSinkSpecificRequest, reply *structs.SingleEventSinkResponse) error {
+	authErr := e.srv.Authenticate(e.ctx, args)
+	if done, err := e.srv.forward("EventSink.GetEventSink", args, args, reply); done {
+		return err
+	}
+	e.srv.MeasureRPCRate("event_sink", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "event_sink", "get_event_sink"}, time.Now())
+
+	if aclObj, err := e.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query.
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			sink, err := store.EventSinkByID(ws, args.ID)
+			if err != nil {
+				return err
+			}
+
+			reply.Sink = sink
+			if sink != nil {
+				reply.Index = sink.ModifyIndex
+			} else {
+				// Return the last index that affected the event sinks table
+				// if the requested event sink doesn't exist.
+				index, err := store.Index(state.TableEventSinks)
+				if err != nil {
+					return err
+				}
+				reply.Index = max(1, index)
+			}
+			return nil
+		}}
+	return e.srv.blockingRPC(&opts)
+}
+
+// UpsertEventSinks registers or updates the given event sinks. Updating a
+// sink keeps its delivery progress.
+func (e *EventSink) UpsertEventSinks(args *structs.EventSinkUpsertRequest, reply *structs.GenericResponse) error {
+	authErr := e.srv.Authenticate(e.ctx, args)
+	if done, err := e.srv.forward("EventSink.UpsertEventSinks", args, args, reply); done {
+		return err
+	}
+	e.srv.MeasureRPCRate("event_sink", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "event_sink", "upsert_event_sinks"}, time.Now())
+
+	if aclObj, err := e.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if !ServersMeetMinimumVersion(
+		e.srv.serf.Members(), e.srv.Region(), minEventSinksVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to register event sinks", minEventSinksVersion)
+	}
+
+	// Validate request.
+	if len(args.Sinks) == 0 {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "must specify at least one event sink")
+	}
+	for _, sink := range args.Sinks {
+		sink.Canonicalize()
+		if err := sink.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid event sink %q: %v", sink.ID, err)
+		}
+	}
+
+	// Update via Raft.
+	_, index, err := e.srv.raftApply(structs.EventSinkRegisterRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
+// DeleteEventSinks deregisters the given event sinks.
+func (e *EventSink) DeleteEventSinks(args *structs.EventSinkDeleteRequest, reply *structs.GenericResponse) error {
+	authErr := e.srv.Authenticate(e.ctx, args)
+	if done, err := e.srv.forward("EventSink.DeleteEventSinks", args, args, reply); done {
+		return err
+	}
+	e.srv.MeasureRPCRate("event_sink", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "event_sink", "delete_event_sinks"}, time.Now())
+
+	if aclObj, err := e.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if !ServersMeetMinimumVersion(
+		e.srv.serf.Members(), e.srv.Region(), minEventSinksVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to deregister event sinks", minEventSinksVersion)
+	}
+
+	// Validate request.
+	if len(args.IDs) == 0 {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "must specify at least one event sink to deregister")
+	}
+	for _, id := range args.IDs {
+		if id == "" {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "event sink ID is empty")
+		}
+	}
+
+	// Delete via Raft.
+	_, index, err := e.srv.raftApply(structs.EventSinkDeregisterRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
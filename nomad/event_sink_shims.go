@@ -0,0 +1,24 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// eventSinkShim implements the eventsink.ProgressApplier interface required
+// by the event sinks manager.
+type eventSinkShim struct {
+	s *Server
+}
+
+func (e eventSinkShim) UpdateEventSinksProgress(progress map[string]uint64) (uint64, error) {
+	args := &structs.EventSinkProgressRequest{
+		Progress:     progress,
+		WriteRequest: structs.WriteRequest{Region: e.s.config.Region},
+	}
+
+	_, index, err := e.s.raftApply(structs.EventSinkProgressRequestType, args)
+	return index, err
+}
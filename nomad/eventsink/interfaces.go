@@ -0,0 +1,13 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package eventsink
+
+// ProgressApplier is a minimal interface of the Server used to commit the
+// delivery progress of the event sinks to raft, which avoids circular
+// references between the nomad package and the eventsink package.
+type ProgressApplier interface {
+	// UpdateEventSinksProgress records the index of the last event delivered
+	// to each event sink, keyed by sink ID.
+	UpdateEventSinksProgress(progress map[string]uint64) (uint64, error)
+}
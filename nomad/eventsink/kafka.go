@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package eventsink
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaBatchTimeout is how long the writer waits for more records before
+// producing a partial batch. Each batch of events is written at once, so it
+// only needs to cover the time to hand the records to the writer.
+const kafkaBatchTimeout = 10 * time.Millisecond
+
+// kafkaPublisher produces each event as a record on a Kafka topic, keyed by
+// the event key so the events of an object are ordered within a partition.
+// Records must be acknowledged by all in-sync replicas.
+type kafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func newKafkaPublisher(sink *structs.EventSink) *kafkaPublisher {
+	var brokers []string
+	for _, broker := range strings.Split(sink.Address, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+
+	transport := &kafka.Transport{
+		ClientID:    "nomad",
+		DialTimeout: publishTimeout,
+	}
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		BatchTimeout: kafkaBatchTimeout,
+		WriteTimeout: publishTimeout,
+		Transport:    transport,
+
+		// Retries are left to the sink, which backs off between attempts.
+		MaxAttempts: 1,
+	}
+	if sink.Kafka != nil {
+		writer.Topic = sink.Kafka.Topic
+		if sink.Kafka.ClientID != "" {
+			transport.ClientID = sink.Kafka.ClientID
+		}
+	}
+	return &kafkaPublisher{writer: writer}
+}
+
+func (k *kafkaPublisher) Publish(ctx context.Context, events *structs.Events) error {
+	msgs := make([]kafka.Message, 0, len(events.Events))
+	for _, event := range events.Events {
+		value, err := encodeJSON(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+		msgs = append(msgs, kafka.Message{
+			Key:   []byte(event.Key),
+			Value: value,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+	return k.writer.WriteMessages(ctx, msgs...)
+}
+
+func (k *kafkaPublisher) Close() error {
+	return k.writer.Close()
+}
@@ -0,0 +1,176 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/protocol"
+	"github.com/segmentio/kafka-go/protocol/metadata"
+	"github.com/segmentio/kafka-go/protocol/produce"
+	"github.com/shoenig/test/must"
+)
+
+// testKafkaRecord is a record produced to the test transport.
+type testKafkaRecord struct {
+	partition int32
+	key       []byte
+	value     []byte
+}
+
+// testKafkaTransport answers the metadata requests for a topic with two
+// partitions led by a single broker, and records the produced records.
+// Produce requests fail with the given error code.
+type testKafkaTransport struct {
+	topic      string
+	produceErr int16
+
+	l       sync.Mutex
+	acks    []int16
+	records []testKafkaRecord
+}
+
+func (t *testKafkaTransport) RoundTrip(ctx context.Context, addr net.Addr, req kafka.Request) (kafka.Response, error) {
+	switch req := req.(type) {
+	case *metadata.Request:
+		resp := &metadata.Response{
+			Brokers: []metadata.ResponseBroker{{NodeID: 1, Host: "127.0.0.1", Port: 9092}},
+			Topics:  []metadata.ResponseTopic{{Name: t.topic}},
+		}
+		for partition := int32(0); partition < 2; partition++ {
+			resp.Topics[0].Partitions = append(resp.Topics[0].Partitions, metadata.ResponsePartition{
+				PartitionIndex: partition,
+				LeaderID:       1,
+				ReplicaNodes:   []int32{1},
+				IsrNodes:       []int32{1},
+			})
+		}
+		return resp, nil
+
+	case *produce.Request:
+		t.l.Lock()
+		defer t.l.Unlock()
+		t.acks = append(t.acks, req.Acks)
+
+		resp := &produce.Response{}
+		for _, topic := range req.Topics {
+			respTopic := produce.ResponseTopic{Topic: topic.Topic}
+			for _, partition := range topic.Partitions {
+				respTopic.Partitions = append(respTopic.Partitions, produce.ResponsePartition{
+					Partition: partition.Partition,
+					ErrorCode: t.produceErr,
+				})
+				if err := t.readRecords(partition); err != nil {
+					return nil, err
+				}
+			}
+			resp.Topics = append(resp.Topics, respTopic)
+		}
+		return resp, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected request %T", req)
+	}
+}
+
+func (t *testKafkaTransport) readRecords(partition produce.RequestPartition) error {
+	records := partition.RecordSet.Records
+	for {
+		record, err := records.ReadRecord()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		key, err := protocol.ReadAll(record.Key)
+		if err != nil {
+			return err
+		}
+		value, err := protocol.ReadAll(record.Value)
+		if err != nil {
+			return err
+		}
+		t.records = append(t.records, testKafkaRecord{
+			partition: partition.Partition,
+			key:       key,
+			value:     value,
+		})
+	}
+}
+
+func testKafkaPublisher(t *testing.T, transport *testKafkaTransport) Publisher {
+	p, err := NewPublisher(&structs.EventSink{
+		Type:    structs.EventSinkTypeKafka,
+		Address: "127.0.0.1:9092",
+		Kafka:   &structs.EventSinkKafkaConfig{Topic: transport.topic},
+	})
+	must.NoError(t, err)
+	p.(*kafkaPublisher).writer.Transport = transport
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func TestKafkaPublisher_Publish(t *testing.T) {
+	ci.Parallel(t)
+
+	transport := &testKafkaTransport{topic: "nomad-events"}
+	p := testKafkaPublisher(t, transport)
+
+	events := &structs.Events{
+		Index: 10,
+		Events: []structs.Event{
+			{Topic: structs.TopicJob, Type: structs.TypeJobRegistered, Key: "example", Index: 10},
+			{Topic: structs.TopicJob, Type: structs.TypeJobRegistered, Key: "other", Index: 10},
+			{Topic: structs.TopicNode, Type: structs.TypeNodeRegistration, Key: "node-1", Index: 10},
+		},
+	}
+	must.NoError(t, p.Publish(context.Background(), events))
+
+	transport.l.Lock()
+	defer transport.l.Unlock()
+
+	// Records must be acknowledged by all the in-sync replicas.
+	for _, acks := range transport.acks {
+		must.Eq(t, int16(kafka.RequireAll), acks)
+	}
+
+	// Records are keyed by the event key, so the events of an object are
+	// always produced to the same partition.
+	keys := make(map[string]struct{})
+	for _, record := range transport.records {
+		var event structs.Event
+		must.NoError(t, json.Unmarshal(record.value, &event))
+		must.Eq(t, string(record.key), event.Key)
+		must.Eq(t, (&kafka.Hash{}).Balance(kafka.Message{Key: record.key}, 0, 1), int(record.partition))
+		keys[event.Key] = struct{}{}
+	}
+	must.MapLen(t, 3, keys)
+	must.MapContainsKeys(t, keys, []string{"example", "other", "node-1"})
+}
+
+func TestKafkaPublisher_ProduceError(t *testing.T) {
+	ci.Parallel(t)
+
+	// Error code 7 is a request timeout.
+	transport := &testKafkaTransport{topic: "nomad-events", produceErr: 7}
+	p := testKafkaPublisher(t, transport)
+
+	events := &structs.Events{
+		Index:  10,
+		Events: []structs.Event{{Topic: structs.TopicJob, Key: "example", Index: 10}},
+	}
+	err := p.Publish(context.Background(), events)
+	must.ErrorContains(t, err, "Request Timed Out")
+}
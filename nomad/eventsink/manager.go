@@ -0,0 +1,239 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package eventsink
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// defaultProgressInterval is how often the delivery progress of the event
+// sinks is committed to raft. Events delivered after the last commit are
+// delivered again by the next leader.
+const defaultProgressInterval = 5 * time.Second
+
+// Manager runs the event sinks on the leader. Each sink subscribes to the
+// event broker from the last index committed for it and delivers the events
+// matching its topics, retrying failed deliveries with backoff. The progress
+// of the sinks is periodically committed to raft.
+type Manager struct {
+	enabled bool
+	logger  log.Logger
+
+	// raft is used to commit the delivery progress of the sinks.
+	raft ProgressApplier
+
+	// state is the state store the sinks are read from and whose event
+	// broker they subscribe to.
+	state *state.StateStore
+
+	// sinks is the set of running sinks, keyed by ID.
+	sinks map[string]*sinkRunner
+
+	// progressInterval is how often the delivery progress is committed.
+	progressInterval time.Duration
+
+	// ctx and exitFn are used to stop the manager goroutines.
+	ctx    context.Context
+	exitFn context.CancelFunc
+
+	l sync.Mutex
+}
+
+// NewManager returns an event sink manager, which must be enabled on the
+// leader to deliver events.
+func NewManager(logger log.Logger, raft ProgressApplier) *Manager {
+	ctx, exitFn := context.WithCancel(context.Background())
+	return &Manager{
+		logger:           logger.Named("event_sinks"),
+		raft:             raft,
+		sinks:            make(map[string]*sinkRunner),
+		progressInterval: defaultProgressInterval,
+		ctx:              ctx,
+		exitFn:           exitFn,
+	}
+}
+
+// SetEnabled is used to control if the manager is enabled. The manager
+// should only be enabled on the active leader. The state store is passed in
+// when enabling it, as it is no longer valid once a leader election has
+// taken place.
+func (m *Manager) SetEnabled(enabled bool, state *state.StateStore) {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	m.enabled = enabled
+	if state != nil {
+		m.state = state
+	}
+
+	// Stop the running sinks and their delivery. Sinks are restarted from
+	// their committed progress when enabled again.
+	m.flush()
+
+	if enabled {
+		go m.watchSinks(m.ctx, m.state)
+		go m.commitProgress(m.ctx)
+	}
+}
+
+// flush stops the running sinks and the manager goroutines.
+func (m *Manager) flush() {
+	for _, runner := range m.sinks {
+		runner.stop()
+	}
+	m.exitFn()
+
+	m.sinks = make(map[string]*sinkRunner)
+	m.ctx, m.exitFn = context.WithCancel(context.Background())
+}
+
+// watchSinks is the long lived goroutine that starts, restarts, and stops
+// the sinks as they are registered, updated, and deregistered.
+func (m *Manager) watchSinks(ctx context.Context, store *state.StateStore) {
+	index := uint64(1)
+	for {
+		resp, newIndex, err := store.BlockingQuery(getSinks, index, ctx)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			m.logger.Error("failed to retrieve event sinks", "error", err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+				continue
+			}
+		}
+
+		index = newIndex
+		m.reconcile(ctx, store, resp.([]*structs.EventSink))
+	}
+}
+
+// getSinks retrieves all event sinks from the passed state store.
+func getSinks(ws memdb.WatchSet, store *state.StateStore) (interface{}, uint64, error) {
+	iter, err := store.EventSinks(ws, state.SortDefault)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var sinks []*structs.EventSink
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		sinks = append(sinks, raw.(*structs.EventSink))
+	}
+
+	// Use the last index that affected the event sinks table.
+	index, err := store.Index(state.TableEventSinks)
+	if err != nil {
+		return nil, 0, err
+	}
+	return sinks, max(1, index), nil
+}
+
+// reconcile makes the running sinks match the registered sinks. Sinks are
+// restarted when their configuration changes, resuming from the last event
+// they delivered.
+func (m *Manager) reconcile(ctx context.Context, store *state.StateStore, sinks []*structs.EventSink) {
+	m.l.Lock()
+	defer m.l.Unlock()
+
+	// The manager may have been disabled while the query was running.
+	if !m.enabled || ctx.Err() != nil {
+		return
+	}
+
+	registered := make(map[string]struct{}, len(sinks))
+	for _, sink := range sinks {
+		registered[sink.ID] = struct{}{}
+
+		startIndex := sink.LatestIndex
+		committed := sink.LatestIndex
+		if runner, ok := m.sinks[sink.ID]; ok {
+			if runner.sink.ModifyIndex == sink.ModifyIndex {
+				continue
+			}
+			runner.stop()
+			startIndex = max(startIndex, runner.latestIndex.Load())
+			committed = max(committed, runner.committed)
+		}
+
+		runner, err := newSinkRunner(ctx, m.logger, store, sink.Copy(), startIndex)
+		if err != nil {
+			m.logger.Error("failed to start event sink", "sink_id", sink.ID, "error", err)
+			delete(m.sinks, sink.ID)
+			continue
+		}
+		runner.committed = committed
+		m.sinks[sink.ID] = runner
+		go runner.run()
+	}
+
+	for id, runner := range m.sinks {
+		if _, ok := registered[id]; !ok {
+			runner.stop()
+			delete(m.sinks, id)
+		}
+	}
+}
+
+// commitProgress is the long lived goroutine that periodically commits the
+// delivery progress of the sinks to raft.
+func (m *Manager) commitProgress(ctx context.Context) {
+	ticker := time.NewTicker(m.progressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := m.commit(); err != nil {
+			m.logger.Warn("failed to commit event sinks progress", "error", err)
+		}
+	}
+}
+
+// commit commits the progress of the sinks that delivered events since the
+// last commit.
+func (m *Manager) commit() error {
+	m.l.Lock()
+	progress := make(map[string]uint64)
+	runners := make(map[string]*sinkRunner)
+	for id, runner := range m.sinks {
+		if index := runner.latestIndex.Load(); index > runner.committed {
+			progress[id] = index
+			runners[id] = runner
+		}
+	}
+	m.l.Unlock()
+
+	if len(progress) == 0 {
+		return nil
+	}
+
+	defer metrics.MeasureSince([]string{"nomad", "event_sink", "commit_progress"}, time.Now())
+	if _, err := m.raft.UpdateEventSinksProgress(progress); err != nil {
+		return err
+	}
+
+	m.l.Lock()
+	defer m.l.Unlock()
+	for id, runner := range runners {
+		runner.committed = max(runner.committed, progress[id])
+	}
+	return nil
+}
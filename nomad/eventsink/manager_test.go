@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package eventsink
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+// testProgressApplier commits the progress to the state store like the raft
+// shim of the server.
+type testProgressApplier struct {
+	state *state.StateStore
+	index uint64
+
+	l        sync.Mutex
+	progress []map[string]uint64
+}
+
+func (a *testProgressApplier) UpdateEventSinksProgress(progress map[string]uint64) (uint64, error) {
+	a.l.Lock()
+	defer a.l.Unlock()
+
+	a.index++
+	a.progress = append(a.progress, progress)
+	return a.index, a.state.UpdateEventSinksProgress(structs.MsgTypeTestSetup, a.index, progress)
+}
+
+// testWebhook records the batches of events it receives. Its first requests
+// fail until failures reaches zero.
+type testWebhook struct {
+	l        sync.Mutex
+	failures int
+	batches  []structs.Events
+}
+
+func (w *testWebhook) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	w.l.Lock()
+	defer w.l.Unlock()
+
+	if w.failures > 0 {
+		w.failures--
+		resp.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var events structs.Events
+	if err := json.NewDecoder(req.Body).Decode(&events); err != nil {
+		resp.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.batches = append(w.batches, events)
+}
+
+func (w *testWebhook) Batches() []structs.Events {
+	w.l.Lock()
+	defer w.l.Unlock()
+	return append([]structs.Events(nil), w.batches...)
+}
+
+func TestManager_DeliverWebhook(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStoreCfg(t, state.TestStateStorePublisher(t))
+	defer store.StopEventBroker()
+
+	webhook := &testWebhook{failures: 2}
+	srv := httptest.NewServer(webhook)
+	defer srv.Close()
+
+	sink := mock.EventSink()
+	sink.Address = srv.URL
+	sink.Topics = map[structs.Topic][]string{structs.TopicNode: {"*"}}
+	sink.MinBackoff = 10 * time.Millisecond
+	sink.MaxBackoff = 50 * time.Millisecond
+	must.NoError(t, store.UpsertEventSinks(structs.MsgTypeTestSetup, 100, []*structs.EventSink{sink}))
+
+	applier := &testProgressApplier{state: store, index: 1000}
+	m := NewManager(testlog.HCLogger(t), applier)
+	m.progressInterval = 50 * time.Millisecond
+	m.SetEnabled(true, store)
+	defer m.SetEnabled(false, nil)
+
+	// Jobs don't match the sink topics.
+	must.NoError(t, store.UpsertJob(structs.JobRegisterRequestType, 101, nil, mock.Job()))
+	node := mock.Node()
+	must.NoError(t, store.UpsertNode(structs.NodeRegisterRequestType, 102, node))
+
+	// The failed deliveries are retried.
+	testutil.WaitForResult(func() (bool, error) {
+		batches := webhook.Batches()
+		if len(batches) != 1 {
+			return false, fmt.Errorf("expected 1 batch, got %d", len(batches))
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	batch := webhook.Batches()[0]
+	must.Eq(t, 102, batch.Index)
+	must.Len(t, 1, batch.Events)
+	must.Eq(t, structs.TopicNode, batch.Events[0].Topic)
+	must.Eq(t, node.ID, batch.Events[0].Key)
+
+	// The progress is committed.
+	testutil.WaitForResult(func() (bool, error) {
+		got, err := store.EventSinkByID(nil, sink.ID)
+		if err != nil {
+			return false, err
+		}
+		if got.LatestIndex != 102 {
+			return false, fmt.Errorf("expected latest index 102, got %d", got.LatestIndex)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
+
+func TestManager_ResumeFromProgress(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStoreCfg(t, state.TestStateStorePublisher(t))
+	defer store.StopEventBroker()
+
+	webhook := &testWebhook{}
+	srv := httptest.NewServer(webhook)
+	defer srv.Close()
+
+	sink := mock.EventSink()
+	sink.Address = srv.URL
+	sink.Topics = map[structs.Topic][]string{structs.TopicNode: {"*"}}
+	must.NoError(t, store.UpsertEventSinks(structs.MsgTypeTestSetup, 100, []*structs.EventSink{sink}))
+
+	// Events up to the committed progress were delivered by the previous
+	// leader and are not delivered again.
+	must.NoError(t, store.UpsertNode(structs.NodeRegisterRequestType, 101, mock.Node()))
+	must.NoError(t, store.UpsertNode(structs.NodeRegisterRequestType, 102, mock.Node()))
+	must.NoError(t, store.UpdateEventSinksProgress(structs.MsgTypeTestSetup, 103,
+		map[string]uint64{sink.ID: 101}))
+
+	applier := &testProgressApplier{state: store, index: 1000}
+	m := NewManager(testlog.HCLogger(t), applier)
+	m.SetEnabled(true, store)
+	defer m.SetEnabled(false, nil)
+
+	testutil.WaitForResult(func() (bool, error) {
+		batches := webhook.Batches()
+		if len(batches) != 1 {
+			return false, fmt.Errorf("expected 1 batch, got %d", len(batches))
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+	must.Eq(t, 102, webhook.Batches()[0].Index)
+
+	// Deregistered sinks are stopped.
+	must.NoError(t, store.DeleteEventSinks(structs.MsgTypeTestSetup, 104, []string{sink.ID}))
+	testutil.WaitForResult(func() (bool, error) {
+		m.l.Lock()
+		defer m.l.Unlock()
+		if len(m.sinks) != 0 {
+			return false, fmt.Errorf("expected no running sinks, got %d", len(m.sinks))
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+}
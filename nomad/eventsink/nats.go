@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package eventsink
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/nats-io/nats.go"
+)
+
+// natsPublisher publishes each event as a message on the subject
+// "<subject>.<topic>" of a NATS server. After publishing a batch it flushes
+// the connection, which waits for the server to answer a PING and so
+// guarantees the messages were processed.
+type natsPublisher struct {
+	address string
+	subject string
+
+	conn *nats.Conn
+}
+
+func newNATSPublisher(sink *structs.EventSink) *natsPublisher {
+	n := &natsPublisher{
+		address: sink.Address,
+	}
+	if sink.NATS != nil {
+		n.subject = sink.NATS.Subject
+	}
+	return n
+}
+
+func (n *natsPublisher) Publish(ctx context.Context, events *structs.Events) error {
+	if n.conn == nil {
+		// Reconnecting is left to the retries of the sink, so a failed batch
+		// is reported rather than buffered by the client.
+		conn, err := nats.Connect(n.address,
+			nats.Name("nomad"),
+			nats.Timeout(publishTimeout),
+			nats.NoReconnect(),
+		)
+		if err != nil {
+			return err
+		}
+		n.conn = conn
+	}
+
+	if err := n.publish(ctx, events); err != nil {
+		// Drop the connection so the next attempt starts from a clean state.
+		n.Close()
+		return err
+	}
+	return nil
+}
+
+func (n *natsPublisher) publish(ctx context.Context, events *structs.Events) error {
+	for _, event := range events.Events {
+		payload, err := encodeJSON(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+		if err := n.conn.Publish(n.subject+"."+string(event.Topic), payload); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+	return n.conn.FlushWithContext(ctx)
+}
+
+func (n *natsPublisher) Close() error {
+	if n.conn == nil {
+		return nil
+	}
+	n.conn.Close()
+	n.conn = nil
+	return nil
+}
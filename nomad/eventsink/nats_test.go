@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package eventsink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+// natsMessage is a message received by the test NATS server.
+type natsMessage struct {
+	subject string
+	payload []byte
+}
+
+// testNATSServer accepts a single connection and implements enough of the
+// NATS protocol to receive messages. It rejects connections whose auth token
+// doesn't match.
+func testNATSServer(t *testing.T, token string) (string, <-chan natsMessage, <-chan map[string]interface{}) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	must.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	messages := make(chan natsMessage, 10)
+	connects := make(chan map[string]interface{}, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		fmt.Fprint(conn, "INFO {\"server_id\":\"test\",\"max_payload\":1048576}\r\n")
+
+		authorized := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+
+			switch {
+			case strings.HasPrefix(line, "CONNECT "):
+				var opts map[string]interface{}
+				json.Unmarshal([]byte(strings.TrimPrefix(line, "CONNECT ")), &opts)
+				connects <- opts
+				authorized = opts["auth_token"] == token
+			case strings.HasPrefix(line, "PUB "):
+				fields := strings.Fields(line)
+				size, _ := strconv.Atoi(fields[2])
+				payload := make([]byte, size+2)
+				if _, err := io.ReadFull(r, payload); err != nil {
+					return
+				}
+				messages <- natsMessage{subject: fields[1], payload: payload[:size]}
+			case line == "PING":
+				if !authorized {
+					fmt.Fprint(conn, "-ERR 'Authorization Violation'\r\n")
+					return
+				}
+				fmt.Fprint(conn, "PONG\r\n")
+			}
+		}
+	}()
+
+	return ln.Addr().String(), messages, connects
+}
+
+func TestNATSPublisher_Publish(t *testing.T) {
+	ci.Parallel(t)
+
+	addr, messages, connects := testNATSServer(t, "secret")
+
+	p, err := NewPublisher(&structs.EventSink{
+		Type:    structs.EventSinkTypeNATS,
+		Address: "nats://secret@" + addr,
+		NATS:    &structs.EventSinkNATSConfig{Subject: "nomad.events"},
+	})
+	must.NoError(t, err)
+	defer p.Close()
+
+	events := &structs.Events{
+		Index: 10,
+		Events: []structs.Event{
+			{Topic: structs.TopicJob, Type: structs.TypeJobRegistered, Key: "example", Index: 10},
+			{Topic: structs.TopicNode, Type: structs.TypeNodeRegistration, Key: "node-1", Index: 10},
+		},
+	}
+	must.NoError(t, p.Publish(context.Background(), events))
+
+	opts := <-connects
+	must.Eq(t, "secret", opts["auth_token"])
+	must.Eq(t, false, opts["verbose"])
+
+	msg := <-messages
+	must.Eq(t, "nomad.events.Job", msg.subject)
+	var event structs.Event
+	must.NoError(t, json.Unmarshal(msg.payload, &event))
+	must.Eq(t, "example", event.Key)
+
+	msg = <-messages
+	must.Eq(t, "nomad.events.Node", msg.subject)
+}
+
+func TestNATSPublisher_AuthError(t *testing.T) {
+	ci.Parallel(t)
+
+	addr, _, _ := testNATSServer(t, "secret")
+
+	p, err := NewPublisher(&structs.EventSink{
+		Type:    structs.EventSinkTypeNATS,
+		Address: "nats://wrong@" + addr,
+		NATS:    &structs.EventSinkNATSConfig{Subject: "nomad.events"},
+	})
+	must.NoError(t, err)
+	defer p.Close()
+
+	events := &structs.Events{
+		Index:  10,
+		Events: []structs.Event{{Topic: structs.TopicJob, Key: "example", Index: 10}},
+	}
+	err = p.Publish(context.Background(), events)
+	must.ErrorContains(t, err, "Authorization Violation")
+}
@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package eventsink
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// publishTimeout is the maximum time allowed to deliver a batch of events to
+// an event sink.
+const publishTimeout = 30 * time.Second
+
+// Publisher delivers batches of events to an event sink.
+type Publisher interface {
+	// Publish delivers the events to the sink. The events must be durably
+	// accepted by the sink when it returns without error.
+	Publish(ctx context.Context, events *structs.Events) error
+
+	// Close releases the resources of the publisher.
+	Close() error
+}
+
+// NewPublisher returns the publisher for the given event sink.
+func NewPublisher(sink *structs.EventSink) (Publisher, error) {
+	switch sink.Type {
+	case structs.EventSinkTypeWebhook:
+		return newWebhookPublisher(sink), nil
+	case structs.EventSinkTypeKafka:
+		return newKafkaPublisher(sink), nil
+	case structs.EventSinkTypeNATS:
+		return newNATSPublisher(sink), nil
+	default:
+		return nil, fmt.Errorf("unknown event sink type %q", sink.Type)
+	}
+}
+
+// encodeJSON encodes events the same way as the event stream API.
+func encodeJSON(v interface{}) ([]byte, error) {
+	var buf []byte
+	if err := codec.NewEncoderBytes(&buf, structs.JsonHandleWithExtensions).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package eventsink
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/stream"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// sinkRunner delivers the events of a single event sink.
+type sinkRunner struct {
+	sink      *structs.EventSink
+	logger    log.Logger
+	state     *state.StateStore
+	publisher Publisher
+
+	// latestIndex is the index of the last event delivered to the sink.
+	latestIndex atomic.Uint64
+
+	// committed is the last index committed to raft for the sink. It is
+	// guarded by the manager lock.
+	committed uint64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func newSinkRunner(ctx context.Context, logger log.Logger, store *state.StateStore,
+	sink *structs.EventSink, startIndex uint64) (*sinkRunner, error) {
+
+	// Sinks are canonicalized when registered, but make sure the backoff
+	// can't be zero.
+	sink.Canonicalize()
+
+	publisher, err := NewPublisher(sink)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &sinkRunner{
+		sink:      sink,
+		logger:    logger.With("sink_id", sink.ID, "sink_type", sink.Type),
+		state:     store,
+		publisher: publisher,
+	}
+	r.latestIndex.Store(startIndex)
+	r.ctx, r.cancel = context.WithCancel(ctx)
+	return r, nil
+}
+
+// stop stops the delivery of events to the sink.
+func (r *sinkRunner) stop() {
+	r.cancel()
+}
+
+// run delivers events to the sink until it is stopped, subscribing to the
+// event broker again if the subscription is closed.
+func (r *sinkRunner) run() {
+	defer r.publisher.Close()
+
+	backoff := r.sink.MinBackoff
+	for {
+		err := r.deliver()
+		if r.ctx.Err() != nil {
+			return
+		}
+
+		r.logger.Warn("event sink subscription failed", "error", err, "retry", backoff)
+		if !r.wait(backoff) {
+			return
+		}
+		backoff = r.nextBackoff(backoff)
+	}
+}
+
+// deliver subscribes to the event broker from the last delivered index and
+// publishes the events to the sink. It only returns once the subscription
+// fails or the runner is stopped.
+func (r *sinkRunner) deliver() error {
+	broker, err := r.state.EventBroker()
+	if err != nil {
+		return err
+	}
+
+	sub, err := broker.Subscribe(&stream.SubscribeRequest{
+		Topics:    r.sink.Topics,
+		Namespace: r.sink.Namespace,
+		Index:     r.latestIndex.Load() + 1,
+	})
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		events, err := sub.Next(r.ctx)
+		if err != nil {
+			return err
+		}
+
+		// The subscription starts at the closest index still buffered,
+		// which may already have been delivered.
+		if len(events.Events) == 0 || events.Index <= r.latestIndex.Load() {
+			continue
+		}
+
+		if err := r.publish(&events); err != nil {
+			return err
+		}
+		r.latestIndex.Store(events.Index)
+	}
+}
+
+// publish delivers the events to the sink, retrying with exponential backoff
+// until it succeeds or the runner is stopped.
+func (r *sinkRunner) publish(events *structs.Events) error {
+	labels := []metrics.Label{{Name: "sink_id", Value: r.sink.ID}}
+
+	backoff := r.sink.MinBackoff
+	for {
+		err := r.publisher.Publish(r.ctx, events)
+		if err == nil {
+			metrics.IncrCounterWithLabels([]string{"nomad", "event_sink", "delivered"},
+				float32(len(events.Events)), labels)
+			return nil
+		}
+		if r.ctx.Err() != nil {
+			return r.ctx.Err()
+		}
+
+		metrics.IncrCounterWithLabels([]string{"nomad", "event_sink", "delivery_failed"}, 1, labels)
+		r.logger.Warn("failed to deliver events", "index", events.Index, "error", err, "retry", backoff)
+		if !r.wait(backoff) {
+			return r.ctx.Err()
+		}
+		backoff = r.nextBackoff(backoff)
+	}
+}
+
+// wait waits for the given duration, returning false if the runner is
+// stopped first.
+func (r *sinkRunner) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-r.ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (r *sinkRunner) nextBackoff(backoff time.Duration) time.Duration {
+	return min(max(backoff*2, r.sink.MinBackoff), r.sink.MaxBackoff)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package eventsink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// webhookPublisher POSTs each batch of events to an HTTP endpoint, encoded
+// as JSON like the event stream API. Any 2xx response acknowledges the batch.
+type webhookPublisher struct {
+	address string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newWebhookPublisher(sink *structs.EventSink) *webhookPublisher {
+	client := cleanhttp.DefaultPooledClient()
+	client.Timeout = publishTimeout
+
+	w := &webhookPublisher{
+		address: sink.Address,
+		client:  client,
+	}
+	if sink.Webhook != nil {
+		w.headers = sink.Webhook.Headers
+	}
+	return w
+}
+
+func (w *webhookPublisher) Publish(ctx context.Context, events *structs.Events) error {
+	body, err := encodeJSON(events)
+	if err != nil {
+		return fmt.Errorf("failed to encode events: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Drain the response so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (w *webhookPublisher) Close() error {
+	w.client.CloseIdleConnections()
+	return nil
+}
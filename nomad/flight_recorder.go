@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"errors"
+	"net/rpc"
+	"time"
+
+	"github.com/hashicorp/nomad/command/agent/flightrecorder"
+	"github.com/hashicorp/nomad/helper"
+)
+
+// flightRecorderCodec wraps a server codec to record the latency of the RPCs
+// it serves in the flight recorder.
+type flightRecorderCodec struct {
+	rpc.ServerCodec
+	recorder *flightrecorder.Recorder
+
+	// method, start, and blocking describe the RPC being served.
+	method   string
+	start    time.Time
+	blocking bool
+}
+
+// wrapFlightRecorder wraps the codec to record the latency of the RPCs it
+// serves if the flight recorder is enabled.
+func (s *Server) wrapFlightRecorder(codec rpc.ServerCodec) rpc.ServerCodec {
+	if s.config.FlightRecorder == nil {
+		return codec
+	}
+	return &flightRecorderCodec{ServerCodec: codec, recorder: s.config.FlightRecorder}
+}
+
+func (c *flightRecorderCodec) ReadRequestHeader(r *rpc.Request) error {
+	err := c.ServerCodec.ReadRequestHeader(r)
+	c.method = r.ServiceMethod
+	c.start = time.Now()
+	c.blocking = false
+	return err
+}
+
+func (c *flightRecorderCodec) ReadRequestBody(body any) error {
+	err := c.ServerCodec.ReadRequestBody(body)
+
+	// The latency of blocking queries includes the time spent waiting for
+	// changes, so they are recorded separately.
+	if q, ok := body.(interface{ TimeToBlock() time.Duration }); ok {
+		c.blocking = q.TimeToBlock() > 0
+	}
+	return err
+}
+
+func (c *flightRecorderCodec) WriteResponse(r *rpc.Response, body any) error {
+	var rpcErr error
+	if r.Error != "" {
+		rpcErr = errors.New(r.Error)
+	}
+	c.recorder.RecordRPC(c.method, c.start, c.blocking, rpcErr)
+	return c.ServerCodec.WriteResponse(r, body)
+}
+
+// recordEvalBrokerStats records the eval broker stats in the flight recorder
+// while the broker is enabled on the leader.
+func (s *Server) recordEvalBrokerStats(period time.Duration, stopCh <-chan struct{}) {
+	timer, stop := helper.NewSafeTimer(period)
+	defer stop()
+
+	for {
+		timer.Reset(period)
+
+		select {
+		case <-timer.C:
+			if !s.evalBroker.Enabled() {
+				continue
+			}
+			stats := s.evalBroker.Stats()
+			s.config.FlightRecorder.RecordEvalBroker(flightrecorder.EvalBrokerSample{
+				Ready:      stats.TotalReady,
+				Unacked:    stats.TotalUnacked,
+				Pending:    stats.TotalPending,
+				Waiting:    stats.TotalWaiting,
+				Cancelable: stats.TotalCancelable,
+			})
+
+		case <-stopCh:
+			return
+		}
+	}
+}
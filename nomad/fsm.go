@@ -4,6 +4,7 @@
 package nomad
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
@@ -56,7 +57,7 @@ const (
 	CSIPluginSnapshot                    SnapshotType = 17
 	CSIVolumeSnapshot                    SnapshotType = 18
 	ScalingEventsSnapshot                SnapshotType = 19
-	EventSinkSnapshot                    SnapshotType = 20
+	LegacyEventSinkSnapshot              SnapshotType = 20
 	ServiceRegistrationSnapshot          SnapshotType = 21
 	VariablesSnapshot                    SnapshotType = 22
 	VariablesQuotaSnapshot               SnapshotType = 23
@@ -66,6 +67,13 @@ const (
 	ACLBindingRuleSnapshot               SnapshotType = 27
 	NodePoolSnapshot                     SnapshotType = 28
 	JobSubmissionSnapshot                SnapshotType = 29
+	JobTemplateSnapshot                  SnapshotType = 30
+	EventSinkSnapshot                    SnapshotType = 31
+	UsageRecordSnapshot                  SnapshotType = 32
+	VariableVersionSnapshot              SnapshotType = 33
+	VariableGrantSnapshot                SnapshotType = 34
+	MaintenanceWindowSnapshot            SnapshotType = 35
+	ClientUpgradeSnapshot                SnapshotType = 36
 
 	// Namespace appliers were moved from enterprise and therefore start at 64
 	NamespaceSnapshot SnapshotType = 64
@@ -105,6 +113,10 @@ type nomadFSM struct {
 	// enterpriseRestorers holds the set of enterprise only snapshot restorers
 	enterpriseRestorers SnapshotRestorers
 
+	// snapshotCache holds the sections of previous snapshots, and is nil if
+	// the section cache is disabled
+	snapshotCache *snapshotCache
+
 	// stateLock is only used to protect outside callers to State() from
 	// racing with Restore(), which is called by Raft (it puts in a totally
 	// new state store). Everything internal here is synchronized by the
@@ -118,6 +130,16 @@ type nomadFSM struct {
 type nomadSnapshot struct {
 	snap      *state.StateSnapshot
 	timetable *TimeTable
+
+	// cache holds the sections of previous snapshots that can be reused, and
+	// is nil if the section cache is disabled. cacheGeneration is the
+	// generation of the cache when the state snapshot was taken.
+	cache           *snapshotCache
+	cacheGeneration uint64
+
+	// chunkSize and writeRate throttle writing the snapshot to the sink
+	chunkSize int
+	writeRate int64
 }
 
 // snapshotHeader is the first entry in our snapshot
@@ -152,6 +174,23 @@ type FSMConfig struct {
 
 	// JobTrackedVersions is the number of historic job versions that are kept.
 	JobTrackedVersions int
+
+	// VariablesTrackedVersions is the number of prior versions of each
+	// variable that are kept.
+	VariablesTrackedVersions int
+
+	// SnapshotCacheDir is the directory where the sections of snapshots are
+	// cached so that later snapshots can reuse the sections of tables that
+	// haven't changed. The section cache is disabled if empty.
+	SnapshotCacheDir string
+
+	// SnapshotChunkSize is the size of the chunks snapshots are written to
+	// the snapshot sink in.
+	SnapshotChunkSize int
+
+	// SnapshotWriteRate limits the rate in bytes per second that snapshots
+	// are written to the snapshot sink. Zero means no limit.
+	SnapshotWriteRate int64
 }
 
 // NewFSM is used to construct a new FSM with a blank state.
@@ -163,6 +202,8 @@ func NewFSM(config *FSMConfig) (*nomadFSM, error) {
 		EnablePublisher:    config.EnableEventBroker,
 		EventBufferSize:    config.EventBufferSize,
 		JobTrackedVersions: config.JobTrackedVersions,
+
+		VariablesTrackedVersions: config.VariablesTrackedVersions,
 	}
 	state, err := state.NewStateStore(sconfig)
 	if err != nil {
@@ -181,6 +222,13 @@ func NewFSM(config *FSMConfig) (*nomadFSM, error) {
 		enterpriseRestorers: make(map[SnapshotType]SnapshotRestorer, 8),
 	}
 
+	if config.SnapshotCacheDir != "" {
+		fsm.snapshotCache, err = newSnapshotCache(config.SnapshotCacheDir, fsm.logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create snapshot cache: %w", err)
+		}
+	}
+
 	// Register all the log applier functions
 	fsm.registerLogAppliers()
 
@@ -237,12 +285,26 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyNodePoolUpsert(msgType, buf[1:], log.Index)
 	case structs.NodePoolDeleteRequestType:
 		return n.applyNodePoolDelete(msgType, buf[1:], log.Index)
+	case structs.JobTemplateUpsertRequestType:
+		return n.applyJobTemplateUpsert(msgType, buf[1:], log.Index)
+	case structs.JobTemplateDeleteRequestType:
+		return n.applyJobTemplateDelete(msgType, buf[1:], log.Index)
+	case structs.EventSinkRegisterRequestType:
+		return n.applyEventSinkRegister(msgType, buf[1:], log.Index)
+	case structs.EventSinkDeregisterRequestType:
+		return n.applyEventSinkDeregister(msgType, buf[1:], log.Index)
+	case structs.EventSinkProgressRequestType:
+		return n.applyEventSinkProgress(msgType, buf[1:], log.Index)
+	case structs.UsageUpsertRequestType:
+		return n.applyUsageUpsert(msgType, buf[1:], log.Index)
 	case structs.JobRegisterRequestType:
 		return n.applyUpsertJob(msgType, buf[1:], log.Index)
 	case structs.JobDeregisterRequestType:
 		return n.applyDeregisterJob(msgType, buf[1:], log.Index)
 	case structs.EvalUpdateRequestType:
 		return n.applyUpdateEval(msgType, buf[1:], log.Index)
+	case structs.EvalBumpPriorityRequestType:
+		return n.applyBumpEvalPriority(msgType, buf[1:], log.Index)
 	case structs.EvalDeleteRequestType:
 		return n.applyDeleteEval(buf[1:], log.Index)
 	case structs.AllocUpdateRequestType:
@@ -267,6 +329,10 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyDeploymentDelete(buf[1:], log.Index)
 	case structs.JobStabilityRequestType:
 		return n.applyJobStability(buf[1:], log.Index)
+	case structs.JobNotifyRequestType:
+		return n.applyJobNotify(msgType, buf[1:], log.Index)
+	case structs.JobDispatchReleaseRequestType:
+		return n.applyJobDispatchRelease(msgType, buf[1:], log.Index)
 	case structs.ACLPolicyUpsertRequestType:
 		return n.applyACLPolicyUpsert(msgType, buf[1:], log.Index)
 	case structs.ACLPolicyDeleteRequestType:
@@ -275,6 +341,8 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyACLTokenUpsert(msgType, buf[1:], log.Index)
 	case structs.ACLTokenDeleteRequestType:
 		return n.applyACLTokenDelete(msgType, buf[1:], log.Index)
+	case structs.ACLTokenUseRequestType:
+		return n.applyACLTokenUse(msgType, buf[1:], log.Index)
 	case structs.ACLTokenBootstrapRequestType:
 		return n.applyACLTokenBootstrap(msgType, buf[1:], log.Index)
 	case structs.AutopilotRequestType:
@@ -303,6 +371,14 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyCSIVolumeRegister(buf[1:], log.Index)
 	case structs.CSIVolumeDeregisterRequestType:
 		return n.applyCSIVolumeDeregister(buf[1:], log.Index)
+	case structs.MaintenanceWindowUpsertRequestType:
+		return n.applyMaintenanceWindowUpsert(msgType, buf[1:], log.Index)
+	case structs.MaintenanceWindowDeleteRequestType:
+		return n.applyMaintenanceWindowDelete(msgType, buf[1:], log.Index)
+	case structs.ClientUpgradeUpsertRequestType:
+		return n.applyClientUpgradeUpsert(msgType, buf[1:], log.Index)
+	case structs.CSIVolumeSnapshotsUpdateRequestType:
+		return n.applyCSIVolumeSnapshotsUpdate(buf[1:], log.Index)
 	case structs.CSIVolumeClaimRequestType:
 		return n.applyCSIVolumeClaim(buf[1:], log.Index)
 	case structs.ScalingEventRegisterRequestType:
@@ -335,6 +411,14 @@ func (n *nomadFSM) Apply(log *raft.Log) interface{} {
 		return n.applyDeleteServiceRegistrationByNodeID(msgType, buf[1:], log.Index)
 	case structs.VarApplyStateRequestType:
 		return n.applyVariableOperation(msgType, buf[1:], log.Index)
+	case structs.VariablesExpireRequestType:
+		return n.applyVariablesExpire(msgType, buf[1:], log.Index)
+	case structs.VariablesPurgeDeletedRequestType:
+		return n.applyVariablesPurgeDeleted(msgType, buf[1:], log.Index)
+	case structs.VariableGrantUpsertRequestType:
+		return n.applyVariableGrantUpsert(msgType, buf[1:], log.Index)
+	case structs.VariableGrantDeleteRequestType:
+		return n.applyVariableGrantDelete(msgType, buf[1:], log.Index)
 	case structs.RootKeyMetaUpsertRequestType:
 		return n.applyRootKeyMetaUpsert(msgType, buf[1:], log.Index)
 	case structs.RootKeyMetaDeleteRequestType:
@@ -586,6 +670,96 @@ func (n *nomadFSM) applyNodePoolDelete(msgType structs.MessageType, buf []byte,
 	return nil
 }
 
+func (n *nomadFSM) applyJobTemplateUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_template_upsert"}, time.Now())
+	var req structs.JobTemplateUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertJobTemplates(msgType, index, req.Templates); err != nil {
+		n.logger.Error("UpsertJobTemplates failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyJobTemplateDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_template_delete"}, time.Now())
+	var req structs.JobTemplateDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteJobTemplates(msgType, index, req.Names); err != nil {
+		n.logger.Error("DeleteJobTemplates failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyEventSinkRegister(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_event_sink_register"}, time.Now())
+	var req structs.EventSinkUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertEventSinks(msgType, index, req.Sinks); err != nil {
+		n.logger.Error("UpsertEventSinks failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyEventSinkDeregister(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_event_sink_deregister"}, time.Now())
+	var req structs.EventSinkDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteEventSinks(msgType, index, req.IDs); err != nil {
+		n.logger.Error("DeleteEventSinks failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyEventSinkProgress(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_event_sink_progress"}, time.Now())
+	var req structs.EventSinkProgressRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateEventSinksProgress(msgType, index, req.Progress); err != nil {
+		n.logger.Error("UpdateEventSinksProgress failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (n *nomadFSM) applyUsageUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_usage_upsert"}, time.Now())
+	var req structs.UsageUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertUsage(msgType, index, &req); err != nil {
+		n.logger.Error("UpsertUsage failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 func (n *nomadFSM) applyUpsertJob(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "register_job"}, time.Now())
 	var req structs.JobRegisterRequest
@@ -898,6 +1072,30 @@ func (n *nomadFSM) handleUpsertedEval(eval *structs.Evaluation) {
 	}
 }
 
+func (n *nomadFSM) applyBumpEvalPriority(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "bump_eval_priority"}, time.Now())
+	var req structs.EvalBumpPriorityRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	evals, err := n.state.BumpEvalPriority(msgType, index, &req)
+	if err != nil {
+		n.logger.Error("BumpEvalPriority failed", "error", err)
+		return err
+	}
+
+	// Reorder the bumped evaluations that are waiting to be processed
+	for _, eval := range evals {
+		if eval.ShouldEnqueue() {
+			n.evalBroker.UpdatePriority(eval)
+		} else if eval.ShouldBlock() {
+			n.blockedEvals.UpdatePriority(eval)
+		}
+	}
+	return nil
+}
+
 func (n *nomadFSM) applyDeleteEval(buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "delete_eval"}, time.Now())
 	var req structs.EvalReapRequest
@@ -1198,6 +1396,39 @@ func (n *nomadFSM) applyJobStability(buf []byte, index uint64) interface{} {
 	return nil
 }
 
+// applyJobNotify is used to record that the notification of a job was sent
+func (n *nomadFSM) applyJobNotify(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_notify"}, time.Now())
+	var req structs.JobNotifyRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpdateJobNotified(msgType, index, &req); err != nil {
+		n.logger.Error("UpdateJobNotified failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyJobDispatchRelease is used to release queued dispatched jobs
+func (n *nomadFSM) applyJobDispatchRelease(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_job_dispatch_release"}, time.Now())
+	var req structs.JobDispatchReleaseRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.ReleaseQueuedDispatches(msgType, index, req.Evals); err != nil {
+		n.logger.Error("ReleaseQueuedDispatches failed", "error", err)
+		return err
+	}
+
+	n.handleUpsertedEvals(req.Evals)
+	return nil
+}
+
 // applyACLPolicyUpsert is used to upsert a set of policies
 func (n *nomadFSM) applyACLPolicyUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_policy_upsert"}, time.Now())
@@ -1243,6 +1474,24 @@ func (n *nomadFSM) applyACLTokenUpsert(msgType structs.MessageType, buf []byte,
 	return nil
 }
 
+// applyACLTokenUse is used to record a use of a token
+func (n *nomadFSM) applyACLTokenUse(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_use"}, time.Now())
+	var req structs.ACLTokenUseRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	// Exhausted tokens are an expected outcome, so they aren't logged
+	if err := n.state.ConsumeACLTokenUse(msgType, index, req.AccessorID); err != nil {
+		if !errors.Is(err, structs.ErrTokenExhausted) {
+			n.logger.Error("ConsumeACLTokenUse failed", "error", err)
+		}
+		return err
+	}
+	return nil
+}
+
 // applyACLTokenDelete is used to delete a set of policies
 func (n *nomadFSM) applyACLTokenDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_acl_token_delete"}, time.Now())
@@ -1369,6 +1618,21 @@ func (n *nomadFSM) applyCSIVolumeRegister(buf []byte, index uint64) interface{}
 	return nil
 }
 
+func (n *nomadFSM) applyCSIVolumeSnapshotsUpdate(buf []byte, index uint64) interface{} {
+	var req structs.CSIVolumeSnapshotsUpdateRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_csi_volume_snapshots_update"}, time.Now())
+
+	if err := n.state.UpdateCSIVolumeScheduledSnapshots(index, req.RequestNamespace(), req.VolumeID, req.Snapshots); err != nil {
+		n.logger.Error("CSIVolumeSnapshotsUpdate failed", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 func (n *nomadFSM) applyCSIVolumeDeregister(buf []byte, index uint64) interface{} {
 	var req structs.CSIVolumeDeregisterRequest
 	if err := structs.Decode(buf, &req); err != nil {
@@ -1496,6 +1760,12 @@ func (n *nomadFSM) Snapshot() (raft.FSMSnapshot, error) {
 	ns := &nomadSnapshot{
 		snap:      snap,
 		timetable: n.timetable,
+		cache:     n.snapshotCache,
+		chunkSize: n.config.SnapshotChunkSize,
+		writeRate: n.config.SnapshotWriteRate,
+	}
+	if ns.cache != nil {
+		ns.cacheGeneration = ns.cache.currentGeneration()
 	}
 	return ns, nil
 }
@@ -1516,6 +1786,11 @@ func (n *nomadFSM) RestoreWithFilter(old io.ReadCloser, filter *FSMFilter) error
 func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 	defer old.Close()
 
+	// The cached snapshot sections are of the state store being replaced
+	if n.snapshotCache != nil {
+		n.snapshotCache.reset()
+	}
+
 	// Create a new state store
 	config := &state.StateStoreConfig{
 		Logger:             n.config.Logger,
@@ -1523,6 +1798,8 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 		EnablePublisher:    n.config.EnableEventBroker,
 		EventBufferSize:    n.config.EventBufferSize,
 		JobTrackedVersions: n.config.JobTrackedVersions,
+
+		VariablesTrackedVersions: n.config.VariablesTrackedVersions,
 	}
 	newState, err := state.NewStateStore(config)
 	if err != nil {
@@ -1790,7 +2067,7 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 			}
 
 		// COMPAT(1.0): Allow 1.0-beta clusterers to gracefully handle
-		case EventSinkSnapshot:
+		case LegacyEventSinkSnapshot:
 			return nil
 
 		case ServiceRegistrationSnapshot:
@@ -1815,6 +2092,46 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 				return err
 			}
 
+		case VariableVersionSnapshot:
+			version := new(structs.VariableEncrypted)
+			if err := dec.Decode(version); err != nil {
+				return err
+			}
+
+			if err := restore.VariableVersionRestore(version); err != nil {
+				return err
+			}
+
+		case VariableGrantSnapshot:
+			grant := new(structs.VariableGrant)
+			if err := dec.Decode(grant); err != nil {
+				return err
+			}
+
+			if err := restore.VariableGrantRestore(grant); err != nil {
+				return err
+			}
+
+		case MaintenanceWindowSnapshot:
+			window := new(structs.MaintenanceWindow)
+			if err := dec.Decode(window); err != nil {
+				return err
+			}
+
+			if err := restore.MaintenanceWindowRestore(window); err != nil {
+				return err
+			}
+
+		case ClientUpgradeSnapshot:
+			upgrade := new(structs.ClientUpgrade)
+			if err := dec.Decode(upgrade); err != nil {
+				return err
+			}
+
+			if err := restore.ClientUpgradeRestore(upgrade); err != nil {
+				return err
+			}
+
 		case VariablesQuotaSnapshot:
 			quota := new(structs.VariablesQuota)
 			if err := dec.Decode(quota); err != nil {
@@ -1884,6 +2201,42 @@ func (n *nomadFSM) restoreImpl(old io.ReadCloser, filter *FSMFilter) error {
 				return err
 			}
 
+		case JobTemplateSnapshot:
+			tmpl := new(structs.JobTemplate)
+
+			if err := dec.Decode(tmpl); err != nil {
+				return err
+			}
+
+			// Perform the restoration.
+			if err := restore.JobTemplateRestore(tmpl); err != nil {
+				return err
+			}
+
+		case EventSinkSnapshot:
+			sink := new(structs.EventSink)
+
+			if err := dec.Decode(sink); err != nil {
+				return err
+			}
+
+			// Perform the restoration.
+			if err := restore.EventSinkRestore(sink); err != nil {
+				return err
+			}
+
+		case UsageRecordSnapshot:
+			record := new(structs.UsageRecord)
+
+			if err := dec.Decode(record); err != nil {
+				return err
+			}
+
+			// Perform the restoration.
+			if err := restore.UsageRecordRestore(record); err != nil {
+				return err
+			}
+
 		case JobSubmissionSnapshot:
 			jobSubmissions := new(structs.JobSubmission)
 
@@ -2292,6 +2645,8 @@ func (n *nomadFSM) applyVariableOperation(msgType structs.MessageType, buf []byt
 		return n.state.VarLockAcquire(index, &req)
 	case structs.VarOpLockRelease:
 		return n.state.VarLockRelease(index, &req)
+	case structs.VarOpRekey:
+		return n.state.VarRekey(index, &req)
 	default:
 		err := fmt.Errorf("Invalid variable operation '%s'", req.Op)
 		n.logger.Warn("Invalid variable operation", "operation", req.Op)
@@ -2299,6 +2654,107 @@ func (n *nomadFSM) applyVariableOperation(msgType structs.MessageType, buf []byt
 	}
 }
 
+// applyVariablesExpire is used to delete the variables whose TTL has expired
+func (n *nomadFSM) applyVariablesExpire(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variables_expire"}, time.Now())
+	var req structs.VariablesExpireRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.VarsExpire(msgType, index, req.Timestamp); err != nil {
+		n.logger.Error("VarsExpire failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+// applyVariablesPurgeDeleted is used to permanently remove the deleted
+// variables whose retention period has passed
+func (n *nomadFSM) applyVariablesPurgeDeleted(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variables_purge_deleted"}, time.Now())
+	var req structs.VariablesPurgeDeletedRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.VarsPurgeDeleted(msgType, index, req.Threshold); err != nil {
+		n.logger.Error("VarsPurgeDeleted failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyVariableGrantUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variable_grant_upsert"}, time.Now())
+	var req structs.VariableGrantUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertVariableGrants(msgType, index, req.Grants); err != nil {
+		n.logger.Error("UpsertVariableGrants failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyVariableGrantDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_variable_grant_delete"}, time.Now())
+	var req structs.VariableGrantDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteVariableGrants(msgType, index, req.RequestNamespace(), req.Names); err != nil {
+		n.logger.Error("DeleteVariableGrants failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyMaintenanceWindowUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_maintenance_window_upsert"}, time.Now())
+	var req structs.MaintenanceWindowUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertMaintenanceWindows(msgType, index, req.Windows); err != nil {
+		n.logger.Error("UpsertMaintenanceWindows failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyMaintenanceWindowDelete(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_maintenance_window_delete"}, time.Now())
+	var req structs.MaintenanceWindowDeleteRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.DeleteMaintenanceWindows(msgType, index, req.IDs); err != nil {
+		n.logger.Error("DeleteMaintenanceWindows failed", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (n *nomadFSM) applyClientUpgradeUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
+	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_client_upgrade_upsert"}, time.Now())
+	var req structs.ClientUpgradeUpsertRequest
+	if err := structs.Decode(buf, &req); err != nil {
+		panic(fmt.Errorf("failed to decode request: %v", err))
+	}
+
+	if err := n.state.UpsertClientUpgrade(msgType, index, req.Upgrade); err != nil {
+		n.logger.Error("UpsertClientUpgrade failed", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (n *nomadFSM) applyRootKeyMetaUpsert(msgType structs.MessageType, buf []byte, index uint64) interface{} {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "apply_root_key_meta_upsert"}, time.Now())
 
@@ -2331,8 +2787,17 @@ func (n *nomadFSM) applyRootKeyMetaDelete(msgType structs.MessageType, buf []byt
 	return nil
 }
 
+// Persist writes a complete snapshot of the state store to the sink. Sections
+// of tables that haven't changed since the previous snapshot are copied from
+// the snapshot section cache instead of being encoded again, but the snapshot
+// raft stores and sends to other servers always holds every section.
 func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 	defer metrics.MeasureSince([]string{"nomad", "fsm", "persist"}, time.Now())
+
+	// Write the snapshot in chunks, throttled if configured
+	throttled := newThrottledSink(sink, s.chunkSize, s.writeRate)
+	sink = throttled
+
 	// Register the nodes
 	encoder := codec.NewEncoder(sink, structs.MsgpackHandle)
 
@@ -2351,123 +2816,13 @@ func (s *nomadSnapshot) Persist(sink raft.SnapshotSink) error {
 	}
 
 	// Write all the data out
-	if err := s.persistIndexes(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistNodes(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistNodePools(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistJobs(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistEvals(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistAllocs(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistPeriodicLaunches(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistJobSummaries(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistVaultAccessors(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistSITokenAccessors(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistJobVersions(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistDeployments(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistScalingPolicies(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistScalingEvents(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistCSIPlugins(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistCSIVolumes(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistACLPolicies(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistACLTokens(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistNamespaces(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistEnterpriseTables(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistSchedulerConfig(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistClusterMetadata(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistServiceRegistrations(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistVariables(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistVariablesQuotas(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistRootKeyMeta(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistACLRoles(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistACLAuthMethods(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
-	}
-	if err := s.persistACLBindingRules(sink, encoder); err != nil {
-		sink.Cancel()
-		return err
+	for _, section := range s.sections() {
+		if err := s.persistSection(sink, section); err != nil {
+			sink.Cancel()
+			return err
+		}
 	}
-	if err := s.persistJobSubmissions(sink, encoder); err != nil {
+	if err := throttled.Flush(); err != nil {
 		sink.Cancel()
 		return err
 	}
@@ -2550,6 +2905,69 @@ func (s *nomadSnapshot) persistNodePools(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistJobTemplates(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all job templates.
+	ws := memdb.NewWatchSet()
+	templates, err := s.snap.JobTemplates(ws, state.SortDefault)
+	if err != nil {
+		return err
+	}
+
+	// Iterate over all job templates and persist them.
+	for raw := templates.Next(); raw != nil; raw = templates.Next() {
+		tmpl := raw.(*structs.JobTemplate)
+
+		sink.Write([]byte{byte(JobTemplateSnapshot)})
+		if err := encoder.Encode(tmpl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistEventSinks(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all event sinks.
+	ws := memdb.NewWatchSet()
+	sinks, err := s.snap.EventSinks(ws, state.SortDefault)
+	if err != nil {
+		return err
+	}
+
+	// Iterate over all event sinks and persist them.
+	for raw := sinks.Next(); raw != nil; raw = sinks.Next() {
+		eventSink := raw.(*structs.EventSink)
+
+		sink.Write([]byte{byte(EventSinkSnapshot)})
+		if err := encoder.Encode(eventSink); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistUsageRecords(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+	// Get all usage records.
+	ws := memdb.NewWatchSet()
+	records, err := s.snap.UsageRecords(ws)
+	if err != nil {
+		return err
+	}
+
+	// Iterate over all usage records and persist them.
+	for raw := records.Next(); raw != nil; raw = records.Next() {
+		record := raw.(*structs.UsageRecord)
+
+		sink.Write([]byte{byte(UsageRecordSnapshot)})
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *nomadSnapshot) persistJobs(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 	// Get all the jobs
@@ -3073,6 +3491,86 @@ func (s *nomadSnapshot) persistVariables(sink raft.SnapshotSink,
 	return nil
 }
 
+func (s *nomadSnapshot) persistVariableVersions(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	versions, err := s.snap.VariableVersions(ws)
+	if err != nil {
+		return err
+	}
+
+	for {
+		raw := versions.Next()
+		if raw == nil {
+			break
+		}
+		version := raw.(*structs.VariableEncrypted)
+		sink.Write([]byte{byte(VariableVersionSnapshot)})
+		if err := encoder.Encode(version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistVariableGrants(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	grants, err := s.snap.VariableGrants(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := grants.Next(); raw != nil; raw = grants.Next() {
+		grant := raw.(*structs.VariableGrant)
+		sink.Write([]byte{byte(VariableGrantSnapshot)})
+		if err := encoder.Encode(grant); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistMaintenanceWindows(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	windows, err := s.snap.MaintenanceWindows(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := windows.Next(); raw != nil; raw = windows.Next() {
+		window := raw.(*structs.MaintenanceWindow)
+		sink.Write([]byte{byte(MaintenanceWindowSnapshot)})
+		if err := encoder.Encode(window); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *nomadSnapshot) persistClientUpgrades(sink raft.SnapshotSink,
+	encoder *codec.Encoder) error {
+
+	ws := memdb.NewWatchSet()
+	upgrades, err := s.snap.ClientUpgrades(ws)
+	if err != nil {
+		return err
+	}
+
+	for raw := upgrades.Next(); raw != nil; raw = upgrades.Next() {
+		upgrade := raw.(*structs.ClientUpgrade)
+		sink.Write([]byte{byte(ClientUpgradeSnapshot)})
+		if err := encoder.Encode(upgrade); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *nomadSnapshot) persistVariablesQuotas(sink raft.SnapshotSink,
 	encoder *codec.Encoder) error {
 
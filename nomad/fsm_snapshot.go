@@ -0,0 +1,385 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/raft"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// defaultSnapshotChunkSize is the size of the chunks the FSM snapshot is
+	// written to the snapshot sink in
+	defaultSnapshotChunkSize = 1024 * 1024
+)
+
+// snapshotSection is a section of the FSM snapshot, which holds the records
+// of one or more snapshot types.
+type snapshotSection struct {
+	// name is used for metrics and logging
+	name string
+
+	// table is the state store table whose index tracks changes to the
+	// section. Sections with a table can be reused from the snapshot cache
+	// when the table hasn't changed since the previous snapshot.
+	table string
+
+	persist func(sink raft.SnapshotSink, encoder *codec.Encoder) error
+}
+
+// sections returns the sections of the snapshot, in the order they are
+// written.
+func (s *nomadSnapshot) sections() []snapshotSection {
+	return []snapshotSection{
+		{name: "indexes", persist: s.persistIndexes},
+		{name: "nodes", table: "nodes", persist: s.persistNodes},
+		{name: "node_pools", persist: s.persistNodePools},
+		{name: "job_templates", persist: s.persistJobTemplates},
+		{name: "event_sinks", persist: s.persistEventSinks},
+		{name: "usage_records", persist: s.persistUsageRecords},
+		{name: "jobs", table: "jobs", persist: s.persistJobs},
+		{name: "evals", table: "evals", persist: s.persistEvals},
+		{name: "allocs", table: "allocs", persist: s.persistAllocs},
+		{name: "periodic_launches", persist: s.persistPeriodicLaunches},
+		{name: "job_summaries", persist: s.persistJobSummaries},
+		{name: "vault_accessors", persist: s.persistVaultAccessors},
+		{name: "si_token_accessors", persist: s.persistSITokenAccessors},
+		{name: "job_versions", table: "job_version", persist: s.persistJobVersions},
+		{name: "deployments", table: "deployment", persist: s.persistDeployments},
+		{name: "scaling_policies", persist: s.persistScalingPolicies},
+		{name: "scaling_events", persist: s.persistScalingEvents},
+		{name: "csi_plugins", persist: s.persistCSIPlugins},
+		{name: "csi_volumes", persist: s.persistCSIVolumes},
+		{name: "acl_policies", persist: s.persistACLPolicies},
+		{name: "acl_tokens", persist: s.persistACLTokens},
+		{name: "namespaces", persist: s.persistNamespaces},
+		{name: "enterprise", persist: s.persistEnterpriseTables},
+		{name: "scheduler_config", persist: s.persistSchedulerConfig},
+		{name: "cluster_metadata", persist: s.persistClusterMetadata},
+		{name: "service_registrations", persist: s.persistServiceRegistrations},
+		{name: "variables", persist: s.persistVariables},
+		{name: "variables_quotas", persist: s.persistVariablesQuotas},
+		{name: "variable_versions", persist: s.persistVariableVersions},
+		{name: "variable_grants", persist: s.persistVariableGrants},
+		{name: "maintenance_windows", persist: s.persistMaintenanceWindows},
+		{name: "client_upgrades", persist: s.persistClientUpgrades},
+		{name: "root_key_meta", persist: s.persistRootKeyMeta},
+		{name: "acl_roles", persist: s.persistACLRoles},
+		{name: "acl_auth_methods", persist: s.persistACLAuthMethods},
+		{name: "acl_binding_rules", persist: s.persistACLBindingRules},
+		{name: "job_submissions", persist: s.persistJobSubmissions},
+	}
+}
+
+// persistSection writes a section of the snapshot and emits its size and
+// duration. If the snapshot cache holds the section as of the current index
+// of its table, the cached section is written instead of encoding the
+// section's records again.
+func (s *nomadSnapshot) persistSection(sink raft.SnapshotSink, section snapshotSection) error {
+	start := time.Now()
+	labels := []metrics.Label{{Name: "section", Value: section.name}}
+
+	counter := &countingSink{SnapshotSink: sink, w: sink}
+
+	var index uint64
+	cache := s.cache
+	if cache != nil && section.table != "" {
+		var err error
+		index, err = s.snap.Index(section.table)
+		if err != nil {
+			return err
+		}
+		ok, err := cache.copyTo(counter, s.cacheGeneration, section.name, index)
+		if err != nil {
+			return fmt.Errorf("failed to copy cached snapshot section %q: %w", section.name, err)
+		}
+		if ok {
+			metrics.IncrCounterWithLabels([]string{"nomad", "fsm", "persist", "section_cached"}, 1, labels)
+			emitSectionMetrics(start, counter.n, labels)
+			return nil
+		}
+	} else {
+		cache = nil
+	}
+
+	// Encode the section, writing it to the snapshot cache as well if the
+	// section can be reused by later snapshots
+	var entry *snapshotCacheWriter
+	if cache != nil {
+		var err error
+		entry, err = cache.create(s.cacheGeneration, section.name, index)
+		if err != nil {
+			cache.logger.Warn("failed to cache snapshot section", "section", section.name, "error", err)
+		} else {
+			counter.w = io.MultiWriter(sink, entry)
+		}
+	}
+
+	if err := section.persist(counter, codec.NewEncoder(counter, structs.MsgpackHandle)); err != nil {
+		if entry != nil {
+			entry.abort()
+		}
+		return err
+	}
+	if entry != nil {
+		if err := entry.commit(); err != nil {
+			cache.logger.Warn("failed to cache snapshot section", "section", section.name, "error", err)
+		}
+	}
+
+	emitSectionMetrics(start, counter.n, labels)
+	return nil
+}
+
+func emitSectionMetrics(start time.Time, size int64, labels []metrics.Label) {
+	metrics.MeasureSinceWithLabels([]string{"nomad", "fsm", "persist", "section"}, start, labels)
+	metrics.AddSampleWithLabels([]string{"nomad", "fsm", "persist", "section_bytes"}, float32(size), labels)
+}
+
+// countingSink is a raft.SnapshotSink that counts the bytes written to it and
+// writes them to w, which may tee them to the snapshot cache.
+type countingSink struct {
+	raft.SnapshotSink
+	w io.Writer
+	n int64
+}
+
+func (c *countingSink) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// throttledSink is a raft.SnapshotSink that writes the snapshot in chunks and
+// waits for the rate limiter before writing each chunk, which spreads the disk
+// I/O of persisting large snapshots over time instead of saturating the disk.
+// Raft sends snapshots to followers from the snapshot store, so transfers to
+// other servers aren't throttled.
+type throttledSink struct {
+	raft.SnapshotSink
+	buf     *bufio.Writer
+	limiter *rate.Limiter
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// newThrottledSink returns a sink that writes to sink in chunks of chunkSize
+// bytes, at most bytesPerSecond bytes per second. A zero rate disables the
+// throttling.
+func newThrottledSink(sink raft.SnapshotSink, chunkSize int, bytesPerSecond int64) *throttledSink {
+	if chunkSize <= 0 {
+		chunkSize = defaultSnapshotChunkSize
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &throttledSink{
+		SnapshotSink: sink,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+	if bytesPerSecond > 0 {
+		t.limiter = rate.NewLimiter(rate.Limit(bytesPerSecond), chunkSize)
+	}
+	t.buf = bufio.NewWriterSize(writerFunc(t.writeChunk), chunkSize)
+	return t
+}
+
+func (t *throttledSink) writeChunk(p []byte) (int, error) {
+	if t.limiter != nil {
+		start := time.Now()
+		for remaining := len(p); remaining > 0; {
+			n := min(remaining, t.limiter.Burst())
+			if err := t.limiter.WaitN(t.ctx, n); err != nil {
+				return 0, err
+			}
+			remaining -= n
+		}
+		metrics.MeasureSince([]string{"nomad", "fsm", "persist", "throttled"}, start)
+	}
+	return t.SnapshotSink.Write(p)
+}
+
+func (t *throttledSink) Write(p []byte) (int, error) {
+	return t.buf.Write(p)
+}
+
+// Flush writes the last chunk to the sink. Raft closes the underlying sink
+// once the snapshot is persisted, so Flush must be called before returning.
+func (t *throttledSink) Flush() error {
+	defer t.cancel()
+	return t.buf.Flush()
+}
+
+func (t *throttledSink) Cancel() error {
+	t.cancel()
+	return t.SnapshotSink.Cancel()
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+// snapshotCache holds the encoded sections of the previous snapshot on disk,
+// so that sections of tables that haven't changed don't have to be encoded
+// again. This makes snapshotting large state stores, where most of the
+// allocations and evaluations are unchanged between snapshots, cheaper. The
+// snapshots themselves are still complete, and are written and sent to other
+// servers in full.
+type snapshotCache struct {
+	dir    string
+	logger hclog.Logger
+
+	lock       sync.Mutex
+	generation uint64
+	entries    map[string]snapshotCacheEntry
+}
+
+type snapshotCacheEntry struct {
+	generation uint64
+	index      uint64
+	path       string
+}
+
+// newSnapshotCache returns a snapshot cache in a new directory below dir.
+// Every FSM has its own cache, as the cached sections are only valid for the
+// state store they were encoded from.
+func newSnapshotCache(dir string, logger hclog.Logger) (*snapshotCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	cacheDir, err := os.MkdirTemp(dir, "fsm-")
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotCache{
+		dir:     cacheDir,
+		logger:  logger,
+		entries: map[string]snapshotCacheEntry{},
+	}, nil
+}
+
+// currentGeneration returns the generation of the cache, which changes every
+// time the cache is reset. Snapshots taken before a reset can neither read nor
+// write the cache afterwards.
+func (c *snapshotCache) currentGeneration() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.generation
+}
+
+// copyTo writes the cached section to w if it was cached at the index. It
+// returns false if the section isn't cached at the index.
+func (c *snapshotCache) copyTo(w io.Writer, generation uint64, section string, index uint64) (bool, error) {
+	c.lock.Lock()
+	entry, ok := c.entries[section]
+	c.lock.Unlock()
+	if !ok || entry.generation != generation || entry.index != index {
+		return false, nil
+	}
+
+	f, err := os.Open(entry.path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	if _, err := io.Copy(w, f); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// create returns a writer for the section at the index, which replaces the
+// cached section once committed.
+func (c *snapshotCache) create(generation uint64, section string, index uint64) (*snapshotCacheWriter, error) {
+	f, err := os.CreateTemp(c.dir, section+"-*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotCacheWriter{
+		cache:      c,
+		file:       f,
+		generation: generation,
+		section:    section,
+		index:      index,
+	}, nil
+}
+
+// reset removes all cached sections. It must be called whenever the state
+// store is replaced, because the indexes of the new state store say nothing
+// about the cached sections.
+func (c *snapshotCache) reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.generation++
+	for section, entry := range c.entries {
+		os.Remove(entry.path)
+		delete(c.entries, section)
+	}
+}
+
+// snapshotCacheWriter writes a section to the snapshot cache.
+type snapshotCacheWriter struct {
+	cache      *snapshotCache
+	file       *os.File
+	generation uint64
+	section    string
+	index      uint64
+	err        error
+}
+
+// Write never fails, so that a failure to cache the section doesn't fail the
+// snapshot. The error is returned by commit instead.
+func (w *snapshotCacheWriter) Write(p []byte) (int, error) {
+	if w.err == nil {
+		_, w.err = w.file.Write(p)
+	}
+	return len(p), nil
+}
+
+func (w *snapshotCacheWriter) abort() {
+	w.file.Close()
+	os.Remove(w.file.Name())
+}
+
+func (w *snapshotCacheWriter) commit() error {
+	if w.err != nil {
+		w.abort()
+		return w.err
+	}
+	if err := w.file.Close(); err != nil {
+		os.Remove(w.file.Name())
+		return err
+	}
+
+	path := filepath.Join(w.cache.dir, w.section+".bin")
+	w.cache.lock.Lock()
+	defer w.cache.lock.Unlock()
+	if w.generation != w.cache.generation {
+		os.Remove(w.file.Name())
+		return nil
+	}
+	if err := os.Rename(w.file.Name(), path); err != nil {
+		os.Remove(w.file.Name())
+		delete(w.cache.entries, w.section)
+		return err
+	}
+	w.cache.entries[w.section] = snapshotCacheEntry{
+		generation: w.generation,
+		index:      w.index,
+		path:       path,
+	}
+	return nil
+}
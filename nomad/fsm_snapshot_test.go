@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+func persistTestSnapshot(t *testing.T, fsm *nomadFSM) []byte {
+	t.Helper()
+
+	snap, err := fsm.Snapshot()
+	must.NoError(t, err)
+	defer snap.Release()
+
+	sink := &MockSink{bytes.NewBuffer(nil), false}
+	must.NoError(t, snap.Persist(sink))
+	must.False(t, sink.cancel)
+	return sink.Bytes()
+}
+
+func TestFSM_SnapshotSectionCache(t *testing.T) {
+	ci.Parallel(t)
+
+	fsm := testFSM(t)
+	fsm.snapshotCache, _ = newSnapshotCache(t.TempDir(), fsm.logger)
+	must.NotNil(t, fsm.snapshotCache)
+	store := fsm.State()
+
+	node := mock.Node()
+	must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+	job := mock.Job()
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1001, nil, job))
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.NodeID = node.ID
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1002, []*structs.Allocation{alloc}))
+
+	// The first snapshot encodes every section and caches the sections of
+	// the large tables
+	first := persistTestSnapshot(t, fsm)
+	must.MapContainsKeys(t, fsm.snapshotCache.entries, []string{"nodes", "jobs", "allocs"})
+
+	// A snapshot without changes is identical to the first one, although its
+	// sections come from the cache
+	second := persistTestSnapshot(t, fsm)
+	must.Eq(t, first, second)
+
+	// Changing a table invalidates its cached section only
+	allocsIndex := fsm.snapshotCache.entries["allocs"].index
+	nodesIndex := fsm.snapshotCache.entries["nodes"].index
+	alloc2 := alloc.Copy()
+	alloc2.ID = "d52a5a9c-a7b3-4bbd-aa8b-3f5e5bfdc4b2"
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1003, []*structs.Allocation{alloc2}))
+	persistTestSnapshot(t, fsm)
+	must.Eq(t, 1003, fsm.snapshotCache.entries["allocs"].index)
+	must.NotEq(t, allocsIndex, fsm.snapshotCache.entries["allocs"].index)
+	must.Eq(t, nodesIndex, fsm.snapshotCache.entries["nodes"].index)
+
+	// The snapshot with cached sections restores to the same state
+	fsm2 := testSnapshotRestore(t, fsm)
+	out, err := fsm2.State().AllocByID(nil, alloc2.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+
+	// Restoring a snapshot invalidates the cache
+	generation := fsm.snapshotCache.currentGeneration()
+	must.NoError(t, fsm.Restore(&MockSink{bytes.NewBuffer(first), false}))
+	must.MapLen(t, 0, fsm.snapshotCache.entries)
+	must.Eq(t, generation+1, fsm.snapshotCache.currentGeneration())
+}
+
+func TestFSM_SnapshotThrottled(t *testing.T) {
+	ci.Parallel(t)
+
+	fsm := testFSM(t)
+	var nodes []*structs.Node
+	for i := 0; i < 10; i++ {
+		node := mock.Node()
+		must.NoError(t, fsm.State().UpsertNode(structs.MsgTypeTestSetup, uint64(1000+i), node))
+		nodes = append(nodes, node)
+	}
+	unthrottled := persistTestSnapshot(t, fsm)
+
+	// The burst of the limiter is the chunk size, so writing the snapshot in
+	// small chunks at a low rate takes a measurable amount of time
+	fsm.config.SnapshotChunkSize = 4096
+	fsm.config.SnapshotWriteRate = int64(len(unthrottled))
+	start := time.Now()
+	throttled := persistTestSnapshot(t, fsm)
+	must.Greater(t, 500*time.Millisecond, time.Since(start))
+
+	// The encoding of maps isn't deterministic, so compare the restored
+	// state rather than the bytes
+	must.Eq(t, len(unthrottled), len(throttled))
+	fsm2 := testFSM(t)
+	must.NoError(t, fsm2.Restore(&MockSink{bytes.NewBuffer(throttled), false}))
+	for _, node := range nodes {
+		out, err := fsm2.State().NodeByID(nil, node.ID)
+		must.NoError(t, err)
+		must.Eq(t, node, out)
+	}
+}
@@ -674,6 +674,101 @@ func TestFSM_NodePoolDelete(t *testing.T) {
 	}
 }
 
+func TestFSM_JobTemplateUpsertDelete(t *testing.T) {
+	ci.Parallel(t)
+
+	fsm := testFSM(t)
+	templates := []*structs.JobTemplate{mock.JobTemplate(), mock.JobTemplate()}
+	buf, err := structs.Encode(structs.JobTemplateUpsertRequestType,
+		structs.JobTemplateUpsertRequest{Templates: templates})
+	must.NoError(t, err)
+
+	resp := fsm.Apply(makeLog(buf))
+	must.Nil(t, resp)
+
+	for _, tmpl := range templates {
+		got, err := fsm.State().JobTemplateByName(nil, tmpl.Name)
+		must.NoError(t, err)
+		must.Eq(t, tmpl.Source, got.Source)
+	}
+
+	buf, err = structs.Encode(structs.JobTemplateDeleteRequestType,
+		structs.JobTemplateDeleteRequest{Names: []string{templates[0].Name}})
+	must.NoError(t, err)
+
+	resp = fsm.Apply(makeLog(buf))
+	must.Nil(t, resp)
+
+	got, err := fsm.State().JobTemplateByName(nil, templates[0].Name)
+	must.NoError(t, err)
+	must.Nil(t, got)
+	got, err = fsm.State().JobTemplateByName(nil, templates[1].Name)
+	must.NoError(t, err)
+	must.NotNil(t, got)
+}
+
+func TestFSM_EventSinkRegisterDeregister(t *testing.T) {
+	ci.Parallel(t)
+
+	fsm := testFSM(t)
+	sinks := []*structs.EventSink{mock.EventSink(), mock.EventSink()}
+	buf, err := structs.Encode(structs.EventSinkRegisterRequestType,
+		structs.EventSinkUpsertRequest{Sinks: sinks})
+	must.NoError(t, err)
+
+	resp := fsm.Apply(makeLog(buf))
+	must.Nil(t, resp)
+
+	for _, sink := range sinks {
+		got, err := fsm.State().EventSinkByID(nil, sink.ID)
+		must.NoError(t, err)
+		must.Eq(t, sink.Address, got.Address)
+	}
+
+	buf, err = structs.Encode(structs.EventSinkProgressRequestType,
+		structs.EventSinkProgressRequest{Progress: map[string]uint64{sinks[1].ID: 10}})
+	must.NoError(t, err)
+
+	resp = fsm.Apply(makeLog(buf))
+	must.Nil(t, resp)
+
+	buf, err = structs.Encode(structs.EventSinkDeregisterRequestType,
+		structs.EventSinkDeleteRequest{IDs: []string{sinks[0].ID}})
+	must.NoError(t, err)
+
+	resp = fsm.Apply(makeLog(buf))
+	must.Nil(t, resp)
+
+	got, err := fsm.State().EventSinkByID(nil, sinks[0].ID)
+	must.NoError(t, err)
+	must.Nil(t, got)
+	got, err = fsm.State().EventSinkByID(nil, sinks[1].ID)
+	must.NoError(t, err)
+	must.Eq(t, 10, got.LatestIndex)
+}
+
+func TestFSM_UsageUpsert(t *testing.T) {
+	ci.Parallel(t)
+
+	fsm := testFSM(t)
+	record := &structs.UsageRecord{Namespace: "default", JobID: "web", Start: 100, ReservedCPU: 10}
+	buf, err := structs.Encode(structs.UsageUpsertRequestType,
+		structs.UsageUpsertRequest{Usage: []*structs.UsageRecord{record}})
+	must.NoError(t, err)
+
+	resp := fsm.Apply(makeLog(buf))
+	must.Nil(t, resp)
+
+	resp = fsm.Apply(makeLog(buf))
+	must.Nil(t, resp)
+
+	iter, err := fsm.State().UsageRecordsByJob(nil, "default", "web")
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	must.Eq(t, 20, raw.(*structs.UsageRecord).ReservedCPU)
+}
+
 func TestFSM_NodePoolUpsert(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2445,6 +2540,57 @@ func TestFSM_SnapshotRestore_NodePools(t *testing.T) {
 	must.Eq(t, pool, out)
 }
 
+func TestFSM_SnapshotRestore_JobTemplates(t *testing.T) {
+	ci.Parallel(t)
+
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	tmpl := mock.JobTemplate()
+	state.UpsertJobTemplates(structs.MsgTypeTestSetup, 1000, []*structs.JobTemplate{tmpl})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, _ := state2.JobTemplateByName(nil, tmpl.Name)
+	must.Eq(t, tmpl, out)
+}
+
+func TestFSM_SnapshotRestore_EventSinks(t *testing.T) {
+	ci.Parallel(t)
+
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	sink := mock.EventSink()
+	state.UpsertEventSinks(structs.MsgTypeTestSetup, 1000, []*structs.EventSink{sink})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	out, _ := state2.EventSinkByID(nil, sink.ID)
+	must.Eq(t, sink, out)
+}
+
+func TestFSM_SnapshotRestore_UsageRecords(t *testing.T) {
+	ci.Parallel(t)
+
+	// Add some state
+	fsm := testFSM(t)
+	state := fsm.State()
+	record := &structs.UsageRecord{Namespace: "default", JobID: "web", Start: 100, ReservedCPU: 10}
+	state.UpsertUsage(structs.MsgTypeTestSetup, 1000,
+		&structs.UsageUpsertRequest{Usage: []*structs.UsageRecord{record}})
+
+	// Verify the contents
+	fsm2 := testSnapshotRestore(t, fsm)
+	state2 := fsm2.State()
+	iter, _ := state2.UsageRecordsByJob(nil, "default", "web")
+	out := iter.Next().(*structs.UsageRecord)
+	must.Eq(t, 10, out.ReservedCPU)
+	must.Eq(t, 1000, out.CreateIndex)
+}
+
 func TestFSM_SnapshotRestore_Jobs(t *testing.T) {
 	ci.Parallel(t)
 	// Add some state
@@ -3515,6 +3661,7 @@ func TestFSM_SnapshotRestore_Variables(t *testing.T) {
 		msvs[sv.Path].CreateTime = sv.CreateTime
 		msvs[sv.Path].ModifyIndex = sv.ModifyIndex
 		msvs[sv.Path].ModifyTime = sv.ModifyTime
+		msvs[sv.Path].Version = sv.Version
 	}
 	svs = msvs.List()
 
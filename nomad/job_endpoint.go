@@ -21,6 +21,7 @@ import (
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/helper"
 	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/state/paginator"
@@ -63,37 +64,48 @@ type Job struct {
 
 // NewJobEndpoints creates a new job endpoint with builtin admission controllers
 func NewJobEndpoints(s *Server, ctx *RPCContext) *Job {
+	// Mutating admission webhooks run after the job is canonicalized but
+	// before the builtin mutators, so the builtin mutators view their changes.
+	// Validating admission webhooks run last, so they only view valid jobs.
+	mutators := []jobMutator{
+		&jobCanonicalizer{srv: s},
+	}
+	mutators = append(mutators, admissionWebhookMutators(s.admissionWebhooks)...)
+	mutators = append(mutators,
+		jobVaultHook{srv: s},
+		jobConsulHook{srv: s},
+		jobConnectHook{},
+		jobExposeCheckHook{},
+		jobImpliedConstraints{},
+		jobNodePoolMutatingHook{srv: s},
+		jobImplicitIdentitiesHook{srv: s},
+		jobNumaHook{},
+	)
+
+	validators := []jobValidator{
+		jobConnectHook{},
+		jobExposeCheckHook{},
+		jobVaultHook{srv: s},
+		jobConsulHook{srv: s},
+		jobNamespaceConstraintCheckHook{srv: s},
+		jobNodePoolValidatingHook{srv: s},
+		&jobValidate{srv: s},
+		&memoryOversubscriptionValidate{srv: s},
+		jobNumaHook{},
+	}
+	validators = append(validators, admissionWebhookValidators(s.admissionWebhooks)...)
+
 	return &Job{
-		srv:    s,
-		ctx:    ctx,
-		logger: s.logger.Named("job"),
-		mutators: []jobMutator{
-			&jobCanonicalizer{srv: s},
-			jobVaultHook{srv: s},
-			jobConsulHook{srv: s},
-			jobConnectHook{},
-			jobExposeCheckHook{},
-			jobImpliedConstraints{},
-			jobNodePoolMutatingHook{srv: s},
-			jobImplicitIdentitiesHook{srv: s},
-			jobNumaHook{},
-		},
-		validators: []jobValidator{
-			jobConnectHook{},
-			jobExposeCheckHook{},
-			jobVaultHook{srv: s},
-			jobConsulHook{srv: s},
-			jobNamespaceConstraintCheckHook{srv: s},
-			jobNodePoolValidatingHook{srv: s},
-			&jobValidate{srv: s},
-			&memoryOversubscriptionValidate{srv: s},
-			jobNumaHook{},
-		},
+		srv:        s,
+		ctx:        ctx,
+		logger:     s.logger.Named("job"),
+		mutators:   mutators,
+		validators: validators,
 	}
 }
 
 // Register is used to upsert a job for scheduling
-func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegisterResponse) error {
+func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegisterResponse) (retErr error) {
 	authErr := j.srv.Authenticate(j.ctx, args)
 	if done, err := j.srv.forward("Job.Register", args, args, reply); done {
 		return err
@@ -104,6 +116,10 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	}
 	defer metrics.MeasureSince([]string{"nomad", "job", "register"}, time.Now())
 
+	span := j.srv.tracer.Start("Job.Register", args.TraceParent,
+		tracing.String("nomad.namespace", args.RequestNamespace()))
+	defer func() { span.End(retErr) }()
+
 	aclObj, err := j.srv.ResolveACL(args)
 	if err != nil {
 		return err
@@ -117,6 +133,7 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	if args.Job == nil {
 		return fmt.Errorf("missing job for registration")
 	}
+	span.SetAttributes(tracing.String("nomad.job.id", args.Job.ID))
 
 	// defensive check; http layer and RPC requester should ensure namespaces are set consistently
 	if args.RequestNamespace() != args.Job.Namespace {
@@ -130,6 +147,18 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 	}
 	args.Job = job
 
+	// Sealed values must be sealed by the submitter so that their cleartext
+	// is never written to raft
+	if args.Job.HasSealedValues() {
+		if args.Job.ParentID != "" {
+			return structs.NewErrRPCCoded(http.StatusBadRequest,
+				"jobs with a parent job cannot have sealed values")
+		}
+		if err := args.Job.ValidateSealed(); err != nil {
+			return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
+		}
+	}
+
 	// Run the submission controller
 	warnings = append(warnings, j.submissionController(args))
 
@@ -215,6 +244,33 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return err
 	}
 
+	// Avoid registering new versions of the job for retry requests, by using
+	// the idempotency token
+	if args.IdempotencyToken != "" && existingJob != nil {
+		registered, err := registeredJobVersion(ws, snap, existingJob, args.IdempotencyToken)
+		if err != nil {
+			errMsg := "failed to retrieve job versions for idempotency check"
+			j.logger.Error(errMsg, "error", err)
+			return fmt.Errorf(errMsg)
+		}
+		if registered != nil {
+			// The job was already registered with this token, so return the
+			// version and evaluation of that registration
+			eval, err := registeredJobEval(ws, snap, registered)
+			if err != nil {
+				return err
+			}
+			if eval != nil {
+				reply.EvalID = eval.ID
+				reply.EvalCreateIndex = eval.CreateIndex
+			}
+			reply.JobModifyIndex = registered.JobModifyIndex
+			reply.Index = existingJob.ModifyIndex
+			return nil
+		}
+	}
+	args.Job.RegisterIdempotencyToken = args.IdempotencyToken
+
 	// If EnforceIndex set, check it before trying to apply
 	if args.EnforceIndex {
 		jmi := args.JobModifyIndex
@@ -235,6 +291,12 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		return err
 	}
 
+	// Restore the notify secrets of jobs resubmitted from their redacted
+	// API representation
+	if existingJob != nil {
+		args.Job.Notify.RestoreRedacted(existingJob.Notify)
+	}
+
 	// Ensure that all scaling policies have an appropriate ID
 	if err := propagateScalingPolicyIDs(existingJob, args.Job); err != nil {
 		return err
@@ -328,7 +390,7 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		}
 	}
 
-	// Submit a multiregion job to other regions (enterprise only).
+	// Submit a multiregion job to other regions.
 	// The job will have its region interpolated.
 	var newVersion uint64
 	if existingJob != nil {
@@ -365,10 +427,12 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 			TriggeredBy: structs.EvalTriggerJobRegister,
 			JobID:       args.Job.ID,
 			Status:      structs.EvalStatusPending,
+			TraceParent: span.TraceParent(),
 			CreateTime:  now,
 			ModifyTime:  now,
 		}
 		reply.EvalID = eval.ID
+		span.SetAttributes(tracing.String("nomad.eval.id", eval.ID))
 	}
 
 	// Check if the job has changed at all
@@ -402,6 +466,11 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 			reply.EvalCreateIndex = index
 		}
 
+		// Make the clients pull the images and artifacts of the job
+		if args.Job.Prefetch && !args.Job.Stopped() && !args.Job.IsPeriodic() && !args.Job.IsParameterized() {
+			go j.srv.prefetchJob(args.Job.Copy())
+		}
+
 	} else {
 		reply.JobModifyIndex = existingJob.JobModifyIndex
 	}
@@ -439,7 +508,7 @@ func (j *Job) Register(args *structs.JobRegisterRequest, reply *structs.JobRegis
 		reply.Index = evalIndex
 	}
 
-	// Kick off a multiregion deployment (enterprise only).
+	// Kick off a multiregion deployment.
 	if isRunner {
 		err = j.multiregionStart(args, reply)
 		if err != nil {
@@ -724,7 +793,7 @@ func (j *Job) Stable(args *structs.JobStabilityRequest, reply *structs.JobStabil
 }
 
 // Evaluate is used to force a job for re-evaluation
-func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegisterResponse) error {
+func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegisterResponse) (retErr error) {
 	authErr := j.srv.Authenticate(j.ctx, args)
 	if done, err := j.srv.forward("Job.Evaluate", args, args, reply); done {
 		return err
@@ -735,6 +804,11 @@ func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegis
 	}
 	defer metrics.MeasureSince([]string{"nomad", "job", "evaluate"}, time.Now())
 
+	span := j.srv.tracer.Start("Job.Evaluate", args.TraceParent,
+		tracing.String("nomad.namespace", args.RequestNamespace()),
+		tracing.String("nomad.job.id", args.JobID))
+	defer func() { span.End(retErr) }()
+
 	// Check for submit-job permissions
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
@@ -778,8 +852,10 @@ func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegis
 
 		for _, alloc := range allocs {
 			taskGroup := job.LookupTaskGroup(alloc.TaskGroup)
-			// Forcing rescheduling is only allowed if task group has rescheduling enabled
-			if taskGroup == nil || !taskGroup.ReschedulePolicy.Enabled() {
+			// Forcing rescheduling is only allowed if task group has
+			// rescheduling enabled, or to retry the failed indices of a job
+			// array
+			if taskGroup == nil || (!taskGroup.ReschedulePolicy.Enabled() && taskGroup.Array == nil) {
 				continue
 			}
 
@@ -800,9 +876,11 @@ func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegis
 		JobID:          job.ID,
 		JobModifyIndex: job.ModifyIndex,
 		Status:         structs.EvalStatusPending,
+		TraceParent:    span.TraceParent(),
 		CreateTime:     now,
 		ModifyTime:     now,
 	}
+	span.SetAttributes(tracing.String("nomad.eval.id", eval.ID))
 
 	// Create a AllocUpdateDesiredTransitionRequest request with the eval and any forced rescheduled allocs
 	updateTransitionReq := &structs.AllocUpdateDesiredTransitionRequest{
@@ -825,7 +903,7 @@ func (j *Job) Evaluate(args *structs.JobEvaluateRequest, reply *structs.JobRegis
 }
 
 // Deregister is used to remove a job the cluster.
-func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobDeregisterResponse) error {
+func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobDeregisterResponse) (retErr error) {
 	authErr := j.srv.Authenticate(j.ctx, args)
 	if done, err := j.srv.forward("Job.Deregister", args, args, reply); done {
 		return err
@@ -836,6 +914,11 @@ func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobD
 	}
 	defer metrics.MeasureSince([]string{"nomad", "job", "deregister"}, time.Now())
 
+	span := j.srv.tracer.Start("Job.Deregister", args.TraceParent,
+		tracing.String("nomad.namespace", args.RequestNamespace()),
+		tracing.String("nomad.job.id", args.JobID))
+	defer func() { span.End(retErr) }()
+
 	// Check for submit-job permissions
 	if aclObj, err := j.srv.ResolveACL(args); err != nil {
 		return err
@@ -893,10 +976,12 @@ func (j *Job) Deregister(args *structs.JobDeregisterRequest, reply *structs.JobD
 			TriggeredBy: structs.EvalTriggerJobDeregister,
 			JobID:       args.JobID,
 			Status:      structs.EvalStatusPending,
+			TraceParent: span.TraceParent(),
 			CreateTime:  now,
 			ModifyTime:  now,
 		}
 		reply.EvalID = eval.ID
+		span.SetAttributes(tracing.String("nomad.eval.id", eval.ID))
 	}
 
 	args.SubmitTime = now
@@ -1014,7 +1099,7 @@ func (j *Job) BatchDeregister(args *structs.JobBatchDeregisterRequest, reply *st
 }
 
 // Scale is used to modify one of the scaling targets in the job
-func (j *Job) Scale(args *structs.JobScaleRequest, reply *structs.JobRegisterResponse) error {
+func (j *Job) Scale(args *structs.JobScaleRequest, reply *structs.JobRegisterResponse) (retErr error) {
 	authErr := j.srv.Authenticate(j.ctx, args)
 	if done, err := j.srv.forward("Job.Scale", args, args, reply); done {
 		return err
@@ -1025,6 +1110,11 @@ func (j *Job) Scale(args *structs.JobScaleRequest, reply *structs.JobRegisterRes
 	}
 	defer metrics.MeasureSince([]string{"nomad", "job", "scale"}, time.Now())
 
+	span := j.srv.tracer.Start("Job.Scale", args.TraceParent,
+		tracing.String("nomad.namespace", args.RequestNamespace()),
+		tracing.String("nomad.job.id", args.JobID))
+	defer func() { span.End(retErr) }()
+
 	namespace := args.RequestNamespace()
 
 	aclObj, err := j.srv.ResolveACL(args)
@@ -1166,9 +1256,11 @@ func (j *Job) Scale(args *structs.JobScaleRequest, reply *structs.JobRegisterRes
 				JobID:          args.JobID,
 				JobModifyIndex: reply.JobModifyIndex,
 				Status:         structs.EvalStatusPending,
+				TraceParent:    span.TraceParent(),
 				CreateTime:     now,
 				ModifyTime:     now,
 			}
+			span.SetAttributes(tracing.String("nomad.eval.id", eval.ID))
 
 			_, evalIndex, err := j.srv.raftApply(
 				structs.EvalUpdateRequestType,
@@ -1345,6 +1437,7 @@ func (j *Job) GetJobVersions(args *structs.JobVersionsRequest,
 							return fmt.Errorf("failed to create job diff: %v", err)
 						}
 						reply.Diffs = append(reply.Diffs, d)
+						reply.DiffSummaries = append(reply.DiffSummaries, d.Summary(old, new))
 					}
 				}
 			} else {
@@ -1363,6 +1456,90 @@ func (j *Job) GetJobVersions(args *structs.JobVersionsRequest,
 	return j.srv.blockingRPC(&opts)
 }
 
+// GetJobVersionDiff is used to diff two versions of a job.
+func (j *Job) GetJobVersionDiff(args *structs.JobVersionDiffRequest,
+	reply *structs.JobVersionDiffResponse) error {
+	authErr := j.srv.Authenticate(j.ctx, args)
+	if done, err := j.srv.forward("Job.GetJobVersionDiff", args, args, reply); done {
+		return err
+	}
+	j.srv.MeasureRPCRate("job", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job", "get_job_version_diff"}, time.Now())
+
+	// Check for read-job permissions
+	if aclObj, err := j.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, state *state.StateStore) error {
+			// Versions are sorted from the newest to the oldest
+			versions, err := state.JobVersionsByID(ws, args.RequestNamespace(), args.JobID)
+			if err != nil {
+				return err
+			}
+			if len(versions) == 0 {
+				return structs.NewErrRPCCodedf(http.StatusNotFound, "job %q not found", args.JobID)
+			}
+			reply.Index = versions[0].ModifyIndex
+
+			to := versions[0]
+			if args.ToVersion != nil {
+				to = nil
+				for _, v := range versions {
+					if v.Version == *args.ToVersion {
+						to = v
+						break
+					}
+				}
+				if to == nil {
+					return structs.NewErrRPCCodedf(http.StatusNotFound,
+						"version %d of job %q not found", *args.ToVersion, args.JobID)
+				}
+			}
+
+			var from *structs.Job
+			for _, v := range versions {
+				if args.FromVersion != nil && v.Version == *args.FromVersion {
+					from = v
+					break
+				}
+				if args.FromVersion == nil && v.Version < to.Version {
+					// Use the newest version preceding the target version
+					from = v
+					break
+				}
+			}
+			if from == nil && args.FromVersion != nil {
+				return structs.NewErrRPCCodedf(http.StatusNotFound,
+					"version %d of job %q not found", *args.FromVersion, args.JobID)
+			} else if from == nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest,
+					"version %d of job %q has no previous version", to.Version, args.JobID)
+			}
+
+			diff, err := from.Diff(to, true)
+			if err != nil {
+				return fmt.Errorf("failed to create job diff: %v", err)
+			}
+			reply.Diff = diff
+			reply.Summary = diff.Summary(from, to)
+
+			// Set the query response
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
 // allowedNSes returns a set (as map of ns->true) of the namespaces a token has access to.
 // Returns `nil` set if the token has access to all namespaces
 // and ErrPermissionDenied if the token has no capabilities on any namespace.
@@ -1555,13 +1732,36 @@ func (j *Job) Allocations(args *structs.JobSpecificRequest,
 				return err
 			}
 
+			// Sort the allocations by ID so they can be paginated
+			sort.Slice(allocs, func(i, j int) bool {
+				if args.Reverse {
+					return allocs[i].ID > allocs[j].ID
+				}
+				return allocs[i].ID < allocs[j].ID
+			})
+			iter := allocsIterator(allocs)
+
 			// Convert to stubs
-			if len(allocs) > 0 {
-				reply.Allocations = make([]*structs.AllocListStub, 0, len(allocs))
-				for _, alloc := range allocs {
+			reply.Allocations = nil
+			tokenizer := paginator.NewStructsTokenizer(iter,
+				paginator.StructsTokenizerOptions{WithID: true})
+			paginator, err := paginator.NewPaginator(iter, tokenizer, nil, args.QueryOptions,
+				func(raw interface{}) error {
+					alloc := raw.(*structs.Allocation)
 					reply.Allocations = append(reply.Allocations, alloc.Stub(nil))
-				}
+					return nil
+				})
+			if err != nil {
+				return structs.NewErrRPCCodedf(
+					http.StatusBadRequest, "failed to create result paginator: %v", err)
+			}
+
+			nextToken, err := paginator.Page()
+			if err != nil {
+				return structs.NewErrRPCCodedf(
+					http.StatusBadRequest, "failed to read result page: %v", err)
 			}
+			reply.QueryMeta.NextToken = nextToken
 
 			// Use the last index that affected the allocs table
 			index, err := state.Index("allocs")
@@ -1578,6 +1778,15 @@ func (j *Job) Allocations(args *structs.JobSpecificRequest,
 	return j.srv.blockingRPC(&opts)
 }
 
+// allocsIterator returns an iterator over allocs for pagination.
+func allocsIterator(allocs []*structs.Allocation) *state.SliceIterator {
+	iter := state.NewSliceIterator()
+	for _, alloc := range allocs {
+		iter.Add(alloc)
+	}
+	return iter
+}
+
 // Evaluations is used to list the evaluations for a job
 func (j *Job) Evaluations(args *structs.JobSpecificRequest,
 	reply *structs.JobEvaluationsResponse) error {
@@ -1799,6 +2008,9 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 	if args.Job == nil {
 		return fmt.Errorf("Job required for plan")
 	}
+	if err := args.WhatIf.Validate(); err != nil {
+		return fmt.Errorf("invalid what-if changes: %v", err)
+	}
 
 	// Run admission controllers
 	job, warnings, err := j.admissionControllers(args.Job)
@@ -1823,6 +2035,10 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 				return structs.ErrPermissionDenied
 			}
 		}
+		// What-if changes are based on existing nodes
+		if args.WhatIf != nil && !aclObj.AllowNodeRead() {
+			return structs.ErrPermissionDenied
+		}
 	}
 
 	// Acquire a snapshot of the state
@@ -1831,6 +2047,12 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		return err
 	}
 
+	// Apply the hypothetical changes to the cluster
+	whatIfNodes, err := applyPlanWhatIf(snap, args.Job, args.WhatIf)
+	if err != nil {
+		return err
+	}
+
 	// Enforce Sentinel policies
 	nomadACLToken, err := snap.ACLTokenBySecretID(nil, args.AuthToken)
 	if err != nil && !strings.Contains(err.Error(), "missing secret id") {
@@ -1868,6 +2090,10 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		return err
 	}
 
+	if existingJob != nil {
+		args.Job.Notify.RestoreRedacted(existingJob.Notify)
+	}
+
 	var index uint64
 	var updatedIndex uint64
 
@@ -1958,6 +2184,13 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 		}
 	}
 
+	if len(whatIfNodes) > 0 {
+		reply.WhatIfPlacements = make(map[string]int, len(whatIfNodes))
+		for nodeID, name := range whatIfNodes {
+			reply.WhatIfPlacements[name] = len(planner.Plans[0].NodeAllocation[nodeID])
+		}
+	}
+
 	reply.FailedTGAllocs = updatedEval.FailedTGAllocs
 	reply.JobModifyIndex = index
 	reply.Annotations = annotations
@@ -1966,6 +2199,141 @@ func (j *Job) Plan(args *structs.JobPlanRequest, reply *structs.JobPlanResponse)
 	return nil
 }
 
+// applyPlanWhatIf applies the hypothetical changes to the cluster of a plan
+// request to the state snapshot the job is planned against. It returns the
+// names of the nodes added, keyed by node ID.
+func applyPlanWhatIf(snap *state.StateSnapshot, job *structs.Job, whatIf *structs.JobPlanWhatIf) (map[string]string, error) {
+	if whatIf == nil {
+		return nil, nil
+	}
+
+	index, err := snap.LatestIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	added := make(map[string]string)
+	for _, nodes := range whatIf.AddNodes {
+		template, err := whatIfNodeTemplate(snap, nodes.NodeClass)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < nodes.Count; i++ {
+			node := template.Copy()
+			node.ID = uuid.Generate()
+			node.SecretID = uuid.Generate()
+			node.Name = fmt.Sprintf("what-if-%s-%d", nodes.NodeClass, len(added)+1)
+			node.Status = structs.NodeStatusReady
+			node.SchedulingEligibility = structs.NodeSchedulingEligible
+			node.DrainStrategy = nil
+			node.LastDrain = nil
+			node.Events = nil
+			if nodes.Datacenter != "" {
+				node.Datacenter = nodes.Datacenter
+			}
+			if err := node.ComputeClass(); err != nil {
+				return nil, fmt.Errorf("failed to compute node class: %v", err)
+			}
+
+			index++
+			if err := snap.UpsertNode(structs.IgnoreUnknownTypeFlag, index, node); err != nil {
+				return nil, err
+			}
+			added[node.ID] = node.Name
+		}
+	}
+
+	for _, prefix := range whatIf.DrainNodes {
+		node, err := whatIfNodeByPrefix(snap, prefix)
+		if err != nil {
+			return nil, err
+		}
+
+		index++
+		err = snap.UpdateNodeDrain(structs.IgnoreUnknownTypeFlag, index, node.ID,
+			&structs.DrainStrategy{}, false, time.Now().Unix(), nil, nil, "")
+		if err != nil {
+			return nil, err
+		}
+
+		// Mark the job's allocations for migration as the drainer would
+		allocs, err := snap.AllocsByNode(nil, node.ID)
+		if err != nil {
+			return nil, err
+		}
+		transitions := make(map[string]*structs.DesiredTransition)
+		for _, alloc := range allocs {
+			if alloc.Namespace == job.Namespace && alloc.JobID == job.ID && !alloc.TerminalStatus() {
+				transitions[alloc.ID] = &structs.DesiredTransition{Migrate: pointer.Of(true)}
+			}
+		}
+		if len(transitions) > 0 {
+			index++
+			err := snap.UpdateAllocsDesiredTransitions(structs.IgnoreUnknownTypeFlag, index, transitions, nil)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return added, nil
+}
+
+// whatIfNodeTemplate returns an existing node of the node class to copy
+// hypothetical nodes from, preferring nodes that are ready and eligible.
+func whatIfNodeTemplate(snap *state.StateSnapshot, nodeClass string) (*structs.Node, error) {
+	iter, err := snap.Nodes(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var template *structs.Node
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if node.NodeClass != nodeClass {
+			continue
+		}
+		if node.Ready() {
+			return node, nil
+		}
+		if template == nil {
+			template = node
+		}
+	}
+
+	if template == nil {
+		return nil, fmt.Errorf("no node with node class %q to add nodes from", nodeClass)
+	}
+	return template, nil
+}
+
+// whatIfNodeByPrefix returns the node to drain with the given ID or unique ID
+// prefix.
+func whatIfNodeByPrefix(snap *state.StateSnapshot, prefix string) (*structs.Node, error) {
+	iter, err := snap.NodesByIDPrefix(nil, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *structs.Node
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if node.ID == prefix {
+			return node, nil
+		}
+		if found != nil {
+			return nil, fmt.Errorf("node prefix %q matches multiple nodes", prefix)
+		}
+		found = node
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("node %q not found", prefix)
+	}
+	return found, nil
+}
+
 // validateJobUpdate ensures updates to a job are valid.
 func validateJobUpdate(old, new *structs.Job) error {
 	// Validate Dispatch not set on new Jobs
@@ -2005,7 +2373,7 @@ func validateJobUpdate(old, new *structs.Job) error {
 }
 
 // Dispatch a parameterized job.
-func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispatchResponse) error {
+func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispatchResponse) (retErr error) {
 	authErr := j.srv.Authenticate(j.ctx, args)
 	if done, err := j.srv.forward("Job.Dispatch", args, args, reply); done {
 		return err
@@ -2016,6 +2384,11 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	}
 	defer metrics.MeasureSince([]string{"nomad", "job", "dispatch"}, time.Now())
 
+	span := j.srv.tracer.Start("Job.Dispatch", args.TraceParent,
+		tracing.String("nomad.namespace", args.RequestNamespace()),
+		tracing.String("nomad.job.id", args.JobID))
+	defer func() { span.End(retErr) }()
+
 	// Check for submit-job permissions
 	aclObj, err := j.srv.ResolveACL(args)
 	if err != nil {
@@ -2059,6 +2432,11 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	if err := validateDispatchRequest(args, parameterizedJob); err != nil {
 		return err
 	}
+	if args.Priority != 0 &&
+		(args.Priority < structs.JobMinPriority || args.Priority > j.srv.config.JobMaxPriority) {
+		return fmt.Errorf("dispatch priority must be between [%d, %d]",
+			structs.JobMinPriority, j.srv.config.JobMaxPriority)
+	}
 
 	// Avoid creating new dispatched jobs for retry requests, by using the idempotency token
 	if args.IdempotencyToken != "" {
@@ -2097,6 +2475,31 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 		}
 	}
 
+	// Queue the dispatched job if the parameterized job is at its concurrency
+	// limit. The leader evaluates it once a running dispatched job completes.
+	queued := false
+	if limit := parameterizedJob.ParameterizedJob.MaxConcurrent; limit > 0 && !parameterizedJob.IsPeriodic() {
+		j.srv.dispatchQueueLock.Lock()
+		defer j.srv.dispatchQueueLock.Unlock()
+
+		snap, err := j.srv.State().Snapshot()
+		if err != nil {
+			return err
+		}
+		running, queuedJobs, err := dispatchedJobs(nil, snap, parameterizedJob.Namespace, parameterizedJob.ID)
+		if err != nil {
+			return err
+		}
+		if running+len(queuedJobs) >= limit {
+			queueLimit := parameterizedJob.ParameterizedJob.QueueLimit
+			if queueLimit > 0 && len(queuedJobs) >= queueLimit {
+				return structs.NewErrRPCCodedf(http.StatusTooManyRequests,
+					"dispatch queue of job %q is full", args.JobID)
+			}
+			queued = true
+		}
+	}
+
 	// Derive the child job and commit it via Raft - with initial status
 	dispatchJob := parameterizedJob.Copy()
 	dispatchJob.ID = structs.DispatchedID(parameterizedJob.ID, args.IdPrefixTemplate, time.Now())
@@ -2107,6 +2510,10 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	dispatchJob.Status = ""
 	dispatchJob.StatusDescription = ""
 	dispatchJob.DispatchIdempotencyToken = args.IdempotencyToken
+	dispatchJob.DispatchQueued = queued
+	if args.Priority != 0 {
+		dispatchJob.Priority = args.Priority
+	}
 
 	// Merge in the meta data
 	for k, v := range args.Meta {
@@ -2134,9 +2541,10 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	reply.JobCreateIndex = jobCreateIndex
 	reply.DispatchedJobID = dispatchJob.ID
 	reply.Index = jobCreateIndex
+	reply.Queued = queued
 
-	// If the job is periodic, we don't create an eval.
-	if !dispatchJob.IsPeriodic() {
+	// If the job is periodic or queued, we don't create an eval.
+	if !dispatchJob.IsPeriodic() && !queued {
 		// Create a new evaluation
 		now := time.Now().UnixNano()
 		eval := &structs.Evaluation{
@@ -2148,9 +2556,12 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 			JobID:          dispatchJob.ID,
 			JobModifyIndex: jobCreateIndex,
 			Status:         structs.EvalStatusPending,
+			TraceParent:    span.TraceParent(),
 			CreateTime:     now,
 			ModifyTime:     now,
 		}
+		span.SetAttributes(tracing.String("nomad.dispatched_job.id", dispatchJob.ID),
+			tracing.String("nomad.eval.id", eval.ID))
 		update := &structs.EvalUpdateRequest{
 			Evals:        []*structs.Evaluation{eval},
 			WriteRequest: structs.WriteRequest{Region: args.Region},
@@ -2172,6 +2583,37 @@ func (j *Job) Dispatch(args *structs.JobDispatchRequest, reply *structs.JobDispa
 	return nil
 }
 
+// registeredJobVersion returns the tracked version of the job that was
+// registered with the idempotency token, or nil if there is none.
+func registeredJobVersion(ws memdb.WatchSet, snap *state.StateSnapshot, job *structs.Job, token string) (*structs.Job, error) {
+	versions, err := snap.JobVersionsByID(ws, job.Namespace, job.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range versions {
+		if version.RegisterIdempotencyToken == token {
+			return version, nil
+		}
+	}
+	return nil, nil
+}
+
+// registeredJobEval returns the evaluation created when the version of the
+// job was registered, or nil if there is none or it was garbage collected.
+func registeredJobEval(ws memdb.WatchSet, snap *state.StateSnapshot, job *structs.Job) (*structs.Evaluation, error) {
+	evals, err := snap.EvalsByJob(ws, job.Namespace, job.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, eval := range evals {
+		if eval.TriggeredBy == structs.EvalTriggerJobRegister &&
+			eval.JobModifyIndex == job.JobModifyIndex {
+			return eval, nil
+		}
+	}
+	return nil, nil
+}
+
 // validateDispatchRequest returns whether the request is valid given the
 // parameterized job.
 func validateDispatchRequest(req *structs.JobDispatchRequest, job *structs.Job) error {
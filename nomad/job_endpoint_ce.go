@@ -7,6 +7,10 @@
 package nomad
 
 import (
+	"fmt"
+	"maps"
+	"slices"
+
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -18,16 +22,128 @@ func (j *Job) enforceSubmitJob(override bool, job *structs.Job, existingJob *str
 // multiregionCreateDeployment is used to create a deployment to register along
 // with the job, if required.
 func (j *Job) multiregionCreateDeployment(job *structs.Job, eval *structs.Evaluation) *structs.Deployment {
-	return nil
+	if !job.IsMultiregion() || eval == nil || job.Type != structs.JobTypeService {
+		return nil
+	}
+
+	// The deployment is held until it's run by the region the job was
+	// submitted to, or by a peer region once its own deployment completes.
+	d := structs.NewDeployment(job, eval.Priority)
+	d.Status = structs.DeploymentStatusInitializing
+	d.StatusDescription = structs.DeploymentStatusDescriptionPendingForPeer
+	return d
 }
 
 // multiregionRegister is used to send a job across multiple regions
 func (j *Job) multiregionRegister(args *structs.JobRegisterRequest, reply *structs.JobRegisterResponse, newVersion uint64) (bool, error) {
-	return false, nil
+	if !args.Job.IsMultiregion() {
+		return false, nil
+	}
+
+	// Jobs registered by a peer region have already been interpolated for
+	// this region, so only the region the job was submitted to fans it out.
+	if args.Job.Region != structs.GlobalRegion {
+		return false, nil
+	}
+
+	local := j.srv.Region()
+	known := j.srv.Regions()
+	var localRegion *structs.MultiregionRegion
+	for _, region := range args.Job.Multiregion.Regions {
+		if !slices.Contains(known, region.Name) {
+			return false, fmt.Errorf("multiregion job region %q is not a known region", region.Name)
+		}
+		if region.Name == local {
+			localRegion = region
+		}
+	}
+	if localRegion == nil {
+		return false, fmt.Errorf("multiregion job must be submitted to one of its regions, not %q", local)
+	}
+
+	// Register the job in the peer regions first, so that their deployments
+	// are waiting to be run by the time this region starts the rollout.
+	for _, region := range args.Job.Multiregion.Regions {
+		if region.Name == local {
+			continue
+		}
+
+		req := &structs.JobRegisterRequest{
+			Submission:     args.Submission,
+			Job:            interpolateMultiregionJob(args.Job, region),
+			PreserveCounts: args.PreserveCounts,
+			PolicyOverride: args.PolicyOverride,
+			EvalPriority:   args.EvalPriority,
+			WriteRequest: structs.WriteRequest{
+				Region:    region.Name,
+				Namespace: args.RequestNamespace(),
+				AuthToken: args.AuthToken,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		if err := j.srv.RPC("Job.Register", req, &resp); err != nil {
+			return false, fmt.Errorf("failed to register job in region %q: %w", region.Name, err)
+		}
+	}
+
+	args.Job = interpolateMultiregionJob(args.Job, localRegion)
+	return true, nil
 }
 
 // multiregionStart is used to kick-off a deployment across multiple regions
 func (j *Job) multiregionStart(args *structs.JobRegisterRequest, reply *structs.JobRegisterResponse) error {
+	job := args.Job
+	if !job.IsMultiregion() || job.Type != structs.JobTypeService {
+		return nil
+	}
+
+	maxParallel := len(job.Multiregion.Regions)
+	if strategy := job.Multiregion.Strategy; strategy != nil && strategy.MaxParallel > 0 {
+		maxParallel = min(strategy.MaxParallel, maxParallel)
+	}
+
+	// Run the first pending deployments in region order. Regions whose job
+	// didn't change have no pending deployment and don't use up a slot.
+	started := 0
+	for _, region := range job.Multiregion.Regions {
+		if started == maxParallel {
+			break
+		}
+
+		latestReq := &structs.JobSpecificRequest{
+			JobID: job.ID,
+			QueryOptions: structs.QueryOptions{
+				Region:    region.Name,
+				Namespace: job.Namespace,
+				AuthToken: args.AuthToken,
+			},
+		}
+		var latestResp structs.SingleDeploymentResponse
+		if err := j.srv.RPC("Job.LatestDeployment", latestReq, &latestResp); err != nil {
+			return fmt.Errorf("failed to get deployment in region %q: %w", region.Name, err)
+		}
+
+		d := latestResp.Deployment
+		if d == nil || !d.IsMultiregion || (d.Status != structs.DeploymentStatusInitializing &&
+			d.Status != structs.DeploymentStatusPending) {
+			continue
+		}
+
+		runReq := &structs.DeploymentRunRequest{
+			DeploymentID: d.ID,
+			WriteRequest: structs.WriteRequest{
+				Region:    region.Name,
+				Namespace: job.Namespace,
+				AuthToken: args.AuthToken,
+			},
+		}
+		var runResp structs.DeploymentUpdateResponse
+		if err := j.srv.RPC("Deployment.Run", runReq, &runResp); err != nil {
+			return fmt.Errorf("failed to run deployment in region %q: %w", region.Name, err)
+		}
+		started++
+	}
+
 	return nil
 }
 
@@ -40,12 +156,79 @@ func (j *Job) multiregionDrop(args *structs.JobRegisterRequest, reply *structs.J
 // multiregionStop is used to fan-out Job.Deregister RPCs to all regions if
 // the global flag is passed to Job.Deregister
 func (j *Job) multiregionStop(job *structs.Job, args *structs.JobDeregisterRequest, reply *structs.JobDeregisterResponse) error {
+	if job == nil || !job.IsMultiregion() || !args.Global {
+		return nil
+	}
+
+	for _, region := range job.Multiregion.Regions {
+		if region.Name == j.srv.Region() {
+			continue
+		}
+
+		req := &structs.JobDeregisterRequest{
+			JobID:           args.JobID,
+			Purge:           args.Purge,
+			EvalPriority:    args.EvalPriority,
+			NoShutdownDelay: args.NoShutdownDelay,
+			WriteRequest: structs.WriteRequest{
+				Region:    region.Name,
+				Namespace: args.RequestNamespace(),
+				AuthToken: args.AuthToken,
+			},
+		}
+		var resp structs.JobDeregisterResponse
+		if err := j.srv.RPC("Job.Deregister", req, &resp); err != nil {
+			return fmt.Errorf("failed to deregister job in region %q: %w", region.Name, err)
+		}
+	}
+
 	return nil
 }
 
 // interpolateMultiregionFields interpolates a job for a specific region
 func (j *Job) interpolateMultiregionFields(args *structs.JobPlanRequest) error {
-	return nil
+	if !args.Job.IsMultiregion() || args.Job.Region != structs.GlobalRegion {
+		return nil
+	}
+
+	for _, region := range args.Job.Multiregion.Regions {
+		if region.Name == j.srv.Region() {
+			args.Job = interpolateMultiregionJob(args.Job, region)
+			return nil
+		}
+	}
+	return fmt.Errorf("multiregion job is not deployed to region %q", j.srv.Region())
+}
+
+// interpolateMultiregionJob returns a copy of the job with the parameters of
+// the region applied.
+func interpolateMultiregionJob(job *structs.Job, region *structs.MultiregionRegion) *structs.Job {
+	rj := job.Copy()
+	rj.Region = region.Name
+
+	if len(region.Datacenters) > 0 {
+		rj.Datacenters = slices.Clone(region.Datacenters)
+	}
+	if region.NodePool != "" {
+		rj.NodePool = region.NodePool
+	}
+	if len(region.Meta) > 0 {
+		if rj.Meta == nil {
+			rj.Meta = make(map[string]string, len(region.Meta))
+		}
+		maps.Copy(rj.Meta, region.Meta)
+	}
+
+	// The region's count only replaces task group counts of zero
+	if region.Count > 0 {
+		for _, tg := range rj.TaskGroups {
+			if tg.Count == 0 {
+				tg.Count = region.Count
+			}
+		}
+	}
+
+	return rj
 }
 
 // multiregionSpecChanged checks to see if the job spec has changed. If the job is multiregion,
@@ -367,6 +367,16 @@ func TestJobEndpoint_Register_NodePool(t *testing.T) {
 	err = msgpackrpc.CallWithCodec(codec, "NodePool.UpsertNodePools", poolReq, &poolResp)
 	must.NoError(t, err)
 
+	// Create test namespace bound to the test node pool.
+	nsBound := mock.Namespace()
+	nsBound.NodePoolConfiguration = &structs.NamespaceNodePoolConfiguration{
+		Default: pool.Name,
+		Allowed: []string{},
+	}
+	nsReq.Namespaces = []*structs.Namespace{nsBound}
+	err = msgpackrpc.CallWithCodec(codec, "Namespace.UpsertNamespaces", nsReq, &nsResp)
+	must.NoError(t, err)
+
 	testCases := []struct {
 		name         string
 		namespace    string
@@ -392,6 +402,18 @@ func TestJobEndpoint_Register_NodePool(t *testing.T) {
 			nodePool:     pool.Name,
 			expectedPool: pool.Name,
 		},
+		{
+			name:         "job without node pool uses namespace default node pool",
+			namespace:    nsBound.Name,
+			nodePool:     "",
+			expectedPool: pool.Name,
+		},
+		{
+			name:        "job can't use node pool not allowed by namespace",
+			namespace:   nsBound.Name,
+			nodePool:    structs.NodePoolDefault,
+			expectedErr: "does not allow jobs to use node pool",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -422,3 +444,114 @@ func TestJobEndpoint_Register_NodePool(t *testing.T) {
 		})
 	}
 }
+
+func TestJobEndpoint_Register_Multiregion(t *testing.T) {
+	ci.Parallel(t)
+
+	west, cleanupWest := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.Region = "west"
+	})
+	defer cleanupWest()
+
+	east, cleanupEast := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.Region = "east"
+	})
+	defer cleanupEast()
+
+	TestJoin(t, west, east)
+	testutil.WaitForLeader(t, west.RPC)
+	testutil.WaitForLeader(t, east.RPC)
+	codec := rpcClient(t, west)
+
+	job := mock.MultiregionJob()
+	job.TaskGroups[0].Count = 0
+
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "west",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	// Each region has a copy of the job interpolated for the region
+	westJob, err := west.State().JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.NotNil(t, westJob)
+	must.Eq(t, "west", westJob.Region)
+	must.Eq(t, []string{"west-1", "west-2"}, westJob.Datacenters)
+	must.Eq(t, 2, westJob.TaskGroups[0].Count)
+	must.Eq(t, "W", westJob.Meta["region_code"])
+
+	eastJob, err := east.State().JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.NotNil(t, eastJob)
+	must.Eq(t, "east", eastJob.Region)
+	must.Eq(t, []string{"east-1"}, eastJob.Datacenters)
+	must.Eq(t, 1, eastJob.TaskGroups[0].Count)
+	must.Eq(t, "E", eastJob.Meta["region_code"])
+
+	// With max_parallel = 1 only the first region's deployment is run, and
+	// the other region waits for it
+	westDeploy, err := west.State().LatestDeploymentByJobID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.NotNil(t, westDeploy)
+	must.True(t, westDeploy.IsMultiregion)
+	must.Eq(t, structs.DeploymentStatusRunning, westDeploy.Status)
+
+	eastDeploy, err := east.State().LatestDeploymentByJobID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.NotNil(t, eastDeploy)
+	must.Eq(t, structs.DeploymentStatusInitializing, eastDeploy.Status)
+
+	// Stopping the job globally stops it in every region
+	deregReq := &structs.JobDeregisterRequest{
+		JobID:  job.ID,
+		Global: true,
+		WriteRequest: structs.WriteRequest{
+			Region:    "west",
+			Namespace: job.Namespace,
+		},
+	}
+	var deregResp structs.JobDeregisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Deregister", deregReq, &deregResp))
+
+	for _, s := range []*Server{west, east} {
+		got, err := s.State().JobByID(nil, job.Namespace, job.ID)
+		must.NoError(t, err)
+		must.True(t, got.Stop, must.Sprintf("job not stopped in region %q", s.Region()))
+	}
+}
+
+func TestJobEndpoint_Register_Multiregion_UnknownRegion(t *testing.T) {
+	ci.Parallel(t)
+
+	s, cleanupS := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.Region = "west"
+	})
+	defer cleanupS()
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	// The job isn't registered in any region if one of its regions is unknown
+	job := mock.MultiregionJob()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "west",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err := msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	must.ErrorContains(t, err, `region "east" is not a known region`)
+
+	got, err := s.State().JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Nil(t, got)
+}
@@ -311,6 +311,10 @@ func groupConnectHook(job *structs.Job, g *structs.TaskGroup) error {
 				service.Connect.SidecarTask.MergeIntoTask(task)
 			}
 
+			if proxy := service.Connect.SidecarService.Proxy; proxy != nil && proxy.Envoy != nil {
+				connectSidecarEnvoyHook(service.Name, proxy.Envoy, task)
+			}
+
 			// Canonicalize task since this mutator runs after job canonicalization
 			task.Canonicalize(job, g)
 
@@ -518,6 +522,31 @@ func newConnectGatewayTask(prefix, service, cluster string, netHost, customizedT
 	}
 }
 
+// connectSidecarEnvoyHook applies the envoy block of a sidecar proxy that
+// isn't part of the proxy configuration registered in Consul. Its concurrency
+// replaces the connect.proxy_concurrency client meta in the args of the
+// sidecar task, and its tracer is named after the service by default.
+func connectSidecarEnvoyHook(service string, envoy *structs.ConsulEnvoyConfig, task *structs.Task) {
+	if t := envoy.Tracing; t != nil && t.ServiceName == "" {
+		t.ServiceName = service
+	}
+
+	if envoy.Concurrency <= 0 {
+		return
+	}
+	args, ok := task.Config["args"].([]interface{})
+	if !ok {
+		return
+	}
+	args = slices.Clone(args)
+	for i, arg := range args {
+		if arg == "${meta.connect.proxy_concurrency}" {
+			args[i] = strconv.Itoa(envoy.Concurrency)
+		}
+	}
+	task.Config["args"] = args
+}
+
 func newConnectSidecarTask(service, driver, cluster string) *structs.Task {
 
 	versionConstraint := connectSidecarVersionConstraint(cluster)
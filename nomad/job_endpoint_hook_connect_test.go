@@ -180,6 +180,50 @@ func TestJobEndpointConnect_groupConnectHook(t *testing.T) {
 	require.Exactly(t, tgExp, job.TaskGroups[0])
 }
 
+func TestJobEndpointConnect_groupConnectHook_Envoy(t *testing.T) {
+	ci.Parallel(t)
+
+	job := mock.Job()
+	job.TaskGroups[0] = &structs.TaskGroup{
+		Networks: structs.Networks{{
+			Mode: "bridge",
+		}},
+		Services: []*structs.Service{{
+			Name:      "backend",
+			PortLabel: "8080",
+			Connect: &structs.ConsulConnect{
+				SidecarService: &structs.ConsulSidecarService{
+					Proxy: &structs.ConsulProxy{
+						Envoy: &structs.ConsulEnvoyConfig{
+							Concurrency: 4,
+							Tracing: &structs.ConsulEnvoyTracing{
+								Provider: structs.EnvoyTracingZipkin,
+								Address:  "127.0.0.1:9411",
+							},
+						},
+					},
+				},
+			},
+		}},
+	}
+
+	must.NoError(t, groupConnectHook(job, job.TaskGroups[0]))
+	must.Len(t, 1, job.TaskGroups[0].Tasks)
+
+	// the concurrency replaces the client meta in the sidecar task args
+	args := job.TaskGroups[0].Tasks[0].Config["args"].([]interface{})
+	must.SliceContains(t, args, any("4"))
+	must.SliceNotContains(t, args, any("${meta.connect.proxy_concurrency}"))
+
+	// the tracer is named after the service
+	envoy := job.TaskGroups[0].Services[0].Connect.SidecarService.Proxy.Envoy
+	must.Eq(t, "backend", envoy.Tracing.ServiceName)
+
+	// the default sidecar task driver config is left untouched
+	must.SliceContains(t, connectSidecarDriverConfig()["args"].([]interface{}),
+		any("${meta.connect.proxy_concurrency}"))
+}
+
 func TestJobEndpointConnect_groupConnectHook_IngressGateway_BridgeNetwork(t *testing.T) {
 	ci.Parallel(t)
 
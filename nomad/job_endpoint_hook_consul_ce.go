@@ -8,23 +8,46 @@ package nomad
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
 func (h jobConsulHook) Validate(job *structs.Job) ([]error, error) {
 
+	ns, err := h.srv.State().NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	requiresToken := false
 
-	clusterNeedsToken := func(name string, identity *structs.WorkloadIdentity) bool {
+	// clusterNeedsToken returns true if the workload needs a Consul token
+	// submitted with the job. Legacy tokens are only derived from the default
+	// cluster, so other clusters must use workload identities.
+	clusterNeedsToken := func(name string, identity *structs.WorkloadIdentity) (bool, error) {
 		if identity != nil {
-			return false
+			return false, nil
 		}
 		config := h.srv.config.ConsulConfigs[name]
-		if config != nil {
-			return !*config.AllowUnauthenticated
+		if config == nil || config.AllowUnauthenticated == nil || *config.AllowUnauthenticated {
+			return false, nil
+		}
+		if name != structs.ConsulDefaultCluster {
+			return false, fmt.Errorf("non-default Consul cluster %q requires a workload identity", name)
+		}
+		return true, nil
+	}
+	checkCluster := func(name string, identity *structs.WorkloadIdentity) error {
+		if err := h.validateCluster(ns, name); err != nil {
+			return err
+		}
+		needsToken, err := clusterNeedsToken(name, identity)
+		if err != nil {
+			return err
 		}
-		return false
+		requiresToken = requiresToken || needsToken
+		return nil
 	}
 
 	for _, group := range job.TaskGroups {
@@ -33,29 +56,25 @@ func (h jobConsulHook) Validate(job *structs.Job) ([]error, error) {
 
 		if group.Consul != nil {
 			groupPartition = group.Consul.Partition
-			if err := h.validateCluster(group.Consul.Cluster); err != nil {
+			if err := h.validateCluster(ns, group.Consul.Cluster); err != nil {
 				return nil, err
 			}
 		}
 
 		for _, service := range group.Services {
 			if service.Provider == structs.ServiceProviderConsul {
-				if err := h.validateCluster(service.Cluster); err != nil {
+				if err := checkCluster(service.Cluster, service.Identity); err != nil {
 					return nil, err
 				}
-				requiresToken = clusterNeedsToken(
-					service.Cluster, service.Identity) || requiresToken
 			}
 		}
 
 		for _, task := range group.Tasks {
 			for _, service := range task.Services {
 				if service.Provider == structs.ServiceProviderConsul {
-					if err := h.validateCluster(service.Cluster); err != nil {
+					if err := checkCluster(service.Cluster, service.Identity); err != nil {
 						return nil, err
 					}
-					requiresToken = clusterNeedsToken(
-						service.Cluster, service.Identity) || requiresToken
 				}
 			}
 
@@ -65,9 +84,6 @@ func (h jobConsulHook) Validate(job *structs.Job) ([]error, error) {
 					return nil, err
 				}
 
-				if err := h.validateCluster(task.Consul.Cluster); err != nil {
-					return nil, err
-				}
 				var clusterIdentity *structs.WorkloadIdentity
 				for _, identity := range task.Identities {
 					if identity.Name == "consul_"+task.Consul.Cluster {
@@ -75,8 +91,9 @@ func (h jobConsulHook) Validate(job *structs.Job) ([]error, error) {
 						break
 					}
 				}
-				requiresToken = clusterNeedsToken(
-					task.Consul.Cluster, clusterIdentity) || requiresToken
+				if err := checkCluster(task.Consul.Cluster, clusterIdentity); err != nil {
+					return nil, err
+				}
 			}
 		}
 	}
@@ -89,15 +106,24 @@ func (h jobConsulHook) Validate(job *structs.Job) ([]error, error) {
 	return nil, nil
 }
 
-func (h jobConsulHook) validateCluster(name string) error {
-	if name != structs.ConsulDefaultCluster {
-		return errors.New("non-default Consul cluster requires Nomad Enterprise")
+// validateCluster returns an error if the Consul cluster is not configured on
+// the servers or not allowed by the job's namespace.
+func (h jobConsulHook) validateCluster(ns *structs.Namespace, name string) error {
+	if _, ok := h.srv.config.ConsulConfigs[name]; !ok {
+		return fmt.Errorf("Consul cluster %q not configured but used in the job", name)
+	}
+	if !ns.AllowsConsulCluster(name) {
+		return fmt.Errorf("namespace %q does not allow jobs to use Consul cluster %q", ns.Name, name)
 	}
 	return nil
 }
 
 // Mutate ensures that the job's Consul cluster has been configured to be the
-// default Consul cluster if unset
+// default Consul cluster of the job's namespace if unset
 func (j jobConsulHook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
-	return j.mutateImpl(job, structs.ConsulDefaultCluster), nil, nil
+	ns, err := j.srv.State().NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	return j.mutateImpl(job, ns.DefaultConsulCluster()), nil, nil
 }
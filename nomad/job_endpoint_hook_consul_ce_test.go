@@ -10,8 +10,10 @@ import (
 	"testing"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/nomad/testutil"
 	"github.com/shoenig/test"
 	"github.com/shoenig/test/must"
@@ -22,6 +24,14 @@ func TestJobEndpointHook_ConsulCE(t *testing.T) {
 
 	srv, cleanup := TestServer(t, func(c *Config) {
 		c.NumSchedulers = 0
+		c.ConsulConfigs["infra"] = &config.ConsulConfig{
+			Name:                 "infra",
+			AllowUnauthenticated: pointer.Of(true),
+		}
+		c.ConsulConfigs["nondefault"] = &config.ConsulConfig{
+			Name:                 "nondefault",
+			AllowUnauthenticated: pointer.Of(false),
+		}
 	})
 	t.Cleanup(cleanup)
 	testutil.WaitForLeader(t, srv.RPC)
@@ -58,6 +68,46 @@ func TestJobEndpointHook_ConsulCE(t *testing.T) {
 			Operand: "=",
 		})
 
+	// Non-default clusters require a workload identity.
 	_, err = hook.Validate(job)
-	must.EqError(t, err, "non-default Consul cluster requires Nomad Enterprise")
+	must.EqError(t, err, `non-default Consul cluster "nondefault" requires a workload identity`)
+
+	job.TaskGroups[0].Tasks[0].Services[0].Identity = &structs.WorkloadIdentity{
+		Name:     "consul-service_web",
+		Audience: []string{"consul.io"},
+	}
+	_, err = hook.Validate(job)
+	must.NoError(t, err)
+
+	// Clusters must be configured on the servers.
+	job.TaskGroups[0].Services[1].Cluster = "unknown"
+	_, err = hook.Validate(job)
+	must.EqError(t, err, `Consul cluster "unknown" not configured but used in the job`)
+
+	// Namespaces can restrict the clusters used by their jobs and set the
+	// default cluster of their jobs.
+	ns := mock.Namespace()
+	ns.Name = "payments"
+	ns.ConsulConfiguration = &structs.NamespaceConsulConfiguration{
+		Default: "infra",
+		Allowed: []string{},
+	}
+	must.NoError(t, srv.State().UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	job = mock.Job()
+	job.Namespace = ns.Name
+	job.TaskGroups[0].Services = []*structs.Service{{
+		Name:     "api",
+		Provider: structs.ServiceProviderConsul,
+	}}
+	job.TaskGroups[0].Tasks[0].Services = nil
+	_, _, err = hook.Mutate(job)
+	must.NoError(t, err)
+	must.Eq(t, "infra", job.TaskGroups[0].Services[0].Cluster)
+	_, err = hook.Validate(job)
+	must.NoError(t, err)
+
+	job.TaskGroups[0].Services[0].Cluster = structs.ConsulDefaultCluster
+	_, err = hook.Validate(job)
+	must.EqError(t, err, `namespace "payments" does not allow jobs to use Consul cluster "default"`)
 }
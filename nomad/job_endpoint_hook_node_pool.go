@@ -30,5 +30,13 @@ func (j jobNodePoolValidatingHook) Validate(job *structs.Job) ([]error, error) {
 		return nil, fmt.Errorf("job %q is in nonexistent node pool %q", job.ID, poolName)
 	}
 
+	ns, err := j.srv.State().NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if !ns.AllowsNodePool(poolName) {
+		return nil, fmt.Errorf("namespace %q does not allow jobs to use node pool %q", job.Namespace, poolName)
+	}
+
 	return j.enterpriseValidation(job, pool)
 }
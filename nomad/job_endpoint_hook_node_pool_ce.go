@@ -14,7 +14,8 @@ func (j jobNodePoolValidatingHook) enterpriseValidation(_ *structs.Job, _ *struc
 	return nil, nil
 }
 
-// jobNodePoolMutatingHook mutates the job on Nomad Enterprise only.
+// jobNodePoolMutatingHook is an admission hook that sets the job's node pool
+// to the default node pool of its namespace if the job doesn't specify one.
 type jobNodePoolMutatingHook struct {
 	srv *Server
 }
@@ -25,7 +26,11 @@ func (c jobNodePoolMutatingHook) Name() string {
 
 func (c jobNodePoolMutatingHook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
 	if job.NodePool == "" {
-		job.NodePool = structs.NodePoolDefault
+		ns, err := c.srv.State().NamespaceByName(nil, job.Namespace)
+		if err != nil {
+			return nil, nil, err
+		}
+		job.NodePool = ns.DefaultNodePool()
 	}
 
 	return job, nil, nil
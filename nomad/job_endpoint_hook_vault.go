@@ -34,11 +34,14 @@ func (h jobVaultHook) Validate(job *structs.Job) ([]error, error) {
 	for _, tg := range vaultBlocks {
 		for _, vaultBlock := range tg {
 			vconf := h.srv.config.VaultConfigs[vaultBlock.Cluster]
-			if !vconf.IsEnabled() {
+			if vconf == nil || !vconf.IsEnabled() {
 				return nil, fmt.Errorf("Vault %q not enabled but used in the job",
 					vaultBlock.Cluster)
 			}
-			if vconf.DefaultIdentity == nil && !vconf.AllowsUnauthenticated() {
+			// Legacy tokens are only derived from the default cluster, and
+			// other clusters are validated to use workload identities.
+			if vaultBlock.Cluster == structs.VaultDefaultCluster &&
+				vconf.DefaultIdentity == nil && !vconf.AllowsUnauthenticated() {
 				requiresToken = true
 			}
 		}
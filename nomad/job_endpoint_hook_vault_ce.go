@@ -6,7 +6,6 @@
 package nomad
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 
@@ -28,11 +27,35 @@ func (jobVaultHook) validateNamespaces(
 	return nil
 }
 
-func (h jobVaultHook) validateClustersForNamespace(_ *structs.Job, blocks map[string]map[string]*structs.Vault) error {
-	for _, tg := range blocks {
-		for _, vault := range tg {
-			if vault.Cluster != "default" {
-				return errors.New("non-default Vault cluster requires Nomad Enterprise")
+// validateClustersForNamespace returns an error if the job uses Vault clusters
+// that are not allowed by its namespace. Tokens for non-default clusters can
+// only be derived with workload identities, since the servers only derive
+// legacy tokens from the default cluster.
+func (h jobVaultHook) validateClustersForNamespace(job *structs.Job, blocks map[string]map[string]*structs.Vault) error {
+	ns, err := h.srv.State().NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return err
+	}
+	if ns == nil {
+		return fmt.Errorf("job %q is in nonexistent namespace %q", job.ID, job.Namespace)
+	}
+
+	for tgName, tg := range blocks {
+		for taskName, vault := range tg {
+			if !ns.AllowsVaultCluster(vault.Cluster) {
+				return fmt.Errorf("namespace %q does not allow jobs to use Vault cluster %q",
+					ns.Name, vault.Cluster)
+			}
+			if vault.Cluster == structs.VaultDefaultCluster {
+				continue
+			}
+			if h.srv.config.VaultIdentityConfig(vault.Cluster) != nil {
+				continue
+			}
+			task := job.LookupTaskGroup(tgName).LookupTask(taskName)
+			if task.GetIdentity(vault.IdentityName()) == nil {
+				return fmt.Errorf("task %q uses non-default Vault cluster %q which requires a workload identity",
+					taskName, vault.Cluster)
 			}
 		}
 	}
@@ -40,13 +63,21 @@ func (h jobVaultHook) validateClustersForNamespace(_ *structs.Job, blocks map[st
 	return nil
 }
 
-func (j jobVaultHook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+func (h jobVaultHook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	var ns *structs.Namespace
 	for _, tg := range job.TaskGroups {
 		for _, task := range tg.Tasks {
 			if task.Vault == nil || task.Vault.Cluster != "" {
 				continue
 			}
-			task.Vault.Cluster = "default"
+			if ns == nil {
+				var err error
+				ns, err = h.srv.State().NamespaceByName(nil, job.Namespace)
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+			task.Vault.Cluster = ns.DefaultVaultCluster()
 		}
 	}
 
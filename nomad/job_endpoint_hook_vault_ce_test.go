@@ -29,6 +29,18 @@ func TestJobEndpointHook_VaultCE(t *testing.T) {
 			Name:     "vault_default",
 			Audience: []string{"vault.io"},
 		}
+		c.VaultConfigs["infra"] = &config.VaultConfig{
+			Name:    "infra",
+			Enabled: pointer.Of(true),
+			DefaultIdentity: &config.WorkloadIdentityConfig{
+				Name:     "vault_infra",
+				Audience: []string{"vault.io"},
+			},
+		}
+		c.VaultConfigs["pci"] = &config.VaultConfig{
+			Name:    "pci",
+			Enabled: pointer.Of(true),
+		}
 	})
 	t.Cleanup(cleanup)
 	testutil.WaitForLeader(t, srv.RPC)
@@ -47,14 +59,49 @@ func TestJobEndpointHook_VaultCE(t *testing.T) {
 	must.Eq(t, structs.VaultDefaultCluster, job.TaskGroups[0].Tasks[0].Vault.Cluster)
 	must.Eq(t, "infra", job.TaskGroups[0].Tasks[1].Vault.Cluster)
 
-	// skipping over the rest of Validate b/c it requires an actual
-	// Vault cluster
-	err = hook.validateClustersForNamespace(job, job.Vault())
-	must.EqError(t, err, "non-default Vault cluster requires Nomad Enterprise")
-
-	job = mock.Job()
-	job.TaskGroups[0].Tasks[0].Vault = &structs.Vault{Cluster: structs.VaultDefaultCluster}
 	warnings, err := hook.Validate(job)
 	must.Len(t, 0, warnings)
 	must.NoError(t, err)
+
+	// Non-default clusters require a workload identity.
+	job.TaskGroups[0].Tasks[1].Vault.Cluster = "pci"
+	_, err = hook.Validate(job)
+	must.EqError(t, err, `task "web2" uses non-default Vault cluster "pci" which requires a workload identity`)
+
+	job.TaskGroups[0].Tasks[1].Identities = []*structs.WorkloadIdentity{
+		{Name: "vault_pci", Audience: []string{"vault.io"}},
+	}
+	_, err = hook.Validate(job)
+	must.NoError(t, err)
+
+	// Unknown clusters are rejected.
+	job.TaskGroups[0].Tasks[1].Vault.Cluster = "unknown"
+	_, err = hook.Validate(job)
+	must.EqError(t, err, `Vault "unknown" not enabled but used in the job`)
+
+	// Namespaces can restrict the clusters used by their jobs and set the
+	// default cluster of their jobs.
+	ns := mock.Namespace()
+	ns.Name = "payments"
+	ns.VaultConfiguration = &structs.NamespaceVaultConfiguration{
+		Default: "pci",
+		Allowed: []string{},
+	}
+	must.NoError(t, srv.State().UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	job = mock.Job()
+	job.Namespace = ns.Name
+	job.TaskGroups[0].Tasks[0].Vault = &structs.Vault{}
+	job.TaskGroups[0].Tasks[0].Identities = []*structs.WorkloadIdentity{
+		{Name: "vault_pci", Audience: []string{"vault.io"}},
+	}
+	_, _, err = hook.Mutate(job)
+	must.NoError(t, err)
+	must.Eq(t, "pci", job.TaskGroups[0].Tasks[0].Vault.Cluster)
+	_, err = hook.Validate(job)
+	must.NoError(t, err)
+
+	job.TaskGroups[0].Tasks[0].Vault.Cluster = "infra"
+	_, err = hook.Validate(job)
+	must.EqError(t, err, `namespace "payments" does not allow jobs to use Vault cluster "infra"`)
 }
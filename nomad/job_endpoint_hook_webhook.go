@@ -0,0 +1,275 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/hashicorp/go-cleanhttp"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+// maxAdmissionWebhookResponseSize limits how much of a webhook's response is
+// read, as mutating webhooks return the whole job.
+const maxAdmissionWebhookResponseSize = 16 * 1024 * 1024
+
+// jobAdmissionWebhook is an admission controller that calls out to an
+// operator configured HTTP endpoint, which can mutate or reject the job.
+// Mutating webhooks implement jobMutator and validating webhooks implement
+// jobValidator.
+type jobAdmissionWebhook struct {
+	config *config.AdmissionWebhookConfig
+	client *http.Client
+	logger log.Logger
+}
+
+// newJobAdmissionWebhook returns the admission controller for the webhook
+// configuration.
+func newJobAdmissionWebhook(conf *config.AdmissionWebhookConfig, logger log.Logger) (*jobAdmissionWebhook, error) {
+	transport := cleanhttp.DefaultPooledTransport()
+
+	if conf.CAFile != "" || conf.TLSSkipVerify {
+		tlsConfig := &tls.Config{
+			InsecureSkipVerify: conf.TLSSkipVerify,
+		}
+		if conf.CAFile != "" {
+			pem, err := os.ReadFile(conf.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file: %v", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("failed to parse CA file %q", conf.CAFile)
+			}
+			tlsConfig.RootCAs = pool
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &jobAdmissionWebhook{
+		config: conf,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   conf.Timeout,
+		},
+		logger: logger.Named("admission_webhook").With("webhook", conf.Name),
+	}, nil
+}
+
+func (h *jobAdmissionWebhook) Name() string {
+	return "admission-webhook-" + h.config.Name
+}
+
+// Mutate calls a mutating webhook and returns the job it responds with.
+// Every mutation is logged, so that changes made to jobs are auditable.
+func (h *jobAdmissionWebhook) Mutate(job *structs.Job) (*structs.Job, []error, error) {
+	if !h.config.AppliesTo(job.Namespace) {
+		return job, nil, nil
+	}
+
+	resp, err := h.call(job)
+	if err != nil {
+		return job, nil, h.failure(job, err)
+	}
+
+	warnings := h.warnings(resp)
+	if !resp.Allowed {
+		return nil, warnings, h.rejected(job, resp)
+	}
+	if resp.Job == nil {
+		return job, warnings, nil
+	}
+
+	out := resp.Job
+	if out.ID != job.ID || out.Namespace != job.Namespace || out.Region != job.Region {
+		return job, warnings, h.failure(job,
+			errors.New("webhook may not change the job ID, namespace, or region"))
+	}
+	out.Canonicalize()
+
+	h.auditMutation(job, out)
+	return out, warnings, nil
+}
+
+// Validate calls a validating webhook.
+func (h *jobAdmissionWebhook) Validate(job *structs.Job) ([]error, error) {
+	if !h.config.AppliesTo(job.Namespace) {
+		return nil, nil
+	}
+
+	resp, err := h.call(job)
+	if err != nil {
+		return nil, h.failure(job, err)
+	}
+
+	warnings := h.warnings(resp)
+	if !resp.Allowed {
+		return warnings, h.rejected(job, resp)
+	}
+	return warnings, nil
+}
+
+// call posts the job to the webhook and decodes its response.
+func (h *jobAdmissionWebhook) call(job *structs.Job) (*structs.AdmissionWebhookResponse, error) {
+	body, err := json.Marshal(&structs.AdmissionWebhookRequest{
+		Webhook: h.config.Name,
+		Type:    h.config.Type,
+		Job:     job,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, h.config.Address, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	httpResp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response code %d", httpResp.StatusCode)
+	}
+
+	var resp structs.AdmissionWebhookResponse
+	dec := json.NewDecoder(io.LimitReader(httpResp.Body, maxAdmissionWebhookResponseSize))
+	if err := dec.Decode(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	return &resp, nil
+}
+
+// failure applies the failure policy of the webhook when it couldn't be
+// called, returning the error if the job should be rejected.
+func (h *jobAdmissionWebhook) failure(job *structs.Job, err error) error {
+	if h.config.FailurePolicy == config.AdmissionWebhookFailurePolicyIgnore {
+		h.logger.Warn("admission webhook failed, admitting job",
+			"namespace", job.Namespace, "job_id", job.ID, "error", err)
+		return nil
+	}
+	h.logger.Error("admission webhook failed, rejecting job",
+		"namespace", job.Namespace, "job_id", job.ID, "error", err)
+	return fmt.Errorf("admission webhook %q failed: %v", h.config.Name, err)
+}
+
+func (h *jobAdmissionWebhook) rejected(job *structs.Job, resp *structs.AdmissionWebhookResponse) error {
+	h.logger.Info("job rejected by admission webhook",
+		"namespace", job.Namespace, "job_id", job.ID, "reason", resp.Reason)
+
+	if resp.Reason == "" {
+		return fmt.Errorf("job rejected by admission webhook %q", h.config.Name)
+	}
+	return fmt.Errorf("job rejected by admission webhook %q: %s", h.config.Name, resp.Reason)
+}
+
+func (h *jobAdmissionWebhook) warnings(resp *structs.AdmissionWebhookResponse) []error {
+	var warnings []error
+	for _, w := range resp.Warnings {
+		warnings = append(warnings, fmt.Errorf("admission webhook %q: %s", h.config.Name, w))
+	}
+	return warnings
+}
+
+// auditMutation logs the fields of the job changed by the webhook.
+func (h *jobAdmissionWebhook) auditMutation(orig, mutated *structs.Job) {
+	diff, err := orig.Diff(mutated, false)
+	if err != nil {
+		h.logger.Warn("failed to diff job mutated by admission webhook",
+			"namespace", orig.Namespace, "job_id", orig.ID, "error", err)
+		return
+	}
+
+	changes := jobDiffChanges(diff)
+	if len(changes) == 0 {
+		return
+	}
+	h.logger.Info("job mutated by admission webhook",
+		"namespace", orig.Namespace, "job_id", orig.ID, "changes", changes)
+}
+
+// jobDiffChanges flattens a job diff into a list of the changed fields and
+// objects, such as "TaskGroup[web].Task[server].Env[FOO] (Added)".
+func jobDiffChanges(diff *structs.JobDiff) []string {
+	var changes []string
+	changes = appendDiffChanges(changes, "Job", diff.Fields, diff.Objects)
+	for _, tg := range diff.TaskGroups {
+		tgPath := fmt.Sprintf("TaskGroup[%s]", tg.Name)
+		if tg.Type == structs.DiffTypeAdded || tg.Type == structs.DiffTypeDeleted {
+			changes = append(changes, fmt.Sprintf("%s (%s)", tgPath, tg.Type))
+			continue
+		}
+		changes = appendDiffChanges(changes, tgPath, tg.Fields, tg.Objects)
+		for _, task := range tg.Tasks {
+			taskPath := fmt.Sprintf("%s.Task[%s]", tgPath, task.Name)
+			if task.Type == structs.DiffTypeAdded || task.Type == structs.DiffTypeDeleted {
+				changes = append(changes, fmt.Sprintf("%s (%s)", taskPath, task.Type))
+				continue
+			}
+			changes = appendDiffChanges(changes, taskPath, task.Fields, task.Objects)
+		}
+	}
+	return changes
+}
+
+func appendDiffChanges(changes []string, path string, fields []*structs.FieldDiff, objects []*structs.ObjectDiff) []string {
+	for _, f := range fields {
+		if f.Type == structs.DiffTypeNone {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s.%s (%s)", path, f.Name, f.Type))
+	}
+	for _, o := range objects {
+		switch o.Type {
+		case structs.DiffTypeNone:
+			continue
+		case structs.DiffTypeAdded, structs.DiffTypeDeleted:
+			changes = append(changes, fmt.Sprintf("%s.%s (%s)", path, o.Name, o.Type))
+			continue
+		}
+		changes = appendDiffChanges(changes, path+"."+o.Name, o.Fields, o.Objects)
+	}
+	return changes
+}
+
+// admissionWebhookMutators returns the admission controllers of the
+// configured mutating webhooks, in the order they are configured.
+func admissionWebhookMutators(webhooks []*jobAdmissionWebhook) []jobMutator {
+	var mutators []jobMutator
+	for _, webhook := range webhooks {
+		if webhook.config.Type == config.AdmissionWebhookTypeMutating {
+			mutators = append(mutators, webhook)
+		}
+	}
+	return mutators
+}
+
+// admissionWebhookValidators returns the admission controllers of the
+// configured validating webhooks, in the order they are configured.
+func admissionWebhookValidators(webhooks []*jobAdmissionWebhook) []jobValidator {
+	var validators []jobValidator
+	for _, webhook := range webhooks {
+		if webhook.config.Type == config.AdmissionWebhookTypeValidating {
+			validators = append(validators, webhook)
+		}
+	}
+	return validators
+}
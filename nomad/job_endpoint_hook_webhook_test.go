@@ -0,0 +1,277 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+// testAdmissionWebhookServer starts an HTTP server that decodes admission
+// webhook requests and responds with the result of fn.
+func testAdmissionWebhookServer(t *testing.T, fn func(*structs.AdmissionWebhookRequest) *structs.AdmissionWebhookResponse) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var req structs.AdmissionWebhookRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(fn(&req))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testAdmissionWebhook(t *testing.T, conf *config.AdmissionWebhookConfig) *jobAdmissionWebhook {
+	t.Helper()
+
+	conf.Name = "test"
+	conf.Headers = map[string]string{"X-Token": "secret"}
+	conf.Canonicalize()
+	must.NoError(t, conf.Validate())
+
+	webhook, err := newJobAdmissionWebhook(conf, testlog.HCLogger(t))
+	must.NoError(t, err)
+	return webhook
+}
+
+func TestJobAdmissionWebhook_Mutate(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := testAdmissionWebhookServer(t, func(req *structs.AdmissionWebhookRequest) *structs.AdmissionWebhookResponse {
+		job := req.Job
+		if job.Meta["reject"] != "" {
+			return &structs.AdmissionWebhookResponse{Reason: job.Meta["reject"]}
+		}
+		if job.Meta["unchanged"] != "" {
+			return &structs.AdmissionWebhookResponse{Allowed: true}
+		}
+		job.Meta["team"] = "platform"
+		job.TaskGroups[0].Tasks[0].Env["ENVIRONMENT"] = "prod"
+		return &structs.AdmissionWebhookResponse{
+			Allowed:  true,
+			Warnings: []string{"team meta was set"},
+			Job:      job,
+		}
+	})
+
+	webhook := testAdmissionWebhook(t, &config.AdmissionWebhookConfig{
+		Type:    config.AdmissionWebhookTypeMutating,
+		Address: srv.URL,
+	})
+	must.Eq(t, "admission-webhook-test", webhook.Name())
+
+	t.Run("mutated", func(t *testing.T) {
+		// The job is canonicalized before admission webhooks are called
+		job := mock.Job()
+		job.Canonicalize()
+		out, warnings, err := webhook.Mutate(job)
+		must.NoError(t, err)
+		must.Eq(t, "platform", out.Meta["team"])
+		must.Eq(t, "prod", out.TaskGroups[0].Tasks[0].Env["ENVIRONMENT"])
+		must.Len(t, 1, warnings)
+		must.EqError(t, warnings[0], `admission webhook "test": team meta was set`)
+
+		diff, err := job.Diff(out, false)
+		must.NoError(t, err)
+		must.Eq(t, []string{
+			"Job.Meta[team] (Added)",
+			"TaskGroup[web].Task[web].Env[ENVIRONMENT] (Added)",
+		}, jobDiffChanges(diff))
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		job := mock.Job()
+		job.Meta["unchanged"] = "true"
+		out, warnings, err := webhook.Mutate(job)
+		must.NoError(t, err)
+		must.Nil(t, warnings)
+		must.Eq(t, job, out)
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		job := mock.Job()
+		job.Meta["reject"] = "missing owner"
+		_, _, err := webhook.Mutate(job)
+		must.EqError(t, err, `job rejected by admission webhook "test": missing owner`)
+	})
+
+	t.Run("other namespace", func(t *testing.T) {
+		webhook := testAdmissionWebhook(t, &config.AdmissionWebhookConfig{
+			Type:       config.AdmissionWebhookTypeMutating,
+			Address:    srv.URL,
+			Namespaces: []string{"prod"},
+		})
+		job := mock.Job()
+		out, _, err := webhook.Mutate(job)
+		must.NoError(t, err)
+		must.MapNotContainsKey(t, out.Meta, "team")
+	})
+}
+
+func TestJobAdmissionWebhook_Mutate_ChangedID(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := testAdmissionWebhookServer(t, func(req *structs.AdmissionWebhookRequest) *structs.AdmissionWebhookResponse {
+		req.Job.ID = "other"
+		return &structs.AdmissionWebhookResponse{Allowed: true, Job: req.Job}
+	})
+
+	webhook := testAdmissionWebhook(t, &config.AdmissionWebhookConfig{
+		Type:    config.AdmissionWebhookTypeMutating,
+		Address: srv.URL,
+	})
+
+	_, _, err := webhook.Mutate(mock.Job())
+	must.ErrorContains(t, err, "webhook may not change the job ID, namespace, or region")
+}
+
+func TestJobAdmissionWebhook_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := testAdmissionWebhookServer(t, func(req *structs.AdmissionWebhookRequest) *structs.AdmissionWebhookResponse {
+		if req.Type != config.AdmissionWebhookTypeValidating {
+			return &structs.AdmissionWebhookResponse{Reason: "wrong type"}
+		}
+		if req.Job.Priority > 80 {
+			return &structs.AdmissionWebhookResponse{Reason: "priority too high"}
+		}
+		return &structs.AdmissionWebhookResponse{Allowed: true, Warnings: []string{"ok"}}
+	})
+
+	webhook := testAdmissionWebhook(t, &config.AdmissionWebhookConfig{
+		Type:    config.AdmissionWebhookTypeValidating,
+		Address: srv.URL,
+	})
+
+	job := mock.Job()
+	warnings, err := webhook.Validate(job)
+	must.NoError(t, err)
+	must.Len(t, 1, warnings)
+
+	job.Priority = 90
+	_, err = webhook.Validate(job)
+	must.EqError(t, err, `job rejected by admission webhook "test": priority too high`)
+}
+
+func TestJobAdmissionWebhook_FailurePolicy(t *testing.T) {
+	ci.Parallel(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Run("fail", func(t *testing.T) {
+		webhook := testAdmissionWebhook(t, &config.AdmissionWebhookConfig{
+			Type:    config.AdmissionWebhookTypeValidating,
+			Address: srv.URL,
+			Timeout: 50 * time.Millisecond,
+		})
+		_, err := webhook.Validate(mock.Job())
+		must.ErrorContains(t, err, `admission webhook "test" failed`)
+	})
+
+	t.Run("ignore", func(t *testing.T) {
+		webhook := testAdmissionWebhook(t, &config.AdmissionWebhookConfig{
+			Type:          config.AdmissionWebhookTypeMutating,
+			Address:       srv.URL,
+			Timeout:       50 * time.Millisecond,
+			FailurePolicy: config.AdmissionWebhookFailurePolicyIgnore,
+		})
+		job := mock.Job()
+		out, _, err := webhook.Mutate(job)
+		must.NoError(t, err)
+		must.Eq(t, job, out)
+	})
+}
+
+func TestJobEndpoint_Register_AdmissionWebhooks(t *testing.T) {
+	ci.Parallel(t)
+
+	mutator := testAdmissionWebhookServer(t, func(req *structs.AdmissionWebhookRequest) *structs.AdmissionWebhookResponse {
+		req.Job.Meta["team"] = "platform"
+		return &structs.AdmissionWebhookResponse{Allowed: true, Job: req.Job}
+	})
+	validator := testAdmissionWebhookServer(t, func(req *structs.AdmissionWebhookRequest) *structs.AdmissionWebhookResponse {
+		// The validator must view the mutated job
+		if req.Job.Meta["team"] != "platform" {
+			return &structs.AdmissionWebhookResponse{Reason: "missing team"}
+		}
+		if req.Job.Meta["deny"] != "" {
+			return &structs.AdmissionWebhookResponse{Reason: "denied"}
+		}
+		return &structs.AdmissionWebhookResponse{Allowed: true}
+	})
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.AdmissionWebhooks = []*config.AdmissionWebhookConfig{
+			{
+				Name:    "team",
+				Type:    config.AdmissionWebhookTypeMutating,
+				Address: mutator.URL,
+				Timeout: 5 * time.Second,
+				Headers: map[string]string{"X-Token": "secret"},
+			},
+			{
+				Name:    "deny",
+				Type:    config.AdmissionWebhookTypeValidating,
+				Address: validator.URL,
+				Timeout: 5 * time.Second,
+				Headers: map[string]string{"X-Token": "secret"},
+			},
+		}
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.NotNil(t, out)
+	must.Eq(t, "platform", out.Meta["team"])
+
+	// A job rejected by the validating webhook is not registered
+	job = mock.Job()
+	job.Meta["deny"] = "true"
+	req.Job = job
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	must.ErrorContains(t, err, `job rejected by admission webhook "deny": denied`)
+
+	out, err = s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Nil(t, out)
+}
@@ -102,7 +102,7 @@ func (j *Job) admissionControllers(job *structs.Job) (out *structs.Job, warnings
 		return nil, nil, err
 	}
 
-	validateWarnings, err := j.admissionValidators(job)
+	validateWarnings, err := j.admissionValidators(out)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -383,6 +383,12 @@ func (v *jobValidate) Validate(job *structs.Job) (warnings []error, err error) {
 		multierror.Append(validationErrors, fmt.Errorf("job priority must be between [%d, %d]", structs.JobMinPriority, v.srv.config.JobMaxPriority))
 	}
 
+	if job.Notify != nil && job.Notify.Webhook != "" {
+		if err := job.Notify.ValidateWebhookHost(v.srv.config.JobNotifyAllowedHosts); err != nil {
+			multierror.Append(validationErrors, err)
+		}
+	}
+
 	okForIdentity := v.isEligibleForMultiIdentity()
 
 	for _, tg := range job.TaskGroups {
@@ -528,6 +534,12 @@ func (j *Job) submissionController(args *structs.JobRegisterRequest) error {
 	if args.Submission == nil {
 		return nil
 	}
+	// discard the submission of jobs with sealed values, because the source
+	// may contain the cleartext of the values
+	if args.Job.HasSealedValues() {
+		args.Submission = nil
+		return fmt.Errorf("job source of a job with sealed values will be discarded")
+	}
 	maxSize := j.srv.GetConfig().JobMaxSourceSize
 	submission := args.Submission
 	// discard the submission if the source + variables is larger than the maximum
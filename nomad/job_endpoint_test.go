@@ -1412,6 +1412,62 @@ func TestJobEndpoint_Register_Dispatched(t *testing.T) {
 	require.Contains(err.Error(), "job can't be submitted with 'Dispatched'")
 }
 
+func TestJobEndpoint_Register_IdempotencyToken(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	register := func(job *structs.Job, token string) *structs.JobRegisterResponse {
+		req := &structs.JobRegisterRequest{
+			Job: job.Copy(),
+			WriteRequest: structs.WriteRequest{
+				Region:           "global",
+				Namespace:        job.Namespace,
+				IdempotencyToken: token,
+			},
+		}
+		var resp structs.JobRegisterResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp))
+		return &resp
+	}
+
+	job := mock.Job()
+	first := register(job, "first")
+	must.NotEq(t, "", first.EvalID)
+
+	// Update the job with another token
+	job.TaskGroups[0].Count++
+	second := register(job, "second")
+	must.Greater(t, first.JobModifyIndex, second.JobModifyIndex)
+
+	// Retrying the first registration returns it without reverting the job
+	job.TaskGroups[0].Count--
+	retry := register(job, "first")
+	must.Eq(t, first.JobModifyIndex, retry.JobModifyIndex)
+	must.Eq(t, first.EvalID, retry.EvalID)
+	must.Eq(t, first.EvalCreateIndex, retry.EvalCreateIndex)
+
+	out, err := s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, 1, out.Version)
+	must.Eq(t, "second", out.RegisterIdempotencyToken)
+	must.Eq(t, second.JobModifyIndex, out.JobModifyIndex)
+
+	// Registering without a token always updates the job
+	third := register(job, "")
+	must.Greater(t, second.JobModifyIndex, third.JobModifyIndex)
+
+	out, err = s1.fsm.State().JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, 2, out.Version)
+	must.Eq(t, "", out.RegisterIdempotencyToken)
+}
+
 func TestJobEndpoint_Register_EnforceIndex(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4880,6 +4936,68 @@ func TestJobEndpoint_GetJobVersions_Diff(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_GetJobVersionDiff(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	reg := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+
+	// Only change the command of the task
+	job.TaskGroups[0].Tasks[0].Config["command"] = "/bin/other"
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+
+	job.TaskGroups[0].Count = 5
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Register", reg, &resp))
+
+	get := &structs.JobVersionDiffRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+
+	// By default the latest version is diffed with its predecessor
+	var diffResp structs.JobVersionDiffResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobVersionDiff", get, &diffResp))
+	must.Eq(t, resp.JobModifyIndex, diffResp.Index)
+	must.NotNil(t, diffResp.Diff)
+	must.Eq(t, 1, diffResp.Summary.FromVersion)
+	must.Eq(t, 2, diffResp.Summary.ToVersion)
+	must.Eq(t, []string{structs.JobDiffCategoryCount}, diffResp.Summary.Categories)
+
+	// Diff any two versions
+	get.FromVersion = pointer.Of(uint64(0))
+	get.ToVersion = pointer.Of(uint64(1))
+	diffResp = structs.JobVersionDiffResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.GetJobVersionDiff", get, &diffResp))
+	must.Eq(t, []string{structs.JobDiffCategoryConfig}, diffResp.Summary.Categories)
+	must.True(t, diffResp.Summary.OnlyCategories(structs.JobDiffCategoryConfig))
+
+	// Unknown versions and versions without predecessors are errors
+	get.FromVersion = pointer.Of(uint64(10))
+	err := msgpackrpc.CallWithCodec(codec, "Job.GetJobVersionDiff", get, &diffResp)
+	must.ErrorContains(t, err, "version 10 of job")
+
+	get.FromVersion = nil
+	get.ToVersion = pointer.Of(uint64(0))
+	err = msgpackrpc.CallWithCodec(codec, "Job.GetJobVersionDiff", get, &diffResp)
+	must.ErrorContains(t, err, "has no previous version")
+}
+
 func TestJobEndpoint_GetJobVersions_Blocking(t *testing.T) {
 	ci.Parallel(t)
 
@@ -5682,6 +5800,64 @@ func TestJobEndpoint_Allocations(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Allocations_Paginate(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	job := mock.Job()
+	allocs := []*structs.Allocation{}
+	for _, id := range []string{
+		"aaaaaaaa-3350-4b4b-d185-0e1992ed43e9",
+		"aaaaaabb-3350-4b4b-d185-0e1992ed43e9",
+		"aaaaaacc-3350-4b4b-d185-0e1992ed43e9",
+	} {
+		alloc := mock.Alloc()
+		alloc.ID = id
+		alloc.Job = job
+		alloc.JobID = job.ID
+		allocs = append(allocs, alloc)
+	}
+	state := s1.fsm.State()
+	must.NoError(t, state.UpsertJobSummary(999, mock.JobSummary(job.ID)))
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1000, allocs))
+
+	get := &structs.JobSpecificRequest{
+		JobID: job.ID,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: job.Namespace,
+			PerPage:   2,
+		},
+	}
+	var resp structs.JobAllocationsResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Allocations", get, &resp))
+	must.Len(t, 2, resp.Allocations)
+	must.Eq(t, allocs[0].ID, resp.Allocations[0].ID)
+	must.Eq(t, allocs[1].ID, resp.Allocations[1].ID)
+	must.Eq(t, allocs[2].ID, resp.NextToken)
+
+	get.NextToken = resp.NextToken
+	resp = structs.JobAllocationsResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Allocations", get, &resp))
+	must.Len(t, 1, resp.Allocations)
+	must.Eq(t, allocs[2].ID, resp.Allocations[0].ID)
+	must.Eq(t, "", resp.NextToken)
+
+	// Reversed and filtered
+	get.NextToken = ""
+	get.Reverse = true
+	get.Filter = fmt.Sprintf(`ID != %q`, allocs[2].ID)
+	resp = structs.JobAllocationsResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Job.Allocations", get, &resp))
+	must.Len(t, 2, resp.Allocations)
+	must.Eq(t, allocs[1].ID, resp.Allocations[0].ID)
+	must.Eq(t, allocs[0].ID, resp.Allocations[1].ID)
+}
+
 func TestJobEndpoint_Allocations_ACL(t *testing.T) {
 	ci.Parallel(t)
 	require := require.New(t)
@@ -6513,6 +6689,14 @@ func TestJobEndpoint_Plan_ACL(t *testing.T) {
 	if err := msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp); err != nil {
 		t.Fatalf("err: %v", err)
 	}
+
+	// Try what-if changes without node read, expect failure
+	submitOnly := mock.CreatePolicyAndToken(t, s1.State(), 1001, "submit-only",
+		mock.NamespacePolicy(structs.DefaultNamespace, "", []string{acl.NamespaceCapabilitySubmitJob}))
+	planReq.AuthToken = submitOnly.SecretID
+	planReq.WhatIf = &structs.JobPlanWhatIf{DrainNodes: []string{uuid.Generate()}}
+	err := msgpackrpc.CallWithCodec(codec, "Job.Plan", planReq, &planResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
 }
 
 func TestJobEndpoint_Plan_WithDiff(t *testing.T) {
@@ -6635,6 +6819,89 @@ func TestJobEndpoint_Plan_NoDiff(t *testing.T) {
 	}
 }
 
+func TestJobEndpoint_Plan_WhatIf(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Create a node that only fits some of the job's allocations
+	node := mock.Node()
+	node.NodeClass = "c5"
+	must.NoError(t, node.ComputeClass())
+	must.NoError(t, s1.fsm.State().UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	job := mock.Job()
+	job.TaskGroups[0].Tasks[0].Resources.CPU = 2000
+	count := job.TaskGroups[0].Count
+
+	plan := func(whatIf *structs.JobPlanWhatIf) (*structs.JobPlanResponse, error) {
+		req := &structs.JobPlanRequest{
+			Job:    job,
+			WhatIf: whatIf,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				Namespace: job.Namespace,
+			},
+		}
+		var resp structs.JobPlanResponse
+		err := msgpackrpc.CallWithCodec(codec, "Job.Plan", req, &resp)
+		return &resp, err
+	}
+
+	// Without changes the job doesn't fit
+	resp, err := plan(nil)
+	must.NoError(t, err)
+	must.MapContainsKey(t, resp.FailedTGAllocs, "web")
+	must.MapEmpty(t, resp.WhatIfPlacements)
+
+	// Adding nodes makes it fit
+	resp, err = plan(&structs.JobPlanWhatIf{
+		AddNodes: []*structs.JobPlanWhatIfNodes{{NodeClass: "c5", Count: 2}},
+	})
+	must.NoError(t, err)
+	must.MapEmpty(t, resp.FailedTGAllocs)
+	must.MapLen(t, 2, resp.WhatIfPlacements)
+	must.MapContainsKeys(t, resp.WhatIfPlacements, []string{"what-if-c5-1", "what-if-c5-2"})
+	must.Eq(t, uint64(count), resp.Annotations.DesiredTGUpdates["web"].Place)
+
+	// Draining the existing node moves every placement to the added nodes
+	resp, err = plan(&structs.JobPlanWhatIf{
+		AddNodes:   []*structs.JobPlanWhatIfNodes{{NodeClass: "c5", Count: 2}},
+		DrainNodes: []string{node.ID[:8]},
+	})
+	must.NoError(t, err)
+	must.MapEmpty(t, resp.FailedTGAllocs)
+	must.Eq(t, count, resp.WhatIfPlacements["what-if-c5-1"]+resp.WhatIfPlacements["what-if-c5-2"])
+
+	// The changes are never applied to the cluster
+	out, err := s1.fsm.State().NodeByID(nil, node.ID)
+	must.NoError(t, err)
+	must.Nil(t, out.DrainStrategy)
+	iter, err := s1.fsm.State().Nodes(nil)
+	must.NoError(t, err)
+	must.NotNil(t, iter.Next())
+	must.Nil(t, iter.Next())
+
+	// Invalid changes are rejected
+	_, err = plan(&structs.JobPlanWhatIf{
+		AddNodes: []*structs.JobPlanWhatIfNodes{{NodeClass: "m5", Count: 1}},
+	})
+	must.ErrorContains(t, err, `no node with node class "m5"`)
+
+	_, err = plan(&structs.JobPlanWhatIf{DrainNodes: []string{uuid.Generate()}})
+	must.ErrorContains(t, err, "not found")
+
+	_, err = plan(&structs.JobPlanWhatIf{
+		AddNodes: []*structs.JobPlanWhatIfNodes{{NodeClass: "c5", Count: 0}},
+	})
+	must.ErrorContains(t, err, "must be positive")
+}
+
 // TestJobEndpoint_Plan_Scaling asserts that the plan endpoint handles
 // jobs with scaling block
 func TestJobEndpoint_Plan_Scaling(t *testing.T) {
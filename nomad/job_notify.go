@@ -0,0 +1,250 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// jobNotifyInterval is the minimum time between two scans for completed
+	// jobs to notify, to avoid scanning the jobs on every job change of a
+	// busy cluster.
+	jobNotifyInterval = 1 * time.Second
+
+	// jobNotifyTimeout is the timeout of webhook notification requests.
+	jobNotifyTimeout = 10 * time.Second
+
+	// jobNotifyMinBackoff and jobNotifyMaxBackoff bound the wait before
+	// retrying a failed webhook notification.
+	jobNotifyMinBackoff = 5 * time.Second
+	jobNotifyMaxBackoff = 5 * time.Minute
+
+	// jobNotifyMaxAttempts is the number of attempts to send a webhook
+	// notification before giving up on it.
+	jobNotifyMaxAttempts = 10
+)
+
+// jobNotifyRetry tracks the failed attempts to send the webhook notification
+// of a job.
+type jobNotifyRetry struct {
+	modifyIndex uint64
+	attempts    int
+	next        time.Time
+}
+
+// runJobNotifications sends the notifications of the completed jobs with a
+// notify block, whenever a job changes. It runs until the stop channel is
+// closed, when the server loses leadership.
+func (s *Server) runJobNotifications(stopCh chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	client := cleanhttp.DefaultPooledClient()
+	client.Timeout = jobNotifyTimeout
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		// Webhooks may only redirect to allowed hosts
+		notify := &structs.JobNotify{Webhook: req.URL.String()}
+		if err := notify.ValidateWebhookHost(s.config.JobNotifyAllowedHosts); err != nil {
+			return err
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	defer client.CloseIdleConnections()
+
+	retries := make(map[structs.NamespacedID]*jobNotifyRetry)
+	for {
+		ws := memdb.NewWatchSet()
+		ws.Add(stopCh)
+		next := s.sendJobNotifications(ctx, ws, client, retries)
+
+		// Wake up for the next retry even if no job changes
+		watchCtx, watchCancel := ctx, context.CancelFunc(func() {})
+		if !next.IsZero() {
+			watchCtx, watchCancel = context.WithDeadline(ctx, next)
+		}
+		_ = ws.WatchCtx(watchCtx)
+		watchCancel()
+
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(jobNotifyInterval):
+		}
+	}
+}
+
+// sendJobNotifications sends the pending notifications of the completed
+// jobs, and records them in raft once sent. Failed webhook notifications are
+// retried with backoff, and sendJobNotifications returns the time of the next
+// retry, if any.
+func (s *Server) sendJobNotifications(ctx context.Context, ws memdb.WatchSet,
+	client *http.Client, retries map[structs.NamespacedID]*jobNotifyRetry) time.Time {
+
+	snap, err := s.State().Snapshot()
+	if err != nil {
+		s.logger.Error("failed to get state for job notifications", "error", err)
+		return time.Time{}
+	}
+
+	iter, err := snap.Jobs(ws)
+	if err != nil {
+		s.logger.Error("failed to list jobs for job notifications", "error", err)
+		return time.Time{}
+	}
+
+	now := time.Now()
+	var next time.Time
+	pending := make(map[structs.NamespacedID]struct{})
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		job := raw.(*structs.Job)
+		if !job.NotifyPending {
+			continue
+		}
+		id := structs.NamespacedID{Namespace: job.Namespace, ID: job.ID}
+		pending[id] = struct{}{}
+
+		retry := retries[id]
+		if retry != nil && retry.modifyIndex != job.ModifyIndex {
+			retry = nil
+		}
+		if retry != nil && now.Before(retry.next) {
+			if next.IsZero() || retry.next.Before(next) {
+				next = retry.next
+			}
+			continue
+		}
+
+		logger := s.logger.With("namespace", job.Namespace, "job_id", job.ID)
+		if err := s.sendJobNotification(ctx, snap, client, job); err != nil {
+			if ctx.Err() != nil {
+				return time.Time{}
+			}
+			if retry == nil {
+				retry = &jobNotifyRetry{modifyIndex: job.ModifyIndex}
+				retries[id] = retry
+			}
+			retry.attempts++
+			if retry.attempts < jobNotifyMaxAttempts {
+				retry.next = now.Add(helper.Backoff(jobNotifyMinBackoff, jobNotifyMaxBackoff, uint64(retry.attempts-1)))
+				logger.Warn("failed to send job notification", "error", err, "retry", retry.next)
+				if next.IsZero() || retry.next.Before(next) {
+					next = retry.next
+				}
+				continue
+			}
+			logger.Error("failed to send job notification, giving up", "error", err, "attempts", retry.attempts)
+		}
+		delete(retries, id)
+
+		req := &structs.JobNotifyRequest{
+			Namespace:    job.Namespace,
+			JobID:        job.ID,
+			ModifyIndex:  job.ModifyIndex,
+			WriteRequest: structs.WriteRequest{Region: s.Region()},
+		}
+		if _, _, err := s.raftApply(structs.JobNotifyRequestType, req); err != nil {
+			logger.Error("failed to record job notification", "error", err)
+		}
+	}
+
+	// Forget the retries of the jobs that were purged or ran again
+	for id := range retries {
+		if _, ok := pending[id]; !ok {
+			delete(retries, id)
+		}
+	}
+	return next
+}
+
+// sendJobNotification POSTs the notification of the completed job to its
+// webhook, signed with the secret of the notify block if set. Jobs without a
+// webhook, whose webhook host isn't allowed by the server configuration, or
+// whose outcome isn't notified are only recorded in raft, which publishes the
+// notification to the event stream.
+func (s *Server) sendJobNotification(ctx context.Context, snap *state.StateSnapshot,
+	client *http.Client, job *structs.Job) error {
+
+	if job.Notify == nil || job.Notify.Webhook == "" {
+		return nil
+	}
+
+	// The allowed hosts may have changed since the job was registered
+	if err := job.Notify.ValidateWebhookHost(s.config.JobNotifyAllowedHosts); err != nil {
+		s.logger.Warn("not sending job notification", "namespace", job.Namespace,
+			"job_id", job.ID, "error", err)
+		return nil
+	}
+
+	allocs, err := snap.AllocsByJob(nil, job.Namespace, job.ID, false)
+	if err != nil {
+		return err
+	}
+	notification := structs.NewJobNotification(job, allocs)
+	if !job.Notify.Notifies(notification.Status) {
+		return nil
+	}
+
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.Notify.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range job.Notify.Headers {
+		req.Header.Set(k, v)
+	}
+	if job.Notify.Secret != "" {
+		req.Header.Set(structs.JobNotifySignatureHeader, signJobNotification(job.Notify.Secret, body))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Drain the response so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// signJobNotification returns the signature header of the body of a webhook
+// notification.
+func signJobNotification(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/shoenig/test/must"
+	"github.com/shoenig/test/wait"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+)
+
+func TestServer_JobNotifications(t *testing.T) {
+	ci.Parallel(t)
+
+	// The webhook fails the first request so the notification is retried
+	type request struct {
+		body      []byte
+		signature string
+		team      string
+	}
+	requests := make(chan request, 10)
+	var failed atomic.Bool
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !failed.Swap(true) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		requests <- request{
+			body:      body,
+			signature: r.Header.Get(structs.JobNotifySignatureHeader),
+			team:      r.Header.Get("X-Team"),
+		}
+	}))
+	defer hook.Close()
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.JobNotifyAllowedHosts = []string{"127.0.0.1"}
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	job := mock.BatchJob()
+	job.Notify = &structs.JobNotify{
+		Webhook: hook.URL,
+		Secret:  "s3cr3t",
+		Headers: map[string]string{"X-Team": "data"},
+	}
+	job.Canonicalize()
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.TaskGroup = job.TaskGroups[0].Name
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusFailed
+	must.NoError(t, store.UpdateAllocsFromClient(structs.MsgTypeTestSetup, 1002, []*structs.Allocation{update}))
+
+	var req request
+	select {
+	case req = <-requests:
+	case <-time.After(jobNotifyMinBackoff + 5*time.Second):
+		t.Fatal("timed out waiting for the job notification")
+	}
+	must.Eq(t, signJobNotification("s3cr3t", req.body), req.signature)
+	must.Eq(t, "data", req.team)
+
+	var notification structs.JobNotification
+	must.NoError(t, json.Unmarshal(req.body, &notification))
+	must.Eq(t, job.ID, notification.JobID)
+	must.Eq(t, structs.JobNotifyFailed, notification.Status)
+	must.Eq(t, 1, notification.Stats.Failed)
+
+	// The notification is recorded once sent
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool {
+			out, err := store.JobByID(nil, job.Namespace, job.ID)
+			must.NoError(t, err)
+			return !out.NotifyPending
+		}),
+		wait.Timeout(5*time.Second),
+		wait.Gap(50*time.Millisecond),
+	))
+	must.Eq(t, 0, len(requests))
+}
+
+func TestServer_JobNotifications_AllowedHosts(t *testing.T) {
+	ci.Parallel(t)
+
+	var requests atomic.Int32
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer hook.Close()
+
+	// The allowed host redirects to the webhook, which isn't allowed
+	var redirects atomic.Int32
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirects.Add(1)
+		http.Redirect(w, r, hook.URL, http.StatusTemporaryRedirect)
+	}))
+	defer redirect.Close()
+	_, redirectPort, err := net.SplitHostPort(redirect.Listener.Addr().String())
+	must.NoError(t, err)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+		c.JobNotifyAllowedHosts = []string{"localhost"}
+	})
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	// Jobs with a webhook to a host that isn't allowed are rejected
+	job := mock.BatchJob()
+	job.Notify = &structs.JobNotify{Webhook: hook.URL}
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var resp structs.JobRegisterResponse
+	err = msgpackrpc.CallWithCodec(codec, "Job.Register", req, &resp)
+	must.ErrorContains(t, err, "is not allowed by the server configuration")
+
+	// Notifications redirected to a host that isn't allowed aren't sent
+	job.Notify.Webhook = "http://localhost:" + redirectPort
+	job.Canonicalize()
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1000, nil, job))
+
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.TaskGroup = job.TaskGroups[0].Name
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusComplete
+	must.NoError(t, store.UpdateAllocsFromClient(structs.MsgTypeTestSetup, 1002, []*structs.Allocation{update}))
+
+	must.Wait(t, wait.InitialSuccess(
+		wait.BoolFunc(func() bool { return redirects.Load() > 0 }),
+		wait.Timeout(5*time.Second),
+		wait.Gap(50*time.Millisecond),
+	))
+	must.Eq(t, 0, requests.Load())
+}
@@ -0,0 +1,263 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/state/paginator"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// JobTemplate endpoint is used for job template management and interaction.
+type JobTemplate struct {
+	srv *Server
+	ctx *RPCContext
+}
+
+func NewJobTemplateEndpoint(srv *Server, ctx *RPCContext) *JobTemplate {
+	return &JobTemplate{srv: srv, ctx: ctx}
+}
+
+// List is used to retrieve the job templates. It supports prefix listing,
+// pagination, and filtering.
+func (j *JobTemplate) List(args *structs.JobTemplateListRequest, reply *structs.JobTemplateListResponse) error {
+	authErr := j.srv.Authenticate(j.ctx, args)
+	if done, err := j.srv.forward("JobTemplate.List", args, args, reply); done {
+		return err
+	}
+	j.srv.MeasureRPCRate("job_template", structs.RateMetricList, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job_template", "list"}, time.Now())
+
+	// Resolve ACL token to only return job templates it has access to.
+	aclObj, err := j.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+
+	// Setup blocking query.
+	sort := state.SortOption(args.Reverse)
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			var err error
+			var iter memdb.ResultIterator
+
+			if prefix := args.QueryOptions.Prefix; prefix != "" {
+				iter, err = store.JobTemplatesByNamePrefix(ws, prefix, sort)
+			} else {
+				iter, err = store.JobTemplates(ws, sort)
+			}
+			if err != nil {
+				return err
+			}
+
+			pageOpts := paginator.StructsTokenizerOptions{WithID: true}
+			tokenizer := paginator.NewStructsTokenizer(iter, pageOpts)
+			filters := []paginator.Filter{
+				// Filter out job templates based on ACL token capabilities.
+				paginator.GenericFilter{
+					Allow: func(raw interface{}) (bool, error) {
+						tmpl := raw.(*structs.JobTemplate)
+						return allowJobTemplateRead(aclObj, tmpl.Name), nil
+					},
+				},
+			}
+
+			var templates []*structs.JobTemplateStub
+			pager, err := paginator.NewPaginator(iter, tokenizer, filters, args.QueryOptions,
+				func(raw interface{}) error {
+					tmpl := raw.(*structs.JobTemplate)
+					templates = append(templates, tmpl.Stub())
+					return nil
+				})
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to create result paginator: %v", err)
+			}
+
+			nextToken, err := pager.Page()
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to read result page: %v", err)
+			}
+
+			reply.QueryMeta.NextToken = nextToken
+			reply.Templates = templates
+
+			// Use the last index that affected the job templates table.
+			index, err := store.Index(state.TableJobTemplates)
+			if err != nil {
+				return err
+			}
+			reply.Index = max(1, index)
+
+			// Set the query response.
+			j.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
+// GetJobTemplate returns the specific job template requested or nil if the
+// job template doesn't exist. Tokens allowed to run a template can also read
+// it, since its source is needed to render jobs.
+func (j *JobTemplate) GetJobTemplate(args *structs.JobTemplateSpecificRequest, reply *structs.SingleJobTemplateResponse) error {
+	authErr := j.srv.Authenticate(j.ctx, args)
+	if done, err := j.srv.forward("JobTemplate.GetJobTemplate", args, args, reply); done {
+		return err
+	}
+	j.srv.MeasureRPCRate("job_template", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job_template", "get_job_template"}, time.Now())
+
+	// Resolve ACL token and verify it has read capability for the template.
+	aclObj, err := j.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !allowJobTemplateRead(aclObj, args.Name) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query.
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			tmpl, err := store.JobTemplateByName(ws, args.Name)
+			if err != nil {
+				return err
+			}
+
+			reply.Template = tmpl
+			if tmpl != nil {
+				reply.Index = tmpl.ModifyIndex
+			} else {
+				// Return the last index that affected the job templates table
+				// if the requested job template doesn't exist.
+				index, err := store.Index(state.TableJobTemplates)
+				if err != nil {
+					return err
+				}
+				reply.Index = max(1, index)
+			}
+			return nil
+		}}
+	return j.srv.blockingRPC(&opts)
+}
+
+// UpsertJobTemplates creates or updates the given job templates.
+func (j *JobTemplate) UpsertJobTemplates(args *structs.JobTemplateUpsertRequest, reply *structs.GenericResponse) error {
+	authErr := j.srv.Authenticate(j.ctx, args)
+	if done, err := j.srv.forward("JobTemplate.UpsertJobTemplates", args, args, reply); done {
+		return err
+	}
+	j.srv.MeasureRPCRate("job_template", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job_template", "upsert_job_templates"}, time.Now())
+
+	// Resolve ACL token and verify it has write capability to all templates
+	// in the request.
+	aclObj, err := j.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	for _, tmpl := range args.Templates {
+		if !aclObj.AllowJobTemplateOperation(tmpl.Name, acl.JobTemplateCapabilityWrite) {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	if !ServersMeetMinimumVersion(
+		j.srv.serf.Members(), j.srv.Region(), minJobTemplatesVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to upsert job templates", minJobTemplatesVersion)
+	}
+
+	// Validate request.
+	if len(args.Templates) == 0 {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "must specify at least one job template")
+	}
+	for _, tmpl := range args.Templates {
+		if err := tmpl.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid job template %q: %v", tmpl.Name, err)
+		}
+	}
+
+	// Update via Raft.
+	_, index, err := j.srv.raftApply(structs.JobTemplateUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
+// DeleteJobTemplates deletes the given job templates. Jobs previously run
+// from the templates are not affected.
+func (j *JobTemplate) DeleteJobTemplates(args *structs.JobTemplateDeleteRequest, reply *structs.GenericResponse) error {
+	authErr := j.srv.Authenticate(j.ctx, args)
+	if done, err := j.srv.forward("JobTemplate.DeleteJobTemplates", args, args, reply); done {
+		return err
+	}
+	j.srv.MeasureRPCRate("job_template", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "job_template", "delete_job_templates"}, time.Now())
+
+	// Resolve ACL token and verify it has delete capability to all templates
+	// in the request.
+	aclObj, err := j.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	for _, name := range args.Names {
+		if !aclObj.AllowJobTemplateOperation(name, acl.JobTemplateCapabilityDelete) {
+			return structs.ErrPermissionDenied
+		}
+	}
+
+	if !ServersMeetMinimumVersion(
+		j.srv.serf.Members(), j.srv.Region(), minJobTemplatesVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to delete job templates", minJobTemplatesVersion)
+	}
+
+	// Validate request.
+	if len(args.Names) == 0 {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "must specify at least one job template to delete")
+	}
+	for _, name := range args.Names {
+		if name == "" {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "job template name is empty")
+		}
+	}
+
+	// Delete via Raft.
+	_, index, err := j.srv.raftApply(structs.JobTemplateDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
+// allowJobTemplateRead returns true if the token can read the job template,
+// which is allowed by either the read or run capabilities.
+func allowJobTemplateRead(aclObj *acl.ACL, name string) bool {
+	return aclObj.AllowJobTemplateOperation(name, acl.JobTemplateCapabilityRead) ||
+		aclObj.AllowJobTemplateOperation(name, acl.JobTemplateCapabilityRun)
+}
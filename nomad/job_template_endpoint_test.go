@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobTemplateEndpoint_CRUD(t *testing.T) {
+	ci.Parallel(t)
+
+	s, cleanupS := TestServer(t, nil)
+	defer cleanupS()
+
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	web := mock.JobTemplate()
+	web.Name = "web"
+	worker := mock.JobTemplate()
+	worker.Name = "worker"
+
+	// Register the templates.
+	upsertReq := &structs.JobTemplateUpsertRequest{
+		Templates:    []*structs.JobTemplate{web, worker},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var upsertResp structs.GenericResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "JobTemplate.UpsertJobTemplates", upsertReq, &upsertResp))
+	must.NonZero(t, upsertResp.Index)
+
+	// List the templates.
+	listReq := &structs.JobTemplateListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var listResp structs.JobTemplateListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "JobTemplate.List", listReq, &listResp))
+	must.Eq(t, upsertResp.Index, listResp.Index)
+	must.Len(t, 2, listResp.Templates)
+	must.Eq(t, "web", listResp.Templates[0].Name)
+	must.Eq(t, web.Description, listResp.Templates[0].Description)
+
+	listReq.Prefix = "wor"
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "JobTemplate.List", listReq, &listResp))
+	must.Len(t, 1, listResp.Templates)
+	must.Eq(t, "worker", listResp.Templates[0].Name)
+
+	// Read a template.
+	getReq := &structs.JobTemplateSpecificRequest{
+		Name:         "web",
+		QueryOptions: structs.QueryOptions{Region: "global"},
+	}
+	var getResp structs.SingleJobTemplateResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "JobTemplate.GetJobTemplate", getReq, &getResp))
+	must.NotNil(t, getResp.Template)
+	must.Eq(t, web.Source, getResp.Template.Source)
+
+	// Invalid templates are rejected.
+	upsertReq.Templates = []*structs.JobTemplate{{Name: "invalid name"}}
+	err := msgpackrpc.CallWithCodec(codec, "JobTemplate.UpsertJobTemplates", upsertReq, &upsertResp)
+	must.ErrorContains(t, err, "invalid job template")
+
+	// Delete a template.
+	deleteReq := &structs.JobTemplateDeleteRequest{
+		Names:        []string{"web"},
+		WriteRequest: structs.WriteRequest{Region: "global"},
+	}
+	var deleteResp structs.GenericResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "JobTemplate.DeleteJobTemplates", deleteReq, &deleteResp))
+
+	getResp = structs.SingleJobTemplateResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "JobTemplate.GetJobTemplate", getReq, &getResp))
+	must.Nil(t, getResp.Template)
+
+	err = msgpackrpc.CallWithCodec(codec, "JobTemplate.DeleteJobTemplates", deleteReq, &deleteResp)
+	must.ErrorContains(t, err, "not found")
+}
+
+func TestJobTemplateEndpoint_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s, root, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	web := mock.JobTemplate()
+	web.Name = "web"
+	prodWeb := mock.JobTemplate()
+	prodWeb.Name = "prod-web"
+	must.NoError(t, s.fsm.State().UpsertJobTemplates(structs.MsgTypeTestSetup, 1000,
+		[]*structs.JobTemplate{web, prodWeb}))
+
+	runToken := mock.CreatePolicyAndToken(t, s.fsm.State(), 1001, "run-web",
+		`job_template "web" { capabilities = ["run"] }`)
+	writeToken := mock.CreatePolicyAndToken(t, s.fsm.State(), 1003, "write-prod",
+		`job_template "prod-*" { policy = "write" }`)
+	noPolicyToken := mock.CreateToken(t, s.fsm.State(), 1005, nil)
+
+	// Listing only returns the templates the token can read or run.
+	listReq := &structs.JobTemplateListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: runToken.SecretID},
+	}
+	var listResp structs.JobTemplateListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "JobTemplate.List", listReq, &listResp))
+	must.Len(t, 1, listResp.Templates)
+	must.Eq(t, "web", listResp.Templates[0].Name)
+
+	listReq.AuthToken = noPolicyToken.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "JobTemplate.List", listReq, &listResp))
+	must.Len(t, 0, listResp.Templates)
+
+	listReq.AuthToken = root.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "JobTemplate.List", listReq, &listResp))
+	must.Len(t, 2, listResp.Templates)
+
+	// The run capability allows reading the template.
+	getReq := &structs.JobTemplateSpecificRequest{
+		Name:         "web",
+		QueryOptions: structs.QueryOptions{Region: "global", AuthToken: runToken.SecretID},
+	}
+	var getResp structs.SingleJobTemplateResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "JobTemplate.GetJobTemplate", getReq, &getResp))
+	must.NotNil(t, getResp.Template)
+
+	getReq.Name = "prod-web"
+	err := msgpackrpc.CallWithCodec(codec, "JobTemplate.GetJobTemplate", getReq, &getResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Writes require the write capability on every template.
+	upsertReq := &structs.JobTemplateUpsertRequest{
+		Templates:    []*structs.JobTemplate{web},
+		WriteRequest: structs.WriteRequest{Region: "global", AuthToken: runToken.SecretID},
+	}
+	var upsertResp structs.GenericResponse
+	err = msgpackrpc.CallWithCodec(codec, "JobTemplate.UpsertJobTemplates", upsertReq, &upsertResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	upsertReq.AuthToken = writeToken.SecretID
+	upsertReq.Templates = []*structs.JobTemplate{prodWeb.Copy(), web.Copy()}
+	err = msgpackrpc.CallWithCodec(codec, "JobTemplate.UpsertJobTemplates", upsertReq, &upsertResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	upsertReq.Templates = []*structs.JobTemplate{prodWeb.Copy()}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "JobTemplate.UpsertJobTemplates", upsertReq, &upsertResp))
+
+	// Deletes require the delete capability.
+	deleteReq := &structs.JobTemplateDeleteRequest{
+		Names:        []string{"web"},
+		WriteRequest: structs.WriteRequest{Region: "global", AuthToken: writeToken.SecretID},
+	}
+	var deleteResp structs.GenericResponse
+	err = msgpackrpc.CallWithCodec(codec, "JobTemplate.DeleteJobTemplates", deleteReq, &deleteResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	deleteReq.Names = []string{"prod-web"}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "JobTemplate.DeleteJobTemplates", deleteReq, &deleteResp))
+}
@@ -4,6 +4,7 @@
 package nomad
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
 
+	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -434,3 +436,144 @@ func (k *Keyring) GetConfig(args *structs.GenericRequest, reply *structs.Keyring
 	reply.OIDCDiscovery = k.srv.oidcDisco
 	return nil
 }
+
+// Seal encrypts the sealed values of a task with the active root key, bound
+// to the job, so they are never submitted to the servers in cleartext. Values
+// that are unchanged from the registered job keep their sealed value.
+func (k *Keyring) Seal(args *structs.KeyringSealRequest, reply *structs.KeyringSealResponse) error {
+
+	authErr := k.srv.Authenticate(k.ctx, args)
+	if done, err := k.srv.forward("Keyring.Seal", args, args, reply); done {
+		return err
+	}
+	k.srv.MeasureRPCRate("keyring", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "seal"}, time.Now())
+
+	// Sealing values is part of submitting the job
+	if aclObj, err := k.srv.ResolveACL(args); err != nil {
+		return err
+	} else if !aclObj.AllowNsOp(args.RequestNamespace(), acl.NamespaceCapabilitySubmitJob) {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.JobID == "" {
+		return fmt.Errorf("job ID is required")
+	}
+
+	binding := structs.SealedBinding(args.RequestNamespace(), args.JobID)
+
+	// look up the values the task of the registered job already has
+	snap, err := k.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	var existing map[string]string
+	job, err := snap.JobByID(nil, args.RequestNamespace(), args.JobID)
+	if err != nil {
+		return err
+	}
+	if job != nil && job.SealedBinding() == binding {
+		if tg := job.LookupTaskGroup(args.TaskGroup); tg != nil {
+			if task := tg.LookupTask(args.Task); task != nil {
+				existing = task.Sealed
+			}
+		}
+	}
+
+	reply.Values = make(map[string]string, len(args.Values))
+	for name, value := range args.Values {
+		if sealed, ok := existing[name]; ok {
+			if cleartext, err := k.unseal(sealed, binding); err == nil &&
+				subtle.ConstantTimeCompare(cleartext, []byte(value)) == 1 {
+				reply.Values[name] = sealed
+				continue
+			}
+		}
+
+		ciphertext, keyID, err := k.encrypter.EncryptBound([]byte(value), binding)
+		if err != nil {
+			return fmt.Errorf("failed to seal value %q: %w", name, err)
+		}
+		reply.Values[name] = structs.FormatSealedValue(keyID, ciphertext)
+	}
+
+	return nil
+}
+
+// Unseal decrypts the sealed values of a task of an allocation. Only the
+// client the allocation is placed on may unseal its values.
+func (k *Keyring) Unseal(args *structs.KeyringUnsealRequest, reply *structs.KeyringUnsealResponse) error {
+
+	aclObj, err := k.srv.AuthenticateClientOnly(k.ctx, args)
+	k.srv.MeasureRPCRate("keyring", structs.RateMetricRead, args)
+	if err != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	if done, err := k.srv.forward("Keyring.Unseal", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "keyring", "unseal"}, time.Now())
+
+	if !aclObj.AllowClientOp() {
+		return structs.ErrPermissionDenied
+	}
+
+	snap, err := k.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	alloc, err := snap.AllocByID(nil, args.AllocID)
+	if err != nil {
+		return err
+	}
+	if alloc == nil {
+		return structs.NewErrUnknownAllocation(args.AllocID)
+	}
+
+	// Clients may only unseal the values of their own allocations.
+	if identity := args.GetIdentity(); identity == nil || identity.ClientID != alloc.NodeID {
+		return structs.ErrPermissionDenied
+	}
+	if alloc.ServerTerminalStatus() {
+		return fmt.Errorf("allocation %q is terminal", alloc.ID)
+	}
+
+	job := alloc.Job
+	if job == nil {
+		return fmt.Errorf("allocation %q has no job", alloc.ID)
+	}
+	var task *structs.Task
+	if tg := job.LookupTaskGroup(alloc.TaskGroup); tg != nil {
+		task = tg.LookupTask(args.Task)
+	}
+	if task == nil {
+		return fmt.Errorf("task %q not found in allocation %q", args.Task, alloc.ID)
+	}
+
+	binding := job.SealedBinding()
+	reply.Values = make(map[string]string, len(task.Sealed))
+	for name, sealed := range task.Sealed {
+		cleartext, err := k.unseal(sealed, binding)
+		if err != nil {
+			return fmt.Errorf("failed to unseal value %q: %w", name, err)
+		}
+		reply.Values[name] = string(cleartext)
+	}
+
+	reply.Index = alloc.ModifyIndex
+	return nil
+}
+
+// unseal decrypts a sealed value encrypted with the binding.
+func (k *Keyring) unseal(sealed, binding string) ([]byte, error) {
+	keyID, ciphertext, err := structs.ParseSealedValue(sealed)
+	if err != nil {
+		return nil, err
+	}
+	return k.encrypter.DecryptBound(ciphertext, keyID, binding)
+}
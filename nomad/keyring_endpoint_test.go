@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/testutil"
 )
@@ -407,3 +408,95 @@ func TestKeyringEndpoint_GetConfig_Disabled(t *testing.T) {
 	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Keyring.GetConfig", &req, &resp))
 	must.Nil(t, resp.OIDCDiscovery)
 }
+
+// TestKeyringEndpoint_SealUnseal asserts that sealed values are bound to their
+// job and can only be unsealed by the node of the allocation
+func TestKeyringEndpoint_SealUnseal(t *testing.T) {
+	ci.Parallel(t)
+	srv, rootToken, shutdown := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+	codec := rpcClient(t, srv)
+	store := srv.fsm.State()
+
+	job := mock.Job()
+	sealReq := &structs.KeyringSealRequest{
+		JobID:     job.ID,
+		TaskGroup: "web",
+		Task:      "web",
+		Values:    map[string]string{"password": "hunter2"},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+		},
+	}
+	var sealResp structs.KeyringSealResponse
+
+	err := msgpackrpc.CallWithCodec(codec, "Keyring.Seal", sealReq, &sealResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	sealReq.AuthToken = rootToken.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Keyring.Seal", sealReq, &sealResp))
+	sealed := sealResp.Values["password"]
+	must.True(t, structs.IsSealedValue(sealed))
+	must.StrNotContains(t, sealed, "hunter2")
+
+	job.TaskGroups[0].Tasks[0].Sealed = map[string]string{"password": sealed}
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 100, nil, job))
+
+	// Sealing an unchanged value keeps the sealed value of the registered
+	// job, and sealing a changed value doesn't
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Keyring.Seal", sealReq, &sealResp))
+	must.Eq(t, sealed, sealResp.Values["password"])
+	sealReq.Values["password"] = "hunter3"
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Keyring.Seal", sealReq, &sealResp))
+	must.NotEq(t, sealed, sealResp.Values["password"])
+
+	node := mock.Node()
+	must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 101, node))
+	otherNode := mock.Node()
+	must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 102, otherNode))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.Job = job
+	alloc.JobID = job.ID
+
+	// The ciphertext of the value can't be unsealed in another job
+	otherAlloc := mock.Alloc()
+	otherAlloc.NodeID = node.ID
+	otherAlloc.Job.TaskGroups[0].Tasks[0].Sealed = map[string]string{"password": sealed}
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 103,
+		[]*structs.Allocation{alloc, otherAlloc}))
+
+	unsealReq := &structs.KeyringUnsealRequest{
+		AllocID: alloc.ID,
+		Task:    "web",
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			AuthToken: otherNode.SecretID,
+		},
+	}
+	var unsealResp structs.KeyringUnsealResponse
+
+	// Only the node of the allocation can unseal its values
+	err = msgpackrpc.CallWithCodec(codec, "Keyring.Unseal", unsealReq, &unsealResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	unsealReq.AuthToken = node.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Keyring.Unseal", unsealReq, &unsealResp))
+	must.Eq(t, map[string]string{"password": "hunter2"}, unsealResp.Values)
+
+	unsealReq.AllocID = otherAlloc.ID
+	err = msgpackrpc.CallWithCodec(codec, "Keyring.Unseal", unsealReq, &unsealResp)
+	must.ErrorContains(t, err, `failed to unseal value "password"`)
+
+	// The key can't be garbage collected while the job uses it
+	keyID, _, err := structs.ParseSealedValue(sealed)
+	must.NoError(t, err)
+	inUse, err := store.IsRootKeyMetaInUse(keyID)
+	must.NoError(t, err)
+	must.True(t, inUse)
+}
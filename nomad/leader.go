@@ -40,6 +40,12 @@ const (
 	// possible loss of leadership event if we are unable to get a barrier
 	// while leader.
 	barrierWriteTimeout = 2 * time.Minute
+
+	// stateUsageCollectionInterval is the interval at which the usage of the
+	// state store tables is collected for metrics. Collecting it walks every
+	// object of the state store, so it's done much less often than other
+	// metrics are collected.
+	stateUsageCollectionInterval = 5 * time.Minute
 )
 
 var minAutopilotVersion = version.Must(version.NewVersion("0.8.0"))
@@ -84,6 +90,39 @@ var minNodePoolsVersion = version.Must(version.NewVersion("1.6.0"))
 // automatically added to jobs that need access to Consul or Vault
 var minVersionMultiIdentities = version.Must(version.NewVersion("1.7.0"))
 
+// minVariableTTLVersion is the Nomad version at which variables can be given
+// a TTL, after which they are expired and deleted by the leader.
+var minVariableTTLVersion = version.Must(version.NewVersion("1.7.7"))
+
+// minVariableHistoryVersion is the Nomad version at which prior versions of
+// variables are kept, deleted variables can be restored, and variables are
+// re-keyed in place.
+var minVariableHistoryVersion = version.Must(version.NewVersion("1.7.7"))
+
+// Any writes to variable grants requires that all servers are on version
+// 1.7.7 to prevent older versions of the server from crashing.
+var minVariableGrantsVersion = version.Must(version.NewVersion("1.7.7"))
+
+// Any writes to maintenance windows requires that all servers are on version
+// 1.7.7 to prevent older versions of the server from crashing.
+var minMaintenanceWindowsVersion = version.Must(version.NewVersion("1.7.7"))
+
+// Any writes to client upgrades requires that all servers are on version 1.7.7
+// to prevent older versions of the server from crashing.
+var minClientUpgradesVersion = version.Must(version.NewVersion("1.7.7"))
+
+// Any writes to job templates requires that all servers are on version 1.7.7
+// to prevent older versions of the server from crashing.
+var minJobTemplatesVersion = version.Must(version.NewVersion("1.7.7"))
+
+// Any writes to event sinks requires that all servers are on version 1.7.7
+// to prevent older versions of the server from crashing.
+var minEventSinksVersion = version.Must(version.NewVersion("1.7.7"))
+
+// Any writes to usage records requires that all servers are on version 1.7.7
+// to prevent older versions of the server from crashing.
+var minUsageVersion = version.Must(version.NewVersion("1.7.7"))
+
 // monitorLeadership is used to monitor if we acquire or lose our role
 // as the leader in the Raft cluster. There is some work the leader is
 // expected to do, so we must react to changes
@@ -126,6 +165,7 @@ func (s *Server) monitorLeadership() {
 	for {
 		select {
 		case isLeader := <-leaderCh:
+			s.config.FlightRecorder.RecordLeadership(isLeader)
 			if wasLeader != isLeader {
 				wasLeader = isLeader
 				// normal case where we went through a transition
@@ -397,6 +437,12 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Enable the volume watcher, since we are now the leader
 	s.volumeWatcher.SetEnabled(true, s.State(), s.getLeaderAcl())
 
+	// Enable the event sinks, since we are now the leader
+	s.eventSinks.SetEnabled(true, s.State())
+
+	// Enable the usage accountant, since we are now the leader
+	s.usageAccountant.SetEnabled(s.config.Usage.IsEnabled(), s.State())
+
 	// Restore the eval broker state and blocked eval state. If these are
 	// currently paused, we do not need to do this.
 	if restoreEvals {
@@ -448,6 +494,27 @@ func (s *Server) establishLeadership(stopCh chan struct{}) error {
 	// Periodically publish job status metrics
 	go s.publishJobStatusMetrics(stopCh)
 
+	// Periodically publish state store table usage metrics
+	go s.publishStateUsageMetrics(stopCh)
+
+	// Periodically take the scheduled snapshots of CSI volumes
+	go s.scheduleCSISnapshots(stopCh)
+
+	// Evaluate the scaling policies of the built-in autoscaler
+	go s.runBuiltinAutoscaler(stopCh)
+
+	// Start and end the maintenance windows of the nodes
+	go s.runMaintenanceWindows(stopCh)
+
+	// Advance the rolling upgrades of the client agents
+	go s.runClientUpgrades(stopCh)
+
+	// Release the queued dispatched jobs of parameterized jobs
+	go s.runDispatchQueues(stopCh)
+
+	// Send the notifications of the completed batch jobs
+	go s.runJobNotifications(stopCh)
+
 	// Populate the variable lock TTL timers, so we can start tracking renewals
 	// and expirations.
 	if err := s.restoreLockTTLTimers(); err != nil {
@@ -1048,6 +1115,8 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 	defer rootKeyGC.Stop()
 	variablesRekey := time.NewTicker(s.config.VariablesRekeyInterval)
 	defer variablesRekey.Stop()
+	variablesExpiredGC := time.NewTicker(s.config.VariablesExpirationGCInterval)
+	defer variablesExpiredGC.Stop()
 
 	// Set up the expired ACL local token garbage collection timer.
 	localTokenExpiredGC, localTokenExpiredGCStop := helper.NewSafeTimer(s.config.ACLTokenExpirationGCInterval)
@@ -1101,6 +1170,14 @@ func (s *Server) schedulePeriodic(stopCh chan struct{}) {
 			if index, ok := s.getLatestIndex(); ok {
 				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobVariablesRekey, index))
 			}
+		case <-variablesExpiredGC.C:
+			if !ServersMeetMinimumVersion(s.Members(), s.Region(), minVariableTTLVersion, false) {
+				continue
+			}
+
+			if index, ok := s.getLatestIndex(); ok {
+				s.evalBroker.Enqueue(s.coreJobEval(structs.CoreJobVariablesExpiredGC, index))
+			}
 		case <-stopCh:
 			return
 		}
@@ -1455,6 +1532,82 @@ func (s *Server) publishJobStatusMetrics(stopCh chan struct{}) {
 	}
 }
 
+// publishStateUsageMetrics publishes the object counts and estimated memory
+// usage of the tables of the state store, and the rate at which the number of
+// objects of each table grows per minute. The usage is collected every
+// stateUsageCollectionInterval, and the last collected values are published
+// every StatsCollectionInterval.
+func (s *Server) publishStateUsageMetrics(stopCh chan struct{}) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	var tables []*structs.StateTableUsage
+	var lastCollected time.Time
+	growth := map[string]float64{}
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-timer.C:
+			timer.Reset(s.config.StatsCollectionInterval)
+
+			if now := time.Now(); now.Sub(lastCollected) >= stateUsageCollectionInterval {
+				collected, err := s.collectStateUsage()
+				if err != nil {
+					s.logger.Error("failed to get state table usage", "error", err)
+				} else {
+					growth = stateUsageGrowth(tables, collected, now.Sub(lastCollected))
+					tables = collected
+					lastCollected = now
+				}
+			}
+
+			for _, table := range tables {
+				labels := []metrics.Label{{Name: "table", Value: table.Name}}
+				metrics.SetGaugeWithLabels([]string{"nomad", "state", "table", "objects"},
+					float32(table.Objects), labels)
+				metrics.SetGaugeWithLabels([]string{"nomad", "state", "table", "estimated_bytes"},
+					float32(table.EstimatedBytes), labels)
+				if perMinute, ok := growth[table.Name]; ok {
+					metrics.SetGaugeWithLabels([]string{"nomad", "state", "table", "growth_rate"},
+						float32(perMinute), labels)
+				}
+			}
+		}
+	}
+}
+
+// collectStateUsage returns the usage of the tables of a snapshot of the
+// state store.
+func (s *Server) collectStateUsage() ([]*structs.StateTableUsage, error) {
+	state, err := s.State().Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return state.TableStats()
+}
+
+// stateUsageGrowth returns the change in the number of objects per minute of
+// each table between two collections of the state usage.
+func stateUsageGrowth(last, current []*structs.StateTableUsage, elapsed time.Duration) map[string]float64 {
+	growth := make(map[string]float64, len(current))
+	if len(last) == 0 || elapsed <= 0 {
+		return growth
+	}
+
+	lastObjects := make(map[string]int, len(last))
+	for _, table := range last {
+		lastObjects[table.Name] = table.Objects
+	}
+	for _, table := range current {
+		if objects, ok := lastObjects[table.Name]; ok {
+			growth[table.Name] = float64(table.Objects-objects) / elapsed.Minutes()
+		}
+	}
+	return growth
+}
+
 func (s *Server) iterateJobStatusMetrics(jobs *memdb.ResultIterator) {
 	var pending int64 // Sum of all jobs in 'pending' state
 	var running int64 // Sum of all jobs in 'running' state
@@ -1522,6 +1675,12 @@ func (s *Server) revokeLeadership() error {
 	// Disable the volume watcher
 	s.volumeWatcher.SetEnabled(false, nil, "")
 
+	// Disable the event sinks
+	s.eventSinks.SetEnabled(false, nil)
+
+	// Disable the usage accountant
+	s.usageAccountant.SetEnabled(false, nil)
+
 	// Disable any enterprise systems required.
 	if err := s.revokeEnterpriseLeadership(); err != nil {
 		return err
@@ -1972,3 +1972,23 @@ func TestServer_handleEvalBrokerStateChange(t *testing.T) {
 		})
 	}
 }
+
+func TestLeader_stateUsageGrowth(t *testing.T) {
+	ci.Parallel(t)
+
+	last := []*structs.StateTableUsage{
+		{Name: "allocs", Objects: 100},
+		{Name: "evals", Objects: 50},
+	}
+	current := []*structs.StateTableUsage{
+		{Name: "allocs", Objects: 110},
+		{Name: "evals", Objects: 40},
+		{Name: "jobs", Objects: 5},
+	}
+
+	// No growth is known on the first collection
+	must.MapEmpty(t, stateUsageGrowth(nil, current, 0))
+
+	growth := stateUsageGrowth(last, current, stateUsageCollectionInterval)
+	must.Eq(t, map[string]float64{"allocs": 2, "evals": -2}, growth)
+}
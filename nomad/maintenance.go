@@ -0,0 +1,168 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+const (
+	// maintenanceWindowInterval is how often the leader checks for
+	// maintenance windows that start or end.
+	maintenanceWindowInterval = 10 * time.Second
+)
+
+// runMaintenanceWindows periodically drains the nodes of the maintenance
+// windows that started, and marks the nodes of the windows that ended
+// eligible again. It runs until the stop channel is closed, when the server
+// loses leadership.
+func (s *Server) runMaintenanceWindows(stopCh chan struct{}) {
+	ticker := time.NewTicker(maintenanceWindowInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			s.applyMaintenanceWindows(time.Now())
+		}
+	}
+}
+
+// applyMaintenanceWindows starts and ends the maintenance windows according
+// to the given time.
+func (s *Server) applyMaintenanceWindows(now time.Time) {
+	snap, err := s.State().Snapshot()
+	if err != nil {
+		s.logger.Error("failed to get state for maintenance windows", "error", err)
+		return
+	}
+
+	iter, err := snap.MaintenanceWindows(nil)
+	if err != nil {
+		s.logger.Error("failed to list maintenance windows", "error", err)
+		return
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		window := raw.(*structs.MaintenanceWindow)
+
+		var err error
+		switch {
+		case window.Status == structs.MaintenanceWindowStatusComplete:
+			continue
+		case !now.Before(window.End):
+			err = s.endMaintenanceWindow(snap, window)
+		case !now.Before(window.Start):
+			err = s.startMaintenanceWindow(snap, window)
+		}
+		if err != nil {
+			s.logger.Error("failed to apply maintenance window", "window_id", window.ID, "error", err)
+		}
+	}
+}
+
+// startMaintenanceWindow drains the nodes of the window and marks the window
+// active. Nodes registered while the window is active are drained as well, but
+// nodes that were drained by the window and made eligible again by an
+// operator are left alone.
+func (s *Server) startMaintenanceWindow(snap *state.StateSnapshot, window *structs.MaintenanceWindow) error {
+	iter, err := snap.Nodes(nil)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if !window.Matches(node) || window.Drained(node) || node.DrainStrategy != nil {
+			continue
+		}
+
+		s.logger.Info("draining node for maintenance window", "node_id", node.ID, "window_id", window.ID)
+		if err := s.updateMaintenanceWindowDrain(window, node, window.DrainStrategy()); err != nil {
+			return err
+		}
+	}
+
+	if window.Status == structs.MaintenanceWindowStatusActive {
+		return nil
+	}
+	return s.setMaintenanceWindowStatus(window, structs.MaintenanceWindowStatusActive)
+}
+
+// endMaintenanceWindow marks the nodes drained by the window eligible again
+// and marks the window complete.
+func (s *Server) endMaintenanceWindow(snap *state.StateSnapshot, window *structs.MaintenanceWindow) error {
+	if err := s.enableMaintenanceWindowNodes(snap, window); err != nil {
+		return err
+	}
+	return s.setMaintenanceWindowStatus(window, structs.MaintenanceWindowStatusComplete)
+}
+
+// enableMaintenanceWindowNodes stops the drain of the nodes drained by the
+// window and marks them eligible for scheduling again.
+func (s *Server) enableMaintenanceWindowNodes(snap *state.StateSnapshot, window *structs.MaintenanceWindow) error {
+	iter, err := snap.Nodes(nil)
+	if err != nil {
+		return err
+	}
+
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		node := raw.(*structs.Node)
+		if !window.Drained(node) {
+			continue
+		}
+		if node.DrainStrategy == nil && node.SchedulingEligibility == structs.NodeSchedulingEligible {
+			continue
+		}
+
+		s.logger.Info("ending maintenance window of node", "node_id", node.ID, "window_id", window.ID)
+		if err := s.updateMaintenanceWindowDrain(window, node, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateMaintenanceWindowDrain sets the drain strategy of a node of the
+// window, or stops its drain and marks it eligible if the strategy is nil.
+func (s *Server) updateMaintenanceWindowDrain(window *structs.MaintenanceWindow, node *structs.Node,
+	strategy *structs.DrainStrategy) error {
+
+	req := &structs.NodeUpdateDrainRequest{
+		NodeID:        node.ID,
+		DrainStrategy: strategy,
+		MarkEligible:  strategy == nil,
+		Meta: map[string]string{
+			structs.MaintenanceWindowDrainMetaKey: window.ID,
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    s.Region(),
+			AuthToken: s.getLeaderAcl(),
+		},
+	}
+	var resp structs.NodeDrainUpdateResponse
+	if err := s.RPC("Node.UpdateDrain", req, &resp); err != nil {
+		return fmt.Errorf("failed to update drain of node %s: %w", node.ID, err)
+	}
+	return nil
+}
+
+// setMaintenanceWindowStatus records the new status of the window in raft.
+func (s *Server) setMaintenanceWindowStatus(window *structs.MaintenanceWindow, status string) error {
+	update := window.Copy()
+	update.Status = status
+	req := &structs.MaintenanceWindowUpsertRequest{
+		Windows: []*structs.MaintenanceWindow{update},
+	}
+	if _, _, err := s.raftApply(structs.MaintenanceWindowUpsertRequestType, req); err != nil {
+		return fmt.Errorf("failed to update maintenance window status: %w", err)
+	}
+	return nil
+}
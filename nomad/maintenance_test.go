@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+)
+
+func TestServer_applyMaintenanceWindows(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+	store := s1.fsm.State()
+
+	byClass := mock.Node()
+	byClass.NodeClass = "maintenance"
+	byID := mock.Node()
+	other := mock.Node()
+	for i, node := range []*structs.Node{byClass, byID, other} {
+		must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(1000+i), node))
+	}
+
+	window := mock.MaintenanceWindow()
+	window.NodeIDs = []string{byID.ID}
+	must.NoError(t, store.UpsertMaintenanceWindows(
+		structs.MsgTypeTestSetup, 1010, []*structs.MaintenanceWindow{window}))
+
+	getNode := func(id string) *structs.Node {
+		node, err := store.NodeByID(nil, id)
+		must.NoError(t, err)
+		return node
+	}
+	getWindow := func() *structs.MaintenanceWindow {
+		out, err := store.MaintenanceWindowByID(nil, window.ID)
+		must.NoError(t, err)
+		return out
+	}
+
+	// Nothing happens before the window starts
+	s1.applyMaintenanceWindows(window.Start.Add(-time.Minute))
+	must.Eq(t, structs.MaintenanceWindowStatusPending, getWindow().Status)
+	must.Eq(t, structs.NodeSchedulingEligible, getNode(byClass.ID).SchedulingEligibility)
+
+	// The nodes of the window are drained when it starts
+	s1.applyMaintenanceWindows(window.Start)
+	must.Eq(t, structs.MaintenanceWindowStatusActive, getWindow().Status)
+	for _, id := range []string{byClass.ID, byID.ID} {
+		node := getNode(id)
+		must.Eq(t, structs.NodeSchedulingIneligible, node.SchedulingEligibility)
+		must.NotNil(t, node.LastDrain)
+		must.Eq(t, window.ID, node.LastDrain.Meta[structs.MaintenanceWindowDrainMetaKey])
+	}
+	must.Eq(t, structs.NodeSchedulingEligible, getNode(other.ID).SchedulingEligibility)
+
+	// The nodes are eligible again when the window ends
+	s1.applyMaintenanceWindows(window.End)
+	must.Eq(t, structs.MaintenanceWindowStatusComplete, getWindow().Status)
+	for _, id := range []string{byClass.ID, byID.ID} {
+		node := getNode(id)
+		must.Nil(t, node.DrainStrategy)
+		must.Eq(t, structs.NodeSchedulingEligible, node.SchedulingEligibility)
+	}
+}
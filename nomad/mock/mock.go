@@ -262,6 +262,81 @@ func NodePool() *structs.NodePool {
 	return pool
 }
 
+// JobTemplate returns a job template with a random name. Its source declares
+// a required "image" variable and an optional "count" variable.
+func JobTemplate() *structs.JobTemplate {
+	return &structs.JobTemplate{
+		Name:        fmt.Sprintf("template-%s", uuid.Short()),
+		Description: "test job template",
+		Source: `
+variable "image" {
+  type = string
+}
+
+variable "count" {
+  type    = number
+  default = 1
+}
+
+job "web" {
+  group "web" {
+    count = var.count
+
+    task "server" {
+      driver = "docker"
+
+      config {
+        image = var.image
+      }
+    }
+  }
+}
+`,
+	}
+}
+
+// VariableGrant returns a variable grant with a random name sharing the
+// "shared/*" variables of the default namespace with the "team-a" namespace.
+func VariableGrant() *structs.VariableGrant {
+	return &structs.VariableGrant{
+		Name:        fmt.Sprintf("grant-%s", uuid.Short()),
+		Namespace:   structs.DefaultNamespace,
+		Description: "test variable grant",
+		Path:        "shared/*",
+		Namespaces:  []string{"team-a"},
+	}
+}
+
+// MaintenanceWindow returns a pending maintenance window of the nodes of
+// the "maintenance" class, which starts in an hour and lasts for an hour.
+func MaintenanceWindow() *structs.MaintenanceWindow {
+	now := time.Now().UTC().Truncate(time.Second)
+	return &structs.MaintenanceWindow{
+		ID:          uuid.Generate(),
+		Description: "test maintenance window",
+		NodeClasses: []string{"maintenance"},
+		Start:       now.Add(time.Hour),
+		End:         now.Add(2 * time.Hour),
+		Deadline:    30 * time.Minute,
+		Status:      structs.MaintenanceWindowStatusPending,
+	}
+}
+
+func EventSink() *structs.EventSink {
+	return &structs.EventSink{
+		ID:      fmt.Sprintf("sink-%s", uuid.Short()),
+		Type:    structs.EventSinkTypeWebhook,
+		Address: "http://127.0.0.1:8080/events",
+		Topics: map[structs.Topic][]string{
+			structs.TopicJob:  {"*"},
+			structs.TopicNode: {"*"},
+		},
+		Namespace:  "*",
+		MinBackoff: structs.DefaultEventSinkMinBackoff,
+		MaxBackoff: structs.DefaultEventSinkMaxBackoff,
+	}
+}
+
 // ServiceRegistrations generates an array containing two unique service
 // registrations.
 func ServiceRegistrations() []*structs.ServiceRegistration {
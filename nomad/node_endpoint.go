@@ -822,6 +822,12 @@ func (n *Node) UpdateDrain(args *structs.NodeUpdateDrainRequest,
 		args.NodeEvent = nil
 	}
 
+	// The leader updates drains on behalf of maintenance windows. Its ACL
+	// token isn't in the state store, so the FSM can't resolve it.
+	if args.AuthToken != "" && args.AuthToken == n.srv.getLeaderAcl() {
+		args.AuthToken = ""
+	}
+
 	// Commit this update via Raft
 	_, index, err := n.srv.raftApply(structs.NodeUpdateDrainRequestType, args)
 	if err != nil {
@@ -1421,6 +1427,14 @@ func (n *Node) UpdateAlloc(args *structs.AllocUpdateRequest, reply *structs.Gene
 			}
 		}
 
+		// Finished allocations of a job array with limited concurrency make
+		// room for the indices that have yet to run.
+		if evalTriggerBy == "" && taskGroup != nil && taskGroup.Array != nil &&
+			taskGroup.Array.Limit() < taskGroup.Array.Count &&
+			allocToUpdate.ClientTerminalStatus() {
+			evalTriggerBy = structs.EvalTriggerArrayProgress
+		}
+
 		var eval *structs.Evaluation
 		// If unknown, and not an orphan, set the trigger by.
 		if evalTriggerBy != structs.EvalTriggerJobDeregister &&
@@ -2285,3 +2299,39 @@ func (n *Node) EmitEvents(args *structs.EmitNodeEventsRequest, reply *structs.Em
 	reply.Index = index
 	return nil
 }
+
+// UpdateAllocUsage is used by clients to report the resource usage of their
+// running allocations to the usage accountant of the leader. The reply
+// indicates whether usage accounting is enabled.
+func (n *Node) UpdateAllocUsage(args *structs.AllocUsageRequest, reply *structs.AllocUsageResponse) error {
+	aclObj, err := n.srv.AuthenticateClientOnly(n.ctx, args)
+	n.srv.MeasureRPCRate("node", structs.RateMetricWrite, args)
+	if err != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	if done, err := n.srv.forward("Node.UpdateAllocUsage", args, args, reply); done {
+		return err
+	}
+	defer metrics.MeasureSince([]string{"nomad", "client", "update_alloc_usage"}, time.Now())
+
+	if !aclObj.AllowClientOp() {
+		return structs.ErrPermissionDenied
+	}
+
+	if args.NodeID == "" {
+		return fmt.Errorf("missing node ID")
+	}
+
+	// Clients may only report the usage of their own allocations.
+	if identity := args.GetIdentity(); identity == nil || identity.ClientID != args.NodeID {
+		return structs.ErrPermissionDenied
+	}
+
+	reply.Enabled = n.srv.usageAccountant.Enabled()
+	if !reply.Enabled || len(args.Usage) == 0 {
+		return nil
+	}
+
+	return n.srv.usageAccountant.RecordUsage(args.NodeID, args.Usage, args.Interval)
+}
@@ -4,11 +4,13 @@
 package nomad
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -20,6 +22,8 @@ import (
 
 	cstructs "github.com/hashicorp/nomad/client/structs"
 	"github.com/hashicorp/nomad/helper/snapshot"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -555,6 +559,535 @@ func (op *Operator) SchedulerGetConfiguration(args *structs.GenericRequest, repl
 	return nil
 }
 
+// StateUsage returns the number of objects and the estimated memory usage of
+// every table of the state store.
+func (op *Operator) StateUsage(args *structs.StateUsageRequest, reply *structs.StateUsageResponse) error {
+
+	authErr := op.srv.Authenticate(op.ctx, args)
+	if done, err := op.srv.forward("Operator.StateUsage", args, args, reply); done {
+		return err
+	}
+	op.srv.MeasureRPCRate("operator", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	snap, err := op.srv.fsm.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	tables, err := snap.TableStats()
+	if err != nil {
+		return err
+	}
+
+	reply.Tables = tables
+	reply.Index, _ = snap.LatestIndex()
+	op.srv.setQueryMeta(&reply.QueryMeta)
+
+	return nil
+}
+
+// MaintenanceWindowList returns the maintenance windows of the nodes, sorted
+// by start time.
+func (op *Operator) MaintenanceWindowList(args *structs.MaintenanceWindowListRequest, reply *structs.MaintenanceWindowListResponse) error {
+
+	authErr := op.srv.Authenticate(op.ctx, args)
+	if done, err := op.srv.forward("Operator.MaintenanceWindowList", args, args, reply); done {
+		return err
+	}
+	op.srv.MeasureRPCRate("operator", structs.RateMetricList, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			iter, err := store.MaintenanceWindows(ws)
+			if err != nil {
+				return err
+			}
+
+			windows := []*structs.MaintenanceWindow{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				windows = append(windows, raw.(*structs.MaintenanceWindow))
+			}
+			slices.SortStableFunc(windows, func(a, b *structs.MaintenanceWindow) int {
+				return a.Start.Compare(b.Start)
+			})
+			reply.Windows = windows
+
+			// Use the last index that affected the maintenance windows table.
+			index, err := store.Index(state.TableMaintenanceWindows)
+			if err != nil {
+				return err
+			}
+			reply.Index = max(1, index)
+
+			op.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return op.srv.blockingRPC(&opts)
+}
+
+// MaintenanceWindowUpsert creates or updates maintenance windows. Windows
+// without an ID are created. The status of the windows is managed by the
+// leader and can't be set.
+func (op *Operator) MaintenanceWindowUpsert(args *structs.MaintenanceWindowUpsertRequest, reply *structs.MaintenanceWindowUpsertResponse) error {
+
+	authErr := op.srv.Authenticate(op.ctx, args)
+	if done, err := op.srv.forward("Operator.MaintenanceWindowUpsert", args, args, reply); done {
+		return err
+	}
+	op.srv.MeasureRPCRate("operator", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// This action requires operator write access.
+	rule, err := op.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if !ServersMeetMinimumVersion(
+		op.srv.serf.Members(), op.srv.Region(), minMaintenanceWindowsVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to upsert maintenance windows", minMaintenanceWindowsVersion)
+	}
+
+	// Validate request.
+	if len(args.Windows) == 0 {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "must specify at least one maintenance window")
+	}
+	snap, err := op.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, window := range args.Windows {
+		if window.ID == "" {
+			window.ID = uuid.Generate()
+			window.Status = structs.MaintenanceWindowStatusPending
+			window.CreateIndex = 0
+		} else {
+			existing, err := snap.MaintenanceWindowByID(nil, window.ID)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				return structs.NewErrRPCCodedf(http.StatusNotFound, "maintenance window %q not found", window.ID)
+			}
+			if existing.Status == structs.MaintenanceWindowStatusComplete {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "maintenance window %q already ended", window.ID)
+			}
+			window.Status = existing.Status
+			window.CreateIndex = existing.CreateIndex
+		}
+		if err := window.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid maintenance window %q: %v", window.ID, err)
+		}
+	}
+
+	// Update via Raft.
+	_, index, err := op.srv.raftApply(structs.MaintenanceWindowUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+
+	for _, window := range args.Windows {
+		window.ModifyIndex = index
+		if window.CreateIndex == 0 {
+			window.CreateIndex = index
+		}
+	}
+	reply.Windows = args.Windows
+	reply.Index = index
+	return nil
+}
+
+// MaintenanceWindowDelete deletes maintenance windows. The nodes drained by
+// an active window are marked eligible again before the window is deleted.
+func (op *Operator) MaintenanceWindowDelete(args *structs.MaintenanceWindowDeleteRequest, reply *structs.GenericResponse) error {
+
+	authErr := op.srv.Authenticate(op.ctx, args)
+	if done, err := op.srv.forward("Operator.MaintenanceWindowDelete", args, args, reply); done {
+		return err
+	}
+	op.srv.MeasureRPCRate("operator", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// This action requires operator write access.
+	rule, err := op.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorWrite() {
+		return structs.ErrPermissionDenied
+	}
+
+	if !ServersMeetMinimumVersion(
+		op.srv.serf.Members(), op.srv.Region(), minMaintenanceWindowsVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to delete maintenance windows", minMaintenanceWindowsVersion)
+	}
+
+	// Validate request.
+	if len(args.IDs) == 0 {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "must specify at least one maintenance window to delete")
+	}
+	snap, err := op.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, id := range args.IDs {
+		window, err := snap.MaintenanceWindowByID(nil, id)
+		if err != nil {
+			return err
+		}
+		if window == nil {
+			return structs.NewErrRPCCodedf(http.StatusNotFound, "maintenance window %q not found", id)
+		}
+		if window.Status == structs.MaintenanceWindowStatusActive {
+			if err := op.srv.enableMaintenanceWindowNodes(snap, window); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Delete via Raft.
+	_, index, err := op.srv.raftApply(structs.MaintenanceWindowDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
+// ClientUpgradeList returns the client upgrades, sorted by creation.
+func (op *Operator) ClientUpgradeList(args *structs.ClientUpgradeListRequest, reply *structs.ClientUpgradeListResponse) error {
+
+	authErr := op.srv.Authenticate(op.ctx, args)
+	if done, err := op.srv.forward("Operator.ClientUpgradeList", args, args, reply); done {
+		return err
+	}
+	op.srv.MeasureRPCRate("operator", structs.RateMetricList, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			iter, err := store.ClientUpgrades(ws)
+			if err != nil {
+				return err
+			}
+
+			upgrades := []*structs.ClientUpgrade{}
+			for raw := iter.Next(); raw != nil; raw = iter.Next() {
+				upgrades = append(upgrades, raw.(*structs.ClientUpgrade))
+			}
+			slices.SortFunc(upgrades, func(a, b *structs.ClientUpgrade) int {
+				return cmp.Compare(a.CreateIndex, b.CreateIndex)
+			})
+			reply.Upgrades = upgrades
+
+			// Use the last index that affected the client upgrades table.
+			index, err := store.Index(state.TableClientUpgrades)
+			if err != nil {
+				return err
+			}
+			reply.Index = max(1, index)
+
+			op.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return op.srv.blockingRPC(&opts)
+}
+
+// ClientUpgradeGet returns a client upgrade, or nil if it doesn't exist.
+func (op *Operator) ClientUpgradeGet(args *structs.ClientUpgradeSpecificRequest, reply *structs.ClientUpgradeResponse) error {
+
+	authErr := op.srv.Authenticate(op.ctx, args)
+	if done, err := op.srv.forward("Operator.ClientUpgradeGet", args, args, reply); done {
+		return err
+	}
+	op.srv.MeasureRPCRate("operator", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// This action requires operator read access.
+	rule, err := op.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	} else if rule != nil && !rule.AllowOperatorRead() {
+		return structs.ErrPermissionDenied
+	}
+
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			upgrade, err := store.ClientUpgradeByID(ws, args.ID)
+			if err != nil {
+				return err
+			}
+			reply.Upgrade = upgrade
+
+			if upgrade != nil {
+				reply.Index = upgrade.ModifyIndex
+			} else {
+				index, err := store.Index(state.TableClientUpgrades)
+				if err != nil {
+					return err
+				}
+				reply.Index = max(1, index)
+			}
+
+			op.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return op.srv.blockingRPC(&opts)
+}
+
+// ClientUpgradeStart starts a rolling upgrade of the client agents. The nodes
+// of the upgrade are the ready nodes of its node pool that don't report its
+// version yet. Only one upgrade can be in progress at a time.
+func (op *Operator) ClientUpgradeStart(args *structs.ClientUpgradeStartRequest, reply *structs.ClientUpgradeResponse) error {
+
+	authErr := op.srv.Authenticate(op.ctx, args)
+	if done, err := op.srv.forward("Operator.ClientUpgradeStart", args, args, reply); done {
+		return err
+	}
+	op.srv.MeasureRPCRate("operator", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// This action requires operator and node write access, since the nodes
+	// are marked ineligible while they restart.
+	rule, err := op.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	} else if rule != nil && (!rule.AllowOperatorWrite() || !rule.AllowNodeWrite()) {
+		return structs.ErrPermissionDenied
+	}
+
+	if !ServersMeetMinimumVersion(
+		op.srv.serf.Members(), op.srv.Region(), minClientUpgradesVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to start client upgrades", minClientUpgradesVersion)
+	}
+
+	// Validate request.
+	if args.Upgrade == nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "missing client upgrade")
+	}
+	upgrade := args.Upgrade
+	upgrade.ID = uuid.Generate()
+	upgrade.Status = structs.ClientUpgradeStatusRunning
+	upgrade.StatusDescription = ""
+	upgrade.Nodes = nil
+	upgrade.CreateIndex = 0
+	if err := upgrade.Validate(); err != nil {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid client upgrade: %v", err)
+	}
+
+	op.srv.clientUpgradeLock.Lock()
+	defer op.srv.clientUpgradeLock.Unlock()
+
+	snap, err := op.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+
+	iter, err := snap.ClientUpgrades(nil)
+	if err != nil {
+		return err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if existing := raw.(*structs.ClientUpgrade); !existing.Terminal() {
+			return structs.NewErrRPCCodedf(http.StatusConflict,
+				"client upgrade %s is already %s", existing.ID, existing.Status)
+		}
+	}
+
+	if upgrade.NodePool != "" {
+		pool, err := snap.NodePoolByName(nil, upgrade.NodePool)
+		if err != nil {
+			return err
+		}
+		if pool == nil {
+			return structs.NewErrRPCCodedf(http.StatusNotFound, "node pool %q not found", upgrade.NodePool)
+		}
+	}
+
+	nodes, err := snap.Nodes(nil)
+	if err != nil {
+		return err
+	}
+	for raw := nodes.Next(); raw != nil; raw = nodes.Next() {
+		node := raw.(*structs.Node)
+		switch {
+		case upgrade.NodePool != "" && node.NodePool != upgrade.NodePool,
+			node.Status != structs.NodeStatusReady,
+			upgrade.Version != "" && node.Attributes[structs.ClientUpgradeVersionAttr] == upgrade.Version:
+			continue
+		}
+		upgrade.Nodes = append(upgrade.Nodes, &structs.ClientUpgradeNode{
+			NodeID: node.ID,
+			Name:   node.Name,
+			Status: structs.ClientUpgradeNodeStatusPending,
+		})
+	}
+
+	// Restart the nodes in a stable order, so the order is predictable for
+	// operators watching the upgrade.
+	slices.SortFunc(upgrade.Nodes, func(a, b *structs.ClientUpgradeNode) int {
+		if c := cmp.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.NodeID, b.NodeID)
+	})
+	if len(upgrade.Nodes) == 0 {
+		upgrade.Status = structs.ClientUpgradeStatusComplete
+		upgrade.StatusDescription = "no nodes to upgrade"
+	}
+
+	// Update via Raft.
+	req := &structs.ClientUpgradeUpsertRequest{
+		Upgrade:      upgrade,
+		WriteRequest: args.WriteRequest,
+	}
+	_, index, err := op.srv.raftApply(structs.ClientUpgradeUpsertRequestType, req)
+	if err != nil {
+		return err
+	}
+
+	upgrade.CreateIndex = index
+	upgrade.ModifyIndex = index
+	reply.Upgrade = upgrade
+	reply.Index = index
+	return nil
+}
+
+// ClientUpgradeUpdateStatus pauses, resumes or cancels a client upgrade. The
+// nodes the upgrade marked ineligible are marked eligible again when it is
+// paused or cancelled, and the nodes that failed are restarted again when it
+// is resumed.
+func (op *Operator) ClientUpgradeUpdateStatus(args *structs.ClientUpgradeUpdateStatusRequest, reply *structs.ClientUpgradeResponse) error {
+
+	authErr := op.srv.Authenticate(op.ctx, args)
+	if done, err := op.srv.forward("Operator.ClientUpgradeUpdateStatus", args, args, reply); done {
+		return err
+	}
+	op.srv.MeasureRPCRate("operator", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	// This action requires operator and node write access, since the nodes
+	// are marked eligible again.
+	rule, err := op.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	} else if rule != nil && (!rule.AllowOperatorWrite() || !rule.AllowNodeWrite()) {
+		return structs.ErrPermissionDenied
+	}
+
+	if !ServersMeetMinimumVersion(
+		op.srv.serf.Members(), op.srv.Region(), minClientUpgradesVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to update client upgrades", minClientUpgradesVersion)
+	}
+
+	op.srv.clientUpgradeLock.Lock()
+	defer op.srv.clientUpgradeLock.Unlock()
+
+	snap, err := op.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	existing, err := snap.ClientUpgradeByID(nil, args.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return structs.NewErrRPCCodedf(http.StatusNotFound, "client upgrade %q not found", args.ID)
+	}
+	if existing.Terminal() {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "client upgrade %q is already %s", args.ID, existing.Status)
+	}
+
+	upgrade := existing.Copy()
+	upgrade.Status = args.Status
+	upgrade.StatusDescription = args.Reason
+
+	var restoreErr error
+	switch args.Status {
+	case structs.ClientUpgradeStatusRunning:
+		upgrade.StatusDescription = ""
+		for _, n := range upgrade.NodesWithStatus(structs.ClientUpgradeNodeStatusFailed) {
+			n.Status = structs.ClientUpgradeNodeStatusPending
+			n.StatusDescription = ""
+		}
+	case structs.ClientUpgradeStatusPaused, structs.ClientUpgradeStatusCancelled:
+		if upgrade.StatusDescription == "" {
+			upgrade.StatusDescription = fmt.Sprintf("%s by operator", args.Status)
+		}
+
+		// Nodes that fail to be marked eligible now are retried by the leader.
+		_, restoreErr = op.srv.restoreClientUpgradeNodes(snap, upgrade)
+	default:
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid client upgrade status %q", args.Status)
+	}
+
+	// Update via Raft.
+	req := &structs.ClientUpgradeUpsertRequest{
+		Upgrade:      upgrade,
+		WriteRequest: args.WriteRequest,
+	}
+	_, index, err := op.srv.raftApply(structs.ClientUpgradeUpsertRequestType, req)
+	if err != nil {
+		return err
+	}
+	if restoreErr != nil {
+		op.logger.Warn("failed to mark nodes of client upgrade eligible", "upgrade_id", upgrade.ID, "error", restoreErr)
+	}
+
+	upgrade.ModifyIndex = index
+	reply.Upgrade = upgrade
+	reply.Index = index
+	return nil
+}
+
 func (op *Operator) forwardStreamingRPC(region string, method string, args interface{}, in io.ReadWriteCloser) error {
 	server, err := op.srv.findRegionServer(region)
 	if err != nil {
@@ -1369,3 +1369,218 @@ func TestOperator_UpgradeCheckRequest_VaultWorkloadIdentity_ACL(t *testing.T) {
 		})
 	}
 }
+
+func TestOperator_StateUsage(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	for i := 0; i < 3; i++ {
+		must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(1000+i), mock.Node()))
+	}
+	invalidToken := mock.CreatePolicyAndToken(t, state, 1003, "test-invalid", mock.NodePolicy(acl.PolicyWrite))
+	operatorToken := mock.CreatePolicyAndToken(t, state, 1004, "test-valid", `operator { policy = "read" }`)
+
+	arg := structs.StateUsageRequest{
+		QueryOptions: structs.QueryOptions{
+			Region: s1.config.Region,
+		},
+	}
+
+	// Try with no token and with an invalid token
+	var reply structs.StateUsageResponse
+	err := msgpackrpc.CallWithCodec(codec, "Operator.StateUsage", &arg, &reply)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	arg.AuthToken = invalidToken.SecretID
+	err = msgpackrpc.CallWithCodec(codec, "Operator.StateUsage", &arg, &reply)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Operator read access is sufficient
+	for _, token := range []string{operatorToken.SecretID, root.SecretID} {
+		arg.AuthToken = token
+		reply = structs.StateUsageResponse{}
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.StateUsage", &arg, &reply))
+		must.Positive(t, reply.Index)
+
+		tables := make(map[string]*structs.StateTableUsage, len(reply.Tables))
+		for _, table := range reply.Tables {
+			tables[table.Name] = table
+		}
+		must.MapContainsKeys(t, tables, []string{"nodes", "jobs", "allocs", "evals", "variables"})
+		must.Eq(t, 3, tables["nodes"].Objects)
+		must.Positive(t, tables["nodes"].EstimatedBytes)
+		must.Eq(t, 0, tables["allocs"].Objects)
+		must.Eq(t, 0, tables["allocs"].EstimatedBytes)
+	}
+}
+
+func TestOperator_MaintenanceWindows(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	readToken := mock.CreatePolicyAndToken(t, state, 1000, "test-read", `operator { policy = "read" }`)
+
+	later := mock.MaintenanceWindow()
+	later.ID = ""
+	later.Start = later.Start.Add(time.Hour)
+	later.End = later.End.Add(time.Hour)
+	sooner := mock.MaintenanceWindow()
+	sooner.ID = ""
+	sooner.Status = structs.MaintenanceWindowStatusComplete
+
+	upsert := &structs.MaintenanceWindowUpsertRequest{
+		Windows: []*structs.MaintenanceWindow{later, sooner},
+		WriteRequest: structs.WriteRequest{
+			Region:    s1.config.Region,
+			AuthToken: readToken.SecretID,
+		},
+	}
+
+	// Writing requires operator write access
+	var upsertResp structs.MaintenanceWindowUpsertResponse
+	err := msgpackrpc.CallWithCodec(codec, "Operator.MaintenanceWindowUpsert", upsert, &upsertResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Created windows get an ID and are pending
+	upsert.AuthToken = root.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.MaintenanceWindowUpsert", upsert, &upsertResp))
+	must.Len(t, 2, upsertResp.Windows)
+	for _, window := range upsertResp.Windows {
+		must.UUIDv4(t, window.ID)
+		must.Eq(t, structs.MaintenanceWindowStatusPending, window.Status)
+	}
+	later.ID, sooner.ID = upsertResp.Windows[0].ID, upsertResp.Windows[1].ID
+
+	// Invalid windows are rejected
+	invalid := mock.MaintenanceWindow()
+	invalid.ID = ""
+	invalid.End = invalid.Start
+	upsert.Windows = []*structs.MaintenanceWindow{invalid}
+	err = msgpackrpc.CallWithCodec(codec, "Operator.MaintenanceWindowUpsert", upsert, &upsertResp)
+	must.ErrorContains(t, err, "end time must be after the start time")
+
+	// The windows are listed by start time with operator read access
+	list := &structs.MaintenanceWindowListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    s1.config.Region,
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var listResp structs.MaintenanceWindowListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.MaintenanceWindowList", list, &listResp))
+	must.Len(t, 2, listResp.Windows)
+	must.Eq(t, sooner.ID, listResp.Windows[0].ID)
+	must.Eq(t, later.ID, listResp.Windows[1].ID)
+
+	// Deleting an active window marks the nodes it drained eligible again
+	node := mock.DrainNode()
+	node.LastDrain = &structs.DrainMetadata{
+		Status: structs.DrainStatusDraining,
+		Meta:   map[string]string{structs.MaintenanceWindowDrainMetaKey: sooner.ID},
+	}
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1100, node))
+	active := sooner.Copy()
+	active.Status = structs.MaintenanceWindowStatusActive
+	must.NoError(t, state.UpsertMaintenanceWindows(
+		structs.MsgTypeTestSetup, 1101, []*structs.MaintenanceWindow{active}))
+
+	del := &structs.MaintenanceWindowDeleteRequest{
+		IDs: []string{sooner.ID},
+		WriteRequest: structs.WriteRequest{
+			Region:    s1.config.Region,
+			AuthToken: root.SecretID,
+		},
+	}
+	var delResp structs.GenericResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.MaintenanceWindowDelete", del, &delResp))
+
+	out, err := state.NodeByID(nil, node.ID)
+	must.NoError(t, err)
+	must.Nil(t, out.DrainStrategy)
+	must.Eq(t, structs.NodeSchedulingEligible, out.SchedulingEligibility)
+
+	window, err := state.MaintenanceWindowByID(nil, sooner.ID)
+	must.NoError(t, err)
+	must.Nil(t, window)
+
+	err = msgpackrpc.CallWithCodec(codec, "Operator.MaintenanceWindowDelete", del, &delResp)
+	must.ErrorContains(t, err, "not found")
+}
+
+func TestOperator_ClientUpgrades_ACL(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, root, cleanupS1 := TestACLServer(t, nil)
+	defer cleanupS1()
+	codec := rpcClient(t, s1)
+	testutil.WaitForLeader(t, s1.RPC)
+	state := s1.fsm.State()
+
+	node := mock.Node()
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+
+	readToken := mock.CreatePolicyAndToken(t, state, 1001, "test-read", `operator { policy = "read" }`)
+	operatorToken := mock.CreatePolicyAndToken(t, state, 1002, "test-operator", `operator { policy = "write" }`)
+
+	start := &structs.ClientUpgradeStartRequest{
+		Upgrade: &structs.ClientUpgrade{
+			Version:       "2.0.0",
+			MaxParallel:   1,
+			HealthTimeout: time.Minute,
+		},
+		WriteRequest: structs.WriteRequest{
+			Region:    s1.config.Region,
+			AuthToken: operatorToken.SecretID,
+		},
+	}
+
+	// Starting an upgrade marks nodes ineligible, so it also requires node
+	// write access
+	var startResp structs.ClientUpgradeResponse
+	err := msgpackrpc.CallWithCodec(codec, "Operator.ClientUpgradeStart", start, &startResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	start.AuthToken = root.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.ClientUpgradeStart", start, &startResp))
+	must.Eq(t, structs.ClientUpgradeStatusRunning, startResp.Upgrade.Status)
+	must.Len(t, 1, startResp.Upgrade.Nodes)
+
+	// The upgrades are listed with operator read access
+	list := &structs.ClientUpgradeListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    s1.config.Region,
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var listResp structs.ClientUpgradeListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.ClientUpgradeList", list, &listResp))
+	must.Len(t, 1, listResp.Upgrades)
+	must.Eq(t, startResp.Upgrade.ID, listResp.Upgrades[0].ID)
+
+	update := &structs.ClientUpgradeUpdateStatusRequest{
+		ID:     startResp.Upgrade.ID,
+		Status: structs.ClientUpgradeStatusPaused,
+		WriteRequest: structs.WriteRequest{
+			Region:    s1.config.Region,
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var updateResp structs.ClientUpgradeResponse
+	err = msgpackrpc.CallWithCodec(codec, "Operator.ClientUpgradeUpdateStatus", update, &updateResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	update.AuthToken = root.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Operator.ClientUpgradeUpdateStatus", update, &updateResp))
+	must.Eq(t, structs.ClientUpgradeStatusPaused, updateResp.Upgrade.Status)
+	must.Eq(t, "paused by operator", updateResp.Upgrade.StatusDescription)
+}
@@ -13,6 +13,7 @@ import (
 	log "github.com/hashicorp/go-hclog"
 	memdb "github.com/hashicorp/go-memdb"
 	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -125,6 +126,15 @@ func (p *planner) planApply() {
 			return
 		}
 
+		// Record the time the plan waited in the queue and trace applying
+		// it, which ends when the plan is responded to.
+		p.srv.tracer.StartAt("plan.queue_wait", pending.plan.TraceParent, pending.enqueueTime,
+			tracing.String("nomad.eval.id", pending.plan.EvalID),
+		).End(nil)
+		pending.span = p.srv.tracer.Start("plan.apply", pending.plan.TraceParent,
+			tracing.String("nomad.eval.id", pending.plan.EvalID),
+		)
+
 		// If last plan has completed get a new snapshot
 		select {
 		case idx := <-planIndexCh:
@@ -162,7 +172,9 @@ func (p *planner) planApply() {
 		}
 
 		// Evaluate the plan
+		evalSpan := pending.span.Child("plan.evaluate")
 		result, err := evaluatePlan(pool, snap, pending.plan, p.srv.logger)
+		evalSpan.End(err)
 		if err != nil {
 			p.srv.logger.Error("failed to evaluate plan", "error", err)
 			pending.respond(nil, err)
@@ -28,7 +28,10 @@ func TestPlanNormalize(t *testing.T) {
 	// The newer format uses OmitEmpty and uses a minimal set of fields for the diff of the
 	// stopped and preempted allocs. The file for the older format hasn't been checked in, because
 	// it's not a good idea to check-in a 20mb file to the git repo.
-	unoptimizedLogSize := 19460168
+	//
+	// The fields added to allocations since then, such as exclusive cores,
+	// add 84 bytes to each of the 20000 allocations of the older format.
+	unoptimizedLogSize := 19460168 + 20000*84
 
 	numUpdatedAllocs := 10000
 	numStoppedAllocs := 8000
@@ -11,6 +11,7 @@ import (
 
 	metrics "github.com/armon/go-metrics"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/nomad/structs"
 )
 
@@ -58,6 +59,9 @@ type pendingPlan struct {
 	enqueueTime time.Time
 	result      *structs.PlanResult
 	errCh       chan error
+
+	// span traces applying the plan. It is nil if tracing is disabled.
+	span *tracing.Span
 }
 
 // Wait is used to block for the plan result or potential error
@@ -69,6 +73,19 @@ func (p *pendingPlan) Wait() (*structs.PlanResult, error) {
 // respond is used to set the response and error for the future
 func (p *pendingPlan) respond(result *structs.PlanResult, err error) {
 	p.result = result
+	if result != nil {
+		placed := 0
+		for _, allocs := range result.NodeAllocation {
+			placed += len(allocs)
+		}
+		p.span.SetAttributes(
+			tracing.Int("nomad.plan.placed_allocs", placed),
+			tracing.Int("nomad.plan.placed_nodes", len(result.NodeAllocation)),
+			tracing.Int("nomad.plan.rejected_nodes", len(result.RejectedNodes)),
+			tracing.Int("nomad.plan.alloc_index", int(result.AllocIndex)),
+		)
+	}
+	p.span.End(err)
 	p.errCh <- err
 }
 
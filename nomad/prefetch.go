@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"sync"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+)
+
+const (
+	// maxParallelPrefetches is the maximum number of clients the leader asks
+	// to prefetch the images and artifacts of a job at the same time.
+	maxParallelPrefetches = 32
+)
+
+// prefetchJob makes the eligible clients pull the images and artifacts of a
+// registered job, so the allocations placed on them later, like the ones of
+// the next batches of a rolling update, don't wait for the downloads.
+// Failures are only logged as the allocations download what they need anyway.
+func (s *Server) prefetchJob(job *structs.Job) {
+	req := structs.NewPrefetchJobRequest(job)
+	if req == nil {
+		return
+	}
+
+	logger := s.logger.With("job_id", job.ID, "namespace", job.Namespace)
+
+	snap, err := s.State().Snapshot()
+	if err != nil {
+		logger.Error("failed to get state for job prefetch", "error", err)
+		return
+	}
+	nodeIDs, err := scheduler.PrefetchNodes(snap, job)
+	if err != nil {
+		logger.Error("failed to find nodes for job prefetch", "error", err)
+		return
+	}
+
+	logger.Debug("prefetching job images and artifacts", "nodes", len(nodeIDs),
+		"images", len(req.Images), "artifacts", len(req.Artifacts))
+
+	sem := make(chan struct{}, maxParallelPrefetches)
+	var wg sync.WaitGroup
+	for _, nodeID := range nodeIDs {
+		nodeReq := *req
+		nodeReq.NodeID = nodeID
+		nodeReq.Region = s.Region()
+		nodeReq.AuthToken = s.getLeaderAcl()
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			var resp structs.GenericResponse
+			if err := s.RPC("Prefetch.Job", &nodeReq, &resp); err != nil {
+				logger.Debug("failed to prefetch job on node", "node_id", nodeReq.NodeID, "error", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
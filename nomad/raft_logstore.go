@@ -0,0 +1,272 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
+	raftwal "github.com/hashicorp/raft-wal"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// RaftLogStoreBoltDB stores the raft logs in a BoltDB file.
+	RaftLogStoreBoltDB = "boltdb"
+
+	// RaftLogStoreWAL stores the raft logs in a write-ahead log of segment
+	// files.
+	RaftLogStoreWAL = "wal"
+
+	// raftBoltFile is the name of the BoltDB log store file in the raft
+	// directory
+	raftBoltFile = "raft.db"
+
+	// raftWALDir is the name of the WAL log store directory in the raft
+	// directory
+	raftWALDir = "wal"
+
+	// raftMigratedSuffix is appended to the name of a log store after its
+	// contents were migrated to another backend
+	raftMigratedSuffix = ".migrated"
+
+	// DefaultRaftWALSegmentSizeMB is the default size of a WAL segment file
+	DefaultRaftWALSegmentSizeMB = 64
+
+	// raftMigrateBatchSize is the number of logs copied at once when
+	// migrating between log stores
+	raftMigrateBatchSize = 1024
+)
+
+// raftStableKeys are the keys raft persists in its stable store.
+var raftStableKeys = []string{"CurrentTerm", "LastVoteTerm", "LastVoteCand"}
+
+// raftUint64StableKeys are the raftStableKeys holding uint64 values.
+var raftUint64StableKeys = map[string]bool{"CurrentTerm": true, "LastVoteTerm": true}
+
+// raftLogStore is the durable log and stable store used by raft.
+type raftLogStore interface {
+	raft.LogStore
+	raft.StableStore
+	io.Closer
+}
+
+// openRaftLogStore opens the raft log store backend configured for the server
+// in the raft directory. If the directory contains the store of the other
+// backend, its contents are migrated to the configured backend first.
+func (s *Server) openRaftLogStore(path string) (raftLogStore, error) {
+	boltPath := filepath.Join(path, raftBoltFile)
+	walPath := filepath.Join(path, raftWALDir)
+
+	boltExists, err := pathExists(boltPath)
+	if err != nil {
+		return nil, err
+	}
+	walExists, err := pathExists(walPath)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := s.config.RaftLogStoreBackend
+	switch backend {
+	case "", RaftLogStoreBoltDB:
+		backend = RaftLogStoreBoltDB
+	case RaftLogStoreWAL:
+	default:
+		return nil, fmt.Errorf("unknown raft log store backend %q", backend)
+	}
+
+	if boltExists && walExists {
+		return nil, fmt.Errorf("raft directory %s contains both a %q and a %q log store; "+
+			"remove the one not in use", path, RaftLogStoreBoltDB, RaftLogStoreWAL)
+	}
+
+	switch {
+	case backend == RaftLogStoreWAL && boltExists:
+		return s.migrateRaftLogStore(boltPath, RaftLogStoreBoltDB, walPath, RaftLogStoreWAL)
+	case backend == RaftLogStoreBoltDB && walExists:
+		return s.migrateRaftLogStore(walPath, RaftLogStoreWAL, boltPath, RaftLogStoreBoltDB)
+	case backend == RaftLogStoreWAL:
+		return s.openRaftWAL(walPath)
+	default:
+		return s.openRaftBolt(boltPath)
+	}
+}
+
+// openRaftBolt opens the BoltDB log store at path.
+func (s *Server) openRaftBolt(path string) (*raftboltdb.BoltStore, error) {
+	store, err := raftboltdb.New(raftboltdb.Options{
+		Path:   path,
+		NoSync: false, // fsync each log write
+		BoltOptions: &bbolt.Options{
+			NoFreelistSync: s.config.RaftBoltNoFreelistSync,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("setting up raft bolt store", "no_freelist_sync", s.config.RaftBoltNoFreelistSync)
+
+	// Start publishing bboltdb metrics
+	go store.RunMetrics(s.shutdownCtx, 0)
+
+	return store, nil
+}
+
+// openRaftWAL opens the WAL log store in the directory at path.
+func (s *Server) openRaftWAL(path string) (*raftwal.WAL, error) {
+	if err := ensurePath(path, true); err != nil {
+		return nil, err
+	}
+
+	segmentSizeMB := s.config.RaftWALSegmentSizeMB
+	if segmentSizeMB <= 0 {
+		segmentSizeMB = DefaultRaftWALSegmentSizeMB
+	}
+
+	store, err := raftwal.Open(path,
+		raftwal.WithLogger(s.logger.Named("raft-wal")),
+		raftwal.WithSegmentSize(segmentSizeMB*1024*1024),
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("setting up raft wal store", "segment_size_mb", segmentSizeMB)
+	return store, nil
+}
+
+// openRaftBackend opens the log store of the backend at path.
+func (s *Server) openRaftBackend(path, backend string) (raftLogStore, error) {
+	if backend == RaftLogStoreWAL {
+		return s.openRaftWAL(path)
+	}
+	return s.openRaftBolt(path)
+}
+
+// migrateRaftLogStore copies the logs and stable state of the log store at
+// srcPath into a new log store at dstPath, and renames the source store so
+// it's no longer used. The new log store is returned open.
+func (s *Server) migrateRaftLogStore(srcPath, srcBackend, dstPath, dstBackend string) (raftLogStore, error) {
+	logger := s.logger.With("from", srcBackend, "to", dstBackend)
+	logger.Info("migrating raft log store")
+
+	src, err := s.openRaftBackend(srcPath, srcBackend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s log store: %v", srcBackend, err)
+	}
+
+	// A failed migration leaves the source untouched and removes the partial
+	// destination, so it's retried on the next start.
+	dst, err := s.openRaftBackend(dstPath, dstBackend)
+	if err != nil {
+		src.Close()
+		return nil, fmt.Errorf("failed to open %s log store: %v", dstBackend, err)
+	}
+
+	copied, err := copyRaftLogStore(logger, src, dst)
+	if closeErr := src.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close %s log store: %v", srcBackend, closeErr)
+	}
+	if err == nil {
+		// Replace the store left by any earlier migration
+		err = os.RemoveAll(srcPath + raftMigratedSuffix)
+	}
+	if err == nil {
+		err = os.Rename(srcPath, srcPath+raftMigratedSuffix)
+	}
+	if err != nil {
+		dst.Close()
+		if rmErr := os.RemoveAll(dstPath); rmErr != nil {
+			logger.Error("failed to remove partially migrated log store", "path", dstPath, "error", rmErr)
+		}
+		return nil, fmt.Errorf("failed to migrate raft log store from %s to %s: %v", srcBackend, dstBackend, err)
+	}
+
+	logger.Info("migrated raft log store", "logs", copied, "previous_store", srcPath+raftMigratedSuffix)
+	return dst, nil
+}
+
+// copyRaftLogStore copies all the logs and the stable state from src to dst,
+// and returns the number of logs copied.
+func copyRaftLogStore(logger hclog.Logger, src, dst raftLogStore) (uint64, error) {
+	for _, key := range raftStableKeys {
+		if raftUint64StableKeys[key] {
+			v, err := src.GetUint64([]byte(key))
+			if err != nil && !isNotFound(err) {
+				return 0, fmt.Errorf("failed to read %s: %v", key, err)
+			}
+			if err := dst.SetUint64([]byte(key), v); err != nil {
+				return 0, fmt.Errorf("failed to write %s: %v", key, err)
+			}
+			continue
+		}
+
+		v, err := src.Get([]byte(key))
+		if err != nil && !isNotFound(err) {
+			return 0, fmt.Errorf("failed to read %s: %v", key, err)
+		}
+		if err := dst.Set([]byte(key), v); err != nil {
+			return 0, fmt.Errorf("failed to write %s: %v", key, err)
+		}
+	}
+
+	first, err := src.FirstIndex()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read first index: %v", err)
+	}
+	last, err := src.LastIndex()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read last index: %v", err)
+	}
+	if last == 0 {
+		return 0, nil
+	}
+
+	var copied uint64
+	batch := make([]*raft.Log, 0, raftMigrateBatchSize)
+	for i := first; i <= last; i++ {
+		log := new(raft.Log)
+		if err := src.GetLog(i, log); err != nil {
+			return copied, fmt.Errorf("failed to read log at index %d: %v", i, err)
+		}
+		batch = append(batch, log)
+
+		if len(batch) == raftMigrateBatchSize || i == last {
+			if err := dst.StoreLogs(batch); err != nil {
+				return copied, fmt.Errorf("failed to write logs up to index %d: %v", i, err)
+			}
+			copied += uint64(len(batch))
+			batch = batch[:0]
+			logger.Debug("migrated raft logs", "index", i, "last_index", last)
+		}
+	}
+
+	return copied, nil
+}
+
+// isNotFound returns whether the error of a stable store read is because the
+// key doesn't exist. Like raft itself, this also matches the error message for
+// stores that don't use the BoltDB error.
+func isNotFound(err error) bool {
+	return errors.Is(err, raftboltdb.ErrKeyNotFound) || err.Error() == "not found"
+}
+
+// pathExists returns whether a file or directory exists at path.
+func pathExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	switch {
+	case err == nil:
+		return true, nil
+	case os.IsNotExist(err):
+		return false, nil
+	default:
+		return false, err
+	}
+}
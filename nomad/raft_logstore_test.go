@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/raft"
+	"github.com/shoenig/test/must"
+)
+
+func testRaftLogStoreServer(t *testing.T, backend string) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	return &Server{
+		config: &Config{
+			RaftLogStoreBackend: backend,
+		},
+		logger:      testlog.HCLogger(t),
+		shutdownCtx: ctx,
+	}
+}
+
+func TestServer_openRaftLogStore_Migrate(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+
+	// Write some state with the default backend
+	store, err := testRaftLogStoreServer(t, "").openRaftLogStore(dir)
+	must.NoError(t, err)
+
+	var logs []*raft.Log
+	for i := uint64(1); i <= raftMigrateBatchSize+10; i++ {
+		logs = append(logs, &raft.Log{Index: i, Term: 2, Type: raft.LogCommand, Data: []byte(fmt.Sprintf("log-%d", i))})
+	}
+	must.NoError(t, store.StoreLogs(logs))
+	must.NoError(t, store.SetUint64([]byte("CurrentTerm"), 2))
+	must.NoError(t, store.Set([]byte("LastVoteCand"), []byte("server-1")))
+	must.NoError(t, store.Close())
+
+	assertState := func(store raftLogStore) {
+		t.Helper()
+
+		first, err := store.FirstIndex()
+		must.NoError(t, err)
+		must.Eq(t, 1, first)
+		last, err := store.LastIndex()
+		must.NoError(t, err)
+		must.Eq(t, raftMigrateBatchSize+10, last)
+
+		var log raft.Log
+		must.NoError(t, store.GetLog(raftMigrateBatchSize+5, &log))
+		must.Eq(t, []byte(fmt.Sprintf("log-%d", raftMigrateBatchSize+5)), log.Data)
+
+		term, err := store.GetUint64([]byte("CurrentTerm"))
+		must.NoError(t, err)
+		must.Eq(t, 2, term)
+		cand, err := store.Get([]byte("LastVoteCand"))
+		must.NoError(t, err)
+		must.Eq(t, []byte("server-1"), cand)
+	}
+
+	// Switching to the WAL migrates the state
+	store, err = testRaftLogStoreServer(t, RaftLogStoreWAL).openRaftLogStore(dir)
+	must.NoError(t, err)
+	assertState(store)
+	must.NoError(t, store.Close())
+
+	must.FileNotExists(t, filepath.Join(dir, raftBoltFile))
+	must.FileExists(t, filepath.Join(dir, raftBoltFile+raftMigratedSuffix))
+
+	// Switching back migrates the state again
+	store, err = testRaftLogStoreServer(t, RaftLogStoreBoltDB).openRaftLogStore(dir)
+	must.NoError(t, err)
+	assertState(store)
+	must.NoError(t, store.Close())
+
+	must.DirNotExists(t, filepath.Join(dir, raftWALDir))
+	must.DirExists(t, filepath.Join(dir, raftWALDir+raftMigratedSuffix))
+}
+
+func TestServer_openRaftLogStore_Errors(t *testing.T) {
+	ci.Parallel(t)
+
+	dir := t.TempDir()
+	_, err := testRaftLogStoreServer(t, "raw").openRaftLogStore(dir)
+	must.ErrorContains(t, err, `unknown raft log store backend "raw"`)
+
+	must.NoError(t, os.WriteFile(filepath.Join(dir, raftBoltFile), nil, 0o644))
+	must.NoError(t, os.Mkdir(filepath.Join(dir, raftWALDir), 0o755))
+	_, err = testRaftLogStoreServer(t, RaftLogStoreWAL).openRaftLogStore(dir)
+	must.ErrorContains(t, err, "contains both")
+}
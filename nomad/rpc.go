@@ -28,6 +28,7 @@ import (
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
 	"github.com/hashicorp/raft"
+	"github.com/hashicorp/serf/serf"
 	"github.com/hashicorp/yamux"
 )
 
@@ -442,7 +443,7 @@ func (r *rpcHandler) handleMultiplex(ctx context.Context, conn net.Conn, rpcCtx
 // handleNomadConn is used to service a single Nomad RPC connection
 func (r *rpcHandler) handleNomadConn(ctx context.Context, conn net.Conn, server *rpc.Server) {
 	defer conn.Close()
-	rpcCodec := pool.NewServerCodec(conn)
+	rpcCodec := r.srv.wrapTracing(r.srv.wrapFlightRecorder(pool.NewServerCodec(conn)))
 	for {
 		select {
 		case <-ctx.Done():
@@ -588,6 +589,15 @@ func (r *rpcHandler) forward(method string, info structs.RPCInfo, args interface
 
 	// Check if we can allow a stale read
 	if info.IsRead() && info.AllowStaleRead() {
+		// Forward to a read replica if the request prefers one and we
+		// aren't one, or serve it locally if the region has none
+		if info.PreferReadReplica() && !r.srv.config.ReadReplica {
+			if replica := r.findReadReplica(); replica != nil {
+				info.SetForwarded()
+				err := r.forwardServer(replica, method, args, reply)
+				return true, err
+			}
+		}
 		return false, nil
 	}
 
@@ -707,6 +717,24 @@ func (r *rpcHandler) findRegionServer(region string) (*serverParts, error) {
 	return servers[offset], nil
 }
 
+// findReadReplica returns a random alive read replica of the local region, or
+// nil if there are none.
+func (r *rpcHandler) findReadReplica() *serverParts {
+	r.srv.peerLock.RLock()
+	defer r.srv.peerLock.RUnlock()
+
+	var replicas []*serverParts
+	for _, server := range r.srv.peers[r.srv.config.Region] {
+		if server.ReadReplica && server.Status == serf.StatusAlive {
+			replicas = append(replicas, server)
+		}
+	}
+	if len(replicas) == 0 {
+		return nil
+	}
+	return replicas[rand.Intn(len(replicas))]
+}
+
 // forwardRegion is used to forward an RPC call to a remote region, or fail if no servers
 func (r *rpcHandler) forwardRegion(region, method string, args interface{}, reply interface{}) error {
 	server, err := r.findRegionServer(region)
@@ -192,6 +192,47 @@ func TestRPC_forwardRegion(t *testing.T) {
 	}
 }
 
+func TestRPC_forwardReadReplica(t *testing.T) {
+	ci.Parallel(t)
+
+	s1, cleanupS1 := TestServer(t, nil)
+	defer cleanupS1()
+	testutil.WaitForLeader(t, s1.RPC)
+
+	// Without read replicas the request is served locally
+	must.Nil(t, s1.findReadReplica())
+	req := &structs.NodeListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", ReadReplica: true},
+	}
+	var resp structs.NodeListResponse
+	must.NoError(t, s1.RPC("Node.List", req, &resp))
+
+	s2, cleanupS2 := TestServer(t, func(c *Config) {
+		c.BootstrapExpect = 0
+		c.ReadReplica = true
+	})
+	defer cleanupS2()
+	TestJoin(t, s1, s2)
+
+	testutil.WaitForResult(func() (bool, error) {
+		replica := s1.findReadReplica()
+		if replica == nil {
+			return false, fmt.Errorf("read replica not found")
+		}
+		return replica.ID == s2.config.NodeID, nil
+	}, func(err error) { must.NoError(t, err) })
+
+	// Local RPCs copy their arguments, so the request is forwarded directly
+	// to check that it was marked as forwarded
+	req = &structs.NodeListRequest{
+		QueryOptions: structs.QueryOptions{Region: "global", ReadReplica: true},
+	}
+	done, err := s1.forward("Node.List", req, req, &resp)
+	must.NoError(t, err)
+	must.True(t, done)
+	must.True(t, req.IsForwarded())
+}
+
 func TestRPC_getServer(t *testing.T) {
 	ci.Parallel(t)
 
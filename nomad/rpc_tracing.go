@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"errors"
+	"net/rpc"
+	"time"
+
+	"github.com/hashicorp/nomad/helper/tracing"
+)
+
+// tracedRequest is implemented by RPC requests that embed the
+// structs.InternalRpcInfo, which carries the trace context between servers.
+type tracedRequest interface {
+	GetTraceParent() string
+	SetTraceParent(string)
+	TimeToBlock() time.Duration
+}
+
+// tracingCodec wraps a server codec to trace the RPCs it serves. The span of
+// an RPC continues the trace of the caller, and replaces the trace context of
+// the request so the handler and any server the RPC is forwarded to continue
+// the trace from it.
+type tracingCodec struct {
+	rpc.ServerCodec
+	tracer *tracing.Tracer
+
+	// method and span describe the RPC being served.
+	method string
+	span   *tracing.Span
+}
+
+// wrapTracing wraps the codec to trace the RPCs it serves if tracing is
+// enabled.
+func (s *Server) wrapTracing(codec rpc.ServerCodec) rpc.ServerCodec {
+	if s.tracer == nil {
+		return codec
+	}
+	return &tracingCodec{ServerCodec: codec, tracer: s.tracer}
+}
+
+func (c *tracingCodec) ReadRequestHeader(r *rpc.Request) error {
+	err := c.ServerCodec.ReadRequestHeader(r)
+	c.method = r.ServiceMethod
+	c.span = nil
+	return err
+}
+
+func (c *tracingCodec) ReadRequestBody(body any) error {
+	if err := c.ServerCodec.ReadRequestBody(body); err != nil {
+		return err
+	}
+
+	// Blocking queries mostly wait for changes, so they aren't traced.
+	req, ok := body.(tracedRequest)
+	if !ok || req.TimeToBlock() > 0 {
+		return nil
+	}
+	c.span = c.tracer.StartRPC(c.method, req.GetTraceParent())
+	req.SetTraceParent(c.span.TraceParent())
+	return nil
+}
+
+func (c *tracingCodec) WriteResponse(r *rpc.Response, body any) error {
+	var rpcErr error
+	if r.Error != "" {
+		rpcErr = errors.New(r.Error)
+	}
+	c.span.End(rpcErr)
+	c.span = nil
+	return c.ServerCodec.WriteResponse(r, body)
+}
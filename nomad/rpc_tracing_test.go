@@ -0,0 +1,119 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/otlp"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestRPC_Tracing(t *testing.T) {
+	ci.Parallel(t)
+
+	var l sync.Mutex
+	spans := map[string]*otlp.Span{}
+	byName := map[string][]*otlp.Span{}
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req otlp.ExportTraceServiceRequest
+		must.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		l.Lock()
+		defer l.Unlock()
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, span := range ss.Spans {
+					spans[span.SpanID] = span
+					byName[span.Name] = append(byName[span.Name], span)
+				}
+			}
+		}
+	}))
+	defer collector.Close()
+
+	// Forwarding is covered by sending the RPC to a follower, which sends it
+	// on to the leader with the trace context of its RPC span.
+	tracing := func(c *Config) {
+		c.NumSchedulers = 0
+		c.BootstrapExpect = 2
+		c.Tracing = &config.TracingConfig{
+			Enabled:        pointer.Of(true),
+			Address:        collector.URL,
+			ExportInterval: 50 * time.Millisecond,
+		}
+	}
+	s1, cleanupS1 := TestServer(t, tracing)
+	defer cleanupS1()
+	s2, cleanupS2 := TestServer(t, tracing)
+	defer cleanupS2()
+	TestJoin(t, s1, s2)
+	testutil.WaitForLeader(t, s1.RPC)
+	testutil.WaitForLeader(t, s2.RPC)
+
+	follower := s1
+	if s1.IsLeader() {
+		follower = s2
+	}
+
+	job := mock.Job()
+	req := &structs.JobRegisterRequest{
+		Job: job,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: job.Namespace,
+			InternalRpcInfo: structs.InternalRpcInfo{
+				TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			},
+		},
+	}
+	var resp structs.JobRegisterResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(rpcClient(t, follower), "Job.Register", req, &resp))
+
+	// The follower's RPC span, the leader's forwarded RPC span, the handler
+	// span and the eval broker span all belong to the caller's trace.
+	var handlerSpan, enqueueSpan *otlp.Span
+	testutil.WaitForResult(func() (bool, error) {
+		l.Lock()
+		defer l.Unlock()
+		if len(byName["Job/Register"]) != 2 || len(byName["Job.Register"]) != 1 || len(byName["eval_broker.enqueue"]) != 1 {
+			return false, nil
+		}
+		handlerSpan = byName["Job.Register"][0]
+		enqueueSpan = byName["eval_broker.enqueue"][0]
+		return true, nil
+	}, func(err error) {
+		t.Fatalf("spans weren't exported: %v", byName)
+	})
+
+	l.Lock()
+	defer l.Unlock()
+
+	leaderSpan := spans[handlerSpan.ParentSpanID]
+	must.NotNil(t, leaderSpan)
+	must.Eq(t, "Job/Register", leaderSpan.Name)
+	must.Eq(t, otlp.SpanKindServer, leaderSpan.Kind)
+
+	followerSpan := spans[leaderSpan.ParentSpanID]
+	must.NotNil(t, followerSpan)
+	must.Eq(t, "Job/Register", followerSpan.Name)
+	must.Eq(t, "00f067aa0ba902b7", followerSpan.ParentSpanID)
+
+	must.Eq(t, handlerSpan.SpanID, enqueueSpan.ParentSpanID)
+	for _, span := range []*otlp.Span{followerSpan, leaderSpan, handlerSpan, enqueueSpan} {
+		must.Eq(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID)
+	}
+}
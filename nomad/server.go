@@ -27,9 +27,7 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/raft"
 	autopilot "github.com/hashicorp/raft-autopilot"
-	raftboltdb "github.com/hashicorp/raft-boltdb/v2"
 	"github.com/hashicorp/serf/serf"
-	"go.etcd.io/bbolt"
 
 	"github.com/hashicorp/nomad/command/agent/consul"
 	"github.com/hashicorp/nomad/helper"
@@ -39,15 +37,18 @@ import (
 	"github.com/hashicorp/nomad/helper/iterator"
 	"github.com/hashicorp/nomad/helper/pool"
 	"github.com/hashicorp/nomad/helper/tlsutil"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/lib/auth/oidc"
 	"github.com/hashicorp/nomad/nomad/auth"
 	"github.com/hashicorp/nomad/nomad/deploymentwatcher"
 	"github.com/hashicorp/nomad/nomad/drainer"
+	"github.com/hashicorp/nomad/nomad/eventsink"
 	"github.com/hashicorp/nomad/nomad/lock"
 	"github.com/hashicorp/nomad/nomad/reporting"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/nomad/usage"
 	"github.com/hashicorp/nomad/nomad/volumewatcher"
 	"github.com/hashicorp/nomad/scheduler"
 )
@@ -70,6 +71,7 @@ const (
 	peersPollJitterFactor = 2
 
 	raftState         = "raft/"
+	raftSnapshotCache = "snapshot-cache"
 	serfSnapshot      = "serf/snapshot"
 	snapshotsRetained = 2
 
@@ -114,7 +116,7 @@ type Server struct {
 	// region to protect operations that require strong consistency
 	raft          *raft.Raft
 	raftLayer     *RaftLayer
-	raftStore     *raftboltdb.BoltStore
+	raftStore     raftLogStore
 	raftInmem     *raft.InmemStore
 	raftTransport *raft.NetworkTransport
 
@@ -224,6 +226,12 @@ type Server struct {
 	// volumeWatcher is used to release volume claims
 	volumeWatcher *volumewatcher.Watcher
 
+	// eventSinks is used to deliver events to the event sinks
+	eventSinks *eventsink.Manager
+
+	// usageAccountant is used to account the resource usage of jobs
+	usageAccountant *usage.Accountant
+
 	// volumeControllerFutures is a map of plugin IDs to pending controller RPCs. If
 	// no RPC is pending for a given plugin, this may be nil.
 	volumeControllerFutures map[string]context.Context
@@ -242,6 +250,14 @@ type Server struct {
 	// periodicDispatcher is used to track and create evaluations for periodic jobs.
 	periodicDispatcher *PeriodicDispatch
 
+	// dispatchQueueLock serializes the dispatches of parameterized jobs with a
+	// concurrency limit with the release of their queued dispatched jobs.
+	dispatchQueueLock sync.Mutex
+
+	// clientUpgradeLock serializes the updates of the client upgrades by the
+	// leader with the updates requested by operators.
+	clientUpgradeLock sync.Mutex
+
 	// planner is used to mange the submitted allocation plans that are waiting
 	// to be accessed by the leader
 	*planner
@@ -309,6 +325,14 @@ type Server struct {
 	// MAY BE nil! Issuer must be explicitly configured by the end user.
 	oidcDisco *structs.OIDCDiscoveryConfig
 
+	// admissionWebhooks are the admission controllers of the configured
+	// admission webhooks, which are shared by every Job endpoint.
+	admissionWebhooks []*jobAdmissionWebhook
+
+	// tracer creates the spans of the RPC handlers and the scheduling
+	// pipeline. It is nil if tracing is disabled, which is safe to use.
+	tracer *tracing.Tracer
+
 	// EnterpriseState is used to fill in state for Pro/Ent builds
 	EnterpriseState
 
@@ -440,6 +464,26 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigFunc
 		s.logger.Debug("issuer not set; OIDC Discovery endpoint for workload identities disabled")
 	}
 
+	// Set up the admission webhooks used by the Job endpoints.
+	for _, conf := range config.AdmissionWebhooks {
+		webhook, err := newJobAdmissionWebhook(conf, s.logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up admission webhook %q: %v", conf.Name, err)
+		}
+		s.admissionWebhooks = append(s.admissionWebhooks, webhook)
+	}
+
+	// Set up tracing of the scheduling pipeline.
+	s.tracer, err = tracing.NewTracer(s.logger, config.Tracing, map[string]string{
+		"service.instance.id": config.NodeName,
+		"nomad.region":        config.Region,
+		"nomad.datacenter":    config.Datacenter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.evalBroker.tracer = s.tracer
+
 	// Set up the SSO OIDC provider cache. This is needed by the setupRPC, but
 	// must be done separately so that the server can stop all background
 	// processes when it shuts down itself.
@@ -460,6 +504,8 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigFunc
 		VerifyTLS:      s.config.TLSConfig != nil && s.config.TLSConfig.EnableRPC && s.config.TLSConfig.VerifyServerHostname,
 		Region:         s.Region(),
 		Encrypter:      s.encrypter,
+
+		ConsumeTokenUseFn: s.consumeACLTokenUse,
 	})
 
 	// Initialize the Raft server
@@ -503,6 +549,12 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigFunc
 	}
 	s.volumeControllerFutures = map[string]context.Context{}
 
+	// Setup the event sinks manager.
+	s.eventSinks = eventsink.NewManager(s.logger, eventSinkShim{s})
+
+	// Setup the usage accountant.
+	s.usageAccountant = usage.NewAccountant(s.logger, usageShim{s}, s.config.Usage)
+
 	// Start the eval broker notification system so any subscribers can get
 	// updates when the processes SetEnabled is triggered.
 	go s.evalBroker.enabledNotifier.Run()
@@ -539,6 +591,11 @@ func NewServer(config *Config, consulCatalog consul.CatalogAPI, consulConfigFunc
 	// Emit metrics for the Vault client.
 	go s.vault.EmitStats(time.Second, s.shutdownCh)
 
+	// Record the eval broker stats in the flight recorder.
+	if s.config.FlightRecorder != nil {
+		go s.recordEvalBrokerStats(time.Second, s.shutdownCh)
+	}
+
 	// Emit metrics
 	go s.heartbeatStats()
 
@@ -775,6 +832,9 @@ func (s *Server) Shutdown() error {
 		s.oidcProviderCache.Shutdown()
 	}
 
+	// Export any remaining spans
+	s.tracer.Shutdown()
+
 	return nil
 }
 
@@ -1321,6 +1381,8 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	// These endpoints are client RPCs and don't include a connection context
 	_ = server.Register(NewClientStatsEndpoint(s))
 	_ = server.Register(newNodeMetaEndpoint(s))
+	_ = server.Register(newHostVolumeEndpoint(s))
+	_ = server.Register(newPrefetchEndpoint(s))
 
 	// These endpoints have their streaming component registered in
 	// setupStreamingEndpoints, but their non-streaming RPCs are registered
@@ -1340,7 +1402,9 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	_ = server.Register(NewCSIPluginEndpoint(s, ctx))
 	_ = server.Register(NewDeploymentEndpoint(s, ctx))
 	_ = server.Register(NewEvalEndpoint(s, ctx))
+	_ = server.Register(NewEventSinkEndpoint(s, ctx))
 	_ = server.Register(NewJobEndpoints(s, ctx))
+	_ = server.Register(NewJobTemplateEndpoint(s, ctx))
 	_ = server.Register(NewKeyringEndpoint(s, ctx, s.encrypter))
 	_ = server.Register(NewNamespaceEndpoint(s, ctx))
 	_ = server.Register(NewNodeEndpoint(s, ctx))
@@ -1353,6 +1417,8 @@ func (s *Server) setupRpcServer(server *rpc.Server, ctx *RPCContext) {
 	_ = server.Register(NewServiceRegistrationEndpoint(s, ctx))
 	_ = server.Register(NewStatusEndpoint(s, ctx))
 	_ = server.Register(NewSystemEndpoint(s, ctx))
+	_ = server.Register(NewUsageEndpoint(s, ctx))
+	_ = server.Register(NewVariableGrantEndpoint(s, ctx))
 	_ = server.Register(NewVariablesEndpoint(s, ctx, s.encrypter))
 
 	// Register non-streaming
@@ -1383,6 +1449,18 @@ func (s *Server) setupRaft() error {
 		EnableEventBroker:  s.config.EnableEventBroker,
 		EventBufferSize:    s.config.EventBufferSize,
 		JobTrackedVersions: s.config.JobTrackedVersions,
+
+		VariablesTrackedVersions: s.config.VariablesTrackedVersions,
+
+		SnapshotChunkSize: s.config.RaftSnapshotChunkSize,
+		SnapshotWriteRate: s.config.RaftSnapshotWriteRate,
+	}
+	if s.config.RaftSnapshotSectionCache && !s.config.DevMode {
+		// Sections cached by a previous run are for a different state store
+		fsmConfig.SnapshotCacheDir = filepath.Join(s.config.DataDir, raftState, raftSnapshotCache)
+		if err := os.RemoveAll(fsmConfig.SnapshotCacheDir); err != nil {
+			return fmt.Errorf("failed to clean up snapshot cache: %w", err)
+		}
 	}
 	var err error
 	s.fsm, err = NewFSM(fsmConfig)
@@ -1435,23 +1513,14 @@ func (s *Server) setupRaft() error {
 			return fmt.Errorf("failed to write Raft version file: %v", err)
 		}
 
-		// Create the BoltDB backend, with NoFreelistSync option
-		store, raftErr := raftboltdb.New(raftboltdb.Options{
-			Path:   filepath.Join(path, "raft.db"),
-			NoSync: false, // fsync each log write
-			BoltOptions: &bbolt.Options{
-				NoFreelistSync: s.config.RaftBoltNoFreelistSync,
-			},
-		})
+		// Create the configured log store backend, migrating the logs of
+		// the other backend if necessary
+		store, raftErr := s.openRaftLogStore(path)
 		if raftErr != nil {
 			return raftErr
 		}
 		s.raftStore = store
 		stable = store
-		s.logger.Info("setting up raft bolt store", "no_freelist_sync", s.config.RaftBoltNoFreelistSync)
-
-		// Start publishing bboltdb metrics
-		go store.RunMetrics(s.shutdownCtx, 0)
 
 		// Wrap the store in a LogCache to improve performance
 		cacheStore, err := raft.NewLogCache(raftLogCacheSize, store)
@@ -1607,9 +1676,12 @@ func (s *Server) setupSerf(conf *serf.Config, ch chan serf.Event, path string) (
 	if bootstrapExpect != 0 {
 		conf.Tags["expect"] = fmt.Sprintf("%d", bootstrapExpect)
 	}
-	if s.config.NonVoter {
+	if s.config.NonVoter || s.config.ReadReplica {
 		conf.Tags["nonvoter"] = "1"
 	}
+	if s.config.ReadReplica {
+		conf.Tags[ReadReplicaTag] = "1"
+	}
 	if s.config.RedundancyZone != "" {
 		conf.Tags[AutopilotRZTag] = s.config.RedundancyZone
 	}
@@ -2016,7 +2088,7 @@ func (s *Server) RPC(method string, args interface{}, reply interface{}) error {
 		Args:   args,
 		Reply:  reply,
 	}
-	if err := s.rpcServer.ServeRequest(codec); err != nil {
+	if err := s.rpcServer.ServeRequest(s.wrapTracing(s.wrapFlightRecorder(codec))); err != nil {
 		return err
 	}
 	return codec.Err
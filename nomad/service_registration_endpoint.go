@@ -372,9 +372,19 @@ func (s *ServiceRegistration) GetService(
 			// Set up our output after we have checked the error.
 			var services []*structs.ServiceRegistration
 
+			// Filter out the services of unhealthy allocations if requested.
+			var filters []paginator.Filter
+			if args.HealthyOnly {
+				filters = append(filters, paginator.GenericFilter{
+					Allow: func(raw interface{}) (bool, error) {
+						return serviceRegistrationHealthy(ws, stateStore, raw.(*structs.ServiceRegistration))
+					},
+				})
+			}
+
 			// Build the paginator. This includes the function that is
 			// responsible for appending a registration to the services array.
-			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, nil, args.QueryOptions,
+			paginatorImpl, err := paginator.NewPaginator(iter, tokenizer, filters, args.QueryOptions,
 				func(raw interface{}) error {
 					services = append(services, raw.(*structs.ServiceRegistration))
 					return nil
@@ -392,6 +402,14 @@ func (s *ServiceRegistration) GetService(
 					http.StatusBadRequest, "failed to read result page: %v", err)
 			}
 
+			// Blue/green deployments switch traffic to the new version when
+			// they're promoted, so the registrations of the standby version
+			// are returned with no weight.
+			services, err = blueGreenWeights(ws, stateStore, services)
+			if err != nil {
+				return err
+			}
+
 			// Select which subset and the order of services to return if using ?choose
 			if args.Choose != "" {
 				chosen, chooseErr := s.choose(services, args.Choose)
@@ -400,6 +418,12 @@ func (s *ServiceRegistration) GetService(
 						http.StatusBadRequest, "failed to choose services: %v", chooseErr)
 				}
 				services = chosen
+			} else {
+				// Otherwise return the registrations with the highest weight,
+				// based on their passing checks, first.
+				sort.SliceStable(services, func(i, j int) bool {
+					return services[i].Weight > services[j].Weight
+				})
 			}
 
 			// Populate the reply.
@@ -413,6 +437,103 @@ func (s *ServiceRegistration) GetService(
 	})
 }
 
+// serviceRegistrationHealthy returns true if the allocation of the service
+// registration is running and hasn't been marked unhealthy by its deployment.
+func serviceRegistrationHealthy(ws memdb.WatchSet, store *state.StateStore, reg *structs.ServiceRegistration) (bool, error) {
+	alloc, err := store.AllocByID(ws, reg.AllocID)
+	if err != nil {
+		return false, err
+	}
+	if alloc == nil || alloc.ClientStatus != structs.AllocClientStatusRunning {
+		return false, nil
+	}
+	return !alloc.DeploymentStatus.IsUnhealthy(), nil
+}
+
+// blueGreenWeights returns the service registrations with the weight of the
+// registrations of the standby version of a blue/green deployment set to zero.
+// Until the deployment is promoted the new version is on standby, and once it
+// is promoted the previous version is. Promotion updates the deployment and
+// the canaries in the same transaction, so the traffic switches atomically.
+func blueGreenWeights(ws memdb.WatchSet, store *state.StateStore,
+	services []*structs.ServiceRegistration) ([]*structs.ServiceRegistration, error) {
+
+	// deployments caches the latest deployment of each job along with the
+	// version of the job it deploys.
+	deployments := make(map[structs.NamespacedID]*blueGreenDeployment)
+
+	out := make([]*structs.ServiceRegistration, 0, len(services))
+	for _, reg := range services {
+		nsID := structs.NamespacedID{ID: reg.JobID, Namespace: reg.Namespace}
+		bg, ok := deployments[nsID]
+		if !ok {
+			var err error
+			if bg, err = latestBlueGreenDeployment(ws, store, reg.Namespace, reg.JobID); err != nil {
+				return nil, err
+			}
+			deployments[nsID] = bg
+		}
+
+		standby, err := bg.standby(ws, store, reg)
+		if err != nil {
+			return nil, err
+		}
+		if standby && reg.Weight != 0 {
+			reg = reg.Copy()
+			reg.Weight = 0
+		}
+		out = append(out, reg)
+	}
+	return out, nil
+}
+
+// blueGreenDeployment is the latest deployment of a job and the version of the
+// job it deploys.
+type blueGreenDeployment struct {
+	deployment *structs.Deployment
+	job        *structs.Job
+}
+
+// latestBlueGreenDeployment returns the latest deployment of the job, or nil
+// if the job has no deployment.
+func latestBlueGreenDeployment(ws memdb.WatchSet, store *state.StateStore,
+	namespace, jobID string) (*blueGreenDeployment, error) {
+
+	d, err := store.LatestDeploymentByJobID(ws, namespace, jobID)
+	if err != nil || d == nil {
+		return nil, err
+	}
+	job, err := store.JobByIDAndVersion(ws, namespace, jobID, d.JobVersion)
+	if err != nil || job == nil {
+		return nil, err
+	}
+	return &blueGreenDeployment{deployment: d, job: job}, nil
+}
+
+// standby returns whether the service registration belongs to the standby
+// version of a task group deployed with the blue/green update strategy.
+func (bg *blueGreenDeployment) standby(ws memdb.WatchSet, store *state.StateStore,
+	reg *structs.ServiceRegistration) (bool, error) {
+
+	if bg == nil {
+		return false, nil
+	}
+	alloc, err := store.AllocByID(ws, reg.AllocID)
+	if err != nil || alloc == nil || alloc.Job == nil {
+		return false, err
+	}
+	tg := bg.job.LookupTaskGroup(alloc.TaskGroup)
+	dstate, ok := bg.deployment.TaskGroups[alloc.TaskGroup]
+	if tg == nil || !tg.Update.IsBlueGreen() || !ok {
+		return false, nil
+	}
+
+	if alloc.DeploymentID == bg.deployment.ID {
+		return alloc.DeploymentStatus.IsCanary() && !dstate.Promoted, nil
+	}
+	return alloc.Job.Version < bg.deployment.JobVersion && dstate.Promoted, nil
+}
+
 // choose uses rendezvous hashing to make a stable selection of a subset of services
 // to return.
 //
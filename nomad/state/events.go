@@ -15,6 +15,7 @@ var MsgTypeEvents = map[structs.MessageType]string{
 	structs.NodePoolUpsertRequestType:                    structs.TypeNodePoolUpserted,
 	structs.NodePoolDeleteRequestType:                    structs.TypeNodePoolDeleted,
 	structs.EvalUpdateRequestType:                        structs.TypeEvalUpdated,
+	structs.EvalBumpPriorityRequestType:                  structs.TypeEvalPriorityBumped,
 	structs.AllocClientUpdateRequestType:                 structs.TypeAllocationUpdated,
 	structs.JobRegisterRequestType:                       structs.TypeJobRegistered,
 	structs.NodeUpdateStatusRequestType:                  structs.TypeNodeEvent,
@@ -41,6 +42,9 @@ var MsgTypeEvents = map[structs.MessageType]string{
 	structs.ServiceRegistrationUpsertRequestType:         structs.TypeServiceRegistration,
 	structs.ServiceRegistrationDeleteByIDRequestType:     structs.TypeServiceDeregistration,
 	structs.ServiceRegistrationDeleteByNodeIDRequestType: structs.TypeServiceDeregistration,
+	structs.VariablesExpireRequestType:                   structs.TypeVariableExpired,
+	structs.JobNotifyRequestType:                         structs.TypeJobNotification,
+	structs.JobDispatchReleaseRequestType:                structs.TypeEvalUpdated,
 }
 
 func eventsFromChanges(tx ReadTxn, changes Changes) *structs.Events {
@@ -54,6 +58,11 @@ func eventsFromChanges(tx ReadTxn, changes Changes) *structs.Events {
 		if event, ok := eventFromChange(change); ok {
 			event.Type = eventType
 			event.Index = changes.Index
+			if changes.MsgType == structs.JobNotifyRequestType {
+				if event, ok = jobNotificationEvent(tx, event); !ok {
+					continue
+				}
+			}
 			events = append(events, event)
 		}
 	}
@@ -61,6 +70,32 @@ func eventsFromChanges(tx ReadTxn, changes Changes) *structs.Events {
 	return &structs.Events{Index: changes.Index, Events: events}
 }
 
+// jobNotificationEvent adds the notification of the completed job to its
+// event. It returns false if the outcome of the job isn't notified.
+func jobNotificationEvent(tx ReadTxn, event structs.Event) (structs.Event, bool) {
+	payload, ok := event.Payload.(*structs.JobEvent)
+	if !ok {
+		return event, false
+	}
+	job := payload.Job
+
+	iter, err := tx.Get("allocs", "job", job.Namespace, job.ID)
+	if err != nil {
+		return event, false
+	}
+	var allocs []*structs.Allocation
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		allocs = append(allocs, raw.(*structs.Allocation))
+	}
+
+	notification := structs.NewJobNotification(job, allocs)
+	if !job.Notify.Notifies(notification.Status) {
+		return event, false
+	}
+	payload.Notification = notification
+	return event, true
+}
+
 func eventFromChange(change memdb.Change) (structs.Event, bool) {
 	if change.Deleted() {
 		switch change.Table {
@@ -181,6 +216,19 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 					Service: before,
 				},
 			}, true
+		case TableVariables:
+			before, ok := change.Before.(*structs.VariableEncrypted)
+			if !ok {
+				return structs.Event{}, false
+			}
+			return structs.Event{
+				Topic:     structs.TopicVariable,
+				Key:       before.Path,
+				Namespace: before.Namespace,
+				Payload: &structs.VariableEvent{
+					Variable: before.VariableMetadata.Copy(),
+				},
+			}, true
 		}
 		return structs.Event{}, false
 	}
@@ -293,12 +341,21 @@ func eventFromChange(change memdb.Change) (structs.Event, bool) {
 		if !ok {
 			return structs.Event{}, false
 		}
+
+		// Summarize the changes of new versions of the job so consumers
+		// don't have to keep the previous version to diff them.
+		var diffSummary *structs.JobDiffSummary
+		if before, ok := change.Before.(*structs.Job); ok && before.Version != after.Version {
+			diffSummary, _ = structs.NewJobDiffSummary(before, after)
+		}
+
 		return structs.Event{
 			Topic:     structs.TopicJob,
 			Key:       after.ID,
 			Namespace: after.Namespace,
 			Payload: &structs.JobEvent{
-				Job: after,
+				Job:         after,
+				DiffSummary: diffSummary,
 			},
 		}, true
 	case "nodes":
@@ -446,6 +446,94 @@ func TestEventsFromChanges_NodePoolDeleteRequestType(t *testing.T) {
 	must.Eq(t, pool, payload.NodePool)
 }
 
+func TestEventsFromChanges_VariablesExpireRequestType(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	// Create test variable that has expired.
+	sv := mock.VariableEncrypted()
+	sv.TTL = time.Minute
+	sv.ExpireTime = time.Now().Add(-time.Second).UnixNano()
+	resp := s.VarSet(1000, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: sv})
+	must.NoError(t, resp.Error)
+
+	// Expire the test variable.
+	err := s.VarsExpire(structs.VariablesExpireRequestType, 1001, time.Now())
+	must.NoError(t, err)
+
+	// Wait and verify the expiry event, which must not hold the variable's
+	// encrypted data.
+	events := WaitForEvents(t, s, 1001, 1, 1*time.Second)
+	must.Len(t, 1, events)
+
+	e := events[0]
+	must.Eq(t, structs.TopicVariable, e.Topic)
+	must.Eq(t, structs.TypeVariableExpired, e.Type)
+	must.Eq(t, sv.Path, e.Key)
+	must.Eq(t, sv.Namespace, e.Namespace)
+
+	payload := e.Payload.(*structs.VariableEvent)
+	must.Eq(t, sv.VariableMetadata, *payload.Variable)
+}
+
+func TestEventsFromChanges_JobNotifyRequestType(t *testing.T) {
+	ci.Parallel(t)
+	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
+	defer s.StopEventBroker()
+
+	job := mock.BatchJob()
+	job.Notify = &structs.JobNotify{On: []string{structs.JobNotifyComplete}}
+	must.NoError(t, s.UpsertJob(structs.MsgTypeTestSetup, 10, nil, job))
+
+	// Completing the job marks its notification as pending
+	alloc := mock.Alloc()
+	alloc.Job = job
+	alloc.JobID = job.ID
+	alloc.TaskGroup = job.TaskGroups[0].Name
+	must.NoError(t, s.UpsertAllocs(structs.MsgTypeTestSetup, 11, []*structs.Allocation{alloc}))
+
+	update := alloc.Copy()
+	update.ClientStatus = structs.AllocClientStatusComplete
+	must.NoError(t, s.UpdateAllocsFromClient(structs.MsgTypeTestSetup, 12, []*structs.Allocation{update}))
+
+	out, err := s.JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.Eq(t, structs.JobStatusDead, out.Status)
+	must.True(t, out.NotifyPending)
+
+	// The notification is still pending if the job changed since it was sent
+	req := &structs.JobNotifyRequest{
+		Namespace:   job.Namespace,
+		JobID:       job.ID,
+		ModifyIndex: out.ModifyIndex - 1,
+	}
+	must.NoError(t, s.UpdateJobNotified(structs.JobNotifyRequestType, 13, req))
+	out, err = s.JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.True(t, out.NotifyPending)
+
+	req.ModifyIndex = out.ModifyIndex
+	must.NoError(t, s.UpdateJobNotified(structs.JobNotifyRequestType, 14, req))
+	out, err = s.JobByID(nil, job.Namespace, job.ID)
+	must.NoError(t, err)
+	must.False(t, out.NotifyPending)
+	must.Eq(t, 14, out.ModifyIndex)
+
+	events := WaitForEvents(t, s, 14, 1, 1*time.Second)
+	must.Len(t, 1, events)
+
+	e := events[0]
+	must.Eq(t, structs.TopicJob, e.Topic)
+	must.Eq(t, structs.TypeJobNotification, e.Type)
+	must.Eq(t, job.ID, e.Key)
+
+	payload := e.Payload.(*structs.JobEvent)
+	must.NotNil(t, payload.Notification)
+	must.Eq(t, structs.JobNotifyComplete, payload.Notification.Status)
+	must.Eq(t, 1, payload.Notification.Stats.Complete)
+}
+
 func TestEventsFromChanges_EvalUpdateRequestType(t *testing.T) {
 	ci.Parallel(t)
 	s := TestStateStoreCfg(t, TestStateStorePublisher(t))
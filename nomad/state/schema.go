@@ -6,6 +6,7 @@ package state
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/nomad/state/indexer"
@@ -20,12 +21,19 @@ const (
 	TableServiceRegistrations = "service_registrations"
 	TableVariables            = "variables"
 	TableVariablesQuotas      = "variables_quota"
+	TableVariableVersions     = "variable_versions"
+	TableVariableGrants       = "variable_grants"
+	TableMaintenanceWindows   = "maintenance_windows"
+	TableClientUpgrades       = "client_upgrades"
 	TableRootKeyMeta          = "root_key_meta"
 	TableACLRoles             = "acl_roles"
 	TableACLAuthMethods       = "acl_auth_methods"
 	TableACLBindingRules      = "acl_binding_rules"
 	TableAllocs               = "allocs"
 	TableJobSubmission        = "job_submission"
+	TableJobTemplates         = "job_templates"
+	TableEventSinks           = "event_sinks"
+	TableUsage                = "usage"
 )
 
 const (
@@ -36,6 +44,8 @@ const (
 	indexServiceName   = "service_name"
 	indexExpiresGlobal = "expires-global"
 	indexExpiresLocal  = "expires-local"
+	indexExpires       = "expires"
+	indexDeleted       = "deleted"
 	indexKeyID         = "key_id"
 	indexPath          = "path"
 	indexName          = "name"
@@ -73,6 +83,9 @@ func init() {
 		jobSummarySchema,
 		jobVersionSchema,
 		jobSubmissionSchema,
+		jobTemplateTableSchema,
+		eventSinkTableSchema,
+		usageTableSchema,
 		deploymentSchema,
 		periodicLaunchTableSchema,
 		evalTableSchema,
@@ -93,6 +106,10 @@ func init() {
 		serviceRegistrationsTableSchema,
 		variablesTableSchema,
 		variablesQuotasTableSchema,
+		variableVersionsTableSchema,
+		variableGrantsTableSchema,
+		maintenanceWindowsTableSchema,
+		clientUpgradesTableSchema,
 		variablesRootKeyMetaSchema,
 		aclRolesTableSchema,
 		aclAuthMethodsTableSchema,
@@ -193,6 +210,73 @@ func nodePoolTableSchema() *memdb.TableSchema {
 	}
 }
 
+// jobTemplateTableSchema returns the MemDB schema for the job templates table.
+func jobTemplateTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableJobTemplates,
+		Indexes: map[string]*memdb.IndexSchema{
+			// Name is the primary index used for lookup and is required to be
+			// unique.
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "Name",
+				},
+			},
+		},
+	}
+}
+
+// eventSinkTableSchema returns the MemDB schema for the event sinks table.
+func eventSinkTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableEventSinks,
+		Indexes: map[string]*memdb.IndexSchema{
+			// ID is the primary index used for lookup and is required to be
+			// unique.
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.StringFieldIndex{
+					Field: "ID",
+				},
+			},
+		},
+	}
+}
+
+// usageTableSchema returns the MemDB schema for the usage records table.
+func usageTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableUsage,
+		Indexes: map[string]*memdb.IndexSchema{
+			// The namespace, job ID, and period start are the primary index
+			// used for lookup and are required to be unique.
+			"id": {
+				Name:         "id",
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "JobID",
+						},
+						&memdb.IntFieldIndex{
+							Field: "Start",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 // jobTableSchema returns the MemDB schema for the jobs table.
 // This table is used to store all the jobs that have been submitted.
 func jobTableSchema() *memdb.TableSchema {
@@ -254,6 +338,14 @@ func jobTableSchema() *memdb.TableSchema {
 					Field: "NodePool",
 				},
 			},
+			"dispatch_queued": {
+				Name:         "dispatch_queued",
+				AllowMissing: false,
+				Unique:       false,
+				Indexer: &memdb.ConditionalIndex{
+					Conditional: jobIsDispatchQueued,
+				},
+			},
 		},
 	}
 }
@@ -408,6 +500,18 @@ func jobIsPeriodic(obj interface{}) (bool, error) {
 	return false, nil
 }
 
+// jobIsDispatchQueued satisfies the ConditionalIndexFunc interface and
+// creates an index of the dispatched jobs waiting in the dispatch queue of
+// their parameterized job.
+func jobIsDispatchQueued(obj interface{}) (bool, error) {
+	j, ok := obj.(*structs.Job)
+	if !ok {
+		return false, fmt.Errorf("Unexpected type: %v", obj)
+	}
+
+	return j.DispatchQueued && j.ParentID != "", nil
+}
+
 // deploymentSchema returns the MemDB schema tracking a job's deployments
 func deploymentSchema() *memdb.TableSchema {
 	return &memdb.TableSchema{
@@ -1473,10 +1577,157 @@ func variablesTableSchema() *memdb.TableSchema {
 					Field: "Path",
 				},
 			},
+			indexExpires: {
+				Name:         indexExpires,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: indexer.SingleIndexer{
+					ReadIndex:  indexer.ReadIndex(indexer.IndexFromTimeQuery),
+					WriteIndex: indexer.WriteIndex(indexExpiresFromVariable),
+				},
+			},
+		},
+	}
+}
+
+// indexExpiresFromVariable implements the indexer.WriteIndex interface and
+// allows us to use a variable's ExpireTime as an index, if it has a TTL. This
+// allows for efficient lookups when removing expired variables from state.
+func indexExpiresFromVariable(raw interface{}) ([]byte, error) {
+	v, ok := raw.(*structs.VariableEncrypted)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T for structs.VariableEncrypted index", raw)
+	}
+	if v.ExpireTime <= 0 {
+		return nil, indexer.ErrMissingValueForIndex
+	}
+
+	var b indexer.IndexBuilder
+	b.Time(time.Unix(0, v.ExpireTime))
+	return b.Bytes(), nil
+}
+
+// variableVersionsTableSchema returns the MemDB schema for the prior versions
+// of Nomad variables, including the versions of deleted variables.
+func variableVersionsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableVariableVersions,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "Path",
+						},
+						&memdb.UintFieldIndex{
+							Field: "Version",
+						},
+					},
+				},
+			},
+			indexKeyID: {
+				Name:         indexKeyID,
+				AllowMissing: false,
+				Indexer:      &variableKeyIDFieldIndexer{},
+			},
+			indexDeleted: {
+				Name:         indexDeleted,
+				AllowMissing: true,
+				Unique:       false,
+				Indexer: indexer.SingleIndexer{
+					ReadIndex:  indexer.ReadIndex(indexer.IndexFromTimeQuery),
+					WriteIndex: indexer.WriteIndex(indexDeletedFromVariable),
+				},
+			},
+		},
+	}
+}
+
+// variableGrantsTableSchema returns the MemDB schema for the grants sharing
+// Nomad variables with the workloads of other namespaces.
+func variableGrantsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableVariableGrants,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.CompoundIndex{
+					Indexes: []memdb.Indexer{
+						&memdb.StringFieldIndex{
+							Field: "Namespace",
+						},
+						&memdb.StringFieldIndex{
+							Field: "Name",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// maintenanceWindowsTableSchema returns the MemDB schema for the maintenance
+// windows of the nodes.
+func maintenanceWindowsTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableMaintenanceWindows,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "ID",
+				},
+			},
 		},
 	}
 }
 
+// clientUpgradesTableSchema returns the MemDB schema for the rolling upgrades
+// of the client agents.
+func clientUpgradesTableSchema() *memdb.TableSchema {
+	return &memdb.TableSchema{
+		Name: TableClientUpgrades,
+		Indexes: map[string]*memdb.IndexSchema{
+			indexID: {
+				Name:         indexID,
+				AllowMissing: false,
+				Unique:       true,
+				Indexer: &memdb.UUIDFieldIndex{
+					Field: "ID",
+				},
+			},
+		},
+	}
+}
+
+// indexDeletedFromVariable implements the indexer.WriteIndex interface and
+// allows us to use the DeleteTime of a variable version as an index, if the
+// variable was deleted. This allows for efficient lookups when purging
+// deleted variables whose retention period has passed.
+func indexDeletedFromVariable(raw interface{}) ([]byte, error) {
+	v, ok := raw.(*structs.VariableEncrypted)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T for structs.VariableEncrypted index", raw)
+	}
+	if v.DeleteTime <= 0 {
+		return nil, indexer.ErrMissingValueForIndex
+	}
+
+	var b indexer.IndexBuilder
+	b.Time(time.Unix(0, v.DeleteTime))
+	return b.Bytes(), nil
+}
+
 type variableKeyIDFieldIndexer struct{}
 
 // FromArgs implements go-memdb/Indexer and is used to build an exact
@@ -103,12 +103,20 @@ type StateStoreConfig struct {
 
 	// JobTrackedVersions is the number of historic job versions that are kept.
 	JobTrackedVersions int
+
+	// VariablesTrackedVersions is the number of prior versions of each
+	// variable that are kept. Zero disables variable history and soft
+	// deletes.
+	VariablesTrackedVersions int
 }
 
 func (c *StateStoreConfig) Validate() error {
 	if c.JobTrackedVersions <= 0 {
 		return fmt.Errorf("JobTrackedVersions must be positive; got: %d", c.JobTrackedVersions)
 	}
+	if c.VariablesTrackedVersions < 0 {
+		return fmt.Errorf("VariablesTrackedVersions must not be negative; got: %d", c.VariablesTrackedVersions)
+	}
 	return nil
 }
 
@@ -1813,6 +1821,10 @@ func (s *StateStore) upsertJobImpl(index uint64, sub *structs.JobSubmission, job
 		if err != nil {
 			return fmt.Errorf("setting job status for %q failed: %v", job.ID, err)
 		}
+
+		// Keep the pending notification of the completed job, unless the job
+		// runs again.
+		job.NotifyPending = existingJob.NotifyPending && job.Status == structs.JobStatusDead
 	} else {
 		job.CreateIndex = index
 		job.ModifyIndex = index
@@ -2475,6 +2487,20 @@ func (s *StateStore) JobsByGC(ws memdb.WatchSet, gc bool) (memdb.ResultIterator,
 	return iter, nil
 }
 
+// JobsByDispatchQueued returns an iterator over the queued dispatched jobs.
+func (s *StateStore) JobsByDispatchQueued(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get("jobs", "dispatch_queued", true)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
 // JobsByPool returns an iterator over all jobs in a given node pool.
 func (s *StateStore) JobsByPool(ws memdb.WatchSet, pool string) (memdb.ResultIterator, error) {
 	txn := s.db.ReadTxn()
@@ -2835,6 +2861,35 @@ func (s *StateStore) CSIVolumeClaim(index uint64, namespace, id string, claim *s
 	return txn.Commit()
 }
 
+// UpdateCSIVolumeScheduledSnapshots records the snapshots retained by the
+// snapshot schedule of a volume. Volumes deregistered since the snapshots
+// were taken are ignored.
+func (s *StateStore) UpdateCSIVolumeScheduledSnapshots(index uint64, namespace, id string, snapshots []*structs.CSIVolumeScheduledSnapshot) error {
+	txn := s.db.WriteTxn(index)
+	defer txn.Abort()
+
+	obj, err := txn.First("csi_volumes", "id", namespace, id)
+	if err != nil {
+		return fmt.Errorf("volume lookup failed: %s: %v", id, err)
+	}
+	if obj == nil {
+		return nil
+	}
+
+	vol := obj.(*structs.CSIVolume).Copy()
+	vol.ScheduledSnapshots = snapshots
+	vol.ModifyIndex = index
+
+	if err := txn.Insert("csi_volumes", vol); err != nil {
+		return fmt.Errorf("volume update failed: %s: %v", id, err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"csi_volumes", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
 // CSIVolumeDeregister removes the volume from the server
 func (s *StateStore) CSIVolumeDeregister(index uint64, namespace string, ids []string, force bool) error {
 	txn := s.db.WriteTxn(index)
@@ -3307,6 +3362,51 @@ func (s *StateStore) UpsertEvals(msgType structs.MessageType, index uint64, eval
 	return err
 }
 
+// BumpEvalPriority raises the priority of the evaluations of the request that
+// are not terminal and have a lower priority, recording the bump on the
+// evaluations. The bumped evaluations are returned.
+func (s *StateStore) BumpEvalPriority(msgType structs.MessageType, index uint64, req *structs.EvalBumpPriorityRequest) ([]*structs.Evaluation, error) {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	var bumped []*structs.Evaluation
+	for _, id := range req.EvalIDs {
+		existing, err := txn.First("evals", "id", id)
+		if err != nil {
+			return nil, fmt.Errorf("eval lookup failed: %v", err)
+		}
+		if existing == nil {
+			continue
+		}
+
+		eval := existing.(*structs.Evaluation)
+		if eval.TerminalStatus() || eval.Priority >= req.Priority {
+			continue
+		}
+
+		eval = eval.Copy()
+		eval.PriorityBump = &structs.EvalPriorityBump{
+			PreviousPriority: eval.Priority,
+			BumpedBy:         req.BumpedBy,
+			BumpTime:         req.BumpTime,
+		}
+		eval.Priority = req.Priority
+		eval.ModifyIndex = index
+		eval.ModifyTime = req.BumpTime
+
+		if err := txn.Insert("evals", eval); err != nil {
+			return nil, fmt.Errorf("eval insert failed: %v", err)
+		}
+		bumped = append(bumped, eval)
+	}
+
+	if err := txn.Insert("index", &IndexEntry{"evals", index}); err != nil {
+		return nil, fmt.Errorf("index update failed: %v", err)
+	}
+
+	return bumped, txn.Commit()
+}
+
 // UpsertEvalsTxn is used to upsert a set of evaluations, like UpsertEvals but
 // in a transaction.  Useful for when making multiple modifications atomically.
 func (s *StateStore) UpsertEvalsTxn(index uint64, evals []*structs.Evaluation, txn Txn) error {
@@ -3937,6 +4037,7 @@ func (s *StateStore) nestedUpdateAllocFromClient(txn *txn, index uint64, alloc *
 	copyAlloc.ClientDescription = alloc.ClientDescription
 	copyAlloc.TaskStates = alloc.TaskStates
 	copyAlloc.NetworkStatus = alloc.NetworkStatus
+	copyAlloc.PendingReason = alloc.PendingReason
 
 	// The client can only set its deployment health and timestamp, so just take
 	// those
@@ -4861,6 +4962,71 @@ func (s *StateStore) updateJobStabilityImpl(index uint64, namespace, jobID strin
 	return s.upsertJobImpl(index, nil, copy, true, txn)
 }
 
+// UpdateJobNotified clears the pending notification of the job, once the
+// leader sent it. The notification is still pending if the job changed since
+// it was sent.
+func (s *StateStore) UpdateJobNotified(msgType structs.MessageType, index uint64, req *structs.JobNotifyRequest) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	existing, err := txn.First("jobs", "id", req.Namespace, req.JobID)
+	if err != nil {
+		return fmt.Errorf("job lookup failed: %v", err)
+	}
+	if existing == nil {
+		return nil
+	}
+	job := existing.(*structs.Job)
+	if !job.NotifyPending || job.ModifyIndex != req.ModifyIndex {
+		return nil
+	}
+
+	updated := job.Copy()
+	updated.NotifyPending = false
+	updated.ModifyIndex = index
+
+	if err := txn.Insert("jobs", updated); err != nil {
+		return fmt.Errorf("job insert failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"jobs", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	return txn.Commit()
+}
+
+// ReleaseQueuedDispatches creates the evaluations of queued dispatched jobs,
+// and clears the queued flag of their jobs in the same transaction.
+func (s *StateStore) ReleaseQueuedDispatches(msgType structs.MessageType, index uint64, evals []*structs.Evaluation) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, eval := range evals {
+		existing, err := txn.First("jobs", "id", eval.Namespace, eval.JobID)
+		if err != nil {
+			return fmt.Errorf("job lookup failed: %v", err)
+		}
+		if existing == nil || !existing.(*structs.Job).DispatchQueued {
+			continue
+		}
+
+		updated := existing.(*structs.Job).Copy()
+		updated.DispatchQueued = false
+		updated.ModifyIndex = index
+		if err := txn.Insert("jobs", updated); err != nil {
+			return fmt.Errorf("job insert failed: %v", err)
+		}
+	}
+	if err := txn.Insert("index", &IndexEntry{"jobs", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+
+	if err := s.UpsertEvalsTxn(index, evals, txn); err != nil {
+		return err
+	}
+	return txn.Commit()
+}
+
 // UpdateDeploymentPromotion is used to promote canaries in a deployment and
 // potentially make a evaluation
 func (s *StateStore) UpdateDeploymentPromotion(msgType structs.MessageType, index uint64, req *structs.ApplyDeploymentPromoteRequest) error {
@@ -5371,6 +5537,10 @@ func (s *StateStore) setJobStatus(index uint64, txn *txn,
 	updated.Status = newStatus
 	updated.ModifyIndex = index
 
+	// Jobs with a notify block are notified once they complete, unless they
+	// were stopped.
+	updated.NotifyPending = updated.Notify != nil && newStatus == structs.JobStatusDead && !updated.Stop
+
 	// Insert the job
 	if err := txn.Insert("jobs", updated); err != nil {
 		return fmt.Errorf("job insert failed: %v", err)
@@ -6202,6 +6372,10 @@ func (s *StateStore) UpsertACLTokens(msgType structs.MessageType, index uint64,
 			token.SecretID = existTK.SecretID
 			token.CreateTime = existTK.CreateTime
 
+			// The uses are only updated by ConsumeACLTokenUse, which may
+			// have happened since the token was read
+			token.Uses = existTK.Uses
+
 		} else {
 			token.CreateIndex = index
 			token.ModifyIndex = index
@@ -6220,6 +6394,36 @@ func (s *StateStore) UpsertACLTokens(msgType structs.MessageType, index uint64,
 	return txn.Commit()
 }
 
+// ConsumeACLTokenUse records a use of the token with the given accessor ID,
+// returning ErrTokenExhausted if the token has no uses left.
+func (s *StateStore) ConsumeACLTokenUse(msgType structs.MessageType, index uint64, accessorID string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	existing, err := txn.First("acl_token", "id", accessorID)
+	if err != nil {
+		return fmt.Errorf("token lookup failed: %v", err)
+	}
+	if existing == nil {
+		return structs.ErrTokenNotFound
+	}
+
+	token := existing.(*structs.ACLToken).Copy()
+	if token.MaxUses > 0 && token.Uses >= token.MaxUses {
+		return structs.ErrTokenExhausted
+	}
+	token.Uses++
+	token.ModifyIndex = index
+
+	if err := txn.Insert("acl_token", token); err != nil {
+		return fmt.Errorf("upserting token failed: %v", err)
+	}
+	if err := txn.Insert("index", &IndexEntry{"acl_token", index}); err != nil {
+		return fmt.Errorf("index update failed: %v", err)
+	}
+	return txn.Commit()
+}
+
 // DeleteACLTokens deletes the tokens with the given accessor ids
 func (s *StateStore) DeleteACLTokens(msgType structs.MessageType, index uint64, ids []string) error {
 	txn := s.db.WriteTxnMsgT(msgType, index)
@@ -6976,6 +7180,16 @@ func (s *StateStore) DeleteNamespaces(index uint64, names []string) error {
 				"All variables in namespace must be deleted before it can be deleted", name)
 		}
 
+		grantIter, err := variableGrantsByNamespaceTxn(txn, nil, name)
+		if err != nil {
+			return err
+		}
+		if rawGrant := grantIter.Next(); rawGrant != nil {
+			grant := rawGrant.(*structs.VariableGrant)
+			return fmt.Errorf("namespace %q contains at least one variable grant %q. "+
+				"All variable grants in namespace must be deleted before it can be deleted", name, grant.Name)
+		}
+
 		// Delete the namespace
 		if err := txn.Delete(TableNamespaces, existing); err != nil {
 			return fmt.Errorf("namespace deletion failed: %v", err)
@@ -7459,7 +7673,8 @@ func (s *StateStore) GetActiveRootKeyMeta(ws memdb.WatchSet) (*structs.RootKeyMe
 }
 
 // IsRootKeyMetaInUse determines whether a key has been used to sign a workload
-// identity for a live allocation or encrypt any variables
+// identity for a live allocation, encrypt any variables, or seal the values of
+// any job version
 func (s *StateStore) IsRootKeyMetaInUse(keyID string) (bool, error) {
 	txn := s.db.ReadTxn()
 
@@ -7481,5 +7696,26 @@ func (s *StateStore) IsRootKeyMetaInUse(keyID string) (bool, error) {
 		return true, nil
 	}
 
+	iter, err = txn.Get(TableVariableVersions, indexKeyID, keyID)
+	if err != nil {
+		return false, err
+	}
+	version := iter.Next()
+	if version != nil {
+		return true, nil
+	}
+
+	// Sealed values aren't indexed by key, so check every job version, which
+	// includes the current version of every job
+	iter, err = txn.Get("job_version", "id")
+	if err != nil {
+		return false, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if raw.(*structs.Job).UsesSealedKey(keyID) {
+			return true, nil
+		}
+	}
+
 	return false, nil
 }
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ClientUpgrades returns an iterator over all the client upgrades.
+func (s *StateStore) ClientUpgrades(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableClientUpgrades, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("client upgrades lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// ClientUpgradeByID returns the client upgrade that matches the given ID or
+// nil if there is no match.
+func (s *StateStore) ClientUpgradeByID(ws memdb.WatchSet, id string) (*structs.ClientUpgrade, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableClientUpgrades, indexID, id)
+	if err != nil {
+		return nil, fmt.Errorf("client upgrade lookup failed: %w", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return nil, nil
+	}
+
+	return existing.(*structs.ClientUpgrade), nil
+}
+
+// UpsertClientUpgrade inserts or updates a client upgrade. Inserting a new
+// upgrade removes the terminal upgrades, so the table only holds the upgrades
+// in progress and the latest finished one.
+func (s *StateStore) UpsertClientUpgrade(msgType structs.MessageType, index uint64, upgrade *structs.ClientUpgrade) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	existing, err := txn.First(TableClientUpgrades, indexID, upgrade.ID)
+	if err != nil {
+		return fmt.Errorf("client upgrade lookup failed: %w", err)
+	}
+
+	if existing != nil {
+		upgrade.CreateIndex = existing.(*structs.ClientUpgrade).CreateIndex
+	} else {
+		upgrade.CreateIndex = index
+
+		iter, err := txn.Get(TableClientUpgrades, indexID)
+		if err != nil {
+			return fmt.Errorf("client upgrades lookup failed: %w", err)
+		}
+		var terminal []*structs.ClientUpgrade
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			if u := raw.(*structs.ClientUpgrade); u.Terminal() {
+				terminal = append(terminal, u)
+			}
+		}
+		for _, u := range terminal {
+			if err := txn.Delete(TableClientUpgrades, u); err != nil {
+				return fmt.Errorf("client upgrade deletion failed: %w", err)
+			}
+		}
+	}
+	upgrade.ModifyIndex = index
+
+	if err := txn.Insert(TableClientUpgrades, upgrade); err != nil {
+		return fmt.Errorf("client upgrade insert failed: %w", err)
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableClientUpgrades, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
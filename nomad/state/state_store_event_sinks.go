@@ -0,0 +1,174 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// EventSinks returns an iterator over all event sinks.
+func (s *StateStore) EventSinks(ws memdb.WatchSet, sort SortOption) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	var iter memdb.ResultIterator
+	var err error
+
+	switch sort {
+	case SortReverse:
+		iter, err = txn.GetReverse(TableEventSinks, "id")
+	default:
+		iter, err = txn.Get(TableEventSinks, "id")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("event sinks lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// EventSinkByID returns the event sink that matches the given ID or nil if
+// there is no match.
+func (s *StateStore) EventSinkByID(ws memdb.WatchSet, id string) (*structs.EventSink, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableEventSinks, "id", id)
+	if err != nil {
+		return nil, fmt.Errorf("event sink lookup failed: %w", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return nil, nil
+	}
+
+	return existing.(*structs.EventSink), nil
+}
+
+// EventSinksByIDPrefix returns an iterator over all event sinks that match
+// the given ID prefix.
+func (s *StateStore) EventSinksByIDPrefix(ws memdb.WatchSet, idPrefix string, sort SortOption) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	var iter memdb.ResultIterator
+	var err error
+
+	switch sort {
+	case SortReverse:
+		iter, err = txn.GetReverse(TableEventSinks, "id_prefix", idPrefix)
+	default:
+		iter, err = txn.Get(TableEventSinks, "id_prefix", idPrefix)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("event sinks prefix lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertEventSinks inserts or updates the given set of event sinks. Updates
+// keep the delivery progress of the existing sinks, and new sinks receive the
+// events written after they are created.
+func (s *StateStore) UpsertEventSinks(msgType structs.MessageType, index uint64, sinks []*structs.EventSink) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, sink := range sinks {
+		if sink == nil {
+			continue
+		}
+
+		existing, err := txn.First(TableEventSinks, "id", sink.ID)
+		if err != nil {
+			return fmt.Errorf("event sink lookup failed: %w", err)
+		}
+
+		if existing != nil {
+			existingSink := existing.(*structs.EventSink)
+			sink.CreateIndex = existingSink.CreateIndex
+			sink.LatestIndex = existingSink.LatestIndex
+		} else {
+			sink.CreateIndex = index
+			sink.LatestIndex = index
+		}
+		sink.ModifyIndex = index
+
+		if err := txn.Insert(TableEventSinks, sink); err != nil {
+			return fmt.Errorf("event sink insert failed: %w", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{TableEventSinks, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
+
+// DeleteEventSinks removes the given set of event sinks.
+func (s *StateStore) DeleteEventSinks(msgType structs.MessageType, index uint64, ids []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, id := range ids {
+		existing, err := txn.First(TableEventSinks, "id", id)
+		if err != nil {
+			return fmt.Errorf("event sink lookup failed: %w", err)
+		}
+		if existing == nil {
+			return fmt.Errorf("event sink %s not found", id)
+		}
+
+		if err := txn.Delete(TableEventSinks, existing); err != nil {
+			return fmt.Errorf("event sink deletion failed: %w", err)
+		}
+	}
+
+	// Update index table.
+	if err := txn.Insert("index", &IndexEntry{TableEventSinks, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
+
+// UpdateEventSinksProgress records the index of the last event delivered to
+// each of the given event sinks. Sinks that no longer exist are ignored, and
+// the progress of a sink never moves backwards. The modify index of the sinks
+// is not updated since their configuration doesn't change.
+func (s *StateStore) UpdateEventSinksProgress(msgType structs.MessageType, index uint64, progress map[string]uint64) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for id, latestIndex := range progress {
+		existing, err := txn.First(TableEventSinks, "id", id)
+		if err != nil {
+			return fmt.Errorf("event sink lookup failed: %w", err)
+		}
+		if existing == nil {
+			continue
+		}
+
+		sink := existing.(*structs.EventSink)
+		if latestIndex <= sink.LatestIndex {
+			continue
+		}
+
+		sink = sink.Copy()
+		sink.LatestIndex = latestIndex
+		if err := txn.Insert(TableEventSinks, sink); err != nil {
+			return fmt.Errorf("event sink insert failed: %w", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{TableEventSinks, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
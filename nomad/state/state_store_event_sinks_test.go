@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"testing"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestStateStore_EventSinks(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	alpha := mock.EventSink()
+	alpha.ID = "alpha"
+	beta := mock.EventSink()
+	beta.ID = "beta"
+	must.NoError(t, state.UpsertEventSinks(structs.MsgTypeTestSetup, 1000,
+		[]*structs.EventSink{alpha, beta}))
+
+	ws := memdb.NewWatchSet()
+	iter, err := state.EventSinks(ws, SortDefault)
+	must.NoError(t, err)
+
+	var ids []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		ids = append(ids, raw.(*structs.EventSink).ID)
+	}
+	must.Eq(t, []string{"alpha", "beta"}, ids)
+
+	iter, err = state.EventSinksByIDPrefix(ws, "be", SortDefault)
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	must.Eq(t, "beta", raw.(*structs.EventSink).ID)
+	must.Nil(t, iter.Next())
+
+	got, err := state.EventSinkByID(ws, "alpha")
+	must.NoError(t, err)
+	must.Eq(t, alpha, got)
+	must.False(t, watchFired(ws))
+
+	missing, err := state.EventSinkByID(nil, "missing")
+	must.NoError(t, err)
+	must.Nil(t, missing)
+}
+
+func TestStateStore_EventSink_UpsertDelete(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	sink := mock.EventSink()
+	must.NoError(t, state.UpsertEventSinks(structs.MsgTypeTestSetup, 1000,
+		[]*structs.EventSink{sink}))
+
+	// New sinks receive the events written after they are created.
+	got, err := state.EventSinkByID(nil, sink.ID)
+	must.NoError(t, err)
+	must.Eq(t, 1000, got.LatestIndex)
+
+	must.NoError(t, state.UpdateEventSinksProgress(structs.MsgTypeTestSetup, 1001,
+		map[string]uint64{sink.ID: 990}))
+	must.NoError(t, state.UpdateEventSinksProgress(structs.MsgTypeTestSetup, 1002,
+		map[string]uint64{sink.ID: 1001}))
+
+	// Updates keep the create index and the delivery progress.
+	ws := memdb.NewWatchSet()
+	_, err = state.EventSinkByID(ws, sink.ID)
+	must.NoError(t, err)
+
+	update := sink.Copy()
+	update.Address = "http://127.0.0.1:8081/events"
+	update.LatestIndex = 0
+	must.NoError(t, state.UpsertEventSinks(structs.MsgTypeTestSetup, 1003,
+		[]*structs.EventSink{update}))
+	must.True(t, watchFired(ws))
+
+	got, err = state.EventSinkByID(nil, sink.ID)
+	must.NoError(t, err)
+	must.Eq(t, "http://127.0.0.1:8081/events", got.Address)
+	must.Eq(t, 1000, got.CreateIndex)
+	must.Eq(t, 1003, got.ModifyIndex)
+	must.Eq(t, 1001, got.LatestIndex)
+
+	index, err := state.Index(TableEventSinks)
+	must.NoError(t, err)
+	must.Eq(t, 1003, index)
+
+	// Deleting a missing sink fails the whole transaction.
+	err = state.DeleteEventSinks(structs.MsgTypeTestSetup, 1004, []string{sink.ID, "missing"})
+	must.ErrorContains(t, err, "event sink missing not found")
+	got, err = state.EventSinkByID(nil, sink.ID)
+	must.NoError(t, err)
+	must.NotNil(t, got)
+
+	must.NoError(t, state.DeleteEventSinks(structs.MsgTypeTestSetup, 1005, []string{sink.ID}))
+	got, err = state.EventSinkByID(nil, sink.ID)
+	must.NoError(t, err)
+	must.Nil(t, got)
+
+	// Progress of deleted sinks is ignored.
+	must.NoError(t, state.UpdateEventSinksProgress(structs.MsgTypeTestSetup, 1006,
+		map[string]uint64{sink.ID: 1005}))
+	got, err = state.EventSinkByID(nil, sink.ID)
+	must.NoError(t, err)
+	must.Nil(t, got)
+}
+
+func TestStateStore_EventSink_Restore(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	sink := mock.EventSink()
+
+	restore, err := state.Restore()
+	must.NoError(t, err)
+	must.NoError(t, restore.EventSinkRestore(sink))
+	must.NoError(t, restore.Commit())
+
+	got, err := state.EventSinkByID(nil, sink.ID)
+	must.NoError(t, err)
+	must.Eq(t, sink, got)
+}
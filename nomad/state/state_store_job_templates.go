@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// JobTemplates returns an iterator over all job templates.
+func (s *StateStore) JobTemplates(ws memdb.WatchSet, sort SortOption) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	var iter memdb.ResultIterator
+	var err error
+
+	switch sort {
+	case SortReverse:
+		iter, err = txn.GetReverse(TableJobTemplates, "id")
+	default:
+		iter, err = txn.Get(TableJobTemplates, "id")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("job templates lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// JobTemplateByName returns the job template that matches the given name or
+// nil if there is no match.
+func (s *StateStore) JobTemplateByName(ws memdb.WatchSet, name string) (*structs.JobTemplate, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableJobTemplates, "id", name)
+	if err != nil {
+		return nil, fmt.Errorf("job template lookup failed: %w", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return nil, nil
+	}
+
+	return existing.(*structs.JobTemplate), nil
+}
+
+// JobTemplatesByNamePrefix returns an iterator over all job templates that
+// match the given name prefix.
+func (s *StateStore) JobTemplatesByNamePrefix(ws memdb.WatchSet, namePrefix string, sort SortOption) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	var iter memdb.ResultIterator
+	var err error
+
+	switch sort {
+	case SortReverse:
+		iter, err = txn.GetReverse(TableJobTemplates, "id_prefix", namePrefix)
+	default:
+		iter, err = txn.Get(TableJobTemplates, "id_prefix", namePrefix)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("job templates prefix lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UpsertJobTemplates inserts or updates the given set of job templates.
+func (s *StateStore) UpsertJobTemplates(msgType structs.MessageType, index uint64, templates []*structs.JobTemplate) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, tmpl := range templates {
+		if tmpl == nil {
+			continue
+		}
+
+		existing, err := txn.First(TableJobTemplates, "id", tmpl.Name)
+		if err != nil {
+			return fmt.Errorf("job template lookup failed: %w", err)
+		}
+
+		if existing != nil {
+			tmpl.CreateIndex = existing.(*structs.JobTemplate).CreateIndex
+		} else {
+			tmpl.CreateIndex = index
+		}
+		tmpl.ModifyIndex = index
+
+		if err := txn.Insert(TableJobTemplates, tmpl); err != nil {
+			return fmt.Errorf("job template insert failed: %w", err)
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{TableJobTemplates, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
+
+// DeleteJobTemplates removes the given set of job templates.
+func (s *StateStore) DeleteJobTemplates(msgType structs.MessageType, index uint64, names []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, name := range names {
+		existing, err := txn.First(TableJobTemplates, "id", name)
+		if err != nil {
+			return fmt.Errorf("job template lookup failed: %w", err)
+		}
+		if existing == nil {
+			return fmt.Errorf("job template %s not found", name)
+		}
+
+		if err := txn.Delete(TableJobTemplates, existing); err != nil {
+			return fmt.Errorf("job template deletion failed: %w", err)
+		}
+	}
+
+	// Update index table.
+	if err := txn.Insert("index", &IndexEntry{TableJobTemplates, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
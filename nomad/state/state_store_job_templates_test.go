@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"testing"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestStateStore_JobTemplates(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	web := mock.JobTemplate()
+	web.Name = "web"
+	worker := mock.JobTemplate()
+	worker.Name = "worker"
+	must.NoError(t, state.UpsertJobTemplates(structs.MsgTypeTestSetup, 1000,
+		[]*structs.JobTemplate{web, worker}))
+
+	ws := memdb.NewWatchSet()
+	iter, err := state.JobTemplates(ws, SortDefault)
+	must.NoError(t, err)
+
+	var names []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		names = append(names, raw.(*structs.JobTemplate).Name)
+	}
+	must.Eq(t, []string{"web", "worker"}, names)
+
+	iter, err = state.JobTemplatesByNamePrefix(ws, "wor", SortDefault)
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	must.Eq(t, "worker", raw.(*structs.JobTemplate).Name)
+	must.Nil(t, iter.Next())
+
+	got, err := state.JobTemplateByName(ws, "web")
+	must.NoError(t, err)
+	must.Eq(t, web, got)
+	must.False(t, watchFired(ws))
+
+	missing, err := state.JobTemplateByName(nil, "missing")
+	must.NoError(t, err)
+	must.Nil(t, missing)
+}
+
+func TestStateStore_JobTemplate_UpsertDelete(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	tmpl := mock.JobTemplate()
+	must.NoError(t, state.UpsertJobTemplates(structs.MsgTypeTestSetup, 1000,
+		[]*structs.JobTemplate{tmpl}))
+
+	// Updates keep the create index.
+	ws := memdb.NewWatchSet()
+	_, err := state.JobTemplateByName(ws, tmpl.Name)
+	must.NoError(t, err)
+
+	update := tmpl.Copy()
+	update.Description = "updated"
+	must.NoError(t, state.UpsertJobTemplates(structs.MsgTypeTestSetup, 1001,
+		[]*structs.JobTemplate{update}))
+	must.True(t, watchFired(ws))
+
+	got, err := state.JobTemplateByName(nil, tmpl.Name)
+	must.NoError(t, err)
+	must.Eq(t, "updated", got.Description)
+	must.Eq(t, 1000, got.CreateIndex)
+	must.Eq(t, 1001, got.ModifyIndex)
+
+	index, err := state.Index(TableJobTemplates)
+	must.NoError(t, err)
+	must.Eq(t, 1001, index)
+
+	// Deleting a missing template fails the whole transaction.
+	err = state.DeleteJobTemplates(structs.MsgTypeTestSetup, 1002, []string{tmpl.Name, "missing"})
+	must.ErrorContains(t, err, "job template missing not found")
+	got, err = state.JobTemplateByName(nil, tmpl.Name)
+	must.NoError(t, err)
+	must.NotNil(t, got)
+
+	must.NoError(t, state.DeleteJobTemplates(structs.MsgTypeTestSetup, 1003, []string{tmpl.Name}))
+	got, err = state.JobTemplateByName(nil, tmpl.Name)
+	must.NoError(t, err)
+	must.Nil(t, got)
+}
+
+func TestStateStore_JobTemplate_Restore(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	tmpl := mock.JobTemplate()
+
+	restore, err := state.Restore()
+	must.NoError(t, err)
+	must.NoError(t, restore.JobTemplateRestore(tmpl))
+	must.NoError(t, restore.Commit())
+
+	got, err := state.JobTemplateByName(nil, tmpl.Name)
+	must.NoError(t, err)
+	must.Eq(t, tmpl, got)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestStateStore_MaintenanceWindows(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	w1 := mock.MaintenanceWindow()
+	w2 := mock.MaintenanceWindow()
+	must.NoError(t, testState.UpsertMaintenanceWindows(
+		structs.MsgTypeTestSetup, 10, []*structs.MaintenanceWindow{w1, w2}))
+
+	iter, err := testState.MaintenanceWindows(nil)
+	must.NoError(t, err)
+	var ids []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		ids = append(ids, raw.(*structs.MaintenanceWindow).ID)
+	}
+	must.SliceContainsAll(t, []string{w1.ID, w2.ID}, ids)
+
+	// Updating a window keeps its create index.
+	update := w1.Copy()
+	update.Status = structs.MaintenanceWindowStatusActive
+	must.NoError(t, testState.UpsertMaintenanceWindows(
+		structs.MsgTypeTestSetup, 11, []*structs.MaintenanceWindow{update}))
+	got, err := testState.MaintenanceWindowByID(nil, w1.ID)
+	must.NoError(t, err)
+	must.Eq(t, 10, got.CreateIndex)
+	must.Eq(t, 11, got.ModifyIndex)
+	must.Eq(t, structs.MaintenanceWindowStatusActive, got.Status)
+
+	index, err := testState.Index(TableMaintenanceWindows)
+	must.NoError(t, err)
+	must.Eq(t, 11, index)
+
+	// Deleting a window that doesn't exist fails without deleting the others.
+	err = testState.DeleteMaintenanceWindows(
+		structs.MsgTypeTestSetup, 12, []string{w2.ID, "ad5a5c5c-0000-0000-0000-000000000000"})
+	must.ErrorContains(t, err, "not found")
+	got, err = testState.MaintenanceWindowByID(nil, w2.ID)
+	must.NoError(t, err)
+	must.NotNil(t, got)
+
+	must.NoError(t, testState.DeleteMaintenanceWindows(structs.MsgTypeTestSetup, 13, []string{w2.ID}))
+	got, err = testState.MaintenanceWindowByID(nil, w2.ID)
+	must.NoError(t, err)
+	must.Nil(t, got)
+}
@@ -42,6 +42,30 @@ func (r *StateRestore) NodePoolRestore(pool *structs.NodePool) error {
 	return nil
 }
 
+// JobTemplateRestore is used to restore a job template
+func (r *StateRestore) JobTemplateRestore(tmpl *structs.JobTemplate) error {
+	if err := r.txn.Insert(TableJobTemplates, tmpl); err != nil {
+		return fmt.Errorf("job template insert failed: %v", err)
+	}
+	return nil
+}
+
+// EventSinkRestore is used to restore an event sink
+func (r *StateRestore) EventSinkRestore(sink *structs.EventSink) error {
+	if err := r.txn.Insert(TableEventSinks, sink); err != nil {
+		return fmt.Errorf("event sink insert failed: %v", err)
+	}
+	return nil
+}
+
+// UsageRecordRestore is used to restore a usage record
+func (r *StateRestore) UsageRecordRestore(record *structs.UsageRecord) error {
+	if err := r.txn.Insert(TableUsage, record); err != nil {
+		return fmt.Errorf("usage record insert failed: %v", err)
+	}
+	return nil
+}
+
 // JobRestore is used to restore a job
 func (r *StateRestore) JobRestore(job *structs.Job) error {
 
@@ -231,6 +255,39 @@ func (r *StateRestore) VariablesRestore(variable *structs.VariableEncrypted) err
 	return nil
 }
 
+// VariableVersionRestore is used to restore a single prior version of a
+// variable into the variable_versions table.
+func (r *StateRestore) VariableVersionRestore(version *structs.VariableEncrypted) error {
+	if err := r.txn.Insert(TableVariableVersions, version); err != nil {
+		return fmt.Errorf("variable version insert failed: %v", err)
+	}
+	return nil
+}
+
+// VariableGrantRestore is used to restore a variable grant
+func (r *StateRestore) VariableGrantRestore(grant *structs.VariableGrant) error {
+	if err := r.txn.Insert(TableVariableGrants, grant); err != nil {
+		return fmt.Errorf("variable grant insert failed: %v", err)
+	}
+	return nil
+}
+
+// MaintenanceWindowRestore is used to restore a maintenance window
+func (r *StateRestore) MaintenanceWindowRestore(window *structs.MaintenanceWindow) error {
+	if err := r.txn.Insert(TableMaintenanceWindows, window); err != nil {
+		return fmt.Errorf("maintenance window insert failed: %v", err)
+	}
+	return nil
+}
+
+// ClientUpgradeRestore is used to restore a client upgrade
+func (r *StateRestore) ClientUpgradeRestore(upgrade *structs.ClientUpgrade) error {
+	if err := r.txn.Insert(TableClientUpgrades, upgrade); err != nil {
+		return fmt.Errorf("client upgrade insert failed: %v", err)
+	}
+	return nil
+}
+
 // VariablesQuotaRestore is used to restore a single variable quota into the
 // variables_quota table.
 func (r *StateRestore) VariablesQuotaRestore(quota *structs.VariablesQuota) error {
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// tableStatsSampleSize is the number of objects of each table that are
+// encoded to estimate the memory used by the table
+const tableStatsSampleSize = 100
+
+// TableStats returns the number of objects and the estimated memory used by
+// each table of the state store, sorted by estimated memory in descending
+// order. Every object of every table is counted, so callers should take a
+// snapshot of the state store first.
+func (s *StateStore) TableStats() ([]*structs.StateTableUsage, error) {
+	txn := s.db.ReadTxn()
+
+	var buf []byte
+	enc := codec.NewEncoderBytes(&buf, structs.MsgpackHandle)
+
+	tables := make([]*structs.StateTableUsage, 0, len(s.db.memdb.DBSchema().Tables))
+	for name := range s.db.memdb.DBSchema().Tables {
+		iter, err := txn.Get(name, indexID)
+		if err != nil {
+			return nil, fmt.Errorf("table %q lookup failed: %w", name, err)
+		}
+
+		usage := &structs.StateTableUsage{Name: name}
+		var sampled, sampledBytes int64
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			usage.Objects++
+			if sampled >= tableStatsSampleSize {
+				continue
+			}
+			buf = buf[:0]
+			enc.ResetBytes(&buf)
+			if err := enc.Encode(raw); err != nil {
+				return nil, fmt.Errorf("table %q object encoding failed: %w", name, err)
+			}
+			sampled++
+			sampledBytes += int64(len(buf))
+		}
+		if sampled > 0 {
+			usage.EstimatedBytes = sampledBytes / sampled * int64(usage.Objects)
+		}
+		tables = append(tables, usage)
+	}
+
+	sort.Slice(tables, func(i, j int) bool {
+		if tables[i].EstimatedBytes != tables[j].EstimatedBytes {
+			return tables[i].EstimatedBytes > tables[j].EstimatedBytes
+		}
+		return tables[i].Name < tables[j].Name
+	})
+	return tables, nil
+}
@@ -3963,6 +3963,33 @@ func TestStateStore_CSIVolume(t *testing.T) {
 	require.Equal(t, 1, len(vs))
 }
 
+func TestStateStore_UpdateCSIVolumeScheduledSnapshots(t *testing.T) {
+	ci.Parallel(t)
+
+	store := testStateStore(t)
+	vol := mock.CSIVolume(mock.CSIPlugin())
+	vol.SnapshotSchedule = &structs.CSIVolumeSnapshotSchedule{Interval: time.Hour, Retain: 2}
+	must.NoError(t, store.UpsertCSIVolume(1000, []*structs.CSIVolume{vol}))
+
+	snapshots := []*structs.CSIVolumeScheduledSnapshot{
+		{ID: "snap-1", CreateTime: 100},
+		{ID: "snap-2", CreateTime: 200},
+	}
+	must.NoError(t, store.UpdateCSIVolumeScheduledSnapshots(1001, vol.Namespace, vol.ID, snapshots))
+
+	got, err := store.CSIVolumeByID(nil, vol.Namespace, vol.ID)
+	must.NoError(t, err)
+	must.Eq(t, snapshots, got.ScheduledSnapshots)
+	must.Eq(t, 1001, got.ModifyIndex)
+	must.NotNil(t, got.SnapshotSchedule)
+
+	// Updating a volume that was deregistered is a no-op.
+	must.NoError(t, store.UpdateCSIVolumeScheduledSnapshots(1002, vol.Namespace, "missing", snapshots))
+	index, err := store.Index("csi_volumes")
+	must.NoError(t, err)
+	must.Eq(t, 1001, index)
+}
+
 func TestStateStore_CSIPlugin_Lifecycle(t *testing.T) {
 	ci.Parallel(t)
 
@@ -9567,6 +9594,48 @@ func TestStateStore_DeleteACLTokens(t *testing.T) {
 	}
 }
 
+func TestStateStore_ConsumeACLTokenUse(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	tk := mock.ACLToken()
+	tk.MaxUses = 2
+	must.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 1000, []*structs.ACLToken{tk}))
+
+	ws := memdb.NewWatchSet()
+	_, err := state.ACLTokenByAccessorID(ws, tk.AccessorID)
+	must.NoError(t, err)
+
+	must.NoError(t, state.ConsumeACLTokenUse(structs.MsgTypeTestSetup, 1001, tk.AccessorID))
+	must.True(t, watchFired(ws))
+	must.NoError(t, state.ConsumeACLTokenUse(structs.MsgTypeTestSetup, 1002, tk.AccessorID))
+
+	out, err := state.ACLTokenByAccessorID(nil, tk.AccessorID)
+	must.NoError(t, err)
+	must.Eq(t, 2, out.Uses)
+	must.Eq(t, 1002, out.ModifyIndex)
+
+	// The token has no uses left.
+	err = state.ConsumeACLTokenUse(structs.MsgTypeTestSetup, 1003, tk.AccessorID)
+	must.ErrorIs(t, err, structs.ErrTokenExhausted)
+
+	// Updating the token doesn't reset its uses.
+	update := out.Copy()
+	update.Name = "updated"
+	update.Uses = 0
+	must.NoError(t, state.UpsertACLTokens(structs.MsgTypeTestSetup, 1004, []*structs.ACLToken{update}))
+	out, err = state.ACLTokenByAccessorID(nil, tk.AccessorID)
+	must.NoError(t, err)
+	must.Eq(t, 2, out.Uses)
+
+	err = state.ConsumeACLTokenUse(structs.MsgTypeTestSetup, 1005, uuid.Generate())
+	must.ErrorIs(t, err, structs.ErrTokenNotFound)
+
+	index, err := state.Index("acl_token")
+	must.NoError(t, err)
+	must.Eq(t, 1004, index)
+}
+
 func TestStateStore_ACLTokenByAccessorIDPrefix(t *testing.T) {
 	ci.Parallel(t)
 
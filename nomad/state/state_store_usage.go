@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// UsageRecords returns an iterator over the usage records of all namespaces.
+func (s *StateStore) UsageRecords(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableUsage, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("usage records lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UsageRecordsByNamespace returns an iterator over the usage records of the
+// given namespace.
+func (s *StateStore) UsageRecordsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	// Only the last argument of the prefix index is matched as a prefix, so
+	// an empty job ID matches the jobs of this exact namespace.
+	iter, err := txn.Get(TableUsage, "id_prefix", namespace, "")
+	if err != nil {
+		return nil, fmt.Errorf("usage records lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// UsageRecordsByJob returns an iterator over the usage records of the given
+// job.
+func (s *StateStore) UsageRecordsByJob(ws memdb.WatchSet, namespace, jobID string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableUsage, "id_prefix", namespace, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("usage records lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+
+	// The job ID is matched as a prefix, so filter out the other jobs that
+	// share it.
+	filter := memdb.NewFilterIterator(iter, func(raw interface{}) bool {
+		record := raw.(*structs.UsageRecord)
+		return record.Namespace != namespace || record.JobID != jobID
+	})
+	return filter, nil
+}
+
+// UpsertUsage adds the usage of the request to the usage records of the same
+// namespace, job, and period, creating the records that don't exist, and
+// removes the records past their retention.
+func (s *StateStore) UpsertUsage(msgType structs.MessageType, index uint64, req *structs.UsageUpsertRequest) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, usage := range req.Usage {
+		if usage == nil {
+			continue
+		}
+
+		existing, err := txn.First(TableUsage, indexID, usage.Namespace, usage.JobID, usage.Start)
+		if err != nil {
+			return fmt.Errorf("usage record lookup failed: %w", err)
+		}
+
+		record := usage.Copy()
+		if existing != nil {
+			record = existing.(*structs.UsageRecord).Copy()
+			record.Add(usage)
+		} else {
+			record.CreateIndex = index
+		}
+		record.ModifyIndex = index
+
+		if err := txn.Insert(TableUsage, record); err != nil {
+			return fmt.Errorf("usage record insert failed: %w", err)
+		}
+	}
+
+	if req.PurgeBefore != 0 {
+		iter, err := txn.Get(TableUsage, indexID)
+		if err != nil {
+			return fmt.Errorf("usage records lookup failed: %w", err)
+		}
+
+		var expired []*structs.UsageRecord
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			if record := raw.(*structs.UsageRecord); record.Start < req.PurgeBefore {
+				expired = append(expired, record)
+			}
+		}
+		for _, record := range expired {
+			if err := txn.Delete(TableUsage, record); err != nil {
+				return fmt.Errorf("usage record deletion failed: %w", err)
+			}
+		}
+	}
+
+	if err := txn.Insert("index", &IndexEntry{TableUsage, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func usageRecords(iter memdb.ResultIterator) []*structs.UsageRecord {
+	var records []*structs.UsageRecord
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		records = append(records, raw.(*structs.UsageRecord))
+	}
+	return records
+}
+
+func TestStateStore_UpsertUsage(t *testing.T) {
+	ci.Parallel(t)
+
+	state := testStateStore(t)
+	hour := int64(time.Hour)
+
+	must.NoError(t, state.UpsertUsage(structs.MsgTypeTestSetup, 1000, &structs.UsageUpsertRequest{
+		Usage: []*structs.UsageRecord{
+			{Namespace: "default", JobID: "web", Start: hour, ReservedCPU: 100, UsedCPU: 50},
+			{Namespace: "default", JobID: "web-api", Start: hour, ReservedCPU: 10},
+			{Namespace: "default-ops", JobID: "web", Start: hour, ReservedCPU: 20},
+		},
+	}))
+
+	// Usage is added to the records of the same period.
+	must.NoError(t, state.UpsertUsage(structs.MsgTypeTestSetup, 1001, &structs.UsageUpsertRequest{
+		Usage: []*structs.UsageRecord{
+			{Namespace: "default", JobID: "web", Start: hour, ReservedCPU: 100, ReservedMemory: 30},
+			{Namespace: "default", JobID: "web", Start: 2 * hour, ReservedCPU: 5},
+		},
+	}))
+
+	iter, err := state.UsageRecordsByJob(nil, "default", "web")
+	must.NoError(t, err)
+	records := usageRecords(iter)
+	must.Len(t, 2, records)
+	must.Eq(t, 200, records[0].ReservedCPU)
+	must.Eq(t, 30, records[0].ReservedMemory)
+	must.Eq(t, 50, records[0].UsedCPU)
+	must.Eq(t, 1000, records[0].CreateIndex)
+	must.Eq(t, 1001, records[0].ModifyIndex)
+	must.Eq(t, 5, records[1].ReservedCPU)
+
+	iter, err = state.UsageRecordsByNamespace(nil, "default")
+	must.NoError(t, err)
+	must.Len(t, 3, usageRecords(iter))
+
+	iter, err = state.UsageRecords(nil)
+	must.NoError(t, err)
+	must.Len(t, 4, usageRecords(iter))
+
+	// Records past their retention are purged.
+	must.NoError(t, state.UpsertUsage(structs.MsgTypeTestSetup, 1002, &structs.UsageUpsertRequest{
+		PurgeBefore: 2 * hour,
+	}))
+
+	iter, err = state.UsageRecords(nil)
+	must.NoError(t, err)
+	records = usageRecords(iter)
+	must.Len(t, 1, records)
+	must.Eq(t, 2*hour, records[0].Start)
+
+	index, err := state.Index(TableUsage)
+	must.NoError(t, err)
+	must.Eq(t, 1002, index)
+}
@@ -0,0 +1,140 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"fmt"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// VariableGrants returns an iterator over the variable grants of all the
+// namespaces.
+func (s *StateStore) VariableGrants(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariableGrants, indexID)
+	if err != nil {
+		return nil, fmt.Errorf("variable grants lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// VariableGrantsByNamespace returns an iterator over the variable grants of
+// the given namespace.
+func (s *StateStore) VariableGrantsByNamespace(ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+	return variableGrantsByNamespaceTxn(txn, ws, namespace)
+}
+
+// VariableGrantByName returns the variable grant of the namespace that
+// matches the given name or nil if there is no match.
+func (s *StateStore) VariableGrantByName(ws memdb.WatchSet, namespace, name string) (*structs.VariableGrant, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, existing, err := txn.FirstWatch(TableVariableGrants, indexID, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("variable grant lookup failed: %w", err)
+	}
+	ws.Add(watchCh)
+
+	if existing == nil {
+		return nil, nil
+	}
+
+	return existing.(*structs.VariableGrant), nil
+}
+
+// VariableGranted returns true if a variable grant of the namespace shares
+// the variable at the given path with the workloads of the given namespace
+// and job.
+func (s *StateStore) VariableGranted(ws memdb.WatchSet, namespace, path, grantedNamespace, jobID string) (bool, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := variableGrantsByNamespaceTxn(txn, ws, namespace)
+	if err != nil {
+		return false, err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		if raw.(*structs.VariableGrant).Allows(path, grantedNamespace, jobID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UpsertVariableGrants inserts or updates the given set of variable grants.
+func (s *StateStore) UpsertVariableGrants(msgType structs.MessageType, index uint64, grants []*structs.VariableGrant) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, grant := range grants {
+		if grant == nil {
+			continue
+		}
+
+		existing, err := txn.First(TableVariableGrants, indexID, grant.Namespace, grant.Name)
+		if err != nil {
+			return fmt.Errorf("variable grant lookup failed: %w", err)
+		}
+
+		if existing != nil {
+			grant.CreateIndex = existing.(*structs.VariableGrant).CreateIndex
+		} else {
+			grant.CreateIndex = index
+		}
+		grant.ModifyIndex = index
+
+		if err := txn.Insert(TableVariableGrants, grant); err != nil {
+			return fmt.Errorf("variable grant insert failed: %w", err)
+		}
+	}
+
+	if err := txn.Insert(tableIndex, &IndexEntry{TableVariableGrants, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
+
+// DeleteVariableGrants removes the given set of variable grants of the
+// namespace.
+func (s *StateStore) DeleteVariableGrants(msgType structs.MessageType, index uint64, namespace string, names []string) error {
+	txn := s.db.WriteTxnMsgT(msgType, index)
+	defer txn.Abort()
+
+	for _, name := range names {
+		existing, err := txn.First(TableVariableGrants, indexID, namespace, name)
+		if err != nil {
+			return fmt.Errorf("variable grant lookup failed: %w", err)
+		}
+		if existing == nil {
+			return fmt.Errorf("variable grant %s not found", name)
+		}
+
+		if err := txn.Delete(TableVariableGrants, existing); err != nil {
+			return fmt.Errorf("variable grant deletion failed: %w", err)
+		}
+	}
+
+	// Update index table.
+	if err := txn.Insert(tableIndex, &IndexEntry{TableVariableGrants, index}); err != nil {
+		return fmt.Errorf("index update failed: %w", err)
+	}
+
+	return txn.Commit()
+}
+
+func variableGrantsByNamespaceTxn(txn ReadTxn, ws memdb.WatchSet, namespace string) (memdb.ResultIterator, error) {
+	iter, err := txn.Get(TableVariableGrants, indexID+"_prefix", namespace, "")
+	if err != nil {
+		return nil, fmt.Errorf("variable grants lookup failed: %w", err)
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package state
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestStateStore_VariableGrants(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	// Namespaces whose names share a prefix must not see each other's grants.
+	must.NoError(t, testState.UpsertNamespaces(5, []*structs.Namespace{
+		{Name: "platform"}, {Name: "platform-dev"},
+	}))
+
+	shared := mock.VariableGrant()
+	shared.Name = "shared"
+	shared.Namespace = "platform"
+
+	jobs := mock.VariableGrant()
+	jobs.Name = "jobs"
+	jobs.Namespace = "platform"
+	jobs.Path = "db/creds"
+	jobs.Namespaces = []string{"*"}
+	jobs.Jobs = []string{"web"}
+
+	dev := mock.VariableGrant()
+	dev.Namespace = "platform-dev"
+
+	must.NoError(t, testState.UpsertVariableGrants(
+		structs.MsgTypeTestSetup, 10, []*structs.VariableGrant{shared, jobs, dev}))
+
+	iter, err := testState.VariableGrantsByNamespace(nil, "platform")
+	must.NoError(t, err)
+	var names []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		names = append(names, raw.(*structs.VariableGrant).Name)
+	}
+	must.Eq(t, []string{"jobs", "shared"}, names)
+
+	got, err := testState.VariableGrantByName(nil, "platform", "shared")
+	must.NoError(t, err)
+	must.Eq(t, 10, got.CreateIndex)
+
+	// Updating a grant keeps its create index.
+	update := shared.Copy()
+	update.Description = "updated"
+	must.NoError(t, testState.UpsertVariableGrants(
+		structs.MsgTypeTestSetup, 11, []*structs.VariableGrant{update}))
+	got, err = testState.VariableGrantByName(nil, "platform", "shared")
+	must.NoError(t, err)
+	must.Eq(t, 10, got.CreateIndex)
+	must.Eq(t, 11, got.ModifyIndex)
+
+	testCases := []struct {
+		path      string
+		namespace string
+		job       string
+		granted   bool
+	}{
+		{path: "shared/tls", namespace: "team-a", job: "api", granted: true},
+		{path: "shared/tls", namespace: "team-b", job: "api", granted: false},
+		{path: "other", namespace: "team-a", job: "api", granted: false},
+		{path: "db/creds", namespace: "team-b", job: "web", granted: true},
+		{path: "db/creds", namespace: "team-b", job: "api", granted: false},
+	}
+	for _, tc := range testCases {
+		granted, err := testState.VariableGranted(nil, "platform", tc.path, tc.namespace, tc.job)
+		must.NoError(t, err)
+		must.Eq(t, tc.granted, granted, must.Sprintf("%s for %s/%s", tc.path, tc.namespace, tc.job))
+	}
+
+	// Namespaces with grants can't be deleted.
+	err = testState.DeleteNamespaces(12, []string{"platform"})
+	must.ErrorContains(t, err, "contains at least one variable grant")
+
+	must.NoError(t, testState.DeleteVariableGrants(
+		structs.MsgTypeTestSetup, 13, "platform", []string{"shared", "jobs"}))
+	got, err = testState.VariableGrantByName(nil, "platform", "shared")
+	must.NoError(t, err)
+	must.Nil(t, got)
+
+	index, err := testState.Index(TableVariableGrants)
+	must.NoError(t, err)
+	must.Eq(t, 13, index)
+
+	err = testState.DeleteVariableGrants(structs.MsgTypeTestSetup, 14, "platform", []string{"shared"})
+	must.ErrorContains(t, err, "not found")
+}
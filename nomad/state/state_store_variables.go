@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
+	"time"
 
 	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -210,6 +212,9 @@ func (s *StateStore) varSetTxn(tx WriteTxn, idx uint64, req *structs.VarApplySta
 		return req.ErrorResponse(idx, fmt.Errorf("variable quota lookup failed: %v", err))
 	}
 
+	// The delete time is only ever set on prior versions of deleted variables
+	sv.DeleteTime = 0
+
 	var quotaChange int64
 	// Set the CreateIndex and CreateTime
 	if existing != nil {
@@ -228,6 +233,7 @@ func (s *StateStore) varSetTxn(tx WriteTxn, idx uint64, req *structs.VarApplySta
 
 		sv.CreateIndex = existing.CreateIndex
 		sv.CreateTime = existing.CreateTime
+		sv.Version = existing.Version
 
 		if existing.Equal(*sv) {
 			// Skip further writing in the state store if the entry is not actually
@@ -239,10 +245,25 @@ func (s *StateStore) varSetTxn(tx WriteTxn, idx uint64, req *structs.VarApplySta
 		}
 		sv.ModifyIndex = idx
 		quotaChange = int64(len(sv.Data) - len(existing.Data))
+
+		// Keep the overwritten value as a prior version
+		prior := existing.Copy()
+		if err := s.varTrackVersionTxn(tx, idx, &prior, 0); err != nil {
+			return req.ErrorResponse(idx, err)
+		}
+		sv.Version = prior.Version + 1
 	} else {
 		sv.CreateIndex = idx
 		sv.ModifyIndex = idx
 		quotaChange = int64(len(sv.Data))
+
+		// A variable written at the path of a deleted variable continues its
+		// version history
+		latest, err := s.varUndeleteTxn(tx, idx, sv.Namespace, sv.Path)
+		if err != nil {
+			return req.ErrorResponse(idx, err)
+		}
+		sv.Version = latest + 1
 	}
 
 	if err := tx.Insert(TableVariables, sv); err != nil {
@@ -429,6 +450,19 @@ func (s *StateStore) svDeleteTxn(tx WriteTxn, idx uint64, req *structs.VarApplyS
 		}
 	}
 
+	// Keep the deleted value as the latest prior version, so that it can be
+	// restored until the deleted variables retention period has passed.
+	// Deletes without a delete time, such as the deletion of expired
+	// variables, remove the variable history as well.
+	if req.Var.DeleteTime != 0 {
+		prior := sv.Copy()
+		if err := s.varTrackVersionTxn(tx, idx, &prior, req.Var.DeleteTime); err != nil {
+			return req.ErrorResponse(idx, err)
+		}
+	} else if err := s.varPruneVersionsTxn(tx, idx, sv.Namespace, sv.Path, 0); err != nil {
+		return req.ErrorResponse(idx, err)
+	}
+
 	// Delete the variable and update the index table.
 	if err := tx.Delete(TableVariables, sv); err != nil {
 		return req.ErrorResponse(idx, fmt.Errorf("failed deleting variable entry: %s", err))
@@ -441,6 +475,306 @@ func (s *StateStore) svDeleteTxn(tx WriteTxn, idx uint64, req *structs.VarApplyS
 	return req.SuccessResponse(idx, nil)
 }
 
+// VariablesByExpired returns an iterator over all variables with a TTL,
+// ordered by the time they expire.
+func (s *StateStore) VariablesByExpired(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariables, indexExpires)
+	if err != nil {
+		return nil, fmt.Errorf("variable lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// VarsExpire deletes all the variables whose TTL has expired by the given
+// timestamp.
+func (s *StateStore) VarsExpire(msgType structs.MessageType, idx uint64, timestamp time.Time) error {
+	tx := s.db.WriteTxnMsgT(msgType, idx)
+	defer tx.Abort()
+
+	iter, err := tx.Get(TableVariables, indexExpires)
+	if err != nil {
+		return fmt.Errorf("variable lookup failed: %v", err)
+	}
+
+	// Collect the expired variables before deleting them, as the iterator
+	// can't be used while the table is modified.
+	var expired []*structs.VariableEncrypted
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		sv := raw.(*structs.VariableEncrypted)
+		if !sv.IsExpired(timestamp) {
+			break
+		}
+		expired = append(expired, sv)
+	}
+
+	for _, sv := range expired {
+		req := &structs.VarApplyStateRequest{
+			Op:  structs.VarOpDelete,
+			Var: sv,
+		}
+		resp := s.svDeleteTxn(tx, idx, req)
+		if resp.IsError() {
+			return resp.Error
+		}
+	}
+
+	return tx.Commit()
+}
+
+// VariableVersions queries all the prior versions of variables and is used
+// only for snapshot/restore.
+func (s *StateStore) VariableVersions(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariableVersions, indexID)
+	if err != nil {
+		return nil, err
+	}
+
+	ws.Add(iter.WatchCh())
+	return iter, nil
+}
+
+// GetVariableVersions returns the prior versions of the variable at the given
+// namespace and path, newest first. The versions of a deleted variable are
+// returned until its retention period has passed.
+func (s *StateStore) GetVariableVersions(
+	ws memdb.WatchSet, namespace, path string) ([]*structs.VariableEncrypted, error) {
+	txn := s.db.ReadTxn()
+	return varVersionsTxn(txn, ws, namespace, path)
+}
+
+// GetVariableVersion returns a single prior version of the variable at the
+// given namespace and path.
+func (s *StateStore) GetVariableVersion(
+	ws memdb.WatchSet, namespace, path string, version uint64) (*structs.VariableEncrypted, error) {
+	txn := s.db.ReadTxn()
+
+	watchCh, raw, err := txn.FirstWatch(TableVariableVersions, indexID, namespace, path, version)
+	if err != nil {
+		return nil, fmt.Errorf("variable version lookup failed: %v", err)
+	}
+	ws.Add(watchCh)
+	if raw == nil {
+		return nil, nil
+	}
+	return raw.(*structs.VariableEncrypted), nil
+}
+
+// GetVariableVersionsByKeyID returns an iterator that contains all the prior
+// versions of variables that were encrypted with a particular key.
+func (s *StateStore) GetVariableVersionsByKeyID(
+	ws memdb.WatchSet, keyID string) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariableVersions, indexKeyID, keyID)
+	if err != nil {
+		return nil, fmt.Errorf("variable version lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// VariableVersionsByDeleted returns an iterator over the latest versions of
+// deleted variables, ordered by the time they were deleted.
+func (s *StateStore) VariableVersionsByDeleted(ws memdb.WatchSet) (memdb.ResultIterator, error) {
+	txn := s.db.ReadTxn()
+
+	iter, err := txn.Get(TableVariableVersions, indexDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("variable version lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	return iter, nil
+}
+
+// VarsPurgeDeleted permanently removes the versions of all the variables that
+// were deleted before the given threshold.
+func (s *StateStore) VarsPurgeDeleted(msgType structs.MessageType, idx uint64, threshold time.Time) error {
+	tx := s.db.WriteTxnMsgT(msgType, idx)
+	defer tx.Abort()
+
+	iter, err := tx.Get(TableVariableVersions, indexDeleted)
+	if err != nil {
+		return fmt.Errorf("variable version lookup failed: %v", err)
+	}
+
+	// Collect the deleted variables before purging them, as the iterator
+	// can't be used while the table is modified.
+	var deleted []*structs.VariableEncrypted
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		sv := raw.(*structs.VariableEncrypted)
+		if sv.DeleteTime > threshold.UnixNano() {
+			break
+		}
+		deleted = append(deleted, sv)
+	}
+
+	for _, sv := range deleted {
+		if err := s.varPruneVersionsTxn(tx, idx, sv.Namespace, sv.Path, 0); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// VarRekey replaces the encrypted data of a variable, or of one of its prior
+// versions, with the same items encrypted by another root key. The metadata of
+// the variable is left untouched so that re-keying doesn't create a new
+// version. The ModifyIndex of the request is used to detect conflicts.
+func (s *StateStore) VarRekey(idx uint64, req *structs.VarApplyStateRequest) *structs.VarApplyStateResponse {
+	tx := s.db.WriteTxn(idx)
+	defer tx.Abort()
+
+	sv := req.Var
+	table := TableVariables
+	raw, err := tx.First(TableVariables, indexID, sv.Namespace, sv.Path)
+	if err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed variable lookup: %s", err))
+	}
+	existing, _ := raw.(*structs.VariableEncrypted)
+
+	if existing == nil || existing.ModifyIndex != sv.ModifyIndex {
+		table = TableVariableVersions
+		raw, err = tx.First(TableVariableVersions, indexID, sv.Namespace, sv.Path, sv.Version)
+		if err != nil {
+			return req.ErrorResponse(idx, fmt.Errorf("failed variable version lookup: %s", err))
+		}
+		existing, _ = raw.(*structs.VariableEncrypted)
+	}
+
+	if existing == nil || existing.ModifyIndex != sv.ModifyIndex {
+		return req.ConflictResponse(idx, nil)
+	}
+
+	updated := existing.Copy()
+	updated.VariableData = sv.VariableData.Copy()
+
+	if err := tx.Insert(table, &updated); err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed inserting variable: %s", err))
+	}
+	if err := tx.Insert(tableIndex, &IndexEntry{table, idx}); err != nil {
+		return req.ErrorResponse(idx, fmt.Errorf("failed updating variable index: %s", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return req.ErrorResponse(idx, err)
+	}
+	return req.SuccessResponse(idx, &updated.VariableMetadata)
+}
+
+// varVersionsTxn returns the prior versions of the variable at the given
+// namespace and path, newest first.
+func varVersionsTxn(txn ReadTxn, ws memdb.WatchSet,
+	namespace, path string) ([]*structs.VariableEncrypted, error) {
+
+	iter, err := txn.Get(TableVariableVersions, indexID+"_prefix", namespace, path)
+	if err != nil {
+		return nil, fmt.Errorf("variable version lookup failed: %v", err)
+	}
+	ws.Add(iter.WatchCh())
+
+	var versions []*structs.VariableEncrypted
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		// The prefix lookup also matches the paths the path is a prefix of
+		sv := raw.(*structs.VariableEncrypted)
+		if sv.Path == path {
+			versions = append(versions, sv)
+		}
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+	return versions, nil
+}
+
+// varTrackVersionTxn keeps a value that is being overwritten or deleted as a
+// prior version of the variable, and removes the versions that are beyond the
+// number of tracked versions. The deleteTime is set if the variable is being
+// deleted.
+func (s *StateStore) varTrackVersionTxn(tx WriteTxn, idx uint64,
+	prior *structs.VariableEncrypted, deleteTime int64) error {
+
+	// Variables written before versions were tracked don't have a version
+	if prior.Version == 0 {
+		prior.Version = 1
+	}
+	prior.DeleteTime = deleteTime
+
+	// Locks only apply to the current version
+	prior.Lock = nil
+
+	keep := s.config.VariablesTrackedVersions
+	if keep > 0 {
+		if err := tx.Insert(TableVariableVersions, prior); err != nil {
+			return fmt.Errorf("failed inserting variable version: %w", err)
+		}
+		if err := tx.Insert(tableIndex, &IndexEntry{TableVariableVersions, idx}); err != nil {
+			return fmt.Errorf("failed updating variable version index: %w", err)
+		}
+	}
+
+	return s.varPruneVersionsTxn(tx, idx, prior.Namespace, prior.Path, keep)
+}
+
+// varPruneVersionsTxn removes the oldest prior versions of the variable at the
+// given namespace and path so that at most keep versions remain.
+func (s *StateStore) varPruneVersionsTxn(tx WriteTxn, idx uint64,
+	namespace, path string, keep int) error {
+
+	versions, err := varVersionsTxn(tx, nil, namespace, path)
+	if err != nil {
+		return err
+	}
+	if len(versions) <= keep {
+		return nil
+	}
+
+	for _, version := range versions[keep:] {
+		if err := tx.Delete(TableVariableVersions, version); err != nil {
+			return fmt.Errorf("failed deleting variable version: %w", err)
+		}
+	}
+	if err := tx.Insert(tableIndex, &IndexEntry{TableVariableVersions, idx}); err != nil {
+		return fmt.Errorf("failed updating variable version index: %w", err)
+	}
+	return nil
+}
+
+// varUndeleteTxn is called when a variable is written at a path that holds
+// no variable. If a variable was deleted at the path, its latest version is
+// no longer marked as deleted so that its history isn't purged. It returns
+// the latest prior version of the path, or zero if there is none.
+func (s *StateStore) varUndeleteTxn(tx WriteTxn, idx uint64,
+	namespace, path string) (uint64, error) {
+
+	versions, err := varVersionsTxn(tx, nil, namespace, path)
+	if err != nil || len(versions) == 0 {
+		return 0, err
+	}
+
+	latest := versions[0]
+	if latest.IsDeleted() {
+		undeleted := latest.Copy()
+		undeleted.DeleteTime = 0
+		if err := tx.Insert(TableVariableVersions, &undeleted); err != nil {
+			return 0, fmt.Errorf("failed inserting variable version: %w", err)
+		}
+		if err := tx.Insert(tableIndex, &IndexEntry{TableVariableVersions, idx}); err != nil {
+			return 0, fmt.Errorf("failed updating variable version index: %w", err)
+		}
+	}
+	return latest.Version, nil
+}
+
 // WriteTxn is implemented by memdb.Txn to perform write operations.
 type WriteTxn interface {
 	ReadTxn
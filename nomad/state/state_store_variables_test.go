@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	memdb "github.com/hashicorp/go-memdb"
 	"github.com/shoenig/test/must"
@@ -1218,3 +1219,301 @@ func getAllVariables(ss *StateStore, ws memdb.WatchSet) ([]*structs.VariableEncr
 
 	return got, nil
 }
+
+func TestStateStore_VarsExpire(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	now := time.Now()
+	insertIndex := uint64(20)
+
+	// Write a variable without a TTL, one that has expired and one that
+	// expires in the future.
+	expireTimes := map[string]int64{
+		"no/ttl":      0,
+		"expired":     now.Add(-time.Minute).UnixNano(),
+		"not/expired": now.Add(time.Hour).UnixNano(),
+	}
+	for path, expireTime := range expireTimes {
+		sv := mock.VariableEncrypted()
+		sv.Namespace = structs.DefaultNamespace
+		sv.Path = path
+		if expireTime != 0 {
+			sv.TTL = time.Hour
+			sv.ExpireTime = expireTime
+		}
+		insertIndex++
+		resp := testState.VarSet(insertIndex, &structs.VarApplyStateRequest{
+			Op:  structs.VarOpSet,
+			Var: sv,
+		})
+		must.NoError(t, resp.Error)
+	}
+
+	iter, err := testState.VariablesByExpired(nil)
+	must.NoError(t, err)
+	var withTTL []string
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		withTTL = append(withTTL, raw.(*structs.VariableEncrypted).Path)
+	}
+	must.Eq(t, []string{"expired", "not/expired"}, withTTL)
+
+	expireIndex := insertIndex + 1
+	must.NoError(t, testState.VarsExpire(structs.VariablesExpireRequestType, expireIndex, now))
+
+	out, err := testState.GetVariable(nil, structs.DefaultNamespace, "expired")
+	must.NoError(t, err)
+	must.Nil(t, out)
+
+	for _, path := range []string{"no/ttl", "not/expired"} {
+		out, err := testState.GetVariable(nil, structs.DefaultNamespace, path)
+		must.NoError(t, err)
+		must.NotNil(t, out)
+	}
+
+	index, err := testState.Index(TableVariables)
+	must.NoError(t, err)
+	must.Eq(t, expireIndex, index)
+
+	quotaUsed, err := testState.VariablesQuotaByNamespace(nil, structs.DefaultNamespace)
+	must.NoError(t, err)
+	must.Eq(t, int64(2*len(mock.VariableEncrypted().Data)), quotaUsed.Size)
+}
+
+func TestStateStore_VariableVersions(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+	testState.config.VariablesTrackedVersions = 2
+
+	sv := mock.VariableEncrypted()
+	sv.Namespace = structs.DefaultNamespace
+	sv.Path = "versioned"
+
+	// Another variable whose path has the versioned path as its prefix
+	other := mock.VariableEncrypted()
+	other.Namespace = structs.DefaultNamespace
+	other.Path = "versioned/other"
+	resp := testState.VarSet(10, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: other})
+	must.NoError(t, resp.Error)
+
+	// Write the variable four times with different data
+	for i := 0; i < 4; i++ {
+		v := sv.Copy()
+		v.Data = []byte{byte(i)}
+		resp := testState.VarSet(uint64(20+i), &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: &v})
+		must.NoError(t, resp.Error)
+		must.Eq(t, uint64(i+1), resp.WrittenSVMeta.Version)
+	}
+
+	// Only the number of tracked versions is kept
+	versions, err := testState.GetVariableVersions(nil, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	must.Len(t, 2, versions)
+	must.Eq(t, 3, versions[0].Version)
+	must.Eq(t, []byte{2}, versions[0].Data)
+	must.Eq(t, 2, versions[1].Version)
+
+	version, err := testState.GetVariableVersion(nil, sv.Namespace, sv.Path, 2)
+	must.NoError(t, err)
+	must.Eq(t, []byte{1}, version.Data)
+
+	version, err = testState.GetVariableVersion(nil, sv.Namespace, sv.Path, 1)
+	must.NoError(t, err)
+	must.Nil(t, version)
+
+	// Writing the same data again doesn't create a version
+	current, err := testState.GetVariable(nil, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	same := current.Copy()
+	resp = testState.VarSet(30, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: &same})
+	must.NoError(t, resp.Error)
+	must.Eq(t, 4, same.Version)
+
+	index, err := testState.Index(TableVariableVersions)
+	must.NoError(t, err)
+	must.Eq(t, 23, index)
+
+	// Deleting the variable keeps it as a deleted version
+	deleteTime := time.Now().UnixNano()
+	deleted := current.Copy()
+	deleted.DeleteTime = deleteTime
+	resp = testState.VarDelete(31, &structs.VarApplyStateRequest{Op: structs.VarOpDelete, Var: &deleted})
+	must.NoError(t, resp.Error)
+
+	out, err := testState.GetVariable(nil, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	must.Nil(t, out)
+
+	versions, err = testState.GetVariableVersions(nil, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	must.Len(t, 2, versions)
+	must.Eq(t, 4, versions[0].Version)
+	must.Eq(t, deleteTime, versions[0].DeleteTime)
+
+	iter, err := testState.VariableVersionsByDeleted(nil)
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	must.Eq(t, sv.Path, raw.(*structs.VariableEncrypted).Path)
+	must.Nil(t, iter.Next())
+
+	// Writing the variable again continues its history and clears the
+	// deleted mark
+	recreated := sv.Copy()
+	resp = testState.VarSet(32, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: &recreated})
+	must.NoError(t, resp.Error)
+	must.Eq(t, 5, resp.WrittenSVMeta.Version)
+
+	versions, err = testState.GetVariableVersions(nil, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	must.Eq(t, 4, versions[0].Version)
+	must.False(t, versions[0].IsDeleted())
+
+	index, err = testState.Index(TableVariableVersions)
+	must.NoError(t, err)
+	must.Eq(t, 32, index)
+
+	// The history of the other variable is untouched
+	versions, err = testState.GetVariableVersions(nil, other.Namespace, other.Path)
+	must.NoError(t, err)
+	must.SliceEmpty(t, versions)
+}
+
+func TestStateStore_VariableVersions_Disabled(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+	testState.config.VariablesTrackedVersions = 0
+
+	sv := mock.VariableEncrypted()
+	for i := 0; i < 3; i++ {
+		v := sv.Copy()
+		v.Data = []byte{byte(i)}
+		resp := testState.VarSet(uint64(20+i), &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: &v})
+		must.NoError(t, resp.Error)
+	}
+
+	versions, err := testState.GetVariableVersions(nil, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	must.SliceEmpty(t, versions)
+}
+
+func TestStateStore_VarsPurgeDeleted(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	now := time.Now()
+	insertIndex := uint64(20)
+
+	// Write and delete a variable that was deleted before the threshold, one
+	// that was deleted after it, and keep one that is not deleted.
+	deleteTimes := map[string]int64{
+		"not/deleted": 0,
+		"old":         now.Add(-time.Hour).UnixNano(),
+		"recent":      now.Add(time.Hour).UnixNano(),
+	}
+	for path, deleteTime := range deleteTimes {
+		for i := 0; i < 2; i++ {
+			sv := mock.VariableEncrypted()
+			sv.Namespace = structs.DefaultNamespace
+			sv.Path = path
+			sv.Data = []byte{byte(i)}
+			insertIndex++
+			resp := testState.VarSet(insertIndex, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: sv})
+			must.NoError(t, resp.Error)
+		}
+		if deleteTime == 0 {
+			continue
+		}
+		insertIndex++
+		resp := testState.VarDelete(insertIndex, &structs.VarApplyStateRequest{
+			Op: structs.VarOpDelete,
+			Var: &structs.VariableEncrypted{
+				VariableMetadata: structs.VariableMetadata{
+					Namespace:  structs.DefaultNamespace,
+					Path:       path,
+					DeleteTime: deleteTime,
+				},
+			},
+		})
+		must.NoError(t, resp.Error)
+	}
+
+	purgeIndex := insertIndex + 1
+	must.NoError(t, testState.VarsPurgeDeleted(structs.VariablesPurgeDeletedRequestType, purgeIndex, now))
+
+	expected := map[string]int{"not/deleted": 1, "old": 0, "recent": 2}
+	for path, count := range expected {
+		versions, err := testState.GetVariableVersions(nil, structs.DefaultNamespace, path)
+		must.NoError(t, err)
+		must.Len(t, count, versions, must.Sprintf("unexpected versions for %s", path))
+	}
+
+	index, err := testState.Index(TableVariableVersions)
+	must.NoError(t, err)
+	must.Eq(t, purgeIndex, index)
+
+	// Expired variables are removed with their history
+	sv := mock.VariableEncrypted()
+	sv.Namespace = structs.DefaultNamespace
+	sv.Path = "not/deleted"
+	resp := testState.VarDelete(purgeIndex+1, &structs.VarApplyStateRequest{Op: structs.VarOpDelete, Var: sv})
+	must.NoError(t, resp.Error)
+
+	versions, err := testState.GetVariableVersions(nil, structs.DefaultNamespace, "not/deleted")
+	must.NoError(t, err)
+	must.SliceEmpty(t, versions)
+}
+
+func TestStateStore_VarRekey(t *testing.T) {
+	ci.Parallel(t)
+	testState := testStateStore(t)
+
+	sv := mock.VariableEncrypted()
+	resp := testState.VarSet(10, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: sv})
+	must.NoError(t, resp.Error)
+	update := sv.Copy()
+	update.Data = []byte("updated")
+	resp = testState.VarSet(11, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: &update})
+	must.NoError(t, resp.Error)
+
+	// Re-key the current version
+	current, err := testState.GetVariable(nil, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	rekeyed := current.Copy()
+	rekeyed.KeyID = "new-key"
+	rekeyed.Data = []byte("re-encrypted")
+	resp = testState.VarRekey(12, &structs.VarApplyStateRequest{Op: structs.VarOpRekey, Var: &rekeyed})
+	must.NoError(t, resp.Error)
+	must.True(t, resp.IsOk())
+
+	out, err := testState.GetVariable(nil, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	must.Eq(t, "new-key", out.KeyID)
+	must.Eq(t, current.ModifyIndex, out.ModifyIndex)
+	must.Eq(t, current.Version, out.Version)
+
+	// Re-key the prior version
+	prior, err := testState.GetVariableVersion(nil, sv.Namespace, sv.Path, 1)
+	must.NoError(t, err)
+	rekeyed = prior.Copy()
+	rekeyed.KeyID = "new-key"
+	resp = testState.VarRekey(13, &structs.VarApplyStateRequest{Op: structs.VarOpRekey, Var: &rekeyed})
+	must.NoError(t, resp.Error)
+	must.True(t, resp.IsOk())
+
+	iter, err := testState.GetVariableVersionsByKeyID(nil, "new-key")
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	must.Eq(t, 1, raw.(*structs.VariableEncrypted).Version)
+
+	versions, err := testState.GetVariableVersions(nil, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	must.Len(t, 1, versions)
+
+	// A variable that was modified in the meantime is a conflict
+	rekeyed = current.Copy()
+	rekeyed.ModifyIndex = 1
+	resp = testState.VarRekey(14, &structs.VarApplyStateRequest{Op: structs.VarOpRekey, Var: &rekeyed})
+	must.True(t, resp.IsConflict())
+}
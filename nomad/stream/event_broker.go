@@ -373,6 +373,12 @@ func aclAllowsSubscription(aclObj *acl.ACL, subReq *SubscribeRequest) bool {
 			if ok := aclObj.IsManagement(); !ok {
 				return false
 			}
+		case structs.TopicVariable:
+			// Variable events only hold metadata, so any access to variables
+			// in the namespace is sufficient.
+			if ok := aclObj.AllowVariableSearch(subReq.Namespace); !ok {
+				return false
+			}
 		default:
 			if ok := aclObj.IsManagement(); !ok {
 				return false
@@ -29,6 +29,9 @@ type JsonStream struct {
 
 	outCh chan *structs.EventJson
 
+	// handle is the codec handle used to encode events.
+	handle codec.Handle
+
 	// heartbeat is the interval to send heartbeat messages to keep a connection
 	// open.
 	heartbeatTick *time.Ticker
@@ -42,6 +45,7 @@ func NewJsonStream(ctx context.Context, heartbeat time.Duration) *JsonStream {
 	s := &JsonStream{
 		ctx:           ctx,
 		outCh:         make(chan *structs.EventJson, 10),
+		handle:        structs.JsonHandleWithExtensions,
 		heartbeatTick: time.NewTicker(heartbeat),
 	}
 
@@ -51,6 +55,14 @@ func NewJsonStream(ctx context.Context, heartbeat time.Duration) *JsonStream {
 	return s
 }
 
+// NewMsgpackStream creates a stream like NewJsonStream that encodes events
+// with msgpack rather than JSON. Heartbeats are still the empty JSON object.
+func NewMsgpackStream(ctx context.Context, heartbeat time.Duration) *JsonStream {
+	s := NewJsonStream(ctx, heartbeat)
+	s.handle = structs.MsgpackHandle
+	return s
+}
+
 func (n *JsonStream) OutCh() chan *structs.EventJson {
 	return n.outCh
 }
@@ -71,18 +83,19 @@ func (n *JsonStream) heartbeat() {
 	}
 }
 
-// Send encodes an object into Newline delimited json. An error is returned
-// if json encoding fails or if the stream is no longer running.
+// Send encodes an object into Newline delimited json, or msgpack for streams
+// created with NewMsgpackStream. An error is returned if encoding fails or if
+// the stream is no longer running.
 func (n *JsonStream) Send(v interface{}) error {
 	if n.ctx.Err() != nil {
 		return n.ctx.Err()
 	}
 
 	var buf bytes.Buffer
-	enc := codec.NewEncoder(&buf, structs.JsonHandleWithExtensions)
+	enc := codec.NewEncoder(&buf, n.handle)
 	err := enc.Encode(v)
 	if err != nil {
-		return fmt.Errorf("error marshaling json for stream: %w", err)
+		return fmt.Errorf("error encoding event for stream: %w", err)
 	}
 
 	select {
@@ -8,7 +8,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/go-msgpack/codec"
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
 	"github.com/stretchr/testify/require"
 )
 
@@ -45,6 +47,26 @@ func TestJsonStream(t *testing.T) {
 	require.Equal(t, []byte(`{"name":"test2"}`), testMessage2.Data)
 }
 
+func TestMsgpackStream(t *testing.T) {
+	ci.Parallel(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := NewMsgpackStream(ctx, 1*time.Second)
+	out := s.OutCh()
+
+	require.NoError(t, s.Send(testObj{Name: "test"}))
+
+	initialHeartbeat := <-out
+	require.Equal(t, JsonHeartbeat, initialHeartbeat)
+
+	msg := <-out
+	var obj testObj
+	require.NoError(t, codec.NewDecoderBytes(msg.Data, structs.MsgpackHandle).Decode(&obj))
+	require.Equal(t, "test", obj.Name)
+}
+
 func TestJson_Send_After_Stop(t *testing.T) {
 	ci.Parallel(t)
 
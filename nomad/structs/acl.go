@@ -47,6 +47,21 @@ const (
 	// Reply: GenericResponse
 	ACLDeleteTokensRPCMethod = "ACL.DeleteTokens"
 
+	// ACLApproveTokenRPCMethod is the RPC method for approving a pending ACL
+	// token.
+	//
+	// Args: ACLTokenApproveRequest
+	// Reply: ACLTokenUpsertResponse
+	ACLApproveTokenRPCMethod = "ACL.ApproveToken"
+
+	// ACLConsumeTokenUseRPCMethod is the RPC method for recording a use of an
+	// ACL token with a maximum number of uses. This is an internal only RPC
+	// endpoint used by the servers when authenticating requests.
+	//
+	// Args: ACLTokenUseRequest
+	// Reply: GenericResponse
+	ACLConsumeTokenUseRPCMethod = "ACL.ConsumeTokenUse"
+
 	// ACLUpsertRolesRPCMethod is the RPC method for batch creating or
 	// modifying ACL roles.
 	//
@@ -173,6 +188,22 @@ const (
 	// Reply: ACLOIDCCompleteAuthResponse
 	ACLOIDCCompleteAuthRPCMethod = "ACL.OIDCCompleteAuth"
 
+	// ACLOIDCDeviceAuthRPCMethod is the RPC method for starting the OIDC
+	// device authorization workflow. It requests a device and user code from
+	// the OIDC provider, which the user enters on another device.
+	//
+	// Args: ACLOIDCDeviceAuthRequest
+	// Reply: ACLOIDCDeviceAuthResponse
+	ACLOIDCDeviceAuthRPCMethod = "ACL.OIDCDeviceAuth"
+
+	// ACLOIDCCompleteDeviceAuthRPCMethod is the RPC method for completing the
+	// OIDC device authorization workflow. It exchanges the device code for a
+	// Nomad ACL token once the user has authorized the device.
+	//
+	// Args: ACLOIDCCompleteDeviceAuthRequest
+	// Reply: ACLOIDCCompleteDeviceAuthResponse
+	ACLOIDCCompleteDeviceAuthRPCMethod = "ACL.OIDCCompleteDeviceAuth"
+
 	// ACLLoginRPCMethod is the RPC method for performing a non-OIDC login
 	// workflow. It exchanges the provided token for a Nomad ACL token with
 	// roles as defined within the remote provider.
@@ -180,6 +211,14 @@ const (
 	// Args: ACLLoginRequest
 	// Reply: ACLLoginResponse
 	ACLLoginRPCMethod = "ACL.Login"
+
+	// ACLCreateChildIdentityRPCMethod is the RPC method for creating a
+	// short-lived child identity, scoped to a subset of capabilities, from the
+	// workload identity used to authenticate the request.
+	//
+	// Args: ChildIdentityRequest
+	// Reply: ChildIdentityResponse
+	ACLCreateChildIdentityRPCMethod = "ACL.CreateChildIdentity"
 )
 
 const (
@@ -333,6 +372,14 @@ func (a *ACLToken) Validate(minTTL, maxTTL time.Duration, existing *ACLToken) er
 	// is being created or updated.
 	switch existing {
 	case nil:
+		if a.MaxUses > 0 && a.Global {
+			mErr.Errors = append(mErr.Errors, errors.New("tokens with maximum uses must be local"))
+		}
+
+		if a.NotBefore != nil && a.HasExpirationTime() && !a.NotBefore.Before(*a.ExpirationTime) {
+			mErr.Errors = append(mErr.Errors, errors.New("not-before time must be before expiration time"))
+		}
+
 		if a.ExpirationTTL < 0 {
 			mErr.Errors = append(mErr.Errors,
 				fmt.Errorf("token expiration TTL '%s' should not be negative", a.ExpirationTTL))
@@ -370,6 +417,14 @@ func (a *ACLToken) Validate(minTTL, maxTTL time.Duration, existing *ACLToken) er
 				mErr.Errors = append(mErr.Errors, errors.New("cannot update expiration time"))
 			}
 		}
+		if a.NotBefore != nil {
+			if existing.NotBefore == nil || !existing.NotBefore.Equal(*a.NotBefore) {
+				mErr.Errors = append(mErr.Errors, errors.New("cannot update not-before time"))
+			}
+		}
+		if a.MaxUses != 0 && existing.MaxUses != a.MaxUses {
+			mErr.Errors = append(mErr.Errors, errors.New("cannot update maximum uses"))
+		}
 
 	}
 
@@ -385,6 +440,14 @@ func (a *ACLToken) HasExpirationTime() bool {
 	return !a.ExpirationTime.IsZero()
 }
 
+// HasRole checks whether the ACL token is linked to the ACL role with the
+// given ID.
+func (a *ACLToken) HasRole(roleID string) bool {
+	return slices.ContainsFunc(a.Roles, func(link *ACLTokenRoleLink) bool {
+		return link.ID == roleID
+	})
+}
+
 // IsExpired compares the ACLToken.ExpirationTime against the passed t to
 // identify whether the token is considered expired. The function can be called
 // without checking whether the ACL token has an expiry time.
@@ -405,6 +468,24 @@ func (a *ACLToken) IsExpired(t time.Time) bool {
 	return a.ExpirationTime.Before(t) || t.IsZero()
 }
 
+// Usable checks whether the ACL token can authenticate requests at the passed
+// time t, returning the reason it can't otherwise. Expired, pending, not yet
+// valid, and exhausted tokens can't be used.
+func (a *ACLToken) Usable(t time.Time) error {
+	switch {
+	case a.IsExpired(t):
+		return ErrTokenExpired
+	case a.Pending:
+		return ErrTokenPending
+	case a.NotBefore != nil && t.Before(*a.NotBefore):
+		return ErrTokenNotYetValid
+	case a.MaxUses > 0 && a.Uses >= a.MaxUses:
+		return ErrTokenExhausted
+	default:
+		return nil
+	}
+}
+
 // HasRoles checks if a given set of role IDs are assigned to the ACL token. It
 // does not account for management tokens, therefore it is the responsibility
 // of the caller to perform this check, if required.
@@ -500,6 +581,12 @@ type ACLRole struct {
 	// both.
 	Policies []*ACLRolePolicyLink
 
+	// RequireApproval makes the tokens linked to the role pending when they
+	// are created, so they must be approved by an operator other than their
+	// creator before they can be used. Existing tokens can't be linked to
+	// the role. Tokens created before it was set are not affected.
+	RequireApproval bool
+
 	// Hash is the hashed value of the role and is generated using all fields
 	// above this point.
 	Hash []byte
@@ -535,6 +622,9 @@ func (a *ACLRole) SetHash() []byte {
 	for _, policyLink := range a.Policies {
 		_, _ = hash.Write([]byte(policyLink.Name))
 	}
+	if a.RequireApproval {
+		_, _ = hash.Write([]byte("require-approval"))
+	}
 
 	// Finalize the hash.
 	hashVal := hash.Sum(nil)
@@ -1630,6 +1720,97 @@ func (a *ACLOIDCCompleteAuthRequest) Validate() error {
 	return mErr.ErrorOrNil()
 }
 
+// ACLOIDCDeviceAuthRequest is the request to make when starting the OIDC
+// device authorization login flow.
+type ACLOIDCDeviceAuthRequest struct {
+
+	// AuthMethodName is the OIDC auth-method to use. This is a required
+	// parameter.
+	AuthMethodName string
+
+	// WriteRequest is used due to the requirement by the RPC forwarding
+	// mechanism. This request doesn't write anything to Nomad's internal
+	// state.
+	WriteRequest
+}
+
+// Validate ensures the request object contains all the required fields in
+// order to start the OIDC device authorization flow.
+func (a *ACLOIDCDeviceAuthRequest) Validate() error {
+	if a.AuthMethodName == "" {
+		return errors.New("missing auth method name")
+	}
+	return nil
+}
+
+// ACLOIDCDeviceAuthResponse is the response when starting the OIDC device
+// authorization login flow.
+type ACLOIDCDeviceAuthResponse struct {
+
+	// DeviceCode is the code used to complete the login flow once the user
+	// has authorized the device. It must be kept secret.
+	DeviceCode string
+
+	// UserCode is the code the user enters at the verification URI.
+	UserCode string
+
+	// VerificationURI is the URI the user visits to authorize the device, and
+	// VerificationURIComplete includes the user code, if the provider
+	// supports it.
+	VerificationURI         string
+	VerificationURIComplete string
+
+	// ExpiresIn is how long the device and user codes are valid for.
+	ExpiresIn time.Duration
+
+	// Interval is how long to wait between attempts to complete the login
+	// flow.
+	Interval time.Duration
+}
+
+// ACLOIDCCompleteDeviceAuthRequest is the request object to complete the OIDC
+// device authorization flow.
+type ACLOIDCCompleteDeviceAuthRequest struct {
+
+	// AuthMethodName is the name of the auth method being used to login. This
+	// will match ACLOIDCDeviceAuthRequest.AuthMethodName. This is a required
+	// parameter.
+	AuthMethodName string
+
+	// DeviceCode is the device code returned when starting the flow. This is
+	// a required parameter.
+	DeviceCode string
+
+	WriteRequest
+}
+
+// Validate ensures the request object contains all the required fields in
+// order to complete the OIDC device authorization flow.
+func (a *ACLOIDCCompleteDeviceAuthRequest) Validate() error {
+
+	var mErr multierror.Error
+
+	if a.AuthMethodName == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing auth method name"))
+	}
+	if a.DeviceCode == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing device code"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ACLOIDCCompleteDeviceAuthResponse is the response when completing the OIDC
+// device authorization flow. If the user hasn't authorized the device yet,
+// the ACL token is nil and Pending is set, in which case the request should
+// be retried after the polling interval. SlowDown indicates the provider
+// asked for the polling interval to be increased.
+type ACLOIDCCompleteDeviceAuthResponse struct {
+	ACLToken *ACLToken
+	Pending  bool
+	SlowDown bool
+	WriteMeta
+}
+
 // ACLLoginResponse is the response when the auth flow has been
 // completed successfully.
 type ACLLoginResponse struct {
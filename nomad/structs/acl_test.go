@@ -189,6 +189,56 @@ func TestACLTokenValidate(t *testing.T) {
 			inputExistingACLToken: nil,
 			expectedErrorContains: "",
 		},
+		{
+			name: "global with max uses",
+			inputACLToken: &ACLToken{
+				Type:    ACLManagementToken,
+				Name:    "foo",
+				Global:  true,
+				MaxUses: 5,
+			},
+			inputExistingACLToken: nil,
+			expectedErrorContains: "tokens with maximum uses must be local",
+		},
+		{
+			name: "not before after expiration",
+			inputACLToken: &ACLToken{
+				Type:           ACLManagementToken,
+				Name:           "foo",
+				CreateTime:     time.Date(2022, time.July, 11, 16, 23, 0, 0, time.UTC),
+				ExpirationTime: pointer.Of(time.Date(2022, time.July, 11, 17, 23, 0, 0, time.UTC)),
+				NotBefore:      pointer.Of(time.Date(2022, time.July, 11, 18, 23, 0, 0, time.UTC)),
+			},
+			inputExistingACLToken: nil,
+			expectedErrorContains: "not-before time must be before expiration time",
+		},
+		{
+			name: "valid not before and max uses",
+			inputACLToken: &ACLToken{
+				Type:           ACLManagementToken,
+				Name:           "foo",
+				CreateTime:     time.Date(2022, time.July, 11, 16, 23, 0, 0, time.UTC),
+				ExpirationTime: pointer.Of(time.Date(2022, time.July, 11, 18, 23, 0, 0, time.UTC)),
+				NotBefore:      pointer.Of(time.Date(2022, time.July, 11, 17, 23, 0, 0, time.UTC)),
+				MaxUses:        5,
+			},
+			inputExistingACLToken: nil,
+			expectedErrorContains: "",
+		},
+		{
+			name: "updated max uses",
+			inputACLToken: &ACLToken{
+				Type:    ACLManagementToken,
+				Name:    "foo",
+				MaxUses: 10,
+			},
+			inputExistingACLToken: &ACLToken{
+				Type:    ACLManagementToken,
+				Name:    "foo",
+				MaxUses: 5,
+			},
+			expectedErrorContains: "cannot update maximum uses",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -303,6 +353,61 @@ func TestACLToken_IsExpired(t *testing.T) {
 	}
 }
 
+func TestACLToken_Usable(t *testing.T) {
+	now := time.Date(2022, time.May, 9, 10, 27, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		inputACLToken *ACLToken
+		expectedError error
+	}{
+		{
+			name:          "token without restrictions",
+			inputACLToken: &ACLToken{},
+		},
+		{
+			name: "token expired",
+			inputACLToken: &ACLToken{
+				ExpirationTime: pointer.Of(now.Add(-time.Minute)),
+			},
+			expectedError: ErrTokenExpired,
+		},
+		{
+			name:          "token pending",
+			inputACLToken: &ACLToken{Pending: true},
+			expectedError: ErrTokenPending,
+		},
+		{
+			name: "token not yet valid",
+			inputACLToken: &ACLToken{
+				NotBefore: pointer.Of(now.Add(time.Minute)),
+			},
+			expectedError: ErrTokenNotYetValid,
+		},
+		{
+			name: "token past not-before time",
+			inputACLToken: &ACLToken{
+				NotBefore: pointer.Of(now.Add(-time.Minute)),
+			},
+		},
+		{
+			name:          "token with uses left",
+			inputACLToken: &ACLToken{MaxUses: 2, Uses: 1},
+		},
+		{
+			name:          "token exhausted",
+			inputACLToken: &ACLToken{MaxUses: 2, Uses: 2},
+			expectedError: ErrTokenExhausted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			must.Eq(t, tc.expectedError, tc.inputACLToken.Usable(now))
+		})
+	}
+}
+
 func TestACLToken_HasRoles(t *testing.T) {
 	testCases := []struct {
 		name           string
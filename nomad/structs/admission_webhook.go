@@ -0,0 +1,34 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+// AdmissionWebhookRequest is the body of the request that servers send to an
+// admission webhook when a job is registered, planned, or validated.
+type AdmissionWebhookRequest struct {
+	// Webhook is the name of the webhook being called.
+	Webhook string
+
+	// Type is the type of the webhook, either "mutating" or "validating".
+	Type string
+
+	// Job is the job being admitted.
+	Job *Job
+}
+
+// AdmissionWebhookResponse is the body of the response that an admission
+// webhook returns.
+type AdmissionWebhookResponse struct {
+	// Allowed is whether the job is admitted.
+	Allowed bool
+
+	// Reason is the reason the job was rejected, and is returned to the user.
+	Reason string
+
+	// Warnings are returned to the user whether or not the job is admitted.
+	Warnings []string
+
+	// Job is the job as modified by a mutating webhook. The job is admitted
+	// unchanged if omitted. It is ignored for validating webhooks.
+	Job *Job
+}
@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/hashstructure"
+)
+
+// AllocationDelta is an allocation sent by the servers to a client that
+// already has a version of the allocation, without the fields that only change
+// along with the version of the job. The job and the allocated resources are
+// the bulk of an allocation, while updates of a running allocation usually
+// only change its desired status or transition, so omitting them cuts the size
+// of the updates sent to clients running many allocations.
+type AllocationDelta struct {
+	// Alloc is the allocation without its Job and AllocatedResources.
+	Alloc *AllocationDiff
+
+	// Checksum is the checksum of the omitted fields, which the client
+	// verifies against the fields of its version of the allocation.
+	Checksum uint64
+}
+
+// allocationDeltaFields are the fields the checksum of an AllocationDelta is
+// computed over. The job is identified by its namespace, ID and modify index
+// rather than hashed in full, since the servers compute a checksum for every
+// delta sent to every client and a job is only ever modified along with its
+// JobModifyIndex.
+type allocationDeltaFields struct {
+	Namespace          string
+	JobID              string
+	JobVersion         uint64
+	JobModifyIndex     uint64
+	AllocatedResources *AllocatedResources
+}
+
+// allocationDeltaChecksum returns the checksum of the fields of the allocation
+// omitted from an AllocationDelta.
+func allocationDeltaChecksum(alloc *Allocation) (uint64, error) {
+	fields := allocationDeltaFields{
+		AllocatedResources: alloc.AllocatedResources,
+	}
+	if alloc.Job != nil {
+		fields.Namespace = alloc.Job.Namespace
+		fields.JobID = alloc.Job.ID
+		fields.JobVersion = alloc.Job.Version
+		fields.JobModifyIndex = alloc.Job.JobModifyIndex
+	}
+	return hashstructure.Hash(fields, nil)
+}
+
+// NewAllocationDelta returns the delta of the allocation for a client whose
+// version of the allocation has the job at the given JobModifyIndex, or nil if
+// the client's version doesn't have the same job and the allocation must be
+// sent in full.
+func NewAllocationDelta(alloc *Allocation, jobModifyIndex uint64) (*AllocationDelta, error) {
+	if alloc.Job == nil || alloc.Job.JobModifyIndex != jobModifyIndex {
+		return nil, nil
+	}
+
+	checksum, err := allocationDeltaChecksum(alloc)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := new(AllocationDiff)
+	*diff = AllocationDiff(*alloc)
+	diff.Job = nil
+	diff.AllocatedResources = nil
+	return &AllocationDelta{
+		Alloc:    diff,
+		Checksum: checksum,
+	}, nil
+}
+
+// Apply reconstructs the allocation from the delta and the client's version of
+// the allocation. It returns an error if the reconstructed allocation doesn't
+// match the checksum of the delta, in which case the allocation must be pulled
+// in full.
+func (d *AllocationDelta) Apply(base *Allocation) (*Allocation, error) {
+	if base == nil {
+		return nil, fmt.Errorf("missing allocation %s to apply delta to", d.Alloc.ID)
+	}
+
+	alloc := new(Allocation)
+	*alloc = Allocation(*d.Alloc)
+	alloc.Job = base.Job
+	alloc.AllocatedResources = base.AllocatedResources
+
+	checksum, err := allocationDeltaChecksum(alloc)
+	if err != nil {
+		return nil, err
+	}
+	if checksum != d.Checksum {
+		return nil, fmt.Errorf("checksum mismatch applying delta of allocation %s", d.Alloc.ID)
+	}
+	return alloc, nil
+}
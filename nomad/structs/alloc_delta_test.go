@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestAllocationDelta(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := MockAlloc()
+	alloc.Job.JobModifyIndex = 10
+
+	// A client with another version of the job gets the allocation in full
+	delta, err := NewAllocationDelta(alloc, 9)
+	must.NoError(t, err)
+	must.Nil(t, delta)
+
+	base := alloc.Copy()
+	alloc.DesiredStatus = AllocDesiredStatusStop
+	alloc.AllocModifyIndex++
+
+	delta, err = NewAllocationDelta(alloc, 10)
+	must.NoError(t, err)
+	must.NotNil(t, delta)
+	must.Nil(t, delta.Alloc.Job)
+	must.Nil(t, delta.Alloc.AllocatedResources)
+	must.NotNil(t, alloc.Job)
+
+	out, err := delta.Apply(base)
+	must.NoError(t, err)
+	must.Eq(t, AllocDesiredStatusStop, out.DesiredStatus)
+	must.Eq(t, alloc.AllocModifyIndex, out.AllocModifyIndex)
+	must.Eq(t, base.Job, out.Job)
+	must.Eq(t, base.AllocatedResources, out.AllocatedResources)
+
+	// The checksum catches a version of the allocation with another job
+	base.Job.JobModifyIndex = 11
+	_, err = delta.Apply(base)
+	must.ErrorContains(t, err, "checksum mismatch")
+
+	// The checksum catches a version of the allocation with other resources
+	base = alloc.Copy()
+	base.AllocatedResources.Shared.DiskMB++
+	_, err = delta.Apply(base)
+	must.ErrorContains(t, err, "checksum mismatch")
+
+	_, err = delta.Apply(nil)
+	must.ErrorContains(t, err, "missing allocation")
+}
@@ -0,0 +1,233 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// ClientUpgradeStatusRunning is the status of a client upgrade whose
+	// nodes are being restarted.
+	ClientUpgradeStatusRunning = "running"
+
+	// ClientUpgradeStatusPaused is the status of a client upgrade that was
+	// paused by an operator or because a node failed to become healthy.
+	ClientUpgradeStatusPaused = "paused"
+
+	// ClientUpgradeStatusComplete is the status of a client upgrade whose
+	// nodes were all restarted.
+	ClientUpgradeStatusComplete = "complete"
+
+	// ClientUpgradeStatusCancelled is the status of a client upgrade that was
+	// cancelled by an operator.
+	ClientUpgradeStatusCancelled = "cancelled"
+
+	// ClientUpgradeNodeStatusPending is the status of a node that hasn't been
+	// restarted yet.
+	ClientUpgradeNodeStatusPending = "pending"
+
+	// ClientUpgradeNodeStatusRestarting is the status of a node that was
+	// selected to be restarted, and that hasn't become healthy yet.
+	ClientUpgradeNodeStatusRestarting = "restarting"
+
+	// ClientUpgradeNodeStatusHealthy is the status of a node that became
+	// healthy after its restart.
+	ClientUpgradeNodeStatusHealthy = "healthy"
+
+	// ClientUpgradeNodeStatusFailed is the status of a node that failed to
+	// become healthy after its restart.
+	ClientUpgradeNodeStatusFailed = "failed"
+
+	// ClientUpgradeNodeStatusSkipped is the status of a node that wasn't
+	// restarted, because it was down or already ran the target version when
+	// its turn came.
+	ClientUpgradeNodeStatusSkipped = "skipped"
+
+	// ClientUpgradeVersionAttr is the node attribute that holds the version
+	// of the Nomad agent running on the client.
+	ClientUpgradeVersionAttr = "nomad.version"
+)
+
+// ClientUpgrade is a rolling restart of the client agents of a node pool,
+// coordinated by the leader. The leader selects the nodes to restart in
+// batches of MaxParallel, marks them ineligible for scheduling, and waits for
+// them to re-register and restore their allocations before marking them
+// eligible again and moving on to the next batch. The agents themselves are
+// restarted by the operator, usually with the `nomad operator upgrade clients`
+// command, since Nomad can't replace its own binary.
+type ClientUpgrade struct {
+	// ID is the UUID of the client upgrade.
+	ID string
+
+	// NodePool is the node pool whose nodes are restarted, or empty for all
+	// the node pools.
+	NodePool string
+
+	// Version is the version of Nomad the nodes must report after their
+	// restart. Nodes that already report it are skipped. If empty, any
+	// version is accepted.
+	Version string
+
+	// MaxParallel is the number of nodes restarted at once.
+	MaxParallel int
+
+	// HealthTimeout is how long a node has to become healthy after it was
+	// selected to be restarted, after which the upgrade is paused.
+	HealthTimeout time.Duration
+
+	// Status is the status of the upgrade, and StatusDescription explains
+	// why it was paused or cancelled.
+	Status            string
+	StatusDescription string
+
+	// Nodes are the nodes of the upgrade, in the order they are restarted.
+	Nodes []*ClientUpgradeNode
+
+	// Raft indexes.
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// ClientUpgradeNode is the progress of a node of a client upgrade.
+type ClientUpgradeNode struct {
+	NodeID string
+	Name   string
+
+	// Status is the status of the node within the upgrade, and
+	// StatusDescription explains why it failed or was skipped.
+	Status            string
+	StatusDescription string
+
+	// MarkedIneligible is set while the node is ineligible for scheduling
+	// because the upgrade marked it so. The leader marks these nodes eligible
+	// again once they are healthy, or when the upgrade is paused or
+	// cancelled, so a failed upgrade doesn't leave nodes ineligible.
+	MarkedIneligible bool
+
+	// RunningAllocs are the IDs of the allocations that were running on the
+	// node when it was selected to be restarted, which must be running again
+	// for the node to be healthy.
+	RunningAllocs []string
+
+	// RestartIndex is the modify index of the node when it was selected to
+	// be restarted. The node must re-register after it to be healthy.
+	RestartIndex uint64
+
+	// RestartTime is when the node was selected to be restarted.
+	RestartTime time.Time
+}
+
+// Validate returns an error if the client upgrade is invalid.
+func (u *ClientUpgrade) Validate() error {
+	var mErr *multierror.Error
+
+	if u.MaxParallel < 1 {
+		mErr = multierror.Append(mErr, fmt.Errorf("max parallel %d must be at least 1", u.MaxParallel))
+	}
+	if u.HealthTimeout <= 0 {
+		mErr = multierror.Append(mErr, errors.New("health timeout must be greater than zero"))
+	}
+	for _, node := range u.Nodes {
+		if node.NodeID == "" {
+			mErr = multierror.Append(mErr, errors.New("node ID is empty"))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Terminal returns true if the upgrade is complete or cancelled.
+func (u *ClientUpgrade) Terminal() bool {
+	return u.Status == ClientUpgradeStatusComplete || u.Status == ClientUpgradeStatusCancelled
+}
+
+// NodesWithStatus returns the nodes of the upgrade with the given status.
+func (u *ClientUpgrade) NodesWithStatus(status string) []*ClientUpgradeNode {
+	var nodes []*ClientUpgradeNode
+	for _, node := range u.Nodes {
+		if node.Status == status {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// Copy returns a copy of the client upgrade.
+func (u *ClientUpgrade) Copy() *ClientUpgrade {
+	if u == nil {
+		return nil
+	}
+
+	nu := new(ClientUpgrade)
+	*nu = *u
+	nu.Nodes = make([]*ClientUpgradeNode, len(u.Nodes))
+	for i, node := range u.Nodes {
+		nn := new(ClientUpgradeNode)
+		*nn = *node
+		nn.RunningAllocs = slices.Clone(node.RunningAllocs)
+		nu.Nodes[i] = nn
+	}
+	return nu
+}
+
+// ClientUpgradeListRequest is used to list the client upgrades.
+type ClientUpgradeListRequest struct {
+	QueryOptions
+}
+
+// ClientUpgradeListResponse is the response to a client upgrades list
+// request. The upgrades are sorted by creation index.
+type ClientUpgradeListResponse struct {
+	Upgrades []*ClientUpgrade
+	QueryMeta
+}
+
+// ClientUpgradeSpecificRequest is used to read a client upgrade.
+type ClientUpgradeSpecificRequest struct {
+	ID string
+	QueryOptions
+}
+
+// ClientUpgradeResponse is the response to a client upgrade read request, or
+// to a request that starts or updates a client upgrade.
+type ClientUpgradeResponse struct {
+	Upgrade *ClientUpgrade
+	QueryMeta
+}
+
+// ClientUpgradeStartRequest is used to start a client upgrade. The nodes of
+// the upgrade are selected by the servers.
+type ClientUpgradeStartRequest struct {
+	Upgrade *ClientUpgrade
+	WriteRequest
+}
+
+// ClientUpgradeUpdateStatusRequest is used to pause, resume or cancel a client
+// upgrade.
+type ClientUpgradeUpdateStatusRequest struct {
+	ID string
+
+	// Status is the new status of the upgrade, either running, paused or
+	// cancelled.
+	Status string
+
+	// Reason optionally explains why the upgrade is paused or cancelled.
+	Reason string
+
+	WriteRequest
+}
+
+// ClientUpgradeUpsertRequest is the raft request that writes a client
+// upgrade. Upserting a new upgrade removes the terminal upgrades, so only the
+// latest finished upgrade is kept.
+type ClientUpgradeUpsertRequest struct {
+	Upgrade *ClientUpgrade
+	WriteRequest
+}
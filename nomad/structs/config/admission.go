@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// AdmissionWebhookTypeMutating webhooks may modify or reject jobs.
+	AdmissionWebhookTypeMutating = "mutating"
+
+	// AdmissionWebhookTypeValidating webhooks may only reject jobs.
+	AdmissionWebhookTypeValidating = "validating"
+
+	// AdmissionWebhookFailurePolicyFail rejects the job when the webhook
+	// can't be reached or returns an invalid response.
+	AdmissionWebhookFailurePolicyFail = "fail"
+
+	// AdmissionWebhookFailurePolicyIgnore admits the job unchanged when the
+	// webhook can't be reached or returns an invalid response.
+	AdmissionWebhookFailurePolicyIgnore = "ignore"
+
+	// DefaultAdmissionWebhookTimeout is how long a webhook call may take if
+	// no timeout is configured.
+	DefaultAdmissionWebhookTimeout = 10 * time.Second
+)
+
+// AdmissionWebhookConfig configures an HTTP endpoint that servers call to
+// mutate or validate jobs before they are registered or planned.
+type AdmissionWebhookConfig struct {
+	// Name is a unique name given to the webhook
+	Name string `hcl:",key"`
+
+	// Type is either "mutating" or "validating". Mutating webhooks run before
+	// the job is validated and validating webhooks run last.
+	Type string `hcl:"type"`
+
+	// Address is the URL that jobs are posted to.
+	Address string `hcl:"address"`
+
+	// Timeout is how long to wait for a response from the webhook.
+	Timeout    time.Duration `hcl:"-"`
+	TimeoutHCL string        `hcl:"timeout" json:"-"`
+
+	// FailurePolicy is either "fail" or "ignore", and decides whether a job is
+	// admitted when the webhook can't be reached or returns an invalid
+	// response. Defaults to "fail".
+	FailurePolicy string `hcl:"failure_policy"`
+
+	// Namespaces limits the webhook to jobs in the listed namespaces. The
+	// webhook is called for jobs in all namespaces if empty.
+	Namespaces []string `hcl:"namespaces"`
+
+	// Headers are added to every request to the webhook, such as for
+	// authentication.
+	Headers map[string]string `hcl:"headers" json:"-"`
+
+	// CAFile is the path to a PEM encoded CA certificate used to verify the
+	// webhook's certificate, when its address uses https.
+	CAFile string `hcl:"ca_file"`
+
+	// TLSSkipVerify disables verification of the webhook's certificate.
+	TLSSkipVerify bool `hcl:"tls_skip_verify"`
+}
+
+// Copy returns a deep copy of the webhook configuration.
+func (a *AdmissionWebhookConfig) Copy() *AdmissionWebhookConfig {
+	if a == nil {
+		return nil
+	}
+
+	nc := new(AdmissionWebhookConfig)
+	*nc = *a
+	nc.Namespaces = slices.Clone(a.Namespaces)
+	nc.Headers = maps.Clone(a.Headers)
+	return nc
+}
+
+// Canonicalize sets the defaults of unset fields.
+func (a *AdmissionWebhookConfig) Canonicalize() {
+	if a.Timeout == 0 {
+		a.Timeout = DefaultAdmissionWebhookTimeout
+	}
+	if a.FailurePolicy == "" {
+		a.FailurePolicy = AdmissionWebhookFailurePolicyFail
+	}
+}
+
+// Validate returns an error if the webhook configuration is invalid.
+func (a *AdmissionWebhookConfig) Validate() error {
+	var mErr multierror.Error
+
+	if a.Name == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing name"))
+	}
+
+	switch a.Type {
+	case AdmissionWebhookTypeMutating, AdmissionWebhookTypeValidating:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("type must be %q or %q, got %q",
+			AdmissionWebhookTypeMutating, AdmissionWebhookTypeValidating, a.Type))
+	}
+
+	if a.Address == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing address"))
+	} else if u, err := url.Parse(a.Address); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid address: %v", err))
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("address scheme must be http or https, got %q", u.Scheme))
+	}
+
+	if a.Timeout < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("timeout must not be negative"))
+	}
+
+	switch a.FailurePolicy {
+	case "", AdmissionWebhookFailurePolicyFail, AdmissionWebhookFailurePolicyIgnore:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("failure_policy must be %q or %q, got %q",
+			AdmissionWebhookFailurePolicyFail, AdmissionWebhookFailurePolicyIgnore, a.FailurePolicy))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// AppliesTo returns whether the webhook is called for jobs in the namespace.
+func (a *AdmissionWebhookConfig) AppliesTo(namespace string) bool {
+	return len(a.Namespaces) == 0 || slices.Contains(a.Namespaces, namespace)
+}
+
+// AdmissionWebhookSliceMerge merges two slices of webhook configurations.
+// Webhooks in b replace those in a with the same name, and are otherwise
+// appended so that the order in which webhooks are called is preserved.
+func AdmissionWebhookSliceMerge(a, b []*AdmissionWebhookConfig) []*AdmissionWebhookConfig {
+	n := make([]*AdmissionWebhookConfig, len(a))
+	seenKeys := make(map[string]int, len(a))
+
+	for i, config := range a {
+		n[i] = config.Copy()
+		seenKeys[config.Name] = i
+	}
+
+	for _, config := range b {
+		if fIndex, ok := seenKeys[config.Name]; ok {
+			n[fIndex] = config.Copy()
+			continue
+		}
+
+		n = append(n, config.Copy())
+	}
+
+	if len(n) == 0 {
+		return nil
+	}
+	return n
+}
@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestAdmissionWebhookConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	valid := &AdmissionWebhookConfig{
+		Name:    "team",
+		Type:    AdmissionWebhookTypeMutating,
+		Address: "https://webhooks.example.com/team",
+	}
+	must.NoError(t, valid.Validate())
+
+	invalid := &AdmissionWebhookConfig{
+		Type:          "bogus",
+		Address:       "ftp://webhooks.example.com",
+		Timeout:       -time.Second,
+		FailurePolicy: "bogus",
+	}
+	err := invalid.Validate()
+	must.ErrorContains(t, err, "missing name")
+	must.ErrorContains(t, err, `type must be "mutating" or "validating", got "bogus"`)
+	must.ErrorContains(t, err, `address scheme must be http or https, got "ftp"`)
+	must.ErrorContains(t, err, "timeout must not be negative")
+	must.ErrorContains(t, err, `failure_policy must be "fail" or "ignore", got "bogus"`)
+
+	invalid = &AdmissionWebhookConfig{Name: "team", Type: AdmissionWebhookTypeValidating}
+	must.ErrorContains(t, invalid.Validate(), "missing address")
+}
+
+func TestAdmissionWebhookConfig_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	c := &AdmissionWebhookConfig{}
+	c.Canonicalize()
+	must.Eq(t, DefaultAdmissionWebhookTimeout, c.Timeout)
+	must.Eq(t, AdmissionWebhookFailurePolicyFail, c.FailurePolicy)
+
+	c = &AdmissionWebhookConfig{
+		Timeout:       time.Second,
+		FailurePolicy: AdmissionWebhookFailurePolicyIgnore,
+	}
+	c.Canonicalize()
+	must.Eq(t, time.Second, c.Timeout)
+	must.Eq(t, AdmissionWebhookFailurePolicyIgnore, c.FailurePolicy)
+}
+
+func TestAdmissionWebhookConfig_AppliesTo(t *testing.T) {
+	ci.Parallel(t)
+
+	c := &AdmissionWebhookConfig{}
+	must.True(t, c.AppliesTo("default"))
+
+	c.Namespaces = []string{"prod"}
+	must.True(t, c.AppliesTo("prod"))
+	must.False(t, c.AppliesTo("default"))
+}
+
+func TestAdmissionWebhookSliceMerge(t *testing.T) {
+	ci.Parallel(t)
+
+	a := []*AdmissionWebhookConfig{
+		{Name: "one", Address: "http://one"},
+		{Name: "two", Address: "http://two", Headers: map[string]string{"X-Token": "a"}},
+	}
+	b := []*AdmissionWebhookConfig{
+		{Name: "two", Address: "http://two.new"},
+		{Name: "three", Address: "http://three"},
+	}
+
+	result := AdmissionWebhookSliceMerge(a, b)
+	must.Eq(t, []*AdmissionWebhookConfig{
+		{Name: "one", Address: "http://one"},
+		{Name: "two", Address: "http://two.new"},
+		{Name: "three", Address: "http://three"},
+	}, result)
+
+	// The inputs are copied
+	result[0].Address = "http://changed"
+	must.Eq(t, "http://one", a[0].Address)
+
+	must.Nil(t, AdmissionWebhookSliceMerge(nil, nil))
+}
@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"slices"
+
+	"github.com/hashicorp/nomad/helper/pointer"
+)
+
+// AllocHookScriptConfig configures a script the client runs on the host at
+// allocation lifecycle points.
+type AllocHookScriptConfig struct {
+	// Name is the unique name of the hook.
+	Name string `hcl:",key"`
+
+	// Command is the path of the executable to run.
+	Command string `hcl:"command"`
+
+	// Args are the arguments passed to the command.
+	Args []string `hcl:"args"`
+
+	// Stages are the allocation lifecycle points the command is run at. Valid
+	// stages are "prerun", "postrun", and "destroy".
+	Stages []string `hcl:"stages"`
+
+	// Timeout is how long the command may run before it is killed.
+	Timeout *string `hcl:"timeout"`
+
+	// FailurePolicy is either "fail" to fail the allocation when the command
+	// fails during prerun, or "ignore" to only log the failure.
+	FailurePolicy *string `hcl:"failure_policy"`
+}
+
+// Copy returns a deep copy of the AllocHookScriptConfig struct.
+func (a *AllocHookScriptConfig) Copy() *AllocHookScriptConfig {
+	if a == nil {
+		return nil
+	}
+	return &AllocHookScriptConfig{
+		Name:          a.Name,
+		Command:       a.Command,
+		Args:          slices.Clone(a.Args),
+		Stages:        slices.Clone(a.Stages),
+		Timeout:       pointer.Copy(a.Timeout),
+		FailurePolicy: pointer.Copy(a.FailurePolicy),
+	}
+}
+
+// AllocHookScriptSliceMerge merges two slices of AllocHookScriptConfig, where
+// hooks in b replace the hooks with the same name in a.
+func AllocHookScriptSliceMerge(a, b []*AllocHookScriptConfig) []*AllocHookScriptConfig {
+	n := make([]*AllocHookScriptConfig, len(a))
+	seenKeys := make(map[string]int, len(a))
+
+	for i, config := range a {
+		n[i] = config.Copy()
+		seenKeys[config.Name] = i
+	}
+
+	for _, config := range b {
+		if fIndex, ok := seenKeys[config.Name]; ok {
+			n[fIndex] = config.Copy()
+			continue
+		}
+
+		n = append(n, config.Copy())
+	}
+
+	return n
+}
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/shoenig/test/must"
+)
+
+func TestAllocHookScriptConfig_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	must.Nil(t, (*AllocHookScriptConfig)(nil).Copy())
+
+	a := &AllocHookScriptConfig{
+		Name:    "register",
+		Command: "/usr/local/bin/register",
+		Args:    []string{"-v"},
+		Stages:  []string{"prerun", "postrun"},
+		Timeout: pointer.Of("10s"),
+	}
+	b := a.Copy()
+	must.Eq(t, a, b)
+
+	b.Args[0] = "-q"
+	b.Stages[1] = "destroy"
+	*b.Timeout = "1m"
+	must.Eq(t, []string{"-v"}, a.Args)
+	must.Eq(t, []string{"prerun", "postrun"}, a.Stages)
+	must.Eq(t, "10s", *a.Timeout)
+}
+
+func TestAllocHookScriptSliceMerge(t *testing.T) {
+	ci.Parallel(t)
+
+	a := []*AllocHookScriptConfig{
+		{Name: "register", Command: "/usr/local/bin/register"},
+		{Name: "audit", Command: "/usr/local/bin/audit"},
+	}
+	b := []*AllocHookScriptConfig{
+		{Name: "audit", Command: "/opt/bin/audit", FailurePolicy: pointer.Of("ignore")},
+		{Name: "warm", Command: "/usr/local/bin/warm"},
+	}
+
+	must.Eq(t, []*AllocHookScriptConfig{
+		{Name: "register", Command: "/usr/local/bin/register"},
+		{Name: "audit", Command: "/opt/bin/audit", FailurePolicy: pointer.Of("ignore")},
+		{Name: "warm", Command: "/usr/local/bin/warm"},
+	}, AllocHookScriptSliceMerge(a, b))
+}
@@ -38,6 +38,10 @@ type ArtifactConfig struct {
 	// it will be canceled. Defaults to 30m.
 	S3Timeout *string `hcl:"s3_timeout"`
 
+	// OCITimeout is the duration in which an OCI registry operation must
+	// complete or it will be canceled. Defaults to 30m.
+	OCITimeout *string `hcl:"oci_timeout"`
+
 	// DecompressionFileCountLimit is the maximum number of files that will
 	// be decompressed before triggering an error and cancelling the operation.
 	//
@@ -59,6 +63,22 @@ type ArtifactConfig struct {
 	// variable names to inherit from the Nomad Client and set in the artifact
 	// download sandbox process.
 	SetEnvironmentVariables *string `hcl:"set_environment_variables"`
+
+	// BandwidthLimit is the maximum number of bytes per second downloaded by
+	// all the artifact downloads of the client combined. Defaults to 0, which
+	// is unlimited.
+	BandwidthLimit *string `hcl:"bandwidth_limit"`
+
+	// CacheMaxSize is the maximum size of the on-disk cache of artifacts with
+	// a checksum, shared across the allocations of the client. Setting it to
+	// 0 disables the cache. Defaults to 10GB.
+	CacheMaxSize *string `hcl:"cache_max_size"`
+
+	// CredentialHelper is the path to an executable run by the client to
+	// exchange the workload identity of a task for credentials to download
+	// the artifacts authenticated with the identity. Defaults to "", which
+	// disables authenticated artifacts.
+	CredentialHelper *string `hcl:"credential_helper"`
 }
 
 func (a *ArtifactConfig) Copy() *ArtifactConfig {
@@ -72,10 +92,14 @@ func (a *ArtifactConfig) Copy() *ArtifactConfig {
 		GitTimeout:                  pointer.Copy(a.GitTimeout),
 		HgTimeout:                   pointer.Copy(a.HgTimeout),
 		S3Timeout:                   pointer.Copy(a.S3Timeout),
+		OCITimeout:                  pointer.Copy(a.OCITimeout),
 		DecompressionFileCountLimit: pointer.Copy(a.DecompressionFileCountLimit),
 		DecompressionSizeLimit:      pointer.Copy(a.DecompressionSizeLimit),
 		DisableFilesystemIsolation:  pointer.Copy(a.DisableFilesystemIsolation),
 		SetEnvironmentVariables:     pointer.Copy(a.SetEnvironmentVariables),
+		BandwidthLimit:              pointer.Copy(a.BandwidthLimit),
+		CacheMaxSize:                pointer.Copy(a.CacheMaxSize),
+		CredentialHelper:            pointer.Copy(a.CredentialHelper),
 	}
 }
 
@@ -93,10 +117,14 @@ func (a *ArtifactConfig) Merge(o *ArtifactConfig) *ArtifactConfig {
 			GitTimeout:                  pointer.Merge(a.GitTimeout, o.GitTimeout),
 			HgTimeout:                   pointer.Merge(a.HgTimeout, o.HgTimeout),
 			S3Timeout:                   pointer.Merge(a.S3Timeout, o.S3Timeout),
+			OCITimeout:                  pointer.Merge(a.OCITimeout, o.OCITimeout),
 			DecompressionFileCountLimit: pointer.Merge(a.DecompressionFileCountLimit, o.DecompressionFileCountLimit),
 			DecompressionSizeLimit:      pointer.Merge(a.DecompressionSizeLimit, o.DecompressionSizeLimit),
 			DisableFilesystemIsolation:  pointer.Merge(a.DisableFilesystemIsolation, o.DisableFilesystemIsolation),
 			SetEnvironmentVariables:     pointer.Merge(a.SetEnvironmentVariables, o.SetEnvironmentVariables),
+			BandwidthLimit:              pointer.Merge(a.BandwidthLimit, o.BandwidthLimit),
+			CacheMaxSize:                pointer.Merge(a.CacheMaxSize, o.CacheMaxSize),
+			CredentialHelper:            pointer.Merge(a.CredentialHelper, o.CredentialHelper),
 		}
 	}
 }
@@ -118,6 +146,8 @@ func (a *ArtifactConfig) Equal(o *ArtifactConfig) bool {
 		return false
 	case !pointer.Eq(a.S3Timeout, o.S3Timeout):
 		return false
+	case !pointer.Eq(a.OCITimeout, o.OCITimeout):
+		return false
 	case !pointer.Eq(a.DecompressionFileCountLimit, o.DecompressionFileCountLimit):
 		return false
 	case !pointer.Eq(a.DecompressionSizeLimit, o.DecompressionSizeLimit):
@@ -126,6 +156,12 @@ func (a *ArtifactConfig) Equal(o *ArtifactConfig) bool {
 		return false
 	case !pointer.Eq(a.SetEnvironmentVariables, o.SetEnvironmentVariables):
 		return false
+	case !pointer.Eq(a.BandwidthLimit, o.BandwidthLimit):
+		return false
+	case !pointer.Eq(a.CacheMaxSize, o.CacheMaxSize):
+		return false
+	case !pointer.Eq(a.CredentialHelper, o.CredentialHelper):
+		return false
 	}
 	return true
 }
@@ -189,6 +225,15 @@ func (a *ArtifactConfig) Validate() error {
 		return fmt.Errorf("s3_timeout must be > 0")
 	}
 
+	if a.OCITimeout == nil {
+		return fmt.Errorf("oci_timeout must be set")
+	}
+	if v, err := time.ParseDuration(*a.OCITimeout); err != nil {
+		return fmt.Errorf("oci_timeout not a valid duration: %w", err)
+	} else if v < 0 {
+		return fmt.Errorf("oci_timeout must be > 0")
+	}
+
 	if a.DecompressionFileCountLimit == nil {
 		return fmt.Errorf("decompression_file_count_limit must not be nil")
 	}
@@ -213,6 +258,28 @@ func (a *ArtifactConfig) Validate() error {
 		return fmt.Errorf("set_environment_variables must be set")
 	}
 
+	if a.BandwidthLimit == nil {
+		return fmt.Errorf("bandwidth_limit must be set")
+	}
+	if v, err := humanize.ParseBytes(*a.BandwidthLimit); err != nil {
+		return fmt.Errorf("bandwidth_limit is not a valid size: %w", err)
+	} else if v > math.MaxInt64 {
+		return fmt.Errorf("bandwidth_limit must be < %d but found %d", int64(math.MaxInt64), v)
+	}
+
+	if a.CacheMaxSize == nil {
+		return fmt.Errorf("cache_max_size must be set")
+	}
+	if v, err := humanize.ParseBytes(*a.CacheMaxSize); err != nil {
+		return fmt.Errorf("cache_max_size is not a valid size: %w", err)
+	} else if v > math.MaxInt64 {
+		return fmt.Errorf("cache_max_size must be < %d but found %d", int64(math.MaxInt64), v)
+	}
+
+	if a.CredentialHelper == nil {
+		return fmt.Errorf("credential_helper must be set")
+	}
+
 	return nil
 }
 
@@ -242,6 +309,10 @@ func DefaultArtifactConfig() *ArtifactConfig {
 		// accommodate large/slow downloads.
 		S3Timeout: pointer.Of("30m"),
 
+		// Timeout for OCI registry operations. Must be long enough to
+		// accommodate large/slow downloads.
+		OCITimeout: pointer.Of("30m"),
+
 		// DecompressionFileCountLimit limits the number of files decompressed
 		// for a single artifact. Must be large enough for payloads with lots
 		// of files.
@@ -256,5 +327,14 @@ func DefaultArtifactConfig() *ArtifactConfig {
 
 		// No environment variables are inherited from Client by default.
 		SetEnvironmentVariables: pointer.Of(""),
+
+		// Downloads are not rate limited by default.
+		BandwidthLimit: pointer.Of("0"),
+
+		// Artifacts with a checksum are cached across allocations.
+		CacheMaxSize: pointer.Of("10GB"),
+
+		// Authenticated artifacts are disabled by default.
+		CredentialHelper: pointer.Of(""),
 	}
 }
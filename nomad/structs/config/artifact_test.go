@@ -23,6 +23,7 @@ func TestArtifactConfig_Copy(t *testing.T) {
 	b.HTTPMaxSize = pointer.Of("2MB")
 	b.GitTimeout = pointer.Of("3m")
 	b.HgTimeout = pointer.Of("2m")
+	b.OCITimeout = pointer.Of("4m")
 	b.DecompressionFileCountLimit = pointer.Of(7)
 	b.DecompressionSizeLimit = pointer.Of("2GB")
 	must.NotEqual(t, a, b)
@@ -46,10 +47,14 @@ func TestArtifactConfig_Merge(t *testing.T) {
 				GitTimeout:                  pointer.Of("30m"),
 				HgTimeout:                   pointer.Of("30m"),
 				S3Timeout:                   pointer.Of("30m"),
+				OCITimeout:                  pointer.Of("30m"),
 				DecompressionFileCountLimit: pointer.Of(4096),
 				DecompressionSizeLimit:      pointer.Of("100GB"),
 				DisableFilesystemIsolation:  pointer.Of(false),
 				SetEnvironmentVariables:     pointer.Of(""),
+				BandwidthLimit:              pointer.Of("0"),
+				CacheMaxSize:                pointer.Of("10GB"),
+				CredentialHelper:            pointer.Of(""),
 			},
 			other: &ArtifactConfig{
 				HTTPReadTimeout:             pointer.Of("5m"),
@@ -58,10 +63,14 @@ func TestArtifactConfig_Merge(t *testing.T) {
 				GitTimeout:                  pointer.Of("2m"),
 				HgTimeout:                   pointer.Of("3m"),
 				S3Timeout:                   pointer.Of("4m"),
+				OCITimeout:                  pointer.Of("5m"),
 				DecompressionFileCountLimit: pointer.Of(100),
 				DecompressionSizeLimit:      pointer.Of("8GB"),
 				DisableFilesystemIsolation:  pointer.Of(true),
 				SetEnvironmentVariables:     pointer.Of("FOO,BAR"),
+				BandwidthLimit:              pointer.Of("1MB"),
+				CacheMaxSize:                pointer.Of("2GB"),
+				CredentialHelper:            pointer.Of("/usr/local/bin/creds"),
 			},
 			expected: &ArtifactConfig{
 				HTTPReadTimeout:             pointer.Of("5m"),
@@ -70,10 +79,14 @@ func TestArtifactConfig_Merge(t *testing.T) {
 				GitTimeout:                  pointer.Of("2m"),
 				HgTimeout:                   pointer.Of("3m"),
 				S3Timeout:                   pointer.Of("4m"),
+				OCITimeout:                  pointer.Of("5m"),
 				DecompressionFileCountLimit: pointer.Of(100),
 				DecompressionSizeLimit:      pointer.Of("8GB"),
 				DisableFilesystemIsolation:  pointer.Of(true),
 				SetEnvironmentVariables:     pointer.Of("FOO,BAR"),
+				BandwidthLimit:              pointer.Of("1MB"),
+				CacheMaxSize:                pointer.Of("2GB"),
+				CredentialHelper:            pointer.Of("/usr/local/bin/creds"),
 			},
 		},
 		{
@@ -365,6 +378,20 @@ func TestArtifactConfig_Validate(t *testing.T) {
 			},
 			expErr: "s3_timeout not a valid duration",
 		},
+		{
+			name: "oci timeout is missing",
+			config: func(a *ArtifactConfig) {
+				a.OCITimeout = nil
+			},
+			expErr: "oci_timeout must be set",
+		},
+		{
+			name: "oci timeout is invalid",
+			config: func(a *ArtifactConfig) {
+				a.OCITimeout = pointer.Of("invalid")
+			},
+			expErr: "oci_timeout not a valid duration",
+		},
 		{
 			name: "decompression file count limit is nil",
 			config: func(a *ArtifactConfig) {
@@ -407,6 +434,41 @@ func TestArtifactConfig_Validate(t *testing.T) {
 			},
 			expErr: "set_environment_variables must be set",
 		},
+		{
+			name: "bandwidth limit not set",
+			config: func(a *ArtifactConfig) {
+				a.BandwidthLimit = nil
+			},
+			expErr: "bandwidth_limit must be set",
+		},
+		{
+			name: "bandwidth limit is invalid",
+			config: func(a *ArtifactConfig) {
+				a.BandwidthLimit = pointer.Of("fast")
+			},
+			expErr: "bandwidth_limit is not a valid size",
+		},
+		{
+			name: "cache max size not set",
+			config: func(a *ArtifactConfig) {
+				a.CacheMaxSize = nil
+			},
+			expErr: "cache_max_size must be set",
+		},
+		{
+			name: "cache max size is zero",
+			config: func(a *ArtifactConfig) {
+				a.CacheMaxSize = pointer.Of("0")
+			},
+			expErr: "",
+		},
+		{
+			name: "credential helper not set",
+			config: func(a *ArtifactConfig) {
+				a.CredentialHelper = nil
+			},
+			expErr: "credential_helper must be set",
+		},
 	}
 
 	for _, tc := range testCases {
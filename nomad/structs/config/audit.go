@@ -34,7 +34,7 @@ type AuditSink struct {
 	// be met in order to successfully make requests
 	DeliveryGuarantee string `hcl:"delivery_guarantee"`
 
-	// Type is the sink type to configure. (file)
+	// Type is the sink type to configure. (file, syslog, tcp)
 	Type string `hcl:"type"`
 
 	// Format is the sink output format. (json)
@@ -56,6 +56,15 @@ type AuditSink struct {
 
 	// Mode is the octal formatted permissions for the audit log files.
 	Mode string `hcl:"mode"`
+
+	// Address is the host:port of the endpoint for tcp sinks.
+	Address string `hcl:"address"`
+
+	// Facility is the syslog facility for syslog sinks.
+	Facility string `hcl:"facility"`
+
+	// Tag is the syslog tag for syslog sinks.
+	Tag string `hcl:"tag"`
 }
 
 // AuditFilter is the configuration for a Audit Log Filter
@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import "github.com/hashicorp/nomad/helper/pointer"
+
+// DynamicHostVolumesConfig configures the client to create the host volumes
+// requested by jobs with `create = true`.
+type DynamicHostVolumesConfig struct {
+	// Path is the directory under which host volumes are created.
+	Path *string `hcl:"path"`
+
+	// MaxSize is the maximum size in MB of a created host volume. Zero means
+	// the size is not limited.
+	MaxSize *int `hcl:"max_size"`
+
+	// Cleanup is the policy for deleting a created host volume once no
+	// allocation on the client uses it; either "retain" or "delete".
+	Cleanup *string `hcl:"cleanup"`
+
+	// PluginDir is the directory with the executables of external host
+	// volume plugins.
+	PluginDir *string `hcl:"plugin_dir"`
+
+	// Plugin is the ID of the host volume plugin that creates the host
+	// volumes requested by jobs. Defaults to the built-in "mkdir" plugin.
+	Plugin *string `hcl:"plugin"`
+}
+
+func (d *DynamicHostVolumesConfig) Copy() *DynamicHostVolumesConfig {
+	if d == nil {
+		return nil
+	}
+	return &DynamicHostVolumesConfig{
+		Path:      pointer.Copy(d.Path),
+		MaxSize:   pointer.Copy(d.MaxSize),
+		Cleanup:   pointer.Copy(d.Cleanup),
+		PluginDir: pointer.Copy(d.PluginDir),
+		Plugin:    pointer.Copy(d.Plugin),
+	}
+}
+
+// Merge returns a new DynamicHostVolumesConfig where non-nil fields in the
+// argument have higher precedence.
+func (d *DynamicHostVolumesConfig) Merge(o *DynamicHostVolumesConfig) *DynamicHostVolumesConfig {
+	switch {
+	case d == nil:
+		return o.Copy()
+	case o == nil:
+		return d.Copy()
+	default:
+		return &DynamicHostVolumesConfig{
+			Path:      pointer.Merge(d.Path, o.Path),
+			MaxSize:   pointer.Merge(d.MaxSize, o.MaxSize),
+			Cleanup:   pointer.Merge(d.Cleanup, o.Cleanup),
+			PluginDir: pointer.Merge(d.PluginDir, o.PluginDir),
+			Plugin:    pointer.Merge(d.Plugin, o.Plugin),
+		}
+	}
+}
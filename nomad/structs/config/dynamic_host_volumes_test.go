@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/shoenig/test/must"
+)
+
+func TestDynamicHostVolumesConfig_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	must.Nil(t, (*DynamicHostVolumesConfig)(nil).Copy())
+
+	a := &DynamicHostVolumesConfig{
+		Path:    pointer.Of("/opt/nomad/volumes"),
+		MaxSize: pointer.Of(1024),
+	}
+	b := a.Copy()
+	must.Eq(t, a, b)
+
+	*b.Path = "/srv/volumes"
+	must.Eq(t, "/opt/nomad/volumes", *a.Path)
+}
+
+func TestDynamicHostVolumesConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	base := &DynamicHostVolumesConfig{
+		Path:    pointer.Of("/opt/nomad/volumes"),
+		MaxSize: pointer.Of(1024),
+	}
+	other := &DynamicHostVolumesConfig{
+		MaxSize: pointer.Of(2048),
+		Cleanup: pointer.Of("delete"),
+	}
+
+	must.Eq(t, base, base.Merge(nil))
+	must.Eq(t, other, (*DynamicHostVolumesConfig)(nil).Merge(other))
+	must.Eq(t, &DynamicHostVolumesConfig{
+		Path:    pointer.Of("/opt/nomad/volumes"),
+		MaxSize: pointer.Of(2048),
+		Cleanup: pointer.Of("delete"),
+	}, base.Merge(other))
+}
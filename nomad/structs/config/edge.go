@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"slices"
+
+	"github.com/hashicorp/nomad/helper/pointer"
+)
+
+// EdgeConfig configures the client-local scheduler that keeps pinned system
+// jobs running while the client is disconnected from the servers.
+type EdgeConfig struct {
+	// Enabled enables the client-local scheduler.
+	Enabled *bool `hcl:"enabled"`
+
+	// PinnedJobs are the IDs of the system and sysbatch jobs the client keeps
+	// running while disconnected. If empty, all system jobs are pinned.
+	PinnedJobs []string `hcl:"pinned_jobs"`
+
+	// DisconnectGrace is how long the client must fail to heartbeat before it
+	// considers itself disconnected and starts scheduling locally.
+	DisconnectGrace *string `hcl:"disconnect_grace"`
+
+	// RestartDelay is the minimum time between local restarts of the same
+	// allocation.
+	RestartDelay *string `hcl:"restart_delay"`
+}
+
+func (e *EdgeConfig) Copy() *EdgeConfig {
+	if e == nil {
+		return nil
+	}
+
+	ne := new(EdgeConfig)
+	*ne = *e
+	ne.PinnedJobs = slices.Clone(e.PinnedJobs)
+	return ne
+}
+
+func (e *EdgeConfig) Merge(o *EdgeConfig) *EdgeConfig {
+	switch {
+	case e == nil:
+		return o.Copy()
+	case o == nil:
+		return e.Copy()
+	default:
+		ne := e.Copy()
+		if o.Enabled != nil {
+			ne.Enabled = pointer.Copy(o.Enabled)
+		}
+		if len(o.PinnedJobs) > 0 {
+			ne.PinnedJobs = slices.Clone(o.PinnedJobs)
+		}
+		if o.DisconnectGrace != nil {
+			ne.DisconnectGrace = pointer.Copy(o.DisconnectGrace)
+		}
+		if o.RestartDelay != nil {
+			ne.RestartDelay = pointer.Copy(o.RestartDelay)
+		}
+		return ne
+	}
+}
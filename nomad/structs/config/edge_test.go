@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/shoenig/test/must"
+)
+
+func TestEdgeConfig_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	var nilConfig *EdgeConfig
+	must.Nil(t, nilConfig.Copy())
+
+	orig := &EdgeConfig{
+		Enabled:         pointer.Of(true),
+		PinnedJobs:      []string{"fluentd"},
+		DisconnectGrace: pointer.Of("1m"),
+	}
+	cp := orig.Copy()
+	must.Eq(t, orig, cp)
+
+	cp.PinnedJobs[0] = "vector"
+	must.Eq(t, "fluentd", orig.PinnedJobs[0])
+}
+
+func TestEdgeConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	base := &EdgeConfig{
+		Enabled:         pointer.Of(false),
+		PinnedJobs:      []string{"fluentd"},
+		DisconnectGrace: pointer.Of("1m"),
+	}
+	other := &EdgeConfig{
+		Enabled:      pointer.Of(true),
+		RestartDelay: pointer.Of("10s"),
+	}
+
+	must.Eq(t, base, base.Merge(nil))
+	must.Eq(t, other, (*EdgeConfig)(nil).Merge(other))
+	must.Eq(t, &EdgeConfig{
+		Enabled:         pointer.Of(true),
+		PinnedJobs:      []string{"fluentd"},
+		DisconnectGrace: pointer.Of("1m"),
+		RestartDelay:    pointer.Of("10s"),
+	}, base.Merge(other))
+}
@@ -0,0 +1,61 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"github.com/hashicorp/nomad/helper/pointer"
+)
+
+// EvictionConfig configures the client eviction manager, which evicts
+// allocations when the host runs low on memory or disk.
+type EvictionConfig struct {
+	// Enabled enables the eviction manager.
+	Enabled *bool `hcl:"enabled"`
+
+	// MemoryThreshold is the percentage of host memory in use above which
+	// allocations are evicted.
+	MemoryThreshold *int `hcl:"memory_threshold"`
+
+	// DiskThreshold is the percentage of the alloc dir disk in use above
+	// which allocations are evicted.
+	DiskThreshold *int `hcl:"disk_threshold"`
+
+	// GracePeriod is how long a threshold must be exceeded before an
+	// allocation is evicted, and the minimum time between evictions.
+	GracePeriod *string `hcl:"grace_period"`
+}
+
+func (e *EvictionConfig) Copy() *EvictionConfig {
+	if e == nil {
+		return nil
+	}
+
+	ne := new(EvictionConfig)
+	*ne = *e
+	return ne
+}
+
+func (e *EvictionConfig) Merge(o *EvictionConfig) *EvictionConfig {
+	switch {
+	case e == nil:
+		return o.Copy()
+	case o == nil:
+		return e.Copy()
+	default:
+		ne := e.Copy()
+		if o.Enabled != nil {
+			ne.Enabled = pointer.Copy(o.Enabled)
+		}
+		if o.MemoryThreshold != nil {
+			ne.MemoryThreshold = pointer.Copy(o.MemoryThreshold)
+		}
+		if o.DiskThreshold != nil {
+			ne.DiskThreshold = pointer.Copy(o.DiskThreshold)
+		}
+		if o.GracePeriod != nil {
+			ne.GracePeriod = pointer.Copy(o.GracePeriod)
+		}
+		return ne
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/shoenig/test/must"
+)
+
+func TestEvictionConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	base := &EvictionConfig{
+		Enabled:         pointer.Of(false),
+		MemoryThreshold: pointer.Of(90),
+		GracePeriod:     pointer.Of("30s"),
+	}
+	other := &EvictionConfig{
+		Enabled:       pointer.Of(true),
+		DiskThreshold: pointer.Of(85),
+	}
+
+	must.Eq(t, base, base.Merge(nil))
+	must.Eq(t, other, (*EvictionConfig)(nil).Merge(other))
+	must.Eq(t, &EvictionConfig{
+		Enabled:         pointer.Of(true),
+		MemoryThreshold: pointer.Of(90),
+		DiskThreshold:   pointer.Of(85),
+		GracePeriod:     pointer.Of("30s"),
+	}, base.Merge(other))
+}
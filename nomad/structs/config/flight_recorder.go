@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper/pointer"
+)
+
+const (
+	// DefaultFlightRecorderCapacity is the number of samples kept in each of
+	// the flight recorder buffers if no capacity is configured.
+	DefaultFlightRecorderCapacity = 1024
+
+	// DefaultFlightRecorderGoroutineInterval is how often goroutines are
+	// snapshotted if no interval is configured.
+	DefaultFlightRecorderGoroutineInterval = time.Minute
+
+	// DefaultFlightRecorderRPCLatencyThreshold is the RPC latency above which
+	// the flight recorder is dumped if no threshold is configured.
+	DefaultFlightRecorderRPCLatencyThreshold = 5 * time.Second
+
+	// DefaultFlightRecorderGoroutineThreshold is the number of goroutines
+	// above which the flight recorder is dumped if no threshold is
+	// configured.
+	DefaultFlightRecorderGoroutineThreshold = 10000
+
+	// DefaultFlightRecorderMinDumpInterval is the minimum time between two
+	// automatic dumps if no interval is configured.
+	DefaultFlightRecorderMinDumpInterval = 10 * time.Minute
+)
+
+// FlightRecorderConfig configures the flight recorder of the agent, which
+// keeps ring buffers of recent activity that can be dumped on demand or when
+// an anomaly is detected.
+type FlightRecorderConfig struct {
+	// Enabled enables the flight recorder.
+	Enabled *bool `hcl:"enabled"`
+
+	// Capacity is the number of samples kept in each buffer.
+	Capacity int `hcl:"capacity"`
+
+	// GoroutineInterval is how often goroutines are snapshotted.
+	GoroutineInterval    time.Duration
+	GoroutineIntervalHCL string `hcl:"goroutine_interval" json:"-"`
+
+	// DumpDir is the directory automatic dumps are written to. Defaults to
+	// the flight-recorder directory of the agent data directory.
+	DumpDir string `hcl:"dump_dir"`
+
+	// DumpOnAnomaly enables automatic dumps when an anomaly is detected.
+	DumpOnAnomaly *bool `hcl:"dump_on_anomaly"`
+
+	// RPCLatencyThreshold is the latency above which a non-blocking RPC is
+	// considered an anomaly.
+	RPCLatencyThreshold    time.Duration
+	RPCLatencyThresholdHCL string `hcl:"rpc_latency_threshold" json:"-"`
+
+	// GoroutineThreshold is the number of goroutines above which the agent
+	// is considered in an anomalous state.
+	GoroutineThreshold int `hcl:"goroutine_threshold"`
+
+	// MinDumpInterval is the minimum time between two automatic dumps.
+	MinDumpInterval    time.Duration
+	MinDumpIntervalHCL string `hcl:"min_dump_interval" json:"-"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+// IsEnabled returns whether the flight recorder is enabled.
+func (f *FlightRecorderConfig) IsEnabled() bool {
+	return f != nil && f.Enabled != nil && *f.Enabled
+}
+
+// Copy returns a deep copy of the flight recorder configuration.
+func (f *FlightRecorderConfig) Copy() *FlightRecorderConfig {
+	if f == nil {
+		return nil
+	}
+
+	nf := new(FlightRecorderConfig)
+	*nf = *f
+	nf.Enabled = pointer.Copy(f.Enabled)
+	nf.DumpOnAnomaly = pointer.Copy(f.DumpOnAnomaly)
+	return nf
+}
+
+// Merge merges two flight recorder configurations together. Settings from b
+// take precedence.
+func (f *FlightRecorderConfig) Merge(b *FlightRecorderConfig) *FlightRecorderConfig {
+	if f == nil {
+		return b.Copy()
+	}
+
+	result := f.Copy()
+	if b == nil {
+		return result
+	}
+
+	if b.Enabled != nil {
+		result.Enabled = pointer.Copy(b.Enabled)
+	}
+	if b.Capacity != 0 {
+		result.Capacity = b.Capacity
+	}
+	if b.GoroutineInterval != 0 {
+		result.GoroutineInterval = b.GoroutineInterval
+		result.GoroutineIntervalHCL = b.GoroutineIntervalHCL
+	}
+	if b.DumpDir != "" {
+		result.DumpDir = b.DumpDir
+	}
+	if b.DumpOnAnomaly != nil {
+		result.DumpOnAnomaly = pointer.Copy(b.DumpOnAnomaly)
+	}
+	if b.RPCLatencyThreshold != 0 {
+		result.RPCLatencyThreshold = b.RPCLatencyThreshold
+		result.RPCLatencyThresholdHCL = b.RPCLatencyThresholdHCL
+	}
+	if b.GoroutineThreshold != 0 {
+		result.GoroutineThreshold = b.GoroutineThreshold
+	}
+	if b.MinDumpInterval != 0 {
+		result.MinDumpInterval = b.MinDumpInterval
+		result.MinDumpIntervalHCL = b.MinDumpIntervalHCL
+	}
+	return result
+}
+
+// Canonicalize sets the defaults of unset fields.
+func (f *FlightRecorderConfig) Canonicalize() {
+	if f.Capacity == 0 {
+		f.Capacity = DefaultFlightRecorderCapacity
+	}
+	if f.GoroutineInterval == 0 {
+		f.GoroutineInterval = DefaultFlightRecorderGoroutineInterval
+	}
+	if f.DumpOnAnomaly == nil {
+		f.DumpOnAnomaly = pointer.Of(true)
+	}
+	if f.RPCLatencyThreshold == 0 {
+		f.RPCLatencyThreshold = DefaultFlightRecorderRPCLatencyThreshold
+	}
+	if f.GoroutineThreshold == 0 {
+		f.GoroutineThreshold = DefaultFlightRecorderGoroutineThreshold
+	}
+	if f.MinDumpInterval == 0 {
+		f.MinDumpInterval = DefaultFlightRecorderMinDumpInterval
+	}
+}
+
+// Validate returns an error if the flight recorder configuration is invalid.
+func (f *FlightRecorderConfig) Validate() error {
+	if f == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+
+	if f.Capacity < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("capacity must not be negative"))
+	}
+	if f.GoroutineInterval < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("goroutine_interval must not be negative"))
+	} else if f.GoroutineInterval != 0 && f.GoroutineInterval < time.Second {
+		mErr.Errors = append(mErr.Errors, errors.New("goroutine_interval must be at least 1s"))
+	}
+	if f.RPCLatencyThreshold < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("rpc_latency_threshold must not be negative"))
+	}
+	if f.GoroutineThreshold < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("goroutine_threshold must not be negative"))
+	}
+	if f.MinDumpInterval < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("min_dump_interval must not be negative"))
+	}
+
+	return mErr.ErrorOrNil()
+}
@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/shoenig/test/must"
+)
+
+func TestFlightRecorderConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	must.NoError(t, (*FlightRecorderConfig)(nil).Validate())
+
+	valid := &FlightRecorderConfig{Enabled: pointer.Of(true)}
+	valid.Canonicalize()
+	must.NoError(t, valid.Validate())
+	must.Eq(t, DefaultFlightRecorderCapacity, valid.Capacity)
+	must.Eq(t, DefaultFlightRecorderMinDumpInterval, valid.MinDumpInterval)
+	must.True(t, *valid.DumpOnAnomaly)
+
+	err := (&FlightRecorderConfig{
+		Capacity:          -1,
+		GoroutineInterval: time.Millisecond,
+		MinDumpInterval:   -time.Minute,
+	}).Validate()
+	must.ErrorContains(t, err, "capacity must not be negative")
+	must.ErrorContains(t, err, "goroutine_interval must be at least 1s")
+	must.ErrorContains(t, err, "min_dump_interval must not be negative")
+}
+
+func TestFlightRecorderConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	a := &FlightRecorderConfig{
+		Enabled:       pointer.Of(true),
+		Capacity:      128,
+		DumpOnAnomaly: pointer.Of(true),
+	}
+	b := &FlightRecorderConfig{
+		DumpDir:                "/var/lib/nomad/recorder",
+		DumpOnAnomaly:          pointer.Of(false),
+		RPCLatencyThreshold:    time.Second,
+		RPCLatencyThresholdHCL: "1s",
+	}
+
+	result := a.Merge(b)
+	must.Eq(t, &FlightRecorderConfig{
+		Enabled:                pointer.Of(true),
+		Capacity:               128,
+		DumpDir:                "/var/lib/nomad/recorder",
+		DumpOnAnomaly:          pointer.Of(false),
+		RPCLatencyThreshold:    time.Second,
+		RPCLatencyThresholdHCL: "1s",
+	}, result)
+
+	// The inputs are copied
+	*result.Enabled = false
+	must.True(t, *a.Enabled)
+
+	must.Eq(t, b, (*FlightRecorderConfig)(nil).Merge(b))
+	must.Eq(t, a, a.Merge(nil))
+}
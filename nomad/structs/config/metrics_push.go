@@ -0,0 +1,268 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/pointer"
+)
+
+const (
+	// DefaultMetricsPushInterval is how often metrics are pushed if no
+	// interval is configured.
+	DefaultMetricsPushInterval = 30 * time.Second
+
+	// DefaultMetricsPushTimeout is how long a push may take if no timeout is
+	// configured.
+	DefaultMetricsPushTimeout = 10 * time.Second
+
+	// RelabelActionReplace sets the target label to the replacement when the
+	// regex matches the source labels.
+	RelabelActionReplace = "replace"
+
+	// RelabelActionKeep drops metrics whose source labels don't match the
+	// regex.
+	RelabelActionKeep = "keep"
+
+	// RelabelActionDrop drops metrics whose source labels match the regex.
+	RelabelActionDrop = "drop"
+
+	// RelabelActionLabelDrop removes labels whose name matches the regex.
+	RelabelActionLabelDrop = "labeldrop"
+
+	// RelabelActionLabelKeep removes labels whose name doesn't match the
+	// regex.
+	RelabelActionLabelKeep = "labelkeep"
+)
+
+// MetricsPushConfig configures an endpoint that agents periodically push
+// metrics to, such as a Prometheus remote-write receiver or an OpenTelemetry
+// collector. It is used for agents that can't be scraped.
+type MetricsPushConfig struct {
+	// Name is a unique name given to the endpoint
+	Name string `hcl:",key"`
+
+	// Address is the URL that metrics are pushed to.
+	Address string `hcl:"address"`
+
+	// Interval is how often metrics are pushed.
+	Interval    time.Duration `hcl:"-"`
+	IntervalHCL string        `hcl:"interval" json:"-"`
+
+	// Timeout is how long to wait for the endpoint to accept a push.
+	Timeout    time.Duration `hcl:"-"`
+	TimeoutHCL string        `hcl:"timeout" json:"-"`
+
+	// Headers are added to every request to the endpoint, such as for
+	// authentication or tenancy.
+	Headers map[string]string `hcl:"headers" json:"-"`
+
+	// Labels are added to every metric pushed to the endpoint. For OTLP
+	// endpoints they are set as resource attributes.
+	Labels map[string]string `hcl:"labels"`
+
+	// PrefixFilter allows for filtering out metrics from being pushed, with
+	// the same syntax as the telemetry prefix_filter.
+	PrefixFilter []string `hcl:"prefix_filter"`
+
+	// FilterDefault controls whether to push metrics that have not been
+	// specified by the filter. Defaults to true.
+	FilterDefault *bool `hcl:"filter_default"`
+
+	// Relabel rules are applied, in order, to every metric before it is
+	// pushed. They are parsed by hand because the blocks have no label.
+	Relabel []*MetricsRelabelConfig `hcl:"-"`
+
+	// CAFile is the path to a PEM encoded CA certificate used to verify the
+	// endpoint's certificate, when its address uses https.
+	CAFile string `hcl:"ca_file"`
+
+	// TLSSkipVerify disables verification of the endpoint's certificate.
+	TLSSkipVerify bool `hcl:"tls_skip_verify"`
+}
+
+// MetricsRelabelConfig is a Prometheus style relabeling rule. The metric
+// name is available as the __name__ label.
+type MetricsRelabelConfig struct {
+	// SourceLabels are the labels whose values are joined with the separator
+	// and matched against the regex.
+	SourceLabels []string `hcl:"source_labels"`
+
+	// Separator joins the values of the source labels. Defaults to ";".
+	Separator string `hcl:"separator"`
+
+	// Regex is matched against the joined source label values, or against
+	// label names for the labeldrop and labelkeep actions. It is anchored at
+	// both ends. Defaults to "(.*)".
+	Regex string `hcl:"regex"`
+
+	// TargetLabel is the label set by the replace action.
+	TargetLabel string `hcl:"target_label"`
+
+	// Replacement is the value of the target label, which may reference
+	// capture groups of the regex. Defaults to "$1".
+	Replacement string `hcl:"replacement"`
+
+	// Action is one of replace, keep, drop, labeldrop or labelkeep. Defaults
+	// to replace.
+	Action string `hcl:"action"`
+}
+
+// Copy returns a deep copy of the push configuration.
+func (m *MetricsPushConfig) Copy() *MetricsPushConfig {
+	if m == nil {
+		return nil
+	}
+
+	nc := new(MetricsPushConfig)
+	*nc = *m
+	nc.Headers = maps.Clone(m.Headers)
+	nc.Labels = maps.Clone(m.Labels)
+	nc.PrefixFilter = slices.Clone(m.PrefixFilter)
+	nc.FilterDefault = pointer.Copy(m.FilterDefault)
+	nc.Relabel = helper.CopySlice(m.Relabel)
+	return nc
+}
+
+// Canonicalize sets the defaults of unset fields.
+func (m *MetricsPushConfig) Canonicalize() {
+	if m.Interval == 0 {
+		m.Interval = DefaultMetricsPushInterval
+	}
+	if m.Timeout == 0 {
+		m.Timeout = DefaultMetricsPushTimeout
+	}
+	if m.FilterDefault == nil {
+		m.FilterDefault = pointer.Of(true)
+	}
+	for _, r := range m.Relabel {
+		r.Canonicalize()
+	}
+}
+
+// Validate returns an error if the push configuration is invalid.
+func (m *MetricsPushConfig) Validate() error {
+	var mErr multierror.Error
+
+	if m.Name == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing name"))
+	}
+
+	if m.Address == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing address"))
+	} else if u, err := url.Parse(m.Address); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid address: %v", err))
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("address scheme must be http or https, got %q", u.Scheme))
+	}
+
+	if m.Interval < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("interval must not be negative"))
+	}
+	if m.Timeout < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("timeout must not be negative"))
+	}
+
+	for _, filter := range m.PrefixFilter {
+		if !strings.HasPrefix(filter, "+") && !strings.HasPrefix(filter, "-") {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("prefix_filter %q must start with '+' or '-'", filter))
+		}
+	}
+
+	for i, r := range m.Relabel {
+		if err := r.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("relabel %d: %v", i, err))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a deep copy of the relabel rule.
+func (r *MetricsRelabelConfig) Copy() *MetricsRelabelConfig {
+	if r == nil {
+		return nil
+	}
+
+	nr := new(MetricsRelabelConfig)
+	*nr = *r
+	nr.SourceLabels = slices.Clone(r.SourceLabels)
+	return nr
+}
+
+// Canonicalize sets the defaults of unset fields.
+func (r *MetricsRelabelConfig) Canonicalize() {
+	if r.Action == "" {
+		r.Action = RelabelActionReplace
+	}
+	if r.Separator == "" {
+		r.Separator = ";"
+	}
+	if r.Regex == "" {
+		r.Regex = "(.*)"
+	}
+	if r.Replacement == "" && r.Action == RelabelActionReplace {
+		r.Replacement = "$1"
+	}
+}
+
+// Validate returns an error if the relabel rule is invalid.
+func (r *MetricsRelabelConfig) Validate() error {
+	if r.Regex != "" {
+		if _, err := regexp.Compile(r.Regex); err != nil {
+			return fmt.Errorf("invalid regex: %v", err)
+		}
+	}
+
+	switch r.Action {
+	case "", RelabelActionReplace:
+		if r.TargetLabel == "" {
+			return errors.New("target_label is required for the replace action")
+		}
+	case RelabelActionKeep, RelabelActionDrop:
+		if len(r.SourceLabels) == 0 {
+			return fmt.Errorf("source_labels are required for the %s action", r.Action)
+		}
+	case RelabelActionLabelDrop, RelabelActionLabelKeep:
+	default:
+		return fmt.Errorf("invalid action %q", r.Action)
+	}
+	return nil
+}
+
+// MetricsPushSliceMerge merges two slices of push configurations. Endpoints
+// in b replace those in a with the same name, and are otherwise appended.
+func MetricsPushSliceMerge(a, b []*MetricsPushConfig) []*MetricsPushConfig {
+	n := make([]*MetricsPushConfig, len(a))
+	seenKeys := make(map[string]int, len(a))
+
+	for i, config := range a {
+		n[i] = config.Copy()
+		seenKeys[config.Name] = i
+	}
+
+	for _, config := range b {
+		if fIndex, ok := seenKeys[config.Name]; ok {
+			n[fIndex] = config.Copy()
+			continue
+		}
+
+		n = append(n, config.Copy())
+	}
+
+	if len(n) == 0 {
+		return nil
+	}
+	return n
+}
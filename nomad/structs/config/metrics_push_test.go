@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestMetricsPushConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	valid := &MetricsPushConfig{
+		Name:         "mimir",
+		Address:      "https://mimir.example.com/api/v1/push",
+		PrefixFilter: []string{"+nomad.client", "-nomad.client.host"},
+		Relabel: []*MetricsRelabelConfig{
+			{SourceLabels: []string{"datacenter"}, TargetLabel: "dc"},
+		},
+	}
+	valid.Canonicalize()
+	must.NoError(t, valid.Validate())
+
+	invalid := &MetricsPushConfig{
+		Address:      "udp://mimir.example.com",
+		Interval:     -time.Second,
+		Timeout:      -time.Second,
+		PrefixFilter: []string{"nomad.client"},
+		Relabel: []*MetricsRelabelConfig{
+			{Action: RelabelActionReplace},
+			{Action: RelabelActionKeep},
+			{Action: "bogus", TargetLabel: "dc"},
+			{Regex: "(", TargetLabel: "dc"},
+		},
+	}
+	err := invalid.Validate()
+	must.ErrorContains(t, err, "missing name")
+	must.ErrorContains(t, err, `address scheme must be http or https, got "udp"`)
+	must.ErrorContains(t, err, "interval must not be negative")
+	must.ErrorContains(t, err, "timeout must not be negative")
+	must.ErrorContains(t, err, `prefix_filter "nomad.client" must start with '+' or '-'`)
+	must.ErrorContains(t, err, "relabel 0: target_label is required for the replace action")
+	must.ErrorContains(t, err, "relabel 1: source_labels are required for the keep action")
+	must.ErrorContains(t, err, `relabel 2: invalid action "bogus"`)
+	must.ErrorContains(t, err, "relabel 3: invalid regex")
+
+	invalid = &MetricsPushConfig{Name: "mimir"}
+	must.ErrorContains(t, invalid.Validate(), "missing address")
+}
+
+func TestMetricsPushConfig_Canonicalize(t *testing.T) {
+	ci.Parallel(t)
+
+	c := &MetricsPushConfig{
+		Relabel: []*MetricsRelabelConfig{
+			{TargetLabel: "dc"},
+			{Regex: "datacenter", Action: RelabelActionLabelDrop},
+		},
+	}
+	c.Canonicalize()
+	must.Eq(t, DefaultMetricsPushInterval, c.Interval)
+	must.Eq(t, DefaultMetricsPushTimeout, c.Timeout)
+	must.True(t, *c.FilterDefault)
+	must.Eq(t, &MetricsRelabelConfig{
+		TargetLabel: "dc",
+		Separator:   ";",
+		Regex:       "(.*)",
+		Replacement: "$1",
+		Action:      RelabelActionReplace,
+	}, c.Relabel[0])
+	must.Eq(t, "", c.Relabel[1].Replacement)
+}
+
+func TestMetricsPushSliceMerge(t *testing.T) {
+	ci.Parallel(t)
+
+	a := []*MetricsPushConfig{
+		{Name: "one", Address: "http://one"},
+		{Name: "two", Address: "http://two", Labels: map[string]string{"cluster": "a"}},
+	}
+	b := []*MetricsPushConfig{
+		{Name: "two", Address: "http://two.new"},
+		{Name: "three", Address: "http://three"},
+	}
+
+	result := MetricsPushSliceMerge(a, b)
+	must.Eq(t, []*MetricsPushConfig{
+		{Name: "one", Address: "http://one"},
+		{Name: "two", Address: "http://two.new"},
+		{Name: "three", Address: "http://three"},
+	}, result)
+
+	// The inputs are copied
+	result[0].Address = "http://changed"
+	must.Eq(t, "http://one", a[0].Address)
+
+	must.Nil(t, MetricsPushSliceMerge(nil, nil))
+}
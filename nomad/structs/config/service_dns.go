@@ -0,0 +1,74 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import "github.com/hashicorp/nomad/helper/pointer"
+
+// ServiceDNSConfig configures the DNS server of the client agent, which
+// serves records for the services registered with Nomad native service
+// discovery.
+type ServiceDNSConfig struct {
+	// Enabled enables the DNS server.
+	Enabled *bool `hcl:"enabled"`
+
+	// Address is the IP address the DNS server binds to. Defaults to
+	// 127.0.0.1.
+	Address *string `hcl:"address"`
+
+	// Port is the UDP and TCP port the DNS server listens on. Defaults to
+	// 8600.
+	Port *int `hcl:"port"`
+
+	// Domain is the domain of the records served by the DNS server. Defaults
+	// to "nomad".
+	Domain *string `hcl:"domain"`
+
+	// TTL is the time-to-live of the records served by the DNS server.
+	// Defaults to 0s, which disables caching.
+	TTL *string `hcl:"ttl"`
+
+	// Token is the ACL token used to look up services. It requires the
+	// read-job capability in the namespaces of the services.
+	Token *string `hcl:"token"`
+}
+
+func (s *ServiceDNSConfig) Copy() *ServiceDNSConfig {
+	if s == nil {
+		return nil
+	}
+
+	ns := new(ServiceDNSConfig)
+	*ns = *s
+	return ns
+}
+
+func (s *ServiceDNSConfig) Merge(o *ServiceDNSConfig) *ServiceDNSConfig {
+	switch {
+	case s == nil:
+		return o.Copy()
+	case o == nil:
+		return s.Copy()
+	default:
+		ns := s.Copy()
+		if o.Enabled != nil {
+			ns.Enabled = pointer.Copy(o.Enabled)
+		}
+		if o.Address != nil {
+			ns.Address = pointer.Copy(o.Address)
+		}
+		if o.Port != nil {
+			ns.Port = pointer.Copy(o.Port)
+		}
+		if o.Domain != nil {
+			ns.Domain = pointer.Copy(o.Domain)
+		}
+		if o.TTL != nil {
+			ns.TTL = pointer.Copy(o.TTL)
+		}
+		if o.Token != nil {
+			ns.Token = pointer.Copy(o.Token)
+		}
+		return ns
+	}
+}
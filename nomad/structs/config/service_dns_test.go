@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/shoenig/test/must"
+)
+
+func TestServiceDNSConfig_Copy(t *testing.T) {
+	ci.Parallel(t)
+
+	var nilConfig *ServiceDNSConfig
+	must.Nil(t, nilConfig.Copy())
+
+	orig := &ServiceDNSConfig{
+		Enabled: pointer.Of(true),
+		Port:    pointer.Of(8600),
+		Domain:  pointer.Of("nomad"),
+	}
+	cp := orig.Copy()
+	must.Eq(t, orig, cp)
+
+	cp.Port = pointer.Of(53)
+	must.Eq(t, 8600, *orig.Port)
+}
+
+func TestServiceDNSConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	base := &ServiceDNSConfig{
+		Enabled: pointer.Of(false),
+		Address: pointer.Of("127.0.0.1"),
+		Port:    pointer.Of(8600),
+	}
+	other := &ServiceDNSConfig{
+		Enabled: pointer.Of(true),
+		TTL:     pointer.Of("5s"),
+		Token:   pointer.Of("secret"),
+	}
+
+	must.Eq(t, base, base.Merge(nil))
+	must.Eq(t, other, (*ServiceDNSConfig)(nil).Merge(other))
+	must.Eq(t, &ServiceDNSConfig{
+		Enabled: pointer.Of(true),
+		Address: pointer.Of("127.0.0.1"),
+		Port:    pointer.Of(8600),
+		TTL:     pointer.Of("5s"),
+		Token:   pointer.Of("secret"),
+	}, base.Merge(other))
+}
@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper/pointer"
+)
+
+const (
+	// DefaultTracingExportInterval is how often batches of spans are
+	// exported if no interval is configured.
+	DefaultTracingExportInterval = 5 * time.Second
+
+	// DefaultTracingTimeout is how long an export may take if no timeout is
+	// configured.
+	DefaultTracingTimeout = 10 * time.Second
+)
+
+// TracingConfig configures the export of OpenTelemetry spans for the server
+// RPC handlers and the scheduling pipeline to an OTLP/HTTP endpoint.
+type TracingConfig struct {
+	// Enabled enables tracing.
+	Enabled *bool `hcl:"enabled"`
+
+	// Address is the URL of the OTLP/HTTP traces endpoint, such as
+	// http://127.0.0.1:4318/v1/traces.
+	Address string `hcl:"address"`
+
+	// Headers are added to every export request, such as for authentication.
+	Headers map[string]string `hcl:"headers" json:"-"`
+
+	// SampleRatio is the fraction of new traces that are sampled, between 0
+	// and 1. Defaults to 1.
+	SampleRatio *float64 `hcl:"sample_ratio"`
+
+	// ExportInterval is how often batches of spans are exported.
+	ExportInterval    time.Duration `hcl:"-"`
+	ExportIntervalHCL string        `hcl:"export_interval" json:"-"`
+
+	// Timeout is how long to wait for the endpoint to accept an export.
+	Timeout    time.Duration `hcl:"-"`
+	TimeoutHCL string        `hcl:"timeout" json:"-"`
+
+	// CAFile is the path to a PEM encoded CA certificate used to verify the
+	// endpoint's certificate, when its address uses https.
+	CAFile string `hcl:"ca_file"`
+
+	// TLSSkipVerify disables verification of the endpoint's certificate.
+	TLSSkipVerify bool `hcl:"tls_skip_verify"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+// IsEnabled returns whether tracing is enabled.
+func (t *TracingConfig) IsEnabled() bool {
+	return t != nil && t.Enabled != nil && *t.Enabled
+}
+
+// Copy returns a deep copy of the tracing configuration.
+func (t *TracingConfig) Copy() *TracingConfig {
+	if t == nil {
+		return nil
+	}
+
+	nt := new(TracingConfig)
+	*nt = *t
+	nt.Enabled = pointer.Copy(t.Enabled)
+	nt.Headers = maps.Clone(t.Headers)
+	nt.SampleRatio = pointer.Copy(t.SampleRatio)
+	return nt
+}
+
+// Merge merges two tracing configurations together. Settings from b take
+// precedence.
+func (t *TracingConfig) Merge(b *TracingConfig) *TracingConfig {
+	if t == nil {
+		return b.Copy()
+	}
+
+	result := t.Copy()
+	if b == nil {
+		return result
+	}
+
+	if b.Enabled != nil {
+		result.Enabled = pointer.Copy(b.Enabled)
+	}
+	if b.Address != "" {
+		result.Address = b.Address
+	}
+	if len(b.Headers) != 0 {
+		result.Headers = maps.Clone(b.Headers)
+	}
+	if b.SampleRatio != nil {
+		result.SampleRatio = pointer.Copy(b.SampleRatio)
+	}
+	if b.ExportInterval != 0 {
+		result.ExportInterval = b.ExportInterval
+		result.ExportIntervalHCL = b.ExportIntervalHCL
+	}
+	if b.Timeout != 0 {
+		result.Timeout = b.Timeout
+		result.TimeoutHCL = b.TimeoutHCL
+	}
+	if b.CAFile != "" {
+		result.CAFile = b.CAFile
+	}
+	if b.TLSSkipVerify {
+		result.TLSSkipVerify = true
+	}
+	return result
+}
+
+// Canonicalize sets the defaults of unset fields.
+func (t *TracingConfig) Canonicalize() {
+	if t.SampleRatio == nil {
+		t.SampleRatio = pointer.Of(1.0)
+	}
+	if t.ExportInterval == 0 {
+		t.ExportInterval = DefaultTracingExportInterval
+	}
+	if t.Timeout == 0 {
+		t.Timeout = DefaultTracingTimeout
+	}
+}
+
+// Validate returns an error if an enabled tracing configuration is invalid.
+func (t *TracingConfig) Validate() error {
+	if !t.IsEnabled() {
+		return nil
+	}
+
+	var mErr multierror.Error
+
+	if t.Address == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("missing address"))
+	} else if u, err := url.Parse(t.Address); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid address: %v", err))
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("address scheme must be http or https, got %q", u.Scheme))
+	}
+
+	if t.SampleRatio != nil && (*t.SampleRatio < 0 || *t.SampleRatio > 1) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("sample_ratio must be between 0 and 1, got %v", *t.SampleRatio))
+	}
+	if t.ExportInterval < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("export_interval must not be negative"))
+	}
+	if t.Timeout < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("timeout must not be negative"))
+	}
+
+	return mErr.ErrorOrNil()
+}
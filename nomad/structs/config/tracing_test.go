@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/shoenig/test/must"
+)
+
+func TestTracingConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	// Disabled configurations aren't validated
+	must.NoError(t, (*TracingConfig)(nil).Validate())
+	must.NoError(t, (&TracingConfig{Address: "bogus://"}).Validate())
+
+	valid := &TracingConfig{
+		Enabled: pointer.Of(true),
+		Address: "http://127.0.0.1:4318/v1/traces",
+	}
+	valid.Canonicalize()
+	must.NoError(t, valid.Validate())
+
+	invalid := &TracingConfig{
+		Enabled:        pointer.Of(true),
+		Address:        "grpc://127.0.0.1:4317",
+		SampleRatio:    pointer.Of(1.5),
+		ExportInterval: -time.Second,
+		Timeout:        -time.Second,
+	}
+	err := invalid.Validate()
+	must.ErrorContains(t, err, `address scheme must be http or https, got "grpc"`)
+	must.ErrorContains(t, err, "sample_ratio must be between 0 and 1, got 1.5")
+	must.ErrorContains(t, err, "export_interval must not be negative")
+	must.ErrorContains(t, err, "timeout must not be negative")
+
+	invalid = &TracingConfig{Enabled: pointer.Of(true)}
+	must.ErrorContains(t, invalid.Validate(), "missing address")
+}
+
+func TestTracingConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	a := &TracingConfig{
+		Enabled:     pointer.Of(true),
+		Address:     "http://a:4318/v1/traces",
+		SampleRatio: pointer.Of(0.5),
+	}
+	b := &TracingConfig{
+		Address:           "http://b:4318/v1/traces",
+		Headers:           map[string]string{"Authorization": "Bearer x"},
+		ExportInterval:    time.Second,
+		ExportIntervalHCL: "1s",
+	}
+
+	result := a.Merge(b)
+	must.Eq(t, &TracingConfig{
+		Enabled:           pointer.Of(true),
+		Address:           "http://b:4318/v1/traces",
+		Headers:           map[string]string{"Authorization": "Bearer x"},
+		SampleRatio:       pointer.Of(0.5),
+		ExportInterval:    time.Second,
+		ExportIntervalHCL: "1s",
+	}, result)
+
+	// The inputs are copied
+	*result.Enabled = false
+	must.True(t, *a.Enabled)
+
+	must.Eq(t, b, (*TracingConfig)(nil).Merge(b))
+	must.Eq(t, a, a.Merge(nil))
+}
@@ -0,0 +1,116 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper/pointer"
+)
+
+const (
+	// DefaultUsageResolution is the length of the periods job usage is
+	// aggregated in if no resolution is configured.
+	DefaultUsageResolution = time.Hour
+
+	// DefaultUsageRetention is how long job usage records are kept if no
+	// retention is configured.
+	DefaultUsageRetention = 90 * 24 * time.Hour
+)
+
+// UsageConfig configures the accounting of the resources reserved and used
+// by jobs over time, which the leader aggregates per job and period.
+type UsageConfig struct {
+	// Enabled enables usage accounting.
+	Enabled *bool `hcl:"enabled"`
+
+	// Resolution is the length of the periods usage is aggregated in.
+	Resolution    time.Duration `hcl:"-"`
+	ResolutionHCL string        `hcl:"resolution" json:"-"`
+
+	// Retention is how long usage records are kept.
+	Retention    time.Duration `hcl:"-"`
+	RetentionHCL string        `hcl:"retention" json:"-"`
+
+	// ExtraKeysHCL is used by hcl to surface unexpected keys
+	ExtraKeysHCL []string `hcl:",unusedKeys" json:"-"`
+}
+
+// IsEnabled returns whether usage accounting is enabled.
+func (u *UsageConfig) IsEnabled() bool {
+	return u != nil && u.Enabled != nil && *u.Enabled
+}
+
+// Copy returns a deep copy of the usage configuration.
+func (u *UsageConfig) Copy() *UsageConfig {
+	if u == nil {
+		return nil
+	}
+
+	nu := new(UsageConfig)
+	*nu = *u
+	nu.Enabled = pointer.Copy(u.Enabled)
+	return nu
+}
+
+// Merge merges two usage configurations together. Settings from b take
+// precedence.
+func (u *UsageConfig) Merge(b *UsageConfig) *UsageConfig {
+	if u == nil {
+		return b.Copy()
+	}
+
+	result := u.Copy()
+	if b == nil {
+		return result
+	}
+
+	if b.Enabled != nil {
+		result.Enabled = pointer.Copy(b.Enabled)
+	}
+	if b.Resolution != 0 {
+		result.Resolution = b.Resolution
+		result.ResolutionHCL = b.ResolutionHCL
+	}
+	if b.Retention != 0 {
+		result.Retention = b.Retention
+		result.RetentionHCL = b.RetentionHCL
+	}
+	return result
+}
+
+// Canonicalize sets the defaults of unset fields.
+func (u *UsageConfig) Canonicalize() {
+	if u.Resolution == 0 {
+		u.Resolution = DefaultUsageResolution
+	}
+	if u.Retention == 0 {
+		u.Retention = DefaultUsageRetention
+	}
+}
+
+// Validate returns an error if the usage configuration is invalid.
+func (u *UsageConfig) Validate() error {
+	if u == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+
+	if u.Resolution < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("resolution must not be negative"))
+	} else if u.Resolution != 0 && u.Resolution < time.Minute {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("resolution must be at least 1m, got %v", u.Resolution))
+	}
+	if u.Retention < 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("retention must not be negative"))
+	} else if u.Retention != 0 && u.Retention < u.Resolution {
+		mErr.Errors = append(mErr.Errors, errors.New("retention must not be shorter than the resolution"))
+	}
+
+	return mErr.ErrorOrNil()
+}
@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/shoenig/test/must"
+)
+
+func TestUsageConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	must.NoError(t, (*UsageConfig)(nil).Validate())
+
+	valid := &UsageConfig{Enabled: pointer.Of(true)}
+	valid.Canonicalize()
+	must.NoError(t, valid.Validate())
+	must.Eq(t, DefaultUsageResolution, valid.Resolution)
+	must.Eq(t, DefaultUsageRetention, valid.Retention)
+
+	err := (&UsageConfig{Resolution: time.Second, Retention: -time.Hour}).Validate()
+	must.ErrorContains(t, err, "resolution must be at least 1m, got 1s")
+	must.ErrorContains(t, err, "retention must not be negative")
+
+	err = (&UsageConfig{Resolution: time.Hour, Retention: time.Minute}).Validate()
+	must.ErrorContains(t, err, "retention must not be shorter than the resolution")
+}
+
+func TestUsageConfig_Merge(t *testing.T) {
+	ci.Parallel(t)
+
+	a := &UsageConfig{
+		Enabled:       pointer.Of(true),
+		Resolution:    time.Hour,
+		ResolutionHCL: "1h",
+	}
+	b := &UsageConfig{
+		Retention:    24 * time.Hour,
+		RetentionHCL: "24h",
+	}
+
+	result := a.Merge(b)
+	must.Eq(t, &UsageConfig{
+		Enabled:       pointer.Of(true),
+		Resolution:    time.Hour,
+		ResolutionHCL: "1h",
+		Retention:     24 * time.Hour,
+		RetentionHCL:  "24h",
+	}, result)
+
+	// The inputs are copied
+	*result.Enabled = false
+	must.True(t, *a.Enabled)
+
+	must.Eq(t, b, (*UsageConfig)(nil).Merge(b))
+	must.Eq(t, a, a.Merge(nil))
+}
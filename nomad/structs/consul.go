@@ -89,6 +89,29 @@ func (c *Consul) IdentityName() string {
 	return fmt.Sprintf("%s_%s", ConsulTaskIdentityNamePrefix, clusterName)
 }
 
+// GetConsulPartition gets the Consul admin partition of this task. The
+// partition of the task's consul block takes precedence over the one of its
+// group. An empty string is returned if no partition is set.
+func (t *Task) GetConsulPartition(tg *TaskGroup) string {
+	if t.Consul != nil && t.Consul.Partition != "" {
+		return t.Consul.Partition
+	}
+	if tg != nil && tg.Consul != nil {
+		return tg.Consul.Partition
+	}
+	return ""
+}
+
+// GetConsulPartition gets the Consul admin partition of this service, which
+// is the partition of its group. An empty string is returned if no partition
+// is set.
+func (s *Service) GetConsulPartition(tg *TaskGroup) string {
+	if tg != nil && tg.Consul != nil {
+		return tg.Consul.Partition
+	}
+	return ""
+}
+
 var (
 	// validConsulVaultClusterName is the rule used to validate a Consul or
 	// Vault cluster name.
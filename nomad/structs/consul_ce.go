@@ -10,14 +10,26 @@ func (c *Consul) GetNamespace() string {
 	return ""
 }
 
-// GetConsulClusterName gets the Consul cluster for this task. Only a single
-// default cluster is supported in Nomad CE.
-func (t *Task) GetConsulClusterName(_ *TaskGroup) string {
+// GetConsulClusterName gets the Consul cluster for this task. The cluster of
+// the task's consul block takes precedence over the one of its group.
+func (t *Task) GetConsulClusterName(tg *TaskGroup) string {
+	if t.Consul != nil && t.Consul.Cluster != "" {
+		return t.Consul.Cluster
+	}
+	if tg != nil && tg.Consul != nil && tg.Consul.Cluster != "" {
+		return tg.Consul.Cluster
+	}
 	return ConsulDefaultCluster
 }
 
-// GetConsulClusterName gets the Consul cluster for this service. Only a single
-// default cluster is supported in Nomad CE.
-func (s *Service) GetConsulClusterName(_ *TaskGroup) string {
+// GetConsulClusterName gets the Consul cluster for this service. The cluster
+// of the service takes precedence over the one of its group.
+func (s *Service) GetConsulClusterName(tg *TaskGroup) string {
+	if s.Cluster != "" {
+		return s.Cluster
+	}
+	if tg != nil && tg.Consul != nil && tg.Consul.Cluster != "" {
+		return tg.Consul.Cluster
+	}
 	return ConsulDefaultCluster
 }
@@ -290,6 +290,15 @@ type CSIVolume struct {
 	CloneID               string
 	SnapshotID            string
 
+	// SnapshotSchedule configures the snapshots the servers take of the
+	// volume periodically.
+	SnapshotSchedule *CSIVolumeSnapshotSchedule
+
+	// ScheduledSnapshots are the snapshots taken by the snapshot schedule
+	// that are still retained, from oldest to newest. This value cannot be
+	// set by the user.
+	ScheduledSnapshots []*CSIVolumeScheduledSnapshot
+
 	// Allocations, tracking claim status
 	ReadAllocs  map[string]*Allocation // AllocID -> Allocation
 	WriteAllocs map[string]*Allocation // AllocID -> Allocation
@@ -552,6 +561,9 @@ func (v *CSIVolume) Copy() *CSIVolume {
 		out.PastClaims[k] = &claim
 	}
 
+	out.SnapshotSchedule = v.SnapshotSchedule.Copy()
+	out.ScheduledSnapshots = helper.CopySlice(v.ScheduledSnapshots)
+
 	return out
 }
 
@@ -729,6 +741,11 @@ func (v *CSIVolume) Validate() error {
 	if v.SnapshotID != "" && v.CloneID != "" {
 		errs = append(errs, "only one of snapshot_id and clone_id is allowed")
 	}
+	if v.SnapshotSchedule != nil {
+		if err := v.SnapshotSchedule.Validate(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
 	if len(v.RequestedCapabilities) == 0 {
 		errs = append(errs, "must include at least one capability block")
 	}
@@ -826,6 +843,10 @@ func (v *CSIVolume) Merge(other *CSIVolume) error {
 	// Secrets can be updated freely
 	v.Secrets = other.Secrets
 
+	// The snapshot schedule can be updated freely, but the snapshots it
+	// already took are retained
+	v.SnapshotSchedule = other.SnapshotSchedule
+
 	// must be compatible with parameters set by from CreateVolumeResponse
 
 	if len(other.Parameters) != 0 && !maps.Equal(v.Parameters, other.Parameters) {
@@ -892,6 +913,14 @@ type CSIVolumeExpandResponse struct {
 	QueryMeta
 }
 
+// CSIVolumeSnapshotsUpdateRequest is used by the leader to record the
+// snapshots taken by the snapshot schedule of a volume.
+type CSIVolumeSnapshotsUpdateRequest struct {
+	VolumeID  string
+	Snapshots []*CSIVolumeScheduledSnapshot
+	WriteRequest
+}
+
 type CSIVolumeClaimMode int
 
 const (
@@ -1015,6 +1044,74 @@ type CSIVolumeUnpublishResponse struct {
 	QueryMeta
 }
 
+// CSIVolumeSnapshotScheduleMinInterval is the shortest interval between two
+// scheduled snapshots of a volume.
+const CSIVolumeSnapshotScheduleMinInterval = time.Minute
+
+// CSIVolumeSnapshotSchedule configures the snapshots the servers take of a
+// volume periodically.
+type CSIVolumeSnapshotSchedule struct {
+	// Interval is the time between two snapshots.
+	Interval time.Duration
+
+	// Retain is the number of scheduled snapshots kept. Older snapshots are
+	// deleted from the storage provider. Zero keeps all the snapshots.
+	Retain int
+
+	// Parameters are passed to the plugin when creating the snapshots.
+	Parameters map[string]string
+}
+
+func (s *CSIVolumeSnapshotSchedule) Copy() *CSIVolumeSnapshotSchedule {
+	if s == nil {
+		return nil
+	}
+	ns := new(CSIVolumeSnapshotSchedule)
+	*ns = *s
+	ns.Parameters = maps.Clone(s.Parameters)
+	return ns
+}
+
+func (s *CSIVolumeSnapshotSchedule) Validate() error {
+	var mErr *multierror.Error
+	if s.Interval < CSIVolumeSnapshotScheduleMinInterval {
+		mErr = multierror.Append(mErr, fmt.Errorf(
+			"snapshot schedule interval must be at least %s", CSIVolumeSnapshotScheduleMinInterval))
+	}
+	if s.Retain < 0 {
+		mErr = multierror.Append(mErr, errors.New("snapshot schedule retain must be >= 0"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// CSIVolumeScheduledSnapshot is a snapshot taken by the snapshot schedule of
+// a volume.
+type CSIVolumeScheduledSnapshot struct {
+	ID         string // storage provider's ID
+	CreateTime int64  // seconds since epoch, set by the server
+}
+
+func (s *CSIVolumeScheduledSnapshot) Copy() *CSIVolumeScheduledSnapshot {
+	if s == nil {
+		return nil
+	}
+	ns := *s
+	return &ns
+}
+
+// SnapshotDue returns true if the snapshot schedule of the volume should take
+// a new snapshot at the given time.
+func (v *CSIVolume) SnapshotDue(now time.Time) bool {
+	if v.SnapshotSchedule == nil {
+		return false
+	}
+	if len(v.ScheduledSnapshots) == 0 {
+		return true
+	}
+	last := v.ScheduledSnapshots[len(v.ScheduledSnapshots)-1]
+	return !now.Before(time.Unix(last.CreateTime, 0).Add(v.SnapshotSchedule.Interval))
+}
+
 // CSISnapshot is the storage provider's view of a volume snapshot
 type CSISnapshot struct {
 	// These fields map to those returned by the storage provider plugin
@@ -74,7 +74,8 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 	diff := &JobDiff{Type: DiffTypeNone}
 	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
 	filter := []string{"ID", "Status", "StatusDescription", "Version", "Stable", "CreateIndex",
-		"ModifyIndex", "JobModifyIndex", "Update", "SubmitTime", "NomadTokenID", "VaultToken"}
+		"ModifyIndex", "JobModifyIndex", "Update", "SubmitTime", "NomadTokenID", "VaultToken",
+		"RegisterIdempotencyToken", "DispatchQueued", "NotifyPending"}
 
 	if j == nil && other == nil {
 		return diff, nil
@@ -150,6 +151,11 @@ func (j *Job) Diff(other *Job, contextual bool) (*JobDiff, error) {
 		diff.Objects = append(diff.Objects, mrDiff)
 	}
 
+	// Notify diff
+	if nDiff := jobNotifyDiff(j.Notify, other.Notify, contextual); nDiff != nil {
+		diff.Objects = append(diff.Objects, nDiff)
+	}
+
 	// Check to see if there is a diff. We don't use reflect because we are
 	// filtering quite a few fields that will change on each diff.
 	if diff.Type == DiffTypeNone {
@@ -309,6 +315,17 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 		diff.Objects = append(diff.Objects, affinitiesDiff...)
 	}
 
+	// Spreads diff
+	spreadsDiff := primitiveObjectSetDiff(
+		interfaceSlice(tg.Spreads),
+		interfaceSlice(other.Spreads),
+		[]string{"str"},
+		"Spread",
+		contextual)
+	if spreadsDiff != nil {
+		diff.Objects = append(diff.Objects, spreadsDiff...)
+	}
+
 	// Restart policy diff
 	rDiff := primitiveObjectDiff(tg.RestartPolicy, other.RestartPolicy, nil, "RestartPolicy", contextual)
 	if rDiff != nil {
@@ -322,7 +339,7 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 	}
 
 	// EphemeralDisk diff
-	diskDiff := primitiveObjectDiff(tg.EphemeralDisk, other.EphemeralDisk, nil, "EphemeralDisk", contextual)
+	diskDiff := ephemeralDiskDiff(tg.EphemeralDisk, other.EphemeralDisk, contextual)
 	if diskDiff != nil {
 		diff.Objects = append(diff.Objects, diskDiff)
 	}
@@ -333,11 +350,21 @@ func (tg *TaskGroup) Diff(other *TaskGroup, contextual bool) (*TaskGroupDiff, er
 	}
 
 	// Update diff
-	// COMPAT: Remove "Stagger" in 0.7.0.
-	if uDiff := primitiveObjectDiff(tg.Update, other.Update, []string{"Stagger"}, "Update", contextual); uDiff != nil {
+	if uDiff := updateStrategyDiff(tg.Update, other.Update, contextual); uDiff != nil {
 		diff.Objects = append(diff.Objects, uDiff)
 	}
 
+	// Array diff
+	arrayDiff := primitiveObjectDiff(tg.Array, other.Array, nil, "Array", contextual)
+	if arrayDiff != nil {
+		diff.Objects = append(diff.Objects, arrayDiff)
+	}
+
+	// Ingress diff
+	if iDiff := ingressDiff(tg.Ingress, other.Ingress, contextual); iDiff != nil {
+		diff.Objects = append(diff.Objects, iDiff)
+	}
+
 	// Disconnect diff
 	if disconnectDiff := disconectStrategyDiffs(tg.Disconnect, other.Disconnect, contextual); disconnectDiff != nil {
 		diff.Objects = append(diff.Objects, disconnectDiff)
@@ -520,7 +547,7 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(t.LogConfig, other.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(t.LogConfig, other.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -531,6 +558,12 @@ func (t *Task) Diff(other *Task, contextual bool) (*TaskDiff, error) {
 		diff.Objects = append(diff.Objects, dDiff)
 	}
 
+	// OOM policy diff
+	oomDiff := primitiveObjectDiff(t.OOMPolicy, other.OOMPolicy, nil, "OOMPolicy", contextual)
+	if oomDiff != nil {
+		diff.Objects = append(diff.Objects, oomDiff)
+	}
+
 	// Artifacts diff
 	diffs := primitiveObjectSetDiff(
 		interfaceSlice(t.Artifacts),
@@ -711,6 +744,33 @@ func (t TaskDiffs) Less(i, j int) bool { return t[i].Name < t[j].Name }
 
 // scalingDiff returns the diff of two Scaling objects. If contextual diff is enabled, unchanged
 // fields within objects nested in the tasks will be returned.
+// ingressDiff returns the diff of two ingress blocks. If contextual diff is
+// enabled, unchanged fields within the listeners will be returned.
+func ingressDiff(old, new *Ingress, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Ingress"}
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &Ingress{}
+		diff.Type = DiffTypeAdded
+	} else if new == nil {
+		new = &Ingress{}
+		diff.Type = DiffTypeDeleted
+	} else {
+		diff.Type = DiffTypeEdited
+	}
+
+	// Diff the listeners.
+	diff.Objects = primitiveObjectSetDiff(
+		interfaceSlice(old.Listeners),
+		interfaceSlice(new.Listeners),
+		nil, "Listener", contextual)
+
+	sort.Sort(ObjectDiffs(diff.Objects))
+	return diff
+}
+
 func scalingDiff(old, new *ScalingPolicy, contextual bool) *ObjectDiff {
 	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Scaling"}
 	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
@@ -741,6 +801,12 @@ func scalingDiff(old, new *ScalingPolicy, contextual bool) *ObjectDiff {
 		diff.Objects = append(diff.Objects, pDiff)
 	}
 
+	// Diff Schedules
+	diff.Objects = append(diff.Objects, primitiveObjectSetDiff(
+		interfaceSlice(old.Schedules),
+		interfaceSlice(new.Schedules),
+		nil, "Schedule", contextual)...)
+
 	sort.Sort(FieldDiffs(diff.Fields))
 	sort.Sort(ObjectDiffs(diff.Objects))
 
@@ -1606,7 +1672,7 @@ func sidecarTaskDiff(old, new *SidecarTask, contextual bool) *ObjectDiff {
 	}
 
 	// LogConfig diff
-	lDiff := primitiveObjectDiff(old.LogConfig, new.LogConfig, nil, "LogConfig", contextual)
+	lDiff := logConfigDiff(old.LogConfig, new.LogConfig, contextual)
 	if lDiff != nil {
 		diff.Objects = append(diff.Objects, lDiff)
 	}
@@ -1648,6 +1714,10 @@ func consulProxyDiff(old, new *ConsulProxy, contextual bool) *ObjectDiff {
 		diff.Objects = append(diff.Objects, exposeDiff)
 	}
 
+	if envoyDiff := consulProxyEnvoyDiff(old.Envoy, new.Envoy, contextual); envoyDiff != nil {
+		diff.Objects = append(diff.Objects, envoyDiff)
+	}
+
 	// diff the config blob
 	if cDiff := configDiff(old.Config, new.Config, contextual); cDiff != nil {
 		diff.Objects = append(diff.Objects, cDiff)
@@ -1758,6 +1828,73 @@ func consulProxyExposeDiff(prev, next *ConsulExposeConfig, contextual bool) *Obj
 	return diff
 }
 
+// consulProxyEnvoyDiff diffs the envoy block of a connect proxy. If contextual
+// diff is enabled, unchanged fields within objects nested in the tasks will be
+// returned.
+func consulProxyEnvoyDiff(old, new *ConsulEnvoyConfig, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Envoy"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &ConsulEnvoyConfig{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &ConsulEnvoyConfig{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	if statsDiff := consulProxyEnvoyStatsDiff(old.Stats, new.Stats, contextual); statsDiff != nil {
+		diff.Objects = append(diff.Objects, statsDiff)
+	}
+
+	if tracingDiff := primitiveObjectDiff(old.Tracing, new.Tracing, nil, "Tracing", contextual); tracingDiff != nil {
+		diff.Objects = append(diff.Objects, tracingDiff)
+	}
+
+	return diff
+}
+
+// consulProxyEnvoyStatsDiff diffs the stats block of a connect proxy's envoy
+// block.
+func consulProxyEnvoyStatsDiff(old, new *ConsulEnvoyStats, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Stats"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &ConsulEnvoyStats{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &ConsulEnvoyStats{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	if tagsDiff := stringSetDiff(old.Tags, new.Tags, "Tags", contextual); tagsDiff != nil {
+		diff.Objects = append(diff.Objects, tagsDiff)
+	}
+
+	return diff
+}
+
 // serviceCheckDiffs diffs a set of service checks. If contextual diff is
 // enabled, unchanged fields within objects nested in the tasks will be
 // returned.
@@ -1884,6 +2021,100 @@ func changeScriptDiff(old, new *ChangeScript, contextual bool) *ObjectDiff {
 	return diff
 }
 
+// ephemeralDiskDiff returns the diff of two EphemeralDisk objects. If
+// contextual diff is enabled, all fields will be returned, even if no diff
+// occurred.
+func ephemeralDiskDiff(old, new *EphemeralDisk, contextual bool) *ObjectDiff {
+	diff := primitiveObjectDiff(old, new, nil, "EphemeralDisk", contextual)
+
+	var oldHooks, newHooks []*MigrateHook
+	if old != nil {
+		oldHooks = old.MigrateHooks
+	}
+	if new != nil {
+		newHooks = new.MigrateHooks
+	}
+	hookDiffs := migrateHookDiffs(oldHooks, newHooks, contextual)
+	if len(hookDiffs) == 0 {
+		return diff
+	}
+
+	if diff == nil {
+		diff = &ObjectDiff{Type: DiffTypeEdited, Name: "EphemeralDisk"}
+		if contextual {
+			diff.Fields = fieldDiffs(flatmap.Flatten(old, nil, true), flatmap.Flatten(new, nil, true), contextual)
+		}
+	}
+	diff.Objects = append(diff.Objects, hookDiffs...)
+	return diff
+}
+
+// migrateHookDiffs returns the diff of two slices of MigrateHook objects,
+// matched by their stage and task. If contextual diff is enabled, all fields
+// will be returned, even if no diff occurred.
+func migrateHookDiffs(old, new []*MigrateHook, contextual bool) []*ObjectDiff {
+	makeSet := func(hooks []*MigrateHook) map[string]*MigrateHook {
+		set := make(map[string]*MigrateHook, len(hooks))
+		for _, h := range hooks {
+			set[h.Stage+"/"+h.Task] = h
+		}
+		return set
+	}
+
+	oldSet := makeSet(old)
+	newSet := makeSet(new)
+
+	var diffs []*ObjectDiff
+	for k, oldHook := range oldSet {
+		if diff := migrateHookDiff(oldHook, newSet[k], contextual); diff != nil {
+			diffs = append(diffs, diff)
+		}
+	}
+	for k, newHook := range newSet {
+		if _, ok := oldSet[k]; !ok {
+			if diff := migrateHookDiff(nil, newHook, contextual); diff != nil {
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+
+	sort.Sort(ObjectDiffs(diffs))
+	return diffs
+}
+
+// migrateHookDiff returns the diff of two MigrateHook objects. If contextual
+// diff is enabled, all fields will be returned, even if no diff occurred.
+func migrateHookDiff(old, new *MigrateHook, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "MigrateHook"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &MigrateHook{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &MigrateHook{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	// Diff the primitive fields.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+
+	// Args diffs
+	if setDiff := stringSetDiff(old.Args, new.Args, "Args", contextual); setDiff != nil {
+		diff.Objects = append(diff.Objects, setDiff)
+	}
+
+	return diff
+}
+
 // templateDiff returns the diff of two Consul Template objects. If contextual diff is
 // enabled, all fields will be returned, even if no diff occurred.
 func templateDiff(old, new *Template, contextual bool) *ObjectDiff {
@@ -2060,6 +2291,50 @@ func parameterizedJobDiff(old, new *ParameterizedJobConfig, contextual bool) *Ob
 	return diff
 }
 
+// jobNotifyDiff returns the diff of two job notify objects. If contextual diff
+// is enabled, all fields will be returned, even if no diff occurred.
+func jobNotifyDiff(old, new *JobNotify, contextual bool) *ObjectDiff {
+	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Notify"}
+	var oldPrimitiveFlat, newPrimitiveFlat map[string]string
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	} else if old == nil {
+		old = &JobNotify{}
+		diff.Type = DiffTypeAdded
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	} else if new == nil {
+		new = &JobNotify{}
+		diff.Type = DiffTypeDeleted
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+	} else {
+		diff.Type = DiffTypeEdited
+		oldPrimitiveFlat = flatmap.Flatten(old, nil, true)
+		newPrimitiveFlat = flatmap.Flatten(new, nil, true)
+	}
+
+	// Diff the primitive fields, redacting the secret and header values so
+	// that plans and job history don't expose them.
+	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
+	for _, field := range diff.Fields {
+		if field.Name == "Secret" || strings.HasPrefix(field.Name, "Headers[") {
+			if field.Old != "" {
+				field.Old = JobNotifyRedacted
+			}
+			if field.New != "" {
+				field.New = JobNotifyRedacted
+			}
+		}
+	}
+
+	// On diff
+	if onDiff := stringSetDiff(old.On, new.On, "On", contextual); onDiff != nil {
+		diff.Objects = append(diff.Objects, onDiff)
+	}
+
+	return diff
+}
+
 func multiregionDiff(old, new *Multiregion, contextual bool) *ObjectDiff {
 
 	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Multiregion"}
@@ -2345,6 +2620,15 @@ func (n *NetworkResource) Diff(other *NetworkResource, contextual bool) *ObjectD
 	// Diff the primitive fields.
 	diff.Fields = fieldDiffs(oldPrimitiveFlat, newPrimitiveFlat, contextual)
 
+	// Interfaces are an ordered list of preferences
+	if len(n.Interfaces) > 0 || len(other.Interfaces) > 0 {
+		oldIfaces, newIfaces := strings.Join(n.Interfaces, ","), strings.Join(other.Interfaces, ",")
+		if ifaceDiff := fieldDiff(oldIfaces, newIfaces, "Interfaces", contextual); ifaceDiff != nil {
+			diff.Fields = append(diff.Fields, ifaceDiff)
+			sort.Sort(FieldDiffs(diff.Fields))
+		}
+	}
+
 	// Port diffs
 	resPorts := portDiffs(n.ReservedPorts, other.ReservedPorts, false, contextual)
 	dynPorts := portDiffs(n.DynamicPorts, other.DynamicPorts, true, contextual)
@@ -2402,6 +2686,37 @@ func (d *DNSConfig) Diff(other *DNSConfig, contextual bool) *ObjectDiff {
 	return diff
 }
 
+// updateStrategyDiff diffs the primitive fields of an UpdateStrategy along with
+// its canary constraints. If contextual diff is enabled, non-changed fields will
+// still be returned.
+func updateStrategyDiff(old, new *UpdateStrategy, contextual bool) *ObjectDiff {
+	// COMPAT: Remove "Stagger" in 0.7.0.
+	diff := primitiveObjectDiff(old, new, []string{"Stagger"}, "Update", contextual)
+
+	var oldConstraints, newConstraints []*Constraint
+	if old != nil {
+		oldConstraints = old.CanaryConstraints
+	}
+	if new != nil {
+		newConstraints = new.CanaryConstraints
+	}
+	conDiff := primitiveObjectSetDiff(
+		interfaceSlice(oldConstraints),
+		interfaceSlice(newConstraints),
+		[]string{"str"},
+		"CanaryConstraint",
+		contextual)
+	if conDiff == nil {
+		return diff
+	}
+
+	if diff == nil {
+		diff = &ObjectDiff{Type: DiffTypeEdited, Name: "Update"}
+	}
+	diff.Objects = append(diff.Objects, conDiff...)
+	return diff
+}
+
 func disconectStrategyDiffs(old, new *DisconnectStrategy, contextual bool) *ObjectDiff {
 	diff := &ObjectDiff{Type: DiffTypeNone, Name: "Disconnect"}
 	var oldDisconnectFlat, newDisconnectFlat map[string]string
@@ -2628,6 +2943,62 @@ func configDiff(old, new map[string]interface{}, contextual bool) *ObjectDiff {
 // idSliceDiff returns the diff of two slices of identity objects. If
 // contextual diff is enabled, all fields will be returned, even if no diff
 // occurred.
+// logConfigDiff returns the diff of two LogConfig objects. If contextual diff
+// is enabled, all fields will be returned, even if no diff occurred.
+func logConfigDiff(old, new *LogConfig, contextual bool) *ObjectDiff {
+	diff := primitiveObjectDiff(old, new, nil, "LogConfig", contextual)
+
+	var oldSinks, newSinks []*LogSink
+	if old != nil {
+		oldSinks = old.Sinks
+	}
+	if new != nil {
+		newSinks = new.Sinks
+	}
+	sinkDiffs := logSinkDiffs(oldSinks, newSinks, contextual)
+	if len(sinkDiffs) == 0 {
+		return diff
+	}
+
+	if diff == nil {
+		diff = &ObjectDiff{Type: DiffTypeEdited, Name: "LogConfig"}
+	}
+	diff.Objects = append(diff.Objects, sinkDiffs...)
+	return diff
+}
+
+// logSinkDiffs diffs a set of log sinks, matched by name. If contextual diff
+// is enabled, unchanged fields within the sinks will be returned.
+func logSinkDiffs(old, new []*LogSink, contextual bool) []*ObjectDiff {
+	oldMap := make(map[string]*LogSink, len(old))
+	newMap := make(map[string]*LogSink, len(new))
+	for _, o := range old {
+		oldMap[o.Name] = o
+	}
+	for _, n := range new {
+		newMap[n.Name] = n
+	}
+
+	var diffs []*ObjectDiff
+	for name, oldSink := range oldMap {
+		// Diff the same, deleted, and edited
+		if diff := primitiveObjectDiff(oldSink, newMap[name], nil, "Sink", contextual); diff != nil {
+			diffs = append(diffs, diff)
+		}
+	}
+	for name, newSink := range newMap {
+		// Diff the added
+		if _, ok := oldMap[name]; !ok {
+			if diff := primitiveObjectDiff(nil, newSink, nil, "Sink", contextual); diff != nil {
+				diffs = append(diffs, diff)
+			}
+		}
+	}
+
+	sort.Sort(ObjectDiffs(diffs))
+	return diffs
+}
+
 func idSliceDiffs(old, new []*WorkloadIdentity, contextual bool) []*ObjectDiff {
 	oldMap := make(map[string]*WorkloadIdentity, len(old))
 	newMap := make(map[string]*WorkloadIdentity, len(new))
@@ -2694,14 +3065,19 @@ func idDiff(oldWI, newWI *WorkloadIdentity, contextual bool) *ObjectDiff {
 
 // ObjectDiff contains the diff of two generic objects.
 type ObjectDiff struct {
-	Type    DiffType
-	Name    string
-	Fields  []*FieldDiff
-	Objects []*ObjectDiff
+	Type        DiffType
+	Name        string
+	Fields      []*FieldDiff
+	Objects     []*ObjectDiff
+	Annotations []string
 }
 
 func (o *ObjectDiff) GoString() string {
-	out := fmt.Sprintf("\n%q (%s) {\n", o.Name, o.Type)
+	out := fmt.Sprintf("\n%q (%s)", o.Name, o.Type)
+	if len(o.Annotations) != 0 {
+		out += fmt.Sprintf(" (%s)", strings.Join(o.Annotations, ", "))
+	}
+	out += " {\n"
 	for _, f := range o.Fields {
 		out += fmt.Sprintf("%#v\n", f)
 	}
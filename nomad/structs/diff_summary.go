@@ -0,0 +1,261 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// JobDiffCategoryImage is a change of the image or artifact run by the
+	// driver of a task, such as the image of the docker driver.
+	JobDiffCategoryImage = "image"
+
+	// JobDiffCategoryConfig is any other change of the driver, user, or
+	// driver config of a task.
+	JobDiffCategoryConfig = "config"
+
+	JobDiffCategoryArtifact  = "artifact"
+	JobDiffCategoryCount     = "count"
+	JobDiffCategoryResources = "resources"
+	JobDiffCategoryEnv       = "env"
+	JobDiffCategoryMeta      = "meta"
+	JobDiffCategoryTemplate  = "template"
+	JobDiffCategoryService   = "service"
+
+	// JobDiffCategoryStructure is a task group or task being added or
+	// removed.
+	JobDiffCategoryStructure = "structure"
+
+	// JobDiffCategoryOther is any change that doesn't fit another category.
+	JobDiffCategoryOther = "other"
+)
+
+// driverImageConfigKeys is the driver config key of the image run by the
+// drivers where it's known, so image updates can be told apart from other
+// config changes.
+var driverImageConfigKeys = map[string]string{
+	"docker":            "image",
+	"podman":            "image",
+	"containerd-driver": "image",
+	"java":              "jar_path",
+	"qemu":              "image_path",
+}
+
+// JobDiffSummary is a machine readable summary of the diff of two versions of
+// a job. It flattens the nested JobDiff into the list of changed fields and
+// classifies them, so tools can gate on the kind of change, for example to
+// only allow image updates.
+type JobDiffSummary struct {
+	FromVersion uint64
+	ToVersion   uint64
+	Type        DiffType
+
+	// Changes is the list of changed fields, sorted by path.
+	Changes []*JobDiffChange
+
+	// Categories is the sorted set of categories of the changes.
+	Categories []string
+}
+
+// JobDiffChange is a changed field of a job diff.
+type JobDiffChange struct {
+	// Path is the path of the field from the job, for example
+	// TaskGroups[web].Tasks[server].Config.image
+	Path string
+
+	// Category classifies the change, see the JobDiffCategory constants.
+	Category string
+
+	Type     DiffType
+	Old, New string
+}
+
+// OnlyCategories returns whether all the changes of the summary are in the
+// given categories. It returns true if there are no changes.
+func (s *JobDiffSummary) OnlyCategories(categories ...string) bool {
+	for _, c := range s.Categories {
+		found := false
+		for _, allowed := range categories {
+			if c == allowed {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// NewJobDiffSummary diffs two versions of a job and returns the summary of
+// the diff.
+func NewJobDiffSummary(old, new *Job) (*JobDiffSummary, error) {
+	diff, err := old.Diff(new, false)
+	if err != nil {
+		return nil, err
+	}
+	return diff.Summary(old, new), nil
+}
+
+// Summary returns the machine readable summary of the diff between the old
+// and new job. The jobs are used to find the drivers of tasks, since task
+// config changes are classified depending on the driver.
+func (j *JobDiff) Summary(old, new *Job) *JobDiffSummary {
+	s := &JobDiffSummary{Type: j.Type}
+	if old != nil {
+		s.FromVersion = old.Version
+	}
+	if new != nil {
+		s.ToVersion = new.Version
+	}
+
+	for _, f := range j.Fields {
+		s.addField("", f, "")
+	}
+	for _, o := range j.Objects {
+		s.addObject("", o, "")
+	}
+
+	for _, tg := range j.TaskGroups {
+		groupPath := fmt.Sprintf("TaskGroups[%s]", tg.Name)
+		if tg.Type == DiffTypeAdded || tg.Type == DiffTypeDeleted {
+			s.add(groupPath, JobDiffCategoryStructure, tg.Type, "", "")
+			continue
+		}
+
+		for _, f := range tg.Fields {
+			s.addField(groupPath+".", f, "")
+		}
+		for _, o := range tg.Objects {
+			s.addObject(groupPath+".", o, "")
+		}
+
+		for _, t := range tg.Tasks {
+			taskPath := fmt.Sprintf("%s.Tasks[%s]", groupPath, t.Name)
+			if t.Type == DiffTypeAdded || t.Type == DiffTypeDeleted {
+				s.add(taskPath, JobDiffCategoryStructure, t.Type, "", "")
+				continue
+			}
+
+			driver := jobTaskDriver(new, tg.Name, t.Name)
+			if driver == "" {
+				driver = jobTaskDriver(old, tg.Name, t.Name)
+			}
+			for _, f := range t.Fields {
+				s.addField(taskPath+".", f, driver)
+			}
+			for _, o := range t.Objects {
+				s.addObject(taskPath+".", o, driver)
+			}
+		}
+	}
+
+	sort.Slice(s.Changes, func(i, j int) bool { return s.Changes[i].Path < s.Changes[j].Path })
+
+	categories := map[string]struct{}{}
+	for _, c := range s.Changes {
+		categories[c.Category] = struct{}{}
+	}
+	for c := range categories {
+		s.Categories = append(s.Categories, c)
+	}
+	sort.Strings(s.Categories)
+	return s
+}
+
+func (s *JobDiffSummary) add(path, category string, diffType DiffType, old, new string) {
+	s.Changes = append(s.Changes, &JobDiffChange{
+		Path:     path,
+		Category: category,
+		Type:     diffType,
+		Old:      old,
+		New:      new,
+	})
+}
+
+// addField adds a changed field of a job, task group, or task. The prefix is
+// the path of its parent and the driver is set for the fields of tasks.
+func (s *JobDiffSummary) addField(prefix string, f *FieldDiff, driver string) {
+	if f.Type == DiffTypeNone {
+		return
+	}
+	s.add(prefix+f.Name, jobDiffCategory(f.Name, "", driver), f.Type, f.Old, f.New)
+}
+
+// addObject adds the changed fields of an object and its nested objects,
+// classified by the top level object.
+func (s *JobDiffSummary) addObject(prefix string, o *ObjectDiff, driver string) {
+	s.addNestedObject(prefix, o, o.Name, driver)
+}
+
+func (s *JobDiffSummary) addNestedObject(prefix string, o *ObjectDiff, root, driver string) {
+	if o.Type == DiffTypeNone {
+		return
+	}
+
+	path := prefix + o.Name
+	if len(o.Fields) == 0 && len(o.Objects) == 0 {
+		s.add(path, jobDiffCategory(root, "", driver), o.Type, "", "")
+		return
+	}
+	for _, f := range o.Fields {
+		if f.Type == DiffTypeNone {
+			continue
+		}
+		s.add(path+"."+f.Name, jobDiffCategory(root, f.Name, driver), f.Type, f.Old, f.New)
+	}
+	for _, nested := range o.Objects {
+		s.addNestedObject(path+".", nested, root, driver)
+	}
+}
+
+// jobDiffCategory classifies a change by the name of the top level field or
+// object that changed. The field is the name of the changed field of the
+// object, and the driver is the driver of the task that changed, if any.
+func jobDiffCategory(name, field, driver string) string {
+	switch {
+	case name == "Count":
+		return JobDiffCategoryCount
+	case name == "Meta" || strings.HasPrefix(name, "Meta["):
+		return JobDiffCategoryMeta
+	case name == "Env" || strings.HasPrefix(name, "Env["):
+		return JobDiffCategoryEnv
+	case name == "Config":
+		if key, ok := driverImageConfigKeys[driver]; ok && field == key {
+			return JobDiffCategoryImage
+		}
+		return JobDiffCategoryConfig
+	case name == "Driver" || name == "User":
+		return JobDiffCategoryConfig
+	case name == "Artifact":
+		return JobDiffCategoryArtifact
+	case name == "Resources" || name == "EphemeralDisk" || name == "Network":
+		return JobDiffCategoryResources
+	case name == "Template":
+		return JobDiffCategoryTemplate
+	case name == "Service":
+		return JobDiffCategoryService
+	}
+	return JobDiffCategoryOther
+}
+
+// jobTaskDriver returns the driver of a task of the job, or an empty string
+// if the task doesn't exist.
+func jobTaskDriver(job *Job, group, task string) string {
+	if job == nil {
+		return ""
+	}
+	tg := job.LookupTaskGroup(group)
+	if tg == nil {
+		return ""
+	}
+	if t := tg.LookupTask(task); t != nil {
+		return t.Driver
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestJobDiffSummary(t *testing.T) {
+	ci.Parallel(t)
+
+	base := func() *Job {
+		return &Job{
+			ID:      "example",
+			Version: 1,
+			TaskGroups: []*TaskGroup{{
+				Name:  "web",
+				Count: 1,
+				Tasks: []*Task{{
+					Name:   "server",
+					Driver: "docker",
+					Config: map[string]any{
+						"image": "nginx:1.24",
+						"ports": []string{"http"},
+					},
+					Env: map[string]string{"LOG_LEVEL": "info"},
+				}},
+			}},
+		}
+	}
+
+	testCases := []struct {
+		name       string
+		update     func(*Job)
+		categories []string
+		paths      []string
+	}{
+		{
+			name:       "no changes",
+			update:     func(*Job) {},
+			categories: nil,
+			paths:      nil,
+		},
+		{
+			name: "image only",
+			update: func(j *Job) {
+				j.TaskGroups[0].Tasks[0].Config["image"] = "nginx:1.25"
+			},
+			categories: []string{JobDiffCategoryImage},
+			paths:      []string{"TaskGroups[web].Tasks[server].Config.image"},
+		},
+		{
+			name: "driver config",
+			update: func(j *Job) {
+				j.TaskGroups[0].Tasks[0].Config["command"] = "/bin/server"
+			},
+			categories: []string{JobDiffCategoryConfig},
+			paths:      []string{"TaskGroups[web].Tasks[server].Config.command"},
+		},
+		{
+			name: "count and env",
+			update: func(j *Job) {
+				j.TaskGroups[0].Count = 3
+				j.TaskGroups[0].Tasks[0].Env["LOG_LEVEL"] = "debug"
+			},
+			categories: []string{JobDiffCategoryCount, JobDiffCategoryEnv},
+			paths: []string{
+				"TaskGroups[web].Count",
+				"TaskGroups[web].Tasks[server].Env[LOG_LEVEL]",
+			},
+		},
+		{
+			name: "added group",
+			update: func(j *Job) {
+				j.TaskGroups = append(j.TaskGroups, &TaskGroup{Name: "cache", Count: 1})
+			},
+			categories: []string{JobDiffCategoryStructure},
+			paths:      []string{"TaskGroups[cache]"},
+		},
+		{
+			name: "job meta",
+			update: func(j *Job) {
+				j.Meta = map[string]string{"owner": "platform"}
+			},
+			categories: []string{JobDiffCategoryMeta},
+			paths:      []string{"Meta[owner]"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			old, new := base(), base()
+			new.Version = 2
+			tc.update(new)
+
+			summary, err := NewJobDiffSummary(old, new)
+			must.NoError(t, err)
+			must.Eq(t, 1, summary.FromVersion)
+			must.Eq(t, 2, summary.ToVersion)
+			must.Eq(t, tc.categories, summary.Categories)
+
+			var paths []string
+			for _, c := range summary.Changes {
+				paths = append(paths, c.Path)
+			}
+			must.Eq(t, tc.paths, paths)
+		})
+	}
+}
+
+func TestJobDiffSummary_OnlyCategories(t *testing.T) {
+	ci.Parallel(t)
+
+	summary := &JobDiffSummary{
+		Categories: []string{JobDiffCategoryEnv, JobDiffCategoryImage},
+	}
+	must.True(t, summary.OnlyCategories(JobDiffCategoryImage, JobDiffCategoryEnv))
+	must.False(t, summary.OnlyCategories(JobDiffCategoryImage))
+	must.True(t, (&JobDiffSummary{}).OnlyCategories(JobDiffCategoryImage))
+}
@@ -177,6 +177,12 @@ func TestJobDiff(t *testing.T) {
 						Old:  "foo",
 						New:  "",
 					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "Prefetch",
+						Old:  "false",
+						New:  "",
+					},
 					{
 						Type: DiffTypeDeleted,
 						Name: "Priority",
@@ -246,6 +252,12 @@ func TestJobDiff(t *testing.T) {
 						Old:  "",
 						New:  "foo",
 					},
+					{
+						Type: DiffTypeAdded,
+						Name: "Prefetch",
+						Old:  "",
+						New:  "false",
+					},
 					{
 						Type: DiffTypeAdded,
 						Name: "Priority",
@@ -1349,9 +1361,11 @@ func TestJobDiff(t *testing.T) {
 			Old: &Job{},
 			New: &Job{
 				ParameterizedJob: &ParameterizedJobConfig{
-					Payload:      DispatchPayloadRequired,
-					MetaOptional: []string{"foo"},
-					MetaRequired: []string{"bar"},
+					Payload:       DispatchPayloadRequired,
+					MetaOptional:  []string{"foo"},
+					MetaRequired:  []string{"bar"},
+					MaxConcurrent: 10,
+					QueueLimit:    100,
 				},
 			},
 			Expected: &JobDiff{
@@ -1361,12 +1375,24 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "ParameterizedJob",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "MaxConcurrent",
+								Old:  "",
+								New:  "10",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Payload",
 								Old:  "",
 								New:  DispatchPayloadRequired,
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "QueueLimit",
+								Old:  "",
+								New:  "100",
+							},
 						},
 						Objects: []*ObjectDiff{
 							{
@@ -1398,13 +1424,75 @@ func TestJobDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Notify edited
+			Old: &Job{
+				Notify: &JobNotify{
+					Webhook: "https://example.com/old",
+					Secret:  "old-secret",
+					On:      []string{JobNotifyComplete, JobNotifyFailed},
+				},
+			},
+			New: &Job{
+				Notify: &JobNotify{
+					Webhook: "https://example.com/new",
+					Secret:  "new-secret",
+					Headers: map[string]string{"X-Team": "data"},
+					On:      []string{JobNotifyFailed},
+				},
+			},
+			Expected: &JobDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Notify",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Headers[X-Team]",
+								Old:  "",
+								New:  JobNotifyRedacted,
+							},
+							{
+								Type: DiffTypeEdited,
+								Name: "Secret",
+								Old:  JobNotifyRedacted,
+								New:  JobNotifyRedacted,
+							},
+							{
+								Type: DiffTypeEdited,
+								Name: "Webhook",
+								Old:  "https://example.com/old",
+								New:  "https://example.com/new",
+							},
+						},
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "On",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeDeleted,
+										Name: "On",
+										Old:  JobNotifyComplete,
+										New:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			// Parameterized Job deleted
 			Old: &Job{
 				ParameterizedJob: &ParameterizedJobConfig{
-					Payload:      DispatchPayloadRequired,
-					MetaOptional: []string{"foo"},
-					MetaRequired: []string{"bar"},
+					Payload:       DispatchPayloadRequired,
+					MetaOptional:  []string{"foo"},
+					MetaRequired:  []string{"bar"},
+					MaxConcurrent: 10,
 				},
 			},
 			New: &Job{},
@@ -1415,12 +1503,24 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeDeleted,
 						Name: "ParameterizedJob",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "MaxConcurrent",
+								Old:  "10",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "Payload",
 								Old:  DispatchPayloadRequired,
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "QueueLimit",
+								Old:  "0",
+								New:  "",
+							},
 						},
 						Objects: []*ObjectDiff{
 							{
@@ -1548,12 +1648,24 @@ func TestJobDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "ParameterizedJob",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeNone,
+								Name: "MaxConcurrent",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "Payload",
 								Old:  DispatchPayloadRequired,
 								New:  DispatchPayloadOptional,
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "QueueLimit",
+								Old:  "0",
+								New:  "0",
+							},
 						},
 						Objects: []*ObjectDiff{
 							{
@@ -2098,6 +2210,66 @@ func TestTaskGroupDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			TestCase: "Spreads edited",
+			Old: &TaskGroup{
+				Spreads: []*Spread{
+					{
+						Attribute: "${node.datacenter}",
+						Weight:    50,
+					},
+				},
+			},
+			New: &TaskGroup{
+				Spreads: []*Spread{
+					{
+						Attribute: "${meta.rack}",
+						Weight:    50,
+					},
+				},
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeAdded,
+						Name: "Spread",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Attribute",
+								Old:  "",
+								New:  "${meta.rack}",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Weight",
+								Old:  "",
+								New:  "50",
+							},
+						},
+					},
+					{
+						Type: DiffTypeDeleted,
+						Name: "Spread",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeDeleted,
+								Name: "Attribute",
+								Old:  "${node.datacenter}",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Weight",
+								Old:  "50",
+								New:  "",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			TestCase: "Consul added",
 			Old:      &TaskGroup{},
@@ -2218,18 +2390,42 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Budget",
+								Old:  "",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "BudgetWindow",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Delay",
 								Old:  "",
 								New:  "1000000000",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "DelayMultiplier",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Interval",
 								Old:  "",
 								New:  "1000000000",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "MaxDelay",
+								Old:  "",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Mode",
@@ -2271,18 +2467,42 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "1",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Budget",
+								Old:  "0",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "BudgetWindow",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "Delay",
 								Old:  "1000000000",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "DelayMultiplier",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "Interval",
 								Old:  "1000000000",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "MaxDelay",
+								Old:  "0",
+								New:  "",
+							},
 							{
 								Type: DiffTypeDeleted,
 								Name: "Mode",
@@ -2395,18 +2615,48 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "1",
 								New:  "2",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Budget",
+								Old:  "0",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "BudgetWindow",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "Delay",
 								Old:  "1000000000",
 								New:  "1000000000",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "DelayFunction",
+								Old:  "",
+								New:  "",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "DelayMultiplier",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "Interval",
 								Old:  "1000000000",
 								New:  "2000000000",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "MaxDelay",
+								Old:  "0",
+								New:  "0",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "Mode",
@@ -2935,6 +3185,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "2",
 								New:  "2",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "CanaryPlacement",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "HealthCheck",
@@ -2965,6 +3221,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "30000000000",
 								New:  "30000000000",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Strategy",
+								Old:  "",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -3300,6 +3562,70 @@ func TestTaskGroupDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			TestCase: "EphemeralDisk migrate hook added",
+			Old: &TaskGroup{
+				EphemeralDisk: &EphemeralDisk{
+					Sticky: true,
+					SizeMB: 100,
+				},
+			},
+			New: &TaskGroup{
+				EphemeralDisk: &EphemeralDisk{
+					Sticky: true,
+					SizeMB: 100,
+					MigrateHooks: []*MigrateHook{
+						{
+							Stage:   MigrateHookStagePreMigrate,
+							Task:    "db",
+							Command: "/bin/flush",
+							Timeout: time.Minute,
+						},
+					},
+				},
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "EphemeralDisk",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "MigrateHook",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Command",
+										Old:  "",
+										New:  "/bin/flush",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Stage",
+										Old:  "",
+										New:  "pre_migrate",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Task",
+										Old:  "",
+										New:  "db",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Timeout",
+										Old:  "",
+										New:  "60000000000",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			TestCase:   "EphemeralDisk edited with context",
 			Contextual: true,
@@ -3744,6 +4070,18 @@ func TestTaskGroupDiff(t *testing.T) {
 										Old:  "http",
 										New:  "tcp",
 									},
+									{
+										Type: DiffTypeNone,
+										Name: "UseContainerHealth",
+										Old:  "false",
+										New:  "false",
+									},
+									{
+										Type: DiffTypeNone,
+										Name: "Weight",
+										Old:  "0",
+										New:  "0",
+									},
 								},
 								Objects: []*ObjectDiff{
 									{
@@ -4583,6 +4921,8 @@ func TestTaskGroupDiff(t *testing.T) {
 						Source:   "foo-src",
 						ReadOnly: true,
 						PerAlloc: true,
+						Create:   true,
+						SizeMB:   100,
 					},
 				},
 			},
@@ -4594,6 +4934,18 @@ func TestTaskGroupDiff(t *testing.T) {
 						Type: DiffTypeAdded,
 						Name: "Volume",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Affinity",
+								Old:  "",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Create",
+								Old:  "",
+								New:  "true",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Name",
@@ -4612,6 +4964,12 @@ func TestTaskGroupDiff(t *testing.T) {
 								Old:  "",
 								New:  "true",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "SizeMB",
+								Old:  "",
+								New:  "100",
+							},
 							{
 								Type: DiffTypeAdded,
 								Name: "Source",
@@ -4981,6 +5339,65 @@ func TestTaskGroupDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			TestCase: "Scaling schedule added",
+			Old: &TaskGroup{
+				Scaling: &ScalingPolicy{
+					Enabled: true,
+					Max:     10,
+					Min:     1,
+				},
+			},
+			New: &TaskGroup{
+				Scaling: &ScalingPolicy{
+					Enabled: true,
+					Max:     10,
+					Min:     1,
+					Schedules: []*ScalingSchedule{
+						{
+							Cron:     "0 8 * * *",
+							Count:    5,
+							TimeZone: "Europe/Berlin",
+						},
+					},
+				},
+			},
+			Expected: &TaskGroupDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "Scaling",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeAdded,
+								Name: "Schedule",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Count",
+										Old:  "",
+										New:  "5",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Cron",
+										Old:  "",
+										New:  "0 8 * * *",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "TimeZone",
+										Old:  "",
+										New:  "Europe/Berlin",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for i, c := range cases {
@@ -5409,6 +5826,18 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Retention",
+								Old:  "",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "RotateInterval",
+								Old:  "",
+								New:  "0",
+							},
 						},
 					},
 				},
@@ -5449,6 +5878,18 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "1",
 								New:  "",
 							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Retention",
+								Old:  "0",
+								New:  "",
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "RotateInterval",
+								Old:  "0",
+								New:  "",
+							},
 						},
 					},
 				},
@@ -5524,6 +5965,18 @@ func TestTaskDiff(t *testing.T) {
 						Type: DiffTypeEdited,
 						Name: "LogConfig",
 						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeNone,
+								Name: "Backpressure",
+								Old:  "",
+								New:  "",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "Compression",
+								Old:  "",
+								New:  "",
+							},
 							{
 								Type: DiffTypeEdited,
 								Name: "Disabled",
@@ -5542,6 +5995,88 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "1",
 								New:  "1",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "Retention",
+								Old:  "0",
+								New:  "0",
+							},
+							{
+								Type: DiffTypeNone,
+								Name: "RotateInterval",
+								Old:  "0",
+								New:  "0",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "LogConfig sinks edited",
+			Old: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+					Sinks: []*LogSink{
+						{Name: "elasticsearch"},
+						{Name: "loki", Options: map[string]string{"team": "web"}},
+					},
+				},
+			},
+			New: &Task{
+				LogConfig: &LogConfig{
+					MaxFiles:      1,
+					MaxFileSizeMB: 10,
+					Sinks: []*LogSink{
+						{Name: "loki", Options: map[string]string{"team": "api"}},
+						{Name: "cloudwatch"},
+					},
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "LogConfig",
+						Objects: []*ObjectDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "Sink",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeEdited,
+										Name: "Options[team]",
+										Old:  "web",
+										New:  "api",
+									},
+								},
+							},
+							{
+								Type: DiffTypeAdded,
+								Name: "Sink",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeAdded,
+										Name: "Name",
+										Old:  "",
+										New:  "cloudwatch",
+									},
+								},
+							},
+							{
+								Type: DiffTypeDeleted,
+								Name: "Sink",
+								Fields: []*FieldDiff{
+									{
+										Type: DiffTypeDeleted,
+										Name: "Name",
+										Old:  "elasticsearch",
+										New:  "",
+									},
+								},
+							},
 						},
 					},
 				},
@@ -5791,6 +6326,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "100",
 								New:  "200",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "ExclusiveCores",
+								Old:  "false",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "IOPS",
@@ -5894,6 +6435,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "100",
 								New:  "100",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "ExclusiveCores",
+								Old:  "false",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "IOPS",
@@ -6259,6 +6806,12 @@ func TestTaskDiff(t *testing.T) {
 								Old:  "100",
 								New:  "100",
 							},
+							{
+								Type: DiffTypeNone,
+								Name: "ExclusiveCores",
+								Old:  "false",
+								New:  "false",
+							},
 							{
 								Type: DiffTypeNone,
 								Name: "IOPS",
@@ -7238,6 +7791,18 @@ func TestTaskDiff(t *testing.T) {
 										Old:  "",
 										New:  "http",
 									},
+									{
+										Type: DiffTypeAdded,
+										Name: "UseContainerHealth",
+										Old:  "",
+										New:  "false",
+									},
+									{
+										Type: DiffTypeAdded,
+										Name: "Weight",
+										Old:  "",
+										New:  "0",
+									},
 								},
 							},
 							{
@@ -7322,6 +7887,18 @@ func TestTaskDiff(t *testing.T) {
 										Old:  "http",
 										New:  "",
 									},
+									{
+										Type: DiffTypeDeleted,
+										Name: "UseContainerHealth",
+										Old:  "false",
+										New:  "",
+									},
+									{
+										Type: DiffTypeDeleted,
+										Name: "Weight",
+										Old:  "0",
+										New:  "",
+									},
 								},
 								Objects: []*ObjectDiff{
 									{
@@ -7602,6 +8179,18 @@ func TestTaskDiff(t *testing.T) {
 										Old:  "http",
 										New:  "tcp",
 									},
+									{
+										Type: DiffTypeNone,
+										Name: "UseContainerHealth",
+										Old:  "false",
+										New:  "false",
+									},
+									{
+										Type: DiffTypeNone,
+										Name: "Weight",
+										Old:  "0",
+										New:  "0",
+									},
 								},
 								Objects: []*ObjectDiff{
 									{
@@ -8691,6 +9280,50 @@ func TestTaskDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "OOMPolicy edited",
+			Old: &Task{
+				OOMPolicy: &OOMPolicy{
+					Mode: OOMPolicyModeRestart,
+				},
+			},
+			New: &Task{
+				OOMPolicy: &OOMPolicy{
+					Mode:             OOMPolicyModeAdjust,
+					MemoryIncreaseMB: 128,
+					MemoryMaxMB:      1024,
+				},
+			},
+			Expected: &TaskDiff{
+				Type: DiffTypeEdited,
+				Objects: []*ObjectDiff{
+					{
+						Type: DiffTypeEdited,
+						Name: "OOMPolicy",
+						Fields: []*FieldDiff{
+							{
+								Type: DiffTypeEdited,
+								Name: "MemoryIncreaseMB",
+								Old:  "0",
+								New:  "128",
+							},
+							{
+								Type: DiffTypeEdited,
+								Name: "MemoryMaxMB",
+								Old:  "0",
+								New:  "1024",
+							},
+							{
+								Type: DiffTypeEdited,
+								Name: "Mode",
+								Old:  "restart",
+								New:  "adjust",
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			Name: "Identity added",
 			Old:  &Task{},
@@ -10020,6 +10653,132 @@ func TestServicesDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:       "ConsulProxy with envoy added",
+			Contextual: false,
+			Old: []*Service{
+				{
+					Name:      "webapp",
+					Provider:  "consul",
+					PortLabel: "http",
+					Connect: &ConsulConnect{
+						SidecarService: &ConsulSidecarService{
+							Port:  "http",
+							Proxy: &ConsulProxy{},
+						},
+					},
+				},
+			},
+			New: []*Service{
+				{
+					Name:      "webapp",
+					Provider:  "consul",
+					PortLabel: "http",
+					Connect: &ConsulConnect{
+						SidecarService: &ConsulSidecarService{
+							Port: "http",
+							Proxy: &ConsulProxy{
+								Envoy: &ConsulEnvoyConfig{
+									Concurrency: 2,
+									Stats: &ConsulEnvoyStats{
+										Tags:          []string{"env=prod"},
+										FlushInterval: 5 * time.Second,
+									},
+									Tracing: &ConsulEnvoyTracing{
+										Provider: "zipkin",
+										Address:  "127.0.0.1:9411",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Expected: []*ObjectDiff{
+				{
+					Type: DiffTypeEdited,
+					Name: "Service",
+					Objects: []*ObjectDiff{
+						{
+							Type: DiffTypeEdited,
+							Name: "ConsulConnect",
+							Objects: []*ObjectDiff{
+								{
+									Type: DiffTypeEdited,
+									Name: "SidecarService",
+									Objects: []*ObjectDiff{
+										{
+											Type: DiffTypeEdited,
+											Name: "ConsulProxy",
+											Objects: []*ObjectDiff{
+												{
+													Type: DiffTypeAdded,
+													Name: "Envoy",
+													Fields: []*FieldDiff{
+														{
+															Type: DiffTypeAdded,
+															Name: "Concurrency",
+															Old:  "",
+															New:  "2",
+														},
+													},
+													Objects: []*ObjectDiff{
+														{
+															Type: DiffTypeAdded,
+															Name: "Stats",
+															Fields: []*FieldDiff{
+																{
+																	Type: DiffTypeAdded,
+																	Name: "FlushInterval",
+																	Old:  "",
+																	New:  "5000000000",
+																},
+															},
+															Objects: []*ObjectDiff{
+																{
+																	Type: DiffTypeAdded,
+																	Name: "Tags",
+																	Fields: []*FieldDiff{
+																		{
+																			Type: DiffTypeAdded,
+																			Name: "Tags",
+																			Old:  "",
+																			New:  "env=prod",
+																		},
+																	},
+																},
+															},
+														},
+														{
+															Type: DiffTypeAdded,
+															Name: "Tracing",
+															Fields: []*FieldDiff{
+																{
+																	Type: DiffTypeAdded,
+																	Name: "Address",
+																	Old:  "",
+																	New:  "127.0.0.1:9411",
+																},
+																{
+																	Type: DiffTypeAdded,
+																	Name: "Provider",
+																	Old:  "",
+																	New:  "zipkin",
+																},
+															},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		{
 			Name:       "SidecarService with different meta",
 			Contextual: false,
@@ -17,6 +17,9 @@ const (
 	errTokenNotFound              = "ACL token not found"
 	errTokenExpired               = "ACL token expired"
 	errTokenInvalid               = "ACL token is invalid" // not a UUID
+	errTokenNotYetValid           = "ACL token not yet valid"
+	errTokenPending               = "ACL token pending approval"
+	errTokenExhausted             = "ACL token maximum uses reached"
 	errPermissionDenied           = "Permission denied"
 	errJobRegistrationDisabled    = "Job registration, dispatch, and scale are disabled by the scheduler configuration"
 	errNoNodeConn                 = "No path to node"
@@ -55,6 +58,9 @@ var (
 	ErrTokenNotFound              = errors.New(errTokenNotFound)
 	ErrTokenExpired               = errors.New(errTokenExpired)
 	ErrTokenInvalid               = errors.New(errTokenInvalid)
+	ErrTokenNotYetValid           = errors.New(errTokenNotYetValid)
+	ErrTokenPending               = errors.New(errTokenPending)
+	ErrTokenExhausted             = errors.New(errTokenExhausted)
 	ErrPermissionDenied           = errors.New(errPermissionDenied)
 	ErrJobRegistrationDisabled    = errors.New(errJobRegistrationDisabled)
 	ErrNoNodeConn                 = errors.New(errNoNodeConn)
@@ -100,6 +106,12 @@ func IsErrTokenNotFound(err error) bool {
 	return err != nil && strings.Contains(err.Error(), errTokenNotFound)
 }
 
+// IsErrTokenExhausted returns whether the error is due to the passed token
+// having reached its maximum number of uses.
+func IsErrTokenExhausted(err error) bool {
+	return err != nil && strings.Contains(err.Error(), errTokenExhausted)
+}
+
 // IsErrPermissionDenied returns whether the error is due to the operation not
 // being allowed due to lack of permissions.
 func IsErrPermissionDenied(err error) bool {
@@ -10,6 +10,13 @@ const (
 	// Args: EvalDeleteRequest
 	// Reply: EvalDeleteResponse
 	EvalDeleteRPCMethod = "Eval.Delete"
+
+	// EvalBumpPriorityRPCMethod is the RPC method for raising the priority
+	// of pending or blocked evaluations using their IDs.
+	//
+	// Args: EvalBumpPriorityRequest
+	// Reply: EvalBumpPriorityResponse
+	EvalBumpPriorityRPCMethod = "Eval.BumpPriority"
 )
 
 // EvalDeleteRequest is the request object used when operators are manually
@@ -31,3 +38,30 @@ type EvalDeleteResponse struct {
 	Count int // how many Evaluations were safe to delete and/or matched the filter
 	WriteMeta
 }
+
+// EvalBumpPriorityRequest is the request object used when operators raise the
+// priority of pending or blocked evaluations, so they are processed before
+// evaluations of lower priority.
+type EvalBumpPriorityRequest struct {
+	EvalIDs []string
+
+	// Priority is the new priority of the evaluations. It must be higher
+	// than their current priority.
+	Priority int
+
+	// BumpedBy and BumpTime are set by the server to record the identity of
+	// the operator and the time of the request.
+	BumpedBy string
+	BumpTime int64
+
+	WriteRequest
+}
+
+// EvalBumpPriorityResponse is the response object when the priority of
+// evaluations is bumped by an operator.
+type EvalBumpPriorityResponse struct {
+	// EvalIDs are the IDs of the bumped evaluations, including the pending
+	// or blocked follow-up evaluations of the requested evaluations.
+	EvalIDs []string
+	WriteMeta
+}
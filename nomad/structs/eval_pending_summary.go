@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"fmt"
+	"sort"
+)
+
+// JobPendingSummary digests the blocked evaluation of a job to explain why
+// some of its allocations can't be placed.
+type JobPendingSummary struct {
+	Namespace string
+	JobID     string
+
+	// EvalID is the ID of the job's blocked evaluation.
+	EvalID string
+
+	// BlockedSince is when the blocked evaluation was created, in unix
+	// nanoseconds.
+	BlockedSince int64
+
+	// QuotaLimitReached is the quota that blocked the evaluation, if any.
+	QuotaLimitReached string
+
+	// TaskGroups is the summary of each task group that failed placement.
+	TaskGroups map[string]*TaskGroupPendingSummary
+}
+
+// TaskGroupPendingSummary explains why allocations of a task group can't be
+// placed.
+type TaskGroupPendingSummary struct {
+	// Queued is the number of allocations waiting to be placed.
+	Queued int
+
+	// Reasons are human readable reasons for the failed placements, ordered
+	// as they're checked by the scheduler.
+	Reasons []string
+
+	// Metrics are the metrics of the last failed placement.
+	Metrics *AllocMetric
+}
+
+// NewJobPendingSummary returns the pending summary of the blocked evaluation.
+// The job summary is optional, and is used for the number of queued
+// allocations of each task group.
+func NewJobPendingSummary(eval *Evaluation, jobSummary *JobSummary) *JobPendingSummary {
+	s := &JobPendingSummary{
+		Namespace:         eval.Namespace,
+		JobID:             eval.JobID,
+		EvalID:            eval.ID,
+		BlockedSince:      eval.CreateTime,
+		QuotaLimitReached: eval.QuotaLimitReached,
+		TaskGroups:        make(map[string]*TaskGroupPendingSummary, len(eval.FailedTGAllocs)),
+	}
+
+	for tg, metric := range eval.FailedTGAllocs {
+		tgSummary := &TaskGroupPendingSummary{
+			Queued:  metric.CoalescedFailures + 1,
+			Reasons: pendingReasons(metric),
+			Metrics: metric.Copy(),
+		}
+		if jobSummary != nil {
+			if summary, ok := jobSummary.Summary[tg]; ok && summary.Queued > 0 {
+				tgSummary.Queued = summary.Queued
+			}
+		}
+		s.TaskGroups[tg] = tgSummary
+	}
+
+	return s
+}
+
+// pendingReasons returns the reasons for a failed placement from its metrics.
+func pendingReasons(m *AllocMetric) []string {
+	var reasons []string
+
+	if m.NodesEvaluated == 0 {
+		reasons = append(reasons, "No nodes were eligible for evaluation")
+	}
+	for _, dc := range sortedIntMapKeys(m.NodesAvailable) {
+		if m.NodesAvailable[dc] == 0 {
+			reasons = append(reasons, fmt.Sprintf("No nodes are available in datacenter %q", dc))
+		}
+	}
+
+	for _, class := range sortedIntMapKeys(m.ClassFiltered) {
+		reasons = append(reasons, fmt.Sprintf("Class %q: %d nodes excluded by filter",
+			class, m.ClassFiltered[class]))
+	}
+	for _, cs := range sortedIntMapKeys(m.ConstraintFiltered) {
+		reasons = append(reasons, fmt.Sprintf("Constraint %q: %d nodes excluded by filter",
+			cs, m.ConstraintFiltered[cs]))
+	}
+
+	if m.NodesExhausted > 0 {
+		reasons = append(reasons, fmt.Sprintf("Resources exhausted on %d nodes", m.NodesExhausted))
+	}
+	for _, class := range sortedIntMapKeys(m.ClassExhausted) {
+		reasons = append(reasons, fmt.Sprintf("Class %q exhausted on %d nodes",
+			class, m.ClassExhausted[class]))
+	}
+	for _, dim := range sortedIntMapKeys(m.DimensionExhausted) {
+		reasons = append(reasons, fmt.Sprintf("Dimension %q exhausted on %d nodes",
+			dim, m.DimensionExhausted[dim]))
+	}
+
+	for _, dim := range m.QuotaExhausted {
+		reasons = append(reasons, fmt.Sprintf("Quota limit hit %q", dim))
+	}
+
+	return reasons
+}
+
+func sortedIntMapKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
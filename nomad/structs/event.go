@@ -8,6 +8,10 @@ type EventStreamRequest struct {
 	Topics map[Topic][]string
 	Index  int
 
+	// Msgpack encodes the events with msgpack rather than JSON. Servers that
+	// don't support it ignore it and encode the events with JSON.
+	Msgpack bool
+
 	QueryOptions
 }
 
@@ -31,6 +35,7 @@ const (
 	TopicACLAuthMethod  Topic = "ACLAuthMethod"
 	TopicACLBindingRule Topic = "ACLBindingRule"
 	TopicService        Topic = "Service"
+	TopicVariable       Topic = "Variable"
 	TopicAll            Topic = "*"
 
 	TypeNodeRegistration              = "NodeRegistration"
@@ -47,9 +52,11 @@ const (
 	TypeAllocationUpdated             = "AllocationUpdated"
 	TypeAllocationUpdateDesiredStatus = "AllocationUpdateDesiredStatus"
 	TypeEvalUpdated                   = "EvaluationUpdated"
+	TypeEvalPriorityBumped            = "EvaluationPriorityBumped"
 	TypeJobRegistered                 = "JobRegistered"
 	TypeJobDeregistered               = "JobDeregistered"
 	TypeJobBatchDeregistered          = "JobBatchDeregistered"
+	TypeJobNotification               = "JobNotification"
 	TypePlanResult                    = "PlanResult"
 	TypeACLTokenDeleted               = "ACLTokenDeleted"
 	TypeACLTokenUpserted              = "ACLTokenUpserted"
@@ -63,6 +70,7 @@ const (
 	TypeACLBindingRuleDeleted         = "ACLBindingRuleDeleted"
 	TypeServiceRegistration           = "ServiceRegistration"
 	TypeServiceDeregistration         = "ServiceDeregistration"
+	TypeVariableExpired               = "VariableExpired"
 )
 
 // Event represents a change in Nomads state.
@@ -97,7 +105,8 @@ type Events struct {
 	Events []Event
 }
 
-// EventJson is a wrapper for a JSON object
+// EventJson is a wrapper for a JSON object, or a msgpack object if the
+// stream was requested with EventStreamRequest.Msgpack.
 type EventJson struct {
 	Data []byte
 }
@@ -113,6 +122,14 @@ func (j *EventJson) Copy() *EventJson {
 // JobEvent holds a newly updated Job.
 type JobEvent struct {
 	Job *Job
+
+	// DiffSummary is the summary of the changes from the previous version
+	// of the job, when the event is for a new version.
+	DiffSummary *JobDiffSummary
+
+	// Notification is the notification of the completed job, for
+	// JobNotification events.
+	Notification *JobNotification
 }
 
 // EvaluationEvent holds a newly updated Eval.
@@ -152,6 +169,12 @@ type ServiceRegistrationStreamEvent struct {
 	Service *ServiceRegistration
 }
 
+// VariableEvent holds the metadata of an expired variable. The variable's
+// encrypted data is never included in the event.
+type VariableEvent struct {
+	Variable *VariableMetadata
+}
+
 // NewACLTokenEvent takes a token and creates a new ACLTokenEvent.  It creates
 // a copy of the passed in ACLToken and empties out the copied tokens SecretID
 func NewACLTokenEvent(token *ACLToken) *ACLTokenEvent {
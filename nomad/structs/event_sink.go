@@ -0,0 +1,313 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper/pointer"
+)
+
+const (
+	// EventSinkTypeWebhook is the type of event sinks that POST batches of
+	// events to an HTTP endpoint.
+	EventSinkTypeWebhook = "webhook"
+
+	// EventSinkTypeKafka is the type of event sinks that produce events to a
+	// Kafka topic.
+	EventSinkTypeKafka = "kafka"
+
+	// EventSinkTypeNATS is the type of event sinks that publish events to a
+	// NATS subject.
+	EventSinkTypeNATS = "nats"
+
+	// DefaultEventSinkMinBackoff is the default initial wait before retrying
+	// a failed delivery to an event sink.
+	DefaultEventSinkMinBackoff = time.Second
+
+	// DefaultEventSinkMaxBackoff is the default maximum wait between retries
+	// of a failed delivery to an event sink.
+	DefaultEventSinkMaxBackoff = time.Minute
+)
+
+var (
+	// validEventSinkID is the rule used to validate an event sink ID.
+	validEventSinkID = regexp.MustCompile("^[a-zA-Z0-9-_]{1,128}$")
+)
+
+// EventSink is a managed subscription to the event stream. The leader
+// delivers the events matching the sink topics to the sink address and
+// tracks the index of the last delivered event in raft, so delivery resumes
+// where it stopped after a leader election. Events are delivered at least
+// once.
+type EventSink struct {
+	// ID is the unique identifier of the event sink.
+	ID string
+
+	// Type is the type of the event sink, one of webhook, kafka, or nats.
+	Type string
+
+	// Address is where events are delivered. It is the URL of the endpoint
+	// for webhook sinks, a comma separated list of bootstrap brokers for
+	// Kafka sinks, and the URL of the server for NATS sinks.
+	Address string
+
+	// Topics is the topics and keys of the events delivered to the sink,
+	// with the same semantics as the event stream API.
+	Topics map[Topic][]string
+
+	// Namespace filters the namespaced events delivered to the sink. It
+	// defaults to all namespaces.
+	Namespace string
+
+	// Webhook is the webhook specific configuration.
+	Webhook *EventSinkWebhookConfig
+
+	// Kafka is the Kafka specific configuration.
+	Kafka *EventSinkKafkaConfig
+
+	// NATS is the NATS specific configuration.
+	NATS *EventSinkNATSConfig
+
+	// MinBackoff and MaxBackoff bound the exponential backoff between
+	// retries of a failed delivery.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// LatestIndex is the raft index of the last event delivered to the
+	// sink.
+	LatestIndex uint64
+
+	// Raft indexes.
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// EventSinkWebhookConfig is the configuration of webhook event sinks.
+type EventSinkWebhookConfig struct {
+	// Headers are added to the requests sent to the webhook.
+	Headers map[string]string
+}
+
+// EventSinkKafkaConfig is the configuration of Kafka event sinks.
+type EventSinkKafkaConfig struct {
+	// Topic is the Kafka topic events are produced to.
+	Topic string
+
+	// ClientID is the client ID sent to the brokers.
+	ClientID string
+}
+
+// EventSinkNATSConfig is the configuration of NATS event sinks.
+type EventSinkNATSConfig struct {
+	// Subject is the NATS subject events are published to.
+	Subject string
+}
+
+// GetID implements the IDGetter interface required for pagination.
+func (s *EventSink) GetID() string {
+	return s.ID
+}
+
+// Canonicalize sets the defaults of the event sink.
+func (s *EventSink) Canonicalize() {
+	if len(s.Topics) == 0 {
+		s.Topics = map[Topic][]string{TopicAll: {"*"}}
+	}
+	if s.Namespace == "" {
+		s.Namespace = "*"
+	}
+	if s.MinBackoff == 0 {
+		s.MinBackoff = DefaultEventSinkMinBackoff
+	}
+	if s.MaxBackoff == 0 {
+		s.MaxBackoff = DefaultEventSinkMaxBackoff
+	}
+	if s.Kafka != nil && s.Kafka.ClientID == "" {
+		s.Kafka.ClientID = "nomad"
+	}
+}
+
+// Validate returns an error if the event sink is invalid.
+func (s *EventSink) Validate() error {
+	var mErr *multierror.Error
+
+	if !validEventSinkID.MatchString(s.ID) {
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid ID %q, must match regex %s", s.ID, validEventSinkID))
+	}
+
+	switch s.Type {
+	case EventSinkTypeWebhook:
+		if u, err := url.Parse(s.Address); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("invalid webhook address: %v", err))
+		} else if u.Scheme != "http" && u.Scheme != "https" {
+			mErr = multierror.Append(mErr, fmt.Errorf("webhook address must be an http or https URL"))
+		}
+	case EventSinkTypeKafka:
+		if s.Address == "" {
+			mErr = multierror.Append(mErr, errors.New("missing Kafka brokers address"))
+		}
+		for _, broker := range strings.Split(s.Address, ",") {
+			if !strings.Contains(broker, ":") {
+				mErr = multierror.Append(mErr, fmt.Errorf("invalid Kafka broker %q, must be host:port", broker))
+			}
+		}
+		if s.Kafka == nil || s.Kafka.Topic == "" {
+			mErr = multierror.Append(mErr, errors.New("missing Kafka topic"))
+		}
+	case EventSinkTypeNATS:
+		if u, err := url.Parse(s.Address); err != nil {
+			mErr = multierror.Append(mErr, fmt.Errorf("invalid NATS address: %v", err))
+		} else if (u.Scheme != "nats" && u.Scheme != "tls") || u.Host == "" {
+			mErr = multierror.Append(mErr, errors.New("NATS address must be a nats:// or tls:// URL"))
+		}
+		if s.NATS == nil || s.NATS.Subject == "" {
+			mErr = multierror.Append(mErr, errors.New("missing NATS subject"))
+		} else if strings.ContainsAny(s.NATS.Subject, " \t\r\n*>") {
+			mErr = multierror.Append(mErr, fmt.Errorf("invalid NATS subject %q", s.NATS.Subject))
+		}
+	default:
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid type %q", s.Type))
+	}
+
+	if len(s.Topics) == 0 {
+		mErr = multierror.Append(mErr, errors.New("must specify at least one topic"))
+	}
+	for topic, keys := range s.Topics {
+		if len(keys) == 0 {
+			mErr = multierror.Append(mErr, fmt.Errorf("topic %q must specify at least one key", topic))
+		}
+	}
+
+	if s.MinBackoff < 0 || s.MaxBackoff < 0 {
+		mErr = multierror.Append(mErr, errors.New("backoff must not be negative"))
+	} else if s.MaxBackoff < s.MinBackoff {
+		mErr = multierror.Append(mErr, errors.New("max backoff must not be less than min backoff"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a deep copy of the event sink.
+func (s *EventSink) Copy() *EventSink {
+	if s == nil {
+		return nil
+	}
+
+	ns := new(EventSink)
+	*ns = *s
+
+	if s.Topics != nil {
+		ns.Topics = make(map[Topic][]string, len(s.Topics))
+		for topic, keys := range s.Topics {
+			ns.Topics[topic] = slices.Clone(keys)
+		}
+	}
+	if s.Webhook != nil {
+		ns.Webhook = &EventSinkWebhookConfig{
+			Headers: maps.Clone(s.Webhook.Headers),
+		}
+	}
+	if s.Kafka != nil {
+		ns.Kafka = pointer.Of(*s.Kafka)
+	}
+	if s.NATS != nil {
+		ns.NATS = pointer.Of(*s.NATS)
+	}
+	return ns
+}
+
+// Stub returns a summary of the event sink.
+func (s *EventSink) Stub() *EventSinkStub {
+	return &EventSinkStub{
+		ID:          s.ID,
+		Type:        s.Type,
+		Address:     s.redactedAddress(),
+		Topics:      s.Topics,
+		LatestIndex: s.LatestIndex,
+		CreateIndex: s.CreateIndex,
+		ModifyIndex: s.ModifyIndex,
+	}
+}
+
+// redactedAddress returns the address of the event sink without the user
+// information of URL addresses, which may hold credentials.
+func (s *EventSink) redactedAddress() string {
+	if s.Type == EventSinkTypeKafka {
+		return s.Address
+	}
+
+	u, err := url.Parse(s.Address)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	return u.String()
+}
+
+// EventSinkStub is the summary of an event sink returned when listing sinks.
+// Credentials in the address are redacted.
+type EventSinkStub struct {
+	ID          string
+	Type        string
+	Address     string
+	Topics      map[Topic][]string
+	LatestIndex uint64
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// EventSinkListRequest is used to list event sinks.
+type EventSinkListRequest struct {
+	QueryOptions
+}
+
+// EventSinkListResponse is the response to an event sinks list request.
+type EventSinkListResponse struct {
+	Sinks []*EventSinkStub
+	QueryMeta
+}
+
+// EventSinkSpecificRequest is used to make a request for a specific event
+// sink.
+type EventSinkSpecificRequest struct {
+	ID string
+	QueryOptions
+}
+
+// SingleEventSinkResponse is the response to a specific event sink request.
+type SingleEventSinkResponse struct {
+	Sink *EventSink
+	QueryMeta
+}
+
+// EventSinkUpsertRequest is used to make a request to insert or update event
+// sinks.
+type EventSinkUpsertRequest struct {
+	Sinks []*EventSink
+	WriteRequest
+}
+
+// EventSinkDeleteRequest is used to make a request to delete event sinks.
+type EventSinkDeleteRequest struct {
+	IDs []string
+	WriteRequest
+}
+
+// EventSinkProgressRequest is used by the leader to record the index of the
+// last event delivered to each event sink.
+type EventSinkProgressRequest struct {
+	// Progress maps event sink IDs to the index of the last event delivered
+	// to them.
+	Progress map[string]uint64
+	WriteRequest
+}
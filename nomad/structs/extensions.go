@@ -15,6 +15,7 @@ var (
 		reflect.TypeOf(&Node{}):      nodeExt,
 		reflect.TypeOf(CSIVolume{}):  csiVolumeExt,
 		reflect.TypeOf(&CSIVolume{}): csiVolumeExt,
+		reflect.TypeOf(&JobNotify{}): jobNotifyExt,
 	}
 )
 
@@ -79,3 +80,11 @@ func csiVolumeExt(v interface{}) interface{} {
 
 	return apiVol
 }
+
+// jobNotifyExt redacts the secret and header values of job notify blocks,
+// which may hold credentials.
+func jobNotifyExt(v interface{}) interface{} {
+	// the defined type prevents this extension from being called recursively
+	type EmbeddedJobNotify JobNotify
+	return (*EmbeddedJobNotify)(v.(*JobNotify).Redacted())
+}
@@ -177,6 +177,12 @@ func AllocsFit(node *Node, allocs []*Allocation, netIdx *NetworkIndex, checkDevi
 		return false, dimension, used, nil
 	}
 
+	// Check that the cpu used by tasks fits outside of the exclusive cores
+	// of the node, which are only used by the tasks reserving them
+	if !sharedComputeFits(node, available, used, reservedCores) {
+		return false, "cpu", used, nil
+	}
+
 	// Create the network index if missing
 	if netIdx == nil {
 		netIdx = NewNetworkIndex()
@@ -210,6 +216,32 @@ func AllocsFit(node *Node, allocs []*Allocation, netIdx *NetworkIndex, checkDevi
 	return true, "", used, nil
 }
 
+// sharedComputeFits returns whether the cpu used by allocations, apart from the
+// exclusive cores they reserve, fits in the compute of the node outside of its
+// pool of exclusive cores. Exclusive cores are never shared, even when they
+// are not reserved by any task.
+func sharedComputeFits(node *Node, available, used *ComparableResources, reservedCores map[uint16]struct{}) bool {
+	if node.NodeResources == nil || node.NodeResources.Processors.Topology == nil {
+		return true
+	}
+
+	var exclusive, exclusiveUsed int64
+	for _, core := range node.NodeResources.Processors.Topology.Cores {
+		if core.Disable || !core.Exclusive {
+			continue
+		}
+		exclusive += int64(core.MHz())
+		if _, ok := reservedCores[uint16(core.ID)]; ok {
+			exclusiveUsed += int64(core.MHz())
+		}
+	}
+	if exclusive == 0 {
+		return true
+	}
+
+	return used.Flattened.Cpu.CpuShares-exclusiveUsed <= available.Flattened.Cpu.CpuShares-exclusive
+}
+
 func computeFreePercentage(node *Node, util *ComparableResources) (freePctCpu, freePctRam float64) {
 	reserved := node.ReservedResources.Comparable()
 	res := node.NodeResources.Comparable()
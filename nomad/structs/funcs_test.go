@@ -247,6 +247,50 @@ func TestAllocsFit(t *testing.T) {
 	must.Eq(t, 1024, used.Flattened.Memory.MemoryMB)
 }
 
+func TestAllocsFit_ExclusiveCores(t *testing.T) {
+	ci.Parallel(t)
+
+	// core 1 is in the pool of exclusive cores
+	n := node2k()
+	n.ReservedResources.Cpu.CpuShares = 0
+	n.NodeResources.Processors.Topology.Cores[1].Exclusive = true
+
+	cpuAlloc := func(shares int64, cores ...uint16) *Allocation {
+		return &Allocation{
+			AllocatedResources: &AllocatedResources{
+				Tasks: map[string]*AllocatedTaskResources{
+					"web": {
+						Cpu: AllocatedCpuResources{
+							CpuShares:     shares,
+							ReservedCores: cores,
+						},
+						Memory: AllocatedMemoryResources{
+							MemoryMB: 128,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// shared cpu fits outside of the exclusive cores
+	fit, dim, _, err := AllocsFit(n, []*Allocation{cpuAlloc(1000)}, nil, false)
+	must.NoError(t, err)
+	must.True(t, fit, must.Sprintf("failed for dimension %q", dim))
+
+	// the exclusive core is used by the task reserving it
+	fit, dim, _, err = AllocsFit(n, []*Allocation{cpuAlloc(1000), cpuAlloc(1000, 1)}, nil, false)
+	must.NoError(t, err)
+	must.True(t, fit, must.Sprintf("failed for dimension %q", dim))
+
+	// shared cpu does not fit on the exclusive core, even when it is not
+	// reserved
+	fit, dim, _, err = AllocsFit(n, []*Allocation{cpuAlloc(600), cpuAlloc(600)}, nil, false)
+	must.NoError(t, err)
+	must.False(t, fit)
+	must.Eq(t, "cpu", dim)
+}
+
 func TestAllocsFit_TerminalAlloc(t *testing.T) {
 	ci.Parallel(t)
 
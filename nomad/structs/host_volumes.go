@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+const (
+	// HostVolumePluginMkdirID is the ID of the built-in host volume plugin
+	// that creates host volumes as directories on the client.
+	HostVolumePluginMkdirID = "mkdir"
+
+	// hostVolumePluginAttrPrefix is the prefix of the node attributes
+	// fingerprinted for each host volume plugin on the client.
+	hostVolumePluginAttrPrefix = "plugins.host_volume."
+)
+
+// HostVolumePluginVersionAttr returns the node attribute with the version of
+// the host volume plugin, which is set on clients that can create host
+// volumes with the plugin.
+func HostVolumePluginVersionAttr(pluginID string) string {
+	return hostVolumePluginAttrPrefix + pluginID + ".version"
+}
+
+// HostVolumePluginCapacityAttr returns the node attribute with the capacity
+// in bytes that the host volume plugin reports as available for new volumes.
+func HostVolumePluginCapacityAttr(pluginID string) string {
+	return hostVolumePluginAttrPrefix + pluginID + ".capacity_bytes"
+}
+
+// HostVolumeCreateRequest is used to create a host volume on a client with
+// a host volume plugin.
+type HostVolumeCreateRequest struct {
+	QueryOptions // Client RPCs must use QueryOptions to set AllowStale=true
+
+	// NodeID is the node the volume is created on. If empty, the server
+	// picks a ready node in NodePool with the plugin and enough capacity.
+	NodeID string
+
+	// NodePool restricts the nodes the server picks from when NodeID is
+	// empty.
+	NodePool string
+
+	// Name is the name of the host volume on the node, which jobs use as the
+	// source of host volume requests.
+	Name string
+
+	// PluginID is the host volume plugin that provisions the volume.
+	PluginID string
+
+	// RequestedCapacityMinBytes and RequestedCapacityMaxBytes are the
+	// minimum and maximum size of the volume passed to the plugin.
+	RequestedCapacityMinBytes int64
+	RequestedCapacityMaxBytes int64
+
+	// Parameters are opaque values passed to the plugin.
+	Parameters map[string]string
+}
+
+func (r *HostVolumeCreateRequest) Validate() error {
+	var mErr *multierror.Error
+
+	if r.Name == "" {
+		mErr = multierror.Append(mErr, errors.New("missing volume name"))
+	} else if r.Name == "." || r.Name == ".." || filepath.Base(r.Name) != r.Name {
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid volume name %q", r.Name))
+	}
+	if r.PluginID == "" {
+		mErr = multierror.Append(mErr, errors.New("missing plugin ID"))
+	}
+	if r.RequestedCapacityMinBytes < 0 || r.RequestedCapacityMaxBytes < 0 {
+		mErr = multierror.Append(mErr, errors.New("capacity must not be negative"))
+	}
+	if r.RequestedCapacityMaxBytes > 0 &&
+		r.RequestedCapacityMaxBytes < r.RequestedCapacityMinBytes {
+		mErr = multierror.Append(mErr, errors.New("capacity_max must not be less than capacity_min"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// HostVolumeCreateResponse is the host volume created on the client.
+type HostVolumeCreateResponse struct {
+	NodeID        string
+	Name          string
+	Path          string
+	CapacityBytes int64
+}
+
+// HostVolumeDeleteRequest is used to delete a host volume created on a
+// client by a host volume plugin.
+type HostVolumeDeleteRequest struct {
+	QueryOptions // Client RPCs must use QueryOptions to set AllowStale=true
+
+	NodeID string
+	Name   string
+}
+
+func (r *HostVolumeDeleteRequest) Validate() error {
+	if r.Name == "" {
+		return errors.New("missing volume name")
+	}
+	return nil
+}
+
+type HostVolumeDeleteResponse struct{}
@@ -0,0 +1,32 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestHostVolumeCreateRequest_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	req := &HostVolumeCreateRequest{
+		Name:                      "data",
+		PluginID:                  "lvm",
+		RequestedCapacityMinBytes: 1024,
+		RequestedCapacityMaxBytes: 2048,
+	}
+	must.NoError(t, req.Validate())
+
+	err := (&HostVolumeCreateRequest{
+		Name:                      "../data",
+		RequestedCapacityMinBytes: 2048,
+		RequestedCapacityMaxBytes: 1024,
+	}).Validate()
+	must.ErrorContains(t, err, `invalid volume name "../data"`)
+	must.ErrorContains(t, err, "missing plugin ID")
+	must.ErrorContains(t, err, "capacity_max must not be less than capacity_min")
+}
@@ -0,0 +1,214 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/helper"
+)
+
+const (
+	// IngressProtocolTCP forwards the connections accepted by an ingress
+	// listener to the instances of a single service.
+	IngressProtocolTCP = "tcp"
+
+	// IngressProtocolHTTP routes the HTTP requests received by an ingress
+	// listener to services based on their host and path.
+	IngressProtocolHTTP = "http"
+)
+
+// Ingress configures the proxy the client runs for the allocations of a task
+// group. The proxy accepts external traffic on the ports of the group network
+// and routes it to the healthy instances of Nomad services.
+type Ingress struct {
+	// Listeners are the ports the proxy accepts traffic on.
+	Listeners []*IngressListener
+}
+
+// IngressListener accepts the traffic of a single port of the group network.
+type IngressListener struct {
+	// Port is the label of the group network port the listener binds to.
+	Port string
+
+	// Protocol is one of "tcp" or "http".
+	Protocol string
+
+	// Service is the Nomad service TCP connections are forwarded to, or the
+	// service HTTP requests not matching any route are sent to.
+	Service string
+
+	// Routes route the HTTP requests to services by host and path prefix.
+	Routes []*IngressRoute
+}
+
+// IngressRoute routes the HTTP requests matching its host and path prefix to
+// the instances of a Nomad service.
+type IngressRoute struct {
+	// Host is the host the requests must be sent to. An empty host matches
+	// any request.
+	Host string
+
+	// PathPrefix is the prefix the path of the requests must start with. An
+	// empty prefix matches any request.
+	PathPrefix string
+
+	// Service is the Nomad service matching requests are sent to.
+	Service string
+}
+
+func (i *Ingress) Copy() *Ingress {
+	if i == nil {
+		return nil
+	}
+	ni := new(Ingress)
+	ni.Listeners = helper.CopySlice(i.Listeners)
+	return ni
+}
+
+func (i *Ingress) Equal(o *Ingress) bool {
+	if i == nil || o == nil {
+		return i == o
+	}
+	return helper.ElementsEqual(i.Listeners, o.Listeners)
+}
+
+// Validate checks the ingress block of the task group. The listeners must
+// bind to distinct ports of the group network, which must run in host mode
+// for the proxy to be reachable.
+func (i *Ingress) Validate(tg *TaskGroup) error {
+	var mErr multierror.Error
+
+	if len(i.Listeners) == 0 {
+		_ = multierror.Append(&mErr, errors.New("Ingress must have at least one listener"))
+	}
+
+	ports := make(map[string]bool)
+	for _, network := range tg.Networks {
+		if network.Mode != "" && network.Mode != "host" {
+			_ = multierror.Append(&mErr, fmt.Errorf("Ingress requires host network mode, found %q", network.Mode))
+		}
+		for _, port := range network.ReservedPorts {
+			ports[port.Label] = true
+		}
+		for _, port := range network.DynamicPorts {
+			ports[port.Label] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	for idx, l := range i.Listeners {
+		if err := l.Validate(); err != nil {
+			_ = multierror.Append(&mErr, multierror.Prefix(err, fmt.Sprintf("Listener %d:", idx+1)))
+			continue
+		}
+		if !ports[l.Port] {
+			_ = multierror.Append(&mErr, fmt.Errorf("Listener %d references unknown port %q", idx+1, l.Port))
+		}
+		if seen[l.Port] {
+			_ = multierror.Append(&mErr, fmt.Errorf("Listener %d reuses port %q", idx+1, l.Port))
+		}
+		seen[l.Port] = true
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+func (l *IngressListener) Copy() *IngressListener {
+	if l == nil {
+		return nil
+	}
+	nl := new(IngressListener)
+	*nl = *l
+	nl.Routes = helper.CopySlice(l.Routes)
+	return nl
+}
+
+// DiffID fulfills the DiffableWithID interface.
+func (l *IngressListener) DiffID() string {
+	return l.Port
+}
+
+func (l *IngressListener) Equal(o *IngressListener) bool {
+	if l == nil || o == nil {
+		return l == o
+	}
+	switch {
+	case l.Port != o.Port:
+		return false
+	case l.Protocol != o.Protocol:
+		return false
+	case l.Service != o.Service:
+		return false
+	case !helper.ElementsEqual(l.Routes, o.Routes):
+		return false
+	}
+	return true
+}
+
+func (l *IngressListener) Validate() error {
+	var mErr multierror.Error
+
+	if l.Port == "" {
+		_ = multierror.Append(&mErr, errors.New("Missing port"))
+	}
+
+	switch l.Protocol {
+	case IngressProtocolTCP:
+		if l.Service == "" {
+			_ = multierror.Append(&mErr, errors.New("TCP listeners must set a service"))
+		}
+		if len(l.Routes) != 0 {
+			_ = multierror.Append(&mErr, errors.New("TCP listeners cannot have routes"))
+		}
+	case IngressProtocolHTTP:
+		if l.Service == "" && len(l.Routes) == 0 {
+			_ = multierror.Append(&mErr, errors.New("HTTP listeners must set a service or at least one route"))
+		}
+		for idx, r := range l.Routes {
+			if err := r.Validate(); err != nil {
+				_ = multierror.Append(&mErr, multierror.Prefix(err, fmt.Sprintf("Route %d:", idx+1)))
+			}
+		}
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Invalid protocol %q", l.Protocol))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+func (r *IngressRoute) Copy() *IngressRoute {
+	if r == nil {
+		return nil
+	}
+	nr := new(IngressRoute)
+	*nr = *r
+	return nr
+}
+
+func (r *IngressRoute) Equal(o *IngressRoute) bool {
+	if r == nil || o == nil {
+		return r == o
+	}
+	return *r == *o
+}
+
+func (r *IngressRoute) Validate() error {
+	var mErr multierror.Error
+
+	if r.Service == "" {
+		_ = multierror.Append(&mErr, errors.New("Missing service"))
+	}
+	if r.PathPrefix != "" && !strings.HasPrefix(r.PathPrefix, "/") {
+		_ = multierror.Append(&mErr, fmt.Errorf("Path prefix %q must start with a slash", r.PathPrefix))
+	}
+	if r.Host == "" && r.PathPrefix == "" {
+		_ = multierror.Append(&mErr, errors.New("Routes must set a host or a path prefix"))
+	}
+
+	return mErr.ErrorOrNil()
+}
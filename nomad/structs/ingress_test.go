@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestIngress_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	tg := &TaskGroup{
+		Networks: Networks{{
+			ReservedPorts: []Port{{Label: "http", Value: 80}},
+			DynamicPorts:  []Port{{Label: "db"}},
+		}},
+	}
+
+	cases := []struct {
+		name    string
+		ingress *Ingress
+		mode    string
+		expErr  string
+	}{
+		{
+			name: "valid",
+			ingress: &Ingress{Listeners: []*IngressListener{
+				{Port: "db", Protocol: IngressProtocolTCP, Service: "postgres"},
+				{Port: "http", Protocol: IngressProtocolHTTP, Routes: []*IngressRoute{
+					{Host: "example.com", Service: "web"},
+					{PathPrefix: "/api", Service: "api"},
+				}},
+			}},
+		},
+		{
+			name:    "no listeners",
+			ingress: &Ingress{},
+			expErr:  "at least one listener",
+		},
+		{
+			name: "bridge network",
+			ingress: &Ingress{Listeners: []*IngressListener{
+				{Port: "db", Protocol: IngressProtocolTCP, Service: "postgres"},
+			}},
+			mode:   "bridge",
+			expErr: "requires host network mode",
+		},
+		{
+			name: "unknown port",
+			ingress: &Ingress{Listeners: []*IngressListener{
+				{Port: "https", Protocol: IngressProtocolTCP, Service: "web"},
+			}},
+			expErr: `unknown port "https"`,
+		},
+		{
+			name: "reused port",
+			ingress: &Ingress{Listeners: []*IngressListener{
+				{Port: "db", Protocol: IngressProtocolTCP, Service: "postgres"},
+				{Port: "db", Protocol: IngressProtocolTCP, Service: "mysql"},
+			}},
+			expErr: `reuses port "db"`,
+		},
+		{
+			name: "tcp routes",
+			ingress: &Ingress{Listeners: []*IngressListener{
+				{Port: "db", Protocol: IngressProtocolTCP, Service: "postgres", Routes: []*IngressRoute{
+					{PathPrefix: "/", Service: "web"},
+				}},
+			}},
+			expErr: "TCP listeners cannot have routes",
+		},
+		{
+			name: "invalid protocol",
+			ingress: &Ingress{Listeners: []*IngressListener{
+				{Port: "db", Protocol: "udp", Service: "dns"},
+			}},
+			expErr: `Invalid protocol "udp"`,
+		},
+		{
+			name: "invalid route",
+			ingress: &Ingress{Listeners: []*IngressListener{
+				{Port: "http", Protocol: IngressProtocolHTTP, Routes: []*IngressRoute{
+					{PathPrefix: "api", Service: "api"},
+				}},
+			}},
+			expErr: "must start with a slash",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tg := tg.Copy()
+			tg.Networks[0].Mode = tc.mode
+
+			err := tc.ingress.Validate(tg)
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
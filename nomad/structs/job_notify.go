@@ -0,0 +1,255 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// JobNotifyComplete is the outcome of batch jobs whose allocations all
+	// completed successfully.
+	JobNotifyComplete = "complete"
+
+	// JobNotifyFailed is the outcome of batch jobs with failed or lost
+	// allocations that were not replaced.
+	JobNotifyFailed = "failed"
+
+	// JobNotifySignatureHeader is the header of webhook notifications that
+	// holds the HMAC-SHA256 signature of the body, as "sha256=<hex>".
+	JobNotifySignatureHeader = "X-Nomad-Signature"
+
+	// JobNotifyRedacted replaces the secret and header values of notify
+	// blocks in API responses and job diffs, as they may hold credentials.
+	JobNotifyRedacted = "[REDACTED]"
+)
+
+// JobNotify configures the notification the leader sends once a batch or
+// sysbatch job completes. Notifications are published to the event stream as
+// JobNotification events of the Job topic, which event sinks deliver, and are
+// POSTed to the webhook if one is set.
+type JobNotify struct {
+	// Webhook is the URL the notification is POSTed to.
+	Webhook string
+
+	// Secret is the key used to sign the body of webhook notifications with
+	// HMAC-SHA256.
+	Secret string
+
+	// Headers are the extra headers of webhook notifications.
+	Headers map[string]string
+
+	// On is the outcomes of the job that are notified. It defaults to all
+	// outcomes.
+	On []string
+}
+
+func (n *JobNotify) Copy() *JobNotify {
+	if n == nil {
+		return nil
+	}
+	nn := new(JobNotify)
+	*nn = *n
+	nn.Headers = maps.Clone(n.Headers)
+	nn.On = slices.Clone(n.On)
+	return nn
+}
+
+// Redacted returns a copy of the notify block with its secret and header
+// values redacted.
+func (n *JobNotify) Redacted() *JobNotify {
+	if n == nil {
+		return nil
+	}
+	nn := n.Copy()
+	if nn.Secret != "" {
+		nn.Secret = JobNotifyRedacted
+	}
+	for k := range nn.Headers {
+		nn.Headers[k] = JobNotifyRedacted
+	}
+	return nn
+}
+
+// RestoreRedacted sets the secret and header values that were submitted
+// redacted, for example after editing the output of job inspect, back to
+// their value in the notify block of the existing job.
+func (n *JobNotify) RestoreRedacted(existing *JobNotify) {
+	if n == nil || existing == nil {
+		return
+	}
+	if n.Secret == JobNotifyRedacted {
+		n.Secret = existing.Secret
+	}
+	for k, v := range n.Headers {
+		if v == JobNotifyRedacted {
+			if ev, ok := existing.Headers[k]; ok {
+				n.Headers[k] = ev
+			}
+		}
+	}
+}
+
+func (n *JobNotify) Canonicalize() {
+	if len(n.On) == 0 {
+		n.On = []string{JobNotifyComplete, JobNotifyFailed}
+	}
+	if len(n.Headers) == 0 {
+		n.Headers = nil
+	}
+}
+
+func (n *JobNotify) Validate() error {
+	var mErr multierror.Error
+
+	if n.Webhook != "" {
+		if u, err := url.Parse(n.Webhook); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("Invalid webhook URL: %v", err))
+		} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			_ = multierror.Append(&mErr, fmt.Errorf("Webhook must be an http or https URL: %q", n.Webhook))
+		}
+	} else if n.Secret != "" || len(n.Headers) > 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Secret and headers require a webhook"))
+	}
+
+	for _, on := range n.On {
+		switch on {
+		case JobNotifyComplete, JobNotifyFailed:
+		default:
+			_ = multierror.Append(&mErr, fmt.Errorf("Unknown notify outcome: %q", on))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// ValidateWebhookHost returns an error if the host of the webhook isn't one of
+// the allowed hosts. Allowed hosts starting with "*." allow all the subdomains
+// of the domain.
+func (n *JobNotify) ValidateWebhookHost(allowed []string) error {
+	u, err := url.Parse(n.Webhook)
+	if err != nil {
+		return fmt.Errorf("Invalid webhook URL: %v", err)
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if a == host || (strings.HasPrefix(a, "*.") && strings.HasSuffix(host, a[1:])) {
+			return nil
+		}
+	}
+	return fmt.Errorf("Webhook host %q is not allowed by the server configuration", host)
+}
+
+// Notifies returns whether the outcome of the job is notified.
+func (n *JobNotify) Notifies(status string) bool {
+	return n != nil && slices.Contains(n.On, status)
+}
+
+// JobNotification is the notification of a completed batch job.
+type JobNotification struct {
+	Namespace string
+	JobID     string
+	ParentID  string
+	Version   uint64
+
+	// Status is the outcome of the job, either complete or failed.
+	Status string
+
+	// StartTime and EndTime are the creation time of the first allocation of
+	// the job and the last update time of its allocations, in nanoseconds.
+	StartTime int64
+	EndTime   int64
+
+	// Stats counts the allocations of the job version by client status, and
+	// TaskGroups counts them for each task group.
+	Stats      JobNotificationStats
+	TaskGroups map[string]*JobNotificationStats
+}
+
+// JobNotificationStats counts the allocations of a completed job.
+type JobNotificationStats struct {
+	Allocations int
+	Complete    int
+	Failed      int
+	Lost        int
+
+	// Rescheduled is the number of failed or lost allocations that were
+	// replaced.
+	Rescheduled int
+}
+
+func (s *JobNotificationStats) add(alloc *Allocation) {
+	s.Allocations++
+	switch alloc.ClientStatus {
+	case AllocClientStatusComplete:
+		s.Complete++
+	case AllocClientStatusFailed:
+		s.Failed++
+	case AllocClientStatusLost:
+		s.Lost++
+	}
+	if alloc.NextAllocation != "" && alloc.ClientStatus != AllocClientStatusComplete {
+		s.Rescheduled++
+	}
+}
+
+// NewJobNotification returns the notification of the completed job from the
+// allocations of the job. Only the allocations of the current version of the
+// job are counted, and the job failed if any of them failed or was lost
+// without being replaced.
+func NewJobNotification(job *Job, allocs []*Allocation) *JobNotification {
+	n := &JobNotification{
+		Namespace:  job.Namespace,
+		JobID:      job.ID,
+		ParentID:   job.ParentID,
+		Version:    job.Version,
+		Status:     JobNotifyComplete,
+		TaskGroups: make(map[string]*JobNotificationStats, len(job.TaskGroups)),
+	}
+	for _, tg := range job.TaskGroups {
+		n.TaskGroups[tg.Name] = new(JobNotificationStats)
+	}
+
+	for _, alloc := range allocs {
+		if alloc.Job != nil && (alloc.Job.CreateIndex != job.CreateIndex || alloc.Job.Version != job.Version) {
+			continue
+		}
+
+		n.Stats.add(alloc)
+		if stats, ok := n.TaskGroups[alloc.TaskGroup]; ok {
+			stats.add(alloc)
+		}
+
+		if alloc.NextAllocation == "" &&
+			(alloc.ClientStatus == AllocClientStatusFailed || alloc.ClientStatus == AllocClientStatusLost) {
+			n.Status = JobNotifyFailed
+		}
+		if n.StartTime == 0 || alloc.CreateTime < n.StartTime {
+			n.StartTime = alloc.CreateTime
+		}
+		n.EndTime = max(n.EndTime, alloc.ModifyTime)
+	}
+
+	return n
+}
+
+// JobNotifyRequest is used by the leader to record that the notification of
+// a completed job was sent.
+type JobNotifyRequest struct {
+	Namespace string
+	JobID     string
+
+	// ModifyIndex is the modify index of the job the notification was sent
+	// for. The notification is still pending if the job changed since.
+	ModifyIndex uint64
+
+	WriteRequest
+}
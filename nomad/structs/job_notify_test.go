@@ -0,0 +1,196 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-msgpack/codec"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestJob_Validate_Notify(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name    string
+		jobType string
+		notify  *JobNotify
+		expErr  string
+	}{
+		{
+			name:    "webhook",
+			jobType: JobTypeBatch,
+			notify: &JobNotify{
+				Webhook: "https://example.com/hook",
+				Secret:  "secret",
+				On:      []string{JobNotifyFailed},
+			},
+		},
+		{
+			name:    "event stream only",
+			jobType: JobTypeSysBatch,
+			notify:  &JobNotify{},
+		},
+		{
+			name:    "service job",
+			jobType: JobTypeService,
+			notify:  &JobNotify{},
+			expErr:  "Notify can only be used with",
+		},
+		{
+			name:    "invalid webhook",
+			jobType: JobTypeBatch,
+			notify:  &JobNotify{Webhook: "example.com/hook"},
+			expErr:  "Webhook must be an http or https URL",
+		},
+		{
+			name:    "secret without webhook",
+			jobType: JobTypeBatch,
+			notify:  &JobNotify{Secret: "secret"},
+			expErr:  "Secret and headers require a webhook",
+		},
+		{
+			name:    "unknown outcome",
+			jobType: JobTypeBatch,
+			notify:  &JobNotify{On: []string{"lost"}},
+			expErr:  "Unknown notify outcome",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			job := MockJob()
+			job.Type = tc.jobType
+			job.TaskGroups[0].Migrate = nil
+			job.Notify = tc.notify
+			job.Canonicalize()
+
+			err := job.Validate()
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestNewJobNotification(t *testing.T) {
+	ci.Parallel(t)
+
+	job := MockJob()
+	job.Type = JobTypeBatch
+	job.Version = 2
+	job.Notify = &JobNotify{}
+	job.Canonicalize()
+	tg := job.TaskGroups[0].Name
+
+	newAlloc := func(status string, created, modified int64) *Allocation {
+		alloc := MockAlloc()
+		alloc.Job = job
+		alloc.TaskGroup = tg
+		alloc.ClientStatus = status
+		alloc.CreateTime = created
+		alloc.ModifyTime = modified
+		return alloc
+	}
+
+	complete := newAlloc(AllocClientStatusComplete, 10, 50)
+	failed := newAlloc(AllocClientStatusFailed, 20, 30)
+	replacement := newAlloc(AllocClientStatusComplete, 35, 60)
+	failed.NextAllocation = replacement.ID
+
+	// Allocations of previous versions of the job are ignored
+	old := newAlloc(AllocClientStatusFailed, 1, 100)
+	old.Job = job.Copy()
+	old.Job.Version = 1
+
+	n := NewJobNotification(job, []*Allocation{complete, failed, replacement, old})
+	must.Eq(t, JobNotifyComplete, n.Status)
+	must.Eq(t, job.ID, n.JobID)
+	must.Eq(t, job.Version, n.Version)
+	must.Eq(t, 10, n.StartTime)
+	must.Eq(t, 60, n.EndTime)
+
+	expStats := JobNotificationStats{Allocations: 3, Complete: 2, Failed: 1, Rescheduled: 1}
+	must.Eq(t, expStats, n.Stats)
+	must.Eq(t, expStats, *n.TaskGroups[tg])
+	must.True(t, job.Notify.Notifies(n.Status))
+
+	// The job failed if a failed allocation wasn't replaced
+	lost := newAlloc(AllocClientStatusLost, 40, 70)
+	n = NewJobNotification(job, []*Allocation{complete, lost})
+	must.Eq(t, JobNotifyFailed, n.Status)
+	must.Eq(t, 1, n.Stats.Lost)
+	must.Eq(t, 70, n.EndTime)
+
+	job.Notify.On = []string{JobNotifyComplete}
+	must.False(t, job.Notify.Notifies(n.Status))
+}
+
+func TestJobNotify_Redacted(t *testing.T) {
+	ci.Parallel(t)
+
+	notify := &JobNotify{
+		Webhook: "https://example.com/hook",
+		Secret:  "secret",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+		On:      []string{JobNotifyFailed},
+	}
+
+	redacted := notify.Redacted()
+	must.Eq(t, JobNotifyRedacted, redacted.Secret)
+	must.Eq(t, map[string]string{"Authorization": JobNotifyRedacted}, redacted.Headers)
+	must.Eq(t, "secret", notify.Secret)
+
+	// The notify block of jobs encoded for the API is redacted
+	job := MockJob()
+	job.Notify = notify
+	var buf []byte
+	must.NoError(t, codec.NewEncoderBytes(&buf, JsonHandleWithExtensions).Encode(job))
+	must.StrNotContains(t, string(buf), "secret")
+	must.StrNotContains(t, string(buf), "Bearer token")
+	must.StrContains(t, string(buf), "https://example.com/hook")
+
+	// Redacted values submitted back are restored from the existing job
+	redacted.Headers["X-Team"] = "data"
+	redacted.RestoreRedacted(notify)
+	must.Eq(t, "secret", redacted.Secret)
+	must.Eq(t, map[string]string{
+		"Authorization": "Bearer token",
+		"X-Team":        "data",
+	}, redacted.Headers)
+}
+
+func TestJobNotify_ValidateWebhookHost(t *testing.T) {
+	ci.Parallel(t)
+
+	allowed := []string{"hooks.example.com", "*.ci.example.com"}
+	testCases := []struct {
+		webhook string
+		expErr  bool
+	}{
+		{webhook: "https://hooks.example.com/nomad"},
+		{webhook: "https://HOOKS.example.com:8443/nomad"},
+		{webhook: "https://build.ci.example.com/nomad"},
+		{webhook: "https://ci.example.com/nomad", expErr: true},
+		{webhook: "https://evilci.example.com/nomad", expErr: true},
+		{webhook: "http://169.254.169.254/latest/meta-data", expErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.webhook, func(t *testing.T) {
+			err := (&JobNotify{Webhook: tc.webhook}).ValidateWebhookHost(allowed)
+			if tc.expErr {
+				must.ErrorContains(t, err, "is not allowed")
+			} else {
+				must.NoError(t, err)
+			}
+		})
+	}
+
+	must.Error(t, (&JobNotify{Webhook: "https://hooks.example.com"}).ValidateWebhookHost(nil))
+}
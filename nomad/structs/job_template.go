@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// maxJobTemplateDescriptionLength is the maximum length allowed for a job
+	// template description.
+	maxJobTemplateDescriptionLength = 256
+
+	// maxJobTemplateSourceSize is the maximum size allowed for the source of
+	// a job template.
+	maxJobTemplateSourceSize = 1024 * 1024
+)
+
+var (
+	// validJobTemplateName is the rule used to validate a job template name.
+	validJobTemplateName = regexp.MustCompile("^[a-zA-Z0-9-_]{1,128}$")
+)
+
+// JobTemplate is a parameterized job registered by operators. Its source is an
+// HCL2 jobspec whose variable blocks declare the parameters of the template,
+// with their types, defaults, and validation rules. Users run jobs from a
+// template by only providing values for its variables.
+type JobTemplate struct {
+	// Name is the job template name. It must be unique.
+	Name string
+
+	// Description is the human-friendly description of the job template.
+	Description string
+
+	// Source is the HCL2 jobspec of the template.
+	Source string
+
+	// Raft indexes.
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// GetID implements the IDGetter interface required for pagination.
+func (t *JobTemplate) GetID() string {
+	return t.Name
+}
+
+// Validate returns an error if the job template is invalid.
+func (t *JobTemplate) Validate() error {
+	var mErr *multierror.Error
+
+	if !validJobTemplateName.MatchString(t.Name) {
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid name %q, must match regex %s", t.Name, validJobTemplateName))
+	}
+	if len(t.Description) > maxJobTemplateDescriptionLength {
+		mErr = multierror.Append(mErr, fmt.Errorf("description longer than %d", maxJobTemplateDescriptionLength))
+	}
+	if t.Source == "" {
+		mErr = multierror.Append(mErr, errors.New("source is empty"))
+	} else if len(t.Source) > maxJobTemplateSourceSize {
+		mErr = multierror.Append(mErr, fmt.Errorf("source larger than %d bytes", maxJobTemplateSourceSize))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Copy returns a copy of the job template.
+func (t *JobTemplate) Copy() *JobTemplate {
+	if t == nil {
+		return nil
+	}
+
+	nt := new(JobTemplate)
+	*nt = *t
+	return nt
+}
+
+// Stub returns a summary of the job template without its source.
+func (t *JobTemplate) Stub() *JobTemplateStub {
+	return &JobTemplateStub{
+		Name:        t.Name,
+		Description: t.Description,
+		CreateIndex: t.CreateIndex,
+		ModifyIndex: t.ModifyIndex,
+	}
+}
+
+// JobTemplateStub is the summary of a job template returned when listing
+// templates.
+type JobTemplateStub struct {
+	Name        string
+	Description string
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// JobTemplateListRequest is used to list job templates.
+type JobTemplateListRequest struct {
+	QueryOptions
+}
+
+// JobTemplateListResponse is the response to a job templates list request.
+type JobTemplateListResponse struct {
+	Templates []*JobTemplateStub
+	QueryMeta
+}
+
+// JobTemplateSpecificRequest is used to make a request for a specific job
+// template.
+type JobTemplateSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleJobTemplateResponse is the response to a specific job template
+// request.
+type SingleJobTemplateResponse struct {
+	Template *JobTemplate
+	QueryMeta
+}
+
+// JobTemplateUpsertRequest is used to make a request to insert or update job
+// templates.
+type JobTemplateUpsertRequest struct {
+	Templates []*JobTemplate
+	WriteRequest
+}
+
+// JobTemplateDeleteRequest is used to make a request to delete job templates.
+type JobTemplateDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
@@ -9,6 +9,7 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"fmt"
+	"maps"
 	"net/url"
 	"time"
 
@@ -198,7 +199,77 @@ type KeyEncryptionKeyWrapper struct {
 	Meta                       *RootKeyMeta
 	EncryptedDataEncryptionKey []byte `json:"DEK"`
 	EncryptedRSAKey            []byte `json:"RSAKey"`
-	KeyEncryptionKey           []byte `json:"KEK"`
+
+	// KeyEncryptionKey is the cleartext key-wrapping key. It is only set for
+	// keys wrapped by the aead provider. Otherwise the KEK is wrapped by the
+	// external KMS provider identified by ProviderID.
+	KeyEncryptionKey        []byte `json:"KEK,omitempty"`
+	WrappedKeyEncryptionKey []byte `json:"WrappedKEK,omitempty"`
+	ProviderID              string `json:",omitempty"`
+}
+
+// KEKProvider is the name of a provider for the key encryption keys (KEK)
+// that wrap the root keys in the on-disk keystore.
+type KEKProvider string
+
+const (
+	// KEKProviderAEAD stores the KEK in cleartext alongside the root key
+	KEKProviderAEAD KEKProvider = "aead"
+
+	// KEKProviderAWSKMS wraps the KEK with AWS KMS
+	KEKProviderAWSKMS KEKProvider = "awskms"
+
+	// KEKProviderGCPCloudKMS wraps the KEK with GCP Cloud KMS
+	KEKProviderGCPCloudKMS KEKProvider = "gcpckms"
+
+	// KEKProviderVaultTransit wraps the KEK with the Vault transit secrets
+	// engine
+	KEKProviderVaultTransit KEKProvider = "transit"
+)
+
+// KEKProviderConfig is the configuration of a `keyring` block, which
+// configures a provider for the key encryption keys of the keystore.
+type KEKProviderConfig struct {
+	Provider KEKProvider
+
+	// Name distinguishes multiple configurations of the same provider, for
+	// example while migrating between KMS keys
+	Name string
+
+	// Active providers wrap new keys. Keys wrapped by inactive providers
+	// are unwrapped on startup and wrapped again by the active provider.
+	Active bool
+
+	// Config is the provider specific configuration
+	Config map[string]string
+}
+
+// ID returns the identifier of the provider configuration stored with the
+// keys it wraps.
+func (c *KEKProviderConfig) ID() string {
+	if c.Name == "" {
+		return string(c.Provider)
+	}
+	return string(c.Provider) + "." + c.Name
+}
+
+func (c *KEKProviderConfig) Copy() *KEKProviderConfig {
+	if c == nil {
+		return nil
+	}
+	nc := *c
+	nc.Config = maps.Clone(c.Config)
+	return &nc
+}
+
+// Validate returns an error if the provider is unknown.
+func (c *KEKProviderConfig) Validate() error {
+	switch c.Provider {
+	case KEKProviderAEAD, KEKProviderAWSKMS, KEKProviderGCPCloudKMS, KEKProviderVaultTransit:
+		return nil
+	default:
+		return fmt.Errorf("unknown keyring provider %q", c.Provider)
+	}
 }
 
 // EncryptionAlgorithm chooses which algorithm is used for
@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// MaintenanceWindowStatusPending is the status of a maintenance window
+	// that hasn't started yet.
+	MaintenanceWindowStatusPending = "pending"
+
+	// MaintenanceWindowStatusActive is the status of a maintenance window
+	// whose nodes are drained.
+	MaintenanceWindowStatusActive = "active"
+
+	// MaintenanceWindowStatusComplete is the status of a maintenance window
+	// that ended and whose nodes were marked eligible again.
+	MaintenanceWindowStatusComplete = "complete"
+
+	// MaintenanceWindowDrainMetaKey is the key of the drain metadata set on
+	// the nodes drained by a maintenance window, whose value is the ID of the
+	// window. It is used to find the nodes to mark eligible again when the
+	// window ends.
+	MaintenanceWindowDrainMetaKey = "maintenance_window"
+
+	// maxMaintenanceWindowDescriptionLength is the maximum length allowed for
+	// a maintenance window description.
+	maxMaintenanceWindowDescriptionLength = 256
+)
+
+// MaintenanceWindow is a period of time during which a set of nodes is
+// drained. The leader drains the nodes when the window starts and marks them
+// eligible for scheduling again when the window ends.
+type MaintenanceWindow struct {
+	// ID is the UUID of the maintenance window.
+	ID string
+
+	// Description is the human-friendly description of the window.
+	Description string
+
+	// NodeIDs and NodeClasses select the nodes drained during the window.
+	// A node is drained if its ID or its class is listed.
+	NodeIDs     []string
+	NodeClasses []string
+
+	// Start and End are the times the window starts and ends.
+	Start time.Time
+	End   time.Time
+
+	// Deadline is the deadline of the drain of the nodes, after which their
+	// remaining allocations are stopped. Zero means no deadline.
+	Deadline time.Duration
+
+	// IgnoreSystemJobs keeps the allocations of system jobs running on the
+	// drained nodes.
+	IgnoreSystemJobs bool
+
+	// Status is the status of the window, which is set by the leader.
+	Status string
+
+	// Raft indexes.
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Validate returns an error if the maintenance window is invalid.
+func (w *MaintenanceWindow) Validate() error {
+	var mErr *multierror.Error
+
+	if len(w.Description) > maxMaintenanceWindowDescriptionLength {
+		mErr = multierror.Append(mErr, fmt.Errorf("description longer than %d", maxMaintenanceWindowDescriptionLength))
+	}
+	if len(w.NodeIDs) == 0 && len(w.NodeClasses) == 0 {
+		mErr = multierror.Append(mErr, errors.New("must select at least one node ID or node class"))
+	}
+	for _, id := range w.NodeIDs {
+		if id == "" {
+			mErr = multierror.Append(mErr, errors.New("node ID is empty"))
+		}
+	}
+	if w.Start.IsZero() {
+		mErr = multierror.Append(mErr, errors.New("start time is missing"))
+	}
+	if w.End.IsZero() {
+		mErr = multierror.Append(mErr, errors.New("end time is missing"))
+	} else if !w.End.After(w.Start) {
+		mErr = multierror.Append(mErr, errors.New("end time must be after the start time"))
+	}
+	if w.Deadline < 0 {
+		mErr = multierror.Append(mErr, errors.New("drain deadline must not be negative"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Matches returns true if the node is drained during the window.
+func (w *MaintenanceWindow) Matches(node *Node) bool {
+	return slices.Contains(w.NodeIDs, node.ID) ||
+		(node.NodeClass != "" && slices.Contains(w.NodeClasses, node.NodeClass))
+}
+
+// Drained returns true if the node was drained by the window.
+func (w *MaintenanceWindow) Drained(node *Node) bool {
+	return node.LastDrain != nil && node.LastDrain.Meta[MaintenanceWindowDrainMetaKey] == w.ID
+}
+
+// DrainStrategy returns the drain strategy applied to the nodes of the
+// window.
+func (w *MaintenanceWindow) DrainStrategy() *DrainStrategy {
+	return &DrainStrategy{
+		DrainSpec: DrainSpec{
+			Deadline:         w.Deadline,
+			IgnoreSystemJobs: w.IgnoreSystemJobs,
+		},
+	}
+}
+
+// Copy returns a copy of the maintenance window.
+func (w *MaintenanceWindow) Copy() *MaintenanceWindow {
+	if w == nil {
+		return nil
+	}
+
+	nw := new(MaintenanceWindow)
+	*nw = *w
+	nw.NodeIDs = slices.Clone(w.NodeIDs)
+	nw.NodeClasses = slices.Clone(w.NodeClasses)
+	return nw
+}
+
+// MaintenanceWindowListRequest is used to list the maintenance windows.
+type MaintenanceWindowListRequest struct {
+	QueryOptions
+}
+
+// MaintenanceWindowListResponse is the response to a maintenance windows
+// list request. The windows are sorted by start time.
+type MaintenanceWindowListResponse struct {
+	Windows []*MaintenanceWindow
+	QueryMeta
+}
+
+// MaintenanceWindowUpsertRequest is used to make a request to insert or
+// update maintenance windows.
+type MaintenanceWindowUpsertRequest struct {
+	Windows []*MaintenanceWindow
+	WriteRequest
+}
+
+// MaintenanceWindowUpsertResponse is the response to a maintenance window
+// upsert request, which holds the windows as they were written.
+type MaintenanceWindowUpsertResponse struct {
+	Windows []*MaintenanceWindow
+	WriteMeta
+}
+
+// MaintenanceWindowDeleteRequest is used to make a request to delete
+// maintenance windows.
+type MaintenanceWindowDeleteRequest struct {
+	IDs []string
+	WriteRequest
+}
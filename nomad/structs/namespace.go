@@ -26,7 +26,7 @@ type NamespaceVaultConfiguration struct {
 }
 
 // NamespaceConsulConfiguration stores configuration about permissions to Consul
-// clusters for a namespace, for use with Nomad Enterprise.
+// clusters for a namespace.
 type NamespaceConsulConfiguration struct {
 	// Default is the Consul cluster used by jobs in this namespace that don't
 	// specify a cluster of their own.
@@ -69,6 +69,10 @@ type NetworkIndex struct {
 
 	MinDynamicPort int // The smallest dynamic port generated
 	MaxDynamicPort int // The largest dynamic port generated
+
+	// reservedPorts are the client's reserved ports, which are reserved on
+	// every address of the node
+	reservedPorts []uint
 }
 
 // NewNetworkIndex is used to construct a new network index
@@ -274,6 +278,8 @@ func (idx *NetworkIndex) SetNode(node *Node) error {
 		}
 	}
 
+	idx.reservedPorts = globalResPorts
+
 	// Filter task networks down to those with a device. For example
 	// taskNetworks may contain a "bridge" interface which has no device
 	// set and cannot be used to fulfill asks.
@@ -559,13 +565,14 @@ func (idx *NetworkIndex) AssignPorts(ask *NetworkResource) (AllocatedPorts, erro
 			// lower memory usage.
 			var dynPorts []int
 			// TODO: its more efficient to find multiple dynamic ports at once
+			minDynamicPort, maxDynamicPort := idx.dynamicPortRange(addr)
 			dynPorts, addrErr = getDynamicPortsStochastic(
-				used, portsInOffer, idx.MinDynamicPort, idx.MaxDynamicPort,
+				used, portsInOffer, minDynamicPort, maxDynamicPort,
 				reservedIdx[port.HostNetwork], 1)
 			if addrErr != nil {
 				// Fall back to the precise method if the random sampling failed.
 				dynPorts, addrErr = getDynamicPortsPrecise(used, portsInOffer,
-					idx.MinDynamicPort, idx.MaxDynamicPort,
+					minDynamicPort, maxDynamicPort,
 					reservedIdx[port.HostNetwork], 1)
 				if addrErr != nil {
 					continue
@@ -598,6 +605,33 @@ func (idx *NetworkIndex) AssignPorts(ask *NetworkResource) (AllocatedPorts, erro
 	return offer, nil
 }
 
+// SetInterfaceAddresses replaces the addresses of the default host network
+// with the addresses of the network interface selected by a group network, so
+// ports without a host network are assigned on that interface. The client's
+// reserved ports are reserved on these addresses too.
+func (idx *NetworkIndex) SetInterfaceAddresses(addrs []NodeNetworkAddress) {
+	idx.HostNetworks["default"] = addrs
+	for _, addr := range addrs {
+		used := idx.getUsedPortsFor(addr.Address)
+		for _, p := range idx.reservedPorts {
+			used.Set(p)
+		}
+	}
+}
+
+// dynamicPortRange returns the dynamic port range of an address, which is the
+// range of its host network if set or else the node's range.
+func (idx *NetworkIndex) dynamicPortRange(addr NodeNetworkAddress) (int, int) {
+	minDynamicPort, maxDynamicPort := idx.MinDynamicPort, idx.MaxDynamicPort
+	if addr.MinDynamicPort > 0 {
+		minDynamicPort = addr.MinDynamicPort
+	}
+	if addr.MaxDynamicPort > 0 {
+		maxDynamicPort = addr.MaxDynamicPort
+	}
+	return minDynamicPort, maxDynamicPort
+}
+
 // AssignTaskNetwork is used to offer network resources given a
 // task.resources.network ask.  If the ask cannot be satisfied, returns nil
 //
@@ -812,11 +846,22 @@ func AllocatedPortsToNetworkResouce(ask *NetworkResource, ports AllocatedPorts,
 	return out
 }
 
+// NodeNetworkInterfaceAttribute returns the name of the node attribute holding
+// a property of a host network interface, as fingerprinted by the client.
+func NodeNetworkInterfaceAttribute(iface, property string) string {
+	return fmt.Sprintf("network.interface.%s.%s", iface, property)
+}
+
 type ClientHostNetworkConfig struct {
 	Name          string `hcl:",key"`
 	CIDR          string `hcl:"cidr"`
 	Interface     string `hcl:"interface"`
 	ReservedPorts string `hcl:"reserved_ports"`
+
+	// MinDynamicPort and MaxDynamicPort override the client's dynamic port
+	// range for the host network when set.
+	MinDynamicPort int `hcl:"min_dynamic_port"`
+	MaxDynamicPort int `hcl:"max_dynamic_port"`
 }
 
 func (p *ClientHostNetworkConfig) Copy() *ClientHostNetworkConfig {
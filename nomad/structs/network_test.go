@@ -502,6 +502,72 @@ func TestNetworkIndex_AssignPortss_SmallRange(t *testing.T) {
 
 }
 
+// TestNetworkIndex_AssignPorts_HostNetworkRange asserts dynamic ports are
+// assigned from the dynamic port range of the host network when set
+func TestNetworkIndex_AssignPorts_HostNetworkRange(t *testing.T) {
+	ci.Parallel(t)
+
+	n := &Node{
+		NodeResources: &NodeResources{
+			NodeNetworks: []*NodeNetworkResource{
+				{
+					Mode:   "host",
+					Device: "eth0",
+					Speed:  1000,
+					Addresses: []NodeNetworkAddress{
+						{
+							Alias:   "default",
+							Address: "192.168.0.100",
+							Family:  NodeNetworkAF_IPv4,
+						},
+						{
+							Alias:          "public",
+							Address:        "203.0.113.10",
+							Family:         NodeNetworkAF_IPv4,
+							MinDynamicPort: 40000,
+							MaxDynamicPort: 40001,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	idx := NewNetworkIndex()
+	idx.SetNode(n)
+
+	ask := &NetworkResource{
+		DynamicPorts: []Port{
+			{"http", 0, 80, "default"},
+			{"public1", 0, 80, "public"},
+			{"public2", 0, 80, "public"},
+		},
+	}
+	offer, err := idx.AssignPorts(ask)
+	must.NoError(t, err)
+
+	httpPortMapping, ok := offer.Get("http")
+	must.True(t, ok)
+	must.Between(t, idx.MinDynamicPort, httpPortMapping.Value, idx.MaxDynamicPort)
+
+	for _, label := range []string{"public1", "public2"} {
+		mapping, ok := offer.Get(label)
+		must.True(t, ok)
+		must.Between(t, 40000, mapping.Value, 40001)
+	}
+
+	// The range of the host network is exhausted
+	ask = &NetworkResource{
+		DynamicPorts: []Port{
+			{"public1", 0, 80, "public"},
+			{"public2", 0, 80, "public"},
+			{"public3", 0, 80, "public"},
+		},
+	}
+	_, err = idx.AssignPorts(ask)
+	must.EqError(t, err, "dynamic port selection failed")
+}
+
 func TestNetworkIndex_AssignTaskNetwork(t *testing.T) {
 	ci.Parallel(t)
 	idx := NewNetworkIndex()
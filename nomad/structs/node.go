@@ -6,6 +6,8 @@ package structs
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -369,6 +371,11 @@ type NodeMetaApplyRequest struct {
 	// Meta is the new Node metadata being applied and differs slightly
 	// from Node.Meta as nil values are used to unset Node.Meta keys.
 	Meta map[string]*string
+
+	// Expected optionally makes the update a compare-and-set operation. The
+	// update is only applied if every key has the expected value in the
+	// merged Node.Meta. A nil value expects the key to be unset.
+	Expected map[string]*string
 }
 
 func (n *NodeMetaApplyRequest) Validate() error {
@@ -389,7 +396,36 @@ func (n *NodeMetaApplyRequest) Validate() error {
 			}
 		}
 	}
+	for k := range n.Expected {
+		if k == "" {
+			return fmt.Errorf("expected metadata keys must not be empty")
+		}
+	}
+
+	return nil
+}
 
+// CheckExpected returns an error naming the first key, in sorted order, whose
+// value in meta does not match the request's expected value.
+func (n *NodeMetaApplyRequest) CheckExpected(meta map[string]string) error {
+	keys := make([]string, 0, len(n.Expected))
+	for k := range n.Expected {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		expected := n.Expected[k]
+		current, ok := meta[k]
+		switch {
+		case expected == nil && ok:
+			return fmt.Errorf("metadata key %q is set to %q, expected it to be unset", k, current)
+		case expected != nil && !ok:
+			return fmt.Errorf("metadata key %q is unset, expected %q", k, *expected)
+		case expected != nil && current != *expected:
+			return fmt.Errorf("metadata key %q is set to %q, expected %q", k, current, *expected)
+		}
+	}
 	return nil
 }
 
@@ -403,4 +439,181 @@ type NodeMetaResponse struct {
 
 	// Static is the static Node metadata (set via agent configuration)
 	Static map[string]string
+
+	// Index is incremented by the client on every dynamic metadata update and
+	// may be used as the index of blocking reads to watch for changes. It is
+	// not persisted and restarts along with the client.
+	Index uint64
+}
+
+// NodeHardware is the hardware inventory of a node, built from the attributes
+// and resources fingerprinted by its client.
+type NodeHardware struct {
+	NodeID   string
+	NodeName string
+	NodePool string
+
+	CPU      NodeHardwareCPU
+	MemoryMB int64
+	Disks    []*NodeHardwareDisk
+	NICs     []*NodeHardwareNIC
+	Devices  []*NodeHardwareDevice
+
+	// NUMA is the NUMA layout of the node, empty if the node does not
+	// support NUMA.
+	NUMA []*NodeHardwareNUMANode
+}
+
+// NodeHardwareCPU describes the processors of a node.
+type NodeHardwareCPU struct {
+	ModelName        string
+	Sockets          int
+	Cores            int
+	PerformanceCores int
+	EfficiencyCores  int
+
+	// TotalCompute is the total compute of the cores in MHz.
+	TotalCompute uint64
+}
+
+// NodeHardwareDisk describes a disk volume of a node.
+type NodeHardwareDisk struct {
+	Volume     string
+	TotalBytes uint64
+	FreeBytes  uint64
+}
+
+// NodeHardwareNIC describes a host network interface of a node.
+type NodeHardwareNIC struct {
+	Device     string
+	MacAddress string
+
+	// SpeedMbits is the speed of the interface in megabits per second.
+	SpeedMbits int
+	Addresses  []string
+}
+
+// NodeHardwareDevice describes a group of devices of a node with the same
+// vendor, type and name, such as GPUs.
+type NodeHardwareDevice struct {
+	Vendor     string
+	Type       string
+	Name       string
+	Instances  int
+	Healthy    int
+	Attributes map[string]string
+}
+
+// NodeHardwareNUMANode describes a NUMA node of a node.
+type NodeHardwareNUMANode struct {
+	ID uint8
+
+	// Cores is the set of cores of the NUMA node in cpuset notation.
+	Cores string
+
+	// Compute is the total compute of the cores in MHz.
+	Compute uint64
+
+	// Distances are the relative costs of accessing the memory of each NUMA
+	// node from this NUMA node, indexed by NUMA node ID.
+	Distances []uint8
+}
+
+// Hardware returns the hardware inventory of the node.
+func (n *Node) Hardware() *NodeHardware {
+	inv := &NodeHardware{
+		NodeID:   n.ID,
+		NodeName: n.Name,
+		NodePool: n.NodePool,
+		CPU: NodeHardwareCPU{
+			ModelName: n.Attributes["cpu.modelname"],
+		},
+	}
+
+	if volume := n.Attributes["unique.storage.volume"]; volume != "" {
+		disk := &NodeHardwareDisk{Volume: volume}
+		disk.TotalBytes, _ = strconv.ParseUint(n.Attributes["unique.storage.bytestotal"], 10, 64)
+		disk.FreeBytes, _ = strconv.ParseUint(n.Attributes["unique.storage.bytesfree"], 10, 64)
+		inv.Disks = append(inv.Disks, disk)
+	}
+
+	r := n.NodeResources
+	if r == nil {
+		return inv
+	}
+
+	inv.MemoryMB = r.Memory.MemoryMB
+
+	if topology := r.Processors.Topology; topology != nil {
+		sockets := make(map[uint8]struct{})
+		nodes := make(map[uint8]*NodeHardwareNUMANode)
+		for _, core := range topology.Cores {
+			sockets[uint8(core.SocketID)] = struct{}{}
+			if _, ok := nodes[core.NodeID]; !ok {
+				nodes[core.NodeID] = &NodeHardwareNUMANode{
+					ID:    core.NodeID,
+					Cores: topology.NodeCores(core.NodeID).String(),
+				}
+				if int(core.NodeID) < len(topology.Distances) {
+					for _, cost := range topology.Distances[core.NodeID] {
+						nodes[core.NodeID].Distances = append(nodes[core.NodeID].Distances, uint8(cost))
+					}
+				}
+			}
+			nodes[core.NodeID].Compute += uint64(core.MHz())
+		}
+
+		inv.CPU.Sockets = len(sockets)
+		inv.CPU.Cores = topology.NumCores()
+		inv.CPU.PerformanceCores = topology.NumPCores()
+		inv.CPU.EfficiencyCores = topology.NumECores()
+		inv.CPU.TotalCompute = uint64(topology.TotalCompute())
+
+		// a single NUMA node is not a NUMA layout
+		if len(nodes) > 1 {
+			for _, node := range nodes {
+				inv.NUMA = append(inv.NUMA, node)
+			}
+			sort.Slice(inv.NUMA, func(i, j int) bool { return inv.NUMA[i].ID < inv.NUMA[j].ID })
+		}
+	}
+
+	for _, network := range r.NodeNetworks {
+		if network.Mode != "host" || network.Device == "" {
+			continue
+		}
+		nic := &NodeHardwareNIC{
+			Device:     network.Device,
+			MacAddress: network.MacAddress,
+			SpeedMbits: network.Speed,
+		}
+		for _, addr := range network.Addresses {
+			nic.Addresses = append(nic.Addresses, addr.Address)
+		}
+		inv.NICs = append(inv.NICs, nic)
+	}
+	sort.Slice(inv.NICs, func(i, j int) bool { return inv.NICs[i].Device < inv.NICs[j].Device })
+
+	for _, device := range r.Devices {
+		d := &NodeHardwareDevice{
+			Vendor:    device.Vendor,
+			Type:      device.Type,
+			Name:      device.Name,
+			Instances: len(device.Instances),
+		}
+		for _, instance := range device.Instances {
+			if instance.Healthy {
+				d.Healthy++
+			}
+		}
+		if len(device.Attributes) > 0 {
+			d.Attributes = make(map[string]string, len(device.Attributes))
+			for k, v := range device.Attributes {
+				d.Attributes[k] = v.GoString()
+			}
+		}
+		inv.Devices = append(inv.Devices, d)
+	}
+
+	return inv
 }
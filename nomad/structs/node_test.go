@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
 	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/require"
 )
@@ -163,3 +164,129 @@ func TestNodeMeta_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeMetaApplyRequest_CheckExpected(t *testing.T) {
+	ci.Parallel(t)
+
+	meta := map[string]string{
+		"maintenance": "node1",
+		"empty":       "",
+	}
+
+	cases := []struct {
+		name     string
+		expected map[string]*string
+		contains string
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "match",
+			expected: map[string]*string{
+				"maintenance": pointer.Of("node1"),
+				"empty":       pointer.Of(""),
+				"unset":       nil,
+			},
+		},
+		{
+			name:     "mismatch",
+			expected: map[string]*string{"maintenance": pointer.Of("node2")},
+			contains: `"maintenance" is set to "node1", expected "node2"`,
+		},
+		{
+			name:     "expected unset",
+			expected: map[string]*string{"maintenance": nil},
+			contains: `expected it to be unset`,
+		},
+		{
+			name:     "expected set",
+			expected: map[string]*string{"unset": pointer.Of("1")},
+			contains: `"unset" is unset, expected "1"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			in := &NodeMetaApplyRequest{Expected: tc.expected}
+			err := in.CheckExpected(meta)
+			if tc.contains == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.contains)
+			}
+		})
+	}
+}
+
+func TestNode_Hardware(t *testing.T) {
+	ci.Parallel(t)
+
+	node := &Node{
+		ID:       "node1",
+		Name:     "worker",
+		NodePool: NodePoolDefault,
+		Attributes: map[string]string{
+			"cpu.modelname":             "AMD EPYC",
+			"unique.storage.volume":     "/dev/sda1",
+			"unique.storage.bytestotal": "1000",
+			"unique.storage.bytesfree":  "400",
+		},
+		NodeResources: &NodeResources{
+			Processors: NodeProcessorResources{
+				Topology: MockWorkstationTopology(),
+			},
+			Memory: NodeMemoryResources{MemoryMB: 8192},
+			NodeNetworks: []*NodeNetworkResource{
+				{
+					Mode:       "host",
+					Device:     "eth0",
+					MacAddress: "00:00:00:00:00:01",
+					Speed:      10000,
+					Addresses:  []NodeNetworkAddress{{Address: "10.0.0.2"}},
+				},
+				{Mode: "bridge"},
+			},
+			Devices: []*NodeDeviceResource{
+				{
+					Vendor:    "nvidia",
+					Type:      "gpu",
+					Name:      "A100",
+					Instances: []*NodeDevice{{ID: "1", Healthy: true}, {ID: "2"}},
+				},
+			},
+		},
+	}
+
+	hw := node.Hardware()
+	must.Eq(t, "AMD EPYC", hw.CPU.ModelName)
+	must.Eq(t, 2, hw.CPU.Sockets)
+	must.Eq(t, 32, hw.CPU.Cores)
+	must.Eq(t, 96_000, hw.CPU.TotalCompute)
+	must.Eq(t, 8192, hw.MemoryMB)
+	must.Eq(t, []*NodeHardwareDisk{{Volume: "/dev/sda1", TotalBytes: 1000, FreeBytes: 400}}, hw.Disks)
+	must.Eq(t, []*NodeHardwareNIC{{
+		Device:     "eth0",
+		MacAddress: "00:00:00:00:00:01",
+		SpeedMbits: 10000,
+		Addresses:  []string{"10.0.0.2"},
+	}}, hw.NICs)
+	must.Eq(t, []*NodeHardwareDevice{{
+		Vendor:    "nvidia",
+		Type:      "gpu",
+		Name:      "A100",
+		Instances: 2,
+		Healthy:   1,
+	}}, hw.Devices)
+
+	must.Len(t, 2, hw.NUMA)
+	must.Eq(t, 1, hw.NUMA[1].ID)
+	must.Eq(t, 48_000, hw.NUMA[1].Compute)
+	must.Eq(t, []uint8{20, 10}, hw.NUMA[1].Distances)
+
+	// a node without fingerprinted resources
+	node.NodeResources = nil
+	hw = node.Hardware()
+	must.Eq(t, "AMD EPYC", hw.CPU.ModelName)
+	must.SliceEmpty(t, hw.NUMA)
+}
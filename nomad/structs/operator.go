@@ -385,3 +385,32 @@ type UpgradeCheckVaultWorkloadIdentityResponse struct {
 
 	QueryMeta
 }
+
+// StateUsageRequest is used to get the object counts and estimated memory
+// usage of the tables of the state store.
+type StateUsageRequest struct {
+	QueryOptions
+}
+
+// StateUsageResponse is the response to the Operator.StateUsage RPC.
+type StateUsageResponse struct {
+	// Tables holds the usage of every table of the state store, sorted by
+	// estimated memory usage in descending order.
+	Tables []*StateTableUsage
+
+	QueryMeta
+}
+
+// StateTableUsage is the usage of a table of the state store.
+type StateTableUsage struct {
+	// Name is the name of the table.
+	Name string
+
+	// Objects is the number of objects in the table.
+	Objects int
+
+	// EstimatedBytes is the memory used by the objects of the table,
+	// estimated from the encoded size of a sample of the objects. It doesn't
+	// include the memory used by the table's indexes.
+	EstimatedBytes int64
+}
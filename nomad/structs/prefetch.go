@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"slices"
+)
+
+// PrefetchImage is the image of a task pulled by a client ahead of the
+// placement of the task.
+type PrefetchImage struct {
+	// Driver is the name of the task driver pulling the image.
+	Driver string
+
+	// Image is the image reference, as set by the "image" option of the task
+	// configuration.
+	Image string
+}
+
+// PrefetchJobRequest is used to make a client pull the images and artifacts
+// of a job before the allocations of the job are placed on the client.
+type PrefetchJobRequest struct {
+	NodeID string
+	JobID  string
+
+	Images    []*PrefetchImage
+	Artifacts []*TaskArtifact
+
+	QueryOptions
+}
+
+// NewPrefetchJobRequest returns the request to prefetch the images and
+// artifacts of the job's tasks, or nil if the job has nothing to prefetch.
+// Images and artifacts shared by several tasks are only included once.
+func NewPrefetchJobRequest(job *Job) *PrefetchJobRequest {
+	req := &PrefetchJobRequest{
+		JobID: job.ID,
+		QueryOptions: QueryOptions{
+			Region:    job.Region,
+			Namespace: job.Namespace,
+		},
+	}
+
+	for _, tg := range job.TaskGroups {
+		for _, task := range tg.Tasks {
+			if image, ok := task.Config["image"].(string); ok && image != "" {
+				pi := &PrefetchImage{Driver: task.Driver, Image: image}
+				if !slices.ContainsFunc(req.Images, func(o *PrefetchImage) bool { return *o == *pi }) {
+					req.Images = append(req.Images, pi)
+				}
+			}
+			for _, artifact := range task.Artifacts {
+				if !slices.ContainsFunc(req.Artifacts, artifact.Equal) {
+					req.Artifacts = append(req.Artifacts, artifact.Copy())
+				}
+			}
+		}
+	}
+
+	if len(req.Images) == 0 && len(req.Artifacts) == 0 {
+		return nil
+	}
+	return req
+}
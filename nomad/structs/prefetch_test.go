@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestNewPrefetchJobRequest(t *testing.T) {
+	ci.Parallel(t)
+
+	job := MockJob()
+	job.TaskGroups[0].Tasks[0].Artifacts = nil
+	must.Nil(t, NewPrefetchJobRequest(job))
+
+	artifact := &TaskArtifact{
+		GetterSource: "https://example.com/file.tar.gz",
+		RelativeDest: "local/",
+	}
+	task := job.TaskGroups[0].Tasks[0]
+	task.Driver = "docker"
+	task.Config = map[string]interface{}{"image": "redis:7"}
+	task.Artifacts = []*TaskArtifact{artifact}
+
+	// tasks sharing the image and artifact only prefetch them once
+	other := task.Copy()
+	other.Name = "other"
+	job.TaskGroups[0].Tasks = append(job.TaskGroups[0].Tasks, other)
+
+	req := NewPrefetchJobRequest(job)
+	must.NotNil(t, req)
+	must.Eq(t, job.ID, req.JobID)
+	must.Eq(t, job.Namespace, req.Namespace)
+	must.Eq(t, []*PrefetchImage{{Driver: "docker", Image: "redis:7"}}, req.Images)
+	must.Eq(t, []*TaskArtifact{artifact}, req.Artifacts)
+}
@@ -0,0 +1,203 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/cronexpr"
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// ScalingPolicyTargetCPU and ScalingPolicyTargetMemory are the keys of a
+	// horizontal scaling policy's opaque configuration holding the target
+	// utilization, in percent of the allocated resources, that the built-in
+	// autoscaler maintains. Policies setting neither are left to an external
+	// autoscaler.
+	ScalingPolicyTargetCPU    = "target_cpu"
+	ScalingPolicyTargetMemory = "target_memory"
+
+	// ScalingPolicyCooldown and ScalingPolicyEvaluationInterval are the keys
+	// of the policy's opaque configuration holding the minimum time between
+	// two scaling events of the group and the time between two evaluations of
+	// the policy. These keys share their meaning with the external autoscaler.
+	ScalingPolicyCooldown           = "cooldown"
+	ScalingPolicyEvaluationInterval = "evaluation_interval"
+
+	DefaultBuiltinScalingCooldown           = 5 * time.Minute
+	DefaultBuiltinScalingEvaluationInterval = 30 * time.Second
+
+	// scalingScheduleLookback bounds the period searched exhaustively for
+	// the latest time matching a scaling schedule
+	scalingScheduleLookback = 24 * time.Hour
+)
+
+// BuiltinScalingPolicy is the configuration of a horizontal scaling policy
+// evaluated by the built-in autoscaler of the servers.
+type BuiltinScalingPolicy struct {
+	// TargetCPU and TargetMemory are the target utilization in percent of the
+	// allocated CPU and memory. Zero means the resource is not considered.
+	TargetCPU    float64
+	TargetMemory float64
+
+	Cooldown           time.Duration
+	EvaluationInterval time.Duration
+}
+
+// BuiltinPolicy returns the configuration of the policy for the built-in
+// autoscaler, or nil if the built-in autoscaler doesn't evaluate the policy
+// because it isn't a horizontal policy or sets no target utilization.
+func (p *ScalingPolicy) BuiltinPolicy() (*BuiltinScalingPolicy, error) {
+	if p.Type != ScalingPolicyTypeHorizontal {
+		return nil, nil
+	}
+	_, hasCPU := p.Policy[ScalingPolicyTargetCPU]
+	_, hasMemory := p.Policy[ScalingPolicyTargetMemory]
+	if !hasCPU && !hasMemory {
+		return nil, nil
+	}
+
+	config := &BuiltinScalingPolicy{
+		Cooldown:           DefaultBuiltinScalingCooldown,
+		EvaluationInterval: DefaultBuiltinScalingEvaluationInterval,
+	}
+
+	var err error
+	for key, target := range map[string]*float64{
+		ScalingPolicyTargetCPU:    &config.TargetCPU,
+		ScalingPolicyTargetMemory: &config.TargetMemory,
+	} {
+		raw, ok := p.Policy[key]
+		if !ok {
+			continue
+		}
+		if *target, err = scalingPolicyFloat(raw); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		if *target <= 0 {
+			return nil, fmt.Errorf("%s must be greater than zero", key)
+		}
+	}
+
+	for key, dur := range map[string]*time.Duration{
+		ScalingPolicyCooldown:           &config.Cooldown,
+		ScalingPolicyEvaluationInterval: &config.EvaluationInterval,
+	} {
+		raw, ok := p.Policy[key]
+		if !ok {
+			continue
+		}
+		s, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s: expected a duration string, got %T", key, raw)
+		}
+		if *dur, err = time.ParseDuration(s); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		if *dur < 0 {
+			return nil, fmt.Errorf("%s must not be negative", key)
+		}
+	}
+
+	return config, nil
+}
+
+// scalingPolicyFloat converts a number of the opaque policy configuration,
+// whose type depends on how the policy was encoded, to a float.
+func scalingPolicyFloat(raw interface{}) (float64, error) {
+	switch v := raw.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", raw)
+	}
+}
+
+// ScalingSchedule sets the count of a task group at the times matching a cron
+// expression.
+type ScalingSchedule struct {
+	// Cron is the cron expression of the times the count is set at
+	Cron string
+
+	// Count is the count the task group is scaled to
+	Count int64
+
+	// TimeZone is the time zone the cron expression is evaluated in. Defaults
+	// to UTC.
+	TimeZone string
+}
+
+func (s *ScalingSchedule) Copy() *ScalingSchedule {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	return &c
+}
+
+// Validate validates the schedule of a policy with the minimum and maximum
+// counts.
+func (s *ScalingSchedule) Validate(min, max int64) error {
+	var mErr multierror.Error
+	if _, err := cronexpr.Parse(s.Cron); err != nil {
+		_ = multierror.Append(&mErr, fmt.Errorf("invalid cron spec %q: %v", s.Cron, err))
+	}
+	if s.TimeZone != "" {
+		if _, err := time.LoadLocation(s.TimeZone); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("invalid time zone %q: %v", s.TimeZone, err))
+		}
+	}
+	if s.Count < min || s.Count > max {
+		_ = multierror.Append(&mErr, fmt.Errorf("count %d is outside of the range [%d, %d]", s.Count, min, max))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// LastMatch returns the latest time in (from, to] that matches the schedule,
+// or the zero time if there is none. Only the day before to is searched for
+// the latest time; if no time of that day matches, the earliest time after
+// from is returned instead.
+func (s *ScalingSchedule) LastMatch(from, to time.Time) (t time.Time, err error) {
+	defer func() {
+		if recover() != nil {
+			t = time.Time{}
+			err = fmt.Errorf("failed parsing cron expression: %q", s.Cron)
+		}
+	}()
+
+	expr, err := cronexpr.Parse(s.Cron)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed parsing cron expression: %s: %v", s.Cron, err)
+	}
+	loc := time.UTC
+	if s.TimeZone != "" {
+		if loc, err = time.LoadLocation(s.TimeZone); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	next := expr.Next(from.In(loc))
+	if next.IsZero() || next.After(to) {
+		return time.Time{}, nil
+	}
+	last := next
+	if start := to.Add(-scalingScheduleLookback); start.After(next) {
+		next = expr.Next(start.In(loc))
+	}
+	for !next.IsZero() && !next.After(to) {
+		last = next
+		next = expr.Next(next)
+	}
+	return last, nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shoenig/test/must"
+
+	"github.com/hashicorp/nomad/ci"
+)
+
+func TestScalingPolicy_BuiltinPolicy(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name   string
+		policy map[string]interface{}
+		exp    *BuiltinScalingPolicy
+		expErr string
+	}{
+		{
+			name:   "external autoscaler",
+			policy: map[string]interface{}{"cooldown": "1m", "check": []interface{}{}},
+		},
+		{
+			name:   "defaults",
+			policy: map[string]interface{}{"target_cpu": 70},
+			exp: &BuiltinScalingPolicy{
+				TargetCPU:          70,
+				Cooldown:           DefaultBuiltinScalingCooldown,
+				EvaluationInterval: DefaultBuiltinScalingEvaluationInterval,
+			},
+		},
+		{
+			name: "full",
+			policy: map[string]interface{}{
+				"target_cpu":          float64(70),
+				"target_memory":       int64(80),
+				"cooldown":            "2m",
+				"evaluation_interval": "10s",
+			},
+			exp: &BuiltinScalingPolicy{
+				TargetCPU:          70,
+				TargetMemory:       80,
+				Cooldown:           2 * time.Minute,
+				EvaluationInterval: 10 * time.Second,
+			},
+		},
+		{
+			name:   "invalid target",
+			policy: map[string]interface{}{"target_memory": "80%"},
+			expErr: "invalid target_memory: expected a number, got string",
+		},
+		{
+			name:   "zero target",
+			policy: map[string]interface{}{"target_cpu": 0},
+			expErr: "target_cpu must be greater than zero",
+		},
+		{
+			name:   "invalid duration",
+			policy: map[string]interface{}{"target_cpu": 70, "cooldown": 60},
+			expErr: "invalid cooldown: expected a duration string, got int",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &ScalingPolicy{Type: ScalingPolicyTypeHorizontal, Policy: tc.policy}
+			config, err := p.BuiltinPolicy()
+			if tc.expErr != "" {
+				must.EqError(t, err, tc.expErr)
+				must.ErrorContains(t, p.Validate(), tc.expErr)
+				return
+			}
+			must.NoError(t, err)
+			must.Eq(t, tc.exp, config)
+		})
+	}
+}
+
+func TestScalingSchedule_LastMatch(t *testing.T) {
+	ci.Parallel(t)
+
+	from := time.Date(2024, 3, 4, 8, 30, 0, 0, time.UTC)
+	s := &ScalingSchedule{Cron: "0 * * * *"}
+
+	// No match since from
+	last, err := s.LastMatch(from, from.Add(20*time.Minute))
+	must.NoError(t, err)
+	must.True(t, last.IsZero())
+
+	// The latest of several matches
+	last, err = s.LastMatch(from, from.Add(3*time.Hour))
+	must.NoError(t, err)
+	must.Eq(t, time.Date(2024, 3, 4, 11, 0, 0, 0, time.UTC), last)
+
+	// Matches further back than the lookback are found too
+	s = &ScalingSchedule{Cron: "0 9 * * 1", TimeZone: "Europe/Berlin"}
+	last, err = s.LastMatch(from.Add(-72*time.Hour), from.Add(40*time.Hour))
+	must.NoError(t, err)
+	must.Eq(t, time.Date(2024, 3, 4, 8, 0, 0, 0, time.UTC), last.UTC())
+
+	_, err = (&ScalingSchedule{Cron: "not a cron"}).LastMatch(from, from.Add(time.Hour))
+	must.Error(t, err)
+}
+
+func TestScalingSchedule_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	s := &ScalingSchedule{Cron: "0 8 * * 1-5", Count: 5, TimeZone: "America/New_York"}
+	must.NoError(t, s.Validate(1, 10))
+
+	s = &ScalingSchedule{Cron: "bad", Count: 20, TimeZone: "Mars/Olympus"}
+	err := s.Validate(1, 10)
+	must.ErrorContains(t, err, `invalid cron spec "bad"`)
+	must.ErrorContains(t, err, `invalid time zone "Mars/Olympus"`)
+	must.ErrorContains(t, err, "count 20 is outside of the range [1, 10]")
+}
@@ -0,0 +1,152 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// sealedValuePrefix prefixes every sealed value, so the servers can tell
+	// sealed values from cleartext ones and the format can change later.
+	sealedValuePrefix = "sealed:v1:"
+
+	// SealedDir is the directory in the task's secrets directory where the
+	// client writes the task's unsealed values.
+	SealedDir = "sealed"
+)
+
+// validSealedName matches the names of sealed values, which become file
+// names in the task's secrets directory.
+var validSealedName = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127}$`)
+
+// FormatSealedValue returns the sealed value for the ciphertext encrypted
+// with the root key.
+func FormatSealedValue(keyID string, ciphertext []byte) string {
+	return sealedValuePrefix + keyID + ":" + base64.StdEncoding.EncodeToString(ciphertext)
+}
+
+// IsSealedValue returns true if the value has the format of a sealed value.
+func IsSealedValue(value string) bool {
+	return strings.HasPrefix(value, sealedValuePrefix)
+}
+
+// ParseSealedValue returns the ID of the root key and the ciphertext of a
+// sealed value.
+func ParseSealedValue(value string) (string, []byte, error) {
+	if !IsSealedValue(value) {
+		return "", nil, errors.New("value is not sealed")
+	}
+	keyID, encoded, ok := strings.Cut(strings.TrimPrefix(value, sealedValuePrefix), ":")
+	if !ok || keyID == "" {
+		return "", nil, errors.New("sealed value is missing its key ID")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to decode sealed value: %w", err)
+	}
+	return keyID, ciphertext, nil
+}
+
+// SealedBinding returns the additional data sealed values of a job are
+// encrypted with, so the ciphertext of a sealed value can't be copied into
+// another job to have it unsealed there.
+func SealedBinding(namespace, jobID string) string {
+	return namespace + "/" + jobID
+}
+
+// SealedBinding returns the binding sealed values of the job were encrypted
+// with. Dispatched and periodic child jobs are bound to their parent job.
+func (j *Job) SealedBinding() string {
+	if j.ParentID != "" && strings.HasPrefix(j.ID, j.ParentID+"/") {
+		return SealedBinding(j.Namespace, j.ParentID)
+	}
+	return SealedBinding(j.Namespace, j.ID)
+}
+
+// HasSealedValues returns true if a task of the job has sealed values.
+func (j *Job) HasSealedValues() bool {
+	for _, tg := range j.TaskGroups {
+		for _, task := range tg.Tasks {
+			if len(task.Sealed) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UsesSealedKey returns true if a sealed value of the job is encrypted with
+// the root key.
+func (j *Job) UsesSealedKey(keyID string) bool {
+	for _, tg := range j.TaskGroups {
+		for _, task := range tg.Tasks {
+			for _, value := range task.Sealed {
+				if id, _, err := ParseSealedValue(value); err == nil && id == keyID {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// ValidateSealed returns an error if a task of the job has a value that isn't
+// sealed, which prevents cleartext secrets from being written to raft.
+func (j *Job) ValidateSealed() error {
+	for _, tg := range j.TaskGroups {
+		for _, task := range tg.Tasks {
+			for name, value := range task.Sealed {
+				if _, _, err := ParseSealedValue(value); err != nil {
+					return fmt.Errorf("task %q sealed value %q: %w", task.Name, name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// KeyringSealRequest is the argument to the Keyring.Seal RPC. It seals the
+// values of a task of the job in the request's namespace.
+type KeyringSealRequest struct {
+	JobID     string
+	TaskGroup string
+	Task      string
+
+	// Values are the cleartext values to seal, keyed by name.
+	Values map[string]string
+
+	WriteRequest
+}
+
+// KeyringSealResponse is the response to the Keyring.Seal RPC.
+type KeyringSealResponse struct {
+	// Values are the sealed values, keyed by name. Values that are unchanged
+	// from the task of the registered job keep their sealed value, so that
+	// submitting the job again doesn't update it.
+	Values map[string]string
+
+	WriteMeta
+}
+
+// KeyringUnsealRequest is the argument to the Keyring.Unseal RPC. Clients use
+// it to unseal the sealed values of the tasks of their allocations.
+type KeyringUnsealRequest struct {
+	AllocID string
+	Task    string
+
+	QueryOptions
+}
+
+// KeyringUnsealResponse is the response to the Keyring.Unseal RPC.
+type KeyringUnsealResponse struct {
+	// Values are the cleartext values, keyed by name.
+	Values map[string]string
+
+	QueryMeta
+}
@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/shoenig/test/must"
+)
+
+func TestSealedValue_Parse(t *testing.T) {
+	ci.Parallel(t)
+
+	sealed := FormatSealedValue("key1", []byte("ciphertext"))
+	must.True(t, IsSealedValue(sealed))
+
+	keyID, ciphertext, err := ParseSealedValue(sealed)
+	must.NoError(t, err)
+	must.Eq(t, "key1", keyID)
+	must.Eq(t, []byte("ciphertext"), ciphertext)
+
+	_, _, err = ParseSealedValue("hunter2")
+	must.EqError(t, err, "value is not sealed")
+	_, _, err = ParseSealedValue("sealed:v1::YQ==")
+	must.EqError(t, err, "sealed value is missing its key ID")
+	_, _, err = ParseSealedValue("sealed:v1:key1:!!")
+	must.ErrorContains(t, err, "failed to decode sealed value")
+}
+
+func TestJob_SealedValues(t *testing.T) {
+	ci.Parallel(t)
+
+	job := &Job{
+		ID:        "example",
+		Namespace: DefaultNamespace,
+		TaskGroups: []*TaskGroup{{
+			Name:  "web",
+			Tasks: []*Task{{Name: "web"}},
+		}},
+	}
+	must.False(t, job.HasSealedValues())
+	must.NoError(t, job.ValidateSealed())
+
+	job.TaskGroups[0].Tasks[0].Sealed = map[string]string{
+		"password": FormatSealedValue("key1", []byte("ciphertext")),
+	}
+	must.True(t, job.HasSealedValues())
+	must.NoError(t, job.ValidateSealed())
+	must.True(t, job.UsesSealedKey("key1"))
+	must.False(t, job.UsesSealedKey("key2"))
+
+	job.TaskGroups[0].Tasks[0].Sealed["token"] = "hunter2"
+	must.EqError(t, job.ValidateSealed(), `task "web" sealed value "token": value is not sealed`)
+
+	// Child jobs are bound to their parent job
+	must.Eq(t, "default/example", job.SealedBinding())
+	child := job.Copy()
+	child.ID = "example/dispatch-1234"
+	child.ParentID = "example"
+	must.Eq(t, "default/example", child.SealedBinding())
+	child.ParentID = "other"
+	must.Eq(t, "default/example/dispatch-1234", child.SealedBinding())
+}
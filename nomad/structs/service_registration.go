@@ -91,6 +91,11 @@ type ServiceRegistration struct {
 	// is determined by a combination of factors on the client.
 	Port int
 
+	// Weight is the sum of the weights of the passing checks of this service
+	// registration, or 1 if the service has no checks. Service queries return
+	// the registrations with the highest weight first.
+	Weight int
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -142,6 +147,9 @@ func (s *ServiceRegistration) Equal(o *ServiceRegistration) bool {
 	if s.Port != o.Port {
 		return false
 	}
+	if s.Weight != o.Weight {
+		return false
+	}
 	if !helper.SliceSetEq(s.Tags, o.Tags) {
 		return false
 	}
@@ -271,6 +279,11 @@ type ServiceRegistrationStub struct {
 type ServiceRegistrationByNameRequest struct {
 	ServiceName string
 	Choose      string // stable selection of n services
+
+	// HealthyOnly filters out the services of allocations that are not
+	// running or have been marked unhealthy by their deployment.
+	HealthyOnly bool
+
 	QueryOptions
 }
 
@@ -11,6 +11,7 @@ import (
 	"hash"
 	"io"
 	"maps"
+	"net"
 	"net/url"
 	"reflect"
 	"regexp"
@@ -18,6 +19,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/hashicorp/consul/api"
@@ -81,6 +83,8 @@ type ServiceCheck struct {
 	FailuresBeforeWarning  int                 // Number of consecutive failures required before showing warning
 	Body                   string              // Body to use in HTTP check
 	OnUpdate               string
+	Weight                 int  // Weight added to the service weight while passing (Nomad only)
+	UseContainerHealth     bool // Whether a script check reports the health of the task's container
 }
 
 // IsReadiness returns whether the configuration of the ServiceCheck is effectively
@@ -209,6 +213,14 @@ func (sc *ServiceCheck) Equal(o *ServiceCheck) bool {
 		return false
 	}
 
+	if sc.Weight != o.Weight {
+		return false
+	}
+
+	if sc.UseContainerHealth != o.UseContainerHealth {
+		return false
+	}
+
 	return true
 }
 
@@ -269,11 +281,19 @@ func (sc *ServiceCheck) validateCommon(allowableTypes []string) error {
 			return fmt.Errorf("http type must have relative http path")
 		}
 	case ServiceCheckScript:
-		if sc.Command == "" {
+		if sc.UseContainerHealth {
+			if sc.Command != "" || len(sc.Args) > 0 {
+				return fmt.Errorf("script type with use_container_health must not have a command or args")
+			}
+		} else if sc.Command == "" {
 			return fmt.Errorf("script type must have a valid script path")
 		}
 	}
 
+	if sc.UseContainerHealth && checkType != ServiceCheckScript {
+		return fmt.Errorf("use_container_health may only be set for script checks")
+	}
+
 	// validate interval
 	if sc.Interval == 0 {
 		return fmt.Errorf("missing required value interval. Interval cannot be less than %v", minCheckInterval)
@@ -342,7 +362,7 @@ func (sc *ServiceCheck) validateCommon(allowableTypes []string) error {
 
 // validate a Service's ServiceCheck in the context of the Nomad provider.
 func (sc *ServiceCheck) validateNomad() error {
-	allowable := []string{ServiceCheckTCP, ServiceCheckHTTP}
+	allowable := []string{ServiceCheckTCP, ServiceCheckHTTP, ServiceCheckGRPC, ServiceCheckScript}
 	if err := sc.validateCommon(allowable); err != nil {
 		return err
 	}
@@ -378,14 +398,16 @@ func (sc *ServiceCheck) validateNomad() error {
 		}
 	}
 
-	// success_before_passing is consul only
-	if sc.SuccessBeforePassing != 0 {
-		return errors.New("success_before_passing may only be set for Consul service checks")
+	if sc.SuccessBeforePassing < 0 {
+		return errors.New("success_before_passing must be non-negative")
 	}
 
-	// failures_before_critical is consul only
-	if sc.FailuresBeforeCritical != 0 {
-		return errors.New("failures_before_critical may only be set for Consul service checks")
+	if sc.FailuresBeforeCritical < 0 {
+		return errors.New("failures_before_critical must be non-negative")
+	}
+
+	if sc.Weight < 0 {
+		return errors.New("weight must be non-negative")
 	}
 
 	// failures_before_warning is consul only
@@ -393,14 +415,14 @@ func (sc *ServiceCheck) validateNomad() error {
 		return errors.New("failures_before_warning may only be set for Consul service checks")
 	}
 
-	// tls_server_name is consul only
-	if sc.TLSServerName != "" {
-		return errors.New("tls_server_name may only be set for Consul service checks")
+	// tls_server_name is consul only, except for grpc checks
+	if sc.TLSServerName != "" && sc.Type != ServiceCheckGRPC {
+		return errors.New("tls_server_name may only be set for Consul service checks or grpc checks")
 	}
 
-	// tls_skip_verify is consul only
-	if sc.TLSSkipVerify {
-		return errors.New("tls_skip_verify may only be set for Consul service checks")
+	// tls_skip_verify is consul only, except for grpc checks
+	if sc.TLSSkipVerify && sc.Type != ServiceCheckGRPC {
+		return errors.New("tls_skip_verify may only be set for Consul service checks or grpc checks")
 	}
 
 	return nil
@@ -454,6 +476,11 @@ func (sc *ServiceCheck) validateConsul() error {
 		return fmt.Errorf("failures_before_warning not supported for check of type %q", sc.Type)
 	}
 
+	// weight is nomad only
+	if sc.Weight != 0 {
+		return fmt.Errorf("weight may only be set for Nomad service checks")
+	}
+
 	return nil
 }
 
@@ -516,6 +543,9 @@ func (sc *ServiceCheck) Hash(serviceID string) string {
 	hashIntIfNonZero(h, "failures", sc.FailuresBeforeCritical)
 	hashIntIfNonZero(h, "failures-before-warning", sc.FailuresBeforeWarning)
 
+	// Only include container health if set to maintain ID stability
+	hashBool(h, sc.UseContainerHealth, "UseContainerHealth")
+
 	// Hash is used for diffing against the Consul check definition, which does
 	// not have an expose parameter. Instead we rely on implied changes to
 	// other fields if the Expose setting is changed in a nomad service.
@@ -722,6 +752,15 @@ func (s *Service) Canonicalize(job, taskGroup, task, jobNamespace string) {
 	} else if s.Provider == ServiceProviderNomad {
 		s.Namespace = jobNamespace
 	}
+
+	// Nomad checks contribute to the weight of their service by default.
+	if s.Provider == ServiceProviderNomad {
+		for _, check := range s.Checks {
+			if check.Weight == 0 {
+				check.Weight = 1
+			}
+		}
+	}
 }
 
 // Warnings returns a list of warnings that may be from dubious settings or
@@ -786,9 +825,79 @@ func (s *Service) Validate() error {
 		mErr.Errors = append(mErr.Errors, err)
 	}
 
+	s.validateRuntimeAttrs(&mErr)
+
 	return mErr.ErrorOrNil()
 }
 
+const (
+	// ServiceRuntimeAttrPrefix is the prefix of the attributes the client
+	// resolves when registering a service. Runtime attributes can only be
+	// interpolated into service tags and meta.
+	ServiceRuntimeAttrPrefix = "runtime."
+
+	// ServiceRuntimeAttrAllocIndex is the index of the allocation.
+	ServiceRuntimeAttrAllocIndex = "runtime.alloc_index"
+
+	// ServiceRuntimeAttrCanary is "true" while the allocation is an
+	// unpromoted canary, and "false" otherwise.
+	ServiceRuntimeAttrCanary = "runtime.canary"
+
+	// ServiceRuntimeAttrNUMANode is the NUMA node of the CPU cores reserved
+	// for the allocation, or empty if it doesn't reserve cores.
+	ServiceRuntimeAttrNUMANode = "runtime.numa_node"
+)
+
+var (
+	// serviceRuntimeAttrRe matches references to runtime attributes
+	serviceRuntimeAttrRe = regexp.MustCompile(`\${(runtime\.[a-zA-Z0-9_\-\.]*)}`)
+
+	// serviceRuntimeAttrInterfaceIPRe matches the runtime attribute of the IP
+	// address of a host network interface
+	serviceRuntimeAttrInterfaceIPRe = regexp.MustCompile(`^runtime\.interface\.[a-zA-Z0-9_\-\.]+\.ip$`)
+)
+
+// ServiceRuntimeAttrInterfaceIP returns the runtime attribute of the IP
+// address of the host network interface.
+func ServiceRuntimeAttrInterfaceIP(iface string) string {
+	return ServiceRuntimeAttrPrefix + "interface." + iface + ".ip"
+}
+
+// validateRuntimeAttrs validates the references to runtime attributes of the
+// service. Runtime attributes are only resolved in tags and meta.
+func (s *Service) validateRuntimeAttrs(mErr *multierror.Error) {
+	for _, field := range []string{s.Name, s.PortLabel, s.Address} {
+		if serviceRuntimeAttrRe.MatchString(field) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf(
+				"Service %s runtime attributes can only be used in tags and meta: %q", s.Name, field))
+		}
+	}
+
+	values := append(slices.Clone(s.Tags), s.CanaryTags...)
+	for _, v := range s.Meta {
+		values = append(values, v)
+	}
+	for _, v := range s.CanaryMeta {
+		values = append(values, v)
+	}
+
+	for _, v := range values {
+		for _, m := range serviceRuntimeAttrRe.FindAllStringSubmatch(v, -1) {
+			switch attr := m[1]; {
+			case attr == ServiceRuntimeAttrAllocIndex,
+				attr == ServiceRuntimeAttrCanary,
+				attr == ServiceRuntimeAttrNUMANode,
+				serviceRuntimeAttrInterfaceIPRe.MatchString(attr):
+			default:
+				mErr.Errors = append(mErr.Errors, fmt.Errorf(
+					"Service %s references unknown runtime attribute %q; must be one of %q, %q, %q, or %q",
+					s.Name, attr, ServiceRuntimeAttrAllocIndex, ServiceRuntimeAttrCanary,
+					ServiceRuntimeAttrNUMANode, ServiceRuntimeAttrInterfaceIP("<interface>")))
+			}
+		}
+	}
+}
+
 // MakeUniqueIdentityName returns a service identity name consisting of: task
 // name, service name and service port label.
 func (s *Service) MakeUniqueIdentityName() string {
@@ -875,6 +984,12 @@ func (s *Service) validateNomadService(mErr *multierror.Error) {
 		// validate the nomad check
 		if err := c.validateNomad(); err != nil {
 			mErr.Errors = append(mErr.Errors, err)
+			continue
+		}
+
+		// script checks run in a task, which group services must specify
+		if c.Type == ServiceCheckScript && c.TaskName == "" && s.TaskName == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Check %s invalid: script checks of group services must set task", c.Name))
 		}
 	}
 
@@ -958,6 +1073,7 @@ func hashConnect(h hash.Hash, connect *ConsulConnect) {
 			hashString(h, p.LocalServiceAddress)
 			hashString(h, strconv.Itoa(p.LocalServicePort))
 			hashConfig(h, p.Config)
+			hashEnvoy(h, p.Envoy)
 			for _, upstream := range p.Upstreams {
 				hashString(h, upstream.DestinationName)
 				hashString(h, upstream.DestinationNamespace)
@@ -974,6 +1090,29 @@ func hashConnect(h hash.Hash, connect *ConsulConnect) {
 	}
 }
 
+// hashEnvoy hashes the envoy block only when set, so the hash of services
+// without it is unchanged.
+func hashEnvoy(h hash.Hash, envoy *ConsulEnvoyConfig) {
+	if envoy == nil {
+		return
+	}
+	hashString(h, strconv.Itoa(envoy.Concurrency))
+	if st := envoy.Stats; st != nil {
+		hashString(h, st.StatsdURL)
+		hashString(h, st.DogstatsdURL)
+		hashString(h, st.PrometheusBindAddr)
+		hashTags(h, st.Tags)
+		hashString(h, st.FlushInterval.String())
+	}
+	if t := envoy.Tracing; t != nil {
+		hashString(h, t.Provider)
+		hashString(h, t.Address)
+		hashString(h, t.Path)
+		hashString(h, t.ServiceName)
+	}
+	hashString(h, envoy.BootstrapTemplate)
+}
+
 func hashIdentity(h hash.Hash, identity *WorkloadIdentity) {
 	if identity != nil {
 		hashString(h, identity.Name)
@@ -1221,7 +1360,14 @@ func (c *ConsulConnect) Validate() error {
 		}
 	}
 
-	// The Native and Sidecar cases are validated up at the service level.
+	if c.HasSidecar() {
+		if err := c.SidecarService.Proxy.Validate(); err != nil {
+			return err
+		}
+	}
+
+	// The rest of the Native and Sidecar cases are validated up at the
+	// service level.
 
 	return nil
 }
@@ -1508,6 +1654,10 @@ type ConsulProxy struct {
 	// used by task-group level service checks using HTTP or gRPC protocols.
 	Expose *ConsulExposeConfig
 
+	// Envoy is the structured configuration of the Envoy proxy, translated
+	// by Nomad into the Envoy keys of the proxy configuration.
+	Envoy *ConsulEnvoyConfig
+
 	// Config is a proxy configuration. It is opaque to Nomad and passed
 	// directly to Consul.
 	Config map[string]interface{}
@@ -1524,6 +1674,7 @@ func (p *ConsulProxy) Copy() *ConsulProxy {
 		LocalServicePort:    p.LocalServicePort,
 		Expose:              p.Expose.Copy(),
 		Upstreams:           slices.Clone(p.Upstreams),
+		Envoy:               p.Envoy.Copy(),
 		Config:              maps.Clone(p.Config),
 	}
 }
@@ -1550,6 +1701,10 @@ func (p *ConsulProxy) Equal(o *ConsulProxy) bool {
 		return false
 	}
 
+	if !p.Envoy.Equal(o.Envoy) {
+		return false
+	}
+
 	// envoy config, use reflect
 	if !reflect.DeepEqual(p.Config, o.Config) {
 		return false
@@ -1558,6 +1713,311 @@ func (p *ConsulProxy) Equal(o *ConsulProxy) bool {
 	return true
 }
 
+// Validate the proxy's envoy block, and that it doesn't conflict with the
+// opaque proxy configuration.
+func (p *ConsulProxy) Validate() error {
+	if p == nil || p.Envoy == nil {
+		return nil
+	}
+
+	if err := p.Envoy.Validate(); err != nil {
+		return err
+	}
+
+	for _, key := range p.Envoy.ConfigKeys() {
+		if _, ok := p.Config[key]; ok {
+			return fmt.Errorf("Consul Connect proxy config %q conflicts with the envoy block", key)
+		}
+	}
+
+	return nil
+}
+
+const (
+	// The keys of the Consul proxy configuration set from the envoy block.
+	EnvoyConfigStatsdURL          = "envoy_statsd_url"
+	EnvoyConfigDogstatsdURL       = "envoy_dogstatsd_url"
+	EnvoyConfigPrometheusBindAddr = "envoy_prometheus_bind_addr"
+	EnvoyConfigStatsTags          = "envoy_stats_tags"
+	EnvoyConfigStatsFlushInterval = "envoy_stats_flush_interval"
+	EnvoyConfigTracingJSON        = "envoy_tracing_json"
+	EnvoyConfigExtraClustersJSON  = "envoy_extra_static_clusters_json"
+	EnvoyConfigBootstrapTemplate  = "envoy_bootstrap_json_tpl"
+
+	// The tracing providers supported by the envoy block.
+	EnvoyTracingZipkin        = "zipkin"
+	EnvoyTracingDatadog       = "datadog"
+	EnvoyTracingOpenTelemetry = "opentelemetry"
+)
+
+// ConsulEnvoyConfig represents the envoy block of a Connect sidecar proxy. It
+// is a structured alternative to the Envoy keys of the opaque proxy config,
+// validated when the job is submitted.
+type ConsulEnvoyConfig struct {
+	// Concurrency is the number of worker threads of Envoy, overriding the
+	// connect.proxy_concurrency client meta. Zero keeps the client meta.
+	Concurrency int
+
+	// Stats configures the stats sinks of Envoy.
+	Stats *ConsulEnvoyStats
+
+	// Tracing configures the tracer of Envoy.
+	Tracing *ConsulEnvoyTracing
+
+	// BootstrapTemplate replaces the template Consul renders the bootstrap
+	// configuration of Envoy with.
+	BootstrapTemplate string
+}
+
+// Copy the block recursively. Returns nil if nil.
+func (e *ConsulEnvoyConfig) Copy() *ConsulEnvoyConfig {
+	if e == nil {
+		return nil
+	}
+	return &ConsulEnvoyConfig{
+		Concurrency:       e.Concurrency,
+		Stats:             e.Stats.Copy(),
+		Tracing:           e.Tracing.Copy(),
+		BootstrapTemplate: e.BootstrapTemplate,
+	}
+}
+
+// Equal returns true if the structs are recursively equal.
+func (e *ConsulEnvoyConfig) Equal(o *ConsulEnvoyConfig) bool {
+	if e == nil || o == nil {
+		return e == o
+	}
+	switch {
+	case e.Concurrency != o.Concurrency:
+		return false
+	case !e.Stats.Equal(o.Stats):
+		return false
+	case !e.Tracing.Equal(o.Tracing):
+		return false
+	case e.BootstrapTemplate != o.BootstrapTemplate:
+		return false
+	}
+	return true
+}
+
+// ConfigKeys returns the keys of the Consul proxy configuration set by the
+// block.
+func (e *ConsulEnvoyConfig) ConfigKeys() []string {
+	if e == nil {
+		return nil
+	}
+
+	var keys []string
+	if st := e.Stats; st != nil {
+		if st.StatsdURL != "" {
+			keys = append(keys, EnvoyConfigStatsdURL)
+		}
+		if st.DogstatsdURL != "" {
+			keys = append(keys, EnvoyConfigDogstatsdURL)
+		}
+		if st.PrometheusBindAddr != "" {
+			keys = append(keys, EnvoyConfigPrometheusBindAddr)
+		}
+		if len(st.Tags) > 0 {
+			keys = append(keys, EnvoyConfigStatsTags)
+		}
+		if st.FlushInterval > 0 {
+			keys = append(keys, EnvoyConfigStatsFlushInterval)
+		}
+	}
+	if e.Tracing != nil {
+		// the tracing collector cluster is appended to any extra static
+		// clusters of the proxy config, so only the tracer conflicts
+		keys = append(keys, EnvoyConfigTracingJSON)
+	}
+	if e.BootstrapTemplate != "" {
+		keys = append(keys, EnvoyConfigBootstrapTemplate)
+	}
+	return keys
+}
+
+// Validate the envoy block.
+func (e *ConsulEnvoyConfig) Validate() error {
+	if e == nil {
+		return nil
+	}
+
+	if e.Concurrency < 0 {
+		return fmt.Errorf("Consul Connect envoy concurrency must not be negative")
+	}
+
+	if err := e.Stats.Validate(); err != nil {
+		return err
+	}
+
+	if err := e.Tracing.Validate(); err != nil {
+		return err
+	}
+
+	if e.BootstrapTemplate != "" {
+		if _, err := template.New("bootstrap").Parse(e.BootstrapTemplate); err != nil {
+			return fmt.Errorf("Consul Connect envoy bootstrap_template is invalid: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// ConsulEnvoyStats represents the stats block of a Connect sidecar proxy's
+// envoy block.
+type ConsulEnvoyStats struct {
+	// StatsdURL is the URL of a statsd sink, such as udp://127.0.0.1:8125.
+	StatsdURL string
+
+	// DogstatsdURL is the URL of a DogStatsD sink, either a udp:// or a
+	// unix:// URL.
+	DogstatsdURL string
+
+	// PrometheusBindAddr is the address Envoy serves Prometheus metrics on.
+	PrometheusBindAddr string
+
+	// Tags are tags added to all the stats, as "name=value".
+	Tags []string
+
+	// FlushInterval is how often stats are flushed to the sinks.
+	FlushInterval time.Duration
+}
+
+// Copy the block recursively. Returns nil if nil.
+func (s *ConsulEnvoyStats) Copy() *ConsulEnvoyStats {
+	if s == nil {
+		return nil
+	}
+	ns := *s
+	ns.Tags = slices.Clone(s.Tags)
+	return &ns
+}
+
+// Equal returns true if the structs are recursively equal.
+func (s *ConsulEnvoyStats) Equal(o *ConsulEnvoyStats) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	switch {
+	case s.StatsdURL != o.StatsdURL:
+		return false
+	case s.DogstatsdURL != o.DogstatsdURL:
+		return false
+	case s.PrometheusBindAddr != o.PrometheusBindAddr:
+		return false
+	case !slices.Equal(s.Tags, o.Tags):
+		return false
+	case s.FlushInterval != o.FlushInterval:
+		return false
+	}
+	return true
+}
+
+// Validate the stats block.
+func (s *ConsulEnvoyStats) Validate() error {
+	if s == nil {
+		return nil
+	}
+
+	if s.StatsdURL != "" && !strings.HasPrefix(s.StatsdURL, "udp://") {
+		return fmt.Errorf("Consul Connect envoy statsd_url must be a udp:// URL")
+	}
+
+	if s.DogstatsdURL != "" && !strings.HasPrefix(s.DogstatsdURL, "udp://") &&
+		!strings.HasPrefix(s.DogstatsdURL, "unix://") {
+		return fmt.Errorf("Consul Connect envoy dogstatsd_url must be a udp:// or unix:// URL")
+	}
+
+	if s.PrometheusBindAddr != "" {
+		if _, _, err := net.SplitHostPort(s.PrometheusBindAddr); err != nil {
+			return fmt.Errorf("Consul Connect envoy prometheus_bind_addr is invalid: %v", err)
+		}
+	}
+
+	for _, tag := range s.Tags {
+		if name, _, _ := strings.Cut(tag, "="); name == "" {
+			return fmt.Errorf("Consul Connect envoy stats tag %q must be of the form name=value", tag)
+		}
+	}
+
+	if s.FlushInterval < 0 {
+		return fmt.Errorf("Consul Connect envoy stats flush_interval must not be negative")
+	}
+
+	return nil
+}
+
+// ConsulEnvoyTracing represents the tracing block of a Connect sidecar
+// proxy's envoy block.
+type ConsulEnvoyTracing struct {
+	// Provider is the tracer used by Envoy; one of "zipkin", "datadog", or
+	// "opentelemetry".
+	Provider string
+
+	// Address is the host:port address of the trace collector.
+	Address string
+
+	// Path is the path of the Zipkin collector endpoint.
+	Path string
+
+	// ServiceName is the name of the service in the traces. It defaults to
+	// the name of the Connect service.
+	ServiceName string
+}
+
+// Copy the block. Returns nil if nil.
+func (t *ConsulEnvoyTracing) Copy() *ConsulEnvoyTracing {
+	if t == nil {
+		return nil
+	}
+	nt := *t
+	return &nt
+}
+
+// Equal returns true if the structs are equal.
+func (t *ConsulEnvoyTracing) Equal(o *ConsulEnvoyTracing) bool {
+	if t == nil || o == nil {
+		return t == o
+	}
+	return *t == *o
+}
+
+// Validate the tracing block.
+func (t *ConsulEnvoyTracing) Validate() error {
+	if t == nil {
+		return nil
+	}
+
+	switch t.Provider {
+	case EnvoyTracingZipkin, EnvoyTracingDatadog, EnvoyTracingOpenTelemetry:
+	default:
+		return fmt.Errorf("Consul Connect envoy tracing provider must be %q, %q, or %q; not %q",
+			EnvoyTracingZipkin, EnvoyTracingDatadog, EnvoyTracingOpenTelemetry, t.Provider)
+	}
+
+	host, port, err := net.SplitHostPort(t.Address)
+	if err != nil {
+		return fmt.Errorf("Consul Connect envoy tracing address is invalid: %v", err)
+	}
+	if host == "" {
+		return fmt.Errorf("Consul Connect envoy tracing address must have a host")
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return fmt.Errorf("Consul Connect envoy tracing address has an invalid port %q", port)
+	}
+
+	if t.Path != "" {
+		if t.Provider != EnvoyTracingZipkin {
+			return fmt.Errorf("Consul Connect envoy tracing path is only supported by the %q provider", EnvoyTracingZipkin)
+		}
+		if !strings.HasPrefix(t.Path, "/") {
+			return fmt.Errorf("Consul Connect envoy tracing path must start with /")
+		}
+	}
+
+	return nil
+}
+
 // ConsulMeshGateway is used to configure mesh gateway usage when connecting to
 // a connect upstream in another datacenter.
 type ConsulMeshGateway struct {
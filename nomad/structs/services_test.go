@@ -254,8 +254,67 @@ func TestServiceCheck_validateNomad(t *testing.T) {
 		sc   *ServiceCheck
 		exp  string
 	}{
-		{name: "grpc", sc: &ServiceCheck{Type: ServiceCheckGRPC}, exp: `invalid check type ("grpc"), must be one of tcp, http`},
-		{name: "script", sc: &ServiceCheck{Type: ServiceCheckScript}, exp: `invalid check type ("script"), must be one of tcp, http`},
+		{name: "docker", sc: &ServiceCheck{Type: "docker"}, exp: `invalid check type ("docker"), must be one of tcp, http, grpc, script`},
+		{
+			name: "grpc",
+			sc: &ServiceCheck{
+				Type:          ServiceCheckGRPC,
+				GRPCService:   "health",
+				GRPCUseTLS:    true,
+				TLSSkipVerify: true,
+				Interval:      3 * time.Second,
+				Timeout:       1 * time.Second,
+			},
+		},
+		{
+			name: "script",
+			sc: &ServiceCheck{
+				Type:     ServiceCheckScript,
+				Command:  "/bin/true",
+				Interval: 3 * time.Second,
+				Timeout:  1 * time.Second,
+			},
+		},
+		{
+			name: "container health",
+			sc: &ServiceCheck{
+				Type:               ServiceCheckScript,
+				UseContainerHealth: true,
+				Interval:           3 * time.Second,
+				Timeout:            1 * time.Second,
+			},
+		},
+		{
+			name: "container health with command",
+			sc: &ServiceCheck{
+				Type:               ServiceCheckScript,
+				Command:            "/bin/true",
+				UseContainerHealth: true,
+				Interval:           3 * time.Second,
+				Timeout:            1 * time.Second,
+			},
+			exp: `script type with use_container_health must not have a command or args`,
+		},
+		{
+			name: "container health not script",
+			sc: &ServiceCheck{
+				Type:               ServiceCheckTCP,
+				UseContainerHealth: true,
+				Interval:           3 * time.Second,
+				Timeout:            1 * time.Second,
+			},
+			exp: `use_container_health may only be set for script checks`,
+		},
+		{
+			name: "negative weight",
+			sc: &ServiceCheck{
+				Type:     ServiceCheckTCP,
+				Interval: 3 * time.Second,
+				Timeout:  1 * time.Second,
+				Weight:   -1,
+			},
+			exp: `weight must be non-negative`,
+		},
 		{
 			name: "expose",
 			sc: &ServiceCheck{
@@ -279,21 +338,20 @@ func TestServiceCheck_validateNomad(t *testing.T) {
 			name: "success_before_passing",
 			sc: &ServiceCheck{
 				Type:                 ServiceCheckTCP,
-				SuccessBeforePassing: 3, // consul only
+				SuccessBeforePassing: 3,
 				Interval:             3 * time.Second,
 				Timeout:              1 * time.Second,
 			},
-			exp: `success_before_passing may only be set for Consul service checks`,
 		},
 		{
 			name: "failures_before_critical",
 			sc: &ServiceCheck{
 				Type:                   ServiceCheckTCP,
-				FailuresBeforeCritical: 3, // consul only
+				FailuresBeforeCritical: -1,
 				Interval:               3 * time.Second,
 				Timeout:                1 * time.Second,
 			},
-			exp: `failures_before_critical may only be set for Consul service checks`,
+			exp: `failures_before_critical must be non-negative`,
 		},
 		{
 			name: "failures_before_warning",
@@ -391,7 +449,7 @@ func TestServiceCheck_validateNomad(t *testing.T) {
 				Path:          "/health",
 				TLSServerName: "foo",
 			},
-			exp: `tls_server_name may only be set for Consul service checks`,
+			exp: `tls_server_name may only be set for Consul service checks or grpc checks`,
 		},
 	}
 
@@ -916,6 +974,164 @@ func TestConsulExposeConfig_Equal(t *testing.T) {
 	}))
 }
 
+func TestConsulEnvoyConfig_CopyEqual(t *testing.T) {
+	ci.Parallel(t)
+
+	envoy := &ConsulEnvoyConfig{
+		Concurrency: 2,
+		Stats: &ConsulEnvoyStats{
+			StatsdURL:     "udp://127.0.0.1:8125",
+			Tags:          []string{"env=prod"},
+			FlushInterval: 5 * time.Second,
+		},
+		Tracing: &ConsulEnvoyTracing{
+			Provider: EnvoyTracingZipkin,
+			Address:  "127.0.0.1:9411",
+		},
+		BootstrapTemplate: "{}",
+	}
+
+	c := envoy.Copy()
+	must.Equal(t, envoy, c)
+	must.True(t, envoy.Equal(c))
+
+	c.Stats.Tags[0] = "env=dev"
+	must.Eq(t, "env=prod", envoy.Stats.Tags[0])
+	must.False(t, envoy.Equal(c))
+
+	c = envoy.Copy()
+	c.Tracing.Provider = EnvoyTracingDatadog
+	must.False(t, envoy.Equal(c))
+
+	must.Nil(t, (*ConsulEnvoyConfig)(nil).Copy())
+	must.True(t, (*ConsulEnvoyConfig)(nil).Equal(nil))
+	must.False(t, envoy.Equal(nil))
+}
+
+func TestConsulEnvoyConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name  string
+		envoy *ConsulEnvoyConfig
+		exp   string
+	}{
+		{
+			name:  "empty",
+			envoy: &ConsulEnvoyConfig{},
+		},
+		{
+			name: "everything set",
+			envoy: &ConsulEnvoyConfig{
+				Concurrency: 4,
+				Stats: &ConsulEnvoyStats{
+					StatsdURL:          "udp://127.0.0.1:8125",
+					DogstatsdURL:       "unix:///var/run/dogstatsd.sock",
+					PrometheusBindAddr: "0.0.0.0:9102",
+					Tags:               []string{"env=prod", "canary"},
+					FlushInterval:      5 * time.Second,
+				},
+				Tracing: &ConsulEnvoyTracing{
+					Provider: EnvoyTracingZipkin,
+					Address:  "zipkin.service.consul:9411",
+					Path:     "/api/v2/spans",
+				},
+				BootstrapTemplate: `{"admin": {{ .AdminBindAddress }}}`,
+			},
+		},
+		{
+			name:  "negative concurrency",
+			envoy: &ConsulEnvoyConfig{Concurrency: -1},
+			exp:   "Consul Connect envoy concurrency must not be negative",
+		},
+		{
+			name:  "statsd url",
+			envoy: &ConsulEnvoyConfig{Stats: &ConsulEnvoyStats{StatsdURL: "tcp://127.0.0.1:8125"}},
+			exp:   "Consul Connect envoy statsd_url must be a udp:// URL",
+		},
+		{
+			name:  "dogstatsd url",
+			envoy: &ConsulEnvoyConfig{Stats: &ConsulEnvoyStats{DogstatsdURL: "127.0.0.1:8125"}},
+			exp:   "Consul Connect envoy dogstatsd_url must be a udp:// or unix:// URL",
+		},
+		{
+			name:  "prometheus bind addr",
+			envoy: &ConsulEnvoyConfig{Stats: &ConsulEnvoyStats{PrometheusBindAddr: "9102"}},
+			exp:   "Consul Connect envoy prometheus_bind_addr is invalid",
+		},
+		{
+			name:  "stats tag",
+			envoy: &ConsulEnvoyConfig{Stats: &ConsulEnvoyStats{Tags: []string{"=prod"}}},
+			exp:   `Consul Connect envoy stats tag "=prod" must be of the form name=value`,
+		},
+		{
+			name: "tracing provider",
+			envoy: &ConsulEnvoyConfig{Tracing: &ConsulEnvoyTracing{
+				Provider: "jaeger",
+				Address:  "127.0.0.1:9411",
+			}},
+			exp: `Consul Connect envoy tracing provider must be "zipkin", "datadog", or "opentelemetry"; not "jaeger"`,
+		},
+		{
+			name: "tracing address",
+			envoy: &ConsulEnvoyConfig{Tracing: &ConsulEnvoyTracing{
+				Provider: EnvoyTracingDatadog,
+				Address:  "127.0.0.1:0",
+			}},
+			exp: `Consul Connect envoy tracing address has an invalid port "0"`,
+		},
+		{
+			name: "tracing path",
+			envoy: &ConsulEnvoyConfig{Tracing: &ConsulEnvoyTracing{
+				Provider: EnvoyTracingOpenTelemetry,
+				Address:  "127.0.0.1:4317",
+				Path:     "/v1/traces",
+			}},
+			exp: `Consul Connect envoy tracing path is only supported by the "zipkin" provider`,
+		},
+		{
+			name:  "bootstrap template",
+			envoy: &ConsulEnvoyConfig{BootstrapTemplate: "{{ .AdminBindAddress"},
+			exp:   "Consul Connect envoy bootstrap_template is invalid",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.envoy.Validate()
+			if tc.exp == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.exp)
+			}
+		})
+	}
+}
+
+func TestConsulProxy_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	proxy := &ConsulProxy{
+		Envoy: &ConsulEnvoyConfig{
+			Stats: &ConsulEnvoyStats{StatsdURL: "udp://127.0.0.1:8125"},
+		},
+		Config: map[string]any{
+			"envoy_dogstatsd_url": "udp://127.0.0.1:8125",
+		},
+	}
+	must.NoError(t, proxy.Validate())
+
+	proxy.Config["envoy_statsd_url"] = "udp://127.0.0.1:8126"
+	must.EqError(t, proxy.Validate(),
+		`Consul Connect proxy config "envoy_statsd_url" conflicts with the envoy block`)
+
+	// the envoy block is validated along with the connect block
+	connect := &ConsulConnect{
+		SidecarService: &ConsulSidecarService{Proxy: proxy},
+	}
+	must.Error(t, connect.Validate())
+}
+
 func TestConsulSidecarService_Copy(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1882,7 +2098,7 @@ func TestService_Validate(t *testing.T) {
 				Checks: []*ServiceCheck{
 					{
 						Name: "servicecheck",
-						Type: "script",
+						Type: "docker",
 					},
 				},
 			},
@@ -1968,6 +2184,66 @@ func TestService_Validate_Address(t *testing.T) {
 	try("driver", "example.com", errors.New(`Service address_mode must be "auto" if address is set`))
 }
 
+func TestService_Validate_RuntimeAttrs(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name    string
+		service *Service
+		expErr  string
+	}{
+		{
+			name: "valid tags and meta",
+			service: &Service{
+				Name:       "s1",
+				Provider:   "consul",
+				Tags:       []string{"index-${runtime.alloc_index}", "numa-${runtime.numa_node}"},
+				CanaryTags: []string{"canary=${runtime.canary}"},
+				Meta:       map[string]string{"ip": "${runtime.interface.eth0.ip}"},
+				CanaryMeta: map[string]string{"vlan": "${runtime.interface.eth0.100.ip}"},
+			},
+		},
+		{
+			name: "unknown attribute",
+			service: &Service{
+				Name:     "s1",
+				Provider: "nomad",
+				Tags:     []string{"${runtime.numa}"},
+			},
+			expErr: `Service s1 references unknown runtime attribute "runtime.numa"`,
+		},
+		{
+			name: "interface without field",
+			service: &Service{
+				Name:     "s1",
+				Provider: "nomad",
+				Meta:     map[string]string{"ip": "${runtime.interface.eth0}"},
+			},
+			expErr: `unknown runtime attribute "runtime.interface.eth0"`,
+		},
+		{
+			name: "used in address",
+			service: &Service{
+				Name:     "s1",
+				Provider: "consul",
+				Address:  "${runtime.interface.eth0.ip}",
+			},
+			expErr: "runtime attributes can only be used in tags and meta",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.service.Validate()
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
 func TestService_Equal(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2090,10 +2366,29 @@ func TestService_validateNomadService(t *testing.T) {
 			},
 			inputErr: &multierror.Error{},
 			expectedOutputErrors: []error{
-				errors.New(`invalid check type (""), must be one of tcp, http`),
+				errors.New(`invalid check type (""), must be one of tcp, http, grpc, script`),
 			},
 			name: "bad nomad check",
 		},
+		{
+			inputService: &Service{
+				Name:      "webapp",
+				Namespace: "default",
+				Provider:  "nomad",
+				Checks: []*ServiceCheck{{
+					Name:     "script",
+					Type:     ServiceCheckScript,
+					Command:  "/bin/true",
+					Interval: 3 * time.Second,
+					Timeout:  1 * time.Second,
+				}},
+			},
+			inputErr: &multierror.Error{},
+			expectedOutputErrors: []error{
+				errors.New("Check script invalid: script checks of group services must set task"),
+			},
+			name: "script check without task",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -21,6 +21,7 @@ import (
 	"math"
 	"net"
 	"os"
+	"path"
 	"reflect"
 	"regexp"
 	"slices"
@@ -38,6 +39,7 @@ import (
 	"github.com/hashicorp/nomad/acl"
 	"github.com/hashicorp/nomad/client/lib/idset"
 	"github.com/hashicorp/nomad/client/lib/numalib/hw"
+	"github.com/hashicorp/nomad/command/agent/flightrecorder"
 	"github.com/hashicorp/nomad/command/agent/host"
 	"github.com/hashicorp/nomad/command/agent/pprof"
 	"github.com/hashicorp/nomad/helper"
@@ -128,10 +130,42 @@ const (
 	ACLBindingRulesDeleteRequestType             MessageType = 58
 	NodePoolUpsertRequestType                    MessageType = 59
 	NodePoolDeleteRequestType                    MessageType = 60
+	VariablesExpireRequestType                   MessageType = 61
 
 	// Namespace types were moved from enterprise and therefore start at 64
 	NamespaceUpsertRequestType MessageType = 64
 	NamespaceDeleteRequestType MessageType = 65
+
+	EvalBumpPriorityRequestType MessageType = 66
+
+	JobTemplateUpsertRequestType MessageType = 67
+	JobTemplateDeleteRequestType MessageType = 68
+
+	// Event sink types can't reuse the types of the event sinks removed
+	// during the 1.0-beta series.
+	EventSinkRegisterRequestType   MessageType = 69
+	EventSinkDeregisterRequestType MessageType = 70
+	EventSinkProgressRequestType   MessageType = 71
+
+	UsageUpsertRequestType MessageType = 72
+
+	ACLTokenUseRequestType MessageType = 73
+
+	VariablesPurgeDeletedRequestType MessageType = 74
+
+	VariableGrantUpsertRequestType MessageType = 75
+	VariableGrantDeleteRequestType MessageType = 76
+
+	CSIVolumeSnapshotsUpdateRequestType MessageType = 77
+
+	MaintenanceWindowUpsertRequestType MessageType = 78
+	MaintenanceWindowDeleteRequestType MessageType = 79
+
+	JobNotifyRequestType MessageType = 80
+
+	JobDispatchReleaseRequestType MessageType = 81
+
+	ClientUpgradeUpsertRequestType MessageType = 82
 )
 
 const (
@@ -238,6 +272,7 @@ type RPCInfo interface {
 	RequestRegion() string
 	IsRead() bool
 	AllowStaleRead() bool
+	PreferReadReplica() bool
 	IsForwarded() bool
 	SetForwarded()
 	TimeToBlock() time.Duration
@@ -252,6 +287,10 @@ type RPCInfo interface {
 type InternalRpcInfo struct {
 	// Forwarded marks whether the RPC has been forwarded.
 	Forwarded bool
+
+	// TraceParent is the W3C traceparent of the span that sent the RPC, so
+	// the server handling it continues the trace.
+	TraceParent string
 }
 
 // IsForwarded returns whether the RPC is forwarded from another server.
@@ -264,6 +303,16 @@ func (i *InternalRpcInfo) SetForwarded() {
 	i.Forwarded = true
 }
 
+// GetTraceParent returns the W3C traceparent of the span that sent the RPC.
+func (i *InternalRpcInfo) GetTraceParent() string {
+	return i.TraceParent
+}
+
+// SetTraceParent sets the W3C traceparent of the span sending the RPC.
+func (i *InternalRpcInfo) SetTraceParent(traceParent string) {
+	i.TraceParent = traceParent
+}
+
 // QueryOptions is used to specify various flags for read queries
 type QueryOptions struct {
 	// The target region for this query
@@ -290,6 +339,10 @@ type QueryOptions struct {
 	// may be arbitrarily stale.
 	AllowStale bool
 
+	// ReadReplica requests that a read replica of the region services the
+	// request, if one is available. It implies AllowStale.
+	ReadReplica bool
+
 	// If set, used as prefix for resource list searches
 	Prefix string
 
@@ -359,7 +412,11 @@ func (q QueryOptions) IsRead() bool {
 }
 
 func (q QueryOptions) AllowStaleRead() bool {
-	return q.AllowStale
+	return q.AllowStale || q.ReadReplica
+}
+
+func (q QueryOptions) PreferReadReplica() bool {
+	return q.ReadReplica
 }
 
 func (q *QueryOptions) GetAuthToken() string {
@@ -472,6 +529,11 @@ func (w WriteRequest) AllowStaleRead() bool {
 	return false
 }
 
+// PreferReadReplica only applies to reads, always false.
+func (w WriteRequest) PreferReadReplica() bool {
+	return false
+}
+
 func (w *WriteRequest) GetAuthToken() string {
 	return w.AuthToken
 }
@@ -866,9 +928,69 @@ type JobPlanRequest struct {
 	Diff bool // Toggles an annotated diff
 	// PolicyOverride is set when the user is attempting to override any policies
 	PolicyOverride bool
+	// WhatIf holds hypothetical changes to the cluster to plan the job against
+	WhatIf *JobPlanWhatIf
 	WriteRequest
 }
 
+// JobPlanWhatIfMaxNodes is the maximum number of hypothetical nodes that can be
+// added to the cluster when planning a job.
+const JobPlanWhatIfMaxNodes = 1000
+
+// JobPlanWhatIf describes hypothetical changes to the cluster. The changes are
+// only applied to the state snapshot used to plan the job and never to the
+// cluster itself.
+type JobPlanWhatIf struct {
+	// AddNodes are groups of hypothetical nodes added to the cluster.
+	AddNodes []*JobPlanWhatIfNodes
+
+	// DrainNodes are the IDs, or unique ID prefixes, of nodes that are
+	// drained.
+	DrainNodes []string
+}
+
+// JobPlanWhatIfNodes adds Count hypothetical nodes to the cluster. The nodes
+// are copies of an existing node of the given node class.
+type JobPlanWhatIfNodes struct {
+	NodeClass string
+	Count     int
+
+	// Datacenter overrides the datacenter of the copied node if set.
+	Datacenter string
+}
+
+func (w *JobPlanWhatIf) Validate() error {
+	if w == nil {
+		return nil
+	}
+
+	var mErr multierror.Error
+	total := 0
+	for _, nodes := range w.AddNodes {
+		if nodes == nil {
+			mErr.Errors = append(mErr.Errors, errors.New("nodes to add must not be null"))
+			continue
+		}
+		if nodes.NodeClass == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("nodes to add must have a node class"))
+		}
+		if nodes.Count < 1 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("count of nodes to add must be positive, got %d", nodes.Count))
+		}
+		total += nodes.Count
+	}
+	if total > JobPlanWhatIfMaxNodes {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("cannot add more than %d nodes, got %d", JobPlanWhatIfMaxNodes, total))
+	}
+	for _, nodeID := range w.DrainNodes {
+		if nodeID == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("nodes to drain must have an ID"))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
 // JobScaleRequest is used for the Job.Scale endpoint to scale one of the
 // scaling targets in a job
 type JobScaleRequest struct {
@@ -938,6 +1060,11 @@ type JobDispatchRequest struct {
 	Meta    map[string]string
 	WriteRequest
 	IdPrefixTemplate string
+
+	// Priority overrides the priority of the dispatched job, which also
+	// orders the dispatched jobs queued by the parameterized job. Zero uses
+	// the priority of the parameterized job.
+	Priority int
 }
 
 // JobValidateRequest is used to validate a job
@@ -1050,8 +1177,10 @@ type EvalDequeueRequest struct {
 
 // EvalListRequest is used to list the evaluations
 type EvalListRequest struct {
-	FilterJobID      string
-	FilterEvalStatus string
+	FilterJobID       string
+	FilterEvalStatus  string
+	FilterTriggeredBy string
+	FilterNodeID      string
 	QueryOptions
 }
 
@@ -1064,9 +1193,30 @@ func (req *EvalListRequest) ShouldBeFiltered(e *Evaluation) bool {
 	if req.FilterEvalStatus != "" && req.FilterEvalStatus != e.Status {
 		return true
 	}
+	if req.FilterTriggeredBy != "" && req.FilterTriggeredBy != e.TriggeredBy {
+		return true
+	}
+	if req.FilterNodeID != "" && req.FilterNodeID != e.NodeID {
+		return true
+	}
 	return false
 }
 
+// HasLegacyFilter returns true if any of the query parameter filters are set,
+// which can't be combined with a filter expression.
+func (req *EvalListRequest) HasLegacyFilter() bool {
+	return req.FilterJobID != "" || req.FilterEvalStatus != "" ||
+		req.FilterTriggeredBy != "" || req.FilterNodeID != ""
+}
+
+// EvalPendingSummaryRequest is used to summarize why jobs have allocations
+// that can't be placed.
+type EvalPendingSummaryRequest struct {
+	// FilterJobID limits the summary to a single job.
+	FilterJobID string
+	QueryOptions
+}
+
 // EvalCountRequest is used to count evaluations
 type EvalCountRequest struct {
 	QueryOptions
@@ -1205,6 +1355,12 @@ type AllocSignalRequest struct {
 // AllocsGetRequest is used to query a set of allocations
 type AllocsGetRequest struct {
 	AllocIDs []string
+
+	// DeltaBases maps the IDs of the allocations the client already has a
+	// version of to the JobModifyIndex of the job of that version. The
+	// allocations whose job is unchanged are returned as deltas.
+	DeltaBases map[string]uint64
+
 	QueryOptions
 }
 
@@ -1636,9 +1792,23 @@ type JobDispatchResponse struct {
 	EvalID          string
 	EvalCreateIndex uint64
 	JobCreateIndex  uint64
+
+	// Queued is set if the dispatched job is queued until the parameterized
+	// job is below its concurrency limit, in which case no evaluation is
+	// created yet.
+	Queued bool
+
 	WriteMeta
 }
 
+// JobDispatchReleaseRequest is used by the leader to release queued
+// dispatched jobs, creating their evaluations and clearing their queued flag
+// at once.
+type JobDispatchReleaseRequest struct {
+	Evals []*Evaluation
+	WriteRequest
+}
+
 // JobListResponse is used for a list request
 type JobListResponse struct {
 	Jobs []*JobListStub
@@ -1656,6 +1826,27 @@ type JobVersionsRequest struct {
 type JobVersionsResponse struct {
 	Versions []*Job
 	Diffs    []*JobDiff
+
+	// DiffSummaries are the machine readable summaries of the Diffs, in the
+	// same order.
+	DiffSummaries []*JobDiffSummary
+	QueryMeta
+}
+
+// JobVersionDiffRequest is used to diff two versions of a job. If ToVersion
+// is not set the latest version is used, and if FromVersion is not set the
+// version preceding ToVersion is used.
+type JobVersionDiffRequest struct {
+	JobID       string
+	FromVersion *uint64
+	ToVersion   *uint64
+	QueryOptions
+}
+
+// JobVersionDiffResponse is used to respond to a job version diff request
+type JobVersionDiffResponse struct {
+	Diff    *JobDiff
+	Summary *JobDiffSummary
 	QueryMeta
 }
 
@@ -1688,6 +1879,10 @@ type JobPlanResponse struct {
 	// deprecation warnings.
 	Warnings string
 
+	// WhatIfPlacements maps the name of each hypothetical node added by the
+	// plan request to the number of allocations placed on it.
+	WhatIfPlacements map[string]int
+
 	WriteMeta
 }
 
@@ -1702,6 +1897,10 @@ type SingleAllocResponse struct {
 type AllocsGetResponse struct {
 	Allocs []*Allocation
 
+	// Deltas are the allocations of the request's DeltaBases whose job is
+	// unchanged, which are not included in Allocs.
+	Deltas []*AllocationDelta
+
 	// SignedIdentities are the alternate workload identities for the Allocs.
 	SignedIdentities []SignedWorkloadIdentity
 
@@ -1783,6 +1982,13 @@ type EvalCountResponse struct {
 	QueryMeta
 }
 
+// EvalPendingSummaryResponse is used to return the pending summaries of jobs
+// with blocked evaluations.
+type EvalPendingSummaryResponse struct {
+	Summaries []*JobPendingSummary
+	QueryMeta
+}
+
 // EvalAllocationsResponse is used to return the allocations for an evaluation
 type EvalAllocationsResponse struct {
 	Allocations []*AllocListStub
@@ -1837,6 +2043,21 @@ type HostDataResponse struct {
 	HostData *host.HostData
 }
 
+// FlightRecorderRequest is used by /agent/flight-recorder to dump the flight
+// recorder of an agent. If ServerID or NodeID is specified, the request is
+// forwarded to the remote agent
+type FlightRecorderRequest struct {
+	ServerID string
+	NodeID   string
+	QueryOptions
+}
+
+// FlightRecorderResponse contains the flight recorder dump of an agent
+type FlightRecorderResponse struct {
+	AgentID string
+	Dump    *flightrecorder.Dump
+}
+
 // EmitNodeEventsRequest is a request to update the node events source
 // with a new client-side event
 type EmitNodeEventsRequest struct {
@@ -1854,12 +2075,14 @@ type EmitNodeEventsResponse struct {
 }
 
 const (
-	NodeEventSubsystemDrain     = "Drain"
-	NodeEventSubsystemDriver    = "Driver"
-	NodeEventSubsystemHeartbeat = "Heartbeat"
-	NodeEventSubsystemCluster   = "Cluster"
-	NodeEventSubsystemScheduler = "Scheduler"
-	NodeEventSubsystemStorage   = "Storage"
+	NodeEventSubsystemDrain       = "Drain"
+	NodeEventSubsystemDriver      = "Driver"
+	NodeEventSubsystemFingerprint = "Fingerprint"
+	NodeEventSubsystemHeartbeat   = "Heartbeat"
+	NodeEventSubsystemCluster     = "Cluster"
+	NodeEventSubsystemNetwork     = "Network"
+	NodeEventSubsystemScheduler   = "Scheduler"
+	NodeEventSubsystemStorage     = "Storage"
 )
 
 // NodeEvent is a single unit representing a node’s state change
@@ -1967,6 +2190,12 @@ type DrainSpec struct {
 	// IgnoreSystemJobs allows systems jobs to remain on the node even though it
 	// has been marked for draining.
 	IgnoreSystemJobs bool
+
+	// CSIDetachTimeout is how long to wait for the CSI volumes of drained
+	// allocations to be unpublished from the node before forcing their
+	// claims to be released. Zero waits until the volumes are unpublished or
+	// the drain deadline is reached.
+	CSIDetachTimeout time.Duration
 }
 
 // DrainStrategy describes a Node's drain behavior.
@@ -2030,6 +2259,8 @@ func (d *DrainStrategy) Equal(o *DrainStrategy) bool {
 		return false
 	} else if d.IgnoreSystemJobs != o.IgnoreSystemJobs {
 		return false
+	} else if d.CSIDetachTimeout != o.CSIDetachTimeout {
+		return false
 	}
 
 	return true
@@ -2430,6 +2661,10 @@ type Resources struct {
 	Networks    Networks
 	Devices     ResourceDevices
 	NUMA        *NUMA
+
+	// ExclusiveCores requests the Cores to be reserved from the pool of
+	// exclusive cores of the node, which are never shared.
+	ExclusiveCores bool
 }
 
 const (
@@ -2479,6 +2714,10 @@ func (r *Resources) Validate() error {
 		mErr.Errors = append(mErr.Errors, errors.New("Task can only ask for 'cpu' or 'cores' resource, not both."))
 	}
 
+	if r.ExclusiveCores && r.Cores == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("Task can only ask for exclusive cores with the 'cores' resource."))
+	}
+
 	if err := r.MeetsMinResources(); err != nil {
 		mErr.Errors = append(mErr.Errors, err)
 	}
@@ -2512,6 +2751,9 @@ func (r *Resources) Merge(other *Resources) {
 	if other.Cores != 0 {
 		r.Cores = other.Cores
 	}
+	if other.ExclusiveCores {
+		r.ExclusiveCores = true
+	}
 	if other.MemoryMB != 0 {
 		r.MemoryMB = other.MemoryMB
 	}
@@ -2541,6 +2783,7 @@ func (r *Resources) Equal(o *Resources) bool {
 	}
 	return r.CPU == o.CPU &&
 		r.Cores == o.Cores &&
+		r.ExclusiveCores == o.ExclusiveCores &&
 		r.MemoryMB == o.MemoryMB &&
 		r.MemoryMaxMB == o.MemoryMaxMB &&
 		r.DiskMB == o.DiskMB &&
@@ -2643,6 +2886,8 @@ func (r *Resources) Copy() *Resources {
 		Networks:    r.Networks.Copy(),
 		Devices:     r.Devices.Copy(),
 		NUMA:        r.NUMA.Copy(),
+
+		ExclusiveCores: r.ExclusiveCores,
 	}
 }
 
@@ -2741,6 +2986,11 @@ type NodeNetworkAddress struct {
 	Address       string
 	ReservedPorts string
 	Gateway       string // default route for this address
+
+	// MinDynamicPort and MaxDynamicPort are the dynamic port range of the
+	// address's host network. Zero values use the node's range.
+	MinDynamicPort int
+	MaxDynamicPort int
 }
 
 type AllocatedPortMapping struct {
@@ -2866,6 +3116,11 @@ type NetworkResource struct {
 	DNS           *DNSConfig // DNS Configuration
 	ReservedPorts []Port     // Host Reserved ports
 	DynamicPorts  []Port     // Host Dynamically assigned ports
+
+	// Interfaces is the list of host network interfaces that ports without a
+	// host network are assigned on, in order of preference. The first
+	// interface that exists on the node is used.
+	Interfaces []string `json:",omitempty"`
 }
 
 func (n *NetworkResource) Hash() uint32 {
@@ -2880,6 +3135,10 @@ func (n *NetworkResource) Hash() uint32 {
 		data = append(data, []byte(fmt.Sprintf("d%d%s%d%d", i, port.Label, port.Value, port.To))...)
 	}
 
+	for i, iface := range n.Interfaces {
+		data = append(data, []byte(fmt.Sprintf("i%d%s", i, iface))...)
+	}
+
 	return crc32.ChecksumIEEE(data)
 }
 
@@ -2896,6 +3155,9 @@ func (n *NetworkResource) Canonicalize() {
 	if len(n.DynamicPorts) == 0 {
 		n.DynamicPorts = nil
 	}
+	if len(n.Interfaces) == 0 {
+		n.Interfaces = nil
+	}
 
 	for i, p := range n.DynamicPorts {
 		if p.HostNetwork == "" {
@@ -2925,6 +3187,7 @@ func (n *NetworkResource) Copy() *NetworkResource {
 		newR.DynamicPorts = make([]Port, len(n.DynamicPorts))
 		copy(newR.DynamicPorts, n.DynamicPorts)
 	}
+	newR.Interfaces = slices.Clone(n.Interfaces)
 	return newR
 }
 
@@ -4372,6 +4635,11 @@ type Job struct {
 	// can slow down larger jobs if resources are not available.
 	AllAtOnce bool
 
+	// Prefetch makes the eligible clients pull the images and artifacts of
+	// the job's tasks when the job is registered, before its allocations are
+	// placed.
+	Prefetch bool
+
 	// Datacenters contains all the datacenters this job is allowed to span
 	Datacenters []string
 
@@ -4420,6 +4688,24 @@ type Job struct {
 	// non-terminal siblings which have the same token value.
 	DispatchIdempotencyToken string
 
+	// RegisterIdempotencyToken is the idempotency token of the registration
+	// of this version of the job, used to avoid registering new versions of
+	// the job for retried requests.
+	RegisterIdempotencyToken string
+
+	// DispatchQueued is set if the job was dispatched while its parameterized
+	// job was at its concurrency limit. The job is queued until the leader
+	// releases it, which creates its evaluation and clears the flag.
+	DispatchQueued bool
+
+	// Notify configures the notification sent once the job completes. It
+	// can only be set on batch and sysbatch jobs.
+	Notify *JobNotify
+
+	// NotifyPending is set once a job with a notify block completes, until
+	// the leader sends its notification.
+	NotifyPending bool
+
 	// Payload is the payload supplied when the job was dispatched.
 	Payload []byte
 
@@ -4548,6 +4834,10 @@ func (j *Job) Canonicalize() {
 	if j.Periodic != nil {
 		j.Periodic.Canonicalize()
 	}
+
+	if j.Notify != nil {
+		j.Notify.Canonicalize()
+	}
 }
 
 // Copy returns a deep copy of the Job. It is expected that callers use recover.
@@ -4574,6 +4864,7 @@ func (j *Job) Copy() *Job {
 	nj.Periodic = nj.Periodic.Copy()
 	nj.Meta = maps.Clone(nj.Meta)
 	nj.ParameterizedJob = nj.ParameterizedJob.Copy()
+	nj.Notify = nj.Notify.Copy()
 	return nj
 }
 
@@ -4676,12 +4967,6 @@ func (j *Job) Validate() error {
 			}
 		}
 
-		if j.Type == "system" && tg.Count > 1 {
-			mErr.Errors = append(mErr.Errors,
-				fmt.Errorf("Job task group %s has count %d. Count cannot exceed 1 with system scheduler",
-					tg.Name, tg.Count))
-		}
-
 		if tg.MaxClientDisconnect != nil &&
 			(tg.ReschedulePolicy != nil && tg.ReschedulePolicy.Attempts > 0) &&
 			tg.PreventRescheduleOnLost {
@@ -4729,6 +5014,18 @@ func (j *Job) Validate() error {
 		}
 	}
 
+	if j.Notify != nil {
+		if j.Type != JobTypeBatch && j.Type != JobTypeSysBatch {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf(
+				"Notify can only be used with %q or %q scheduler", JobTypeBatch, JobTypeSysBatch,
+			))
+		}
+
+		if err := j.Notify.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, err)
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -4750,7 +5047,7 @@ func (j *Job) Warnings() error {
 			hasAutoPromote = hasAutoPromote || u.AutoPromote
 
 			// Having no canaries implies auto-promotion since there are no canaries to promote.
-			allAutoPromote = allAutoPromote && (u.Canary == 0 || u.AutoPromote)
+			allAutoPromote = allAutoPromote && (u.DesiredCanaries(tg.Count) == 0 || u.AutoPromote)
 		}
 	}
 
@@ -5017,6 +5314,8 @@ func (j *Job) SpecChanged(new *Job) bool {
 	c.ModifyIndex = j.ModifyIndex
 	c.JobModifyIndex = j.JobModifyIndex
 	c.SubmitTime = j.SubmitTime
+	c.RegisterIdempotencyToken = j.RegisterIdempotencyToken
+	c.NotifyPending = j.NotifyPending
 
 	// cgbaker: FINISH: probably need some consideration of scaling policy ID here
 
@@ -5130,6 +5429,27 @@ const (
 	UpdateStrategyHealthCheck_Manual = "manual"
 )
 
+const (
+	// UpdateStrategyRolling replaces allocations of the previous version in
+	// batches of MaxParallel, optionally gated by a set of canaries.
+	UpdateStrategyRolling = "rolling"
+
+	// UpdateStrategyBlueGreen places a full set of allocations for the new
+	// version alongside the previous version. The new set is treated as
+	// canaries, and the previous version is stopped once the deployment is
+	// promoted.
+	UpdateStrategyBlueGreen = "bluegreen"
+
+	// CanaryPlacementDistinct prefers placing canaries on nodes that aren't
+	// running allocations of the previous version of the task group.
+	CanaryPlacementDistinct = "distinct"
+
+	// CanaryPlacementSame prefers placing canaries on the nodes running
+	// allocations of the previous version of the task group, so that the new
+	// version is qualified on the same nodes.
+	CanaryPlacementSame = "same"
+)
+
 var (
 	// DefaultUpdateStrategy provides a baseline that can be used to upgrade
 	// jobs with the old policy or for populating field defaults.
@@ -5143,6 +5463,7 @@ var (
 		AutoRevert:       false,
 		AutoPromote:      false,
 		Canary:           0,
+		Strategy:         UpdateStrategyRolling,
 	}
 )
 
@@ -5187,6 +5508,20 @@ type UpdateStrategy struct {
 	// Canary is the number of canaries to deploy when a change to the task
 	// group is detected.
 	Canary int
+
+	// Strategy is the method used to replace allocations of the previous
+	// version. An empty value is treated as UpdateStrategyRolling.
+	Strategy string
+
+	// CanaryConstraints selects the nodes of a system job that are updated
+	// first. Allocations on the remaining nodes are only updated once the
+	// allocations on the canary nodes are running the new version.
+	CanaryConstraints []*Constraint
+
+	// CanaryPlacement is the preference for the nodes canaries are placed
+	// on relative to the nodes running the previous version of the task
+	// group. Canaries are placed without a preference if empty.
+	CanaryPlacement string
 }
 
 func (u *UpdateStrategy) Copy() *UpdateStrategy {
@@ -5196,6 +5531,7 @@ func (u *UpdateStrategy) Copy() *UpdateStrategy {
 
 	c := new(UpdateStrategy)
 	*c = *u
+	c.CanaryConstraints = CopySliceConstraints(u.CanaryConstraints)
 	return c
 }
 
@@ -5217,8 +5553,26 @@ func (u *UpdateStrategy) Validate() error {
 	if u.Canary < 0 {
 		_ = multierror.Append(&mErr, fmt.Errorf("Canary count can not be less than zero: %d < 0", u.Canary))
 	}
-	if u.Canary == 0 && u.AutoPromote {
-		_ = multierror.Append(&mErr, fmt.Errorf("Auto Promote requires a Canary count greater than zero"))
+	switch u.Strategy {
+	case "", UpdateStrategyRolling:
+		if u.Canary == 0 && u.AutoPromote {
+			_ = multierror.Append(&mErr, fmt.Errorf("Auto Promote requires a Canary count greater than zero"))
+		}
+	case UpdateStrategyBlueGreen:
+		if u.Canary != 0 {
+			_ = multierror.Append(&mErr, fmt.Errorf("Canary count can not be set with the %q strategy", UpdateStrategyBlueGreen))
+		}
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Invalid update strategy given: %q", u.Strategy))
+	}
+	switch u.CanaryPlacement {
+	case "":
+	case CanaryPlacementDistinct, CanaryPlacementSame:
+		if u.Canary == 0 && !u.IsBlueGreen() {
+			_ = multierror.Append(&mErr, fmt.Errorf("Canary placement requires a Canary count greater than zero"))
+		}
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Invalid canary placement given: %q", u.CanaryPlacement))
 	}
 	if u.MinHealthyTime < 0 {
 		_ = multierror.Append(&mErr, fmt.Errorf("Minimum healthy time may not be less than zero: %v", u.MinHealthyTime))
@@ -5238,6 +5592,11 @@ func (u *UpdateStrategy) Validate() error {
 	if u.Stagger <= 0 {
 		_ = multierror.Append(&mErr, fmt.Errorf("Stagger must be greater than zero: %v", u.Stagger))
 	}
+	for idx, constr := range u.CanaryConstraints {
+		if err := constr.Validate(); err != nil {
+			_ = multierror.Append(&mErr, fmt.Errorf("Canary constraint %d validation failed: %s", idx+1, err))
+		}
+	}
 
 	return mErr.ErrorOrNil()
 }
@@ -5256,12 +5615,34 @@ func (u *UpdateStrategy) IsEmpty() bool {
 	return u.MaxParallel == 0
 }
 
+// IsBlueGreen returns whether the blue/green strategy should be used.
+func (u *UpdateStrategy) IsBlueGreen() bool {
+	return u != nil && u.Strategy == UpdateStrategyBlueGreen
+}
+
+// DesiredCanaries returns the number of canaries that should be placed for a
+// task group with the given count. With the blue/green strategy the entire
+// new version is placed as canaries.
+func (u *UpdateStrategy) DesiredCanaries(count int) int {
+	if u == nil {
+		return 0
+	}
+	if u.IsBlueGreen() {
+		return count
+	}
+	return u.Canary
+}
+
 // Rolling returns if a rolling strategy should be used.
 // TODO(alexdadgar): Remove once no longer used by the scheduler.
 func (u *UpdateStrategy) Rolling() bool {
 	return u.Stagger > 0 && u.MaxParallel > 0
 }
 
+// GlobalRegion is the region of multiregion jobs as submitted, before they are
+// interpolated for each of their regions.
+const GlobalRegion = "global"
+
 type Multiregion struct {
 	Strategy *MultiregionStrategy
 	Regions  []*MultiregionRegion
@@ -5309,6 +5690,16 @@ func (m *Multiregion) Copy() *Multiregion {
 	return copy
 }
 
+const (
+	// MultiregionOnFailureFailAll fails the deployments of all regions when
+	// the deployment of any region fails.
+	MultiregionOnFailureFailAll = "fail_all"
+
+	// MultiregionOnFailureFailLocal only fails the deployment of the region
+	// that failed, and the remaining regions continue their deployments.
+	MultiregionOnFailureFailLocal = "fail_local"
+)
+
 type MultiregionStrategy struct {
 	MaxParallel int
 	OnFailure   string
@@ -5517,6 +5908,7 @@ func (n *Namespace) Copy() *Namespace {
 		*np = *n.NodePoolConfiguration
 		np.Allowed = slices.Clone(n.NodePoolConfiguration.Allowed)
 		np.Denied = slices.Clone(n.NodePoolConfiguration.Denied)
+		nc.NodePoolConfiguration = np
 	}
 	if n.VaultConfiguration != nil {
 		nv := new(NamespaceVaultConfiguration)
@@ -5541,6 +5933,114 @@ func (n *Namespace) Copy() *Namespace {
 	return nc
 }
 
+// DefaultNodePool returns the node pool used by jobs in the namespace that
+// don't specify a node pool of their own.
+func (n *Namespace) DefaultNodePool() string {
+	if n == nil || n.NodePoolConfiguration == nil || n.NodePoolConfiguration.Default == "" {
+		return NodePoolDefault
+	}
+	return n.NodePoolConfiguration.Default
+}
+
+// AllowsNodePool returns true if jobs in the namespace may be placed in the
+// given node pool. The namespace's default node pool is always allowed.
+func (n *Namespace) AllowsNodePool(pool string) bool {
+	if n == nil || n.NodePoolConfiguration == nil {
+		return true
+	}
+	if pool == n.DefaultNodePool() {
+		return true
+	}
+
+	config := n.NodePoolConfiguration
+	if config.Allowed != nil {
+		for _, pattern := range config.Allowed {
+			if glob.Glob(pattern, pool) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, pattern := range config.Denied {
+		if glob.Glob(pattern, pool) {
+			return false
+		}
+	}
+	return true
+}
+
+// DefaultVaultCluster returns the Vault cluster used by jobs in the namespace
+// that don't specify a cluster of their own.
+func (n *Namespace) DefaultVaultCluster() string {
+	if n == nil || n.VaultConfiguration == nil || n.VaultConfiguration.Default == "" {
+		return VaultDefaultCluster
+	}
+	return n.VaultConfiguration.Default
+}
+
+// AllowsVaultCluster returns true if jobs in the namespace may use the given
+// Vault cluster. The namespace's default Vault cluster is always allowed.
+func (n *Namespace) AllowsVaultCluster(cluster string) bool {
+	if n == nil || n.VaultConfiguration == nil {
+		return true
+	}
+	if cluster == n.DefaultVaultCluster() {
+		return true
+	}
+
+	config := n.VaultConfiguration
+	if config.Allowed != nil {
+		for _, pattern := range config.Allowed {
+			if glob.Glob(pattern, cluster) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, pattern := range config.Denied {
+		if glob.Glob(pattern, cluster) {
+			return false
+		}
+	}
+	return true
+}
+
+// DefaultConsulCluster returns the Consul cluster used by jobs in the
+// namespace that don't specify a cluster of their own.
+func (n *Namespace) DefaultConsulCluster() string {
+	if n == nil || n.ConsulConfiguration == nil || n.ConsulConfiguration.Default == "" {
+		return ConsulDefaultCluster
+	}
+	return n.ConsulConfiguration.Default
+}
+
+// AllowsConsulCluster returns true if jobs in the namespace may use the given
+// Consul cluster. The namespace's default Consul cluster is always allowed.
+func (n *Namespace) AllowsConsulCluster(cluster string) bool {
+	if n == nil || n.ConsulConfiguration == nil {
+		return true
+	}
+	if cluster == n.DefaultConsulCluster() {
+		return true
+	}
+
+	config := n.ConsulConfiguration
+	if config.Allowed != nil {
+		for _, pattern := range config.Allowed {
+			if glob.Glob(pattern, cluster) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, pattern := range config.Denied {
+		if glob.Glob(pattern, cluster) {
+			return false
+		}
+	}
+	return true
+}
+
 // NamespaceListRequest is used to request a list of namespaces
 type NamespaceListRequest struct {
 	QueryOptions
@@ -5807,6 +6307,15 @@ type ParameterizedJobConfig struct {
 
 	// MetaOptional is metadata keys that may be specified by the dispatcher
 	MetaOptional []string
+
+	// MaxConcurrent is the maximum number of dispatched jobs that run at the
+	// same time. Jobs dispatched beyond the limit are queued until a running
+	// dispatched job completes. Zero means unlimited.
+	MaxConcurrent int
+
+	// QueueLimit is the maximum number of queued dispatched jobs, beyond which
+	// dispatches are rejected. Zero means unlimited.
+	QueueLimit int
 }
 
 func (d *ParameterizedJobConfig) Validate() error {
@@ -5823,6 +6332,15 @@ func (d *ParameterizedJobConfig) Validate() error {
 		_ = multierror.Append(&mErr, fmt.Errorf("Required and optional meta keys should be disjoint. Following keys exist in both: %v", offending))
 	}
 
+	if d.MaxConcurrent < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Max concurrent must be greater than or equal to 0: %d", d.MaxConcurrent))
+	}
+	if d.QueueLimit < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Queue limit must be greater than or equal to 0: %d", d.QueueLimit))
+	} else if d.QueueLimit > 0 && d.MaxConcurrent == 0 {
+		_ = multierror.Append(&mErr, errors.New("Queue limit requires max concurrent to be set"))
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -5888,9 +6406,39 @@ const (
 	TaskLifecycleHookPoststop  = "poststop"
 )
 
+const (
+	// TaskLifecycleUnhealthyActionFail fails the task, and so the
+	// allocation, if a sidecar does not become healthy in time.
+	TaskLifecycleUnhealthyActionFail = "fail"
+
+	// TaskLifecycleUnhealthyActionRestart restarts a sidecar that does not
+	// become healthy in time, subject to the task's restart policy.
+	TaskLifecycleUnhealthyActionRestart = "restart"
+
+	// TaskLifecycleUnhealthyActionIgnore starts the main tasks even if a
+	// sidecar does not become healthy in time.
+	TaskLifecycleUnhealthyActionIgnore = "ignore"
+
+	// DefaultTaskLifecycleHealthyTimeout is how long main tasks wait for a
+	// sidecar to become healthy if no timeout is set.
+	DefaultTaskLifecycleHealthyTimeout = 5 * time.Minute
+)
+
 type TaskLifecycleConfig struct {
 	Hook    string
 	Sidecar bool
+
+	// WaitHealthy delays the main tasks until the checks of this prestart
+	// sidecar are passing, instead of only waiting for it to be running.
+	WaitHealthy bool
+
+	// HealthyTimeout is how long to wait for the sidecar to become healthy
+	// before applying UnhealthyAction.
+	HealthyTimeout time.Duration
+
+	// UnhealthyAction is the action taken when the sidecar does not become
+	// healthy within HealthyTimeout.
+	UnhealthyAction string
 }
 
 func (d *TaskLifecycleConfig) Copy() *TaskLifecycleConfig {
@@ -5917,9 +6465,53 @@ func (d *TaskLifecycleConfig) Validate() error {
 		return fmt.Errorf("invalid hook: %v", d.Hook)
 	}
 
+	if !d.WaitHealthy {
+		return nil
+	}
+
+	if d.Hook != TaskLifecycleHookPrestart || !d.Sidecar {
+		return fmt.Errorf("wait_healthy is only supported for prestart sidecar tasks")
+	}
+
+	if d.HealthyTimeout < 0 {
+		return fmt.Errorf("healthy_timeout must not be negative")
+	}
+
+	switch d.UnhealthyAction {
+	case "", TaskLifecycleUnhealthyActionFail,
+		TaskLifecycleUnhealthyActionRestart,
+		TaskLifecycleUnhealthyActionIgnore:
+	default:
+		return fmt.Errorf("invalid unhealthy_action: %v", d.UnhealthyAction)
+	}
+
 	return nil
 }
 
+// WaitsHealthy returns true if tasks after this one should wait for it to be
+// healthy before starting.
+func (d *TaskLifecycleConfig) WaitsHealthy() bool {
+	return d != nil && d.WaitHealthy
+}
+
+// GetHealthyTimeout returns the timeout for the sidecar to become healthy,
+// falling back to DefaultTaskLifecycleHealthyTimeout.
+func (d *TaskLifecycleConfig) GetHealthyTimeout() time.Duration {
+	if d == nil || d.HealthyTimeout == 0 {
+		return DefaultTaskLifecycleHealthyTimeout
+	}
+	return d.HealthyTimeout
+}
+
+// GetUnhealthyAction returns the action taken on a sidecar that failed to
+// become healthy, falling back to TaskLifecycleUnhealthyActionFail.
+func (d *TaskLifecycleConfig) GetUnhealthyAction() string {
+	if d == nil || d.UnhealthyAction == "" {
+		return TaskLifecycleUnhealthyActionFail
+	}
+	return d.UnhealthyAction
+}
+
 var (
 	// These default restart policies needs to be in sync with
 	// Canonicalize in api/tasks.go
@@ -5971,6 +6563,22 @@ const (
 	// restart policy.
 	RestartPolicyMinInterval = 5 * time.Second
 
+	// RestartPolicyDelayFunctionConstant waits the same delay between every
+	// restart attempt.
+	RestartPolicyDelayFunctionConstant = "constant"
+
+	// RestartPolicyDelayFunctionExponential multiplies the delay by the delay
+	// multiplier after every restart attempt within an interval.
+	RestartPolicyDelayFunctionExponential = "exponential"
+
+	// RestartPolicyDefaultDelayMultiplier is the multiplier used by the
+	// exponential delay function when none is set.
+	RestartPolicyDefaultDelayMultiplier = 2.0
+
+	// RestartPolicyDefaultJitter is the fraction of the delay added as random
+	// jitter when no jitter is set.
+	RestartPolicyDefaultJitter = 0.25
+
 	// ReasonWithinPolicy describes restart events that are within policy
 	ReasonWithinPolicy = "Restart within policy"
 )
@@ -6072,6 +6680,9 @@ type ScalingPolicy struct {
 	// Enabled indicates whether this policy has been enabled/disabled
 	Enabled bool
 
+	// Schedules set the count of the target task group on a timetable
+	Schedules []*ScalingSchedule
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -6123,6 +6734,12 @@ func (p *ScalingPolicy) Copy() *ScalingPolicy {
 	for k, v := range p.Target {
 		c.Target[k] = v
 	}
+	if p.Schedules != nil {
+		c.Schedules = make([]*ScalingSchedule, len(p.Schedules))
+		for i, schedule := range p.Schedules {
+			c.Schedules[i] = schedule.Copy()
+		}
+	}
 	return &c
 }
 
@@ -6154,6 +6771,20 @@ func (p *ScalingPolicy) Validate() error {
 			fmt.Errorf("minimum count must be specified and non-negative"))
 	}
 
+	if _, err := p.BuiltinPolicy(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+
+	if len(p.Schedules) > 0 && p.Type != ScalingPolicyTypeHorizontal {
+		mErr.Errors = append(mErr.Errors,
+			fmt.Errorf("schedules are only supported by horizontal scaling policies"))
+	}
+	for i, schedule := range p.Schedules {
+		if err := schedule.Validate(p.Min, p.Max); err != nil {
+			mErr.Errors = append(mErr.Errors, multierror.Prefix(err, fmt.Sprintf("schedule %d:", i+1)))
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
@@ -6272,6 +6903,31 @@ type RestartPolicy struct {
 
 	// RenderTemplates is flag to explicitly render all templates on task restart
 	RenderTemplates bool
+
+	// DelayFunction determines how the delay changes on subsequent restarts
+	// within an interval. Valid values are "constant" and "exponential". An
+	// empty value is treated as "constant".
+	DelayFunction string
+
+	// DelayMultiplier is the factor the delay is multiplied by after each
+	// restart when the delay function is "exponential".
+	DelayMultiplier float64
+
+	// MaxDelay is an upper bound on the delay when the delay function is
+	// "exponential". If unset the delay is bounded by the interval.
+	MaxDelay time.Duration
+
+	// Jitter is the fraction of the delay, between 0 and 1, added as a random
+	// jitter. If nil RestartPolicyDefaultJitter is used.
+	Jitter *float64
+
+	// Budget is the number of restarts allowed within the sliding
+	// BudgetWindow. Unlike Attempts, the window does not reset at fixed
+	// boundaries. Zero disables the budget.
+	Budget int
+
+	// BudgetWindow is the sliding window the Budget is counted over.
+	BudgetWindow time.Duration
 }
 
 func (r *RestartPolicy) Copy() *RestartPolicy {
@@ -6280,9 +6936,18 @@ func (r *RestartPolicy) Copy() *RestartPolicy {
 	}
 	nrp := new(RestartPolicy)
 	*nrp = *r
+	nrp.Jitter = pointer.Copy(r.Jitter)
 	return nrp
 }
 
+// GetJitter returns the jitter fraction, falling back to the default.
+func (r *RestartPolicy) GetJitter() float64 {
+	if r.Jitter == nil {
+		return RestartPolicyDefaultJitter
+	}
+	return *r.Jitter
+}
+
 func (r *RestartPolicy) Validate() error {
 	var mErr multierror.Error
 	switch r.Mode {
@@ -6303,6 +6968,30 @@ func (r *RestartPolicy) Validate() error {
 		_ = multierror.Append(&mErr,
 			fmt.Errorf("Nomad can't restart the TaskGroup %v times in an interval of %v with a delay of %v", r.Attempts, r.Interval, r.Delay))
 	}
+
+	switch r.DelayFunction {
+	case "", RestartPolicyDelayFunctionConstant:
+	case RestartPolicyDelayFunctionExponential:
+		if r.DelayMultiplier != 0 && r.DelayMultiplier < 1 {
+			_ = multierror.Append(&mErr, fmt.Errorf("Delay multiplier must be at least 1 (got %v)", r.DelayMultiplier))
+		}
+		if r.MaxDelay != 0 && r.MaxDelay < r.Delay {
+			_ = multierror.Append(&mErr, fmt.Errorf("Max delay %v can not be less than delay %v", r.MaxDelay, r.Delay))
+		}
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Unsupported restart delay function: %q", r.DelayFunction))
+	}
+
+	if r.Jitter != nil && (*r.Jitter < 0 || *r.Jitter > 1) {
+		_ = multierror.Append(&mErr, fmt.Errorf("Jitter must be between 0 and 1 (got %v)", *r.Jitter))
+	}
+
+	if r.Budget < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Budget can not be negative (got %d)", r.Budget))
+	}
+	if r.Budget > 0 && r.BudgetWindow <= 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Budget window must be set when budget is %d", r.Budget))
+	}
 	return mErr.ErrorOrNil()
 }
 
@@ -6318,6 +7007,80 @@ func NewRestartPolicy(jobType string) *RestartPolicy {
 	return nil
 }
 
+const (
+	// OOMPolicyModeRestart follows the restart policy when a task is OOM
+	// killed. This is the default.
+	OOMPolicyModeRestart = "restart"
+
+	// OOMPolicyModeFail fails the task when it is OOM killed, regardless of
+	// the restart policy.
+	OOMPolicyModeFail = "fail"
+
+	// OOMPolicyModeIgnore restarts the task when it is OOM killed without
+	// counting the restart against the restart policy.
+	OOMPolicyModeIgnore = "ignore"
+
+	// OOMPolicyModeAdjust raises the memory limit of the task before it is
+	// restarted according to the restart policy.
+	OOMPolicyModeAdjust = "adjust"
+)
+
+// OOMPolicy configures how the client handles a task being killed by the OOM
+// killer.
+type OOMPolicy struct {
+	// Mode is one of "restart", "fail", "ignore", or "adjust".
+	Mode string
+
+	// MemoryIncreaseMB is how much the memory limit is raised after each OOM
+	// kill when Mode is "adjust".
+	MemoryIncreaseMB int
+
+	// MemoryMaxMB is the upper bound the memory limit is raised to when Mode
+	// is "adjust".
+	MemoryMaxMB int
+}
+
+func (o *OOMPolicy) Copy() *OOMPolicy {
+	if o == nil {
+		return nil
+	}
+	no := new(OOMPolicy)
+	*no = *o
+	return no
+}
+
+// GetMode returns the mode of the policy, defaulting to "restart".
+func (o *OOMPolicy) GetMode() string {
+	if o == nil || o.Mode == "" {
+		return OOMPolicyModeRestart
+	}
+	return o.Mode
+}
+
+// Validate the OOM policy against the resources of its task.
+func (o *OOMPolicy) Validate(resources *Resources) error {
+	var mErr multierror.Error
+	switch o.Mode {
+	case "", OOMPolicyModeRestart, OOMPolicyModeFail, OOMPolicyModeIgnore:
+		if o.MemoryIncreaseMB != 0 || o.MemoryMaxMB != 0 {
+			_ = multierror.Append(&mErr, fmt.Errorf("Memory increase and max are only supported with mode %q", OOMPolicyModeAdjust))
+		}
+	case OOMPolicyModeAdjust:
+		if o.MemoryIncreaseMB <= 0 {
+			_ = multierror.Append(&mErr, fmt.Errorf("Memory increase must be greater than 0 with mode %q", OOMPolicyModeAdjust))
+		}
+		if resources != nil {
+			limit := max(resources.MemoryMB, resources.MemoryMaxMB)
+			if o.MemoryMaxMB < limit {
+				_ = multierror.Append(&mErr, fmt.Errorf("Memory max %d MB can not be less than the task memory limit %d MB", o.MemoryMaxMB, limit))
+			}
+		}
+	default:
+		_ = multierror.Append(&mErr, fmt.Errorf("Unsupported OOM policy mode: %q", o.Mode))
+	}
+	return mErr.ErrorOrNil()
+}
+
 const ReschedulePolicyMinInterval = 15 * time.Second
 const ReschedulePolicyMinDelay = 5 * time.Second
 
@@ -6587,6 +7350,49 @@ func (m *MigrateStrategy) Validate() error {
 	return mErr.ErrorOrNil()
 }
 
+// ArrayConfig configures a task group of a batch job as a job array. Each
+// allocation of the group runs one index of the array, exposed to its tasks as
+// NOMAD_ARRAY_INDEX.
+type ArrayConfig struct {
+	// Count is the number of indices in the array. The task group count is
+	// always set to this value.
+	Count int
+
+	// Concurrency is the maximum number of indices that may be running at
+	// once. Zero means all the indices may run at once.
+	Concurrency int
+}
+
+func (a *ArrayConfig) Copy() *ArrayConfig {
+	if a == nil {
+		return nil
+	}
+	na := new(ArrayConfig)
+	*na = *a
+	return na
+}
+
+func (a *ArrayConfig) Validate() error {
+	var mErr multierror.Error
+
+	if a.Count <= 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Count must be greater than zero: %d", a.Count))
+	}
+	if a.Concurrency < 0 {
+		_ = multierror.Append(&mErr, fmt.Errorf("Concurrency must be >= 0 but found %d", a.Concurrency))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Limit returns the maximum number of indices that may be running at once.
+func (a *ArrayConfig) Limit() int {
+	if a.Concurrency == 0 || a.Concurrency > a.Count {
+		return a.Count
+	}
+	return a.Concurrency
+}
+
 // TaskGroup is an atomic unit of placement. Each task group belongs to
 // a job and may contain any number of tasks. A task group support running
 // in many replicas using the same configuration..
@@ -6601,6 +7407,9 @@ type TaskGroup struct {
 	// Update is used to control the update strategy for this task group
 	Update *UpdateStrategy
 
+	// Array configures the task group of a batch job as a job array
+	Array *ArrayConfig
+
 	// Migrate is used to control the migration strategy for this task group
 	Migrate *MigrateStrategy
 
@@ -6647,6 +7456,10 @@ type TaskGroup struct {
 	// Consul configuration specific to this task group
 	Consul *Consul
 
+	// Ingress configures the proxy routing external traffic to the Nomad
+	// services of the cluster
+	Ingress *Ingress
+
 	// Services this group provides
 	Services []*Service
 
@@ -6681,6 +7494,8 @@ func (tg *TaskGroup) Copy() *TaskGroup {
 	ntg := new(TaskGroup)
 	*ntg = *tg
 	ntg.Update = ntg.Update.Copy()
+	ntg.Array = ntg.Array.Copy()
+	ntg.Ingress = ntg.Ingress.Copy()
 	ntg.Constraints = CopySliceConstraints(ntg.Constraints)
 	ntg.RestartPolicy = ntg.RestartPolicy.Copy()
 	ntg.Disconnect = ntg.Disconnect.Copy()
@@ -6783,6 +7598,11 @@ func (tg *TaskGroup) Canonicalize(job *Job) {
 		tg.Count = 1
 	}
 
+	// The count of a job array is the number of indices
+	if tg.Array != nil {
+		tg.Count = tg.Array.Count
+	}
+
 	if tg.Scaling != nil {
 		tg.Scaling.Canonicalize()
 	}
@@ -6934,6 +7754,11 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		if err := tg.EphemeralDisk.Validate(); err != nil {
 			mErr = multierror.Append(mErr, err)
 		}
+		for _, hook := range tg.EphemeralDisk.MigrateHooks {
+			if hook != nil && hook.Task != "" && tg.LookupTask(hook.Task) == nil {
+				mErr = multierror.Append(mErr, fmt.Errorf("Migrate hook references unknown task %q", hook.Task))
+			}
+		}
 	} else {
 		mErr = multierror.Append(mErr, fmt.Errorf("Task Group %v should have an ephemeral disk object", tg.Name))
 	}
@@ -6945,11 +7770,39 @@ func (tg *TaskGroup) Validate(j *Job) error {
 		default:
 			mErr = multierror.Append(mErr, fmt.Errorf("Job type %q does not allow update block", j.Type))
 		}
+		if len(u.CanaryConstraints) != 0 && j.Type != JobTypeSystem {
+			mErr = multierror.Append(mErr, fmt.Errorf("Job type %q does not allow update canary constraints", j.Type))
+		}
 		if err := u.Validate(); err != nil {
 			mErr = multierror.Append(mErr, err)
 		}
 	}
 
+	// Validate the job array
+	if a := tg.Array; a != nil {
+		if j.Type != JobTypeBatch {
+			mErr = multierror.Append(mErr, fmt.Errorf("Job type %q does not allow array block", j.Type))
+		}
+		if tg.Scaling != nil {
+			mErr = multierror.Append(mErr, errors.New("Task group with an array block cannot have a scaling block"))
+		}
+		if tg.Count != a.Count {
+			mErr = multierror.Append(mErr, fmt.Errorf("Task group count (%d) must equal the array count (%d)", tg.Count, a.Count))
+		}
+		if err := a.Validate(); err != nil {
+			outer := fmt.Errorf("Task group array validation failed: %v", err)
+			mErr = multierror.Append(mErr, outer)
+		}
+	}
+
+	// Validate the ingress
+	if tg.Ingress != nil {
+		if err := tg.Ingress.Validate(tg); err != nil {
+			outer := fmt.Errorf("Task group ingress validation failed: %v", err)
+			mErr = multierror.Append(mErr, outer)
+		}
+	}
+
 	// Validate the migration strategy
 	switch j.Type {
 	case JobTypeService:
@@ -6986,10 +7839,7 @@ func (tg *TaskGroup) Validate(j *Job) error {
 	}
 
 	// Validate the volume requests
-	var canaries int
-	if tg.Update != nil {
-		canaries = tg.Update.Canary
-	}
+	canaries := tg.Update.DesiredCanaries(tg.Count)
 	for name, volReq := range tg.Volumes {
 		if err := volReq.Validate(j.Type, tg.Count, canaries); err != nil {
 			mErr = multierror.Append(mErr, fmt.Errorf(
@@ -7086,6 +7936,12 @@ func (tg *TaskGroup) validateNetworks() error {
 				mErr.Errors = append(mErr.Errors, errors.New("Hostname is not a valid DNS name"))
 			}
 		}
+
+		for i, iface := range net.Interfaces {
+			if iface == "" {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("Network interface %d cannot be empty", i+1))
+			}
+		}
 	}
 
 	// Check for duplicate tasks or port labels, and no duplicated static ports
@@ -7317,7 +8173,9 @@ func (tg *TaskGroup) Warnings(j *Job) error {
 	// Validate the update strategy
 	if u := tg.Update; u != nil {
 		// Check the counts are appropriate
-		if tg.Count > 1 && u.MaxParallel > tg.Count && !(j.IsMultiregion() && tg.Count == 0) {
+		// The count of system jobs is per node, so it doesn't bound the
+		// number of allocations replaced at once.
+		if j.Type != JobTypeSystem && tg.Count > 1 && u.MaxParallel > tg.Count && !(j.IsMultiregion() && tg.Count == 0) {
 			mErr.Errors = append(mErr.Errors,
 				fmt.Errorf("Update max parallel count is greater than task group count (%d > %d). "+
 					"A destructive change would result in the simultaneous replacement of all allocations.", u.MaxParallel, tg.Count))
@@ -7508,11 +8366,80 @@ const (
 	DefaultKillTimeout = 5 * time.Second
 )
 
+const (
+	// LogBackpressureBlock blocks writes to the task's stdout and stderr
+	// until logmon has written the output to disk. This is the default.
+	LogBackpressureBlock = "block"
+
+	// LogBackpressureDrop drops the task's output when logmon can't keep up
+	// with the task, and counts the dropped lines.
+	LogBackpressureDrop = "drop"
+
+	// LogBackpressureSpill writes the task's output to the client's log spill
+	// directory when logmon can't keep up with the task.
+	LogBackpressureSpill = "spill"
+
+	// LogCompressionGzip compresses rotated log files with gzip.
+	LogCompressionGzip = "gzip"
+
+	// LogCompressionZstd compresses rotated log files with zstd.
+	LogCompressionZstd = "zstd"
+)
+
 // LogConfig provides configuration for log rotation
 type LogConfig struct {
 	MaxFiles      int
 	MaxFileSizeMB int
 	Disabled      bool
+
+	// Backpressure is the policy applied when logmon can't write the task's
+	// output to disk as fast as the task writes it. Defaults to "block".
+	Backpressure string
+
+	// RotateInterval rotates the current log file once it has been open for
+	// the interval, in addition to rotating it by size. Zero disables time
+	// based rotation.
+	RotateInterval time.Duration
+
+	// Compression is the algorithm used to compress rotated log files,
+	// either "gzip" or "zstd". Rotated files are left uncompressed if empty.
+	Compression string
+
+	// Retention removes rotated log files older than the duration, in
+	// addition to keeping at most MaxFiles. Zero disables retention.
+	Retention time.Duration
+
+	// Sinks are the log sink plugins the task's output is shipped to, in
+	// addition to being written to disk. The log sinks configured on the
+	// client are used if empty.
+	Sinks []*LogSink
+}
+
+// LogSink is a log sink plugin that the output of a task is shipped to.
+type LogSink struct {
+	// Name is the name of the log sink plugin.
+	Name string
+
+	// Options are passed to the plugin along with the task's output, such as
+	// the labels or index of the task's logs.
+	Options map[string]string
+}
+
+func (s *LogSink) Equal(o *LogSink) bool {
+	if s == nil || o == nil {
+		return s == o
+	}
+	return s.Name == o.Name && maps.Equal(s.Options, o.Options)
+}
+
+func (s *LogSink) Copy() *LogSink {
+	if s == nil {
+		return nil
+	}
+	return &LogSink{
+		Name:    s.Name,
+		Options: maps.Clone(s.Options),
+	}
 }
 
 func (l *LogConfig) Equal(o *LogConfig) bool {
@@ -7532,6 +8459,26 @@ func (l *LogConfig) Equal(o *LogConfig) bool {
 		return false
 	}
 
+	if l.Backpressure != o.Backpressure {
+		return false
+	}
+
+	if l.RotateInterval != o.RotateInterval {
+		return false
+	}
+
+	if l.Compression != o.Compression {
+		return false
+	}
+
+	if l.Retention != o.Retention {
+		return false
+	}
+
+	if !slices.EqualFunc(l.Sinks, o.Sinks, (*LogSink).Equal) {
+		return false
+	}
+
 	return true
 }
 
@@ -7540,9 +8487,14 @@ func (l *LogConfig) Copy() *LogConfig {
 		return nil
 	}
 	return &LogConfig{
-		MaxFiles:      l.MaxFiles,
-		MaxFileSizeMB: l.MaxFileSizeMB,
-		Disabled:      l.Disabled,
+		MaxFiles:       l.MaxFiles,
+		MaxFileSizeMB:  l.MaxFileSizeMB,
+		Disabled:       l.Disabled,
+		Backpressure:   l.Backpressure,
+		RotateInterval: l.RotateInterval,
+		Compression:    l.Compression,
+		Retention:      l.Retention,
+		Sinks:          helper.CopySlice(l.Sinks),
 	}
 }
 
@@ -7566,6 +8518,35 @@ func (l *LogConfig) Validate(disk *EphemeralDisk) error {
 	if l.MaxFileSizeMB < 1 {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum file size is 1MB; got %d", l.MaxFileSizeMB))
 	}
+	switch l.Backpressure {
+	case "", LogBackpressureBlock, LogBackpressureDrop, LogBackpressureSpill:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("backpressure must be one of %q, %q, or %q; got %q",
+			LogBackpressureBlock, LogBackpressureDrop, LogBackpressureSpill, l.Backpressure))
+	}
+	if l.RotateInterval < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("rotate interval must not be negative; got %v", l.RotateInterval))
+	}
+	switch l.Compression {
+	case "", LogCompressionGzip, LogCompressionZstd:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("compression must be one of %q or %q; got %q",
+			LogCompressionGzip, LogCompressionZstd, l.Compression))
+	}
+	if l.Retention < 0 {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("retention must not be negative; got %v", l.Retention))
+	}
+	sinks := make(map[string]struct{}, len(l.Sinks))
+	for i, sink := range l.Sinks {
+		if sink == nil || sink.Name == "" {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("log sink %d has an empty name", i+1))
+			continue
+		}
+		if _, ok := sinks[sink.Name]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("duplicate log sink %q", sink.Name))
+		}
+		sinks[sink.Name] = struct{}{}
+	}
 	if disk != nil {
 		logUsage := (l.MaxFiles * l.MaxFileSizeMB)
 		if disk.SizeMB <= logUsage {
@@ -7595,6 +8576,12 @@ type Task struct {
 	// Map of environment variables to be used by the driver
 	Env map[string]string
 
+	// Sealed is a map of values that are encrypted with the cluster keyring
+	// when the job is submitted. They are only decrypted by the client, which
+	// writes each value to a file named after its key in the task's secrets
+	// directory.
+	Sealed map[string]string
+
 	// List of service definitions exposed by the Task
 	Services []*Service
 
@@ -7623,6 +8610,10 @@ type Task struct {
 	// RestartPolicy of a TaskGroup
 	RestartPolicy *RestartPolicy
 
+	// OOMPolicy controls how the client handles the task being killed by the
+	// OOM killer. If nil the restart policy is followed.
+	OOMPolicy *OOMPolicy
+
 	// DispatchPayload configures how the task retrieves its input from a dispatch
 	DispatchPayload *DispatchPayloadConfig
 
@@ -7715,6 +8706,16 @@ func (t *Task) IsPoststop() bool {
 		t.Lifecycle.Hook == TaskLifecycleHookPoststop
 }
 
+// hasServiceChecks returns true if any of the task's services define a check.
+func (t *Task) hasServiceChecks() bool {
+	for _, service := range t.Services {
+		if len(service.Checks) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *Task) GetIdentity(name string) *WorkloadIdentity {
 	for _, wid := range t.Identities {
 		if wid.Name == name {
@@ -7750,6 +8751,7 @@ func (t *Task) Copy() *Task {
 	nt := new(Task)
 	*nt = *t
 	nt.Env = maps.Clone(nt.Env)
+	nt.Sealed = maps.Clone(nt.Sealed)
 
 	if t.Services != nil {
 		services := make([]*Service, len(nt.Services))
@@ -7771,6 +8773,7 @@ func (t *Task) Copy() *Task {
 	nt.Meta = maps.Clone(nt.Meta)
 	nt.DispatchPayload = nt.DispatchPayload.Copy()
 	nt.Lifecycle = nt.Lifecycle.Copy()
+	nt.OOMPolicy = nt.OOMPolicy.Copy()
 	nt.Identity = nt.Identity.Copy()
 	nt.Identities = helper.CopySlice(nt.Identities)
 	nt.Actions = helper.CopySlice(nt.Actions)
@@ -7954,12 +8957,23 @@ func (t *Task) Validate(jobType string, tg *TaskGroup) error {
 		mErr.Errors = append(mErr.Errors, err)
 	}
 
+	// Validate sealed values.
+	for name := range t.Sealed {
+		if !validSealedName.MatchString(name) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Sealed value name %q is invalid: must only contain alphanumeric characters, underscores, dashes, and periods", name))
+		}
+	}
+
 	// Validate artifacts.
 	for idx, artifact := range t.Artifacts {
 		if err := artifact.Validate(); err != nil {
 			outer := fmt.Errorf("Artifact %d validation failed: %v", idx+1, err)
 			mErr.Errors = append(mErr.Errors, outer)
 		}
+		if artifact.GetterIdentity != "" && t.GetIdentity(artifact.GetterIdentity) == nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Artifact %d identity %q not found in task identities",
+				idx+1, artifact.GetterIdentity))
+		}
 	}
 
 	// Validate Vault.
@@ -8014,6 +9028,9 @@ func (t *Task) Validate(jobType string, tg *TaskGroup) error {
 			mErr.Errors = append(mErr.Errors, fmt.Errorf("Lifecycle validation failed: %v", err))
 		}
 
+		if t.Lifecycle.WaitsHealthy() && !t.hasServiceChecks() {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Lifecycle validation failed: wait_healthy requires a service check"))
+		}
 	}
 
 	// Validation for TaskKind field which is used for Consul Connect integration
@@ -8062,6 +9079,13 @@ func (t *Task) Validate(jobType string, tg *TaskGroup) error {
 		// TODO: Investigate validation of the PluginMountDir. Not much we can do apart from check IsAbs until after we understand its execution environment though :(
 	}
 
+	// Validate the OOM policy
+	if t.OOMPolicy != nil {
+		if err := t.OOMPolicy.Validate(t.Resources); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("OOM policy is invalid: %w", err))
+		}
+	}
+
 	// Validate Identity/Identities
 	for _, wid := range t.Identities {
 		// Task.Canonicalize should move the default identity out of the Identities
@@ -8941,6 +9965,18 @@ const (
 	// restarted because it has exceeded its restart policy.
 	TaskNotRestarting = "Not Restarting"
 
+	// TaskOOMKilled indicates that a process of the task was killed by the
+	// OOM killer.
+	TaskOOMKilled = "OOM Killed"
+
+	// TaskResized indicates that the cpu and memory limits of the running
+	// task have been updated in-place.
+	TaskResized = "Resized"
+
+	// TaskDeviceVanished indicates that a device allocated to the task was
+	// removed from the node while the task was running.
+	TaskDeviceVanished = "Device Vanished"
+
 	// TaskRestartSignal indicates that the task has been signaled to be
 	// restarted
 	TaskRestartSignal = "Restart Signaled"
@@ -8999,6 +10035,14 @@ const (
 	// TaskPluginHealthy indicates that a plugin managed by Nomad became healthy
 	TaskPluginHealthy = "Plugin became healthy"
 
+	// TaskSidecarHealthy indicates that a prestart sidecar waiting to be
+	// healthy passed its checks and the main tasks may start.
+	TaskSidecarHealthy = "Sidecar became healthy"
+
+	// TaskSidecarUnhealthy indicates that a prestart sidecar waiting to be
+	// healthy did not pass its checks before its healthy timeout.
+	TaskSidecarUnhealthy = "Sidecar did not become healthy"
+
 	// TaskClientReconnected indicates that the client running the task reconnected.
 	TaskClientReconnected = "Reconnected"
 
@@ -9189,6 +10233,24 @@ func (e *TaskEvent) PopulateEventDisplayMessage() {
 		} else {
 			desc = "Task exceeded restart policy"
 		}
+	case TaskOOMKilled:
+		if e.Message != "" {
+			desc = e.Message
+		} else {
+			desc = "Task was killed by the OOM killer"
+		}
+	case TaskDeviceVanished:
+		if e.Message != "" {
+			desc = e.Message
+		} else {
+			desc = "Device allocated to the task was removed from the node"
+		}
+	case TaskResized:
+		if e.Message != "" {
+			desc = e.Message
+		} else {
+			desc = "Task resources updated"
+		}
 	case TaskSiblingFailed:
 		if e.FailedSibling != "" {
 			desc = fmt.Sprintf("Task's sibling %q failed", e.FailedSibling)
@@ -9427,11 +10489,39 @@ func (e *TaskEvent) SetDriverMessage(m string) *TaskEvent {
 	return e
 }
 
+// SetMissingDependencies is used to store the template dependencies a task is
+// waiting on
+func (e *TaskEvent) SetMissingDependencies(deps string) *TaskEvent {
+	e.Details["missing_dependencies"] = deps
+	return e
+}
+
 func (e *TaskEvent) SetOOMKilled(oom bool) *TaskEvent {
 	e.Details["oom_killed"] = strconv.FormatBool(oom)
 	return e
 }
 
+// SetOOMKilledProcess is used to store the process most likely killed by the
+// OOM killer and its resident set size in bytes.
+func (e *TaskEvent) SetOOMKilledProcess(pid string, rss uint64) *TaskEvent {
+	e.Details["oom_pid"] = pid
+	e.Details["oom_rss_bytes"] = strconv.FormatUint(rss, 10)
+	return e
+}
+
+// SetOOMKills is used to store the number of processes of the task killed by
+// the OOM killer.
+func (e *TaskEvent) SetOOMKills(kills uint64) *TaskEvent {
+	e.Details["oom_kills"] = strconv.FormatUint(kills, 10)
+	return e
+}
+
+// SetMemoryLimit is used to store the memory limit of the task in MB.
+func (e *TaskEvent) SetMemoryLimit(mb int64) *TaskEvent {
+	e.Details["memory_limit_mb"] = strconv.FormatInt(mb, 10)
+	return e
+}
+
 // TaskArtifact is an artifact to download before running the task.
 type TaskArtifact struct {
 	// GetterSource is the source to download an artifact using go-getter
@@ -9456,6 +10546,11 @@ type TaskArtifact struct {
 	// RelativeDest is the download destination given relative to the task's
 	// directory.
 	RelativeDest string
+
+	// GetterIdentity is the name of the workload identity of the task that
+	// the client's credential helper exchanges for the credentials used to
+	// download the artifact.
+	GetterIdentity string
 }
 
 func (ta *TaskArtifact) Equal(o *TaskArtifact) bool {
@@ -9475,6 +10570,8 @@ func (ta *TaskArtifact) Equal(o *TaskArtifact) bool {
 		return false
 	case ta.RelativeDest != o.RelativeDest:
 		return false
+	case ta.GetterIdentity != o.GetterIdentity:
+		return false
 	}
 	return true
 }
@@ -9490,6 +10587,7 @@ func (ta *TaskArtifact) Copy() *TaskArtifact {
 		GetterMode:     ta.GetterMode,
 		GetterInsecure: ta.GetterInsecure,
 		RelativeDest:   ta.RelativeDest,
+		GetterIdentity: ta.GetterIdentity,
 	}
 }
 
@@ -9531,6 +10629,11 @@ func (ta *TaskArtifact) Hash() string {
 	_, _ = h.Write([]byte(ta.GetterMode))
 	_, _ = h.Write([]byte(strconv.FormatBool(ta.GetterInsecure)))
 	_, _ = h.Write([]byte(ta.RelativeDest))
+
+	// only hashed if set so the hash of existing artifacts is unchanged
+	if ta.GetterIdentity != "" {
+		_, _ = h.Write([]byte(ta.GetterIdentity))
+	}
 	return base64.RawStdEncoding.EncodeToString(h.Sum(nil))
 }
 
@@ -9995,6 +11098,10 @@ type EphemeralDisk struct {
 	// Migrate determines if Nomad client should migrate the allocation dir for
 	// sticky allocations
 	Migrate bool
+
+	// MigrateHooks are commands run inside the tasks of the group so the
+	// data of a sticky or migrated disk is application consistent
+	MigrateHooks []*MigrateHook
 }
 
 // DefaultEphemeralDisk returns a EphemeralDisk with default configurations
@@ -10015,25 +11122,151 @@ func (d *EphemeralDisk) Equal(o *EphemeralDisk) bool {
 		return false
 	case d.Migrate != o.Migrate:
 		return false
+	case !slices.EqualFunc(d.MigrateHooks, o.MigrateHooks, (*MigrateHook).Equal):
+		return false
 	}
 	return true
 }
 
 // Validate validates EphemeralDisk
 func (d *EphemeralDisk) Validate() error {
+	var mErr multierror.Error
 	if d.SizeMB < 10 {
-		return fmt.Errorf("minimum DiskMB value is 10; got %d", d.SizeMB)
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("minimum DiskMB value is 10; got %d", d.SizeMB))
 	}
-	return nil
+
+	if len(d.MigrateHooks) > 0 && !d.Sticky && !d.Migrate {
+		mErr.Errors = append(mErr.Errors, errors.New("migrate hooks require a sticky or migrated disk"))
+	}
+
+	seen := make(map[string]bool, len(d.MigrateHooks))
+	for i, hook := range d.MigrateHooks {
+		if err := hook.Validate(); err != nil {
+			mErr.Errors = append(mErr.Errors, multierror.Prefix(err, fmt.Sprintf("migrate hook %d:", i+1)))
+			continue
+		}
+		key := hook.Stage + "/" + hook.Task
+		if seen[key] {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("duplicate %s migrate hook for task %q", hook.Stage, hook.Task))
+		}
+		seen[key] = true
+	}
+
+	return mErr.ErrorOrNil()
 }
 
 // Copy copies the EphemeralDisk struct and returns a new one
 func (d *EphemeralDisk) Copy() *EphemeralDisk {
 	ld := new(EphemeralDisk)
 	*ld = *d
+	if d.MigrateHooks != nil {
+		ld.MigrateHooks = make([]*MigrateHook, len(d.MigrateHooks))
+		for i, hook := range d.MigrateHooks {
+			ld.MigrateHooks[i] = hook.Copy()
+		}
+	}
 	return ld
 }
 
+// MigrateHooksForTask returns the migrate hooks of the given stage run inside
+// the named task.
+func (d *EphemeralDisk) MigrateHooksForTask(stage, task string) []*MigrateHook {
+	if d == nil {
+		return nil
+	}
+	var hooks []*MigrateHook
+	for _, hook := range d.MigrateHooks {
+		if hook.Stage == stage && hook.Task == task {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks
+}
+
+const (
+	// MigrateHookStagePreMigrate hooks are run inside a task before it is
+	// stopped by the servers, to quiesce and flush the application state
+	// written to the disk that is migrated to the replacement allocation.
+	MigrateHookStagePreMigrate = "pre_migrate"
+
+	// MigrateHookStagePostRestore hooks are run inside a task once it first
+	// starts in an allocation that replaced a previous allocation, after the
+	// data of the previous allocation was restored.
+	MigrateHookStagePostRestore = "post_restore"
+)
+
+// MigrateHook is a command run inside a task to capture an application
+// consistent state of the ephemeral disk when it is migrated.
+type MigrateHook struct {
+	// Stage is when the hook is run
+	Stage string
+
+	// Task is the name of the task the hook is run inside
+	Task string
+
+	// Command is the command to run
+	Command string
+
+	// Args is a slice of arguments passed to the command
+	Args []string
+
+	// Timeout is the amount of time we wait for the command to finish
+	Timeout time.Duration
+}
+
+func (h *MigrateHook) Equal(o *MigrateHook) bool {
+	if h == nil || o == nil {
+		return h == o
+	}
+	switch {
+	case h.Stage != o.Stage:
+		return false
+	case h.Task != o.Task:
+		return false
+	case h.Command != o.Command:
+		return false
+	case !slices.Equal(h.Args, o.Args):
+		return false
+	case h.Timeout != o.Timeout:
+		return false
+	}
+	return true
+}
+
+func (h *MigrateHook) Copy() *MigrateHook {
+	if h == nil {
+		return nil
+	}
+	nh := *h
+	nh.Args = slices.Clone(h.Args)
+	return &nh
+}
+
+// Validate makes sure all the required fields of MigrateHook are present
+func (h *MigrateHook) Validate() error {
+	if h == nil {
+		return errors.New("empty migrate hook")
+	}
+
+	var mErr multierror.Error
+	switch h.Stage {
+	case MigrateHookStagePreMigrate, MigrateHookStagePostRestore:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("stage must be %q or %q; got %q",
+			MigrateHookStagePreMigrate, MigrateHookStagePostRestore, h.Stage))
+	}
+	if h.Task == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("task must be specified"))
+	}
+	if h.Command == "" {
+		mErr.Errors = append(mErr.Errors, errors.New("command must be specified"))
+	}
+	if h.Timeout <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("timeout must be greater than zero"))
+	}
+	return mErr.ErrorOrNil()
+}
+
 var (
 	// VaultUnrecoverableError matches unrecoverable errors returned by a Vault
 	// server
@@ -10750,6 +11983,9 @@ type Allocation struct {
 	// NetworkStatus captures networking details of an allocation known at runtime
 	NetworkStatus *AllocNetworkStatus
 
+	// PendingReason captures the stage a pending allocation is blocked on
+	PendingReason *AllocPendingReason
+
 	// FollowupEvalID captures a follow up evaluation created to handle a failed allocation
 	// that can be rescheduled in the future
 	FollowupEvalID string
@@ -10770,6 +12006,11 @@ type Allocation struct {
 	// SigningKeyID is the key used to sign the SignedIdentities field.
 	SigningKeyID string
 
+	// TraceParent is the W3C traceparent of the plan that placed the
+	// allocation, when tracing is enabled. The client continues this trace
+	// while it starts the allocation.
+	TraceParent string
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -10944,6 +12185,7 @@ func (a *Allocation) copyImpl(job bool) *Allocation {
 	}
 
 	na.RescheduleTracker = a.RescheduleTracker.Copy()
+	na.PendingReason = a.PendingReason.Copy()
 	na.PreemptedAllocations = slices.Clone(a.PreemptedAllocations)
 	return na
 }
@@ -11327,6 +12569,22 @@ func (a *Allocation) LookupTask(name string) *Task {
 	return tg.LookupTask(name)
 }
 
+// TaskForPath returns the name of the task whose directory contains the path
+// relative to the allocation directory, or an empty string if the path is
+// outside of the task directories, such as in the shared alloc directory.
+// The shared alloc directory mounted into a task directory is not considered
+// part of the task directory.
+func (a *Allocation) TaskForPath(p string) string {
+	name, rest, _ := strings.Cut(strings.TrimPrefix(path.Clean("/"+p), "/"), "/")
+	if name == "" || a.LookupTask(name) == nil {
+		return ""
+	}
+	if dir, _, _ := strings.Cut(rest, "/"); dir == "alloc" {
+		return ""
+	}
+	return name
+}
+
 // Stub returns a list stub for the allocation
 func (a *Allocation) Stub(fields *AllocStubFields) *AllocListStub {
 	s := &AllocListStub{
@@ -11350,6 +12608,7 @@ func (a *Allocation) Stub(fields *AllocStubFields) *AllocListStub {
 		FollowupEvalID:        a.FollowupEvalID,
 		NextAllocation:        a.NextAllocation,
 		RescheduleTracker:     a.RescheduleTracker,
+		PendingReason:         a.PendingReason,
 		PreemptedAllocations:  a.PreemptedAllocations,
 		PreemptedByAllocation: a.PreemptedByAllocation,
 		CreateIndex:           a.CreateIndex,
@@ -11475,6 +12734,14 @@ type IdentityClaims struct {
 	VaultNamespace  string `json:"vault_namespace,omitempty"`
 	VaultRole       string `json:"vault_role,omitempty"`
 
+	// Scope is set on child identities and restricts them to a subset of the
+	// capabilities of the workload they were created from.
+	Scope *IdentityScope `json:"nomad_scope,omitempty"`
+
+	// ExtraClaims are the interpolated extra claims of the workload identity.
+	// They are added to the top level of the signed JWT.
+	ExtraClaims map[string]string `json:"-"`
+
 	jwt.Claims
 }
 
@@ -11621,6 +12888,7 @@ type AllocListStub struct {
 	FollowupEvalID        string
 	NextAllocation        string
 	RescheduleTracker     *RescheduleTracker
+	PendingReason         *AllocPendingReason
 	PreemptedAllocations  []string
 	PreemptedByAllocation string
 	CreateIndex           uint64
@@ -11906,6 +13174,72 @@ func (s *NodeScoreMeta) Data() interface{} {
 	return s
 }
 
+const (
+	// AllocPendingStageCSIVolumes is the stage of claiming and mounting the
+	// CSI volumes of an allocation
+	AllocPendingStageCSIVolumes = "csi-volumes"
+
+	// AllocPendingStageArtifacts is the stage of downloading the artifacts of
+	// a task
+	AllocPendingStageArtifacts = "artifacts"
+
+	// AllocPendingStageTemplate is the stage of waiting for the dependencies
+	// of a task's templates to render them
+	AllocPendingStageTemplate = "template"
+
+	// AllocPendingStageImagePull is the stage of pulling the image of a task
+	AllocPendingStageImagePull = "image-pull"
+)
+
+// AllocPendingReason captures the stage a pending allocation is blocked on.
+type AllocPendingReason struct {
+	// Stage is the stage the allocation is blocked on
+	Stage string
+
+	// Task is the task blocked on the stage, or empty if the stage applies to
+	// the whole allocation
+	Task string
+
+	// Message is a human readable description of the stage
+	Message string
+
+	// Details are stage specific details, such as the image being pulled or
+	// the missing template dependencies
+	Details map[string]string
+
+	// Time is when the stage was last reported, in Unix nanoseconds
+	Time int64
+}
+
+func (r *AllocPendingReason) Copy() *AllocPendingReason {
+	if r == nil {
+		return nil
+	}
+	nr := new(AllocPendingReason)
+	*nr = *r
+	nr.Details = maps.Clone(r.Details)
+	return nr
+}
+
+func (r *AllocPendingReason) Equal(o *AllocPendingReason) bool {
+	if r == nil || o == nil {
+		return r == o
+	}
+	switch {
+	case r.Stage != o.Stage:
+		return false
+	case r.Task != o.Task:
+		return false
+	case r.Message != o.Message:
+		return false
+	case !maps.Equal(r.Details, o.Details):
+		return false
+	case r.Time != o.Time:
+		return false
+	}
+	return true
+}
+
 // AllocNetworkStatus captures the status of an allocation's network during runtime.
 // Depending on the network mode, an allocation's address may need to be known to other
 // systems in Nomad such as service registration.
@@ -12081,6 +13415,7 @@ const (
 	EvalTriggerScaling              = "job-scaling"
 	EvalTriggerMaxDisconnectTimeout = "max-disconnect-timeout"
 	EvalTriggerReconnect            = "reconnect"
+	EvalTriggerArrayProgress        = "array-progress"
 )
 
 const (
@@ -12139,6 +13474,11 @@ const (
 	// active key
 	CoreJobVariablesRekey = "variables-rekey"
 
+	// CoreJobVariablesExpiredGC is used for the garbage collection of
+	// variables whose TTL has expired. We periodically scan for expired
+	// variables and delete them.
+	CoreJobVariablesExpiredGC = "variables-expired-gc"
+
 	// CoreJobForceGC is used to force garbage collection of all GCable objects.
 	CoreJobForceGC = "force-gc"
 )
@@ -12249,6 +13589,10 @@ type Evaluation struct {
 	// evaluation was processed. The map is keyed by Task Group names.
 	QueuedAllocations map[string]int
 
+	// PriorityBump records an operator raising the priority of the
+	// evaluation while it was pending or blocked.
+	PriorityBump *EvalPriorityBump
+
 	// LeaderACL provides the ACL token to when issuing RPCs back to the
 	// leader. This will be a valid management token as long as the leader is
 	// active. This should not ever be exposed via the API.
@@ -12262,6 +13606,11 @@ type Evaluation struct {
 	// the SnapshotIndex being less than the CreateIndex.
 	SnapshotIndex uint64
 
+	// TraceParent is the W3C traceparent of the span that created the
+	// evaluation, when tracing is enabled. Scheduling the evaluation and any
+	// follow up evaluations continue this trace.
+	TraceParent string
+
 	// Raft Indexes
 	CreateIndex uint64
 	ModifyIndex uint64
@@ -12409,9 +13758,32 @@ func (e *Evaluation) Copy() *Evaluation {
 		ne.QueuedAllocations = queuedAllocations
 	}
 
+	ne.PriorityBump = e.PriorityBump.Copy()
+
 	return ne
 }
 
+// EvalPriorityBump records an operator raising the priority of an evaluation.
+type EvalPriorityBump struct {
+	// PreviousPriority is the priority of the evaluation before it was
+	// bumped.
+	PreviousPriority int
+
+	// BumpedBy is the identity of the operator that bumped the priority.
+	BumpedBy string
+
+	// BumpTime is the time the priority was bumped.
+	BumpTime int64
+}
+
+func (b *EvalPriorityBump) Copy() *EvalPriorityBump {
+	if b == nil {
+		return nil
+	}
+	nb := *b
+	return &nb
+}
+
 // ShouldEnqueue checks if a given evaluation should be enqueued into the
 // eval_broker
 func (e *Evaluation) ShouldEnqueue() bool {
@@ -12469,6 +13841,7 @@ func (e *Evaluation) NextRollingEval(wait time.Duration) *Evaluation {
 		Status:         EvalStatusPending,
 		Wait:           wait,
 		PreviousEval:   e.ID,
+		TraceParent:    e.TraceParent,
 		CreateTime:     now,
 		ModifyTime:     now,
 	}
@@ -12495,6 +13868,7 @@ func (e *Evaluation) CreateBlockedEval(classEligibility map[string]bool,
 		ClassEligibility:     classEligibility,
 		EscapedComputedClass: escaped,
 		QuotaLimitReached:    quotaReached,
+		TraceParent:          e.TraceParent,
 		CreateTime:           now,
 		ModifyTime:           now,
 	}
@@ -12517,6 +13891,7 @@ func (e *Evaluation) CreateFailedFollowUpEval(wait time.Duration) *Evaluation {
 		Status:         EvalStatusPending,
 		Wait:           wait,
 		PreviousEval:   e.ID,
+		TraceParent:    e.TraceParent,
 		CreateTime:     now,
 		ModifyTime:     now,
 	}
@@ -12595,6 +13970,10 @@ type Plan struct {
 	// Plan. The leader will wait to evaluate the plan until its StateStore
 	// has reached at least this index.
 	SnapshotIndex uint64
+
+	// TraceParent is the W3C traceparent of the scheduler span that
+	// submitted the plan, when tracing is enabled.
+	TraceParent string
 }
 
 func (p *Plan) GoString() string {
@@ -12860,6 +14239,10 @@ type PlanAnnotations struct {
 
 	// PreemptedAllocs is the set of allocations to be preempted to make the placement successful.
 	PreemptedAllocs []*AllocListStub
+
+	// PrefetchNodes are the IDs of the nodes that pull the images and
+	// artifacts of the job when it is registered, if the job enables prefetch.
+	PrefetchNodes []string
 }
 
 // DesiredUpdates is the set of changes the scheduler would like to make given
@@ -13201,6 +14584,37 @@ type ACLToken struct {
 	// creation. This is a string version of a time.Duration like "2m".
 	ExpirationTTL time.Duration
 
+	// NotBefore is the point before which the token can't be used. Like
+	// ExpirationTime, it should always use UTC and is a pointer, so we can
+	// store nil.
+	NotBefore *time.Time
+
+	// MaxUses is the number of requests the token can authenticate before it
+	// is exhausted. Zero means the number of uses is unlimited. Only local
+	// tokens can have a maximum number of uses.
+	MaxUses uint64
+
+	// Uses is the number of requests the token has authenticated. It is only
+	// tracked for tokens with MaxUses set and is managed by the servers.
+	Uses uint64
+
+	// Pending marks a token waiting for approval. Pending tokens can't be
+	// used until approved by an operator other than the one who created
+	// them.
+	Pending bool
+
+	// CreatedBy is the accessor ID of the token used to create the token,
+	// and ApprovedBy the accessor ID of the token used to approve a pending
+	// token. Following CreatedBy gives the lineage of a token.
+	CreatedBy  string
+	ApprovedBy string
+
+	// AuthIdentity identifies the operator the token was issued to, as the
+	// auth method name and the subject of the login, separated by a slash.
+	// Tokens created with a token inherit its identity, so an operator can't
+	// approve their own pending tokens with a token they created.
+	AuthIdentity string
+
 	CreateIndex uint64
 	ModifyIndex uint64
 }
@@ -13276,6 +14690,10 @@ type ACLTokenListStub struct {
 	Hash           []byte
 	CreateTime     time.Time
 	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	MaxUses        uint64
+	Uses           uint64
+	Pending        bool
 	CreateIndex    uint64
 	ModifyIndex    uint64
 }
@@ -13309,6 +14727,19 @@ func (a *ACLToken) SetHash() []byte {
 		_, _ = hash.Write([]byte(roleLink.ID))
 	}
 
+	// Hash the fields restricting when the token can be used, so approving a
+	// pending token changes its hash and is replicated to the other regions.
+	if a.NotBefore != nil {
+		_, _ = hash.Write([]byte(a.NotBefore.UTC().Format(time.RFC3339Nano)))
+	}
+	if a.MaxUses > 0 {
+		_, _ = hash.Write([]byte(strconv.FormatUint(a.MaxUses, 10)))
+	}
+	if a.Pending {
+		_, _ = hash.Write([]byte("pending"))
+	}
+	_, _ = hash.Write([]byte(a.ApprovedBy))
+
 	// Finalize the hash
 	hashVal := hash.Sum(nil)
 
@@ -13328,6 +14759,10 @@ func (a *ACLToken) Stub() *ACLTokenListStub {
 		Hash:           a.Hash,
 		CreateTime:     a.CreateTime,
 		ExpirationTime: a.ExpirationTime,
+		NotBefore:      a.NotBefore,
+		MaxUses:        a.MaxUses,
+		Uses:           a.Uses,
+		Pending:        a.Pending,
 		CreateIndex:    a.CreateIndex,
 		ModifyIndex:    a.ModifyIndex,
 	}
@@ -13407,6 +14842,19 @@ type ACLTokenUpsertResponse struct {
 	WriteMeta
 }
 
+// ACLTokenApproveRequest is used to approve a pending token
+type ACLTokenApproveRequest struct {
+	AccessorID string
+	WriteRequest
+}
+
+// ACLTokenUseRequest is used by the servers to record a use of a token with a
+// maximum number of uses
+type ACLTokenUseRequest struct {
+	AccessorID string
+	WriteRequest
+}
+
 // OneTimeToken is used to log into the web UI using a token provided by the
 // command line.
 type OneTimeToken struct {
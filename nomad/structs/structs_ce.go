@@ -11,6 +11,7 @@ import (
 	"fmt"
 
 	multierror "github.com/hashicorp/go-multierror"
+	"github.com/ryanuber/go-glob"
 )
 
 func (n *Namespace) Canonicalize() {}
@@ -18,36 +19,123 @@ func (n *Namespace) Canonicalize() {}
 func (n *NamespaceNodePoolConfiguration) Canonicalize() {}
 
 func (n *NamespaceNodePoolConfiguration) Validate() error {
-	if n != nil {
-		return errors.New("Node Pools Governance is unlicensed.")
+	if n == nil {
+		return nil
 	}
-	return nil
+
+	var mErr multierror.Error
+
+	if n.Default != "" && !validNodePoolName.MatchString(n.Default) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid default node pool %q, must match regex %s", n.Default, validNodePoolName))
+	}
+	if n.Allowed != nil && len(n.Denied) > 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("allowed and denied node pools are mutually exclusive"))
+	}
+	for _, pattern := range n.Denied {
+		if n.Default != "" && glob.Glob(pattern, n.Default) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("default node pool %q is denied by %q", n.Default, pattern))
+		}
+	}
+
+	return mErr.ErrorOrNil()
 }
 
 func (n *NamespaceVaultConfiguration) Canonicalize() {}
 
 func (n *NamespaceVaultConfiguration) Validate() error {
-	if n != nil {
-		return errors.New("Multi-Cluster Vault is unlicensed.")
+	if n == nil {
+		return nil
 	}
-	return nil
+
+	var mErr multierror.Error
+
+	if n.Default != "" {
+		if err := ValidateVaultClusterName(n.Default); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid default Vault cluster: %v", err))
+		}
+	}
+	if n.Allowed != nil && len(n.Denied) > 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("allowed and denied Vault clusters are mutually exclusive"))
+	}
+	for _, pattern := range n.Denied {
+		if n.Default != "" && glob.Glob(pattern, n.Default) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("default Vault cluster %q is denied by %q", n.Default, pattern))
+		}
+	}
+
+	return mErr.ErrorOrNil()
 }
 
 func (n *NamespaceConsulConfiguration) Canonicalize() {}
 
 func (n *NamespaceConsulConfiguration) Validate() error {
-	if n != nil {
-		return errors.New("Multi-Cluster Consul is unlicensed.")
+	if n == nil {
+		return nil
 	}
-	return nil
+
+	var mErr multierror.Error
+
+	if n.Default != "" {
+		if err := ValidateConsulClusterName(n.Default); err != nil {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid default Consul cluster: %v", err))
+		}
+	}
+	if n.Allowed != nil && len(n.Denied) > 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("allowed and denied Consul clusters are mutually exclusive"))
+	}
+	for _, pattern := range n.Denied {
+		if n.Default != "" && glob.Glob(pattern, n.Default) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("default Consul cluster %q is denied by %q", n.Default, pattern))
+		}
+	}
+
+	return mErr.ErrorOrNil()
 }
 
 func (m *Multiregion) Validate(jobType string, jobDatacenters []string) error {
-	if m != nil {
-		return errors.New("Multiregion jobs are unlicensed.")
+	if m == nil {
+		return nil
 	}
 
-	return nil
+	var mErr multierror.Error
+
+	if jobType == JobTypeSysBatch {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("Multiregion jobs of type %q are not supported", jobType))
+	}
+
+	if m.Strategy != nil {
+		if m.Strategy.MaxParallel < 0 {
+			mErr.Errors = append(mErr.Errors, errors.New("Multiregion max_parallel must be non-negative"))
+		}
+		switch m.Strategy.OnFailure {
+		case "", MultiregionOnFailureFailAll, MultiregionOnFailureFailLocal:
+		default:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf(
+				"Multiregion on_failure must be %q, %q, or empty; got %q",
+				MultiregionOnFailureFailAll, MultiregionOnFailureFailLocal, m.Strategy.OnFailure))
+		}
+	}
+
+	seen := make(map[string]struct{}, len(m.Regions))
+	for _, region := range m.Regions {
+		if region.Name == "" {
+			mErr.Errors = append(mErr.Errors, errors.New("Multiregion region must have a name"))
+			continue
+		}
+		if _, ok := seen[region.Name]; ok {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Multiregion region %q is defined more than once", region.Name))
+		}
+		seen[region.Name] = struct{}{}
+
+		if region.Count < 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Multiregion region %q count must be non-negative", region.Name))
+		}
+		if len(region.Datacenters) == 0 && len(jobDatacenters) == 0 {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("Multiregion region %q must have datacenters when the job has none", region.Name))
+		}
+	}
+
+	return mErr.ErrorOrNil()
 }
 
 func (p *ScalingPolicy) validateType() multierror.Error {
@@ -22,34 +22,109 @@ func TestNamespace_Validate_Oss(t *testing.T) {
 		expectedErr string
 	}{
 		{
-			name: "node pool config not allowed",
+			name: "node pool config",
 			namespace: &Namespace{
 				Name: "test",
 				NodePoolConfiguration: &NamespaceNodePoolConfiguration{
 					Default: "dev",
+					Allowed: []string{"dev-*"},
 				},
 			},
-			expectedErr: "unlicensed",
 		},
 		{
-			name: "vault config not allowed",
+			name: "node pool config allowed and denied",
+			namespace: &Namespace{
+				Name: "test",
+				NodePoolConfiguration: &NamespaceNodePoolConfiguration{
+					Allowed: []string{"dev"},
+					Denied:  []string{"prod"},
+				},
+			},
+			expectedErr: "mutually exclusive",
+		},
+		{
+			name: "node pool config default denied",
+			namespace: &Namespace{
+				Name: "test",
+				NodePoolConfiguration: &NamespaceNodePoolConfiguration{
+					Default: "prod-1",
+					Denied:  []string{"prod-*"},
+				},
+			},
+			expectedErr: `default node pool "prod-1" is denied`,
+		},
+		{
+			name: "node pool config invalid default",
+			namespace: &Namespace{
+				Name: "test",
+				NodePoolConfiguration: &NamespaceNodePoolConfiguration{
+					Default: "not a pool",
+				},
+			},
+			expectedErr: "invalid default node pool",
+		},
+		{
+			name: "vault config",
 			namespace: &Namespace{
 				Name: "test",
 				VaultConfiguration: &NamespaceVaultConfiguration{
 					Default: "dev",
+					Allowed: []string{"pci-*"},
+				},
+			},
+		},
+		{
+			name: "vault config allowed and denied",
+			namespace: &Namespace{
+				Name: "test",
+				VaultConfiguration: &NamespaceVaultConfiguration{
+					Allowed: []string{"dev"},
+					Denied:  []string{"pci"},
+				},
+			},
+			expectedErr: "mutually exclusive",
+		},
+		{
+			name: "vault config default denied",
+			namespace: &Namespace{
+				Name: "test",
+				VaultConfiguration: &NamespaceVaultConfiguration{
+					Default: "pci-1",
+					Denied:  []string{"pci-*"},
 				},
 			},
-			expectedErr: "unlicensed",
+			expectedErr: `default Vault cluster "pci-1" is denied`,
 		},
 		{
-			name: "consul config not allowed",
+			name: "vault config invalid default",
+			namespace: &Namespace{
+				Name: "test",
+				VaultConfiguration: &NamespaceVaultConfiguration{
+					Default: "not a cluster",
+				},
+			},
+			expectedErr: "invalid default Vault cluster",
+		},
+		{
+			name: "consul config",
 			namespace: &Namespace{
 				Name: "test",
 				ConsulConfiguration: &NamespaceConsulConfiguration{
 					Default: "dev",
+					Denied:  []string{"prod-*"},
+				},
+			},
+		},
+		{
+			name: "consul config default denied",
+			namespace: &Namespace{
+				Name: "test",
+				ConsulConfiguration: &NamespaceConsulConfiguration{
+					Default: "prod-1",
+					Denied:  []string{"prod-*"},
 				},
 			},
-			expectedErr: "unlicensed",
+			expectedErr: `default Consul cluster "prod-1" is denied`,
 		},
 	}
 
@@ -64,3 +139,87 @@ func TestNamespace_Validate_Oss(t *testing.T) {
 		})
 	}
 }
+
+func TestMultiregion_Validate_CE(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name        string
+		multiregion *Multiregion
+		jobType     string
+		datacenters []string
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			multiregion: &Multiregion{
+				Strategy: &MultiregionStrategy{MaxParallel: 1, OnFailure: MultiregionOnFailureFailAll},
+				Regions: []*MultiregionRegion{
+					{Name: "west", Count: 2, Datacenters: []string{"west-1"}},
+					{Name: "east"},
+				},
+			},
+			jobType:     JobTypeService,
+			datacenters: []string{"dc1"},
+		},
+		{
+			name: "sysbatch not supported",
+			multiregion: &Multiregion{
+				Regions: []*MultiregionRegion{{Name: "west"}},
+			},
+			jobType:     JobTypeSysBatch,
+			datacenters: []string{"dc1"},
+			expectedErr: "not supported",
+		},
+		{
+			name: "invalid strategy",
+			multiregion: &Multiregion{
+				Strategy: &MultiregionStrategy{MaxParallel: -1, OnFailure: "fail_some"},
+				Regions:  []*MultiregionRegion{{Name: "west"}},
+			},
+			jobType:     JobTypeService,
+			datacenters: []string{"dc1"},
+			expectedErr: "2 errors occurred",
+		},
+		{
+			name: "duplicate region",
+			multiregion: &Multiregion{
+				Regions: []*MultiregionRegion{{Name: "west"}, {Name: "west"}},
+			},
+			jobType:     JobTypeService,
+			datacenters: []string{"dc1"},
+			expectedErr: `region "west" is defined more than once`,
+		},
+		{
+			name: "negative count",
+			multiregion: &Multiregion{
+				Regions: []*MultiregionRegion{{Name: "west", Count: -1}},
+			},
+			jobType:     JobTypeService,
+			datacenters: []string{"dc1"},
+			expectedErr: "count must be non-negative",
+		},
+		{
+			name: "missing datacenters",
+			multiregion: &Multiregion{
+				Regions: []*MultiregionRegion{
+					{Name: "west", Datacenters: []string{"west-1"}},
+					{Name: "east"},
+				},
+			},
+			jobType:     JobTypeService,
+			expectedErr: `region "east" must have datacenters`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.multiregion.Validate(tc.jobType, tc.datacenters)
+			if tc.expectedErr != "" {
+				must.ErrorContains(t, err, tc.expectedErr)
+			} else {
+				must.NoError(t, err)
+			}
+		})
+	}
+}
@@ -234,6 +234,114 @@ func TestNamespace_Copy(t *testing.T) {
 	must.Eq(t, ns, nsCopy2)
 }
 
+func TestNamespace_AllowsNodePool(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name       string
+		config     *NamespaceNodePoolConfiguration
+		allowed    []string
+		notAllowed []string
+	}{
+		{
+			name:    "no config allows all",
+			config:  nil,
+			allowed: []string{NodePoolDefault, "dev", "prod"},
+		},
+		{
+			name: "empty allowed only allows default",
+			config: &NamespaceNodePoolConfiguration{
+				Default: "dev",
+				Allowed: []string{},
+			},
+			allowed:    []string{"dev"},
+			notAllowed: []string{NodePoolDefault, "prod"},
+		},
+		{
+			name: "allowed globs",
+			config: &NamespaceNodePoolConfiguration{
+				Allowed: []string{"dev-*"},
+			},
+			allowed:    []string{NodePoolDefault, "dev-1", "dev-2"},
+			notAllowed: []string{"prod-1"},
+		},
+		{
+			name: "denied globs",
+			config: &NamespaceNodePoolConfiguration{
+				Denied: []string{"prod-*"},
+			},
+			allowed:    []string{NodePoolDefault, "dev-1"},
+			notAllowed: []string{"prod-1", "prod-2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ns := &Namespace{Name: "test", NodePoolConfiguration: tc.config}
+			for _, pool := range tc.allowed {
+				must.True(t, ns.AllowsNodePool(pool), must.Sprintf("expected %q to be allowed", pool))
+			}
+			for _, pool := range tc.notAllowed {
+				must.False(t, ns.AllowsNodePool(pool), must.Sprintf("expected %q not to be allowed", pool))
+			}
+		})
+	}
+}
+
+func TestNamespace_AllowsVaultCluster(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		name       string
+		config     *NamespaceVaultConfiguration
+		allowed    []string
+		notAllowed []string
+	}{
+		{
+			name:    "no config allows all",
+			config:  nil,
+			allowed: []string{VaultDefaultCluster, "pci"},
+		},
+		{
+			name: "empty allowed only allows default",
+			config: &NamespaceVaultConfiguration{
+				Default: "pci",
+				Allowed: []string{},
+			},
+			allowed:    []string{"pci"},
+			notAllowed: []string{VaultDefaultCluster, "infra"},
+		},
+		{
+			name: "allowed globs",
+			config: &NamespaceVaultConfiguration{
+				Allowed: []string{"pci-*"},
+			},
+			allowed:    []string{VaultDefaultCluster, "pci-1"},
+			notAllowed: []string{"infra"},
+		},
+		{
+			name: "denied globs",
+			config: &NamespaceVaultConfiguration{
+				Denied: []string{"pci-*"},
+			},
+			allowed:    []string{VaultDefaultCluster, "infra"},
+			notAllowed: []string{"pci-1", "pci-2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ns := &Namespace{Name: "test", VaultConfiguration: tc.config}
+			for _, cluster := range tc.allowed {
+				must.True(t, ns.AllowsVaultCluster(cluster), must.Sprintf("expected %q to be allowed", cluster))
+			}
+			for _, cluster := range tc.notAllowed {
+				must.False(t, ns.AllowsVaultCluster(cluster), must.Sprintf("expected %q not to be allowed", cluster))
+			}
+		})
+	}
+}
+
 func TestAuthenticatedIdentity_String(t *testing.T) {
 	ci.Parallel(t)
 
@@ -847,9 +955,10 @@ func TestJob_SystemJob_Validate(t *testing.T) {
 	j.TaskGroups[0].ReschedulePolicy = nil
 	j.Canonicalize()
 
-	err := j.Validate()
-	if err == nil || !strings.Contains(err.Error(), "exceed") {
-		t.Fatalf("expect error due to count")
+	// The count of system jobs is per node
+	j.TaskGroups[0].Count = 2
+	if err := j.Validate(); err != nil {
+		t.Fatalf("unexpected err: %v", err)
 	}
 
 	j.TaskGroups[0].Count = 0
@@ -879,7 +988,7 @@ func TestJob_SystemJob_Validate(t *testing.T) {
 		LTarget: "${meta.rack}",
 		RTarget: "r1",
 	}}
-	err = j.Validate()
+	err := j.Validate()
 	require.NotNil(t, err)
 	require.Contains(t, err.Error(), "System jobs may not have an affinity block")
 
@@ -1438,6 +1547,53 @@ func TestTaskGroup_Validate(t *testing.T) {
 			},
 			jobType: JobTypeBatch,
 		},
+		{
+			name: "invalid update canary constraints for service job",
+			tg: &TaskGroup{
+				Name:  "web",
+				Count: 1,
+				Tasks: []*Task{
+					{Name: "web", Leader: true},
+				},
+				Update: &UpdateStrategy{
+					Stagger:          30 * time.Second,
+					MaxParallel:      1,
+					HealthCheck:      UpdateStrategyHealthCheck_Checks,
+					MinHealthyTime:   10 * time.Second,
+					HealthyDeadline:  5 * time.Minute,
+					ProgressDeadline: 10 * time.Minute,
+					CanaryConstraints: []*Constraint{{
+						LTarget: "${meta.canary}",
+						RTarget: "true",
+						Operand: "=",
+					}},
+				},
+			},
+			expErr: []string{
+				"does not allow update canary constraints",
+			},
+			jobType: JobTypeService,
+		},
+		{
+			name: "invalid array block for service job",
+			tg: &TaskGroup{
+				Name:  "web",
+				Count: 2,
+				Tasks: []*Task{
+					{Name: "web", Leader: true},
+				},
+				Array: &ArrayConfig{
+					Count:       5,
+					Concurrency: -1,
+				},
+			},
+			expErr: []string{
+				"does not allow array block",
+				"Task group count (2) must equal the array count (5)",
+				"Concurrency must be >= 0",
+			},
+			jobType: JobTypeService,
+		},
 		{
 			name: "invalid reschedule policy for system job",
 			tg: &TaskGroup{
@@ -2042,6 +2198,16 @@ func TestTask_Validate(t *testing.T) {
 		"task level: distinct_hosts",
 		"task level: distinct_property",
 	)
+	task.Constraints = nil
+
+	task.Artifacts = []*TaskArtifact{{
+		GetterSource:   "git::https://example.com/repo.git",
+		GetterIdentity: "git",
+	}}
+	err = task.Validate(JobTypeBatch, tg)
+	requireErrors(t, err,
+		`Artifact 1 identity "git" not found in task identities`,
+	)
 }
 
 func TestTask_Validate_Resources(t *testing.T) {
@@ -2914,6 +3080,57 @@ func TestTask_Validate_LogConfig(t *testing.T) {
 	require.Error(t, err, "log storage")
 }
 
+func TestLogConfig_Validate_Backpressure(t *testing.T) {
+	ci.Parallel(t)
+
+	for _, policy := range []string{"", LogBackpressureBlock, LogBackpressureDrop, LogBackpressureSpill} {
+		l := DefaultLogConfig()
+		l.Backpressure = policy
+		require.NoError(t, l.Validate(nil), policy)
+	}
+
+	l := DefaultLogConfig()
+	l.Backpressure = "bogus"
+	require.ErrorContains(t, l.Validate(nil), `backpressure must be one of "block", "drop", or "spill"; got "bogus"`)
+}
+
+func TestLogConfig_Validate_Rotation(t *testing.T) {
+	ci.Parallel(t)
+
+	for _, compression := range []string{"", LogCompressionGzip, LogCompressionZstd} {
+		l := DefaultLogConfig()
+		l.Compression = compression
+		l.RotateInterval = time.Hour
+		l.Retention = 24 * time.Hour
+		require.NoError(t, l.Validate(nil), compression)
+	}
+
+	l := DefaultLogConfig()
+	l.Compression = "bogus"
+	l.RotateInterval = -time.Hour
+	l.Retention = -time.Hour
+	err := l.Validate(nil)
+	require.ErrorContains(t, err, `compression must be one of "gzip" or "zstd"; got "bogus"`)
+	require.ErrorContains(t, err, "rotate interval must not be negative")
+	require.ErrorContains(t, err, "retention must not be negative")
+}
+
+func TestLogConfig_Validate_Sinks(t *testing.T) {
+	ci.Parallel(t)
+
+	l := DefaultLogConfig()
+	l.Sinks = []*LogSink{
+		{Name: "loki", Options: map[string]string{"team": "web"}},
+		{Name: "elasticsearch"},
+	}
+	require.NoError(t, l.Validate(nil))
+
+	l.Sinks = []*LogSink{{Name: "loki"}, {Name: ""}, {Name: "loki"}}
+	err := l.Validate(nil)
+	require.ErrorContains(t, err, "log sink 2 has an empty name")
+	require.ErrorContains(t, err, `duplicate log sink "loki"`)
+}
+
 func TestLogConfig_Equals(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2941,6 +3158,37 @@ func TestLogConfig_Equals(t *testing.T) {
 		require.False(t, a.Equal(b))
 	})
 
+	t.Run("backpressure", func(t *testing.T) {
+		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Backpressure: LogBackpressureDrop}
+		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Backpressure: LogBackpressureSpill}
+		require.False(t, a.Equal(b))
+	})
+
+	t.Run("rotate interval", func(t *testing.T) {
+		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, RotateInterval: time.Hour}
+		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, RotateInterval: time.Minute}
+		require.False(t, a.Equal(b))
+	})
+
+	t.Run("compression", func(t *testing.T) {
+		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Compression: LogCompressionGzip}
+		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Compression: LogCompressionZstd}
+		require.False(t, a.Equal(b))
+	})
+
+	t.Run("retention", func(t *testing.T) {
+		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Retention: time.Hour}
+		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
+		require.False(t, a.Equal(b))
+	})
+
+	t.Run("sinks", func(t *testing.T) {
+		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Sinks: []*LogSink{{Name: "loki", Options: map[string]string{"team": "web"}}}}
+		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200, Sinks: []*LogSink{{Name: "loki", Options: map[string]string{"team": "api"}}}}
+		require.False(t, a.Equal(b))
+		require.True(t, a.Equal(a.Copy()))
+	})
+
 	t.Run("same", func(t *testing.T) {
 		a := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
 		b := &LogConfig{MaxFiles: 1, MaxFileSizeMB: 200}
@@ -3554,6 +3802,49 @@ func TestUpdateStrategy_Validate(t *testing.T) {
 	)
 }
 
+func TestUpdateStrategy_Validate_BlueGreen(t *testing.T) {
+	ci.Parallel(t)
+
+	u := DefaultUpdateStrategy.Copy()
+	u.Strategy = UpdateStrategyBlueGreen
+	u.AutoPromote = true
+	must.NoError(t, u.Validate())
+	must.Eq(t, 3, u.DesiredCanaries(3))
+
+	u.Canary = 1
+	requireErrors(t, u.Validate(),
+		"Canary count can not be set with the \"bluegreen\" strategy",
+	)
+
+	u.Strategy = "recreate"
+	requireErrors(t, u.Validate(),
+		"Invalid update strategy given",
+	)
+}
+
+func TestUpdateStrategy_Validate_CanaryPlacement(t *testing.T) {
+	ci.Parallel(t)
+
+	u := DefaultUpdateStrategy.Copy()
+	u.CanaryPlacement = CanaryPlacementDistinct
+	requireErrors(t, u.Validate(),
+		"Canary placement requires a Canary count greater than zero",
+	)
+
+	u.Canary = 1
+	must.NoError(t, u.Validate())
+
+	u.Canary = 0
+	u.Strategy = UpdateStrategyBlueGreen
+	u.CanaryPlacement = CanaryPlacementSame
+	must.NoError(t, u.Validate())
+
+	u.CanaryPlacement = "anywhere"
+	requireErrors(t, u.Validate(),
+		"Invalid canary placement given",
+	)
+}
+
 func TestResource_NetIndex(t *testing.T) {
 	ci.Parallel(t)
 
@@ -4366,6 +4657,36 @@ func TestTaskLifecycleConfig_Validate(t *testing.T) {
 			},
 			err: fmt.Errorf("no lifecycle hook provided"),
 		},
+		{
+			name: "prestart sidecar wait healthy",
+			tlc: &TaskLifecycleConfig{
+				Hook:            "prestart",
+				Sidecar:         true,
+				WaitHealthy:     true,
+				HealthyTimeout:  time.Minute,
+				UnhealthyAction: TaskLifecycleUnhealthyActionRestart,
+			},
+			err: nil,
+		},
+		{
+			name: "wait healthy not sidecar",
+			tlc: &TaskLifecycleConfig{
+				Hook:        "poststart",
+				Sidecar:     true,
+				WaitHealthy: true,
+			},
+			err: fmt.Errorf("wait_healthy is only supported for prestart sidecar tasks"),
+		},
+		{
+			name: "wait healthy invalid action",
+			tlc: &TaskLifecycleConfig{
+				Hook:            "prestart",
+				Sidecar:         true,
+				WaitHealthy:     true,
+				UnhealthyAction: "explode",
+			},
+			err: fmt.Errorf("invalid unhealthy_action: explode"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -4437,6 +4758,112 @@ func TestRestartPolicy_Validate(t *testing.T) {
 	if err := p.Validate(); err == nil || !strings.Contains(err.Error(), "Interval can not be less than") {
 		t.Fatalf("expect interval too small error, got: %v", err)
 	}
+
+	// Exponential backoff and budget pass
+	p = &RestartPolicy{
+		Mode:            RestartPolicyModeDelay,
+		Attempts:        3,
+		Delay:           5 * time.Second,
+		Interval:        time.Minute,
+		DelayFunction:   RestartPolicyDelayFunctionExponential,
+		DelayMultiplier: 1.5,
+		MaxDelay:        30 * time.Second,
+		Jitter:          pointer.Of(0.5),
+		Budget:          10,
+		BudgetWindow:    time.Hour,
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Bad backoff and budget settings fail
+	p = &RestartPolicy{
+		Mode:            RestartPolicyModeDelay,
+		Attempts:        3,
+		Delay:           5 * time.Second,
+		Interval:        time.Minute,
+		DelayFunction:   RestartPolicyDelayFunctionExponential,
+		DelayMultiplier: 0.5,
+		MaxDelay:        time.Second,
+		Jitter:          pointer.Of(1.5),
+		Budget:          10,
+	}
+	err := p.Validate()
+	if err == nil {
+		t.Fatalf("expect backoff and budget errors")
+	}
+	for _, msg := range []string{"Delay multiplier", "Max delay", "Jitter", "Budget window"} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Fatalf("expect %q error, got: %v", msg, err)
+		}
+	}
+
+	// Unknown delay function fails
+	p = &RestartPolicy{
+		Mode:          RestartPolicyModeDelay,
+		Attempts:      3,
+		Interval:      time.Minute,
+		DelayFunction: "fibonacci",
+	}
+	if err := p.Validate(); err == nil || !strings.Contains(err.Error(), "delay function") {
+		t.Fatalf("expect delay function error, got: %v", err)
+	}
+}
+
+func TestOOMPolicy_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	resources := &Resources{MemoryMB: 256, MemoryMaxMB: 512}
+
+	cases := []struct {
+		name   string
+		policy *OOMPolicy
+		err    string
+	}{
+		{
+			name:   "empty",
+			policy: &OOMPolicy{},
+		},
+		{
+			name:   "fail",
+			policy: &OOMPolicy{Mode: OOMPolicyModeFail},
+		},
+		{
+			name:   "adjust",
+			policy: &OOMPolicy{Mode: OOMPolicyModeAdjust, MemoryIncreaseMB: 128, MemoryMaxMB: 1024},
+		},
+		{
+			name:   "unknown mode",
+			policy: &OOMPolicy{Mode: "nope"},
+			err:    "Unsupported OOM policy mode",
+		},
+		{
+			name:   "increase without adjust",
+			policy: &OOMPolicy{Mode: OOMPolicyModeIgnore, MemoryIncreaseMB: 128},
+			err:    "only supported with mode",
+		},
+		{
+			name:   "adjust without increase",
+			policy: &OOMPolicy{Mode: OOMPolicyModeAdjust, MemoryMaxMB: 1024},
+			err:    "Memory increase must be greater than 0",
+		},
+		{
+			name:   "adjust max below limit",
+			policy: &OOMPolicy{Mode: OOMPolicyModeAdjust, MemoryIncreaseMB: 128, MemoryMaxMB: 384},
+			err:    "can not be less than the task memory limit 512 MB",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate(resources)
+			if tc.err == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
 }
 
 func TestReschedulePolicy_Validate(t *testing.T) {
@@ -4795,6 +5222,17 @@ func TestTaskArtifact_Hash(t *testing.T) {
 			GetterInsecure: true,
 			RelativeDest:   "i",
 		},
+		{
+			GetterSource: "b",
+			GetterOptions: map[string]string{
+				"c": "c",
+				"d": "e",
+			},
+			GetterMode:     "g",
+			GetterInsecure: true,
+			RelativeDest:   "i",
+			GetterIdentity: "j",
+		},
 	}
 
 	// Map of hash to source
@@ -5113,6 +5551,28 @@ func TestAllocation_Terminated(t *testing.T) {
 	}
 }
 
+func TestAllocation_TaskForPath(t *testing.T) {
+	ci.Parallel(t)
+
+	alloc := MockAlloc()
+
+	cases := map[string]string{
+		"/":                   "",
+		"":                    "",
+		"alloc/logs":          "",
+		"web":                 "web",
+		"/web/local/file.txt": "web",
+		"web/secrets":         "web",
+		"web/alloc/logs":      "",
+		"web/../alloc":        "",
+		"../web/local":        "web",
+		"other/local":         "",
+	}
+	for p, expected := range cases {
+		must.Eq(t, expected, alloc.TaskForPath(p), must.Sprintf("path %q", p))
+	}
+}
+
 func TestAllocation_ShouldReschedule(t *testing.T) {
 	ci.Parallel(t)
 	type testCase struct {
@@ -6180,6 +6640,22 @@ func TestParameterizedJobConfig_Validate(t *testing.T) {
 	if err := d.Validate(); err == nil || !strings.Contains(err.Error(), "disjoint") {
 		t.Fatalf("Expected meta not being disjoint error: %v", err)
 	}
+
+	d.MetaRequired = nil
+	d.QueueLimit = 100
+	if err := d.Validate(); err == nil || !strings.Contains(err.Error(), "requires max concurrent") {
+		t.Fatalf("Expected queue limit without max concurrent error: %v", err)
+	}
+
+	d.MaxConcurrent = -1
+	if err := d.Validate(); err == nil || !strings.Contains(err.Error(), "Max concurrent") {
+		t.Fatalf("Expected negative max concurrent error: %v", err)
+	}
+
+	d.MaxConcurrent = 10
+	if err := d.Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 }
 
 func TestParameterizedJobConfig_Validate_NonBatch(t *testing.T) {
@@ -6439,6 +6915,23 @@ func TestACLTokenSetHash(t *testing.T) {
 	assert.NotNil(t, tk.Hash)
 	assert.Equal(t, out2, tk.Hash)
 	assert.NotEqual(t, out1, out2)
+
+	// Approving a pending token changes its hash
+	tk.Pending = true
+	out3 := tk.SetHash()
+	tk.Pending = false
+	tk.ApprovedBy = "approver"
+	out4 := tk.SetHash()
+	assert.NotEqual(t, out2, out3)
+	assert.NotEqual(t, out3, out4)
+
+	tk.NotBefore = pointer.Of(time.Now().UTC())
+	out5 := tk.SetHash()
+	assert.NotEqual(t, out4, out5)
+
+	tk.MaxUses = 3
+	out6 := tk.SetHash()
+	assert.NotEqual(t, out5, out6)
 }
 
 func TestACLPolicySetHash(t *testing.T) {
@@ -7465,6 +7958,12 @@ func TestEphemeralDisk_Equal(t *testing.T) {
 		Sticky:  true,
 		SizeMB:  42,
 		Migrate: true,
+		MigrateHooks: []*MigrateHook{{
+			Stage:   MigrateHookStagePreMigrate,
+			Task:    "db",
+			Command: "/bin/flush",
+			Timeout: time.Minute,
+		}},
 	}, []must.Tweak[*EphemeralDisk]{{
 		Field: "Sticky",
 		Apply: func(e *EphemeralDisk) { e.Sticky = false },
@@ -7474,6 +7973,124 @@ func TestEphemeralDisk_Equal(t *testing.T) {
 	}, {
 		Field: "Migrate",
 		Apply: func(e *EphemeralDisk) { e.Migrate = false },
+	}, {
+		Field: "MigrateHooks",
+		Apply: func(e *EphemeralDisk) { e.MigrateHooks = nil },
+	}})
+}
+
+func TestEphemeralDisk_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	hook := func(stage, task string) *MigrateHook {
+		return &MigrateHook{
+			Stage:   stage,
+			Task:    task,
+			Command: "/bin/flush",
+			Timeout: time.Minute,
+		}
+	}
+
+	cases := []struct {
+		name   string
+		disk   *EphemeralDisk
+		expErr string
+	}{
+		{
+			name: "ok",
+			disk: &EphemeralDisk{
+				SizeMB: 300,
+				Sticky: true,
+				MigrateHooks: []*MigrateHook{
+					hook(MigrateHookStagePreMigrate, "db"),
+					hook(MigrateHookStagePostRestore, "db"),
+				},
+			},
+		},
+		{
+			name:   "too small",
+			disk:   &EphemeralDisk{SizeMB: 5},
+			expErr: "minimum DiskMB value is 10",
+		},
+		{
+			name: "hooks without sticky or migrate",
+			disk: &EphemeralDisk{
+				SizeMB:       300,
+				MigrateHooks: []*MigrateHook{hook(MigrateHookStagePreMigrate, "db")},
+			},
+			expErr: "migrate hooks require a sticky or migrated disk",
+		},
+		{
+			name: "bad stage",
+			disk: &EphemeralDisk{
+				SizeMB:       300,
+				Migrate:      true,
+				MigrateHooks: []*MigrateHook{hook("pre_start", "db")},
+			},
+			expErr: `stage must be "pre_migrate" or "post_restore"`,
+		},
+		{
+			name: "duplicate",
+			disk: &EphemeralDisk{
+				SizeMB:  300,
+				Migrate: true,
+				MigrateHooks: []*MigrateHook{
+					hook(MigrateHookStagePreMigrate, "db"),
+					hook(MigrateHookStagePreMigrate, "db"),
+				},
+			},
+			expErr: `duplicate pre_migrate migrate hook for task "db"`,
+		},
+		{
+			name: "missing fields",
+			disk: &EphemeralDisk{
+				SizeMB:       300,
+				Migrate:      true,
+				MigrateHooks: []*MigrateHook{{Stage: MigrateHookStagePostRestore}},
+			},
+			expErr: "command must be specified",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.disk.Validate()
+			if tc.expErr == "" {
+				must.NoError(t, err)
+			} else {
+				must.ErrorContains(t, err, tc.expErr)
+			}
+		})
+	}
+}
+
+func TestMigrateHook_Equal(t *testing.T) {
+	ci.Parallel(t)
+
+	must.Equal[*MigrateHook](t, nil, nil)
+	must.NotEqual[*MigrateHook](t, nil, new(MigrateHook))
+
+	must.StructEqual(t, &MigrateHook{
+		Stage:   MigrateHookStagePostRestore,
+		Task:    "db",
+		Command: "/bin/reopen",
+		Args:    []string{"-v"},
+		Timeout: time.Minute,
+	}, []must.Tweak[*MigrateHook]{{
+		Field: "Stage",
+		Apply: func(h *MigrateHook) { h.Stage = MigrateHookStagePreMigrate },
+	}, {
+		Field: "Task",
+		Apply: func(h *MigrateHook) { h.Task = "web" },
+	}, {
+		Field: "Command",
+		Apply: func(h *MigrateHook) { h.Command = "/bin/false" },
+	}, {
+		Field: "Args",
+		Apply: func(h *MigrateHook) { h.Args = nil },
+	}, {
+		Field: "Timeout",
+		Apply: func(h *MigrateHook) { h.Timeout = time.Second },
 	}})
 }
 
@@ -7570,6 +8187,9 @@ func TestTaskArtifact_Equal(t *testing.T) {
 	}, {
 		Field: "RelativeDest",
 		Apply: func(ta *TaskArtifact) { ta.RelativeDest = "./alloc" },
+	}, {
+		Field: "GetterIdentity",
+		Apply: func(ta *TaskArtifact) { ta.GetterIdentity = "git" },
 	}})
 }
 
@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// UsageGroupByJob groups usage records by job.
+	UsageGroupByJob = "job"
+
+	// UsageGroupByNamespace groups usage records by namespace, summing the
+	// usage of the jobs of each namespace.
+	UsageGroupByNamespace = "namespace"
+
+	// AllocUsageReportInterval is how often clients report the resource
+	// usage of their running allocations.
+	AllocUsageReportInterval = time.Minute
+)
+
+// UsageRecord is the resource usage of a job, or of a namespace when grouped
+// by namespace, during one period of the usage resolution. Reserved resources
+// are accounted from the allocations running on clients and used resources
+// from the usage reported by the clients.
+//
+// Resources are accounted in resource-seconds: CPU in MHz-seconds and memory
+// and disk in MB-seconds. A task reserving 500 MHz for an hour accounts for
+// 1,800,000 MHz-seconds.
+type UsageRecord struct {
+	Namespace string
+	JobID     string
+
+	// Start is the start of the period in Unix nanoseconds, aligned to the
+	// resolution.
+	Start int64
+
+	// Resolution is the length of the period.
+	Resolution time.Duration
+
+	ReservedCPU    uint64
+	ReservedMemory uint64
+	ReservedDisk   uint64
+	UsedCPU        uint64
+	UsedMemory     uint64
+
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// Add adds the resources accounted by o to the record.
+func (r *UsageRecord) Add(o *UsageRecord) {
+	r.ReservedCPU += o.ReservedCPU
+	r.ReservedMemory += o.ReservedMemory
+	r.ReservedDisk += o.ReservedDisk
+	r.UsedCPU += o.UsedCPU
+	r.UsedMemory += o.UsedMemory
+}
+
+// Copy returns a copy of the record.
+func (r *UsageRecord) Copy() *UsageRecord {
+	if r == nil {
+		return nil
+	}
+	nr := *r
+	return &nr
+}
+
+// UsagePeriodStart returns the start of the usage period of the given
+// resolution that contains t.
+func UsagePeriodStart(t time.Time, resolution time.Duration) int64 {
+	return t.Truncate(resolution).UnixNano()
+}
+
+// AllocUsage is the resource usage of an allocation reported by a client.
+type AllocUsage struct {
+	AllocID string
+
+	// CPU is the CPU used by the allocation, in MHz.
+	CPU uint64
+
+	// MemoryMB is the memory used by the allocation, in MB.
+	MemoryMB uint64
+}
+
+// AllocUsageRequest is used by clients to report the resource usage of their
+// running allocations.
+type AllocUsageRequest struct {
+	NodeID string
+	Usage  []*AllocUsage
+
+	// Interval is the time since the previous report. The usage of the
+	// allocations is accounted for the whole interval.
+	Interval time.Duration
+
+	WriteRequest
+}
+
+// AllocUsageResponse is the response to an allocation usage report.
+type AllocUsageResponse struct {
+	// Enabled is false when usage accounting is disabled on the servers, in
+	// which case clients may report their usage less often.
+	Enabled bool
+
+	WriteMeta
+}
+
+// UsageUpsertRequest is used by the leader to add the accounted usage to the
+// usage records and to remove the records past their retention.
+type UsageUpsertRequest struct {
+	// Usage is added to the usage records of the same namespace, job, and
+	// period.
+	Usage []*UsageRecord
+
+	// PurgeBefore removes the usage records whose period starts before it,
+	// in Unix nanoseconds. Zero disables the purge.
+	PurgeBefore int64
+
+	WriteRequest
+}
+
+// UsageListRequest is used to query usage records. The namespace of the
+// query options may be the wildcard to query every namespace.
+type UsageListRequest struct {
+	// JobID filters the records to a single job.
+	JobID string
+
+	// Start and End filter the records to the periods starting in
+	// [Start, End), in Unix nanoseconds. Zero values are unbounded.
+	Start int64
+	End   int64
+
+	// GroupBy is either job, the default, or namespace.
+	GroupBy string
+
+	QueryOptions
+}
+
+// Validate validates the usage list request.
+func (r *UsageListRequest) Validate() error {
+	switch r.GroupBy {
+	case "", UsageGroupByJob:
+	case UsageGroupByNamespace:
+		if r.JobID != "" {
+			return fmt.Errorf("job filter can't be used when grouping by namespace")
+		}
+	default:
+		return fmt.Errorf("invalid group by %q, must be %q or %q",
+			r.GroupBy, UsageGroupByJob, UsageGroupByNamespace)
+	}
+	if r.End != 0 && r.End < r.Start {
+		return fmt.Errorf("end must not be before start")
+	}
+	return nil
+}
+
+// UsageListResponse is the response to a usage list request.
+type UsageListResponse struct {
+	Usage []*UsageRecord
+	QueryMeta
+}
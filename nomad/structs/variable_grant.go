@@ -0,0 +1,173 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/hashicorp/go-multierror"
+	glob "github.com/ryanuber/go-glob"
+)
+
+const (
+	// maxVariableGrantDescriptionLength is the maximum length allowed for a
+	// variable grant description.
+	maxVariableGrantDescriptionLength = 256
+)
+
+var (
+	// validVariableGrantName is the rule used to validate a variable grant
+	// name.
+	validVariableGrantName = regexp.MustCompile("^[a-zA-Z0-9-_]{1,128}$")
+
+	// validVariableGrantPath is used to validate a variable grant path. It
+	// allows the characters of variable paths and the "*" glob pattern.
+	validVariableGrantPath = regexp.MustCompile("^[a-zA-Z0-9-_~/*]{1,128}$")
+)
+
+// VariableGrant shares the variables at a path of a namespace with the
+// workloads of other namespaces. Workloads whose identity belongs to one of
+// the granted namespaces can read and list the matching variables, without
+// the variables being duplicated in every namespace.
+type VariableGrant struct {
+	// Name is the name of the grant. It must be unique within the namespace.
+	Name string
+
+	// Namespace is the namespace of the shared variables.
+	Namespace string
+
+	// Description is the human-friendly description of the grant.
+	Description string
+
+	// Path is the path of the shared variables. It may contain glob
+	// patterns, such as "shared/*".
+	Path string
+
+	// Namespaces are the namespaces whose workloads are granted access to
+	// the variables. The "*" namespace grants access to the workloads of all
+	// the namespaces.
+	Namespaces []string
+
+	// Jobs optionally restricts the grant to the workloads of the given jobs
+	// of the granted namespaces.
+	Jobs []string
+
+	// Raft indexes.
+	CreateIndex uint64
+	ModifyIndex uint64
+}
+
+// GetID implements the IDGetter interface required for pagination.
+func (g *VariableGrant) GetID() string {
+	return g.Name
+}
+
+// GetNamespace implements the NamespaceGetter interface required for
+// pagination.
+func (g *VariableGrant) GetNamespace() string {
+	return g.Namespace
+}
+
+// Validate returns an error if the variable grant is invalid.
+func (g *VariableGrant) Validate() error {
+	var mErr *multierror.Error
+
+	if !validVariableGrantName.MatchString(g.Name) {
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid name %q, must match regex %s", g.Name, validVariableGrantName))
+	}
+	if len(g.Description) > maxVariableGrantDescriptionLength {
+		mErr = multierror.Append(mErr, fmt.Errorf("description longer than %d", maxVariableGrantDescriptionLength))
+	}
+	if g.Path == "" {
+		mErr = multierror.Append(mErr, errors.New("path is empty"))
+	} else if !validVariableGrantPath.MatchString(g.Path) {
+		mErr = multierror.Append(mErr, fmt.Errorf("invalid path %q", g.Path))
+	}
+	if len(g.Namespaces) == 0 {
+		mErr = multierror.Append(mErr, errors.New("must grant at least one namespace"))
+	}
+	for _, ns := range g.Namespaces {
+		if ns == g.Namespace {
+			mErr = multierror.Append(mErr, fmt.Errorf("can't grant the variables namespace %q to itself", ns))
+		} else if ns != AllNamespacesSentinel && !validNamespaceName.MatchString(ns) {
+			mErr = multierror.Append(mErr, fmt.Errorf("invalid namespace %q", ns))
+		}
+	}
+	for _, job := range g.Jobs {
+		if job == "" {
+			mErr = multierror.Append(mErr, errors.New("job ID is empty"))
+		}
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// Allows returns true if the grant shares the variable at the given path with
+// the workloads of the given namespace and job.
+func (g *VariableGrant) Allows(path, namespace, jobID string) bool {
+	if !slices.Contains(g.Namespaces, namespace) &&
+		!slices.Contains(g.Namespaces, AllNamespacesSentinel) {
+		return false
+	}
+	if len(g.Jobs) > 0 && !slices.Contains(g.Jobs, jobID) {
+		return false
+	}
+	return glob.Glob(g.Path, path)
+}
+
+// Copy returns a copy of the variable grant.
+func (g *VariableGrant) Copy() *VariableGrant {
+	if g == nil {
+		return nil
+	}
+
+	ng := new(VariableGrant)
+	*ng = *g
+	ng.Namespaces = slices.Clone(g.Namespaces)
+	ng.Jobs = slices.Clone(g.Jobs)
+	return ng
+}
+
+// VariableGrantListRequest is used to list variable grants.
+type VariableGrantListRequest struct {
+	QueryOptions
+}
+
+// VariableGrantListResponse is the response to a variable grants list
+// request.
+type VariableGrantListResponse struct {
+	Grants []*VariableGrant
+	QueryMeta
+}
+
+// VariableGrantSpecificRequest is used to make a request for a specific
+// variable grant.
+type VariableGrantSpecificRequest struct {
+	Name string
+	QueryOptions
+}
+
+// SingleVariableGrantResponse is the response to a specific variable grant
+// request.
+type SingleVariableGrantResponse struct {
+	Grant *VariableGrant
+	QueryMeta
+}
+
+// VariableGrantUpsertRequest is used to make a request to insert or update
+// variable grants.
+type VariableGrantUpsertRequest struct {
+	Grants []*VariableGrant
+	WriteRequest
+}
+
+// VariableGrantDeleteRequest is used to make a request to delete variable
+// grants of the request namespace.
+type VariableGrantDeleteRequest struct {
+	Names []string
+	WriteRequest
+}
@@ -46,6 +46,40 @@ const (
 	// Reply: VariablesRenewLockResponse
 	VariablesRenewLockRPCMethod = "Variables.RenewLock"
 
+	// VariablesExpireRPCMethod is the RPC method for deleting all variables
+	// whose TTL has expired. It is called by the leader's core scheduler.
+	//
+	// Args: VariablesExpireRequest
+	// Reply: GenericResponse
+	VariablesExpireRPCMethod = "Variables.Expire"
+
+	// VariablesVersionsRPCMethod is the RPC method for listing the current
+	// and prior versions of a variable, including deleted variables that are
+	// still retained.
+	//
+	// Args: VariablesVersionsRequest
+	// Reply: VariablesVersionsResponse
+	VariablesVersionsRPCMethod = "Variables.Versions"
+
+	// VariablesRestoreRPCMethod is the RPC method for restoring a prior
+	// version of a variable, including a deleted variable.
+	//
+	// Args: VariablesRestoreRequest
+	// Reply: VariablesRestoreResponse
+	VariablesRestoreRPCMethod = "Variables.Restore"
+
+	// VariablesPurgeDeletedRPCMethod is the RPC method for permanently
+	// removing the versions of deleted variables whose retention period has
+	// passed. It is called by the leader's core scheduler.
+	//
+	// Args: VariablesPurgeDeletedRequest
+	// Reply: GenericResponse
+	VariablesPurgeDeletedRPCMethod = "Variables.PurgeDeleted"
+
+	// VariableDefaultTrackedVersions is the number of prior versions of each
+	// variable that are kept by default.
+	VariableDefaultTrackedVersions = 10
+
 	// maxVariableSize is the maximum size of the unencrypted contents of a
 	// variable. This size is deliberately set low and is not configurable, to
 	// discourage DoS'ing the cluster
@@ -74,6 +108,8 @@ var (
 	errQuotaExhausted     = errors.New("variables are limited to 64KiB in total size")
 	errNegativeDelayOrTTL = errors.New("Lock delay and TTL must be positive")
 	errInvalidTTL         = errors.New("TTL must be between 10 seconds and 24 hours")
+	errNegativeVarTTL     = errors.New("variable TTL must not be negative")
+	errTTLOnLock          = errors.New("variables used for locking can not have a TTL")
 )
 
 // VariableMetadata is the metadata envelope for a Variable, it is the list
@@ -86,6 +122,24 @@ type VariableMetadata struct {
 	// Lock represents a variable which is used for locking functionality.
 	Lock *VariableLock `json:",omitempty"`
 
+	// TTL is the optional time-to-live of the variable. When set, the
+	// variable expires TTL after it was last written and is then deleted.
+	TTL time.Duration `json:",omitempty"`
+
+	// ExpireTime is the unix nano time at which the variable expires. It is
+	// set by the server from the TTL and is zero if the variable never
+	// expires.
+	ExpireTime int64 `json:",omitempty"`
+
+	// Version is incremented each time the variable is written, starting at
+	// 1. Prior versions are kept so that they can be read and restored.
+	Version uint64
+
+	// DeleteTime is the unix nano time at which the variable was deleted. It
+	// is only set on the latest prior version of a deleted variable, which is
+	// kept until the deleted variables retention period has passed.
+	DeleteTime int64 `json:",omitempty"`
+
 	CreateIndex uint64
 	CreateTime  int64
 	ModifyIndex uint64
@@ -303,6 +357,18 @@ func (sv VariableMetadata) Equal(vm2 VariableMetadata) bool {
 	if sv.ModifyTime != vm2.ModifyTime {
 		return false
 	}
+	if sv.TTL != vm2.TTL {
+		return false
+	}
+	if sv.ExpireTime != vm2.ExpireTime {
+		return false
+	}
+	if sv.Version != vm2.Version {
+		return false
+	}
+	if sv.DeleteTime != vm2.DeleteTime {
+		return false
+	}
 	return sv.Lock.Equal(vm2.Lock)
 }
 
@@ -384,7 +450,14 @@ func (vd VariableDecrypted) Validate() error {
 		return err
 	}
 
+	if vd.TTL < 0 {
+		return errNegativeVarTTL
+	}
+
 	if vd.Lock != nil {
+		if vd.TTL != 0 {
+			return errTTLOnLock
+		}
 		return vd.Lock.Validate()
 	}
 
@@ -408,6 +481,10 @@ func (vd VariableDecrypted) ValidateForLock() error {
 		return err
 	}
 
+	if vd.TTL != 0 {
+		return errTTLOnLock
+	}
+
 	return vd.Lock.Validate()
 }
 
@@ -501,6 +578,16 @@ func (sv *VariableMetadata) LockID() string {
 // locking.
 func (sv *VariableMetadata) IsLock() bool { return sv.Lock != nil }
 
+// IsExpired returns true if the variable has a TTL that has expired by the
+// given time.
+func (sv *VariableMetadata) IsExpired(now time.Time) bool {
+	return sv.ExpireTime != 0 && sv.ExpireTime <= now.UnixNano()
+}
+
+// IsDeleted returns true if the metadata is the latest version of a variable
+// that has been deleted.
+func (sv *VariableMetadata) IsDeleted() bool { return sv.DeleteTime != 0 }
+
 // VariablesQuota is used to track the total size of variables entries per
 // namespace. The total length of Variable.EncryptedData in bytes will be added
 // to the VariablesQuota table in the same transaction as a write, update, or
@@ -541,6 +628,10 @@ const (
 	// VarOpLockRelease is the variable operation used when attempting to
 	// release a held variable lock.
 	VarOpLockRelease VarOp = "lock-release"
+
+	// VarOpRekey is the variable operation used by the leader to re-encrypt
+	// a variable, or one of its prior versions, with the active root key.
+	VarOpRekey VarOp = "rekey"
 )
 
 // VarOpResult constants give possible operations results from a transaction.
@@ -587,6 +678,24 @@ func (r *VariablesApplyResponse) IsRedacted() bool {
 	return r.Result == VarOpResultRedacted
 }
 
+// VariablesExpireRequest is used by the core scheduler to delete variables
+// whose TTL has expired.
+type VariablesExpireRequest struct {
+	// Timestamp is the time used to determine which variables have expired.
+	// It is set by the leader so every server deletes the same variables.
+	Timestamp time.Time
+	WriteRequest
+}
+
+// VariablesPurgeDeletedRequest is used by the core scheduler to permanently
+// remove deleted variables whose retention period has passed.
+type VariablesPurgeDeletedRequest struct {
+	// Threshold is the time before which deleted variables are purged. It is
+	// set by the leader so every server purges the same variables.
+	Threshold time.Time
+	WriteRequest
+}
+
 // VarApplyStateRequest is used by the FSM to modify the variable store
 type VarApplyStateRequest struct {
 	Op  VarOp              // Which operation are we performing
@@ -662,6 +771,10 @@ type VariablesListResponse struct {
 
 type VariablesReadRequest struct {
 	Path string
+
+	// Version is the version of the variable to read. The current version is
+	// read if it is zero.
+	Version uint64
 	QueryOptions
 }
 
@@ -670,6 +783,48 @@ type VariablesReadResponse struct {
 	QueryMeta
 }
 
+// VariablesVersionsRequest is used to list the versions of a variable.
+type VariablesVersionsRequest struct {
+	Path string
+	QueryOptions
+}
+
+// VariablesVersionsResponse holds the metadata of the versions of a
+// variable, newest first. The current version, if the variable isn't deleted,
+// is the first entry.
+type VariablesVersionsResponse struct {
+	Versions []*VariableMetadata
+	QueryMeta
+}
+
+// VariablesRestoreRequest is used to restore a prior version of a variable.
+// The restored items are written as a new version of the variable.
+type VariablesRestoreRequest struct {
+	Path    string
+	Version uint64
+	WriteRequest
+}
+
+func (r *VariablesRestoreRequest) Validate() error {
+	var mErr multierror.Error
+
+	if r.Path == "" {
+		mErr.Errors = append(mErr.Errors, errNoPath)
+	}
+	if r.Version == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("missing version"))
+	}
+
+	return mErr.ErrorOrNil()
+}
+
+// VariablesRestoreResponse is sent back to the user with the metadata of the
+// variable written by the restore.
+type VariablesRestoreResponse struct {
+	VarMeta *VariableMetadata
+	WriteMeta
+}
+
 // VariablesRenewLockRequest is used to renew the lease on a lock. This request
 // behaves like a write because the renewal needs to be forwarded to the leader
 // where the timers and lock work is kept.
@@ -259,6 +259,46 @@ func TestVariableMetadata_IsLock(t *testing.T) {
 	}
 }
 
+func TestVariableMetadata_IsExpired(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now()
+
+	testCases := []struct {
+		name                  string
+		inputVariableMetadata *VariableMetadata
+		expectedOutput        bool
+	}{
+		{
+			name:                  "no ttl",
+			inputVariableMetadata: &VariableMetadata{},
+			expectedOutput:        false,
+		},
+		{
+			name: "not expired",
+			inputVariableMetadata: &VariableMetadata{
+				TTL:        time.Hour,
+				ExpireTime: now.Add(time.Minute).UnixNano(),
+			},
+			expectedOutput: false,
+		},
+		{
+			name: "expired",
+			inputVariableMetadata: &VariableMetadata{
+				TTL:        time.Hour,
+				ExpireTime: now.Add(-time.Minute).UnixNano(),
+			},
+			expectedOutput: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			must.Eq(t, tc.expectedOutput, tc.inputVariableMetadata.IsExpired(now))
+		})
+	}
+}
+
 func TestStructs_VariableDecrypted_Copy(t *testing.T) {
 	ci.Parallel(t)
 	n := time.Now()
@@ -320,6 +360,28 @@ func TestStructs_VariableDecrypted_Validate(t *testing.T) {
 	}
 }
 
+func TestStructs_VariableDecrypted_Validate_TTL(t *testing.T) {
+	ci.Parallel(t)
+
+	sv := VariableDecrypted{
+		VariableMetadata: VariableMetadata{
+			Namespace: "a",
+			Path:      "a/b/c",
+			TTL:       time.Hour,
+		},
+		Items: VariableItems{"foo": "bar"},
+	}
+	must.NoError(t, sv.Validate())
+
+	sv.TTL = -time.Hour
+	must.ErrorIs(t, sv.Validate(), errNegativeVarTTL)
+
+	sv.TTL = time.Hour
+	sv.Lock = &VariableLock{TTL: time.Minute}
+	must.ErrorIs(t, sv.Validate(), errTTLOnLock)
+	must.ErrorIs(t, sv.ValidateForLock(), errTTLOnLock)
+}
+
 func TestStructs_VariablesRenewLockRequest_Validate(t *testing.T) {
 	ci.Parallel(t)
 
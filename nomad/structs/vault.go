@@ -85,10 +85,9 @@ func ValidateVaultClusterName(cluster string) error {
 	return nil
 }
 
-// GetVaultClusterName gets the Vault cluster for this task. Only a single
-// default cluster is supported in Nomad CE, but this function can be safely
-// used for ENT as well because the appropriate Cluster value will be set at the
-// time of job submission.
+// GetVaultClusterName gets the Vault cluster for this task. The Cluster value is
+// set to the default cluster of the job's namespace at the time of job
+// submission if the task doesn't specify one.
 func (t *Task) GetVaultClusterName() string {
 	if t.Vault != nil && t.Vault.Cluster != "" {
 		return t.Vault.Cluster
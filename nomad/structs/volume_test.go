@@ -51,6 +51,50 @@ func TestVolumeRequest_Validate(t *testing.T) {
 				PerAlloc: true,
 			},
 		},
+		{
+			name: "host volume with size but not created",
+			expected: []string{
+				"host volume size can only be set when the volume is created",
+			},
+			req: &VolumeRequest{
+				Type:   VolumeTypeHost,
+				Source: "data",
+				SizeMB: 100,
+			},
+		},
+		{
+			name: "CSI volume created by the client",
+			expected: []string{
+				"CSI volumes cannot be created by the client",
+			},
+			req: &VolumeRequest{
+				Type:   VolumeTypeCSI,
+				Source: "data",
+				Create: true,
+			},
+		},
+		{
+			name: "volume affinity out of range",
+			expected: []string{
+				"CSI volume affinity must be between 0 and 100",
+			},
+			req: &VolumeRequest{
+				Type:     VolumeTypeCSI,
+				Source:   "data",
+				Affinity: -10,
+			},
+		},
+		{
+			name: "host volume with affinity",
+			expected: []string{
+				"host volumes cannot have an affinity",
+			},
+			req: &VolumeRequest{
+				Type:     VolumeTypeHost,
+				Source:   "data",
+				Affinity: 50,
+			},
+		},
 		{
 			name: "CSI volume multi-reader-single-writer access mode",
 			expected: []string{
@@ -117,6 +161,9 @@ func TestVolumeRequest_Equal(t *testing.T) {
 			MountFlags: []string{"flag1"},
 		},
 		PerAlloc: true,
+		Create:   true,
+		SizeMB:   100,
+		Affinity: 50,
 	}, []must.Tweak[*VolumeRequest]{{
 		Field: "Name",
 		Apply: func(vr *VolumeRequest) { vr.Name = "name2" },
@@ -141,6 +188,15 @@ func TestVolumeRequest_Equal(t *testing.T) {
 	}, {
 		Field: "PerAlloc",
 		Apply: func(vr *VolumeRequest) { vr.PerAlloc = false },
+	}, {
+		Field: "Create",
+		Apply: func(vr *VolumeRequest) { vr.Create = false },
+	}, {
+		Field: "SizeMB",
+		Apply: func(vr *VolumeRequest) { vr.SizeMB = 200 },
+	}, {
+		Field: "Affinity",
+		Apply: func(vr *VolumeRequest) { vr.Affinity = 0 },
 	}})
 }
 
@@ -26,6 +26,20 @@ var (
 	errVolMountEmptyVol               = fmt.Errorf("volume mount references an empty volume")
 )
 
+const (
+	// NodeAttrDynamicHostVolumes is the node attribute set on clients that
+	// create the host volumes requested by jobs
+	NodeAttrDynamicHostVolumes = "nomad.dynamic_host_volumes"
+
+	// NodeAttrDynamicHostVolumesMaxSize is the node attribute with the maximum
+	// size in MB of a host volume created by the client, if limited
+	NodeAttrDynamicHostVolumesMaxSize = "nomad.dynamic_host_volumes.max_size"
+
+	// NodeAttrDynamicHostVolumesPlugin is the node attribute with the ID of
+	// the host volume plugin that creates the host volumes requested by jobs
+	NodeAttrDynamicHostVolumesPlugin = "nomad.dynamic_host_volumes.plugin"
+)
+
 // ClientHostVolumeConfig is used to configure access to host paths on a Nomad Client
 type ClientHostVolumeConfig struct {
 	Name     string `hcl:",key"`
@@ -101,6 +115,16 @@ type VolumeRequest struct {
 	AttachmentMode CSIVolumeAttachmentMode
 	MountOptions   *CSIMountOptions
 	PerAlloc       bool
+
+	// Create requests that the client creates the host volume if it doesn't
+	// exist on the node. SizeMB is the size of the created volume in MB.
+	Create bool
+	SizeMB int
+
+	// Affinity is the weight (0-100) the scheduler gives to nodes where a
+	// CSI volume is already staged or attached, so that rescheduled
+	// allocations avoid detaching and re-attaching the volume.
+	Affinity int8
 }
 
 func (v *VolumeRequest) Equal(o *VolumeRequest) bool {
@@ -124,6 +148,12 @@ func (v *VolumeRequest) Equal(o *VolumeRequest) bool {
 		return false
 	case v.PerAlloc != o.PerAlloc:
 		return false
+	case v.Create != o.Create:
+		return false
+	case v.SizeMB != o.SizeMB:
+		return false
+	case v.Affinity != o.Affinity:
+		return false
 	}
 	return true
 }
@@ -163,8 +193,23 @@ func (v *VolumeRequest) Validate(jobType string, taskGroupCount, canaries int) e
 		if v.MountOptions != nil {
 			addErr("host volumes cannot have mount options")
 		}
+		if v.SizeMB < 0 {
+			addErr("host volume size must not be negative")
+		}
+		if v.SizeMB > 0 && !v.Create {
+			addErr("host volume size can only be set when the volume is created")
+		}
+		if v.Affinity != 0 {
+			addErr("host volumes cannot have an affinity")
+		}
 
 	case VolumeTypeCSI:
+		if v.Create || v.SizeMB != 0 {
+			addErr("CSI volumes cannot be created by the client")
+		}
+		if v.Affinity < 0 || v.Affinity > 100 {
+			addErr("CSI volume affinity must be between 0 and 100")
+		}
 
 		switch v.AttachmentMode {
 		case CSIVolumeAttachmentModeUnknown:
@@ -5,13 +5,18 @@ package structs
 
 import (
 	"fmt"
+	"maps"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/go-jose/go-jose/v3/jwt"
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/helper/uuid"
 )
 
 const (
@@ -47,6 +52,14 @@ const (
 
 	// WIChangeModeRestart restarts the task when a new token is retrieved.
 	WIChangeModeRestart = "restart"
+
+	// ChildIdentityDefaultTTL is the TTL of child identities when none is
+	// requested.
+	ChildIdentityDefaultTTL = 5 * time.Minute
+
+	// ChildIdentityMaxTTL is the maximum TTL of child identities. Child
+	// identities are intended to be short-lived and refreshed as needed.
+	ChildIdentityMaxTTL = time.Hour
 )
 
 var (
@@ -54,6 +67,19 @@ var (
 	// be safe to use in filenames.
 	validIdentityName = regexp.MustCompile("^[a-zA-Z0-9-_]{1,128}$")
 
+	// validIdentityClaimName is used to validate the names of the extra claims
+	// of workload identities.
+	validIdentityClaimName = regexp.MustCompile("^[a-zA-Z0-9-_:./]{1,128}$")
+
+	// identityClaimInterpolation matches the ${...} interpolations in the
+	// values of extra claims.
+	identityClaimInterpolation = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+	// reservedIdentityClaims are the registered JWT claims set by Nomad, which
+	// can't be overridden by extra claims. Claims prefixed with "nomad_",
+	// "consul_", or "vault_" are reserved as well.
+	reservedIdentityClaims = []string{"iss", "sub", "aud", "exp", "nbf", "iat", "jti"}
+
 	// MinNomadVersionVaultWID is the minimum version of Nomad that supports
 	// workload identities for Vault.
 	// "-a" is used here so that it is "less than" all pre-release versions of
@@ -96,6 +122,14 @@ type WorkloadIdentity struct {
 	// TTL is used to determine the expiration of the credentials created for
 	// this identity (eg the JWT "exp" claim).
 	TTL time.Duration
+
+	// ExtraClaims are additional claims added to the identity, for use by
+	// third-party services such as cloud provider identity federation. The
+	// values may interpolate the node the workload runs on with
+	// ${node.unique.id}, ${node.unique.name}, ${node.datacenter},
+	// ${node.class}, ${node.pool}, and ${meta.<key>}, and the task meta with
+	// ${NOMAD_META_<key>}.
+	ExtraClaims map[string]string
 }
 
 // IsConsul returns true if the identity name starts with the standard prefix
@@ -124,6 +158,7 @@ func (wi *WorkloadIdentity) Copy() *WorkloadIdentity {
 		File:         wi.File,
 		ServiceName:  wi.ServiceName,
 		TTL:          wi.TTL,
+		ExtraClaims:  maps.Clone(wi.ExtraClaims),
 	}
 }
 
@@ -164,6 +199,10 @@ func (wi *WorkloadIdentity) Equal(other *WorkloadIdentity) bool {
 		return false
 	}
 
+	if !maps.Equal(wi.ExtraClaims, other.ExtraClaims) {
+		return false
+	}
+
 	return true
 }
 
@@ -228,9 +267,85 @@ func (wi *WorkloadIdentity) Validate() error {
 		mErr.Errors = append(mErr.Errors, fmt.Errorf("ttl must be >= 0"))
 	}
 
+	if len(wi.ExtraClaims) > 0 && (wi.Name == "" || wi.Name == WorkloadIdentityDefaultName) {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("extra_claims for default identity not supported"))
+	}
+
+	for name, value := range wi.ExtraClaims {
+		if !validIdentityClaimName.MatchString(name) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid claim name %q. Must match regex %s",
+				name, validIdentityClaimName))
+		} else if isReservedIdentityClaim(name) {
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("claim %q is reserved", name))
+		}
+
+		for _, match := range identityClaimInterpolation.FindAllStringSubmatch(value, -1) {
+			if _, ok := identityClaimVar(match[1], nil, nil); !ok {
+				mErr.Errors = append(mErr.Errors, fmt.Errorf("claim %q: unsupported interpolation %q",
+					name, match[0]))
+			}
+		}
+	}
+
 	return mErr.ErrorOrNil()
 }
 
+// isReservedIdentityClaim returns true if the claim is set by Nomad and can't
+// be used as an extra claim.
+func isReservedIdentityClaim(name string) bool {
+	return slices.Contains(reservedIdentityClaims, name) ||
+		strings.HasPrefix(name, "nomad_") ||
+		strings.HasPrefix(name, "consul_") ||
+		strings.HasPrefix(name, "vault_")
+}
+
+// identityClaimVar returns the value of a variable interpolated in an extra
+// claim, and whether the variable is supported. Node variables are empty if
+// the node is nil.
+func identityClaimVar(name string, taskMeta map[string]string, node *Node) (string, bool) {
+	if key, ok := strings.CutPrefix(name, "NOMAD_META_"); ok {
+		return taskMeta[key], true
+	}
+
+	if node == nil {
+		node = &Node{}
+	}
+	if key, ok := strings.CutPrefix(name, "meta."); ok {
+		return node.Meta[key], true
+	}
+
+	switch name {
+	case "node.unique.id":
+		return node.ID, true
+	case "node.unique.name":
+		return node.Name, true
+	case "node.datacenter":
+		return node.Datacenter, true
+	case "node.class":
+		return node.NodeClass, true
+	case "node.pool":
+		return node.NodePool, true
+	}
+	return "", false
+}
+
+// InterpolateExtraClaims returns the extra claims of the identity with the
+// task meta and node variables interpolated.
+func (wi *WorkloadIdentity) InterpolateExtraClaims(taskMeta map[string]string, node *Node) map[string]string {
+	if len(wi.ExtraClaims) == 0 {
+		return nil
+	}
+
+	claims := make(map[string]string, len(wi.ExtraClaims))
+	for name, value := range wi.ExtraClaims {
+		claims[name] = identityClaimInterpolation.ReplaceAllStringFunc(value, func(match string) string {
+			v, _ := identityClaimVar(match[2:len(match)-1], taskMeta, node)
+			return v
+		})
+	}
+	return claims
+}
+
 func (wi *WorkloadIdentity) Warnings() error {
 	if wi == nil {
 		return fmt.Errorf("must not be nil")
@@ -296,6 +411,98 @@ type AllocIdentitiesResponse struct {
 	QueryMeta
 }
 
+// ChildIdentityRequest is the RPC arguments for creating a child identity from
+// the workload identity used to authenticate the request.
+type ChildIdentityRequest struct {
+	// Capabilities is the list of namespace capabilities the child identity
+	// is scoped to. The child identity is only ever granted the capabilities
+	// that the parent workload is also granted. An empty list creates an
+	// identity that may only use the access implicitly granted to all
+	// workloads, such as reading service registrations.
+	Capabilities []string
+
+	// TTL is how long the child identity is valid for. Defaults to
+	// ChildIdentityDefaultTTL and may not exceed ChildIdentityMaxTTL.
+	TTL time.Duration
+
+	QueryOptions
+}
+
+// Canonicalize sets default values for unset request fields.
+func (r *ChildIdentityRequest) Canonicalize() {
+	if r.TTL == 0 {
+		r.TTL = ChildIdentityDefaultTTL
+	}
+}
+
+// Validate ensures the requested scope and TTL are valid. Canonicalize must be
+// called first.
+func (r *ChildIdentityRequest) Validate() error {
+	var mErr multierror.Error
+	if r.TTL <= 0 || r.TTL > ChildIdentityMaxTTL {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("ttl must be greater than 0 and at most %s", ChildIdentityMaxTTL))
+	}
+	scope := &IdentityScope{Capabilities: r.Capabilities}
+	if _, err := acl.Parse(scope.PolicyRules(DefaultNamespace)); err != nil {
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid capabilities: %w", err))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// ChildIdentityResponse is the RPC response for a created child identity.
+type ChildIdentityResponse struct {
+	JWT        string
+	Expiration time.Time
+	QueryMeta
+}
+
+// IdentityScope restricts what a workload identity is allowed to do. It is
+// only set on child identities.
+type IdentityScope struct {
+	Capabilities []string `json:"capabilities"`
+}
+
+// PolicyRules returns the ACL policy rules granting the scope's capabilities
+// in the given namespace.
+func (s *IdentityScope) PolicyRules(namespace string) string {
+	quoted := make([]string, len(s.Capabilities))
+	for i, c := range s.Capabilities {
+		quoted[i] = strconv.Quote(c)
+	}
+	return fmt.Sprintf("namespace %q {\n  capabilities = [%s]\n}\n",
+		namespace, strings.Join(quoted, ", "))
+}
+
+// NewChildIdentityClaims returns claims for a child identity of the parent
+// claims restricted to the given scope. Claims for third parties, such as Consul
+// and Vault, are not inherited so that child identities may only be used with
+// Nomad. The child identity never outlives its parent.
+func NewChildIdentityClaims(parent *IdentityClaims, scope *IdentityScope, ttl time.Duration, now time.Time) *IdentityClaims {
+	jwtnow := jwt.NewNumericDate(now.UTC())
+	claims := &IdentityClaims{
+		Namespace:    parent.Namespace,
+		JobID:        parent.JobID,
+		AllocationID: parent.AllocationID,
+		TaskName:     parent.TaskName,
+		ServiceName:  parent.ServiceName,
+		Scope:        scope,
+		Claims: jwt.Claims{
+			ID:        uuid.Generate(),
+			Subject:   parent.Subject,
+			Audience:  jwt.Audience{WorkloadIdentityDefaultAud},
+			NotBefore: jwtnow,
+			IssuedAt:  jwtnow,
+			Expiry:    jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+
+	if parent.Expiry != nil && parent.Expiry.Time().Before(claims.Expiry.Time()) {
+		claims.Expiry = parent.Expiry
+	}
+
+	return claims
+}
+
 type WorkloadType int
 
 const (
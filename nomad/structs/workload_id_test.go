@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/go-jose/go-jose/v3/jwt"
 	"github.com/hashicorp/nomad/ci"
 	"github.com/shoenig/test/must"
 )
@@ -244,6 +245,52 @@ func TestWorkloadIdentity_Validate(t *testing.T) {
 			},
 			Warn: "identities without an expiration are insecure",
 		},
+		{
+			Desc: "Ok extra claims",
+			In: WorkloadIdentity{
+				Name:     "aws",
+				Audience: []string{"sts.amazonaws.com"},
+				TTL:      time.Hour,
+				ExtraClaims: map[string]string{
+					"team":       "${NOMAD_META_team}",
+					"node_class": "${node.class}",
+					"rack":       "rack-${meta.rack}",
+				},
+			},
+			Exp: WorkloadIdentity{
+				Name:     "aws",
+				Audience: []string{"sts.amazonaws.com"},
+				TTL:      time.Hour,
+				ExtraClaims: map[string]string{
+					"team":       "${NOMAD_META_team}",
+					"node_class": "${node.class}",
+					"rack":       "rack-${meta.rack}",
+				},
+			},
+		},
+		{
+			Desc: "Extra claims on default identity",
+			In: WorkloadIdentity{
+				ExtraClaims: map[string]string{"team": "web"},
+			},
+			Err: "extra_claims for default identity not supported",
+		},
+		{
+			Desc: "Reserved extra claim",
+			In: WorkloadIdentity{
+				Name:        "foo",
+				ExtraClaims: map[string]string{"nomad_job_id": "other"},
+			},
+			Err: `claim "nomad_job_id" is reserved`,
+		},
+		{
+			Desc: "Unsupported extra claim interpolation",
+			In: WorkloadIdentity{
+				Name:        "foo",
+				ExtraClaims: map[string]string{"kernel": "${attr.kernel.name}"},
+			},
+			Err: `claim "kernel": unsupported interpolation "${attr.kernel.name}"`,
+		},
 	}
 
 	for _, tc := range cases {
@@ -272,6 +319,41 @@ func TestWorkloadIdentity_Validate(t *testing.T) {
 	}
 }
 
+func TestWorkloadIdentity_InterpolateExtraClaims(t *testing.T) {
+	ci.Parallel(t)
+
+	wid := &WorkloadIdentity{
+		Name: "gcp",
+		ExtraClaims: map[string]string{
+			"team":     "${NOMAD_META_team}",
+			"location": "${node.datacenter}/${meta.rack}",
+			"node":     "${node.unique.name}",
+			"static":   "value",
+			"missing":  "${NOMAD_META_missing}",
+		},
+	}
+
+	node := &Node{
+		Name:       "node-1",
+		Datacenter: "dc1",
+		Meta:       map[string]string{"rack": "r1"},
+	}
+	claims := wid.InterpolateExtraClaims(map[string]string{"team": "web"}, node)
+	must.Eq(t, map[string]string{
+		"team":     "web",
+		"location": "dc1/r1",
+		"node":     "node-1",
+		"static":   "value",
+		"missing":  "",
+	}, claims)
+
+	// Node variables are empty without a node.
+	claims = wid.InterpolateExtraClaims(nil, nil)
+	must.Eq(t, "/", claims["location"])
+
+	must.Nil(t, (&WorkloadIdentity{Name: "foo"}).InterpolateExtraClaims(nil, node))
+}
+
 func TestWorkloadIdentity_Nil(t *testing.T) {
 	ci.Parallel(t)
 
@@ -288,3 +370,51 @@ func TestWorkloadIdentity_Nil(t *testing.T) {
 
 	must.Error(t, nilWID.Warnings())
 }
+
+func TestChildIdentityRequest_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	req := &ChildIdentityRequest{}
+	req.Canonicalize()
+	must.Eq(t, ChildIdentityDefaultTTL, req.TTL)
+	must.NoError(t, req.Validate())
+
+	req.Capabilities = []string{"read-job", "list-jobs"}
+	must.NoError(t, req.Validate())
+
+	req.Capabilities = []string{"read-job", "bogus"}
+	must.ErrorContains(t, req.Validate(), "invalid capabilities")
+
+	req.Capabilities = nil
+	req.TTL = ChildIdentityMaxTTL + time.Second
+	must.ErrorContains(t, req.Validate(), "ttl must be")
+}
+
+func TestNewChildIdentityClaims(t *testing.T) {
+	ci.Parallel(t)
+
+	now := time.Now()
+	parent := &IdentityClaims{
+		Namespace:       "default",
+		JobID:           "example",
+		AllocationID:    "d0f3e6a4-4b6c-5b8a-3ec0-2f9a4b5f6c7d",
+		TaskName:        "web",
+		ConsulNamespace: "consul-ns",
+		VaultRole:       "vault-role",
+	}
+	scope := &IdentityScope{Capabilities: []string{"read-job"}}
+
+	claims := NewChildIdentityClaims(parent, scope, time.Minute, now)
+	must.Eq(t, parent.AllocationID, claims.AllocationID)
+	must.Eq(t, parent.TaskName, claims.TaskName)
+	must.Eq(t, scope, claims.Scope)
+	must.Eq(t, "", claims.ConsulNamespace)
+	must.Eq(t, "", claims.VaultRole)
+	must.NotEq(t, "", claims.ID)
+	must.Eq(t, now.Add(time.Minute).Unix(), claims.Expiry.Time().Unix())
+
+	// Child identities never outlive their parent
+	parent.Expiry = jwt.NewNumericDate(now.Add(time.Second))
+	claims = NewChildIdentityClaims(parent, scope, time.Minute, now)
+	must.Eq(t, parent.Expiry, claims.Expiry)
+}
@@ -0,0 +1,277 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package usage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+)
+
+const (
+	// defaultSampleInterval is how often the reserved resources of the
+	// running allocations are sampled and the accounted usage is committed
+	// to raft.
+	defaultSampleInterval = time.Minute
+
+	// maxReportInterval is the longest interval a client usage report is
+	// accounted for. Clients that didn't report for longer were likely
+	// disconnected, and their last usage isn't representative of the whole
+	// interval.
+	maxReportInterval = 2 * structs.AllocUsageReportInterval
+)
+
+// usageKey identifies the usage record of a job for a period.
+type usageKey struct {
+	namespace string
+	jobID     string
+	start     int64
+}
+
+// Accountant runs on the leader and accounts the resources reserved and used
+// by jobs over time. The reserved resources of the running allocations are
+// sampled periodically, and the used resources are reported by the clients.
+// The accounted usage is aggregated in memory and periodically added to the
+// usage records in raft, so usage accounted since the last commit is lost
+// when leadership is lost.
+type Accountant struct {
+	enabled bool
+	logger  log.Logger
+
+	// raft is used to commit the accounted usage.
+	raft UsageApplier
+
+	// state is the state store the allocations are read from.
+	state *state.StateStore
+
+	// resolution is the length of the periods usage is aggregated in and
+	// retention is how long the usage records are kept.
+	resolution time.Duration
+	retention  time.Duration
+
+	// sampleInterval is how often the reserved resources are sampled and the
+	// usage is committed.
+	sampleInterval time.Duration
+
+	// lastSample is the time the reserved resources were last sampled.
+	lastSample time.Time
+
+	// pending is the usage accounted since the last commit.
+	pending map[usageKey]*structs.UsageRecord
+
+	// now returns the current time and may be replaced in tests.
+	now func() time.Time
+
+	// ctx and exitFn are used to stop the accountant goroutine.
+	ctx    context.Context
+	exitFn context.CancelFunc
+
+	l sync.Mutex
+}
+
+// NewAccountant returns a usage accountant for the given configuration, which
+// may be nil to use the defaults. It must be enabled on the leader to account
+// usage.
+func NewAccountant(logger log.Logger, raft UsageApplier, conf *config.UsageConfig) *Accountant {
+	if conf == nil {
+		conf = &config.UsageConfig{}
+	}
+	conf = conf.Copy()
+	conf.Canonicalize()
+
+	ctx, exitFn := context.WithCancel(context.Background())
+	return &Accountant{
+		logger:         logger.Named("usage"),
+		raft:           raft,
+		resolution:     conf.Resolution,
+		retention:      conf.Retention,
+		sampleInterval: min(defaultSampleInterval, conf.Resolution),
+		pending:        make(map[usageKey]*structs.UsageRecord),
+		now:            time.Now,
+		ctx:            ctx,
+		exitFn:         exitFn,
+	}
+}
+
+// SetEnabled is used to control if the accountant is enabled. The accountant
+// should only be enabled on the active leader. The state store is passed in
+// when enabling it, as it is no longer valid once a leader election has
+// taken place.
+func (a *Accountant) SetEnabled(enabled bool, state *state.StateStore) {
+	a.l.Lock()
+	defer a.l.Unlock()
+
+	a.enabled = enabled
+	if state != nil {
+		a.state = state
+	}
+
+	// Stop the accountant and discard the usage that wasn't committed.
+	a.exitFn()
+	a.ctx, a.exitFn = context.WithCancel(context.Background())
+	a.pending = make(map[usageKey]*structs.UsageRecord)
+
+	if enabled {
+		a.lastSample = a.now()
+		go a.run(a.ctx)
+	}
+}
+
+// Enabled returns whether the accountant is enabled.
+func (a *Accountant) Enabled() bool {
+	a.l.Lock()
+	defer a.l.Unlock()
+	return a.enabled
+}
+
+// RecordUsage accounts the resources used by the allocations of a node, as
+// reported by its client for the given interval. Allocations that aren't
+// running on the node are ignored.
+func (a *Accountant) RecordUsage(nodeID string, usage []*structs.AllocUsage, interval time.Duration) error {
+	a.l.Lock()
+	defer a.l.Unlock()
+
+	if !a.enabled {
+		return nil
+	}
+
+	seconds := uint64(min(interval, maxReportInterval) / time.Second)
+	start := structs.UsagePeriodStart(a.now(), a.resolution)
+
+	for _, u := range usage {
+		alloc, err := a.state.AllocByID(nil, u.AllocID)
+		if err != nil {
+			return err
+		}
+		if alloc == nil || alloc.NodeID != nodeID {
+			continue
+		}
+
+		record := a.record(alloc.Namespace, alloc.JobID, start)
+		record.UsedCPU += u.CPU * seconds
+		record.UsedMemory += u.MemoryMB * seconds
+	}
+	return nil
+}
+
+// record returns the pending usage record of the job for the period. The
+// lock must be held.
+func (a *Accountant) record(namespace, jobID string, start int64) *structs.UsageRecord {
+	key := usageKey{namespace: namespace, jobID: jobID, start: start}
+	record, ok := a.pending[key]
+	if !ok {
+		record = &structs.UsageRecord{
+			Namespace:  namespace,
+			JobID:      jobID,
+			Start:      start,
+			Resolution: a.resolution,
+		}
+		a.pending[key] = record
+	}
+	return record
+}
+
+// run is the long lived goroutine that samples the reserved resources and
+// commits the accounted usage.
+func (a *Accountant) run(ctx context.Context) {
+	ticker := time.NewTicker(a.sampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := a.sample(ctx); err != nil {
+			a.logger.Error("failed to sample reserved resources", "error", err)
+		}
+		if err := a.commit(ctx); err != nil {
+			a.logger.Warn("failed to commit usage", "error", err)
+		}
+	}
+}
+
+// sample accounts the resources reserved by the running allocations since
+// the last sample.
+func (a *Accountant) sample(ctx context.Context) error {
+	a.l.Lock()
+	defer a.l.Unlock()
+
+	// The accountant may have been disabled while waiting for the lock.
+	if !a.enabled || ctx.Err() != nil {
+		return nil
+	}
+
+	now := a.now()
+	elapsed := min(now.Sub(a.lastSample), 2*a.sampleInterval)
+	a.lastSample = now
+	seconds := uint64(elapsed / time.Second)
+	if seconds == 0 {
+		return nil
+	}
+	start := structs.UsagePeriodStart(now, a.resolution)
+
+	iter, err := a.state.Allocs(nil, state.SortDefault)
+	if err != nil {
+		return err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		alloc := raw.(*structs.Allocation)
+		if alloc.ClientStatus != structs.AllocClientStatusRunning || alloc.AllocatedResources == nil {
+			continue
+		}
+
+		resources := alloc.AllocatedResources.Comparable()
+		record := a.record(alloc.Namespace, alloc.JobID, start)
+		record.ReservedCPU += uint64(resources.Flattened.Cpu.CpuShares) * seconds
+		record.ReservedMemory += uint64(resources.Flattened.Memory.MemoryMB) * seconds
+		record.ReservedDisk += uint64(resources.Shared.DiskMB) * seconds
+	}
+	return nil
+}
+
+// commit adds the usage accounted since the last commit to the usage records
+// and purges the records past their retention. Usage that fails to be
+// committed is retried on the next commit.
+func (a *Accountant) commit(ctx context.Context) error {
+	a.l.Lock()
+	if !a.enabled || ctx.Err() != nil || len(a.pending) == 0 {
+		a.l.Unlock()
+		return nil
+	}
+
+	req := &structs.UsageUpsertRequest{
+		Usage: make([]*structs.UsageRecord, 0, len(a.pending)),
+	}
+	for _, record := range a.pending {
+		req.Usage = append(req.Usage, record)
+	}
+	req.PurgeBefore = a.now().Add(-a.retention).UnixNano()
+	pending := a.pending
+	a.pending = make(map[usageKey]*structs.UsageRecord)
+	a.l.Unlock()
+
+	defer metrics.MeasureSince([]string{"nomad", "usage", "commit"}, time.Now())
+	if _, err := a.raft.UpsertUsage(req); err != nil {
+		a.l.Lock()
+		defer a.l.Unlock()
+
+		// Merge the usage back unless the accountant was reset meanwhile.
+		if ctx.Err() == nil {
+			for key, record := range pending {
+				a.record(key.namespace, key.jobID, key.start).Add(record)
+			}
+		}
+		return err
+	}
+	return nil
+}
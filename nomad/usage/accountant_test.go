@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package usage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/testlog"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/shoenig/test/must"
+)
+
+// testUsageApplier commits the usage to the state store like the raft shim
+// of the server, or fails when err is set.
+type testUsageApplier struct {
+	state *state.StateStore
+	index uint64
+	err   error
+}
+
+func (a *testUsageApplier) UpsertUsage(req *structs.UsageUpsertRequest) (uint64, error) {
+	if a.err != nil {
+		return 0, a.err
+	}
+	a.index++
+	return a.index, a.state.UpsertUsage(structs.MsgTypeTestSetup, a.index, req)
+}
+
+func TestAccountant_SampleAndCommit(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+
+	running := mock.Alloc()
+	running.ClientStatus = structs.AllocClientStatusRunning
+	pending := mock.Alloc()
+	pending.Job = running.Job
+	pending.JobID = running.JobID
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 99, nil, running.Job))
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 100,
+		[]*structs.Allocation{running, pending}))
+
+	now := time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)
+	applier := &testUsageApplier{state: store, index: 1000}
+	a := NewAccountant(testlog.HCLogger(t), applier, &config.UsageConfig{
+		Resolution: time.Hour,
+		Retention:  24 * time.Hour,
+	})
+	a.now = func() time.Time { return now }
+
+	// Enable the accountant without its goroutine to drive it manually.
+	a.enabled = true
+	a.state = store
+	a.lastSample = now
+	ctx := context.Background()
+
+	now = now.Add(time.Minute)
+	must.NoError(t, a.sample(ctx))
+
+	// Used resources are only accounted for allocations of the node.
+	must.NoError(t, a.RecordUsage(running.NodeID, []*structs.AllocUsage{
+		{AllocID: running.ID, CPU: 100, MemoryMB: 64},
+	}, time.Minute))
+	must.NoError(t, a.RecordUsage("other-node", []*structs.AllocUsage{
+		{AllocID: running.ID, CPU: 100, MemoryMB: 64},
+	}, time.Minute))
+
+	// Usage that fails to be committed is retried.
+	applier.err = errors.New("no leader")
+	must.Error(t, a.commit(ctx))
+	applier.err = nil
+	must.NoError(t, a.commit(ctx))
+
+	iter, err := store.UsageRecordsByJob(nil, running.Namespace, running.JobID)
+	must.NoError(t, err)
+	raw := iter.Next()
+	must.NotNil(t, raw)
+	must.Nil(t, iter.Next())
+
+	resources := running.AllocatedResources.Comparable()
+	record := raw.(*structs.UsageRecord)
+	must.Eq(t, structs.UsagePeriodStart(now, time.Hour), record.Start)
+	must.Eq(t, time.Hour, record.Resolution)
+	must.Eq(t, uint64(resources.Flattened.Cpu.CpuShares)*60, record.ReservedCPU)
+	must.Eq(t, uint64(resources.Flattened.Memory.MemoryMB)*60, record.ReservedMemory)
+	must.Eq(t, uint64(resources.Shared.DiskMB)*60, record.ReservedDisk)
+	must.Eq(t, 100*60, record.UsedCPU)
+	must.Eq(t, 64*60, record.UsedMemory)
+
+	// Records past the retention are purged on the next commit.
+	now = now.Add(48 * time.Hour)
+	a.lastSample = now.Add(-time.Minute)
+	must.NoError(t, a.sample(ctx))
+	must.NoError(t, a.commit(ctx))
+
+	iter, err = store.UsageRecordsByJob(nil, running.Namespace, running.JobID)
+	must.NoError(t, err)
+	raw = iter.Next()
+	must.NotNil(t, raw)
+	must.Eq(t, structs.UsagePeriodStart(now, time.Hour), raw.(*structs.UsageRecord).Start)
+	must.Nil(t, iter.Next())
+}
+
+func TestAccountant_Disabled(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+	alloc := mock.Alloc()
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 99, nil, alloc.Job))
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 100, []*structs.Allocation{alloc}))
+
+	applier := &testUsageApplier{state: store, index: 1000}
+	a := NewAccountant(testlog.HCLogger(t), applier, nil)
+	a.SetEnabled(false, store)
+	must.False(t, a.Enabled())
+
+	must.NoError(t, a.RecordUsage(alloc.NodeID, []*structs.AllocUsage{
+		{AllocID: alloc.ID, CPU: 100, MemoryMB: 64},
+	}, time.Minute))
+	must.MapEmpty(t, a.pending)
+}
@@ -0,0 +1,15 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package usage
+
+import "github.com/hashicorp/nomad/nomad/structs"
+
+// UsageApplier is a minimal interface of the Server used to commit the
+// accounted usage to raft, which avoids circular references between the
+// nomad package and the usage package.
+type UsageApplier interface {
+	// UpsertUsage adds the accounted usage to the usage records and purges
+	// the records past their retention.
+	UpsertUsage(req *structs.UsageUpsertRequest) (uint64, error)
+}
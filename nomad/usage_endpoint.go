@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// Usage endpoint is used to query the resource usage accounted for jobs and
+// namespaces.
+type Usage struct {
+	srv *Server
+	ctx *RPCContext
+}
+
+func NewUsageEndpoint(srv *Server, ctx *RPCContext) *Usage {
+	return &Usage{srv: srv, ctx: ctx}
+}
+
+// List is used to retrieve the usage records of a namespace, or of every
+// namespace the token can read jobs in when querying the wildcard namespace.
+// The records can be filtered by job and period, and grouped by namespace.
+func (u *Usage) List(args *structs.UsageListRequest, reply *structs.UsageListResponse) error {
+	authErr := u.srv.Authenticate(u.ctx, args)
+	if done, err := u.srv.forward("Usage.List", args, args, reply); done {
+		return err
+	}
+	u.srv.MeasureRPCRate("usage", structs.RateMetricList, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "usage", "list"}, time.Now())
+
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
+	}
+
+	namespace := args.RequestNamespace()
+
+	// Check namespace read-job permissions
+	aclObj, err := u.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !aclObj.AllowNsOp(namespace, acl.NamespaceCapabilityReadJob) {
+		return structs.ErrPermissionDenied
+	}
+	allow := aclObj.AllowNsOpFunc(acl.NamespaceCapabilityReadJob)
+
+	// Setup the blocking query
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			reply.Usage = make([]*structs.UsageRecord, 0)
+
+			allowableNamespaces, err := allowedNSes(aclObj, store, allow)
+			if err != nil && err != structs.ErrPermissionDenied {
+				return err
+			} else if err == nil {
+				var iter memdb.ResultIterator
+				switch {
+				case args.JobID != "":
+					iter, err = store.UsageRecordsByJob(ws, namespace, args.JobID)
+				case namespace != structs.AllNamespacesSentinel:
+					iter, err = store.UsageRecordsByNamespace(ws, namespace)
+				default:
+					iter, err = store.UsageRecords(ws)
+				}
+				if err != nil {
+					return err
+				}
+
+				reply.Usage = usageRecords(iter, args, allowableNamespaces)
+			}
+
+			// Use the last index that affected the usage table
+			index, err := store.Index(state.TableUsage)
+			if err != nil {
+				return err
+			}
+			reply.Index = max(1, index)
+
+			// Set the query response
+			u.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return u.srv.blockingRPC(&opts)
+}
+
+// usageRecords returns the usage records of the iterator matching the
+// request, grouped as requested and sorted by namespace, job, and period.
+func usageRecords(iter memdb.ResultIterator, args *structs.UsageListRequest,
+	allowableNamespaces map[string]bool) []*structs.UsageRecord {
+
+	type namespacePeriod struct {
+		namespace string
+		start     int64
+	}
+	byNamespace := make(map[namespacePeriod]*structs.UsageRecord)
+
+	records := make([]*structs.UsageRecord, 0)
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		record := raw.(*structs.UsageRecord)
+		if allowableNamespaces != nil && !allowableNamespaces[record.Namespace] {
+			continue
+		}
+		if record.Start < args.Start || (args.End != 0 && record.Start >= args.End) {
+			continue
+		}
+
+		if args.GroupBy != structs.UsageGroupByNamespace {
+			records = append(records, record)
+			continue
+		}
+
+		key := namespacePeriod{namespace: record.Namespace, start: record.Start}
+		if group, ok := byNamespace[key]; ok {
+			group.Add(record)
+			group.CreateIndex = min(group.CreateIndex, record.CreateIndex)
+			group.ModifyIndex = max(group.ModifyIndex, record.ModifyIndex)
+			continue
+		}
+
+		group := record.Copy()
+		group.JobID = ""
+		byNamespace[key] = group
+		records = append(records, group)
+	}
+
+	slices.SortFunc(records, func(a, b *structs.UsageRecord) int {
+		if c := cmp.Compare(a.Namespace, b.Namespace); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.JobID, b.JobID); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Start, b.Start)
+	})
+	return records
+}
@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/acl"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/helper/pointer"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/nomad/structs/config"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestUsageEndpoint_List(t *testing.T) {
+	ci.Parallel(t)
+
+	s, root, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	store := s.fsm.State()
+
+	ns := mock.Namespace()
+	ns.Name = "ops"
+	must.NoError(t, store.UpsertNamespaces(1000, []*structs.Namespace{ns}))
+
+	hour := int64(time.Hour)
+	must.NoError(t, store.UpsertUsage(structs.MsgTypeTestSetup, 1001, &structs.UsageUpsertRequest{
+		Usage: []*structs.UsageRecord{
+			{Namespace: "default", JobID: "web", Start: hour, ReservedCPU: 10},
+			{Namespace: "default", JobID: "web", Start: 2 * hour, ReservedCPU: 20},
+			{Namespace: "default", JobID: "api", Start: hour, ReservedCPU: 30},
+			{Namespace: "ops", JobID: "web", Start: hour, ReservedCPU: 40},
+		},
+	}))
+
+	req := &structs.UsageListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.AllNamespacesSentinel,
+			AuthToken: root.SecretID,
+		},
+	}
+	var resp structs.UsageListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Usage.List", req, &resp))
+	must.Eq(t, 1001, resp.Index)
+	must.Len(t, 4, resp.Usage)
+	must.Eq(t, "api", resp.Usage[0].JobID)
+
+	// Filter by job and period.
+	req.Namespace = "default"
+	req.JobID = "web"
+	req.Start = 2 * hour
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Usage.List", req, &resp))
+	must.Len(t, 1, resp.Usage)
+	must.Eq(t, 20, resp.Usage[0].ReservedCPU)
+
+	// Group by namespace.
+	req.Namespace = structs.AllNamespacesSentinel
+	req.JobID = ""
+	req.Start = 0
+	req.End = 2 * hour
+	req.GroupBy = structs.UsageGroupByNamespace
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Usage.List", req, &resp))
+	must.Len(t, 2, resp.Usage)
+	must.Eq(t, "default", resp.Usage[0].Namespace)
+	must.Eq(t, "", resp.Usage[0].JobID)
+	must.Eq(t, 40, resp.Usage[0].ReservedCPU)
+	must.Eq(t, "ops", resp.Usage[1].Namespace)
+
+	// Tokens only see the namespaces they can read jobs in.
+	token := mock.CreatePolicyAndToken(t, store, 1002, "read-default",
+		mock.NamespacePolicy("default", "", []string{acl.NamespaceCapabilityReadJob}))
+	req.AuthToken = token.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Usage.List", req, &resp))
+	must.Len(t, 1, resp.Usage)
+	must.Eq(t, "default", resp.Usage[0].Namespace)
+
+	req.Namespace = "ops"
+	err := msgpackrpc.CallWithCodec(codec, "Usage.List", req, &resp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Invalid requests are rejected.
+	req.Namespace = "default"
+	req.AuthToken = root.SecretID
+	req.GroupBy = "node"
+	err = msgpackrpc.CallWithCodec(codec, "Usage.List", req, &resp)
+	must.ErrorContains(t, err, "invalid group by")
+}
+
+func TestClientEndpoint_UpdateAllocUsage(t *testing.T) {
+	ci.Parallel(t)
+
+	s, cleanupS := TestServer(t, func(c *Config) {
+		c.Usage = &config.UsageConfig{
+			Enabled:    pointer.Of(true),
+			Resolution: time.Second,
+		}
+	})
+	defer cleanupS()
+
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+	store := s.fsm.State()
+
+	node := mock.Node()
+	must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, 1000, node))
+	alloc := mock.Alloc()
+	alloc.NodeID = node.ID
+	alloc.ClientStatus = structs.AllocClientStatusRunning
+	must.NoError(t, store.UpsertJob(structs.MsgTypeTestSetup, 1001, nil, alloc.Job))
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1002, []*structs.Allocation{alloc}))
+
+	req := &structs.AllocUsageRequest{
+		NodeID:   node.ID,
+		Usage:    []*structs.AllocUsage{{AllocID: alloc.ID, CPU: 100, MemoryMB: 64}},
+		Interval: time.Minute,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: node.SecretID,
+		},
+	}
+	var resp structs.AllocUsageResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "Node.UpdateAllocUsage", req, &resp))
+	must.True(t, resp.Enabled)
+
+	// The used and reserved resources are committed.
+	testutil.WaitForResult(func() (bool, error) {
+		iter, err := store.UsageRecordsByJob(nil, alloc.Namespace, alloc.JobID)
+		if err != nil {
+			return false, err
+		}
+		var used, reserved uint64
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			used += raw.(*structs.UsageRecord).UsedCPU
+			reserved += raw.(*structs.UsageRecord).ReservedCPU
+		}
+		if used != 100*60 || reserved == 0 {
+			return false, fmt.Errorf("unexpected usage, used %d, reserved %d", used, reserved)
+		}
+		return true, nil
+	}, func(err error) {
+		t.Fatal(err)
+	})
+
+	// Clients can't report the usage of other nodes.
+	req.NodeID = mock.Node().ID
+	err := msgpackrpc.CallWithCodec(codec, "Node.UpdateAllocUsage", req, &resp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+}
@@ -0,0 +1,26 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// usageShim implements the usage.UsageApplier interface required by the
+// usage accountant.
+type usageShim struct {
+	s *Server
+}
+
+func (u usageShim) UpsertUsage(req *structs.UsageUpsertRequest) (uint64, error) {
+	if !ServersMeetMinimumVersion(u.s.serf.Members(), u.s.Region(), minUsageVersion, true) {
+		return 0, fmt.Errorf("all servers must be running version %v or later to account usage", minUsageVersion)
+	}
+
+	req.Region = u.s.config.Region
+	_, index, err := u.s.raftApply(structs.UsageUpsertRequestType, req)
+	return index, err
+}
@@ -55,6 +55,7 @@ type serverParts struct {
 	RPCAddr     net.Addr
 	Status      serf.MemberStatus
 	NonVoter    bool
+	ReadReplica bool
 
 	// Deprecated: Functionally unused but needs to always be set by 1 for
 	// compatibility with v1.2.x and earlier.
@@ -125,6 +126,7 @@ func isNomadServer(m serf.Member) (bool, *serverParts) {
 
 	// Check if the server is a non voter
 	_, nonVoter := m.Tags["nonvoter"]
+	_, readReplica := m.Tags[ReadReplicaTag]
 
 	addr := &net.TCPAddr{IP: m.Addr, Port: port}
 	rpcAddr := &net.TCPAddr{IP: rpcIP, Port: port}
@@ -142,6 +144,7 @@ func isNomadServer(m serf.Member) (bool, *serverParts) {
 		RaftVersion:  raftVsn,
 		Status:       m.Status,
 		NonVoter:     nonVoter,
+		ReadReplica:  readReplica,
 		MajorVersion: deprecatedAPIMajorVersion,
 	}
 	return true, parts
@@ -0,0 +1,268 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-memdb"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/state/paginator"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// VariableGrant endpoint is used for the management of the grants sharing
+// variables with the workloads of other namespaces.
+type VariableGrant struct {
+	srv *Server
+	ctx *RPCContext
+}
+
+func NewVariableGrantEndpoint(srv *Server, ctx *RPCContext) *VariableGrant {
+	return &VariableGrant{srv: srv, ctx: ctx}
+}
+
+// List is used to retrieve the variable grants of a namespace, or of all the
+// namespaces. It supports pagination and filtering.
+func (v *VariableGrant) List(args *structs.VariableGrantListRequest, reply *structs.VariableGrantListResponse) error {
+	authErr := v.srv.Authenticate(v.ctx, args)
+	if done, err := v.srv.forward("VariableGrant.List", args, args, reply); done {
+		return err
+	}
+	v.srv.MeasureRPCRate("variable_grant", structs.RateMetricList, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variable_grant", "list"}, time.Now())
+
+	// Resolve ACL token to only return the grants of the namespaces whose
+	// variables it has access to.
+	aclObj, err := v.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	namespace := args.RequestNamespace()
+
+	// Setup blocking query.
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			var err error
+			var iter memdb.ResultIterator
+
+			if namespace == structs.AllNamespacesSentinel {
+				iter, err = store.VariableGrants(ws)
+			} else {
+				iter, err = store.VariableGrantsByNamespace(ws, namespace)
+			}
+			if err != nil {
+				return err
+			}
+
+			pageOpts := paginator.StructsTokenizerOptions{WithNamespace: true, WithID: true}
+			tokenizer := paginator.NewStructsTokenizer(iter, pageOpts)
+			filters := []paginator.Filter{
+				// Filter out the grants of the namespaces the token has no
+				// variables access to.
+				paginator.GenericFilter{
+					Allow: func(raw interface{}) (bool, error) {
+						grant := raw.(*structs.VariableGrant)
+						return aclObj.AllowVariableSearch(grant.Namespace), nil
+					},
+				},
+			}
+
+			var grants []*structs.VariableGrant
+			pager, err := paginator.NewPaginator(iter, tokenizer, filters, args.QueryOptions,
+				func(raw interface{}) error {
+					grants = append(grants, raw.(*structs.VariableGrant))
+					return nil
+				})
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to create result paginator: %v", err)
+			}
+
+			nextToken, err := pager.Page()
+			if err != nil {
+				return structs.NewErrRPCCodedf(http.StatusBadRequest, "failed to read result page: %v", err)
+			}
+
+			reply.QueryMeta.NextToken = nextToken
+			reply.Grants = grants
+
+			// Use the last index that affected the variable grants table.
+			index, err := store.Index(state.TableVariableGrants)
+			if err != nil {
+				return err
+			}
+			reply.Index = max(1, index)
+
+			// Set the query response.
+			v.srv.setQueryMeta(&reply.QueryMeta)
+			return nil
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// GetVariableGrant returns the specific variable grant requested or nil if
+// the grant doesn't exist.
+func (v *VariableGrant) GetVariableGrant(args *structs.VariableGrantSpecificRequest, reply *structs.SingleVariableGrantResponse) error {
+	authErr := v.srv.Authenticate(v.ctx, args)
+	if done, err := v.srv.forward("VariableGrant.GetVariableGrant", args, args, reply); done {
+		return err
+	}
+	v.srv.MeasureRPCRate("variable_grant", structs.RateMetricRead, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variable_grant", "get_variable_grant"}, time.Now())
+
+	// Resolve ACL token and verify it has access to the variables of the
+	// namespace.
+	aclObj, err := v.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !aclObj.AllowVariableSearch(args.RequestNamespace()) {
+		return structs.ErrPermissionDenied
+	}
+
+	// Setup the blocking query.
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, store *state.StateStore) error {
+			grant, err := store.VariableGrantByName(ws, args.RequestNamespace(), args.Name)
+			if err != nil {
+				return err
+			}
+
+			reply.Grant = grant
+			if grant != nil {
+				reply.Index = grant.ModifyIndex
+			} else {
+				// Return the last index that affected the variable grants
+				// table if the requested grant doesn't exist.
+				index, err := store.Index(state.TableVariableGrants)
+				if err != nil {
+					return err
+				}
+				reply.Index = max(1, index)
+			}
+			return nil
+		}}
+	return v.srv.blockingRPC(&opts)
+}
+
+// UpsertVariableGrants creates or updates the given variable grants. As
+// grants give access to variables across namespaces, they can only be
+// managed with a management token.
+func (v *VariableGrant) UpsertVariableGrants(args *structs.VariableGrantUpsertRequest, reply *structs.GenericResponse) error {
+	authErr := v.srv.Authenticate(v.ctx, args)
+	if done, err := v.srv.forward("VariableGrant.UpsertVariableGrants", args, args, reply); done {
+		return err
+	}
+	v.srv.MeasureRPCRate("variable_grant", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variable_grant", "upsert_variable_grants"}, time.Now())
+
+	// Check management level permissions.
+	aclObj, err := v.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	} else if !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if !ServersMeetMinimumVersion(
+		v.srv.serf.Members(), v.srv.Region(), minVariableGrantsVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to upsert variable grants", minVariableGrantsVersion)
+	}
+
+	// Validate request.
+	if len(args.Grants) == 0 {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "must specify at least one variable grant")
+	}
+	snap, err := v.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	for _, grant := range args.Grants {
+		if grant.Namespace == "" {
+			grant.Namespace = args.RequestNamespace()
+		}
+		if err := grant.Validate(); err != nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "invalid variable grant %q: %v", grant.Name, err)
+		}
+		ns, err := snap.NamespaceByName(nil, grant.Namespace)
+		if err != nil {
+			return err
+		}
+		if ns == nil {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "variable grant %q: namespace %q not found", grant.Name, grant.Namespace)
+		}
+	}
+
+	// Update via Raft.
+	_, index, err := v.srv.raftApply(structs.VariableGrantUpsertRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
+// DeleteVariableGrants deletes the given variable grants of the request
+// namespace.
+func (v *VariableGrant) DeleteVariableGrants(args *structs.VariableGrantDeleteRequest, reply *structs.GenericResponse) error {
+	authErr := v.srv.Authenticate(v.ctx, args)
+	if done, err := v.srv.forward("VariableGrant.DeleteVariableGrants", args, args, reply); done {
+		return err
+	}
+	v.srv.MeasureRPCRate("variable_grant", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variable_grant", "delete_variable_grants"}, time.Now())
+
+	// Check management level permissions.
+	aclObj, err := v.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	} else if !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	if !ServersMeetMinimumVersion(
+		v.srv.serf.Members(), v.srv.Region(), minVariableGrantsVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to delete variable grants", minVariableGrantsVersion)
+	}
+
+	// Validate request.
+	if args.RequestNamespace() == structs.AllNamespacesSentinel {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "can not target wildcard (\"*\") namespace")
+	}
+	if len(args.Names) == 0 {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest, "must specify at least one variable grant to delete")
+	}
+	for _, name := range args.Names {
+		if name == "" {
+			return structs.NewErrRPCCodedf(http.StatusBadRequest, "variable grant name is empty")
+		}
+	}
+
+	// Delete via Raft.
+	_, index, err := v.srv.raftApply(structs.VariableGrantDeleteRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
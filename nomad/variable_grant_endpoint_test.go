@@ -0,0 +1,190 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package nomad
+
+import (
+	"testing"
+	"time"
+
+	msgpackrpc "github.com/hashicorp/net-rpc-msgpackrpc"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/testutil"
+	"github.com/shoenig/test/must"
+)
+
+func TestVariableGrantEndpoint_CRUD(t *testing.T) {
+	ci.Parallel(t)
+
+	s, rootToken, cleanupS := TestACLServer(t, nil)
+	defer cleanupS()
+
+	codec := rpcClient(t, s)
+	testutil.WaitForLeader(t, s.RPC)
+
+	grant := mock.VariableGrant()
+
+	// Only management tokens can register grants.
+	token := mock.CreatePolicyAndToken(t, s.fsm.State(), 1001, "var-write",
+		mock.NamespacePolicyWithVariables(structs.DefaultNamespace, "", nil,
+			map[string][]string{"*": {"write", "read", "list"}}))
+
+	upsertReq := &structs.VariableGrantUpsertRequest{
+		Grants: []*structs.VariableGrant{grant},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: token.SecretID,
+		},
+	}
+	var upsertResp structs.GenericResponse
+	err := msgpackrpc.CallWithCodec(codec, "VariableGrant.UpsertVariableGrants", upsertReq, &upsertResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Grants of unknown namespaces are rejected.
+	upsertReq.AuthToken = rootToken.SecretID
+	grant.Namespace = "unknown"
+	err = msgpackrpc.CallWithCodec(codec, "VariableGrant.UpsertVariableGrants", upsertReq, &upsertResp)
+	must.ErrorContains(t, err, `namespace "unknown" not found`)
+
+	grant.Namespace = structs.DefaultNamespace
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "VariableGrant.UpsertVariableGrants", upsertReq, &upsertResp))
+	must.NonZero(t, upsertResp.Index)
+
+	// Tokens with access to the variables of the namespace can list and read
+	// its grants.
+	listReq := &structs.VariableGrantListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var listResp structs.VariableGrantListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "VariableGrant.List", listReq, &listResp))
+	must.Eq(t, upsertResp.Index, listResp.Index)
+	must.Len(t, 1, listResp.Grants)
+	must.Eq(t, grant.Name, listResp.Grants[0].Name)
+
+	getReq := &structs.VariableGrantSpecificRequest{
+		Name: grant.Name,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: token.SecretID,
+		},
+	}
+	var getResp structs.SingleVariableGrantResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "VariableGrant.GetVariableGrant", getReq, &getResp))
+	must.NotNil(t, getResp.Grant)
+	must.Eq(t, grant.Path, getResp.Grant.Path)
+
+	// Delete the grant.
+	deleteReq := &structs.VariableGrantDeleteRequest{
+		Names: []string{grant.Name},
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: structs.DefaultNamespace,
+			AuthToken: rootToken.SecretID,
+		},
+	}
+	var deleteResp structs.GenericResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "VariableGrant.DeleteVariableGrants", deleteReq, &deleteResp))
+
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, "VariableGrant.GetVariableGrant", getReq, &getResp))
+	must.Nil(t, getResp.Grant)
+}
+
+func TestVariablesEndpoint_Read_Grant(t *testing.T) {
+	ci.Parallel(t)
+
+	srv, rootToken, shutdown := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+	codec := rpcClient(t, srv)
+	store := srv.fsm.State()
+
+	must.NoError(t, store.UpsertNamespaces(1000, []*structs.Namespace{
+		{Name: "platform"}, {Name: "team-a"},
+	}))
+
+	// Write the shared variable with a management token.
+	sv := mock.Variable()
+	sv.Namespace = "platform"
+	sv.Path = "shared/tls"
+	sv.ModifyIndex = 0
+	applyReq := structs.VariablesApplyRequest{
+		Op:  structs.VarOpSet,
+		Var: sv,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: "platform",
+			AuthToken: rootToken.SecretID,
+		},
+	}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &applyReq, &structs.VariablesApplyResponse{}))
+
+	// Create a workload identity for a job of the team-a namespace.
+	alloc := mock.Alloc()
+	alloc.Namespace = "team-a"
+	alloc.Job.Namespace = "team-a"
+	must.NoError(t, store.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+
+	wiHandle := &structs.WIHandle{
+		WorkloadIdentifier: "web",
+		WorkloadType:       structs.WorkloadTypeTask,
+	}
+	claims := structs.NewIdentityClaims(alloc.Job, alloc, wiHandle, alloc.LookupTask("web").Identity, time.Now())
+	idToken, _, err := srv.encrypter.SignClaims(claims)
+	must.NoError(t, err)
+
+	readReq := structs.VariablesReadRequest{
+		Path: sv.Path,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: "platform",
+			AuthToken: idToken,
+		},
+	}
+	listReq := structs.VariablesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: "platform",
+			Prefix:    "shared",
+			AuthToken: idToken,
+		},
+	}
+
+	// The workload can't read the variable without a grant.
+	var readResp structs.VariablesReadResponse
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, &readReq, &readResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Grant the variable to the namespace of the workload.
+	grant := mock.VariableGrant()
+	grant.Namespace = "platform"
+	must.NoError(t, store.UpsertVariableGrants(structs.MsgTypeTestSetup, 1002, []*structs.VariableGrant{grant}))
+
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, &readReq, &readResp))
+	must.NotNil(t, readResp.Data)
+	must.Eq(t, sv.Items, readResp.Data.Items)
+
+	var listResp structs.VariablesListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesListRPCMethod, &listReq, &listResp))
+	must.Len(t, 1, listResp.Data)
+
+	// Grants restricted to other jobs don't apply.
+	grant = grant.Copy()
+	grant.Jobs = []string{"other"}
+	must.NoError(t, store.UpsertVariableGrants(structs.MsgTypeTestSetup, 1003, []*structs.VariableGrant{grant}))
+
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, &readReq, &readResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	listResp = structs.VariablesListResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesListRPCMethod, &listReq, &listResp))
+	must.SliceEmpty(t, listResp.Data)
+}
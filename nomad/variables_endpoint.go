@@ -106,6 +106,16 @@ func (sv *Variables) Apply(args *structs.VariablesApplyRequest, reply *structs.V
 		return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
 	}
 
+	if args.Var.TTL > 0 && !ServersMeetMinimumVersion(
+		sv.srv.serf.Members(), sv.srv.Region(), minVariableTTLVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to apply variables with a TTL", minVariableTTLVersion)
+	}
+
+	if args.Op == structs.VarOpRekey && !ServersMeetMinimumVersion(
+		sv.srv.serf.Members(), sv.srv.Region(), minVariableHistoryVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to rekey variables in place", minVariableHistoryVersion)
+	}
+
 	var ev *structs.VariableEncrypted
 
 	switch args.Op {
@@ -119,14 +129,30 @@ func (sv *Variables) Apply(args *structs.VariablesApplyRequest, reply *structs.V
 		ev.CreateTime = now // existing will override if it exists
 		ev.ModifyTime = now
 
+		// The TTL is restarted each time the variable is written
+		ev.ExpireTime = 0
+		if ev.TTL > 0 {
+			ev.ExpireTime = now + int64(ev.TTL)
+		}
+
 	case structs.VarOpDelete, structs.VarOpDeleteCAS:
 		ev = &structs.VariableEncrypted{
 			VariableMetadata: structs.VariableMetadata{
 				Namespace:   args.Var.Namespace,
 				Path:        args.Var.Path,
 				ModifyIndex: args.Var.ModifyIndex,
+
+				// Deleted variables are kept until the retention period
+				// has passed since this time
+				DeleteTime: time.Now().UnixNano(),
 			},
 		}
+
+	case structs.VarOpRekey:
+		ev, err = sv.encrypt(args.Var)
+		if err != nil {
+			return fmt.Errorf("variable error: encrypt: %w", err)
+		}
 	}
 
 	// Make a SVEArgs
@@ -188,6 +214,12 @@ func hasOperationPermissions(aclObj *acl.ACL, namespace, path string, op structs
 		if !hasPerm(acl.VariablesCapabilityDestroy) {
 			return structs.ErrPermissionDenied
 		}
+
+	case structs.VarOpRekey:
+		// Re-keying is only performed by the leader's core scheduler
+		if !aclObj.IsManagement() {
+			return structs.ErrPermissionDenied
+		}
 	default:
 		return fmt.Errorf("svPreApply: unexpected VarOp received: %q", op)
 	}
@@ -343,9 +375,15 @@ func (sv *Variables) Read(args *structs.VariablesReadRequest, reply *structs.Var
 	if err != nil {
 		return err
 	}
-	if !aclObj.AllowVariableOperation(args.RequestNamespace(), args.Path, acl.PolicyRead,
-		auth.IdentityToACLClaim(args.GetIdentity(), sv.srv.State())) {
-		return structs.ErrPermissionDenied
+	claim := auth.IdentityToACLClaim(args.GetIdentity(), sv.srv.State())
+	if !aclObj.AllowVariableOperation(args.RequestNamespace(), args.Path, acl.PolicyRead, claim) {
+		granted, err := sv.allowedByGrant(sv.srv.State(), args.RequestNamespace(), args.Path, claim)
+		if err != nil {
+			return err
+		}
+		if !granted {
+			return structs.ErrPermissionDenied
+		}
 	}
 
 	// Setup the blocking query
@@ -358,6 +396,21 @@ func (sv *Variables) Read(args *structs.VariablesReadRequest, reply *structs.Var
 				return err
 			}
 
+			// Variables that have expired but have not yet been deleted by
+			// the garbage collector are treated as though they don't exist
+			if out != nil && out.IsExpired(time.Now()) {
+				out = nil
+			}
+
+			// Prior versions, including the versions of deleted variables,
+			// are read from the variable history
+			if args.Version != 0 && (out == nil || out.Version != args.Version) {
+				out, err = s.GetVariableVersion(ws, args.RequestNamespace(), args.Path, args.Version)
+				if err != nil {
+					return err
+				}
+			}
+
 			// Setup the output
 			reply.Data = nil
 			if out != nil {
@@ -409,6 +462,7 @@ func (sv *Variables) List(
 	if err != nil {
 		return err
 	}
+	claim := auth.IdentityToACLClaim(args.GetIdentity(), sv.srv.State())
 
 	// Set up and return the blocking query.
 	return sv.srv.blockingRPC(&blockingOptions{
@@ -431,6 +485,7 @@ func (sv *Variables) List(
 				},
 			)
 
+			now := time.Now()
 			filters := []paginator.Filter{
 				paginator.GenericFilter{
 					Allow: func(raw interface{}) (bool, error) {
@@ -438,10 +493,14 @@ func (sv *Variables) List(
 						if !strings.HasPrefix(v.Path, args.Prefix) {
 							return false, nil
 						}
+						if v.IsExpired(now) {
+							return false, nil
+						}
 
-						return aclObj.AllowVariableOperation(args.Namespace, v.Path,
-							acl.PolicyList,
-							auth.IdentityToACLClaim(args.GetIdentity(), sv.srv.State())), nil
+						if aclObj.AllowVariableOperation(args.Namespace, v.Path, acl.PolicyList, claim) {
+							return true, nil
+						}
+						return sv.allowedByGrant(stateStore, args.Namespace, v.Path, claim)
 					},
 				},
 			}
@@ -521,6 +580,7 @@ func (sv *Variables) listAllVariables(
 					WithID:        true,
 				})
 
+			now := time.Now()
 			filters := []paginator.Filter{
 				paginator.GenericFilter{
 					Allow: func(raw interface{}) (bool, error) {
@@ -528,6 +588,9 @@ func (sv *Variables) listAllVariables(
 						if !strings.HasPrefix(v.Path, args.Prefix) {
 							return false, nil
 						}
+						if v.IsExpired(now) {
+							return false, nil
+						}
 
 						return aclObj.AllowVariableOperation(v.Namespace, v.Path,
 							acl.PolicyList,
@@ -673,6 +736,260 @@ func (sv *Variables) RenewLock(args *structs.VariablesRenewLockRequest, reply *s
 	return nil
 }
 
+// Expire is used to delete the variables whose TTL has expired. It is called
+// by the leader's core scheduler.
+func (sv *Variables) Expire(args *structs.VariablesExpireRequest, reply *structs.GenericResponse) error {
+
+	authErr := sv.srv.Authenticate(sv.ctx, args)
+	if done, err := sv.srv.forward(structs.VariablesExpireRPCMethod, args, args, reply); done {
+		return err
+	}
+	sv.srv.MeasureRPCRate("variables", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "expire"}, time.Now())
+
+	if !ServersMeetMinimumVersion(sv.srv.Members(), sv.srv.Region(), minVariableTTLVersion, false) {
+		return fmt.Errorf("all servers must be running version %v or later to expire variables", minVariableTTLVersion)
+	}
+
+	// Check management level permissions
+	aclObj, err := sv.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	} else if !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	args.Timestamp = time.Now() // use the leader's timestamp
+
+	// Expire variables via raft; because this is the only write in the RPC
+	// the caller can safely retry if the raft write fails
+	_, index, err := sv.srv.raftApply(structs.VariablesExpireRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
+// Versions is used to list the current and prior versions of a variable. The
+// versions of deleted variables are listed until they are purged.
+func (sv *Variables) Versions(args *structs.VariablesVersionsRequest, reply *structs.VariablesVersionsResponse) error {
+
+	authErr := sv.srv.Authenticate(sv.ctx, args)
+	if done, err := sv.srv.forward(structs.VariablesVersionsRPCMethod, args, args, reply); done {
+		return err
+	}
+	sv.srv.MeasureRPCRate("variables", structs.RateMetricList, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	defer metrics.MeasureSince([]string{"nomad", "variables", "versions"}, time.Now())
+
+	aclObj, err := sv.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !aclObj.AllowVariableOperation(args.RequestNamespace(), args.Path, acl.PolicyList,
+		auth.IdentityToACLClaim(args.GetIdentity(), sv.srv.State())) {
+		return structs.ErrPermissionDenied
+	}
+
+	opts := blockingOptions{
+		queryOpts: &args.QueryOptions,
+		queryMeta: &reply.QueryMeta,
+		run: func(ws memdb.WatchSet, s *state.StateStore) error {
+			current, err := s.GetVariable(ws, args.RequestNamespace(), args.Path)
+			if err != nil {
+				return err
+			}
+			prior, err := s.GetVariableVersions(ws, args.RequestNamespace(), args.Path)
+			if err != nil {
+				return err
+			}
+
+			reply.Versions = make([]*structs.VariableMetadata, 0, len(prior)+1)
+			if current != nil && !current.IsExpired(time.Now()) {
+				reply.Versions = append(reply.Versions, current.VariableMetadata.Copy())
+			}
+			for _, version := range prior {
+				reply.Versions = append(reply.Versions, version.VariableMetadata.Copy())
+			}
+			if !aclObj.IsManagement() {
+				for _, version := range reply.Versions {
+					version.Lock = nil
+				}
+			}
+
+			if err := sv.srv.setReplyQueryMeta(s, state.TableVariables, &reply.QueryMeta); err != nil {
+				return err
+			}
+			versionsIndex, err := s.Index(state.TableVariableVersions)
+			if err != nil {
+				return err
+			}
+			reply.Index = max(reply.Index, versionsIndex)
+			return nil
+		}}
+	return sv.srv.blockingRPC(&opts)
+}
+
+// Restore is used to restore a prior version of a variable, including a
+// deleted variable. The items of the prior version are written as a new
+// version of the variable.
+func (sv *Variables) Restore(args *structs.VariablesRestoreRequest, reply *structs.VariablesRestoreResponse) error {
+
+	authErr := sv.srv.Authenticate(sv.ctx, args)
+	if done, err := sv.srv.forward(structs.VariablesRestoreRPCMethod, args, args, reply); done {
+		return err
+	}
+	sv.srv.MeasureRPCRate("variables", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+
+	defer metrics.MeasureSince([]string{"nomad", "variables", "restore"}, time.Now())
+
+	if err := args.Validate(); err != nil {
+		return structs.NewErrRPCCoded(http.StatusBadRequest, err.Error())
+	}
+
+	if !ServersMeetMinimumVersion(
+		sv.srv.serf.Members(), sv.srv.Region(), minVariableHistoryVersion, true) {
+		return fmt.Errorf("all servers must be running version %v or later to restore variables", minVariableHistoryVersion)
+	}
+
+	aclObj, err := sv.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	}
+	if !aclObj.AllowVariableOperation(args.RequestNamespace(), args.Path,
+		acl.VariablesCapabilityWrite, nil) {
+		return structs.ErrPermissionDenied
+	}
+
+	snap, err := sv.srv.State().Snapshot()
+	if err != nil {
+		return err
+	}
+	current, err := snap.GetVariable(nil, args.RequestNamespace(), args.Path)
+	if err != nil {
+		return err
+	}
+	if current != nil && current.Version == args.Version {
+		return structs.NewErrRPCCodedf(http.StatusBadRequest,
+			"version %d is the current version of the variable", args.Version)
+	}
+	prior, err := snap.GetVariableVersion(nil, args.RequestNamespace(), args.Path, args.Version)
+	if err != nil {
+		return err
+	}
+	if prior == nil {
+		return structs.NewErrRPCCodedf(http.StatusNotFound,
+			"variable version %d not found", args.Version)
+	}
+
+	// The restored value is written with a check-and-set against the current
+	// value, so that a concurrent write isn't silently overwritten
+	now := time.Now().UnixNano()
+	ev := prior.Copy()
+	ev.Lock = nil
+	ev.DeleteTime = 0
+	ev.CreateTime = now
+	ev.ModifyTime = now
+	ev.ModifyIndex = 0
+	if current != nil {
+		ev.ModifyIndex = current.ModifyIndex
+	}
+	ev.ExpireTime = 0
+	if ev.TTL > 0 {
+		ev.ExpireTime = now + int64(ev.TTL)
+	}
+
+	sveArgs := structs.VarApplyStateRequest{
+		Op:           structs.VarOpCAS,
+		Var:          &ev,
+		WriteRequest: args.WriteRequest,
+	}
+	o, index, err := sv.srv.raftApply(structs.VarApplyStateRequestType, sveArgs)
+	if err != nil {
+		return fmt.Errorf("raft apply failed: %w", err)
+	}
+
+	out, _ := o.(*structs.VarApplyStateResponse)
+	switch {
+	case out == nil:
+		return fmt.Errorf("unexpected raft apply response %T", o)
+	case out.IsError():
+		return out.Error
+	case out.IsConflict():
+		if current != nil && current.IsLock() {
+			return errVarIsLocked
+		}
+		return structs.NewErrRPCCoded(http.StatusConflict,
+			"variable was modified while it was being restored")
+	}
+
+	reply.VarMeta = out.WrittenSVMeta
+	if reply.VarMeta != nil && !aclObj.IsManagement() {
+		reply.VarMeta.Lock = nil
+	}
+	reply.Index = index
+	return nil
+}
+
+// PurgeDeleted is used to permanently remove the deleted variables whose
+// retention period has passed. It is called by the leader's core scheduler.
+func (sv *Variables) PurgeDeleted(args *structs.VariablesPurgeDeletedRequest, reply *structs.GenericResponse) error {
+
+	authErr := sv.srv.Authenticate(sv.ctx, args)
+	if done, err := sv.srv.forward(structs.VariablesPurgeDeletedRPCMethod, args, args, reply); done {
+		return err
+	}
+	sv.srv.MeasureRPCRate("variables", structs.RateMetricWrite, args)
+	if authErr != nil {
+		return structs.ErrPermissionDenied
+	}
+	defer metrics.MeasureSince([]string{"nomad", "variables", "purge_deleted"}, time.Now())
+
+	if !ServersMeetMinimumVersion(sv.srv.Members(), sv.srv.Region(), minVariableHistoryVersion, false) {
+		return fmt.Errorf("all servers must be running version %v or later to purge deleted variables", minVariableHistoryVersion)
+	}
+
+	// Check management level permissions
+	aclObj, err := sv.srv.ResolveACL(args)
+	if err != nil {
+		return err
+	} else if !aclObj.IsManagement() {
+		return structs.ErrPermissionDenied
+	}
+
+	// use the leader's clock and retention
+	args.Threshold = time.Now().Add(-sv.srv.config.VariablesDeleteRetention)
+
+	// Purge variables via raft; because this is the only write in the RPC
+	// the caller can safely retry if the raft write fails
+	_, index, err := sv.srv.raftApply(structs.VariablesPurgeDeletedRequestType, args)
+	if err != nil {
+		return err
+	}
+	reply.Index = index
+	return nil
+}
+
+// allowedByGrant returns true if a variable grant of the namespace shares the
+// variable at the path with the workload of the claim. Grants only apply to
+// the workloads of other namespaces, and never to ACL tokens.
+func (sv *Variables) allowedByGrant(store *state.StateStore, ns, path string, claim *acl.ACLClaim) (bool, error) {
+	if claim == nil || claim.Namespace == ns {
+		return false, nil
+	}
+	return store.VariableGranted(nil, ns, path, claim.Namespace, claim.Job)
+}
+
 func isCallerOwner(req *structs.VariablesApplyRequest, respVarMeta *structs.VariableMetadata) bool {
 	reqLock := req.Var.VariableMetadata.Lock
 	savedLock := respVarMeta.Lock
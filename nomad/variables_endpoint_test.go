@@ -1658,3 +1658,233 @@ func TestVariablesEndpoint_RenewLock(t *testing.T) {
 		must.NoError(t, err)
 	})
 }
+
+func TestVariablesEndpoint_TTL(t *testing.T) {
+	ci.Parallel(t)
+	srv, rootToken, shutdown := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+	codec := rpcClient(t, srv)
+	state := srv.fsm.State()
+
+	writePol := mock.NamespacePolicyWithVariables(
+		structs.DefaultNamespace, "", []string{"list-jobs"},
+		map[string][]string{
+			"*": {"write", "read", "list"},
+		})
+	writeToken := mock.CreatePolicyAndToken(t, state, 1003, "test-write", writePol)
+
+	// Write a variable with a TTL and check the expire time is set from it
+	sv := mock.Variable()
+	sv.ModifyIndex = 0
+	sv.TTL = time.Hour
+	applyReq := structs.VariablesApplyRequest{
+		Op:  structs.VarOpSet,
+		Var: sv,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: writeToken.SecretID,
+		},
+	}
+	var applyResp structs.VariablesApplyResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &applyReq, &applyResp))
+	must.Eq(t, structs.VarOpResultOk, applyResp.Result)
+	must.Eq(t, time.Hour, applyResp.Output.TTL)
+	must.Eq(t, applyResp.Output.ModifyTime+int64(time.Hour), applyResp.Output.ExpireTime)
+
+	// A negative TTL is rejected
+	badReq := applyReq
+	badVar := sv.Copy()
+	badVar.TTL = -time.Hour
+	badReq.Var = &badVar
+	must.ErrorContains(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &badReq, &structs.VariablesApplyResponse{}),
+		"variable TTL must not be negative")
+
+	// Expire the variable in state without deleting it
+	ev, err := state.GetVariable(nil, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	expired := ev.Copy()
+	expired.ExpireTime = time.Now().Add(-time.Minute).UnixNano()
+	resp := state.VarSet(2000, &structs.VarApplyStateRequest{Op: structs.VarOpSet, Var: &expired})
+	must.NoError(t, resp.Error)
+
+	// The expired variable can't be read or listed
+	readReq := structs.VariablesReadRequest{
+		Path: sv.Path,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: sv.Namespace,
+			AuthToken: writeToken.SecretID,
+		},
+	}
+	var readResp structs.VariablesReadResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, &readReq, &readResp))
+	must.Nil(t, readResp.Data)
+
+	listReq := structs.VariablesListRequest{
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: sv.Namespace,
+			AuthToken: writeToken.SecretID,
+		},
+	}
+	var listResp structs.VariablesListResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesListRPCMethod, &listReq, &listResp))
+	must.SliceEmpty(t, listResp.Data)
+
+	// Only management tokens can expire variables
+	expireReq := structs.VariablesExpireRequest{
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: writeToken.SecretID,
+		},
+	}
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesExpireRPCMethod, &expireReq, &structs.GenericResponse{})
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	expireReq.AuthToken = rootToken.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesExpireRPCMethod, &expireReq, &structs.GenericResponse{}))
+
+	ev, err = state.GetVariable(nil, sv.Namespace, sv.Path)
+	must.NoError(t, err)
+	must.Nil(t, ev)
+}
+
+func TestVariablesEndpoint_VersionsAndRestore(t *testing.T) {
+	ci.Parallel(t)
+	srv, rootToken, shutdown := TestACLServer(t, func(c *Config) {
+		c.NumSchedulers = 0 // Prevent automatic dequeue
+	})
+	defer shutdown()
+	testutil.WaitForKeyring(t, srv.RPC, "global")
+	codec := rpcClient(t, srv)
+	state := srv.fsm.State()
+
+	writePol := mock.NamespacePolicyWithVariables(
+		structs.DefaultNamespace, "", []string{"list-jobs"},
+		map[string][]string{
+			"*": {"write", "read", "list", "destroy"},
+		})
+	writeToken := mock.CreatePolicyAndToken(t, state, 1003, "test-write", writePol)
+
+	readPol := mock.NamespacePolicyWithVariables(
+		structs.DefaultNamespace, "", []string{"list-jobs"},
+		map[string][]string{
+			"*": {"read", "list"},
+		})
+	readToken := mock.CreatePolicyAndToken(t, state, 1005, "test-read", readPol)
+
+	// Write the variable twice and then delete it
+	sv := mock.Variable()
+	sv.ModifyIndex = 0
+	for _, item := range []string{"one", "two"} {
+		sv.Items = structs.VariableItems{"item": item}
+		applyReq := structs.VariablesApplyRequest{
+			Op:  structs.VarOpSet,
+			Var: sv,
+			WriteRequest: structs.WriteRequest{
+				Region:    "global",
+				AuthToken: writeToken.SecretID,
+			},
+		}
+		var applyResp structs.VariablesApplyResponse
+		must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &applyReq, &applyResp))
+		must.Eq(t, structs.VarOpResultOk, applyResp.Result)
+	}
+
+	versionsReq := structs.VariablesVersionsRequest{
+		Path: sv.Path,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: sv.Namespace,
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var versionsResp structs.VariablesVersionsResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesVersionsRPCMethod, &versionsReq, &versionsResp))
+	must.Len(t, 2, versionsResp.Versions)
+	must.Eq(t, 2, versionsResp.Versions[0].Version)
+	must.Eq(t, 1, versionsResp.Versions[1].Version)
+
+	// A prior version can be read by its version number
+	readReq := structs.VariablesReadRequest{
+		Path:    sv.Path,
+		Version: 1,
+		QueryOptions: structs.QueryOptions{
+			Region:    "global",
+			Namespace: sv.Namespace,
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var readResp structs.VariablesReadResponse
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, &readReq, &readResp))
+	must.NotNil(t, readResp.Data)
+	must.Eq(t, "one", readResp.Data.Items["item"])
+
+	deleteReq := structs.VariablesApplyRequest{
+		Op:  structs.VarOpDelete,
+		Var: sv,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: writeToken.SecretID,
+		},
+	}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesApplyRPCMethod, &deleteReq, &structs.VariablesApplyResponse{}))
+
+	versionsResp = structs.VariablesVersionsResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesVersionsRPCMethod, &versionsReq, &versionsResp))
+	must.Len(t, 2, versionsResp.Versions)
+	must.True(t, versionsResp.Versions[0].IsDeleted())
+
+	// Restoring requires write capability
+	restoreReq := structs.VariablesRestoreRequest{
+		Path:    sv.Path,
+		Version: 1,
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			Namespace: sv.Namespace,
+			AuthToken: readToken.SecretID,
+		},
+	}
+	var restoreResp structs.VariablesRestoreResponse
+	err := msgpackrpc.CallWithCodec(codec, structs.VariablesRestoreRPCMethod, &restoreReq, &restoreResp)
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	// Unknown versions can't be restored
+	restoreReq.AuthToken = writeToken.SecretID
+	restoreReq.Version = 10
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesRestoreRPCMethod, &restoreReq, &restoreResp)
+	must.ErrorContains(t, err, "variable version 10 not found")
+
+	restoreReq.Version = 1
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesRestoreRPCMethod, &restoreReq, &restoreResp))
+	must.NotNil(t, restoreResp.VarMeta)
+	must.Eq(t, 3, restoreResp.VarMeta.Version)
+
+	readReq.Version = 0
+	readResp = structs.VariablesReadResponse{}
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesReadRPCMethod, &readReq, &readResp))
+	must.NotNil(t, readResp.Data)
+	must.Eq(t, "one", readResp.Data.Items["item"])
+	must.Eq(t, 3, readResp.Data.Version)
+
+	// The current version can't be restored
+	restoreReq.Version = 3
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesRestoreRPCMethod, &restoreReq, &restoreResp)
+	must.ErrorContains(t, err, "version 3 is the current version of the variable")
+
+	// Only management tokens can purge deleted variables
+	purgeReq := structs.VariablesPurgeDeletedRequest{
+		WriteRequest: structs.WriteRequest{
+			Region:    "global",
+			AuthToken: writeToken.SecretID,
+		},
+	}
+	err = msgpackrpc.CallWithCodec(codec, structs.VariablesPurgeDeletedRPCMethod, &purgeReq, &structs.GenericResponse{})
+	must.EqError(t, err, structs.ErrPermissionDenied.Error())
+
+	purgeReq.AuthToken = rootToken.SecretID
+	must.NoError(t, msgpackrpc.CallWithCodec(codec, structs.VariablesPurgeDeletedRPCMethod, &purgeReq, &structs.GenericResponse{}))
+}
@@ -17,6 +17,7 @@ import (
 	memdb "github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/nomad/helper"
+	"github.com/hashicorp/nomad/helper/tracing"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/state"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -121,6 +122,10 @@ type Worker struct {
 	// first invoked. It is used to mark the SnapshotIndex of evaluations
 	// Created, Updated or Reblocked.
 	snapshotIndex uint64
+
+	// evalSpan is the span of the evaluation being processed, which is the
+	// parent of the spans of the plans the scheduler submits.
+	evalSpan *tracing.Span
 }
 
 // NewWorker starts a new scheduler worker associated with the given server
@@ -423,6 +428,12 @@ func (w *Worker) run(raftSyncLimit time.Duration) {
 			return
 		}
 
+		// Record the time the evaluation waited in the eval broker
+		w.srv.tracer.StartAt("eval_broker.wait", eval.TraceParent, time.Unix(0, eval.ModifyTime),
+			tracing.String("nomad.eval.id", eval.ID),
+			tracing.String("nomad.worker.id", w.id),
+		).End(nil)
+
 		// Wait for the raft log to catchup to the evaluation
 		w.setWorkloadStatus(WorkloadWaitingForRaft)
 		snap, err := w.snapshotMinIndex(waitIndex, raftSyncLimit)
@@ -607,8 +618,23 @@ func (w *Worker) snapshotMinIndex(waitIndex uint64, timeout time.Duration) (*sta
 }
 
 // invokeScheduler is used to invoke the business logic of the scheduler
-func (w *Worker) invokeScheduler(snap *state.StateSnapshot, eval *structs.Evaluation, token string) error {
+func (w *Worker) invokeScheduler(snap *state.StateSnapshot, eval *structs.Evaluation, token string) (retErr error) {
 	defer metrics.MeasureSince([]string{"nomad", "worker", "invoke_scheduler", eval.Type}, time.Now())
+
+	// Continue the trace of the evaluation
+	w.evalSpan = w.srv.tracer.Start("scheduler.process", eval.TraceParent,
+		tracing.String("nomad.namespace", eval.Namespace),
+		tracing.String("nomad.job.id", eval.JobID),
+		tracing.String("nomad.eval.id", eval.ID),
+		tracing.String("nomad.eval.type", eval.Type),
+		tracing.String("nomad.eval.triggered_by", eval.TriggeredBy),
+		tracing.String("nomad.worker.id", w.id),
+	)
+	defer func() {
+		w.evalSpan.End(retErr)
+		w.evalSpan = nil
+	}()
+
 	// Store the evaluation token
 	w.evalToken = token
 
@@ -648,6 +674,29 @@ func (w *Worker) ServersMeetMinimumVersion(minVersion *version.Version, checkFai
 // SubmitPlan is used to submit a plan for consideration. This allows
 // the worker to act as the planner for the scheduler.
 func (w *Worker) SubmitPlan(plan *structs.Plan) (*structs.PlanResult, scheduler.State, error) {
+	span := w.evalSpan.Child("worker.submit_plan",
+		tracing.String("nomad.eval.id", plan.EvalID),
+		tracing.Int("nomad.plan.node_allocations", len(plan.NodeAllocation)),
+		tracing.Int("nomad.plan.node_updates", len(plan.NodeUpdate)),
+	)
+	plan.TraceParent = span.TraceParent()
+
+	// Clients continue the trace while they start the placed allocations
+	for _, allocs := range plan.NodeAllocation {
+		for _, alloc := range allocs {
+			alloc.TraceParent = plan.TraceParent
+		}
+	}
+
+	result, state, err := w.submitPlan(plan)
+	if result != nil {
+		span.SetAttributes(tracing.Bool("nomad.plan.refresh", result.RefreshIndex != 0))
+	}
+	span.End(err)
+	return result, state, err
+}
+
+func (w *Worker) submitPlan(plan *structs.Plan) (*structs.PlanResult, scheduler.State, error) {
 	// Check for a shutdown before plan submission. Checking server state rather than
 	// worker state to allow work in flight to complete before stopping.
 	if w.srv.IsShutdown() {
@@ -33,6 +33,10 @@ func (b *BasePluginClient) PluginInfo() (*PluginInfoResponse, error) {
 		ptype = PluginTypeDriver
 	case proto.PluginType_DEVICE:
 		ptype = PluginTypeDevice
+	case proto.PluginType_FINGERPRINT:
+		ptype = PluginTypeFingerprint
+	case proto.PluginType_LOG_SINK:
+		ptype = PluginTypeLogSink
 	default:
 		return nil, fmt.Errorf("plugin is of unknown type: %q", presp.GetType().String())
 	}
@@ -23,6 +23,12 @@ const (
 
 	// PluginTypeDevice implements the device plugin interface
 	PluginTypeDevice = "device"
+
+	// PluginTypeFingerprint implements the fingerprint plugin interface
+	PluginTypeFingerprint = "fingerprint"
+
+	// PluginTypeLogSink implements the log sink plugin interface
+	PluginTypeLogSink = "log_sink"
 )
 
 var (
@@ -29,21 +29,27 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 type PluginType int32
 
 const (
-	PluginType_UNKNOWN PluginType = 0
-	PluginType_DRIVER  PluginType = 2
-	PluginType_DEVICE  PluginType = 3
+	PluginType_UNKNOWN     PluginType = 0
+	PluginType_DRIVER      PluginType = 2
+	PluginType_DEVICE      PluginType = 3
+	PluginType_FINGERPRINT PluginType = 4
+	PluginType_LOG_SINK    PluginType = 5
 )
 
 var PluginType_name = map[int32]string{
 	0: "UNKNOWN",
 	2: "DRIVER",
 	3: "DEVICE",
+	4: "FINGERPRINT",
+	5: "LOG_SINK",
 }
 
 var PluginType_value = map[string]int32{
-	"UNKNOWN": 0,
-	"DRIVER":  2,
-	"DEVICE":  3,
+	"UNKNOWN":     0,
+	"DRIVER":      2,
+	"DEVICE":      3,
+	"FINGERPRINT": 4,
+	"LOG_SINK":    5,
 }
 
 func (x PluginType) String() string {
@@ -691,61 +697,63 @@ func init() {
 }
 
 var fileDescriptor_19edef855873449e = []byte{
-	// 861 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x4d, 0x6f, 0x23, 0x45,
-	0x10, 0xcd, 0xd8, 0x8e, 0x3f, 0xca, 0xb1, 0x71, 0x2a, 0x0b, 0x0c, 0x86, 0x15, 0xd6, 0x88, 0x95,
-	0xa2, 0x55, 0x98, 0x08, 0xb3, 0x59, 0xf6, 0x08, 0xf1, 0x46, 0xc8, 0x22, 0x6b, 0xa2, 0xb6, 0xc9,
-	0x22, 0x84, 0x64, 0x75, 0x66, 0xda, 0x76, 0x6b, 0x3d, 0xd3, 0xc3, 0xf4, 0x38, 0x24, 0x48, 0x9c,
-	0x38, 0xf3, 0x3f, 0xb8, 0xf1, 0x03, 0x38, 0x70, 0xe0, 0x8f, 0xa1, 0xfe, 0x18, 0xdb, 0x49, 0x84,
-	0xb0, 0xf7, 0xe4, 0xee, 0x7a, 0xaf, 0x5e, 0x75, 0xbd, 0x1e, 0x57, 0xc3, 0xe3, 0x64, 0xbe, 0x98,
-	0xf2, 0x58, 0x1e, 0x5f, 0x51, 0xc9, 0x8e, 0x93, 0x54, 0x64, 0x42, 0x2f, 0x7d, 0xbd, 0x44, 0x6f,
-	0x46, 0xe5, 0x8c, 0x07, 0x22, 0x4d, 0xfc, 0x58, 0x44, 0x34, 0xf4, 0x2d, 0xdd, 0x5f, 0x71, 0xda,
-	0x4f, 0x72, 0x09, 0x39, 0xa3, 0x29, 0x0b, 0x8f, 0x67, 0xc1, 0x5c, 0x26, 0x2c, 0x50, 0xbf, 0x63,
-	0xb5, 0x30, 0x34, 0xef, 0x00, 0xf6, 0x2f, 0x34, 0xb1, 0x1f, 0x4f, 0x04, 0x61, 0x3f, 0x2d, 0x98,
-	0xcc, 0xbc, 0x7f, 0x1c, 0xc0, 0xf5, 0xa8, 0x4c, 0x44, 0x2c, 0x19, 0x9e, 0x42, 0x29, 0xbb, 0x4d,
-	0x98, 0xeb, 0x74, 0x9c, 0xc3, 0x66, 0xd7, 0xf7, 0xff, 0xff, 0x14, 0xbe, 0x51, 0x19, 0xdd, 0x26,
-	0x8c, 0xe8, 0x5c, 0xf4, 0xe1, 0xc0, 0xd0, 0xc6, 0x34, 0xe1, 0xe3, 0x6b, 0x96, 0x4a, 0x2e, 0x62,
-	0xe9, 0x16, 0x3a, 0xc5, 0xc3, 0x1a, 0xd9, 0x37, 0xd0, 0x57, 0x09, 0xbf, 0xb4, 0x00, 0x3e, 0x81,
-	0xa6, 0xe5, 0x5b, 0xae, 0x5b, 0xec, 0x38, 0x87, 0x35, 0xd2, 0x30, 0x51, 0xcb, 0x43, 0x84, 0x52,
-	0x4c, 0x23, 0xe6, 0x96, 0x34, 0xa8, 0xd7, 0xde, 0xbb, 0x70, 0xd0, 0x13, 0xf1, 0x84, 0x4f, 0x87,
-	0xc1, 0x8c, 0x45, 0x34, 0x6f, 0xee, 0x7b, 0x78, 0x74, 0x37, 0x6c, 0xbb, 0xfb, 0x12, 0x4a, 0xca,
-	0x17, 0xdd, 0x5d, 0xbd, 0x7b, 0xf4, 0x9f, 0xdd, 0x19, 0x3f, 0x7d, 0xeb, 0xa7, 0x3f, 0x4c, 0x58,
-	0x40, 0x74, 0xa6, 0xf7, 0x97, 0x03, 0xad, 0x21, 0xcb, 0x8c, 0xba, 0x2d, 0xa7, 0x1a, 0x88, 0xe4,
-	0x34, 0xa1, 0xc1, 0x9b, 0x71, 0xa0, 0x01, 0x5d, 0x60, 0x8f, 0x34, 0x6c, 0xd4, 0xb0, 0x91, 0xc0,
-	0x9e, 0x2e, 0x93, 0x93, 0x0a, 0xfa, 0x14, 0xc7, 0x9b, 0x78, 0x3c, 0x50, 0x80, 0x2d, 0x5a, 0x8f,
-	0x57, 0x1b, 0x3c, 0x02, 0x7c, 0xe8, 0xb5, 0xf5, 0xaf, 0x75, 0xdf, 0x6a, 0xef, 0x47, 0xa8, 0xaf,
-	0x29, 0xe1, 0x2b, 0x28, 0x87, 0x29, 0xbf, 0x66, 0xa9, 0x35, 0xe4, 0x64, 0xe3, 0xa3, 0xbc, 0xd4,
-	0x69, 0xf6, 0x40, 0x56, 0xc4, 0xfb, 0xd3, 0x81, 0xfd, 0x07, 0x28, 0x7e, 0x02, 0x8d, 0xde, 0x9c,
-	0xb3, 0x38, 0x7b, 0x45, 0x6f, 0x2e, 0x44, 0x9a, 0xe9, 0x5a, 0x0d, 0x72, 0x37, 0xb8, 0xc6, 0xe2,
-	0xb1, 0x66, 0x15, 0xee, 0xb0, 0x4c, 0x10, 0x07, 0x50, 0x1d, 0x89, 0x44, 0xcc, 0xc5, 0xf4, 0x56,
-	0xf7, 0x58, 0xef, 0x76, 0x37, 0x39, 0xb2, 0x11, 0xc9, 0x33, 0xc9, 0x52, 0xc3, 0xfb, 0xbb, 0x00,
-	0xcd, 0xbb, 0x20, 0x7e, 0x00, 0xd5, 0x58, 0x84, 0x6c, 0xcc, 0x43, 0xe9, 0x3a, 0x9d, 0xe2, 0x61,
-	0x83, 0x54, 0xd4, 0xbe, 0x1f, 0x4a, 0x1c, 0x41, 0x2d, 0xe4, 0x32, 0xa3, 0x71, 0xc0, 0xa4, 0xbd,
-	0xbc, 0xe7, 0xdb, 0x97, 0x1f, 0x9e, 0xf7, 0x47, 0x64, 0x25, 0x84, 0xe7, 0xb0, 0x1b, 0x88, 0x94,
-	0x49, 0xb7, 0xd8, 0x29, 0xbe, 0x9d, 0x62, 0x4f, 0xa4, 0x8c, 0x18, 0x11, 0x7c, 0x06, 0xef, 0x89,
-	0x6b, 0x96, 0xa6, 0x3c, 0x64, 0xe3, 0x4c, 0x64, 0x74, 0x3e, 0x0e, 0x44, 0x94, 0x2c, 0x32, 0xf3,
-	0xb7, 0x29, 0x91, 0x47, 0x39, 0x3a, 0x52, 0x60, 0xcf, 0x60, 0xf8, 0x02, 0xdc, 0x65, 0xd6, 0xcf,
-	0x3c, 0x9b, 0x89, 0x79, 0xb8, 0xcc, 0xdb, 0xd5, 0x79, 0x4b, 0xd5, 0xd7, 0x06, 0xb6, 0x99, 0xde,
-	0x00, 0xf0, 0x61, 0x7b, 0xf8, 0x91, 0x72, 0x2a, 0x62, 0xb1, 0xfe, 0x18, 0xcd, 0x7d, 0xaf, 0x02,
-	0xd8, 0x86, 0xf2, 0x35, 0x9d, 0x2f, 0x98, 0x19, 0x09, 0x8d, 0xd3, 0x42, 0xcb, 0x21, 0x36, 0xe2,
-	0xfd, 0x51, 0xb8, 0x2f, 0xa8, 0xba, 0xc3, 0x0f, 0xa1, 0x26, 0x45, 0xf0, 0x86, 0x65, 0x63, 0x1e,
-	0x5a, 0xc1, 0xaa, 0x09, 0xf4, 0x43, 0x7c, 0x1f, 0x2a, 0xf6, 0xca, 0xec, 0x57, 0x53, 0x36, 0x37,
-	0xa6, 0x00, 0xe5, 0x8a, 0x02, 0x8a, 0x06, 0x50, 0xdb, 0x7e, 0x88, 0xe7, 0x00, 0x1a, 0x98, 0xa6,
-	0x34, 0x34, 0xce, 0x34, 0xbb, 0x9f, 0x6e, 0x64, 0xbc, 0x48, 0xd9, 0xd7, 0x2a, 0x89, 0xd4, 0x82,
-	0x7c, 0x89, 0x2e, 0x54, 0x42, 0x2e, 0xe9, 0xd5, 0xdc, 0x98, 0x55, 0x25, 0xf9, 0x16, 0x1f, 0x03,
-	0xa8, 0x64, 0x35, 0x8c, 0x59, 0xe8, 0x96, 0xb5, 0x93, 0x35, 0x15, 0x19, 0xaa, 0x80, 0xea, 0x2a,
-	0xa2, 0x37, 0x16, 0xad, 0x68, 0xb4, 0x1a, 0xd1, 0x1b, 0x03, 0x7e, 0x0c, 0xf5, 0xe9, 0x82, 0x49,
-	0x69, 0xe1, 0xaa, 0x86, 0x41, 0x87, 0x34, 0x41, 0x8d, 0xf5, 0xb5, 0x49, 0x64, 0x26, 0xdc, 0xd3,
-	0xcf, 0x00, 0x56, 0xf3, 0x18, 0xeb, 0x50, 0xf9, 0x6e, 0xf0, 0xcd, 0xe0, 0xdb, 0xd7, 0x83, 0xd6,
-	0x0e, 0x02, 0x94, 0x5f, 0x92, 0xfe, 0xe5, 0x19, 0x69, 0x15, 0xf4, 0xfa, 0xec, 0xb2, 0xdf, 0x3b,
-	0x6b, 0x15, 0x9f, 0x1e, 0x41, 0x6d, 0xd9, 0x16, 0xbe, 0x03, 0xf5, 0x0b, 0x96, 0x4e, 0x44, 0x1a,
-	0xa9, 0xaf, 0xb3, 0xb5, 0x83, 0x4d, 0x80, 0xb3, 0xc9, 0x84, 0x07, 0x9c, 0xc5, 0xc1, 0x6d, 0xcb,
-	0xe9, 0xfe, 0x5e, 0x04, 0x38, 0xa5, 0x92, 0x99, 0x2a, 0xf8, 0x6b, 0x5e, 0x4f, 0xbd, 0x22, 0x78,
-	0xb2, 0xf9, 0x7b, 0xb1, 0xf6, 0x16, 0xb5, 0x9f, 0x6f, 0x9b, 0x66, 0x9a, 0xf5, 0x76, 0xf0, 0x37,
-	0x07, 0xf6, 0xd6, 0x27, 0x3d, 0x7e, 0xb1, 0xd9, 0x2d, 0x3e, 0x78, 0x32, 0xda, 0x2f, 0xb6, 0x4f,
-	0x5c, 0x9e, 0xe2, 0x17, 0xa8, 0x2d, 0x6f, 0x02, 0x9f, 0x6d, 0x22, 0x74, 0xff, 0x09, 0x69, 0x9f,
-	0x6c, 0x99, 0x95, 0xd7, 0x3e, 0xad, 0xfc, 0xb0, 0xab, 0xc1, 0xab, 0xb2, 0xfe, 0xf9, 0xfc, 0xdf,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0xe1, 0xe9, 0x67, 0x7b, 0x58, 0x08, 0x00, 0x00,
+	// 882 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x5d, 0x6f, 0xe3, 0x44,
+	0x14, 0x5d, 0x27, 0x69, 0x3e, 0x6e, 0x9a, 0xe0, 0xde, 0x2e, 0x60, 0x02, 0x2b, 0x22, 0x8b, 0x95,
+	0xaa, 0x55, 0x71, 0xa5, 0xb0, 0x5d, 0xf6, 0x11, 0x9a, 0x0d, 0x95, 0xb5, 0x5d, 0x6f, 0x34, 0x09,
+	0x5d, 0x84, 0x90, 0x2c, 0xd7, 0x9e, 0x24, 0xd6, 0xc6, 0x1e, 0xe3, 0x71, 0x4a, 0x8b, 0xc4, 0x13,
+	0xcf, 0xfc, 0x0f, 0xde, 0xf8, 0x01, 0x3c, 0xf0, 0xc0, 0x1f, 0x43, 0xf3, 0x91, 0x8f, 0x6e, 0x84,
+	0x48, 0x79, 0xca, 0xcc, 0x3d, 0xe7, 0x9e, 0x7b, 0xef, 0x19, 0x67, 0x06, 0x1e, 0x65, 0xf3, 0xc5,
+	0x34, 0x4e, 0xf9, 0xc9, 0x55, 0xc0, 0xe9, 0x49, 0x96, 0xb3, 0x82, 0xc9, 0xa5, 0x23, 0x97, 0x68,
+	0xcf, 0x02, 0x3e, 0x8b, 0x43, 0x96, 0x67, 0x4e, 0xca, 0x92, 0x20, 0x72, 0x34, 0xdd, 0x59, 0x73,
+	0x3a, 0x8f, 0x97, 0x12, 0x7c, 0x16, 0xe4, 0x34, 0x3a, 0x99, 0x85, 0x73, 0x9e, 0xd1, 0x50, 0xfc,
+	0xfa, 0x62, 0xa1, 0x68, 0xf6, 0x21, 0x1c, 0x0c, 0x25, 0xd1, 0x4d, 0x27, 0x8c, 0xd0, 0x1f, 0x17,
+	0x94, 0x17, 0xf6, 0xdf, 0x06, 0xe0, 0x66, 0x94, 0x67, 0x2c, 0xe5, 0x14, 0xcf, 0xa0, 0x52, 0xdc,
+	0x66, 0xd4, 0x32, 0xba, 0xc6, 0x51, 0xbb, 0xe7, 0x38, 0xff, 0xdd, 0x85, 0xa3, 0x54, 0xc6, 0xb7,
+	0x19, 0x25, 0x32, 0x17, 0x1d, 0x38, 0x54, 0x34, 0x3f, 0xc8, 0x62, 0xff, 0x9a, 0xe6, 0x3c, 0x66,
+	0x29, 0xb7, 0x4a, 0xdd, 0xf2, 0x51, 0x83, 0x1c, 0x28, 0xe8, 0xeb, 0x2c, 0xbe, 0xd4, 0x00, 0x3e,
+	0x86, 0xb6, 0xe6, 0x6b, 0xae, 0x55, 0xee, 0x1a, 0x47, 0x0d, 0xd2, 0x52, 0x51, 0xcd, 0x43, 0x84,
+	0x4a, 0x1a, 0x24, 0xd4, 0xaa, 0x48, 0x50, 0xae, 0xed, 0xf7, 0xe1, 0xb0, 0xcf, 0xd2, 0x49, 0x3c,
+	0x1d, 0x85, 0x33, 0x9a, 0x04, 0xcb, 0xe1, 0xbe, 0x83, 0x87, 0x77, 0xc3, 0x7a, 0xba, 0xaf, 0xa0,
+	0x22, 0x7c, 0x91, 0xd3, 0x35, 0x7b, 0xc7, 0xff, 0x3a, 0x9d, 0xf2, 0xd3, 0xd1, 0x7e, 0x3a, 0xa3,
+	0x8c, 0x86, 0x44, 0x66, 0xda, 0x7f, 0x1a, 0x60, 0x8e, 0x68, 0xa1, 0xd4, 0x75, 0x39, 0x31, 0x40,
+	0xc2, 0xa7, 0x59, 0x10, 0xbe, 0xf5, 0x43, 0x09, 0xc8, 0x02, 0xfb, 0xa4, 0xa5, 0xa3, 0x8a, 0x8d,
+	0x04, 0xf6, 0x65, 0x99, 0x25, 0xa9, 0x24, 0xbb, 0x38, 0xd9, 0xc5, 0x63, 0x4f, 0x00, 0xba, 0x68,
+	0x33, 0x5d, 0x6f, 0xf0, 0x18, 0x70, 0xdb, 0x6b, 0xed, 0x9f, 0xf9, 0xae, 0xd5, 0xf6, 0x0f, 0xd0,
+	0xdc, 0x50, 0xc2, 0x57, 0x50, 0x8d, 0xf2, 0xf8, 0x9a, 0xe6, 0xda, 0x90, 0xd3, 0x9d, 0x5b, 0x79,
+	0x21, 0xd3, 0x74, 0x43, 0x5a, 0xc4, 0xfe, 0xc3, 0x80, 0x83, 0x2d, 0x14, 0x3f, 0x83, 0x56, 0x7f,
+	0x1e, 0xd3, 0xb4, 0x78, 0x15, 0xdc, 0x0c, 0x59, 0x5e, 0xc8, 0x5a, 0x2d, 0x72, 0x37, 0xb8, 0xc1,
+	0x8a, 0x53, 0xc9, 0x2a, 0xdd, 0x61, 0xa9, 0x20, 0x7a, 0x50, 0x1f, 0xb3, 0x8c, 0xcd, 0xd9, 0xf4,
+	0x56, 0xce, 0xd8, 0xec, 0xf5, 0x76, 0x69, 0x59, 0x89, 0x2c, 0x33, 0xc9, 0x4a, 0xc3, 0xfe, 0xab,
+	0x04, 0xed, 0xbb, 0x20, 0x7e, 0x04, 0xf5, 0x94, 0x45, 0xd4, 0x8f, 0x23, 0x6e, 0x19, 0xdd, 0xf2,
+	0x51, 0x8b, 0xd4, 0xc4, 0xde, 0x8d, 0x38, 0x8e, 0xa1, 0x11, 0xc5, 0xbc, 0x08, 0xd2, 0x90, 0x72,
+	0x7d, 0x78, 0xcf, 0xee, 0x5f, 0x7e, 0x74, 0xe1, 0x8e, 0xc9, 0x5a, 0x08, 0x2f, 0x60, 0x2f, 0x64,
+	0x39, 0xe5, 0x56, 0xb9, 0x5b, 0xfe, 0x7f, 0x8a, 0x7d, 0x96, 0x53, 0xa2, 0x44, 0xf0, 0x29, 0x7c,
+	0xc0, 0xae, 0x69, 0x9e, 0xc7, 0x11, 0xf5, 0x0b, 0x56, 0x04, 0x73, 0x3f, 0x64, 0x49, 0xb6, 0x28,
+	0xd4, 0xdf, 0xa6, 0x42, 0x1e, 0x2e, 0xd1, 0xb1, 0x00, 0xfb, 0x0a, 0xc3, 0xe7, 0x60, 0xad, 0xb2,
+	0x7e, 0x8a, 0x8b, 0x19, 0x9b, 0x47, 0xab, 0xbc, 0x3d, 0x99, 0xb7, 0x52, 0x7d, 0xa3, 0x60, 0x9d,
+	0x69, 0x7b, 0x80, 0xdb, 0xe3, 0xe1, 0x27, 0xc2, 0xa9, 0x84, 0xa6, 0xf2, 0x63, 0x54, 0xe7, 0xbd,
+	0x0e, 0x60, 0x07, 0xaa, 0xd7, 0xc1, 0x7c, 0x41, 0xd5, 0x95, 0xd0, 0x3a, 0x2b, 0x99, 0x06, 0xd1,
+	0x11, 0xfb, 0xf7, 0x12, 0xe0, 0xf6, 0x74, 0xf8, 0x31, 0x34, 0x38, 0x0b, 0xdf, 0xd2, 0xc2, 0x8f,
+	0x23, 0x2d, 0x58, 0x57, 0x01, 0x37, 0xc2, 0x0f, 0xa1, 0xa6, 0x8f, 0x4c, 0x7f, 0x35, 0x55, 0x75,
+	0x62, 0x02, 0x10, 0xae, 0x08, 0xa0, 0xac, 0x00, 0xb1, 0x75, 0x23, 0xbc, 0x00, 0x90, 0xc0, 0x34,
+	0x0f, 0x22, 0xe5, 0x4c, 0xbb, 0xf7, 0xf9, 0x4e, 0xc6, 0xb3, 0x9c, 0x9e, 0x8b, 0x24, 0xd2, 0x08,
+	0x97, 0x4b, 0xb4, 0xa0, 0x16, 0xc5, 0x3c, 0xb8, 0x9a, 0x2b, 0xb3, 0xea, 0x64, 0xb9, 0xc5, 0x47,
+	0x00, 0x22, 0x59, 0x5c, 0xc6, 0x34, 0xb2, 0xaa, 0xd2, 0xc9, 0x86, 0x88, 0x8c, 0x44, 0x40, 0x4c,
+	0x95, 0x04, 0x37, 0x1a, 0xad, 0x49, 0xb4, 0x9e, 0x04, 0x37, 0x0a, 0xfc, 0x14, 0x9a, 0xd3, 0x05,
+	0xe5, 0x5c, 0xc3, 0x75, 0x09, 0x83, 0x0c, 0x49, 0x82, 0xb8, 0xd6, 0x37, 0x6e, 0x22, 0x75, 0xc3,
+	0x3d, 0x19, 0x02, 0xac, 0xef, 0x63, 0x6c, 0x42, 0xed, 0x5b, 0xef, 0xa5, 0xf7, 0xfa, 0x8d, 0x67,
+	0x3e, 0x40, 0x80, 0xea, 0x0b, 0xe2, 0x5e, 0x0e, 0x88, 0x59, 0x92, 0xeb, 0xc1, 0xa5, 0xdb, 0x1f,
+	0x98, 0x65, 0x7c, 0x0f, 0x9a, 0xdf, 0xb8, 0xde, 0xf9, 0x80, 0x0c, 0x89, 0xeb, 0x8d, 0xcd, 0x0a,
+	0xee, 0x43, 0xfd, 0xe2, 0xf5, 0xb9, 0x3f, 0x72, 0xbd, 0x97, 0xe6, 0xde, 0x93, 0x63, 0x68, 0xac,
+	0xa6, 0x16, 0xdc, 0x21, 0xcd, 0x27, 0x2c, 0x4f, 0xc4, 0xc7, 0x6b, 0x3e, 0xc0, 0x36, 0xc0, 0x60,
+	0x32, 0x89, 0xc3, 0x98, 0xa6, 0xe1, 0xad, 0x69, 0xf4, 0x7e, 0x2b, 0x03, 0x9c, 0x05, 0x9c, 0xaa,
+	0x26, 0xf0, 0x17, 0x80, 0xf5, 0x23, 0x83, 0xa7, 0xbb, 0x3f, 0x27, 0x1b, 0x4f, 0x55, 0xe7, 0xd9,
+	0x7d, 0xd3, 0x94, 0x17, 0xf6, 0x03, 0xfc, 0xd5, 0x80, 0xfd, 0xcd, 0x87, 0x00, 0xbf, 0xdc, 0xed,
+	0x90, 0xb7, 0x5e, 0x94, 0xce, 0xf3, 0xfb, 0x27, 0xae, 0xba, 0xf8, 0x19, 0x1a, 0xab, 0x83, 0xc2,
+	0xa7, 0xbb, 0x08, 0xbd, 0xfb, 0xc2, 0x74, 0x4e, 0xef, 0x99, 0xb5, 0xac, 0x7d, 0x56, 0xfb, 0x7e,
+	0x4f, 0x82, 0x57, 0x55, 0xf9, 0xf3, 0xc5, 0x3f, 0x03, 0x00, 0xcc, 0x3d, 0xd2, 0x03, 0x77, 0x08,
+	0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
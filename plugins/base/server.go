@@ -29,6 +29,10 @@ func (b *basePluginServer) PluginInfo(context.Context, *proto.PluginInfoRequest)
 		ptype = proto.PluginType_DRIVER
 	case PluginTypeDevice:
 		ptype = proto.PluginType_DEVICE
+	case PluginTypeFingerprint:
+		ptype = proto.PluginType_FINGERPRINT
+	case PluginTypeLogSink:
+		ptype = proto.PluginType_LOG_SINK
 	default:
 		return nil, fmt.Errorf("plugin is of unknown type: %q", resp.Type)
 	}
@@ -68,6 +68,13 @@ type DriverPlugin interface {
 	ExecTask(taskID string, cmd []string, timeout time.Duration) (*ExecTaskResult, error)
 }
 
+// ImagePrefetcher marks that a driver can pull the image of a task ahead of
+// the placement of the task, so starting the task doesn't wait for the pull.
+// Only drivers loaded in the agent process are asked to prefetch images.
+type ImagePrefetcher interface {
+	PrefetchImage(image string) error
+}
+
 // ExecTaskStreamingDriver marks that a driver supports streaming exec task.  This represents a user friendly
 // interface to implement, as an alternative to the ExecTaskStreamingRawDriver, the low level interface.
 type ExecTaskStreamingDriver interface {
@@ -444,17 +451,40 @@ func (d *DeviceConfig) Copy() *DeviceConfig {
 	return dc
 }
 
+const (
+	// MountTypeBind is the type of a bind mount of a host path into the task.
+	// Mounts without a type are bind mounts.
+	MountTypeBind = "bind"
+
+	// MountTypeTmpfs is the type of a tmpfs mount, which is only created by
+	// the exec driver.
+	MountTypeTmpfs = "tmpfs"
+)
+
+// MountConfig is a mount into the task, either a bind mount of a HostPath or
+// a tmpfs mount.
 type MountConfig struct {
 	TaskPath        string
 	HostPath        string
 	Readonly        bool
 	PropagationMode string
 	SELinuxLabel    string
+
+	// Type is either MountTypeBind or MountTypeTmpfs. An empty type is a bind
+	// mount.
+	Type string
+}
+
+// IsTmpfs returns true if the mount is a tmpfs mount rather than a bind mount
+// of a host path.
+func (m *MountConfig) IsTmpfs() bool {
+	return m.Type == MountTypeTmpfs
 }
 
 func (m *MountConfig) IsEqual(o *MountConfig) bool {
 	return m.TaskPath == o.TaskPath &&
 		m.HostPath == o.HostPath &&
+		m.Type == o.Type &&
 		m.Readonly == o.Readonly &&
 		m.PropagationMode == o.PropagationMode &&
 		m.SELinuxLabel == o.SELinuxLabel
@@ -498,6 +528,18 @@ func (r *ExitResult) Copy() *ExitResult {
 	return res
 }
 
+const (
+	// TaskStatusAttrHealth is the driver attribute of a TaskStatus with the
+	// health status of the task's container, as determined by the healthcheck
+	// of its image: one of "starting", "healthy" or "unhealthy". Drivers set it
+	// only for tasks whose container has a healthcheck.
+	TaskStatusAttrHealth = "health_status"
+
+	// TaskStatusAttrHealthOutput is the driver attribute of a TaskStatus with
+	// the output of the last healthcheck of the task's container.
+	TaskStatusAttrHealthOutput = "health_output"
+)
+
 type TaskStatus struct {
 	ID               string
 	Name             string
@@ -632,3 +674,14 @@ type InternalCapabilities struct {
 	DisableLogCollection     bool
 	DisableMetricsCollection bool
 }
+
+// ResourceUpdaterDriver is an experimental interface enabling a driver to
+// update the cpu and memory limits of a running task, so that changes to the
+// resources of a task can be applied without replacing the allocation. Drivers
+// implementing it advertise the "driver.<name>.resize" fingerprint attribute
+// so the scheduler can mark resource updates as in-place.
+//
+// Intended for internal drivers only while the interface is stabalized.
+type ResourceUpdaterDriver interface {
+	UpdateTaskResources(taskID string, resources *Resources) error
+}
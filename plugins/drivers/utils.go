@@ -295,6 +295,7 @@ func MountFromProto(mount *proto.Mount) *MountConfig {
 		Readonly:        mount.Readonly,
 		PropagationMode: mount.PropagationMode,
 		SELinuxLabel:    mount.SelinuxLabel,
+		Type:            mount.Type,
 	}
 }
 
@@ -347,6 +348,7 @@ func MountToProto(mount *MountConfig) *proto.Mount {
 		Readonly:        mount.Readonly,
 		PropagationMode: mount.PropagationMode,
 		SelinuxLabel:    mount.SELinuxLabel,
+		Type:            mount.Type,
 	}
 }
 
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fingerprint
+
+import (
+	"context"
+
+	"github.com/LK4D4/joincontext"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/nomad/helper/pluginutils/grpcutils"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/fingerprint/proto"
+)
+
+// fingerprintPluginClient implements the client side of a remote fingerprint
+// plugin, using gRPC to communicate to the remote plugin.
+type fingerprintPluginClient struct {
+	// basePluginClient is embedded to give access to the base plugin methods.
+	*base.BasePluginClient
+
+	client proto.FingerprintPluginClient
+
+	// doneCtx is closed when the plugin exits
+	doneCtx context.Context
+}
+
+// Fingerprint is used to retrieve the node attributes detected by the
+// fingerprint plugin. If the context is cancelled, the error will be
+// propagated.
+func (f *fingerprintPluginClient) Fingerprint(ctx context.Context) (*FingerprintResponse, error) {
+	// Join the passed context and the shutdown context
+	joinedCtx, _ := joincontext.Join(ctx, f.doneCtx)
+
+	resp, err := f.client.Fingerprint(joinedCtx, &proto.FingerprintRequest{})
+	if err != nil {
+		return nil, grpcutils.HandleReqCtxGrpcErr(err, ctx, f.doneCtx)
+	}
+
+	out := &FingerprintResponse{
+		Attributes:        resp.GetAttributes(),
+		Healthy:           resp.GetHealthy(),
+		HealthDescription: resp.GetHealthDescription(),
+	}
+	if resp.GetPeriod() != nil {
+		period, err := ptypes.Duration(resp.GetPeriod())
+		if err != nil {
+			return nil, err
+		}
+		out.Period = period
+	}
+
+	return out, nil
+}
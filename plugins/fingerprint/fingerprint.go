@@ -0,0 +1,39 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fingerprint
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/nomad/plugins/base"
+)
+
+// FingerprintPlugin is the interface for a plugin that detects node attributes
+// on behalf of the Nomad client.
+type FingerprintPlugin interface {
+	base.BasePlugin
+
+	// Fingerprint detects the node attributes the plugin is responsible for.
+	Fingerprint(ctx context.Context) (*FingerprintResponse, error)
+}
+
+// FingerprintResponse is the set of node attributes detected by a fingerprint
+// plugin.
+type FingerprintResponse struct {
+	// Attributes are the detected node attributes. Attributes that were
+	// returned by an earlier fingerprint but are missing from this one are
+	// removed from the node.
+	Attributes map[string]string
+
+	// Period is the interval after which the client fingerprints again. A zero
+	// period means the plugin is only fingerprinted once.
+	Period time.Duration
+
+	// Healthy is whether the plugin was able to fingerprint the node.
+	Healthy bool
+
+	// HealthDescription describes why the plugin is unhealthy.
+	HealthDescription string
+}
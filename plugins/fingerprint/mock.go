@@ -0,0 +1,31 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fingerprint
+
+import (
+	"context"
+
+	"github.com/hashicorp/nomad/plugins/base"
+)
+
+type FingerprintFn func(context.Context) (*FingerprintResponse, error)
+
+// MockFingerprintPlugin is used for testing.
+// Each function can be set as a closure to make assertions about how data
+// is passed through the base plugin layer.
+type MockFingerprintPlugin struct {
+	*base.MockPlugin
+	FingerprintF FingerprintFn
+}
+
+func (p *MockFingerprintPlugin) Fingerprint(ctx context.Context) (*FingerprintResponse, error) {
+	return p.FingerprintF(ctx)
+}
+
+// StaticFingerprinter returns the passed response on every fingerprint
+func StaticFingerprinter(resp *FingerprintResponse) FingerprintFn {
+	return func(_ context.Context) (*FingerprintResponse, error) {
+		return resp, nil
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fingerprint
+
+import (
+	"context"
+
+	log "github.com/hashicorp/go-hclog"
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/plugins/base"
+	bproto "github.com/hashicorp/nomad/plugins/base/proto"
+	"github.com/hashicorp/nomad/plugins/fingerprint/proto"
+	"google.golang.org/grpc"
+)
+
+// PluginFingerprint is wraps a FingerprintPlugin and implements go-plugins
+// GRPCPlugin interface to expose the interface over gRPC.
+type PluginFingerprint struct {
+	plugin.NetRPCUnsupportedPlugin
+	Impl FingerprintPlugin
+}
+
+func (p *PluginFingerprint) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	proto.RegisterFingerprintPluginServer(s, &fingerprintPluginServer{
+		impl:   p.Impl,
+		broker: broker,
+	})
+	return nil
+}
+
+func (p *PluginFingerprint) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &fingerprintPluginClient{
+		doneCtx: ctx,
+		client:  proto.NewFingerprintPluginClient(c),
+		BasePluginClient: &base.BasePluginClient{
+			Client:  bproto.NewBasePluginClient(c),
+			DoneCtx: ctx,
+		},
+	}, nil
+}
+
+// Serve is used to serve a fingerprint plugin
+func Serve(fp FingerprintPlugin, logger log.Logger) {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: base.Handshake,
+		Plugins: map[string]plugin.Plugin{
+			base.PluginTypeBase:        &base.PluginBase{Impl: fp},
+			base.PluginTypeFingerprint: &PluginFingerprint{Impl: fp},
+		},
+		GRPCServer: plugin.DefaultGRPCServer,
+		Logger:     logger,
+	})
+}
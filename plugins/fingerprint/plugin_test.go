@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fingerprint
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/shoenig/test/must"
+)
+
+func testFingerprintPlugin(t *testing.T, mock *MockFingerprintPlugin) FingerprintPlugin {
+	client, server := plugin.TestPluginGRPCConn(t, true, map[string]plugin.Plugin{
+		base.PluginTypeBase:        &base.PluginBase{Impl: mock},
+		base.PluginTypeFingerprint: &PluginFingerprint{Impl: mock},
+	})
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+
+	raw, err := client.Dispense(base.PluginTypeFingerprint)
+	must.NoError(t, err)
+
+	impl, ok := raw.(FingerprintPlugin)
+	must.True(t, ok)
+	return impl
+}
+
+func TestFingerprintPlugin_PluginInfo(t *testing.T) {
+	ci.Parallel(t)
+
+	mock := &MockFingerprintPlugin{
+		MockPlugin: &base.MockPlugin{
+			PluginInfoF: func() (*base.PluginInfoResponse, error) {
+				return &base.PluginInfoResponse{
+					Type:              base.PluginTypeFingerprint,
+					PluginApiVersions: []string{ApiVersion010},
+					PluginVersion:     "v0.1.0",
+					Name:              "mock_fingerprint",
+				}, nil
+			},
+		},
+	}
+	impl := testFingerprintPlugin(t, mock)
+
+	resp, err := impl.PluginInfo()
+	must.NoError(t, err)
+	must.Eq(t, base.PluginTypeFingerprint, resp.Type)
+	must.Eq(t, "mock_fingerprint", resp.Name)
+	must.Eq(t, []string{ApiVersion010}, resp.PluginApiVersions)
+}
+
+func TestFingerprintPlugin_Fingerprint(t *testing.T) {
+	ci.Parallel(t)
+
+	expected := &FingerprintResponse{
+		Attributes: map[string]string{
+			"fpga.vendor": "xilinx",
+			"fpga.count":  "2",
+		},
+		Period:            30 * time.Second,
+		Healthy:           true,
+		HealthDescription: "",
+	}
+	mock := &MockFingerprintPlugin{
+		MockPlugin:   &base.MockPlugin{},
+		FingerprintF: StaticFingerprinter(expected),
+	}
+	impl := testFingerprintPlugin(t, mock)
+
+	resp, err := impl.Fingerprint(context.Background())
+	must.NoError(t, err)
+	must.Eq(t, expected, resp)
+
+	// A plugin without a period is only fingerprinted once
+	mock.FingerprintF = StaticFingerprinter(&FingerprintResponse{
+		Healthy:           false,
+		HealthDescription: "license server unreachable",
+	})
+	resp, err = impl.Fingerprint(context.Background())
+	must.NoError(t, err)
+	must.Zero(t, resp.Period)
+	must.False(t, resp.Healthy)
+	must.Eq(t, "license server unreachable", resp.HealthDescription)
+
+	// Errors are propagated
+	mock.FingerprintF = func(context.Context) (*FingerprintResponse, error) {
+		return nil, errors.New("fingerprint failed")
+	}
+	_, err = impl.Fingerprint(context.Background())
+	must.ErrorContains(t, err, "fingerprint failed")
+}
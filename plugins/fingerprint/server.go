@@ -0,0 +1,37 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package fingerprint
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/hashicorp/nomad/plugins/fingerprint/proto"
+)
+
+// fingerprintPluginServer wraps a fingerprint plugin and exposes it via gRPC.
+type fingerprintPluginServer struct {
+	broker *plugin.GRPCBroker
+	impl   FingerprintPlugin
+}
+
+func (f *fingerprintPluginServer) Fingerprint(ctx context.Context, req *proto.FingerprintRequest) (*proto.FingerprintResponse, error) {
+	resp, err := f.impl.Fingerprint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	presp := &proto.FingerprintResponse{
+		Attributes:        resp.Attributes,
+		Healthy:           resp.Healthy,
+		HealthDescription: resp.HealthDescription,
+	}
+	if resp.Period > 0 {
+		presp.Period = ptypes.DurationProto(resp.Period)
+	}
+
+	return presp, nil
+}
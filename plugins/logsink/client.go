@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logsink
+
+import (
+	"context"
+
+	"github.com/LK4D4/joincontext"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/nomad/helper/pluginutils/grpcutils"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/hashicorp/nomad/plugins/logsink/proto"
+)
+
+// logSinkPluginClient implements the client side of a remote log sink plugin,
+// using gRPC to communicate to the remote plugin.
+type logSinkPluginClient struct {
+	// basePluginClient is embedded to give access to the base plugin methods.
+	*base.BasePluginClient
+
+	client proto.LogSinkPluginClient
+
+	// doneCtx is closed when the plugin exits
+	doneCtx context.Context
+}
+
+// ShipLogs is used to ship a batch of log lines with the log sink plugin. If
+// the context is cancelled, the error will be propagated.
+func (l *logSinkPluginClient) ShipLogs(ctx context.Context, batch *LogBatch) error {
+	req := &proto.ShipLogsRequest{
+		Options: batch.Options,
+		Entries: make([]*proto.LogEntry, 0, len(batch.Entries)),
+	}
+	if t := batch.Task; t != nil {
+		req.Task = &proto.TaskInfo{
+			Namespace: t.Namespace,
+			JobId:     t.JobID,
+			AllocId:   t.AllocID,
+			TaskGroup: t.TaskGroup,
+			TaskName:  t.TaskName,
+			NodeId:    t.NodeID,
+		}
+	}
+	for _, e := range batch.Entries {
+		ts, err := ptypes.TimestampProto(e.Timestamp)
+		if err != nil {
+			return err
+		}
+		req.Entries = append(req.Entries, &proto.LogEntry{
+			Timestamp: ts,
+			Stream:    e.Stream,
+			Line:      e.Line,
+		})
+	}
+
+	// Join the passed context and the shutdown context
+	joinedCtx, _ := joincontext.Join(ctx, l.doneCtx)
+
+	if _, err := l.client.ShipLogs(joinedCtx, req); err != nil {
+		return grpcutils.HandleReqCtxGrpcErr(err, ctx, l.doneCtx)
+	}
+	return nil
+}
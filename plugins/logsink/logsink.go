@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logsink
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/nomad/plugins/base"
+)
+
+const (
+	// StreamStdout is the stream of log lines written to the standard output
+	// of a task.
+	StreamStdout = "stdout"
+
+	// StreamStderr is the stream of log lines written to the standard error
+	// of a task.
+	StreamStderr = "stderr"
+)
+
+// LogSinkPlugin is the interface for a plugin that ships the logs of tasks to
+// a log store on behalf of the Nomad client.
+type LogSinkPlugin interface {
+	base.BasePlugin
+
+	// ShipLogs ships a batch of log lines written by a task. Batches of the
+	// same task are shipped in order, and a batch is retried if an error is
+	// returned.
+	ShipLogs(ctx context.Context, batch *LogBatch) error
+}
+
+// LogBatch is a batch of log lines written by a task.
+type LogBatch struct {
+	// Task identifies the task that wrote the log lines.
+	Task *TaskInfo
+
+	// Options are the options set for the log sink by the logs block of the
+	// task, such as labels or an index name.
+	Options map[string]string
+
+	// Entries are the log lines, in the order they were written.
+	Entries []*LogEntry
+}
+
+// TaskInfo identifies the task that wrote log lines.
+type TaskInfo struct {
+	Namespace string
+	JobID     string
+	AllocID   string
+	TaskGroup string
+	TaskName  string
+	NodeID    string
+}
+
+// LogEntry is a log line written by a task.
+type LogEntry struct {
+	// Timestamp is the time the log line was read from the task.
+	Timestamp time.Time
+
+	// Stream is the output stream the line was written to, either
+	// StreamStdout or StreamStderr.
+	Stream string
+
+	// Line is the log line, without its trailing newline.
+	Line []byte
+}
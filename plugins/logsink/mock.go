@@ -0,0 +1,24 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logsink
+
+import (
+	"context"
+
+	"github.com/hashicorp/nomad/plugins/base"
+)
+
+type ShipLogsFn func(context.Context, *LogBatch) error
+
+// MockLogSinkPlugin is used for testing.
+// Each function can be set as a closure to make assertions about how data
+// is passed through the base plugin layer.
+type MockLogSinkPlugin struct {
+	*base.MockPlugin
+	ShipLogsF ShipLogsFn
+}
+
+func (p *MockLogSinkPlugin) ShipLogs(ctx context.Context, batch *LogBatch) error {
+	return p.ShipLogsF(ctx, batch)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logsink
+
+import (
+	"context"
+
+	log "github.com/hashicorp/go-hclog"
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/plugins/base"
+	bproto "github.com/hashicorp/nomad/plugins/base/proto"
+	"github.com/hashicorp/nomad/plugins/logsink/proto"
+	"google.golang.org/grpc"
+)
+
+// PluginLogSink wraps a LogSinkPlugin and implements go-plugins GRPCPlugin
+// interface to expose the interface over gRPC.
+type PluginLogSink struct {
+	plugin.NetRPCUnsupportedPlugin
+	Impl LogSinkPlugin
+}
+
+func (p *PluginLogSink) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	proto.RegisterLogSinkPluginServer(s, &logSinkPluginServer{
+		impl:   p.Impl,
+		broker: broker,
+	})
+	return nil
+}
+
+func (p *PluginLogSink) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &logSinkPluginClient{
+		doneCtx: ctx,
+		client:  proto.NewLogSinkPluginClient(c),
+		BasePluginClient: &base.BasePluginClient{
+			Client:  bproto.NewBasePluginClient(c),
+			DoneCtx: ctx,
+		},
+	}, nil
+}
+
+// Serve is used to serve a log sink plugin
+func Serve(ls LogSinkPlugin, logger log.Logger) {
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: base.Handshake,
+		Plugins: map[string]plugin.Plugin{
+			base.PluginTypeBase:    &base.PluginBase{Impl: ls},
+			base.PluginTypeLogSink: &PluginLogSink{Impl: ls},
+		},
+		GRPCServer: plugin.DefaultGRPCServer,
+		Logger:     logger,
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logsink
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	plugin "github.com/hashicorp/go-plugin"
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/plugins/base"
+	"github.com/shoenig/test/must"
+)
+
+func testLogSinkPlugin(t *testing.T, mock *MockLogSinkPlugin) LogSinkPlugin {
+	client, server := plugin.TestPluginGRPCConn(t, true, map[string]plugin.Plugin{
+		base.PluginTypeBase:    &base.PluginBase{Impl: mock},
+		base.PluginTypeLogSink: &PluginLogSink{Impl: mock},
+	})
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+
+	raw, err := client.Dispense(base.PluginTypeLogSink)
+	must.NoError(t, err)
+
+	impl, ok := raw.(LogSinkPlugin)
+	must.True(t, ok)
+	return impl
+}
+
+func TestLogSinkPlugin_PluginInfo(t *testing.T) {
+	ci.Parallel(t)
+
+	mock := &MockLogSinkPlugin{
+		MockPlugin: &base.MockPlugin{
+			PluginInfoF: func() (*base.PluginInfoResponse, error) {
+				return &base.PluginInfoResponse{
+					Type:              base.PluginTypeLogSink,
+					PluginApiVersions: []string{ApiVersion010},
+					PluginVersion:     "v0.1.0",
+					Name:              "mock_log_sink",
+				}, nil
+			},
+		},
+	}
+	impl := testLogSinkPlugin(t, mock)
+
+	resp, err := impl.PluginInfo()
+	must.NoError(t, err)
+	must.Eq(t, base.PluginTypeLogSink, resp.Type)
+	must.Eq(t, "mock_log_sink", resp.Name)
+	must.Eq(t, []string{ApiVersion010}, resp.PluginApiVersions)
+}
+
+func TestLogSinkPlugin_ShipLogs(t *testing.T) {
+	ci.Parallel(t)
+
+	var shipped *LogBatch
+	mock := &MockLogSinkPlugin{
+		MockPlugin: &base.MockPlugin{},
+		ShipLogsF: func(_ context.Context, batch *LogBatch) error {
+			shipped = batch
+			return nil
+		},
+	}
+	impl := testLogSinkPlugin(t, mock)
+
+	now := time.Now().UTC()
+	batch := &LogBatch{
+		Task: &TaskInfo{
+			Namespace: "default",
+			JobID:     "example",
+			AllocID:   "a8198d79-cfdb-6593-a999-1e9adabcba2e",
+			TaskGroup: "cache",
+			TaskName:  "redis",
+			NodeID:    "c5ea5fa4-fc47-3e4c-0ab8-1f8b2c4a4e7a",
+		},
+		Options: map[string]string{"index": "redis"},
+		Entries: []*LogEntry{
+			{Timestamp: now, Stream: StreamStdout, Line: []byte("ready to accept connections")},
+			{Timestamp: now.Add(time.Second), Stream: StreamStderr, Line: []byte("out of memory")},
+		},
+	}
+	must.NoError(t, impl.ShipLogs(context.Background(), batch))
+	must.Eq(t, batch, shipped)
+
+	// Errors are propagated
+	mock.ShipLogsF = func(context.Context, *LogBatch) error {
+		return errors.New("log store unreachable")
+	}
+	err := impl.ShipLogs(context.Background(), batch)
+	must.ErrorContains(t, err, "log store unreachable")
+}
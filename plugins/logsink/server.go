@@ -0,0 +1,55 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logsink
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/hashicorp/nomad/plugins/logsink/proto"
+)
+
+// logSinkPluginServer wraps a log sink plugin and exposes it via gRPC.
+type logSinkPluginServer struct {
+	broker *plugin.GRPCBroker
+	impl   LogSinkPlugin
+}
+
+func (l *logSinkPluginServer) ShipLogs(ctx context.Context, req *proto.ShipLogsRequest) (*proto.ShipLogsResponse, error) {
+	batch := &LogBatch{
+		Options: req.GetOptions(),
+		Entries: make([]*LogEntry, 0, len(req.GetEntries())),
+	}
+	if t := req.GetTask(); t != nil {
+		batch.Task = &TaskInfo{
+			Namespace: t.GetNamespace(),
+			JobID:     t.GetJobId(),
+			AllocID:   t.GetAllocId(),
+			TaskGroup: t.GetTaskGroup(),
+			TaskName:  t.GetTaskName(),
+			NodeID:    t.GetNodeId(),
+		}
+	}
+	for _, e := range req.GetEntries() {
+		entry := &LogEntry{
+			Stream: e.GetStream(),
+			Line:   e.GetLine(),
+		}
+		if e.GetTimestamp() != nil {
+			ts, err := ptypes.Timestamp(e.GetTimestamp())
+			if err != nil {
+				return nil, err
+			}
+			entry.Timestamp = ts
+		}
+		batch.Entries = append(batch.Entries, entry)
+	}
+
+	if err := l.impl.ShipLogs(ctx, batch); err != nil {
+		return nil, err
+	}
+	return &proto.ShipLogsResponse{}, nil
+}
@@ -5,6 +5,7 @@ package scheduler
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/nomad/nomad/structs"
 )
@@ -36,7 +37,11 @@ const (
 //   - Count up and count down changes
 //   - Update counts (creates, destroys, migrates, etc)
 //
-// * Task changes will be annotated with:
+// * The changed fields and objects of an edited task group will be annotated with:
+//   - forces create/destroy update
+//   - forces in-place update
+//
+// * Task changes and the changed fields and objects of a task will be annotated with:
 //   - forces create/destroy update
 //   - forces in-place update
 func Annotate(diff *structs.JobDiff, annotations *structs.PlanAnnotations) error {
@@ -93,6 +98,9 @@ func annotateTaskGroup(diff *structs.TaskGroupDiff, annotations *structs.PlanAnn
 		return err
 	}
 
+	// Annotate the group level fields and objects
+	annotateTaskGroupChanges(diff)
+
 	// Annotate the tasks.
 	taskDiffs := diff.Tasks
 	if len(taskDiffs) == 0 {
@@ -150,7 +158,83 @@ func annotateCountChange(diff *structs.TaskGroupDiff) error {
 	return nil
 }
 
-// annotateCountChange takes a task diff and annotates it.
+// annotateTaskGroupChanges annotates every changed field and object of an
+// edited task group with the type of update it forces. The count is
+// annotated separately.
+func annotateTaskGroupChanges(diff *structs.TaskGroupDiff) {
+	if diff.Type != structs.DiffTypeEdited {
+		return
+	}
+
+	for _, fDiff := range diff.Fields {
+		if fDiff.Type == structs.DiffTypeNone || fDiff.Name == "Count" {
+			continue
+		}
+		fDiff.Annotations = append(fDiff.Annotations,
+			updateAnnotation(taskGroupFieldForcesDestructive(fDiff)))
+	}
+
+	for _, oDiff := range diff.Objects {
+		if oDiff.Type == structs.DiffTypeNone {
+			continue
+		}
+		oDiff.Annotations = append(oDiff.Annotations,
+			updateAnnotation(taskGroupObjectForcesDestructive(oDiff)))
+	}
+}
+
+// taskGroupFieldForcesDestructive returns whether a change to a primitive task
+// group field requires the allocation to be replaced. Only the group meta is
+// passed down to the tasks, all other fields can be updated in-place.
+func taskGroupFieldForcesDestructive(diff *structs.FieldDiff) bool {
+	return strings.HasPrefix(diff.Name, "Meta[")
+}
+
+// taskGroupObjectForcesDestructive returns whether a change to a task group
+// object requires the allocation to be replaced. It follows the rules used by
+// tasksUpdated to compare task groups.
+func taskGroupObjectForcesDestructive(diff *structs.ObjectDiff) bool {
+	switch diff.Name {
+	case "Network", "EphemeralDisk", "Volume", "Affinity", "Spread":
+		return true
+	case "Consul":
+		// the namespace is always pushed down to the group, but the cluster
+		// and partition are only compared when both are set
+		return diff.Type == structs.DiffTypeEdited || fieldChanged(diff, "Namespace")
+	case "RestartPolicy":
+		// the template hook has to be restarted to receive render_templates,
+		// which is disabled when the policy is unset
+		for _, fDiff := range diff.Fields {
+			if fDiff.Name == "RenderTemplates" {
+				return (fDiff.Old == "true") != (fDiff.New == "true")
+			}
+		}
+		return false
+	case "Service":
+		// ordinary services are updated in Consul, but most Connect changes
+		// require the task to be destroyed
+		for _, oDiff := range diff.Objects {
+			if oDiff.Name == "ConsulConnect" && oDiff.Type != structs.DiffTypeNone {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// fieldChanged returns whether the named field of an object diff changed.
+func fieldChanged(diff *structs.ObjectDiff, name string) bool {
+	for _, fDiff := range diff.Fields {
+		if fDiff.Name == name && fDiff.Type != structs.DiffTypeNone {
+			return true
+		}
+	}
+	return false
+}
+
+// annotateTask takes a task diff and annotates it.
 func annotateTask(diff *structs.TaskDiff, parent *structs.TaskGroupDiff) {
 	if diff.Type == structs.DiffTypeNone {
 		return
@@ -167,49 +251,97 @@ func annotateTask(diff *structs.TaskDiff, parent *structs.TaskGroupDiff) {
 		}
 	}
 
-	// All changes to primitive fields result in a destructive update except
-	// KillTimeout
+	// When a task is edited, every changed field and object is annotated with
+	// the type of update it forces so consumers of the diff can tell which
+	// changes are responsible for a destructive update.
+	edited := diff.Type == structs.DiffTypeEdited
+
 	destructive := false
-FieldsLoop:
 	for _, fDiff := range diff.Fields {
-		switch fDiff.Name {
-		case "KillTimeout":
+		if fDiff.Type == structs.DiffTypeNone {
 			continue
-		default:
-			destructive = true
-			break FieldsLoop
 		}
+
+		fieldDestructive := taskFieldForcesDestructive(fDiff)
+		if edited {
+			fDiff.Annotations = append(fDiff.Annotations, updateAnnotation(fieldDestructive))
+		}
+		destructive = destructive || fieldDestructive
 	}
 
-	// Object changes that can be done in-place are log configs, services,
-	// constraints.
+	for _, oDiff := range diff.Objects {
+		if oDiff.Type == structs.DiffTypeNone {
+			continue
+		}
 
-	if !destructive {
-	ObjectsLoop:
-		for _, oDiff := range diff.Objects {
-			switch oDiff.Name {
-			case "Service", "Constraint":
-				continue
-			case "LogConfig":
-				for _, fDiff := range oDiff.Fields {
-					switch fDiff.Name {
-					// force a destructive update if logger was enabled or disabled
-					case "Disabled":
-						destructive = true
-						break ObjectsLoop
-					}
-				}
-				continue
-			default:
-				destructive = true
-				break ObjectsLoop
-			}
+		objectDestructive := taskObjectForcesDestructive(oDiff)
+		if edited {
+			oDiff.Annotations = append(oDiff.Annotations, updateAnnotation(objectDestructive))
+		}
+		destructive = destructive || objectDestructive
+	}
+
+	diff.Annotations = append(diff.Annotations, updateAnnotation(destructive))
+}
+
+// taskFieldForcesDestructive returns whether a change to a primitive task
+// field requires the allocation to be replaced. All changes to primitive
+// fields result in a destructive update except KillTimeout.
+func taskFieldForcesDestructive(diff *structs.FieldDiff) bool {
+	switch diff.Name {
+	case "KillTimeout":
+		return false
+	default:
+		return true
+	}
+}
+
+// taskObjectForcesDestructive returns whether a change to a task object
+// requires the allocation to be replaced. Object changes that can be done
+// in-place are log configs, services, constraints, and resources changes
+// limited to cpu and memory.
+func taskObjectForcesDestructive(diff *structs.ObjectDiff) bool {
+	switch diff.Name {
+	case "Service", "Constraint":
+		return false
+	case "LogConfig":
+		// force a destructive update if logger was enabled or disabled
+		return fieldChanged(diff, "Disabled")
+	case "Resources":
+		return resourcesForcesDestructive(diff)
+	default:
+		return true
+	}
+}
+
+// resourcesForcesDestructive returns whether a change to the resources of a
+// task requires the allocation to be replaced. Like in tasksUpdated, changes
+// limited to cpu and memory are resized in-place on nodes whose task driver
+// supports it, and the scheduler replaces the allocation on other nodes.
+func resourcesForcesDestructive(diff *structs.ObjectDiff) bool {
+	for _, fDiff := range diff.Fields {
+		if fDiff.Type == structs.DiffTypeNone {
+			continue
+		}
+		switch fDiff.Name {
+		case "CPU", "MemoryMB", "MemoryMaxMB":
+		default:
+			return true
 		}
 	}
+	for _, oDiff := range diff.Objects {
+		if oDiff.Type != structs.DiffTypeNone {
+			return true
+		}
+	}
+	return false
+}
 
+// updateAnnotation returns the annotation for a change that does or does not
+// force a destructive update.
+func updateAnnotation(destructive bool) string {
 	if destructive {
-		diff.Annotations = append(diff.Annotations, AnnotationForcesDestructiveUpdate)
-	} else {
-		diff.Annotations = append(diff.Annotations, AnnotationForcesInplaceUpdate)
+		return AnnotationForcesDestructiveUpdate
 	}
+	return AnnotationForcesInplaceUpdate
 }
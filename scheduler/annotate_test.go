@@ -9,6 +9,7 @@ import (
 
 	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
 )
 
 func TestAnnotateTaskGroup_Updates(t *testing.T) {
@@ -466,3 +467,237 @@ func TestAnnotateTask(t *testing.T) {
 		}
 	}
 }
+
+func TestAnnotateTask_FieldsAndObjects(t *testing.T) {
+	ci.Parallel(t)
+
+	killTimeout := &structs.FieldDiff{
+		Type: structs.DiffTypeEdited,
+		Name: "KillTimeout",
+		Old:  "5000000000",
+		New:  "10000000000",
+	}
+	user := &structs.FieldDiff{
+		Type: structs.DiffTypeEdited,
+		Name: "User",
+		Old:  "alice",
+		New:  "bob",
+	}
+	unchanged := &structs.FieldDiff{
+		Type: structs.DiffTypeNone,
+		Name: "Driver",
+		Old:  "docker",
+		New:  "docker",
+	}
+	service := &structs.ObjectDiff{
+		Type: structs.DiffTypeEdited,
+		Name: "Service",
+	}
+	resources := &structs.ObjectDiff{
+		Type: structs.DiffTypeNone,
+		Name: "Resources",
+	}
+	config := &structs.ObjectDiff{
+		Type: structs.DiffTypeEdited,
+		Name: "Config",
+	}
+
+	// Only in-place changes and unchanged objects
+	diff := &structs.TaskDiff{
+		Type:    structs.DiffTypeEdited,
+		Fields:  []*structs.FieldDiff{killTimeout, unchanged},
+		Objects: []*structs.ObjectDiff{service, resources},
+	}
+	annotateTask(diff, &structs.TaskGroupDiff{Type: structs.DiffTypeEdited})
+
+	must.Eq(t, []string{AnnotationForcesInplaceUpdate}, diff.Annotations)
+	must.Eq(t, []string{AnnotationForcesInplaceUpdate}, killTimeout.Annotations)
+	must.Eq(t, []string{AnnotationForcesInplaceUpdate}, service.Annotations)
+	must.SliceEmpty(t, unchanged.Annotations)
+	must.SliceEmpty(t, resources.Annotations)
+
+	// Mixed in-place and destructive changes
+	killTimeout.Annotations = nil
+	service.Annotations = nil
+	diff = &structs.TaskDiff{
+		Type:    structs.DiffTypeEdited,
+		Fields:  []*structs.FieldDiff{killTimeout, user},
+		Objects: []*structs.ObjectDiff{service, config},
+	}
+	annotateTask(diff, &structs.TaskGroupDiff{Type: structs.DiffTypeEdited})
+
+	must.Eq(t, []string{AnnotationForcesDestructiveUpdate}, diff.Annotations)
+	must.Eq(t, []string{AnnotationForcesInplaceUpdate}, killTimeout.Annotations)
+	must.Eq(t, []string{AnnotationForcesDestructiveUpdate}, user.Annotations)
+	must.Eq(t, []string{AnnotationForcesInplaceUpdate}, service.Annotations)
+	must.Eq(t, []string{AnnotationForcesDestructiveUpdate}, config.Annotations)
+}
+
+func TestAnnotateTaskGroup_FieldsAndObjects(t *testing.T) {
+	ci.Parallel(t)
+
+	count := &structs.FieldDiff{
+		Type: structs.DiffTypeEdited,
+		Name: "Count",
+		Old:  "1",
+		New:  "2",
+	}
+	shutdownDelay := &structs.FieldDiff{
+		Type: structs.DiffTypeEdited,
+		Name: "ShutdownDelay",
+		Old:  "0",
+		New:  "5000000000",
+	}
+	meta := &structs.FieldDiff{
+		Type: structs.DiffTypeAdded,
+		Name: "Meta[rack]",
+		New:  "r1",
+	}
+	constraint := &structs.ObjectDiff{
+		Type: structs.DiffTypeAdded,
+		Name: "Constraint",
+	}
+	reschedule := &structs.ObjectDiff{
+		Type: structs.DiffTypeEdited,
+		Name: "ReschedulePolicy",
+	}
+	restart := &structs.ObjectDiff{
+		Type: structs.DiffTypeEdited,
+		Name: "RestartPolicy",
+		Fields: []*structs.FieldDiff{{
+			Type: structs.DiffTypeEdited,
+			Name: "Attempts",
+			Old:  "2",
+			New:  "3",
+		}},
+	}
+	renderTemplates := &structs.ObjectDiff{
+		Type: structs.DiffTypeAdded,
+		Name: "RestartPolicy",
+		Fields: []*structs.FieldDiff{{
+			Type: structs.DiffTypeAdded,
+			Name: "RenderTemplates",
+			New:  "true",
+		}},
+	}
+	service := &structs.ObjectDiff{
+		Type: structs.DiffTypeEdited,
+		Name: "Service",
+	}
+	connect := &structs.ObjectDiff{
+		Type: structs.DiffTypeEdited,
+		Name: "Service",
+		Objects: []*structs.ObjectDiff{{
+			Type: structs.DiffTypeEdited,
+			Name: "ConsulConnect",
+		}},
+	}
+	network := &structs.ObjectDiff{
+		Type: structs.DiffTypeEdited,
+		Name: "Network",
+	}
+	disk := &structs.ObjectDiff{
+		Type: structs.DiffTypeEdited,
+		Name: "EphemeralDisk",
+	}
+	volume := &structs.ObjectDiff{
+		Type: structs.DiffTypeAdded,
+		Name: "Volume",
+	}
+	affinity := &structs.ObjectDiff{
+		Type: structs.DiffTypeDeleted,
+		Name: "Affinity",
+	}
+	spread := &structs.ObjectDiff{
+		Type: structs.DiffTypeAdded,
+		Name: "Spread",
+	}
+
+	diff := &structs.TaskGroupDiff{
+		Type:   structs.DiffTypeEdited,
+		Fields: []*structs.FieldDiff{count, shutdownDelay, meta},
+		Objects: []*structs.ObjectDiff{constraint, reschedule, restart,
+			renderTemplates, service, connect, network, disk, volume,
+			affinity, spread},
+	}
+	must.NoError(t, annotateTaskGroup(diff, nil))
+
+	inplace := []string{AnnotationForcesInplaceUpdate}
+	destructive := []string{AnnotationForcesDestructiveUpdate}
+
+	must.Eq(t, []string{AnnotationForcesCreate}, count.Annotations)
+	must.Eq(t, inplace, shutdownDelay.Annotations)
+	must.Eq(t, destructive, meta.Annotations)
+	must.Eq(t, inplace, constraint.Annotations)
+	must.Eq(t, inplace, reschedule.Annotations)
+	must.Eq(t, inplace, restart.Annotations)
+	must.Eq(t, destructive, renderTemplates.Annotations)
+	must.Eq(t, inplace, service.Annotations)
+	must.Eq(t, destructive, connect.Annotations)
+	must.Eq(t, destructive, network.Annotations)
+	must.Eq(t, destructive, disk.Annotations)
+	must.Eq(t, destructive, volume.Annotations)
+	must.Eq(t, destructive, affinity.Annotations)
+	must.Eq(t, destructive, spread.Annotations)
+
+	// Added task groups are only annotated with the count
+	added := &structs.ObjectDiff{
+		Type: structs.DiffTypeAdded,
+		Name: "Network",
+	}
+	diff = &structs.TaskGroupDiff{
+		Type:    structs.DiffTypeAdded,
+		Objects: []*structs.ObjectDiff{added},
+	}
+	must.NoError(t, annotateTaskGroup(diff, nil))
+	must.SliceEmpty(t, added.Annotations)
+}
+
+func TestAnnotateTask_ResourcesResized(t *testing.T) {
+	ci.Parallel(t)
+
+	resized := &structs.ObjectDiff{
+		Type: structs.DiffTypeEdited,
+		Name: "Resources",
+		Fields: []*structs.FieldDiff{
+			{Type: structs.DiffTypeEdited, Name: "CPU", Old: "100", New: "200"},
+			{Type: structs.DiffTypeNone, Name: "Cores", Old: "0", New: "0"},
+			{Type: structs.DiffTypeEdited, Name: "MemoryMB", Old: "100", New: "200"},
+			{Type: structs.DiffTypeAdded, Name: "MemoryMaxMB", New: "400"},
+		},
+	}
+	diff := &structs.TaskDiff{
+		Type:    structs.DiffTypeEdited,
+		Objects: []*structs.ObjectDiff{resized},
+	}
+	annotateTask(diff, &structs.TaskGroupDiff{Type: structs.DiffTypeEdited})
+	must.Eq(t, []string{AnnotationForcesInplaceUpdate}, diff.Annotations)
+	must.Eq(t, []string{AnnotationForcesInplaceUpdate}, resized.Annotations)
+
+	// Changing the cores or devices along with the cpu is destructive
+	cores := &structs.ObjectDiff{
+		Type: structs.DiffTypeEdited,
+		Name: "Resources",
+		Fields: []*structs.FieldDiff{
+			{Type: structs.DiffTypeEdited, Name: "CPU", Old: "100", New: "0"},
+			{Type: structs.DiffTypeEdited, Name: "Cores", Old: "0", New: "2"},
+		},
+	}
+	devices := &structs.ObjectDiff{
+		Type: structs.DiffTypeEdited,
+		Name: "Resources",
+		Fields: []*structs.FieldDiff{
+			{Type: structs.DiffTypeEdited, Name: "MemoryMB", Old: "100", New: "200"},
+		},
+		Objects: []*structs.ObjectDiff{{Type: structs.DiffTypeAdded, Name: "Device"}},
+	}
+	for _, resources := range []*structs.ObjectDiff{cores, devices} {
+		diff = &structs.TaskDiff{
+			Type:    structs.DiffTypeEdited,
+			Objects: []*structs.ObjectDiff{resources},
+		}
+		annotateTask(diff, &structs.TaskGroupDiff{Type: structs.DiffTypeEdited})
+		must.Eq(t, []string{AnnotationForcesDestructiveUpdate}, diff.Annotations)
+		must.Eq(t, []string{AnnotationForcesDestructiveUpdate}, resources.Annotations)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package benchmarks
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/nomad/client/lib/idset"
+	"github.com/hashicorp/nomad/client/lib/numalib"
+	"github.com/hashicorp/nomad/client/lib/numalib/hw"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// ClusterConfig describes the synthetic cluster that a benchmark is run
+// against. All nodes are identical apart from their datacenter and rack.
+type ClusterConfig struct {
+	// Nodes is the number of nodes in the cluster.
+	Nodes int
+
+	// Datacenters is the number of datacenters the nodes are spread across.
+	Datacenters int
+
+	// Racks is the number of distinct values of the node's "rack" meta
+	// attribute, which is what spread jobs are spread across.
+	Racks int
+
+	// NodeCores and NodeCoreMHz set the processor topology of each node.
+	NodeCores   int
+	NodeCoreMHz int
+
+	// NodeMemoryMB and NodeDiskMB set the memory and disk of each node.
+	NodeMemoryMB int
+	NodeDiskMB   int
+}
+
+// DefaultClusterConfig returns a cluster of 1000 medium sized nodes.
+func DefaultClusterConfig() *ClusterConfig {
+	return &ClusterConfig{
+		Nodes:        1000,
+		Datacenters:  2,
+		Racks:        25,
+		NodeCores:    8,
+		NodeCoreMHz:  3000,
+		NodeMemoryMB: 32 * 1024,
+		NodeDiskMB:   100 * 1024,
+	}
+}
+
+// Validate returns an error if the cluster configuration is invalid.
+func (c *ClusterConfig) Validate() error {
+	var mErr multierror.Error
+	if c.Nodes <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("number of nodes must be positive"))
+	}
+	if c.Datacenters <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("number of datacenters must be positive"))
+	}
+	if c.Racks <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("number of racks must be positive"))
+	}
+	if c.NodeCores <= 0 || c.NodeCoreMHz <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("node cores and core speed must be positive"))
+	}
+	if c.NodeMemoryMB <= 0 || c.NodeDiskMB <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("node memory and disk must be positive"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// DatacenterNames returns the names of the datacenters in the cluster.
+func (c *ClusterConfig) DatacenterNames() []string {
+	dcs := make([]string, c.Datacenters)
+	for i := range dcs {
+		dcs[i] = fmt.Sprintf("dc%d", i+1)
+	}
+	return dcs
+}
+
+// GenerateNodes returns the nodes of the synthetic cluster. Nodes are
+// distributed round-robin across the datacenters and racks.
+func GenerateNodes(c *ClusterConfig) []*structs.Node {
+	dcs := c.DatacenterNames()
+	nodes := make([]*structs.Node, c.Nodes)
+	for i := range nodes {
+		nodes[i] = generateNode(c, i, dcs[i%len(dcs)], fmt.Sprintf("r%d", i%c.Racks))
+	}
+	return nodes
+}
+
+func generateNode(c *ClusterConfig, i int, dc, rack string) *structs.Node {
+	cores := make([]numalib.Core, c.NodeCores)
+	for id := range cores {
+		cores[id] = numalib.Core{
+			ID:        hw.CoreID(id),
+			Grade:     numalib.Performance,
+			BaseSpeed: hw.MHz(c.NodeCoreMHz),
+		}
+	}
+
+	node := &structs.Node{
+		ID:         uuid.Generate(),
+		SecretID:   uuid.Generate(),
+		Datacenter: dc,
+		Name:       fmt.Sprintf("bench-%d", i),
+		NodePool:   structs.NodePoolDefault,
+		Drivers: map[string]*structs.DriverInfo{
+			"exec": {
+				Detected: true,
+				Healthy:  true,
+			},
+		},
+		Attributes: map[string]string{
+			"kernel.name": "linux",
+			"cpu.arch":    "amd64",
+			"driver.exec": "1",
+		},
+		NodeResources: &structs.NodeResources{
+			Processors: structs.NodeProcessorResources{
+				Topology: &numalib.Topology{
+					NodeIDs:   idset.From[hw.NodeID]([]hw.NodeID{0}),
+					Distances: numalib.SLIT{[]numalib.Cost{10}},
+					Cores:     cores,
+				},
+			},
+			Memory: structs.NodeMemoryResources{
+				MemoryMB: int64(c.NodeMemoryMB),
+			},
+			Disk: structs.NodeDiskResources{
+				DiskMB: int64(c.NodeDiskMB),
+			},
+			Networks: []*structs.NetworkResource{
+				{
+					Mode:   "host",
+					Device: "eth0",
+					CIDR:   "192.168.0.100/32",
+					MBits:  1000,
+				},
+			},
+		},
+		ReservedResources: &structs.NodeReservedResources{},
+		Meta: map[string]string{
+			"rack": rack,
+		},
+		Status:                structs.NodeStatusReady,
+		SchedulingEligibility: structs.NodeSchedulingEligible,
+	}
+
+	_ = node.ComputeClass()
+	node.NodeResources.Compatibility()
+
+	return node
+}
+
+// JobConfig describes the synthetic job that a benchmark places.
+type JobConfig struct {
+	// Type is the type of the job, which selects the scheduler used.
+	Type string
+
+	// Groups is the number of task groups in the job.
+	Groups int
+
+	// Count is the count of each task group. It is ignored by system jobs,
+	// which are placed on every node.
+	Count int
+
+	// CPU and MemoryMB are the resources of the single task in each group.
+	CPU      int
+	MemoryMB int
+
+	// Spread spreads the allocations of the job across the racks.
+	Spread bool
+}
+
+// DefaultJobConfig returns a service job with a single group of 500
+// allocations.
+func DefaultJobConfig() *JobConfig {
+	return &JobConfig{
+		Type:     structs.JobTypeService,
+		Groups:   1,
+		Count:    500,
+		CPU:      500,
+		MemoryMB: 256,
+	}
+}
+
+// Validate returns an error if the job configuration is invalid.
+func (c *JobConfig) Validate() error {
+	var mErr multierror.Error
+	switch c.Type {
+	case structs.JobTypeService, structs.JobTypeBatch, structs.JobTypeSystem, structs.JobTypeSysBatch:
+	default:
+		mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid job type %q", c.Type))
+	}
+	if c.Groups <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("number of groups must be positive"))
+	}
+	if c.Count <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("group count must be positive"))
+	}
+	if c.CPU <= 0 || c.MemoryMB <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("task cpu and memory must be positive"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// GenerateJob returns the synthetic job, placeable in the given datacenters.
+func GenerateJob(c *JobConfig, datacenters []string) *structs.Job {
+	job := &structs.Job{
+		Region:      "global",
+		ID:          "bench-" + uuid.Short(),
+		Namespace:   structs.DefaultNamespace,
+		Type:        c.Type,
+		Priority:    structs.JobDefaultPriority,
+		Datacenters: datacenters,
+		NodePool:    structs.NodePoolDefault,
+		Status:      structs.JobStatusPending,
+	}
+	job.Name = job.ID
+
+	if c.Spread {
+		job.Spreads = []*structs.Spread{{Attribute: "${meta.rack}", Weight: 50}}
+	}
+
+	count := c.Count
+	if job.Type == structs.JobTypeSystem || job.Type == structs.JobTypeSysBatch {
+		count = 1
+	}
+
+	for i := 0; i < c.Groups; i++ {
+		job.TaskGroups = append(job.TaskGroups, &structs.TaskGroup{
+			Name:  fmt.Sprintf("group-%d", i),
+			Count: count,
+			Tasks: []*structs.Task{
+				{
+					Name:   "task",
+					Driver: "exec",
+					Config: map[string]interface{}{
+						"command": "/bin/true",
+					},
+					Resources: &structs.Resources{
+						CPU:      c.CPU,
+						MemoryMB: c.MemoryMB,
+					},
+				},
+			},
+		})
+	}
+
+	job.Canonicalize()
+	return job
+}
@@ -0,0 +1,310 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package benchmarks
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/nomad/helper/uuid"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+)
+
+// Config is the configuration of a scheduler benchmark.
+type Config struct {
+	Cluster *ClusterConfig
+	Job     *JobConfig
+
+	// Algorithms are the scheduler algorithms to benchmark. Each algorithm
+	// is run against its own copy of the cluster.
+	Algorithms []structs.SchedulerAlgorithm
+
+	// Iterations is the number of times the job's evaluation is processed
+	// for each algorithm.
+	Iterations int
+
+	// Logger is passed to the schedulers. It defaults to a null logger.
+	Logger hclog.Logger
+}
+
+// DefaultConfig returns a benchmark of the default job and cluster with the
+// binpack algorithm.
+func DefaultConfig() *Config {
+	return &Config{
+		Cluster:    DefaultClusterConfig(),
+		Job:        DefaultJobConfig(),
+		Algorithms: []structs.SchedulerAlgorithm{structs.SchedulerAlgorithmBinpack},
+		Iterations: 10,
+	}
+}
+
+// Validate returns an error if the benchmark configuration is invalid.
+func (c *Config) Validate() error {
+	var mErr multierror.Error
+	if c.Cluster == nil {
+		mErr.Errors = append(mErr.Errors, errors.New("missing cluster configuration"))
+	} else if err := c.Cluster.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if c.Job == nil {
+		mErr.Errors = append(mErr.Errors, errors.New("missing job configuration"))
+	} else if err := c.Job.Validate(); err != nil {
+		mErr.Errors = append(mErr.Errors, err)
+	}
+	if len(c.Algorithms) == 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("at least one scheduler algorithm is required"))
+	}
+	for _, algorithm := range c.Algorithms {
+		switch algorithm {
+		case structs.SchedulerAlgorithmBinpack, structs.SchedulerAlgorithmSpread:
+		default:
+			mErr.Errors = append(mErr.Errors, fmt.Errorf("invalid scheduler algorithm %q", algorithm))
+		}
+	}
+	if c.Iterations <= 0 {
+		mErr.Errors = append(mErr.Errors, errors.New("number of iterations must be positive"))
+	}
+	return mErr.ErrorOrNil()
+}
+
+// Result is the outcome of benchmarking one scheduler algorithm.
+type Result struct {
+	// SchedulerVersion is the version of the scheduler that was benchmarked,
+	// so that results from different Nomad builds can be compared.
+	SchedulerVersion uint16
+
+	Algorithm  structs.SchedulerAlgorithm
+	JobType    string
+	Nodes      int
+	Iterations int
+
+	// Placements and FailedPlacements are the number of allocations placed
+	// and failed to be placed by each iteration.
+	Placements       int
+	FailedPlacements int
+
+	// Total is the time spent processing evaluations across all iterations.
+	Total time.Duration
+
+	// Latency statistics of processing a single evaluation.
+	Min  time.Duration
+	Mean time.Duration
+	P50  time.Duration
+	P90  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+
+	// PlacementsPerSecond is the placement throughput of the scheduler.
+	PlacementsPerSecond float64
+}
+
+// Run runs the benchmark described by the configuration, returning a result
+// for each scheduler algorithm.
+func Run(c *Config) ([]*Result, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	logger := c.Logger
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
+
+	results := make([]*Result, 0, len(c.Algorithms))
+	for _, algorithm := range c.Algorithms {
+		result, err := run(c, algorithm, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to benchmark %s algorithm: %w", algorithm, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func run(c *Config, algorithm structs.SchedulerAlgorithm, logger hclog.Logger) (*Result, error) {
+	store, err := state.NewStateStore(&state.StateStoreConfig{
+		Logger:             logger,
+		Region:             "global",
+		JobTrackedVersions: structs.JobDefaultTrackedVersions,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	eval, err := setupCluster(store, c, algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up synthetic cluster: %w", err)
+	}
+
+	factory, ok := scheduler.BuiltinSchedulers[c.Job.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown scheduler %q", c.Job.Type)
+	}
+
+	durations := make([]time.Duration, c.Iterations)
+	var p *planner
+	for i := 0; i < c.Iterations; i++ {
+		snap, err := store.Snapshot()
+		if err != nil {
+			return nil, err
+		}
+
+		p = &planner{state: store}
+		sched := factory(logger, nil, snap, p)
+
+		start := time.Now()
+		err = sched.Process(eval.Copy())
+		durations[i] = time.Since(start)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process evaluation: %w", err)
+		}
+	}
+
+	result := &Result{
+		SchedulerVersion: scheduler.SchedulerVersion,
+		Algorithm:        algorithm,
+		JobType:          c.Job.Type,
+		Nodes:            c.Cluster.Nodes,
+		Iterations:       c.Iterations,
+		Placements:       p.placements,
+		FailedPlacements: p.failed,
+	}
+	result.setLatencies(durations)
+	return result, nil
+}
+
+// setupCluster writes the synthetic nodes and job to the state store, and
+// returns the evaluation that places the job.
+func setupCluster(store *state.StateStore, c *Config, algorithm structs.SchedulerAlgorithm) (*structs.Evaluation, error) {
+	index := uint64(1)
+
+	schedConfig := &structs.SchedulerConfiguration{
+		SchedulerAlgorithm: algorithm,
+	}
+	if err := store.SchedulerSetConfig(index, schedConfig); err != nil {
+		return nil, err
+	}
+
+	for _, node := range GenerateNodes(c.Cluster) {
+		index++
+		if err := store.UpsertNode(structs.MsgTypeTestSetup, index, node); err != nil {
+			return nil, err
+		}
+	}
+
+	job := GenerateJob(c.Job, c.Cluster.DatacenterNames())
+	index++
+	if err := store.UpsertJob(structs.MsgTypeTestSetup, index, nil, job); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().UnixNano()
+	eval := &structs.Evaluation{
+		ID:          uuid.Generate(),
+		Namespace:   job.Namespace,
+		Priority:    job.Priority,
+		Type:        job.Type,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+		CreateTime:  now,
+		ModifyTime:  now,
+	}
+	index++
+	if err := store.UpsertEvals(structs.MsgTypeTestSetup, index, []*structs.Evaluation{eval}); err != nil {
+		return nil, err
+	}
+
+	return eval, nil
+}
+
+// setLatencies computes the latency statistics and throughput of the result
+// from the duration of each iteration.
+func (r *Result) setLatencies(durations []time.Duration) {
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	for _, d := range sorted {
+		r.Total += d
+	}
+
+	r.Min = sorted[0]
+	r.Max = sorted[len(sorted)-1]
+	r.Mean = r.Total / time.Duration(len(sorted))
+	r.P50 = percentile(sorted, 50)
+	r.P90 = percentile(sorted, 90)
+	r.P99 = percentile(sorted, 99)
+
+	if r.Total > 0 {
+		r.PlacementsPerSecond = float64(r.Placements*r.Iterations) / r.Total.Seconds()
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// planner implements the scheduler.Planner interface without applying plans
+// to the state store, so that every iteration of a benchmark processes the
+// same evaluation against the same cluster.
+type planner struct {
+	state *state.StateStore
+
+	placements int
+	failed     int
+}
+
+func (p *planner) SubmitPlan(plan *structs.Plan) (*structs.PlanResult, scheduler.State, error) {
+	index, err := p.state.LatestIndex()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, allocs := range plan.NodeAllocation {
+		p.placements += len(allocs)
+	}
+
+	// Accept the plan in full without refreshing the scheduler's state
+	result := &structs.PlanResult{
+		NodeUpdate:        plan.NodeUpdate,
+		NodeAllocation:    plan.NodeAllocation,
+		NodePreemptions:   plan.NodePreemptions,
+		Deployment:        plan.Deployment,
+		DeploymentUpdates: plan.DeploymentUpdates,
+		AllocIndex:        index + 1,
+	}
+	return result, nil, nil
+}
+
+func (p *planner) UpdateEval(eval *structs.Evaluation) error {
+	for _, metrics := range eval.FailedTGAllocs {
+		p.failed += metrics.CoalescedFailures + 1
+	}
+	return nil
+}
+
+func (p *planner) CreateEval(*structs.Evaluation) error {
+	return nil
+}
+
+func (p *planner) ReblockEval(*structs.Evaluation) error {
+	return nil
+}
+
+func (p *planner) ServersMeetMinimumVersion(*version.Version, bool) bool {
+	return true
+}
@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package benchmarks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/hashicorp/nomad/scheduler"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	ci.Parallel(t)
+
+	cfg := DefaultConfig()
+	cfg.Cluster.Nodes = 20
+	cfg.Cluster.Racks = 4
+	cfg.Job.Count = 30
+	cfg.Job.Spread = true
+	cfg.Algorithms = []structs.SchedulerAlgorithm{
+		structs.SchedulerAlgorithmBinpack,
+		structs.SchedulerAlgorithmSpread,
+	}
+	cfg.Iterations = 3
+
+	results, err := Run(cfg)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	for i, result := range results {
+		require.Equal(t, cfg.Algorithms[i], result.Algorithm)
+		require.Equal(t, scheduler.SchedulerVersion, result.SchedulerVersion)
+		require.Equal(t, 30, result.Placements)
+		require.Zero(t, result.FailedPlacements)
+		require.Positive(t, result.PlacementsPerSecond)
+		require.LessOrEqual(t, result.Min, result.P50)
+		require.LessOrEqual(t, result.P50, result.P99)
+		require.LessOrEqual(t, result.P99, result.Max)
+	}
+}
+
+func TestRun_System(t *testing.T) {
+	ci.Parallel(t)
+
+	cfg := DefaultConfig()
+	cfg.Cluster.Nodes = 15
+	cfg.Job.Type = structs.JobTypeSystem
+	cfg.Job.Groups = 2
+	cfg.Iterations = 1
+
+	results, err := Run(cfg)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, 30, results[0].Placements)
+}
+
+func TestRun_FailedPlacements(t *testing.T) {
+	ci.Parallel(t)
+
+	// Each node only fits a single allocation
+	cfg := DefaultConfig()
+	cfg.Cluster.Nodes = 5
+	cfg.Job.Count = 8
+	cfg.Job.MemoryMB = cfg.Cluster.NodeMemoryMB * 2 / 3
+	cfg.Iterations = 1
+
+	results, err := Run(cfg)
+	require.NoError(t, err)
+	require.Equal(t, 5, results[0].Placements)
+	require.Equal(t, 3, results[0].FailedPlacements)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	ci.Parallel(t)
+
+	cfg := DefaultConfig()
+	require.NoError(t, cfg.Validate())
+
+	cfg.Cluster.Nodes = 0
+	cfg.Job.Type = "bogus"
+	cfg.Algorithms = []structs.SchedulerAlgorithm{"bogus"}
+	cfg.Iterations = 0
+
+	err := cfg.Validate()
+	require.ErrorContains(t, err, "number of nodes must be positive")
+	require.ErrorContains(t, err, `invalid job type "bogus"`)
+	require.ErrorContains(t, err, `invalid scheduler algorithm "bogus"`)
+	require.ErrorContains(t, err, "number of iterations must be positive")
+}
+
+func TestResult_setLatencies(t *testing.T) {
+	ci.Parallel(t)
+
+	durations := make([]time.Duration, 100)
+	for i := range durations {
+		durations[len(durations)-1-i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	result := &Result{Iterations: 100, Placements: 10}
+	result.setLatencies(durations)
+
+	require.Equal(t, time.Millisecond, result.Min)
+	require.Equal(t, 100*time.Millisecond, result.Max)
+	require.Equal(t, 50*time.Millisecond, result.P50)
+	require.Equal(t, 90*time.Millisecond, result.P90)
+	require.Equal(t, 99*time.Millisecond, result.P99)
+	require.Equal(t, 5050*time.Millisecond, result.Total)
+	require.InDelta(t, 1000/5.05, result.PlacementsPerSecond, 0.01)
+}
@@ -142,6 +142,9 @@ type HostVolumeChecker struct {
 	// volumes is a map[HostVolumeName][]RequestedVolume. The requested volumes are
 	// a slice because a single task group may request the same volume multiple times.
 	volumes map[string][]*structs.VolumeRequest
+
+	// create is whether any of the volumes may be created by the client
+	create bool
 }
 
 // NewHostVolumeChecker creates a HostVolumeChecker from a set of volumes
@@ -154,12 +157,14 @@ func NewHostVolumeChecker(ctx Context) *HostVolumeChecker {
 // SetVolumes takes the volumes required by a task group and updates the checker.
 func (h *HostVolumeChecker) SetVolumes(allocName string, volumes map[string]*structs.VolumeRequest) {
 	lookupMap := make(map[string][]*structs.VolumeRequest)
+	create := false
 	// Convert the map from map[DesiredName]Request to map[Source][]Request to improve
 	// lookup performance. Also filter non-host volumes.
 	for _, req := range volumes {
 		if req.Type != structs.VolumeTypeHost {
 			continue
 		}
+		create = create || req.Create
 
 		if req.PerAlloc {
 			// provide a unique volume source per allocation
@@ -171,6 +176,7 @@ func (h *HostVolumeChecker) SetVolumes(allocName string, volumes map[string]*str
 		}
 	}
 	h.volumes = lookupMap
+	h.create = create
 }
 
 func (h *HostVolumeChecker) Feasible(candidate *structs.Node) bool {
@@ -191,15 +197,19 @@ func (h *HostVolumeChecker) hasVolumes(n *structs.Node) bool {
 		return true
 	}
 
-	// Fast path: Requesting more volumes than the node has, can't meet the criteria.
-	if rLen > hLen {
+	// Fast path: Requesting more volumes than the node has and none can be
+	// created, can't meet the criteria.
+	if rLen > hLen && !h.create {
 		return false
 	}
 
 	for source, requests := range h.volumes {
 		nodeVolume, ok := n.HostVolumes[source]
 		if !ok {
-			return false
+			if !canCreateHostVolume(n, requests) {
+				return false
+			}
+			continue
 		}
 
 		// If the volume supports being mounted as ReadWrite, we do not need to
@@ -220,6 +230,45 @@ func (h *HostVolumeChecker) hasVolumes(n *structs.Node) bool {
 	return true
 }
 
+// canCreateHostVolume returns whether the node can create a host volume for
+// the requests, which is the case if any of them asks for the volume to be
+// created and the node creates host volumes of the requested size.
+func canCreateHostVolume(n *structs.Node, requests []*structs.VolumeRequest) bool {
+	create := false
+	sizeMB := 0
+	for _, req := range requests {
+		create = create || req.Create
+		sizeMB = max(sizeMB, req.SizeMB)
+	}
+	if !create || n.Attributes[structs.NodeAttrDynamicHostVolumes] != "true" {
+		return false
+	}
+
+	if maxSize, ok := n.Attributes[structs.NodeAttrDynamicHostVolumesMaxSize]; ok {
+		maxSizeMB, err := strconv.Atoi(maxSize)
+		if err != nil || sizeMB > maxSizeMB {
+			return false
+		}
+	}
+
+	// The plugin creating the volume must be available, and have the
+	// capacity for the volume if it reports its capacity
+	plugin := n.Attributes[structs.NodeAttrDynamicHostVolumesPlugin]
+	if plugin == "" {
+		return true
+	}
+	if _, ok := n.Attributes[structs.HostVolumePluginVersionAttr(plugin)]; !ok {
+		return false
+	}
+	if capacity, ok := n.Attributes[structs.HostVolumePluginCapacityAttr(plugin)]; ok && sizeMB > 0 {
+		capacityBytes, err := strconv.ParseInt(capacity, 10, 64)
+		if err != nil || int64(sizeMB)*structs.BytesInMegabyte > capacityBytes {
+			return false
+		}
+	}
+	return true
+}
+
 type CSIVolumeChecker struct {
 	ctx       Context
 	namespace string
@@ -242,7 +291,12 @@ func (c *CSIVolumeChecker) SetNamespace(namespace string) {
 }
 
 func (c *CSIVolumeChecker) SetVolumes(allocName string, volumes map[string]*structs.VolumeRequest) {
+	c.volumes = csiVolumeRequests(allocName, volumes)
+}
 
+// csiVolumeRequests filters the volume requests to only CSI volumes, giving
+// per_alloc volumes the unique source for the allocation being placed.
+func csiVolumeRequests(allocName string, volumes map[string]*structs.VolumeRequest) map[string]*structs.VolumeRequest {
 	xs := make(map[string]*structs.VolumeRequest)
 
 	// Filter to only CSI Volumes
@@ -259,7 +313,7 @@ func (c *CSIVolumeChecker) SetVolumes(allocName string, volumes map[string]*stru
 			xs[alias] = req
 		}
 	}
-	c.volumes = xs
+	return xs
 }
 
 func (c *CSIVolumeChecker) Feasible(n *structs.Node) bool {
@@ -374,6 +428,7 @@ type NetworkChecker struct {
 	ctx         Context
 	networkMode string
 	ports       []structs.Port
+	interfaces  []string
 }
 
 func NewNetworkChecker(ctx Context) *NetworkChecker {
@@ -389,6 +444,7 @@ func (c *NetworkChecker) SetNetwork(network *structs.NetworkResource) {
 	c.ports = make([]structs.Port, len(network.DynamicPorts)+len(network.ReservedPorts))
 	c.ports = append(c.ports, network.DynamicPorts...)
 	c.ports = append(c.ports, network.ReservedPorts...)
+	c.interfaces = network.Interfaces
 }
 
 func (c *NetworkChecker) Feasible(option *structs.Node) bool {
@@ -420,6 +476,13 @@ func (c *NetworkChecker) Feasible(option *structs.Node) bool {
 		}
 	}
 
+	if len(c.interfaces) > 0 {
+		if iface, _ := selectNetworkInterface(c.interfaces, option); iface == "" {
+			c.ctx.Metrics().FilterNode(option, "missing network interface")
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -828,6 +891,39 @@ func resolveTarget(target string, node *structs.Node) (string, bool) {
 	}
 }
 
+// selectNetworkInterface returns the first of the candidate network interfaces
+// of a group network that exists on the node and has addresses, along with its
+// addresses as fingerprinted in the node attributes. Candidates may interpolate
+// node attributes and metadata, and candidates that cannot be resolved on the
+// node are skipped.
+func selectNetworkInterface(candidates []string, node *structs.Node) (string, []structs.NodeNetworkAddress) {
+	for _, candidate := range candidates {
+		iface, ok := resolveTarget(candidate, node)
+		if !ok || iface == "" {
+			continue
+		}
+
+		var addrs []structs.NodeNetworkAddress
+		for _, family := range []structs.NodeNetworkAF{structs.NodeNetworkAF_IPv4, structs.NodeNetworkAF_IPv6} {
+			attr := structs.NodeNetworkInterfaceAttribute(iface, string(family))
+			for _, addr := range strings.Split(node.Attributes[attr], ",") {
+				if addr == "" {
+					continue
+				}
+				addrs = append(addrs, structs.NodeNetworkAddress{
+					Family:  family,
+					Alias:   "default",
+					Address: addr,
+				})
+			}
+		}
+		if len(addrs) > 0 {
+			return iface, addrs
+		}
+	}
+	return "", nil
+}
+
 // checkConstraint checks if a constraint is satisfied. The lVal and rVal
 // interfaces may be nil.
 func checkConstraint(ctx Context, operand string, lVal, rVal interface{}, lFound, rFound bool) bool {
@@ -258,6 +258,133 @@ func TestHostVolumeChecker_ReadOnly(t *testing.T) {
 	}
 }
 
+func TestHostVolumeChecker_Create(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+	nodes := []*structs.Node{
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+		mock.Node(),
+	}
+
+	// Only nodes[1] and nodes[2] create host volumes, nodes[2] up to 100 MB
+	nodes[1].Attributes[structs.NodeAttrDynamicHostVolumes] = "true"
+	nodes[2].Attributes[structs.NodeAttrDynamicHostVolumes] = "true"
+	nodes[2].Attributes[structs.NodeAttrDynamicHostVolumesMaxSize] = "100"
+	nodes[3].HostVolumes = map[string]*structs.ClientHostVolumeConfig{
+		"foo": {Name: "foo"},
+	}
+
+	// nodes[4] creates host volumes with a plugin that has 300 MB left, and
+	// nodes[5] with a plugin that failed to fingerprint
+	nodes[4].Attributes[structs.NodeAttrDynamicHostVolumes] = "true"
+	nodes[4].Attributes[structs.NodeAttrDynamicHostVolumesPlugin] = "lvm"
+	nodes[4].Attributes[structs.HostVolumePluginVersionAttr("lvm")] = "0.1.0"
+	nodes[4].Attributes[structs.HostVolumePluginCapacityAttr("lvm")] = "314572800"
+	nodes[5].Attributes[structs.NodeAttrDynamicHostVolumes] = "true"
+	nodes[5].Attributes[structs.NodeAttrDynamicHostVolumesPlugin] = "lvm"
+
+	createRequest := map[string]*structs.VolumeRequest{
+		"foo": {
+			Type:   "host",
+			Source: "foo",
+			Create: true,
+		},
+	}
+
+	largeCreateRequest := map[string]*structs.VolumeRequest{
+		"foo": {
+			Type:   "host",
+			Source: "foo",
+			Create: true,
+			SizeMB: 200,
+		},
+	}
+
+	hugeCreateRequest := map[string]*structs.VolumeRequest{
+		"foo": {
+			Type:   "host",
+			Source: "foo",
+			Create: true,
+			SizeMB: 400,
+		},
+	}
+
+	existingRequest := map[string]*structs.VolumeRequest{
+		"foo": {
+			Type:   "host",
+			Source: "foo",
+		},
+	}
+
+	checker := NewHostVolumeChecker(ctx)
+	cases := []struct {
+		Node             *structs.Node
+		RequestedVolumes map[string]*structs.VolumeRequest
+		Result           bool
+	}{
+		{ // Create Request, Host without dynamic host volumes
+			Node:             nodes[0],
+			RequestedVolumes: createRequest,
+			Result:           false,
+		},
+		{ // Create Request, Host with dynamic host volumes
+			Node:             nodes[1],
+			RequestedVolumes: createRequest,
+			Result:           true,
+		},
+		{ // Existing Request, Host with dynamic host volumes
+			Node:             nodes[1],
+			RequestedVolumes: existingRequest,
+			Result:           false,
+		},
+		{ // Create Request within the size limit
+			Node:             nodes[2],
+			RequestedVolumes: createRequest,
+			Result:           true,
+		},
+		{ // Create Request exceeding the size limit
+			Node:             nodes[2],
+			RequestedVolumes: largeCreateRequest,
+			Result:           false,
+		},
+		{ // Create Request, Host with the volume
+			Node:             nodes[3],
+			RequestedVolumes: largeCreateRequest,
+			Result:           true,
+		},
+		{ // Create Request within the plugin's capacity
+			Node:             nodes[4],
+			RequestedVolumes: largeCreateRequest,
+			Result:           true,
+		},
+		{ // Create Request exceeding the plugin's capacity
+			Node:             nodes[4],
+			RequestedVolumes: hugeCreateRequest,
+			Result:           false,
+		},
+		{ // Create Request, plugin not fingerprinted
+			Node:             nodes[5],
+			RequestedVolumes: createRequest,
+			Result:           false,
+		},
+	}
+
+	alloc := mock.Alloc()
+	alloc.NodeID = nodes[1].ID
+
+	for i, c := range cases {
+		checker.SetVolumes(alloc.Name, c.RequestedVolumes)
+		if act := checker.Feasible(c.Node); act != c.Result {
+			t.Fatalf("case(%d) failed: got %v; want %v", i, act, c.Result)
+		}
+	}
+}
+
 func TestCSIVolumeChecker(t *testing.T) {
 	ci.Parallel(t)
 	state, ctx := testContext(t)
@@ -665,6 +792,55 @@ func TestNetworkChecker(t *testing.T) {
 	}
 }
 
+func TestNetworkChecker_interfaces(t *testing.T) {
+	ci.Parallel(t)
+
+	_, ctx := testContext(t)
+
+	node := func(iface string) *structs.Node {
+		n := mock.Node()
+		n.Attributes["network.interface."+iface+".ipv4"] = "10.0.1.5"
+		n.Meta["data_nic"] = iface
+		return n
+	}
+
+	nodes := []*structs.Node{
+		node("eth1"),
+		node("ens5"),
+		node("eth2"),
+	}
+
+	checker := NewNetworkChecker(ctx)
+	cases := []struct {
+		interfaces []string
+		results    []bool
+	}{
+		{
+			interfaces: []string{"eth1"},
+			results:    []bool{true, false, false},
+		},
+		{
+			interfaces: []string{"eth1", "ens5"},
+			results:    []bool{true, true, false},
+		},
+		{
+			interfaces: []string{"${meta.data_nic}"},
+			results:    []bool{true, true, true},
+		},
+		{
+			interfaces: []string{"${meta.missing}", "eth2"},
+			results:    []bool{false, false, true},
+		},
+	}
+
+	for _, c := range cases {
+		checker.SetNetwork(&structs.NetworkResource{Mode: "host", Interfaces: c.interfaces})
+		for i, node := range nodes {
+			must.Eq(t, c.results[i], checker.Feasible(node), must.Sprintf("interfaces=%v, idx=%d", c.interfaces, i))
+		}
+	}
+}
+
 func TestNetworkChecker_bridge_upgrade_path(t *testing.T) {
 	ci.Parallel(t)
 
@@ -170,7 +170,8 @@ func (s *GenericScheduler) Process(eval *structs.Evaluation) (err error) {
 		structs.EvalTriggerPeriodicJob, structs.EvalTriggerMaxPlans,
 		structs.EvalTriggerDeploymentWatcher, structs.EvalTriggerRetryFailedAlloc,
 		structs.EvalTriggerFailedFollowUp, structs.EvalTriggerPreemption,
-		structs.EvalTriggerScaling, structs.EvalTriggerMaxDisconnectTimeout, structs.EvalTriggerReconnect:
+		structs.EvalTriggerScaling, structs.EvalTriggerMaxDisconnectTimeout, structs.EvalTriggerReconnect,
+		structs.EvalTriggerArrayProgress:
 	default:
 		desc := fmt.Sprintf("scheduler cannot handle '%s' evaluation reason",
 			eval.TriggeredBy)
@@ -393,6 +394,13 @@ func (s *GenericScheduler) computeJobAllocs() error {
 		s.plan.Annotations = &structs.PlanAnnotations{
 			DesiredTGUpdates: results.desiredTGUpdates,
 		}
+		if s.job != nil && s.job.Prefetch && !s.job.Stopped() {
+			prefetch, err := PrefetchNodes(s.state, s.job)
+			if err != nil {
+				return err
+			}
+			s.plan.Annotations.PrefetchNodes = prefetch
+		}
 	}
 
 	// Add the deployment changes to the plan
@@ -599,6 +607,9 @@ func (s *GenericScheduler) computePlacements(destructive, place []placementResul
 			// Compute penalty nodes for rescheduled allocs
 			selectOptions := getSelectOptions(prevAllocation, preferredNode)
 			selectOptions.AllocName = missing.Name()
+			if err := s.setCanaryPlacementOptions(missing, selectOptions); err != nil {
+				return err
+			}
 			option := s.selectNextOption(tg, selectOptions)
 
 			// Store the available nodes by datacenter
@@ -755,6 +766,16 @@ func (s *GenericScheduler) setJob(job *structs.Job) error {
 // setnodes updates the stack with the nodes that are ready for placement for
 // the given job.
 func (s *GenericScheduler) setNodes(job *structs.Job) ([]*structs.Node, map[string]int, error) {
+	// Jobs can't be placed in node pools their namespace doesn't allow
+	allowed, err := namespaceAllowsNodePool(s.state, job)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !allowed {
+		s.stack.SetNodes(nil)
+		return nil, map[string]int{}, nil
+	}
+
 	nodes, _, byDC, err := readyNodesInDCsAndPool(s.state, job.Datacenters, job.NodePool)
 	if err != nil {
 		return nil, nil, err
@@ -835,6 +856,43 @@ func getSelectOptions(prevAllocation *structs.Allocation, preferredNode *structs
 	return selectOptions
 }
 
+// setCanaryPlacementOptions applies the canary placement preference of the
+// update strategy to the select options of a canary. Canaries placed on
+// distinct nodes penalize the nodes running the previous version, while
+// canaries placed on the same nodes prefer them.
+func (s *GenericScheduler) setCanaryPlacementOptions(place placementResult, selectOptions *SelectOptions) error {
+	if !place.Canary() {
+		return nil
+	}
+
+	placement, previousNodes := place.CanaryPlacement()
+	if len(previousNodes) == 0 {
+		return nil
+	}
+
+	switch placement {
+	case structs.CanaryPlacementDistinct:
+		if selectOptions.PenaltyNodeIDs == nil {
+			selectOptions.PenaltyNodeIDs = make(map[string]struct{}, len(previousNodes))
+		}
+		for nodeID := range previousNodes {
+			selectOptions.PenaltyNodeIDs[nodeID] = struct{}{}
+		}
+	case structs.CanaryPlacementSame:
+		ws := memdb.NewWatchSet()
+		for nodeID := range previousNodes {
+			node, err := s.state.NodeByID(ws, nodeID)
+			if err != nil {
+				return err
+			}
+			if node != nil && node.Ready() {
+				selectOptions.PreferredNodes = append(selectOptions.PreferredNodes, node)
+			}
+		}
+	}
+	return nil
+}
+
 // updateRescheduleTracker carries over previous restart attempts and adds the most recent restart
 func updateRescheduleTracker(alloc *structs.Allocation, prev *structs.Allocation, now time.Time) {
 	reschedPolicy := prev.ReschedulePolicy()
@@ -1271,6 +1271,56 @@ func TestServiceSched_JobRegister_AllocFail(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestServiceSched_JobRegister_NamespaceNodePool(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create nodes in a node pool other than default
+	pool := mock.NodePool()
+	must.NoError(t, h.State.UpsertNodePools(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.NodePool{pool}))
+	for i := 0; i < 3; i++ {
+		node := mock.Node()
+		node.NodePool = pool.Name
+		must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+	}
+
+	// Create a job in the node pool, and then bind its namespace to the
+	// default node pool only
+	ns := mock.Namespace()
+	must.NoError(t, h.State.UpsertNamespaces(h.NextIndex(), []*structs.Namespace{ns}))
+
+	job := mock.Job()
+	job.Namespace = ns.Name
+	job.NodePool = pool.Name
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	ns = ns.Copy()
+	ns.NodePoolConfiguration = &structs.NamespaceNodePoolConfiguration{Allowed: []string{}}
+	must.NoError(t, h.State.UpsertNamespaces(h.NextIndex(), []*structs.Namespace{ns}))
+
+	// Create a mock evaluation to register the job
+	eval := &structs.Evaluation{
+		Namespace:   ns.Name,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	must.NoError(t, h.Process(NewServiceScheduler, eval))
+
+	// Ensure nothing was placed in the node pool
+	must.SliceEmpty(t, h.Plans)
+	must.Len(t, 1, h.Evals)
+	must.MapContainsKey(t, h.Evals[0].FailedTGAllocs, job.TaskGroups[0].Name)
+	must.Eq(t, 10, h.Evals[0].QueuedAllocations["web"])
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
 func TestServiceSched_JobRegister_CreateBlockedEval(t *testing.T) {
 	ci.Parallel(t)
 
@@ -2903,6 +2953,90 @@ func TestServiceSched_JobModify_Canaries(t *testing.T) {
 	}
 }
 
+func TestServiceSched_JobModify_CanaryPlacement(t *testing.T) {
+	ci.Parallel(t)
+
+	testCases := []struct {
+		placement      string
+		expectPrevNode bool
+	}{
+		{placement: structs.CanaryPlacementDistinct, expectPrevNode: false},
+		{placement: structs.CanaryPlacementSame, expectPrevNode: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.placement, func(t *testing.T) {
+			h := NewHarness(t)
+
+			// Create some nodes
+			var nodes []*structs.Node
+			for i := 0; i < 20; i++ {
+				node := mock.Node()
+				nodes = append(nodes, node)
+				must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), node))
+			}
+
+			// Generate a fake job with allocations on some of the nodes
+			job := mock.Job()
+			job.TaskGroups[0].Count = 5
+			must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+			prevNodes := make(map[string]struct{})
+			var allocs []*structs.Allocation
+			for i := 0; i < 5; i++ {
+				alloc := mock.Alloc()
+				alloc.Job = job
+				alloc.JobID = job.ID
+				alloc.NodeID = nodes[i].ID
+				alloc.Name = fmt.Sprintf("my-job.web[%d]", i)
+				allocs = append(allocs, alloc)
+				prevNodes[alloc.NodeID] = struct{}{}
+			}
+			must.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+			// Update the job with a canary placement preference
+			job2 := job.Copy()
+			job2.TaskGroups[0].Update = &structs.UpdateStrategy{
+				MaxParallel:     2,
+				Canary:          2,
+				CanaryPlacement: tc.placement,
+				HealthCheck:     structs.UpdateStrategyHealthCheck_Checks,
+				MinHealthyTime:  10 * time.Second,
+				HealthyDeadline: 10 * time.Minute,
+			}
+
+			// Update the task, such that it cannot be done in-place
+			job2.TaskGroups[0].Tasks[0].Config["command"] = "/bin/other"
+			must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job2))
+
+			eval := &structs.Evaluation{
+				Namespace:   structs.DefaultNamespace,
+				ID:          uuid.Generate(),
+				Priority:    50,
+				TriggeredBy: structs.EvalTriggerJobRegister,
+				JobID:       job.ID,
+				Status:      structs.EvalStatusPending,
+			}
+			must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+			must.NoError(t, h.Process(NewServiceScheduler, eval))
+			must.Len(t, 1, h.Plans)
+
+			var planned []*structs.Allocation
+			for _, allocList := range h.Plans[0].NodeAllocation {
+				planned = append(planned, allocList...)
+			}
+			must.Len(t, 2, planned)
+
+			for _, canary := range planned {
+				must.True(t, canary.DeploymentStatus.IsCanary())
+				_, onPrevNode := prevNodes[canary.NodeID]
+				must.Eq(t, tc.expectPrevNode, onPrevNode, must.Sprintf("canary placed on node %s", canary.NodeID))
+			}
+		})
+	}
+}
+
 func TestServiceSched_JobModify_InPlace(t *testing.T) {
 	ci.Parallel(t)
 
@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package scheduler
+
+import (
+	"github.com/hashicorp/nomad/nomad/structs"
+)
+
+// PrefetchNodes returns the IDs of the nodes that pull the images and
+// artifacts of a job with prefetch enabled when it is registered. These are the
+// ready nodes of the job's datacenters and node pool with the drivers of all
+// the tasks of at least one of the job's task groups.
+func PrefetchNodes(state State, job *structs.Job) ([]string, error) {
+	nodes, _, _, err := readyNodesInDCsAndPool(state, job.Datacenters, job.NodePool)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, node := range nodes {
+		for _, tg := range job.TaskGroups {
+			if prefetchDriversHealthy(node, tg) {
+				ids = append(ids, node.ID)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
+// prefetchDriversHealthy returns true if the drivers of all the tasks of the
+// group are detected and healthy on the node.
+func prefetchDriversHealthy(node *structs.Node, tg *structs.TaskGroup) bool {
+	for _, task := range tg.Tasks {
+		info, ok := node.Drivers[task.Driver]
+		if !ok || !info.Detected || !info.Healthy {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: BUSL-1.1
+
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/hashicorp/nomad/ci"
+	"github.com/hashicorp/nomad/nomad/mock"
+	"github.com/hashicorp/nomad/nomad/state"
+	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
+)
+
+func TestPrefetchNodes(t *testing.T) {
+	ci.Parallel(t)
+
+	store := state.TestStateStore(t)
+
+	ready := mock.Node()
+	unhealthy := mock.Node()
+	unhealthy.Drivers["exec"].Healthy = false
+	otherDC := mock.Node()
+	otherDC.Datacenter = "dc2"
+	ineligible := mock.Node()
+	ineligible.SchedulingEligibility = structs.NodeSchedulingIneligible
+
+	for i, node := range []*structs.Node{ready, unhealthy, otherDC, ineligible} {
+		must.NoError(t, store.UpsertNode(structs.MsgTypeTestSetup, uint64(1000+i), node))
+	}
+
+	job := mock.Job()
+	job.Prefetch = true
+
+	nodeIDs, err := PrefetchNodes(store, job)
+	must.NoError(t, err)
+	must.Eq(t, []string{ready.ID}, nodeIDs)
+
+	// A node is eligible if it has the drivers of any task group
+	tg := job.TaskGroups[0].Copy()
+	tg.Name = "mock"
+	tg.Tasks[0].Driver = "mock_driver"
+	job.TaskGroups = append(job.TaskGroups, tg)
+
+	nodeIDs, err = PrefetchNodes(store, job)
+	must.NoError(t, err)
+	must.SliceContainsAll(t, []string{ready.ID, unhealthy.ID}, nodeIDs)
+}
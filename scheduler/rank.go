@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/hashicorp/go-memdb"
 	"github.com/hashicorp/nomad/client/lib/idset"
 	"github.com/hashicorp/nomad/client/lib/numalib/hw"
 	"github.com/hashicorp/nomad/nomad/structs"
@@ -306,6 +307,14 @@ OUTER:
 					}
 				}
 			}
+
+			// Assign the ports without a host network on the selected
+			// network interface
+			iface, ifaceAddrs := selectNetworkInterface(ask.Interfaces, option.Node)
+			if iface != "" {
+				netIdx.SetInterfaceAddresses(ifaceAddrs)
+			}
+
 			offer, err := netIdx.AssignPorts(ask)
 			if err != nil {
 				// If eviction is not enabled, mark this node as exhausted and continue
@@ -336,6 +345,9 @@ OUTER:
 				netIdx.Release()
 				netIdx = structs.NewNetworkIndex()
 				netIdx.SetNode(option.Node)
+				if iface != "" {
+					netIdx.SetInterfaceAddresses(ifaceAddrs)
+				}
 				netIdx.AddAllocs(proposed)
 
 				offer, err = netIdx.AssignPorts(ask)
@@ -353,6 +365,10 @@ OUTER:
 
 			// Update the network ask to the offer
 			nwRes := structs.AllocatedPortsToNetworkResouce(ask, offer, option.Node.NodeResources)
+			if iface != "" {
+				nwRes.Device = iface
+				nwRes.IP = ifaceAddrs[0].Address
+			}
 			total.Shared.Networks = []*structs.NetworkResource{nwRes}
 			total.Shared.Ports = offer
 			option.AllocResources = &structs.AllocatedSharedResources{
@@ -479,8 +495,14 @@ OUTER:
 
 			// Handle CPU core reservations
 			if wantedCores := task.Resources.Cores; wantedCores > 0 {
-				// set of cores on this node allowable for use by nomad
-				nodeCores := option.Node.NodeResources.Processors.Topology.UsableCores()
+				// set of cores on this node allowable for use by the task; the
+				// exclusive cores are only reserved by tasks asking for them
+				nodeCores := option.Node.NodeResources.Processors.Topology.SharedCores()
+				exhausted := "cores"
+				if task.Resources.ExclusiveCores {
+					nodeCores = option.Node.NodeResources.Processors.Topology.ExclusiveCores()
+					exhausted = "exclusive-cores"
+				}
 
 				// set of consumed cores on this node
 				consumedCores := idset.Empty[hw.CoreID]()
@@ -500,7 +522,7 @@ OUTER:
 
 				// mark the node as exhausted if not enough cores available
 				if availableCores.Size() < wantedCores {
-					iter.ctx.Metrics().ExhaustedNode(option.Node, "cores")
+					iter.ctx.Metrics().ExhaustedNode(option.Node, exhausted)
 					continue OUTER
 				}
 
@@ -703,6 +725,158 @@ func (iter *NodeReschedulingPenaltyIterator) Reset() {
 	iter.source.Reset()
 }
 
+// CSIVolumeScoringIterator is used to score nodes based on the CSI volumes
+// requested by the task group. Nodes are penalized for the attach slots of the
+// node plugin already in use, while nodes in one of the volume's preferred
+// topologies and, when the volume request sets an affinity, nodes where the
+// volume is already staged are scored higher. The feasibility of each volume
+// has already been checked by the CSIVolumeChecker.
+type CSIVolumeScoringIterator struct {
+	ctx       Context
+	source    RankIterator
+	namespace string
+	volumes   map[string]*structs.VolumeRequest
+}
+
+// NewCSIVolumeScoringIterator is used to create a CSIVolumeScoringIterator
+// that scores nodes according to the CSI volumes of the task group.
+func NewCSIVolumeScoringIterator(ctx Context, source RankIterator) *CSIVolumeScoringIterator {
+	return &CSIVolumeScoringIterator{
+		ctx:    ctx,
+		source: source,
+	}
+}
+
+func (iter *CSIVolumeScoringIterator) SetJob(job *structs.Job) {
+	iter.namespace = job.Namespace
+}
+
+func (iter *CSIVolumeScoringIterator) SetVolumes(allocName string, volumes map[string]*structs.VolumeRequest) {
+	iter.volumes = csiVolumeRequests(allocName, volumes)
+}
+
+func (iter *CSIVolumeScoringIterator) Reset() {
+	iter.source.Reset()
+}
+
+func (iter *CSIVolumeScoringIterator) Next() *RankedNode {
+	option := iter.source.Next()
+	if option == nil {
+		return nil
+	}
+	if len(iter.volumes) == 0 {
+		return option
+	}
+
+	// Only append non-zero scores so nodes without any CSI volume preference
+	// aren't scored towards the average, the same way as node affinities
+	score := iter.scoreNode(option.Node)
+	if score != 0.0 {
+		option.Scores = append(option.Scores, score)
+		iter.ctx.Metrics().ScoreNode(option.Node, "csi-volume", score)
+	}
+	return option
+}
+
+// scoreNode returns the average of the attach limit, topology and affinity
+// scores of every requested volume on the node. The attach limit score is
+// between -1 and 0, while the others are between 0 and 1.
+func (iter *CSIVolumeScoringIterator) scoreNode(n *structs.Node) float64 {
+	ws := memdb.NewWatchSet()
+
+	// Find the count per plugin for this node, and which volumes are
+	// already staged or attached, so we know how many attach slots remain
+	pluginCount := map[string]int64{}
+	staged := map[string]struct{}{}
+	volIter, err := iter.ctx.State().CSIVolumesByNodeID(ws, "", n.ID)
+	if err != nil {
+		return 0
+	}
+	for {
+		raw := volIter.Next()
+		if raw == nil {
+			break
+		}
+		vol, ok := raw.(*structs.CSIVolume)
+		if !ok {
+			continue
+		}
+		pluginCount[vol.PluginID] += 1
+		if vol.Namespace == iter.namespace {
+			staged[vol.ID] = struct{}{}
+		}
+	}
+
+	total := 0.0
+	count := 0
+	for _, req := range iter.volumes {
+		vol, err := iter.ctx.State().CSIVolumeByID(ws, iter.namespace, req.Source)
+		if err != nil || vol == nil {
+			continue
+		}
+		plugin, ok := n.CSINodePlugins[vol.PluginID]
+		if !ok || plugin.NodeInfo == nil {
+			continue
+		}
+
+		_, isStaged := staged[vol.ID]
+		if !isStaged {
+			isStaged = csiVolumeStagedOnNode(vol, n.ID)
+		}
+
+		// Penalize nodes by the attach slots of the plugin used by other
+		// volumes, as a volume that's already attached doesn't take
+		// another slot
+		if maxVolumes := plugin.NodeInfo.MaxVolumes; maxVolumes > 0 {
+			used := pluginCount[vol.PluginID]
+			if isStaged && used > 0 {
+				used--
+			}
+			total -= math.Min(float64(used)/float64(maxVolumes), 1.0)
+			count++
+		}
+
+		// Prefer nodes in one of the topologies the volume was created
+		// with a preference for
+		if vol.RequestedTopologies != nil && len(vol.RequestedTopologies.Preferred) > 0 {
+			if plugin.NodeInfo.AccessibleTopology.MatchFound(vol.RequestedTopologies.Preferred) {
+				total += 1.0
+			}
+			count++
+		}
+
+		// Prefer nodes where the volume is already staged, weighted by
+		// the volume affinity
+		if req.Affinity > 0 {
+			if isStaged {
+				total += float64(req.Affinity) / 100.0
+			}
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
+
+// csiVolumeStagedOnNode returns true if any claim on the volume, including
+// past claims that are still being released, holds the volume on the node
+// without having been detached by the controller.
+func csiVolumeStagedOnNode(vol *structs.CSIVolume, nodeID string) bool {
+	for _, claims := range []map[string]*structs.CSIVolumeClaim{
+		vol.ReadClaims, vol.WriteClaims, vol.PastClaims} {
+		for _, claim := range claims {
+			if claim != nil && claim.NodeID == nodeID &&
+				claim.State < structs.CSIVolumeClaimStateControllerDetached {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // NodeAffinityIterator is used to resolve any affinity rules in the job or task group,
 // and apply a weighted score to nodes if they match.
 type NodeAffinityIterator struct {
@@ -7,12 +7,14 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/hashicorp/nomad/ci"
 	"github.com/hashicorp/nomad/client/lib/idset"
 	"github.com/hashicorp/nomad/client/lib/numalib"
 	"github.com/hashicorp/nomad/client/lib/numalib/hw"
 	"github.com/hashicorp/nomad/helper/uuid"
 	"github.com/hashicorp/nomad/nomad/mock"
 	"github.com/hashicorp/nomad/nomad/structs"
+	"github.com/shoenig/test/must"
 	"github.com/stretchr/testify/require"
 )
 
@@ -972,6 +974,83 @@ func TestBinPackIterator_Network_Interpolation_Success(t *testing.T) {
 	require.Contains([]string{"first", "second"}, out[1].AllocResources.Networks[0].DynamicPorts[1].HostNetwork)
 }
 
+// Tests that ports without a host network are assigned on the network
+// interface selected by the group network
+func TestBinPackIterator_Network_Interface(t *testing.T) {
+	_, ctx := testContext(t)
+	nodes := []*RankedNode{
+		{
+			Node: &structs.Node{
+				Meta: map[string]string{
+					"data_nic": "eth1",
+				},
+				Attributes: map[string]string{
+					"network.interface.eth0.ipv4": "192.168.0.100",
+					"network.interface.eth1.ipv4": "10.0.1.5",
+				},
+				NodeResources: &structs.NodeResources{
+					Processors: processorResources2048,
+					Cpu:        legacyCpuResources2048,
+					Memory: structs.NodeMemoryResources{
+						MemoryMB: 2048,
+					},
+					NodeNetworks: []*structs.NodeNetworkResource{
+						{
+							Mode:   "host",
+							Device: "eth0",
+							Addresses: []structs.NodeNetworkAddress{
+								{
+									Alias:   "default",
+									Address: "192.168.0.100",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	static := NewStaticRankIterator(ctx, nodes)
+
+	taskGroup := &structs.TaskGroup{
+		Networks: []*structs.NetworkResource{
+			{
+				Interfaces: []string{"${meta.missing}", "${meta.data_nic}", "eth0"},
+				DynamicPorts: []structs.Port{
+					{
+						Label:       "http",
+						To:          8080,
+						HostNetwork: "default",
+					},
+				},
+			},
+		},
+		EphemeralDisk: &structs.EphemeralDisk{},
+		Tasks: []*structs.Task{
+			{
+				Name: "web",
+				Resources: &structs.Resources{
+					CPU:      1024,
+					MemoryMB: 1024,
+				},
+			},
+		},
+	}
+	binp := NewBinPackIterator(ctx, static, false, 0)
+	binp.SetTaskGroup(taskGroup)
+	binp.SetSchedulerConfiguration(testSchedulerConfig)
+
+	out := collectRanked(NewScoreNormalizationIterator(ctx, binp))
+	require := require.New(t)
+	require.Len(out, 1)
+
+	network := out[0].AllocResources.Networks[0]
+	require.Equal("eth1", network.Device)
+	require.Equal("10.0.1.5", network.IP)
+	require.Len(out[0].AllocResources.Ports, 1)
+	require.Equal("10.0.1.5", out[0].AllocResources.Ports[0].HostIP)
+}
+
 // Tests that bin packing iterator fails due to absence of meta value
 // This test has network resources at task group
 func TestBinPackIterator_Host_Network_Interpolation_Absent_Value(t *testing.T) {
@@ -1405,6 +1484,66 @@ func TestBinPackIterator_ReservedCores(t *testing.T) {
 	require.Equal([]uint16{1}, out[0].TaskResources["web"].Cpu.ReservedCores)
 }
 
+func TestBinPackIterator_ExclusiveCores(t *testing.T) {
+	_, ctx := testContext(t)
+
+	// core 1 is in the pool of exclusive cores
+	topology := &numalib.Topology{
+		NodeIDs:   idset.From[hw.NodeID]([]hw.NodeID{0}),
+		Distances: numalib.SLIT{[]numalib.Cost{10}},
+		Cores: []numalib.Core{{
+			ID:        0,
+			Grade:     numalib.Performance,
+			BaseSpeed: 1024,
+		}, {
+			ID:        1,
+			Grade:     numalib.Performance,
+			BaseSpeed: 1024,
+			Exclusive: true,
+		}},
+	}
+	legacyCpuResources, processorResources := cpuResourcesFrom(topology)
+
+	nodes := []*RankedNode{{
+		Node: &structs.Node{
+			ID: uuid.Generate(),
+			NodeResources: &structs.NodeResources{
+				Processors: processorResources,
+				Cpu:        legacyCpuResources,
+				Memory: structs.NodeMemoryResources{
+					MemoryMB: 2048,
+				},
+			},
+		},
+	}}
+
+	for _, exclusive := range []bool{false, true} {
+		taskGroup := &structs.TaskGroup{
+			EphemeralDisk: &structs.EphemeralDisk{},
+			Tasks: []*structs.Task{{
+				Name: "web",
+				Resources: &structs.Resources{
+					Cores:          1,
+					ExclusiveCores: exclusive,
+					MemoryMB:       1024,
+				},
+			}},
+		}
+		binp := NewBinPackIterator(ctx, NewStaticRankIterator(ctx, nodes), false, 0)
+		binp.SetTaskGroup(taskGroup)
+		binp.SetSchedulerConfiguration(testSchedulerConfig)
+
+		out := collectRanked(NewScoreNormalizationIterator(ctx, binp))
+		require.Len(t, out, 1)
+
+		expected := []uint16{0}
+		if exclusive {
+			expected = []uint16{1}
+		}
+		require.Equal(t, expected, out[0].TaskResources["web"].Cpu.ReservedCores)
+	}
+}
+
 func TestBinPackIterator_ExistingAlloc(t *testing.T) {
 	state, ctx := testContext(t)
 	nodes := []*RankedNode{
@@ -2220,3 +2359,119 @@ func TestNodeAffinityIterator(t *testing.T) {
 	}
 
 }
+
+func TestCSIVolumeScoringIterator(t *testing.T) {
+	ci.Parallel(t)
+	state, ctx := testContext(t)
+
+	nodes := []*RankedNode{
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+		{Node: mock.Node()},
+	}
+	racks := []string{"R1", "R2", "R2"}
+	for i, rack := range racks {
+		nodes[i].Node.CSINodePlugins = map[string]*structs.CSIInfo{
+			"foo": {
+				PluginID: "foo",
+				Healthy:  true,
+				NodeInfo: &structs.CSINodeInfo{
+					MaxVolumes: 4,
+					AccessibleTopology: &structs.CSITopology{
+						Segments: map[string]string{"rack": rack},
+					},
+				},
+			},
+		}
+	}
+
+	// The volume prefers rack R1 and is still staged on nodes[2] by a
+	// past claim that hasn't been released yet
+	vol := structs.NewCSIVolume("volume-id", 1000)
+	vol.PluginID = "foo"
+	vol.Namespace = structs.DefaultNamespace
+	vol.AccessMode = structs.CSIVolumeAccessModeSingleNodeWriter
+	vol.AttachmentMode = structs.CSIVolumeAttachmentModeFilesystem
+	vol.RequestedTopologies = &structs.CSITopologyRequest{
+		Preferred: []*structs.CSITopology{
+			{Segments: map[string]string{"rack": "R1"}},
+		},
+	}
+	vol.PastClaims = map[string]*structs.CSIVolumeClaim{
+		"old-alloc": {
+			AllocationID: "old-alloc",
+			NodeID:       nodes[2].Node.ID,
+			State:        structs.CSIVolumeClaimStateTaken,
+		},
+	}
+
+	// Another volume of the plugin is used by an allocation on nodes[1]
+	other := vol.Copy()
+	other.ID = "other-volume-id"
+	other.RequestedTopologies = nil
+	other.PastClaims = nil
+	must.NoError(t, state.UpsertCSIVolume(1000, []*structs.CSIVolume{vol, other}))
+
+	alloc := mock.Alloc()
+	alloc.NodeID = nodes[1].Node.ID
+	alloc.Job.TaskGroups[0].Volumes = map[string]*structs.VolumeRequest{
+		"other": {
+			Name:   "other",
+			Type:   structs.VolumeTypeCSI,
+			Source: other.ID,
+		},
+	}
+	must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Volumes = map[string]*structs.VolumeRequest{
+		"data": {
+			Name:     "data",
+			Type:     structs.VolumeTypeCSI,
+			Source:   "volume-id",
+			Affinity: 100,
+		},
+	}
+
+	static := NewStaticRankIterator(ctx, nodes)
+	csiScore := NewCSIVolumeScoringIterator(ctx, static)
+	csiScore.SetJob(job)
+	csiScore.SetVolumes("", tg.Volumes)
+
+	out := collectRanked(NewScoreNormalizationIterator(ctx, csiScore))
+	must.Len(t, 4, out)
+
+	expectedScores := map[string]float64{
+		// Preferred topology
+		nodes[0].Node.ID: 1.0 / 3.0,
+		// One of four attach slots used by another volume
+		nodes[1].Node.ID: -0.25 / 3.0,
+		// Already staged, with affinity
+		nodes[2].Node.ID: 1.0 / 3.0,
+		// No node plugin, so no CSI score
+		nodes[3].Node.ID: 0,
+	}
+	for _, n := range out {
+		must.Eq(t, expectedScores[n.Node.ID], n.FinalScore,
+			must.Sprintf("unexpected score for node %s", n.Node.ID))
+	}
+
+	// Without the affinity, the staged node is no longer preferred
+	tg.Volumes["data"].Affinity = 0
+	static = NewStaticRankIterator(ctx, nodes[:3])
+	for _, n := range nodes {
+		n.Scores = nil
+		n.FinalScore = 0
+	}
+	csiScore = NewCSIVolumeScoringIterator(ctx, static)
+	csiScore.SetJob(job)
+	csiScore.SetVolumes("", tg.Volumes)
+
+	out = collectRanked(NewScoreNormalizationIterator(ctx, csiScore))
+	must.Len(t, 3, out)
+	must.Eq(t, 1.0/2.0, out[0].FinalScore)
+	must.Eq(t, -0.25/2.0, out[1].FinalScore)
+	must.Eq(t, 0, out[2].FinalScore)
+}
@@ -648,21 +648,30 @@ func (a *allocReconciler) requiresCanaries(tg *structs.TaskGroup, dstate *struct
 	canariesPromoted := dstate != nil && dstate.Promoted
 	return tg.Update != nil &&
 		len(destructive) != 0 &&
-		len(canaries) < tg.Update.Canary &&
+		len(canaries) < tg.Update.DesiredCanaries(tg.Count) &&
 		!canariesPromoted
 }
 
 func (a *allocReconciler) computeCanaries(tg *structs.TaskGroup, dstate *structs.DeploymentState,
 	destructive, canaries allocSet, desiredChanges *structs.DesiredUpdates, nameIndex *allocNameIndex) {
-	dstate.DesiredCanaries = tg.Update.Canary
+	dstate.DesiredCanaries = tg.Update.DesiredCanaries(tg.Count)
 
 	if !a.deploymentPaused && !a.deploymentFailed {
-		desiredChanges.Canary += uint64(tg.Update.Canary - len(canaries))
+		// Canaries are placed relative to the nodes running the previous
+		// version if the update strategy has a canary placement preference
+		var previousNodes map[string]struct{}
+		if tg.Update.CanaryPlacement != "" {
+			previousNodes = destructive.nodeIDs()
+		}
+
+		desiredChanges.Canary += uint64(dstate.DesiredCanaries - len(canaries))
 		for _, name := range nameIndex.NextCanaries(uint(desiredChanges.Canary), canaries, destructive) {
 			a.result.place = append(a.result.place, allocPlaceResult{
-				name:      name,
-				canary:    true,
-				taskGroup: tg,
+				name:            name,
+				canary:          true,
+				taskGroup:       tg,
+				canaryPlacement: tg.Update.CanaryPlacement,
+				previousNodes:   previousNodes,
 			})
 		}
 	}
@@ -839,9 +848,14 @@ func (a *allocReconciler) computePlacements(group *structs.TaskGroup,
 		})
 	}
 
-	// Add remaining placement results
+	// Add remaining placement results, limited by the concurrency of job
+	// arrays
 	if existing < group.Count {
-		for _, name := range nameIndex.Next(uint(group.Count - existing)) {
+		remaining := group.Count - existing
+		if group.Array != nil {
+			remaining = min(remaining, arrayAvailable(group.Array, place, untainted, migrate))
+		}
+		for _, name := range nameIndex.Next(uint(remaining)) {
 			place = append(place, allocPlaceResult{
 				name:               name,
 				taskGroup:          group,
@@ -853,6 +867,20 @@ func (a *allocReconciler) computePlacements(group *structs.TaskGroup,
 	return place
 }
 
+// arrayAvailable returns how many more indices of a job array may start
+// running given the placements already made and the existing allocations.
+func arrayAvailable(array *structs.ArrayConfig, place []allocPlaceResult, existing ...allocSet) int {
+	running := len(place)
+	for _, set := range existing {
+		for _, alloc := range set {
+			if !alloc.ClientTerminalStatus() {
+				running++
+			}
+		}
+	}
+	return max(array.Limit()-running, 0)
+}
+
 // computeReplacements either applies the placements calculated by computePlacements,
 // or computes more placements based on whether the deployment is ready for placement
 // and if the placement is already rescheduling or part of a failed deployment.
@@ -52,6 +52,11 @@ type placementResult interface {
 	DowngradeNonCanary() bool
 
 	MinJobVersion() uint64
+
+	// CanaryPlacement returns the canary placement preference of the update
+	// strategy and the IDs of the nodes running the previous version of the
+	// task group, which the preference is relative to.
+	CanaryPlacement() (string, map[string]struct{})
 }
 
 // allocStopResult contains the information required to stop a single allocation
@@ -74,6 +79,9 @@ type allocPlaceResult struct {
 
 	downgradeNonCanary bool
 	minJobVersion      uint64
+
+	canaryPlacement string
+	previousNodes   map[string]struct{}
 }
 
 func (a allocPlaceResult) TaskGroup() *structs.TaskGroup           { return a.taskGroup }
@@ -85,6 +93,9 @@ func (a allocPlaceResult) StopPreviousAlloc() (bool, string)       { return fals
 func (a allocPlaceResult) DowngradeNonCanary() bool                { return a.downgradeNonCanary }
 func (a allocPlaceResult) MinJobVersion() uint64                   { return a.minJobVersion }
 func (a allocPlaceResult) PreviousLost() bool                      { return a.lost }
+func (a allocPlaceResult) CanaryPlacement() (string, map[string]struct{}) {
+	return a.canaryPlacement, a.previousNodes
+}
 
 // allocDestructiveResult contains the information required to do a destructive
 // update. Destructive changes should be applied atomically, as in the old alloc
@@ -107,6 +118,9 @@ func (a allocDestructiveResult) StopPreviousAlloc() (bool, string) {
 func (a allocDestructiveResult) DowngradeNonCanary() bool { return false }
 func (a allocDestructiveResult) MinJobVersion() uint64    { return 0 }
 func (a allocDestructiveResult) PreviousLost() bool       { return false }
+func (a allocDestructiveResult) CanaryPlacement() (string, map[string]struct{}) {
+	return "", nil
+}
 
 // allocMatrix is a mapping of task groups to their allocation set.
 type allocMatrix map[string]allocSet
@@ -161,6 +175,15 @@ func (a allocSet) nameSet() map[string]struct{} {
 	return names
 }
 
+// nodeIDs returns the set of IDs of the nodes the allocations are on
+func (a allocSet) nodeIDs() map[string]struct{} {
+	nodes := make(map[string]struct{}, len(a))
+	for _, alloc := range a {
+		nodes[alloc.NodeID] = struct{}{}
+	}
+	return nodes
+}
+
 // nameOrder returns the set of allocation names in sorted order
 func (a allocSet) nameOrder() []*structs.Allocation {
 	allocs := make([]*structs.Allocation, 0, len(a))
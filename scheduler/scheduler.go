@@ -81,6 +81,9 @@ type State interface {
 	// NodePoolByName is used to lookup a node by ID.
 	NodePoolByName(ws memdb.WatchSet, poolName string) (*structs.NodePool, error)
 
+	// NamespaceByName is used to lookup a namespace by name.
+	NamespaceByName(ws memdb.WatchSet, name string) (*structs.Namespace, error)
+
 	// AllocsByJob returns the allocations by JobID
 	AllocsByJob(ws memdb.WatchSet, namespace, jobID string, all bool) ([]*structs.Allocation, error)
 
@@ -6,6 +6,7 @@ package scheduler
 import (
 	"fmt"
 	"runtime/debug"
+	"time"
 
 	log "github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-memdb"
@@ -46,8 +47,10 @@ type SystemScheduler struct {
 	notReadyNodes map[string]struct{}
 	nodesByDC     map[string]int
 
-	limitReached bool
-	nextEval     *structs.Evaluation
+	limitReached  bool
+	canaryWaiting bool
+	stagger       time.Duration
+	nextEval      *structs.Evaluation
 
 	failedTGAllocs map[string]*structs.AllocMetric
 	queuedAllocs   map[string]int
@@ -137,10 +140,21 @@ func (s *SystemScheduler) process() (bool, error) {
 
 	// Get the ready nodes in the required datacenters
 	if !s.job.Stopped() {
-		s.nodes, s.notReadyNodes, s.nodesByDC, err = readyNodesInDCsAndPool(
-			s.state, s.job.Datacenters, s.job.NodePool)
+		var allowed bool
+		allowed, err = namespaceAllowsNodePool(s.state, s.job)
 		if err != nil {
-			return false, fmt.Errorf("failed to get ready nodes: %v", err)
+			return false, err
+		}
+
+		// Jobs can't be placed in node pools their namespace doesn't allow
+		if allowed {
+			s.nodes, s.notReadyNodes, s.nodesByDC, err = readyNodesInDCsAndPool(
+				s.state, s.job.Datacenters, s.job.NodePool)
+			if err != nil {
+				return false, fmt.Errorf("failed to get ready nodes: %v", err)
+			}
+		} else {
+			s.nodes, s.notReadyNodes, s.nodesByDC = nil, map[string]struct{}{}, map[string]int{}
 		}
 	}
 
@@ -165,16 +179,13 @@ func (s *SystemScheduler) process() (bool, error) {
 		return false, err
 	}
 
-	// If the plan is a no-op, we can bail. If AnnotatePlan is set submit the plan
-	// anyways to get the annotations.
-	if s.plan.IsNoOp() && !s.eval.AnnotatePlan {
-		return true, nil
-	}
-
 	// If the limit of placements was reached we need to create an evaluation
-	// to pickup from here after the stagger period.
-	if s.limitReached && s.nextEval == nil {
-		s.nextEval = s.eval.NextRollingEval(s.job.Update.Stagger)
+	// to pickup from here after the stagger period. If the plan is a no-op
+	// this is only done while updates are held back waiting on canaries that
+	// may still become healthy, otherwise the next evaluation would make no
+	// progress either.
+	if s.limitReached && s.nextEval == nil && (!s.plan.IsNoOp() || s.canaryWaiting) {
+		s.nextEval = s.eval.NextRollingEval(s.stagger)
 		if err := s.planner.CreateEval(s.nextEval); err != nil {
 			s.logger.Error("failed to make next eval for rolling update", "error", err)
 			return false, err
@@ -182,6 +193,12 @@ func (s *SystemScheduler) process() (bool, error) {
 		s.logger.Debug("rolling update limit reached, next eval created", "next_eval_id", s.nextEval.ID)
 	}
 
+	// If the plan is a no-op, we can bail. If AnnotatePlan is set submit the plan
+	// anyways to get the annotations.
+	if s.plan.IsNoOp() && !s.eval.AnnotatePlan {
+		return true, nil
+	}
+
 	// Submit the plan
 	result, newState, err := s.planner.SubmitPlan(s.plan)
 	s.planResult = result
@@ -294,14 +311,8 @@ func (s *SystemScheduler) computeJobAllocs() error {
 		}
 	}
 
-	// Check if a rolling upgrade strategy is being used
-	limit := len(diff.update)
-	if !s.job.Stopped() && s.job.Update.Rolling() {
-		limit = s.job.Update.MaxParallel
-	}
-
 	// Treat non in-place updates as an eviction and new placement.
-	s.limitReached = evictAndPlace(s.ctx, diff, diff.update, allocUpdating, &limit)
+	s.limitReached = s.evictAndPlaceUpdates(diff)
 
 	// Nothing remaining to do if placement is not required
 	if len(diff.place) == 0 {
@@ -322,6 +333,187 @@ func (s *SystemScheduler) computeJobAllocs() error {
 	return s.computePlacements(diff.place)
 }
 
+// evictAndPlaceUpdates treats destructive updates as an eviction and a new
+// placement. The number of allocations replaced at once is limited per task
+// group by its update strategy, and allocations on the canary nodes of a task
+// group are replaced before any others. It returns true if any updates were
+// held back, in which case s.stagger is set to how long to wait before the
+// next rolling evaluation, and s.canaryWaiting to whether updates are waiting
+// on canaries that may still become healthy.
+func (s *SystemScheduler) evictAndPlaceUpdates(diff *diffResult) bool {
+	updates := make(map[string][]allocTuple)
+	for _, tuple := range diff.update {
+		updates[tuple.TaskGroup.Name] = append(updates[tuple.TaskGroup.Name], tuple)
+	}
+
+	// Lookup the canary nodes lazily as most jobs don't use them
+	var nodeByID map[string]*structs.Node
+
+	s.stagger = 0
+	s.canaryWaiting = false
+
+	// A stopped or purged job has no update strategy to follow
+	if s.job.Stopped() {
+		limit := len(diff.update)
+		return evictAndPlace(s.ctx, diff, diff.update, allocUpdating, &limit)
+	}
+
+	limitReached := false
+	for _, tg := range s.job.TaskGroups {
+		tgUpdates := updates[tg.Name]
+		if len(tgUpdates) == 0 {
+			continue
+		}
+
+		u := tg.Update
+		if u == nil {
+			u = &s.job.Update
+		}
+
+		// Check if a rolling upgrade strategy is being used
+		limit := len(tgUpdates)
+		if u.Rolling() {
+			limit = u.MaxParallel
+		}
+
+		held := false
+		if len(u.CanaryConstraints) != 0 {
+			if nodeByID == nil {
+				nodeByID = make(map[string]*structs.Node, len(s.nodes))
+				for _, node := range s.nodes {
+					nodeByID[node.ID] = node
+				}
+			}
+			isCanary := s.canaryNodeChecker(u, nodeByID)
+
+			var canaries []allocTuple
+			for _, tuple := range tgUpdates {
+				if isCanary(tuple.Alloc.NodeID) {
+					canaries = append(canaries, tuple)
+				}
+			}
+
+			switch {
+			case len(canaries) != 0:
+				// Replace the allocations on canary nodes first
+				held = len(canaries) != len(tgUpdates)
+				tgUpdates = canaries
+			default:
+				healthy, waiting, failed := s.canaryHealth(tg, u, diff, isCanary)
+				if failed {
+					// Halt the update until the job is updated again
+					s.logger.Warn("canary allocation failed, pausing update",
+						"task_group", tg.Name)
+					continue
+				}
+				if !healthy {
+					tgUpdates = nil
+					held = true
+					s.canaryWaiting = s.canaryWaiting || waiting
+				}
+			}
+		}
+
+		if evictAndPlace(s.ctx, diff, tgUpdates, allocUpdating, &limit) {
+			held = true
+		}
+		if held {
+			limitReached = true
+			if s.stagger == 0 || u.Stagger < s.stagger {
+				s.stagger = u.Stagger
+			}
+		}
+	}
+
+	return limitReached
+}
+
+// canaryNodeChecker returns a function that reports whether a node is a canary
+// node of the update strategy.
+func (s *SystemScheduler) canaryNodeChecker(u *structs.UpdateStrategy, nodeByID map[string]*structs.Node) func(string) bool {
+	checker := NewConstraintChecker(s.ctx, u.CanaryConstraints)
+	return func(nodeID string) bool {
+		node, ok := nodeByID[nodeID]
+		if !ok {
+			return false
+		}
+		for _, constraint := range u.CanaryConstraints {
+			if !checker.meetsConstraint(constraint, node) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// canaryHealth returns whether all the allocations of the task group on its
+// canary nodes are running the current version of the job, whether any
+// unhealthy ones are still pending or running and so may become healthy, and
+// whether any of them have failed. Allocations are only considered healthy
+// once their tasks have been running for the update strategy's minimum
+// healthy time.
+func (s *SystemScheduler) canaryHealth(tg *structs.TaskGroup, u *structs.UpdateStrategy,
+	diff *diffResult, isCanary func(string) bool) (healthy, waiting, failed bool) {
+
+	now := time.Now()
+	healthy = true
+
+	for _, tuple := range diff.place {
+		if tuple.TaskGroup.Name != tg.Name || !isCanary(tuple.Alloc.NodeID) {
+			continue
+		}
+
+		// The canary still needs to be placed. If the previous allocation
+		// failed on the current version, the canary has failed.
+		healthy = false
+		prev := tuple.Alloc
+		if prev.Job != nil && prev.Job.JobModifyIndex == s.job.JobModifyIndex &&
+			prev.ClientStatus == structs.AllocClientStatusFailed {
+			failed = true
+		}
+	}
+
+	for _, tuple := range diff.ignore {
+		if tuple.TaskGroup.Name != tg.Name || !isCanary(tuple.Alloc.NodeID) {
+			continue
+		}
+		if !systemCanaryHealthy(tuple.Alloc, s.job, u.MinHealthyTime, now) {
+			healthy = false
+			if tuple.Alloc.Job != nil && tuple.Alloc.Job.JobModifyIndex == s.job.JobModifyIndex &&
+				!tuple.Alloc.ClientTerminalStatus() {
+				waiting = true
+			}
+		}
+	}
+
+	return healthy, waiting, failed
+}
+
+// systemCanaryHealthy returns whether an allocation on a canary node is running
+// the current version of the job and all of its running tasks have been
+// running for at least minHealthyTime.
+func systemCanaryHealthy(alloc *structs.Allocation, job *structs.Job, minHealthyTime time.Duration, now time.Time) bool {
+	if alloc.Job == nil || alloc.Job.JobModifyIndex != job.JobModifyIndex {
+		return false
+	}
+	if alloc.ClientStatus != structs.AllocClientStatusRunning {
+		return false
+	}
+	for _, state := range alloc.TaskStates {
+		if state.State != structs.TaskStateRunning {
+			continue
+		}
+		started := state.StartedAt
+		if state.Restarts > 0 {
+			started = state.LastRestart
+		}
+		if now.Sub(started) < minHealthyTime {
+			return false
+		}
+	}
+	return true
+}
+
 func mergeNodeFiltered(acc, curr *structs.AllocMetric) *structs.AllocMetric {
 	if acc == nil {
 		return curr.Copy()
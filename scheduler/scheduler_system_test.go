@@ -94,6 +94,47 @@ func TestSystemSched_JobRegister(t *testing.T) {
 	h.AssertEvalStatus(t, structs.EvalStatusComplete)
 }
 
+func TestSystemSched_JobRegister_Count(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create some nodes
+	nodes := createNodes(t, h, 5)
+
+	// Create a job that runs two allocations per node
+	job := mock.SystemJob()
+	job.TaskGroups[0].Count = 2
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	eval := &structs.Evaluation{
+		Namespace:   structs.DefaultNamespace,
+		ID:          uuid.Generate(),
+		Priority:    job.Priority,
+		TriggeredBy: structs.EvalTriggerJobRegister,
+		JobID:       job.ID,
+		Status:      structs.EvalStatusPending,
+	}
+	must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+
+	// Process the evaluation
+	must.NoError(t, h.Process(NewSystemScheduler, eval))
+	must.Len(t, 1, h.Plans)
+	plan := h.Plans[0]
+
+	// Ensure every node got an allocation for each index
+	must.MapLen(t, len(nodes), plan.NodeAllocation)
+	for _, node := range nodes {
+		var names []string
+		for _, alloc := range plan.NodeAllocation[node.ID] {
+			names = append(names, alloc.Name)
+		}
+		must.SliceContainsAll(t, []string{"my-job.web[0]", "my-job.web[1]"}, names)
+	}
+
+	h.AssertEvalStatus(t, structs.EvalStatusComplete)
+}
+
 func TestSystemSched_JobRegister_StickyAllocs(t *testing.T) {
 	ci.Parallel(t)
 
@@ -723,6 +764,190 @@ func TestSystemSched_JobModify_Rolling(t *testing.T) {
 	}
 }
 
+func TestSystemSched_JobModify_CanaryConstraints(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create some nodes, one of which is a canary node
+	nodes := createNodes(t, h, 4)
+	canary := nodes[0].Copy()
+	canary.Meta["canary"] = "true"
+	must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), canary))
+
+	// Generate a fake job with allocations
+	job := mock.SystemJob()
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	var allocs []*structs.Allocation
+	for _, node := range nodes {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = node.ID
+		alloc.Name = "my-job.web[0]"
+		allocs = append(allocs, alloc)
+	}
+	must.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	// Update the job such that it cannot be done in-place, and update the
+	// canary node first
+	job2 := job.Copy()
+	job2.TaskGroups[0].Update = &structs.UpdateStrategy{
+		Stagger:        30 * time.Second,
+		MaxParallel:    10,
+		MinHealthyTime: 10 * time.Second,
+		CanaryConstraints: []*structs.Constraint{{
+			LTarget: "${meta.canary}",
+			RTarget: "true",
+			Operand: "=",
+		}},
+	}
+	job2.TaskGroups[0].Tasks[0].Config["command"] = "/bin/other"
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job2))
+
+	process := func() {
+		eval := &structs.Evaluation{
+			Namespace:   structs.DefaultNamespace,
+			ID:          uuid.Generate(),
+			Priority:    50,
+			TriggeredBy: structs.EvalTriggerJobRegister,
+			JobID:       job.ID,
+			Status:      structs.EvalStatusPending,
+		}
+		must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+		must.NoError(t, h.Process(NewSystemScheduler, eval))
+	}
+
+	// Ensure only the allocation on the canary node was replaced and a
+	// follow up eval was created
+	process()
+	must.Len(t, 1, h.Plans)
+	plan := h.Plans[0]
+	must.MapLen(t, 1, plan.NodeUpdate)
+	must.Len(t, 1, plan.NodeUpdate[canary.ID])
+	must.MapLen(t, 1, plan.NodeAllocation)
+	must.Len(t, 1, plan.NodeAllocation[canary.ID])
+	must.Len(t, 1, h.CreateEvals)
+	must.Eq(t, structs.EvalTriggerRollingUpdate, h.CreateEvals[0].TriggeredBy)
+	must.Eq(t, 30*time.Second, h.CreateEvals[0].Wait)
+
+	// The remaining allocations are held back until the canary is healthy,
+	// but another follow up eval is created to check on it
+	process()
+	must.Len(t, 1, h.Plans)
+	must.Len(t, 2, h.CreateEvals)
+
+	// Mark the canary as running for longer than the minimum healthy time
+	ws := memdb.NewWatchSet()
+	out, err := h.State.AllocsByNode(ws, canary.ID)
+	must.NoError(t, err)
+	var running *structs.Allocation
+	for _, alloc := range out {
+		if !alloc.TerminalStatus() {
+			running = alloc.Copy()
+		}
+	}
+	must.NotNil(t, running)
+	running.ClientStatus = structs.AllocClientStatusRunning
+	running.TaskStates = map[string]*structs.TaskState{
+		"web": {
+			State:     structs.TaskStateRunning,
+			StartedAt: time.Now().Add(-time.Minute),
+		},
+	}
+	must.NoError(t, h.State.UpdateAllocsFromClient(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Allocation{running}))
+
+	// Ensure the remaining allocations are now replaced
+	process()
+	must.Len(t, 2, h.Plans)
+	plan = h.Plans[1]
+	must.MapLen(t, 3, plan.NodeUpdate)
+	must.MapLen(t, 3, plan.NodeAllocation)
+	must.MapNotContainsKey(t, plan.NodeAllocation, canary.ID)
+}
+
+func TestSystemSched_JobModify_CanaryConstraints_PlacementFailed(t *testing.T) {
+	ci.Parallel(t)
+
+	h := NewHarness(t)
+
+	// Create some nodes, one of which is a canary node too small to place
+	// the updated allocation on
+	nodes := createNodes(t, h, 3)
+	canary := nodes[0].Copy()
+	canary.Meta["canary"] = "true"
+	canary.NodeResources.Memory.MemoryMB = 10
+	must.NoError(t, h.State.UpsertNode(structs.MsgTypeTestSetup, h.NextIndex(), canary))
+
+	// Generate a fake job with allocations
+	job := mock.SystemJob()
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job))
+
+	var allocs []*structs.Allocation
+	for _, node := range nodes {
+		alloc := mock.Alloc()
+		alloc.Job = job
+		alloc.JobID = job.ID
+		alloc.NodeID = node.ID
+		alloc.Name = "my-job.web[0]"
+		allocs = append(allocs, alloc)
+	}
+	must.NoError(t, h.State.UpsertAllocs(structs.MsgTypeTestSetup, h.NextIndex(), allocs))
+
+	// Update the job such that it cannot be done in-place, and update the
+	// canary node first
+	job2 := job.Copy()
+	job2.TaskGroups[0].Update = &structs.UpdateStrategy{
+		Stagger:     30 * time.Second,
+		MaxParallel: 10,
+		CanaryConstraints: []*structs.Constraint{{
+			LTarget: "${meta.canary}",
+			RTarget: "true",
+			Operand: "=",
+		}},
+	}
+	job2.TaskGroups[0].Tasks[0].Config["command"] = "/bin/other"
+	must.NoError(t, h.State.UpsertJob(structs.MsgTypeTestSetup, h.NextIndex(), nil, job2))
+
+	process := func() {
+		eval := &structs.Evaluation{
+			Namespace:   structs.DefaultNamespace,
+			ID:          uuid.Generate(),
+			Priority:    50,
+			TriggeredBy: structs.EvalTriggerJobRegister,
+			JobID:       job.ID,
+			Status:      structs.EvalStatusPending,
+		}
+		must.NoError(t, h.State.UpsertEvals(structs.MsgTypeTestSetup, h.NextIndex(), []*structs.Evaluation{eval}))
+		must.NoError(t, h.Process(NewSystemScheduler, eval))
+	}
+	rollingEvals := func() int {
+		n := 0
+		for _, eval := range h.CreateEvals {
+			if eval.TriggeredBy == structs.EvalTriggerRollingUpdate {
+				n++
+			}
+		}
+		return n
+	}
+
+	// Ensure the allocation on the canary node was stopped but couldn't be
+	// replaced, and a follow up eval was created
+	process()
+	must.Len(t, 1, h.Plans)
+	must.MapLen(t, 1, h.Plans[0].NodeUpdate)
+	must.MapEmpty(t, h.Plans[0].NodeAllocation)
+	must.Eq(t, 1, rollingEvals())
+
+	// The canary still can't be placed, so the plan is a no-op and no follow
+	// up eval is created as it couldn't make progress either. The blocked
+	// eval retries the placement once the node changes.
+	process()
+	must.Len(t, 1, h.Plans)
+	must.Eq(t, 1, rollingEvals())
+}
+
 func TestSystemSched_JobModify_InPlace(t *testing.T) {
 	ci.Parallel(t)
 
@@ -475,6 +475,83 @@ func TestSpreadIterator_EvenSpread(t *testing.T) {
 
 }
 
+// TestSpreadIterator_DynamicNodeMeta asserts that spreading on node metadata
+// honors values that were changed after the node registered, such as those
+// set with the dynamic node metadata API.
+func TestSpreadIterator_DynamicNodeMeta(t *testing.T) {
+	ci.Parallel(t)
+
+	state, ctx := testContext(t)
+	racks := []string{"r1", "r1", "r2", "r2"}
+	var nodes []*RankedNode
+
+	for i, rack := range racks {
+		node := mock.Node()
+		node.Meta["rack"] = rack
+		must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, uint64(100+i), node))
+		nodes = append(nodes, &RankedNode{Node: node})
+	}
+
+	job := mock.Job()
+	tg := job.TaskGroups[0]
+	tg.Count = 4
+	tg.Spreads = []*structs.Spread{{
+		Weight:    100,
+		Attribute: "${meta.rack}",
+	}}
+
+	// Place one alloc in each rack
+	for _, i := range []int{0, 2} {
+		ctx.plan.NodeAllocation[nodes[i].Node.ID] = []*structs.Allocation{{
+			Namespace: structs.DefaultNamespace,
+			TaskGroup: tg.Name,
+			JobID:     job.ID,
+			Job:       job,
+			ID:        uuid.Generate(),
+			NodeID:    nodes[i].Node.ID,
+		}}
+	}
+
+	scores := func() map[string]float64 {
+		for _, node := range nodes {
+			node.Scores = nil
+			node.FinalScore = 0
+		}
+		static := NewStaticRankIterator(ctx, nodes)
+		spreadIter := NewSpreadIterator(ctx, static)
+		spreadIter.SetJob(job)
+		spreadIter.SetTaskGroup(tg)
+		scoreNorm := NewScoreNormalizationIterator(ctx, spreadIter)
+
+		out := map[string]float64{}
+		for _, rn := range collectRanked(scoreNorm) {
+			out[rn.Node.ID] = rn.FinalScore
+		}
+		return out
+	}
+
+	// The distribution is even so every node is penalized
+	out := scores()
+	for _, node := range nodes {
+		must.Eq(t, -1.0, out[node.Node.ID])
+	}
+
+	// Move the node with the r2 alloc into a new rack, as
+	// "nomad node meta apply -node-id <id> rack=r3" would
+	updated := nodes[2].Node.Copy()
+	updated.Meta["rack"] = "r3"
+	must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 200, updated))
+	nodes[2].Node = updated
+
+	// r2 no longer has any allocs so its remaining node is boosted, while the
+	// existing alloc is now counted against r3
+	out = scores()
+	must.Eq(t, -1.0, out[nodes[0].Node.ID])
+	must.Eq(t, -1.0, out[nodes[1].Node.ID])
+	must.Eq(t, -1.0, out[nodes[2].Node.ID])
+	must.Eq(t, 1.0, out[nodes[3].Node.ID])
+}
+
 // Test scenarios where the spread iterator sets maximum penalty (-1.0)
 func TestSpreadIterator_MaxPenalty(t *testing.T) {
 	ci.Parallel(t)
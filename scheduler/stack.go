@@ -64,6 +64,7 @@ type GenericStack struct {
 	binPack                    *BinPackIterator
 	jobAntiAff                 *JobAntiAffinityIterator
 	nodeReschedulingPenalty    *NodeReschedulingPenaltyIterator
+	csiVolumeScore             *CSIVolumeScoringIterator
 	limit                      *LimitIterator
 	maxScore                   *MaxScoreIterator
 	nodeAffinity               *NodeAffinityIterator
@@ -107,6 +108,7 @@ func (s *GenericStack) SetJob(job *structs.Job) {
 	s.distinctPropertyConstraint.SetJob(job)
 	s.binPack.SetJob(job)
 	s.jobAntiAff.SetJob(job)
+	s.csiVolumeScore.SetJob(job)
 	s.nodeAffinity.SetJob(job)
 	s.spread.SetJob(job)
 	s.ctx.Eligibility().SetJob(job)
@@ -170,6 +172,7 @@ func (s *GenericStack) Select(tg *structs.TaskGroup, options *SelectOptions) *Ra
 	if options != nil {
 		s.nodeReschedulingPenalty.SetPenaltyNodes(options.PenaltyNodeIDs)
 	}
+	s.csiVolumeScore.SetVolumes(options.AllocName, tg.Volumes)
 	s.nodeAffinity.SetTaskGroup(tg)
 	s.spread.SetTaskGroup(tg)
 
@@ -445,8 +448,11 @@ func NewGenericStack(batch bool, ctx Context) *GenericStack {
 	// node where the allocation failed previously
 	s.nodeReschedulingPenalty = NewNodeReschedulingPenaltyIterator(ctx, s.jobAntiAff)
 
+	// Apply scores based on CSI volume attach limits, topology and affinity
+	s.csiVolumeScore = NewCSIVolumeScoringIterator(ctx, s.nodeReschedulingPenalty)
+
 	// Apply scores based on affinity block
-	s.nodeAffinity = NewNodeAffinityIterator(ctx, s.nodeReschedulingPenalty)
+	s.nodeAffinity = NewNodeAffinityIterator(ctx, s.csiVolumeScore)
 
 	// Apply scores based on spread block
 	s.spread = NewSpreadIterator(ctx, s.nodeAffinity)
@@ -44,6 +44,18 @@ func (d *diffResult) Append(other *diffResult) {
 	d.reconnecting = append(d.reconnecting, other.reconnecting...)
 }
 
+// namespaceAllowsNodePool returns true if the job's namespace allows its jobs
+// to be placed in the job's node pool. The node pool is validated when the job
+// is registered, but the namespace may have been bound to other node pools
+// since.
+func namespaceAllowsNodePool(state State, job *structs.Job) (bool, error) {
+	ns, err := state.NamespaceByName(nil, job.Namespace)
+	if err != nil {
+		return false, fmt.Errorf("failed to get namespace %q: %v", job.Namespace, err)
+	}
+	return ns.AllowsNodePool(job.NodePool), nil
+}
+
 // readyNodesInDCsAndPool returns all the ready nodes in the given datacenters
 // and pool, and a mapping of each data center to the count of ready nodes.
 func readyNodesInDCsAndPool(state State, dcs []string, pool string) ([]*structs.Node, map[string]struct{}, map[string]int, error) {
@@ -191,6 +203,11 @@ type comparison struct {
 	label    string
 	before   any
 	after    any
+
+	// resize is set if the only modification is to the cpu or memory
+	// resources of tasks, which can be applied in-place by drivers that
+	// support resizing running tasks.
+	resize bool
 }
 
 func difference(label string, before, after any) comparison {
@@ -204,6 +221,13 @@ func difference(label string, before, after any) comparison {
 	}
 }
 
+// resized is a difference in the cpu or memory resources of a task.
+func resized(label string, before, after any) comparison {
+	c := difference(label, before, after)
+	c.resize = true
+	return c
+}
+
 func (c comparison) String() string {
 	return fmt.Sprintf("%s changed; before: %#v, after: %#v", c.label, c.before, c.after)
 }
@@ -211,6 +235,16 @@ func (c comparison) String() string {
 // same indicates no destructive difference between two task groups
 var same = comparison{modified: false}
 
+// ephemeralDiskUpdated returns whether the ephemeral disk changed in a way
+// that requires a destructive update. Migrate hooks are run by the client
+// from the current version of the allocation, so they are updated in place.
+func ephemeralDiskUpdated(a, b *structs.EphemeralDisk) bool {
+	if a == nil || b == nil {
+		return a != b
+	}
+	return a.Sticky != b.Sticky || a.SizeMB != b.SizeMB || a.Migrate != b.Migrate
+}
+
 // tasksUpdated creates a comparison between task groups to see if the tasks, their
 // drivers, environment variables or config have been modified.
 func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) comparison {
@@ -223,7 +257,7 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) comparison {
 	}
 
 	// Check ephemeral disk
-	if !a.EphemeralDisk.Equal(b.EphemeralDisk) {
+	if ephemeralDiskUpdated(a.EphemeralDisk, b.EphemeralDisk) {
 		return difference("ephemeral disk", a.EphemeralDisk, b.EphemeralDisk)
 	}
 
@@ -283,6 +317,9 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) comparison {
 		if !maps.Equal(at.Env, bt.Env) {
 			return difference("task env", at.Env, bt.Env)
 		}
+		if !maps.Equal(at.Sealed, bt.Sealed) {
+			return difference("task sealed values", at.Sealed, bt.Sealed)
+		}
 		if !slices.EqualFunc(at.Artifacts, bt.Artifacts, func(a, b *structs.TaskArtifact) bool { return a.Equal(b) }) {
 			return difference("task artifacts", at.Artifacts, bt.Artifacts)
 		}
@@ -342,6 +379,16 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) comparison {
 
 	}
 
+	// Changes to the cpu and memory of tasks are only checked once no other
+	// destructive change has been found, as they may be applied in-place if
+	// the task drivers support resizing running tasks
+	for _, at := range a.Tasks {
+		bt := b.LookupTask(at.Name)
+		if c := resourcesResized(at.Resources, bt.Resources); c.modified {
+			return c
+		}
+	}
+
 	// none of the fields that trigger a destructive update were modified,
 	// indicating this group can be updated in-place or ignored
 	return same
@@ -350,14 +397,10 @@ func tasksUpdated(jobA, jobB *structs.Job, taskGroup string) comparison {
 func nonNetworkResourcesUpdated(a, b *structs.Resources) comparison {
 	// Inspect the non-network resources
 	switch {
-	case a.CPU != b.CPU:
-		return difference("task cpu", a.CPU, b.CPU)
 	case a.Cores != b.Cores:
 		return difference("task cores", a.Cores, b.Cores)
-	case a.MemoryMB != b.MemoryMB:
-		return difference("task memory", a.MemoryMB, b.MemoryMB)
-	case a.MemoryMaxMB != b.MemoryMaxMB:
-		return difference("task memory max", a.MemoryMaxMB, b.MemoryMaxMB)
+	case a.ExclusiveCores != b.ExclusiveCores:
+		return difference("task exclusive cores", a.ExclusiveCores, b.ExclusiveCores)
 	case !a.Devices.Equal(&b.Devices):
 		return difference("task devices", a.Devices, b.Devices)
 	case !a.NUMA.Equal(b.NUMA):
@@ -366,6 +409,40 @@ func nonNetworkResourcesUpdated(a, b *structs.Resources) comparison {
 	return same
 }
 
+// resourcesResized returns a resize comparison if the cpu or memory resources
+// of a task have been changed.
+func resourcesResized(a, b *structs.Resources) comparison {
+	switch {
+	case a.CPU != b.CPU:
+		return resized("task cpu", a.CPU, b.CPU)
+	case a.MemoryMB != b.MemoryMB:
+		return resized("task memory", a.MemoryMB, b.MemoryMB)
+	case a.MemoryMaxMB != b.MemoryMaxMB:
+		return resized("task memory max", a.MemoryMaxMB, b.MemoryMaxMB)
+	}
+	return same
+}
+
+// resizeSupported returns true if the drivers of all the tasks of the group
+// whose cpu or memory resources have changed support resizing running tasks
+// on the node. Drivers advertise this with the "driver.<name>.resize" node
+// attribute.
+func resizeSupported(node *structs.Node, a, b *structs.TaskGroup) bool {
+	for _, at := range a.Tasks {
+		bt := b.LookupTask(at.Name)
+		if bt == nil {
+			return false
+		}
+		if !resourcesResized(at.Resources, bt.Resources).modified {
+			continue
+		}
+		if node.Attributes[fmt.Sprintf("driver.%s.resize", at.Driver)] != "true" {
+			return false
+		}
+	}
+	return true
+}
+
 // consulUpdated returns true if the Consul namespace or cluster in the task
 // group has been changed.
 //
@@ -510,6 +587,10 @@ func networkUpdated(netA, netB []*structs.NetworkResource) comparison {
 			return difference("network dns", an.DNS, bn.DNS)
 		}
 
+		if !slices.Equal(an.Interfaces, bn.Interfaces) {
+			return difference("network interfaces", an.Interfaces, bn.Interfaces)
+		}
+
 		aPorts, bPorts := networkPortMap(an), networkPortMap(bn)
 		if !aPorts.Equal(bPorts) {
 			return difference("network port map", aPorts, bPorts)
@@ -669,7 +750,8 @@ func inplaceUpdate(ctx Context, eval *structs.Evaluation, job *structs.Job,
 		// Check if the task drivers or config has changed, requires
 		// a rolling upgrade since that cannot be done in-place.
 		existing := update.Alloc.Job
-		if c := tasksUpdated(job, existing, update.TaskGroup.Name); c.modified {
+		c := tasksUpdated(job, existing, update.TaskGroup.Name)
+		if c.modified && !c.resize {
 			continue
 		}
 
@@ -700,6 +782,11 @@ func inplaceUpdate(ctx Context, eval *structs.Evaluation, job *structs.Job,
 		if !node.IsInPool(job.NodePool) {
 			continue
 		}
+		// Resources can only be changed in-place if the drivers on the node
+		// can resize the running tasks
+		if c.resize && !resizeSupported(node, existing.LookupTaskGroup(update.TaskGroup.Name), update.TaskGroup) {
+			continue
+		}
 
 		// Set the existing node as the base set
 		stack.SetNodes([]*structs.Node{node})
@@ -917,7 +1004,8 @@ func genericAllocUpdateFn(ctx Context, stack Stack, evalID string) allocUpdateTy
 
 		// Check if the task drivers or config has changed, requires
 		// a destructive upgrade since that cannot be done in-place.
-		if c := tasksUpdated(newJob, existing.Job, newTG.Name); c.modified {
+		c := tasksUpdated(newJob, existing.Job, newTG.Name)
+		if c.modified && !c.resize {
 			return false, true, nil
 		}
 
@@ -947,6 +1035,11 @@ func genericAllocUpdateFn(ctx Context, stack Stack, evalID string) allocUpdateTy
 		if !node.IsInPool(newJob.NodePool) {
 			return false, true, nil
 		}
+		// Resources can only be changed in-place if the drivers on the node
+		// can resize the running tasks
+		if c.resize && !resizeSupported(node, existing.Job.LookupTaskGroup(newTG.Name), newTG) {
+			return false, true, nil
+		}
 
 		// Set the existing node as the base set
 		stack.SetNodes([]*structs.Node{node})
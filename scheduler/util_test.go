@@ -455,6 +455,16 @@ func TestTasksUpdated(t *testing.T) {
 	j16.TaskGroups[0].EphemeralDisk.Sticky = true
 	must.True(t, tasksUpdated(j1, j16, name).modified)
 
+	// Migrate hooks are updated in place
+	j16b := mock.Job()
+	j16b.TaskGroups[0].EphemeralDisk.MigrateHooks = []*structs.MigrateHook{{
+		Stage:   structs.MigrateHookStagePreMigrate,
+		Task:    "web",
+		Command: "/bin/flush",
+		Timeout: time.Minute,
+	}}
+	must.False(t, tasksUpdated(j1, j16b, name).modified)
+
 	// Change group meta
 	j17 := mock.Job()
 	j17.TaskGroups[0].Meta["j17_test"] = "roll_baby_roll"
@@ -547,6 +557,11 @@ func TestTasksUpdated(t *testing.T) {
 	// Compare changed Template ErrMissingKey
 	j30.TaskGroups[0].Tasks[0].Templates[0].ErrMissingKey = true
 	must.True(t, tasksUpdated(j29, j30, name).modified)
+
+	// Compare changed sealed values
+	j31 := mock.Job()
+	j31.TaskGroups[0].Tasks[0].Sealed = map[string]string{"password": "sealed:v1:key1:YQ=="}
+	must.True(t, tasksUpdated(j1, j31, name).modified)
 }
 
 func TestTasksUpdated_connectServiceUpdated(t *testing.T) {
@@ -1018,6 +1033,82 @@ func TestInplaceUpdate_Success(t *testing.T) {
 	}
 }
 
+func TestInplaceUpdate_Resize(t *testing.T) {
+	ci.Parallel(t)
+
+	cases := []struct {
+		name    string
+		resize  bool
+		inplace bool
+	}{
+		{name: "driver supports resize", resize: true, inplace: true},
+		{name: "driver does not support resize", resize: false, inplace: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			state, ctx := testContext(t)
+			eval := mock.Eval()
+			job := mock.Job()
+
+			node := mock.Node()
+			if tc.resize {
+				node.Attributes["driver.exec.resize"] = "true"
+			}
+			must.NoError(t, state.UpsertNode(structs.MsgTypeTestSetup, 900, node))
+
+			alloc := &structs.Allocation{
+				Namespace: structs.DefaultNamespace,
+				ID:        uuid.Generate(),
+				EvalID:    eval.ID,
+				NodeID:    node.ID,
+				JobID:     job.ID,
+				Job:       job,
+				TaskGroup: job.TaskGroups[0].Name,
+				AllocatedResources: &structs.AllocatedResources{
+					Tasks: map[string]*structs.AllocatedTaskResources{
+						"web": {
+							Cpu: structs.AllocatedCpuResources{
+								CpuShares: 500,
+							},
+							Memory: structs.AllocatedMemoryResources{
+								MemoryMB: 256,
+							},
+						},
+					},
+				},
+				DesiredStatus: structs.AllocDesiredStatusRun,
+			}
+			must.NoError(t, state.UpsertJobSummary(999, mock.JobSummary(alloc.JobID)))
+			must.NoError(t, state.UpsertAllocs(structs.MsgTypeTestSetup, 1001, []*structs.Allocation{alloc}))
+
+			// Update only the resources of the task
+			newJob := job.Copy()
+			newJob.TaskGroups[0].Tasks[0].Resources.CPU = 600
+			newJob.TaskGroups[0].Tasks[0].Resources.MemoryMB = 512
+
+			updates := []allocTuple{{Alloc: alloc, TaskGroup: newJob.TaskGroups[0]}}
+			stack := NewGenericStack(false, ctx)
+			stack.SetJob(newJob)
+
+			unplaced, inplace := inplaceUpdate(ctx, eval, newJob, stack, updates)
+			if !tc.inplace {
+				must.Len(t, 1, unplaced)
+				must.Len(t, 0, inplace)
+				return
+			}
+
+			must.Len(t, 0, unplaced)
+			must.Len(t, 1, inplace)
+
+			updated := ctx.plan.NodeAllocation[node.ID][0]
+			res := updated.AllocatedResources.Tasks["web"]
+			must.Eq(t, 600, res.Cpu.CpuShares)
+			must.Eq(t, 512, res.Memory.MemoryMB)
+		})
+	}
+}
+
 func TestInplaceUpdate_WildcardDatacenters(t *testing.T) {
 	ci.Parallel(t)
 
@@ -1209,6 +1300,62 @@ func TestTasksUpdated_NUMA(t *testing.T) {
 	must.True(t, tasksUpdated(j1, j2, name).modified)
 }
 
+func TestTasksUpdated_Resize(t *testing.T) {
+	ci.Parallel(t)
+
+	j1 := mock.Job()
+	name := j1.TaskGroups[0].Name
+
+	// Changes to cpu and memory are resizes
+	j2 := j1.Copy()
+	j2.TaskGroups[0].Tasks[0].Resources.CPU = 1337
+	c := tasksUpdated(j1, j2, name)
+	must.True(t, c.modified)
+	must.True(t, c.resize)
+
+	j3 := j1.Copy()
+	j3.TaskGroups[0].Tasks[0].Resources.MemoryMB = 1024
+	j3.TaskGroups[0].Tasks[0].Resources.MemoryMaxMB = 2048
+	c = tasksUpdated(j1, j3, name)
+	must.True(t, c.modified)
+	must.True(t, c.resize)
+
+	// Other destructive changes take precedence
+	j4 := j2.Copy()
+	j4.TaskGroups[0].Tasks[0].Env["NEW_ENV"] = "NEW_VALUE"
+	c = tasksUpdated(j1, j4, name)
+	must.True(t, c.modified)
+	must.False(t, c.resize)
+
+	// Changes to cores are not resizes
+	j5 := j1.Copy()
+	j5.TaskGroups[0].Tasks[0].Resources.Cores = 2
+	c = tasksUpdated(j1, j5, name)
+	must.True(t, c.modified)
+	must.False(t, c.resize)
+}
+
+func TestResizeSupported(t *testing.T) {
+	ci.Parallel(t)
+
+	j1 := mock.Job()
+	j2 := j1.Copy()
+	j2.TaskGroups[0].Tasks[0].Resources.CPU = 1337
+	a, b := j1.TaskGroups[0], j2.TaskGroups[0]
+
+	node := mock.Node()
+	must.False(t, resizeSupported(node, a, b))
+
+	node.Attributes["driver.exec.resize"] = "true"
+	must.True(t, resizeSupported(node, a, b))
+
+	// Only the drivers of resized tasks are checked
+	a.Tasks[0].Driver = "docker"
+	b.Tasks[0].Driver = "docker"
+	must.False(t, resizeSupported(node, a, b))
+	must.True(t, resizeSupported(node, a, a))
+}
+
 func TestTaskGroupConstraints(t *testing.T) {
 	ci.Parallel(t)
 